@@ -0,0 +1,120 @@
+// Команда auditctl проверяет и закрепляет цепочку хешей журнала аудита:
+//
+//	auditctl verify [-from N] [-to M]  — проверка целостности записей N..M (по умолчанию всей цепочки)
+//	auditctl anchor                    — закрепление хеша последней записи во внешнем журнале
+//
+// При нарушении цепочки команда verify печатает результат и завершается с кодом 1.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/infrastructure/anchoring"
+	"driver-service/internal/infrastructure/database"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "auditctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: auditctl verify [-from <sequence>] [-to <sequence>] | anchor")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Storage.Type != config.StorageTypePostgres {
+		return fmt.Errorf("audit chain is stored only with storage.type=postgres")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logger.Sync()
+
+	db, err := database.NewPostgresDB(&cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	sink, err := anchoring.New(cfg.Audit.Anchor)
+	if err != nil {
+		return err
+	}
+	auditService := services.NewAuditService(
+		repositories.NewAuditRepository(db, logger),
+		repositories.NewDriverRepository(db, logger),
+		sink,
+		services.AuditPolicy{
+			HashChain:       cfg.Audit.HashChain,
+			VerifyBatchSize: cfg.Audit.VerifyBatchSize,
+		},
+		logger,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "verify":
+		flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+		from := flags.Int64("from", 1, "первая проверяемая запись")
+		to := flags.Int64("to", 0, "последняя проверяемая запись (0 — до конца цепочки)")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		verification, err := auditService.VerifyChain(ctx, *from, *to)
+		if err != nil {
+			return err
+		}
+		if err := printJSON(verification); err != nil {
+			return err
+		}
+		if !verification.Valid {
+			return fmt.Errorf("audit chain is broken at sequence %d: %s",
+				verification.Break.Sequence, verification.Break.Reason)
+		}
+		return nil
+	case "anchor":
+		if sink == nil {
+			return fmt.Errorf("audit anchoring is not configured (audit.anchor.sink)")
+		}
+		anchor, err := auditService.AnchorChain(ctx)
+		if err != nil {
+			return err
+		}
+		if anchor == nil {
+			fmt.Println("nothing to anchor: no new entries since the last anchor")
+			return nil
+		}
+		return printJSON(anchor)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
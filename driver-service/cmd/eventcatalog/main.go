@@ -0,0 +1,42 @@
+// Команда eventcatalog выгружает каталог событий сервиса в JSON:
+//
+//	eventcatalog                              — каталог в stdout
+//	eventcatalog -o api/events/catalog.json   — обновление опубликованного артефакта
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"driver-service/internal/domain/services"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "eventcatalog: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	flags := flag.NewFlagSet("eventcatalog", flag.ContinueOnError)
+	output := flags.String("o", "", "файл каталога; по умолчанию stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := services.MarshalEventCatalog()
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
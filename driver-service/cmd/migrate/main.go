@@ -0,0 +1,139 @@
+// Команда migrate управляет схемой базы данных по миграциям, встроенным в бинарник:
+//
+//	migrate [-shard S] up                 — применение всех новых миграций
+//	migrate [-shard S] down [-steps N]    — откат N последних миграций (по умолчанию одной)
+//	migrate [-shard S] status [-history N] — примененная и последняя версии, ожидающие миграции и журнал команд
+//	migrate [-shard S] force -version N   — установка версии после ручного исправления прерванной миграции
+//
+// С -shard команда выполняется на базе шарда местоположений из sharding.shards.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"driver-service/internal/config"
+	"driver-service/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	global := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	shard := global.String("shard", "", "шард местоположений вместо основной базы")
+	if err := global.Parse(args); err != nil {
+		return err
+	}
+	args = global.Args()
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate [-shard <name>] up | down [-steps N] | status [-history N] | force -version N")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	dbCfg := cfg.Database
+	if *shard != "" {
+		if _, ok := cfg.Sharding.Shards[*shard]; !ok {
+			return fmt.Errorf("unknown shard %q", *shard)
+		}
+		dbCfg = cfg.ShardDatabase(*shard)
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logger.Sync()
+
+	db, err := database.NewPostgresDB(&dbCfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	migrator, err := db.NewMigrator(ctx, operator())
+	if err != nil {
+		return err
+	}
+	defer migrator.Close()
+
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	case "down":
+		flags := flag.NewFlagSet("down", flag.ContinueOnError)
+		steps := flags.Int("steps", 1, "число откатываемых миграций")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if err := migrator.Down(*steps); err != nil {
+			return err
+		}
+	case "force":
+		flags := flag.NewFlagSet("force", flag.ContinueOnError)
+		version := flags.Int("version", -1, "устанавливаемая версия схемы")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *version < 0 {
+			return fmt.Errorf("-version is required")
+		}
+		if err := migrator.Force(*version); err != nil {
+			return err
+		}
+	case "status":
+		flags := flag.NewFlagSet("status", flag.ContinueOnError)
+		history := flags.Int("history", 10, "число последних команд журнала")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		status, err := migrator.Status(ctx, *history)
+		if err != nil {
+			return err
+		}
+		return printJSON(status)
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	status, err := migrator.Status(ctx, 1)
+	if err != nil {
+		return err
+	}
+	return printJSON(status)
+}
+
+// operator автор команд в журнале миграций: пользователь ОС и хост
+func operator() string {
+	host, _ := os.Hostname()
+	user := os.Getenv("USER")
+	if user == "" {
+		return host
+	}
+	return user + "@" + host
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,92 @@
+// Команда ratingctl обслуживает рейтинги водителей:
+//
+//	ratingctl recompute  — пересчет текущего рейтинга всех водителей по их оценкам
+//
+// Пересчет нужен после загрузки оценок в обход API и для рейтингов, записанных до перехода
+// на средневзвешенное: он идет так же, как задача rating_recompute, и безопасен параллельно
+// с работающим сервисом.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/infrastructure/database"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "ratingctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: ratingctl recompute")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.Storage.Type != config.StorageTypePostgres {
+		return fmt.Errorf("ratings are stored only with storage.type=postgres")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logger.Sync()
+
+	db, err := database.NewPostgresDB(&cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	// Пересчет не публикует событий, поэтому шина событий не нужна
+	ratingService := services.NewRatingService(
+		repositories.NewRatingRepository(db, logger),
+		repositories.NewDriverRepository(db, logger),
+		nil,
+		repositories.NewTxManager(db),
+		services.RatingPolicy{
+			MaxPerCustomerPerDay: cfg.Ratings.MaxPerCustomerPerDay,
+			SuspiciousMinRatings: cfg.Ratings.SuspiciousMinRatings,
+			SuspiciousWindow:     cfg.Ratings.SuspiciousWindow,
+			DecayHalfLife:        cfg.Ratings.DecayHalfLife,
+		},
+		logger,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "recompute":
+		updated, err := ratingService.RecomputeRatings(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(map[string]int{"updated": updated})
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -5,19 +5,43 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
 	"driver-service/internal/domain/services"
 	httpHandlers "driver-service/internal/interfaces/http/handlers"
+	grpcServer "driver-service/internal/interfaces/grpc"
+	mqttConsumer "driver-service/internal/interfaces/mqtt"
+	natsConsumer "driver-service/internal/interfaces/nats"
 	httpServer "driver-service/internal/interfaces/http"
+	"driver-service/internal/interfaces/http/middleware"
+	wsServer "driver-service/internal/interfaces/websocket"
+	"driver-service/internal/infrastructure/analytics"
+	"driver-service/internal/infrastructure/anchoring"
+	"driver-service/internal/infrastructure/capacity"
 	"driver-service/internal/infrastructure/database"
+	"driver-service/internal/infrastructure/external/licenseregistry"
+	"driver-service/internal/infrastructure/external/ocr"
+	"driver-service/internal/infrastructure/health"
+	"driver-service/internal/infrastructure/logging"
+	"driver-service/internal/infrastructure/messaging"
+	"driver-service/internal/infrastructure/notifications"
+	"driver-service/internal/infrastructure/scheduler"
+	"driver-service/internal/infrastructure/shutdown"
+	"driver-service/internal/infrastructure/slo"
+	"driver-service/internal/infrastructure/storage"
+	"driver-service/internal/infrastructure/warmup"
+	"driver-service/internal/infrastructure/webhooks"
 	"driver-service/internal/repositories"
+	"driver-service/internal/repositories/memory"
 
 	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -27,18 +51,131 @@ type Application struct {
 	config   *config.Config
 	logger   *zap.Logger
 	db       *database.DB
+
+	// Уровень логирования, меняющийся при перечитывании конфигурации
+	logLevel zap.AtomicLevel
+
+	// Шарды местоположений по городам (только при sharding.enabled)
+	shards      map[string]*database.DB
+	shardRouter *database.ShardRouter
+	shardRepo   repositories.ShardRepository
 	
 	// Repositories
-	driverRepo   repositories.DriverRepository
-	documentRepo repositories.DocumentRepository
-	locationRepo repositories.LocationRepository
+	// txManager транзакции нескольких репозиториев; nil при хранении в памяти
+	txManager       repositories.TxManager
+	driverRepo      repositories.DriverRepository
+	documentRepo    repositories.DocumentRepository
+	locationRepo    repositories.LocationRepository
+	summaryRepo     repositories.LocationSummaryRepository
+	shiftRepo       repositories.ShiftRepository
+	shiftBreakRepo  repositories.ShiftBreakRepository
+	ratingRepo      repositories.RatingRepository
+	inspectionRepo  repositories.InspectionRepository
+	leaderboardRepo repositories.LeaderboardRepository
+	expenseRepo     repositories.ExpenseRepository
+	earningRepo     repositories.EarningRepository
+	capacityRepo    repositories.CapacityRepository
+	auditRepo       repositories.AuditRepository
+	securityRepo    repositories.SecurityRepository
+	campaignRepo    repositories.CampaignRepository
+	scheduleRepo    repositories.ScheduleRepository
+	messageRepo     repositories.MessageRepository
+	geofenceRepo    repositories.GeofenceRepository
+	fleetRepo       repositories.FleetRepository
+	dispatchRepo    repositories.DispatchRepository
+	heartbeatRepo   repositories.HeartbeatRepository
+	apiKeyRepo      repositories.APIKeyRepository
+	supplyRepo      repositories.SupplyRepository
+	deviceRepo      repositories.DeviceRepository
+	jobRunRepo      repositories.JobRunRepository
+	blockRepo       repositories.BlockRepository
+	referralRepo    repositories.ReferralRepository
+	statusHistoryRepo repositories.StatusHistoryRepository
+	cityRepo        repositories.CityRepository
+	featureFlagRepo repositories.FeatureFlagRepository
+	locationExportRepo repositories.LocationExportRepository
+	dailyStatsRepo  repositories.DriverDailyStatsRepository
+	webhookRepo     repositories.WebhookRepository
+	phoneVerificationRepo repositories.PhoneVerificationRepository
+	driverNoteRepo  repositories.DriverNoteRepository
+	profileChangeRepo repositories.ProfileChangeRepository
+	driverInviteRepo repositories.DriverInviteRepository
+	orderTrackingRepo repositories.OrderTrackingRepository
 	
 	// Services
-	driverService   services.DriverService
-	locationService services.LocationService
+	driverService       services.DriverService
+	locationService     services.LocationService
+	inspectionService   services.InspectionService
+	shiftService        services.ShiftService
+	profileService      services.ProfileService
+	leaderboardService  services.LeaderboardService
+	verificationService services.DocumentVerificationService
+	renewalService      services.DocumentRenewalService
+	documentOCR         services.DocumentOCRService
+	ratingService       services.RatingService
+	expenseService      services.ExpenseService
+	earningService      services.EarningService
+	capacityService     services.CapacityService
+	driverVerification  services.DriverVerificationService
+	auditService        services.AuditService
+	securityService     services.SecurityService
+	reverification      services.ReverificationService
+	privacyService      services.PrivacyService
+	tripService         services.TripAnalysisService
+	locationRetention   services.LocationRetentionService
+	scheduleService     services.ScheduleService
+	messageService      services.MessageService
+	geofenceService     services.GeofenceService
+	fleetService        services.FleetService
+	dispatchService     services.DispatchScoringService
+	heartbeatService    services.HeartbeatService
+	statsService        services.DriverStatsService
+	apiKeyService       services.APIKeyService
+	supplyService       services.SupplyService
+	deviceService       services.DeviceService
+	blockService        services.BlockService
+	bulkStatusService   services.BulkStatusService
+	referralService     services.ReferralService
+	statusHistoryService services.StatusHistoryService
+	cityService         services.CityService
+	featureFlagService  services.FeatureFlagService
+	locationExportService services.LocationExportService
+	driverTagService    services.DriverTagService
+	webhookService      services.WebhookService
+	phoneVerificationService services.PhoneVerificationService
+	driverNoteService   services.DriverNoteService
+	profileChangeService services.ProfileChangeService
+	driverInviteService services.DriverInviteService
+	orderTracking       services.OrderTrackingService
+	locationAnalytics   services.LocationAnalyticsService
 	
 	// Servers
 	httpServer *httpServer.Server
+	grpcServer *grpcServer.Server
+	wsHub      *wsServer.Hub
+
+	// Background jobs
+	scheduler *scheduler.Scheduler
+
+	// Метрики нагрузки для планирования мощностей
+	capacityCollector *capacity.Collector
+	// Учет целевых показателей ключевых операций; nil, если выключен
+	sloTracker *slo.Tracker
+
+	// Прогрев после запуска; nil, если выключен
+	warmer *warmup.Warmer
+
+	// Внешний журнал для закрепления хешей цепочки аудита; nil, если не настроен
+	auditAnchorSink services.AuditAnchorSink
+
+	// Копирование принятых местоположений в ClickHouse; nil, если аналитика выключена
+	locationSink *analytics.LocationSink
+
+	// Messaging
+	natsConn         *nats.Conn
+	billingConsumer  *messaging.BillingConsumer
+	locationConsumer *natsConsumer.LocationConsumer
+	trackerConsumer  *mqttConsumer.LocationConsumer
 	
 	// Shutdown
 	shutdown chan struct{}
@@ -73,7 +210,7 @@ func NewApplication() (*Application, error) {
 	}
 
 	// Инициализируем логгер
-	logger, err := initLogger(cfg.Logger)
+	logger, logLevel, err := initLogger(cfg.Logger, cfg.Server.Environment)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
@@ -84,22 +221,31 @@ func NewApplication() (*Application, error) {
 		zap.Int("http_port", cfg.Server.HTTPPort),
 	)
 
-	// Инициализируем базу данных
-	db, err := database.NewPostgresDB(&cfg.Database, logger)
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
-	}
+	// Инициализируем базу данных (in-memory хранилищу она не нужна)
+	var db *database.DB
+	if cfg.Storage.IsMemory() {
+		logger.Warn("Using in-memory storage, data will be lost on restart")
+	} else {
+		db, err = database.NewPostgresDB(&cfg.Database, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize database: %w", err)
+		}
+
+		// Выполняем миграции; с прерванной миграцией сервис не запускается
+		if err := db.RunMigrations(); err != nil {
+			return nil, fmt.Errorf("failed to run migrations: %w", err)
+		}
 
-	// Выполняем миграции
-	migrationsPath := filepath.Join("internal", "infrastructure", "database", "migrations")
-	if err := db.RunMigrations(migrationsPath); err != nil {
-		logger.Error("Failed to run migrations", zap.Error(err))
-		// Не прерываем выполнение, так как миграции могут быть уже выполнены
+		// Подключаем реплики для чтения; их недоступность не мешает запуску
+		if err := database.OpenReplicas(cfg, db, logger); err != nil {
+			return nil, fmt.Errorf("failed to initialize database replicas: %w", err)
+		}
 	}
 
 	app := &Application{
 		config:   cfg,
 		logger:   logger,
+		logLevel: logLevel,
 		db:       db,
 		shutdown: make(chan struct{}),
 	}
@@ -113,15 +259,27 @@ func NewApplication() (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize services: %w", err)
 	}
 
+	if err := app.initMessaging(); err != nil {
+		return nil, fmt.Errorf("failed to initialize messaging: %w", err)
+	}
+
+	if err := app.initScheduler(); err != nil {
+		return nil, fmt.Errorf("failed to initialize scheduler: %w", err)
+	}
+
 	if err := app.initServers(); err != nil {
 		return nil, fmt.Errorf("failed to initialize servers: %w", err)
 	}
 
+	app.initWarmup()
+	app.initHealth()
+
 	return app, nil
 }
 
-// initLogger инициализирует логгер
-func initLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
+// initLogger инициализирует логгер с маскированием персональных данных. Возвращаемый
+// уровень можно менять после запуска
+func initLogger(cfg config.LoggerConfig, environment string) (*zap.Logger, zap.AtomicLevel, error) {
 	var zapConfig zap.Config
 
 	if cfg.Format == "json" {
@@ -133,7 +291,7 @@ func initLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
 	// Устанавливаем уровень логирования
 	level, err := zapcore.ParseLevel(cfg.Level)
 	if err != nil {
-		return nil, fmt.Errorf("invalid log level: %w", err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log level: %w", err)
 	}
 	zapConfig.Level.SetLevel(level)
 
@@ -142,40 +300,838 @@ func initLogger(cfg config.LoggerConfig) (*zap.Logger, error) {
 		zapConfig.OutputPaths = []string{cfg.OutputPath}
 	}
 
-	logger, err := zapConfig.Build()
+	// Полные значения допустимы только в debug-записях при локальной разработке
+	logger, err := zapConfig.Build(logging.WrapCore(logging.Options{
+		Mode:     logging.Mode(cfg.Redaction.Mode),
+		Fields:   cfg.Redaction.Fields,
+		RawDebug: cfg.Redaction.DebugUnmasked && environment == "development",
+	}))
 	if err != nil {
-		return nil, fmt.Errorf("failed to build logger: %w", err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("failed to build logger: %w", err)
 	}
 
-	return logger, nil
+	return logger, zapConfig.Level, nil
 }
 
 // initRepositories инициализирует репозитории
 func (app *Application) initRepositories() error {
-	app.driverRepo = repositories.NewDriverRepository(app.db, app.logger)
-	app.documentRepo = repositories.NewDocumentRepository(app.db, app.logger)
-	app.locationRepo = repositories.NewLocationRepository(app.db, app.logger)
+	switch app.config.Storage.Type {
+	case config.StorageTypeMemory:
+		driverRepo := memory.NewDriverRepository()
+		shiftRepo := memory.NewShiftRepository()
+		ratingRepo := memory.NewRatingRepository()
+		locationRepo := memory.NewLocationRepository()
+		app.driverRepo = driverRepo
+		app.documentRepo = memory.NewDocumentRepository()
+		app.locationRepo = locationRepo
+		app.summaryRepo = memory.NewLocationSummaryRepository()
+		app.shiftRepo = shiftRepo
+		app.shiftBreakRepo = memory.NewShiftBreakRepository()
+		app.ratingRepo = ratingRepo
+		app.inspectionRepo = memory.NewInspectionRepository()
+		app.leaderboardRepo = memory.NewLeaderboardRepository(driverRepo, shiftRepo, ratingRepo)
+		app.expenseRepo = memory.NewExpenseRepository()
+		app.earningRepo = memory.NewEarningRepository()
+		app.capacityRepo = memory.NewCapacityRepository()
+		app.auditRepo = memory.NewAuditRepository()
+		app.securityRepo = memory.NewSecurityRepository()
+		app.campaignRepo = memory.NewCampaignRepository()
+		app.scheduleRepo = memory.NewScheduleRepository()
+		app.messageRepo = memory.NewMessageRepository()
+		app.geofenceRepo = memory.NewGeofenceRepository()
+		app.fleetRepo = memory.NewFleetRepository()
+		app.dispatchRepo = memory.NewDispatchRepository()
+		app.heartbeatRepo = memory.NewHeartbeatRepository()
+		app.apiKeyRepo = memory.NewAPIKeyRepository()
+		app.supplyRepo = memory.NewSupplyRepository(driverRepo, locationRepo)
+		app.deviceRepo = memory.NewDeviceRepository()
+		app.jobRunRepo = memory.NewJobRunRepository()
+		app.blockRepo = memory.NewBlockRepository(driverRepo)
+		app.referralRepo = memory.NewReferralRepository()
+		app.statusHistoryRepo = memory.NewStatusHistoryRepository(driverRepo)
+		app.cityRepo = memory.NewCityRepository(driverRepo)
+		app.featureFlagRepo = memory.NewFeatureFlagRepository()
+		app.locationExportRepo = memory.NewLocationExportRepository()
+		app.dailyStatsRepo = memory.NewDriverDailyStatsRepository()
+		app.webhookRepo = memory.NewWebhookRepository()
+		app.phoneVerificationRepo = memory.NewPhoneVerificationRepository()
+		app.driverNoteRepo = memory.NewDriverNoteRepository()
+		app.profileChangeRepo = memory.NewProfileChangeRepository(driverRepo)
+		app.driverInviteRepo = memory.NewDriverInviteRepository()
+		app.orderTrackingRepo = memory.NewOrderTrackingRepository()
+	case config.StorageTypePostgres:
+		app.txManager = repositories.NewTxManager(app.db)
+		app.driverRepo = repositories.NewDriverRepository(app.db, app.logger)
+		app.documentRepo = repositories.NewDocumentRepository(app.db, app.logger)
+		app.locationRepo = repositories.NewLocationRepository(app.db, app.logger)
+		app.summaryRepo = repositories.NewLocationSummaryRepository(app.db, app.logger)
+		app.supplyRepo = repositories.NewSupplyRepository(app.db, app.logger)
+		if app.config.Sharding.Enabled {
+			if err := app.initShards(); err != nil {
+				return err
+			}
+		}
+		app.shiftRepo = repositories.NewShiftRepository(app.db, app.logger)
+		app.shiftBreakRepo = repositories.NewShiftBreakRepository(app.db, app.logger)
+		app.ratingRepo = repositories.NewRatingRepository(app.db, app.logger)
+		app.inspectionRepo = repositories.NewInspectionRepository(app.db, app.logger)
+		app.leaderboardRepo = repositories.NewLeaderboardRepository(app.db, app.logger)
+		app.expenseRepo = repositories.NewExpenseRepository(app.db, app.logger)
+		app.earningRepo = repositories.NewEarningRepository(app.db, app.logger)
+		app.capacityRepo = repositories.NewCapacityRepository(app.db, app.logger)
+		app.auditRepo = repositories.NewAuditRepository(app.db, app.logger)
+		app.securityRepo = repositories.NewSecurityRepository(app.db, app.logger)
+		app.campaignRepo = repositories.NewCampaignRepository(app.db, app.logger)
+		app.scheduleRepo = repositories.NewScheduleRepository(app.db, app.logger)
+		app.messageRepo = repositories.NewMessageRepository(app.db, app.logger)
+		app.geofenceRepo = repositories.NewGeofenceRepository(app.db, app.logger)
+		app.fleetRepo = repositories.NewFleetRepository(app.db, app.logger)
+		app.dispatchRepo = repositories.NewDispatchRepository(app.db, app.logger)
+		app.heartbeatRepo = repositories.NewHeartbeatRepository(app.db, app.logger)
+		app.apiKeyRepo = repositories.NewAPIKeyRepository(app.db, app.logger)
+		app.deviceRepo = repositories.NewDeviceRepository(app.db, app.logger)
+		app.jobRunRepo = repositories.NewJobRunRepository(app.db, app.logger)
+		app.blockRepo = repositories.NewBlockRepository(app.db, app.logger)
+		app.referralRepo = repositories.NewReferralRepository(app.db, app.logger)
+		app.statusHistoryRepo = repositories.NewStatusHistoryRepository(app.db, app.logger)
+		app.cityRepo = repositories.NewCityRepository(app.db, app.logger)
+		app.featureFlagRepo = repositories.NewFeatureFlagRepository(app.db, app.logger)
+		app.locationExportRepo = repositories.NewLocationExportRepository(app.db, app.logger)
+		app.dailyStatsRepo = repositories.NewDriverDailyStatsRepository(app.db, app.logger)
+		app.webhookRepo = repositories.NewWebhookRepository(app.db, app.logger)
+		app.phoneVerificationRepo = repositories.NewPhoneVerificationRepository(app.db, app.logger)
+		app.driverNoteRepo = repositories.NewDriverNoteRepository(app.db, app.logger)
+		app.profileChangeRepo = repositories.NewProfileChangeRepository(app.db, app.logger)
+		app.driverInviteRepo = repositories.NewDriverInviteRepository(app.db, app.logger)
+		app.orderTrackingRepo = repositories.NewOrderTrackingRepository(app.db, app.logger)
+	default:
+		return fmt.Errorf("unsupported storage type: %s", app.config.Storage.Type)
+	}
+
+	// Новые записи журнала аудита связываются цепочкой хешей
+	if app.config.Audit.HashChain {
+		app.auditRepo = repositories.WithHashChain(app.auditRepo)
+	}
 
-	app.logger.Info("Repositories initialized")
+	app.logger.Info("Repositories initialized",
+		zap.String("storage", app.config.Storage.Type),
+	)
+	return nil
+}
+
+// initShards подключается к шардам и переводит местоположения на шардированный репозиторий
+func (app *Application) initShards() error {
+	shards, err := database.OpenShards(app.config, app.db, app.logger)
+	if err != nil {
+		return err
+	}
+	app.shards = shards
+
+	for name, db := range shards {
+		if db == app.db {
+			continue
+		}
+		if err := db.RunMigrations(); err != nil {
+			return fmt.Errorf("failed to run shard %s migrations: %w", name, err)
+		}
+	}
+
+	router, err := database.NewShardRouter(shards, app.config.Sharding.DefaultShard, app.config.Sharding.Cities, app.logger)
+	if err != nil {
+		return fmt.Errorf("failed to init shard router: %w", err)
+	}
+	app.shardRouter = router
+	app.shardRepo = repositories.NewShardRepository(app.db, app.logger)
+	if err := repositories.LoadShardAssignments(context.Background(), app.shardRepo, router); err != nil {
+		return fmt.Errorf("failed to load city shard assignments: %w", err)
+	}
+
+	app.locationRepo, err = repositories.NewShardedLocationRepository(router, app.driverRepo, app.logger)
+	if err != nil {
+		return err
+	}
+	app.supplyRepo = repositories.NewShardedSupplyRepository(router, app.db, app.logger)
+
+	app.logger.Info("Location sharding enabled",
+		zap.Strings("shards", router.Names()),
+		zap.String("default_shard", app.config.Sharding.DefaultShard),
+	)
 	return nil
 }
 
 // initServices инициализирует сервисы
 func (app *Application) initServices() error {
-	// Создаем заглушку для EventPublisher
-	eventBus := &mockEventPublisher{logger: app.logger}
+	deliveryCfg := app.config.Webhooks.Delivery
+	app.webhookService = services.NewWebhookService(
+		app.webhookRepo,
+		webhooks.NewSender(deliveryCfg.Timeout),
+		services.WebhookPolicy{
+			Workers:        deliveryCfg.Workers,
+			QueueSize:      deliveryCfg.QueueSize,
+			Timeout:        deliveryCfg.Timeout,
+			MaxAttempts:    deliveryCfg.MaxAttempts,
+			InitialBackoff: deliveryCfg.InitialBackoff,
+			MaxBackoff:     deliveryCfg.MaxBackoff,
+			Lease:          deliveryCfg.Lease,
+			RetryBatch:     deliveryCfg.RetryBatch,
+			CacheTTL:       deliveryCfg.CacheTTL,
+		},
+		app.logger,
+	)
+
+	// Создаем заглушку для EventPublisher; опубликованные события доставляются подписчикам
+	// вебхуков, внутренние подписчики получают их через диспетчер
+	eventBus := messaging.NewEventDispatcher(
+		services.NewWebhookEventPublisher(&mockEventPublisher{logger: app.logger}, app.webhookService, app.logger),
+		app.logger,
+	)
 
 	app.driverService = services.NewDriverService(
 		app.driverRepo,
 		app.documentRepo,
+		app.scheduleRepo,
+		eventBus,
+		app.logger,
+	)
+	// Изменения, смена статуса и удаление водителей записываются в журнал аудита
+	app.driverService = services.NewAuditedDriverService(app.driverService, app.auditRepo, app.logger)
+
+	// Регистрацию и изменение профилей водителей автопарков проверяют их вебхуки
+	fleetClient, err := webhooks.NewFleetClient(app.config.Webhooks, app.logger)
+	if err != nil {
+		return fmt.Errorf("failed to init fleet webhooks: %w", err)
+	}
+	app.driverService = services.NewFleetValidatedDriverService(
+		app.driverService,
+		fleetClient,
+		app.auditRepo,
+		services.FleetValidationPolicy{EnrichmentKeys: app.config.Webhooks.EnrichmentKeys},
+		app.logger,
+	)
+	// Отправить водителя на верификацию можно только с подтвержденным телефоном
+	if app.config.Phone.VerificationRequired {
+		app.driverService = services.NewPhoneVerifiedDriverService(app.driverService)
+	}
+	// Партнеры видят только водителей своих автопарков; проверка выполняется до вебхуков
+	app.driverService = services.NewFleetScopedDriverService(app.driverService, app.fleetRepo)
+	app.fleetService = services.NewFleetService(app.fleetRepo, app.logger)
+	if app.auditAnchorSink, err = anchoring.New(app.config.Audit.Anchor); err != nil {
+		return fmt.Errorf("failed to init audit anchor sink: %w", err)
+	}
+	app.auditService = services.NewAuditService(
+		app.auditRepo,
+		app.driverRepo,
+		app.auditAnchorSink,
+		services.AuditPolicy{
+			HashChain:       app.config.Audit.HashChain,
+			VerifyBatchSize: app.config.Audit.VerifyBatchSize,
+		},
+		app.logger,
+	)
+
+	app.wsHub = wsServer.NewHub(app.config.WebSocket, app.logger)
+
+	app.geofenceService = services.NewGeofenceService(
+		app.geofenceRepo,
 		eventBus,
+		services.GeofencePolicy{CacheTTL: app.config.Geofences.CacheTTL},
 		app.logger,
 	)
 
+	app.cityService = services.NewCityService(
+		app.cityRepo,
+		services.CityPolicy{CacheTTL: app.config.Cities.CacheTTL},
+		app.logger,
+	)
+
+	// Принятые точки рассылаются WebSocket подписчикам и, если включена аналитика,
+	// копируются в ClickHouse
+	var locationBroadcaster services.LocationBroadcaster = app.wsHub
+	var analyticsStore services.LocationAnalyticsStore
+	if analyticsCfg := app.config.Locations.Analytics; analyticsCfg.Enabled {
+		client := analytics.NewClient(analyticsCfg)
+		schemaCtx, cancel := context.WithTimeout(context.Background(), analyticsCfg.Timeout)
+		if err := client.EnsureSchema(schemaCtx); err != nil {
+			// Аналитика необязательна: вставки повторяются, пока ClickHouse не станет доступен
+			app.logger.Error("Failed to prepare location analytics table", zap.Error(err))
+		}
+		cancel()
+		app.locationSink = analytics.NewLocationSink(client, analyticsCfg, app.logger)
+		locationBroadcaster = services.LocationBroadcasters{app.wsHub, app.locationSink}
+		analyticsStore = client
+	}
+	app.locationAnalytics = services.NewLocationAnalyticsService(analyticsStore, app.logger)
+
 	app.locationService = services.NewLocationService(
 		app.locationRepo,
 		app.driverRepo,
+		app.scheduleRepo,
+		eventBus,
+		locationBroadcaster,
+		app.geofenceService,
+		app.cityService,
+		services.LocationPolicy{
+			MaxGapInterval: app.config.Locations.MaxGapInterval,
+			Ingestion: services.LocationIngestionPolicy{
+				Async:         app.config.Locations.Ingestion.Async,
+				BufferSize:    app.config.Locations.Ingestion.BufferSize,
+				Workers:       app.config.Locations.Ingestion.Workers,
+				BatchSize:     app.config.Locations.Ingestion.BatchSize,
+				FlushInterval: app.config.Locations.Ingestion.FlushInterval,
+			},
+		},
+		app.logger,
+	)
+
+	dispatch := app.config.Dispatch
+	app.dispatchService = services.NewDispatchScoringService(
+		app.locationService,
+		app.driverRepo,
+		app.shiftRepo,
+		app.dispatchRepo,
+		services.DispatchPolicy{
+			Weights:         dispatchWeights(dispatch.Weights),
+			DefaultRadiusKm: dispatch.DefaultRadiusKm,
+			MaxCandidates:   dispatch.MaxCandidates,
+			MaxIdle:         dispatch.MaxIdle,
+			StatsWindow:     dispatch.StatsWindow,
+			MinOffers:       dispatch.MinOffers,
+			DefaultRating:   dispatch.DefaultRating,
+		},
+		app.logger,
+	)
+
+	app.messageService = services.NewMessageService(
+		app.messageRepo,
+		app.driverRepo,
+		eventBus,
+		app.wsHub,
+		services.MessagePolicy{Retention: time.Duration(app.config.Messages.RetentionDays) * 24 * time.Hour},
+		app.logger,
+	)
+
+	trips := app.config.Locations.Trips
+	app.tripService = services.NewTripAnalysisService(
+		app.locationRepo,
+		app.driverRepo,
+		services.TripPolicy{
+			Segmentation: entities.TripSegmentation{
+				StopSpeed:         trips.StopSpeed,
+				MinStopDuration:   trips.MinStopDuration,
+				MaxGapInterval:    app.config.Locations.MaxGapInterval,
+				MinDistanceKm:     trips.MinDistanceKm,
+				SimplifyTolerance: trips.SimplifyTolerance,
+			},
+			MaxRange: trips.MaxRange,
+		},
+		app.logger,
+	)
+
+	retention := app.config.Locations.Retention
+	app.locationRetention = services.NewLocationRetentionService(
+		app.locationRepo,
+		app.summaryRepo,
+		app.driverRepo,
+		services.LocationRetentionPolicy{
+			RawRetention:    time.Duration(retention.RawDays) * 24 * time.Hour,
+			Tiers:           locationRetentionTiers(retention),
+			PartitionsAhead: retention.PartitionsAhead,
+		},
+		app.logger,
+	)
+
+	app.statsService = services.NewDriverStatsService(
+		app.driverRepo,
+		app.ratingRepo,
+		app.shiftRepo,
+		app.documentRepo,
+		app.locationRepo,
+		app.summaryRepo,
+		app.dailyStatsRepo,
+		app.dispatchRepo,
+		services.StatsPolicy{
+			CacheTTL:           app.config.Statistics.CacheTTL,
+			MaxGapInterval:     app.config.Locations.MaxGapInterval,
+			SummaryTier:        longestRetentionTier(locationRetentionTiers(retention)),
+			DailyLookbackDays:  app.config.Statistics.DailyLookbackDays,
+			DailyBackfillDays:  app.config.Statistics.DailyBackfillDays,
+			DispatchWindowDays: app.config.Statistics.DispatchWindowDays,
+		},
+		app.logger,
+	)
+
+	app.supplyService = services.NewSupplyService(
+		app.supplyRepo,
+		services.SupplyPolicy{
+			DefaultPrecision: app.config.Locations.Heatmap.DefaultPrecision,
+			MaxPrecision:     app.config.Locations.Heatmap.MaxPrecision,
+			LocationMaxAge:   app.config.Locations.Heatmap.MaxAge,
+		},
+		app.logger,
+	)
+
+	app.apiKeyService = services.NewAPIKeyService(
+		app.apiKeyRepo,
+		services.APIKeyPolicy{
+			DefaultRateLimit: app.config.Auth.APIKeyRateLimit,
+			CacheTTL:         app.config.Auth.APIKeyCacheTTL,
+		},
+		app.logger,
+	)
+
+	// Расписания переводят водителей в неактивные через driverService, чтобы смена статуса
+	// проходила проверки автопарка и публиковала событие
+	app.scheduleService = services.NewScheduleService(
+		app.scheduleRepo,
+		app.driverRepo,
+		app.driverService,
+		services.SchedulePolicy{DefaultTimezone: app.config.Schedules.DefaultTimezone},
+		app.logger,
+	)
+
+	notifier, err := app.newNotificationService()
+	if err != nil {
+		return fmt.Errorf("failed to init notifications: %w", err)
+	}
+	// Водитель узнает о блокировке из события смены статуса, кто бы ее ни выполнил
+	eventBus.Subscribe(notifier.HandleStatusEvent, services.DriverStatusEventTypes...)
+
+	app.phoneVerificationService = services.NewPhoneVerificationService(
+		app.driverRepo,
+		app.phoneVerificationRepo,
+		notifier,
+		services.PhoneVerificationPolicy{
+			CodeTTL:        app.config.Phone.CodeTTL,
+			ResendInterval: app.config.Phone.ResendInterval,
+			MaxAttempts:    app.config.Phone.MaxAttempts,
+		},
+		app.logger,
+	)
+
+	photoStorage, err := storage.NewLocalStorage(app.config.Inspections.PhotoDir, app.config.Inspections.PhotoBaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to init inspection photo storage: %w", err)
+	}
+
+	app.inspectionService = services.NewInspectionService(
+		app.inspectionRepo,
+		notifier,
+		photoStorage,
+		eventBus,
+		services.InspectionPolicy{
+			BlockShiftOnOverdue: app.config.Inspections.BlockShiftOnOverdue,
+			IntervalDays:        app.config.Inspections.IntervalDays,
+			ReminderDays:        app.config.Inspections.ReminderDays,
+			PhotoIntervalDays:   app.config.Inspections.PhotoIntervalDays,
+			PhotoGraceDays:      app.config.Inspections.PhotoGraceDays,
+			MaxPhotoSize:        app.config.Inspections.PhotoMaxSize,
+		},
+		app.logger,
+	)
+
+	app.shiftService = services.NewShiftService(
+		app.shiftRepo,
+		app.shiftBreakRepo,
+		app.driverRepo,
+		app.locationRepo,
+		app.txManager,
+		app.inspectionService,
+		notifier,
+		eventBus,
+		services.ShiftPolicy{
+			MaxDuration:    app.config.Shifts.MaxDuration,
+			MaxGapInterval: app.config.Locations.MaxGapInterval,
+			Rest: entities.ShiftRestPolicy{
+				WorkLimit: app.config.Shifts.BreakRequiredAfter,
+				MinBreak:  app.config.Shifts.MinBreak,
+			},
+		},
+		app.logger,
+	)
+
+	app.heartbeatService = services.NewHeartbeatService(
+		app.heartbeatRepo,
+		app.driverRepo,
+		app.driverService,
+		app.shiftService,
+		eventBus,
+		services.HeartbeatPolicy{
+			OfflineAfter:  app.config.Heartbeat.OfflineAfter,
+			TouchInterval: app.config.Heartbeat.TouchInterval,
+		},
+		app.logger,
+	)
+	// Принятая точка местоположения тоже считается сигналом присутствия водителя
+	eventBus.Subscribe(app.heartbeatService.HandleLocationEvent, services.HeartbeatEventTypes...)
+
+	app.profileService = services.NewProfileService(
+		app.driverRepo,
+		app.documentRepo,
+		notifier,
+		app.config.Profile.NudgeInterval,
+		app.logger,
+	)
+
+	tieBreakers := make(map[entities.LeaderboardMetric][]entities.LeaderboardMetric)
+	for metric, breakers := range app.config.Leaderboard.TieBreakers {
+		for _, breaker := range breakers {
+			tieBreakers[entities.LeaderboardMetric(metric)] = append(tieBreakers[entities.LeaderboardMetric(metric)], entities.LeaderboardMetric(breaker))
+		}
+	}
+	metrics := make([]entities.LeaderboardMetric, len(app.config.Leaderboard.Metrics))
+	for i, metric := range app.config.Leaderboard.Metrics {
+		metrics[i] = entities.LeaderboardMetric(metric)
+	}
+
+	app.leaderboardService = services.NewLeaderboardService(
+		app.leaderboardRepo,
+		app.driverRepo,
+		services.LeaderboardPolicy{
+			Metrics:     metrics,
+			TieBreakers: tieBreakers,
+			MinRatings:  app.config.Leaderboard.MinRatings,
+			MaxLimit:    app.config.Leaderboard.MaxLimit,
+			CacheTTL:    app.config.Leaderboard.CacheTTL,
+		},
+		app.logger,
+	)
+
+	documentStorage, err := storage.NewLocalStorage(app.config.Documents.FileDir, app.config.Documents.FileBaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to init document storage: %w", err)
+	}
+
+	// Распознавание загруженных документов; без провайдера документы проверяются без подсказок
+	ocrProvider, err := ocr.New(app.config)
+	if err != nil {
+		return fmt.Errorf("failed to init OCR provider: %w", err)
+	}
+	if ocrProvider != nil {
+		app.documentOCR = services.NewDocumentOCRService(
+			ocrProvider,
+			app.documentRepo,
+			app.driverRepo,
+			services.DocumentOCRPolicy{
+				Workers:   app.config.External.OCR.Workers,
+				QueueSize: app.config.External.OCR.QueueSize,
+				Timeout:   app.config.External.OCR.Timeout,
+			},
+			app.logger,
+		)
+	}
+
+	app.renewalService = services.NewDocumentRenewalService(
+		app.documentRepo,
+		app.driverRepo,
+		documentStorage,
+		app.documentOCR,
+		notifier,
+		eventBus,
+		services.DocumentRenewalPolicy{
+			WindowDays:               app.config.Documents.RenewalWindowDays,
+			MaxFileSize:              app.config.Documents.FileMaxSize,
+			ResubmissionReminderDays: app.config.Documents.ResubmissionReminderDays,
+		},
+		app.logger,
+	)
+
+	licenses, err := licenseregistry.New(app.config, app.logger)
+	if err != nil {
+		return fmt.Errorf("failed to init license registry: %w", err)
+	}
+
+	app.verificationService = services.NewDocumentVerificationService(
+		app.documentRepo,
+		app.auditRepo,
+		app.renewalService,
+		notifier,
+		licenses,
+		eventBus,
+		services.VerificationQueuePolicy{
+			ClaimTTL: app.config.Verification.ClaimTTL,
+			MaxBatch: app.config.Verification.MaxBatch,
+		},
+		app.logger,
+	)
+
+	requiredDocuments := make([]entities.DocumentType, len(app.config.Verification.RequiredDocuments))
+	for i, docType := range app.config.Verification.RequiredDocuments {
+		requiredDocuments[i] = entities.DocumentType(docType)
+	}
+
+	app.driverVerification = services.NewDriverVerificationService(
+		app.driverRepo,
+		app.documentRepo,
+		app.driverService,
+		eventBus,
+		services.DriverVerificationPolicy{
+			RequiredDocuments: requiredDocuments,
+		},
+		app.logger,
+	)
+	eventBus.Subscribe(app.driverVerification.HandleDocumentEvent, services.DocumentEventTypes...)
+
+	app.reverification = services.NewReverificationService(
+		app.campaignRepo,
+		app.documentRepo,
+		app.driverRepo,
+		notifier,
+		eventBus,
+		services.ReverificationPolicy{
+			DefaultReminderIntervalDays: app.config.Campaigns.ReminderIntervalDays,
+			BatchSize:                   app.config.Campaigns.BatchSize,
+		},
+		app.logger,
+	)
+	// Документ перепроверен, когда подтвержденная новая версия заменила его
+	eventBus.Subscribe(app.reverification.HandleDocumentEvent, "driver.document.renewed")
+
+	autoRevoke := make([]entities.SecurityEventType, len(app.config.Security.AutoRevoke))
+	for i, eventType := range app.config.Security.AutoRevoke {
+		autoRevoke[i] = entities.SecurityEventType(eventType)
+	}
+
+	app.securityService = services.NewSecurityService(
+		app.securityRepo,
+		app.locationRepo,
+		eventBus,
+		services.SecurityPolicy{
+			HistoryWindow:         time.Duration(app.config.Security.HistoryDays) * 24 * time.Hour,
+			ConcurrentWindow:      app.config.Security.ConcurrentWindow,
+			MaxConcurrentSessions: app.config.Security.MaxConcurrentSessions,
+			MaxPlausibleSpeedKmh:  app.config.Security.MaxPlausibleSpeedKmh,
+			MinJumpDistanceKm:     app.config.Security.MinJumpDistanceKm,
+			AlertCooldown:         app.config.Security.AlertCooldown,
+			SessionCacheTTL:       app.config.Security.SessionCacheTTL,
+			AutoRevoke:            autoRevoke,
+		},
+		app.logger,
+	)
+	// Скачки местоположения проверяются по каждой принятой точке
+	eventBus.Subscribe(app.securityService.HandleLocationEvent, "driver.location.updated")
+
+	app.deviceService = services.NewDeviceService(
+		app.deviceRepo,
+		app.driverRepo,
+		app.securityService,
+		app.logger,
+	)
+
+	app.featureFlagService = services.NewFeatureFlagService(
+		app.featureFlagRepo,
+		app.driverRepo,
+		app.deviceRepo,
+		services.FeatureFlagPolicy{
+			CacheTTL: app.config.DriverApp.FlagsCacheTTL,
+			Defaults: entities.AppParameters{
+				GPSUploadIntervalSeconds: int(app.config.DriverApp.GPSUploadInterval / time.Second),
+				LocationBatchSize:        app.config.DriverApp.LocationBatchSize,
+				HeartbeatIntervalSeconds: int(app.config.DriverApp.HeartbeatInterval / time.Second),
+			},
+		},
+		app.logger,
+	)
+
+	exportsCfg := app.config.Locations.Exports
+	exportStorage, err := storage.NewSignedLocalStorage(exportsCfg.Dir, exportsCfg.BaseURL, exportsCfg.URLSecret)
+	if err != nil {
+		return fmt.Errorf("failed to init location export storage: %w", err)
+	}
+	app.locationExportService = services.NewLocationExportService(
+		app.locationExportRepo,
+		app.driverRepo,
+		app.locationRepo,
+		exportStorage,
+		services.LocationExportPolicy{
+			Workers:       exportsCfg.Workers,
+			QueueSize:     exportsCfg.QueueSize,
+			Timeout:       exportsCfg.Timeout,
+			MaxRange:      exportsCfg.MaxRange,
+			ChunkInterval: exportsCfg.ChunkInterval,
+			Retention:     exportsCfg.Retention,
+			URLTTL:        exportsCfg.URLTTL,
+			StaleAfter:    exportsCfg.StaleAfter,
+		},
+		app.logger,
+	)
+
+	app.blockService = services.NewBlockService(
+		app.blockRepo,
+		app.driverRepo,
+		app.auditRepo,
+		eventBus,
+		app.logger,
+	)
+
+	app.bulkStatusService = services.NewBulkStatusService(
+		app.driverRepo,
+		app.auditRepo,
+		eventBus,
+		app.logger,
+	)
+
+	app.referralService = services.NewReferralService(
+		app.referralRepo,
+		app.driverRepo,
+		eventBus,
+		services.ReferralPolicy{
+			RequiredTrips: app.config.Referrals.RequiredTrips,
+		},
+		app.logger,
+	)
+	// Допуск и поездки приглашенного водителя продвигают его реферал
+	eventBus.Subscribe(app.referralService.HandleDriverEvent, services.ReferralEventTypes...)
+
+	app.driverInviteService = services.NewDriverInviteService(
+		app.driverInviteRepo,
+		app.fleetRepo,
+		app.driverRepo,
+		app.driverService,
+		app.txManager,
+		services.DriverInvitePolicy{
+			Secret:      app.config.Invites.Secret,
+			LinkBaseURL: app.config.Invites.LinkBaseURL,
+			DefaultTTL:  app.config.Invites.DefaultTTL,
+			MaxTTL:      app.config.Invites.MaxTTL,
+		},
+		app.logger,
+	)
+
+	app.orderTracking = services.NewOrderTrackingService(
+		app.orderTrackingRepo,
+		app.driverRepo,
+		app.locationService,
+		services.OrderTrackingPolicy{
+			Secret:      app.config.OrderTracking.Secret,
+			LinkBaseURL: app.config.OrderTracking.LinkBaseURL,
+			TTL:         app.config.OrderTracking.TTL,
+		},
+		app.logger,
+	)
+
+	app.driverTagService = services.NewDriverTagService(
+		app.driverRepo,
+		services.DriverTagPolicy{
+			Controlled:   app.config.DriverTags.Controlled,
+			FreeForm:     app.config.DriverTags.FreeForm,
+			MaxPerDriver: app.config.DriverTags.MaxPerDriver,
+		},
+		app.logger,
+	)
+
+	app.driverNoteService = services.NewDriverNoteService(
+		app.driverRepo,
+		app.driverNoteRepo,
+		app.auditRepo,
+		app.logger,
+	)
+
+	app.profileChangeService = services.NewProfileChangeService(
+		app.profileChangeRepo,
+		app.driverRepo,
+		app.auditRepo,
+		eventBus,
+		app.logger,
+	)
+
+	app.statusHistoryService = services.NewStatusHistoryService(
+		app.statusHistoryRepo,
+		app.driverRepo,
+		app.logger,
+	)
+
+	app.ratingService = services.NewRatingService(
+		app.ratingRepo,
+		app.driverRepo,
+		eventBus,
+		app.txManager,
+		services.RatingPolicy{
+			MaxPerCustomerPerDay: app.config.Ratings.MaxPerCustomerPerDay,
+			SuspiciousMinRatings: app.config.Ratings.SuspiciousMinRatings,
+			SuspiciousWindow:     app.config.Ratings.SuspiciousWindow,
+			DecayHalfLife:        app.config.Ratings.DecayHalfLife,
+		},
+		app.logger,
+	)
+
+	app.privacyService = services.NewPrivacyService(
+		app.driverRepo,
+		app.documentRepo,
+		app.locationRepo,
+		app.summaryRepo,
+		app.messageRepo,
+		app.ratingRepo,
+		app.shiftRepo,
+		app.auditRepo,
+		documentStorage,
+		eventBus,
+		app.logger,
+	)
+
+	receiptStorage, err := storage.NewLocalStorage(app.config.Expenses.ReceiptDir, app.config.Expenses.ReceiptBaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to init receipt storage: %w", err)
+	}
+	maxAmount := make(map[entities.ExpenseCategory]float64, len(app.config.Expenses.MaxAmount))
+	for category, amount := range app.config.Expenses.MaxAmount {
+		maxAmount[entities.ExpenseCategory(category)] = amount
+	}
+
+	app.expenseService = services.NewExpenseService(
+		app.expenseRepo,
+		app.shiftRepo,
+		receiptStorage,
+		eventBus,
+		services.ExpensePolicy{
+			Currencies:     app.config.Expenses.Currencies,
+			MaxAmount:      maxAmount,
+			MaxPerShift:    app.config.Expenses.MaxPerShift,
+			EditWindow:     app.config.Expenses.EditWindow,
+			MaxReceiptSize: app.config.Expenses.ReceiptMaxSize,
+		},
+		app.logger,
+	)
+
+	// Часовой пояс проверен при загрузке конфигурации
+	earningsLocation, err := time.LoadLocation(app.config.Earnings.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid earnings timezone: %w", err)
+	}
+	app.earningService = services.NewEarningService(
+		app.earningRepo,
+		app.driverRepo,
+		app.shiftRepo,
 		eventBus,
+		services.EarningPolicy{
+			Currencies: app.config.Earnings.Currencies,
+			Location:   earningsLocation,
+		},
+		app.logger,
+	)
+
+	instance, err := app.instanceName()
+	if err != nil {
+		return fmt.Errorf("failed to resolve capacity instance name: %w", err)
+	}
+	// Пул соединений есть только при хранении в PostgreSQL
+	var poolStats services.PoolStatsSource
+	if app.db != nil {
+		poolStats = app.db
+	}
+
+	app.capacityCollector = capacity.NewCollector()
+	app.capacityService = services.NewCapacityService(
+		app.capacityRepo,
+		app.capacityCollector,
+		poolStats,
+		services.CapacityPolicy{
+			Instance:        instance,
+			DefaultLimitRPS: app.config.Capacity.DefaultLimitRPS,
+			EndpointLimits:  app.config.Capacity.EndpointLimits,
+			WindowDays:      app.config.Capacity.WindowDays,
+			HorizonDays:     app.config.Capacity.HorizonDays,
+			RetentionDays:   app.config.Capacity.RetentionDays,
+		},
 		app.logger,
 	)
 
@@ -183,29 +1139,223 @@ func (app *Application) initServices() error {
 	return nil
 }
 
+// locationRetentionTiers возвращает уровни хранения местоположений от мелких интервалов к крупным
+func locationRetentionTiers(cfg config.LocationRetentionConfig) []entities.LocationRetentionTier {
+	tiers := make([]entities.LocationRetentionTier, 0, len(cfg.Tiers))
+	for name, tier := range cfg.Tiers {
+		tiers = append(tiers, entities.LocationRetentionTier{
+			Name:     name,
+			Interval: tier.Interval,
+			Keep:     time.Duration(tier.KeepDays) * 24 * time.Hour,
+		})
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Interval < tiers[j].Interval
+	})
+	return tiers
+}
+
+// longestRetentionTier возвращает уровень сводных точек, хранящийся дольше всех; пусто без уровней
+func longestRetentionTier(tiers []entities.LocationRetentionTier) string {
+	// Keep = 0 — уровень хранится бессрочно
+	var longest *entities.LocationRetentionTier
+	for i := range tiers {
+		tier := &tiers[i]
+		if longest == nil || (longest.Keep > 0 && (tier.Keep <= 0 || tier.Keep > longest.Keep)) {
+			longest = tier
+		}
+	}
+	if longest == nil {
+		return ""
+	}
+	return longest.Name
+}
+
 // initServers инициализирует серверы
 func (app *Application) initServers() error {
 	// HTTP handlers
 	driverHandler := httpHandlers.NewDriverHandler(app.driverService, app.logger)
 	locationHandler := httpHandlers.NewLocationHandler(app.locationService, app.logger)
+	inspectionHandler := httpHandlers.NewInspectionHandler(app.inspectionService, app.logger)
+	shiftHandler := httpHandlers.NewShiftHandler(app.shiftService, app.logger)
+	profileHandler := httpHandlers.NewProfileHandler(app.profileService, app.logger)
+	leaderboardHandler := httpHandlers.NewLeaderboardHandler(app.leaderboardService, app.logger)
+	verificationHandler := httpHandlers.NewVerificationHandler(app.verificationService, app.logger)
+	ratingHandler := httpHandlers.NewRatingHandler(app.ratingService, app.logger)
+	expenseHandler := httpHandlers.NewExpenseHandler(app.expenseService, app.logger)
+	earningHandler := httpHandlers.NewEarningHandler(app.earningService, app.logger)
+	documentHandler := httpHandlers.NewDocumentHandler(app.renewalService, app.logger)
+	capacityHandler := httpHandlers.NewCapacityHandler(app.capacityService, app.logger)
+	driverVerificationHandler := httpHandlers.NewDriverVerificationHandler(app.driverVerification, app.logger)
+	auditHandler := httpHandlers.NewAuditHandler(app.auditService, app.logger)
+	securityHandler := httpHandlers.NewSecurityHandler(app.securityService, app.logger)
+	reverificationHandler := httpHandlers.NewReverificationHandler(app.reverification, app.logger)
+	privacyHandler := httpHandlers.NewPrivacyHandler(app.privacyService, app.logger)
+	tripHandler := httpHandlers.NewTripHandler(app.tripService, app.logger)
+	locationSummaryHandler := httpHandlers.NewLocationSummaryHandler(app.locationRetention, app.logger)
+	scheduleHandler := httpHandlers.NewScheduleHandler(app.scheduleService, app.logger)
+	messageHandler := httpHandlers.NewMessageHandler(app.messageService, app.logger)
+	geofenceHandler := httpHandlers.NewGeofenceHandler(app.geofenceService, app.logger)
+	fleetHandler := httpHandlers.NewFleetHandler(app.fleetService, app.logger)
+	dispatchHandler := httpHandlers.NewDispatchHandler(app.dispatchService, app.logger)
+	heartbeatHandler := httpHandlers.NewHeartbeatHandler(app.heartbeatService, app.logger)
+	statsHandler := httpHandlers.NewDriverStatsHandler(app.statsService, app.logger)
+
+	registrars := []httpServer.RouteRegistrar{
+		inspectionHandler,
+		shiftHandler,
+		profileHandler,
+		leaderboardHandler,
+		verificationHandler,
+		ratingHandler,
+		expenseHandler,
+		earningHandler,
+		documentHandler,
+		capacityHandler,
+		driverVerificationHandler,
+		auditHandler,
+		securityHandler,
+		reverificationHandler,
+		privacyHandler,
+		tripHandler,
+		locationSummaryHandler,
+		scheduleHandler,
+		messageHandler,
+		geofenceHandler,
+		fleetHandler,
+		dispatchHandler,
+		heartbeatHandler,
+		statsHandler,
+		httpHandlers.NewSupplyHandler(app.supplyService, app.logger),
+		httpHandlers.NewDeviceHandler(app.deviceService, app.logger),
+		httpHandlers.NewBlockHandler(app.blockService, app.logger),
+		httpHandlers.NewReferralHandler(app.referralService, app.logger),
+		httpHandlers.NewDriverInviteHandler(app.driverInviteService, app.logger),
+		httpHandlers.NewOrderTrackingHandler(app.orderTracking, app.config.OrderTracking.RequestsPerMinute, app.logger),
+		httpHandlers.NewStatusHistoryHandler(app.statusHistoryService, app.logger),
+		httpHandlers.NewCityHandler(app.cityService, app.logger),
+		httpHandlers.NewFeatureFlagHandler(app.featureFlagService, app.logger),
+		httpHandlers.NewLocationExportHandler(app.locationExportService, app.logger),
+		httpHandlers.NewLocationAnalyticsHandler(app.locationAnalytics, app.logger),
+		httpHandlers.NewDriverTagHandler(app.driverTagService, app.logger),
+		httpHandlers.NewWebhookHandler(app.webhookService, app.logger),
+		httpHandlers.NewPhoneVerificationHandler(app.phoneVerificationService, app.logger),
+		httpHandlers.NewDriverNoteHandler(app.driverNoteService, app.logger),
+		httpHandlers.NewProfileChangeHandler(app.profileChangeService, app.logger),
+		httpHandlers.NewStatusOverrideHandler(app.driverService, app.logger),
+		httpHandlers.NewBulkStatusHandler(app.bulkStatusService, app.logger),
+		httpHandlers.NewAPIKeyHandler(app.apiKeyService, app.logger),
+		httpHandlers.NewEventCatalogHandler(),
+		httpHandlers.NewJobsHandler(app.scheduler, app.logger),
+		wsServer.NewHandler(app.wsHub, app.logger),
+		wsServer.NewChatHandler(app.wsHub, app.messageService, app.logger),
+	}
+	// Статистика пула и повторов доступна только при хранении в PostgreSQL
+	if app.db != nil {
+		registrars = append(registrars, httpHandlers.NewDatabaseHandler(app.db))
+	}
+
+	verifier, err := app.initTokenVerifier()
+	if err != nil {
+		return err
+	}
+
+	var recorder middleware.RequestRecorder = app.capacityCollector
+	if cfg := app.config.Metrics.SLO; cfg.Enabled {
+		app.sloTracker = slo.NewTracker(sloObjectives(cfg), cfg.Window, cfg.Resolution)
+		recorder = middleware.Recorders(app.capacityCollector, app.sloTracker)
+	}
 
 	// HTTP server
 	app.httpServer = httpServer.NewServer(
 		app.config,
 		app.logger,
+		verifier,
+		recorder,
+		app.securityService,
+		app.apiKeyService,
 		driverHandler,
 		locationHandler,
+		registrars...,
+	)
+	app.httpServer.SetSLOTracker(app.sloTracker)
+
+	// gRPC server
+	app.grpcServer = grpcServer.NewServer(
+		app.config,
+		app.logger,
+		verifier,
+		app.driverService,
+		app.locationService,
 	)
 
 	app.logger.Info("Servers initialized")
 	return nil
 }
 
+// sloObjectives переводит цели операций из конфигурации; метод маршрута приводится к
+// верхнему регистру, как в шаблонах маршрутов gin
+func sloObjectives(cfg config.SLOConfig) []slo.Objective {
+	objectives := make([]slo.Objective, 0, len(cfg.Objectives))
+	for name, objective := range cfg.Objectives {
+		routes := make([]string, 0, len(objective.Routes))
+		for _, route := range objective.Routes {
+			if parts := strings.Fields(route); len(parts) == 2 {
+				routes = append(routes, strings.ToUpper(parts[0])+" "+parts[1])
+			}
+		}
+		objectives = append(objectives, slo.Objective{
+			Name:                  name,
+			Routes:                routes,
+			LatencyTarget:         objective.LatencyTarget,
+			LatencyObjective:      objective.LatencyObjective,
+			AvailabilityObjective: objective.AvailabilityObjective,
+		})
+	}
+	return objectives
+}
+
+// initTokenVerifier создает проверку JWT; при выключенной аутентификации возвращает nil
+func (app *Application) initTokenVerifier() (middleware.TokenVerifier, error) {
+	cfg := app.config.Auth
+	if !cfg.Enabled {
+		app.logger.Warn("HTTP API authentication is disabled")
+		return nil, nil
+	}
+
+	var publicKey []byte
+	if cfg.PublicKeyFile != "" {
+		data, err := os.ReadFile(cfg.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read auth public key: %w", err)
+		}
+		publicKey = data
+	}
+
+	verifier, err := middleware.NewJWTVerifier(middleware.JWTConfig{
+		Issuer:              cfg.Issuer,
+		Audience:            cfg.Audience,
+		HMACSecret:          cfg.HMACSecret,
+		PublicKeyPEM:        publicKey,
+		JWKSURL:             cfg.JWKSURL,
+		JWKSRefreshInterval: cfg.JWKSRefreshInterval,
+		ClockSkew:           cfg.ClockSkew,
+		RolesClaim:          cfg.RolesClaim,
+		DriverIDClaim:       cfg.DriverIDClaim,
+		FleetIDsClaim:       cfg.FleetIDsClaim,
+		ScopesClaim:         cfg.ScopesClaim,
+	}, app.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init token verifier: %w", err)
+	}
+
+	return verifier, nil
+}
+
 // Run запускает приложение
 func (app *Application) Run() error {
 	// Запускаем background задачи
-	app.wg.Add(1)
-	go app.runBackgroundTasks()
+	app.scheduler.Start()
 
 	// Запускаем HTTP сервер
 	app.wg.Add(1)
@@ -216,6 +1366,25 @@ func (app *Application) Run() error {
 		}
 	}()
 
+	// Прогрев идет после запуска HTTP сервера: до его завершения проба /ready отвечает 503
+	if app.warmer != nil {
+		go app.warmer.Run(context.Background())
+	}
+
+	// Запускаем gRPC сервер
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		if err := app.grpcServer.Start(); err != nil {
+			app.logger.Error("gRPC server failed", zap.Error(err))
+		}
+	}()
+
+	// Перечитываем конфигурацию по SIGHUP и при изменении файла
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+	go config.Watch(watchCtx, app.config.Reload.WatchInterval, app.reloadConfig)
+
 	// Ждем сигнал для завершения
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
@@ -231,46 +1400,412 @@ func (app *Application) Run() error {
 	return app.gracefulShutdown()
 }
 
-// runBackgroundTasks запускает фоновые задачи
-func (app *Application) runBackgroundTasks() {
-	defer app.wg.Done()
+// reloadConfig перечитывает конфигурацию и применяет настройки, которые меняются без
+// перезапуска: уровень логирования, лимиты частоты запросов, веса подбора водителя и
+// расписания фоновых задач.
+// Некорректная конфигурация отклоняется целиком, действующие настройки сохраняются
+func (app *Application) reloadConfig() {
+	next, err := config.LoadConfig()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		app.logger.Error("Failed to reload config, keeping current settings", zap.Error(err))
+		return
+	}
 
-	// Cleanup старых местоположений
-	cleanupTicker := time.NewTicker(24 * time.Hour)
-	defer cleanupTicker.Stop()
+	level, err := zapcore.ParseLevel(next.Logger.Level)
+	if err != nil {
+		app.logger.Error("Failed to reload config, keeping current settings", zap.Error(err))
+		return
+	}
+	if level != app.logLevel.Level() {
+		app.logger.Info("Log level changed",
+			zap.String("from", app.logLevel.Level().String()),
+			zap.String("to", level.String()),
+		)
+		app.logLevel.SetLevel(level)
+	}
+
+	app.capacityService.SetLimits(next.Capacity.DefaultLimitRPS, next.Capacity.EndpointLimits)
+	app.dispatchService.SetWeights(dispatchWeights(next.Dispatch.Weights))
 
-	for {
-		select {
-		case <-cleanupTicker.C:
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
-			if err := app.locationService.CleanupOldLocations(ctx); err != nil {
-				app.logger.Error("Failed to cleanup old locations", zap.Error(err))
+	for _, job := range app.scheduler.Jobs() {
+		jobCfg, ok := next.Scheduler.Jobs[job.Name]
+		if !ok || jobCfg.Disabled || jobCfg.Schedule == "" {
+			// Выключение задачи применяется при перезапуске
+			continue
+		}
+		if err := app.scheduler.Reschedule(job.Name, jobCfg.Schedule, jobCfg.Timeout); err != nil {
+			app.logger.Error("Failed to reschedule background job", zap.Error(err), zap.String("job", job.Name))
+		}
+	}
+
+	if app.config.RequiresRestart(next) {
+		app.logger.Warn("Config has changes that take effect only after restart")
+	}
+	app.logger.Info("Config reloaded")
+}
+
+// dispatchWeights переводит веса оценки кандидатов из конфигурации
+func dispatchWeights(weights config.DispatchWeightsConfig) entities.DispatchWeights {
+	return entities.DispatchWeights{
+		Distance:     weights.Distance,
+		Rating:       weights.Rating,
+		Acceptance:   weights.Acceptance,
+		Idle:         weights.Idle,
+		Cancellation: weights.Cancellation,
+	}
+}
+
+// initMessaging подключается к NATS и подписывается на входящие события
+func (app *Application) initMessaging() error {
+	conn, err := messaging.Connect(&app.config.NATS, app.logger)
+	if err != nil {
+		return err
+	}
+	app.natsConn = conn
+
+	app.billingConsumer = messaging.NewBillingConsumer(conn, app.driverService, app.logger)
+	if err := app.billingConsumer.Start(); err != nil {
+		return err
+	}
+
+	// Мобильный шлюз публикует точки GPS в NATS
+	if app.config.NATS.Locations.Enabled {
+		app.locationConsumer = natsConsumer.NewLocationConsumer(conn, app.locationService, app.config.NATS.Locations, app.logger)
+		if err := app.locationConsumer.Start(); err != nil {
+			return err
+		}
+	}
+
+	// GPS-трекеры автопарков публикуют точки в MQTT; ID клиента у каждого экземпляра свой
+	if app.config.MQTT.Enabled {
+		instance, err := app.instanceName()
+		if err != nil {
+			return fmt.Errorf("failed to resolve instance name: %w", err)
+		}
+		clientID := app.config.MQTT.ClientID + "-" + instance
+		app.trackerConsumer = mqttConsumer.NewLocationConsumer(clientID, app.locationService, app.deviceService, app.config.MQTT, app.logger)
+		if err := app.trackerConsumer.Start(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// initWarmup регистрирует шаги прогрева после запуска и подключает его к пробе готовности
+func (app *Application) initWarmup() {
+	cfg := app.config.Warmup
+	if !cfg.Enabled {
+		return
+	}
+
+	app.warmer = warmup.New(cfg.Timeout, app.logger)
+
+	// Соединения с базой и шардами открываются заранее, чтобы первые запросы не ждали их установки
+	if app.db != nil {
+		connections := cfg.Connections
+		if connections == 0 {
+			connections = app.config.Database.MaxIdleConns
+		}
+		app.warmer.Add("database_connections", func(ctx context.Context) (int, error) {
+			opened, err := app.db.WarmConnections(ctx, connections)
+			if err != nil {
+				return opened, err
+			}
+			for name, shard := range app.shards {
+				if shard == app.db {
+					continue
+				}
+				n, err := shard.WarmConnections(ctx, connections)
+				opened += n
+				if err != nil {
+					return opened, fmt.Errorf("shard %s: %w", name, err)
+				}
 			}
-			cancel()
+			return opened, nil
+		})
+	}
 
-		case <-app.shutdown:
-			app.logger.Info("Stopping background tasks")
-			return
+	var hotDrivers []uuid.UUID
+	app.warmer.Add("active_drivers", func(ctx context.Context) (int, error) {
+		drivers, err := app.driverService.GetActiveDrivers(ctx)
+		if err != nil {
+			return 0, err
 		}
+		for _, driver := range drivers {
+			if len(hotDrivers) == cfg.HotDrivers {
+				break
+			}
+			hotDrivers = append(hotDrivers, driver.ID)
+		}
+		return len(drivers), nil
+	})
+
+	// Последние точки активных водителей читаются заранее, чтобы их страницы были в кэше базы
+	app.warmer.Add("current_locations", func(ctx context.Context) (int, error) {
+		loaded := 0
+		for _, driverID := range hotDrivers {
+			if _, err := app.locationRepo.GetLatestByDriverID(ctx, driverID); err != nil {
+				if err == entities.ErrLocationNotFound {
+					continue
+				}
+				return loaded, err
+			}
+			loaded++
+		}
+		return loaded, nil
+	})
+
+	app.warmer.Add("leaderboards", app.leaderboardService.WarmCache)
+
+	app.httpServer.SetWarmer(app.warmer)
+}
+
+// initHealth регистрирует проверки зависимостей для пробы готовности
+func (app *Application) initHealth() {
+	checker := health.NewChecker(app.config.Health.Timeout)
+
+	if app.db != nil {
+		latest, latestErr := database.LatestMigrationVersion(database.Migrations())
+		if latestErr != nil {
+			app.logger.Error("Failed to read migrations for health check", zap.Error(latestErr))
+		}
+
+		// Основная база и шарды проверяются одинаково: соединение и состояние схемы
+		databases := map[string]*database.DB{"postgres": app.db}
+		for name, shard := range app.shards {
+			if shard != app.db {
+				databases["postgres:"+name] = shard
+			}
+		}
+		for name, db := range databases {
+			db := db
+			checker.Add(name, func(ctx context.Context) error {
+				return db.PingContext(ctx)
+			})
+			checker.Add(name+":migrations", func(ctx context.Context) error {
+				if latestErr != nil {
+					return latestErr
+				}
+				return db.CheckMigrations(ctx, latest)
+			})
+		}
+	}
+
+	checker.Add("nats", func(ctx context.Context) error {
+		return messaging.Ping(ctx, app.natsConn)
+	})
+	if app.trackerConsumer != nil {
+		checker.Add("mqtt", app.trackerConsumer.Ping)
 	}
+
+	if app.config.Health.CheckRedis {
+		redis := app.config.Redis
+		checker.Add("redis", health.RedisPing(redis.GetRedisAddr(), redis.Password, redis.Database))
+	}
+
+	app.httpServer.SetHealthChecker(checker)
 }
 
-// gracefulShutdown выполняет graceful shutdown
+// instanceName имя экземпляра сервиса в отчетах о нагрузке и истории фоновых задач:
+// capacity.instance или имя хоста (имя пода в Kubernetes)
+func (app *Application) instanceName() (string, error) {
+	if app.config.Capacity.Instance != "" {
+		return app.config.Capacity.Instance, nil
+	}
+	return os.Hostname()
+}
+
+// initScheduler регистрирует фоновые задачи в планировщике
+func (app *Application) initScheduler() error {
+	sched, err := scheduler.New(app.config.Scheduler.Timezone, app.logger)
+	if err != nil {
+		return err
+	}
+
+	instance, err := app.instanceName()
+	if err != nil {
+		return fmt.Errorf("failed to resolve scheduler instance name: %w", err)
+	}
+	sched.SetHistory(app.jobRunRepo, instance)
+	// Без общей базы экземпляры не видят блокировки друг друга
+	if app.config.Scheduler.Locking && app.db != nil {
+		sched.SetLocker(database.NewAdvisoryLocker(app.db, "driver-service.jobs"))
+		sched.SetClaimer(app.jobRunRepo)
+	}
+
+	jobs := map[string]scheduler.JobFunc{
+		config.JobLocationCleanup: func(ctx context.Context) error {
+			_, err := app.locationRetention.ApplyRetention(ctx)
+			return err
+		},
+		config.JobLocationPartitions: func(ctx context.Context) error {
+			_, err := app.locationRetention.EnsurePartitions(ctx)
+			return err
+		},
+		config.JobInspectionReminders: func(ctx context.Context) error {
+			_, err := app.inspectionService.SendDueReminders(ctx)
+			return err
+		},
+		config.JobProfileNudges: func(ctx context.Context) error {
+			_, err := app.profileService.SendProfileNudges(ctx)
+			return err
+		},
+		config.JobLeaderboardRefresh: app.leaderboardService.RefreshLeaderboards,
+		config.JobReleaseStaleClaims: func(ctx context.Context) error {
+			_, err := app.verificationService.ReleaseStaleClaims(ctx)
+			return err
+		},
+		config.JobDocumentReminders: func(ctx context.Context) error {
+			_, err := app.renewalService.SendExpiryReminders(ctx)
+			return err
+		},
+		config.JobResubmissionReminders: func(ctx context.Context) error {
+			_, err := app.renewalService.SendResubmissionReminders(ctx)
+			return err
+		},
+		config.JobDocumentExpiry: func(ctx context.Context) error {
+			_, err := app.driverVerification.ExpireDocuments(ctx)
+			return err
+		},
+		config.JobCampaignReminders: func(ctx context.Context) error {
+			_, err := app.reverification.SendReminders(ctx)
+			return err
+		},
+		config.JobCampaignDeadlines: func(ctx context.Context) error {
+			_, err := app.reverification.EnforceDeadlines(ctx)
+			return err
+		},
+		config.JobScheduleEnforcement: func(ctx context.Context) error {
+			_, err := app.scheduleService.EnforceSchedules(ctx)
+			return err
+		},
+		config.JobMessageCleanup: func(ctx context.Context) error {
+			_, err := app.messageService.CleanupOldMessages(ctx)
+			return err
+		},
+		config.JobShiftAutoEnd: func(ctx context.Context) error {
+			_, err := app.shiftService.EndOverdueShifts(ctx)
+			return err
+		},
+		config.JobOfflineDetection: func(ctx context.Context) error {
+			_, err := app.heartbeatService.DetectOffline(ctx)
+			return err
+		},
+		config.JobRatingRecompute: func(ctx context.Context) error {
+			_, err := app.ratingService.RecomputeRatings(ctx)
+			return err
+		},
+		config.JobCapacitySample: app.capacityService.SamplePool,
+		config.JobCapacityReport: func(ctx context.Context) error {
+			_, err := app.capacityService.GenerateDailyReport(ctx)
+			return err
+		},
+		config.JobBlockExpiry: func(ctx context.Context) error {
+			_, err := app.blockService.ReleaseExpired(ctx)
+			return err
+		},
+		config.JobReferralProgress: func(ctx context.Context) error {
+			_, err := app.referralService.ProcessOpen(ctx)
+			return err
+		},
+		config.JobLocationExportCleanup: func(ctx context.Context) error {
+			_, err := app.locationExportService.CleanupExpired(ctx)
+			return err
+		},
+		config.JobDriverDailyStats: func(ctx context.Context) error {
+			_, err := app.statsService.RefreshDailyStats(ctx)
+			return err
+		},
+		config.JobWebhookRetry: func(ctx context.Context) error {
+			_, err := app.webhookService.RetryDue(ctx)
+			return err
+		},
+		config.JobRunHistoryCleanup: func(ctx context.Context) error {
+			retention := app.config.Scheduler.HistoryRetentionDays
+			if retention == 0 {
+				return nil
+			}
+			_, err := app.jobRunRepo.DeleteOlderThan(ctx, time.Now().AddDate(0, 0, -retention))
+			return err
+		},
+	}
+
+	// Задачи обслуживают сам экземпляр: его пул соединений и карту шардов
+	perInstance := map[string]bool{
+		config.JobCapacitySample: true,
+		config.JobCapacityReport: true,
+		config.JobShardRefresh:   true,
+	}
+
+	// Хеш цепочки аудита закрепляется, только если настроен внешний журнал
+	if app.auditAnchorSink != nil {
+		jobs[config.JobAuditAnchor] = func(ctx context.Context) error {
+			_, err := app.auditService.AnchorChain(ctx)
+			return err
+		}
+	}
+
+	// Назначения городов шардам перечитываются, только если шардирование включено
+	if app.shardRouter != nil {
+		jobs[config.JobShardRefresh] = func(ctx context.Context) error {
+			return repositories.LoadShardAssignments(ctx, app.shardRepo, app.shardRouter)
+		}
+	}
+
+	for name, fn := range jobs {
+		jobCfg, ok := app.config.Scheduler.Jobs[name]
+		if !ok || jobCfg.Disabled || jobCfg.Schedule == "" {
+			app.logger.Info("Background job disabled", zap.String("job", name))
+			continue
+		}
+
+		register := sched.Register
+		if perInstance[name] {
+			register = sched.RegisterPerInstance
+		}
+		if err := register(name, jobCfg.Schedule, jobCfg.Timeout, fn); err != nil {
+			return err
+		}
+	}
+
+	app.scheduler = sched
+	return nil
+}
+
+// gracefulShutdown выполняет graceful shutdown. Буферы подсистем дописываются до закрытия
+// базы, каждый в пределах своего срока; потерянные элементы попадают в итоговый отчет
 func (app *Application) gracefulShutdown() error {
 	app.logger.Info("Starting graceful shutdown")
 
+	shutdownCfg := app.config.Server.Shutdown
+
 	// Создаем контекст с таймаутом для shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownCfg.Timeout)
 	defer cancel()
 
 	// Закрываем канал для уведомления background задач
 	close(app.shutdown)
 
-	// Останавливаем HTTP сервер
+	// Останавливаем планировщик, дожидаясь выполняющихся задач
+	if err := app.scheduler.Stop(ctx); err != nil {
+		app.logger.Error("Background jobs did not finish in time", zap.Error(err))
+	}
+
+	// Останавливаем HTTP сервер. WebSocket подписчики остаются подключенными, пока
+	// не будут отправлены уведомления о дописанных точках
 	if err := app.httpServer.Stop(ctx); err != nil {
 		app.logger.Error("Failed to stop HTTP server", zap.Error(err))
 	}
 
+	// Останавливаем gRPC сервер
+	if err := app.grpcServer.Stop(ctx); err != nil {
+		app.logger.Error("Failed to stop gRPC server", zap.Error(err))
+	}
+
 	// Ждем завершения background задач
 	done := make(chan struct{})
 	go func() {
@@ -285,9 +1820,45 @@ func (app *Application) gracefulShutdown() error {
 		app.logger.Error("Shutdown timeout exceeded")
 	}
 
-	// Закрываем подключение к базе данных
-	if err := app.db.Close(); err != nil {
-		app.logger.Error("Failed to close database connection", zap.Error(err))
+	// Отписываемся от входящих событий, закрываем подключения к NATS и брокеру MQTT
+	app.billingConsumer.Stop()
+	if app.locationConsumer != nil {
+		app.locationConsumer.Stop()
+	}
+	if app.trackerConsumer != nil {
+		app.trackerConsumer.Stop()
+	}
+	app.natsConn.Close()
+
+	// Сохраняем распознавание документов из очереди, дописываем точки из буфера асинхронной
+	// записи, затем отправляем подписчикам уведомления о них. Все подсистемы должны
+	// закончить до закрытия базы
+	var report shutdown.Report
+	if app.documentOCR != nil {
+		report.Drain(ctx, "document_ocr", app.config.External.OCR.Timeout, app.documentOCR.Drain)
+	}
+	// Выгрузки, не сформированные к сроку, задача location_export_cleanup завершит ошибкой
+	report.Drain(ctx, "location_exports", shutdownCfg.LocationDrainTimeout, app.locationExportService.Drain)
+	report.Drain(ctx, "locations", shutdownCfg.LocationDrainTimeout, app.locationService.Drain)
+	// Копии точек, не записанные в ClickHouse к сроку, теряются только для аналитики
+	if app.locationSink != nil {
+		report.Drain(ctx, "location_analytics", shutdownCfg.LocationDrainTimeout, app.locationSink.Drain)
+	}
+	// Доставки вебхуков сохранены до отправки: не отправленные к сроку повторит задача webhook_retry
+	report.Drain(ctx, "webhooks", app.config.Webhooks.Delivery.Timeout, app.webhookService.Drain)
+	report.Drain(ctx, "websocket", shutdownCfg.WebSocketDrainTimeout, func(ctx context.Context) (int, error) {
+		return app.wsHub.Drain(ctx), nil
+	})
+	report.Publish(app.logger)
+
+	// Закрываем подключения к шардам и основной базе данных
+	if app.shards != nil {
+		database.CloseShards(app.shards, app.db)
+	}
+	if app.db != nil {
+		if err := app.db.Close(); err != nil {
+			app.logger.Error("Failed to close database connection", zap.Error(err))
+		}
 	}
 
 	app.logger.Info("Graceful shutdown completed")
@@ -307,4 +1878,47 @@ func (m *mockEventPublisher) PublishDriverEvent(ctx context.Context, eventType s
 		zap.Any("data", data),
 	)
 	return nil
-}
\ No newline at end of file
+}
+
+// newNotificationService создает сервис уведомлений с каналами и шаблонами из конфигурации
+func (app *Application) newNotificationService() (services.NotificationService, error) {
+	cfg := app.config.Notifications
+
+	providers, err := notifications.New(cfg, app.config.External.SMSAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := services.NotificationPolicy{
+		DefaultChannels: notificationChannels(cfg.DefaultChannels),
+		Location:        loc,
+	}
+	for _, tmpl := range cfg.Templates {
+		policy.Templates = append(policy.Templates, entities.NotificationTemplate{
+			Name:     tmpl.Name,
+			Channels: notificationChannels(tmpl.Channels),
+			Subject:  tmpl.Subject,
+			Body:     tmpl.Body,
+		})
+	}
+
+	if len(providers) == 0 {
+		app.logger.Warn("No notification providers configured, driver notifications are not delivered")
+	}
+
+	return services.NewNotificationService(app.driverRepo, providers, policy, app.logger)
+}
+
+// notificationChannels приводит названия каналов из конфигурации к entities.NotificationChannel
+func notificationChannels(names []string) []entities.NotificationChannel {
+	channels := make([]entities.NotificationChannel, 0, len(names))
+	for _, name := range names {
+		channels = append(channels, entities.NotificationChannel(name))
+	}
+	return channels
+}
@@ -0,0 +1,105 @@
+// Команда shardctl управляет назначением городов шардам местоположений:
+//
+//	shardctl assignments                  — действующие назначения городов
+//	shardctl rebalance -city X -to Y      — перенос местоположений города на шард Y
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"driver-service/internal/config"
+	"driver-service/internal/infrastructure/database"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "shardctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: shardctl assignments | rebalance -city <city> -to <shard>")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.Sharding.Enabled {
+		return fmt.Errorf("sharding is disabled (sharding.enabled)")
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logger.Sync()
+
+	primary, err := database.NewPostgresDB(&cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer primary.Close()
+
+	shards, err := database.OpenShards(cfg, primary, logger)
+	if err != nil {
+		return err
+	}
+	defer database.CloseShards(shards, primary)
+
+	router, err := database.NewShardRouter(shards, cfg.Sharding.DefaultShard, cfg.Sharding.Cities, logger)
+	if err != nil {
+		return err
+	}
+	shardRepo := repositories.NewShardRepository(primary, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "assignments":
+		if err := repositories.LoadShardAssignments(ctx, shardRepo, router); err != nil {
+			return err
+		}
+		return printJSON(router.Assignments())
+	case "rebalance":
+		flags := flag.NewFlagSet("rebalance", flag.ContinueOnError)
+		city := flags.String("city", "", "город для переноса")
+		target := flags.String("to", "", "шард назначения")
+		if err := flags.Parse(args[1:]); err != nil {
+			return err
+		}
+		if *city == "" || *target == "" {
+			return fmt.Errorf("-city and -to are required")
+		}
+
+		rebalancer := repositories.NewLocationRebalancer(router, shardRepo, logger)
+		result, err := rebalancer.Rebalance(ctx, *city, *target,
+			cfg.Sharding.RebalanceBatchSize, cfg.Sharding.RebalanceLockWait)
+		if result != nil {
+			if printErr := printJSON(result); printErr != nil {
+				return printErr
+			}
+		}
+		return err
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
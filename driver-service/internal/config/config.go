@@ -1,8 +1,12 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -10,22 +14,101 @@ import (
 
 // Config структура конфигурации приложения
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	NATS     NATSConfig     `mapstructure:"nats"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
-	External ExternalConfig `mapstructure:"external"`
-	Metrics  MetricsConfig  `mapstructure:"metrics"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Storage       StorageConfig       `mapstructure:"storage"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Sharding      ShardingConfig      `mapstructure:"sharding"`
+	Locations     LocationsConfig     `mapstructure:"locations"`
+	Redis         RedisConfig         `mapstructure:"redis"`
+	NATS          NATSConfig          `mapstructure:"nats"`
+	MQTT          MQTTConfig          `mapstructure:"mqtt"`
+	Logger        LoggerConfig        `mapstructure:"logger"`
+	External      ExternalConfig      `mapstructure:"external"`
+	Metrics       MetricsConfig       `mapstructure:"metrics"`
+	Inspections   InspectionsConfig   `mapstructure:"inspections"`
+	Profile       ProfileConfig       `mapstructure:"profile"`
+	Scheduler     SchedulerConfig     `mapstructure:"scheduler"`
+	WebSocket     WebSocketConfig     `mapstructure:"websocket"`
+	Leaderboard   LeaderboardConfig   `mapstructure:"leaderboard"`
+	Verification  VerificationConfig  `mapstructure:"verification"`
+	Documents     DocumentsConfig     `mapstructure:"documents"`
+	Capacity      CapacityConfig      `mapstructure:"capacity"`
+	Expenses      ExpensesConfig      `mapstructure:"expenses"`
+	Earnings      EarningsConfig      `mapstructure:"earnings"`
+	Auth          AuthConfig          `mapstructure:"auth"`
+	Webhooks      WebhooksConfig      `mapstructure:"webhooks"`
+	Security      SecurityConfig      `mapstructure:"security"`
+	Campaigns     CampaignsConfig     `mapstructure:"campaigns"`
+	Warmup        WarmupConfig        `mapstructure:"warmup"`
+	Health        HealthConfig        `mapstructure:"health"`
+	Audit         AuditConfig         `mapstructure:"audit"`
+	Schedules     SchedulesConfig     `mapstructure:"schedules"`
+	Messages      MessagesConfig      `mapstructure:"messages"`
+	Geofences     GeofencesConfig     `mapstructure:"geofences"`
+	Cities        CitiesConfig        `mapstructure:"cities"`
+	DriverApp     DriverAppConfig     `mapstructure:"driver_app"`
+	Dispatch      DispatchConfig      `mapstructure:"dispatch"`
+	Shifts        ShiftsConfig        `mapstructure:"shifts"`
+	Ratings       RatingsConfig       `mapstructure:"ratings"`
+	Referrals     ReferralsConfig     `mapstructure:"referrals"`
+	Invites       InvitesConfig       `mapstructure:"invites"`
+	OrderTracking OrderTrackingConfig `mapstructure:"order_tracking"`
+	DriverTags    DriverTagsConfig    `mapstructure:"driver_tags"`
+	Phone         PhoneConfig         `mapstructure:"phone"`
+	Heartbeat     HeartbeatConfig     `mapstructure:"heartbeat"`
+	Statistics    StatisticsConfig    `mapstructure:"statistics"`
+	Notifications NotificationsConfig `mapstructure:"notifications"`
+	Secrets       SecretsConfig       `mapstructure:"secrets"`
+	Reload        ReloadConfig        `mapstructure:"reload"`
 }
 
 // ServerConfig конфигурация HTTP и gRPC серверов
 type ServerConfig struct {
-	HTTPPort    int           `mapstructure:"http_port"`
-	GRPCPort    int           `mapstructure:"grpc_port"`
-	MetricsPort int           `mapstructure:"metrics_port"`
-	Timeout     time.Duration `mapstructure:"timeout"`
-	Environment string        `mapstructure:"environment"`
+	HTTPPort    int            `mapstructure:"http_port"`
+	GRPCPort    int            `mapstructure:"grpc_port"`
+	MetricsPort int            `mapstructure:"metrics_port"`
+	Timeout     time.Duration  `mapstructure:"timeout"`
+	Environment string         `mapstructure:"environment"`
+	Shutdown    ShutdownConfig `mapstructure:"shutdown"`
+	// Language язык сообщений об ошибках API для клиентов без подходящего Accept-Language
+	Language string `mapstructure:"language"`
+	// Compression сжатие ответов REST API
+	Compression CompressionConfig `mapstructure:"compression"`
+}
+
+// CompressionConfig сжатие ответов REST API кодировкой из Accept-Encoding (zstd или gzip)
+type CompressionConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MinSize ответы короче этого числа байт отправляются без сжатия
+	MinSize int `mapstructure:"min_size"`
+}
+
+// ShutdownConfig сроки остановки сервиса
+type ShutdownConfig struct {
+	// Timeout общий срок остановки
+	Timeout time.Duration `mapstructure:"timeout"`
+	// LocationDrainTimeout срок записи точек из буфера асинхронной записи; не записанные
+	// за это время точки теряются
+	LocationDrainTimeout time.Duration `mapstructure:"location_drain_timeout"`
+	// WebSocketDrainTimeout срок отправки сообщений из буферов WebSocket подписчиков
+	WebSocketDrainTimeout time.Duration `mapstructure:"websocket_drain_timeout"`
+}
+
+// Типы хранилища данных
+const (
+	StorageTypePostgres = "postgres"
+	StorageTypeMemory   = "memory"
+)
+
+// StorageConfig конфигурация хранилища данных
+type StorageConfig struct {
+	// Type тип хранилища: postgres или memory (только для тестов и локальной разработки)
+	Type string `mapstructure:"type"`
+}
+
+// IsMemory проверяет, используется ли in-memory хранилище
+func (c *StorageConfig) IsMemory() bool {
+	return c.Type == StorageTypeMemory
 }
 
 // DatabaseConfig конфигурация PostgreSQL
@@ -39,6 +122,197 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	// Повторы идемпотентных операций при временных ошибках (конфликт сериализации, обрыв соединения)
+	RetryMaxAttempts int           `mapstructure:"retry_max_attempts"`
+	RetryBaseDelay   time.Duration `mapstructure:"retry_base_delay"`
+	RetryMaxDelay    time.Duration `mapstructure:"retry_max_delay"`
+	// SlowQueryThreshold запросы дольше логируются с ID запроса клиента; 0 — не логировать
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold"`
+	// Replicas реплики для чтения; незаданные параметры подключения берутся из основной базы.
+	// Учитываются только в секции database
+	Replicas map[string]DatabaseConfig `mapstructure:"replicas"`
+	// ReplicaCheckInterval как часто проверяется доступность и отставание реплик
+	ReplicaCheckInterval time.Duration `mapstructure:"replica_check_interval"`
+	// ReplicaMaxLag отставание, после которого реплика не используется для чтения; 0 — не проверять
+	ReplicaMaxLag time.Duration `mapstructure:"replica_max_lag"`
+}
+
+// ShardingConfig конфигурация геошардирования местоположений водителей по городам
+type ShardingConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Shards базы данных шардов; незаданные параметры подключения берутся из секции database.
+	// Основная база данных — шард primary
+	Shards map[string]DatabaseConfig `mapstructure:"shards"`
+	// Cities закрепление городов за шардами; переносы городов сохраняются в таблице city_shards
+	// и имеют приоритет над конфигурацией
+	Cities       map[string]string `mapstructure:"cities"`
+	DefaultShard string            `mapstructure:"default_shard"`
+	// RebalanceLockWait пауза после блокировки города перед копированием: за нее все экземпляры
+	// сервиса должны обновить назначения (задача shard_refresh)
+	RebalanceLockWait  time.Duration `mapstructure:"rebalance_lock_wait"`
+	RebalanceBatchSize int           `mapstructure:"rebalance_batch_size"`
+}
+
+// LocationsConfig конфигурация обработки истории местоположений
+type LocationsConfig struct {
+	// MaxGapInterval интервал между точками, после которого трек считается разорванным (0 — не искать разрывы)
+	MaxGapInterval time.Duration `mapstructure:"max_gap_interval"`
+	Trips          TripsConfig   `mapstructure:"trips"`
+	Retention      LocationRetentionConfig `mapstructure:"retention"`
+	Ingestion      LocationIngestionConfig `mapstructure:"ingestion"`
+	Heatmap        LocationHeatmapConfig   `mapstructure:"heatmap"`
+	Exports        LocationExportsConfig   `mapstructure:"exports"`
+	Analytics      LocationAnalyticsConfig `mapstructure:"analytics"`
+}
+
+// LocationAnalyticsConfig копирование принятых местоположений в ClickHouse для тяжелой
+// аналитики (GET /analytics/locations/distance)
+type LocationAnalyticsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// URL HTTP интерфейс ClickHouse, например http://clickhouse:8123
+	URL      string `mapstructure:"url"`
+	Database string `mapstructure:"database"`
+	Table    string `mapstructure:"table"`
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	// BufferSize сколько точек может ждать отправки; при заполненном буфере точки не копируются
+	BufferSize int `mapstructure:"buffer_size"`
+	// BatchSize и FlushInterval: пакет отправляется, когда набрано BatchSize точек или прошел FlushInterval
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// Timeout ограничение одного запроса к ClickHouse
+	Timeout time.Duration `mapstructure:"timeout"`
+	// RetryInterval пауза перед повтором неудачной вставки; удваивается до MaxRetryInterval
+	RetryInterval    time.Duration `mapstructure:"retry_interval"`
+	MaxRetryInterval time.Duration `mapstructure:"max_retry_interval"`
+}
+
+// LocationExportsConfig конфигурация выгрузок истории местоположений в файл
+// (POST /drivers/{id}/locations/export)
+type LocationExportsConfig struct {
+	// Dir каталог файлов выгрузок, раздаваемый по BaseURL; BaseURL не должен быть открыт без подписи
+	Dir     string `mapstructure:"dir"`
+	BaseURL string `mapstructure:"base_url"`
+	// URLSecret секрет подписи ссылок на скачивание (nginx secure_link); пустой — ссылки без подписи
+	URLSecret string        `mapstructure:"url_secret"`
+	URLTTL    time.Duration `mapstructure:"url_ttl"`
+	Workers   int           `mapstructure:"workers"`
+	// QueueSize при заполненной очереди клиент получает 503
+	QueueSize int           `mapstructure:"queue_size"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	// MaxRange наибольший период одной выгрузки
+	MaxRange time.Duration `mapstructure:"max_range"`
+	// ChunkInterval период истории, читаемый из базы за один запрос
+	ChunkInterval time.Duration `mapstructure:"chunk_interval"`
+	// Retention сколько хранятся файл и запись о выгрузке (задача location_export_cleanup)
+	Retention time.Duration `mapstructure:"retention"`
+	// StaleAfter незавершенные за это время выгрузки считаются потерянными при перезапуске
+	StaleAfter time.Duration `mapstructure:"stale_after"`
+}
+
+// LocationHeatmapConfig конфигурация карты предложения водителей (GET /analytics/supply-heatmap)
+type LocationHeatmapConfig struct {
+	// DefaultPrecision и MaxPrecision длина geohash ячейки по умолчанию и наибольшая допустимая
+	DefaultPrecision int `mapstructure:"default_precision"`
+	MaxPrecision     int `mapstructure:"max_precision"`
+	// MaxAge водители, последняя точка которых старше, на карту не попадают
+	MaxAge time.Duration `mapstructure:"max_age"`
+}
+
+// LocationIngestionConfig конфигурация асинхронной записи местоположений
+type LocationIngestionConfig struct {
+	// Async точки из UpdateLocation ставятся в буфер и записываются пакетами в фоне
+	Async bool `mapstructure:"async"`
+	// BufferSize сколько точек может ждать записи; при заполненном буфере клиент получает 503
+	BufferSize int `mapstructure:"buffer_size"`
+	Workers    int `mapstructure:"workers"`
+	// BatchSize и FlushInterval: пакет записывается, когда набрано BatchSize точек или прошел FlushInterval
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// LocationRetentionConfig конфигурация хранения истории местоположений (задача location_cleanup)
+type LocationRetentionConfig struct {
+	// RawDays сколько суток хранятся исходные точки; более старые прореживаются в уровни Tiers и удаляются
+	RawDays int `mapstructure:"raw_days"`
+	// Tiers уровни сводных точек по именам; без уровней исходные точки просто удаляются
+	Tiers map[string]RetentionTierConfig `mapstructure:"tiers"`
+	// PartitionsAhead на сколько месяцев вперед задача location_partitions создает секции
+	PartitionsAhead int `mapstructure:"partitions_ahead"`
+}
+
+// RetentionTierConfig уровень хранения сводных точек
+type RetentionTierConfig struct {
+	// Interval одна сводная точка водителя на интервал; должен делить сутки без остатка
+	Interval time.Duration `mapstructure:"interval"`
+	// KeepDays сколько суток хранятся сводные точки уровня (0 — бессрочно)
+	KeepDays int `mapstructure:"keep_days"`
+}
+
+// TripsConfig конфигурация восстановления поездок по истории местоположений
+type TripsConfig struct {
+	// StopSpeed скорость в км/ч, ниже которой водитель считается стоящим
+	StopSpeed float64 `mapstructure:"stop_speed"`
+	// MinStopDuration остановка вне заказа дольше этого интервала завершает поездку
+	MinStopDuration time.Duration `mapstructure:"min_stop_duration"`
+	// MinDistanceKm более короткие поездки считаются дрейфом GPS
+	MinDistanceKm float64 `mapstructure:"min_distance_km"`
+	// SimplifyTolerance допустимое отклонение упрощенного маршрута в метрах (0 — без упрощения)
+	SimplifyTolerance float64       `mapstructure:"simplify_tolerance"`
+	MaxRange          time.Duration `mapstructure:"max_range"`
+}
+
+// ShardDatabase возвращает конфигурацию базы шарда, дополненную параметрами основной базы
+func (c *Config) ShardDatabase(name string) DatabaseConfig {
+	return inheritDatabase(c.Sharding.Shards[name], c.Database)
+}
+
+// ReplicaDatabase возвращает конфигурацию реплики, дополненную параметрами основной базы.
+// В отличие от шарда, реплика по умолчанию содержит ту же базу данных
+func (c *Config) ReplicaDatabase(name string) DatabaseConfig {
+	replica := inheritDatabase(c.Database.Replicas[name], c.Database)
+	if replica.Database == "" {
+		replica.Database = c.Database.Database
+	}
+	return replica
+}
+
+// inheritDatabase дополняет незаданные параметры подключения shard параметрами primary
+func inheritDatabase(shard, primary DatabaseConfig) DatabaseConfig {
+	shard.Replicas = nil
+	if shard.Host == "" {
+		shard.Host = primary.Host
+	}
+	if shard.Port == 0 {
+		shard.Port = primary.Port
+	}
+	if shard.User == "" {
+		shard.User = primary.User
+	}
+	if shard.Password == "" {
+		shard.Password = primary.Password
+	}
+	if shard.SSLMode == "" {
+		shard.SSLMode = primary.SSLMode
+	}
+	if shard.MaxOpenConns == 0 {
+		shard.MaxOpenConns = primary.MaxOpenConns
+	}
+	if shard.MaxIdleConns == 0 {
+		shard.MaxIdleConns = primary.MaxIdleConns
+	}
+	if shard.ConnMaxLifetime == 0 {
+		shard.ConnMaxLifetime = primary.ConnMaxLifetime
+	}
+	if shard.RetryMaxAttempts == 0 {
+		shard.RetryMaxAttempts = primary.RetryMaxAttempts
+		shard.RetryBaseDelay = primary.RetryBaseDelay
+		shard.RetryMaxDelay = primary.RetryMaxDelay
+	}
+	if shard.SlowQueryThreshold == 0 {
+		shard.SlowQueryThreshold = primary.SlowQueryThreshold
+	}
+	return shard
 }
 
 // RedisConfig конфигурация Redis
@@ -62,21 +336,148 @@ type NATSConfig struct {
 	MaxReconnect    int           `mapstructure:"max_reconnect"`
 	PingInterval    time.Duration `mapstructure:"ping_interval"`
 	MaxPingsOut     int           `mapstructure:"max_pings_out"`
+	// Учетные данные NATS: пользователь и пароль или токен
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"`
+	// Locations прием местоположений от мобильного шлюза
+	Locations LocationConsumerConfig `mapstructure:"locations"`
+}
+
+// LocationConsumerConfig конфигурация приема местоположений из NATS
+type LocationConsumerConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Subject string `mapstructure:"subject"`
+	// QueueGroup группа подписчиков: каждое сообщение обрабатывает один экземпляр сервиса
+	QueueGroup string `mapstructure:"queue_group"`
+	// DeadLetterSubject куда пересылаются необработанные сообщения; пусто — только логируются
+	DeadLetterSubject string `mapstructure:"dead_letter_subject"`
+	// BatchSize и FlushInterval: пакет сохраняется, когда набрано BatchSize точек или прошел FlushInterval
+	BatchSize     int           `mapstructure:"batch_size"`
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+	// MaxPending сколько сообщений может ждать сохранения; сверх этого клиент NATS отбрасывает сообщения
+	MaxPending int `mapstructure:"max_pending"`
+	// MaxRetries повторы сохранения пакета при ошибке хранилища до пересылки в dead letter
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
+}
+
+// MQTTConfig конфигурация приема местоположений от GPS-трекеров автопарков по MQTT
+type MQTTConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	BrokerURL string `mapstructure:"broker_url"`
+	// ClientID префикс ID клиента; к нему добавляется имя экземпляра сервиса
+	ClientID string `mapstructure:"client_id"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	// Topics шаблоны топиков трекеров; сегмент на месте первого "+" — ID трекера
+	Topics []string `mapstructure:"topics"`
+	// SharedGroup группа общей подписки ($share/<group>/...): каждое сообщение обрабатывает
+	// один экземпляр сервиса; пусто — каждый экземпляр получает все сообщения
+	SharedGroup string `mapstructure:"shared_group"`
+	// QoS уровень подписки: 0 или 1
+	QoS int `mapstructure:"qos"`
+	// CleanSession false: брокер хранит подписку и неподтвержденные сообщения QoS 1 между переподключениями
+	CleanSession   bool          `mapstructure:"clean_session"`
+	ConnectTimeout time.Duration `mapstructure:"connect_timeout"`
+	KeepAlive      time.Duration `mapstructure:"keep_alive"`
+	// DedupWindow и DedupSize: повторная доставка сообщения QoS 1 в течение DedupWindow
+	// отбрасывается; помнится не больше DedupSize последних сообщений
+	DedupWindow time.Duration `mapstructure:"dedup_window"`
+	DedupSize   int           `mapstructure:"dedup_size"`
+	// TrackerCacheTTL сколько помнить, за каким водителем закреплен трекер
+	TrackerCacheTTL time.Duration `mapstructure:"tracker_cache_ttl"`
 }
 
 // LoggerConfig конфигурация логгера
 type LoggerConfig struct {
-	Level      string `mapstructure:"level"`
-	Format     string `mapstructure:"format"`
-	OutputPath string `mapstructure:"output_path"`
+	Level      string           `mapstructure:"level"`
+	Format     string           `mapstructure:"format"`
+	OutputPath string           `mapstructure:"output_path"`
+	Redaction  RedactionConfig  `mapstructure:"redaction"`
+	Requests   RequestLogConfig `mapstructure:"requests"`
+}
+
+// RequestLogConfig конфигурация журнала HTTP запросов
+type RequestLogConfig struct {
+	// SampleRate доля запросов в журнале (0-1); ответы 5xx логируются всегда
+	SampleRate float64 `mapstructure:"sample_rate"`
+	// BodySampleRate доля записей журнала с замаскированными JSON телами запроса и ответа (0-1)
+	BodySampleRate float64 `mapstructure:"body_sample_rate"`
+	// MaxBodySize тела длиннее не логируются
+	MaxBodySize int `mapstructure:"max_body_size"`
+	// Routes доли для отдельных маршрутов, например частой отправки местоположений
+	Routes []RouteLogConfig `mapstructure:"routes"`
+}
+
+// RouteLogConfig доли журнала для маршрута; незаданные берутся из RequestLogConfig
+type RouteLogConfig struct {
+	// Route метод и шаблон пути: "POST /api/v1/drivers/:id/locations"
+	Route          string   `mapstructure:"route"`
+	SampleRate     *float64 `mapstructure:"sample_rate"`
+	BodySampleRate *float64 `mapstructure:"body_sample_rate"`
+}
+
+// RedactionConfig конфигурация маскирования персональных данных в логах
+type RedactionConfig struct {
+	// Mode строгость маскирования: strict, partial или off (off запрещен в production)
+	Mode string `mapstructure:"mode"`
+	// Fields дополнительные поля логов с персональными данными (phone, email и паспортные поля маскируются всегда)
+	Fields []string `mapstructure:"fields"`
+	// DebugUnmasked выводит полные значения в записях уровня debug, только в окружении development
+	DebugUnmasked bool `mapstructure:"debug_unmasked"`
 }
 
 // ExternalConfig конфигурация внешних сервисов
 type ExternalConfig struct {
-	GIBDDAPI GIBDDAPIConfig `mapstructure:"gibdd_api"`
-	MapsAPI  MapsAPIConfig  `mapstructure:"maps_api"`
-	SMSAPI   SMSAPIConfig   `mapstructure:"sms_api"`
-	S3       S3Config       `mapstructure:"s3"`
+	GIBDDAPI        GIBDDAPIConfig        `mapstructure:"gibdd_api"`
+	MapsAPI         MapsAPIConfig         `mapstructure:"maps_api"`
+	SMSAPI          SMSAPIConfig          `mapstructure:"sms_api"`
+	S3              S3Config              `mapstructure:"s3"`
+	LicenseRegistry LicenseRegistryConfig `mapstructure:"license_registry"`
+	OCR             OCRConfig             `mapstructure:"ocr"`
+}
+
+// Реестры водительских удостоверений
+const (
+	// LicenseRegistryProviderGIBDD API ГИБДД из external.gibdd_api
+	LicenseRegistryProviderGIBDD = "gibdd"
+	// LicenseRegistryProviderStub заглушка, подтверждающая любые удостоверения (не для production)
+	LicenseRegistryProviderStub = "stub"
+)
+
+// LicenseRegistryConfig сверка водительских удостоверений с внешним реестром при подтверждении документа
+type LicenseRegistryConfig struct {
+	// Provider реестр: gibdd или stub; пусто — без сверки
+	Provider string `mapstructure:"provider"`
+	// CacheTTL как долго ответ реестра по номеру удостоверения используется повторно
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// FailureThreshold после скольких отказов подряд реестр перестает вызываться до OpenTimeout
+	FailureThreshold int           `mapstructure:"failure_threshold"`
+	OpenTimeout      time.Duration `mapstructure:"open_timeout"`
+	// FailOpen подтверждать удостоверение, если реестр недоступен; по умолчанию подтверждение отклоняется
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// Сервисы распознавания документов
+const (
+	// OCRProviderHTTP HTTP API распознавания из external.ocr
+	OCRProviderHTTP = "http"
+	// OCRProviderStub заглушка, ничего не распознающая (не для production)
+	OCRProviderStub = "stub"
+)
+
+// OCRConfig распознавание полей загруженных документов для верификатора
+type OCRConfig struct {
+	// Provider сервис распознавания: http или stub; пусто — без распознавания
+	Provider string        `mapstructure:"provider"`
+	BaseURL  string        `mapstructure:"base_url"`
+	APIKey   string        `mapstructure:"api_key"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	// Workers число параллельных распознаваний
+	Workers int `mapstructure:"workers"`
+	// QueueSize сколько документов может ждать распознавания; файлы очереди хранятся в памяти
+	QueueSize int `mapstructure:"queue_size"`
 }
 
 // GIBDDAPIConfig конфигурация API ГИБДД
@@ -115,22 +516,583 @@ type S3Config struct {
 type MetricsConfig struct {
 	Enabled bool   `mapstructure:"enabled"`
 	Path    string `mapstructure:"path"`
+	// SLO учет задержек и бюджета ошибок ключевых операций, отчет — /debug/slo
+	SLO SLOConfig `mapstructure:"slo"`
+}
+
+// SLOConfig конфигурация учета целевых показателей ключевых операций
+type SLOConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Window длина скользящего окна; запросы уходят из окна интервалами по Resolution
+	Window     time.Duration `mapstructure:"window"`
+	Resolution time.Duration `mapstructure:"resolution"`
+	// Objectives цели по операциям; ключ — имя операции в отчете
+	Objectives map[string]SLOObjectiveConfig `mapstructure:"objectives"`
+}
+
+// SLOObjectiveConfig цели операции
+type SLOObjectiveConfig struct {
+	// Routes маршруты операции: метод и шаблон пути, "POST /api/v1/drivers"
+	Routes []string `mapstructure:"routes"`
+	// LatencyTarget и LatencyObjective — доля запросов быстрее порога (0 — без цели по задержке)
+	LatencyTarget    time.Duration `mapstructure:"latency_target"`
+	LatencyObjective float64       `mapstructure:"latency_objective"`
+	// AvailabilityObjective доля запросов без ответа 5xx (0 — без цели по доступности)
+	AvailabilityObjective float64 `mapstructure:"availability_objective"`
+}
+
+// InspectionsConfig конфигурация техосмотров автомобилей
+type InspectionsConfig struct {
+	BlockShiftOnOverdue bool          `mapstructure:"block_shift_on_overdue"`
+	IntervalDays        int           `mapstructure:"interval_days"`
+	ReminderDays        int           `mapstructure:"reminder_days"`
+	// PhotoIntervalDays периодичность фотоосмотра; PhotoGraceDays — льготный период после срока
+	PhotoIntervalDays int   `mapstructure:"photo_interval_days"`
+	PhotoGraceDays    int   `mapstructure:"photo_grace_days"`
+	PhotoMaxSize      int64 `mapstructure:"photo_max_size"`
+	// PhotoDir каталог для фотографий автомобилей, раздаваемый по PhotoBaseURL
+	PhotoDir     string `mapstructure:"photo_dir"`
+	PhotoBaseURL string `mapstructure:"photo_base_url"`
+}
+
+// ProfileConfig конфигурация заполнения профиля водителя
+type ProfileConfig struct {
+	// NudgeInterval минимальный интервал между напоминаниями о незаполненном профиле
+	NudgeInterval time.Duration `mapstructure:"nudge_interval"`
+}
+
+// WebSocketConfig конфигурация WebSocket подписок на местоположения
+type WebSocketConfig struct {
+	// SendBuffer сколько сообщений может ожидать отправки одному клиенту;
+	// при переполнении клиент отключается как медленный
+	SendBuffer     int           `mapstructure:"send_buffer"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	PingInterval   time.Duration `mapstructure:"ping_interval"`
+	MaxConnections int           `mapstructure:"max_connections"`
+}
+
+// LeaderboardConfig конфигурация рейтингов водителей
+type LeaderboardConfig struct {
+	Metrics []string `mapstructure:"metrics"`
+	// TieBreakers показатели для разрешения равенства по каждому основному показателю
+	TieBreakers map[string][]string `mapstructure:"tie_breakers"`
+	MinRatings  int                 `mapstructure:"min_ratings"`
+	MaxLimit    int                 `mapstructure:"max_limit"`
+	CacheTTL    time.Duration       `mapstructure:"cache_ttl"`
+}
+
+// VerificationConfig конфигурация очереди проверки документов
+type VerificationConfig struct {
+	// ClaimTTL время, после которого незавершенный захват документа возвращается в очередь
+	ClaimTTL time.Duration `mapstructure:"claim_ttl"`
+	MaxBatch int           `mapstructure:"max_batch"`
+	// RequiredDocuments типы документов, подтверждение которых обязательно для допуска водителя
+	RequiredDocuments []string `mapstructure:"required_documents"`
+}
+
+// DocumentsConfig конфигурация продления документов водителями
+type DocumentsConfig struct {
+	// RenewalWindowDays за сколько дней до истечения документ можно продлить
+	RenewalWindowDays int   `mapstructure:"renewal_window_days"`
+	FileMaxSize       int64 `mapstructure:"file_max_size"`
+	// ResubmissionReminderDays через сколько дней после отказа напомнить о повторной загрузке
+	// отклоненного документа (задача document_resubmission_reminders)
+	ResubmissionReminderDays int `mapstructure:"resubmission_reminder_days"`
+	// FileDir каталог для файлов документов, раздаваемый по FileBaseURL
+	FileDir     string `mapstructure:"file_dir"`
+	FileBaseURL string `mapstructure:"file_base_url"`
+}
+
+// CapacityConfig конфигурация отчетов о нагрузке и прогноза мощностей
+type CapacityConfig struct {
+	// Instance имя экземпляра в отчетах; по умолчанию имя хоста
+	Instance string `mapstructure:"instance"`
+	// DefaultLimitRPS допустимая частота запросов к эндпоинту на экземпляр; EndpointLimits
+	// задает лимиты отдельных маршрутов, ключ — "GET /api/v1/drivers/:id"
+	DefaultLimitRPS float64            `mapstructure:"default_limit_rps"`
+	EndpointLimits  map[string]float64 `mapstructure:"endpoint_limits"`
+	WindowDays      int                `mapstructure:"window_days"`
+	HorizonDays     int                `mapstructure:"horizon_days"`
+	RetentionDays   int                `mapstructure:"retention_days"`
+}
+
+// ExpensesConfig конфигурация учета расходов водителей
+type ExpensesConfig struct {
+	// Currencies допустимые валюты; первая — валюта заработка
+	Currencies []string `mapstructure:"currencies"`
+	// MaxAmount максимальная сумма одного расхода по категориям
+	MaxAmount   map[string]float64 `mapstructure:"max_amount"`
+	MaxPerShift int                `mapstructure:"max_per_shift"`
+	// EditWindow сколько времени после завершения смены можно добавлять расходы
+	EditWindow     time.Duration `mapstructure:"edit_window"`
+	ReceiptMaxSize int64         `mapstructure:"receipt_max_size"`
+	// ReceiptDir каталог для фотографий чеков, раздаваемый по ReceiptBaseURL
+	ReceiptDir     string `mapstructure:"receipt_dir"`
+	ReceiptBaseURL string `mapstructure:"receipt_base_url"`
+}
+
+// EarningsConfig конфигурация начислений водителям
+type EarningsConfig struct {
+	// Currencies допустимые валюты начислений; первая — валюта по умолчанию
+	Currencies []string `mapstructure:"currencies"`
+	// Timezone часовой пояс IANA, в котором ведомость делится на дни, недели и месяцы
+	Timezone string `mapstructure:"timezone"`
+}
+
+// AuthConfig конфигурация аутентификации HTTP API по JWT
+type AuthConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// HMACSecret общий секрет для токенов HS256
+	HMACSecret string `mapstructure:"hmac_secret"`
+	// PublicKeyFile PEM файл открытого ключа RSA для токенов RS256
+	PublicKeyFile string `mapstructure:"public_key_file"`
+	// JWKSURL адрес набора ключей провайдера для токенов RS256 с kid
+	JWKSURL             string        `mapstructure:"jwks_url"`
+	JWKSRefreshInterval time.Duration `mapstructure:"jwks_refresh_interval"`
+	ClockSkew           time.Duration `mapstructure:"clock_skew"`
+	RolesClaim          string        `mapstructure:"roles_claim"`
+	DriverIDClaim       string        `mapstructure:"driver_id_claim"`
+	FleetIDsClaim       string        `mapstructure:"fleet_ids_claim"`
+	ScopesClaim         string        `mapstructure:"scopes_claim"`
+	// GRPCRequired требует токен у вызовов gRPC. Без него вызовы без токена считаются
+	// внутренними и не ограничиваются автопарками
+	GRPCRequired bool `mapstructure:"grpc_required"`
+	// APIKeyRateLimit ограничение запросов в минуту для ключей API, выпущенных без своего; 0 — без ограничения
+	APIKeyRateLimit int `mapstructure:"api_key_rate_limit"`
+	// APIKeyCacheTTL как долго проверенный ключ API не перечитывается из базы; отзыв ключа
+	// доходит до других экземпляров не позже чем через это время
+	APIKeyCacheTTL time.Duration `mapstructure:"api_key_cache_ttl"`
+}
+
+// WebhooksConfig конфигурация вебхуков: проверка изменений профилей водителей автопарками и
+// доставка событий водителей внешним подписчикам
+type WebhooksConfig struct {
+	// DefaultTimeout время ожидания ответа, если для автопарка не задано свое
+	DefaultTimeout time.Duration `mapstructure:"default_timeout"`
+	// EnrichmentKeys ключи метаданных водителя, которые автопарк может заполнить в ответе
+	EnrichmentKeys []string `mapstructure:"enrichment_keys"`
+	// Fleets вебхуки по ID автопарка
+	Fleets map[string]FleetWebhookConfig `mapstructure:"fleets"`
+	// Delivery доставка событий подписчикам, зарегистрированным через /admin/webhooks
+	Delivery WebhookDeliveryConfig `mapstructure:"delivery"`
+}
+
+// WebhookDeliveryConfig конфигурация доставки событий водителей подписчикам вебхуков.
+// Неудачные попытки повторяет задача webhook_retry с паузой от initial_backoff до max_backoff
+type WebhookDeliveryConfig struct {
+	Workers int `mapstructure:"workers"`
+	// QueueSize доставки сверх очереди отправляет задача webhook_retry
+	QueueSize int           `mapstructure:"queue_size"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+	// MaxAttempts после стольких неудачных попыток доставка считается неудачной
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	// Lease на сколько откладывается повтор доставки, взятой в отправку; больше timeout
+	Lease time.Duration `mapstructure:"lease"`
+	// RetryBatch сколько доставок отправляет один запуск webhook_retry
+	RetryBatch int `mapstructure:"retry_batch"`
+	// CacheTTL как быстро изменения подписок доходят до других экземпляров
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// FleetWebhookConfig вебхук автопарка
+type FleetWebhookConfig struct {
+	URL string `mapstructure:"url"`
+	// Secret ключ подписи тела запроса (HMAC-SHA256 в заголовке X-Signature)
+	Secret  string        `mapstructure:"secret"`
+	Timeout time.Duration `mapstructure:"timeout"`
+	// FailOpen пропускать изменение, если вебхук не ответил; по умолчанию изменение отклоняется
+	FailOpen bool `mapstructure:"fail_open"`
+}
+
+// SecurityConfig конфигурация обнаружения подозрительной активности в аккаунтах водителей
+type SecurityConfig struct {
+	// CountryHeader заголовок балансировщика с кодом страны клиента
+	CountryHeader string `mapstructure:"country_header"`
+	// SessionCacheTTL как долго известная сессия не перечитывается из базы
+	SessionCacheTTL time.Duration `mapstructure:"session_cache_ttl"`
+	// HistoryDays за сколько дней сессий новое устройство или страна сравниваются с известными
+	HistoryDays int `mapstructure:"history_days"`
+	// ConcurrentWindow сессии с запросами в этом окне считаются одновременными
+	ConcurrentWindow      time.Duration `mapstructure:"concurrent_window"`
+	MaxConcurrentSessions int           `mapstructure:"max_concurrent_sessions"`
+	// MaxPlausibleSpeedKmh скорость между двумя точками, выше которой перемещение считается скачком
+	MaxPlausibleSpeedKmh float64 `mapstructure:"max_plausible_speed_kmh"`
+	MinJumpDistanceKm    float64 `mapstructure:"min_jump_distance_km"`
+	// AlertCooldown повторные события того же типа по водителю в этом окне не создаются
+	AlertCooldown time.Duration `mapstructure:"alert_cooldown"`
+	// AutoRevoke типы событий, при которых все сессии водителя отзываются сразу
+	AutoRevoke []string `mapstructure:"auto_revoke"`
+}
+
+// CampaignsConfig конфигурация кампаний перепроверки документов
+type CampaignsConfig struct {
+	// ReminderIntervalDays интервал напоминаний водителям, если в кампании он не задан
+	ReminderIntervalDays int `mapstructure:"reminder_interval_days"`
+	// BatchSize сколько водителей сегмента читается за один запрос при запуске кампании
+	BatchSize int `mapstructure:"batch_size"`
+}
+
+// WarmupConfig конфигурация прогрева после запуска экземпляра
+type WarmupConfig struct {
+	// Enabled пока прогрев не завершен, проба /ready отвечает 503
+	Enabled bool `mapstructure:"enabled"`
+	// Timeout после этого времени экземпляр считается готовым, даже если прогрев не завершен
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Connections сколько соединений с базой открывается заранее (0 — database.max_idle_conns)
+	Connections int `mapstructure:"connections"`
+	// HotDrivers для скольких активных водителей заранее читается текущее местоположение
+	HotDrivers int `mapstructure:"hot_drivers"`
+}
+
+// HealthConfig конфигурация пробы готовности /health/ready
+type HealthConfig struct {
+	// Timeout ограничение одной проверки зависимости
+	Timeout time.Duration `mapstructure:"timeout"`
+	// CheckRedis проверять Redis из секции redis; сервис пока не использует Redis
+	CheckRedis bool `mapstructure:"check_redis"`
+}
+
+// SchedulesConfig конфигурация расписаний доступности водителей
+type SchedulesConfig struct {
+	// DefaultTimezone часовой пояс IANA для расписаний, в которых он не указан
+	DefaultTimezone string `mapstructure:"default_timezone"`
+}
+
+// GeofencesConfig конфигурация геозон
+type GeofencesConfig struct {
+	// CacheTTL как долго экземпляр использует загруженный список активных геозон; 0 — без кэша
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// CitiesConfig конфигурация городов
+type CitiesConfig struct {
+	// CacheTTL как долго экземпляр использует загруженный список активных городов; 0 — без кэша
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// DriverAppConfig конфигурация приложения водителя, которую оно получает из /drivers/:id/app-config
+type DriverAppConfig struct {
+	// FlagsCacheTTL как долго экземпляр использует загруженные флаги функций; 0 — без кэша
+	FlagsCacheTTL time.Duration `mapstructure:"flags_cache_ttl"`
+	// GPSUploadInterval, LocationBatchSize и HeartbeatInterval параметры приложения по умолчанию;
+	// включенные флаги функций могут их переопределить
+	GPSUploadInterval time.Duration `mapstructure:"gps_upload_interval"`
+	LocationBatchSize int           `mapstructure:"location_batch_size"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+}
+
+// DispatchConfig конфигурация подбора водителя на заказ
+type DispatchConfig struct {
+	Weights DispatchWeightsConfig `mapstructure:"weights"`
+	// DefaultRadiusKm радиус поиска кандидатов, если он не указан в запросе
+	DefaultRadiusKm float64 `mapstructure:"default_radius_km"`
+	// MaxCandidates сколько ближайших водителей оценивается
+	MaxCandidates int `mapstructure:"max_candidates"`
+	// MaxIdle время простоя, после которого водитель получает полный балл за ожидание
+	MaxIdle time.Duration `mapstructure:"max_idle"`
+	// StatsWindow за какой период учитываются ответы на предложения заказов
+	StatsWindow time.Duration `mapstructure:"stats_window"`
+	// MinOffers минимум ответов за окно, после которого учитывается доля принятых
+	MinOffers int `mapstructure:"min_offers"`
+	// DefaultRating рейтинг водителя без оценок
+	DefaultRating float64 `mapstructure:"default_rating"`
+}
+
+// DispatchWeightsConfig веса составляющих оценки кандидата; меняются без перезапуска
+type DispatchWeightsConfig struct {
+	Distance   float64 `mapstructure:"distance"`
+	Rating     float64 `mapstructure:"rating"`
+	Acceptance float64 `mapstructure:"acceptance"`
+	Idle       float64 `mapstructure:"idle"`
+	// Cancellation вес доли заказов, которые водитель не отменял
+	Cancellation float64 `mapstructure:"cancellation"`
+}
+
+// ShiftsConfig конфигурация смен водителей
+type ShiftsConfig struct {
+	// MaxDuration длительность, после которой смена закрывается автоматически (задача shift_auto_end); 0 — без ограничения
+	MaxDuration time.Duration `mapstructure:"max_duration"`
+	// BreakRequiredAfter сколько водитель может работать без перерыва; смена с более долгой работой
+	// без отдыха отмечается как не соответствующая требованиям. 0 — не проверять
+	BreakRequiredAfter time.Duration `mapstructure:"break_required_after"`
+	// MinBreak минимальная длительность перерыва, который считается отдыхом
+	MinBreak time.Duration `mapstructure:"min_break"`
+}
+
+// RatingsConfig правила приема оценок и расчета рейтинга водителей
+type RatingsConfig struct {
+	// MaxPerCustomerPerDay сколько оценок клиент может поставить за сутки; 0 — без ограничения
+	MaxPerCustomerPerDay int `mapstructure:"max_per_customer_per_day"`
+	// SuspiciousMinRatings сколько оценок 1 подряд от клиента за suspicious_window делают его
+	// оценки подозрительными; 0 — не проверять
+	SuspiciousMinRatings int           `mapstructure:"suspicious_min_ratings"`
+	SuspiciousWindow     time.Duration `mapstructure:"suspicious_window"`
+	// DecayHalfLife за сколько вес оценки в рейтинге водителя уменьшается вдвое (задача
+	// rating_recompute); 0 — простое среднее
+	DecayHalfLife time.Duration `mapstructure:"decay_half_life"`
+}
+
+// ReferralsConfig конфигурация реферальной программы водителей
+type ReferralsConfig struct {
+	// RequiredTrips сколько поездок после допуска должен выполнить приглашенный водитель,
+	// чтобы пригласивший получил право на выплату
+	RequiredTrips int `mapstructure:"required_trips"`
 }
 
-// LoadConfig загружает конфигурацию из переменных окружения и файлов
+// InvitesConfig конфигурация приглашений водителей на самостоятельную регистрацию
+type InvitesConfig struct {
+	// Secret ключ подписи токенов приглашений (HMAC-SHA256); пустой — приглашения отключены.
+	// Смена ключа делает недействительными все выданные ссылки
+	Secret string `mapstructure:"secret"`
+	// LinkBaseURL ссылка на регистрацию в приложении водителя; токен добавляется параметром token
+	LinkBaseURL string `mapstructure:"link_base_url"`
+	// DefaultTTL срок действия приглашения, выпущенного без своего
+	DefaultTTL time.Duration `mapstructure:"default_ttl"`
+	// MaxTTL наибольший срок действия приглашения
+	MaxTTL time.Duration `mapstructure:"max_ttl"`
+}
+
+// OrderTrackingConfig конфигурация ссылок отслеживания заказов клиентами
+type OrderTrackingConfig struct {
+	// Secret ключ подписи токенов ссылок (HMAC-SHA256); пустой — ссылки отключены.
+	// Смена ключа делает недействительными все выданные ссылки
+	Secret string `mapstructure:"secret"`
+	// LinkBaseURL страница отслеживания для клиента; токен добавляется параметром token
+	LinkBaseURL string `mapstructure:"link_base_url"`
+	// TTL наибольшая длительность отслеживания заказа, после нее ссылка не действует
+	TTL time.Duration `mapstructure:"ttl"`
+	// RequestsPerMinute запросов в минуту по одной ссылке на экземпляр сервиса; 0 — без ограничения
+	RequestsPerMinute int `mapstructure:"requests_per_minute"`
+}
+
+// DriverTagsConfig конфигурация меток водителей
+type DriverTagsConfig struct {
+	// Controlled контролируемые метки, доступные всегда и показываемые в справочнике
+	Controlled []string `mapstructure:"controlled"`
+	// FreeForm разрешает произвольные метки помимо контролируемых
+	FreeForm bool `mapstructure:"free_form"`
+	// MaxPerDriver ограничение числа меток у водителя; 0 — без ограничения
+	MaxPerDriver int `mapstructure:"max_per_driver"`
+}
+
+// PhoneConfig конфигурация подтверждения телефона водителя кодом из SMS
+type PhoneConfig struct {
+	// VerificationRequired без подтвержденного телефона водитель не переводится в pending_verification
+	VerificationRequired bool `mapstructure:"verification_required"`
+	// CodeTTL сколько действует отправленный код
+	CodeTTL time.Duration `mapstructure:"code_ttl"`
+	// ResendInterval как часто водителю можно отправлять новый код
+	ResendInterval time.Duration `mapstructure:"resend_interval"`
+	// MaxAttempts сколько раз можно ввести один код
+	MaxAttempts int `mapstructure:"max_attempts"`
+}
+
+// HeartbeatConfig конфигурация сигналов присутствия водителей
+type HeartbeatConfig struct {
+	// OfflineAfter через сколько без сигнала или точки местоположения водитель переводится
+	// в неактивные, а его смена закрывается (задача offline_detection); 0 — не переводить
+	OfflineAfter time.Duration `mapstructure:"offline_after"`
+	// TouchInterval как часто точки местоположения обновляют сохраненный сигнал водителя
+	TouchInterval time.Duration `mapstructure:"touch_interval"`
+}
+
+// StatisticsConfig конфигурация сводных показателей водителя
+type StatisticsConfig struct {
+	// CacheTTL как долго показатели водителя не пересчитываются; 0 — считать при каждом запросе
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+	// DailyLookbackDays сколько прошедших суток пересчитывает задача driver_daily_stats, чтобы
+	// учесть данные, записанные с опозданием
+	DailyLookbackDays int `mapstructure:"daily_lookback_days"`
+	// DailyBackfillDays за сколько прошедших суток показатели считаются при первом запуске задачи
+	DailyBackfillDays int `mapstructure:"daily_backfill_days"`
+	// DispatchWindowDays окна в сутках, за которые показываются доли принятых и отмененных
+	// предложений заказов
+	DispatchWindowDays []int `mapstructure:"dispatch_window_days"`
+}
+
+// Каналы уведомлений водителям
+const (
+	NotificationChannelSMS   = "sms"
+	NotificationChannelPush  = "push"
+	NotificationChannelEmail = "email"
+)
+
+// NotificationsConfig конфигурация уведомлений водителям
+type NotificationsConfig struct {
+	// Providers включенные каналы: sms (шлюз external.sms_api), push, email;
+	// пусто — уведомления только пишутся в лог
+	Providers []string `mapstructure:"providers"`
+	// DefaultChannels каналы шаблонов, для которых каналы не заданы
+	DefaultChannels []string `mapstructure:"default_channels"`
+	// Timezone часовой пояс IANA дат в тексте уведомлений
+	Timezone string                   `mapstructure:"timezone"`
+	Push     PushNotificationsConfig  `mapstructure:"push"`
+	Email    EmailNotificationsConfig `mapstructure:"email"`
+	// Templates переопределяют каналы и тексты шаблонов по умолчанию по имени
+	Templates []NotificationTemplateConfig `mapstructure:"templates"`
+}
+
+// PushNotificationsConfig push-шлюз, рассылающий уведомления на устройства водителя
+type PushNotificationsConfig struct {
+	URL     string        `mapstructure:"url"`
+	APIKey  string        `mapstructure:"api_key"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// EmailNotificationsConfig SMTP-сервер для писем водителям
+type EmailNotificationsConfig struct {
+	Host     string        `mapstructure:"host"`
+	Port     int           `mapstructure:"port"`
+	Username string        `mapstructure:"username"`
+	Password string        `mapstructure:"password"`
+	From     string        `mapstructure:"from"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// NotificationTemplateConfig переопределение шаблона уведомления. Subject и Body
+// в синтаксисе text/template; пустые поля берутся из шаблона по умолчанию
+type NotificationTemplateConfig struct {
+	Name     string   `mapstructure:"name"`
+	Channels []string `mapstructure:"channels"`
+	Subject  string   `mapstructure:"subject"`
+	Body     string   `mapstructure:"body"`
+}
+
+// MessagesConfig конфигурация переписки водителей с диспетчерами
+type MessagesConfig struct {
+	// RetentionDays срок хранения сообщений (задача message_cleanup); 0 — бессрочно
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// Внешние журналы для закрепления хешей цепочки аудита
+const (
+	AuditAnchorSinkFile    = "file"
+	AuditAnchorSinkWebhook = "webhook"
+)
+
+// AuditConfig конфигурация журнала аудита
+type AuditConfig struct {
+	// HashChain новые записи связываются цепочкой хешей: изменение, удаление или вставка
+	// записи обнаруживается проверкой цепочки
+	HashChain bool `mapstructure:"hash_chain"`
+	// VerifyBatchSize сколько записей читается за один запрос при проверке цепочки
+	VerifyBatchSize int               `mapstructure:"verify_batch_size"`
+	Anchor          AuditAnchorConfig `mapstructure:"anchor"`
+}
+
+// AuditAnchorConfig закрепление хеша последней записи цепочки во внешнем журнале (задача audit_anchor)
+type AuditAnchorConfig struct {
+	// Sink file — дописывание строки в файл на томе с защитой от перезаписи,
+	// webhook — POST во внешний сервис; пусто — без закрепления
+	Sink     string `mapstructure:"sink"`
+	FilePath string `mapstructure:"file_path"`
+	URL      string `mapstructure:"url"`
+	// Secret ключ подписи тела запроса (HMAC-SHA256 в заголовке X-Signature)
+	Secret  string        `mapstructure:"secret"`
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// Имена фоновых задач
+const (
+	JobLocationCleanup     = "location_cleanup"
+	JobInspectionReminders = "inspection_reminders"
+	JobProfileNudges       = "profile_nudges"
+	JobLeaderboardRefresh  = "leaderboard_refresh"
+	JobReleaseStaleClaims  = "release_stale_claims"
+	// JobDocumentReminders напоминания о продлении истекающих документов
+	JobDocumentReminders = "document_reminders"
+	// JobResubmissionReminders напоминания о повторной загрузке отклоненных документов
+	JobResubmissionReminders = "document_resubmission_reminders"
+	// JobCapacitySample снимает состояние пула соединений, JobCapacityReport сохраняет суточный отчет
+	JobCapacitySample = "capacity_sample"
+	JobCapacityReport = "capacity_report"
+	// JobDocumentExpiry помечает истекшие документы и пересматривает допуск водителей
+	JobDocumentExpiry = "document_expiry"
+	// JobShardRefresh перечитывает назначения городов шардам
+	JobShardRefresh = "shard_refresh"
+	// JobCampaignReminders напоминает о перепроверке документов, JobCampaignDeadlines
+	// завершает кампании с прошедшим сроком и отстраняет водителей
+	JobCampaignReminders = "campaign_reminders"
+	JobCampaignDeadlines = "campaign_deadlines"
+	// JobAuditAnchor закрепляет хеш последней записи цепочки аудита во внешнем журнале
+	JobAuditAnchor = "audit_anchor"
+	// JobScheduleEnforcement переводит свободных водителей вне окон расписания в неактивные
+	JobScheduleEnforcement = "schedule_enforcement"
+	// JobMessageCleanup удаляет сообщения переписки старше messages.retention_days
+	JobMessageCleanup = "message_cleanup"
+	// JobShiftAutoEnd закрывает смены длиннее shifts.max_duration
+	JobShiftAutoEnd = "shift_auto_end"
+	// JobOfflineDetection переводит в неактивные водителей без сигнала дольше heartbeat.offline_after
+	JobOfflineDetection = "offline_detection"
+	// JobRatingRecompute пересчитывает рейтинг водителей с учетом давности оценок
+	JobRatingRecompute = "rating_recompute"
+	// JobRunHistoryCleanup удаляет историю запусков задач старше scheduler.history_retention_days
+	JobRunHistoryCleanup = "job_history_cleanup"
+	// JobBlockExpiry снимает блокировки водителей с наступившим сроком unblock_at
+	JobBlockExpiry = "block_expiry"
+	// JobReferralProgress пересчитывает прогресс незавершенных рефералов и публикует referral.completed
+	JobReferralProgress = "referral_progress"
+	// JobLocationExportCleanup удаляет просроченные файлы выгрузок истории местоположений
+	JobLocationExportCleanup = "location_export_cleanup"
+	// JobDriverDailyStats пересчитывает суточные показатели водителей для статистики
+	JobDriverDailyStats = "driver_daily_stats"
+	// JobWebhookRetry повторяет доставки вебхуков с наступившим временем попытки
+	JobWebhookRetry = "webhook_retry"
+	// JobLocationPartitions создает месячные секции истории местоположений заранее
+	JobLocationPartitions = "location_partitions"
+)
+
+// SchedulerConfig конфигурация планировщика фоновых задач
+type SchedulerConfig struct {
+	// Timezone часовой пояс IANA, в котором интерпретируются cron-выражения
+	Timezone string               `mapstructure:"timezone"`
+	Jobs     map[string]JobConfig `mapstructure:"jobs"`
+	// Locking блокирует задачи между экземплярами через advisory locks PostgreSQL и отмечает
+	// запуски по расписанию в job_schedule_claims, чтобы каждый запуск выполнял один
+	// экземпляр; при хранении в памяти не действует
+	Locking bool `mapstructure:"locking"`
+	// HistoryRetentionDays срок хранения истории запусков (задача job_history_cleanup); 0 — бессрочно
+	HistoryRetentionDays int `mapstructure:"history_retention_days"`
+}
+
+// JobConfig конфигурация фоновой задачи
+type JobConfig struct {
+	// Schedule cron-выражение (минуты часы день месяц день_недели) или дескриптор вида @daily
+	Schedule string        `mapstructure:"schedule"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+	Disabled bool          `mapstructure:"disabled"`
+}
+
+var (
+	// loadMu не дает перечитыванию конфигурации пересечься с другой загрузкой
+	loadMu    sync.Mutex
+	setupOnce sync.Once
+)
+
+// LoadConfig загружает конфигурацию из переменных окружения и файлов и раскрывает ссылки
+// на секреты. Повторный вызов перечитывает файл конфигурации
 func LoadConfig() (*Config, error) {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
-	viper.AddConfigPath("./configs")
+	loadMu.Lock()
+	defer loadMu.Unlock()
 
-	// Установка значений по умолчанию
-	setDefaults()
+	setupOnce.Do(func() {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./configs")
 
-	// Настройка чтения переменных окружения
-	viper.AutomaticEnv()
-	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
-	viper.SetEnvPrefix("DRIVER_SERVICE")
+		// Установка значений по умолчанию
+		setDefaults()
+
+		// Настройка чтения переменных окружения
+		viper.AutomaticEnv()
+		viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+		viper.SetEnvPrefix("DRIVER_SERVICE")
+	})
 
 	// Чтение конфигурационного файла
 	if err := viper.ReadInConfig(); err != nil {
@@ -145,9 +1107,23 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	resolver, err := NewSecretsResolver(ctx, config.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.ResolveSecrets(ctx, resolver); err != nil {
+		return nil, fmt.Errorf("failed to resolve secrets: %w", err)
+	}
+
 	return &config, nil
 }
 
+// secretsTimeout ограничение времени раскрытия всех секретов при загрузке конфигурации
+const secretsTimeout = 30 * time.Second
+
 // setDefaults устанавливает значения по умолчанию
 func setDefaults() {
 	// Server
@@ -156,6 +1132,15 @@ func setDefaults() {
 	viper.SetDefault("server.metrics_port", 9002)
 	viper.SetDefault("server.timeout", "30s")
 	viper.SetDefault("server.environment", "development")
+	viper.SetDefault("server.language", "en")
+	viper.SetDefault("server.compression.enabled", true)
+	viper.SetDefault("server.compression.min_size", 1024)
+	viper.SetDefault("server.shutdown.timeout", "30s")
+	viper.SetDefault("server.shutdown.location_drain_timeout", "10s")
+	viper.SetDefault("server.shutdown.websocket_drain_timeout", "5s")
+
+	// Storage
+	viper.SetDefault("storage.type", StorageTypePostgres)
 
 	// Database
 	viper.SetDefault("database.host", "localhost")
@@ -167,6 +1152,63 @@ func setDefaults() {
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 25)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.retry_max_attempts", 3)
+	viper.SetDefault("database.retry_base_delay", "50ms")
+	viper.SetDefault("database.retry_max_delay", "1s")
+	viper.SetDefault("database.slow_query_threshold", "500ms")
+	viper.SetDefault("database.replica_check_interval", "5s")
+	viper.SetDefault("database.replica_max_lag", "10s")
+
+	// Locations
+	viper.SetDefault("locations.max_gap_interval", "5m")
+	viper.SetDefault("locations.trips.stop_speed", 5.0)
+	viper.SetDefault("locations.trips.min_stop_duration", "3m")
+	viper.SetDefault("locations.trips.min_distance_km", 0.2)
+	viper.SetDefault("locations.trips.simplify_tolerance", 10.0)
+	viper.SetDefault("locations.trips.max_range", "168h")
+	viper.SetDefault("locations.retention.raw_days", 30)
+	viper.SetDefault("locations.retention.partitions_ahead", 3)
+	viper.SetDefault("locations.ingestion.async", false)
+	viper.SetDefault("locations.ingestion.buffer_size", 10000)
+	viper.SetDefault("locations.ingestion.workers", 4)
+	viper.SetDefault("locations.ingestion.batch_size", 200)
+	viper.SetDefault("locations.ingestion.flush_interval", "200ms")
+	viper.SetDefault("locations.heatmap.default_precision", 6)
+	viper.SetDefault("locations.heatmap.max_precision", 8)
+	viper.SetDefault("locations.heatmap.max_age", "5m")
+	viper.SetDefault("locations.exports.dir", "./data/exports")
+	viper.SetDefault("locations.exports.base_url", "/exports")
+	viper.SetDefault("locations.exports.url_secret", "")
+	viper.SetDefault("locations.exports.url_ttl", "15m")
+	viper.SetDefault("locations.exports.workers", 2)
+	viper.SetDefault("locations.exports.queue_size", 100)
+	viper.SetDefault("locations.exports.timeout", "30m")
+	viper.SetDefault("locations.exports.max_range", "744h")
+	viper.SetDefault("locations.exports.chunk_interval", "6h")
+	viper.SetDefault("locations.exports.retention", "72h")
+	viper.SetDefault("locations.exports.stale_after", "2h")
+	viper.SetDefault("locations.analytics.enabled", false)
+	viper.SetDefault("locations.analytics.url", "http://localhost:8123")
+	viper.SetDefault("locations.analytics.database", "default")
+	viper.SetDefault("locations.analytics.table", "driver_locations")
+	viper.SetDefault("locations.analytics.user", "default")
+	viper.SetDefault("locations.analytics.password", "")
+	viper.SetDefault("locations.analytics.buffer_size", 50000)
+	viper.SetDefault("locations.analytics.batch_size", 5000)
+	viper.SetDefault("locations.analytics.flush_interval", "1s")
+	viper.SetDefault("locations.analytics.timeout", "10s")
+	viper.SetDefault("locations.analytics.retry_interval", "1s")
+	viper.SetDefault("locations.analytics.max_retry_interval", "30s")
+	viper.SetDefault("locations.retention.tiers", map[string]interface{}{
+		"5m": map[string]interface{}{"interval": "5m", "keep_days": 365},
+		"1h": map[string]interface{}{"interval": "1h", "keep_days": 1825},
+	})
+
+	// Sharding
+	viper.SetDefault("sharding.enabled", false)
+	viper.SetDefault("sharding.default_shard", "primary")
+	viper.SetDefault("sharding.rebalance_lock_wait", "2m")
+	viper.SetDefault("sharding.rebalance_batch_size", 1000)
 
 	// Redis
 	viper.SetDefault("redis.host", "localhost")
@@ -186,16 +1228,54 @@ func setDefaults() {
 	viper.SetDefault("nats.max_reconnect", -1)
 	viper.SetDefault("nats.ping_interval", "20s")
 	viper.SetDefault("nats.max_pings_out", 2)
+	viper.SetDefault("nats.locations.enabled", true)
+	viper.SetDefault("nats.locations.subject", "drivers.locations")
+	viper.SetDefault("nats.locations.queue_group", "driver-service")
+	viper.SetDefault("nats.locations.dead_letter_subject", "drivers.locations.dead")
+	viper.SetDefault("nats.locations.batch_size", 100)
+	viper.SetDefault("nats.locations.flush_interval", "500ms")
+	viper.SetDefault("nats.locations.max_pending", 10000)
+	viper.SetDefault("nats.locations.max_retries", 3)
+	viper.SetDefault("nats.locations.retry_backoff", "500ms")
+
+	// MQTT
+	viper.SetDefault("mqtt.enabled", false)
+	viper.SetDefault("mqtt.broker_url", "tcp://localhost:1883")
+	viper.SetDefault("mqtt.client_id", "driver-service")
+	viper.SetDefault("mqtt.topics", []string{"fleet/+/gps"})
+	viper.SetDefault("mqtt.shared_group", "driver-service")
+	viper.SetDefault("mqtt.qos", 1)
+	viper.SetDefault("mqtt.clean_session", false)
+	viper.SetDefault("mqtt.connect_timeout", "30s")
+	viper.SetDefault("mqtt.keep_alive", "30s")
+	viper.SetDefault("mqtt.dedup_window", "10m")
+	viper.SetDefault("mqtt.dedup_size", 100000)
+	viper.SetDefault("mqtt.tracker_cache_ttl", "1m")
 
 	// Logger
 	viper.SetDefault("logger.level", "info")
 	viper.SetDefault("logger.format", "json")
 	viper.SetDefault("logger.output_path", "stdout")
+	viper.SetDefault("logger.redaction.mode", "partial")
+	viper.SetDefault("logger.redaction.fields", []string{})
+	viper.SetDefault("logger.redaction.debug_unmasked", true)
+	viper.SetDefault("logger.requests.sample_rate", 1.0)
+	viper.SetDefault("logger.requests.body_sample_rate", 0.0)
+	viper.SetDefault("logger.requests.max_body_size", 4096)
 
 	// External APIs
 	viper.SetDefault("external.gibdd_api.timeout", "30s")
 	viper.SetDefault("external.maps_api.timeout", "10s")
 	viper.SetDefault("external.sms_api.timeout", "15s")
+	viper.SetDefault("external.license_registry.provider", "")
+	viper.SetDefault("external.license_registry.cache_ttl", "24h")
+	viper.SetDefault("external.license_registry.failure_threshold", 5)
+	viper.SetDefault("external.license_registry.open_timeout", "30s")
+	viper.SetDefault("external.license_registry.fail_open", false)
+	viper.SetDefault("external.ocr.provider", "")
+	viper.SetDefault("external.ocr.timeout", "30s")
+	viper.SetDefault("external.ocr.workers", 2)
+	viper.SetDefault("external.ocr.queue_size", 32)
 
 	// S3
 	viper.SetDefault("external.s3.region", "us-east-1")
@@ -204,6 +1284,303 @@ func setDefaults() {
 	// Metrics
 	viper.SetDefault("metrics.enabled", true)
 	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.slo.enabled", true)
+	viper.SetDefault("metrics.slo.window", "1h")
+	viper.SetDefault("metrics.slo.resolution", "1m")
+	viper.SetDefault("metrics.slo.objectives", map[string]interface{}{
+		"create_driver": map[string]interface{}{
+			"routes":                 []string{"POST /api/v1/drivers"},
+			"latency_target":         "500ms",
+			"latency_objective":      0.99,
+			"availability_objective": 0.999,
+		},
+		"update_location": map[string]interface{}{
+			"routes":                 []string{"POST /api/v1/drivers/:id/locations", "POST /api/v1/drivers/:id/locations/batch"},
+			"latency_target":         "200ms",
+			"latency_objective":      0.99,
+			"availability_objective": 0.999,
+		},
+		"nearby_search": map[string]interface{}{
+			"routes":                 []string{"GET /api/v1/locations/nearby"},
+			"latency_target":         "300ms",
+			"latency_objective":      0.95,
+			"availability_objective": 0.999,
+		},
+	})
+
+	// Inspections
+	viper.SetDefault("inspections.block_shift_on_overdue", true)
+	viper.SetDefault("inspections.interval_days", 365)
+	viper.SetDefault("inspections.reminder_days", 14)
+	viper.SetDefault("inspections.photo_interval_days", 30)
+	viper.SetDefault("inspections.photo_grace_days", 3)
+	viper.SetDefault("inspections.photo_max_size", 10<<20)
+	viper.SetDefault("inspections.photo_dir", "./data/inspections")
+	viper.SetDefault("inspections.photo_base_url", "/inspection-photos")
+
+	// Profile
+	viper.SetDefault("profile.nudge_interval", "72h")
+
+	// WebSocket
+	viper.SetDefault("websocket.send_buffer", 64)
+	viper.SetDefault("websocket.write_timeout", "10s")
+	viper.SetDefault("websocket.ping_interval", "30s")
+	viper.SetDefault("websocket.max_connections", 1000)
+
+	// Leaderboard
+	viper.SetDefault("leaderboard.metrics", []string{"trips", "rating", "earnings"})
+	viper.SetDefault("leaderboard.tie_breakers", map[string][]string{
+		"trips":    {"rating", "earnings"},
+		"rating":   {"trips"},
+		"earnings": {"trips", "rating"},
+	})
+	viper.SetDefault("leaderboard.min_ratings", 5)
+	viper.SetDefault("leaderboard.max_limit", 100)
+	viper.SetDefault("leaderboard.cache_ttl", "15m")
+
+	// Verification queue
+	viper.SetDefault("verification.claim_ttl", "15m")
+	viper.SetDefault("verification.max_batch", 50)
+	viper.SetDefault("verification.required_documents", []string{"driver_license", "passport"})
+
+	// Documents
+	viper.SetDefault("documents.renewal_window_days", 30)
+	viper.SetDefault("documents.file_max_size", 10<<20)
+	viper.SetDefault("documents.resubmission_reminder_days", 3)
+	viper.SetDefault("documents.file_dir", "./data/documents")
+	viper.SetDefault("documents.file_base_url", "/documents")
+
+	// Capacity
+	viper.SetDefault("capacity.default_limit_rps", 100)
+	viper.SetDefault("capacity.window_days", 28)
+	viper.SetDefault("capacity.horizon_days", 90)
+	viper.SetDefault("capacity.retention_days", 365)
+
+	// Expenses
+	viper.SetDefault("expenses.currencies", []string{"RUB"})
+	viper.SetDefault("expenses.max_amount", map[string]float64{
+		"fuel":    15000,
+		"toll":    5000,
+		"wash":    3000,
+		"parking": 3000,
+		"other":   5000,
+	})
+	viper.SetDefault("expenses.max_per_shift", 30)
+	viper.SetDefault("expenses.edit_window", "24h")
+	viper.SetDefault("expenses.receipt_max_size", 10<<20)
+	viper.SetDefault("expenses.receipt_dir", "./data/receipts")
+	viper.SetDefault("expenses.receipt_base_url", "/receipts")
+
+	// Earnings
+	viper.SetDefault("earnings.currencies", []string{"RUB"})
+	viper.SetDefault("earnings.timezone", "Europe/Moscow")
+
+	// Fleet webhooks
+	viper.SetDefault("webhooks.default_timeout", "2s")
+	viper.SetDefault("webhooks.enrichment_keys", []string{"city", "external_id", "tariff_group"})
+
+	// Webhook subscriptions
+	viper.SetDefault("webhooks.delivery.workers", 4)
+	viper.SetDefault("webhooks.delivery.queue_size", 1000)
+	viper.SetDefault("webhooks.delivery.timeout", "5s")
+	viper.SetDefault("webhooks.delivery.max_attempts", 8)
+	viper.SetDefault("webhooks.delivery.initial_backoff", "30s")
+	viper.SetDefault("webhooks.delivery.max_backoff", "1h")
+	viper.SetDefault("webhooks.delivery.lease", "2m")
+	viper.SetDefault("webhooks.delivery.retry_batch", 200)
+	viper.SetDefault("webhooks.delivery.cache_ttl", "30s")
+
+	// Security
+	viper.SetDefault("security.country_header", "X-Country-Code")
+	viper.SetDefault("security.session_cache_ttl", "1m")
+	viper.SetDefault("security.history_days", 90)
+	viper.SetDefault("security.concurrent_window", "5m")
+	viper.SetDefault("security.max_concurrent_sessions", 1)
+	viper.SetDefault("security.max_plausible_speed_kmh", 300)
+	viper.SetDefault("security.min_jump_distance_km", 1)
+	viper.SetDefault("security.alert_cooldown", "15m")
+	viper.SetDefault("security.auto_revoke", []string{})
+
+	// Reverification campaigns
+	viper.SetDefault("campaigns.reminder_interval_days", 3)
+	viper.SetDefault("campaigns.batch_size", 500)
+
+	// Warm-up
+	viper.SetDefault("warmup.enabled", false)
+	viper.SetDefault("warmup.timeout", "1m")
+	viper.SetDefault("warmup.connections", 0)
+	viper.SetDefault("warmup.hot_drivers", 1000)
+
+	// Health
+	viper.SetDefault("health.timeout", "2s")
+	viper.SetDefault("health.check_redis", false)
+
+	// Audit
+	viper.SetDefault("audit.hash_chain", false)
+	viper.SetDefault("audit.verify_batch_size", 1000)
+	viper.SetDefault("audit.anchor.sink", "")
+	viper.SetDefault("audit.anchor.timeout", "10s")
+
+	// Schedules
+	viper.SetDefault("schedules.default_timezone", "Europe/Moscow")
+
+	// Messages
+	viper.SetDefault("messages.retention_days", 90)
+
+	// Geofences
+	viper.SetDefault("geofences.cache_ttl", "30s")
+
+	// Cities
+	viper.SetDefault("cities.cache_ttl", "1m")
+
+	// Driver app
+	viper.SetDefault("driver_app.flags_cache_ttl", "30s")
+	viper.SetDefault("driver_app.gps_upload_interval", "5s")
+	viper.SetDefault("driver_app.location_batch_size", 20)
+	viper.SetDefault("driver_app.heartbeat_interval", "1m")
+
+	// Dispatch
+	viper.SetDefault("dispatch.weights.distance", 0.4)
+	viper.SetDefault("dispatch.weights.rating", 0.2)
+	viper.SetDefault("dispatch.weights.acceptance", 0.2)
+	viper.SetDefault("dispatch.weights.idle", 0.2)
+	viper.SetDefault("dispatch.weights.cancellation", 0.2)
+	viper.SetDefault("dispatch.default_radius_km", 5.0)
+	viper.SetDefault("dispatch.max_candidates", 50)
+	viper.SetDefault("dispatch.max_idle", "30m")
+	viper.SetDefault("dispatch.stats_window", "168h")
+	viper.SetDefault("dispatch.min_offers", 10)
+	viper.SetDefault("dispatch.default_rating", 4.5)
+
+	// Shifts
+	viper.SetDefault("shifts.max_duration", "16h")
+	viper.SetDefault("shifts.break_required_after", "4h")
+	viper.SetDefault("shifts.min_break", "15m")
+
+	// Ratings
+	viper.SetDefault("ratings.max_per_customer_per_day", 20)
+	viper.SetDefault("ratings.suspicious_min_ratings", 5)
+	viper.SetDefault("ratings.suspicious_window", "720h")
+	viper.SetDefault("ratings.decay_half_life", "2160h")
+
+	// Referrals
+	viper.SetDefault("referrals.required_trips", 10)
+
+	// Invites
+	viper.SetDefault("invites.secret", "")
+	viper.SetDefault("invites.link_base_url", "driverapp://register")
+	viper.SetDefault("invites.default_ttl", "168h")
+	viper.SetDefault("invites.max_ttl", "720h")
+
+	// Order tracking
+	viper.SetDefault("order_tracking.secret", "")
+	viper.SetDefault("order_tracking.link_base_url", "")
+	viper.SetDefault("order_tracking.ttl", "6h")
+	viper.SetDefault("order_tracking.requests_per_minute", 30)
+
+	// Driver tags
+	viper.SetDefault("driver_tags.controlled", []string{"vip-capable", "pet-friendly", "wheelchair-accessible", "child-seat"})
+	viper.SetDefault("driver_tags.free_form", true)
+	viper.SetDefault("driver_tags.max_per_driver", 20)
+
+	// Phone verification
+	viper.SetDefault("phone.verification_required", false)
+	viper.SetDefault("phone.code_ttl", "10m")
+	viper.SetDefault("phone.resend_interval", "1m")
+	viper.SetDefault("phone.max_attempts", 5)
+
+	// Heartbeat
+	viper.SetDefault("heartbeat.offline_after", "10m")
+	viper.SetDefault("heartbeat.touch_interval", "30s")
+
+	// Statistics
+	viper.SetDefault("statistics.cache_ttl", "5m")
+	viper.SetDefault("statistics.daily_lookback_days", 2)
+	viper.SetDefault("statistics.daily_backfill_days", 30)
+	viper.SetDefault("statistics.dispatch_window_days", []int{1, 7, 30})
+
+	// Secrets
+	viper.SetDefault("secrets.vault.timeout", "5s")
+
+	// Reload
+	viper.SetDefault("reload.watch_interval", "10s")
+
+	// Notifications
+	viper.SetDefault("notifications.providers", []string{})
+	viper.SetDefault("notifications.default_channels", []string{NotificationChannelPush})
+	viper.SetDefault("notifications.timezone", "Europe/Moscow")
+	viper.SetDefault("notifications.push.timeout", "5s")
+	viper.SetDefault("notifications.email.port", 587)
+	viper.SetDefault("notifications.email.timeout", "10s")
+
+	// Auth
+	viper.SetDefault("auth.enabled", false)
+	viper.SetDefault("auth.jwks_refresh_interval", "10m")
+	viper.SetDefault("auth.clock_skew", "30s")
+	viper.SetDefault("auth.roles_claim", "roles")
+	viper.SetDefault("auth.driver_id_claim", "driver_id")
+	viper.SetDefault("auth.fleet_ids_claim", "fleet_ids")
+	viper.SetDefault("auth.scopes_claim", "scope")
+	viper.SetDefault("auth.grpc_required", false)
+	viper.SetDefault("auth.api_key_rate_limit", 600)
+	viper.SetDefault("auth.api_key_cache_ttl", "30s")
+
+	// Scheduler
+	viper.SetDefault("scheduler.timezone", "Europe/Moscow")
+	viper.SetDefault("scheduler.locking", true)
+	viper.SetDefault("scheduler.history_retention_days", 30)
+	viper.SetDefault("scheduler.jobs.location_cleanup.schedule", "0 3 * * *")
+	viper.SetDefault("scheduler.jobs.location_cleanup.timeout", "30m")
+	viper.SetDefault("scheduler.jobs.inspection_reminders.schedule", "0 10,16 * * *")
+	viper.SetDefault("scheduler.jobs.inspection_reminders.timeout", "5m")
+	viper.SetDefault("scheduler.jobs.profile_nudges.schedule", "0 12 * * *")
+	viper.SetDefault("scheduler.jobs.profile_nudges.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.leaderboard_refresh.schedule", "*/15 * * * *")
+	viper.SetDefault("scheduler.jobs.leaderboard_refresh.timeout", "5m")
+	viper.SetDefault("scheduler.jobs.release_stale_claims.schedule", "*/5 * * * *")
+	viper.SetDefault("scheduler.jobs.release_stale_claims.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.document_reminders.schedule", "0 11 * * *")
+	viper.SetDefault("scheduler.jobs.document_reminders.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.document_resubmission_reminders.schedule", "30 11 * * *")
+	viper.SetDefault("scheduler.jobs.document_resubmission_reminders.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.capacity_sample.schedule", "* * * * *")
+	viper.SetDefault("scheduler.jobs.capacity_sample.timeout", "10s")
+	viper.SetDefault("scheduler.jobs.capacity_report.schedule", "0 0 * * *")
+	viper.SetDefault("scheduler.jobs.capacity_report.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.document_expiry.schedule", "0 1 * * *")
+	viper.SetDefault("scheduler.jobs.document_expiry.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.shard_refresh.schedule", "* * * * *")
+	viper.SetDefault("scheduler.jobs.shard_refresh.timeout", "10s")
+	viper.SetDefault("scheduler.jobs.campaign_reminders.schedule", "0 10 * * *")
+	viper.SetDefault("scheduler.jobs.campaign_reminders.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.campaign_deadlines.schedule", "*/15 * * * *")
+	viper.SetDefault("scheduler.jobs.campaign_deadlines.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.audit_anchor.schedule", "0 * * * *")
+	viper.SetDefault("scheduler.jobs.audit_anchor.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.schedule_enforcement.schedule", "*/5 * * * *")
+	viper.SetDefault("scheduler.jobs.schedule_enforcement.timeout", "2m")
+	viper.SetDefault("scheduler.jobs.message_cleanup.schedule", "30 3 * * *")
+	viper.SetDefault("scheduler.jobs.message_cleanup.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.shift_auto_end.schedule", "*/10 * * * *")
+	viper.SetDefault("scheduler.jobs.shift_auto_end.timeout", "2m")
+	viper.SetDefault("scheduler.jobs.offline_detection.schedule", "* * * * *")
+	viper.SetDefault("scheduler.jobs.offline_detection.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.rating_recompute.schedule", "15 4 * * *")
+	viper.SetDefault("scheduler.jobs.rating_recompute.timeout", "30m")
+	viper.SetDefault("scheduler.jobs.job_history_cleanup.schedule", "45 3 * * *")
+	viper.SetDefault("scheduler.jobs.job_history_cleanup.timeout", "5m")
+	viper.SetDefault("scheduler.jobs.block_expiry.schedule", "* * * * *")
+	viper.SetDefault("scheduler.jobs.block_expiry.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.referral_progress.schedule", "*/15 * * * *")
+	viper.SetDefault("scheduler.jobs.referral_progress.timeout", "5m")
+	viper.SetDefault("scheduler.jobs.location_export_cleanup.schedule", "*/30 * * * *")
+	viper.SetDefault("scheduler.jobs.location_export_cleanup.timeout", "5m")
+	viper.SetDefault("scheduler.jobs.driver_daily_stats.schedule", "*/15 * * * *")
+	viper.SetDefault("scheduler.jobs.driver_daily_stats.timeout", "10m")
+	viper.SetDefault("scheduler.jobs.webhook_retry.schedule", "* * * * *")
+	viper.SetDefault("scheduler.jobs.webhook_retry.timeout", "1m")
+	viper.SetDefault("scheduler.jobs.location_partitions.schedule", "30 2 * * *")
+	viper.SetDefault("scheduler.jobs.location_partitions.timeout", "10m")
 }
 
 // GetDSN возвращает строку подключения к базе данных
@@ -227,21 +1604,822 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid gRPC port: %d", c.Server.GRPCPort)
 	}
 
-	if c.Database.Host == "" {
-		return fmt.Errorf("database host is required")
+	switch c.Server.Language {
+	case "en", "ru":
+	default:
+		return fmt.Errorf("invalid server language: %q (must be en or ru)", c.Server.Language)
 	}
 
-	if c.Database.User == "" {
-		return fmt.Errorf("database user is required")
+	if c.Server.Compression.MinSize < 0 {
+		return fmt.Errorf("server compression min_size must not be negative")
 	}
 
-	if c.Database.Database == "" {
-		return fmt.Errorf("database name is required")
+	switch c.Storage.Type {
+	case StorageTypePostgres:
+		if c.Database.Host == "" {
+			return fmt.Errorf("database host is required")
+		}
+
+		if c.Database.User == "" {
+			return fmt.Errorf("database user is required")
+		}
+
+		if c.Database.Database == "" {
+			return fmt.Errorf("database name is required")
+		}
+
+		if c.Database.RetryMaxAttempts < 1 {
+			return fmt.Errorf("database retry_max_attempts must be at least 1")
+		}
+
+		if c.Database.RetryMaxDelay < c.Database.RetryBaseDelay {
+			return fmt.Errorf("database retry_max_delay must not be less than retry_base_delay")
+		}
+
+		if c.Database.SlowQueryThreshold < 0 {
+			return fmt.Errorf("database slow_query_threshold must not be negative")
+		}
+
+		if err := c.validateReplicas(); err != nil {
+			return err
+		}
+	case StorageTypeMemory:
+		if c.Server.Environment == "production" {
+			return fmt.Errorf("memory storage is not allowed in production")
+		}
+	default:
+		return fmt.Errorf("invalid storage type: %s", c.Storage.Type)
 	}
 
 	if c.NATS.URL == "" {
 		return fmt.Errorf("NATS URL is required")
 	}
+	if locations := c.NATS.Locations; locations.Enabled {
+		if locations.Subject == "" {
+			return fmt.Errorf("NATS location subject is required")
+		}
+		if locations.BatchSize <= 0 || locations.FlushInterval <= 0 {
+			return fmt.Errorf("NATS location batch size and flush interval must be positive")
+		}
+		if locations.MaxPending < locations.BatchSize {
+			return fmt.Errorf("NATS location max pending must not be less than batch size")
+		}
+		if locations.MaxRetries < 0 || locations.RetryBackoff < 0 {
+			return fmt.Errorf("invalid NATS location retry settings")
+		}
+	}
 
-	return nil
-}
\ No newline at end of file
+	if mqtt := c.MQTT; mqtt.Enabled {
+		if mqtt.BrokerURL == "" || mqtt.ClientID == "" {
+			return fmt.Errorf("MQTT broker URL and client ID are required")
+		}
+		if mqtt.ConnectTimeout <= 0 {
+			return fmt.Errorf("MQTT connect timeout must be positive")
+		}
+		if len(mqtt.Topics) == 0 {
+			return fmt.Errorf("MQTT topics are required")
+		}
+		for _, topic := range mqtt.Topics {
+			if !strings.Contains(topic, "+") {
+				return fmt.Errorf("MQTT topic %s must contain + for the tracker ID", topic)
+			}
+		}
+		if mqtt.QoS < 0 || mqtt.QoS > 1 {
+			return fmt.Errorf("invalid MQTT QoS: %d", mqtt.QoS)
+		}
+		if mqtt.DedupWindow < 0 || (mqtt.DedupWindow > 0 && mqtt.DedupSize <= 0) {
+			return fmt.Errorf("invalid MQTT dedup settings")
+		}
+		if mqtt.TrackerCacheTTL < 0 {
+			return fmt.Errorf("MQTT tracker cache TTL must not be negative")
+		}
+	}
+
+	switch c.Logger.Redaction.Mode {
+	case "strict", "partial":
+	case "off":
+		if c.Server.Environment == "production" {
+			return fmt.Errorf("log redaction cannot be disabled in production")
+		}
+	default:
+		return fmt.Errorf("invalid log redaction mode: %s", c.Logger.Redaction.Mode)
+	}
+
+	requests := c.Logger.Requests
+	if !isRate(requests.SampleRate) || !isRate(requests.BodySampleRate) {
+		return fmt.Errorf("request log sample rates must be between 0 and 1")
+	}
+	if requests.MaxBodySize < 0 {
+		return fmt.Errorf("request log max body size must not be negative")
+	}
+	for _, route := range requests.Routes {
+		if len(strings.Fields(route.Route)) != 2 {
+			return fmt.Errorf("invalid request log route %q: expected \"METHOD /path\"", route.Route)
+		}
+		if (route.SampleRate != nil && !isRate(*route.SampleRate)) || (route.BodySampleRate != nil && !isRate(*route.BodySampleRate)) {
+			return fmt.Errorf("request log sample rates of route %q must be between 0 and 1", route.Route)
+		}
+	}
+
+	for _, metric := range c.Leaderboard.Metrics {
+		if !isLeaderboardMetric(metric) {
+			return fmt.Errorf("invalid leaderboard metric: %s", metric)
+		}
+		for _, tieBreaker := range c.Leaderboard.TieBreakers[metric] {
+			if !isLeaderboardMetric(tieBreaker) {
+				return fmt.Errorf("invalid leaderboard tie breaker for %s: %s", metric, tieBreaker)
+			}
+		}
+	}
+
+	if c.Auth.Enabled {
+		if c.Auth.HMACSecret == "" && c.Auth.PublicKeyFile == "" && c.Auth.JWKSURL == "" {
+			return fmt.Errorf("auth requires hmac_secret, public_key_file or jwks_url")
+		}
+	} else if c.Server.Environment == "production" {
+		return fmt.Errorf("auth cannot be disabled in production")
+	}
+	if c.Auth.GRPCRequired && !c.Auth.Enabled {
+		return fmt.Errorf("auth.grpc_required requires auth to be enabled")
+	}
+	if c.Auth.APIKeyRateLimit < 0 {
+		return fmt.Errorf("auth.api_key_rate_limit must not be negative")
+	}
+	if c.Auth.APIKeyCacheTTL < 0 {
+		return fmt.Errorf("auth.api_key_cache_ttl must not be negative")
+	}
+
+	if err := c.validateShutdown(); err != nil {
+		return err
+	}
+
+	if err := c.validateWebhooks(); err != nil {
+		return err
+	}
+
+	if err := c.validateAudit(); err != nil {
+		return err
+	}
+
+	if err := c.validateLicenseRegistry(); err != nil {
+		return err
+	}
+
+	if err := c.validateOCR(); err != nil {
+		return err
+	}
+
+	if err := c.validateSecurity(); err != nil {
+		return err
+	}
+
+	if err := c.validateNotifications(); err != nil {
+		return err
+	}
+
+	if c.Campaigns.ReminderIntervalDays <= 0 || c.Campaigns.BatchSize <= 0 {
+		return fmt.Errorf("campaign reminder interval and batch size must be positive")
+	}
+
+	if c.Warmup.Enabled && (c.Warmup.Timeout <= 0 || c.Warmup.Connections < 0 || c.Warmup.HotDrivers < 0) {
+		return fmt.Errorf("warm-up timeout must be positive, connections and hot drivers must not be negative")
+	}
+
+	if c.Health.Timeout <= 0 {
+		return fmt.Errorf("health check timeout must be positive")
+	}
+
+	if c.Inspections.PhotoIntervalDays < 0 || c.Inspections.PhotoGraceDays < 0 {
+		return fmt.Errorf("inspection photo interval and grace days must not be negative")
+	}
+
+	if c.Locations.MaxGapInterval < 0 {
+		return fmt.Errorf("location max gap interval must not be negative")
+	}
+	if ingestion := c.Locations.Ingestion; ingestion.Async {
+		if ingestion.Workers <= 0 || ingestion.BatchSize <= 0 || ingestion.FlushInterval <= 0 {
+			return fmt.Errorf("location ingestion workers, batch size and flush interval must be positive")
+		}
+		if ingestion.BufferSize < ingestion.Workers*ingestion.BatchSize {
+			return fmt.Errorf("location ingestion buffer size must hold a batch for every worker")
+		}
+	}
+
+	// ST_GeoHash строит geohash длиной не больше 12
+	if heatmap := c.Locations.Heatmap; heatmap.MaxPrecision < 1 || heatmap.MaxPrecision > 12 ||
+		heatmap.DefaultPrecision < 1 || heatmap.DefaultPrecision > heatmap.MaxPrecision || heatmap.MaxAge <= 0 {
+		return fmt.Errorf("location heatmap precision must be between 1 and max precision (at most 12) and max age must be positive")
+	}
+
+	if trips := c.Locations.Trips; trips.StopSpeed < 0 || trips.MinStopDuration < 0 || trips.MinDistanceKm < 0 ||
+		trips.SimplifyTolerance < 0 || trips.MaxRange <= 0 {
+		return fmt.Errorf("trip thresholds must not be negative and max range must be positive")
+	}
+
+	if exports := c.Locations.Exports; exports.Dir == "" || exports.Workers <= 0 || exports.QueueSize < 0 ||
+		exports.Timeout <= 0 || exports.MaxRange <= 0 || exports.ChunkInterval <= 0 || exports.URLTTL <= 0 || exports.Retention <= 0 {
+		return fmt.Errorf("location exports dir is required, workers, timeout, max range, chunk interval, url ttl and retention must be positive")
+	}
+	if exports := c.Locations.Exports; exports.StaleAfter <= exports.Timeout {
+		return fmt.Errorf("location exports stale_after must be greater than timeout")
+	}
+
+	if err := c.validateLocationRetention(); err != nil {
+		return err
+	}
+	if err := c.validateLocationAnalytics(); err != nil {
+		return err
+	}
+
+	if c.Sharding.Enabled {
+		if err := c.validateSharding(); err != nil {
+			return err
+		}
+	}
+
+	if len(c.Verification.RequiredDocuments) == 0 {
+		return fmt.Errorf("at least one required verification document must be configured")
+	}
+	for _, docType := range c.Verification.RequiredDocuments {
+		if !isDocumentType(docType) {
+			return fmt.Errorf("invalid required verification document: %s", docType)
+		}
+	}
+
+	if c.Documents.RenewalWindowDays <= 0 {
+		return fmt.Errorf("document renewal window must be positive")
+	}
+	if c.Documents.ResubmissionReminderDays <= 0 {
+		return fmt.Errorf("document resubmission reminder delay must be positive")
+	}
+
+	if err := c.validateSLO(); err != nil {
+		return err
+	}
+
+	if c.Capacity.WindowDays <= 0 || c.Capacity.HorizonDays <= 0 {
+		return fmt.Errorf("capacity forecast window and horizon must be positive")
+	}
+	for route, limit := range c.Capacity.EndpointLimits {
+		if limit <= 0 {
+			return fmt.Errorf("capacity limit for %q must be positive", route)
+		}
+	}
+
+	for _, currency := range c.Expenses.Currencies {
+		if len(currency) != 3 || strings.ToUpper(currency) != currency {
+			return fmt.Errorf("invalid expense currency: %s", currency)
+		}
+	}
+	for category := range c.Expenses.MaxAmount {
+		if !isExpenseCategory(category) {
+			return fmt.Errorf("invalid expense category: %s", category)
+		}
+	}
+
+	for _, currency := range c.Earnings.Currencies {
+		if len(currency) != 3 || strings.ToUpper(currency) != currency {
+			return fmt.Errorf("invalid earnings currency: %s", currency)
+		}
+	}
+	if _, err := time.LoadLocation(c.Earnings.Timezone); err != nil || c.Earnings.Timezone == "" {
+		return fmt.Errorf("invalid earnings timezone: %s", c.Earnings.Timezone)
+	}
+
+	if c.Scheduler.Timezone != "" {
+		if _, err := time.LoadLocation(c.Scheduler.Timezone); err != nil {
+			return fmt.Errorf("invalid scheduler timezone: %s", c.Scheduler.Timezone)
+		}
+	}
+	if c.Scheduler.HistoryRetentionDays < 0 {
+		return fmt.Errorf("job history retention days must not be negative")
+	}
+
+	if _, err := time.LoadLocation(c.Schedules.DefaultTimezone); err != nil || c.Schedules.DefaultTimezone == "" {
+		return fmt.Errorf("invalid default schedule timezone: %s", c.Schedules.DefaultTimezone)
+	}
+
+	if c.Messages.RetentionDays < 0 {
+		return fmt.Errorf("message retention days must not be negative")
+	}
+
+	if c.Geofences.CacheTTL < 0 {
+		return fmt.Errorf("geofence cache TTL must not be negative")
+	}
+
+	if c.Cities.CacheTTL < 0 {
+		return fmt.Errorf("city cache TTL must not be negative")
+	}
+
+	if c.DriverApp.FlagsCacheTTL < 0 {
+		return fmt.Errorf("driver app flags cache TTL must not be negative")
+	}
+	if c.DriverApp.GPSUploadInterval < time.Second || c.DriverApp.HeartbeatInterval < time.Second {
+		return fmt.Errorf("driver app GPS upload and heartbeat intervals must be at least 1s")
+	}
+	if c.DriverApp.LocationBatchSize <= 0 || c.DriverApp.LocationBatchSize > 1000 {
+		return fmt.Errorf("driver app location batch size must be between 1 and 1000")
+	}
+	if c.Heartbeat.OfflineAfter > 0 && c.DriverApp.HeartbeatInterval >= c.Heartbeat.OfflineAfter {
+		return fmt.Errorf("driver app heartbeat interval must be shorter than heartbeat offline_after")
+	}
+
+	weights := c.Dispatch.Weights
+	if weights.Distance < 0 || weights.Rating < 0 || weights.Acceptance < 0 || weights.Idle < 0 || weights.Cancellation < 0 {
+		return fmt.Errorf("dispatch weights must not be negative")
+	}
+	if weights.Distance+weights.Rating+weights.Acceptance+weights.Idle+weights.Cancellation == 0 {
+		return fmt.Errorf("at least one dispatch weight must be positive")
+	}
+	if c.Dispatch.DefaultRadiusKm <= 0 || c.Dispatch.MaxCandidates <= 0 {
+		return fmt.Errorf("dispatch default radius and max candidates must be positive")
+	}
+	if c.Dispatch.MaxIdle < 0 || c.Dispatch.StatsWindow <= 0 || c.Dispatch.MinOffers < 0 {
+		return fmt.Errorf("invalid dispatch statistics settings")
+	}
+	if c.Dispatch.DefaultRating < 0 || c.Dispatch.DefaultRating > 5 {
+		return fmt.Errorf("dispatch default rating must be between 0 and 5")
+	}
+
+	if c.Shifts.MaxDuration < 0 {
+		return fmt.Errorf("shift max duration must not be negative")
+	}
+	if c.Shifts.BreakRequiredAfter < 0 || c.Shifts.MinBreak < 0 {
+		return fmt.Errorf("shift break durations must not be negative")
+	}
+
+	ratings := c.Ratings
+	if ratings.MaxPerCustomerPerDay < 0 || ratings.SuspiciousMinRatings < 0 {
+		return fmt.Errorf("ratings limits must not be negative")
+	}
+	if ratings.SuspiciousMinRatings > 0 && ratings.SuspiciousWindow <= 0 {
+		return fmt.Errorf("ratings suspicious_window must be positive")
+	}
+	if ratings.DecayHalfLife < 0 {
+		return fmt.Errorf("ratings decay_half_life must not be negative")
+	}
+
+	if c.Referrals.RequiredTrips <= 0 {
+		return fmt.Errorf("referrals required_trips must be positive")
+	}
+
+	if c.Invites.DefaultTTL <= 0 || c.Invites.MaxTTL < c.Invites.DefaultTTL {
+		return fmt.Errorf("invites default_ttl must be positive and not exceed max_ttl")
+	}
+
+	if c.OrderTracking.TTL <= 0 {
+		return fmt.Errorf("order_tracking ttl must be positive")
+	}
+	if c.OrderTracking.RequestsPerMinute < 0 {
+		return fmt.Errorf("order_tracking requests_per_minute must not be negative")
+	}
+
+	if c.DriverTags.MaxPerDriver < 0 {
+		return fmt.Errorf("driver_tags max_per_driver must not be negative")
+	}
+	for _, tag := range c.DriverTags.Controlled {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("driver_tags controlled must not contain empty tags")
+		}
+	}
+
+	if c.Phone.CodeTTL <= 0 || c.Phone.ResendInterval < 0 || c.Phone.MaxAttempts <= 0 {
+		return fmt.Errorf("phone code_ttl and max_attempts must be positive, resend_interval must not be negative")
+	}
+
+	if c.Heartbeat.OfflineAfter < 0 || c.Heartbeat.TouchInterval < 0 {
+		return fmt.Errorf("heartbeat intervals must not be negative")
+	}
+	if c.Heartbeat.OfflineAfter > 0 && c.Heartbeat.TouchInterval >= c.Heartbeat.OfflineAfter {
+		return fmt.Errorf("heartbeat touch interval must be shorter than offline_after")
+	}
+
+	if c.Statistics.CacheTTL < 0 {
+		return fmt.Errorf("statistics cache TTL must not be negative")
+	}
+	if c.Statistics.DailyLookbackDays < 0 || c.Statistics.DailyBackfillDays < c.Statistics.DailyLookbackDays {
+		return fmt.Errorf("statistics daily lookback must not be negative and must not exceed backfill")
+	}
+	for _, days := range c.Statistics.DispatchWindowDays {
+		if days <= 0 {
+			return fmt.Errorf("statistics dispatch window days must be positive")
+		}
+	}
+
+	if c.Reload.WatchInterval < 0 {
+		return fmt.Errorf("config reload watch interval must not be negative")
+	}
+
+	return nil
+}
+
+// isLeaderboardMetric проверяет название показателя рейтинга
+func isLeaderboardMetric(metric string) bool {
+	switch metric {
+	case "trips", "rating", "earnings":
+		return true
+	}
+	return false
+}
+
+// isRate проверяет, что доля лежит в [0, 1]
+func isRate(rate float64) bool {
+	return rate >= 0 && rate <= 1
+}
+
+// isExpenseCategory проверяет название категории расходов
+func isExpenseCategory(category string) bool {
+	switch category {
+	case "fuel", "toll", "wash", "parking", "other":
+		return true
+	}
+	return false
+}
+
+// validateReplicas проверяет реплики для чтения
+func (c *Config) validateReplicas() error {
+	if len(c.Database.Replicas) == 0 {
+		return nil
+	}
+	for name := range c.Database.Replicas {
+		if c.ReplicaDatabase(name).Host == "" {
+			return fmt.Errorf("database replica %s requires a host", name)
+		}
+	}
+	if c.Database.ReplicaCheckInterval <= 0 {
+		return fmt.Errorf("database replica_check_interval must be positive")
+	}
+	if c.Database.ReplicaMaxLag < 0 {
+		return fmt.Errorf("database replica_max_lag must not be negative")
+	}
+	return nil
+}
+
+// validateSharding проверяет шарды и закрепление городов
+func (c *Config) validateSharding() error {
+	if c.Storage.Type != StorageTypePostgres {
+		return fmt.Errorf("sharding requires postgres storage")
+	}
+
+	known := func(shard string) bool {
+		_, ok := c.Sharding.Shards[shard]
+		return ok || shard == "primary"
+	}
+	for name := range c.Sharding.Shards {
+		if name == "primary" {
+			return fmt.Errorf("shard name primary is reserved for the main database")
+		}
+		if c.ShardDatabase(name).Database == "" {
+			return fmt.Errorf("shard %s requires a database name", name)
+		}
+	}
+	if !known(c.Sharding.DefaultShard) {
+		return fmt.Errorf("unknown default shard: %s", c.Sharding.DefaultShard)
+	}
+	for city, shard := range c.Sharding.Cities {
+		if !known(shard) {
+			return fmt.Errorf("unknown shard for city %s: %s", city, shard)
+		}
+	}
+	if c.Sharding.RebalanceBatchSize <= 0 {
+		return fmt.Errorf("shard rebalance batch size must be positive")
+	}
+	return nil
+}
+
+// clickHouseIdentifier допустимое имя базы или таблицы ClickHouse
+var clickHouseIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateLocationAnalytics проверяет копирование местоположений в ClickHouse
+func (c *Config) validateLocationAnalytics() error {
+	analytics := c.Locations.Analytics
+	if !analytics.Enabled {
+		return nil
+	}
+
+	target, err := url.Parse(analytics.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("invalid location analytics URL: %q", analytics.URL)
+	}
+	// Имена базы и таблицы подставляются в запросы
+	if !clickHouseIdentifier.MatchString(analytics.Database) || !clickHouseIdentifier.MatchString(analytics.Table) {
+		return fmt.Errorf("invalid location analytics database or table name: %q.%q", analytics.Database, analytics.Table)
+	}
+	if analytics.BufferSize <= 0 || analytics.BatchSize <= 0 || analytics.FlushInterval <= 0 || analytics.Timeout <= 0 {
+		return fmt.Errorf("location analytics buffer size, batch size, flush interval and timeout must be positive")
+	}
+	if analytics.RetryInterval <= 0 || analytics.MaxRetryInterval < analytics.RetryInterval {
+		return fmt.Errorf("location analytics retry interval must be positive and not greater than max retry interval")
+	}
+	return nil
+}
+
+// validateLocationRetention проверяет уровни хранения истории местоположений
+func (c *Config) validateLocationRetention() error {
+	retention := c.Locations.Retention
+	if retention.RawDays <= 0 {
+		return fmt.Errorf("location raw retention days must be positive")
+	}
+	if retention.PartitionsAhead < 1 {
+		return fmt.Errorf("location partitions ahead must be at least 1")
+	}
+	for name, tier := range retention.Tiers {
+		if tier.Interval <= 0 || tier.Interval%time.Second != 0 || (24*time.Hour)%tier.Interval != 0 {
+			return fmt.Errorf("location retention tier %s: interval must be a whole number of seconds dividing 24h", name)
+		}
+		// Сводные точки появляются только после удаления исходных
+		if tier.KeepDays < 0 || (tier.KeepDays > 0 && tier.KeepDays <= retention.RawDays) {
+			return fmt.Errorf("location retention tier %s: keep days must be 0 or longer than raw days", name)
+		}
+	}
+	return nil
+}
+
+// validateSLO проверяет цели операций: маршрут в формате "МЕТОД /путь", доли от 0 до 1
+// и окно из целого числа интервалов
+func (c *Config) validateSLO() error {
+	slo := c.Metrics.SLO
+	if !slo.Enabled {
+		return nil
+	}
+	if slo.Resolution <= 0 || slo.Window < slo.Resolution || slo.Window%slo.Resolution != 0 {
+		return fmt.Errorf("SLO window must be a positive multiple of resolution")
+	}
+	for name, objective := range slo.Objectives {
+		if len(objective.Routes) == 0 {
+			return fmt.Errorf("SLO objective %s: routes are required", name)
+		}
+		for _, route := range objective.Routes {
+			if parts := strings.Fields(route); len(parts) != 2 || !strings.HasPrefix(parts[1], "/") {
+				return fmt.Errorf("SLO objective %s: route %q must be \"METHOD /path\"", name, route)
+			}
+		}
+		if objective.LatencyObjective < 0 || objective.LatencyObjective >= 1 ||
+			objective.AvailabilityObjective < 0 || objective.AvailabilityObjective >= 1 {
+			return fmt.Errorf("SLO objective %s: objectives must be in [0, 1)", name)
+		}
+		if objective.LatencyObjective > 0 && objective.LatencyTarget <= 0 {
+			return fmt.Errorf("SLO objective %s: latency objective requires a positive latency target", name)
+		}
+	}
+	return nil
+}
+
+// validateWebhooks проверяет вебхуки автопарков
+func (c *Config) validateWebhooks() error {
+	for fleetID, webhook := range c.Webhooks.Fleets {
+		target, err := url.Parse(webhook.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid webhook URL for fleet %s: %q", fleetID, webhook.URL)
+		}
+		if webhook.Timeout < 0 {
+			return fmt.Errorf("webhook timeout for fleet %s must not be negative", fleetID)
+		}
+	}
+	for _, key := range c.Webhooks.EnrichmentKeys {
+		// Автопарк не может перевести водителя в другой автопарк
+		if key == "fleet_id" {
+			return fmt.Errorf("fleet_id cannot be a webhook enrichment key")
+		}
+	}
+
+	delivery := c.Webhooks.Delivery
+	if delivery.Workers <= 0 || delivery.QueueSize < 0 || delivery.Timeout <= 0 || delivery.MaxAttempts <= 0 ||
+		delivery.InitialBackoff <= 0 || delivery.MaxBackoff < delivery.InitialBackoff || delivery.RetryBatch <= 0 ||
+		delivery.CacheTTL < 0 {
+		return fmt.Errorf("invalid webhook delivery settings")
+	}
+	// Иначе доставку, которая еще отправляется, подхватит задача повторов
+	if delivery.Lease <= delivery.Timeout {
+		return fmt.Errorf("webhook delivery lease must be longer than timeout")
+	}
+	return nil
+}
+
+// validateAudit проверяет цепочку хешей журнала аудита и ее закрепление
+func (c *Config) validateAudit() error {
+	if c.Audit.VerifyBatchSize <= 0 {
+		return fmt.Errorf("audit verify batch size must be positive")
+	}
+
+	anchor := c.Audit.Anchor
+	if anchor.Sink == "" {
+		return nil
+	}
+	if !c.Audit.HashChain {
+		return fmt.Errorf("audit anchoring requires audit.hash_chain")
+	}
+	if anchor.Timeout <= 0 {
+		return fmt.Errorf("audit anchor timeout must be positive")
+	}
+	switch anchor.Sink {
+	case AuditAnchorSinkFile:
+		if anchor.FilePath == "" {
+			return fmt.Errorf("audit anchor file path is required")
+		}
+	case AuditAnchorSinkWebhook:
+		target, err := url.Parse(anchor.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid audit anchor URL: %q", anchor.URL)
+		}
+	default:
+		return fmt.Errorf("unknown audit anchor sink: %s", anchor.Sink)
+	}
+	return nil
+}
+
+// validateShutdown проверяет сроки остановки: сроки подсистем укладываются в общий
+func (c *Config) validateShutdown() error {
+	shutdown := c.Server.Shutdown
+	if shutdown.Timeout <= 0 {
+		return fmt.Errorf("server.shutdown.timeout must be positive")
+	}
+	drains := map[string]time.Duration{
+		"location_drain_timeout":  shutdown.LocationDrainTimeout,
+		"websocket_drain_timeout": shutdown.WebSocketDrainTimeout,
+	}
+	for name, timeout := range drains {
+		if timeout <= 0 {
+			return fmt.Errorf("server.shutdown.%s must be positive", name)
+		}
+	}
+	if shutdown.LocationDrainTimeout+shutdown.WebSocketDrainTimeout > shutdown.Timeout {
+		return fmt.Errorf("server.shutdown drain timeouts must fit into server.shutdown.timeout")
+	}
+	return nil
+}
+
+// validateLicenseRegistry проверяет сверку удостоверений с внешним реестром
+func (c *Config) validateLicenseRegistry() error {
+	registry := c.External.LicenseRegistry
+	switch registry.Provider {
+	case "":
+		return nil
+	case LicenseRegistryProviderGIBDD:
+		api := c.External.GIBDDAPI
+		target, err := url.Parse(api.BaseURL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid gibdd API base URL: %q", api.BaseURL)
+		}
+		if api.Timeout <= 0 {
+			return fmt.Errorf("gibdd API timeout must be positive")
+		}
+	case LicenseRegistryProviderStub:
+		if c.Server.Environment == "production" {
+			return fmt.Errorf("stub license registry is not allowed in production")
+		}
+	default:
+		return fmt.Errorf("unknown license registry provider: %s", registry.Provider)
+	}
+
+	if registry.CacheTTL < 0 {
+		return fmt.Errorf("license registry cache_ttl must not be negative")
+	}
+	if registry.FailureThreshold < 0 {
+		return fmt.Errorf("license registry failure_threshold must not be negative")
+	}
+	if registry.FailureThreshold > 0 && registry.OpenTimeout <= 0 {
+		return fmt.Errorf("license registry open_timeout must be positive")
+	}
+	return nil
+}
+
+// validateOCR проверяет распознавание документов
+func (c *Config) validateOCR() error {
+	ocr := c.External.OCR
+	switch ocr.Provider {
+	case "":
+		return nil
+	case OCRProviderHTTP:
+		target, err := url.Parse(ocr.BaseURL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("invalid OCR base URL: %q", ocr.BaseURL)
+		}
+	case OCRProviderStub:
+		if c.Server.Environment == "production" {
+			return fmt.Errorf("stub OCR provider is not allowed in production")
+		}
+	default:
+		return fmt.Errorf("unknown OCR provider: %s", ocr.Provider)
+	}
+
+	if ocr.Timeout <= 0 {
+		return fmt.Errorf("OCR timeout must be positive")
+	}
+	if ocr.Workers <= 0 {
+		return fmt.Errorf("OCR workers must be positive")
+	}
+	if ocr.QueueSize < 0 {
+		return fmt.Errorf("OCR queue_size must not be negative")
+	}
+	return nil
+}
+
+// validateNotifications проверяет каналы уведомлений и настройки включенных провайдеров
+func (c *Config) validateNotifications() error {
+	n := c.Notifications
+	if _, err := time.LoadLocation(n.Timezone); err != nil || n.Timezone == "" {
+		return fmt.Errorf("invalid notifications timezone: %s", n.Timezone)
+	}
+
+	for _, channel := range n.DefaultChannels {
+		if !isNotificationChannel(channel) {
+			return fmt.Errorf("unknown notification channel: %s", channel)
+		}
+	}
+	for _, tmpl := range n.Templates {
+		if tmpl.Name == "" {
+			return fmt.Errorf("notification template name is required")
+		}
+		for _, channel := range tmpl.Channels {
+			if !isNotificationChannel(channel) {
+				return fmt.Errorf("unknown notification channel %s in template %s", channel, tmpl.Name)
+			}
+		}
+	}
+
+	for _, provider := range n.Providers {
+		switch provider {
+		case NotificationChannelSMS:
+			sms := c.External.SMSAPI
+			target, err := url.Parse(sms.BaseURL)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				return fmt.Errorf("invalid SMS API base URL: %q", sms.BaseURL)
+			}
+			if sms.Timeout <= 0 {
+				return fmt.Errorf("SMS API timeout must be positive")
+			}
+		case NotificationChannelPush:
+			target, err := url.Parse(n.Push.URL)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				return fmt.Errorf("invalid push gateway URL: %q", n.Push.URL)
+			}
+			if n.Push.Timeout <= 0 {
+				return fmt.Errorf("push gateway timeout must be positive")
+			}
+		case NotificationChannelEmail:
+			if n.Email.Host == "" || n.Email.From == "" {
+				return fmt.Errorf("email notifications require host and from")
+			}
+			if n.Email.Port <= 0 || n.Email.Port > 65535 {
+				return fmt.Errorf("invalid SMTP port: %d", n.Email.Port)
+			}
+			if n.Email.Timeout <= 0 {
+				return fmt.Errorf("SMTP timeout must be positive")
+			}
+		default:
+			return fmt.Errorf("unknown notification provider: %s", provider)
+		}
+	}
+	return nil
+}
+
+// isNotificationChannel проверяет название канала уведомлений
+func isNotificationChannel(channel string) bool {
+	switch channel {
+	case NotificationChannelSMS, NotificationChannelPush, NotificationChannelEmail:
+		return true
+	}
+	return false
+}
+
+// validateSecurity проверяет пороги обнаружения подозрительной активности
+func (c *Config) validateSecurity() error {
+	if c.Security.HistoryDays <= 0 {
+		return fmt.Errorf("security history days must be positive")
+	}
+	if c.Security.MaxConcurrentSessions <= 0 {
+		return fmt.Errorf("security max concurrent sessions must be positive")
+	}
+	if c.Security.MaxPlausibleSpeedKmh <= 0 {
+		return fmt.Errorf("security max plausible speed must be positive")
+	}
+	if c.Security.ConcurrentWindow < 0 || c.Security.AlertCooldown < 0 || c.Security.SessionCacheTTL < 0 {
+		return fmt.Errorf("security windows must not be negative")
+	}
+	for _, eventType := range c.Security.AutoRevoke {
+		if !isSecurityEventType(eventType) {
+			return fmt.Errorf("invalid security auto revoke event type: %s", eventType)
+		}
+	}
+	return nil
+}
+
+// isSecurityEventType проверяет название типа события безопасности
+func isSecurityEventType(eventType string) bool {
+	switch eventType {
+	case "new_device", "new_country", "location_jump", "simultaneous_sessions":
+		return true
+	}
+	return false
+}
+
+// isDocumentType проверяет название типа документа
+func isDocumentType(docType string) bool {
+	switch docType {
+	case "driver_license", "medical_certificate", "vehicle_registration", "insurance",
+		"passport", "taxi_permit", "work_permit":
+		return true
+	}
+	return false
+}
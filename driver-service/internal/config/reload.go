@@ -0,0 +1,93 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// ReloadConfig конфигурация перечитывания настроек без перезапуска
+type ReloadConfig struct {
+	// WatchInterval как часто проверяется изменение файла конфигурации; 0 — только по SIGHUP
+	WatchInterval time.Duration `mapstructure:"watch_interval"`
+}
+
+// RequiresRestart сообщает, отличается ли next от c в настройках, которые применяются только
+// при запуске. Без перезапуска применяются уровень логирования (logger.level), лимиты частоты
+// запросов (capacity.default_limit_rps, capacity.endpoint_limits), расписания и таймауты
+// фоновых задач (scheduler.jobs.*.schedule, scheduler.jobs.*.timeout) и сам reload
+func (c *Config) RequiresRestart(next *Config) bool {
+	return !reflect.DeepEqual(c.withoutReloadable(), next.withoutReloadable())
+}
+
+// withoutReloadable возвращает копию конфигурации без настроек, применяемых на лету
+func (c *Config) withoutReloadable() Config {
+	stripped := *c
+	stripped.Logger.Level = ""
+	stripped.Capacity.DefaultLimitRPS = 0
+	stripped.Capacity.EndpointLimits = nil
+	stripped.Dispatch.Weights = DispatchWeightsConfig{}
+	stripped.Reload = ReloadConfig{}
+
+	jobs := make(map[string]JobConfig, len(c.Scheduler.Jobs))
+	for name, job := range c.Scheduler.Jobs {
+		// Включение и выключение задачи применяется только при запуске
+		jobs[name] = JobConfig{Disabled: job.Disabled || job.Schedule == ""}
+	}
+	stripped.Scheduler.Jobs = jobs
+
+	return stripped
+}
+
+// Watch вызывает reload по SIGHUP и при изменении файла конфигурации, пока ctx не отменен.
+// reload вызывается последовательно из одной горутины
+func Watch(ctx context.Context, interval time.Duration, reload func()) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	path := viper.ConfigFileUsed()
+	var ticks <-chan time.Time
+	if interval > 0 && path != "" {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+	// Kubernetes подменяет файл ConfigMap через символическую ссылку, поэтому сравниваются
+	// время изменения и размер файла, на который она указывает
+	lastMod, lastSize := configFileStamp(path)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			lastMod, lastSize = configFileStamp(path)
+			reload()
+		case <-ticks:
+			mod, size := configFileStamp(path)
+			if mod.Equal(lastMod) && size == lastSize {
+				continue
+			}
+			lastMod, lastSize = mod, size
+			reload()
+		}
+	}
+}
+
+// configFileStamp возвращает время изменения и размер файла; нулевые значения, если файла нет
+func configFileStamp(path string) (time.Time, int64) {
+	if path == "" {
+		return time.Time{}, 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
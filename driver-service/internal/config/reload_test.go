@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_RequiresRestart(t *testing.T) {
+	base := func() *Config {
+		return &Config{
+			Logger:   LoggerConfig{Level: "info"},
+			Database: DatabaseConfig{Host: "localhost", Password: "secret"},
+			Capacity: CapacityConfig{DefaultLimitRPS: 100, EndpointLimits: map[string]float64{"GET /api/v1/drivers": 50}},
+			Scheduler: SchedulerConfig{Jobs: map[string]JobConfig{
+				JobLocationCleanup: {Schedule: "0 3 * * *", Timeout: 30 * time.Minute},
+				JobMessageCleanup:  {Schedule: "", Timeout: 10 * time.Minute},
+			}},
+		}
+	}
+
+	current := base()
+	next := base()
+	next.Logger.Level = "debug"
+	next.Capacity.DefaultLimitRPS = 200
+	next.Capacity.EndpointLimits = nil
+	next.Scheduler.Jobs[JobLocationCleanup] = JobConfig{Schedule: "0 4 * * *", Timeout: time.Hour}
+	next.Reload.WatchInterval = time.Minute
+	next.Dispatch.Weights = DispatchWeightsConfig{Distance: 1}
+	assert.False(t, current.RequiresRestart(next))
+
+	next = base()
+	next.Database.Password = "rotated"
+	assert.True(t, current.RequiresRestart(next))
+
+	// Задача без расписания выключена: включить ее можно только перезапуском
+	next = base()
+	next.Scheduler.Jobs[JobMessageCleanup] = JobConfig{Schedule: "30 3 * * *"}
+	assert.True(t, current.RequiresRestart(next))
+}
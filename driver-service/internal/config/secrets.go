@@ -0,0 +1,262 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Провайдеры секретов. Значение вида ${env:NAME}, ${file:/run/secrets/db_password} или
+// ${vault:secret/data/driver-service#db_password} заменяется секретом при загрузке конфигурации
+const (
+	SecretProviderEnv   = "env"
+	SecretProviderFile  = "file"
+	SecretProviderVault = "vault"
+)
+
+// SecretsConfig конфигурация провайдеров секретов для учетных данных БД и NATS
+type SecretsConfig struct {
+	// FileDir каталог, от которого отсчитываются относительные пути ${file:...}
+	FileDir string      `mapstructure:"file_dir"`
+	Vault   VaultConfig `mapstructure:"vault"`
+}
+
+// VaultConfig подключение к HashiCorp Vault (KV v1 и v2)
+type VaultConfig struct {
+	// Address адрес Vault; пусто — ссылки ${vault:...} не поддерживаются
+	Address string `mapstructure:"address"`
+	// Token токен доступа; может ссылаться на ${env:...} или ${file:...}
+	Token     string        `mapstructure:"token"`
+	Namespace string        `mapstructure:"namespace"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// SecretsProvider источник секретов
+type SecretsProvider interface {
+	// Secret возвращает секрет по ссылке, формат которой определяет провайдер
+	Secret(ctx context.Context, ref string) (string, error)
+}
+
+// EnvSecrets читает секреты из переменных окружения: ${env:NAME}
+type EnvSecrets struct{}
+
+// Secret возвращает значение переменной окружения; незаданная переменная — ошибка
+func (EnvSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	value, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", ref)
+	}
+	return value, nil
+}
+
+// FileSecrets читает секреты из файлов (Docker и Kubernetes secrets): ${file:path}
+type FileSecrets struct {
+	// Dir каталог относительных путей; пусто — рабочий каталог
+	Dir string
+}
+
+// Secret возвращает содержимое файла без завершающего перевода строки
+func (p FileSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	path := ref
+	if !filepath.IsAbs(path) && p.Dir != "" {
+		path = filepath.Join(p.Dir, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file: %w", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// maxVaultResponseSize ограничение размера ответа Vault
+const maxVaultResponseSize = 1 << 20
+
+// VaultSecrets читает секреты из Vault по HTTP API: ${vault:path#key}, где path — путь
+// после /v1/ (для KV v2 вида secret/data/driver-service), key — поле секрета
+type VaultSecrets struct {
+	address   string
+	token     string
+	namespace string
+	timeout   time.Duration
+	client    *http.Client
+}
+
+// NewVaultSecrets создает VaultSecrets
+func NewVaultSecrets(address, token, namespace string, timeout time.Duration) *VaultSecrets {
+	return &VaultSecrets{
+		address:   strings.TrimRight(address, "/"),
+		token:     token,
+		namespace: namespace,
+		timeout:   timeout,
+		client:    &http.Client{},
+	}
+}
+
+// vaultResponse ответ Vault; для KV v2 поля секрета вложены в data.data
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// Secret читает поле секрета из Vault
+func (p *VaultSecrets) Secret(ctx context.Context, ref string) (string, error) {
+	path, key, ok := strings.Cut(ref, "#")
+	if !ok || path == "" || key == "" {
+		return "", fmt.Errorf("vault secret reference must look like path#key: %q", ref)
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.address+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault responded with status %d for %s", resp.StatusCode, path)
+	}
+
+	var result vaultResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxVaultResponseSize)).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid vault response: %w", err)
+	}
+
+	fields := result.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		if _, versioned := fields["metadata"]; versioned {
+			fields = nested
+		}
+	}
+	value, ok := fields[key].(string)
+	if !ok {
+		return "", fmt.Errorf("vault secret %s has no string field %s", path, key)
+	}
+	return value, nil
+}
+
+// SecretsResolver раскрывает ссылки на секреты через зарегистрированных провайдеров
+type SecretsResolver struct {
+	providers map[string]SecretsProvider
+}
+
+// NewSecretsResolver создает SecretsResolver с провайдерами env и file, а также vault,
+// если задан secrets.vault.address. Токен Vault может ссылаться на env и file
+func NewSecretsResolver(ctx context.Context, cfg SecretsConfig) (*SecretsResolver, error) {
+	resolver := &SecretsResolver{
+		providers: map[string]SecretsProvider{
+			SecretProviderEnv:  EnvSecrets{},
+			SecretProviderFile: FileSecrets{Dir: cfg.FileDir},
+		},
+	}
+
+	if cfg.Vault.Address != "" {
+		token, err := resolver.Resolve(ctx, cfg.Vault.Token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve vault token: %w", err)
+		}
+		resolver.providers[SecretProviderVault] = NewVaultSecrets(cfg.Vault.Address, token, cfg.Vault.Namespace, cfg.Vault.Timeout)
+	}
+
+	return resolver, nil
+}
+
+// Register добавляет или заменяет провайдера
+func (r *SecretsResolver) Register(name string, provider SecretsProvider) {
+	r.providers[name] = provider
+}
+
+// Resolve возвращает секрет для ссылки ${provider:ref}; другие значения возвращаются как есть
+func (r *SecretsResolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") {
+		return value, nil
+	}
+
+	name, ref, ok := strings.Cut(value[2:len(value)-1], ":")
+	if !ok || ref == "" {
+		return "", fmt.Errorf("invalid secret reference %q", value)
+	}
+	provider, ok := r.providers[name]
+	if !ok {
+		return "", fmt.Errorf("secret provider %s is not configured", name)
+	}
+
+	secret, err := provider.Secret(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s secret: %w", name, err)
+	}
+	return secret, nil
+}
+
+// ResolveSecrets заменяет ссылки на секреты в учетных данных БД, шардов и NATS
+func (c *Config) ResolveSecrets(ctx context.Context, resolver *SecretsResolver) error {
+	resolve := func(field string, value *string) error {
+		secret, err := resolver.Resolve(ctx, *value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		*value = secret
+		return nil
+	}
+
+	fields := map[string]*string{
+		"database.user":     &c.Database.User,
+		"database.password": &c.Database.Password,
+		"nats.url":          &c.NATS.URL,
+		"nats.user":         &c.NATS.User,
+		"nats.password":     &c.NATS.Password,
+		"nats.token":        &c.NATS.Token,
+		"mqtt.broker_url":   &c.MQTT.BrokerURL,
+		"mqtt.username":     &c.MQTT.Username,
+		"mqtt.password":     &c.MQTT.Password,
+
+		"locations.analytics.password": &c.Locations.Analytics.Password,
+		"invites.secret":               &c.Invites.Secret,
+		"order_tracking.secret":        &c.OrderTracking.Secret,
+	}
+	for field, value := range fields {
+		if err := resolve(field, value); err != nil {
+			return err
+		}
+	}
+
+	for name, shard := range c.Sharding.Shards {
+		if err := resolve("sharding.shards."+name+".user", &shard.User); err != nil {
+			return err
+		}
+		if err := resolve("sharding.shards."+name+".password", &shard.Password); err != nil {
+			return err
+		}
+		c.Sharding.Shards[name] = shard
+	}
+
+	for name, replica := range c.Database.Replicas {
+		if err := resolve("database.replicas."+name+".user", &replica.User); err != nil {
+			return err
+		}
+		if err := resolve("database.replicas."+name+".password", &replica.Password); err != nil {
+			return err
+		}
+		c.Database.Replicas[name] = replica
+	}
+
+	return nil
+}
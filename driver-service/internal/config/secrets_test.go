@@ -0,0 +1,104 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newVaultServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/driver-service":
+			w.Write([]byte(`{"data":{"data":{"db_password":"from-vault-v2"},"metadata":{"version":3}}}`))
+		case "/v1/kv/nats":
+			w.Write([]byte(`{"data":{"token":"from-vault-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSecretsResolver_Providers(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_user"), []byte("driver_service\n"), 0o600))
+	t.Setenv("TEST_VAULT_TOKEN", "vault-token")
+	t.Setenv("TEST_DB_PASSWORD", "from-env")
+
+	server := newVaultServer(t)
+	resolver, err := NewSecretsResolver(ctx, SecretsConfig{
+		FileDir: dir,
+		Vault:   VaultConfig{Address: server.URL + "/", Token: "${env:TEST_VAULT_TOKEN}", Timeout: time.Second},
+	})
+	require.NoError(t, err)
+
+	cases := map[string]string{
+		"plain":                   "plain",
+		"${env:TEST_DB_PASSWORD}": "from-env",
+		"${file:db_user}":         "driver_service",
+		"${vault:secret/data/driver-service#db_password}": "from-vault-v2",
+		"${vault:kv/nats#token}":                          "from-vault-v1",
+	}
+	for value, expected := range cases {
+		secret, err := resolver.Resolve(ctx, value)
+		require.NoError(t, err, value)
+		assert.Equal(t, expected, secret, value)
+	}
+
+	for _, value := range []string{
+		"${env:TEST_UNSET_SECRET}",
+		"${file:missing}",
+		"${vault:secret/data/driver-service#missing}",
+		"${vault:secret/data/unknown#key}",
+		"${vault:no-key}",
+		"${aws:secret}",
+		"${env}",
+	} {
+		_, err := resolver.Resolve(ctx, value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestConfig_ResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_DB_PASSWORD", "primary-secret")
+	t.Setenv("TEST_SHARD_PASSWORD", "shard-secret")
+	t.Setenv("TEST_REPLICA_PASSWORD", "replica-secret")
+
+	resolver, err := NewSecretsResolver(context.Background(), SecretsConfig{})
+	require.NoError(t, err)
+
+	cfg := &Config{
+		Database: DatabaseConfig{User: "driver_service", Password: "${env:TEST_DB_PASSWORD}", Replicas: map[string]DatabaseConfig{
+			"replica1": {Host: "replica1-db", Password: "${env:TEST_REPLICA_PASSWORD}"},
+		}},
+		NATS: NATSConfig{URL: "nats://localhost:4222", Token: "${env:TEST_DB_PASSWORD}"},
+		Sharding: ShardingConfig{Shards: map[string]DatabaseConfig{
+			"spb": {Host: "spb-db", Password: "${env:TEST_SHARD_PASSWORD}"},
+		}},
+	}
+	require.NoError(t, cfg.ResolveSecrets(context.Background(), resolver))
+	assert.Equal(t, "driver_service", cfg.Database.User)
+	assert.Equal(t, "primary-secret", cfg.Database.Password)
+	assert.Equal(t, "primary-secret", cfg.NATS.Token)
+	assert.Equal(t, "shard-secret", cfg.Sharding.Shards["spb"].Password)
+	assert.Equal(t, "spb-db", cfg.Sharding.Shards["spb"].Host)
+	assert.Equal(t, "replica-secret", cfg.Database.Replicas["replica1"].Password)
+
+	cfg.NATS.Password = "${vault:secret/data/nats#password}"
+	assert.Error(t, cfg.ResolveSecrets(context.Background(), resolver))
+}
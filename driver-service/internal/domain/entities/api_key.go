@@ -0,0 +1,183 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix начало всех ключей API: по нему ключ отличается от JWT и находится
+// сканерами утечек
+const APIKeyPrefix = "dsk_"
+
+// apiKeyPrefixLength сколько символов ключа хранится открыто, чтобы администратор
+// мог узнать ключ в списке
+const apiKeyPrefixLength = len(APIKeyPrefix) + 8
+
+// apiKeyMaxNameLength ограничение длины имени вызывающего сервиса
+const apiKeyMaxNameLength = 100
+
+// Области действия ключей API: роль, с которой вызывающий сервис проходит правила доступа
+// к маршрутам. Роли водителя и партнера ключам не выдаются: они привязаны к водителю и автопаркам.
+// Ключу с ролью можно дополнительно выдать ScopeLocationReadPrecise и ScopePIIRead
+const (
+	APIKeyScopeDispatcher = "dispatcher"
+	APIKeyScopeAdmin      = "admin"
+)
+
+// APIKeyScopeList области действия ключа, хранимые в JSONB
+type APIKeyScopeList []string
+
+// Value реализует driver.Valuer
+func (l APIKeyScopeList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *APIKeyScopeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into APIKeyScopeList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// APIKey ключ API внутреннего сервиса (сервис заказов, биллинг). Хранится только
+// хеш ключа; сам ключ показывается один раз при выпуске
+type APIKey struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// Name вызывающий сервис, например order-service; попадает в журнал аудита как автор изменений
+	Name string `json:"name" db:"name"`
+	// Prefix начало ключа для поиска в списке
+	Prefix  string          `json:"prefix" db:"prefix"`
+	KeyHash string          `json:"-" db:"key_hash"`
+	Scopes  APIKeyScopeList `json:"scopes" db:"scopes"`
+	// RateLimitPerMinute сколько запросов в минуту принимается по ключу; 0 — без ограничения
+	RateLimitPerMinute int        `json:"rate_limit_per_minute" db:"rate_limit_per_minute"`
+	CreatedBy          string     `json:"created_by" db:"created_by"`
+	CreatedAt          time.Time  `json:"created_at" db:"created_at"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt          *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedBy          *string    `json:"revoked_by,omitempty" db:"revoked_by"`
+}
+
+// Subject субъект, от имени которого выполняются запросы по ключу
+func (k *APIKey) Subject() string {
+	return "service:" + k.Name
+}
+
+// IsActive проверяет, что ключ не отозван и не истек к моменту at
+func (k *APIKey) IsActive(at time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || at.Before(*k.ExpiresAt)
+}
+
+// Revoke отзывает ключ
+func (k *APIKey) Revoke(actorID string, at time.Time) error {
+	if k.RevokedAt != nil {
+		return ErrAPIKeyRevoked
+	}
+	k.RevokedAt = &at
+	if actorID != "" {
+		k.RevokedBy = &actorID
+	}
+	return nil
+}
+
+// IssueAPIKeyRequest запрос выпуска ключа API
+type IssueAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"`
+	// RateLimitPerMinute ограничение частоты запросов; 0 — ограничение по умолчанию из конфигурации
+	RateLimitPerMinute int        `json:"rate_limit_per_minute"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+	// CreatedBy администратор, выпустивший ключ; по умолчанию субъект токена
+	CreatedBy string `json:"created_by"`
+}
+
+// Validate проверяет запрос: известные области действия, положительное ограничение частоты
+// и срок действия в будущем
+func (r *IssueAPIKeyRequest) Validate(now time.Time) error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" || len(name) > apiKeyMaxNameLength || strings.TrimSpace(r.CreatedBy) == "" {
+		return ErrInvalidAPIKeyRequest
+	}
+	if len(r.Scopes) == 0 {
+		return ErrInvalidAPIKeyRequest
+	}
+	hasRole := false
+	for _, scope := range r.Scopes {
+		switch scope {
+		case APIKeyScopeDispatcher, APIKeyScopeAdmin:
+			hasRole = true
+		case ScopeLocationReadPrecise, ScopePIIRead:
+		default:
+			return ErrInvalidAPIKeyRequest
+		}
+	}
+	if !hasRole {
+		return ErrInvalidAPIKeyRequest
+	}
+	if r.RateLimitPerMinute < 0 {
+		return ErrInvalidAPIKeyRequest
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(now) {
+		return ErrInvalidAPIKeyRequest
+	}
+	return nil
+}
+
+// IssuedAPIKey выпущенный ключ вместе с самим ключом, который больше не будет показан
+type IssuedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// NewAPIKey выпускает ключ по проверенному запросу
+func NewAPIKey(req *IssueAPIKeyRequest) (*IssuedAPIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &IssuedAPIKey{
+		APIKey: &APIKey{
+			ID:                 uuid.New(),
+			Name:               strings.TrimSpace(req.Name),
+			Prefix:             key[:apiKeyPrefixLength],
+			KeyHash:            HashAPIKey(key),
+			Scopes:             APIKeyScopeList(req.Scopes),
+			RateLimitPerMinute: req.RateLimitPerMinute,
+			CreatedBy:          req.CreatedBy,
+			CreatedAt:          time.Now(),
+			ExpiresAt:          req.ExpiresAt,
+		},
+		Key: key,
+	}, nil
+}
+
+// HashAPIKey хеш ключа, по которому ключ ищется в хранилище. Ключи случайные и длинные,
+// поэтому достаточно SHA-256 без соли
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,199 @@
+package entities
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// События журнала аудита водителей
+const (
+	// AuditEventFleetValidation решение вебхука автопарка по изменению профиля
+	AuditEventFleetValidation = "fleet_validation"
+	// AuditEventPersonalDataErasure удаление персональных данных по запросу водителя
+	AuditEventPersonalDataErasure = "personal_data_erasure"
+	// AuditEventDriverUpdate изменение профиля водителя
+	AuditEventDriverUpdate = "driver_update"
+	// AuditEventDriverStatus изменение статуса водителя
+	AuditEventDriverStatus = "driver_status"
+	// AuditEventDriverStatusOverride смена статуса водителя администратором в обход правил переходов
+	AuditEventDriverStatusOverride = "driver_status_override"
+	// AuditEventDriverBlock блокировка водителя с кодом причины
+	AuditEventDriverBlock = "driver_block"
+	// AuditEventDriverUnblock снятие блокировки водителя администратором или по сроку
+	AuditEventDriverUnblock = "driver_unblock"
+	// AuditEventDriverDeletion удаление водителя
+	AuditEventDriverDeletion = "driver_deletion"
+	// AuditEventDocumentVerification решение по документу водителя
+	AuditEventDocumentVerification = "document_verification"
+	// AuditEventDriverNote внутренняя заметка о водителе; details.note_id ссылается на заметку
+	AuditEventDriverNote = "driver_note"
+	// AuditEventProfileChange заявка на изменение юридически значимых полей профиля и решение
+	// по ней; details.change_id ссылается на заявку
+	AuditEventProfileChange = "profile_change"
+)
+
+// AuditRedacted значение персонального поля в снимках журнала: журнал неизменяем
+// и переживает удаление персональных данных, поэтому хранит только факт изменения
+const AuditRedacted = "[redacted]"
+
+// auditPersonalFields поля водителя, значения которых не попадают в снимки журнала
+var auditPersonalFields = map[string]bool{
+	"phone":           true,
+	"email":           true,
+	"first_name":      true,
+	"last_name":       true,
+	"middle_name":     true,
+	"birth_date":      true,
+	"passport_series": true,
+	"passport_number": true,
+	"license_number":  true,
+	"metadata":        true,
+}
+
+// AuditEntry запись журнала аудита водителя
+type AuditEntry struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// DriverID пустой, если изменение отклонено при регистрации и водитель не создан
+	DriverID  *uuid.UUID `json:"driver_id,omitempty" db:"driver_id"`
+	FleetID   *uuid.UUID `json:"fleet_id,omitempty" db:"fleet_id"`
+	Event     string     `json:"event" db:"event"`
+	Action    string     `json:"action" db:"action"`
+	Outcome   string     `json:"outcome" db:"outcome"`
+	Details   Metadata   `json:"details" db:"details"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+
+	// Actor субъект токена, выполнившего изменение, ActorRoles — его роли через запятую;
+	// пустые у внутренних вызовов без токена
+	Actor      *string `json:"actor,omitempty" db:"actor"`
+	ActorRoles *string `json:"actor_roles,omitempty" db:"actor_roles"`
+	// RequestID ID запроса (X-Request-ID), в котором сделано изменение
+	RequestID *string `json:"request_id,omitempty" db:"request_id"`
+	// Before и After значения измененных полей до и после изменения
+	Before Metadata `json:"before,omitempty" db:"before_snapshot"`
+	After  Metadata `json:"after,omitempty" db:"after_snapshot"`
+
+	// Sequence номер записи в цепочке хешей; пустой, если запись сделана без цепочки
+	Sequence *int64 `json:"sequence,omitempty" db:"sequence"`
+	// PrevHash хеш предыдущей записи цепочки, Hash — хеш этой записи (SHA-256 в hex)
+	PrevHash *string `json:"prev_hash,omitempty" db:"prev_hash"`
+	Hash     *string `json:"hash,omitempty" db:"hash"`
+}
+
+// NewAuditEntry создает запись журнала аудита
+func NewAuditEntry(event, action, outcome string) *AuditEntry {
+	return &AuditEntry{
+		ID:        uuid.New(),
+		Event:     event,
+		Action:    action,
+		Outcome:   outcome,
+		Details:   make(Metadata),
+		CreatedAt: time.Now(),
+	}
+}
+
+// AttachActor заполняет автора изменения и ID запроса из контекста
+func (e *AuditEntry) AttachActor(ctx context.Context) {
+	actor, ok := AuditActorFromContext(ctx)
+	if !ok {
+		return
+	}
+	if actor.Subject != "" {
+		e.Actor = &actor.Subject
+	}
+	if actor.Roles != "" {
+		e.ActorRoles = &actor.Roles
+	}
+	if actor.RequestID != "" {
+		e.RequestID = &actor.RequestID
+	}
+}
+
+// SetChanges сохраняет снимки полей, которые различаются в before и after. Значения
+// персональных полей заменяются на AuditRedacted
+func (e *AuditEntry) SetChanges(before, after interface{}) error {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return err
+	}
+
+	e.Before, e.After = make(Metadata), make(Metadata)
+	for key, value := range beforeFields {
+		if other, ok := afterFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			e.Before[key] = auditValue(key, value)
+		}
+	}
+	for key, value := range afterFields {
+		if other, ok := beforeFields[key]; !ok || !reflect.DeepEqual(value, other) {
+			e.After[key] = auditValue(key, value)
+		}
+	}
+	return nil
+}
+
+// auditFields представляет значение полями JSON; время изменения не сравнивается
+func auditFields(value interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "updated_at")
+	return fields, nil
+}
+
+// auditValue скрывает значение персонального поля
+func auditValue(key string, value interface{}) interface{} {
+	if auditPersonalFields[key] && value != nil {
+		return AuditRedacted
+	}
+	return value
+}
+
+// AuditFilters фильтры журнала аудита
+type AuditFilters struct {
+	DriverID *uuid.UUID `json:"driver_id,omitempty"`
+	FleetID  *uuid.UUID `json:"fleet_id,omitempty"`
+	Actor    *string    `json:"actor,omitempty"`
+	Event    *string    `json:"event,omitempty"`
+	Action   *string    `json:"action,omitempty"`
+	// RequestID все изменения одного запроса
+	RequestID *string    `json:"request_id,omitempty"`
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+}
+
+// AuditActor автор изменения: субъект и роли токена, ID запроса и, для водителя,
+// ID устройства, с которого пришел запрос
+type AuditActor struct {
+	Subject   string
+	Roles     string
+	RequestID string
+	DeviceID  string
+}
+
+// auditActorKey ключ контекста с автором изменения
+type auditActorKey struct{}
+
+// WithAuditActor добавляет в контекст автора изменения для журнала аудита
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditActorFromContext возвращает автора изменения из контекста
+func AuditActorFromContext(ctx context.Context) (AuditActor, bool) {
+	actor, ok := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor, ok
+}
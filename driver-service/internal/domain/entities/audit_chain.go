@@ -0,0 +1,217 @@
+package entities
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditChainGenesis PrevHash первой записи цепочки
+var AuditChainGenesis = strings.Repeat("0", sha256.Size*2)
+
+// Причины нарушения цепочки хешей
+const (
+	// AuditBreakSequenceGap пропущен номер: запись удалена
+	AuditBreakSequenceGap = "sequence_gap"
+	// AuditBreakPrevHash запись ссылается не на предыдущую: запись вставлена или цепочка пересобрана
+	AuditBreakPrevHash = "prev_hash_mismatch"
+	// AuditBreakHash хеш не совпадает с содержимым: запись изменена
+	AuditBreakHash = "hash_mismatch"
+	// AuditBreakAnchor хеш не совпадает с закрепленным во внешнем журнале: цепочка пересобрана
+	AuditBreakAnchor = "anchor_mismatch"
+)
+
+// auditChainContent содержимое записи, покрываемое хешем. Время хранится в UTC с точностью
+// до микросекунд — точностью TIMESTAMP WITH TIME ZONE, чтобы хеш не менялся после чтения из базы.
+// Автор и снимки пропускаются, если пусты: хеши записей, сделанных до их появления, не меняются
+type auditChainContent struct {
+	Sequence   int64      `json:"sequence"`
+	PrevHash   string     `json:"prev_hash"`
+	ID         uuid.UUID  `json:"id"`
+	DriverID   *uuid.UUID `json:"driver_id"`
+	FleetID    *uuid.UUID `json:"fleet_id"`
+	Event      string     `json:"event"`
+	Action     string     `json:"action"`
+	Outcome    string     `json:"outcome"`
+	Details    Metadata   `json:"details"`
+	CreatedAt  string     `json:"created_at"`
+	Actor      *string    `json:"actor,omitempty"`
+	ActorRoles *string    `json:"actor_roles,omitempty"`
+	RequestID  *string    `json:"request_id,omitempty"`
+	Before     Metadata   `json:"before,omitempty"`
+	After      Metadata   `json:"after,omitempty"`
+}
+
+// Seal добавляет запись в цепочку: назначает номер, ссылку на предыдущую запись и хеш
+func (e *AuditEntry) Seal(sequence int64, prevHash string) error {
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	e.Sequence = &sequence
+	e.PrevHash = &prevHash
+
+	hash, err := e.ChainHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = &hash
+	return nil
+}
+
+// ChainHash вычисляет хеш записи цепочки по ее содержимому
+func (e *AuditEntry) ChainHash() (string, error) {
+	if e.Sequence == nil || e.PrevHash == nil {
+		return "", fmt.Errorf("audit entry %s is not part of the hash chain", e.ID)
+	}
+
+	details := e.Details
+	if details == nil {
+		details = Metadata{}
+	}
+	content, err := json.Marshal(&auditChainContent{
+		Sequence:   *e.Sequence,
+		PrevHash:   *e.PrevHash,
+		ID:         e.ID,
+		DriverID:   e.DriverID,
+		FleetID:    e.FleetID,
+		Event:      e.Event,
+		Action:     e.Action,
+		Outcome:    e.Outcome,
+		Details:    details,
+		CreatedAt:  e.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+		Actor:      e.Actor,
+		ActorRoles: e.ActorRoles,
+		RequestID:  e.RequestID,
+		Before:     e.Before,
+		After:      e.After,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditAnchor хеш записи цепочки, закрепленный во внешнем журнале. Пересобрать цепочку
+// до закрепленной записи незаметно нельзя: хеш во внешнем журнале не совпадет
+type AuditAnchor struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	Sequence int64     `json:"sequence" db:"sequence"`
+	Hash     string    `json:"hash" db:"hash"`
+	// Sink внешний журнал, Reference — идентификатор записи в нем
+	Sink       string    `json:"sink" db:"sink"`
+	Reference  string    `json:"reference" db:"reference"`
+	AnchoredAt time.Time `json:"anchored_at" db:"anchored_at"`
+}
+
+// NewAuditAnchor создает закрепление записи цепочки
+func NewAuditAnchor(entry *AuditEntry, sink string) *AuditAnchor {
+	return &AuditAnchor{
+		ID:         uuid.New(),
+		Sequence:   *entry.Sequence,
+		Hash:       *entry.Hash,
+		Sink:       sink,
+		AnchoredAt: time.Now(),
+	}
+}
+
+// AuditChainBreak первое нарушение целостности цепочки
+type AuditChainBreak struct {
+	Sequence int64      `json:"sequence"`
+	EntryID  *uuid.UUID `json:"entry_id,omitempty"`
+	Reason   string     `json:"reason"`
+	Expected string     `json:"expected,omitempty"`
+	Actual   string     `json:"actual,omitempty"`
+}
+
+// AuditChainVerification результат проверки цепочки на отрезке номеров
+type AuditChainVerification struct {
+	From           int64            `json:"from"`
+	To             int64            `json:"to"`
+	Checked        int              `json:"checked"`
+	AnchorsChecked int              `json:"anchors_checked"`
+	Valid          bool             `json:"valid"`
+	Break          *AuditChainBreak `json:"break,omitempty"`
+	// LatestAnchor последнее закрепление; записи после него защищены только цепочкой
+	LatestAnchor *AuditAnchor `json:"latest_anchor,omitempty"`
+	VerifiedAt   time.Time    `json:"verified_at"`
+}
+
+// AuditChainVerifier проверяет записи цепочки, переданные по возрастанию номеров
+type AuditChainVerifier struct {
+	next     int64
+	prevHash string
+	checked  int
+}
+
+// NewAuditChainVerifier создает проверку, начинающуюся с записи from; prevHash — хеш
+// записи from-1 или AuditChainGenesis для начала цепочки
+func NewAuditChainVerifier(from int64, prevHash string) *AuditChainVerifier {
+	return &AuditChainVerifier{next: from, prevHash: prevHash}
+}
+
+// Check проверяет очередную запись и возвращает нарушение, если оно найдено
+func (v *AuditChainVerifier) Check(entry *AuditEntry) *AuditChainBreak {
+	entryID := entry.ID
+	if entry.Sequence == nil || *entry.Sequence != v.next {
+		return &AuditChainBreak{Sequence: v.next, Reason: AuditBreakSequenceGap}
+	}
+
+	if entry.PrevHash == nil || *entry.PrevHash != v.prevHash {
+		return &AuditChainBreak{
+			Sequence: v.next,
+			EntryID:  &entryID,
+			Reason:   AuditBreakPrevHash,
+			Expected: v.prevHash,
+			Actual:   stringValue(entry.PrevHash),
+		}
+	}
+
+	hash, err := entry.ChainHash()
+	if err != nil || entry.Hash == nil || *entry.Hash != hash {
+		return &AuditChainBreak{
+			Sequence: v.next,
+			EntryID:  &entryID,
+			Reason:   AuditBreakHash,
+			Expected: hash,
+			Actual:   stringValue(entry.Hash),
+		}
+	}
+
+	v.next++
+	v.prevHash = hash
+	v.checked++
+	return nil
+}
+
+// Checked количество проверенных записей
+func (v *AuditChainVerifier) Checked() int {
+	return v.checked
+}
+
+// CheckAnchor сверяет запись цепочки с закреплением во внешнем журнале
+func CheckAnchor(entry *AuditEntry, anchor *AuditAnchor) *AuditChainBreak {
+	if entry.Hash != nil && *entry.Hash == anchor.Hash {
+		return nil
+	}
+	entryID := entry.ID
+	return &AuditChainBreak{
+		Sequence: anchor.Sequence,
+		EntryID:  &entryID,
+		Reason:   AuditBreakAnchor,
+		Expected: anchor.Hash,
+		Actual:   stringValue(entry.Hash),
+	}
+}
+
+// stringValue возвращает значение строки или пустую строку
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
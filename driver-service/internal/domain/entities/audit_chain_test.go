@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestChain(t *testing.T, n int) []*AuditEntry {
+	chain := make([]*AuditEntry, n)
+	prevHash := AuditChainGenesis
+	for i := range chain {
+		entry := NewAuditEntry(AuditEventFleetValidation, "update", "approved")
+		entry.Details["attempt"] = i
+		entry.CreatedAt = time.Date(2024, 3, 1, 10, 0, i, 123456789, time.FixedZone("MSK", 3*3600))
+		require.NoError(t, entry.Seal(int64(i+1), prevHash))
+		prevHash = *entry.Hash
+		chain[i] = entry
+	}
+	return chain
+}
+
+func verifyChain(chain []*AuditEntry) *AuditChainBreak {
+	verifier := NewAuditChainVerifier(1, AuditChainGenesis)
+	for _, entry := range chain {
+		if chainBreak := verifier.Check(entry); chainBreak != nil {
+			return chainBreak
+		}
+	}
+	return nil
+}
+
+func TestAuditChainVerifier(t *testing.T) {
+	chain := newTestChain(t, 4)
+	assert.Nil(t, verifyChain(chain))
+	assert.Equal(t, AuditChainGenesis, *chain[0].PrevHash)
+	// Время приводится к точности базы, хеш не зависит от часового пояса
+	assert.Equal(t, time.UTC, chain[0].CreatedAt.Location())
+	assert.Zero(t, chain[0].CreatedAt.Nanosecond()%1000)
+
+	// Изменение содержимого записи
+	tampered := newTestChain(t, 4)
+	tampered[2].Outcome = "rejected"
+	chainBreak := verifyChain(tampered)
+	require.NotNil(t, chainBreak)
+	assert.Equal(t, AuditBreakHash, chainBreak.Reason)
+	assert.Equal(t, int64(3), chainBreak.Sequence)
+
+	// Удаление записи
+	chainBreak = verifyChain(append(append([]*AuditEntry{}, chain[:1]...), chain[2:]...))
+	require.NotNil(t, chainBreak)
+	assert.Equal(t, AuditBreakSequenceGap, chainBreak.Reason)
+	assert.Equal(t, int64(2), chainBreak.Sequence)
+
+	// Запись пересчитана с правильным хешем, но ссылка на предыдущую не совпадает
+	forged := newTestChain(t, 4)
+	forged[1].Outcome = "rejected"
+	require.NoError(t, forged[1].Seal(2, AuditChainGenesis))
+	chainBreak = verifyChain(forged)
+	require.NotNil(t, chainBreak)
+	assert.Equal(t, AuditBreakPrevHash, chainBreak.Reason)
+	assert.Equal(t, *forged[0].Hash, chainBreak.Expected)
+}
+
+func TestAuditChainHash_Actor(t *testing.T) {
+	newEntry := func() *AuditEntry {
+		entry := NewAuditEntry(AuditEventFleetValidation, "update", "approved")
+		entry.ID = uuid.MustParse("6f1c2a0e-3b7d-4c55-9a8e-1d2f3e4a5b6c")
+		entry.Details["attempt"] = 1
+		entry.CreatedAt = time.Date(2024, 3, 1, 10, 0, 1, 123456000, time.UTC)
+		return entry
+	}
+
+	// Хеш записи без автора и снимков совпадает с хешем до их появления
+	legacy := newEntry()
+	require.NoError(t, legacy.Seal(1, AuditChainGenesis))
+	assert.Equal(t, "369fafec89168da8a8cd05dfcc1057be0c05c75cf300f542e407e1b2b69be856", *legacy.Hash)
+
+	actor := "admin-1"
+	entry := newEntry()
+	entry.Actor = &actor
+	entry.After = Metadata{"status": "blocked"}
+	require.NoError(t, entry.Seal(1, AuditChainGenesis))
+	assert.NotEqual(t, *legacy.Hash, *entry.Hash)
+
+	// Подмена автора нарушает цепочку
+	other := "admin-2"
+	entry.Actor = &other
+	chainBreak := verifyChain([]*AuditEntry{entry})
+	require.NotNil(t, chainBreak)
+	assert.Equal(t, AuditBreakHash, chainBreak.Reason)
+}
+
+func TestCheckAnchor(t *testing.T) {
+	chain := newTestChain(t, 2)
+	anchor := NewAuditAnchor(chain[1], "file")
+	assert.Nil(t, CheckAnchor(chain[1], anchor))
+
+	// Цепочка пересобрана целиком: внутренние ссылки верны, но хеш не совпадает с закрепленным
+	rebuilt := newTestChain(t, 2)
+	rebuilt[0].Outcome = "rejected"
+	require.NoError(t, rebuilt[0].Seal(1, AuditChainGenesis))
+	require.NoError(t, rebuilt[1].Seal(2, *rebuilt[0].Hash))
+	assert.Nil(t, verifyChain(rebuilt))
+
+	chainBreak := CheckAnchor(rebuilt[1], anchor)
+	require.NotNil(t, chainBreak)
+	assert.Equal(t, AuditBreakAnchor, chainBreak.Reason)
+}
@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditEntry_SetChanges(t *testing.T) {
+	before := NewDriver("+79001234567", "old@example.com", "Иван", "Иванов", "LIC1")
+	after := *before
+	after.Email = "new@example.com"
+	after.Status = StatusBlocked
+	after.CurrentRating = 4.5
+
+	entry := NewAuditEntry(AuditEventDriverUpdate, "update", "completed")
+	require.NoError(t, entry.SetChanges(before, &after))
+
+	assert.Equal(t, Metadata{"email": AuditRedacted, "status": string(before.Status), "current_rating": 0.0}, entry.Before)
+	assert.Equal(t, Metadata{"email": AuditRedacted, "status": string(StatusBlocked), "current_rating": 4.5}, entry.After)
+
+	unchanged := NewAuditEntry(AuditEventDriverUpdate, "update", "completed")
+	require.NoError(t, unchanged.SetChanges(before, before))
+	assert.Empty(t, unchanged.Before)
+	assert.Empty(t, unchanged.After)
+}
+
+func TestAuditEntry_AttachActor(t *testing.T) {
+	entry := NewAuditEntry(AuditEventDriverStatus, "change_status", "completed")
+	entry.AttachActor(context.Background())
+	assert.Nil(t, entry.Actor)
+	assert.Nil(t, entry.RequestID)
+
+	ctx := WithAuditActor(context.Background(), AuditActor{Subject: "admin-1", Roles: "admin", RequestID: "req-1"})
+	entry.AttachActor(ctx)
+	require.NotNil(t, entry.Actor)
+	assert.Equal(t, "admin-1", *entry.Actor)
+	assert.Equal(t, "admin", *entry.ActorRoles)
+	assert.Equal(t, "req-1", *entry.RequestID)
+
+	// Запросы без токена несут только ID запроса
+	anonymous := NewAuditEntry(AuditEventDriverStatus, "change_status", "completed")
+	anonymous.AttachActor(WithAuditActor(context.Background(), AuditActor{RequestID: "req-2"}))
+	assert.Nil(t, anonymous.Actor)
+	assert.Nil(t, anonymous.ActorRoles)
+	assert.Equal(t, "req-2", *anonymous.RequestID)
+}
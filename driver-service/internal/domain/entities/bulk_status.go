@@ -0,0 +1,126 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkStatusDrivers максимальное число водителей в одной массовой смене статуса
+const MaxBulkStatusDrivers = 1000
+
+// BulkStatusOutcome итог массовой смены статуса для одного водителя
+type BulkStatusOutcome string
+
+const (
+	// BulkStatusUpdated статус водителя изменен
+	BulkStatusUpdated BulkStatusOutcome = "updated"
+	// BulkStatusSkipped водитель пропущен: уже в целевом статусе или атомарная операция отменена
+	BulkStatusSkipped BulkStatusOutcome = "skipped"
+	// BulkStatusError переход недопустим или водитель не найден
+	BulkStatusError BulkStatusOutcome = "error"
+)
+
+// Коды причин пропуска и ошибок в результатах массовой смены статуса
+const (
+	BulkStatusCodeUnchanged         = "STATUS_UNCHANGED"
+	BulkStatusCodeAborted           = "ABORTED"
+	BulkStatusCodeNotFound          = "DRIVER_NOT_FOUND"
+	BulkStatusCodeInvalidTransition = "INVALID_STATUS_TRANSITION"
+	BulkStatusCodeProfileIncomplete = "PROFILE_INCOMPLETE"
+	BulkStatusCodeConflict          = "STATUS_CONFLICT"
+	BulkStatusCodeFailed            = "UPDATE_FAILED"
+)
+
+// BulkStatusRequest массовая смена статуса водителей для обслуживания автопарка
+type BulkStatusRequest struct {
+	DriverIDs []uuid.UUID `json:"driver_ids"`
+	Status    Status      `json:"status"`
+	// Atomic все или ничего: при любой ошибке ни один статус не меняется. Иначе водители
+	// обрабатываются пачками и ошибка одного не мешает остальным
+	Atomic bool `json:"atomic"`
+	// Reason причина операции для журнала аудита
+	Reason string `json:"reason,omitempty"`
+	// ActorID идентификатор администратора, запустившего операцию
+	ActorID string `json:"-"`
+}
+
+// Validate проверяет список водителей, статус, автора и причину; повторы водителей удаляются.
+// Перевод в blocked массовой операцией недоступен: у блокировки должен быть код причины
+func (r *BulkStatusRequest) Validate() error {
+	r.Reason = strings.TrimSpace(r.Reason)
+	r.ActorID = strings.TrimSpace(r.ActorID)
+
+	if r.Status == StatusBlocked {
+		return ErrBlockRequiresReason
+	}
+	if !r.Status.IsValid() || r.ActorID == "" {
+		return ErrInvalidBulkStatus
+	}
+	if utf8.RuneCountInString(r.Reason) > MaxStatusOverrideReasonLength {
+		return ErrInvalidBulkStatus
+	}
+
+	seen := make(map[uuid.UUID]bool, len(r.DriverIDs))
+	unique := make([]uuid.UUID, 0, len(r.DriverIDs))
+	for _, id := range r.DriverIDs {
+		if id == uuid.Nil {
+			return ErrInvalidBulkStatus
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 || len(unique) > MaxBulkStatusDrivers {
+		return ErrInvalidBulkStatus
+	}
+	r.DriverIDs = unique
+	return nil
+}
+
+// StatusUpdate смена статуса водителя, которая применяется, только если его статус
+// по-прежнему равен From
+type StatusUpdate struct {
+	DriverID uuid.UUID
+	From     Status
+	To       Status
+}
+
+// BulkStatusResult итог массовой смены статуса для одного водителя
+type BulkStatusResult struct {
+	DriverID       uuid.UUID         `json:"driver_id"`
+	Result         BulkStatusOutcome `json:"result"`
+	PreviousStatus Status            `json:"previous_status,omitempty"`
+	Code           string            `json:"code,omitempty"`
+	Message        string            `json:"message,omitempty"`
+}
+
+// BulkStatusReport результат массовой смены статуса в порядке водителей из запроса
+type BulkStatusReport struct {
+	// OperationID идентификатор операции; записывается в журнал аудита каждого водителя
+	OperationID uuid.UUID           `json:"operation_id"`
+	Status      Status              `json:"status"`
+	Atomic      bool                `json:"atomic"`
+	Updated     int                 `json:"updated"`
+	Skipped     int                 `json:"skipped"`
+	Failed      int                 `json:"failed"`
+	Results     []*BulkStatusResult `json:"results"`
+	StartedAt   time.Time           `json:"started_at"`
+	CompletedAt time.Time           `json:"completed_at"`
+}
+
+// Add добавляет результат по водителю и обновляет счетчики
+func (r *BulkStatusReport) Add(result *BulkStatusResult) {
+	r.Results = append(r.Results, result)
+	switch result.Result {
+	case BulkStatusUpdated:
+		r.Updated++
+	case BulkStatusSkipped:
+		r.Skipped++
+	case BulkStatusError:
+		r.Failed++
+	}
+}
@@ -0,0 +1,204 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// EndpointUsage нагрузка на эндпоинт и стоимость его обработки за период
+type EndpointUsage struct {
+	// Route метод и шаблон пути, например "GET /api/v1/drivers/:id"
+	Route        string  `json:"route"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+	// BusySeconds суммарное время обработки запросов — основная оценка стоимости эндпоинта
+	BusySeconds float64 `json:"busy_seconds"`
+	BytesOut    int64   `json:"bytes_out"`
+	// PeakRPS средняя частота запросов в самую нагруженную минуту периода
+	PeakRPS float64 `json:"peak_rps"`
+	// LimitRPS допустимая частота запросов из конфигурации; 0 — лимит не задан
+	LimitRPS    float64 `json:"limit_rps,omitempty"`
+	Utilization float64 `json:"utilization,omitempty"`
+}
+
+// EndpointUsageList нагрузка по эндпоинтам в формате JSON
+type EndpointUsageList []EndpointUsage
+
+// Value реализует интерфейс driver.Valuer для сериализации в БД
+func (l EndpointUsageList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует интерфейс sql.Scanner для десериализации из БД
+func (l *EndpointUsageList) Scan(value interface{}) error {
+	if value == nil {
+		*l = EndpointUsageList{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EndpointUsageList", value)
+	}
+
+	return json.Unmarshal(bytes, l)
+}
+
+// PoolSample снимок пула соединений с базой данных
+type PoolSample struct {
+	InUse        int
+	MaxOpen      int
+	WaitCount    int64
+	WaitDuration time.Duration
+}
+
+// PoolUsage загрузка пула соединений за период. Насыщенность — доля занятых соединений
+// от максимально допустимого числа
+type PoolUsage struct {
+	MaxOpenConnections int     `json:"max_open_connections"`
+	Samples            int     `json:"samples"`
+	AvgInUse           float64 `json:"avg_in_use"`
+	PeakInUse          int     `json:"peak_in_use"`
+	AvgSaturation      float64 `json:"avg_saturation"`
+	PeakSaturation     float64 `json:"peak_saturation"`
+	WaitCount          int64   `json:"wait_count"`
+	WaitDurationMs     int64   `json:"wait_duration_ms"`
+}
+
+// Value реализует интерфейс driver.Valuer для сериализации в БД
+func (p PoolUsage) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+// Scan реализует интерфейс sql.Scanner для десериализации из БД
+func (p *PoolUsage) Scan(value interface{}) error {
+	if value == nil {
+		*p = PoolUsage{}
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into PoolUsage", value)
+	}
+
+	return json.Unmarshal(bytes, p)
+}
+
+// CapacityUsage нагрузка, накопленная экземпляром сервиса с начала периода
+type CapacityUsage struct {
+	From      time.Time
+	To        time.Time
+	Endpoints []EndpointUsage
+	Pool      PoolUsage
+}
+
+// CapacityReport суточный отчет о нагрузке одного экземпляра сервиса
+type CapacityReport struct {
+	Date          time.Time         `json:"date" db:"report_date"`
+	Instance      string            `json:"instance" db:"instance"`
+	PeriodFrom    time.Time         `json:"period_from" db:"period_from"`
+	PeriodTo      time.Time         `json:"period_to" db:"period_to"`
+	TotalRequests int64             `json:"total_requests" db:"total_requests"`
+	Endpoints     EndpointUsageList `json:"endpoints" db:"endpoints"`
+	Pool          PoolUsage         `json:"pool" db:"pool"`
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
+
+// EndpointForecast прогноз нагрузки на эндпоинт
+type EndpointForecast struct {
+	Route          string  `json:"route"`
+	LimitRPS       float64 `json:"limit_rps,omitempty"`
+	CurrentPeakRPS float64 `json:"current_peak_rps"`
+	// TrendPerDay изменение пиковой частоты запросов за сутки по линейной регрессии
+	TrendPerDay          float64 `json:"trend_per_day"`
+	ProjectedPeakRPS     float64 `json:"projected_peak_rps"`
+	ProjectedUtilization float64 `json:"projected_utilization,omitempty"`
+	// DaysUntilLimit через сколько дней пик достигнет лимита; не заполняется, если рост не ожидается
+	DaysUntilLimit *int `json:"days_until_limit,omitempty"`
+}
+
+// PoolForecast прогноз насыщенности пула соединений
+type PoolForecast struct {
+	MaxOpenConnections      int     `json:"max_open_connections"`
+	CurrentPeakSaturation   float64 `json:"current_peak_saturation"`
+	TrendPerDay             float64 `json:"trend_per_day"`
+	ProjectedPeakSaturation float64 `json:"projected_peak_saturation"`
+	DaysUntilSaturation     *int    `json:"days_until_saturation,omitempty"`
+	CurrentWaitCount        int64   `json:"current_wait_count"`
+}
+
+// CapacityForecast прогноз нагрузки на горизонт планирования по последним суточным отчетам
+type CapacityForecast struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	// Days число суток с отчетами, по которым построен прогноз
+	Days        int                `json:"days"`
+	HorizonDays int                `json:"horizon_days"`
+	Endpoints   []EndpointForecast `json:"endpoints"`
+	Pool        PoolForecast       `json:"pool"`
+}
+
+// LinearTrend строит линейную регрессию значений y в моменты x (в сутках) методом
+// наименьших квадратов и возвращает наклон и значение прямой в последней точке
+func LinearTrend(x, y []float64) (slope, last float64) {
+	switch len(y) {
+	case 0:
+		return 0, 0
+	case 1:
+		return 0, y[0]
+	}
+
+	n := float64(len(y))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range y {
+		sumX += x[i]
+		sumY += y[i]
+		sumXY += x[i] * y[i]
+		sumXX += x[i] * x[i]
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0, sumY / n
+	}
+	slope = (n*sumXY - sumX*sumY) / denominator
+	intercept := (sumY - slope*sumX) / n
+	return slope, intercept + slope*x[len(x)-1]
+}
+
+// DaysUntil возвращает, через сколько суток значение current с приростом slope в сутки
+// достигнет limit. nil означает, что при текущем тренде лимит не будет достигнут
+func DaysUntil(current, slope, limit float64) *int {
+	if limit <= 0 {
+		return nil
+	}
+
+	days := 0
+	if current < limit {
+		if slope <= 0 {
+			return nil
+		}
+		days = int(math.Ceil((limit - current) / slope))
+	}
+	return &days
+}
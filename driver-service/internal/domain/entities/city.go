@@ -0,0 +1,179 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Ограничения городов и регионов
+const (
+	// MaxCityNameLength максимальная длина названия города или региона в символах
+	MaxCityNameLength = 100
+	// MaxCityKeyLength максимальная длина ключа города, как у ключа шарда водителя
+	MaxCityKeyLength = 64
+	// MaxRegionCodeLength максимальная длина кода региона
+	MaxRegionCodeLength = 16
+	// MaxCityBoundaryVertices максимальное число вершин границы города
+	MaxCityBoundaryVertices = 5000
+)
+
+// Region регион (страна, область), объединяющий города
+type Region struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// Code уникальный код региона в верхнем регистре, например "RU-MOW"
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// RegionRequest запрос на создание региона
+type RegionRequest struct {
+	Code string `json:"code" binding:"required"`
+	Name string `json:"name" binding:"required"`
+}
+
+// NewRegion создает регион из запроса
+func NewRegion(req *RegionRequest) *Region {
+	now := time.Now()
+	return &Region{
+		ID:        uuid.New(),
+		Code:      strings.ToUpper(strings.TrimSpace(req.Code)),
+		Name:      strings.TrimSpace(req.Name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Validate проверяет код и название региона
+func (r *Region) Validate() error {
+	if r.Code == "" || len(r.Code) > MaxRegionCodeLength {
+		return ErrInvalidRegion
+	}
+	if r.Name == "" || utf8.RuneCountInString(r.Name) > MaxCityNameLength {
+		return ErrInvalidRegion
+	}
+	return nil
+}
+
+// City город региона с границей. Точки водителей относятся к городу, в границы которого
+// попадают; при пересечении границ выбирается город, созданный раньше
+type City struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	RegionID uuid.UUID `json:"region_id" db:"region_id"`
+	// Key уникальный ключ города в виде NormalizeCity, как город в метаданных водителя и
+	// в маршрутизации шардов. Не меняется после создания
+	Key       string       `json:"key" db:"key"`
+	Name      string       `json:"name" db:"name"`
+	Boundary  GeoPointList `json:"boundary" db:"boundary"`
+	Active    bool         `json:"active" db:"active"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// CityRequest запрос на создание или замену города. Без key ключ строится из названия;
+// без active город активен
+type CityRequest struct {
+	RegionID uuid.UUID  `json:"region_id" binding:"required"`
+	Key      string     `json:"key"`
+	Name     string     `json:"name" binding:"required"`
+	Boundary []GeoPoint `json:"boundary" binding:"required"`
+	Active   *bool      `json:"active"`
+}
+
+// Apply переносит поля запроса в город; ключ существующего города не меняется
+func (r *CityRequest) Apply(city *City) {
+	city.RegionID = r.RegionID
+	city.Name = strings.TrimSpace(r.Name)
+	if city.Key == "" {
+		city.Key = NormalizeCity(r.Key)
+		if city.Key == "" {
+			city.Key = NormalizeCity(r.Name)
+		}
+	}
+	city.Boundary = append(GeoPointList(nil), r.Boundary...)
+	city.Active = r.Active == nil || *r.Active
+}
+
+// NewCity создает город из запроса
+func NewCity(req *CityRequest) *City {
+	now := time.Now()
+	city := &City{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(city)
+	return city
+}
+
+// Validate проверяет ключ, название и границу города
+func (c *City) Validate() error {
+	if c.RegionID == uuid.Nil {
+		return ErrInvalidCity
+	}
+	if c.Key == "" || len(c.Key) > MaxCityKeyLength {
+		return ErrInvalidCity
+	}
+	if c.Name == "" || utf8.RuneCountInString(c.Name) > MaxCityNameLength {
+		return ErrInvalidCity
+	}
+	if !c.Boundary.isValidPolygon(MaxCityBoundaryVertices) {
+		return ErrInvalidCity
+	}
+	return nil
+}
+
+// Contains проверяет, находится ли точка в границах города
+func (c *City) Contains(latitude, longitude float64) bool {
+	return c.Boundary.PolygonContains(latitude, longitude)
+}
+
+// CityFilters фильтр списка городов
+type CityFilters struct {
+	RegionID *uuid.UUID
+	Active   *bool
+	Limit    int
+	Offset   int
+}
+
+// OperatingCity возвращает ключ города, в котором работает водитель: определенный по его
+// местоположениям, а до первого определения — город из метаданных. Пустая строка, если
+// город неизвестен
+func (d *Driver) OperatingCity() string {
+	if d.ResolvedCity != nil && *d.ResolvedCity != "" {
+		return *d.ResolvedCity
+	}
+	return d.ShardKey
+}
+
+// CityDriverCount число активных водителей города по статусам для планирования мощностей
+type CityDriverCount struct {
+	// City ключ города (см. Driver.OperatingCity); nil — водители без города
+	City *string `json:"city" db:"city"`
+	// Name и RegionID заполняются для городов из справочника
+	Name      *string    `json:"name,omitempty" db:"-"`
+	RegionID  *uuid.UUID `json:"region_id,omitempty" db:"-"`
+	Available int        `json:"available" db:"available"`
+	OnShift   int        `json:"on_shift" db:"on_shift"`
+	Busy      int        `json:"busy" db:"busy"`
+	Total     int        `json:"total" db:"total"`
+}
+
+// Add учитывает активного водителя в статусе status
+func (c *CityDriverCount) Add(status Status) {
+	switch status {
+	case StatusAvailable:
+		c.Available++
+	case StatusOnShift:
+		c.OnShift++
+	case StatusBusy:
+		c.Busy++
+	default:
+		return
+	}
+	c.Total++
+}
@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DevicePlatform платформа устройства водителя
+type DevicePlatform string
+
+const (
+	DevicePlatformAndroid DevicePlatform = "android"
+	DevicePlatformIOS     DevicePlatform = "ios"
+	DevicePlatformWeb     DevicePlatform = "web"
+	// DevicePlatformTracker GPS-трекер автомобиля, который присылает точки по MQTT;
+	// ID устройства — ID трекера в топике
+	DevicePlatformTracker DevicePlatform = "tracker"
+)
+
+// IsValid проверяет, известна ли платформа
+func (p DevicePlatform) IsValid() bool {
+	switch p {
+	case DevicePlatformAndroid, DevicePlatformIOS, DevicePlatformWeb, DevicePlatformTracker:
+		return true
+	}
+	return false
+}
+
+// Ограничения длины полей устройства
+const (
+	MaxDeviceIDLength   = 128
+	MaxAppVersionLength = 32
+	MaxPushTokenLength  = 4096
+)
+
+// DriverDevice устройство, с которого водитель работает в приложении. Устройство
+// определяется ID, который приложение передает в утверждении device_id токена или в
+// заголовке X-Device-ID
+type DriverDevice struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	DriverID   uuid.UUID      `json:"driver_id" db:"driver_id"`
+	DeviceID   string         `json:"device_id" db:"device_id"`
+	Platform   DevicePlatform `json:"platform" db:"platform"`
+	AppVersion string         `json:"app_version" db:"app_version"`
+	// PushToken токен push-уведомлений; у отозванного устройства удаляется
+	PushToken    *string    `json:"-" db:"push_token"`
+	RegisteredAt time.Time  `json:"registered_at" db:"registered_at"`
+	LastSeenAt   time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsRevoked проверяет, отозвано ли устройство
+func (d *DriverDevice) IsRevoked() bool {
+	return d.RevokedAt != nil
+}
+
+// IsTracker проверяет, является ли устройство GPS-трекером
+func (d *DriverDevice) IsTracker() bool {
+	return d.Platform == DevicePlatformTracker
+}
+
+// HasPushToken проверяет, может ли устройство получать push-уведомления
+func (d *DriverDevice) HasPushToken() bool {
+	return d.PushToken != nil && *d.PushToken != ""
+}
+
+// Revoke отзывает устройство и удаляет его токен push-уведомлений
+func (d *DriverDevice) Revoke(at time.Time) {
+	d.RevokedAt = &at
+	d.PushToken = nil
+}
+
+// DriverDeviceResponse устройство водителя в ответе API: токен push-уведомлений не раскрывается
+type DriverDeviceResponse struct {
+	ID           uuid.UUID      `json:"id"`
+	DriverID     uuid.UUID      `json:"driver_id"`
+	DeviceID     string         `json:"device_id"`
+	Platform     DevicePlatform `json:"platform"`
+	AppVersion   string         `json:"app_version"`
+	PushEnabled  bool           `json:"push_enabled"`
+	RegisteredAt time.Time      `json:"registered_at"`
+	LastSeenAt   time.Time      `json:"last_seen_at"`
+	RevokedAt    *time.Time     `json:"revoked_at,omitempty"`
+}
+
+// ToResponse преобразует устройство в ответ API
+func (d *DriverDevice) ToResponse() *DriverDeviceResponse {
+	return &DriverDeviceResponse{
+		ID:           d.ID,
+		DriverID:     d.DriverID,
+		DeviceID:     d.DeviceID,
+		Platform:     d.Platform,
+		AppVersion:   d.AppVersion,
+		PushEnabled:  d.HasPushToken(),
+		RegisteredAt: d.RegisteredAt,
+		LastSeenAt:   d.LastSeenAt,
+		RevokedAt:    d.RevokedAt,
+	}
+}
+
+// DeviceRegistration данные устройства, которые присылает приложение при входе и обновлении
+type DeviceRegistration struct {
+	DeviceID   string         `json:"device_id" binding:"required"`
+	Platform   DevicePlatform `json:"platform" binding:"required"`
+	AppVersion string         `json:"app_version" binding:"required"`
+	PushToken  *string        `json:"push_token,omitempty"`
+}
+
+// Validate проверяет и нормализует данные устройства
+func (r *DeviceRegistration) Validate() error {
+	r.DeviceID = strings.TrimSpace(r.DeviceID)
+	r.AppVersion = strings.TrimSpace(r.AppVersion)
+	if r.DeviceID == "" || len(r.DeviceID) > MaxDeviceIDLength {
+		return ErrInvalidDevice
+	}
+	if !r.Platform.IsValid() {
+		return ErrInvalidDevice
+	}
+	if r.AppVersion == "" || len(r.AppVersion) > MaxAppVersionLength {
+		return ErrInvalidDevice
+	}
+	if r.PushToken != nil {
+		token := strings.TrimSpace(*r.PushToken)
+		if len(token) > MaxPushTokenLength {
+			return ErrInvalidDevice
+		}
+		r.PushToken = &token
+	}
+	return nil
+}
+
+// NewDriverDevice создает устройство водителя по данным регистрации
+func NewDriverDevice(driverID uuid.UUID, registration *DeviceRegistration, at time.Time) *DriverDevice {
+	return &DriverDevice{
+		ID:           uuid.New(),
+		DriverID:     driverID,
+		DeviceID:     registration.DeviceID,
+		Platform:     registration.Platform,
+		AppVersion:   registration.AppVersion,
+		PushToken:    registration.PushToken,
+		RegisteredAt: at,
+		LastSeenAt:   at,
+	}
+}
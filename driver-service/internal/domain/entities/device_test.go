@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceRegistration_Validate(t *testing.T) {
+	token := " push-token "
+	registration := &DeviceRegistration{DeviceID: " phone-1 ", Platform: DevicePlatformIOS, AppVersion: "3.2.1 ", PushToken: &token}
+	require.NoError(t, registration.Validate())
+	assert.Equal(t, "phone-1", registration.DeviceID)
+	assert.Equal(t, "3.2.1", registration.AppVersion)
+	assert.Equal(t, "push-token", *registration.PushToken)
+
+	tests := []struct {
+		name         string
+		registration DeviceRegistration
+	}{
+		{"empty device_id", DeviceRegistration{DeviceID: " ", Platform: DevicePlatformAndroid, AppVersion: "1.0"}},
+		{"long device_id", DeviceRegistration{DeviceID: strings.Repeat("d", MaxDeviceIDLength+1), Platform: DevicePlatformAndroid, AppVersion: "1.0"}},
+		{"unknown platform", DeviceRegistration{DeviceID: "phone-1", Platform: "symbian", AppVersion: "1.0"}},
+		{"empty app_version", DeviceRegistration{DeviceID: "phone-1", Platform: DevicePlatformWeb}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.registration.Validate(), ErrInvalidDevice)
+		})
+	}
+}
+
+func TestDriverDevice_Revoke(t *testing.T) {
+	token := "push-token"
+	device := NewDriverDevice(uuid.New(), &DeviceRegistration{
+		DeviceID: "phone-1", Platform: DevicePlatformAndroid, AppVersion: "1.0", PushToken: &token,
+	}, time.Now())
+	assert.True(t, device.ToResponse().PushEnabled)
+
+	device.Revoke(time.Now())
+	assert.True(t, device.IsRevoked())
+	assert.False(t, device.HasPushToken())
+	assert.NotNil(t, device.ToResponse().RevokedAt)
+}
@@ -0,0 +1,202 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxDriverRating максимальный рейтинг водителя
+const maxDriverRating = 5.0
+
+// DispatchOffer ответ водителя на предложение заказа. Одно предложение заказа водителю
+// учитывается один раз
+type DispatchOffer struct {
+	DriverID    uuid.UUID `json:"driver_id" db:"driver_id"`
+	OrderID     uuid.UUID `json:"order_id" db:"order_id"`
+	Accepted    bool      `json:"accepted" db:"accepted"`
+	RespondedAt time.Time `json:"responded_at" db:"responded_at"`
+	// CancelledAt когда водитель отменил принятый заказ
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
+}
+
+// DispatchOfferRequest запрос сервиса заказов на учет ответа водителя
+type DispatchOfferRequest struct {
+	DriverID uuid.UUID `json:"driver_id" binding:"required"`
+	OrderID  uuid.UUID `json:"order_id" binding:"required"`
+	Accepted *bool     `json:"accepted" binding:"required"`
+	// RespondedAt время ответа; по умолчанию — время запроса
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
+
+// DispatchCancellationRequest запрос сервиса заказов на учет отмены принятого заказа водителем.
+// Отмены клиентом или диспетчером не сообщаются: они не влияют на долю отмен водителя
+type DispatchCancellationRequest struct {
+	DriverID uuid.UUID `json:"driver_id" binding:"required"`
+	OrderID  uuid.UUID `json:"order_id" binding:"required"`
+	// CancelledAt время отмены; по умолчанию — время запроса
+	CancelledAt *time.Time `json:"cancelled_at,omitempty"`
+}
+
+// Validate проверяет ответ водителя
+func (o *DispatchOffer) Validate() error {
+	if o.DriverID == uuid.Nil || o.OrderID == uuid.Nil {
+		return ErrInvalidDispatchOffer
+	}
+	if o.RespondedAt.IsZero() {
+		return ErrInvalidTimestamp
+	}
+	return nil
+}
+
+// DispatchStats статистика предложений водителю за окно учета. Отмены относятся к окну
+// по времени ответа на предложение
+type DispatchStats struct {
+	DriverID        uuid.UUID  `json:"driver_id" db:"driver_id"`
+	OffersReceived  int        `json:"offers_received" db:"offers_received"`
+	OffersAccepted  int        `json:"offers_accepted" db:"offers_accepted"`
+	OffersCancelled int        `json:"offers_cancelled" db:"offers_cancelled"`
+	LastAcceptedAt  *time.Time `json:"last_accepted_at,omitempty" db:"last_accepted_at"`
+}
+
+// AcceptanceRate доля принятых предложений. Пока предложений меньше minOffers, статистика
+// недостоверна и водитель не штрафуется: возвращается 1
+func (s *DispatchStats) AcceptanceRate(minOffers int) float64 {
+	if s == nil || s.OffersReceived == 0 || s.OffersReceived < minOffers {
+		return 1
+	}
+	return float64(s.OffersAccepted) / float64(s.OffersReceived)
+}
+
+// CancellationRate доля отмененных водителем среди принятых предложений. Пока предложений
+// меньше minOffers, водитель не штрафуется: возвращается 0
+func (s *DispatchStats) CancellationRate(minOffers int) float64 {
+	if s == nil || s.OffersAccepted == 0 || s.OffersReceived < minOffers {
+		return 0
+	}
+	return float64(s.OffersCancelled) / float64(s.OffersAccepted)
+}
+
+// DispatchRates доли принятых и отмененных предложений водителя за скользящее окно
+type DispatchRates struct {
+	// WindowDays длина окна: учитываются ответы за последние WindowDays суток
+	WindowDays      int `json:"window_days"`
+	OffersReceived  int `json:"offers_received"`
+	OffersAccepted  int `json:"offers_accepted"`
+	OffersCancelled int `json:"offers_cancelled"`
+	// AcceptanceRate доля принятых; нет, если предложений за окно не было
+	AcceptanceRate *float64 `json:"acceptance_rate,omitempty"`
+	// CancellationRate доля отмененных среди принятых; нет, если принятых не было
+	CancellationRate *float64 `json:"cancellation_rate,omitempty"`
+}
+
+// NewDispatchRates сводит статистику водителя за окно в windowDays суток; stats может быть nil,
+// если ответов за окно нет
+func NewDispatchRates(windowDays int, stats *DispatchStats) *DispatchRates {
+	rates := &DispatchRates{WindowDays: windowDays}
+	if stats == nil {
+		return rates
+	}
+	rates.OffersReceived = stats.OffersReceived
+	rates.OffersAccepted = stats.OffersAccepted
+	rates.OffersCancelled = stats.OffersCancelled
+	if stats.OffersReceived > 0 {
+		acceptance := stats.AcceptanceRate(0)
+		rates.AcceptanceRate = &acceptance
+	}
+	if stats.OffersAccepted > 0 {
+		cancellation := stats.CancellationRate(0)
+		rates.CancellationRate = &cancellation
+	}
+	return rates
+}
+
+// DispatchWeights веса составляющих оценки кандидата. Веса задают относительную важность
+// и не обязаны давать в сумме 1
+type DispatchWeights struct {
+	Distance     float64 `json:"distance"`
+	Rating       float64 `json:"rating"`
+	Acceptance   float64 `json:"acceptance"`
+	Idle         float64 `json:"idle"`
+	Cancellation float64 `json:"cancellation"`
+}
+
+// total сумма весов
+func (w DispatchWeights) total() float64 {
+	return w.Distance + w.Rating + w.Acceptance + w.Idle + w.Cancellation
+}
+
+// DispatchCandidateQuery поиск кандидатов для заказа
+type DispatchCandidateQuery struct {
+	Latitude  float64
+	Longitude float64
+	// RadiusKm радиус поиска; 0 — радиус по умолчанию
+	RadiusKm float64
+	Limit    int
+	// Tags метки, которые должны быть у кандидата (в нормальной форме)
+	Tags []string
+}
+
+// Validate проверяет точку подачи и радиус
+func (q *DispatchCandidateQuery) Validate() error {
+	center := DriverLocation{Latitude: q.Latitude, Longitude: q.Longitude}
+	if !center.IsValidLocation() || q.RadiusKm < 0 {
+		return ErrInvalidLocation
+	}
+	return nil
+}
+
+// DispatchCandidate водитель поблизости с оценкой для назначения заказа
+type DispatchCandidate struct {
+	DriverID       uuid.UUID `json:"driver_id"`
+	Latitude       float64   `json:"latitude"`
+	Longitude      float64   `json:"longitude"`
+	DistanceKm     float64   `json:"distance_km"`
+	Rating         float64   `json:"rating"`
+	AcceptanceRate float64   `json:"acceptance_rate"`
+	// CancellationRate доля принятых заказов, отмененных водителем
+	CancellationRate float64 `json:"cancellation_rate"`
+	// IdleSeconds сколько водитель ждет заказа: с последнего принятого предложения
+	// или начала смены; 0, если неизвестно
+	IdleSeconds int64     `json:"idle_seconds"`
+	Score       float64   `json:"score"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// DispatchScoring параметры нормализации составляющих оценки
+type DispatchScoring struct {
+	Weights  DispatchWeights
+	RadiusKm float64
+	// MaxIdle время простоя, после которого составляющая простоя максимальна
+	MaxIdle time.Duration
+}
+
+// Score вычисляет оценку кандидата от 0 до 1: каждая составляющая приводится к [0, 1]
+// (ближе, выше рейтинг, чаще принимает, дольше ждет, реже отменяет — лучше) и взвешивается
+func (p DispatchScoring) Score(candidate *DispatchCandidate) float64 {
+	distance := 0.0
+	if p.RadiusKm > 0 {
+		distance = 1 - clamp01(candidate.DistanceKm/p.RadiusKm)
+	}
+	idle := 0.0
+	if p.MaxIdle > 0 {
+		idle = clamp01(float64(candidate.IdleSeconds) / p.MaxIdle.Seconds())
+	}
+
+	total := p.Weights.total()
+	if total == 0 {
+		return 0
+	}
+	score := p.Weights.Distance*distance +
+		p.Weights.Rating*clamp01(candidate.Rating/maxDriverRating) +
+		p.Weights.Acceptance*clamp01(candidate.AcceptanceRate) +
+		p.Weights.Idle*idle +
+		p.Weights.Cancellation*(1-clamp01(candidate.CancellationRate))
+	return score / total
+}
+
+// clamp01 ограничивает значение отрезком [0, 1]
+func clamp01(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
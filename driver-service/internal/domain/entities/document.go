@@ -28,6 +28,11 @@ const (
 	VerificationStatusRejected  VerificationStatus = "rejected"
 	VerificationStatusExpired   VerificationStatus = "expired"
 	VerificationStatusProcessing VerificationStatus = "processing"
+	// VerificationStatusSuperseded документ заменен подтвержденной новой версией
+	VerificationStatusSuperseded VerificationStatus = "superseded"
+	// VerificationStatusReverificationRequired подтвержденный документ нужно подтвердить заново
+	// до срока кампании перепроверки (VerifyBy); до срока документ остается действующим
+	VerificationStatusReverificationRequired VerificationStatus = "reverification_required"
 )
 
 // DriverDocument представляет документ водителя
@@ -46,6 +51,29 @@ type DriverDocument struct {
 	Metadata       Metadata           `json:"metadata" db:"metadata"`
 	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time          `json:"updated_at" db:"updated_at"`
+
+	// Захват документа верификатором в очереди проверки
+	ClaimedBy      *string    `json:"claimed_by,omitempty" db:"claimed_by"`
+	ClaimedAt      *time.Time `json:"claimed_at,omitempty" db:"claimed_at"`
+	ClaimExpiresAt *time.Time `json:"claim_expires_at,omitempty" db:"claim_expires_at"`
+
+	// Продление: новая версия ссылается на заменяемый документ и должна быть
+	// проверена до его истечения
+	ReplacesID           *uuid.UUID `json:"replaces_id,omitempty" db:"replaces_id"`
+	VerifyBy             *time.Time `json:"verify_by,omitempty" db:"verify_by"`
+	ExpiryReminderSentAt *time.Time `json:"expiry_reminder_sent_at,omitempty" db:"expiry_reminder_sent_at"`
+	// IsCurrent действующая версия документа своего типа; у водителя одна текущая версия
+	// каждого типа, остальные — история (замененные и не подтвержденные продления)
+	IsCurrent bool `json:"is_current" db:"is_current"`
+
+	// Повторная загрузка после отказа: прежние версии хранятся в document_versions
+	ResubmissionCount          int        `json:"resubmission_count" db:"resubmission_count"`
+	ResubmittedAt              *time.Time `json:"resubmitted_at,omitempty" db:"resubmitted_at"`
+	ResubmissionReminderSentAt *time.Time `json:"resubmission_reminder_sent_at,omitempty" db:"resubmission_reminder_sent_at"`
+
+	// OCR поля, распознанные в файле после загрузки, и их расхождения с данными водителя;
+	// nil, пока распознавание не выполнено или если оно не настроено
+	OCR *DocumentOCRResult `json:"ocr,omitempty" db:"ocr_result"`
 }
 
 // IsExpired проверяет, не истек ли документ
@@ -127,6 +155,7 @@ func NewDriverDocument(driverID uuid.UUID, docType DocumentType, docNumber strin
 		ExpiryDate:     expiryDate,
 		FileURL:        fileURL,
 		Status:         VerificationStatusPending,
+		IsCurrent:      true,
 		Metadata:       make(Metadata),
 		CreatedAt:      now,
 		UpdatedAt:      now,
@@ -0,0 +1,184 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OCRStatus итог распознавания документа
+type OCRStatus string
+
+const (
+	// OCRStatusCompleted поля распознаны и сверены с данными водителя
+	OCRStatusCompleted OCRStatus = "completed"
+	// OCRStatusFailed сервис распознавания не ответил; документ проверяется без подсказок
+	OCRStatusFailed OCRStatus = "failed"
+)
+
+// OCRField поле документа, сверяемое с распознанным значением
+type OCRField string
+
+const (
+	// OCRFieldDocumentNumber номер документа; для удостоверения — номер удостоверения
+	OCRFieldDocumentNumber OCRField = "document_number"
+	// OCRFieldExpiryDate срок действия документа
+	OCRFieldExpiryDate OCRField = "expiry_date"
+	// OCRFieldFullName ФИО владельца документа
+	OCRFieldFullName OCRField = "full_name"
+)
+
+// OCRRequest файл документа для распознавания
+type OCRRequest struct {
+	DocumentID   uuid.UUID
+	DocumentType DocumentType
+	ContentType  string
+	Content      []byte
+}
+
+// OCRExtraction поля, распознанные в файле документа. Пустое поле означает, что
+// сервис распознавания его не прочитал
+type OCRExtraction struct {
+	DocumentNumber string     `json:"document_number,omitempty"`
+	ExpiryDate     *time.Time `json:"expiry_date,omitempty"`
+	FullName       string     `json:"full_name,omitempty"`
+	// Confidence уверенность распознавания от 0 до 1
+	Confidence float64 `json:"confidence"`
+}
+
+// OCRMismatch расхождение распознанного поля с указанным водителем значением
+type OCRMismatch struct {
+	Field     OCRField `json:"field"`
+	Submitted string   `json:"submitted"`
+	Extracted string   `json:"extracted"`
+}
+
+// DocumentOCRResult результат распознавания документа для верификатора
+type DocumentOCRResult struct {
+	Status     OCRStatus      `json:"status"`
+	Provider   string         `json:"provider"`
+	Confidence float64        `json:"confidence"`
+	Extracted  *OCRExtraction `json:"extracted,omitempty"`
+	// Mismatches поля, распознанные со значением, отличным от указанного водителем
+	Mismatches  []OCRMismatch `json:"mismatches"`
+	Error       string        `json:"error,omitempty"`
+	ProcessedAt time.Time     `json:"processed_at"`
+}
+
+// NewDocumentOCRResult сверяет распознанные поля с номером и сроком документа и ФИО
+// водителя. Нераспознанные поля не считаются расхождением
+func NewDocumentOCRResult(provider string, document *DriverDocument, owner *Driver, extracted *OCRExtraction, now time.Time) *DocumentOCRResult {
+	confidence := extracted.Confidence
+	if confidence < 0 {
+		confidence = 0
+	} else if confidence > 1 {
+		confidence = 1
+	}
+
+	result := &DocumentOCRResult{
+		Status:      OCRStatusCompleted,
+		Provider:    provider,
+		Confidence:  confidence,
+		Extracted:   extracted,
+		Mismatches:  []OCRMismatch{},
+		ProcessedAt: now,
+	}
+
+	if extracted.DocumentNumber != "" &&
+		NormalizeLicenseNumber(extracted.DocumentNumber) != NormalizeLicenseNumber(document.DocumentNumber) {
+		result.addMismatch(OCRFieldDocumentNumber, document.DocumentNumber, extracted.DocumentNumber)
+	}
+
+	if extracted.ExpiryDate != nil {
+		submitted := document.ExpiryDate.Format(time.DateOnly)
+		if value := extracted.ExpiryDate.Format(time.DateOnly); value != submitted {
+			result.addMismatch(OCRFieldExpiryDate, submitted, value)
+		}
+	}
+
+	if extracted.FullName != "" && owner != nil && !matchFullName(owner, extracted.FullName) {
+		result.addMismatch(OCRFieldFullName, owner.GetFullName(), extracted.FullName)
+	}
+
+	return result
+}
+
+// NewFailedOCRResult результат распознавания, которое не удалось выполнить
+func NewFailedOCRResult(provider, reason string, now time.Time) *DocumentOCRResult {
+	return &DocumentOCRResult{
+		Status:      OCRStatusFailed,
+		Provider:    provider,
+		Mismatches:  []OCRMismatch{},
+		Error:       reason,
+		ProcessedAt: now,
+	}
+}
+
+// HasMismatches сообщает, расходятся ли распознанные поля с данными водителя
+func (r *DocumentOCRResult) HasMismatches() bool {
+	return len(r.Mismatches) > 0
+}
+
+// addMismatch добавляет расхождение поля
+func (r *DocumentOCRResult) addMismatch(field OCRField, submitted, extracted string) {
+	r.Mismatches = append(r.Mismatches, OCRMismatch{Field: field, Submitted: submitted, Extracted: extracted})
+}
+
+// Value реализует driver.Valuer для хранения результата в JSONB
+func (r DocumentOCRResult) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+// Scan реализует sql.Scanner для чтения результата из JSONB
+func (r *DocumentOCRResult) Scan(value interface{}) error {
+	if value == nil {
+		*r = DocumentOCRResult{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into DocumentOCRResult", value)
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// matchFullName сравнивает ФИО без учета порядка слов, регистра и «ё». Если отчество
+// в профиле не указано, в документе допускается одно лишнее слово
+func matchFullName(owner *Driver, extracted string) bool {
+	submitted := nameWords(owner.GetFullName())
+	remaining := make(map[string]int, len(submitted))
+	for _, word := range submitted {
+		remaining[word]++
+	}
+
+	extra := 0
+	for _, word := range nameWords(extracted) {
+		if remaining[word] > 0 {
+			remaining[word]--
+			continue
+		}
+		extra++
+	}
+
+	for _, count := range remaining {
+		if count > 0 {
+			return false
+		}
+	}
+	allowed := 0
+	if owner.MiddleName == nil || *owner.MiddleName == "" {
+		allowed = 1
+	}
+	return extra <= allowed
+}
+
+// nameWords разбивает имя на слова в верхнем регистре, «ё» заменяется на «е»
+func nameWords(name string) []string {
+	name = strings.NewReplacer("ё", "е", "Ё", "Е").Replace(name)
+	return strings.Fields(strings.ToUpper(name))
+}
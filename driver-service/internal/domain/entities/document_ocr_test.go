@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentOCRResult(t *testing.T) {
+	now := time.Now()
+	expiry := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+	owner := &Driver{ID: uuid.New(), FirstName: "Пётр", LastName: "Иванов"}
+	document := NewDriverDocument(owner.ID, DocumentTypeDriverLicense, "77 AB 000001",
+		expiry.AddDate(-10, 0, 0), expiry, "https://example.com/license.pdf")
+
+	// Номер без пробелов, ФИО в другом порядке с отчеством, которого нет в профиле
+	result := NewDocumentOCRResult("stub", document, owner, &OCRExtraction{
+		DocumentNumber: "77ab000001",
+		ExpiryDate:     &expiry,
+		FullName:       "ПЕТР ИВАНОВ СЕРГЕЕВИЧ",
+		Confidence:     1.4,
+	}, now)
+	assert.Equal(t, OCRStatusCompleted, result.Status)
+	assert.Equal(t, 1.0, result.Confidence)
+	assert.False(t, result.HasMismatches())
+
+	otherExpiry := expiry.AddDate(1, 0, 0)
+	result = NewDocumentOCRResult("stub", document, owner, &OCRExtraction{
+		DocumentNumber: "77 AB 000002",
+		ExpiryDate:     &otherExpiry,
+		FullName:       "Сидоров Пётр",
+		Confidence:     0.6,
+	}, now)
+	require.Len(t, result.Mismatches, 3)
+	assert.Equal(t, OCRMismatch{Field: OCRFieldDocumentNumber, Submitted: "77 AB 000001", Extracted: "77 AB 000002"}, result.Mismatches[0])
+	assert.Equal(t, OCRFieldExpiryDate, result.Mismatches[1].Field)
+	assert.Equal(t, "2031-05-01", result.Mismatches[1].Extracted)
+	assert.Equal(t, OCRFieldFullName, result.Mismatches[2].Field)
+
+	// Нераспознанные поля не считаются расхождением
+	result = NewDocumentOCRResult("stub", document, owner, &OCRExtraction{Confidence: 0.1}, now)
+	assert.False(t, result.HasMismatches())
+
+	// Отчество указано в профиле: лишнее слово в документе — расхождение
+	middleName := "Сергеевич"
+	owner.MiddleName = &middleName
+	result = NewDocumentOCRResult("stub", document, owner, &OCRExtraction{FullName: "Иванов Петр Сергеевич Оглы"}, now)
+	require.Len(t, result.Mismatches, 1)
+	assert.Equal(t, "Иванов Пётр Сергеевич", result.Mismatches[0].Submitted)
+}
+
+func TestDocumentOCRResult_ValueScan(t *testing.T) {
+	expiry := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+	result := NewDocumentOCRResult("stub", &DriverDocument{DocumentNumber: "1", ExpiryDate: expiry}, nil,
+		&OCRExtraction{DocumentNumber: "2", FullName: "Иванов Иван", Confidence: 0.5}, time.Now().UTC())
+
+	value, err := result.Value()
+	require.NoError(t, err)
+
+	var scanned DocumentOCRResult
+	require.NoError(t, scanned.Scan(value))
+	assert.Equal(t, result.Mismatches, scanned.Mismatches)
+	assert.Equal(t, "Иванов Иван", scanned.Extracted.FullName)
+	assert.Error(t, scanned.Scan("text"))
+}
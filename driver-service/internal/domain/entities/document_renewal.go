@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IsRenewal проверяет, является ли документ новой версией продлеваемого документа
+func (d *DriverDocument) IsRenewal() bool {
+	return d.ReplacesID != nil
+}
+
+// CanRenew проверяет, можно ли продлить документ: текущая версия подтверждена и истекает
+// в ближайшие windowDays дней, уже истекла или требует перепроверки
+func (d *DriverDocument) CanRenew(windowDays int, now time.Time) bool {
+	if !d.IsCurrent {
+		return false
+	}
+	switch d.Status {
+	case VerificationStatusVerified:
+		return d.ExpiryDate.Before(now.AddDate(0, 0, windowDays))
+	case VerificationStatusExpired, VerificationStatusReverificationRequired:
+		return true
+	default:
+		return false
+	}
+}
+
+// NewRenewal создает новую версию документа. Текущий документ остается действующим
+// до проверки новой версии, а в очереди проверки новая версия получает срок,
+// равный дате истечения текущего документа или сроку перепроверки, если он раньше
+func (d *DriverDocument) NewRenewal(documentNumber string, issueDate, expiryDate time.Time, fileURL string) *DriverDocument {
+	renewal := NewDriverDocument(d.DriverID, d.DocumentType, documentNumber, issueDate, expiryDate, fileURL)
+	renewal.IsCurrent = false
+	replacesID := d.ID
+	verifyBy := d.ExpiryDate
+	// При перепроверке новая версия должна быть проверена до срока кампании
+	if d.IsReverificationRequired() && d.VerifyBy != nil && d.VerifyBy.Before(verifyBy) {
+		verifyBy = *d.VerifyBy
+	}
+	renewal.ReplacesID = &replacesID
+	renewal.VerifyBy = &verifyBy
+	return renewal
+}
+
+// IsReverificationRequired проверяет, нужно ли подтвердить документ заново
+func (d *DriverDocument) IsReverificationRequired() bool {
+	return d.Status == VerificationStatusReverificationRequired
+}
+
+// IsReverificationOverdue проверяет, истек ли срок перепроверки документа
+func (d *DriverDocument) IsReverificationOverdue(now time.Time) bool {
+	return d.IsReverificationRequired() && d.VerifyBy != nil && !now.Before(*d.VerifyBy)
+}
+
+// Supersede отмечает документ замененным подтвержденной новой версией
+func (d *DriverDocument) Supersede() {
+	d.Status = VerificationStatusSuperseded
+	d.IsCurrent = false
+	d.UpdatedAt = time.Now()
+}
+
+// MarkExpiryReminderSent отмечает отправку напоминания о продлении
+func (d *DriverDocument) MarkExpiryReminderSent() {
+	now := time.Now()
+	d.ExpiryReminderSentAt = &now
+	d.UpdatedAt = now
+}
+
+// DocumentRenewalRequest поля новой версии документа, загружаемой водителем
+type DocumentRenewalRequest struct {
+	DocumentNumber string    `form:"document_number" binding:"required"`
+	IssueDate      time.Time `form:"issue_date" binding:"required" time_format:"2006-01-02"`
+	ExpiryDate     time.Time `form:"expiry_date" binding:"required" time_format:"2006-01-02"`
+}
+
+// DocumentRenewalStatus состояние документа водителя с учетом продления
+type DocumentRenewalStatus struct {
+	Document *DriverDocument `json:"document"`
+	// Renewal новая версия, ожидающая проверки или отклоненная последней
+	Renewal  *DriverDocument `json:"renewal,omitempty"`
+	CanRenew bool            `json:"can_renew"`
+	// CanResubmit документ отклонен и его можно загрузить повторно
+	CanResubmit  bool `json:"can_resubmit"`
+	DaysToExpiry int  `json:"days_to_expiry"`
+}
+
+// DocumentHistory текущая версия документа одного типа и прежние версии
+type DocumentHistory struct {
+	DriverID     uuid.UUID    `json:"driver_id"`
+	DocumentType DocumentType `json:"document_type"`
+	// Current действующая версия; nil, если документа этого типа нет
+	Current *DriverDocument `json:"current"`
+	// History замененные версии и непринятые продления от новых к старым
+	History []*DriverDocument `json:"history"`
+}
+
+// DriverDocumentsResponse действующие документы водителя
+type DriverDocumentsResponse struct {
+	DriverID  uuid.UUID                `json:"driver_id"`
+	Documents []*DocumentRenewalStatus `json:"documents"`
+}
@@ -0,0 +1,113 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Claim захватывает документ верификатором на время ttl
+func (d *DriverDocument) Claim(verifierID string, now time.Time, ttl time.Duration) {
+	expiresAt := now.Add(ttl)
+	d.Status = VerificationStatusProcessing
+	d.ClaimedBy = &verifierID
+	d.ClaimedAt = &now
+	d.ClaimExpiresAt = &expiresAt
+	d.UpdatedAt = now
+}
+
+// ReleaseClaim возвращает документ в очередь проверки
+func (d *DriverDocument) ReleaseClaim(now time.Time) {
+	d.Status = VerificationStatusPending
+	d.ClaimedBy = nil
+	d.ClaimedAt = nil
+	d.ClaimExpiresAt = nil
+	d.UpdatedAt = now
+}
+
+// IsClaimedBy проверяет, удерживает ли верификатор действующий захват документа
+func (d *DriverDocument) IsClaimedBy(verifierID string, now time.Time) bool {
+	return d.Status == VerificationStatusProcessing &&
+		d.ClaimedBy != nil && *d.ClaimedBy == verifierID &&
+		d.ClaimExpiresAt != nil && now.Before(*d.ClaimExpiresAt)
+}
+
+// IsClaimStale проверяет, истек ли срок захвата документа
+func (d *DriverDocument) IsClaimStale(now time.Time) bool {
+	return d.Status == VerificationStatusProcessing &&
+		d.ClaimExpiresAt != nil && !now.Before(*d.ClaimExpiresAt)
+}
+
+// QueueBefore определяет порядок очереди проверки: сначала продления с ближайшим
+// сроком проверки, затем остальные документы в порядке поступления
+func QueueBefore(a, b *DriverDocument) bool {
+	switch {
+	case a.VerifyBy != nil && b.VerifyBy != nil:
+		if !a.VerifyBy.Equal(*b.VerifyBy) {
+			return a.VerifyBy.Before(*b.VerifyBy)
+		}
+	case a.VerifyBy != nil:
+		return true
+	case b.VerifyBy != nil:
+		return false
+	}
+	return a.CreatedAt.Before(b.CreatedAt)
+}
+
+// DocumentDecision решение верификатора по документу
+type DocumentDecision struct {
+	DocumentID      uuid.UUID          `json:"document_id" binding:"required"`
+	Status          VerificationStatus `json:"status" binding:"required"`
+	RejectionReason *string            `json:"rejection_reason,omitempty"`
+}
+
+// Validate проверяет решение: допускаются только verified и rejected, отказ требует причины
+func (d *DocumentDecision) Validate() error {
+	switch d.Status {
+	case VerificationStatusVerified:
+		return nil
+	case VerificationStatusRejected:
+		if d.RejectionReason == nil || strings.TrimSpace(*d.RejectionReason) == "" {
+			return ErrInvalidDecision
+		}
+		return nil
+	default:
+		return ErrInvalidDecision
+	}
+}
+
+// DocumentDecisionResult результат применения решения по одному документу
+type DocumentDecisionResult struct {
+	DocumentID uuid.UUID          `json:"document_id"`
+	Status     VerificationStatus `json:"status,omitempty"`
+	Applied    bool               `json:"applied"`
+	Error      string             `json:"error,omitempty"`
+}
+
+// DocumentDecisionBatchResult результат пакетного применения решений
+type DocumentDecisionBatchResult struct {
+	Results []*DocumentDecisionResult `json:"results"`
+	Applied int                       `json:"applied"`
+	Failed  int                       `json:"failed"`
+}
+
+// VerifierStats производительность верификатора за период
+type VerifierStats struct {
+	VerifierID             string  `json:"verifier_id" db:"verifier_id"`
+	Verified               int     `json:"verified" db:"verified"`
+	Rejected               int     `json:"rejected" db:"rejected"`
+	Total                  int     `json:"total" db:"total"`
+	AverageHandlingSeconds float64 `json:"average_handling_seconds" db:"average_handling_seconds"`
+	DocumentsPerHour       float64 `json:"documents_per_hour" db:"-"`
+}
+
+// VerifierStatsReport производительность верификаторов за период
+type VerifierStatsReport struct {
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Verifiers []*VerifierStats `json:"verifiers"`
+	// Pending и InProgress текущий размер очереди
+	Pending    int `json:"pending"`
+	InProgress int `json:"in_progress"`
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DocumentVersion прежняя версия документа, замененная повторной загрузкой после отказа.
+// Хранит данные и решение верификатора на момент замены
+type DocumentVersion struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	DocumentID uuid.UUID `json:"document_id" db:"document_id"`
+	DriverID   uuid.UUID `json:"driver_id" db:"driver_id"`
+	// Version порядковый номер версии документа, начиная с 1
+	Version         int                `json:"version" db:"version"`
+	DocumentNumber  string             `json:"document_number" db:"document_number"`
+	IssueDate       time.Time          `json:"issue_date" db:"issue_date"`
+	ExpiryDate      time.Time          `json:"expiry_date" db:"expiry_date"`
+	FileURL         string             `json:"file_url" db:"file_url"`
+	Status          VerificationStatus `json:"status" db:"status"`
+	VerifiedBy      *string            `json:"verified_by,omitempty" db:"verified_by"`
+	VerifiedAt      *time.Time         `json:"verified_at,omitempty" db:"verified_at"`
+	RejectionReason *string            `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	// SubmittedAt когда водитель загрузил эту версию
+	SubmittedAt time.Time `json:"submitted_at" db:"submitted_at"`
+	// ReplacedAt когда версию заменила повторная загрузка
+	ReplacedAt time.Time `json:"replaced_at" db:"replaced_at"`
+}
+
+// CanResubmit проверяет, можно ли загрузить документ заново: отклоненный документ
+// заменяется новой версией. Отклоненное продление не загружается заново — водитель
+// продлевает действующий документ еще раз
+func (d *DriverDocument) CanResubmit() bool {
+	return d.Status == VerificationStatusRejected && !d.IsRenewal()
+}
+
+// Resubmit заменяет данные отклоненного документа новой версией и возвращает прежнюю
+// версию для истории. Документ возвращается в очередь проверки
+func (d *DriverDocument) Resubmit(documentNumber string, issueDate, expiryDate time.Time, fileURL string, now time.Time) *DocumentVersion {
+	submittedAt := d.CreatedAt
+	if d.ResubmittedAt != nil {
+		submittedAt = *d.ResubmittedAt
+	}
+	previous := &DocumentVersion{
+		ID:              uuid.New(),
+		DocumentID:      d.ID,
+		DriverID:        d.DriverID,
+		Version:         d.ResubmissionCount + 1,
+		DocumentNumber:  d.DocumentNumber,
+		IssueDate:       d.IssueDate,
+		ExpiryDate:      d.ExpiryDate,
+		FileURL:         d.FileURL,
+		Status:          d.Status,
+		VerifiedBy:      d.VerifiedBy,
+		VerifiedAt:      d.VerifiedAt,
+		RejectionReason: d.RejectionReason,
+		SubmittedAt:     submittedAt,
+		ReplacedAt:      now,
+	}
+
+	d.DocumentNumber = documentNumber
+	d.IssueDate = issueDate
+	d.ExpiryDate = expiryDate
+	d.FileURL = fileURL
+	d.Status = VerificationStatusPending
+	d.VerifiedBy = nil
+	d.VerifiedAt = nil
+	d.RejectionReason = nil
+	d.ClaimedBy = nil
+	d.ClaimedAt = nil
+	d.ClaimExpiresAt = nil
+	d.OCR = nil
+	d.ResubmissionCount++
+	d.ResubmittedAt = &now
+	d.ResubmissionReminderSentAt = nil
+	d.UpdatedAt = now
+	return previous
+}
+
+// CurrentVersion номер текущей версии документа
+func (d *DriverDocument) CurrentVersion() int {
+	return d.ResubmissionCount + 1
+}
+
+// MarkResubmissionReminderSent отмечает отправку напоминания о повторной загрузке
+func (d *DriverDocument) MarkResubmissionReminderSent() {
+	now := time.Now()
+	d.ResubmissionReminderSentAt = &now
+	d.UpdatedAt = now
+}
+
+// DocumentVersionHistory документ водителя и его прежние версии
+type DocumentVersionHistory struct {
+	Document *DriverDocument `json:"document"`
+	// Versions замененные версии от новых к старым
+	Versions []*DocumentVersion `json:"versions"`
+}
@@ -25,6 +25,17 @@ const (
 	StatusBlocked             Status = "blocked"
 )
 
+// IsValid проверяет, что статус относится к известным статусам водителя
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusRegistered, StatusPendingVerification, StatusVerified, StatusRejected, StatusAvailable,
+		StatusOnShift, StatusBusy, StatusInactive, StatusSuspended, StatusBlocked:
+		return true
+	default:
+		return false
+	}
+}
+
 // Metadata дополнительные данные в формате JSON
 type Metadata map[string]interface{}
 
@@ -56,6 +67,10 @@ func (m *Metadata) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, m)
 }
 
+// PreviousDriverMetadataKey ключ метаданных водителя, повторно зарегистрированного с номером
+// удаленного водителя: ID удаленной записи
+const PreviousDriverMetadataKey = "previous_driver_id"
+
 // Driver представляет водителя в системе
 type Driver struct {
 	ID             uuid.UUID  `json:"id" db:"id"`
@@ -76,6 +91,57 @@ type Driver struct {
 	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+
+	// ShardKey ключ шарда местоположений водителя: нормализованный город из метаданных
+	ShardKey string `json:"shard_key,omitempty" db:"shard_key"`
+
+	// ResolvedCity ключ города, в границы которого попала последняя точка водителя (см. City).
+	// Меняется только при обработке местоположений; ключ шарда от него не зависит
+	ResolvedCity *string `json:"resolved_city,omitempty" db:"resolved_city"`
+
+	// FleetID автопарк (партнер), к которому прикреплен водитель
+	FleetID *uuid.UUID `json:"fleet_id,omitempty" db:"fleet_id"`
+
+	// Tags метки водителя (см. DriverTagList); изменяются только через DriverRepository.UpdateTags
+	Tags DriverTagList `json:"tags" db:"tags"`
+
+	// PhoneVerifiedAt когда водитель подтвердил телефон кодом из SMS; сбрасывается при смене
+	// номера и устанавливается только через DriverRepository.SetPhoneVerified
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty" db:"phone_verified_at"`
+
+	// Доли принятых и отмененных предложений заказов за окно dispatch.stats_window на момент
+	// последнего ответа водителя; пусто, пока ответов меньше dispatch.min_offers. Изменяются
+	// только через DriverRepository.UpdateDispatchRates
+	AcceptanceRate   *float64 `json:"acceptance_rate,omitempty" db:"acceptance_rate"`
+	CancellationRate *float64 `json:"cancellation_rate,omitempty" db:"cancellation_rate"`
+
+	// StatusVersion растет с каждой сменой статуса; в базе его увеличивает триггер, поэтому
+	// значение в памяти после записи может отставать. Сравнивается с X-Min-Status-Version при
+	// чтении, чтобы клиент не увидел статус старше своей записи
+	StatusVersion int64 `json:"status_version" db:"status_version"`
+
+	// Блокировка выплат со стороны биллинга
+	PaymentHold       bool       `json:"payment_hold" db:"payment_hold"`
+	PaymentHoldReason *string    `json:"payment_hold_reason,omitempty" db:"payment_hold_reason"`
+	PaymentHoldAt     *time.Time `json:"payment_hold_at,omitempty" db:"payment_hold_at"`
+}
+
+// Причины блокировки выплат. Детали от биллинга не сохраняются,
+// наружу отдается только категория причины.
+const (
+	PaymentHoldReasonChargeback  = "chargeback"
+	PaymentHoldReasonFraudReview = "fraud_review"
+	PaymentHoldReasonOther       = "other"
+)
+
+// NormalizePaymentHoldReason приводит причину блокировки к известной категории
+func NormalizePaymentHoldReason(reason string) string {
+	switch reason {
+	case PaymentHoldReasonChargeback, PaymentHoldReasonFraudReview:
+		return reason
+	default:
+		return PaymentHoldReasonOther
+	}
 }
 
 // IsActive проверяет, активен ли водитель
@@ -85,7 +151,25 @@ func (d *Driver) IsActive() bool {
 
 // CanReceiveOrders проверяет, может ли водитель получать заказы
 func (d *Driver) CanReceiveOrders() bool {
-	return d.Status == StatusAvailable && d.DeletedAt == nil
+	return d.Status == StatusAvailable && d.DeletedAt == nil && !d.PaymentHold
+}
+
+// PlacePaymentHold устанавливает блокировку со стороны биллинга
+func (d *Driver) PlacePaymentHold(reason string) {
+	now := time.Now()
+	normalized := NormalizePaymentHoldReason(reason)
+	d.PaymentHold = true
+	d.PaymentHoldReason = &normalized
+	d.PaymentHoldAt = &now
+	d.UpdatedAt = now
+}
+
+// ReleasePaymentHold снимает блокировку со стороны биллинга
+func (d *Driver) ReleasePaymentHold() {
+	d.PaymentHold = false
+	d.PaymentHoldReason = nil
+	d.PaymentHoldAt = nil
+	d.UpdatedAt = time.Now()
 }
 
 // UpdateRating обновляет рейтинг водителя
@@ -158,12 +242,14 @@ func NewDriver(phone, email, firstName, lastName, licenseNumber string) *Driver
 		CurrentRating: 0.0,
 		TotalTrips:    0,
 		Metadata:      make(Metadata),
+		Tags:          DriverTagList{},
 		CreatedAt:     now,
 		UpdatedAt:     now,
 	}
 }
 
-// DriverFilters фильтры для поиска водителей
+// DriverFilters фильтры для поиска водителей. City отбирает водителей по городу, в котором
+// они работают (см. Driver.OperatingCity)
 type DriverFilters struct {
 	Status        []Status   `json:"status,omitempty"`
 	MinRating     *float64   `json:"min_rating,omitempty"`
@@ -175,6 +261,19 @@ type DriverFilters struct {
 	Offset        int        `json:"offset,omitempty"`
 	SortBy        string     `json:"sort_by,omitempty"`
 	SortDirection string     `json:"sort_direction,omitempty"`
+
+	// FleetIDs отбирает водителей этих автопарков; nil — без ограничения,
+	// пустой список не отбирает никого
+	FleetIDs []uuid.UUID `json:"fleet_ids,omitempty"`
+
+	// Tags отбирает водителей, у которых есть все эти метки (в нормальной форме)
+	Tags []string `json:"tags,omitempty"`
+
+	// IDs отбирает водителей с этими ID; nil — без ограничения, пустой список не отбирает никого
+	IDs []uuid.UUID `json:"ids,omitempty"`
+
+	// PaymentHold отбирает водителей с блокировкой выплат (true) или без нее (false)
+	PaymentHold *bool `json:"payment_hold,omitempty"`
 }
 
 // DriverSummary краткая информация о водителе
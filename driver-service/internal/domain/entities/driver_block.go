@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// BlockReason код причины блокировки водителя
+type BlockReason string
+
+const (
+	// BlockReasonFraud мошенничество с заказами или выплатами
+	BlockReasonFraud BlockReason = "fraud"
+	// BlockReasonDocumentFraud поддельные или чужие документы
+	BlockReasonDocumentFraud BlockReason = "document_fraud"
+	// BlockReasonSafety угроза безопасности пассажиров
+	BlockReasonSafety BlockReason = "safety"
+)
+
+// IsValid проверяет, известен ли код причины
+func (r BlockReason) IsValid() bool {
+	switch r {
+	case BlockReasonFraud, BlockReasonDocumentFraud, BlockReasonSafety:
+		return true
+	}
+	return false
+}
+
+// UnblockReason способ снятия блокировки
+type UnblockReason string
+
+const (
+	// UnblockReasonManual блокировку снял администратор
+	UnblockReasonManual UnblockReason = "manual"
+	// UnblockReasonExpired блокировка снята фоновой задачей по сроку
+	UnblockReasonExpired UnblockReason = "expired"
+)
+
+// BlockSystemActor автор снятия блокировки по сроку
+const BlockSystemActor = "system"
+
+// MaxBlockCommentLength максимальная длина комментария к блокировке в символах
+const MaxBlockCommentLength = 500
+
+// DriverBlock блокировка водителя. Пока блокировка активна, водитель в статусе blocked;
+// при снятии ему возвращается статус, который был до блокировки (см. RestoreStatus)
+type DriverBlock struct {
+	ID       uuid.UUID   `json:"id" db:"id"`
+	DriverID uuid.UUID   `json:"driver_id" db:"driver_id"`
+	Reason   BlockReason `json:"reason" db:"reason"`
+	Comment  string      `json:"comment,omitempty" db:"comment"`
+	// PreviousStatus статус водителя до блокировки
+	PreviousStatus Status    `json:"previous_status" db:"previous_status"`
+	BlockedBy      string    `json:"blocked_by" db:"blocked_by"`
+	BlockedAt      time.Time `json:"blocked_at" db:"blocked_at"`
+	// UnblockAt время автоматического снятия; nil — бессрочно
+	UnblockAt      *time.Time     `json:"unblock_at,omitempty" db:"unblock_at"`
+	UnblockedAt    *time.Time     `json:"unblocked_at,omitempty" db:"unblocked_at"`
+	UnblockedBy    *string        `json:"unblocked_by,omitempty" db:"unblocked_by"`
+	UnblockReason  *UnblockReason `json:"unblock_reason,omitempty" db:"unblock_reason"`
+	UnblockComment *string        `json:"unblock_comment,omitempty" db:"unblock_comment"`
+}
+
+// IsActive проверяет, действует ли блокировка
+func (b *DriverBlock) IsActive() bool {
+	return b.UnblockedAt == nil
+}
+
+// IsExpired проверяет, наступил ли срок автоматического снятия
+func (b *DriverBlock) IsExpired(now time.Time) bool {
+	return b.IsActive() && b.UnblockAt != nil && !b.UnblockAt.After(now)
+}
+
+// RestoreStatus возвращает статус водителя после снятия блокировки. Водитель, который
+// был на линии, возвращается неактивным: за время блокировки он мог уйти из приложения,
+// и заказы ему начнут приходить только после выхода на линию
+func (b *DriverBlock) RestoreStatus() Status {
+	switch b.PreviousStatus {
+	case StatusAvailable, StatusOnShift, StatusBusy:
+		return StatusInactive
+	case "", StatusBlocked:
+		return StatusRegistered
+	default:
+		return b.PreviousStatus
+	}
+}
+
+// Lift снимает блокировку
+func (b *DriverBlock) Lift(at time.Time, actorID string, reason UnblockReason, comment string) {
+	actorID = strings.TrimSpace(actorID)
+	b.UnblockedAt = &at
+	b.UnblockedBy = &actorID
+	b.UnblockReason = &reason
+	if comment = strings.TrimSpace(comment); comment != "" {
+		b.UnblockComment = &comment
+	}
+}
+
+// BlockRequest запрос блокировки водителя
+type BlockRequest struct {
+	Reason  BlockReason `json:"reason" binding:"required"`
+	Comment string      `json:"comment,omitempty"`
+	// UnblockAt время автоматического снятия; не задано — блокировка бессрочная
+	UnblockAt *time.Time `json:"unblock_at,omitempty"`
+	BlockedBy string     `json:"-"`
+}
+
+// Validate проверяет код причины, комментарий, автора и срок блокировки
+func (r *BlockRequest) Validate(now time.Time) error {
+	r.Comment = strings.TrimSpace(r.Comment)
+	r.BlockedBy = strings.TrimSpace(r.BlockedBy)
+	if !r.Reason.IsValid() || r.BlockedBy == "" {
+		return ErrInvalidBlock
+	}
+	if utf8.RuneCountInString(r.Comment) > MaxBlockCommentLength {
+		return ErrInvalidBlock
+	}
+	if r.UnblockAt != nil && !r.UnblockAt.After(now) {
+		return ErrInvalidBlock
+	}
+	return nil
+}
+
+// NewDriverBlock создает блокировку водителя в статусе previous
+func NewDriverBlock(driverID uuid.UUID, previous Status, req *BlockRequest, at time.Time) *DriverBlock {
+	return &DriverBlock{
+		ID:             uuid.New(),
+		DriverID:       driverID,
+		Reason:         req.Reason,
+		Comment:        req.Comment,
+		PreviousStatus: previous,
+		BlockedBy:      req.BlockedBy,
+		BlockedAt:      at,
+		UnblockAt:      req.UnblockAt,
+	}
+}
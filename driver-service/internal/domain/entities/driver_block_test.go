@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockRequest_Validate(t *testing.T) {
+	now := time.Now()
+	future, past := now.Add(time.Hour), now.Add(-time.Hour)
+
+	req := &BlockRequest{Reason: BlockReasonSafety, Comment: " жалоба ", BlockedBy: " admin-1 ", UnblockAt: &future}
+	require.NoError(t, req.Validate(now))
+	assert.Equal(t, "жалоба", req.Comment)
+	assert.Equal(t, "admin-1", req.BlockedBy)
+
+	tests := []struct {
+		name string
+		req  BlockRequest
+	}{
+		{"unknown reason", BlockRequest{Reason: "rude", BlockedBy: "admin-1"}},
+		{"no author", BlockRequest{Reason: BlockReasonFraud}},
+		{"unblock in the past", BlockRequest{Reason: BlockReasonFraud, BlockedBy: "admin-1", UnblockAt: &past}},
+		{"long comment", BlockRequest{Reason: BlockReasonFraud, BlockedBy: "admin-1", Comment: strings.Repeat("к", MaxBlockCommentLength+1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.req.Validate(now), ErrInvalidBlock)
+		})
+	}
+}
+
+func TestDriverBlock_RestoreStatus(t *testing.T) {
+	tests := []struct {
+		previous Status
+		want     Status
+	}{
+		{StatusAvailable, StatusInactive},
+		{StatusOnShift, StatusInactive},
+		{StatusBusy, StatusInactive},
+		{StatusVerified, StatusVerified},
+		{StatusSuspended, StatusSuspended},
+		{StatusPendingVerification, StatusPendingVerification},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.previous), func(t *testing.T) {
+			block := &DriverBlock{PreviousStatus: tt.previous}
+			assert.Equal(t, tt.want, block.RestoreStatus())
+		})
+	}
+}
+
+func TestDriverBlock_Expiry(t *testing.T) {
+	now := time.Now()
+	unblockAt := now.Add(time.Hour)
+	block := NewDriverBlock(uuid.New(), StatusAvailable, &BlockRequest{
+		Reason: BlockReasonFraud, BlockedBy: "admin-1", UnblockAt: &unblockAt,
+	}, now)
+
+	assert.True(t, block.IsActive())
+	assert.False(t, block.IsExpired(now))
+	assert.True(t, block.IsExpired(unblockAt))
+
+	block.Lift(unblockAt, BlockSystemActor, UnblockReasonExpired, "")
+	assert.False(t, block.IsActive())
+	assert.False(t, block.IsExpired(unblockAt.Add(time.Hour)), "lifted block does not expire again")
+	assert.Nil(t, block.UnblockComment)
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxDailyStatsRange наибольший период запроса суточных показателей водителя
+const MaxDailyStatsRange = 366 * 24 * time.Hour
+
+// DriverDailyStats показатели водителя за сутки (UTC). Их сохраняет задача driver_daily_stats,
+// чтобы статистика не читала всю историю местоположений
+type DriverDailyStats struct {
+	DriverID uuid.UUID `json:"-" db:"driver_id"`
+	Day      time.Time `json:"day" db:"day"`
+	// Trips поездки смен, начатых в эти сутки
+	Trips int `json:"trips" db:"trips"`
+	// DistanceKm и OnlineSeconds как в LocationActivity по точкам суток; интервал между
+	// последней точкой предыдущих суток и первой точкой этих не учитывается
+	DistanceKm    float64 `json:"distance_km" db:"distance_km"`
+	OnlineSeconds int64   `json:"online_seconds" db:"online_seconds"`
+	// RatingSum сумма оценок за сутки; хранится, чтобы средняя за период считалась точно
+	RatingSum     int       `json:"-" db:"rating_sum"`
+	RatingCount   int       `json:"rating_count" db:"rating_count"`
+	AverageRating float64   `json:"average_rating" db:"-"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FillAverageRating вычисляет среднюю оценку за сутки
+func (s *DriverDailyStats) FillAverageRating() {
+	s.AverageRating = 0
+	if s.RatingCount > 0 {
+		s.AverageRating = float64(s.RatingSum) / float64(s.RatingCount)
+	}
+}
+
+// IsEmpty сообщает, что за сутки у водителя нет ни точек, ни поездок, ни оценок
+func (s *DriverDailyStats) IsEmpty() bool {
+	return s.Trips == 0 && s.DistanceKm == 0 && s.OnlineSeconds == 0 && s.RatingCount == 0
+}
+
+// DailyStatsCoverage сутки, за которые сохранены показатели всех водителей: [CoveredFrom,
+// RefreshedThrough). Текущие сутки сохраняются, но в покрытие не входят, пока не закончатся
+type DailyStatsCoverage struct {
+	CoveredFrom      time.Time `json:"covered_from" db:"covered_from"`
+	RefreshedThrough time.Time `json:"refreshed_through" db:"refreshed_through"`
+	RefreshedAt      time.Time `json:"refreshed_at" db:"refreshed_at"`
+}
+
+// Covers сообщает, есть ли в покрытии хотя бы одни сутки
+func (c *DailyStatsCoverage) Covers() bool {
+	return c != nil && c.RefreshedThrough.After(c.CoveredFrom)
+}
+
+// DriverDailyStatsReport суточные показатели водителя за период [From, To)
+type DriverDailyStatsReport struct {
+	DriverID uuid.UUID `json:"driver_id"`
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	// Coverage сутки, показатели которых окончательны; nil — показатели еще не сохранялись
+	Coverage *DailyStatsCoverage `json:"coverage,omitempty"`
+	// Days только сутки с активностью водителя
+	Days []*DriverDailyStats `json:"days"`
+}
+
+// DayStart возвращает начало суток (00:00 UTC), которым принадлежит момент времени
+func DayStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// ValidateDailyStatsRange проверяет период запроса суточных показателей
+func ValidateDailyStatsRange(from, to time.Time) error {
+	if !to.After(from) || to.Sub(from) > MaxDailyStatsRange {
+		return ErrInvalidStatsRange
+	}
+	return nil
+}
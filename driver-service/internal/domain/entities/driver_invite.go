@@ -0,0 +1,168 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// driverInviteTokenPurpose назначение токенов приглашений; пустое, как у токенов, выпущенных
+// до появления других подписанных ссылок
+const driverInviteTokenPurpose = ""
+
+// InviteMetadataKey ключ метаданных водителя, зарегистрированного по приглашению: ID приглашения
+const InviteMetadataKey = "invite_id"
+
+// DriverInviteStatus состояние приглашения водителя
+type DriverInviteStatus string
+
+const (
+	// DriverInviteStatusPending приглашение ждет регистрации водителя
+	DriverInviteStatusPending DriverInviteStatus = "pending"
+	// DriverInviteStatusAccepted водитель зарегистрировался по приглашению
+	DriverInviteStatusAccepted DriverInviteStatus = "accepted"
+	// DriverInviteStatusRevoked приглашение отозвано администратором
+	DriverInviteStatusRevoked DriverInviteStatus = "revoked"
+)
+
+// DriverInvite приглашение водителя автопарка: администратор заранее задает телефон и
+// автопарк, водитель по ссылке заполняет только личные данные
+type DriverInvite struct {
+	ID        uuid.UUID          `json:"id" db:"id"`
+	Phone     string             `json:"phone" db:"phone"`
+	FleetID   uuid.UUID          `json:"fleet_id" db:"fleet_id"`
+	Status    DriverInviteStatus `json:"status" db:"status"`
+	ExpiresAt time.Time          `json:"expires_at" db:"expires_at"`
+	CreatedBy string             `json:"created_by" db:"created_by"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+	// DriverID водитель, зарегистрированный по приглашению
+	DriverID   *uuid.UUID `json:"driver_id,omitempty" db:"driver_id"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty" db:"accepted_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedBy  *string    `json:"revoked_by,omitempty" db:"revoked_by"`
+}
+
+// CreateDriverInviteRequest запрос на приглашение водителя
+type CreateDriverInviteRequest struct {
+	Phone   string    `json:"phone" binding:"required"`
+	FleetID uuid.UUID `json:"fleet_id" binding:"required"`
+	// ExpiresAt срок действия ссылки; по умолчанию invites.default_ttl от создания
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// CreatedBy администратор, выпустивший приглашение; по умолчанию субъект токена
+	CreatedBy string `json:"created_by"`
+}
+
+// NewDriverInvite создает приглашение, действующее до expiresAt. Срок не может быть
+// в прошлом и дальше maxTTL от now
+func NewDriverInvite(req *CreateDriverInviteRequest, expiresAt time.Time, maxTTL time.Duration, now time.Time) (*DriverInvite, error) {
+	phone := strings.TrimSpace(req.Phone)
+	createdBy := strings.TrimSpace(req.CreatedBy)
+	if phone == "" || req.FleetID == uuid.Nil || createdBy == "" {
+		return nil, ErrInvalidDriverInvite
+	}
+	if !expiresAt.After(now) || expiresAt.Sub(now) > maxTTL {
+		return nil, ErrInvalidDriverInvite
+	}
+
+	return &DriverInvite{
+		ID:        uuid.New(),
+		Phone:     phone,
+		FleetID:   req.FleetID,
+		Status:    DriverInviteStatusPending,
+		ExpiresAt: expiresAt.UTC().Truncate(time.Second),
+		CreatedBy: createdBy,
+		CreatedAt: now,
+	}, nil
+}
+
+// CheckUsable проверяет, что по приглашению еще можно зарегистрироваться в момент at
+func (i *DriverInvite) CheckUsable(at time.Time) error {
+	switch i.Status {
+	case DriverInviteStatusAccepted:
+		return ErrDriverInviteUsed
+	case DriverInviteStatusRevoked:
+		return ErrDriverInviteRevoked
+	}
+	if !at.Before(i.ExpiresAt) {
+		return ErrDriverInviteExpired
+	}
+	return nil
+}
+
+// Accept отмечает приглашение использованным водителем driverID
+func (i *DriverInvite) Accept(driverID uuid.UUID, at time.Time) error {
+	if err := i.CheckUsable(at); err != nil {
+		return err
+	}
+	i.Status = DriverInviteStatusAccepted
+	i.DriverID = &driverID
+	i.AcceptedAt = &at
+	return nil
+}
+
+// Revoke отзывает неиспользованное приглашение
+func (i *DriverInvite) Revoke(actorID string, at time.Time) error {
+	switch i.Status {
+	case DriverInviteStatusAccepted:
+		return ErrDriverInviteUsed
+	case DriverInviteStatusRevoked:
+		return ErrDriverInviteRevoked
+	}
+	i.Status = DriverInviteStatusRevoked
+	i.RevokedAt = &at
+	if actorID != "" {
+		i.RevokedBy = &actorID
+	}
+	return nil
+}
+
+// DriverInviteLink приглашение вместе с токеном и ссылкой для водителя
+type DriverInviteLink struct {
+	*DriverInvite
+	Token string `json:"token"`
+	// Link ссылка для приложения водителя (invites.link_base_url с параметром token)
+	Link string `json:"link"`
+}
+
+// SignDriverInviteToken выпускает токен приглашения: ID и срок действия, подписанные
+// HMAC-SHA256 секретом secret. Токен не хранится и может быть выпущен повторно
+func SignDriverInviteToken(secret []byte, invite *DriverInvite) string {
+	return signToken(secret, driverInviteTokenPurpose, invite.ID, invite.ExpiresAt)
+}
+
+// ParseDriverInviteToken проверяет подпись токена и срок действия из него и возвращает
+// ID приглашения. Истекший токен отклоняется без обращения к хранилищу
+func ParseDriverInviteToken(secret []byte, token string, now time.Time) (uuid.UUID, error) {
+	inviteID, expiresAt, ok := parseSignedToken(secret, driverInviteTokenPurpose, token)
+	if !ok {
+		return uuid.Nil, ErrInvalidInviteToken
+	}
+	if !now.Before(expiresAt) {
+		return uuid.Nil, ErrDriverInviteExpired
+	}
+	return inviteID, nil
+}
+
+// DriverRegistrationRequest самостоятельная регистрация водителя по приглашению. Телефон и
+// автопарк берутся из приглашения, остальной профиль водитель заполняет позже
+type DriverRegistrationRequest struct {
+	FirstName  string  `json:"first_name" binding:"required"`
+	LastName   string  `json:"last_name" binding:"required"`
+	MiddleName *string `json:"middle_name,omitempty"`
+	Email      string  `json:"email"`
+}
+
+// Driver создает водителя по приглашению с данными из запроса
+func (r *DriverRegistrationRequest) Driver(invite *DriverInvite) *Driver {
+	fleetID := invite.FleetID
+	return &Driver{
+		Phone:      invite.Phone,
+		Email:      strings.TrimSpace(r.Email),
+		FirstName:  strings.TrimSpace(r.FirstName),
+		LastName:   strings.TrimSpace(r.LastName),
+		MiddleName: r.MiddleName,
+		FleetID:    &fleetID,
+		Metadata:   Metadata{InviteMetadataKey: invite.ID.String()},
+	}
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriverInviteToken(t *testing.T) {
+	secret := []byte("invite-secret")
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	invite, err := NewDriverInvite(&CreateDriverInviteRequest{
+		Phone:     "+79001112233",
+		FleetID:   uuid.New(),
+		CreatedBy: "admin-1",
+	}, now.Add(24*time.Hour), 30*24*time.Hour, now)
+	require.NoError(t, err)
+
+	token := SignDriverInviteToken(secret, invite)
+	id, err := ParseDriverInviteToken(secret, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, invite.ID, id)
+
+	// Срок действия входит в подпись и проверяется без хранилища
+	_, err = ParseDriverInviteToken(secret, token, now.Add(24*time.Hour))
+	assert.Equal(t, ErrDriverInviteExpired, err)
+
+	for _, bad := range []string{"", "no-dot", token[:10] + "." + token[11:], token + "A"} {
+		_, err = ParseDriverInviteToken(secret, bad, now)
+		assert.Equal(t, ErrInvalidInviteToken, err, bad)
+	}
+	_, err = ParseDriverInviteToken([]byte("other"), token, now)
+	assert.Equal(t, ErrInvalidInviteToken, err)
+}
+
+func TestDriverInvite_Lifecycle(t *testing.T) {
+	now := time.Now()
+	invite := &DriverInvite{Status: DriverInviteStatusPending, ExpiresAt: now.Add(time.Hour)}
+
+	assert.NoError(t, invite.CheckUsable(now))
+	assert.Equal(t, ErrDriverInviteExpired, invite.CheckUsable(now.Add(time.Hour)))
+
+	driverID := uuid.New()
+	require.NoError(t, invite.Accept(driverID, now))
+	assert.Equal(t, DriverInviteStatusAccepted, invite.Status)
+	assert.Equal(t, ErrDriverInviteUsed, invite.Accept(uuid.New(), now))
+	assert.Equal(t, ErrDriverInviteUsed, invite.Revoke("admin-1", now))
+
+	pending := &DriverInvite{Status: DriverInviteStatusPending, ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, pending.Revoke("admin-1", now))
+	require.NotNil(t, pending.RevokedBy)
+	assert.Equal(t, ErrDriverInviteRevoked, pending.CheckUsable(now))
+}
@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxDriverNoteLength максимальная длина заметки в символах
+const MaxDriverNoteLength = 4000
+
+// DriverNote внутренняя заметка поддержки или диспетчера о водителе. Заметки видят только
+// сотрудники; водителю и кабинетам автопарков они не отдаются
+type DriverNote struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	Body     string    `json:"body" db:"body"`
+	// Author субъект токена автора, AuthorRoles — его роли через запятую; пустые, если
+	// заметка добавлена без аутентификации
+	Author      *string   `json:"author,omitempty" db:"author"`
+	AuthorRoles *string   `json:"author_roles,omitempty" db:"author_roles"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// DriverNoteRequest запрос на добавление заметки
+type DriverNoteRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// Validate нормализует и проверяет текст заметки
+func (r *DriverNoteRequest) Validate() error {
+	r.Body = strings.TrimSpace(r.Body)
+	if r.Body == "" || utf8.RuneCountInString(r.Body) > MaxDriverNoteLength {
+		return ErrInvalidDriverNote
+	}
+	return nil
+}
+
+// NewDriverNote создает заметку о водителе с автором из контекста запроса (AuditActor)
+func NewDriverNote(driverID uuid.UUID, body string, actor AuditActor, now time.Time) *DriverNote {
+	note := &DriverNote{
+		ID:        uuid.New(),
+		DriverID:  driverID,
+		Body:      body,
+		CreatedAt: now,
+	}
+	if actor.Subject != "" {
+		note.Author = &actor.Subject
+	}
+	if actor.Roles != "" {
+		note.AuthorRoles = &actor.Roles
+	}
+	return note
+}
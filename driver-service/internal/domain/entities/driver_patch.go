@@ -0,0 +1,234 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// driverPatchFields поля профиля, которые можно изменить частичным обновлением
+var driverPatchFields = map[string]bool{
+	"email":           true,
+	"first_name":      true,
+	"last_name":       true,
+	"middle_name":     true,
+	"birth_date":      true,
+	"passport_series": true,
+	"passport_number": true,
+	"license_number":  true,
+	"license_expiry":  true,
+}
+
+// driverProtectedFields поля водителя, которые меняются только отдельными операциями:
+// статус — сменой статуса, рейтинг и поездки — по событиям, автопарк — переводом
+var driverProtectedFields = map[string]bool{
+	"id":                  true,
+	"phone":               true,
+	"status":              true,
+	"current_rating":      true,
+	"total_trips":         true,
+	"metadata":            true,
+	"created_at":          true,
+	"updated_at":          true,
+	"deleted_at":          true,
+	"shard_key":           true,
+	"fleet_id":            true,
+	"payment_hold":        true,
+	"payment_hold_reason": true,
+	"payment_hold_at":     true,
+}
+
+// jsonNull значение null в JSON merge patch: поле очищается
+var jsonNull = []byte("null")
+
+// DriverPatch частичное обновление профиля водителя в формате JSON merge patch (RFC 7396).
+// Проверяются только переданные поля; требования этапа профиля к итоговым данным
+// проверяет DriverService.UpdateDriver
+type DriverPatch struct {
+	fields map[string]json.RawMessage
+}
+
+// ParseDriverPatch разбирает тело merge patch. Если задана маска полей, применяются только
+// поля из маски, остальные поля тела игнорируются; поле из маски, отсутствующее в теле,
+// очищается так же, как переданное значение null
+func ParseDriverPatch(body []byte, mask []string) (*DriverPatch, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, fmt.Errorf("%w: body must be a JSON object", ErrInvalidPatch)
+	}
+
+	if len(mask) == 0 {
+		for name := range raw {
+			if err := checkPatchField(name); err != nil {
+				return nil, err
+			}
+		}
+		return &DriverPatch{fields: raw}, nil
+	}
+
+	fields := make(map[string]json.RawMessage, len(mask))
+	for _, name := range mask {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if err := checkPatchField(name); err != nil {
+			return nil, err
+		}
+		value, ok := raw[name]
+		if !ok {
+			value = jsonNull
+		}
+		fields[name] = value
+	}
+	return &DriverPatch{fields: fields}, nil
+}
+
+// checkPatchField проверяет, что поле можно изменить частичным обновлением
+func checkPatchField(name string) error {
+	if driverProtectedFields[name] {
+		return fmt.Errorf("%w: %s", ErrProtectedField, name)
+	}
+	if !driverPatchFields[name] {
+		return fmt.Errorf("%w: %s", ErrUnknownField, name)
+	}
+	return nil
+}
+
+// Fields возвращает изменяемые поля в алфавитном порядке
+func (p *DriverPatch) Fields() []string {
+	names := make([]string, 0, len(p.fields))
+	for name := range p.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsEmpty проверяет, что патч ничего не меняет
+func (p *DriverPatch) IsEmpty() bool {
+	return len(p.fields) == 0
+}
+
+// Apply проверяет переданные значения и применяет их к водителю. При ошибке водитель
+// не изменяется
+func (p *DriverPatch) Apply(d *Driver) error {
+	patched := *d
+	for _, name := range p.Fields() {
+		if err := patched.applyPatchField(name, p.fields[name]); err != nil {
+			return err
+		}
+	}
+	*d = patched
+	return nil
+}
+
+// applyPatchField применяет значение одного поля
+func (d *Driver) applyPatchField(name string, value json.RawMessage) error {
+	if bytes.Equal(bytes.TrimSpace(value), jsonNull) {
+		// Очистить можно только необязательное отчество
+		if name == "middle_name" {
+			d.MiddleName = nil
+			return nil
+		}
+		return fmt.Errorf("%w: %s cannot be null", ErrInvalidPatch, name)
+	}
+
+	switch name {
+	case "email":
+		email, err := patchString(name, value)
+		if err != nil {
+			return err
+		}
+		if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+			return ErrInvalidEmail
+		}
+		d.Email = email
+	case "first_name", "last_name":
+		nameValue, err := patchString(name, value)
+		if err != nil {
+			return err
+		}
+		if nameValue == "" {
+			return ErrInvalidName
+		}
+		if name == "first_name" {
+			d.FirstName = nameValue
+		} else {
+			d.LastName = nameValue
+		}
+	case "middle_name":
+		middleName, err := patchString(name, value)
+		if err != nil {
+			return err
+		}
+		if middleName == "" {
+			d.MiddleName = nil
+		} else {
+			d.MiddleName = &middleName
+		}
+	case "passport_series", "passport_number":
+		passport, err := patchString(name, value)
+		if err != nil {
+			return err
+		}
+		if passport == "" {
+			return ErrInvalidPassport
+		}
+		if name == "passport_series" {
+			d.PassportSeries = passport
+		} else {
+			d.PassportNumber = passport
+		}
+	case "license_number":
+		license, err := patchString(name, value)
+		if err != nil {
+			return err
+		}
+		if license == "" {
+			return ErrInvalidLicense
+		}
+		d.LicenseNumber = license
+	case "birth_date":
+		birthDate, err := patchTime(name, value)
+		if err != nil {
+			return err
+		}
+		if birthDate.IsZero() || birthDate.After(time.Now()) {
+			return ErrInvalidBirthDate
+		}
+		d.BirthDate = birthDate
+	case "license_expiry":
+		expiry, err := patchTime(name, value)
+		if err != nil {
+			return err
+		}
+		if expiry.IsZero() {
+			return ErrInvalidLicense
+		}
+		d.LicenseExpiry = expiry
+	}
+	return nil
+}
+
+// patchString разбирает строковое значение поля без окружающих пробелов
+func patchString(name string, value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", fmt.Errorf("%w: %s must be a string", ErrInvalidPatch, name)
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// patchTime разбирает значение поля с датой в формате RFC 3339
+func patchTime(name string, value json.RawMessage) (time.Time, error) {
+	var t time.Time
+	if err := json.Unmarshal(value, &t); err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s must be an RFC 3339 timestamp", ErrInvalidPatch, name)
+	}
+	return t, nil
+}
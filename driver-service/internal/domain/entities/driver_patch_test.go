@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func patchTestDriver() *Driver {
+	middleName := "Ivanovich"
+	driver := NewDriver("+79990000000", "old@example.com", "Ivan", "Petrov", "7700123456")
+	driver.MiddleName = &middleName
+	driver.PassportSeries = "4510"
+	driver.PassportNumber = "123456"
+	return driver
+}
+
+func TestDriverPatch_AppliesOnlySuppliedFields(t *testing.T) {
+	patch, err := ParseDriverPatch([]byte(`{"email":" new@example.com ","middle_name":null,"license_expiry":"2030-01-02T00:00:00Z"}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email", "license_expiry", "middle_name"}, patch.Fields())
+
+	driver := patchTestDriver()
+	require.NoError(t, patch.Apply(driver))
+	assert.Equal(t, "new@example.com", driver.Email)
+	assert.Nil(t, driver.MiddleName)
+	assert.Equal(t, time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC), driver.LicenseExpiry.UTC())
+	assert.Equal(t, "Ivan", driver.FirstName)
+	assert.Equal(t, "4510", driver.PassportSeries)
+}
+
+func TestDriverPatch_RejectsFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		mask []string
+		err  error
+	}{
+		{"status", `{"status":"available"}`, nil, ErrProtectedField},
+		{"rating", `{"current_rating":5}`, nil, ErrProtectedField},
+		{"trips in mask", `{}`, []string{"total_trips"}, ErrProtectedField},
+		{"unknown", `{"nickname":"x"}`, nil, ErrUnknownField},
+		{"not an object", `["email"]`, nil, ErrInvalidPatch},
+		{"null body", `null`, nil, ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDriverPatch([]byte(tt.body), tt.mask)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestDriverPatch_ValidatesValues(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{"bad email", `{"email":"not-an-email"}`, ErrInvalidEmail},
+		{"empty first name", `{"first_name":"  "}`, ErrInvalidName},
+		{"null last name", `{"last_name":null}`, ErrInvalidPatch},
+		{"number as string", `{"passport_number":123}`, ErrInvalidPatch},
+		{"future birth date", `{"birth_date":"2999-01-01T00:00:00Z"}`, ErrInvalidBirthDate},
+		{"bad date", `{"license_expiry":"tomorrow"}`, ErrInvalidPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch, err := ParseDriverPatch([]byte(tt.body), nil)
+			require.NoError(t, err)
+
+			driver := patchTestDriver()
+			before := *driver
+			assert.ErrorIs(t, patch.Apply(driver), tt.err)
+			assert.Equal(t, before, *driver)
+		})
+	}
+}
+
+func TestDriverPatch_FieldMask(t *testing.T) {
+	body := []byte(`{"first_name":"Pyotr","last_name":"Sidorov","status":"blocked"}`)
+
+	patch, err := ParseDriverPatch(body, []string{"first_name", " middle_name"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first_name", "middle_name"}, patch.Fields())
+
+	driver := patchTestDriver()
+	require.NoError(t, patch.Apply(driver))
+	assert.Equal(t, "Pyotr", driver.FirstName)
+	assert.Equal(t, "Petrov", driver.LastName, "fields outside the mask are ignored")
+	assert.Nil(t, driver.MiddleName, "masked field missing from the body is cleared")
+
+	patch, err = ParseDriverPatch(body, []string{"email"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, patch.Apply(patchTestDriver()), ErrInvalidPatch, "required field cannot be cleared")
+}
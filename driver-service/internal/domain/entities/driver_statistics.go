@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationActivity пробег и время на связи водителя по исходным точкам за период
+type LocationActivity struct {
+	DistanceKm float64 `json:"distance_km" db:"distance_km"`
+	// OnlineSeconds сумма интервалов между соседними точками, не превышающих допустимый разрыв
+	OnlineSeconds int64 `json:"online_seconds" db:"online_seconds"`
+}
+
+// NewLocationActivity считает пробег и время на связи по точкам, упорядоченным по времени
+// записи. Интервал длиннее maxGap считается потерей связи и во время на связи не входит;
+// maxGap = 0 учитывает все интервалы
+func NewLocationActivity(locations []*DriverLocation, maxGap time.Duration) *LocationActivity {
+	activity := &LocationActivity{}
+	for i := 1; i < len(locations); i++ {
+		activity.DistanceKm += locations[i-1].DistanceTo(locations[i])
+		gap := locations[i].RecordedAt.Sub(locations[i-1].RecordedAt)
+		if maxGap <= 0 || gap <= maxGap {
+			activity.OnlineSeconds += int64(gap.Seconds())
+		}
+	}
+	return activity
+}
+
+// PeriodHours часы за текущую неделю и текущий месяц (UTC)
+type PeriodHours struct {
+	Week  float64 `json:"week"`
+	Month float64 `json:"month"`
+}
+
+// RatingSummary средняя оценка водителя и перцентили распределения оценок
+type RatingSummary struct {
+	Average      float64 `json:"average"`
+	Total        int     `json:"total"`
+	Percentile90 float64 `json:"percentile_90"`
+	Percentile95 float64 `json:"percentile_95"`
+}
+
+// DocumentStatistics состояние действующих документов водителя
+type DocumentStatistics struct {
+	Total    int                        `json:"total"`
+	ByStatus map[VerificationStatus]int `json:"by_status"`
+	Expired  int                        `json:"expired"`
+	// NextExpiry ближайший срок действия среди неистекших документов
+	NextExpiry *time.Time `json:"next_expiry,omitempty"`
+}
+
+// NewDocumentStatistics сводит документы водителя; замененные версии не учитываются
+func NewDocumentStatistics(documents []*DriverDocument, now time.Time) *DocumentStatistics {
+	stats := &DocumentStatistics{ByStatus: make(map[VerificationStatus]int)}
+	for _, document := range documents {
+		if document.Status == VerificationStatusSuperseded {
+			continue
+		}
+		stats.Total++
+		stats.ByStatus[document.Status]++
+		if !document.ExpiryDate.After(now) {
+			stats.Expired++
+			continue
+		}
+		if stats.NextExpiry == nil || document.ExpiryDate.Before(*stats.NextExpiry) {
+			expiry := document.ExpiryDate
+			stats.NextExpiry = &expiry
+		}
+	}
+	return stats
+}
+
+// DriverStatistics сводные показатели водителя для панели диспетчера
+type DriverStatistics struct {
+	DriverID   uuid.UUID `json:"driver_id"`
+	TotalTrips int       `json:"total_trips"`
+	// TotalDistanceKm пробег по всей сохраненной истории местоположений
+	TotalDistanceKm float64             `json:"total_distance_km"`
+	Rating          *RatingSummary      `json:"rating"`
+	ShiftHours      PeriodHours         `json:"shift_hours"`
+	OnlineHours     PeriodHours         `json:"online_hours"`
+	Documents       *DocumentStatistics `json:"documents"`
+	// Dispatch доли принятых и отмененных предложений заказов по окнам учета
+	Dispatch   []*DispatchRates `json:"dispatch"`
+	WeekStart  time.Time        `json:"week_start"`
+	MonthStart time.Time        `json:"month_start"`
+	// AggregatedThrough пробег и время на связи до этого момента взяты из суточных показателей
+	AggregatedThrough *time.Time `json:"aggregated_through,omitempty"`
+	ComputedAt        time.Time  `json:"computed_at"`
+}
+
+// MonthStart возвращает начало месяца (1 число 00:00 UTC), которому принадлежит момент времени
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ShiftHours суммирует часы смен внутри периода [from, to); активная смена учитывается до to
+func ShiftHours(shifts []*DriverShift, from, to time.Time) float64 {
+	var total time.Duration
+	for _, shift := range shifts {
+		start, end := shift.StartTime, to
+		if shift.EndTime != nil && shift.EndTime.Before(end) {
+			end = *shift.EndTime
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total.Hours()
+}
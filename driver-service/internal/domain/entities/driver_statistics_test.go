@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocationActivity(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	track := []*DriverLocation{
+		newTrackPoint(start, 55.700, 20),
+		newTrackPoint(start.Add(1*time.Minute), 55.701, 20),
+		newTrackPoint(start.Add(3*time.Minute), 55.702, 20),
+		// Разрыв связи: пробег учитывается, время — нет
+		newTrackPoint(start.Add(20*time.Minute), 55.710, 20),
+	}
+
+	activity := NewLocationActivity(track, 5*time.Minute)
+	assert.InDelta(t, track[0].DistanceTo(track[2])+track[2].DistanceTo(track[3]), activity.DistanceKm, 0.001)
+	assert.Equal(t, int64(180), activity.OnlineSeconds)
+
+	assert.Equal(t, int64(1200), NewLocationActivity(track, 0).OnlineSeconds)
+	assert.Equal(t, &LocationActivity{}, NewLocationActivity(track[:1], time.Minute))
+}
+
+func TestShiftHours(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+	ended := func(start time.Time, hours int) *DriverShift {
+		end := start.Add(time.Duration(hours) * time.Hour)
+		return &DriverShift{StartTime: start, EndTime: &end}
+	}
+	shifts := []*DriverShift{
+		// Смена с воскресенья захватывает 2 часа недели
+		ended(monday.Add(-4*time.Hour), 6),
+		ended(monday.Add(10*time.Hour), 8),
+		// Активная смена считается до конца периода
+		{StartTime: monday.Add(30 * time.Hour)},
+	}
+
+	assert.InDelta(t, 13.0, ShiftHours(shifts, monday, monday.Add(33*time.Hour)), 0.001)
+	assert.InDelta(t, 0.0, ShiftHours(shifts, monday.Add(40*time.Hour), monday.Add(33*time.Hour)), 0.001)
+}
+
+func TestNewDocumentStatistics(t *testing.T) {
+	now := time.Now()
+	driverID := uuid.New()
+	document := func(status VerificationStatus, expiresIn time.Duration) *DriverDocument {
+		doc := NewDriverDocument(driverID, DocumentTypeDriverLicense, "77 00 123456", now.AddDate(-1, 0, 0), now.Add(expiresIn), "")
+		doc.Status = status
+		return doc
+	}
+
+	stats := NewDocumentStatistics([]*DriverDocument{
+		document(VerificationStatusVerified, 90*24*time.Hour),
+		document(VerificationStatusPending, 30*24*time.Hour),
+		document(VerificationStatusVerified, -24*time.Hour),
+		document(VerificationStatusSuperseded, 10*24*time.Hour),
+	}, now)
+
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, map[VerificationStatus]int{VerificationStatusVerified: 2, VerificationStatusPending: 1}, stats.ByStatus)
+	assert.Equal(t, 1, stats.Expired)
+	require.NotNil(t, stats.NextExpiry)
+	assert.WithinDuration(t, now.Add(30*24*time.Hour), *stats.NextExpiry, time.Second)
+}
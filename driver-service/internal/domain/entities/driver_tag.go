@@ -0,0 +1,123 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxDriverTagLength ограничение длины метки водителя в символах
+const MaxDriverTagLength = 50
+
+// DriverTagList метки водителя, хранимые в JSONB. Метки приводятся к нормальной форме
+// (см. NormalizeDriverTag) и используются для адресной раздачи заказов и промоакций:
+// например vip-capable, pet-friendly, wheelchair-accessible
+type DriverTagList []string
+
+// Value реализует driver.Valuer
+func (l DriverTagList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *DriverTagList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into DriverTagList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains проверяет, есть ли метка в списке
+func (l DriverTagList) Contains(tag string) bool {
+	for _, t := range l {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// With возвращает список с добавленными метками; уже имеющиеся метки не повторяются
+func (l DriverTagList) With(tags []string) DriverTagList {
+	result := append(DriverTagList{}, l...)
+	for _, tag := range tags {
+		if !result.Contains(tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// Without возвращает список без меток tags; Without(nil) — непустую копию списка
+func (l DriverTagList) Without(tags []string) DriverTagList {
+	result := DriverTagList{}
+	for _, t := range l {
+		if !DriverTagList(tags).Contains(t) {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// NormalizeDriverTag приводит метку к нормальной форме: нижний регистр, пробелы и
+// подчеркивания заменяются дефисом. "Wheelchair-accessible vehicle" становится
+// "wheelchair-accessible-vehicle". Допускаются буквы, цифры и дефис
+func NormalizeDriverTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.Join(strings.Fields(strings.TrimSpace(tag)), "-"))
+	tag = strings.ReplaceAll(tag, "_", "-")
+	if tag == "" || utf8.RuneCountInString(tag) > MaxDriverTagLength {
+		return "", ErrInvalidDriverTag
+	}
+	for _, r := range tag {
+		if r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "", ErrInvalidDriverTag
+		}
+	}
+	return tag, nil
+}
+
+// NormalizeDriverTags нормализует метки и убирает повторы, сохраняя порядок
+func NormalizeDriverTags(tags []string) ([]string, error) {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized, err := NormalizeDriverTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !DriverTagList(result).Contains(normalized) {
+			result = append(result, normalized)
+		}
+	}
+	return result, nil
+}
+
+// HasTags проверяет, что у водителя есть все метки tags
+func (d *Driver) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !d.Tags.Contains(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// DriverTagCatalog справочник меток: контролируемые метки доступны всегда, произвольные —
+// только при FreeForm
+type DriverTagCatalog struct {
+	Controlled []string `json:"controlled"`
+	FreeForm   bool     `json:"free_form"`
+	// MaxPerDriver ограничение числа меток у водителя; 0 — без ограничения
+	MaxPerDriver int `json:"max_per_driver"`
+}
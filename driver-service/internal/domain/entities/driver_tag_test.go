@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeDriverTag(t *testing.T) {
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"VIP-capable", "vip-capable"},
+		{"  pet friendly ", "pet-friendly"},
+		{"Wheelchair-accessible vehicle", "wheelchair-accessible-vehicle"},
+		{"child_seat", "child-seat"},
+		{"Детское кресло", "детское-кресло"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			tag, err := NormalizeDriverTag(tt.tag)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tag)
+		})
+	}
+
+	for _, tag := range []string{"", "   ", "vip!", "a/b", strings.Repeat("a", MaxDriverTagLength+1)} {
+		_, err := NormalizeDriverTag(tag)
+		assert.ErrorIs(t, err, ErrInvalidDriverTag, tag)
+	}
+}
+
+func TestDriverTagList(t *testing.T) {
+	tags := DriverTagList{"vip-capable"}.With([]string{"pet-friendly", "vip-capable"})
+	assert.Equal(t, DriverTagList{"vip-capable", "pet-friendly"}, tags)
+	assert.Equal(t, DriverTagList{"pet-friendly"}, tags.Without([]string{"vip-capable"}))
+
+	value, err := DriverTagList(nil).Value()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("[]"), value)
+
+	var scanned DriverTagList
+	require.NoError(t, scanned.Scan([]byte(`["vip-capable"]`)))
+	assert.Equal(t, DriverTagList{"vip-capable"}, scanned)
+
+	driver := &Driver{Tags: tags}
+	assert.True(t, driver.HasTags(nil))
+	assert.True(t, driver.HasTags([]string{"pet-friendly", "vip-capable"}))
+	assert.False(t, driver.HasTags([]string{"pet-friendly", "child-seat"}))
+}
@@ -322,3 +322,32 @@ func stringPtr(s string) *string {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestDriver_Anonymize(t *testing.T) {
+	driver := NewDriver("+79001234567", "test@example.com", "Иван", "Тестовый", "TEST123456")
+	driver.PassportSeries = "1234"
+	driver.PassportNumber = "567890"
+	driver.CurrentRating = 4.8
+	driver.TotalTrips = 120
+	driver.Metadata = Metadata{DriverMetaCity: "Москва", "telegram": "@ivan"}
+	now := time.Now()
+
+	driver.Anonymize(now)
+
+	assert.Equal(t, ErasedDriverName, driver.FirstName)
+	assert.Empty(t, driver.LastName)
+	assert.Empty(t, driver.PassportSeries)
+	assert.Empty(t, driver.PassportNumber)
+	assert.True(t, driver.BirthDate.IsZero())
+	assert.NotContains(t, driver.Phone, "9001234567")
+	assert.LessOrEqual(t, len(driver.Phone), 20)
+	assert.NotEqual(t, "test@example.com", driver.Email)
+	assert.NotEqual(t, "TEST123456", driver.LicenseNumber)
+	assert.Equal(t, 4.8, driver.CurrentRating)
+	assert.Equal(t, 120, driver.TotalTrips)
+
+	require.NotNil(t, driver.City())
+	assert.Equal(t, "Москва", *driver.City())
+	assert.NotContains(t, driver.Metadata, "telegram")
+	assert.Contains(t, driver.Metadata, DriverMetaPersonalDataErasedAt)
+}
@@ -0,0 +1,133 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RequirementState состояние обязательного документа водителя
+type RequirementState string
+
+const (
+	RequirementMissing  RequirementState = "missing"
+	RequirementPending  RequirementState = "pending"
+	RequirementVerified RequirementState = "verified"
+	RequirementRejected RequirementState = "rejected"
+	RequirementExpired  RequirementState = "expired"
+	// RequirementReverification документ действует, но должен быть подтвержден заново
+	RequirementReverification RequirementState = "reverification_required"
+	// RequirementReverificationOverdue срок перепроверки документа прошел
+	RequirementReverificationOverdue RequirementState = "reverification_overdue"
+)
+
+// Failed проверяет, перестал ли документ подтверждать допуск водителя
+func (s RequirementState) Failed() bool {
+	return s == RequirementRejected || s == RequirementExpired || s == RequirementReverificationOverdue
+}
+
+// VerificationOutcome решение по допуску водителя после проверки документов
+type VerificationOutcome string
+
+const (
+	// VerificationOutcomeNone статус водителя не меняется
+	VerificationOutcomeNone VerificationOutcome = "none"
+	// VerificationOutcomeVerified все обязательные документы подтверждены
+	VerificationOutcomeVerified VerificationOutcome = "verified"
+	// VerificationOutcomeRejected документ отклонен или истек до завершения верификации
+	VerificationOutcomeRejected VerificationOutcome = "rejected"
+	// VerificationOutcomeSuspended документ допущенного водителя отклонен или истек
+	VerificationOutcomeSuspended VerificationOutcome = "suspended"
+	// VerificationOutcomeReinstated документы снова подтверждены после автоматического отстранения
+	VerificationOutcomeReinstated VerificationOutcome = "reinstated"
+	// VerificationOutcomeDeferred отстранение отложено: водитель выполняет заказ
+	VerificationOutcomeDeferred VerificationOutcome = "deferred"
+)
+
+// DocumentRequirement состояние обязательного документа
+type DocumentRequirement struct {
+	DocumentType    DocumentType     `json:"document_type"`
+	State           RequirementState `json:"state"`
+	DocumentID      *uuid.UUID       `json:"document_id,omitempty"`
+	ExpiryDate      *time.Time       `json:"expiry_date,omitempty"`
+	RejectionReason *string          `json:"rejection_reason,omitempty"`
+}
+
+// DriverVerificationResult результат проверки допуска водителя по документам
+type DriverVerificationResult struct {
+	DriverID       uuid.UUID             `json:"driver_id"`
+	PreviousStatus Status                `json:"previous_status"`
+	Status         Status                `json:"status"`
+	Outcome        VerificationOutcome   `json:"outcome"`
+	Requirements   []DocumentRequirement `json:"requirements"`
+	EvaluatedAt    time.Time             `json:"evaluated_at"`
+}
+
+// EvaluateRequirements определяет состояние каждого обязательного типа документа по его
+// текущей версии (IsCurrent). Продление, ожидающее проверки, не заменяет текущую версию:
+// истекший документ остается истекшим, пока новая версия не подтверждена
+func EvaluateRequirements(required []DocumentType, documents []*DriverDocument) []DocumentRequirement {
+	requirements := make([]DocumentRequirement, len(required))
+	for i, docType := range required {
+		requirement := DocumentRequirement{DocumentType: docType, State: RequirementMissing}
+
+		for _, document := range documents {
+			if document.DocumentType != docType || !document.IsCurrent {
+				continue
+			}
+			id := document.ID
+			expiry := document.ExpiryDate
+			requirement.State = documentState(document)
+			requirement.DocumentID = &id
+			requirement.ExpiryDate = &expiry
+			if requirement.State == RequirementRejected {
+				requirement.RejectionReason = document.RejectionReason
+			}
+			break
+		}
+		requirements[i] = requirement
+	}
+	return requirements
+}
+
+// documentState состояние обязательного документа по одному документу
+func documentState(document *DriverDocument) RequirementState {
+	switch {
+	case document.IsVerified():
+		return RequirementVerified
+	case document.Status == VerificationStatusPending || document.Status == VerificationStatusProcessing:
+		if document.IsExpired() {
+			return RequirementExpired
+		}
+		return RequirementPending
+	case document.Status == VerificationStatusRejected:
+		return RequirementRejected
+	case document.IsReverificationRequired() && !document.IsExpired():
+		if document.IsReverificationOverdue(time.Now()) {
+			return RequirementReverificationOverdue
+		}
+		return RequirementReverification
+	default:
+		return RequirementExpired
+	}
+}
+
+// AllVerified проверяет, подтверждены ли все обязательные документы
+func AllVerified(requirements []DocumentRequirement) bool {
+	for _, requirement := range requirements {
+		if requirement.State != RequirementVerified {
+			return false
+		}
+	}
+	return true
+}
+
+// AnyFailed проверяет, отклонен или истек ли хотя бы один обязательный документ
+func AnyFailed(requirements []DocumentRequirement) bool {
+	for _, requirement := range requirements {
+		if requirement.State.Failed() {
+			return true
+		}
+	}
+	return false
+}
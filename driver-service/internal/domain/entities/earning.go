@@ -0,0 +1,280 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EarningType вид начисления водителю
+type EarningType string
+
+const (
+	// EarningTypeTrip оплата поездки: стоимость, комиссия сервиса и бонус за поездку
+	EarningTypeTrip EarningType = "trip"
+	// EarningTypeBonus бонус вне поездки, например за выполненный план недели
+	EarningTypeBonus EarningType = "bonus"
+)
+
+// IsValid проверяет, известен ли вид начисления
+func (t EarningType) IsValid() bool {
+	switch t {
+	case EarningTypeTrip, EarningTypeBonus:
+		return true
+	}
+	return false
+}
+
+// DriverEarning начисление водителю. NetAmount — сумма к выплате: стоимость поездки
+// за вычетом комиссии плюс бонус
+type DriverEarning struct {
+	ID       uuid.UUID   `json:"id" db:"id"`
+	DriverID uuid.UUID   `json:"driver_id" db:"driver_id"`
+	ShiftID  *uuid.UUID  `json:"shift_id,omitempty" db:"shift_id"`
+	OrderID  *uuid.UUID  `json:"order_id,omitempty" db:"order_id"`
+	Type     EarningType `json:"type" db:"type"`
+	// Fare стоимость поездки, оплаченная пассажиром
+	Fare        float64   `json:"fare" db:"fare"`
+	Commission  float64   `json:"commission" db:"commission"`
+	Bonus       float64   `json:"bonus" db:"bonus"`
+	NetAmount   float64   `json:"net_amount" db:"net_amount"`
+	Currency    string    `json:"currency" db:"currency"`
+	Description *string   `json:"description,omitempty" db:"description"`
+	EarnedAt    time.Time `json:"earned_at" db:"earned_at"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// Validate проверяет вид, суммы и валюту начисления
+func (e *DriverEarning) Validate() error {
+	if e.DriverID == uuid.Nil {
+		return ErrInvalidDriverID
+	}
+
+	switch e.Type {
+	case EarningTypeTrip:
+		// Комиссия не может превышать стоимость поездки
+		if e.OrderID == nil || e.Fare <= 0 || e.Commission < 0 || e.Commission > e.Fare || e.Bonus < 0 {
+			return ErrInvalidEarning
+		}
+	case EarningTypeBonus:
+		if e.Fare != 0 || e.Commission != 0 || e.Bonus <= 0 {
+			return ErrInvalidEarning
+		}
+	default:
+		return ErrInvalidEarning
+	}
+
+	if len(e.Currency) != 3 || strings.ToUpper(e.Currency) != e.Currency {
+		return ErrInvalidCurrency
+	}
+
+	if e.EarnedAt.IsZero() {
+		return ErrInvalidTimestamp
+	}
+
+	return nil
+}
+
+// EarningRequest запрос на начисление водителю
+type EarningRequest struct {
+	Type EarningType `json:"type" binding:"required"`
+	// OrderID заказ поездки; обязателен для trip, по одному заказу начисляется одна поездка
+	OrderID *uuid.UUID `json:"order_id,omitempty"`
+	// ShiftID смена водителя, в которой выполнена поездка
+	ShiftID     *uuid.UUID `json:"shift_id,omitempty"`
+	Fare        float64    `json:"fare"`
+	Commission  float64    `json:"commission"`
+	Bonus       float64    `json:"bonus"`
+	Currency    string     `json:"currency,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	// EarnedAt время начисления, по умолчанию текущее
+	EarnedAt *time.Time `json:"earned_at,omitempty"`
+}
+
+// NewDriverEarning создает начисление водителю из запроса
+func NewDriverEarning(driverID uuid.UUID, req *EarningRequest, currency string, earnedAt time.Time) *DriverEarning {
+	return &DriverEarning{
+		ID:          uuid.New(),
+		DriverID:    driverID,
+		ShiftID:     req.ShiftID,
+		OrderID:     req.OrderID,
+		Type:        req.Type,
+		Fare:        req.Fare,
+		Commission:  req.Commission,
+		Bonus:       req.Bonus,
+		NetAmount:   req.Fare - req.Commission + req.Bonus,
+		Currency:    strings.ToUpper(currency),
+		Description: req.Description,
+		EarnedAt:    earnedAt,
+		CreatedAt:   time.Now(),
+	}
+}
+
+// EarningFilters фильтры для поиска начислений
+type EarningFilters struct {
+	DriverID *uuid.UUID   `json:"driver_id,omitempty"`
+	ShiftID  *uuid.UUID   `json:"shift_id,omitempty"`
+	Type     *EarningType `json:"type,omitempty"`
+	From     *time.Time   `json:"from,omitempty"`
+	To       *time.Time   `json:"to,omitempty"`
+	Limit    int          `json:"limit,omitempty"`
+	Offset   int          `json:"offset,omitempty"`
+}
+
+// EarningsGrouping период группировки сводки начислений
+type EarningsGrouping string
+
+const (
+	EarningsGroupingDay   EarningsGrouping = "day"
+	EarningsGroupingWeek  EarningsGrouping = "week"
+	EarningsGroupingMonth EarningsGrouping = "month"
+)
+
+// IsValid проверяет, известен ли период группировки
+func (g EarningsGrouping) IsValid() bool {
+	switch g {
+	case EarningsGroupingDay, EarningsGroupingWeek, EarningsGroupingMonth:
+		return true
+	}
+	return false
+}
+
+// PeriodStart возвращает начало периода, которому принадлежит момент времени, в часовом
+// поясе loc; неделя начинается с понедельника
+func (g EarningsGrouping) PeriodStart(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch g {
+	case EarningsGroupingWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	case EarningsGroupingMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// PeriodEnd возвращает начало следующего периода
+func (g EarningsGrouping) PeriodEnd(start time.Time) time.Time {
+	switch g {
+	case EarningsGroupingWeek:
+		return start.AddDate(0, 0, 7)
+	case EarningsGroupingMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// EarningAmounts суммы начислений в одной валюте
+type EarningAmounts struct {
+	Currency string `json:"currency" db:"currency"`
+	// Entries число начислений, Trips — из них оплат поездок
+	Entries    int     `json:"entries" db:"entries"`
+	Trips      int     `json:"trips" db:"trips"`
+	Fare       float64 `json:"fare" db:"fare"`
+	Commission float64 `json:"commission" db:"commission"`
+	Bonus      float64 `json:"bonus" db:"bonus"`
+	NetAmount  float64 `json:"net_amount" db:"net_amount"`
+}
+
+// add прибавляет суммы других начислений в той же валюте
+func (a *EarningAmounts) add(other *EarningAmounts) {
+	a.Entries += other.Entries
+	a.Trips += other.Trips
+	a.Fare += other.Fare
+	a.Commission += other.Commission
+	a.Bonus += other.Bonus
+	a.NetAmount += other.NetAmount
+}
+
+// AddEarning прибавляет начисление к суммам
+func (a *EarningAmounts) AddEarning(earning *DriverEarning) {
+	a.Entries++
+	if earning.Type == EarningTypeTrip {
+		a.Trips++
+	}
+	a.Fare += earning.Fare
+	a.Commission += earning.Commission
+	a.Bonus += earning.Bonus
+	a.NetAmount += earning.NetAmount
+}
+
+// EarningsPeriodTotal суммы начислений за период группировки в одной валюте
+type EarningsPeriodTotal struct {
+	PeriodStart time.Time `json:"period_start" db:"period_start"`
+	EarningAmounts
+}
+
+// EarningsPeriod период выплатной ведомости
+type EarningsPeriod struct {
+	Start  time.Time         `json:"start"`
+	End    time.Time         `json:"end"`
+	Totals []*EarningAmounts `json:"totals"`
+}
+
+// EarningsStatement сводка начислений водителя за период для выплатной ведомости
+type EarningsStatement struct {
+	DriverID uuid.UUID        `json:"driver_id"`
+	From     time.Time        `json:"from"`
+	To       time.Time        `json:"to"`
+	GroupBy  EarningsGrouping `json:"group_by"`
+	Timezone string           `json:"timezone"`
+	// Periods периоды с начислениями, ранние первыми
+	Periods []*EarningsPeriod `json:"periods"`
+	// Totals итоги за весь период по валютам
+	Totals []*EarningAmounts `json:"totals"`
+}
+
+// NewEarningsStatement собирает ведомость из сумм по периодам и валютам
+func NewEarningsStatement(driverID uuid.UUID, from, to time.Time, groupBy EarningsGrouping, loc *time.Location, totals []*EarningsPeriodTotal) *EarningsStatement {
+	statement := &EarningsStatement{
+		DriverID: driverID,
+		From:     from,
+		To:       to,
+		GroupBy:  groupBy,
+		Timezone: loc.String(),
+		Periods:  make([]*EarningsPeriod, 0),
+		Totals:   make([]*EarningAmounts, 0),
+	}
+
+	periods := make(map[time.Time]*EarningsPeriod)
+	byCurrency := make(map[string]*EarningAmounts)
+	for _, total := range totals {
+		start := total.PeriodStart.In(loc)
+		period, ok := periods[start]
+		if !ok {
+			period = &EarningsPeriod{Start: start, End: groupBy.PeriodEnd(start)}
+			periods[start] = period
+			statement.Periods = append(statement.Periods, period)
+		}
+		amounts := total.EarningAmounts
+		period.Totals = append(period.Totals, &amounts)
+
+		sum, ok := byCurrency[total.Currency]
+		if !ok {
+			sum = &EarningAmounts{Currency: total.Currency}
+			byCurrency[total.Currency] = sum
+			statement.Totals = append(statement.Totals, sum)
+		}
+		sum.add(&total.EarningAmounts)
+	}
+
+	sort.Slice(statement.Periods, func(i, j int) bool {
+		return statement.Periods[i].Start.Before(statement.Periods[j].Start)
+	})
+	for _, period := range statement.Periods {
+		sortEarningAmounts(period.Totals)
+	}
+	sortEarningAmounts(statement.Totals)
+	return statement
+}
+
+// sortEarningAmounts упорядочивает суммы по валюте
+func sortEarningAmounts(amounts []*EarningAmounts) {
+	sort.Slice(amounts, func(i, j int) bool {
+		return amounts[i].Currency < amounts[j].Currency
+	})
+}
@@ -0,0 +1,100 @@
+package entities
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrorKind категория доменной ошибки. По категории интерфейсы выбирают код ответа:
+// HTTP-статус, код gRPC
+type ErrorKind string
+
+const (
+	// ErrorKindValidation неверные входные данные
+	ErrorKindValidation ErrorKind = "validation"
+	// ErrorKindNotFound сущность не найдена
+	ErrorKindNotFound ErrorKind = "not_found"
+	// ErrorKindConflict сущность уже существует или изменена параллельно
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindInvalidTransition операция недопустима в текущем состоянии сущности:
+	// переход статуса, действие над закрытой сменой или кампанией
+	ErrorKindInvalidTransition ErrorKind = "invalid_transition"
+	// ErrorKindPrecondition данные корректны, но не выполнено бизнес-условие операции
+	ErrorKindPrecondition ErrorKind = "precondition_failed"
+	// ErrorKindUnauthorized вызывающий не аутентифицирован
+	ErrorKindUnauthorized ErrorKind = "unauthorized"
+	// ErrorKindForbidden операция запрещена вызывающему или водителю
+	ErrorKindForbidden ErrorKind = "forbidden"
+	// ErrorKindRateLimited превышен лимит запросов
+	ErrorKindRateLimited ErrorKind = "rate_limited"
+	// ErrorKindUnavailable временная недоступность; запрос можно повторить
+	ErrorKindUnavailable ErrorKind = "unavailable"
+)
+
+// Ошибки категорий: errors.Is(err, ErrConflict) истинно для любой доменной ошибки
+// этой категории, в том числе обернутой через fmt.Errorf("%w")
+var (
+	ErrValidation         error = kindError(ErrorKindValidation)
+	ErrNotFound           error = kindError(ErrorKindNotFound)
+	ErrConflict           error = kindError(ErrorKindConflict)
+	ErrInvalidTransition  error = kindError(ErrorKindInvalidTransition)
+	ErrPreconditionFailed error = kindError(ErrorKindPrecondition)
+	ErrUnauthenticated    error = kindError(ErrorKindUnauthorized)
+	ErrForbidden          error = kindError(ErrorKindForbidden)
+	ErrRateLimited        error = kindError(ErrorKindRateLimited)
+	ErrUnavailable        error = kindError(ErrorKindUnavailable)
+)
+
+// kindError ошибка категории для сравнения через errors.Is
+type kindError ErrorKind
+
+func (e kindError) Error() string {
+	return string(e)
+}
+
+// DomainError доменная ошибка с категорией и машиночитаемым кодом
+type DomainError struct {
+	Kind    ErrorKind `json:"kind"`
+	Code    string    `json:"code"`
+	Message string    `json:"message"`
+}
+
+// domainErrors все доменные ошибки пакета в порядке объявления
+var domainErrors []*DomainError
+
+// newDomainError объявляет доменную ошибку и добавляет ее в каталог
+func newDomainError(kind ErrorKind, code, message string) error {
+	err := &DomainError{Kind: kind, Code: code, Message: message}
+	domainErrors = append(domainErrors, err)
+	return err
+}
+
+// Error возвращает сообщение ошибки
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+// Is сопоставляет ошибку с ошибкой ее категории
+func (e *DomainError) Is(target error) bool {
+	kind, ok := target.(kindError)
+	return ok && ErrorKind(kind) == e.Kind
+}
+
+// AsDomainError находит доменную ошибку в цепочке err
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
+
+// ErrorCatalog возвращает все доменные ошибки, упорядоченные по коду
+func ErrorCatalog() []DomainError {
+	catalog := make([]DomainError, 0, len(domainErrors))
+	for _, err := range domainErrors {
+		catalog = append(catalog, *err)
+	}
+	sort.Slice(catalog, func(i, j int) bool { return catalog[i].Code < catalog[j].Code })
+	return catalog
+}
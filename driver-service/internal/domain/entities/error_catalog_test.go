@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	catalog := ErrorCatalog()
+	require.NotEmpty(t, catalog)
+
+	kinds := map[ErrorKind]bool{
+		ErrorKindValidation: true, ErrorKindNotFound: true, ErrorKindConflict: true,
+		ErrorKindInvalidTransition: true, ErrorKindPrecondition: true, ErrorKindUnauthorized: true,
+		ErrorKindForbidden: true, ErrorKindRateLimited: true, ErrorKindUnavailable: true,
+	}
+	codes := make(map[string]bool, len(catalog))
+	for _, err := range catalog {
+		assert.NotEmpty(t, err.Message)
+		assert.True(t, kinds[err.Kind], "unknown kind %q of %s", err.Kind, err.Code)
+		assert.False(t, codes[err.Code], "duplicate code %s", err.Code)
+		codes[err.Code] = true
+	}
+}
+
+func TestDomainError_Is(t *testing.T) {
+	err := fmt.Errorf("%w from registered to available", ErrInvalidStatusTransition)
+
+	assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+	assert.NotErrorIs(t, err, ErrValidation)
+	assert.ErrorIs(t, fmt.Errorf("failed to create driver: %w", ErrDriverExists), ErrConflict)
+	assert.False(t, errors.Is(ErrDriverNotFound, ErrDriverExists), "errors of one package stay distinct")
+
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, "INVALID_STATUS_TRANSITION", domainErr.Code)
+	assert.Equal(t, "invalid status transition from registered to available", err.Error())
+
+	_, ok = AsDomainError(errors.New("connection refused"))
+	assert.False(t, ok)
+}
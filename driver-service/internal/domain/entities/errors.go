@@ -1,57 +1,329 @@
 package entities
 
-import "errors"
-
-// Domain errors for Driver entity
+// Domain errors for Driver entity. Каждая ошибка несет категорию (ErrorKind) и машиночитаемый
+// код; ответы интерфейсов строятся по ним, см. error_catalog.go
 var (
 	// Driver errors
-	ErrDriverNotFound    = errors.New("driver not found")
-	ErrDriverExists      = errors.New("driver already exists")
-	ErrInvalidPhone      = errors.New("invalid phone number")
-	ErrInvalidEmail      = errors.New("invalid email address")
-	ErrInvalidName       = errors.New("invalid name")
-	ErrInvalidLicense    = errors.New("invalid license number")
-	ErrInvalidPassport   = errors.New("invalid passport data")
-	ErrInvalidStatus     = errors.New("invalid driver status")
-	ErrInvalidDriverID   = errors.New("invalid driver ID")
+	ErrDriverNotFound   = newDomainError(ErrorKindNotFound, "DRIVER_NOT_FOUND", "driver not found")
+	ErrDriverExists     = newDomainError(ErrorKindConflict, "DRIVER_EXISTS", "driver already exists")
+	ErrInvalidPhone     = newDomainError(ErrorKindValidation, "INVALID_PHONE", "invalid phone number")
+	ErrInvalidEmail     = newDomainError(ErrorKindValidation, "INVALID_EMAIL", "invalid email address")
+	ErrInvalidName      = newDomainError(ErrorKindValidation, "INVALID_NAME", "invalid name")
+	ErrInvalidLicense   = newDomainError(ErrorKindValidation, "INVALID_LICENSE", "invalid license number")
+	ErrInvalidPassport  = newDomainError(ErrorKindValidation, "INVALID_PASSPORT", "invalid passport data")
+	ErrInvalidStatus    = newDomainError(ErrorKindValidation, "INVALID_STATUS", "invalid driver status")
+	ErrInvalidDriverID  = newDomainError(ErrorKindValidation, "INVALID_DRIVER_ID", "invalid driver ID")
+	ErrInvalidBirthDate = newDomainError(ErrorKindValidation, "INVALID_BIRTH_DATE", "invalid birth date")
+	// ErrInvalidStatusTransition правила не допускают переход из текущего статуса в запрошенный
+	ErrInvalidStatusTransition = newDomainError(ErrorKindInvalidTransition, "INVALID_STATUS_TRANSITION", "invalid status transition")
+	// ErrInvalidStatusOverride ручная смена статуса без причины, автора или с неизвестным статусом
+	ErrInvalidStatusOverride = newDomainError(ErrorKindValidation, "INVALID_STATUS_OVERRIDE", "invalid status override")
+	// ErrStatusUnchanged водитель уже в запрошенном статусе
+	ErrStatusUnchanged = newDomainError(ErrorKindConflict, "STATUS_UNCHANGED", "driver already has this status")
+	// ErrInvalidBlock блокировка без известной причины, автора или со сроком в прошлом
+	ErrInvalidBlock = newDomainError(ErrorKindValidation, "INVALID_BLOCK", "invalid driver block")
+	// ErrDriverAlreadyBlocked у водителя уже есть активная блокировка
+	ErrDriverAlreadyBlocked = newDomainError(ErrorKindConflict, "DRIVER_ALREADY_BLOCKED", "driver is already blocked")
+	// ErrDriverNotBlocked у водителя нет активной блокировки
+	ErrDriverNotBlocked = newDomainError(ErrorKindInvalidTransition, "DRIVER_NOT_BLOCKED", "driver is not blocked")
+	// ErrBlockNotFound блокировка не найдена или уже снята
+	ErrBlockNotFound = newDomainError(ErrorKindNotFound, "BLOCK_NOT_FOUND", "driver block not found")
+	// ErrBlockRequiresReason перевод в blocked сменой статуса: блокировка выполняется отдельной
+	// операцией с кодом причины
+	ErrBlockRequiresReason = newDomainError(ErrorKindValidation, "BLOCK_REQUIRES_REASON", "driver can only be blocked with a reason code")
+	// ErrInvalidBulkStatus массовая смена статуса без водителей, со слишком длинным списком или
+	// неизвестным статусом
+	ErrInvalidBulkStatus = newDomainError(ErrorKindValidation, "INVALID_BULK_STATUS", "invalid bulk status change")
+	// ErrStatusConflict статус водителя изменился после проверки перехода
+	ErrStatusConflict = newDomainError(ErrorKindConflict, "STATUS_CONFLICT", "driver status changed concurrently")
+	// ErrInvalidStatusHistoryRange начало периода истории статусов не раньше его конца
+	ErrInvalidStatusHistoryRange = newDomainError(ErrorKindValidation, "INVALID_STATUS_HISTORY_RANGE", "invalid status history time range")
+	// ErrInvalidPatch тело частичного обновления или значение поля имеет неверный формат
+	ErrInvalidPatch = newDomainError(ErrorKindValidation, "INVALID_PATCH", "invalid driver patch")
+	// ErrProtectedField поле нельзя изменить частичным обновлением
+	ErrProtectedField = newDomainError(ErrorKindValidation, "PROTECTED_FIELD", "field is protected from update")
+	// ErrUnknownField поле не относится к профилю водителя
+	ErrUnknownField = newDomainError(ErrorKindValidation, "UNKNOWN_FIELD", "unknown driver field")
 
 	// Document errors
-	ErrDocumentNotFound      = errors.New("document not found")
-	ErrDocumentExists        = errors.New("document already exists")
-	ErrInvalidDocumentType   = errors.New("invalid document type")
-	ErrInvalidDocumentNumber = errors.New("invalid document number")
-	ErrInvalidFileURL        = errors.New("invalid file URL")
-	ErrInvalidExpiryDate     = errors.New("invalid expiry date")
-	ErrDocumentExpired       = errors.New("document expired")
-	ErrDocumentNotVerified   = errors.New("document not verified")
+	ErrDocumentNotFound      = newDomainError(ErrorKindNotFound, "DOCUMENT_NOT_FOUND", "document not found")
+	ErrDocumentExists        = newDomainError(ErrorKindConflict, "DOCUMENT_EXISTS", "document already exists")
+	ErrInvalidDocumentType   = newDomainError(ErrorKindValidation, "INVALID_DOCUMENT_TYPE", "invalid document type")
+	ErrInvalidDocumentNumber = newDomainError(ErrorKindValidation, "INVALID_DOCUMENT_NUMBER", "invalid document number")
+	ErrInvalidFileURL        = newDomainError(ErrorKindValidation, "INVALID_FILE_U_R_L", "invalid file URL")
+	ErrInvalidExpiryDate     = newDomainError(ErrorKindValidation, "INVALID_EXPIRY_DATE", "invalid expiry date")
+	ErrDocumentExpired       = newDomainError(ErrorKindPrecondition, "DOCUMENT_EXPIRED", "document expired")
+	ErrDocumentNotVerified   = newDomainError(ErrorKindPrecondition, "DOCUMENT_NOT_VERIFIED", "document not verified")
+	ErrDocumentClaimNotHeld  = newDomainError(ErrorKindInvalidTransition, "DOCUMENT_CLAIM_NOT_HELD", "document is not claimed by this verifier")
+	ErrInvalidDecision       = newDomainError(ErrorKindValidation, "INVALID_DECISION", "invalid verification decision")
+	ErrInvalidDecisionBatch  = newDomainError(ErrorKindValidation, "INVALID_DECISION_BATCH", "invalid verification decision batch")
+	ErrInvalidVerifierID     = newDomainError(ErrorKindValidation, "INVALID_VERIFIER_ID", "invalid verifier ID")
+	ErrDocumentNotRenewable  = newDomainError(ErrorKindInvalidTransition, "DOCUMENT_NOT_RENEWABLE", "document cannot be renewed")
+	ErrRenewalAlreadyPending = newDomainError(ErrorKindConflict, "RENEWAL_ALREADY_PENDING", "document renewal already pending")
+	ErrInvalidDocumentFile   = newDomainError(ErrorKindValidation, "INVALID_DOCUMENT_FILE", "invalid document file")
+	// ErrDocumentNotResubmittable заново загрузить можно только отклоненный документ
+	ErrDocumentNotResubmittable = newDomainError(ErrorKindInvalidTransition, "DOCUMENT_NOT_RESUBMITTABLE", "document cannot be resubmitted")
+	// ErrLicenseRegistryRejected внешний реестр не подтвердил водительское удостоверение
+	ErrLicenseRegistryRejected = newDomainError(ErrorKindPrecondition, "LICENSE_REGISTRY_REJECTED", "driver license rejected by registry")
+	// ErrLicenseRegistryUnavailable внешний реестр удостоверений не ответил
+	ErrLicenseRegistryUnavailable = newDomainError(ErrorKindUnavailable, "LICENSE_REGISTRY_UNAVAILABLE", "license registry is unavailable")
 
 	// Location errors
-	ErrLocationNotFound  = errors.New("location not found")
-	ErrInvalidLocation   = errors.New("invalid location coordinates")
-	ErrInvalidTimestamp  = errors.New("invalid timestamp")
-	ErrLocationTooOld    = errors.New("location data is too old")
+	ErrLocationNotFound        = newDomainError(ErrorKindNotFound, "LOCATION_NOT_FOUND", "location not found")
+	ErrInvalidLocation         = newDomainError(ErrorKindValidation, "INVALID_LOCATION", "invalid location coordinates")
+	ErrInvalidTimestamp        = newDomainError(ErrorKindValidation, "INVALID_TIMESTAMP", "invalid timestamp")
+	ErrLocationTooOld          = newDomainError(ErrorKindNotFound, "LOCATION_TOO_OLD", "location data is too old")
+	ErrInvalidLocationMetadata = newDomainError(ErrorKindValidation, "INVALID_LOCATION_METADATA", "invalid location metadata")
+	ErrInvalidTripRange        = newDomainError(ErrorKindValidation, "INVALID_TRIP_RANGE", "invalid trip time range")
+	ErrRetentionTierNotFound   = newDomainError(ErrorKindValidation, "UNKNOWN_RETENTION_TIER", "location retention tier not found")
+	ErrInvalidSummaryRange     = newDomainError(ErrorKindValidation, "INVALID_SUMMARY_RANGE", "invalid location summary time range")
+	ErrInvalidLocationPrivacy  = newDomainError(ErrorKindValidation, "INVALID_LOCATION_PRIVACY", "invalid location privacy mode")
+	ErrInvalidStatsRange       = newDomainError(ErrorKindValidation, "INVALID_STATS_RANGE", "invalid daily statistics time range")
+	ErrInvalidLocationFilters  = newDomainError(ErrorKindValidation, "INVALID_LOCATION_FILTERS", "invalid location history filters")
+	// ErrLocationIngestionOverloaded буфер асинхронной записи местоположений заполнен
+	ErrLocationIngestionOverloaded = newDomainError(ErrorKindUnavailable, "LOCATION_INGESTION_OVERLOADED", "location ingestion buffer is full")
+
+	// Supply heatmap errors
+	ErrInvalidBoundingBox     = newDomainError(ErrorKindValidation, "INVALID_BBOX", "invalid bounding box")
+	ErrInvalidHeatmapCellSize = newDomainError(ErrorKindValidation, "INVALID_CELL", "invalid heatmap cell precision")
 
 	// Shift errors
-	ErrShiftNotFound     = errors.New("shift not found")
-	ErrShiftExists       = errors.New("active shift already exists")
-	ErrInvalidStartTime  = errors.New("invalid start time")
-	ErrInvalidEndTime    = errors.New("invalid end time")
-	ErrShiftNotActive    = errors.New("shift is not active")
-	ErrShiftAlreadyEnded = errors.New("shift already ended")
+	ErrShiftNotFound     = newDomainError(ErrorKindNotFound, "SHIFT_NOT_FOUND", "shift not found")
+	ErrShiftExists       = newDomainError(ErrorKindConflict, "SHIFT_EXISTS", "active shift already exists")
+	ErrInvalidStartTime  = newDomainError(ErrorKindValidation, "INVALID_START_TIME", "invalid start time")
+	ErrInvalidEndTime    = newDomainError(ErrorKindValidation, "INVALID_END_TIME", "invalid end time")
+	ErrShiftNotActive    = newDomainError(ErrorKindInvalidTransition, "SHIFT_NOT_ACTIVE", "shift is not active")
+	ErrShiftAlreadyEnded = newDomainError(ErrorKindInvalidTransition, "SHIFT_ALREADY_ENDED", "shift already ended")
+	// ErrInvalidShiftBreak перерыв начинается вне смены или заканчивается раньше начала
+	ErrInvalidShiftBreak = newDomainError(ErrorKindValidation, "INVALID_SHIFT_BREAK", "invalid shift break")
+	// ErrShiftBreakActive в смене уже идет перерыв
+	ErrShiftBreakActive = newDomainError(ErrorKindConflict, "SHIFT_BREAK_ACTIVE", "shift break already in progress")
+	// ErrShiftBreakNotActive в смене нет идущего перерыва
+	ErrShiftBreakNotActive = newDomainError(ErrorKindInvalidTransition, "SHIFT_BREAK_NOT_ACTIVE", "no shift break in progress")
+	// ErrShiftBreakOverlap перерыв пересекается с прошлым перерывом смены
+	ErrShiftBreakOverlap = newDomainError(ErrorKindConflict, "SHIFT_BREAK_OVERLAP", "shift break overlaps another break")
 
 	// Rating errors
-	ErrRatingNotFound       = errors.New("rating not found")
-	ErrInvalidRating        = errors.New("invalid rating value")
-	ErrInvalidCriteriaScore = errors.New("invalid criteria score")
-	ErrRatingExists         = errors.New("rating already exists")
+	ErrRatingNotFound       = newDomainError(ErrorKindNotFound, "RATING_NOT_FOUND", "rating not found")
+	ErrInvalidRating        = newDomainError(ErrorKindValidation, "INVALID_RATING", "invalid rating value")
+	ErrInvalidCriteriaScore = newDomainError(ErrorKindValidation, "INVALID_CRITERIA_SCORE", "invalid criteria score")
+	ErrRatingExists         = newDomainError(ErrorKindConflict, "RATING_EXISTS", "rating already exists")
+	ErrInvalidRatingType    = newDomainError(ErrorKindValidation, "INVALID_RATING_TYPE", "invalid rating type")
+	ErrRatingRateLimited    = newDomainError(ErrorKindRateLimited, "RATING_RATE_LIMITED", "too many ratings from customer")
+
+	// Inspection errors
+	ErrInspectionNotFound         = newDomainError(ErrorKindNotFound, "INSPECTION_NOT_FOUND", "inspection not found")
+	ErrInspectionOverdue          = newDomainError(ErrorKindForbidden, "INSPECTION_OVERDUE", "vehicle inspection is overdue")
+	ErrInspectionAlreadyCompleted = newDomainError(ErrorKindConflict, "INSPECTION_COMPLETED", "inspection already completed")
+	ErrInvalidVehicleID           = newDomainError(ErrorKindValidation, "INVALID_VEHICLE_ID", "invalid vehicle ID")
+	ErrInvalidDueDate             = newDomainError(ErrorKindValidation, "INVALID_DUE_DATE", "invalid due date")
+	ErrNotPhotoInspection         = newDomainError(ErrorKindInvalidTransition, "NOT_PHOTO_INSPECTION", "inspection does not accept photos")
+	ErrInvalidPhotoAngle          = newDomainError(ErrorKindValidation, "INVALID_PHOTO_ANGLE", "invalid photo angle")
+	ErrInvalidInspectionPhoto     = newDomainError(ErrorKindValidation, "INVALID_PHOTO", "invalid inspection photo")
+	ErrInspectionPhotosMissing    = newDomainError(ErrorKindPrecondition, "PHOTOS_MISSING", "inspection photos are missing or rejected")
+	ErrInspectionNotSubmitted     = newDomainError(ErrorKindInvalidTransition, "INSPECTION_NOT_SUBMITTED", "inspection is not submitted for review")
+	ErrInspectionNotEditable      = newDomainError(ErrorKindInvalidTransition, "INSPECTION_NOT_EDITABLE", "inspection is not accepting photos")
+	ErrInspectionReviewIncomplete = newDomainError(ErrorKindPrecondition, "REVIEW_INCOMPLETE", "review must cover every photo")
+
+	// Leaderboard errors
+	ErrInvalidLeaderboardMetric     = newDomainError(ErrorKindValidation, "INVALID_METRIC", "invalid leaderboard metric")
+	ErrInvalidLeaderboardVisibility = newDomainError(ErrorKindValidation, "INVALID_VISIBILITY", "invalid leaderboard visibility")
+	ErrLeaderboardOptedOut          = newDomainError(ErrorKindInvalidTransition, "LEADERBOARD_OPTED_OUT", "driver opted out of leaderboards")
+	ErrDriverNotRanked              = newDomainError(ErrorKindNotFound, "DRIVER_NOT_RANKED", "driver is not ranked on this leaderboard")
+
+	// Expense errors
+	ErrExpenseNotFound        = newDomainError(ErrorKindNotFound, "EXPENSE_NOT_FOUND", "expense not found")
+	ErrInvalidExpenseCategory = newDomainError(ErrorKindValidation, "INVALID_EXPENSE_CATEGORY", "invalid expense category")
+	ErrInvalidExpenseAmount   = newDomainError(ErrorKindValidation, "INVALID_EXPENSE_AMOUNT", "invalid expense amount")
+	ErrInvalidCurrency        = newDomainError(ErrorKindValidation, "INVALID_CURRENCY", "invalid currency")
+	ErrExpenseLimitExceeded   = newDomainError(ErrorKindPrecondition, "EXPENSE_LIMIT_EXCEEDED", "expense limit exceeded")
+	ErrExpenseWindowClosed    = newDomainError(ErrorKindInvalidTransition, "EXPENSE_WINDOW_CLOSED", "shift is closed for expenses")
+	ErrInvalidReceipt         = newDomainError(ErrorKindValidation, "INVALID_RECEIPT", "invalid receipt file")
+
+	// Earning errors
+	ErrInvalidEarning = newDomainError(ErrorKindValidation, "INVALID_EARNING", "invalid earning")
+	ErrEarningExists  = newDomainError(ErrorKindConflict, "EARNING_EXISTS", "earning for this order already exists")
+
+	// Dispatch errors
+	ErrInvalidDispatchOffer     = newDomainError(ErrorKindValidation, "INVALID_DISPATCH_OFFER", "invalid dispatch offer")
+	ErrDispatchOfferNotAccepted = newDomainError(ErrorKindPrecondition, "DISPATCH_OFFER_NOT_ACCEPTED", "driver has not accepted this order")
+
+	// Capacity errors
+	ErrCapacityReportNotFound = newDomainError(ErrorKindNotFound, "CAPACITY_REPORT_NOT_FOUND", "capacity report not found")
+
+	// Sharding errors
+	ErrShardNotFound   = newDomainError(ErrorKindNotFound, "SHARD_NOT_FOUND", "shard not found")
+	ErrCityRebalancing = newDomainError(ErrorKindUnavailable, "CITY_REBALANCING", "city is being moved to another shard")
+	ErrShardUnchanged  = newDomainError(ErrorKindConflict, "SHARD_UNCHANGED", "city is already on this shard")
+
+	// Fleet validation errors
+	ErrFleetValidationRejected    = newDomainError(ErrorKindPrecondition, "FLEET_VALIDATION_REJECTED", "rejected by fleet validation")
+	ErrFleetValidationUnavailable = newDomainError(ErrorKindUnavailable, "FLEET_VALIDATION_UNAVAILABLE", "fleet validation is unavailable")
+
+	// Reverification campaign errors
+	ErrCampaignNotFound  = newDomainError(ErrorKindNotFound, "CAMPAIGN_NOT_FOUND", "reverification campaign not found")
+	ErrCampaignClosed    = newDomainError(ErrorKindInvalidTransition, "CAMPAIGN_CLOSED", "reverification campaign is closed")
+	ErrCampaignNoTargets = newDomainError(ErrorKindPrecondition, "CAMPAIGN_NO_TARGETS", "no verified documents match the campaign segment")
+	ErrInvalidCampaign   = newDomainError(ErrorKindValidation, "INVALID_CAMPAIGN", "invalid reverification campaign")
+
+	// Audit chain errors
+	ErrAuditChainDisabled  = newDomainError(ErrorKindInvalidTransition, "AUDIT_CHAIN_DISABLED", "audit hash chain is disabled")
+	ErrAuditEntryNotFound  = newDomainError(ErrorKindNotFound, "AUDIT_ENTRY_NOT_FOUND", "audit entry not found")
+	ErrAuditAnchorNotFound = newDomainError(ErrorKindNotFound, "AUDIT_ANCHOR_NOT_FOUND", "audit anchor not found")
+	ErrInvalidAuditRange   = newDomainError(ErrorKindValidation, "INVALID_AUDIT_RANGE", "invalid audit chain range")
+	ErrAuditChainBroken    = newDomainError(ErrorKindPrecondition, "AUDIT_CHAIN_BROKEN", "audit hash chain is broken")
+	ErrInvalidAuditFilters = newDomainError(ErrorKindValidation, "INVALID_AUDIT_FILTERS", "invalid audit filters")
+
+	// Personal data errors
+	ErrErasureBlocked = newDomainError(ErrorKindInvalidTransition, "ERASURE_BLOCKED", "personal data cannot be erased while the driver is on shift or on order")
+
+	// Schedule errors
+	ErrScheduleNotFound = newDomainError(ErrorKindNotFound, "SCHEDULE_NOT_FOUND", "driver schedule not found")
+	ErrInvalidSchedule  = newDomainError(ErrorKindValidation, "INVALID_SCHEDULE", "invalid driver schedule")
+
+	// Message errors
+	ErrMessageNotFound       = newDomainError(ErrorKindNotFound, "MESSAGE_NOT_FOUND", "message not found")
+	ErrInvalidMessage        = newDomainError(ErrorKindValidation, "INVALID_MESSAGE", "invalid message")
+	ErrInvalidMessageReceipt = newDomainError(ErrorKindValidation, "INVALID_MESSAGE_RECEIPT", "invalid message receipt")
+
+	// Geofence errors
+	ErrGeofenceNotFound = newDomainError(ErrorKindNotFound, "GEOFENCE_NOT_FOUND", "geofence not found")
+	ErrInvalidGeofence  = newDomainError(ErrorKindValidation, "INVALID_GEOFENCE", "invalid geofence")
+
+	// City zoning errors
+	ErrRegionNotFound = newDomainError(ErrorKindNotFound, "REGION_NOT_FOUND", "region not found")
+	ErrInvalidRegion  = newDomainError(ErrorKindValidation, "INVALID_REGION", "invalid region")
+	ErrRegionExists   = newDomainError(ErrorKindConflict, "REGION_EXISTS", "region with this code already exists")
+	ErrCityNotFound   = newDomainError(ErrorKindNotFound, "CITY_NOT_FOUND", "city not found")
+	ErrInvalidCity    = newDomainError(ErrorKindValidation, "INVALID_CITY", "invalid city")
+	ErrCityExists     = newDomainError(ErrorKindConflict, "CITY_EXISTS", "city with this key already exists")
+
+	// Feature flag errors
+	ErrFeatureFlagNotFound = newDomainError(ErrorKindNotFound, "FEATURE_FLAG_NOT_FOUND", "feature flag not found")
+	ErrInvalidFeatureFlag  = newDomainError(ErrorKindValidation, "INVALID_FEATURE_FLAG", "invalid feature flag")
+	ErrFeatureFlagExists   = newDomainError(ErrorKindConflict, "FEATURE_FLAG_EXISTS", "feature flag with this key already exists")
+	// ErrInvalidAppVersion версия приложения не состоит из чисел через точку
+	ErrInvalidAppVersion = newDomainError(ErrorKindValidation, "INVALID_APP_VERSION", "invalid app version")
+
+	// Location export errors
+	ErrLocationExportNotFound = newDomainError(ErrorKindNotFound, "LOCATION_EXPORT_NOT_FOUND", "location export not found")
+	ErrInvalidLocationExport  = newDomainError(ErrorKindValidation, "INVALID_LOCATION_EXPORT", "invalid location export request")
+	ErrLocationExportNotReady = newDomainError(ErrorKindPrecondition, "LOCATION_EXPORT_NOT_READY", "location export is not completed yet")
+	ErrLocationExportExpired  = newDomainError(ErrorKindPrecondition, "LOCATION_EXPORT_EXPIRED", "location export file has expired")
+	// ErrLocationExportQueueFull очередь выгрузок заполнена; запрос можно повторить позже
+	ErrLocationExportQueueFull = newDomainError(ErrorKindUnavailable, "LOCATION_EXPORT_QUEUE_FULL", "location export queue is full")
+
+	// Location analytics errors
+	ErrInvalidAnalyticsRange = newDomainError(ErrorKindValidation, "INVALID_ANALYTICS_RANGE", "invalid location analytics time range")
+	// ErrLocationAnalyticsDisabled аналитическое хранилище местоположений не настроено
+	ErrLocationAnalyticsDisabled = newDomainError(ErrorKindUnavailable, "LOCATION_ANALYTICS_DISABLED", "location analytics is disabled")
+
+	// Fleet errors
+	ErrFleetNotFound   = newDomainError(ErrorKindNotFound, "FLEET_NOT_FOUND", "fleet not found")
+	ErrInvalidFleet    = newDomainError(ErrorKindValidation, "INVALID_FLEET", "invalid fleet")
+	ErrFleetOutOfScope = newDomainError(ErrorKindForbidden, "FLEET_OUT_OF_SCOPE", "fleet is outside of the caller's scope")
+
+	// Security errors
+	ErrSecurityEventNotFound = newDomainError(ErrorKindNotFound, "SECURITY_EVENT_NOT_FOUND", "security event not found")
+	ErrSecurityEventReviewed = newDomainError(ErrorKindConflict, "SECURITY_EVENT_REVIEWED", "security event is already reviewed")
+	ErrSessionNotFound       = newDomainError(ErrorKindNotFound, "SESSION_NOT_FOUND", "session not found")
+	ErrSessionRevoked        = newDomainError(ErrorKindUnauthorized, "SESSION_REVOKED", "session is revoked")
+
+	// Device errors
+	ErrDeviceNotFound = newDomainError(ErrorKindNotFound, "DEVICE_NOT_FOUND", "driver device not found")
+	ErrInvalidDevice  = newDomainError(ErrorKindValidation, "INVALID_DEVICE", "invalid driver device")
+
+	// API key errors
+	ErrAPIKeyNotFound       = newDomainError(ErrorKindNotFound, "API_KEY_NOT_FOUND", "api key not found")
+	ErrAPIKeyRevoked        = newDomainError(ErrorKindConflict, "API_KEY_REVOKED", "api key is already revoked")
+	ErrInvalidAPIKey        = newDomainError(ErrorKindUnauthorized, "INVALID_API_KEY", "invalid, expired or revoked api key")
+	ErrInvalidAPIKeyRequest = newDomainError(ErrorKindValidation, "INVALID_API_KEY_REQUEST", "invalid api key request")
+
+	// Driver tag errors
+	ErrInvalidDriverTag  = newDomainError(ErrorKindValidation, "INVALID_DRIVER_TAG", "driver tag must contain only letters, digits and dashes")
+	ErrUnknownDriverTag  = newDomainError(ErrorKindValidation, "UNKNOWN_DRIVER_TAG", "driver tag is not in the controlled tags list")
+	ErrTooManyDriverTags = newDomainError(ErrorKindPrecondition, "TOO_MANY_DRIVER_TAGS", "driver has too many tags")
+
+	// Webhook errors
+	ErrWebhookNotFound         = newDomainError(ErrorKindNotFound, "WEBHOOK_NOT_FOUND", "webhook subscription not found")
+	ErrWebhookDeliveryNotFound = newDomainError(ErrorKindNotFound, "WEBHOOK_DELIVERY_NOT_FOUND", "webhook delivery not found")
+	ErrInvalidWebhook          = newDomainError(ErrorKindValidation, "INVALID_WEBHOOK", "invalid webhook subscription")
+	ErrUnknownWebhookEvent     = newDomainError(ErrorKindValidation, "UNKNOWN_WEBHOOK_EVENT", "event type is not in the event catalog")
+
+	// Phone verification errors
+	ErrPhoneAlreadyVerified = newDomainError(ErrorKindConflict, "PHONE_ALREADY_VERIFIED", "driver phone is already verified")
+	// ErrPhoneNotVerified переход требует подтвержденного телефона (phone.verification_required)
+	ErrPhoneNotVerified = newDomainError(ErrorKindPrecondition, "PHONE_NOT_VERIFIED", "driver phone is not verified")
+	// ErrVerificationCodeNotFound код не отправлялся, уже использован или отправлен на прежний номер
+	ErrVerificationCodeNotFound = newDomainError(ErrorKindNotFound, "VERIFICATION_CODE_NOT_FOUND", "no pending verification code")
+	ErrVerificationCodeExpired  = newDomainError(ErrorKindPrecondition, "VERIFICATION_CODE_EXPIRED", "verification code has expired")
+	ErrInvalidVerificationCode  = newDomainError(ErrorKindValidation, "INVALID_VERIFICATION_CODE", "invalid verification code")
+	// ErrVerificationCodeRecentlySent новый код запрошен раньше phone.resend_interval
+	ErrVerificationCodeRecentlySent = newDomainError(ErrorKindRateLimited, "VERIFICATION_CODE_RECENTLY_SENT", "verification code was sent recently")
+	// ErrTooManyVerificationAttempts попытки ввода кода исчерпаны; нужен новый код
+	ErrTooManyVerificationAttempts = newDomainError(ErrorKindRateLimited, "TOO_MANY_VERIFICATION_ATTEMPTS", "too many verification attempts")
+
+	// Driver note errors
+	ErrInvalidDriverNote = newDomainError(ErrorKindValidation, "INVALID_DRIVER_NOTE", "note must be non-empty and at most 4000 characters")
+
+	// Profile change errors
+	ErrProfileChangeNotFound = newDomainError(ErrorKindNotFound, "PROFILE_CHANGE_NOT_FOUND", "profile change request not found")
+	// ErrInvalidProfileChange заявка без изменений, с полем, которое не требует одобрения, или
+	// с недопустимым значением; решение без автора или отклонение без комментария
+	ErrInvalidProfileChange = newDomainError(ErrorKindValidation, "INVALID_PROFILE_CHANGE", "invalid profile change request")
+	// ErrProfileChangePending у водителя уже есть заявка, ожидающая решения
+	ErrProfileChangePending = newDomainError(ErrorKindConflict, "PROFILE_CHANGE_PENDING", "driver already has a pending profile change request")
+	// ErrProfileChangeResolved по заявке уже принято решение
+	ErrProfileChangeResolved = newDomainError(ErrorKindConflict, "PROFILE_CHANGE_RESOLVED", "profile change request has already been resolved")
+	// ErrProfileChangeRequiresApproval водитель меняет заполненное юридически значимое поле
+	// напрямую; такие изменения подаются заявкой
+	ErrProfileChangeRequiresApproval = newDomainError(ErrorKindForbidden, "PROFILE_CHANGE_REQUIRES_APPROVAL", "changes to name, passport and license number require approval")
+
+	// Referral errors
+	ErrReferralNotFound      = newDomainError(ErrorKindNotFound, "REFERRAL_NOT_FOUND", "referral not found")
+	ErrReferralExists        = newDomainError(ErrorKindConflict, "REFERRAL_EXISTS", "driver is already referred")
+	ErrInvalidReferral       = newDomainError(ErrorKindValidation, "INVALID_REFERRAL", "invalid referral")
+	ErrInvalidReferralCode   = newDomainError(ErrorKindValidation, "INVALID_REFERRAL_CODE", "referral code does not belong to the referrer")
+	ErrReferralNotEligible   = newDomainError(ErrorKindPrecondition, "REFERRAL_NOT_ELIGIBLE", "only drivers that have not passed verification can be referred")
+	ErrReferralCodeNotFound  = newDomainError(ErrorKindNotFound, "REFERRAL_CODE_NOT_FOUND", "referral code not found")
+	ErrReferralCodeTaken     = newDomainError(ErrorKindConflict, "REFERRAL_CODE_TAKEN", "referral code is already taken")
+	ErrInvalidReferralPeriod = newDomainError(ErrorKindValidation, "INVALID_REFERRAL_PERIOD", "report period start must be before its end")
+
+	// Driver invite errors
+	ErrDriverInviteNotFound = newDomainError(ErrorKindNotFound, "DRIVER_INVITE_NOT_FOUND", "driver invite not found")
+	// ErrInvalidDriverInvite приглашение без телефона, автопарка, автора или со сроком вне допустимого
+	ErrInvalidDriverInvite = newDomainError(ErrorKindValidation, "INVALID_DRIVER_INVITE", "invite requires a phone, a fleet, a creator and an expiry within the allowed period")
+	// ErrInvalidInviteToken токен ссылки поврежден или подписан другим ключом
+	ErrInvalidInviteToken  = newDomainError(ErrorKindNotFound, "INVALID_INVITE_TOKEN", "invite token is invalid")
+	ErrDriverInviteExpired = newDomainError(ErrorKindPrecondition, "DRIVER_INVITE_EXPIRED", "driver invite has expired")
+	ErrDriverInviteUsed    = newDomainError(ErrorKindConflict, "DRIVER_INVITE_USED", "driver invite has already been used")
+	ErrDriverInviteRevoked = newDomainError(ErrorKindConflict, "DRIVER_INVITE_REVOKED", "driver invite has been revoked")
+	// ErrDriverInvitesDisabled не задан секрет подписи ссылок (invites.secret)
+	ErrDriverInvitesDisabled = newDomainError(ErrorKindUnavailable, "DRIVER_INVITES_DISABLED", "driver invites are disabled")
+
+	// Order tracking errors
+	// ErrInvalidOrderTracking отслеживание без водителя или заказа
+	ErrInvalidOrderTracking = newDomainError(ErrorKindValidation, "INVALID_ORDER_TRACKING", "order tracking requires a driver and an order")
+	// ErrOrderTrackingNotFound заказ водителя не отслеживается
+	ErrOrderTrackingNotFound = newDomainError(ErrorKindNotFound, "ORDER_TRACKING_NOT_FOUND", "order tracking not found")
+	// ErrOrderTrackingExists заказ уже отслеживается
+	ErrOrderTrackingExists = newDomainError(ErrorKindConflict, "ORDER_TRACKING_EXISTS", "order is already tracked")
+	// ErrInvalidTrackingToken токен ссылки отслеживания поврежден или подписан другим ключом
+	ErrInvalidTrackingToken = newDomainError(ErrorKindNotFound, "INVALID_TRACKING_TOKEN", "tracking token is invalid")
+	// ErrOrderTrackingInactive отслеживание остановлено или срок ссылки истек
+	ErrOrderTrackingInactive = newDomainError(ErrorKindPrecondition, "ORDER_TRACKING_INACTIVE", "order tracking is no longer active")
+	// ErrOrderTrackingDisabled не задан секрет подписи ссылок (tracking.secret)
+	ErrOrderTrackingDisabled = newDomainError(ErrorKindUnavailable, "ORDER_TRACKING_DISABLED", "order tracking links are disabled")
 
 	// Business logic errors
-	ErrDriverNotAvailable     = errors.New("driver is not available")
-	ErrDriverBlocked          = errors.New("driver is blocked")
-	ErrDriverSuspended        = errors.New("driver is suspended")
-	ErrLicenseExpired         = errors.New("driver license expired")
-	ErrUnauthorized           = errors.New("unauthorized access")
-	ErrPermissionDenied       = errors.New("permission denied")
-	ErrInvalidOperation       = errors.New("invalid operation")
-	ErrConcurrentModification = errors.New("concurrent modification detected")
-)
\ No newline at end of file
+	ErrDriverNotAvailable     = newDomainError(ErrorKindInvalidTransition, "DRIVER_NOT_AVAILABLE", "driver is not available")
+	ErrDriverBlocked          = newDomainError(ErrorKindForbidden, "DRIVER_BLOCKED", "driver is blocked")
+	ErrDriverSuspended        = newDomainError(ErrorKindForbidden, "DRIVER_SUSPENDED", "driver is suspended")
+	ErrLicenseExpired         = newDomainError(ErrorKindPrecondition, "LICENSE_EXPIRED", "driver license expired")
+	ErrDriverPaymentHold      = newDomainError(ErrorKindInvalidTransition, "DRIVER_PAYMENT_HOLD", "driver is on payment hold")
+	ErrProfileIncomplete      = newDomainError(ErrorKindPrecondition, "PROFILE_INCOMPLETE", "driver profile is incomplete for this stage")
+	ErrUnauthorized           = newDomainError(ErrorKindUnauthorized, "UNAUTHORIZED", "unauthorized access")
+	ErrPermissionDenied       = newDomainError(ErrorKindForbidden, "PERMISSION_DENIED", "permission denied")
+	ErrInvalidOperation       = newDomainError(ErrorKindInvalidTransition, "INVALID_OPERATION", "invalid operation")
+	ErrConcurrentModification = newDomainError(ErrorKindConflict, "CONCURRENT_MODIFICATION", "concurrent modification detected")
+)
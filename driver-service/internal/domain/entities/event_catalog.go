@@ -0,0 +1,78 @@
+package entities
+
+// Типы полей данных событий
+const (
+	EventFieldString    = "string"
+	EventFieldUUID      = "uuid"
+	EventFieldInteger   = "integer"
+	EventFieldNumber    = "number"
+	EventFieldBoolean   = "boolean"
+	EventFieldTimestamp = "timestamp"
+	EventFieldObject    = "object"
+)
+
+// EventField поле данных события
+type EventField struct {
+	Name        string
+	Type        string
+	Description string
+	// Optional поле передается не во всех событиях этого типа
+	Optional bool
+}
+
+// EventSchemaProperty описание поля в JSON Schema
+type EventSchemaProperty struct {
+	Type        string `json:"type"`
+	Format      string `json:"format,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// EventSchema JSON Schema данных события; события без данных имеют тип null
+type EventSchema struct {
+	Type       string                          `json:"type"`
+	Properties map[string]*EventSchemaProperty `json:"properties,omitempty"`
+	Required   []string                        `json:"required,omitempty"`
+}
+
+// NewEventSchema строит JSON Schema по полям события
+func NewEventSchema(fields []EventField) *EventSchema {
+	if len(fields) == 0 {
+		return &EventSchema{Type: "null"}
+	}
+
+	schema := &EventSchema{
+		Type:       EventFieldObject,
+		Properties: make(map[string]*EventSchemaProperty, len(fields)),
+	}
+	for _, field := range fields {
+		property := &EventSchemaProperty{Type: field.Type, Description: field.Description}
+		switch field.Type {
+		case EventFieldUUID:
+			property.Type, property.Format = EventFieldString, "uuid"
+		case EventFieldTimestamp:
+			property.Type, property.Format = EventFieldString, "date-time"
+		}
+		schema.Properties[field.Name] = property
+		if !field.Optional {
+			schema.Required = append(schema.Required, field.Name)
+		}
+	}
+	return schema
+}
+
+// EventDefinition описание события водителя в каталоге для команд-потребителей
+type EventDefinition struct {
+	Name string `json:"name"`
+	// Version версия схемы данных; увеличивается при несовместимом изменении полей
+	Version     int                    `json:"version"`
+	Description string                 `json:"description"`
+	Schema      *EventSchema           `json:"schema"`
+	Sample      map[string]interface{} `json:"sample"`
+}
+
+// EventCatalog каталог событий, публикуемых сервисом. ID водителя передается
+// вместе с каждым событием и в данные не входит
+type EventCatalog struct {
+	Service string             `json:"service"`
+	Events  []*EventDefinition `json:"events"`
+}
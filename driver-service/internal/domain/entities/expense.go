@@ -0,0 +1,172 @@
+package entities
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExpenseCategory категория расхода водителя
+type ExpenseCategory string
+
+const (
+	ExpenseCategoryFuel    ExpenseCategory = "fuel"
+	ExpenseCategoryToll    ExpenseCategory = "toll"
+	ExpenseCategoryWash    ExpenseCategory = "wash"
+	ExpenseCategoryParking ExpenseCategory = "parking"
+	ExpenseCategoryOther   ExpenseCategory = "other"
+)
+
+// IsValid проверяет, известна ли категория расхода
+func (c ExpenseCategory) IsValid() bool {
+	switch c {
+	case ExpenseCategoryFuel, ExpenseCategoryToll, ExpenseCategoryWash, ExpenseCategoryParking, ExpenseCategoryOther:
+		return true
+	}
+	return false
+}
+
+// ShiftExpense расход водителя во время смены (топливо, платные дороги, мойка)
+type ShiftExpense struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	ShiftID     uuid.UUID       `json:"shift_id" db:"shift_id"`
+	DriverID    uuid.UUID       `json:"driver_id" db:"driver_id"`
+	Category    ExpenseCategory `json:"category" db:"category"`
+	Amount      float64         `json:"amount" db:"amount"`
+	Currency    string          `json:"currency" db:"currency"`
+	Description *string         `json:"description,omitempty" db:"description"`
+	ReceiptURL  *string         `json:"receipt_url,omitempty" db:"receipt_url"`
+	IncurredAt  time.Time       `json:"incurred_at" db:"incurred_at"`
+	ExportedAt  *time.Time      `json:"exported_at,omitempty" db:"exported_at"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// Validate проверяет категорию, сумму и валюту расхода
+func (e *ShiftExpense) Validate() error {
+	if e.DriverID == uuid.Nil {
+		return ErrInvalidDriverID
+	}
+
+	if !e.Category.IsValid() {
+		return ErrInvalidExpenseCategory
+	}
+
+	if e.Amount <= 0 {
+		return ErrInvalidExpenseAmount
+	}
+
+	if len(e.Currency) != 3 || strings.ToUpper(e.Currency) != e.Currency {
+		return ErrInvalidCurrency
+	}
+
+	if e.IncurredAt.IsZero() {
+		return ErrInvalidTimestamp
+	}
+
+	return nil
+}
+
+// NewShiftExpense создает расход в рамках смены
+func NewShiftExpense(shift *DriverShift, category ExpenseCategory, amount float64, currency string, incurredAt time.Time) *ShiftExpense {
+	now := time.Now()
+	return &ShiftExpense{
+		ID:         uuid.New(),
+		ShiftID:    shift.ID,
+		DriverID:   shift.DriverID,
+		Category:   category,
+		Amount:     amount,
+		Currency:   strings.ToUpper(currency),
+		IncurredAt: incurredAt,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// ExpenseRequest запрос на добавление расхода
+type ExpenseRequest struct {
+	Category    ExpenseCategory `json:"category" binding:"required"`
+	Amount      float64         `json:"amount" binding:"required,gt=0"`
+	Currency    string          `json:"currency,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	// IncurredAt время расхода, по умолчанию текущее
+	IncurredAt *time.Time `json:"incurred_at,omitempty"`
+}
+
+// ExpenseFilters фильтры для поиска расходов
+type ExpenseFilters struct {
+	DriverID *uuid.UUID        `json:"driver_id,omitempty"`
+	ShiftID  *uuid.UUID        `json:"shift_id,omitempty"`
+	Category []ExpenseCategory `json:"category,omitempty"`
+	From     *time.Time        `json:"from,omitempty"`
+	To       *time.Time        `json:"to,omitempty"`
+	// Exported отбирает уже выгруженные (true) или еще не выгруженные (false) в учетную систему расходы
+	Exported *bool `json:"exported,omitempty"`
+	Limit    int   `json:"limit,omitempty"`
+	Offset   int   `json:"offset,omitempty"`
+}
+
+// ExpenseTotal сумма расходов одной категории в одной валюте
+type ExpenseTotal struct {
+	Category ExpenseCategory `json:"category" db:"category"`
+	Currency string          `json:"currency" db:"currency"`
+	Amount   float64         `json:"amount" db:"amount"`
+	Count    int             `json:"count" db:"count"`
+}
+
+// ExpenseSummary итоги расходов по валютам и категориям
+type ExpenseSummary struct {
+	Count int `json:"count"`
+	// Totals суммы по валютам
+	Totals map[string]float64 `json:"totals"`
+	// ByCategory суммы по категориям, детализация по валютам
+	ByCategory []*ExpenseTotal `json:"by_category"`
+}
+
+// NewExpenseSummary собирает итоги расходов из сумм по категориям
+func NewExpenseSummary(totals []*ExpenseTotal) *ExpenseSummary {
+	summary := &ExpenseSummary{
+		Totals:     make(map[string]float64),
+		ByCategory: make([]*ExpenseTotal, 0, len(totals)),
+	}
+
+	for _, total := range totals {
+		summary.Count += total.Count
+		summary.Totals[total.Currency] += total.Amount
+		summary.ByCategory = append(summary.ByCategory, total)
+	}
+
+	sort.SliceStable(summary.ByCategory, func(i, j int) bool {
+		a, b := summary.ByCategory[i], summary.ByCategory[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Currency < b.Currency
+	})
+	return summary
+}
+
+// ShiftExpenseReport смена вместе с ее расходами и заработком за вычетом расходов
+type ShiftExpenseReport struct {
+	Shift    *ShiftResponse  `json:"shift"`
+	Expenses []*ShiftExpense `json:"expenses"`
+	Summary  *ExpenseSummary `json:"summary"`
+	// NetEarnings заработок за смену минус расходы в валюте заработка
+	NetEarnings float64 `json:"net_earnings"`
+}
+
+// EarningsSummary заработок и расходы водителя за период
+type EarningsSummary struct {
+	DriverID      uuid.UUID       `json:"driver_id"`
+	From          time.Time       `json:"from"`
+	To            time.Time       `json:"to"`
+	Currency      string          `json:"currency"`
+	Shifts        int             `json:"shifts"`
+	Trips         int             `json:"trips"`
+	TotalEarnings float64         `json:"total_earnings"`
+	Expenses      *ExpenseSummary `json:"expenses"`
+	// NetEarnings заработок минус расходы в валюте заработка
+	NetEarnings float64 `json:"net_earnings"`
+}
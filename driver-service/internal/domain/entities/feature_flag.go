@@ -0,0 +1,369 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Ограничения флагов функций
+const (
+	// MaxFeatureFlagKeyLength максимальная длина ключа флага
+	MaxFeatureFlagKeyLength = 64
+	// MaxFeatureFlagDescriptionLength максимальная длина описания флага в символах
+	MaxFeatureFlagDescriptionLength = 500
+	// MaxFeatureFlagRules максимальное число правил флага
+	MaxFeatureFlagRules = 20
+	// MaxAppLocationBatchSize максимальный размер пакета точек, который можно задать приложению
+	MaxAppLocationBatchSize = 1000
+)
+
+// AppParameters рабочие параметры приложения водителя
+type AppParameters struct {
+	// GPSUploadIntervalSeconds как часто приложение отправляет точки местоположения
+	GPSUploadIntervalSeconds int `json:"gps_upload_interval_seconds"`
+	// LocationBatchSize сколько точек приложение отправляет одним пакетом
+	LocationBatchSize int `json:"location_batch_size"`
+	// HeartbeatIntervalSeconds как часто приложение отправляет сигнал присутствия
+	HeartbeatIntervalSeconds int `json:"heartbeat_interval_seconds"`
+}
+
+// AppParameterOverrides параметры приложения, которые меняет включенный флаг; незаданный
+// параметр не меняется. Хранится в JSONB
+type AppParameterOverrides struct {
+	GPSUploadIntervalSeconds *int `json:"gps_upload_interval_seconds,omitempty"`
+	LocationBatchSize        *int `json:"location_batch_size,omitempty"`
+	HeartbeatIntervalSeconds *int `json:"heartbeat_interval_seconds,omitempty"`
+}
+
+// Value реализует driver.Valuer
+func (o AppParameterOverrides) Value() (driver.Value, error) {
+	return json.Marshal(o)
+}
+
+// Scan реализует sql.Scanner
+func (o *AppParameterOverrides) Scan(value interface{}) error {
+	if value == nil {
+		*o = AppParameterOverrides{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into AppParameterOverrides", value)
+	}
+	return json.Unmarshal(bytes, o)
+}
+
+// Apply переносит заданные параметры в params
+func (o AppParameterOverrides) Apply(params *AppParameters) {
+	if o.GPSUploadIntervalSeconds != nil {
+		params.GPSUploadIntervalSeconds = *o.GPSUploadIntervalSeconds
+	}
+	if o.LocationBatchSize != nil {
+		params.LocationBatchSize = *o.LocationBatchSize
+	}
+	if o.HeartbeatIntervalSeconds != nil {
+		params.HeartbeatIntervalSeconds = *o.HeartbeatIntervalSeconds
+	}
+}
+
+// isValid проверяет, что заданные параметры положительны, а пакет не больше MaxAppLocationBatchSize
+func (o AppParameterOverrides) isValid() bool {
+	for _, value := range []*int{o.GPSUploadIntervalSeconds, o.LocationBatchSize, o.HeartbeatIntervalSeconds} {
+		if value != nil && *value <= 0 {
+			return false
+		}
+	}
+	return o.LocationBatchSize == nil || *o.LocationBatchSize <= MaxAppLocationBatchSize
+}
+
+// FeatureFlagRule правило включения флага; все заданные условия должны выполняться
+type FeatureFlagRule struct {
+	// Cities ключи городов водителя (см. Driver.OperatingCity); пусто — любой город
+	Cities []string `json:"cities,omitempty"`
+	// MinAppVersion и MaxAppVersion границы версии приложения включительно; пусто — без границы.
+	// Водитель с неизвестной версией не проходит правило с границей версии
+	MinAppVersion string `json:"min_app_version,omitempty"`
+	MaxAppVersion string `json:"max_app_version,omitempty"`
+	// Percentage доля водителей в процентах (0-100); nil — все водители. Водитель попадает
+	// в одну и ту же долю при каждом запросе, а при увеличении доли остается в ней
+	Percentage *int `json:"percentage,omitempty"`
+}
+
+// FeatureFlagRuleList правила флага, хранимые в JSONB
+type FeatureFlagRuleList []FeatureFlagRule
+
+// Value реализует driver.Valuer
+func (l FeatureFlagRuleList) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]FeatureFlagRule{})
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *FeatureFlagRuleList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into FeatureFlagRuleList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// FeatureFlag флаг функции приложения водителя. Включенный флаг без правил действует на всех
+// водителей, с правилами — на водителей, прошедших хотя бы одно правило. Parameters
+// переопределяют параметры приложения у водителей, для которых флаг включен
+type FeatureFlag struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// Key уникальный ключ флага: строчные латинские буквы, цифры, "_", "-" и "."
+	Key         string                `json:"key" db:"key"`
+	Description string                `json:"description" db:"description"`
+	Enabled     bool                  `json:"enabled" db:"enabled"`
+	Rules       FeatureFlagRuleList   `json:"rules" db:"rules"`
+	Parameters  AppParameterOverrides `json:"parameters" db:"parameters"`
+	CreatedAt   time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// FeatureFlagRequest запрос на создание или замену флага
+type FeatureFlagRequest struct {
+	Key         string                `json:"key" binding:"required"`
+	Description string                `json:"description"`
+	Enabled     bool                  `json:"enabled"`
+	Rules       []FeatureFlagRule     `json:"rules"`
+	Parameters  AppParameterOverrides `json:"parameters"`
+}
+
+// Apply переносит поля запроса во флаг; ключ существующего флага не меняется.
+// Города правил приводятся к виду ключа города
+func (r *FeatureFlagRequest) Apply(flag *FeatureFlag) {
+	if flag.Key == "" {
+		flag.Key = strings.TrimSpace(r.Key)
+	}
+	flag.Description = strings.TrimSpace(r.Description)
+	flag.Enabled = r.Enabled
+	flag.Rules = make(FeatureFlagRuleList, 0, len(r.Rules))
+	for _, rule := range r.Rules {
+		cities := make([]string, 0, len(rule.Cities))
+		for _, city := range rule.Cities {
+			cities = append(cities, NormalizeCity(city))
+		}
+		rule.Cities = cities
+		rule.MinAppVersion = strings.TrimSpace(rule.MinAppVersion)
+		rule.MaxAppVersion = strings.TrimSpace(rule.MaxAppVersion)
+		flag.Rules = append(flag.Rules, rule)
+	}
+	flag.Parameters = r.Parameters
+}
+
+// NewFeatureFlag создает флаг из запроса
+func NewFeatureFlag(req *FeatureFlagRequest) *FeatureFlag {
+	now := time.Now()
+	flag := &FeatureFlag{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(flag)
+	return flag
+}
+
+// Validate проверяет ключ, описание, правила и параметры флага
+func (f *FeatureFlag) Validate() error {
+	if !isValidFeatureFlagKey(f.Key) {
+		return ErrInvalidFeatureFlag
+	}
+	if utf8.RuneCountInString(f.Description) > MaxFeatureFlagDescriptionLength {
+		return ErrInvalidFeatureFlag
+	}
+	if len(f.Rules) > MaxFeatureFlagRules {
+		return ErrInvalidFeatureFlag
+	}
+	for _, rule := range f.Rules {
+		if !rule.isValid() {
+			return ErrInvalidFeatureFlag
+		}
+	}
+	if !f.Parameters.isValid() {
+		return ErrInvalidFeatureFlag
+	}
+	return nil
+}
+
+// isValidFeatureFlagKey проверяет формат ключа флага
+func isValidFeatureFlagKey(key string) bool {
+	if key == "" || len(key) > MaxFeatureFlagKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// isValid проверяет долю, города и границы версии правила
+func (r FeatureFlagRule) isValid() bool {
+	if r.Percentage != nil && (*r.Percentage < 0 || *r.Percentage > 100) {
+		return false
+	}
+	for _, city := range r.Cities {
+		if city == "" || len(city) > MaxCityKeyLength {
+			return false
+		}
+	}
+	if r.MinAppVersion != "" && !IsValidAppVersion(r.MinAppVersion) {
+		return false
+	}
+	if r.MaxAppVersion != "" && !IsValidAppVersion(r.MaxAppVersion) {
+		return false
+	}
+	if r.MinAppVersion != "" && r.MaxAppVersion != "" && CompareAppVersions(r.MinAppVersion, r.MaxAppVersion) > 0 {
+		return false
+	}
+	return true
+}
+
+// FeatureFlagTarget водитель, для которого вычисляются флаги
+type FeatureFlagTarget struct {
+	DriverID uuid.UUID
+	// City ключ города водителя (см. Driver.OperatingCity); пусто — город неизвестен
+	City string
+	// AppVersion версия приложения; пусто — версия неизвестна
+	AppVersion string
+}
+
+// EnabledFor проверяет, включен ли флаг для водителя
+func (f *FeatureFlag) EnabledFor(target FeatureFlagTarget) bool {
+	if !f.Enabled {
+		return false
+	}
+	if len(f.Rules) == 0 {
+		return true
+	}
+	for _, rule := range f.Rules {
+		if f.matches(rule, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches проверяет одно правило флага
+func (f *FeatureFlag) matches(rule FeatureFlagRule, target FeatureFlagTarget) bool {
+	if len(rule.Cities) > 0 && !containsString(rule.Cities, target.City) {
+		return false
+	}
+	if rule.MinAppVersion != "" || rule.MaxAppVersion != "" {
+		if !IsValidAppVersion(target.AppVersion) {
+			return false
+		}
+		if rule.MinAppVersion != "" && CompareAppVersions(target.AppVersion, rule.MinAppVersion) < 0 {
+			return false
+		}
+		if rule.MaxAppVersion != "" && CompareAppVersions(target.AppVersion, rule.MaxAppVersion) > 0 {
+			return false
+		}
+	}
+	if rule.Percentage != nil && f.bucket(target.DriverID) >= *rule.Percentage {
+		return false
+	}
+	return true
+}
+
+// bucket относит водителя к одной из 100 долей. Доля зависит от ключа флага, поэтому
+// разные флаги с одинаковым процентом включаются у разных водителей
+func (f *FeatureFlag) bucket(driverID uuid.UUID) int {
+	h := fnv.New32a()
+	h.Write([]byte(f.Key))
+	h.Write([]byte{':'})
+	h.Write(driverID[:])
+	return int(h.Sum32() % 100)
+}
+
+// containsString проверяет, есть ли value в values
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidAppVersion проверяет, что версия приложения состоит из чисел через точку, например
+// "4.12.1"; суффикс после "-" или "+" ("4.12.1-beta") не учитывается
+func IsValidAppVersion(version string) bool {
+	_, ok := parseAppVersion(version)
+	return ok
+}
+
+// CompareAppVersions сравнивает версии приложения по числам: -1, если a меньше b, 0 — если
+// равны, 1 — если больше. Недостающие числа считаются нулями: "4.12" равна "4.12.0"
+func CompareAppVersions(a, b string) int {
+	left, _ := parseAppVersion(a)
+	right, _ := parseAppVersion(b)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		if l != r {
+			if l < r {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseAppVersion разбирает числа версии приложения
+func parseAppVersion(version string) ([]int, bool) {
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(version, ".")
+	numbers := make([]int, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers, true
+}
+
+// AppConfig конфигурация приложения водителя: флаги функций и рабочие параметры
+type AppConfig struct {
+	DriverID uuid.UUID `json:"driver_id"`
+	// City и AppVersion по которым вычислены флаги; пусто — неизвестны
+	City       string `json:"city,omitempty"`
+	AppVersion string `json:"app_version,omitempty"`
+	// Flags состояние каждого флага для водителя
+	Flags       map[string]bool `json:"flags"`
+	Parameters  AppParameters   `json:"parameters"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareAppVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareAppVersions("4.12", "4.12.0"))
+	assert.Equal(t, 1, CompareAppVersions("4.12.1", "4.9.30"))
+	assert.Equal(t, -1, CompareAppVersions("4.2.0-beta", "4.10"))
+
+	assert.True(t, IsValidAppVersion("4.12.1+build.7"))
+	assert.False(t, IsValidAppVersion("v4.12"))
+	assert.False(t, IsValidAppVersion("4..1"))
+	assert.False(t, IsValidAppVersion(""))
+}
+
+func TestFeatureFlag_EnabledFor(t *testing.T) {
+	flag := NewFeatureFlag(&FeatureFlagRequest{
+		Key:     "new_navigation",
+		Enabled: true,
+		Rules: []FeatureFlagRule{
+			{Cities: []string{" Москва "}, MinAppVersion: "4.10"},
+		},
+	})
+	require.NoError(t, flag.Validate())
+	assert.Equal(t, []string{"москва"}, flag.Rules[0].Cities)
+
+	driverID := uuid.New()
+	assert.True(t, flag.EnabledFor(FeatureFlagTarget{DriverID: driverID, City: "москва", AppVersion: "4.12"}))
+	assert.False(t, flag.EnabledFor(FeatureFlagTarget{DriverID: driverID, City: "москва", AppVersion: "4.9"}))
+	// Неизвестная версия не проходит правило с границей версии
+	assert.False(t, flag.EnabledFor(FeatureFlagTarget{DriverID: driverID, City: "москва"}))
+	assert.False(t, flag.EnabledFor(FeatureFlagTarget{DriverID: driverID, City: "тула", AppVersion: "4.12"}))
+
+	flag.Enabled = false
+	assert.False(t, flag.EnabledFor(FeatureFlagTarget{DriverID: driverID, City: "москва", AppVersion: "4.12"}))
+}
+
+func TestFeatureFlag_PercentageRollout(t *testing.T) {
+	percentage := 30
+	flag := NewFeatureFlag(&FeatureFlagRequest{
+		Key:     "fast_gps",
+		Enabled: true,
+		Rules:   []FeatureFlagRule{{Percentage: &percentage}},
+	})
+	require.NoError(t, flag.Validate())
+
+	enabled := make(map[uuid.UUID]bool)
+	for i := 0; i < 2000; i++ {
+		id := uuid.New()
+		enabled[id] = flag.EnabledFor(FeatureFlagTarget{DriverID: id})
+	}
+	count := 0
+	for _, on := range enabled {
+		if on {
+			count++
+		}
+	}
+	assert.InDelta(t, 600, count, 120)
+
+	// Увеличение доли не выключает флаг у тех, у кого он уже включен
+	percentage = 60
+	flag.Rules[0].Percentage = &percentage
+	for id, on := range enabled {
+		if on {
+			assert.True(t, flag.EnabledFor(FeatureFlagTarget{DriverID: id}))
+		}
+	}
+}
+
+func TestFeatureFlag_Validate(t *testing.T) {
+	negative := -1
+	over := 101
+	tooLarge := MaxAppLocationBatchSize + 1
+	tests := []struct {
+		name string
+		req  FeatureFlagRequest
+	}{
+		{"uppercase key", FeatureFlagRequest{Key: "NewNavigation"}},
+		{"key with spaces", FeatureFlagRequest{Key: "new navigation"}},
+		{"percentage over 100", FeatureFlagRequest{Key: "f", Rules: []FeatureFlagRule{{Percentage: &over}}}},
+		{"invalid min version", FeatureFlagRequest{Key: "f", Rules: []FeatureFlagRule{{MinAppVersion: "latest"}}}},
+		{"min above max", FeatureFlagRequest{Key: "f", Rules: []FeatureFlagRule{{MinAppVersion: "5.0", MaxAppVersion: "4.9"}}}},
+		{"empty city", FeatureFlagRequest{Key: "f", Rules: []FeatureFlagRule{{Cities: []string{" "}}}}},
+		{"negative interval", FeatureFlagRequest{Key: "f", Parameters: AppParameterOverrides{GPSUploadIntervalSeconds: &negative}}},
+		{"batch too large", FeatureFlagRequest{Key: "f", Parameters: AppParameterOverrides{LocationBatchSize: &tooLarge}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, NewFeatureFlag(&tt.req).Validate(), ErrInvalidFeatureFlag)
+		})
+	}
+}
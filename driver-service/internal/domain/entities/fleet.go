@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// MaxFleetNameLength максимальная длина названия автопарка в символах
+const MaxFleetNameLength = 100
+
+// Fleet автопарк (партнер), к которому прикреплены водители
+type Fleet struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Name      string    `json:"name" db:"name"`
+	Metadata  Metadata  `json:"metadata" db:"metadata"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// FleetRequest запрос на создание или замену автопарка
+type FleetRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Metadata Metadata `json:"metadata"`
+}
+
+// Apply переносит поля запроса в автопарк
+func (r *FleetRequest) Apply(fleet *Fleet) {
+	fleet.Name = strings.TrimSpace(r.Name)
+	fleet.Metadata = r.Metadata
+	if fleet.Metadata == nil {
+		fleet.Metadata = make(Metadata)
+	}
+}
+
+// NewFleet создает автопарк по запросу
+func NewFleet(req *FleetRequest) *Fleet {
+	now := time.Now()
+	fleet := &Fleet{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(fleet)
+	return fleet
+}
+
+// Validate проверяет название автопарка
+func (f *Fleet) Validate() error {
+	if f.Name == "" || utf8.RuneCountInString(f.Name) > MaxFleetNameLength {
+		return ErrInvalidFleet
+	}
+	return nil
+}
+
+// FleetFilters фильтр списка автопарков
+type FleetFilters struct {
+	// IDs отбирает автопарки из списка; nil — без ограничения
+	IDs    []uuid.UUID
+	Limit  int
+	Offset int
+}
+
+// fleetScopeKey ключ контекста с автопарками, доступными вызывающему
+type fleetScopeKey struct{}
+
+// WithFleetScope ограничивает запрос водителями и автопарками из fleetIDs: так партнерский
+// кабинет видит только своих водителей. Пустой список не дает доступа ни к одному автопарку
+func WithFleetScope(ctx context.Context, fleetIDs []uuid.UUID) context.Context {
+	return context.WithValue(ctx, fleetScopeKey{}, append([]uuid.UUID{}, fleetIDs...))
+}
+
+// FleetScopeFromContext возвращает автопарки, доступные вызывающему; false — запрос
+// не ограничен автопарками (сотрудники и внутренние вызовы)
+func FleetScopeFromContext(ctx context.Context) ([]uuid.UUID, bool) {
+	fleetIDs, ok := ctx.Value(fleetScopeKey{}).([]uuid.UUID)
+	return fleetIDs, ok
+}
+
+// FleetScopeAllows проверяет, доступен ли автопарк в контексте запроса. Водитель
+// без автопарка доступен только запросам без ограничения
+func FleetScopeAllows(ctx context.Context, fleetID *uuid.UUID) bool {
+	fleetIDs, scoped := FleetScopeFromContext(ctx)
+	if !scoped {
+		return true
+	}
+	if fleetID == nil {
+		return false
+	}
+	for _, id := range fleetIDs {
+		if id == *fleetID {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictFleets сужает фильтр автопарков до доступных в области: пересечение запрошенных
+// и разрешенных. Пустой результат при ограниченной области означает «ни одного автопарка»
+func RestrictFleets(requested, allowed []uuid.UUID) []uuid.UUID {
+	if requested == nil {
+		return append([]uuid.UUID{}, allowed...)
+	}
+	permitted := make(map[uuid.UUID]bool, len(allowed))
+	for _, id := range allowed {
+		permitted[id] = true
+	}
+	result := []uuid.UUID{}
+	for _, id := range requested {
+		if permitted[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
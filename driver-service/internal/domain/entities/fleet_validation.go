@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+)
+
+// FleetValidationAction изменение профиля, проверяемое вебхуком автопарка
+type FleetValidationAction string
+
+const (
+	FleetValidationCreate FleetValidationAction = "create"
+	FleetValidationUpdate FleetValidationAction = "update"
+)
+
+// FleetValidationDecision решение вебхука автопарка
+type FleetValidationDecision string
+
+const (
+	FleetDecisionAllow  FleetValidationDecision = "allow"
+	FleetDecisionReject FleetValidationDecision = "reject"
+)
+
+// Итог проверки изменения вебхуком для журнала аудита
+const (
+	FleetOutcomeAllowed  = "allowed"
+	FleetOutcomeRejected = "rejected"
+	// FleetOutcomeFailedOpen вебхук недоступен, изменение пропущено (fail_open)
+	FleetOutcomeFailedOpen = "failed_open"
+	// FleetOutcomeFailedClosed вебхук недоступен, изменение отклонено
+	FleetOutcomeFailedClosed = "failed_closed"
+)
+
+// FleetValidationRequest запрос к вебхуку автопарка
+type FleetValidationRequest struct {
+	Action  FleetValidationAction `json:"action"`
+	FleetID uuid.UUID             `json:"fleet_id"`
+	Driver  *Driver               `json:"driver"`
+	// Previous профиль до изменения; только для update
+	Previous *Driver `json:"previous,omitempty"`
+}
+
+// FleetValidationResult ответ вебхука автопарка
+type FleetValidationResult struct {
+	Decision FleetValidationDecision `json:"decision"`
+	Reason   string                  `json:"reason,omitempty"`
+	// Enrichment поля, которые автопарк добавляет в метаданные водителя
+	Enrichment map[string]interface{} `json:"enrichment,omitempty"`
+}
+
+// IsValid проверяет решение вебхука
+func (d FleetValidationDecision) IsValid() bool {
+	return d == FleetDecisionAllow || d == FleetDecisionReject
+}
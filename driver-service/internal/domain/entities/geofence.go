@@ -0,0 +1,233 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Ограничения геозон
+const (
+	// MaxGeofenceNameLength максимальная длина названия геозоны в символах
+	MaxGeofenceNameLength = 100
+	// MaxGeofenceRadiusMeters максимальный радиус круглой геозоны
+	MaxGeofenceRadiusMeters = 50000
+	// MaxGeofenceVertices максимальное число вершин многоугольника
+	MaxGeofenceVertices = 500
+)
+
+// GeofenceShape форма геозоны
+type GeofenceShape string
+
+const (
+	// GeofenceShapeCircle круг с центром и радиусом в метрах
+	GeofenceShapeCircle GeofenceShape = "circle"
+	// GeofenceShapePolygon многоугольник; последняя вершина соединяется с первой
+	GeofenceShapePolygon GeofenceShape = "polygon"
+)
+
+// GeoPoint вершина многоугольника
+type GeoPoint struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// isValid проверяет диапазон координат
+func (p GeoPoint) isValid() bool {
+	return p.Latitude >= -90 && p.Latitude <= 90 && p.Longitude >= -180 && p.Longitude <= 180
+}
+
+// GeoPointList вершины многоугольника, хранимые в JSONB
+type GeoPointList []GeoPoint
+
+// Value реализует driver.Valuer; у круглой геозоны вершин нет
+func (l GeoPointList) Value() (driver.Value, error) {
+	if l == nil {
+		return nil, nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *GeoPointList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into GeoPointList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Geofence геозона: круг или многоугольник, при входе в который и выходе из которого
+// публикуются события водителя, например очередь в аэропорту или закрытая территория.
+// Metadata передается в событиях без изменений
+type Geofence struct {
+	ID    uuid.UUID     `json:"id" db:"id"`
+	Name  string        `json:"name" db:"name"`
+	Shape GeofenceShape `json:"shape" db:"shape"`
+	// CenterLatitude, CenterLongitude и RadiusMeters задаются только у круга
+	CenterLatitude  *float64 `json:"center_latitude,omitempty" db:"center_latitude"`
+	CenterLongitude *float64 `json:"center_longitude,omitempty" db:"center_longitude"`
+	RadiusMeters    *float64 `json:"radius_meters,omitempty" db:"radius_meters"`
+	// Polygon задается только у многоугольника
+	Polygon   GeoPointList `json:"polygon,omitempty" db:"polygon"`
+	Metadata  Metadata     `json:"metadata" db:"metadata"`
+	Active    bool         `json:"active" db:"active"`
+	CreatedAt time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// GeofenceRequest запрос на создание или замену геозоны; без active геозона активна
+type GeofenceRequest struct {
+	Name            string        `json:"name" binding:"required"`
+	Shape           GeofenceShape `json:"shape" binding:"required"`
+	CenterLatitude  *float64      `json:"center_latitude"`
+	CenterLongitude *float64      `json:"center_longitude"`
+	RadiusMeters    *float64      `json:"radius_meters"`
+	Polygon         []GeoPoint    `json:"polygon"`
+	Metadata        Metadata      `json:"metadata"`
+	Active          *bool         `json:"active"`
+}
+
+// Apply переносит поля запроса в геозону
+func (r *GeofenceRequest) Apply(geofence *Geofence) {
+	geofence.Name = strings.TrimSpace(r.Name)
+	geofence.Shape = r.Shape
+	geofence.CenterLatitude = r.CenterLatitude
+	geofence.CenterLongitude = r.CenterLongitude
+	geofence.RadiusMeters = r.RadiusMeters
+	geofence.Polygon = nil
+	if len(r.Polygon) > 0 {
+		geofence.Polygon = append(GeoPointList(nil), r.Polygon...)
+	}
+	geofence.Metadata = r.Metadata
+	if geofence.Metadata == nil {
+		geofence.Metadata = make(Metadata)
+	}
+	geofence.Active = r.Active == nil || *r.Active
+}
+
+// NewGeofence создает геозону из запроса
+func NewGeofence(req *GeofenceRequest) *Geofence {
+	now := time.Now()
+	geofence := &Geofence{
+		ID:        uuid.New(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.Apply(geofence)
+	return geofence
+}
+
+// Validate проверяет название и геометрию геозоны
+func (g *Geofence) Validate() error {
+	if g.Name == "" || utf8.RuneCountInString(g.Name) > MaxGeofenceNameLength {
+		return ErrInvalidGeofence
+	}
+
+	switch g.Shape {
+	case GeofenceShapeCircle:
+		if g.CenterLatitude == nil || g.CenterLongitude == nil || g.RadiusMeters == nil || len(g.Polygon) > 0 {
+			return ErrInvalidGeofence
+		}
+		if !(GeoPoint{Latitude: *g.CenterLatitude, Longitude: *g.CenterLongitude}).isValid() {
+			return ErrInvalidGeofence
+		}
+		if *g.RadiusMeters <= 0 || *g.RadiusMeters > MaxGeofenceRadiusMeters {
+			return ErrInvalidGeofence
+		}
+	case GeofenceShapePolygon:
+		if g.CenterLatitude != nil || g.CenterLongitude != nil || g.RadiusMeters != nil {
+			return ErrInvalidGeofence
+		}
+		if !g.Polygon.isValidPolygon(MaxGeofenceVertices) {
+			return ErrInvalidGeofence
+		}
+	default:
+		return ErrInvalidGeofence
+	}
+	return nil
+}
+
+// Contains проверяет, находится ли точка внутри геозоны. Многоугольник рассматривается
+// на плоскости широты и долготы, что достаточно точно для зон в пределах города;
+// зоны через антимеридиан не поддерживаются
+func (g *Geofence) Contains(latitude, longitude float64) bool {
+	switch g.Shape {
+	case GeofenceShapeCircle:
+		if g.CenterLatitude == nil || g.CenterLongitude == nil || g.RadiusMeters == nil {
+			return false
+		}
+		point := &DriverLocation{Latitude: latitude, Longitude: longitude}
+		center := &DriverLocation{Latitude: *g.CenterLatitude, Longitude: *g.CenterLongitude}
+		return point.DistanceTo(center)*1000 <= *g.RadiusMeters
+	case GeofenceShapePolygon:
+		return g.Polygon.PolygonContains(latitude, longitude)
+	}
+	return false
+}
+
+// isValidPolygon проверяет число вершин (не больше maxVertices), их координаты и площадь
+// многоугольника
+func (l GeoPointList) isValidPolygon(maxVertices int) bool {
+	if len(l) < 3 || len(l) > maxVertices {
+		return false
+	}
+	for _, point := range l {
+		if !point.isValid() {
+			return false
+		}
+	}
+	// Вырожденный многоугольник (все вершины на одной прямой) не содержит ни одной точки
+	return l.polygonArea() != 0
+}
+
+// PolygonContains проверяет, лежит ли точка внутри многоугольника, методом трассировки луча
+func (l GeoPointList) PolygonContains(latitude, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(l)-1; i < len(l); j, i = i, i+1 {
+		a, b := l[i], l[j]
+		if (a.Latitude > latitude) != (b.Latitude > latitude) {
+			crossing := (b.Longitude-a.Longitude)*(latitude-a.Latitude)/(b.Latitude-a.Latitude) + a.Longitude
+			if longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// polygonArea удвоенная площадь многоугольника в градусах (формула шнурования)
+func (l GeoPointList) polygonArea() float64 {
+	area := 0.0
+	for i, j := 0, len(l)-1; i < len(l); j, i = i, i+1 {
+		area += l[j].Longitude*l[i].Latitude - l[i].Longitude*l[j].Latitude
+	}
+	if area < 0 {
+		return -area
+	}
+	return area
+}
+
+// GeofencePresence водитель внутри геозоны с момента EnteredAt
+type GeofencePresence struct {
+	GeofenceID uuid.UUID `json:"geofence_id" db:"geofence_id"`
+	DriverID   uuid.UUID `json:"driver_id" db:"driver_id"`
+	EnteredAt  time.Time `json:"entered_at" db:"entered_at"`
+}
+
+// GeofenceFilters фильтр списка геозон
+type GeofenceFilters struct {
+	Active *bool
+	Limit  int
+	Offset int
+}
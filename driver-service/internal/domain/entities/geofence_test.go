@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func float64Ptr(v float64) *float64 { return &v }
+
+// sheremetyevo многоугольник около терминала аэропорта
+var sheremetyevo = []GeoPoint{
+	{Latitude: 55.970, Longitude: 37.405},
+	{Latitude: 55.970, Longitude: 37.425},
+	{Latitude: 55.980, Longitude: 37.425},
+	{Latitude: 55.980, Longitude: 37.405},
+}
+
+func TestGeofence_Validate(t *testing.T) {
+	circle := func() *GeofenceRequest {
+		return &GeofenceRequest{
+			Name:            " Вокзал ",
+			Shape:           GeofenceShapeCircle,
+			CenterLatitude:  float64Ptr(55.7766),
+			CenterLongitude: float64Ptr(37.6550),
+			RadiusMeters:    float64Ptr(300),
+		}
+	}
+	polygon := func() *GeofenceRequest {
+		return &GeofenceRequest{Name: "Шереметьево", Shape: GeofenceShapePolygon, Polygon: append([]GeoPoint(nil), sheremetyevo...)}
+	}
+
+	geofence := NewGeofence(circle())
+	assert.NoError(t, geofence.Validate())
+	assert.Equal(t, "Вокзал", geofence.Name)
+	assert.True(t, geofence.Active, "active by default")
+	assert.NotNil(t, geofence.Metadata)
+	assert.NoError(t, NewGeofence(polygon()).Validate())
+
+	inactive := false
+	req := polygon()
+	req.Active = &inactive
+	assert.False(t, NewGeofence(req).Active)
+
+	cases := map[string]*GeofenceRequest{
+		"empty name":         func() *GeofenceRequest { r := circle(); r.Name = "  "; return r }(),
+		"unknown shape":      func() *GeofenceRequest { r := circle(); r.Shape = "square"; return r }(),
+		"circle no radius":   func() *GeofenceRequest { r := circle(); r.RadiusMeters = nil; return r }(),
+		"circle zero radius": func() *GeofenceRequest { r := circle(); r.RadiusMeters = float64Ptr(0); return r }(),
+		"circle huge radius": func() *GeofenceRequest {
+			r := circle()
+			r.RadiusMeters = float64Ptr(MaxGeofenceRadiusMeters + 1)
+			return r
+		}(),
+		"circle bad center":   func() *GeofenceRequest { r := circle(); r.CenterLatitude = float64Ptr(91); return r }(),
+		"circle with polygon": func() *GeofenceRequest { r := circle(); r.Polygon = sheremetyevo; return r }(),
+		"polygon two points":  func() *GeofenceRequest { r := polygon(); r.Polygon = r.Polygon[:2]; return r }(),
+		"polygon with radius": func() *GeofenceRequest { r := polygon(); r.RadiusMeters = float64Ptr(100); return r }(),
+		"polygon bad vertex":  func() *GeofenceRequest { r := polygon(); r.Polygon[1].Longitude = 181; return r }(),
+		"polygon collinear": func() *GeofenceRequest {
+			r := polygon()
+			r.Polygon = []GeoPoint{{55.0, 37.0}, {55.1, 37.1}, {55.2, 37.2}}
+			return r
+		}(),
+	}
+	for name, req := range cases {
+		assert.Equal(t, ErrInvalidGeofence, NewGeofence(req).Validate(), name)
+	}
+}
+
+func TestGeofence_Contains(t *testing.T) {
+	circle := &Geofence{
+		Shape:           GeofenceShapeCircle,
+		CenterLatitude:  float64Ptr(55.7558),
+		CenterLongitude: float64Ptr(37.6176),
+		RadiusMeters:    float64Ptr(500),
+	}
+	assert.True(t, circle.Contains(55.7558, 37.6176))
+	// 0.004° широты — около 445 м, 0.005° — около 556 м
+	assert.True(t, circle.Contains(55.7598, 37.6176))
+	assert.False(t, circle.Contains(55.7608, 37.6176))
+
+	polygon := &Geofence{Shape: GeofenceShapePolygon, Polygon: sheremetyevo}
+	assert.True(t, polygon.Contains(55.975, 37.415))
+	assert.False(t, polygon.Contains(55.985, 37.415))
+	assert.False(t, polygon.Contains(55.975, 37.430))
+
+	// Невыпуклый многоугольник: выемка в форме буквы П не входит в зону
+	notch := &Geofence{Shape: GeofenceShapePolygon, Polygon: []GeoPoint{
+		{0, 0}, {0, 3}, {3, 3}, {3, 2}, {1, 2}, {1, 1}, {3, 1}, {3, 0},
+	}}
+	assert.True(t, notch.Contains(0.5, 1.5))
+	assert.False(t, notch.Contains(2, 1.5))
+	assert.True(t, notch.Contains(2, 0.5))
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Источники сигнала водителя
+const (
+	// HeartbeatSourceHeartbeat явный сигнал приложения водителя
+	HeartbeatSourceHeartbeat = "heartbeat"
+	// HeartbeatSourceLocation принятая точка местоположения
+	HeartbeatSourceLocation = "location"
+)
+
+// DriverHeartbeat последний сигнал от приложения водителя
+type DriverHeartbeat struct {
+	DriverID   uuid.UUID `json:"driver_id" db:"driver_id"`
+	LastSeenAt time.Time `json:"last_seen_at" db:"last_seen_at"`
+	Source     string    `json:"source" db:"source"`
+}
+
+// NewDriverHeartbeat создает сигнал водителя, полученный сейчас
+func NewDriverHeartbeat(driverID uuid.UUID, source string) *DriverHeartbeat {
+	return &DriverHeartbeat{
+		DriverID:   driverID,
+		LastSeenAt: time.Now(),
+		Source:     source,
+	}
+}
+
+// OfflineDetection итог перевода в неактивные водителей, переставших выходить на связь
+type OfflineDetection struct {
+	Checked     int `json:"checked"`
+	WentOffline int `json:"went_offline"`
+	ShiftsEnded int `json:"shifts_ended"`
+	Failed      int `json:"failed"`
+}
@@ -0,0 +1,225 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InspectionStatus статус техосмотра
+type InspectionStatus string
+
+const (
+	InspectionStatusScheduled InspectionStatus = "scheduled"
+	InspectionStatusSubmitted InspectionStatus = "submitted"
+	InspectionStatusPassed    InspectionStatus = "passed"
+	InspectionStatusFailed    InspectionStatus = "failed"
+	InspectionStatusCancelled InspectionStatus = "cancelled"
+)
+
+// ComplianceState состояние соответствия автомобиля требованиям техосмотра
+type ComplianceState string
+
+const (
+	ComplianceStateCompliant ComplianceState = "compliant"
+	ComplianceStateDueSoon   ComplianceState = "due_soon"
+	ComplianceStateOverdue   ComplianceState = "overdue"
+	// ComplianceStateInReview фотографии отправлены и ожидают проверки
+	ComplianceStateInReview ComplianceState = "in_review"
+)
+
+// VehicleInspection представляет плановый техосмотр автомобиля
+type VehicleInspection struct {
+	ID             uuid.UUID        `json:"id" db:"id"`
+	VehicleID      uuid.UUID        `json:"vehicle_id" db:"vehicle_id"`
+	FleetID        *uuid.UUID       `json:"fleet_id,omitempty" db:"fleet_id"`
+	DriverID       *uuid.UUID       `json:"driver_id,omitempty" db:"driver_id"`
+	InspectionType string           `json:"inspection_type" db:"inspection_type"`
+	DueDate        time.Time        `json:"due_date" db:"due_date"`
+	Status         InspectionStatus `json:"status" db:"status"`
+	CompletedAt    *time.Time       `json:"completed_at,omitempty" db:"completed_at"`
+	InspectedBy    *string          `json:"inspected_by,omitempty" db:"inspected_by"`
+	Notes          *string          `json:"notes,omitempty" db:"notes"`
+	ReminderSentAt *time.Time       `json:"reminder_sent_at,omitempty" db:"reminder_sent_at"`
+	SubmittedAt    *time.Time       `json:"submitted_at,omitempty" db:"submitted_at"`
+	Metadata       Metadata         `json:"metadata" db:"metadata"`
+	CreatedAt      time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at" db:"updated_at"`
+
+	// Photos фотографии автомобиля для фотоосмотра
+	Photos []*InspectionPhoto `json:"photos,omitempty" db:"-"`
+}
+
+// IsPending проверяет, ожидает ли техосмотр проведения
+func (i *VehicleInspection) IsPending() bool {
+	return i.Status == InspectionStatusScheduled
+}
+
+// IsSubmitted проверяет, ожидают ли отправленные фотографии проверки
+func (i *VehicleInspection) IsSubmitted() bool {
+	return i.Status == InspectionStatusSubmitted
+}
+
+// IsOverdue проверяет, просрочен ли техосмотр
+func (i *VehicleInspection) IsOverdue() bool {
+	return i.IsPending() && time.Now().After(i.DueDate)
+}
+
+// IsDueWithin проверяет, наступает ли срок техосмотра в ближайшие days дней
+func (i *VehicleInspection) IsDueWithin(days int) bool {
+	return i.IsPending() && !i.IsOverdue() && i.DueDate.Before(time.Now().AddDate(0, 0, days))
+}
+
+// Complete фиксирует результат техосмотра
+func (i *VehicleInspection) Complete(passed bool, inspectedBy string, notes *string) {
+	now := time.Now()
+	i.Status = InspectionStatusFailed
+	if passed {
+		i.Status = InspectionStatusPassed
+	}
+	i.CompletedAt = &now
+	i.InspectedBy = &inspectedBy
+	i.Notes = notes
+	i.UpdatedAt = now
+}
+
+// Submit отправляет фотографии техосмотра на проверку
+func (i *VehicleInspection) Submit() {
+	now := time.Now()
+	i.Status = InspectionStatusSubmitted
+	i.SubmittedAt = &now
+	i.UpdatedAt = now
+}
+
+// Reopen возвращает техосмотр водителю для повторной отправки фотографий
+func (i *VehicleInspection) Reopen(notes *string) {
+	i.Status = InspectionStatusScheduled
+	i.SubmittedAt = nil
+	i.Notes = notes
+	i.UpdatedAt = time.Now()
+}
+
+// Cancel отменяет запланированный техосмотр
+func (i *VehicleInspection) Cancel() {
+	i.Status = InspectionStatusCancelled
+	i.UpdatedAt = time.Now()
+}
+
+// MarkReminderSent отмечает отправку напоминания
+func (i *VehicleInspection) MarkReminderSent() {
+	now := time.Now()
+	i.ReminderSentAt = &now
+	i.UpdatedAt = now
+}
+
+// Validate проверяет валидность данных техосмотра
+func (i *VehicleInspection) Validate() error {
+	if i.VehicleID == uuid.Nil {
+		return ErrInvalidVehicleID
+	}
+
+	if i.DueDate.IsZero() {
+		return ErrInvalidDueDate
+	}
+
+	return nil
+}
+
+// NewVehicleInspection создает новый запланированный техосмотр
+func NewVehicleInspection(vehicleID uuid.UUID, inspectionType string, dueDate time.Time) *VehicleInspection {
+	now := time.Now()
+	return &VehicleInspection{
+		ID:             uuid.New(),
+		VehicleID:      vehicleID,
+		InspectionType: inspectionType,
+		DueDate:        dueDate,
+		Status:         InspectionStatusScheduled,
+		Metadata:       make(Metadata),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// InspectionFilters фильтры для поиска техосмотров
+type InspectionFilters struct {
+	VehicleID *uuid.UUID         `json:"vehicle_id,omitempty"`
+	FleetID   *uuid.UUID         `json:"fleet_id,omitempty"`
+	DriverID  *uuid.UUID         `json:"driver_id,omitempty"`
+	Status    []InspectionStatus `json:"status,omitempty"`
+	DueBefore *time.Time         `json:"due_before,omitempty"`
+	DueAfter  *time.Time         `json:"due_after,omitempty"`
+	Limit     int                `json:"limit,omitempty"`
+	Offset    int                `json:"offset,omitempty"`
+}
+
+// ScheduleInspectionRequest запрос на планирование техосмотра
+type ScheduleInspectionRequest struct {
+	VehicleID      uuid.UUID  `json:"vehicle_id" binding:"required"`
+	FleetID        *uuid.UUID `json:"fleet_id,omitempty"`
+	DriverID       *uuid.UUID `json:"driver_id,omitempty"`
+	InspectionType string     `json:"inspection_type,omitempty"`
+	DueDate        time.Time  `json:"due_date" binding:"required"`
+	Notes          *string    `json:"notes,omitempty"`
+}
+
+// CompleteInspectionRequest запрос на фиксацию результата техосмотра
+type CompleteInspectionRequest struct {
+	Passed      *bool   `json:"passed" binding:"required"`
+	InspectedBy string  `json:"inspected_by" binding:"required"`
+	Notes       *string `json:"notes,omitempty"`
+}
+
+// ReviewInspectionRequest запрос на проверку фотографий техосмотра.
+// Техосмотр считается пройденным, если приняты все фотографии
+type ReviewInspectionRequest struct {
+	ReviewedBy string          `json:"reviewed_by" binding:"required"`
+	Decisions  []PhotoDecision `json:"decisions" binding:"required,dive"`
+	Notes      *string         `json:"notes,omitempty"`
+}
+
+// PhotoDecision решение по отдельной фотографии
+type PhotoDecision struct {
+	Angle    PhotoAngle `json:"angle" binding:"required"`
+	Accepted *bool      `json:"accepted" binding:"required"`
+	Reason   *string    `json:"reason,omitempty"`
+}
+
+// VehicleCompliance состояние техосмотра конкретного автомобиля
+type VehicleCompliance struct {
+	VehicleID   uuid.UUID       `json:"vehicle_id"`
+	FleetID     *uuid.UUID      `json:"fleet_id,omitempty"`
+	State       ComplianceState `json:"state"`
+	NextDueDate *time.Time      `json:"next_due_date,omitempty"`
+}
+
+// InspectionComplianceReport отчет о соответствии автопарка требованиям техосмотра
+type InspectionComplianceReport struct {
+	FleetID        *uuid.UUID           `json:"fleet_id,omitempty"`
+	TotalVehicles  int                  `json:"total_vehicles"`
+	Compliant      int                  `json:"compliant"`
+	DueSoon        int                  `json:"due_soon"`
+	Overdue        int                  `json:"overdue"`
+	InReview       int                  `json:"in_review"`
+	ComplianceRate float64              `json:"compliance_rate"`
+	Vehicles       []*VehicleCompliance `json:"vehicles"`
+	GeneratedAt    time.Time            `json:"generated_at"`
+}
+
+// VehicleInspectionSummary состояние техосмотров автомобиля
+type VehicleInspectionSummary struct {
+	VehicleID uuid.UUID       `json:"vehicle_id"`
+	State     ComplianceState `json:"state"`
+	// CanOperate false, если начало смены на автомобиле заблокировано просроченным техосмотром
+	CanOperate    bool                 `json:"can_operate"`
+	NextDueDate   *time.Time           `json:"next_due_date,omitempty"`
+	Open          []*VehicleInspection `json:"open"`
+	LastCompleted *VehicleInspection   `json:"last_completed,omitempty"`
+}
+
+// DriverInspectionSummary состояние техосмотров автомобилей водителя.
+// State — наиболее требующее внимания состояние среди автомобилей
+type DriverInspectionSummary struct {
+	DriverID uuid.UUID                   `json:"driver_id"`
+	State    ComplianceState             `json:"state"`
+	Vehicles []*VehicleInspectionSummary `json:"vehicles"`
+}
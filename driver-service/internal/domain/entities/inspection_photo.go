@@ -0,0 +1,91 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PhotoAngle ракурс фотографии автомобиля
+type PhotoAngle string
+
+const (
+	PhotoAngleFront    PhotoAngle = "front"
+	PhotoAngleBack     PhotoAngle = "back"
+	PhotoAngleLeft     PhotoAngle = "left"
+	PhotoAngleRight    PhotoAngle = "right"
+	PhotoAngleInterior PhotoAngle = "interior"
+)
+
+// RequiredPhotoAngles ракурсы, обязательные для фотоосмотра: четыре стороны и салон
+var RequiredPhotoAngles = []PhotoAngle{
+	PhotoAngleFront,
+	PhotoAngleBack,
+	PhotoAngleLeft,
+	PhotoAngleRight,
+	PhotoAngleInterior,
+}
+
+// IsValid проверяет, что ракурс входит в список обязательных
+func (a PhotoAngle) IsValid() bool {
+	for _, angle := range RequiredPhotoAngles {
+		if a == angle {
+			return true
+		}
+	}
+	return false
+}
+
+// PhotoStatus статус проверки фотографии
+type PhotoStatus string
+
+const (
+	PhotoStatusPending  PhotoStatus = "pending"
+	PhotoStatusAccepted PhotoStatus = "accepted"
+	PhotoStatusRejected PhotoStatus = "rejected"
+)
+
+// InspectionPhoto фотография автомобиля в рамках фотоосмотра.
+// На каждый ракурс хранится одна фотография, повторная загрузка заменяет предыдущую
+type InspectionPhoto struct {
+	ID              uuid.UUID   `json:"id" db:"id"`
+	InspectionID    uuid.UUID   `json:"inspection_id" db:"inspection_id"`
+	Angle           PhotoAngle  `json:"angle" db:"angle"`
+	URL             string      `json:"url" db:"url"`
+	ContentType     string      `json:"content_type" db:"content_type"`
+	Size            int64       `json:"size" db:"size"`
+	Status          PhotoStatus `json:"status" db:"status"`
+	RejectionReason *string     `json:"rejection_reason,omitempty" db:"rejection_reason"`
+	UploadedAt      time.Time   `json:"uploaded_at" db:"uploaded_at"`
+	ReviewedAt      *time.Time  `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+// Accept принимает фотографию
+func (p *InspectionPhoto) Accept() {
+	now := time.Now()
+	p.Status = PhotoStatusAccepted
+	p.RejectionReason = nil
+	p.ReviewedAt = &now
+}
+
+// Reject отклоняет фотографию с указанием причины
+func (p *InspectionPhoto) Reject(reason *string) {
+	now := time.Now()
+	p.Status = PhotoStatusRejected
+	p.RejectionReason = reason
+	p.ReviewedAt = &now
+}
+
+// NewInspectionPhoto создает фотографию, ожидающую проверки
+func NewInspectionPhoto(inspectionID uuid.UUID, angle PhotoAngle, url, contentType string, size int64) *InspectionPhoto {
+	return &InspectionPhoto{
+		ID:           uuid.New(),
+		InspectionID: inspectionID,
+		Angle:        angle,
+		URL:          url,
+		ContentType:  contentType,
+		Size:         size,
+		Status:       PhotoStatusPending,
+		UploadedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,68 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobRunTrigger причина запуска фоновой задачи
+type JobRunTrigger string
+
+const (
+	// JobRunScheduled запуск по расписанию
+	JobRunScheduled JobRunTrigger = "schedule"
+	// JobRunManual запуск администратором вне расписания
+	JobRunManual JobRunTrigger = "manual"
+)
+
+// JobRunStatus результат запуска фоновой задачи
+type JobRunStatus string
+
+const (
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun запуск фоновой задачи в истории запусков
+type JobRun struct {
+	ID      uuid.UUID     `json:"id" db:"id"`
+	JobName string        `json:"job_name" db:"job_name"`
+	Trigger JobRunTrigger `json:"trigger" db:"trigger"`
+	// TriggeredBy субъект токена администратора, запустившего задачу вручную
+	TriggeredBy string `json:"triggered_by,omitempty" db:"triggered_by"`
+	// Instance экземпляр сервиса, выполнивший задачу
+	Instance   string       `json:"instance" db:"instance"`
+	Status     JobRunStatus `json:"status" db:"status"`
+	Error      string       `json:"error,omitempty" db:"error"`
+	StartedAt  time.Time    `json:"started_at" db:"started_at"`
+	FinishedAt time.Time    `json:"finished_at" db:"finished_at"`
+	DurationMs int64        `json:"duration_ms" db:"duration_ms"`
+}
+
+// NewJobRun создает запись о завершенном запуске задачи
+func NewJobRun(jobName string, trigger JobRunTrigger, triggeredBy, instance string, startedAt, finishedAt time.Time, err error) *JobRun {
+	run := &JobRun{
+		ID:          uuid.New(),
+		JobName:     jobName,
+		Trigger:     trigger,
+		TriggeredBy: triggeredBy,
+		Instance:    instance,
+		Status:      JobRunSucceeded,
+		StartedAt:   startedAt,
+		FinishedAt:  finishedAt,
+		DurationMs:  finishedAt.Sub(startedAt).Milliseconds(),
+	}
+	if err != nil {
+		run.Status = JobRunFailed
+		run.Error = err.Error()
+	}
+	return run
+}
+
+// JobRunFilters фильтры истории запусков
+type JobRunFilters struct {
+	JobName string
+	Status  JobRunStatus
+	Limit   int
+}
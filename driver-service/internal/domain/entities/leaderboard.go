@@ -0,0 +1,259 @@
+package entities
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// LeaderboardMetric показатель, по которому строится рейтинг водителей
+type LeaderboardMetric string
+
+const (
+	LeaderboardMetricTrips    LeaderboardMetric = "trips"
+	LeaderboardMetricRating   LeaderboardMetric = "rating"
+	LeaderboardMetricEarnings LeaderboardMetric = "earnings"
+)
+
+// IsValid проверяет, известен ли показатель
+func (m LeaderboardMetric) IsValid() bool {
+	switch m {
+	case LeaderboardMetricTrips, LeaderboardMetricRating, LeaderboardMetricEarnings:
+		return true
+	}
+	return false
+}
+
+// LeaderboardVisibility настройка приватности водителя в рейтингах
+type LeaderboardVisibility string
+
+const (
+	// LeaderboardVisibilityPublic водитель отображается по имени
+	LeaderboardVisibilityPublic LeaderboardVisibility = "public"
+	// LeaderboardVisibilityPseudonymous водитель участвует под псевдонимом, без ID
+	LeaderboardVisibilityPseudonymous LeaderboardVisibility = "pseudonymous"
+	// LeaderboardVisibilityHidden водитель отказался от участия в рейтингах
+	LeaderboardVisibilityHidden LeaderboardVisibility = "hidden"
+)
+
+// IsValid проверяет, известна ли настройка приватности
+func (v LeaderboardVisibility) IsValid() bool {
+	switch v {
+	case LeaderboardVisibilityPublic, LeaderboardVisibilityPseudonymous, LeaderboardVisibilityHidden:
+		return true
+	}
+	return false
+}
+
+// Ключи метаданных водителя, используемые рейтингами
+const (
+	DriverMetaCity                  = "city"
+	DriverMetaLeaderboardVisibility = "leaderboard_visibility"
+)
+
+// LeaderboardVisibility возвращает настройку приватности водителя (по умолчанию public)
+func (d *Driver) LeaderboardVisibility() LeaderboardVisibility {
+	if value, ok := d.Metadata[DriverMetaLeaderboardVisibility].(string); ok {
+		if visibility := LeaderboardVisibility(value); visibility.IsValid() {
+			return visibility
+		}
+	}
+	return LeaderboardVisibilityPublic
+}
+
+// City возвращает город водителя из метаданных
+func (d *Driver) City() *string {
+	if value, ok := d.Metadata[DriverMetaCity].(string); ok && value != "" {
+		return &value
+	}
+	return nil
+}
+
+// WeekStart возвращает начало недели (понедельник 00:00 UTC), которой принадлежит момент времени
+func WeekStart(t time.Time) time.Time {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+}
+
+// LeaderboardAggregate недельные показатели водителя вместе с данными для фильтрации и приватности
+type LeaderboardAggregate struct {
+	DriverID      uuid.UUID             `json:"driver_id"`
+	FirstName     string                `json:"first_name"`
+	LastName      string                `json:"last_name"`
+	City          *string               `json:"city,omitempty"`
+	FleetID       *uuid.UUID            `json:"fleet_id,omitempty"`
+	Visibility    LeaderboardVisibility `json:"visibility"`
+	WeekStart     time.Time             `json:"week_start"`
+	Trips         int                   `json:"trips"`
+	Earnings      float64               `json:"earnings"`
+	AverageRating float64               `json:"average_rating"`
+	RatingCount   int                   `json:"rating_count"`
+}
+
+// Value возвращает значение показателя
+func (a *LeaderboardAggregate) Value(metric LeaderboardMetric) float64 {
+	switch metric {
+	case LeaderboardMetricTrips:
+		return float64(a.Trips)
+	case LeaderboardMetricRating:
+		return a.AverageRating
+	case LeaderboardMetricEarnings:
+		return a.Earnings
+	default:
+		return 0
+	}
+}
+
+// DisplayName возвращает имя для публичного рейтинга: имя и инициал фамилии
+// или стабильный псевдоним, если водитель выбрал анонимное участие
+func (a *LeaderboardAggregate) DisplayName() string {
+	if a.Visibility == LeaderboardVisibilityPseudonymous {
+		return LeaderboardPseudonym(a.DriverID)
+	}
+
+	name := a.FirstName
+	if initial, _ := utf8.DecodeRuneInString(a.LastName); initial != utf8.RuneError {
+		name = fmt.Sprintf("%s %c.", name, initial)
+	}
+	return strings.TrimSpace(name)
+}
+
+// LeaderboardPseudonym возвращает стабильный псевдоним водителя, не раскрывающий его ID
+func LeaderboardPseudonym(driverID uuid.UUID) string {
+	sum := sha256.Sum256(driverID[:])
+	return "Водитель #" + strings.ToUpper(hex.EncodeToString(sum[:3]))
+}
+
+// LeaderboardScope область рейтинга: неделя и, опционально, город или автопарк
+type LeaderboardScope struct {
+	Metric    LeaderboardMetric `json:"metric"`
+	WeekStart time.Time         `json:"week_start"`
+	City      *string           `json:"city,omitempty"`
+	FleetID   *uuid.UUID        `json:"fleet_id,omitempty"`
+}
+
+// Includes проверяет, относится ли водитель к области рейтинга
+func (s *LeaderboardScope) Includes(a *LeaderboardAggregate) bool {
+	if s.City != nil && (a.City == nil || !strings.EqualFold(*a.City, *s.City)) {
+		return false
+	}
+	if s.FleetID != nil && (a.FleetID == nil || *a.FleetID != *s.FleetID) {
+		return false
+	}
+	return true
+}
+
+// RankingRules правила ранжирования для показателя
+type RankingRules struct {
+	// TieBreakers показатели, сравниваемые по порядку при равенстве основного
+	TieBreakers []LeaderboardMetric
+	// MinRatings минимальное число оценок за неделю для участия в рейтинге по оценкам
+	MinRatings int
+}
+
+// RankedDriver позиция водителя в рейтинге
+type RankedDriver struct {
+	Rank      int
+	Aggregate *LeaderboardAggregate
+}
+
+// RankDrivers фильтрует водителей по области и приватности и расставляет места.
+// Сортировка по убыванию основного показателя, затем по показателям tie-break;
+// водители, равные по всем показателям, делят место (1, 2, 2, 4)
+func RankDrivers(aggregates []*LeaderboardAggregate, scope *LeaderboardScope, rules RankingRules) []*RankedDriver {
+	eligible := make([]*LeaderboardAggregate, 0, len(aggregates))
+	for _, aggregate := range aggregates {
+		if aggregate.Visibility == LeaderboardVisibilityHidden || !scope.Includes(aggregate) {
+			continue
+		}
+		if scope.Metric == LeaderboardMetricRating && (aggregate.RatingCount == 0 || aggregate.RatingCount < rules.MinRatings) {
+			continue
+		}
+		eligible = append(eligible, aggregate)
+	}
+
+	metrics := append([]LeaderboardMetric{scope.Metric}, rules.TieBreakers...)
+	compare := func(a, b *LeaderboardAggregate) int {
+		for _, metric := range metrics {
+			if c := cmp.Compare(b.Value(metric), a.Value(metric)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+
+	sort.SliceStable(eligible, func(i, j int) bool {
+		if c := compare(eligible[i], eligible[j]); c != 0 {
+			return c < 0
+		}
+		// Детерминированный порядок внутри разделенного места
+		return eligible[i].DriverID.String() < eligible[j].DriverID.String()
+	})
+
+	ranked := make([]*RankedDriver, len(eligible))
+	for i, aggregate := range eligible {
+		rank := i + 1
+		if i > 0 && compare(eligible[i-1], aggregate) == 0 {
+			rank = ranked[i-1].Rank
+		}
+		ranked[i] = &RankedDriver{Rank: rank, Aggregate: aggregate}
+	}
+	return ranked
+}
+
+// LeaderboardEntry публичная строка рейтинга
+type LeaderboardEntry struct {
+	Rank          int        `json:"rank"`
+	DriverID      *uuid.UUID `json:"driver_id,omitempty"`
+	DisplayName   string     `json:"display_name"`
+	Value         float64    `json:"value"`
+	Trips         int        `json:"trips"`
+	Earnings      float64    `json:"earnings"`
+	AverageRating float64    `json:"average_rating"`
+}
+
+// Entry возвращает публичное представление позиции с учетом приватности водителя
+func (r *RankedDriver) Entry(metric LeaderboardMetric) *LeaderboardEntry {
+	entry := &LeaderboardEntry{
+		Rank:          r.Rank,
+		DisplayName:   r.Aggregate.DisplayName(),
+		Value:         r.Aggregate.Value(metric),
+		Trips:         r.Aggregate.Trips,
+		Earnings:      r.Aggregate.Earnings,
+		AverageRating: r.Aggregate.AverageRating,
+	}
+	if r.Aggregate.Visibility == LeaderboardVisibilityPublic {
+		driverID := r.Aggregate.DriverID
+		entry.DriverID = &driverID
+	}
+	return entry
+}
+
+// Leaderboard рейтинг водителей за неделю
+type Leaderboard struct {
+	LeaderboardScope
+	Entries      []*LeaderboardEntry `json:"entries"`
+	Participants int                 `json:"participants"`
+	GeneratedAt  time.Time           `json:"generated_at"`
+}
+
+// DriverRank позиция водителя в рейтинге для самого водителя
+type DriverRank struct {
+	LeaderboardScope
+	DriverID     uuid.UUID             `json:"driver_id"`
+	Rank         int                   `json:"rank"`
+	Participants int                   `json:"participants"`
+	Value        float64               `json:"value"`
+	Visibility   LeaderboardVisibility `json:"visibility"`
+	// GapToNext сколько не хватает до следующего места (0 для первого места)
+	GapToNext   float64   `json:"gap_to_next"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
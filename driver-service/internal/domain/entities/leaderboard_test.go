@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeekStart(t *testing.T) {
+	sunday := time.Date(2024, 3, 17, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), WeekStart(sunday))
+
+	monday := time.Date(2024, 3, 18, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, WeekStart(monday))
+}
+
+func TestRankDrivers_SharedRanks(t *testing.T) {
+	moscow, kazan := "Москва", "Казань"
+	aggregates := []*LeaderboardAggregate{
+		{DriverID: uuid.New(), City: &moscow, Trips: 10, Earnings: 100, Visibility: LeaderboardVisibilityPublic},
+		{DriverID: uuid.New(), City: &moscow, Trips: 10, Earnings: 100, Visibility: LeaderboardVisibilityPublic},
+		{DriverID: uuid.New(), City: &moscow, Trips: 10, Earnings: 50, Visibility: LeaderboardVisibilityPublic},
+		{DriverID: uuid.New(), City: &moscow, Trips: 40, Visibility: LeaderboardVisibilityHidden},
+		{DriverID: uuid.New(), City: &kazan, Trips: 50, Visibility: LeaderboardVisibilityPublic},
+	}
+
+	city := "москва"
+	ranked := RankDrivers(aggregates, &LeaderboardScope{Metric: LeaderboardMetricTrips, City: &city}, RankingRules{
+		TieBreakers: []LeaderboardMetric{LeaderboardMetricEarnings},
+	})
+
+	require.Len(t, ranked, 3)
+	assert.Equal(t, []int{1, 1, 3}, []int{ranked[0].Rank, ranked[1].Rank, ranked[2].Rank})
+	assert.Equal(t, 50.0, ranked[2].Aggregate.Earnings)
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LicenseRegistryStatus состояние водительского удостоверения во внешнем реестре
+type LicenseRegistryStatus string
+
+const (
+	// LicenseRegistryValid удостоверение действует
+	LicenseRegistryValid LicenseRegistryStatus = "valid"
+	// LicenseRegistryNotFound реестр не знает удостоверение с таким номером
+	LicenseRegistryNotFound LicenseRegistryStatus = "not_found"
+	// LicenseRegistryExpired срок действия удостоверения истек
+	LicenseRegistryExpired LicenseRegistryStatus = "expired"
+	// LicenseRegistrySuspended водитель временно лишен права управления
+	LicenseRegistrySuspended LicenseRegistryStatus = "suspended"
+	// LicenseRegistryRevoked удостоверение аннулировано
+	LicenseRegistryRevoked LicenseRegistryStatus = "revoked"
+)
+
+// IsValid проверяет, что состояние относится к известным
+func (s LicenseRegistryStatus) IsValid() bool {
+	switch s {
+	case LicenseRegistryValid, LicenseRegistryNotFound, LicenseRegistryExpired,
+		LicenseRegistrySuspended, LicenseRegistryRevoked:
+		return true
+	default:
+		return false
+	}
+}
+
+// LicenseCheckRequest запрос проверки удостоверения во внешнем реестре
+type LicenseCheckRequest struct {
+	DriverID      uuid.UUID `json:"driver_id"`
+	LicenseNumber string    `json:"license_number"`
+	ExpiryDate    time.Time `json:"expiry_date"`
+}
+
+// NewLicenseCheckRequest создает запрос проверки по документу водительского удостоверения
+func NewLicenseCheckRequest(document *DriverDocument) *LicenseCheckRequest {
+	return &LicenseCheckRequest{
+		DriverID:      document.DriverID,
+		LicenseNumber: NormalizeLicenseNumber(document.DocumentNumber),
+		ExpiryDate:    document.ExpiryDate,
+	}
+}
+
+// LicenseCheckResult ответ внешнего реестра
+type LicenseCheckResult struct {
+	Status LicenseRegistryStatus `json:"status"`
+	// ExpiryDate срок действия по данным реестра, если реестр его сообщает
+	ExpiryDate *time.Time `json:"expiry_date,omitempty"`
+	// Registry имя реестра, проверившего удостоверение
+	Registry  string    `json:"registry"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Accepted сообщает, подтверждает ли реестр удостоверение
+func (r *LicenseCheckResult) Accepted() bool {
+	return r.Status == LicenseRegistryValid
+}
+
+// NormalizeLicenseNumber приводит номер удостоверения к виду, в котором он хранится в реестре:
+// без пробелов, в верхнем регистре
+func NormalizeLicenseNumber(number string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(number), ""))
+}
@@ -19,6 +19,8 @@ type DriverLocation struct {
 	Bearing    *float64  `json:"bearing,omitempty" db:"bearing"`
 	Address    *string   `json:"address,omitempty" db:"address"`
 	Metadata   Metadata  `json:"metadata" db:"metadata"`
+	// ShardKey ключ шарда водителя на момент записи
+	ShardKey   string    `json:"shard_key,omitempty" db:"shard_key"`
 	RecordedAt time.Time `json:"recorded_at" db:"recorded_at"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
 }
@@ -86,9 +88,12 @@ func (dl *DriverLocation) GetAccuracy() float64 {
 	return *dl.Accuracy
 }
 
-// IsHighAccuracy проверяет высокую точность (меньше 50 метров)
+// HighAccuracyMeters погрешность в метрах, начиная с которой точность уже не считается высокой
+const HighAccuracyMeters = 50.0
+
+// IsHighAccuracy проверяет высокую точность (меньше HighAccuracyMeters)
 func (dl *DriverLocation) IsHighAccuracy() bool {
-	return dl.GetAccuracy() > 0 && dl.GetAccuracy() < 50
+	return dl.GetAccuracy() > 0 && dl.GetAccuracy() < HighAccuracyMeters
 }
 
 // Validate проверяет валидность данных местоположения
@@ -130,10 +135,45 @@ type LocationFilters struct {
 	Bounds    *GeoBounds `json:"bounds,omitempty"`
 	MinSpeed  *float64   `json:"min_speed,omitempty"`
 	MaxSpeed  *float64   `json:"max_speed,omitempty"`
+	// MinAccuracy наибольшая допустимая погрешность в метрах: точки с большей погрешностью
+	// или без нее не возвращаются
+	MinAccuracy *float64 `json:"min_accuracy,omitempty"`
+	// OnlyHighAccuracy возвращает только точки с высокой точностью (IsHighAccuracy)
+	OnlyHighAccuracy bool `json:"only_high_accuracy,omitempty"`
+	OnTrip    *bool      `json:"on_trip,omitempty"`
+	OrderID   *uuid.UUID `json:"order_id,omitempty"`
+	Source    *string    `json:"source,omitempty"`
+	// City ограничивает выборку городом и его шардом; без него запрос выполняется на всех шардах
+	City      string     `json:"city,omitempty"`
 	Limit     int        `json:"limit,omitempty"`
 	Offset    int        `json:"offset,omitempty"`
 }
 
+// HasPointFilters проверяет, заданы ли фильтры по свойствам точек: точности, скорости,
+// области или заказу
+func (f *LocationFilters) HasPointFilters() bool {
+	return f.MinAccuracy != nil || f.OnlyHighAccuracy || f.MinSpeed != nil || f.MaxSpeed != nil ||
+		f.Bounds != nil || f.OrderID != nil
+}
+
+// Validate проверяет фильтры: погрешность и скорость неотрицательны, диапазон скорости
+// не пуст, область корректна
+func (f *LocationFilters) Validate() error {
+	if f.MinAccuracy != nil && *f.MinAccuracy < 0 {
+		return ErrInvalidLocationFilters
+	}
+	if (f.MinSpeed != nil && *f.MinSpeed < 0) || (f.MaxSpeed != nil && *f.MaxSpeed < 0) {
+		return ErrInvalidLocationFilters
+	}
+	if f.MinSpeed != nil && f.MaxSpeed != nil && *f.MinSpeed > *f.MaxSpeed {
+		return ErrInvalidLocationFilters
+	}
+	if f.Bounds != nil {
+		return f.Bounds.Validate()
+	}
+	return nil
+}
+
 // GeoBounds географические границы
 type GeoBounds struct {
 	NorthEast Location `json:"north_east"`
@@ -202,10 +242,61 @@ type LocationStats struct {
 	AverageSpeed   float64 `json:"average_speed_kmh"`
 	MaxSpeed       float64 `json:"max_speed_kmh"`
 	TimeSpan       int64   `json:"time_span_minutes"`
+	// IdleMinutes время на связи, когда водитель стоял: скорость между соседними точками
+	// ниже LocationIdleSpeedKmh
+	IdleMinutes int64 `json:"idle_minutes"`
+
+	// Разрывы трека: интервалы между соседними точками длиннее порога.
+	// TrackedMinutes — TimeSpan без разрывов
+	GapCount       int           `json:"gap_count"`
+	GapMinutes     int64         `json:"gap_minutes"`
+	TrackedMinutes int64         `json:"tracked_minutes"`
+	Gaps           []LocationGap `json:"gaps,omitempty"`
+}
+
+// LocationGap разрыв трека: путь водителя между точками неизвестен
+type LocationGap struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// ToLocationID первая точка после разрыва
+	ToLocationID    uuid.UUID `json:"to_location_id"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	// DistanceKm расстояние по прямой между точками разрыва
+	DistanceKm float64 `json:"distance_km"`
+}
+
+// DetectLocationGaps находит разрывы в точках, упорядоченных по времени записи.
+// maxInterval <= 0 отключает поиск разрывов
+func DetectLocationGaps(locations []*DriverLocation, maxInterval time.Duration) []LocationGap {
+	if maxInterval <= 0 {
+		return nil
+	}
+
+	var gaps []LocationGap
+	for i := 1; i < len(locations); i++ {
+		prev, curr := locations[i-1], locations[i]
+		interval := curr.RecordedAt.Sub(prev.RecordedAt)
+		if interval <= maxInterval {
+			continue
+		}
+		gaps = append(gaps, LocationGap{
+			From:            prev.RecordedAt,
+			To:              curr.RecordedAt,
+			ToLocationID:    curr.ID,
+			DurationSeconds: int64(interval.Seconds()),
+			DistanceKm:      prev.DistanceTo(curr),
+		})
+	}
+	return gaps
 }
 
-// CalculateLocationStats вычисляет статистику по массиву точек
-func CalculateLocationStats(locations []*DriverLocation) *LocationStats {
+// LocationIdleSpeedKmh скорость между соседними точками, ниже которой водитель считается стоящим
+const LocationIdleSpeedKmh = 3.0
+
+// CalculateLocationStats вычисляет статистику по массиву точек, упорядоченных по времени записи.
+// Точки после разрыва длиннее maxInterval не участвуют в средней скорости: скорость первой
+// точки после потери сигнала не описывает движение во время разрыва
+func CalculateLocationStats(locations []*DriverLocation, maxInterval time.Duration) *LocationStats {
 	if len(locations) == 0 {
 		return &LocationStats{}
 	}
@@ -222,6 +313,7 @@ func CalculateLocationStats(locations []*DriverLocation) *LocationStats {
 	var totalSpeed float64
 	var maxSpeed float64
 	var speedCount int
+	var idleDuration time.Duration
 
 	for i := 1; i < len(locations); i++ {
 		prev := locations[i-1]
@@ -231,19 +323,30 @@ func CalculateLocationStats(locations []*DriverLocation) *LocationStats {
 		distance := prev.DistanceTo(curr)
 		totalDistance += distance
 
+		// Простой: интервал без разрыва, за который водитель почти не сдвинулся
+		interval := curr.RecordedAt.Sub(prev.RecordedAt)
+		if interval > 0 && (maxInterval <= 0 || interval <= maxInterval) &&
+			distance/interval.Hours() < LocationIdleSpeedKmh {
+			idleDuration += interval
+		}
+
 		// Расчет скорости
 		if curr.Speed != nil {
 			speed := *curr.Speed
-			totalSpeed += speed
-			speedCount++
 			if speed > maxSpeed {
 				maxSpeed = speed
 			}
+			if maxInterval > 0 && curr.RecordedAt.Sub(prev.RecordedAt) > maxInterval {
+				continue
+			}
+			totalSpeed += speed
+			speedCount++
 		}
 	}
 
 	stats.DistanceTraveled = totalDistance
 	stats.MaxSpeed = maxSpeed
+	stats.IdleMinutes = int64(idleDuration.Minutes())
 
 	if speedCount > 0 {
 		stats.AverageSpeed = totalSpeed / float64(speedCount)
@@ -254,5 +357,15 @@ func CalculateLocationStats(locations []*DriverLocation) *LocationStats {
 	lastTime := locations[len(locations)-1].RecordedAt
 	stats.TimeSpan = int64(lastTime.Sub(firstTime).Minutes())
 
+	// Разрывы трека
+	var gapDuration time.Duration
+	stats.Gaps = DetectLocationGaps(locations, maxInterval)
+	for _, gap := range stats.Gaps {
+		gapDuration += gap.To.Sub(gap.From)
+	}
+	stats.GapCount = len(stats.Gaps)
+	stats.GapMinutes = int64(gapDuration.Minutes())
+	stats.TrackedMinutes = int64((lastTime.Sub(firstTime) - gapDuration).Minutes())
+
 	return stats
-}
\ No newline at end of file
+}
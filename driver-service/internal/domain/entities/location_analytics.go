@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// MaxAnalyticsRange наибольший период одного запроса аналитики местоположений
+const MaxAnalyticsRange = 92 * 24 * time.Hour
+
+// CityDistanceQuery запрос пробега водителей по городам и дням
+type CityDistanceQuery struct {
+	From time.Time
+	To   time.Time
+	// City ограничивает выборку одним городом; пустой — все города
+	City string
+}
+
+// Validate проверяет период запроса: From раньше To и период не длиннее MaxAnalyticsRange
+func (q *CityDistanceQuery) Validate() error {
+	if q.From.IsZero() || q.To.IsZero() || !q.From.Before(q.To) {
+		return ErrInvalidAnalyticsRange
+	}
+	if q.To.Sub(q.From) > MaxAnalyticsRange {
+		return ErrInvalidAnalyticsRange
+	}
+	return nil
+}
+
+// CityDistance пробег водителей в городе за сутки (UTC). Точки вне границ городов
+// относятся к городу с пустым ключом
+type CityDistance struct {
+	City string    `json:"city"`
+	Day  time.Time `json:"day"`
+	// DistanceKm сумма расстояний между соседними точками каждого водителя за сутки
+	DistanceKm float64 `json:"distance_km"`
+	// Drivers сколько водителей передавали местоположение
+	Drivers int `json:"drivers"`
+	// Points число принятых точек
+	Points int64 `json:"points"`
+}
+
+// CityDistanceReport пробег по городам и дням за период
+type CityDistanceReport struct {
+	From  time.Time       `json:"from"`
+	To    time.Time       `json:"to"`
+	Items []*CityDistance `json:"items"`
+}
@@ -0,0 +1,73 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationRejection точка пакета, отклоненная из-за своего содержимого
+type LocationRejection struct {
+	// Index позиция точки в пакете
+	Index int    `json:"index"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+	// Err исходная ошибка для вызывающего кода
+	Err error `json:"-"`
+}
+
+// LocationBatchResult итог сохранения пакета местоположений. Точки с ошибкой в данных не
+// прерывают пакет, а попадают в Rejected; повторы уже сохраненных точек того же водителя
+// с тем же временем записи пропускаются, что делает повторную отправку пакета безопасной
+type LocationBatchResult struct {
+	Received int `json:"received"`
+	Accepted int `json:"accepted"`
+	// Duplicates точки, уже сохраненные ранее или повторенные в пакете
+	Duplicates int `json:"duplicates"`
+	// Dropped точки вне смены, отброшенные режимом приватности водителя
+	Dropped  int                  `json:"dropped"`
+	Rejected []*LocationRejection `json:"rejected"`
+}
+
+// NewLocationBatchResult создает итог для пакета из received точек
+func NewLocationBatchResult(received int) *LocationBatchResult {
+	return &LocationBatchResult{
+		Received: received,
+		Rejected: []*LocationRejection{},
+	}
+}
+
+// Reject отмечает точку index отклоненной с ошибкой err
+func (r *LocationBatchResult) Reject(index int, err error) {
+	rejection := &LocationRejection{Index: index, Code: "INVALID_LOCATION", Error: err.Error(), Err: err}
+	if domainErr, ok := AsDomainError(err); ok {
+		rejection.Code = domainErr.Code
+	}
+	r.Rejected = append(r.Rejected, rejection)
+}
+
+// LocationPointKey ключ уникальности точки: водитель и время записи с точностью хранения
+// (микросекунды)
+type LocationPointKey struct {
+	DriverID   uuid.UUID
+	RecordedAt int64
+}
+
+// PointKey возвращает ключ уникальности точки
+func (dl *DriverLocation) PointKey() LocationPointKey {
+	return LocationPointKey{DriverID: dl.DriverID, RecordedAt: dl.RecordedAt.UnixMicro()}
+}
+
+// LocationBatchStats счетчики пакетной записи местоположений с запуска экземпляра
+type LocationBatchStats struct {
+	Batches    int64 `json:"batches"`
+	Received   int64 `json:"received"`
+	Accepted   int64 `json:"accepted"`
+	Duplicates int64 `json:"duplicates"`
+	Dropped    int64 `json:"dropped"`
+	Rejected   int64 `json:"rejected"`
+	// RejectedByCode отклоненные точки по коду ошибки
+	RejectedByCode map[string]int64 `json:"rejected_by_code"`
+	// Since время запуска счетчиков
+	Since time.Time `json:"since"`
+}
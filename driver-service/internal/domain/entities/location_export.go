@@ -0,0 +1,165 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationExportStatus состояние выгрузки истории местоположений
+type LocationExportStatus string
+
+const (
+	// LocationExportPending выгрузка ждет в очереди
+	LocationExportPending LocationExportStatus = "pending"
+	// LocationExportRunning файл выгрузки формируется
+	LocationExportRunning LocationExportStatus = "running"
+	// LocationExportCompleted файл готов к скачиванию до ExpiresAt
+	LocationExportCompleted LocationExportStatus = "completed"
+	// LocationExportFailed выгрузка не удалась; причина в Error
+	LocationExportFailed LocationExportStatus = "failed"
+)
+
+// LocationExportFormat формат файла выгрузки
+type LocationExportFormat string
+
+const (
+	// LocationExportCSV CSV с заголовком LocationCSVHeader
+	LocationExportCSV LocationExportFormat = "csv"
+)
+
+// LocationCSVHeader колонки CSV выгрузки истории местоположений
+var LocationCSVHeader = []string{
+	"recorded_at", "latitude", "longitude", "altitude", "accuracy", "speed", "bearing", "address",
+}
+
+// LocationExport выгрузка истории местоположений водителя за полуинтервал [From, To) в файл
+type LocationExport struct {
+	ID       uuid.UUID            `json:"id" db:"id"`
+	DriverID uuid.UUID            `json:"driver_id" db:"driver_id"`
+	Format   LocationExportFormat `json:"format" db:"format"`
+	From     time.Time            `json:"from" db:"from_time"`
+	To       time.Time            `json:"to" db:"to_time"`
+	// Precise в файле точные координаты; иначе точки огрублены как DriverLocation.Blur
+	Precise bool                 `json:"precise" db:"precise"`
+	Status  LocationExportStatus `json:"status" db:"status"`
+	// RequestedBy субъект токена, запросившего выгрузку
+	RequestedBy *string `json:"requested_by,omitempty" db:"requested_by"`
+	Rows        int64   `json:"rows" db:"row_count"`
+	SizeBytes   int64   `json:"size_bytes" db:"size_bytes"`
+	// FileURL адрес файла в хранилище; наружу отдается только подписанная ссылка
+	FileURL     *string    `json:"-" db:"file_url"`
+	Error       *string    `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	// ExpiresAt после этого времени файл и запись удаляются задачей location_export_cleanup
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+
+	// DownloadURL подписанная ссылка на файл готовой выгрузки, действует до DownloadURLExpiresAt
+	DownloadURL          *string    `json:"download_url,omitempty" db:"-"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty" db:"-"`
+}
+
+// LocationExportRequest запрос на выгрузку истории местоположений; формат по умолчанию csv
+type LocationExportRequest struct {
+	From   time.Time            `json:"from" binding:"required"`
+	To     time.Time            `json:"to" binding:"required"`
+	Format LocationExportFormat `json:"format"`
+}
+
+// NewLocationExport создает выгрузку в очереди
+func NewLocationExport(driverID uuid.UUID, req *LocationExportRequest, precise bool, requestedBy string, now time.Time) *LocationExport {
+	format := req.Format
+	if format == "" {
+		format = LocationExportCSV
+	}
+
+	export := &LocationExport{
+		ID:        uuid.New(),
+		DriverID:  driverID,
+		Format:    format,
+		From:      req.From,
+		To:        req.To,
+		Precise:   precise,
+		Status:    LocationExportPending,
+		CreatedAt: now,
+	}
+	if requestedBy != "" {
+		export.RequestedBy = &requestedBy
+	}
+	return export
+}
+
+// Validate проверяет формат и период выгрузки; maxRange 0 — период не ограничен
+func (e *LocationExport) Validate(maxRange time.Duration) error {
+	if e.Format != LocationExportCSV {
+		return ErrInvalidLocationExport
+	}
+	if !e.To.After(e.From) {
+		return ErrInvalidLocationExport
+	}
+	if maxRange > 0 && e.To.Sub(e.From) > maxRange {
+		return ErrInvalidLocationExport
+	}
+	return nil
+}
+
+// Start отмечает начало формирования файла
+func (e *LocationExport) Start(at time.Time) {
+	e.Status = LocationExportRunning
+	e.StartedAt = &at
+}
+
+// Complete отмечает готовность файла, который хранится retention
+func (e *LocationExport) Complete(fileURL string, rows, sizeBytes int64, at time.Time, retention time.Duration) {
+	expiresAt := at.Add(retention)
+	e.Status = LocationExportCompleted
+	e.FileURL = &fileURL
+	e.Rows = rows
+	e.SizeBytes = sizeBytes
+	e.CompletedAt = &at
+	e.ExpiresAt = &expiresAt
+}
+
+// Fail отмечает неудачную выгрузку, запись о которой хранится retention
+func (e *LocationExport) Fail(reason string, at time.Time, retention time.Duration) {
+	expiresAt := at.Add(retention)
+	e.Status = LocationExportFailed
+	e.Error = &reason
+	e.CompletedAt = &at
+	e.ExpiresAt = &expiresAt
+}
+
+// IsDownloadable сообщает, можно ли скачать файл выгрузки в момент now
+func (e *LocationExport) IsDownloadable(now time.Time) bool {
+	return e.Status == LocationExportCompleted && e.FileURL != nil &&
+		(e.ExpiresAt == nil || now.Before(*e.ExpiresAt))
+}
+
+// CSVRecord возвращает строку CSV выгрузки в порядке LocationCSVHeader; незаданные поля пустые
+func (dl *DriverLocation) CSVRecord() []string {
+	address := ""
+	if dl.Address != nil {
+		address = *dl.Address
+	}
+	return []string{
+		dl.RecordedAt.UTC().Format(time.RFC3339Nano),
+		formatCSVFloat(&dl.Latitude),
+		formatCSVFloat(&dl.Longitude),
+		formatCSVFloat(dl.Altitude),
+		formatCSVFloat(dl.Accuracy),
+		formatCSVFloat(dl.Speed),
+		formatCSVFloat(dl.Bearing),
+		address,
+	}
+}
+
+// formatCSVFloat форматирует число без потери точности; nil — пустая строка
+func formatCSVFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'f', -1, 64)
+}
@@ -0,0 +1,268 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Известные ключи метаданных местоположения
+const (
+	LocationMetaOnTrip       = "on_trip"
+	LocationMetaOrderID      = "order_id"
+	LocationMetaSource       = "source"
+	LocationMetaProvider     = "provider"
+	LocationMetaBatteryLevel = "battery_level"
+	// LocationMetaDeviceID устройство, с которого пришла точка; записывается сервером
+	LocationMetaDeviceID = "device_id"
+	// LocationMetaCity ключ города, в границы которого попала точка; записывается сервером
+	LocationMetaCity = "city"
+)
+
+// Источники местоположения
+const (
+	LocationSourceApp      = "app"
+	LocationSourceAPI      = "api"
+	LocationSourceBatch    = "batch"
+	LocationSourceTracking = "tracking"
+	// LocationSourceTracker точка от GPS-трекера автомобиля
+	LocationSourceTracker = "tracker"
+)
+
+// Провайдеры координат на устройстве
+const (
+	LocationProviderGPS     = "gps"
+	LocationProviderNetwork = "network"
+	LocationProviderFused   = "fused"
+	LocationProviderPassive = "passive"
+)
+
+// MaxLocationExtraMetadataBytes допустимый размер неизвестных ключей метаданных в JSON
+const MaxLocationExtraMetadataBytes = 512
+
+var (
+	locationSources = map[string]bool{
+		LocationSourceApp:      true,
+		LocationSourceAPI:      true,
+		LocationSourceBatch:    true,
+		LocationSourceTracking: true,
+		LocationSourceTracker:  true,
+	}
+	locationProviders = map[string]bool{
+		LocationProviderGPS:     true,
+		LocationProviderNetwork: true,
+		LocationProviderFused:   true,
+		LocationProviderPassive: true,
+	}
+)
+
+// NormalizeLocationMetadata проверяет метаданные местоположения и приводит известные ключи
+// к каноническому виду. Неизвестные ключи сохраняются, пока их суммарный размер
+// не превышает MaxLocationExtraMetadataBytes.
+func NormalizeLocationMetadata(metadata Metadata) (Metadata, error) {
+	normalized := make(Metadata, len(metadata))
+	extra := make(map[string]interface{})
+
+	for key, value := range metadata {
+		if value == nil {
+			continue
+		}
+
+		var err error
+		switch key {
+		case LocationMetaOnTrip:
+			normalized[key], err = normalizeBool(value)
+		case LocationMetaOrderID:
+			normalized[key], err = normalizeUUID(value)
+		case LocationMetaSource:
+			normalized[key], err = normalizeEnum(value, locationSources)
+		case LocationMetaProvider:
+			normalized[key], err = normalizeEnum(value, locationProviders)
+		case LocationMetaBatteryLevel:
+			normalized[key], err = normalizeBatteryLevel(value)
+		case LocationMetaDeviceID:
+			normalized[key], err = normalizeDeviceID(value)
+		case LocationMetaCity:
+			// Город определяется сервером по границам городов
+			continue
+		default:
+			extra[key] = value
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidLocationMetadata, key, err)
+		}
+	}
+
+	if len(extra) > 0 {
+		encoded, err := json.Marshal(extra)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidLocationMetadata, err)
+		}
+		if len(encoded) > MaxLocationExtraMetadataBytes {
+			return nil, fmt.Errorf("%w: unknown keys exceed %d bytes", ErrInvalidLocationMetadata, MaxLocationExtraMetadataBytes)
+		}
+		for key, value := range extra {
+			normalized[key] = value
+		}
+	}
+
+	return normalized, nil
+}
+
+// NormalizeMetadata проверяет и нормализует метаданные местоположения на месте
+func (dl *DriverLocation) NormalizeMetadata() error {
+	normalized, err := NormalizeLocationMetadata(dl.Metadata)
+	if err != nil {
+		return err
+	}
+	dl.Metadata = normalized
+	return nil
+}
+
+// SetDevice записывает в метаданные устройство, с которого пришла точка. Пустой или
+// слишком длинный deviceID удаляет значение, присланное клиентом: устройство в журнале
+// должно совпадать с устройством запроса
+func (dl *DriverLocation) SetDevice(deviceID string) {
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" || len(deviceID) > MaxDeviceIDLength {
+		delete(dl.Metadata, LocationMetaDeviceID)
+		return
+	}
+	if dl.Metadata == nil {
+		dl.Metadata = make(Metadata)
+	}
+	dl.Metadata[LocationMetaDeviceID] = deviceID
+}
+
+// SetCity записывает в метаданные город, в границы которого попала точка; пустой ключ
+// удаляет значение
+func (dl *DriverLocation) SetCity(city string) {
+	if city == "" {
+		delete(dl.Metadata, LocationMetaCity)
+		return
+	}
+	if dl.Metadata == nil {
+		dl.Metadata = make(Metadata)
+	}
+	dl.Metadata[LocationMetaCity] = city
+}
+
+// City возвращает город, в границы которого попала точка
+func (dl *DriverLocation) City() string {
+	city, _ := dl.Metadata[LocationMetaCity].(string)
+	return city
+}
+
+// DeviceID возвращает устройство, с которого пришла точка
+func (dl *DriverLocation) DeviceID() string {
+	deviceID, _ := dl.Metadata[LocationMetaDeviceID].(string)
+	return deviceID
+}
+
+// IsOnTrip проверяет, записано ли местоположение во время выполнения заказа
+func (dl *DriverLocation) IsOnTrip() bool {
+	onTrip, _ := dl.Metadata[LocationMetaOnTrip].(bool)
+	return onTrip
+}
+
+// OrderID возвращает ID заказа из метаданных местоположения
+func (dl *DriverLocation) OrderID() *uuid.UUID {
+	raw, ok := dl.Metadata[LocationMetaOrderID].(string)
+	if !ok {
+		return nil
+	}
+	orderID, err := uuid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return &orderID
+}
+
+// Source возвращает источник местоположения из метаданных
+func (dl *DriverLocation) Source() string {
+	source, _ := dl.Metadata[LocationMetaSource].(string)
+	return source
+}
+
+func normalizeBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "true", "1":
+			return true, nil
+		case "false", "0":
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("expected boolean, got %v", value)
+}
+
+func normalizeUUID(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v.String(), nil
+	case string:
+		id, err := uuid.Parse(strings.TrimSpace(v))
+		if err != nil {
+			return "", fmt.Errorf("expected UUID, got %q", v)
+		}
+		return id.String(), nil
+	}
+	return "", fmt.Errorf("expected UUID, got %v", value)
+}
+
+func normalizeEnum(value interface{}, allowed map[string]bool) (string, error) {
+	v, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string, got %v", value)
+	}
+	v = strings.ToLower(strings.TrimSpace(v))
+	if !allowed[v] {
+		return "", fmt.Errorf("unsupported value %q", v)
+	}
+	return v, nil
+}
+
+func normalizeDeviceID(value interface{}) (string, error) {
+	v, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("expected string, got %v", value)
+	}
+	v = strings.TrimSpace(v)
+	if v == "" || len(v) > MaxDeviceIDLength {
+		return "", fmt.Errorf("expected device ID up to %d characters", MaxDeviceIDLength)
+	}
+	return v, nil
+}
+
+func normalizeBatteryLevel(value interface{}) (int, error) {
+	var level float64
+	switch v := value.(type) {
+	case int:
+		level = float64(v)
+	case int32:
+		level = float64(v)
+	case int64:
+		level = float64(v)
+	case float64:
+		level = v
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("expected number, got %q", v)
+		}
+		level = parsed
+	default:
+		return 0, fmt.Errorf("expected number, got %v", value)
+	}
+
+	if math.IsNaN(level) || level < 0 || level > 100 {
+		return 0, fmt.Errorf("expected percentage 0-100, got %v", level)
+	}
+	return int(math.Round(level)), nil
+}
@@ -0,0 +1,63 @@
+package entities
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocationMetadata(t *testing.T) {
+	orderID := uuid.New()
+
+	normalized, err := NormalizeLocationMetadata(Metadata{
+		LocationMetaOnTrip:       "true",
+		LocationMetaOrderID:      strings.ToUpper(orderID.String()),
+		LocationMetaSource:       " App ",
+		LocationMetaProvider:     "GPS",
+		LocationMetaBatteryLevel: 54.6,
+		"app_version":            "3.2.1",
+		"ignored":                nil,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, Metadata{
+		LocationMetaOnTrip:       true,
+		LocationMetaOrderID:      orderID.String(),
+		LocationMetaSource:       LocationSourceApp,
+		LocationMetaProvider:     LocationProviderGPS,
+		LocationMetaBatteryLevel: 55,
+		"app_version":            "3.2.1",
+	}, normalized)
+
+	location := &DriverLocation{Metadata: normalized}
+	assert.True(t, location.IsOnTrip())
+	require.NotNil(t, location.OrderID())
+	assert.Equal(t, orderID, *location.OrderID())
+	assert.Equal(t, LocationSourceApp, location.Source())
+}
+
+func TestNormalizeLocationMetadata_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata Metadata
+	}{
+		{"on_trip not boolean", Metadata{LocationMetaOnTrip: "yes please"}},
+		{"order_id not uuid", Metadata{LocationMetaOrderID: "order-42"}},
+		{"unknown source", Metadata{LocationMetaSource: "satellite"}},
+		{"unknown provider", Metadata{LocationMetaProvider: 5}},
+		{"battery above 100", Metadata{LocationMetaBatteryLevel: 120}},
+		{"device_id too long", Metadata{LocationMetaDeviceID: strings.Repeat("d", MaxDeviceIDLength+1)}},
+		{"unknown keys over budget", Metadata{"debug": strings.Repeat("x", MaxLocationExtraMetadataBytes)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NormalizeLocationMetadata(tt.metadata)
+			assert.True(t, errors.Is(err, ErrInvalidLocationMetadata), "got %v", err)
+		})
+	}
+}
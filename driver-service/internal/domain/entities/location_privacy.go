@@ -0,0 +1,77 @@
+package entities
+
+import "math"
+
+// ScopeLocationReadPrecise область доступа к точным координатам водителей. Вызывающие без
+// нее получают координаты, округленные до CoarseLocationDecimals знаков
+const ScopeLocationReadPrecise = "location:read:precise"
+
+// DriverMetaLocationPrivacy ключ метаданных водителя с режимом приватности местоположения
+const DriverMetaLocationPrivacy = "location_privacy"
+
+const (
+	// CoarseLocationDecimals знаков после запятой в огрубленных координатах (около 1 км)
+	CoarseLocationDecimals = 2
+	// CoarseLocationAccuracy точность огрубленной точки в метрах
+	CoarseLocationAccuracy = 1000.0
+)
+
+// LocationPrivacyMode режим приватности местоположения водителя вне смены
+type LocationPrivacyMode string
+
+const (
+	// LocationPrivacyOff точки сохраняются всегда
+	LocationPrivacyOff LocationPrivacyMode = "off"
+	// LocationPrivacyDrop точки вне смены не сохраняются
+	LocationPrivacyDrop LocationPrivacyMode = "drop"
+	// LocationPrivacyBlur точки вне смены сохраняются с огрубленными координатами
+	LocationPrivacyBlur LocationPrivacyMode = "blur"
+)
+
+// IsValid проверяет, известен ли режим приватности
+func (m LocationPrivacyMode) IsValid() bool {
+	switch m {
+	case LocationPrivacyOff, LocationPrivacyDrop, LocationPrivacyBlur:
+		return true
+	}
+	return false
+}
+
+// LocationPrivacy возвращает режим приватности местоположения водителя (по умолчанию off)
+func (d *Driver) LocationPrivacy() LocationPrivacyMode {
+	if value, ok := d.Metadata[DriverMetaLocationPrivacy].(string); ok {
+		if mode := LocationPrivacyMode(value); mode.IsValid() {
+			return mode
+		}
+	}
+	return LocationPrivacyOff
+}
+
+// IsOnShift сообщает, работает ли водитель в смене: свободен на смене или выполняет заказ
+func (d *Driver) IsOnShift() bool {
+	return d.Status == StatusOnShift || d.Status == StatusBusy
+}
+
+// CoarsenCoordinate округляет координату до CoarseLocationDecimals знаков
+func CoarsenCoordinate(value float64) float64 {
+	return roundCoordinate(value, CoarseLocationDecimals)
+}
+
+// roundCoordinate округляет координату до decimals знаков после запятой
+func roundCoordinate(value float64, decimals int) float64 {
+	scale := math.Pow10(decimals)
+	return math.Round(value*scale) / scale
+}
+
+// Blur огрубляет точку: координаты округляются, а высота, скорость, направление и адрес,
+// по которым можно уточнить положение, удаляются
+func (dl *DriverLocation) Blur() {
+	dl.Latitude = CoarsenCoordinate(dl.Latitude)
+	dl.Longitude = CoarsenCoordinate(dl.Longitude)
+	accuracy := CoarseLocationAccuracy
+	dl.Accuracy = &accuracy
+	dl.Altitude = nil
+	dl.Speed = nil
+	dl.Bearing = nil
+	dl.Address = nil
+}
@@ -0,0 +1,37 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDriver_LocationPrivacy(t *testing.T) {
+	driver := &Driver{}
+	assert.Equal(t, LocationPrivacyOff, driver.LocationPrivacy())
+
+	driver.Metadata = Metadata{DriverMetaLocationPrivacy: "blur"}
+	assert.Equal(t, LocationPrivacyBlur, driver.LocationPrivacy())
+
+	driver.Metadata[DriverMetaLocationPrivacy] = "hidden"
+	assert.Equal(t, LocationPrivacyOff, driver.LocationPrivacy())
+}
+
+func TestDriverLocation_Blur(t *testing.T) {
+	speed, address := 42.0, "Тверская, 1"
+	location := NewDriverLocation(uuid.New(), 55.75583, 37.61731, time.Now())
+	location.Speed = &speed
+	location.Address = &address
+
+	location.Blur()
+	assert.Equal(t, 55.76, location.Latitude)
+	assert.Equal(t, 37.62, location.Longitude)
+	require.NotNil(t, location.Accuracy)
+	assert.Equal(t, CoarseLocationAccuracy, *location.Accuracy)
+	assert.Nil(t, location.Speed)
+	assert.Nil(t, location.Address)
+	assert.NoError(t, location.Validate())
+}
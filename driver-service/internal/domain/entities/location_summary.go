@@ -0,0 +1,111 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LocationSummary сводная точка истории местоположений: одна точка водителя на интервал
+// уровня хранения. Заменяет исходные точки после истечения срока их хранения
+type LocationSummary struct {
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	// Tier имя уровня хранения, BucketStart и BucketSeconds — начало и длина интервала
+	Tier          string    `json:"tier" db:"tier"`
+	BucketStart   time.Time `json:"bucket_start" db:"bucket_start"`
+	BucketSeconds int       `json:"bucket_seconds" db:"bucket_seconds"`
+	// Latitude и Longitude последняя точка интервала: среднее на повороте легло бы вне дороги
+	Latitude  float64 `json:"latitude" db:"latitude"`
+	Longitude float64 `json:"longitude" db:"longitude"`
+	// DistanceKm пробег за интервал, включая отрезок от последней точки предыдущего интервала
+	DistanceKm   float64 `json:"distance_km" db:"distance_km"`
+	AverageSpeed float64 `json:"average_speed_kmh" db:"average_speed"`
+	MaxSpeed     float64 `json:"max_speed_kmh" db:"max_speed"`
+	PointCount   int     `json:"point_count" db:"point_count"`
+	// OnTrip хотя бы одна точка интервала записана во время заказа, OrderID — последний заказ интервала
+	OnTrip          bool       `json:"on_trip" db:"on_trip"`
+	OrderID         *uuid.UUID `json:"order_id,omitempty" db:"order_id"`
+	FirstRecordedAt time.Time  `json:"first_recorded_at" db:"first_recorded_at"`
+	LastRecordedAt  time.Time  `json:"last_recorded_at" db:"last_recorded_at"`
+}
+
+// LocationRetentionTier уровень хранения сводных точек
+type LocationRetentionTier struct {
+	Name string
+	// Interval длина интервала сводной точки; делит сутки без остатка
+	Interval time.Duration
+	// Keep сколько хранятся сводные точки уровня; 0 — бессрочно
+	Keep time.Duration
+}
+
+// LocationRetentionResult итог применения политики хранения местоположений
+type LocationRetentionResult struct {
+	// Cutoff исходные точки до этого момента прорежены и удалены
+	Cutoff           time.Time `json:"cutoff"`
+	Days             int       `json:"days"`
+	Drivers          int       `json:"drivers"`
+	PointsSummarized int       `json:"points_summarized"`
+	SummariesWritten int       `json:"summaries_written"`
+	SummariesExpired int       `json:"summaries_expired"`
+}
+
+// DownsampleLocations прореживает точки одного водителя, упорядоченные по времени записи,
+// до одной сводной точки на интервал уровня. Интервалы выравниваются по UTC
+func DownsampleLocations(locations []*DriverLocation, tier LocationRetentionTier) []*LocationSummary {
+	if len(locations) == 0 || tier.Interval <= 0 {
+		return nil
+	}
+
+	var summaries []*LocationSummary
+	var current *LocationSummary
+	var speedSum float64
+
+	closeBucket := func() {
+		if current != nil {
+			current.AverageSpeed = speedSum / float64(current.PointCount)
+			summaries = append(summaries, current)
+		}
+	}
+
+	for i, location := range locations {
+		var prev *DriverLocation
+		if i > 0 {
+			prev = locations[i-1]
+		}
+
+		bucketStart := location.RecordedAt.UTC().Truncate(tier.Interval)
+		if current == nil || !current.BucketStart.Equal(bucketStart) {
+			closeBucket()
+			current = &LocationSummary{
+				DriverID:        location.DriverID,
+				Tier:            tier.Name,
+				BucketStart:     bucketStart,
+				BucketSeconds:   int(tier.Interval / time.Second),
+				FirstRecordedAt: location.RecordedAt,
+			}
+			speedSum = 0
+		}
+
+		speed := pointSpeed(prev, location)
+		speedSum += speed
+		if speed > current.MaxSpeed {
+			current.MaxSpeed = speed
+		}
+		if prev != nil {
+			current.DistanceKm += prev.DistanceTo(location)
+		}
+		if location.IsOnTrip() {
+			current.OnTrip = true
+		}
+		if orderID := location.OrderID(); orderID != nil {
+			current.OrderID = orderID
+		}
+		current.Latitude = location.Latitude
+		current.Longitude = location.Longitude
+		current.LastRecordedAt = location.RecordedAt
+		current.PointCount++
+	}
+	closeBucket()
+
+	return summaries
+}
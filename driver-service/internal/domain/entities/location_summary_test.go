@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsampleLocations(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 2, 0, 0, time.UTC)
+	orderID := uuid.New()
+	track := []*DriverLocation{
+		newTrackPoint(start, 55.700, 20),
+		newTrackPoint(start.Add(1*time.Minute), 55.701, 40),
+		newTrackPoint(start.Add(2*time.Minute), 55.702, 30),
+		newTrackPoint(start.Add(4*time.Minute), 55.703, 10),
+		newTrackPoint(start.Add(15*time.Minute), 55.710, 0),
+	}
+	track[3].Metadata = Metadata{LocationMetaOnTrip: true, LocationMetaOrderID: orderID.String()}
+
+	summaries := DownsampleLocations(track, LocationRetentionTier{Name: "5m", Interval: 5 * time.Minute})
+	require.Len(t, summaries, 3)
+
+	first := summaries[0]
+	assert.Equal(t, "5m", first.Tier)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), first.BucketStart)
+	assert.Equal(t, 300, first.BucketSeconds)
+	assert.Equal(t, 3, first.PointCount)
+	assert.Equal(t, 55.702, first.Latitude)
+	assert.Equal(t, 40.0, first.MaxSpeed)
+	assert.InDelta(t, 30.0, first.AverageSpeed, 0.001)
+	assert.InDelta(t, track[0].DistanceTo(track[2]), first.DistanceKm, 0.001)
+	assert.False(t, first.OnTrip)
+	assert.Equal(t, start, first.FirstRecordedAt)
+	assert.Equal(t, start.Add(2*time.Minute), first.LastRecordedAt)
+
+	// Отрезок от последней точки предыдущего интервала учитывается в пробеге следующего
+	second := summaries[1]
+	assert.Equal(t, 1, second.PointCount)
+	assert.InDelta(t, track[2].DistanceTo(track[3]), second.DistanceKm, 0.001)
+	assert.True(t, second.OnTrip)
+	require.NotNil(t, second.OrderID)
+	assert.Equal(t, orderID, *second.OrderID)
+
+	// Пустые интервалы не создаются
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC), summaries[2].BucketStart)
+
+	hourly := DownsampleLocations(track, LocationRetentionTier{Name: "1h", Interval: time.Hour})
+	require.Len(t, hourly, 1)
+	assert.Equal(t, 5, hourly[0].PointCount)
+	assert.InDelta(t, summaries[0].DistanceKm+summaries[1].DistanceKm+summaries[2].DistanceKm, hourly[0].DistanceKm, 0.001)
+
+	assert.Empty(t, DownsampleLocations(nil, LocationRetentionTier{Name: "5m", Interval: 5 * time.Minute}))
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTrackPoint(at time.Time, lat float64, speed float64) *DriverLocation {
+	return &DriverLocation{
+		ID:         uuid.New(),
+		Latitude:   lat,
+		Longitude:  37.6,
+		Speed:      &speed,
+		RecordedAt: at,
+	}
+}
+
+func TestCalculateLocationStats_Gaps(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	track := []*DriverLocation{
+		newTrackPoint(start, 55.70, 40),
+		newTrackPoint(start.Add(time.Minute), 55.71, 40),
+		// Сигнал потерян на 20 минут
+		newTrackPoint(start.Add(21*time.Minute), 55.80, 0),
+		newTrackPoint(start.Add(22*time.Minute), 55.81, 60),
+	}
+
+	stats := CalculateLocationStats(track, 5*time.Minute)
+	require.Len(t, stats.Gaps, 1)
+	assert.Equal(t, track[2].ID, stats.Gaps[0].ToLocationID)
+	assert.Equal(t, int64(20*60), stats.Gaps[0].DurationSeconds)
+	assert.Equal(t, 1, stats.GapCount)
+	assert.Equal(t, int64(20), stats.GapMinutes)
+	assert.Equal(t, int64(22), stats.TimeSpan)
+	assert.Equal(t, int64(2), stats.TrackedMinutes)
+
+	// Скорость точки после разрыва не учитывается в средней
+	assert.InDelta(t, 50.0, stats.AverageSpeed, 0.001)
+	assert.Equal(t, 60.0, stats.MaxSpeed)
+
+	// Без порога разрывы не ищутся
+	stats = CalculateLocationStats(track, 0)
+	assert.Empty(t, stats.Gaps)
+	assert.InDelta(t, 100.0/3, stats.AverageSpeed, 0.001)
+	assert.Equal(t, int64(22), stats.TrackedMinutes)
+}
+
+func TestCalculateLocationStats_Idle(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	track := []*DriverLocation{
+		newTrackPoint(start, 55.70, 40),
+		// Стоянка 10 минут на месте
+		newTrackPoint(start.Add(time.Minute), 55.71, 0),
+		newTrackPoint(start.Add(11*time.Minute), 55.71, 0),
+		newTrackPoint(start.Add(12*time.Minute), 55.72, 40),
+		// Разрыв на месте в простой не входит: неизвестно, что водитель делал
+		newTrackPoint(start.Add(32*time.Minute), 55.72, 0),
+	}
+
+	stats := CalculateLocationStats(track, 15*time.Minute)
+	assert.Equal(t, int64(10), stats.IdleMinutes)
+
+	stats = CalculateLocationStats(track, 0)
+	assert.Equal(t, int64(30), stats.IdleMinutes)
+}
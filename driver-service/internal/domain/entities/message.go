@@ -0,0 +1,177 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Ограничения сообщений водителю и диспетчеру
+const (
+	// MaxMessageTextLength максимальная длина текста сообщения в символах
+	MaxMessageTextLength = 500
+	// MaxMessageDelayMinutes максимальная задержка в сообщении delayed
+	MaxMessageDelayMinutes = 120
+	// MaxReceiptBatch сколько сообщений можно отметить одним запросом
+	MaxReceiptBatch = 100
+)
+
+// MessageSender сторона переписки
+type MessageSender string
+
+const (
+	MessageSenderDriver     MessageSender = "driver"
+	MessageSenderDispatcher MessageSender = "dispatcher"
+)
+
+// IsValid проверяет сторону переписки
+func (s MessageSender) IsValid() bool {
+	return s == MessageSenderDriver || s == MessageSenderDispatcher
+}
+
+// MessageKind тип структурированного сообщения
+type MessageKind string
+
+const (
+	// MessageKindArrived водитель на месте подачи
+	MessageKindArrived MessageKind = "arrived"
+	// MessageKindDelayed задержка на DelayMinutes минут
+	MessageKindDelayed MessageKind = "delayed"
+	// MessageKindOnTheWay водитель выехал к месту подачи
+	MessageKindOnTheWay MessageKind = "on_the_way"
+	// MessageKindCallMe просьба перезвонить
+	MessageKindCallMe MessageKind = "call_me"
+	// MessageKindText произвольный текст
+	MessageKindText MessageKind = "text"
+)
+
+// IsValid проверяет тип сообщения
+func (k MessageKind) IsValid() bool {
+	switch k {
+	case MessageKindArrived, MessageKindDelayed, MessageKindOnTheWay, MessageKindCallMe, MessageKindText:
+		return true
+	}
+	return false
+}
+
+// ReceiptStatus отметка о сообщении, которую ставит получатель
+type ReceiptStatus string
+
+const (
+	ReceiptDelivered ReceiptStatus = "delivered"
+	ReceiptRead      ReceiptStatus = "read"
+)
+
+// DriverMessage сообщение между водителем и диспетчером. Сообщения с OrderID образуют
+// переписку по заказу, без него — общую переписку с водителем
+type DriverMessage struct {
+	ID       uuid.UUID     `json:"id" db:"id"`
+	DriverID uuid.UUID     `json:"driver_id" db:"driver_id"`
+	OrderID  *uuid.UUID    `json:"order_id,omitempty" db:"order_id"`
+	Sender   MessageSender `json:"sender" db:"sender"`
+	// SenderID субъект токена отправителя; пусто при выключенной аутентификации
+	SenderID     string      `json:"sender_id,omitempty" db:"sender_id"`
+	Kind         MessageKind `json:"kind" db:"kind"`
+	DelayMinutes *int        `json:"delay_minutes,omitempty" db:"delay_minutes"`
+	Text         string      `json:"text,omitempty" db:"text"`
+	CreatedAt    time.Time   `json:"created_at" db:"created_at"`
+	DeliveredAt  *time.Time  `json:"delivered_at,omitempty" db:"delivered_at"`
+	ReadAt       *time.Time  `json:"read_at,omitempty" db:"read_at"`
+}
+
+// SendMessageRequest запрос на отправку сообщения. Sender учитывается только при
+// выключенной аутентификации, иначе сторона определяется по токену
+type SendMessageRequest struct {
+	OrderID      *uuid.UUID    `json:"order_id"`
+	Sender       MessageSender `json:"sender"`
+	Kind         MessageKind   `json:"kind" binding:"required"`
+	DelayMinutes *int          `json:"delay_minutes"`
+	Text         string        `json:"text"`
+}
+
+// Validate проверяет тип сообщения и его параметры
+func (r *SendMessageRequest) Validate() error {
+	r.Text = strings.TrimSpace(r.Text)
+
+	if !r.Kind.IsValid() || utf8.RuneCountInString(r.Text) > MaxMessageTextLength {
+		return ErrInvalidMessage
+	}
+	if r.Kind == MessageKindText && r.Text == "" {
+		return ErrInvalidMessage
+	}
+	if r.Kind == MessageKindDelayed {
+		if r.DelayMinutes == nil || *r.DelayMinutes <= 0 || *r.DelayMinutes > MaxMessageDelayMinutes {
+			return ErrInvalidMessage
+		}
+	} else if r.DelayMinutes != nil {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+// NewDriverMessage создает сообщение из проверенного запроса
+func NewDriverMessage(driverID uuid.UUID, sender MessageSender, senderID string, req *SendMessageRequest) *DriverMessage {
+	return &DriverMessage{
+		ID:           uuid.New(),
+		DriverID:     driverID,
+		OrderID:      req.OrderID,
+		Sender:       sender,
+		SenderID:     senderID,
+		Kind:         req.Kind,
+		DelayMinutes: req.DelayMinutes,
+		Text:         req.Text,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// Recipient возвращает сторону, которой адресовано сообщение
+func (m *DriverMessage) Recipient() MessageSender {
+	if m.Sender == MessageSenderDriver {
+		return MessageSenderDispatcher
+	}
+	return MessageSenderDriver
+}
+
+// Acknowledge ставит отметку получателя; прочтение подразумевает доставку.
+// Возвращает false, если отметка уже стояла
+func (m *DriverMessage) Acknowledge(status ReceiptStatus, at time.Time) bool {
+	changed := false
+	if m.DeliveredAt == nil {
+		m.DeliveredAt = &at
+		changed = true
+	}
+	if status == ReceiptRead && m.ReadAt == nil {
+		m.ReadAt = &at
+		changed = true
+	}
+	return changed
+}
+
+// MessageReceiptRequest отметка о доставке или прочтении сообщений получателем
+type MessageReceiptRequest struct {
+	MessageIDs []uuid.UUID   `json:"message_ids" binding:"required,min=1"`
+	Status     ReceiptStatus `json:"status" binding:"required"`
+	// Reader учитывается только при выключенной аутентификации, как Sender при отправке
+	Reader MessageSender `json:"reader"`
+}
+
+// Validate проверяет отметку
+func (r *MessageReceiptRequest) Validate() error {
+	if r.Status != ReceiptDelivered && r.Status != ReceiptRead {
+		return ErrInvalidMessageReceipt
+	}
+	if len(r.MessageIDs) == 0 || len(r.MessageIDs) > MaxReceiptBatch {
+		return ErrInvalidMessageReceipt
+	}
+	return nil
+}
+
+// MessageFilters фильтр переписки водителя. Без OrderID выбирается общая переписка
+type MessageFilters struct {
+	DriverID uuid.UUID
+	OrderID  *uuid.UUID
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMessageRequest_Validate(t *testing.T) {
+	delay := func(minutes int) *int { return &minutes }
+
+	req := &SendMessageRequest{Kind: MessageKindText, Text: "  Буду через минуту  "}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "Буду через минуту", req.Text)
+
+	assert.NoError(t, (&SendMessageRequest{Kind: MessageKindArrived}).Validate())
+	assert.NoError(t, (&SendMessageRequest{Kind: MessageKindDelayed, DelayMinutes: delay(MaxMessageDelayMinutes)}).Validate())
+	assert.NoError(t, (&SendMessageRequest{Kind: MessageKindText, Text: strings.Repeat("я", MaxMessageTextLength)}).Validate())
+
+	cases := map[string]*SendMessageRequest{
+		"unknown kind":        {Kind: "honk"},
+		"text without text":   {Kind: MessageKindText, Text: "   "},
+		"text too long":       {Kind: MessageKindText, Text: strings.Repeat("я", MaxMessageTextLength+1)},
+		"delayed no minutes":  {Kind: MessageKindDelayed},
+		"delayed zero":        {Kind: MessageKindDelayed, DelayMinutes: delay(0)},
+		"delayed too long":    {Kind: MessageKindDelayed, DelayMinutes: delay(MaxMessageDelayMinutes + 1)},
+		"minutes not delayed": {Kind: MessageKindArrived, DelayMinutes: delay(5)},
+	}
+	for name, req := range cases {
+		assert.Equal(t, ErrInvalidMessage, req.Validate(), name)
+	}
+}
+
+func TestMessageReceiptRequest_Validate(t *testing.T) {
+	assert.NoError(t, (&MessageReceiptRequest{MessageIDs: []uuid.UUID{uuid.New()}, Status: ReceiptRead}).Validate())
+
+	tooMany := make([]uuid.UUID, MaxReceiptBatch+1)
+	cases := map[string]*MessageReceiptRequest{
+		"unknown status": {MessageIDs: []uuid.UUID{uuid.New()}, Status: "seen"},
+		"no ids":         {Status: ReceiptDelivered},
+		"too many ids":   {MessageIDs: tooMany, Status: ReceiptDelivered},
+	}
+	for name, req := range cases {
+		assert.Equal(t, ErrInvalidMessageReceipt, req.Validate(), name)
+	}
+}
+
+func TestDriverMessage_Acknowledge(t *testing.T) {
+	message := NewDriverMessage(uuid.New(), MessageSenderDriver, "", &SendMessageRequest{Kind: MessageKindArrived})
+	assert.Equal(t, MessageSenderDispatcher, message.Recipient())
+
+	delivered := time.Now()
+	assert.True(t, message.Acknowledge(ReceiptDelivered, delivered))
+	assert.False(t, message.Acknowledge(ReceiptDelivered, delivered.Add(time.Minute)))
+	assert.Nil(t, message.ReadAt)
+
+	read := delivered.Add(2 * time.Minute)
+	assert.True(t, message.Acknowledge(ReceiptRead, read))
+	assert.Equal(t, delivered, *message.DeliveredAt, "delivery time is kept")
+	assert.Equal(t, read, *message.ReadAt)
+	assert.False(t, message.Acknowledge(ReceiptRead, read.Add(time.Minute)))
+
+	// Прочтение без отдельной доставки ставит обе отметки
+	reply := NewDriverMessage(message.DriverID, MessageSenderDispatcher, "dispatcher-1", &SendMessageRequest{Kind: MessageKindCallMe})
+	assert.Equal(t, MessageSenderDriver, reply.Recipient())
+	assert.True(t, reply.Acknowledge(ReceiptRead, read))
+	assert.Equal(t, read, *reply.DeliveredAt)
+	assert.Equal(t, read, *reply.ReadAt)
+}
@@ -0,0 +1,46 @@
+package entities
+
+import "github.com/google/uuid"
+
+// NotificationChannel канал доставки уведомлений водителю
+type NotificationChannel string
+
+const (
+	NotificationChannelSMS   NotificationChannel = "sms"
+	NotificationChannelPush  NotificationChannel = "push"
+	NotificationChannelEmail NotificationChannel = "email"
+)
+
+// IsValid проверяет, известен ли канал
+func (c NotificationChannel) IsValid() bool {
+	switch c {
+	case NotificationChannelSMS, NotificationChannelPush, NotificationChannelEmail:
+		return true
+	}
+	return false
+}
+
+// NotificationTemplate шаблон уведомления. Subject и Body задаются в синтаксисе text/template
+// и получают данные уведомления вместе с именем водителя (first_name)
+type NotificationTemplate struct {
+	Name string `json:"name"`
+	// Channels каналы доставки; пусто — каналы по умолчанию
+	Channels []NotificationChannel `json:"channels,omitempty"`
+	// Subject тема письма и заголовок push-уведомления; в SMS не передается
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// NotificationRecipient адресат уведомления; каждый канал берет свой адрес
+type NotificationRecipient struct {
+	DriverID uuid.UUID
+	Phone    string
+	Email    string
+}
+
+// NotificationMessage уведомление, подготовленное по шаблону
+type NotificationMessage struct {
+	Template string
+	Subject  string
+	Body     string
+}
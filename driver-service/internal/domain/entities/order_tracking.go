@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// orderTrackingTokenPurpose назначение токенов ссылок отслеживания заказа
+const orderTrackingTokenPurpose = "order-tracking"
+
+const (
+	// TrackingLocationDecimals знаков после запятой в координатах для клиента (около 100 м):
+	// клиент видит подъезд машины, но не точное положение водителя
+	TrackingLocationDecimals = 3
+	// TrackingLocationAccuracy точность точки для клиента в метрах
+	TrackingLocationAccuracy = 100.0
+)
+
+// OrderTrackingSession отслеживание заказа: пока оно идет, клиент по ссылке видит
+// местоположение назначенного водителя
+type OrderTrackingSession struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	OrderID   uuid.UUID  `json:"order_id" db:"order_id"`
+	DriverID  uuid.UUID  `json:"driver_id" db:"driver_id"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	StoppedAt *time.Time `json:"stopped_at,omitempty" db:"stopped_at"`
+	// ExpiresAt после этого времени ссылка не действует, даже если отслеживание не остановлено
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	CreatedBy string    `json:"created_by" db:"created_by"`
+}
+
+// NewOrderTrackingSession начинает отслеживание заказа orderID водителем driverID на срок ttl
+func NewOrderTrackingSession(driverID, orderID uuid.UUID, createdBy string, ttl time.Duration, now time.Time) (*OrderTrackingSession, error) {
+	if driverID == uuid.Nil || orderID == uuid.Nil || ttl <= 0 {
+		return nil, ErrInvalidOrderTracking
+	}
+
+	return &OrderTrackingSession{
+		ID:        uuid.New(),
+		OrderID:   orderID,
+		DriverID:  driverID,
+		StartedAt: now,
+		ExpiresAt: now.Add(ttl).UTC().Truncate(time.Second),
+		CreatedBy: createdBy,
+	}, nil
+}
+
+// CheckActive проверяет, что заказ еще отслеживается в момент at
+func (s *OrderTrackingSession) CheckActive(at time.Time) error {
+	if s.StoppedAt != nil || !at.Before(s.ExpiresAt) {
+		return ErrOrderTrackingInactive
+	}
+	return nil
+}
+
+// Stop останавливает отслеживание
+func (s *OrderTrackingSession) Stop(at time.Time) error {
+	if s.StoppedAt != nil {
+		return ErrOrderTrackingInactive
+	}
+	s.StoppedAt = &at
+	return nil
+}
+
+// OrderTrackingLink отслеживание заказа вместе с токеном и ссылкой для клиента
+type OrderTrackingLink struct {
+	*OrderTrackingSession
+	Token string `json:"token"`
+	// Link ссылка для клиента (tracking.link_base_url с параметром token)
+	Link string `json:"link"`
+}
+
+// TrackedLocation местоположение водителя для клиента: огрубленные координаты без данных,
+// по которым можно уточнить положение или узнать водителя
+type TrackedLocation struct {
+	OrderID    uuid.UUID `json:"order_id"`
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	Accuracy   float64   `json:"accuracy"`
+	RecordedAt time.Time `json:"recorded_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// NewTrackedLocation огрубляет точку водителя до TrackingLocationDecimals знаков
+func NewTrackedLocation(session *OrderTrackingSession, location *DriverLocation) *TrackedLocation {
+	return &TrackedLocation{
+		OrderID:    session.OrderID,
+		Latitude:   roundCoordinate(location.Latitude, TrackingLocationDecimals),
+		Longitude:  roundCoordinate(location.Longitude, TrackingLocationDecimals),
+		Accuracy:   TrackingLocationAccuracy,
+		RecordedAt: location.RecordedAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
+
+// SignOrderTrackingToken выпускает токен ссылки отслеживания: ID отслеживания и срок действия,
+// подписанные HMAC-SHA256 секретом secret
+func SignOrderTrackingToken(secret []byte, session *OrderTrackingSession) string {
+	return signToken(secret, orderTrackingTokenPurpose, session.ID, session.ExpiresAt)
+}
+
+// ParseOrderTrackingToken проверяет подпись и срок действия токена и возвращает ID отслеживания.
+// Истекший токен отклоняется без обращения к хранилищу
+func ParseOrderTrackingToken(secret []byte, token string, now time.Time) (uuid.UUID, error) {
+	sessionID, expiresAt, ok := parseSignedToken(secret, orderTrackingTokenPurpose, token)
+	if !ok {
+		return uuid.Nil, ErrInvalidTrackingToken
+	}
+	if !now.Before(expiresAt) {
+		return uuid.Nil, ErrOrderTrackingInactive
+	}
+	return sessionID, nil
+}
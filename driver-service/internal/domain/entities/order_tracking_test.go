@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderTrackingToken(t *testing.T) {
+	secret := []byte("tracking-secret")
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	session, err := NewOrderTrackingSession(uuid.New(), uuid.New(), "dispatcher-1", 6*time.Hour, now)
+	require.NoError(t, err)
+
+	token := SignOrderTrackingToken(secret, session)
+	id, err := ParseOrderTrackingToken(secret, token, now)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, id)
+
+	_, err = ParseOrderTrackingToken(secret, token, now.Add(6*time.Hour))
+	assert.Equal(t, ErrOrderTrackingInactive, err)
+
+	for _, bad := range []string{"", "no-dot", token + "A"} {
+		_, err = ParseOrderTrackingToken(secret, bad, now)
+		assert.Equal(t, ErrInvalidTrackingToken, err, bad)
+	}
+	_, err = ParseOrderTrackingToken([]byte("other"), token, now)
+	assert.Equal(t, ErrInvalidTrackingToken, err)
+
+	// Токен приглашения с тем же секретом не открывает отслеживание, и наоборот
+	invite := &DriverInvite{ID: uuid.New(), ExpiresAt: session.ExpiresAt}
+	_, err = ParseOrderTrackingToken(secret, SignDriverInviteToken(secret, invite), now)
+	assert.Equal(t, ErrInvalidTrackingToken, err)
+	_, err = ParseDriverInviteToken(secret, token, now)
+	assert.Equal(t, ErrInvalidInviteToken, err)
+}
+
+func TestOrderTrackingSession_Lifecycle(t *testing.T) {
+	now := time.Now()
+	_, err := NewOrderTrackingSession(uuid.New(), uuid.Nil, "", time.Hour, now)
+	assert.Equal(t, ErrInvalidOrderTracking, err)
+	_, err = NewOrderTrackingSession(uuid.New(), uuid.New(), "", 0, now)
+	assert.Equal(t, ErrInvalidOrderTracking, err)
+
+	session, err := NewOrderTrackingSession(uuid.New(), uuid.New(), "", time.Hour, now)
+	require.NoError(t, err)
+	assert.NoError(t, session.CheckActive(now))
+	assert.Equal(t, ErrOrderTrackingInactive, session.CheckActive(now.Add(2*time.Hour)))
+
+	require.NoError(t, session.Stop(now))
+	assert.Equal(t, ErrOrderTrackingInactive, session.CheckActive(now))
+	assert.Equal(t, ErrOrderTrackingInactive, session.Stop(now))
+}
+
+func TestNewTrackedLocation(t *testing.T) {
+	session, err := NewOrderTrackingSession(uuid.New(), uuid.New(), "", time.Hour, time.Now())
+	require.NoError(t, err)
+	location := NewDriverLocation(session.DriverID, 55.755831, 37.617673, time.Now())
+
+	tracked := NewTrackedLocation(session, location)
+	assert.Equal(t, session.OrderID, tracked.OrderID)
+	assert.Equal(t, 55.756, tracked.Latitude)
+	assert.Equal(t, 37.618, tracked.Longitude)
+	assert.Equal(t, TrackingLocationAccuracy, tracked.Accuracy)
+	assert.Equal(t, session.ExpiresAt, tracked.ExpiresAt)
+}
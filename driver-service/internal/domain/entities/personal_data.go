@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScopePIIRead область доступа к паспортным данным, дате рождения, номеру и сроку действия
+// водительского удостоверения. Вызывающие без нее получают профиль водителя без этих полей
+const ScopePIIRead = "pii:read"
+
+// ExportFormat формат выгрузки данных водителя
+type ExportFormat string
+
+const (
+	ExportFormatJSON ExportFormat = "json"
+	// ExportFormatZIP архив с отдельным JSON-файлом на каждый раздел выгрузки
+	ExportFormatZIP ExportFormat = "zip"
+)
+
+const (
+	// ErasedDriverName имя, которое получает водитель после удаления персональных данных
+	ErasedDriverName = "Удален"
+	// DriverMetaPersonalDataErasedAt ключ метаданных со временем удаления персональных данных
+	DriverMetaPersonalDataErasedAt = "personal_data_erased_at"
+)
+
+// erasedMetadataKeys ключи метаданных, которые сохраняются после удаления персональных
+// данных: по ним строятся сводные показатели городов
+var erasedMetadataKeys = []string{DriverMetaCity}
+
+// DriverDataExport выгрузка всех данных водителя по запросу субъекта персональных данных
+type DriverDataExport struct {
+	ExportedAt time.Time         `json:"exported_at"`
+	Driver     *Driver           `json:"driver"`
+	Documents  []*DriverDocument `json:"documents"`
+	Locations  []*DriverLocation `json:"locations"`
+	// LocationSummaries прореженная история местоположений старше срока хранения исходных точек
+	LocationSummaries []*LocationSummary `json:"location_summaries"`
+	// Messages переписка с диспетчерами в пределах срока хранения сообщений
+	Messages    []*DriverMessage `json:"messages"`
+	Ratings     []*DriverRating  `json:"ratings"`
+	RatingStats *RatingStats     `json:"rating_stats,omitempty"`
+	Shifts      []*DriverShift   `json:"shifts"`
+}
+
+// Sections возвращает разделы выгрузки по именам файлов архива
+func (e *DriverDataExport) Sections() map[string]interface{} {
+	return map[string]interface{}{
+		"profile.json":            e.Driver,
+		"documents.json":          e.Documents,
+		"locations.json":          e.Locations,
+		"location_summaries.json": e.LocationSummaries,
+		"messages.json":           e.Messages,
+		"ratings.json": map[string]interface{}{
+			"ratings": e.Ratings,
+			"stats":   e.RatingStats,
+		},
+		"shifts.json": e.Shifts,
+	}
+}
+
+// PersonalDataErasure результат удаления персональных данных водителя
+type PersonalDataErasure struct {
+	DriverID uuid.UUID `json:"driver_id"`
+	// RequestedBy кто запросил удаление: субъект токена водителя или администратора
+	RequestedBy              string    `json:"requested_by"`
+	LocationsDeleted         int       `json:"locations_deleted"`
+	LocationSummariesDeleted int       `json:"location_summaries_deleted"`
+	MessagesDeleted          int       `json:"messages_deleted"`
+	DocumentsErased          int       `json:"documents_erased"`
+	FilesDeleted             int       `json:"files_deleted"`
+	RatingsAnonymized        int       `json:"ratings_anonymized"`
+	ShiftsAnonymized         int       `json:"shifts_anonymized"`
+	ErasedAt                 time.Time `json:"erased_at"`
+}
+
+// Anonymize удаляет персональные данные водителя. Рейтинг, число поездок, город
+// и автопарк сохраняются для сводной статистики. Телефон, email и номер удостоверения
+// уникальны в базе, поэтому заменяются обезличенными значениями на основе ID
+func (d *Driver) Anonymize(now time.Time) {
+	token := strings.ReplaceAll(d.ID.String(), "-", "")
+	d.Phone = "erased-" + token[:13]
+	d.Email = token + "@erased.invalid"
+	d.FirstName = ErasedDriverName
+	d.LastName = ""
+	d.MiddleName = nil
+	d.BirthDate = time.Time{}
+	d.PassportSeries = ""
+	d.PassportNumber = ""
+	d.LicenseNumber = "ERASED-" + token[:13]
+	d.PaymentHoldReason = nil
+
+	metadata := Metadata{DriverMetaPersonalDataErasedAt: now.UTC().Format(time.RFC3339)}
+	for _, key := range erasedMetadataKeys {
+		if value, ok := d.Metadata[key]; ok {
+			metadata[key] = value
+		}
+	}
+	d.Metadata = metadata
+}
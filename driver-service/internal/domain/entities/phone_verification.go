@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// phoneVerificationCodeDigits длина кода подтверждения телефона
+const phoneVerificationCodeDigits = 6
+
+// PhoneVerification отправленный водителю код подтверждения телефона. Хранится только хеш
+// кода; у водителя не больше одного действующего кода, новая отправка заменяет прежний
+type PhoneVerification struct {
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	// Phone номер, на который отправлен код; после смены номера код недействителен
+	Phone    string `json:"-" db:"phone"`
+	CodeHash string `json:"-" db:"code_hash"`
+	// Attempts сколько раз код вводился, включая неверные попытки
+	Attempts  int       `json:"attempts" db:"attempts"`
+	ExpiresAt time.Time `json:"expires_at" db:"expires_at"`
+	SentAt    time.Time `json:"sent_at" db:"sent_at"`
+}
+
+// NewPhoneVerification создает код подтверждения номера phone, действующий ttl, и возвращает
+// его вместе с открытым кодом для отправки водителю
+func NewPhoneVerification(driverID uuid.UUID, phone string, now time.Time, ttl time.Duration) (*PhoneVerification, string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < phoneVerificationCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate verification code: %w", err)
+	}
+	code := fmt.Sprintf("%0*d", phoneVerificationCodeDigits, n.Int64())
+
+	return &PhoneVerification{
+		DriverID:  driverID,
+		Phone:     phone,
+		CodeHash:  hashPhoneVerificationCode(driverID, code),
+		ExpiresAt: now.Add(ttl),
+		SentAt:    now,
+	}, code, nil
+}
+
+// hashPhoneVerificationCode хеш кода, привязанный к водителю
+func hashPhoneVerificationCode(driverID uuid.UUID, code string) string {
+	sum := sha256.Sum256([]byte(driverID.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches сравнивает введенный код с отправленным за постоянное время
+func (v *PhoneVerification) Matches(code string) bool {
+	hash := hashPhoneVerificationCode(v.DriverID, strings.TrimSpace(code))
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(v.CodeHash)) == 1
+}
+
+// IsExpired проверяет, истек ли срок действия кода
+func (v *PhoneVerification) IsExpired(now time.Time) bool {
+	return !now.Before(v.ExpiresAt)
+}
+
+// ResendAfter время, раньше которого новый код не отправляется
+func (v *PhoneVerification) ResendAfter(interval time.Duration) time.Time {
+	return v.SentAt.Add(interval)
+}
+
+// PhoneVerificationSent ответ на отправку кода подтверждения
+type PhoneVerificationSent struct {
+	DriverID  uuid.UUID `json:"driver_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ResendAfter раньше этого времени повторная отправка вернет ошибку
+	ResendAfter time.Time `json:"resend_after"`
+}
+
+// ConfirmPhoneRequest запрос подтверждения телефона кодом
+type ConfirmPhoneRequest struct {
+	Code string `json:"code" binding:"required"`
+}
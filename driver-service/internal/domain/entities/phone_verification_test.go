@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPhoneVerification(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	verification, code, err := NewPhoneVerification(uuid.New(), "+79001234567", now, 10*time.Minute)
+	require.NoError(t, err)
+
+	assert.Regexp(t, `^\d{6}$`, code)
+	assert.NotContains(t, verification.CodeHash, code)
+	assert.True(t, verification.Matches(" "+code+" "))
+	assert.False(t, verification.Matches(code+"0"))
+
+	// Хеш привязан к водителю: тот же код другого водителя не совпадает
+	other := *verification
+	other.DriverID = uuid.New()
+	assert.False(t, other.Matches(code))
+
+	assert.False(t, verification.IsExpired(now.Add(9*time.Minute)))
+	assert.True(t, verification.IsExpired(now.Add(10*time.Minute)))
+	assert.Equal(t, now.Add(time.Minute), verification.ResendAfter(time.Minute))
+}
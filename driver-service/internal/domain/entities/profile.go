@@ -0,0 +1,194 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileStage этап заполнения профиля водителя
+type ProfileStage string
+
+const (
+	// ProfileStageRegistration минимальные данные для регистрации
+	ProfileStageRegistration ProfileStage = "registration"
+	// ProfileStageVerification данные, необходимые для отправки на верификацию
+	ProfileStageVerification ProfileStage = "verification"
+	// ProfileStageActivation данные и документы, необходимые для выхода на линию
+	ProfileStageActivation ProfileStage = "activation"
+)
+
+// profileStages этапы профиля в порядке прохождения
+var profileStages = []ProfileStage{
+	ProfileStageRegistration,
+	ProfileStageVerification,
+	ProfileStageActivation,
+}
+
+// stageIndex возвращает порядковый номер этапа
+func stageIndex(stage ProfileStage) int {
+	for i, s := range profileStages {
+		if s == stage {
+			return i
+		}
+	}
+	return 0
+}
+
+// StageForStatus возвращает этап профиля, требования которого действуют для статуса водителя
+func StageForStatus(status Status) ProfileStage {
+	switch status {
+	case StatusPendingVerification, StatusVerified:
+		return ProfileStageVerification
+	case StatusAvailable, StatusOnShift, StatusBusy, StatusInactive, StatusSuspended:
+		return ProfileStageActivation
+	default:
+		return ProfileStageRegistration
+	}
+}
+
+// profileRequirement требование к профилю водителя
+type profileRequirement struct {
+	item      string
+	stage     ProfileStage
+	satisfied func(d *Driver, documents []*DriverDocument) bool
+}
+
+// profileRequirements требования к профилю по этапам
+var profileRequirements = []profileRequirement{
+	{"phone", ProfileStageRegistration, func(d *Driver, _ []*DriverDocument) bool { return d.Phone != "" }},
+	{"first_name", ProfileStageRegistration, func(d *Driver, _ []*DriverDocument) bool { return d.FirstName != "" }},
+	{"last_name", ProfileStageRegistration, func(d *Driver, _ []*DriverDocument) bool { return d.LastName != "" }},
+
+	{"email", ProfileStageVerification, func(d *Driver, _ []*DriverDocument) bool { return d.Email != "" }},
+	{"birth_date", ProfileStageVerification, func(d *Driver, _ []*DriverDocument) bool { return !d.BirthDate.IsZero() }},
+	{"passport", ProfileStageVerification, func(d *Driver, _ []*DriverDocument) bool {
+		return d.PassportSeries != "" && d.PassportNumber != ""
+	}},
+	{"license_number", ProfileStageVerification, func(d *Driver, _ []*DriverDocument) bool { return d.LicenseNumber != "" }},
+	{"license_expiry", ProfileStageVerification, func(d *Driver, _ []*DriverDocument) bool { return !d.LicenseExpiry.IsZero() }},
+
+	{"license_valid", ProfileStageActivation, func(d *Driver, _ []*DriverDocument) bool {
+		return !d.LicenseExpiry.IsZero() && !d.IsLicenseExpired()
+	}},
+	{"documents.driver_license", ProfileStageActivation, func(_ *Driver, documents []*DriverDocument) bool {
+		return hasVerifiedDocument(documents, DocumentTypeDriverLicense)
+	}},
+	{"documents.passport", ProfileStageActivation, func(_ *Driver, documents []*DriverDocument) bool {
+		return hasDocument(documents, DocumentTypePassport)
+	}},
+}
+
+// hasDocument проверяет, загружен ли действующий документ указанного типа
+func hasDocument(documents []*DriverDocument, docType DocumentType) bool {
+	for _, doc := range documents {
+		if doc.DocumentType == docType && doc.Status != VerificationStatusRejected && !doc.IsExpired() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasVerifiedDocument проверяет наличие верифицированного документа указанного типа
+func hasVerifiedDocument(documents []*DriverDocument, docType DocumentType) bool {
+	for _, doc := range documents {
+		if doc.DocumentType == docType && doc.IsVerified() {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateForStage проверяет поля профиля, обязательные на указанном и предыдущих этапах.
+// Документы здесь не проверяются: их наличие учитывается в оценке заполненности профиля.
+func (d *Driver) ValidateForStage(stage ProfileStage) error {
+	if d.Phone == "" {
+		return ErrInvalidPhone
+	}
+
+	if d.FirstName == "" || d.LastName == "" {
+		return ErrInvalidName
+	}
+
+	if stageIndex(stage) < stageIndex(ProfileStageVerification) {
+		return nil
+	}
+
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	if d.BirthDate.IsZero() {
+		return ErrInvalidBirthDate
+	}
+
+	if d.LicenseExpiry.IsZero() {
+		return ErrInvalidLicense
+	}
+
+	if stage == ProfileStageActivation && d.IsLicenseExpired() {
+		return ErrLicenseExpired
+	}
+
+	return nil
+}
+
+// MissingProfileItem незаполненный элемент профиля
+type MissingProfileItem struct {
+	Item  string       `json:"item"`
+	Stage ProfileStage `json:"stage"`
+}
+
+// ProfileCompleteness оценка заполненности профиля водителя
+type ProfileCompleteness struct {
+	DriverID uuid.UUID `json:"driver_id"`
+	// Score процент выполненных требований всех этапов
+	Score int `json:"score"`
+	// CurrentStage этап, требования которого действуют для текущего статуса
+	CurrentStage ProfileStage `json:"current_stage"`
+	// CompletedStage последний полностью пройденный этап
+	CompletedStage *ProfileStage `json:"completed_stage,omitempty"`
+	// NextStage следующий этап, который предстоит пройти
+	NextStage   *ProfileStage        `json:"next_stage,omitempty"`
+	Missing     []MissingProfileItem `json:"missing"`
+	EvaluatedAt time.Time            `json:"evaluated_at"`
+}
+
+// IsStageComplete проверяет, выполнены ли все требования этапа и предыдущих этапов
+func (p *ProfileCompleteness) IsStageComplete(stage ProfileStage) bool {
+	return p.CompletedStage != nil && stageIndex(*p.CompletedStage) >= stageIndex(stage)
+}
+
+// EvaluateProfile оценивает заполненность профиля водителя с учетом загруженных документов
+func EvaluateProfile(driver *Driver, documents []*DriverDocument) *ProfileCompleteness {
+	result := &ProfileCompleteness{
+		DriverID:     driver.ID,
+		CurrentStage: StageForStatus(driver.Status),
+		Missing:      make([]MissingProfileItem, 0),
+		EvaluatedAt:  time.Now(),
+	}
+
+	incomplete := make(map[ProfileStage]bool)
+	satisfied := 0
+	for _, req := range profileRequirements {
+		if req.satisfied(driver, documents) {
+			satisfied++
+			continue
+		}
+		incomplete[req.stage] = true
+		result.Missing = append(result.Missing, MissingProfileItem{Item: req.item, Stage: req.stage})
+	}
+
+	result.Score = satisfied * 100 / len(profileRequirements)
+
+	for i := range profileStages {
+		stage := profileStages[i]
+		if incomplete[stage] {
+			result.NextStage = &stage
+			break
+		}
+		result.CompletedStage = &stage
+	}
+
+	return result
+}
@@ -0,0 +1,223 @@
+package entities
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ProfileChangeStatus статус заявки на изменение профиля
+type ProfileChangeStatus string
+
+const (
+	// ProfileChangeStatusPending заявка ожидает решения администратора
+	ProfileChangeStatusPending ProfileChangeStatus = "pending"
+	// ProfileChangeStatusApproved заявка одобрена, изменения внесены в профиль
+	ProfileChangeStatusApproved ProfileChangeStatus = "approved"
+	// ProfileChangeStatusRejected заявка отклонена, профиль не изменился
+	ProfileChangeStatusRejected ProfileChangeStatus = "rejected"
+)
+
+// MaxProfileChangeCommentLength максимальная длина комментария к заявке и решению в символах
+const MaxProfileChangeCommentLength = 500
+
+// legalProfileFields юридически значимые поля профиля: ФИО и данные паспорта и водительского
+// удостоверения. Заполненные значения водитель меняет только заявкой, которую одобряет администратор
+var legalProfileFields = map[string]bool{
+	"first_name":      true,
+	"last_name":       true,
+	"middle_name":     true,
+	"passport_series": true,
+	"passport_number": true,
+	"license_number":  true,
+}
+
+// IsLegalProfileField проверяет, меняется ли поле профиля только через заявку
+func IsLegalProfileField(name string) bool {
+	return legalProfileFields[name]
+}
+
+// LegalProfileChanges возвращает юридически значимые поля, которые были заполнены в before
+// и отличаются в after, в алфавитном порядке. Заполнение пустого поля изменением не считается
+func LegalProfileChanges(before, after *Driver) []string {
+	var fields []string
+	for name := range legalProfileFields {
+		previous, current := legalFieldValue(before, name), legalFieldValue(after, name)
+		if previous != "" && previous != current {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// legalFieldValue значение юридически значимого поля водителя
+func legalFieldValue(d *Driver, name string) string {
+	switch name {
+	case "first_name":
+		return d.FirstName
+	case "last_name":
+		return d.LastName
+	case "middle_name":
+		if d.MiddleName == nil {
+			return ""
+		}
+		return *d.MiddleName
+	case "passport_series":
+		return d.PassportSeries
+	case "passport_number":
+		return d.PassportNumber
+	case "license_number":
+		return d.LicenseNumber
+	}
+	return ""
+}
+
+// ProfileChange заявка на изменение юридически значимых полей профиля. Новые значения
+// хранятся в заявке и переносятся в профиль водителя только после одобрения
+type ProfileChange struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	// Changes новые значения полей: имя поля — строковое значение; пустое отчество очищает его
+	Changes Metadata            `json:"changes" db:"changes"`
+	Comment string              `json:"comment,omitempty" db:"comment"`
+	Status  ProfileChangeStatus `json:"status" db:"status"`
+	// RequestedBy субъект токена автора заявки; пустой, если заявка подана без аутентификации
+	RequestedBy *string   `json:"requested_by,omitempty" db:"requested_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	// Решение администратора
+	ReviewedBy    *string    `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ReviewComment *string    `json:"review_comment,omitempty" db:"review_comment"`
+}
+
+// IsPending проверяет, ожидает ли заявка решения
+func (c *ProfileChange) IsPending() bool {
+	return c.Status == ProfileChangeStatusPending
+}
+
+// Fields возвращает изменяемые поля в алфавитном порядке
+func (c *ProfileChange) Fields() []string {
+	names := make([]string, 0, len(c.Changes))
+	for name := range c.Changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Apply проверяет значения заявки и применяет их к водителю. При ошибке водитель не изменяется
+func (c *ProfileChange) Apply(d *Driver) error {
+	patched := *d
+	for _, name := range c.Fields() {
+		if !IsLegalProfileField(name) {
+			return ErrInvalidProfileChange
+		}
+		value, ok := c.Changes[name].(string)
+		if !ok {
+			return ErrInvalidProfileChange
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return ErrInvalidProfileChange
+		}
+		if err := patched.applyPatchField(name, raw); err != nil {
+			return err
+		}
+	}
+	*d = patched
+	return nil
+}
+
+// Resolve сохраняет решение администратора
+func (c *ProfileChange) Resolve(status ProfileChangeStatus, reviewerID, comment string, at time.Time) {
+	c.Status = status
+	c.ReviewedBy = &reviewerID
+	c.ReviewedAt = &at
+	c.ReviewComment = nil
+	if comment != "" {
+		c.ReviewComment = &comment
+	}
+}
+
+// ProfileChangeRequest запрос водителя на изменение юридически значимых полей
+type ProfileChangeRequest struct {
+	Changes map[string]string `json:"changes" binding:"required"`
+	Comment string            `json:"comment,omitempty"`
+}
+
+// Validate нормализует запрос и проверяет его против текущего профиля: поля должны быть
+// юридически значимыми, значения — допустимыми, и хотя бы одно значение должно отличаться
+func (r *ProfileChangeRequest) Validate(current *Driver) error {
+	r.Comment = strings.TrimSpace(r.Comment)
+	if len(r.Changes) == 0 || utf8.RuneCountInString(r.Comment) > MaxProfileChangeCommentLength {
+		return ErrInvalidProfileChange
+	}
+
+	changes := make(map[string]string, len(r.Changes))
+	for name, value := range r.Changes {
+		if !IsLegalProfileField(name) {
+			return ErrInvalidProfileChange
+		}
+		value = strings.TrimSpace(value)
+		// Значения, совпадающие с текущими, в заявку не попадают
+		if value != legalFieldValue(current, name) {
+			changes[name] = value
+		}
+	}
+	if len(changes) == 0 {
+		return ErrInvalidProfileChange
+	}
+	r.Changes = changes
+
+	// Значения проверяются теми же правилами, что и при частичном обновлении профиля
+	probe := *current
+	return r.changeSet().Apply(&probe)
+}
+
+// changeSet заявка с полями запроса без идентификаторов и статуса
+func (r *ProfileChangeRequest) changeSet() *ProfileChange {
+	changes := make(Metadata, len(r.Changes))
+	for name, value := range r.Changes {
+		changes[name] = value
+	}
+	return &ProfileChange{Changes: changes}
+}
+
+// NewProfileChange создает заявку водителя на изменение профиля с автором из контекста запроса
+func NewProfileChange(driverID uuid.UUID, req *ProfileChangeRequest, actor AuditActor, now time.Time) *ProfileChange {
+	change := req.changeSet()
+	change.ID = uuid.New()
+	change.DriverID = driverID
+	change.Comment = req.Comment
+	change.Status = ProfileChangeStatusPending
+	change.CreatedAt = now
+	if actor.Subject != "" {
+		change.RequestedBy = &actor.Subject
+	}
+	return change
+}
+
+// ProfileChangeReviewRequest решение администратора по заявке. При отклонении комментарий
+// обязателен: водитель видит в нем причину
+type ProfileChangeReviewRequest struct {
+	Comment    string `json:"comment,omitempty"`
+	ReviewerID string `json:"-"`
+}
+
+// Validate нормализует и проверяет решение; requireComment — комментарий обязателен
+func (r *ProfileChangeReviewRequest) Validate(requireComment bool) error {
+	r.Comment = strings.TrimSpace(r.Comment)
+	r.ReviewerID = strings.TrimSpace(r.ReviewerID)
+	if r.ReviewerID == "" || utf8.RuneCountInString(r.Comment) > MaxProfileChangeCommentLength {
+		return ErrInvalidProfileChange
+	}
+	if requireComment && r.Comment == "" {
+		return ErrInvalidProfileChange
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegalProfileChanges(t *testing.T) {
+	middleName := "Петрович"
+	before := &Driver{FirstName: "Иван", LastName: "Иванов", PassportSeries: "1234", LicenseNumber: ""}
+
+	after := *before
+	after.Email = "new@example.com"
+	after.LicenseNumber = "LIC1"
+	after.MiddleName = &middleName
+	assert.Empty(t, LegalProfileChanges(before, &after), "filling empty fields and other fields need no approval")
+
+	after.LastName = "Петров"
+	after.PassportSeries = "4321"
+	assert.Equal(t, []string{"last_name", "passport_series"}, LegalProfileChanges(before, &after))
+
+	cleared := *before
+	cleared.MiddleName = &middleName
+	assert.Equal(t, []string{"middle_name"}, LegalProfileChanges(&cleared, before))
+}
+
+func TestProfileChangeRequest_Validate(t *testing.T) {
+	middleName := "Петрович"
+	driver := &Driver{FirstName: "Иван", LastName: "Иванов", MiddleName: &middleName, PassportSeries: "1234", PassportNumber: "567890", LicenseNumber: "LIC1"}
+
+	req := &ProfileChangeRequest{
+		Changes: map[string]string{"last_name": " Петров ", "first_name": "Иван", "middle_name": ""},
+		Comment: "  Смена фамилии  ",
+	}
+	require.NoError(t, req.Validate(driver))
+	assert.Equal(t, map[string]string{"last_name": "Петров", "middle_name": ""}, req.Changes)
+	assert.Equal(t, "Смена фамилии", req.Comment)
+
+	change := NewProfileChange(driver.ID, req, AuditActor{Subject: "driver-1"}, driver.CreatedAt)
+	assert.True(t, change.IsPending())
+	patched := *driver
+	require.NoError(t, change.Apply(&patched))
+	assert.Equal(t, "Петров", patched.LastName)
+	assert.Nil(t, patched.MiddleName)
+	assert.Equal(t, "Иванов", driver.LastName, "apply works on the given driver only")
+
+	for name, changes := range map[string]map[string]string{
+		"empty":        {},
+		"not legal":    {"email": "new@example.com"},
+		"unchanged":    {"last_name": "Иванов"},
+		"empty name":   {"first_name": " "},
+		"empty number": {"license_number": ""},
+	} {
+		req := &ProfileChangeRequest{Changes: changes}
+		assert.Error(t, req.Validate(driver), name)
+	}
+}
+
+func TestProfileChangeReviewRequest_Validate(t *testing.T) {
+	assert.Equal(t, ErrInvalidProfileChange, (&ProfileChangeReviewRequest{}).Validate(false))
+	assert.NoError(t, (&ProfileChangeReviewRequest{ReviewerID: "admin-42"}).Validate(false))
+	assert.Equal(t, ErrInvalidProfileChange, (&ProfileChangeReviewRequest{ReviewerID: "admin-42", Comment: " "}).Validate(true))
+	assert.NoError(t, (&ProfileChangeReviewRequest{ReviewerID: "admin-42", Comment: "Не совпадает с паспортом"}).Validate(true))
+}
@@ -1,6 +1,10 @@
 package entities
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +21,46 @@ const (
 	RatingTypeAutomatic    RatingType = "automatic"
 )
 
+// IsValid проверяет, известен ли тип оценки
+func (t RatingType) IsValid() bool {
+	switch t {
+	case RatingTypeCustomer, RatingTypeSystem, RatingTypeAdmin, RatingTypePeer, RatingTypeAutomatic:
+		return true
+	}
+	return false
+}
+
+// CriteriaScores оценки по критериям в формате JSON
+type CriteriaScores map[string]int
+
+// Value реализует интерфейс driver.Valuer для сериализации в БД
+func (c CriteriaScores) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c)
+}
+
+// Scan реализует интерфейс sql.Scanner для десериализации из БД
+func (c *CriteriaScores) Scan(value interface{}) error {
+	if value == nil {
+		*c = make(CriteriaScores)
+		return nil
+	}
+
+	var bytes []byte
+	switch v := value.(type) {
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into CriteriaScores", value)
+	}
+
+	return json.Unmarshal(bytes, c)
+}
+
 // DriverRating представляет оценку водителя
 type DriverRating struct {
 	ID             uuid.UUID      `json:"id" db:"id"`
@@ -26,7 +70,7 @@ type DriverRating struct {
 	Rating         int            `json:"rating" db:"rating"`
 	Comment        *string        `json:"comment,omitempty" db:"comment"`
 	RatingType     RatingType     `json:"rating_type" db:"rating_type"`
-	CriteriaScores map[string]int `json:"criteria_scores" db:"criteria_scores"`
+	CriteriaScores CriteriaScores `json:"criteria_scores" db:"criteria_scores"`
 	IsVerified     bool           `json:"is_verified" db:"is_verified"`
 	IsAnonymous    bool           `json:"is_anonymous" db:"is_anonymous"`
 	Metadata       Metadata       `json:"metadata" db:"metadata"`
@@ -59,6 +103,49 @@ func (r *DriverRating) GetOverallScore() float64 {
 	return float64(total) / float64(count)
 }
 
+// RatingFlagCustomerAllOneStar клиент ставит только оценки 1
+const RatingFlagCustomerAllOneStar = "customer_all_one_star"
+
+// FlagSuspicious отмечает оценку подозрительной; такая оценка не учитывается в рейтинге водителя
+func (r *DriverRating) FlagSuspicious(reason string) {
+	if r.Metadata == nil {
+		r.Metadata = make(Metadata)
+	}
+	r.Metadata["suspicious"] = true
+	r.Metadata["suspicious_reason"] = reason
+}
+
+// IsSuspicious проверяет, отмечена ли оценка подозрительной
+func (r *DriverRating) IsSuspicious() bool {
+	suspicious, _ := r.Metadata["suspicious"].(bool)
+	return suspicious
+}
+
+// NoRating рейтинг водителя, у которого нет учитываемых оценок
+const NoRating = 0.0
+
+// DecayedRatingAverage средневзвешенная оценка, в которой вес оценки уменьшается вдвое каждые
+// halfLife с момента ее создания; halfLife <= 0 — простое среднее. Подозрительные оценки не
+// учитываются. Возвращает false, если учитывать нечего
+func DecayedRatingAverage(ratings []*DriverRating, now time.Time, halfLife time.Duration) (float64, bool) {
+	var sum, weights float64
+	for _, rating := range ratings {
+		if rating.IsSuspicious() {
+			continue
+		}
+		weight := 1.0
+		if age := now.Sub(rating.CreatedAt); halfLife > 0 && age > 0 {
+			weight = math.Exp2(-float64(age) / float64(halfLife))
+		}
+		sum += weight * float64(rating.Rating)
+		weights += weight
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return math.Round(sum/weights*100) / 100, true
+}
+
 // Verify верифицирует оценку
 func (r *DriverRating) Verify() {
 	r.IsVerified = true
@@ -93,7 +180,7 @@ func NewDriverRating(driverID uuid.UUID, rating int, ratingType RatingType) *Dri
 		DriverID:       driverID,
 		Rating:         rating,
 		RatingType:     ratingType,
-		CriteriaScores: make(map[string]int),
+		CriteriaScores: make(CriteriaScores),
 		IsVerified:     false,
 		IsAnonymous:    false,
 		Metadata:       make(Metadata),
@@ -241,7 +328,10 @@ type RatingFilters struct {
 
 // RatingRequest запрос на добавление оценки
 type RatingRequest struct {
+	OrderID        *uuid.UUID     `json:"order_id,omitempty"`
+	CustomerID     *uuid.UUID     `json:"customer_id,omitempty"`
 	Rating         int            `json:"rating" binding:"required,min=1,max=5"`
+	RatingType     RatingType     `json:"rating_type,omitempty"`
 	Comment        *string        `json:"comment,omitempty"`
 	CriteriaScores map[string]int `json:"criteria_scores,omitempty"`
 	IsAnonymous    *bool          `json:"is_anonymous,omitempty"`
@@ -259,22 +349,29 @@ type RatingResponse struct {
 	CriteriaScores map[string]int `json:"criteria_scores,omitempty"`
 	IsVerified     bool           `json:"is_verified"`
 	IsAnonymous    bool           `json:"is_anonymous"`
+	Suspicious     bool           `json:"suspicious,omitempty"`
 	CreatedAt      time.Time      `json:"created_at"`
 }
 
-// ToResponse конвертирует в ответ
+// ToResponse конвертирует в ответ; у анонимной оценки клиент не раскрывается
 func (r *DriverRating) ToResponse() *RatingResponse {
+	customerID := r.CustomerID
+	if r.IsAnonymous {
+		customerID = nil
+	}
+
 	return &RatingResponse{
 		ID:             r.ID,
 		DriverID:       r.DriverID,
 		OrderID:        r.OrderID,
-		CustomerID:     r.CustomerID,
+		CustomerID:     customerID,
 		Rating:         r.Rating,
 		Comment:        r.Comment,
 		RatingType:     r.RatingType,
 		CriteriaScores: r.CriteriaScores,
 		IsVerified:     r.IsVerified,
 		IsAnonymous:    r.IsAnonymous,
+		Suspicious:     r.IsSuspicious(),
 		CreatedAt:      r.CreatedAt,
 	}
 }
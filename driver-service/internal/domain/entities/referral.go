@@ -0,0 +1,178 @@
+package entities
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReferralCodeLength длина реферального кода
+const ReferralCodeLength = 8
+
+// referralCodeAlphabet символы реферального кода без похожих друг на друга (0/O, 1/I)
+const referralCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// ReferralStatus этап реферала
+type ReferralStatus string
+
+const (
+	// ReferralStatusRegistered приглашенный водитель зарегистрирован и проходит проверку
+	ReferralStatusRegistered ReferralStatus = "registered"
+	// ReferralStatusVerified приглашенный водитель допущен к работе
+	ReferralStatusVerified ReferralStatus = "verified"
+	// ReferralStatusCompleted приглашенный водитель выполнил нужное число поездок;
+	// пригласивший получает право на выплату
+	ReferralStatusCompleted ReferralStatus = "completed"
+)
+
+// ReferralCode реферальный код водителя
+type ReferralCode struct {
+	DriverID  uuid.UUID `json:"driver_id" db:"driver_id"`
+	Code      string    `json:"code" db:"code"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewReferralCode создает случайный реферальный код водителя
+func NewReferralCode(driverID uuid.UUID, now time.Time) (*ReferralCode, error) {
+	random := make([]byte, ReferralCodeLength)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate referral code: %w", err)
+	}
+
+	code := make([]byte, ReferralCodeLength)
+	for i, b := range random {
+		code[i] = referralCodeAlphabet[int(b)%len(referralCodeAlphabet)]
+	}
+	return &ReferralCode{DriverID: driverID, Code: string(code), CreatedAt: now}, nil
+}
+
+// NormalizeReferralCode приводит введенный код к виду, в котором он хранится
+func NormalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Referral приглашение водителя другим водителем
+type Referral struct {
+	ID               uuid.UUID      `json:"id" db:"id"`
+	ReferrerID       uuid.UUID      `json:"referrer_id" db:"referrer_id"`
+	ReferredDriverID uuid.UUID      `json:"referred_driver_id" db:"referred_driver_id"`
+	Code             string         `json:"code" db:"code"`
+	Status           ReferralStatus `json:"status" db:"status"`
+	// TripsCompleted поездки приглашенного водителя на момент последней проверки
+	TripsCompleted int `json:"trips_completed" db:"trips_completed"`
+	// RequiredTrips сколько поездок нужно для выплаты; фиксируется при регистрации
+	RequiredTrips int        `json:"required_trips" db:"required_trips"`
+	RegisteredAt  time.Time  `json:"registered_at" db:"registered_at"`
+	VerifiedAt    *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// NewReferral создает реферал на этапе registered
+func NewReferral(referrerID, referredDriverID uuid.UUID, code string, requiredTrips int, now time.Time) *Referral {
+	return &Referral{
+		ID:               uuid.New(),
+		ReferrerID:       referrerID,
+		ReferredDriverID: referredDriverID,
+		Code:             code,
+		Status:           ReferralStatusRegistered,
+		RequiredTrips:    requiredTrips,
+		RegisteredAt:     now,
+		UpdatedAt:        now,
+	}
+}
+
+// IsOpen сообщает, отслеживается ли еще прогресс приглашенного водителя
+func (r *Referral) IsOpen() bool {
+	return r.Status != ReferralStatusCompleted
+}
+
+// Advance переносит прогресс приглашенного водителя: допуск к работе переводит реферал
+// в verified, а нужное число поездок после допуска — в completed. Возвращает true,
+// если реферал изменился
+func (r *Referral) Advance(referred *Driver, now time.Time) bool {
+	if !r.IsOpen() {
+		return false
+	}
+
+	changed := false
+	if referred.TotalTrips != r.TripsCompleted {
+		r.TripsCompleted = referred.TotalTrips
+		changed = true
+	}
+	if r.Status == ReferralStatusRegistered && isAdmittedStatus(referred.Status) {
+		r.Status = ReferralStatusVerified
+		r.VerifiedAt = &now
+		changed = true
+	}
+	if r.Status == ReferralStatusVerified && r.TripsCompleted >= r.RequiredTrips {
+		r.Status = ReferralStatusCompleted
+		r.CompletedAt = &now
+		changed = true
+	}
+
+	if changed {
+		r.UpdatedAt = now
+	}
+	return changed
+}
+
+// isAdmittedStatus сообщает, прошел ли водитель в этом статусе проверку документов
+func isAdmittedStatus(status Status) bool {
+	switch status {
+	case StatusVerified, StatusAvailable, StatusOnShift, StatusBusy, StatusInactive:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanBeReferred сообщает, можно ли зарегистрировать водителя приглашенным: реферал
+// засчитывается только за водителей, еще не прошедших проверку
+func (d *Driver) CanBeReferred() bool {
+	return d.Status == StatusRegistered || d.Status == StatusPendingVerification
+}
+
+// ReferralRequest регистрация приглашенного водителя
+type ReferralRequest struct {
+	ReferredDriverID uuid.UUID `json:"referred_driver_id" binding:"required"`
+	// Code реферальный код, который приглашенный водитель указал при регистрации
+	Code string `json:"code" binding:"required"`
+}
+
+// ReferralPayout рефералы одного пригласившего водителя, завершенные за период
+type ReferralPayout struct {
+	ReferrerID uuid.UUID   `json:"referrer_id"`
+	Count      int         `json:"count"`
+	Referrals  []*Referral `json:"referrals"`
+}
+
+// ReferralPayoutReport рефералы с правом на выплату, завершенные в [From, To)
+type ReferralPayoutReport struct {
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Total     int               `json:"total"`
+	Referrers []*ReferralPayout `json:"referrers"`
+}
+
+// NewReferralPayoutReport группирует завершенные рефералы по пригласившим в порядке первого
+// завершения
+func NewReferralPayoutReport(from, to time.Time, referrals []*Referral) *ReferralPayoutReport {
+	report := &ReferralPayoutReport{From: from, To: to, Referrers: []*ReferralPayout{}}
+	byReferrer := make(map[uuid.UUID]*ReferralPayout)
+	for _, referral := range referrals {
+		payout, ok := byReferrer[referral.ReferrerID]
+		if !ok {
+			payout = &ReferralPayout{ReferrerID: referral.ReferrerID}
+			byReferrer[referral.ReferrerID] = payout
+			report.Referrers = append(report.Referrers, payout)
+		}
+		payout.Referrals = append(payout.Referrals, referral)
+		payout.Count++
+		report.Total++
+	}
+	return report
+}
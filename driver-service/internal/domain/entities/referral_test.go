@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReferralCode(t *testing.T) {
+	code, err := NewReferralCode(uuid.New(), time.Now())
+	require.NoError(t, err)
+	assert.Len(t, code.Code, ReferralCodeLength)
+	for _, r := range code.Code {
+		assert.True(t, strings.ContainsRune(referralCodeAlphabet, r), "unexpected symbol %q", r)
+	}
+	assert.Equal(t, code.Code, NormalizeReferralCode(" "+strings.ToLower(code.Code)+"\n"))
+}
+
+func TestReferral_Advance(t *testing.T) {
+	now := time.Now()
+	referred := &Driver{ID: uuid.New(), Status: StatusPendingVerification}
+	referral := NewReferral(uuid.New(), referred.ID, "K7M2QX9P", 2, now)
+
+	assert.False(t, referral.Advance(referred, now))
+
+	// Поездки до допуска учитываются в счетчике, но этап не меняется
+	referred.TotalTrips = 3
+	assert.True(t, referral.Advance(referred, now))
+	assert.Equal(t, ReferralStatusRegistered, referral.Status)
+
+	// Допущенный водитель с нужным числом поездок проходит оба этапа сразу
+	referred.Status = StatusAvailable
+	assert.True(t, referral.Advance(referred, now))
+	assert.Equal(t, ReferralStatusCompleted, referral.Status)
+	require.NotNil(t, referral.VerifiedAt)
+	require.NotNil(t, referral.CompletedAt)
+
+	referred.TotalTrips = 4
+	assert.False(t, referral.Advance(referred, now))
+	assert.Equal(t, 3, referral.TripsCompleted)
+}
+
+func TestNewReferralPayoutReport(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	referrals := []*Referral{
+		{ID: uuid.New(), ReferrerID: first},
+		{ID: uuid.New(), ReferrerID: second},
+		{ID: uuid.New(), ReferrerID: first},
+	}
+
+	report := NewReferralPayoutReport(time.Time{}, time.Now(), referrals)
+	assert.Equal(t, 3, report.Total)
+	require.Len(t, report.Referrers, 2)
+	assert.Equal(t, first, report.Referrers[0].ReferrerID)
+	assert.Equal(t, 2, report.Referrers[0].Count)
+	assert.Equal(t, 1, report.Referrers[1].Count)
+}
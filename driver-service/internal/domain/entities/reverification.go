@@ -0,0 +1,249 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CampaignStatus состояние кампании перепроверки документов
+type CampaignStatus string
+
+const (
+	// CampaignActive документы водителей ждут перепроверки до срока кампании
+	CampaignActive CampaignStatus = "active"
+	// CampaignCompleted все документы перепроверены или срок кампании прошел
+	CampaignCompleted CampaignStatus = "completed"
+	// CampaignCancelled кампания отменена, неперепроверенные документы снова действуют
+	CampaignCancelled CampaignStatus = "cancelled"
+)
+
+// CampaignTargetStatus состояние перепроверки документа в кампании
+type CampaignTargetStatus string
+
+const (
+	CampaignTargetPending   CampaignTargetStatus = "pending"
+	CampaignTargetCompleted CampaignTargetStatus = "completed"
+	// CampaignTargetOverdue документ не перепроверен к сроку, водитель отстраняется
+	CampaignTargetOverdue   CampaignTargetStatus = "overdue"
+	CampaignTargetCancelled CampaignTargetStatus = "cancelled"
+)
+
+// CampaignSegment водители, документы которых перепроверяются. Пустые поля не ограничивают выборку
+type CampaignSegment struct {
+	City      string      `json:"city,omitempty"`
+	FleetID   *uuid.UUID  `json:"fleet_id,omitempty"`
+	Statuses  []Status    `json:"statuses,omitempty"`
+	DriverIDs []uuid.UUID `json:"driver_ids,omitempty"`
+}
+
+// Matches проверяет, входит ли водитель в сегмент
+func (s *CampaignSegment) Matches(driver *Driver) bool {
+	if s.City != "" {
+		city := driver.City()
+		if city == nil || NormalizeCity(*city) != NormalizeCity(s.City) {
+			return false
+		}
+	}
+	if s.FleetID != nil {
+		fleetID := driver.FleetID
+		if fleetID == nil || *fleetID != *s.FleetID {
+			return false
+		}
+	}
+	if len(s.Statuses) > 0 {
+		matched := false
+		for _, status := range s.Statuses {
+			if driver.Status == status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// Value реализует driver.Valuer для хранения сегмента в JSONB
+func (s CampaignSegment) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan реализует sql.Scanner для чтения сегмента из JSONB
+func (s *CampaignSegment) Scan(value interface{}) error {
+	if value == nil {
+		*s = CampaignSegment{}
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into CampaignSegment", value)
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// DocumentTypeList список типов документов, хранимый в JSONB
+type DocumentTypeList []DocumentType
+
+// Value реализует driver.Valuer
+func (l DocumentTypeList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *DocumentTypeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into DocumentTypeList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains проверяет наличие типа документа в списке
+func (l DocumentTypeList) Contains(docType DocumentType) bool {
+	for _, t := range l {
+		if t == docType {
+			return true
+		}
+	}
+	return false
+}
+
+// ReverificationCampaign кампания перепроверки документов водителей, например по требованию регулятора
+type ReverificationCampaign struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	Name          string           `json:"name" db:"name"`
+	Reason        string           `json:"reason,omitempty" db:"reason"`
+	Segment       CampaignSegment  `json:"segment" db:"segment"`
+	DocumentTypes DocumentTypeList `json:"document_types" db:"document_types"`
+	// Deadline срок перепроверки: водители с неперепроверенными документами отстраняются
+	Deadline time.Time `json:"deadline" db:"deadline"`
+	// ReminderIntervalDays как часто водителям напоминается о перепроверке
+	ReminderIntervalDays int            `json:"reminder_interval_days" db:"reminder_interval_days"`
+	Status               CampaignStatus `json:"status" db:"status"`
+	CreatedBy            string         `json:"created_by" db:"created_by"`
+	CreatedAt            time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at" db:"updated_at"`
+	ClosedAt             *time.Time     `json:"closed_at,omitempty" db:"closed_at"`
+}
+
+// IsDue проверяет, наступил ли срок кампании
+func (c *ReverificationCampaign) IsDue(now time.Time) bool {
+	return !now.Before(c.Deadline)
+}
+
+// Close завершает или отменяет кампанию
+func (c *ReverificationCampaign) Close(status CampaignStatus, now time.Time) {
+	c.Status = status
+	c.ClosedAt = &now
+	c.UpdatedAt = now
+}
+
+// CampaignTarget документ водителя, который нужно перепроверить в рамках кампании
+type CampaignTarget struct {
+	CampaignID     uuid.UUID            `json:"campaign_id" db:"campaign_id"`
+	DocumentID     uuid.UUID            `json:"document_id" db:"document_id"`
+	DriverID       uuid.UUID            `json:"driver_id" db:"driver_id"`
+	DocumentType   DocumentType         `json:"document_type" db:"document_type"`
+	Status         CampaignTargetStatus `json:"status" db:"status"`
+	RemindersSent  int                  `json:"reminders_sent" db:"reminders_sent"`
+	LastRemindedAt *time.Time           `json:"last_reminded_at,omitempty" db:"last_reminded_at"`
+	CompletedAt    *time.Time           `json:"completed_at,omitempty" db:"completed_at"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// NewCampaignTarget создает документ кампании, ожидающий перепроверки
+func NewCampaignTarget(campaignID uuid.UUID, document *DriverDocument, now time.Time) *CampaignTarget {
+	return &CampaignTarget{
+		CampaignID:   campaignID,
+		DocumentID:   document.ID,
+		DriverID:     document.DriverID,
+		DocumentType: document.DocumentType,
+		Status:       CampaignTargetPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// IsOpen проверяет, ждет ли документ перепроверки; просроченный документ можно перепроверить позже
+func (t *CampaignTarget) IsOpen() bool {
+	return t.Status == CampaignTargetPending || t.Status == CampaignTargetOverdue
+}
+
+// CampaignTargetFilters фильтры документов кампаний
+type CampaignTargetFilters struct {
+	CampaignID *uuid.UUID
+	DriverID   *uuid.UUID
+	Status     []CampaignTargetStatus
+	// RemindedBefore документы без напоминаний или с последним напоминанием раньше указанного времени
+	RemindedBefore *time.Time
+	Limit          int
+	Offset         int
+}
+
+// CreateCampaignRequest запрос на запуск кампании перепроверки
+type CreateCampaignRequest struct {
+	Name                 string           `json:"name" binding:"required"`
+	Reason               string           `json:"reason"`
+	Segment              CampaignSegment  `json:"segment"`
+	DocumentTypes        DocumentTypeList `json:"document_types" binding:"required,min=1"`
+	Deadline             time.Time        `json:"deadline" binding:"required"`
+	ReminderIntervalDays int              `json:"reminder_interval_days" binding:"min=0"`
+	CreatedBy            string           `json:"created_by"`
+}
+
+// CampaignTypeProgress ход перепроверки документов одного типа
+type CampaignTypeProgress struct {
+	DocumentType DocumentType `json:"document_type" db:"document_type"`
+	Total        int          `json:"total" db:"total"`
+	Pending      int          `json:"pending" db:"pending"`
+	Completed    int          `json:"completed" db:"completed"`
+	Overdue      int          `json:"overdue" db:"overdue"`
+	Cancelled    int          `json:"cancelled" db:"cancelled"`
+}
+
+// CampaignDailyProgress число документов, перепроверенных за день
+type CampaignDailyProgress struct {
+	Date      time.Time `json:"date" db:"date"`
+	Completed int       `json:"completed" db:"completed"`
+}
+
+// CampaignProgress ход кампании перепроверки для панели администратора
+type CampaignProgress struct {
+	CampaignID uuid.UUID `json:"campaign_id"`
+	Total      int       `json:"total"`
+	Pending    int       `json:"pending"`
+	Completed  int       `json:"completed"`
+	Overdue    int       `json:"overdue"`
+	Cancelled  int       `json:"cancelled"`
+	// CompletionRate доля перепроверенных документов, в процентах
+	CompletionRate float64 `json:"completion_rate"`
+	Drivers        int     `json:"drivers"`
+	// CompliantDrivers водители, перепроверившие все документы кампании
+	CompliantDrivers int                      `json:"compliant_drivers"`
+	ByDocumentType   []*CampaignTypeProgress  `json:"by_document_type"`
+	Daily            []*CampaignDailyProgress `json:"daily"`
+	// TimeToDeadline оставшееся время в секундах; отрицательное, если срок прошел
+	TimeToDeadline int64 `json:"time_to_deadline_seconds"`
+}
+
+// CampaignDetails кампания с ее ходом
+type CampaignDetails struct {
+	*ReverificationCampaign
+	Progress *CampaignProgress `json:"progress"`
+}
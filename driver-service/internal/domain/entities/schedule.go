@@ -0,0 +1,184 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxScheduleWindows максимальное число окон в расписании водителя
+const maxScheduleWindows = 50
+
+// scheduleWeekdays дни недели расписания
+var scheduleWeekdays = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"sun": time.Sunday,
+}
+
+// AvailabilityWindow еженедельное окно доступности водителя, например пн–пт 08:00–18:00.
+// Время задается в часовом поясе расписания; если End раньше Start, окно переходит
+// через полночь и заканчивается на следующий день
+type AvailabilityWindow struct {
+	// Days дни недели начала окна: mon, tue, wed, thu, fri, sat, sun
+	Days  []string `json:"days" binding:"required"`
+	Start string   `json:"start" binding:"required"`
+	End   string   `json:"end" binding:"required"`
+}
+
+// AvailabilityWindowList окна расписания, хранимые в JSONB
+type AvailabilityWindowList []AvailabilityWindow
+
+// Value реализует driver.Valuer
+func (l AvailabilityWindowList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *AvailabilityWindowList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into AvailabilityWindowList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// DriverSchedule еженедельное расписание доступности водителя. Водитель без расписания
+// доступен в любое время
+type DriverSchedule struct {
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	// Timezone часовой пояс IANA, в котором заданы окна
+	Timezone  string                 `json:"timezone" db:"timezone"`
+	Windows   AvailabilityWindowList `json:"windows" db:"windows"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// SetScheduleRequest запрос на установку расписания; без часового пояса используется
+// часовой пояс по умолчанию из конфигурации
+type SetScheduleRequest struct {
+	Timezone string               `json:"timezone"`
+	Windows  []AvailabilityWindow `json:"windows" binding:"required,min=1,dive"`
+}
+
+// Normalize приводит дни недели к нижнему регистру
+func (s *DriverSchedule) Normalize() {
+	for i := range s.Windows {
+		for j, day := range s.Windows[i].Days {
+			s.Windows[i].Days[j] = strings.ToLower(strings.TrimSpace(day))
+		}
+	}
+}
+
+// Validate проверяет часовой пояс и окна расписания
+func (s *DriverSchedule) Validate() error {
+	if _, err := time.LoadLocation(s.Timezone); err != nil || s.Timezone == "" {
+		return ErrInvalidSchedule
+	}
+	if len(s.Windows) == 0 || len(s.Windows) > maxScheduleWindows {
+		return ErrInvalidSchedule
+	}
+
+	for _, window := range s.Windows {
+		if len(window.Days) == 0 {
+			return ErrInvalidSchedule
+		}
+		seen := make(map[string]bool, len(window.Days))
+		for _, day := range window.Days {
+			if _, ok := scheduleWeekdays[day]; !ok || seen[day] {
+				return ErrInvalidSchedule
+			}
+			seen[day] = true
+		}
+
+		start, ok := parseClock(window.Start)
+		if !ok || start == minutesPerDay {
+			return ErrInvalidSchedule
+		}
+		end, ok := parseClock(window.End)
+		if !ok || end == start {
+			return ErrInvalidSchedule
+		}
+	}
+	return nil
+}
+
+// IsAvailableAt проверяет, попадает ли момент в одно из окон расписания
+func (s *DriverSchedule) IsAvailableAt(at time.Time) bool {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return false
+	}
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	today := local.Weekday()
+	yesterday := (today + 6) % 7
+
+	for _, window := range s.Windows {
+		start, okStart := parseClock(window.Start)
+		end, okEnd := parseClock(window.End)
+		if !okStart || !okEnd {
+			continue
+		}
+
+		if start < end {
+			if window.startsOn(today) && minute >= start && minute < end {
+				return true
+			}
+			continue
+		}
+		// Окно через полночь: вечер дня начала и утро следующего дня
+		if (window.startsOn(today) && minute >= start) || (window.startsOn(yesterday) && minute < end) {
+			return true
+		}
+	}
+	return false
+}
+
+// startsOn проверяет, начинается ли окно в указанный день недели
+func (w AvailabilityWindow) startsOn(day time.Weekday) bool {
+	for _, name := range w.Days {
+		if scheduleWeekdays[name] == day {
+			return true
+		}
+	}
+	return false
+}
+
+// minutesPerDay число минут в сутках; 24:00 допустимо только как конец окна
+const minutesPerDay = 24 * 60
+
+// parseClock разбирает время HH:MM в минуты от начала суток
+func parseClock(value string) (int, bool) {
+	if value == "24:00" {
+		return minutesPerDay, true
+	}
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, false
+	}
+	return clock.Hour()*60 + clock.Minute(), true
+}
+
+// ScheduleEnforcement итог перевода водителей вне окон расписания в неактивные
+type ScheduleEnforcement struct {
+	Checked     int `json:"checked"`
+	Deactivated int `json:"deactivated"`
+	Failed      int `json:"failed"`
+}
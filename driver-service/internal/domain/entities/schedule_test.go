@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDriverSchedule_Validate(t *testing.T) {
+	valid := func() *DriverSchedule {
+		return &DriverSchedule{
+			Timezone: "Europe/Moscow",
+			Windows: AvailabilityWindowList{
+				{Days: []string{"Mon", " tue ", "wed", "thu", "fri"}, Start: "08:00", End: "18:00"},
+				{Days: []string{"sat"}, Start: "22:00", End: "24:00"},
+			},
+		}
+	}
+
+	schedule := valid()
+	schedule.Normalize()
+	assert.NoError(t, schedule.Validate())
+	assert.Equal(t, "mon", schedule.Windows[0].Days[0])
+	assert.Equal(t, "tue", schedule.Windows[0].Days[1])
+
+	cases := map[string]func(s *DriverSchedule){
+		"unknown timezone": func(s *DriverSchedule) { s.Timezone = "Mars/Olympus" },
+		"empty timezone":   func(s *DriverSchedule) { s.Timezone = "" },
+		"no windows":       func(s *DriverSchedule) { s.Windows = nil },
+		"no days":          func(s *DriverSchedule) { s.Windows[0].Days = nil },
+		"unknown day":      func(s *DriverSchedule) { s.Windows[0].Days = []string{"monday"} },
+		"duplicate day":    func(s *DriverSchedule) { s.Windows[0].Days = []string{"mon", "mon"} },
+		"bad start":        func(s *DriverSchedule) { s.Windows[0].Start = "8am" },
+		"start at 24:00":   func(s *DriverSchedule) { s.Windows[0].Start = "24:00" },
+		"bad end":          func(s *DriverSchedule) { s.Windows[0].End = "25:00" },
+		"empty window":     func(s *DriverSchedule) { s.Windows[0].End = "08:00" },
+	}
+	for name, mutate := range cases {
+		schedule := valid()
+		mutate(schedule)
+		schedule.Normalize()
+		assert.Equal(t, ErrInvalidSchedule, schedule.Validate(), name)
+	}
+}
+
+func TestDriverSchedule_IsAvailableAt(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+
+	schedule := &DriverSchedule{
+		Timezone: "Europe/Moscow",
+		Windows: AvailabilityWindowList{
+			{Days: []string{"mon", "tue", "wed", "thu", "fri"}, Start: "08:00", End: "18:00"},
+			// Ночное окно пятницы заканчивается утром субботы
+			{Days: []string{"fri"}, Start: "22:00", End: "02:00"},
+		},
+	}
+
+	// 2024-03-04 — понедельник
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 4, hour, minute, 0, 0, moscow)
+	}
+	assert.True(t, schedule.IsAvailableAt(monday(8, 0)))
+	assert.True(t, schedule.IsAvailableAt(monday(17, 59)))
+	assert.False(t, schedule.IsAvailableAt(monday(18, 0)), "end is exclusive")
+	assert.False(t, schedule.IsAvailableAt(monday(7, 59)))
+
+	// Момент в UTC переводится в часовой пояс расписания: 05:30 UTC — 08:30 в Москве
+	assert.True(t, schedule.IsAvailableAt(time.Date(2024, 3, 4, 5, 30, 0, 0, time.UTC)))
+	assert.False(t, schedule.IsAvailableAt(time.Date(2024, 3, 4, 4, 30, 0, 0, time.UTC)))
+
+	friday := monday(0, 0).AddDate(0, 0, 4)
+	assert.True(t, schedule.IsAvailableAt(friday.Add(23*time.Hour)))
+	assert.True(t, schedule.IsAvailableAt(friday.Add(25*time.Hour)), "overnight window continues on saturday")
+	assert.False(t, schedule.IsAvailableAt(friday.Add(26*time.Hour)))
+	assert.False(t, schedule.IsAvailableAt(friday.Add(30*time.Hour)))
+
+	// Утро понедельника не продолжает ночное окно воскресенья
+	assert.False(t, schedule.IsAvailableAt(monday(1, 0)))
+}
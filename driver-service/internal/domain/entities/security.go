@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SecurityEventType тип подозрительной активности в аккаунте водителя
+type SecurityEventType string
+
+const (
+	// SecurityEventNewDevice вход с устройства, которого не было в истории сессий
+	SecurityEventNewDevice SecurityEventType = "new_device"
+	// SecurityEventNewCountry вход из страны, которой не было в истории сессий
+	SecurityEventNewCountry SecurityEventType = "new_country"
+	// SecurityEventLocationJump скачок местоположения с невозможной скоростью (подмена GPS)
+	SecurityEventLocationJump SecurityEventType = "location_jump"
+	// SecurityEventSimultaneousSessions одновременные сессии с разных устройств
+	SecurityEventSimultaneousSessions SecurityEventType = "simultaneous_sessions"
+)
+
+// IsValid проверяет тип события безопасности
+func (t SecurityEventType) IsValid() bool {
+	switch t {
+	case SecurityEventNewDevice, SecurityEventNewCountry, SecurityEventLocationJump, SecurityEventSimultaneousSessions:
+		return true
+	}
+	return false
+}
+
+// SecurityEventStatus состояние рассмотрения события безопасности
+type SecurityEventStatus string
+
+const (
+	SecurityEventOpen      SecurityEventStatus = "open"
+	SecurityEventConfirmed SecurityEventStatus = "confirmed"
+	SecurityEventDismissed SecurityEventStatus = "dismissed"
+)
+
+// SecurityEvent подозрительная активность в аккаунте водителя
+type SecurityEvent struct {
+	ID       uuid.UUID           `json:"id" db:"id"`
+	DriverID uuid.UUID           `json:"driver_id" db:"driver_id"`
+	Type     SecurityEventType   `json:"type" db:"event_type"`
+	Status   SecurityEventStatus `json:"status" db:"status"`
+	Details  Metadata            `json:"details" db:"details"`
+	// SessionsRevoked сессии водителя отозваны автоматически или при рассмотрении
+	SessionsRevoked bool       `json:"sessions_revoked" db:"sessions_revoked"`
+	ReviewedBy      *string    `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewNote      *string    `json:"review_note,omitempty" db:"review_note"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewSecurityEvent создает открытое событие безопасности
+func NewSecurityEvent(driverID uuid.UUID, eventType SecurityEventType, details Metadata) *SecurityEvent {
+	if details == nil {
+		details = make(Metadata)
+	}
+	return &SecurityEvent{
+		ID:        uuid.New(),
+		DriverID:  driverID,
+		Type:      eventType,
+		Status:    SecurityEventOpen,
+		Details:   details,
+		CreatedAt: time.Now(),
+	}
+}
+
+// SecurityEventFilters фильтры событий безопасности
+type SecurityEventFilters struct {
+	DriverID *uuid.UUID
+	Type     SecurityEventType
+	Status   SecurityEventStatus
+	Since    *time.Time
+	Limit    int
+	Offset   int
+}
+
+// SecurityReview решение администратора по событию безопасности
+type SecurityReview struct {
+	Status SecurityEventStatus `json:"status" binding:"required,oneof=confirmed dismissed"`
+	// ReviewerID по умолчанию субъект токена администратора
+	ReviewerID string `json:"reviewer_id"`
+	Note       string `json:"note"`
+	// RevokeSessions отозвать все сессии водителя: водителю потребуется войти заново
+	RevokeSessions bool `json:"revoke_sessions"`
+}
+
+// DriverSession сессия водителя, известная сервису по утверждениям токена
+type DriverSession struct {
+	ID          string     `json:"id" db:"id"`
+	DriverID    uuid.UUID  `json:"driver_id" db:"driver_id"`
+	DeviceID    string     `json:"device_id,omitempty" db:"device_id"`
+	Country     string     `json:"country,omitempty" db:"country"`
+	IPAddress   string     `json:"ip_address,omitempty" db:"ip_address"`
+	FirstSeenAt time.Time  `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsRevoked проверяет, отозвана ли сессия
+func (s *DriverSession) IsRevoked() bool {
+	return s.RevokedAt != nil
+}
+
+// SessionObservation запрос водителя в рамках сессии
+type SessionObservation struct {
+	DriverID  uuid.UUID
+	SessionID string
+	DeviceID  string
+	// Country код страны ISO 3166-1 из заголовка балансировщика
+	Country   string
+	IPAddress string
+	At        time.Time
+}
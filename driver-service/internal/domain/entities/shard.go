@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// PrimaryShard имя шарда основной базы данных (секция database конфигурации)
+const PrimaryShard = "primary"
+
+// CityShard закрепление города за шардом
+type CityShard struct {
+	City  string `json:"city" db:"city"`
+	Shard string `json:"shard" db:"shard"`
+	// Locked запись в шард города запрещена: данные города переносятся на другой шард
+	Locked    bool      `json:"locked" db:"locked"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ShardRebalanceResult результат переноса города на другой шард
+type ShardRebalanceResult struct {
+	City        string        `json:"city"`
+	FromShard   string        `json:"from_shard"`
+	ToShard     string        `json:"to_shard"`
+	Copied      int64         `json:"copied"`
+	Deleted     int64         `json:"deleted"`
+	Duration    time.Duration `json:"duration"`
+	CompletedAt time.Time     `json:"completed_at"`
+}
+
+// NormalizeCity приводит ключ города к виду, в котором он хранится и сопоставляется с шардом
+func NormalizeCity(city string) string {
+	return strings.ToLower(strings.TrimSpace(city))
+}
+
+// RefreshShardKey пересчитывает ключ шарда по городу из метаданных водителя
+func (d *Driver) RefreshShardKey() {
+	d.ShardKey = ""
+	if city := d.City(); city != nil {
+		d.ShardKey = NormalizeCity(*city)
+	}
+}
+
+// shardCityKey ключ контекста с городом запроса
+type shardCityKey struct{}
+
+// WithShardCity добавляет в контекст город запроса: запросы без водителя, например поиск
+// поблизости, выполняются только на шарде этого города
+func WithShardCity(ctx context.Context, city string) context.Context {
+	return context.WithValue(ctx, shardCityKey{}, NormalizeCity(city))
+}
+
+// ShardCityFromContext возвращает город запроса из контекста
+func ShardCityFromContext(ctx context.Context) (string, bool) {
+	city, ok := ctx.Value(shardCityKey{}).(string)
+	return city, ok && city != ""
+}
@@ -32,6 +32,14 @@ type DriverShift struct {
 	TotalDistance   float64    `json:"total_distance" db:"total_distance"`
 	TotalEarnings   float64    `json:"total_earnings" db:"total_earnings"`
 	FuelConsumed    *float64   `json:"fuel_consumed,omitempty" db:"fuel_consumed"`
+	// AverageSpeed и IdleMinutes считаются по истории местоположений при завершении смены;
+	// nil, если точек за смену не было
+	AverageSpeed    *float64   `json:"average_speed_kmh,omitempty" db:"average_speed"`
+	IdleMinutes     *int64     `json:"idle_minutes,omitempty" db:"idle_minutes"`
+	// BreakMinutes и RestCompliant записываются при завершении смены по ее перерывам;
+	// RestCompliant nil у смен, завершенных до учета перерывов
+	BreakMinutes    int64      `json:"break_minutes" db:"break_minutes"`
+	RestCompliant   *bool      `json:"rest_compliant,omitempty" db:"rest_compliant"`
 	Metadata        Metadata   `json:"metadata" db:"metadata"`
 	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
@@ -95,6 +103,32 @@ func (s *DriverShift) AddTrip(distance float64, earnings float64) {
 	s.UpdatedAt = time.Now()
 }
 
+// ApplyRest записывает в смену время перерывов и соблюдение требований к отдыху
+func (s *DriverShift) ApplyRest(rest *ShiftRest) {
+	compliant := rest.Compliant
+	s.BreakMinutes = rest.BreakMinutes
+	s.RestCompliant = &compliant
+}
+
+// ApplyLocationStats записывает в смену пробег, среднюю скорость и время простоя по истории
+// местоположений за смену. Если водитель не присылал скорость, средняя скорость считается
+// как пробег за время на связи. Без трека (меньше двух точек) остается пробег поездок
+func (s *DriverShift) ApplyLocationStats(stats *LocationStats) {
+	if stats.TotalPoints < 2 {
+		return
+	}
+
+	averageSpeed := stats.AverageSpeed
+	if averageSpeed == 0 && stats.TrackedMinutes > 0 {
+		averageSpeed = stats.DistanceTraveled / (float64(stats.TrackedMinutes) / 60)
+	}
+	idleMinutes := stats.IdleMinutes
+
+	s.TotalDistance = stats.DistanceTraveled
+	s.AverageSpeed = &averageSpeed
+	s.IdleMinutes = &idleMinutes
+}
+
 // GetAverageEarningsPerTrip возвращает средний заработок за поездку
 func (s *DriverShift) GetAverageEarningsPerTrip() float64 {
 	if s.TotalTrips == 0 {
@@ -232,6 +266,10 @@ type ShiftResponse struct {
 	TotalDistance   float64      `json:"total_distance"`
 	TotalEarnings   float64      `json:"total_earnings"`
 	FuelConsumed    *float64     `json:"fuel_consumed,omitempty"`
+	AverageSpeed    *float64     `json:"average_speed_kmh,omitempty"`
+	IdleMinutes     *int64       `json:"idle_minutes,omitempty"`
+	BreakMinutes    int64        `json:"break_minutes"`
+	RestCompliant   *bool        `json:"rest_compliant,omitempty"`
 	EarningsPerHour float64      `json:"earnings_per_hour"`
 	AvgTripDistance float64      `json:"avg_trip_distance"`
 	AvgTripEarnings float64      `json:"avg_trip_earnings"`
@@ -253,6 +291,10 @@ func (s *DriverShift) ToResponse() *ShiftResponse {
 		TotalDistance:   s.TotalDistance,
 		TotalEarnings:   s.TotalEarnings,
 		FuelConsumed:    s.FuelConsumed,
+		AverageSpeed:    s.AverageSpeed,
+		IdleMinutes:     s.IdleMinutes,
+		BreakMinutes:    s.BreakMinutes,
+		RestCompliant:   s.RestCompliant,
 		EarningsPerHour: s.GetEarningsPerHour(),
 		AvgTripDistance: s.GetAverageDistancePerTrip(),
 		AvgTripEarnings: s.GetAverageEarningsPerTrip(),
@@ -293,4 +335,48 @@ type ShiftStats struct {
 	AvgShiftDuration float64 `json:"avg_shift_duration_hours"`
 	AvgShiftEarnings float64 `json:"avg_shift_earnings"`
 	AvgHourlyRate   float64 `json:"avg_hourly_rate"`
+	// TotalBreakHours время перерывов; у активных смен — с учетом идущего перерыва
+	TotalBreakHours float64 `json:"total_break_hours"`
+	// RestViolations завершенные смены, не соответствующие требованиям к отдыху
+	RestViolations  int     `json:"rest_violations"`
+}
+
+// NewShiftStats считает статистику по сменам. Отработанные часы — длительность смен за вычетом
+// перерывов; средние считаются по завершенным сменам
+func NewShiftStats(shifts []*DriverShift) *ShiftStats {
+	stats := &ShiftStats{}
+	var completedHours, completedEarnings float64
+	for _, shift := range shifts {
+		stats.TotalShifts++
+		hours := float64(shift.GetDuration()-shift.BreakMinutes) / 60
+		if hours < 0 {
+			hours = 0
+		}
+		stats.TotalHours += hours
+		stats.TotalBreakHours += float64(shift.BreakMinutes) / 60
+		stats.TotalEarnings += shift.TotalEarnings
+		stats.TotalTrips += shift.TotalTrips
+		stats.TotalDistance += shift.TotalDistance
+
+		switch shift.Status {
+		case ShiftStatusActive:
+			stats.ActiveShifts++
+		case ShiftStatusCompleted:
+			stats.CompletedShifts++
+			completedHours += hours
+			completedEarnings += shift.TotalEarnings
+			if shift.RestCompliant != nil && !*shift.RestCompliant {
+				stats.RestViolations++
+			}
+		}
+	}
+
+	if stats.CompletedShifts > 0 {
+		stats.AvgShiftDuration = completedHours / float64(stats.CompletedShifts)
+		stats.AvgShiftEarnings = completedEarnings / float64(stats.CompletedShifts)
+	}
+	if completedHours > 0 {
+		stats.AvgHourlyRate = completedEarnings / completedHours
+	}
+	return stats
 }
\ No newline at end of file
@@ -0,0 +1,158 @@
+package entities
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShiftBreak перерыв водителя внутри смены. Перерывы одной смены не пересекаются, открытым
+// (без EndedAt) может быть только последний
+type ShiftBreak struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	ShiftID   uuid.UUID  `json:"shift_id" db:"shift_id"`
+	DriverID  uuid.UUID  `json:"driver_id" db:"driver_id"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty" db:"ended_at"`
+	Notes     *string    `json:"notes,omitempty" db:"notes"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// ShiftBreakStartRequest запрос на начало перерыва. StartedAt позволяет отметить перерыв,
+// начатый раньше (например, без связи); по умолчанию — время запроса
+type ShiftBreakStartRequest struct {
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Notes     *string    `json:"notes,omitempty"`
+}
+
+// ShiftBreakEndRequest запрос на окончание перерыва; по умолчанию перерыв заканчивается
+// во время запроса
+type ShiftBreakEndRequest struct {
+	EndedAt *time.Time `json:"ended_at,omitempty"`
+}
+
+// NewShiftBreak начинает перерыв в смене shift в момент startedAt. Перерыв должен начаться
+// внутри смены и не раньше окончания прошлых перерывов existing; пока открыт прошлый
+// перерыв, новый не начинается
+func NewShiftBreak(shift *DriverShift, existing []*ShiftBreak, startedAt, now time.Time, notes *string) (*ShiftBreak, error) {
+	if startedAt.Before(shift.StartTime) || startedAt.After(now) {
+		return nil, ErrInvalidShiftBreak
+	}
+	for _, other := range existing {
+		if other.IsOpen() {
+			return nil, ErrShiftBreakActive
+		}
+		if other.EndedAt.After(startedAt) {
+			return nil, ErrShiftBreakOverlap
+		}
+	}
+
+	return &ShiftBreak{
+		ID:        uuid.New(),
+		ShiftID:   shift.ID,
+		DriverID:  shift.DriverID,
+		StartedAt: startedAt,
+		Notes:     notes,
+		CreatedAt: now,
+	}, nil
+}
+
+// IsOpen проверяет, что перерыв еще идет
+func (b *ShiftBreak) IsOpen() bool {
+	return b.EndedAt == nil
+}
+
+// End заканчивает перерыв в момент endedAt, не раньше его начала и не позже now
+func (b *ShiftBreak) End(endedAt, now time.Time) error {
+	if !b.IsOpen() {
+		return ErrShiftBreakNotActive
+	}
+	if endedAt.Before(b.StartedAt) || endedAt.After(now) {
+		return ErrInvalidShiftBreak
+	}
+	b.EndedAt = &endedAt
+	return nil
+}
+
+// Duration возвращает длительность перерыва; открытый перерыв считается до now
+func (b *ShiftBreak) Duration(now time.Time) time.Duration {
+	end := now
+	if b.EndedAt != nil {
+		end = *b.EndedAt
+	}
+	if end.Before(b.StartedAt) {
+		return 0
+	}
+	return end.Sub(b.StartedAt)
+}
+
+// TotalBreakDuration суммирует длительность перерывов; открытые считаются до now
+func TotalBreakDuration(breaks []*ShiftBreak, now time.Time) time.Duration {
+	var total time.Duration
+	for _, b := range breaks {
+		total += b.Duration(now)
+	}
+	return total
+}
+
+// ShiftRestPolicy требования к отдыху в смене: после WorkLimit работы без перерыва нужен
+// перерыв не короче MinBreak. Перерывы короче MinBreak отдыхом не считаются
+type ShiftRestPolicy struct {
+	WorkLimit time.Duration
+	MinBreak  time.Duration
+}
+
+// ShiftRest соблюдение требований к отдыху в смене
+type ShiftRest struct {
+	BreakMinutes int64 `json:"break_minutes"`
+	// LongestWorkMinutes самый длинный отрезок работы между перерывами не короче min_break
+	LongestWorkMinutes int64 `json:"longest_work_minutes"`
+	// Compliant работа без отдыха ни разу не превысила WorkLimit
+	Compliant bool `json:"compliant"`
+}
+
+// EvaluateShiftRest проверяет перерывы смены по policy. Незавершенная смена и открытый
+// перерыв считаются до now. Без WorkLimit смена всегда соответствует требованиям
+func EvaluateShiftRest(shift *DriverShift, breaks []*ShiftBreak, policy ShiftRestPolicy, now time.Time) *ShiftRest {
+	end := now
+	if shift.EndTime != nil {
+		end = *shift.EndTime
+	}
+
+	sorted := make([]*ShiftBreak, len(breaks))
+	copy(sorted, breaks)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartedAt.Before(sorted[j].StartedAt) })
+
+	var longest time.Duration
+	workStart := shift.StartTime
+	for _, b := range sorted {
+		breakEnd := end
+		if b.EndedAt != nil && b.EndedAt.Before(end) {
+			breakEnd = *b.EndedAt
+		}
+		if breakEnd.Sub(b.StartedAt) < policy.MinBreak {
+			continue
+		}
+		if work := b.StartedAt.Sub(workStart); work > longest {
+			longest = work
+		}
+		workStart = breakEnd
+	}
+	if work := end.Sub(workStart); work > longest {
+		longest = work
+	}
+
+	return &ShiftRest{
+		BreakMinutes:       int64(TotalBreakDuration(breaks, end).Minutes()),
+		LongestWorkMinutes: int64(longest.Minutes()),
+		Compliant:          policy.WorkLimit <= 0 || longest <= policy.WorkLimit,
+	}
+}
+
+// ShiftBreaksReport перерывы смены и соблюдение требований к отдыху
+type ShiftBreaksReport struct {
+	ShiftID uuid.UUID     `json:"shift_id"`
+	Breaks  []*ShiftBreak `json:"breaks"`
+	Rest    *ShiftRest    `json:"rest"`
+}
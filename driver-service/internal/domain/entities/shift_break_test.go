@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewShiftBreak(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	shift := &DriverShift{ID: uuid.New(), DriverID: uuid.New(), StartTime: now.Add(-6 * time.Hour)}
+	endedAt := now.Add(-2 * time.Hour)
+	finished := &ShiftBreak{StartedAt: now.Add(-3 * time.Hour), EndedAt: &endedAt}
+	open := &ShiftBreak{StartedAt: now.Add(-time.Hour)}
+
+	shiftBreak, err := NewShiftBreak(shift, []*ShiftBreak{finished}, now.Add(-30*time.Minute), now, nil)
+	require.NoError(t, err)
+	assert.Equal(t, shift.ID, shiftBreak.ShiftID)
+	assert.Equal(t, shift.DriverID, shiftBreak.DriverID)
+	assert.True(t, shiftBreak.IsOpen())
+
+	_, err = NewShiftBreak(shift, nil, shift.StartTime.Add(-time.Minute), now, nil)
+	assert.Equal(t, ErrInvalidShiftBreak, err, "break before the shift")
+	_, err = NewShiftBreak(shift, nil, now.Add(time.Minute), now, nil)
+	assert.Equal(t, ErrInvalidShiftBreak, err, "break in the future")
+	_, err = NewShiftBreak(shift, []*ShiftBreak{open}, now, now, nil)
+	assert.Equal(t, ErrShiftBreakActive, err)
+	_, err = NewShiftBreak(shift, []*ShiftBreak{finished}, now.Add(-150*time.Minute), now, nil)
+	assert.Equal(t, ErrShiftBreakOverlap, err)
+}
+
+func TestShiftBreak_End(t *testing.T) {
+	now := time.Date(2024, 1, 15, 14, 0, 0, 0, time.UTC)
+	shiftBreak := &ShiftBreak{StartedAt: now.Add(-20 * time.Minute)}
+
+	assert.Equal(t, ErrInvalidShiftBreak, shiftBreak.End(now.Add(-time.Hour), now))
+	assert.Equal(t, ErrInvalidShiftBreak, shiftBreak.End(now.Add(time.Minute), now))
+	require.NoError(t, shiftBreak.End(now, now))
+	assert.Equal(t, 20*time.Minute, shiftBreak.Duration(now.Add(time.Hour)))
+	assert.Equal(t, ErrShiftBreakNotActive, shiftBreak.End(now, now))
+}
+
+func TestEvaluateShiftRest(t *testing.T) {
+	start := time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC)
+	end := start.Add(9 * time.Hour)
+	shift := &DriverShift{StartTime: start, EndTime: &end}
+	policy := ShiftRestPolicy{WorkLimit: 4 * time.Hour, MinBreak: 15 * time.Minute}
+	breakAt := func(from, length time.Duration) *ShiftBreak {
+		endedAt := start.Add(from + length)
+		return &ShiftBreak{StartedAt: start.Add(from), EndedAt: &endedAt}
+	}
+
+	// Перерывы каждые 4 часа работы
+	rest := EvaluateShiftRest(shift, []*ShiftBreak{
+		breakAt(4*time.Hour, 30*time.Minute),
+		breakAt(8*time.Hour, 20*time.Minute),
+	}, policy, end)
+	assert.True(t, rest.Compliant)
+	assert.Equal(t, int64(50), rest.BreakMinutes)
+	assert.Equal(t, int64(240), rest.LongestWorkMinutes)
+
+	// Короткий перерыв отдыхом не считается: 5 часов работы без отдыха
+	rest = EvaluateShiftRest(shift, []*ShiftBreak{
+		breakAt(2*time.Hour, 5*time.Minute),
+		breakAt(5*time.Hour, 30*time.Minute),
+	}, policy, end)
+	assert.False(t, rest.Compliant)
+	assert.Equal(t, int64(35), rest.BreakMinutes)
+	assert.Equal(t, int64(300), rest.LongestWorkMinutes)
+
+	// Без перерывов и без ограничения
+	rest = EvaluateShiftRest(shift, nil, ShiftRestPolicy{}, end)
+	assert.True(t, rest.Compliant)
+	assert.Equal(t, int64(540), rest.LongestWorkMinutes)
+}
+
+func TestNewShiftStats(t *testing.T) {
+	start := time.Now().Add(-24 * time.Hour)
+	end := start.Add(9 * time.Hour)
+	compliant, violated := true, false
+	shifts := []*DriverShift{
+		{StartTime: start, EndTime: &end, Status: ShiftStatusCompleted, TotalEarnings: 8000, TotalTrips: 10,
+			BreakMinutes: 60, RestCompliant: &compliant},
+		{StartTime: start, EndTime: &end, Status: ShiftStatusCompleted, TotalEarnings: 4000, TotalTrips: 6,
+			RestCompliant: &violated},
+		{StartTime: time.Now().Add(-time.Hour), Status: ShiftStatusActive, TotalTrips: 1},
+	}
+
+	stats := NewShiftStats(shifts)
+	assert.Equal(t, 3, stats.TotalShifts)
+	assert.Equal(t, 1, stats.ActiveShifts)
+	assert.Equal(t, 2, stats.CompletedShifts)
+	assert.Equal(t, 1, stats.RestViolations)
+	assert.Equal(t, 17, stats.TotalTrips)
+	assert.InDelta(t, 1.0, stats.TotalBreakHours, 0.001)
+	assert.InDelta(t, 18.0, stats.TotalHours, 0.05, "worked hours exclude breaks")
+	assert.InDelta(t, 8.5, stats.AvgShiftDuration, 0.001)
+	assert.InDelta(t, 12000.0/17, stats.AvgHourlyRate, 0.001)
+}
@@ -0,0 +1,59 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// signedTokenPayloadSize размер подписанной части токена ссылки: ID объекта и срок действия
+// в секундах Unix
+const signedTokenPayloadSize = 16 + 8
+
+// signToken выпускает токен ссылки: ID объекта и срок действия, подписанные HMAC-SHA256.
+// purpose входит в подпись, поэтому токен одной ссылки не подходит для другой даже при общем
+// ключе. Токены не хранятся и могут быть выпущены повторно
+func signToken(secret []byte, purpose string, id uuid.UUID, expiresAt time.Time) string {
+	payload := make([]byte, signedTokenPayloadSize)
+	copy(payload, id[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(expiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signTokenPayload(secret, purpose, payload))
+}
+
+// parseSignedToken проверяет подпись токена и возвращает ID объекта и срок действия из него;
+// ok ложно у поврежденного или чужого токена. Срок действия проверяет вызывающий
+func parseSignedToken(secret []byte, purpose, token string) (uuid.UUID, time.Time, bool) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != signedTokenPayloadSize {
+		return uuid.Nil, time.Time{}, false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signTokenPayload(secret, purpose, payload)) {
+		return uuid.Nil, time.Time{}, false
+	}
+
+	id, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.Nil, time.Time{}, false
+	}
+	return id, time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0), true
+}
+
+// signTokenPayload подпись токена ссылки с назначением purpose
+func signTokenPayload(secret []byte, purpose string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
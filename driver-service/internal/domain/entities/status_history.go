@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusTransition запись истории статусов водителя: смена статуса, ее время и автор.
+// История пишется репозиторием водителей в одной транзакции со сменой статуса, поэтому
+// по ней восстанавливается статус водителя на любой момент
+type StatusTransition struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	DriverID uuid.UUID `json:"driver_id" db:"driver_id"`
+	// FromStatus статус до смены; nil у первой записи водителя (регистрация или снимок)
+	FromStatus *Status `json:"from_status,omitempty" db:"from_status"`
+	ToStatus   Status  `json:"to_status" db:"to_status"`
+	// Actor субъект токена, сменившего статус; nil для изменений планировщика и фоновых задач
+	Actor     *string `json:"actor,omitempty" db:"actor"`
+	RequestID *string `json:"request_id,omitempty" db:"request_id"`
+	// Snapshot запись снимка статуса при включении истории: водитель был в статусе ToStatus
+	// на момент ChangedAt, но когда он в него перешел, неизвестно
+	Snapshot  bool      `json:"snapshot" db:"snapshot"`
+	ChangedAt time.Time `json:"changed_at" db:"changed_at"`
+}
+
+// NewStatusTransition создает запись истории статусов; автор и ID запроса берутся из
+// контекста (см. WithAuditActor)
+func NewStatusTransition(ctx context.Context, driverID uuid.UUID, from *Status, to Status, at time.Time) *StatusTransition {
+	transition := &StatusTransition{
+		ID:         uuid.New(),
+		DriverID:   driverID,
+		FromStatus: from,
+		ToStatus:   to,
+		ChangedAt:  at,
+	}
+	if actor, ok := AuditActorFromContext(ctx); ok {
+		if actor.Subject != "" {
+			subject := actor.Subject
+			transition.Actor = &subject
+		}
+		if actor.RequestID != "" {
+			requestID := actor.RequestID
+			transition.RequestID = &requestID
+		}
+	}
+	return transition
+}
+
+// StatusHistoryFilters фильтры истории статусов водителя
+type StatusHistoryFilters struct {
+	DriverID uuid.UUID  `json:"driver_id"`
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+}
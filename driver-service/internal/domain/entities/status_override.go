@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// StatusOverrideMetadataKey ключ метаданных водителя с последней ручной сменой статуса
+const StatusOverrideMetadataKey = "status_override"
+
+// MaxStatusOverrideReasonLength максимальная длина причины ручной смены статуса в символах
+const MaxStatusOverrideReasonLength = 500
+
+// StatusOverride ручная смена статуса администратором в обход правил переходов,
+// например разблокировка водителя после разбора обращения
+type StatusOverride struct {
+	DriverID       uuid.UUID `json:"driver_id"`
+	PreviousStatus Status    `json:"previous_status"`
+	Status         Status    `json:"status"`
+	Reason         string    `json:"reason"`
+	// ActorID идентификатор администратора, сменившего статус
+	ActorID      string    `json:"actor_id"`
+	OverriddenAt time.Time `json:"overridden_at"`
+}
+
+// NewStatusOverride создает ручную смену статуса; причина и автор очищаются от пробелов по краям
+func NewStatusOverride(driverID uuid.UUID, status Status, reason, actorID string) *StatusOverride {
+	return &StatusOverride{
+		DriverID:     driverID,
+		Status:       status,
+		Reason:       strings.TrimSpace(reason),
+		ActorID:      strings.TrimSpace(actorID),
+		OverriddenAt: time.Now(),
+	}
+}
+
+// Validate проверяет статус, причину и автора смены
+func (o *StatusOverride) Validate() error {
+	if !o.Status.IsValid() || o.ActorID == "" {
+		return ErrInvalidStatusOverride
+	}
+	if o.Reason == "" || utf8.RuneCountInString(o.Reason) > MaxStatusOverrideReasonLength {
+		return ErrInvalidStatusOverride
+	}
+	return nil
+}
+
+// Metadata представление смены для метаданных водителя
+func (o *StatusOverride) Metadata() Metadata {
+	return Metadata{
+		"previous_status": o.PreviousStatus,
+		"status":          o.Status,
+		"reason":          o.Reason,
+		"actor_id":        o.ActorID,
+		"overridden_at":   o.OverriddenAt,
+	}
+}
@@ -0,0 +1,195 @@
+package entities
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// geohashAlphabet алфавит base32 geohash
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// GeohashMaxPrecision наибольшая длина geohash, которую поддерживает PostGIS ST_GeoHash
+const GeohashMaxPrecision = 12
+
+// ParseGeoBounds разбирает область в формате bbox GeoJSON "min_lon,min_lat,max_lon,max_lat"
+func ParseGeoBounds(value string) (*GeoBounds, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, ErrInvalidBoundingBox
+	}
+
+	coords := make([]float64, len(parts))
+	for i, part := range parts {
+		coord, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, ErrInvalidBoundingBox
+		}
+		coords[i] = coord
+	}
+
+	bounds := &GeoBounds{
+		SouthWest: Location{Longitude: coords[0], Latitude: coords[1]},
+		NorthEast: Location{Longitude: coords[2], Latitude: coords[3]},
+	}
+	if err := bounds.Validate(); err != nil {
+		return nil, err
+	}
+	return bounds, nil
+}
+
+// Validate проверяет углы области. Область, пересекающая 180-й меридиан, не поддерживается
+func (b *GeoBounds) Validate() error {
+	sw, ne := b.SouthWest, b.NorthEast
+	if sw.Latitude < -90 || ne.Latitude > 90 || sw.Longitude < -180 || ne.Longitude > 180 {
+		return ErrInvalidBoundingBox
+	}
+	if sw.Latitude > ne.Latitude || sw.Longitude > ne.Longitude {
+		return ErrInvalidBoundingBox
+	}
+	return nil
+}
+
+// Contains проверяет, лежит ли точка в области, включая границы
+func (b *GeoBounds) Contains(lat, lon float64) bool {
+	return lat >= b.SouthWest.Latitude && lat <= b.NorthEast.Latitude &&
+		lon >= b.SouthWest.Longitude && lon <= b.NorthEast.Longitude
+}
+
+// EncodeGeohash возвращает geohash точки длиной precision; совпадает с PostGIS ST_GeoHash
+func EncodeGeohash(lat, lon float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	hash := make([]byte, precision)
+	even := true
+	for i := range hash {
+		index := 0
+		for bit := 4; bit >= 0; bit-- {
+			value, bounds := lat, &latRange
+			if even {
+				value, bounds = lon, &lonRange
+			}
+			mid := (bounds[0] + bounds[1]) / 2
+			if value >= mid {
+				index |= 1 << bit
+				bounds[0] = mid
+			} else {
+				bounds[1] = mid
+			}
+			even = !even
+		}
+		hash[i] = geohashAlphabet[index]
+	}
+	return string(hash)
+}
+
+// GeohashBounds возвращает область ячейки geohash; false, если hash содержит недопустимые символы
+func GeohashBounds(hash string) (*GeoBounds, bool) {
+	latRange := [2]float64{-90, 90}
+	lonRange := [2]float64{-180, 180}
+
+	even := true
+	for _, c := range hash {
+		index := strings.IndexRune(geohashAlphabet, c)
+		if index < 0 {
+			return nil, false
+		}
+		for bit := 4; bit >= 0; bit-- {
+			bounds := &latRange
+			if even {
+				bounds = &lonRange
+			}
+			mid := (bounds[0] + bounds[1]) / 2
+			if index&(1<<bit) != 0 {
+				bounds[0] = mid
+			} else {
+				bounds[1] = mid
+			}
+			even = !even
+		}
+	}
+
+	return &GeoBounds{
+		SouthWest: Location{Latitude: latRange[0], Longitude: lonRange[0]},
+		NorthEast: Location{Latitude: latRange[1], Longitude: lonRange[1]},
+	}, true
+}
+
+// SupplyHeatmapQuery параметры карты предложения водителей
+type SupplyHeatmapQuery struct {
+	Bounds GeoBounds
+	// Precision длина geohash ячейки
+	Precision int
+	// Since водители, чья последняя точка записана раньше, не учитываются
+	Since time.Time
+}
+
+// SupplyCellCount число водителей одного статуса в ячейке
+type SupplyCellCount struct {
+	Geohash string `json:"geohash" db:"geohash"`
+	Status  Status `json:"status" db:"status"`
+	Drivers int    `json:"drivers" db:"drivers"`
+}
+
+// SupplyCell ячейка карты предложения водителей
+type SupplyCell struct {
+	Geohash   string         `json:"geohash"`
+	Latitude  float64        `json:"latitude"`
+	Longitude float64        `json:"longitude"`
+	Bounds    *GeoBounds     `json:"bounds,omitempty"`
+	Total     int            `json:"total"`
+	ByStatus  map[Status]int `json:"by_status"`
+}
+
+// SupplyHeatmap число водителей по текущим местоположениям в ячейках geohash
+type SupplyHeatmap struct {
+	Bounds      GeoBounds      `json:"bounds"`
+	Precision   int            `json:"precision"`
+	Since       time.Time      `json:"since"`
+	Total       int            `json:"total"`
+	ByStatus    map[Status]int `json:"by_status"`
+	Cells       []*SupplyCell  `json:"cells"`
+	GeneratedAt time.Time      `json:"generated_at"`
+}
+
+// NewSupplyHeatmap собирает карту из количеств водителей по ячейкам и статусам.
+// Ячейки упорядочены по geohash; пустые ячейки в карту не попадают
+func NewSupplyHeatmap(query *SupplyHeatmapQuery, counts []*SupplyCellCount, generatedAt time.Time) *SupplyHeatmap {
+	heatmap := &SupplyHeatmap{
+		Bounds:      query.Bounds,
+		Precision:   query.Precision,
+		Since:       query.Since,
+		ByStatus:    make(map[Status]int),
+		Cells:       make([]*SupplyCell, 0),
+		GeneratedAt: generatedAt,
+	}
+
+	cells := make(map[string]*SupplyCell)
+	for _, count := range counts {
+		if count.Drivers <= 0 {
+			continue
+		}
+		cell, ok := cells[count.Geohash]
+		if !ok {
+			cell = &SupplyCell{Geohash: count.Geohash, ByStatus: make(map[Status]int)}
+			if bounds, valid := GeohashBounds(count.Geohash); valid {
+				cell.Bounds = bounds
+				cell.Latitude = (bounds.SouthWest.Latitude + bounds.NorthEast.Latitude) / 2
+				cell.Longitude = (bounds.SouthWest.Longitude + bounds.NorthEast.Longitude) / 2
+			}
+			cells[count.Geohash] = cell
+			heatmap.Cells = append(heatmap.Cells, cell)
+		}
+		cell.Total += count.Drivers
+		cell.ByStatus[count.Status] += count.Drivers
+		heatmap.Total += count.Drivers
+		heatmap.ByStatus[count.Status] += count.Drivers
+	}
+
+	sort.Slice(heatmap.Cells, func(i, j int) bool {
+		return heatmap.Cells[i].Geohash < heatmap.Cells[j].Geohash
+	})
+	return heatmap
+}
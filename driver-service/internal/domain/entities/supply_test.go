@@ -0,0 +1,49 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeGeohash(t *testing.T) {
+	assert.Equal(t, "ezs42", EncodeGeohash(42.6, -5.6, 5))
+	assert.Equal(t, "u4pruydqqvj", EncodeGeohash(57.64911, 10.40744, 11))
+
+	bounds, ok := GeohashBounds("ezs42")
+	require.True(t, ok)
+	assert.True(t, bounds.Contains(42.6, -5.6))
+
+	_, ok = GeohashBounds("ezs4a")
+	assert.False(t, ok, "a is not in the geohash alphabet")
+}
+
+func TestParseGeoBounds(t *testing.T) {
+	bounds, err := ParseGeoBounds("37.3, 55.5,37.9,56.0")
+	require.NoError(t, err)
+	assert.Equal(t, Location{Latitude: 55.5, Longitude: 37.3}, bounds.SouthWest)
+	assert.Equal(t, Location{Latitude: 56.0, Longitude: 37.9}, bounds.NorthEast)
+
+	for _, value := range []string{"", "37.3,55.5,37.9", "37.3,55.5,37.9,north", "37.9,55.5,37.3,56.0", "37.3,55.5,37.9,91"} {
+		_, err := ParseGeoBounds(value)
+		assert.ErrorIs(t, err, ErrInvalidBoundingBox, value)
+	}
+}
+
+func TestNewSupplyHeatmap(t *testing.T) {
+	query := &SupplyHeatmapQuery{Precision: 5}
+	heatmap := NewSupplyHeatmap(query, []*SupplyCellCount{
+		{Geohash: "ucfv1", Status: StatusAvailable, Drivers: 2},
+		{Geohash: "ucfv0", Status: StatusAvailable, Drivers: 3},
+		{Geohash: "ucfv0", Status: StatusBusy, Drivers: 1},
+	}, time.Now())
+
+	assert.Equal(t, 6, heatmap.Total)
+	assert.Equal(t, map[Status]int{StatusAvailable: 5, StatusBusy: 1}, heatmap.ByStatus)
+	require.Len(t, heatmap.Cells, 2)
+	assert.Equal(t, "ucfv0", heatmap.Cells[0].Geohash)
+	assert.Equal(t, 4, heatmap.Cells[0].Total)
+	assert.True(t, heatmap.Cells[0].Bounds.Contains(heatmap.Cells[0].Latitude, heatmap.Cells[0].Longitude))
+}
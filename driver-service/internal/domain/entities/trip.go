@@ -0,0 +1,289 @@
+package entities
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripEndReason причина завершения поездки
+type TripEndReason string
+
+const (
+	// TripEndStop водитель стоял дольше минимальной длительности остановки
+	TripEndStop TripEndReason = "stop"
+	// TripEndGap разрыв трека: путь водителя после последней точки неизвестен
+	TripEndGap TripEndReason = "gap"
+	// TripEndOrderChange начался или завершился заказ
+	TripEndOrderChange TripEndReason = "order_change"
+	// TripEndRangeEnd поездка не завершилась в запрошенном периоде
+	TripEndRangeEnd TripEndReason = "range_end"
+)
+
+// TripPoint точка маршрута поездки
+type TripPoint struct {
+	Latitude   float64   `json:"latitude"`
+	Longitude  float64   `json:"longitude"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// Trip поездка, восстановленная по истории местоположений
+type Trip struct {
+	// OrderID заказ, во время которого записаны точки поездки
+	OrderID         *uuid.UUID    `json:"order_id,omitempty"`
+	StartedAt       time.Time     `json:"started_at"`
+	EndedAt         time.Time     `json:"ended_at"`
+	Start           TripPoint     `json:"start"`
+	End             TripPoint     `json:"end"`
+	DistanceKm      float64       `json:"distance_km"`
+	DurationSeconds int64         `json:"duration_seconds"`
+	AverageSpeed    float64       `json:"average_speed_kmh"`
+	MaxSpeed        float64       `json:"max_speed_kmh"`
+	PointCount      int           `json:"point_count"`
+	EndReason       TripEndReason `json:"end_reason"`
+	// Polyline маршрут, упрощенный алгоритмом Дугласа-Пекера
+	Polyline []TripPoint `json:"polyline"`
+}
+
+// TripSummary поездки водителя за период
+type TripSummary struct {
+	DriverID             uuid.UUID `json:"driver_id"`
+	From                 time.Time `json:"from"`
+	To                   time.Time `json:"to"`
+	Trips                []*Trip   `json:"trips"`
+	Count                int       `json:"count"`
+	TotalDistanceKm      float64   `json:"total_distance_km"`
+	TotalDurationSeconds int64     `json:"total_duration_seconds"`
+}
+
+// TripSegmentation параметры разбиения истории местоположений на поездки
+type TripSegmentation struct {
+	// StopSpeed скорость в км/ч, ниже которой водитель считается стоящим
+	StopSpeed float64
+	// MinStopDuration остановка без заказа дольше этого интервала завершает поездку
+	MinStopDuration time.Duration
+	// MaxGapInterval интервал между точками, после которого трек считается разорванным;
+	// 0 отключает поиск разрывов
+	MaxGapInterval time.Duration
+	// MinDistanceKm более короткие поездки отбрасываются как дрейф GPS
+	MinDistanceKm float64
+	// SimplifyTolerance допустимое отклонение упрощенного маршрута в метрах; 0 сохраняет все точки
+	SimplifyTolerance float64
+}
+
+// SegmentTrips разбивает точки, упорядоченные по времени записи, на поездки.
+// Поездка начинается с движения или с первой точки заказа и завершается остановкой
+// дольше MinStopDuration, разрывом трека или сменой заказа. Во время заказа остановки
+// поездку не завершают: ожидание пассажира и пробки остаются частью одного маршрута
+func SegmentTrips(locations []*DriverLocation, params TripSegmentation) []*Trip {
+	var trips []*Trip
+	var current []*DriverLocation
+	// stopAt индекс в current первой точки текущей остановки, -1 — водитель в движении
+	stopAt := -1
+
+	closeTrip := func(reason TripEndReason) {
+		points := current
+		if stopAt > 0 {
+			// Поездка заканчивается в момент прибытия, а не после ожидания
+			points = current[:stopAt+1]
+		}
+		if trip := newTrip(points, reason, params); trip != nil {
+			trips = append(trips, trip)
+		}
+		current, stopAt = nil, -1
+	}
+
+	for i, location := range locations {
+		var prev *DriverLocation
+		if i > 0 {
+			prev = locations[i-1]
+		}
+		gap := prev != nil && params.MaxGapInterval > 0 &&
+			location.RecordedAt.Sub(prev.RecordedAt) > params.MaxGapInterval
+
+		if len(current) > 0 {
+			switch {
+			case gap:
+				closeTrip(TripEndGap)
+			case !sameOrder(current[len(current)-1], location):
+				closeTrip(TripEndOrderChange)
+			}
+		}
+
+		moving := pointSpeed(prev, location) >= params.StopSpeed
+		onOrder := location.OrderID() != nil
+
+		if len(current) == 0 {
+			if !moving && !onOrder {
+				continue
+			}
+			// Точка отправления — последняя точка стоянки перед началом движения
+			if prev != nil && !gap && sameOrder(prev, location) {
+				current = append(current, prev)
+			}
+			current = append(current, location)
+			if !moving {
+				stopAt = len(current) - 1
+			}
+			continue
+		}
+
+		current = append(current, location)
+		if moving {
+			stopAt = -1
+			continue
+		}
+		if stopAt < 0 {
+			stopAt = len(current) - 1
+		}
+		if !onOrder && location.RecordedAt.Sub(current[stopAt].RecordedAt) >= params.MinStopDuration {
+			closeTrip(TripEndStop)
+		}
+	}
+
+	if len(current) > 0 {
+		closeTrip(TripEndRangeEnd)
+	}
+	return trips
+}
+
+// newTrip рассчитывает показатели поездки; возвращает nil для слишком коротких поездок
+func newTrip(points []*DriverLocation, reason TripEndReason, params TripSegmentation) *Trip {
+	if len(points) < 2 {
+		return nil
+	}
+
+	first, last := points[0], points[len(points)-1]
+	trip := &Trip{
+		OrderID:    last.OrderID(),
+		StartedAt:  first.RecordedAt,
+		EndedAt:    last.RecordedAt,
+		Start:      toTripPoint(first),
+		End:        toTripPoint(last),
+		PointCount: len(points),
+		EndReason:  reason,
+	}
+
+	for i := 1; i < len(points); i++ {
+		trip.DistanceKm += points[i-1].DistanceTo(points[i])
+		if speed := pointSpeed(points[i-1], points[i]); speed > trip.MaxSpeed {
+			trip.MaxSpeed = speed
+		}
+	}
+	if trip.DistanceKm < params.MinDistanceKm {
+		return nil
+	}
+
+	duration := last.RecordedAt.Sub(first.RecordedAt)
+	trip.DurationSeconds = int64(duration.Seconds())
+	if duration > 0 {
+		trip.AverageSpeed = trip.DistanceKm / duration.Hours()
+	}
+	trip.Polyline = SimplifyTrack(points, params.SimplifyTolerance)
+	return trip
+}
+
+// SimplifyTrack упрощает маршрут алгоритмом Дугласа-Пекера: отбрасывает точки,
+// отклоняющиеся от упрощенной линии не больше чем на toleranceMeters.
+// Первая и последняя точки сохраняются всегда
+func SimplifyTrack(points []*DriverLocation, toleranceMeters float64) []TripPoint {
+	if len(points) == 0 {
+		return nil
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	if toleranceMeters > 0 {
+		simplifyRange(points, 0, len(points)-1, toleranceMeters, keep)
+	} else {
+		for i := range keep {
+			keep[i] = true
+		}
+	}
+
+	polyline := make([]TripPoint, 0, len(points))
+	for i, point := range points {
+		if keep[i] {
+			polyline = append(polyline, toTripPoint(point))
+		}
+	}
+	return polyline
+}
+
+// simplifyRange отмечает точки между first и last, которые нужно сохранить
+func simplifyRange(points []*DriverLocation, first, last int, toleranceMeters float64, keep []bool) {
+	if last-first < 2 {
+		return
+	}
+
+	farthest, maxDistance := -1, 0.0
+	for i := first + 1; i < last; i++ {
+		if distance := segmentDistanceMeters(points[i], points[first], points[last]); distance > maxDistance {
+			farthest, maxDistance = i, distance
+		}
+	}
+	if farthest < 0 || maxDistance <= toleranceMeters {
+		return
+	}
+
+	keep[farthest] = true
+	simplifyRange(points, first, farthest, toleranceMeters, keep)
+	simplifyRange(points, farthest, last, toleranceMeters, keep)
+}
+
+// segmentDistanceMeters расстояние от точки до отрезка ab в метрах. Координаты
+// проецируются на плоскость, касательную в точке a: на длине поездки ошибка пренебрежимо мала
+func segmentDistanceMeters(point, a, b *DriverLocation) float64 {
+	const metersPerDegree = 6371000.0 * math.Pi / 180
+
+	scale := math.Cos(a.Latitude * math.Pi / 180)
+	project := func(location *DriverLocation) (float64, float64) {
+		return (location.Longitude - a.Longitude) * scale * metersPerDegree,
+			(location.Latitude - a.Latitude) * metersPerDegree
+	}
+
+	px, py := project(point)
+	bx, by := project(b)
+
+	lengthSquared := bx*bx + by*by
+	if lengthSquared == 0 {
+		return math.Hypot(px, py)
+	}
+	t := math.Max(0, math.Min(1, (px*bx+py*by)/lengthSquared))
+	return math.Hypot(px-t*bx, py-t*by)
+}
+
+// pointSpeed скорость в точке в км/ч: переданная устройством или рассчитанная
+// по расстоянию от предыдущей точки
+func pointSpeed(prev, location *DriverLocation) float64 {
+	if location.Speed != nil {
+		return *location.Speed
+	}
+	if prev == nil {
+		return 0
+	}
+	hours := location.RecordedAt.Sub(prev.RecordedAt).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return prev.DistanceTo(location) / hours
+}
+
+// sameOrder проверяет, что точки записаны в рамках одного заказа или обе вне заказа
+func sameOrder(a, b *DriverLocation) bool {
+	orderA, orderB := a.OrderID(), b.OrderID()
+	if orderA == nil || orderB == nil {
+		return orderA == nil && orderB == nil
+	}
+	return *orderA == *orderB
+}
+
+// toTripPoint преобразует местоположение в точку маршрута
+func toTripPoint(location *DriverLocation) TripPoint {
+	return TripPoint{
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		RecordedAt: location.RecordedAt,
+	}
+}
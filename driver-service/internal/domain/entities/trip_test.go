@@ -0,0 +1,108 @@
+package entities
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withOrder(location *DriverLocation, orderID uuid.UUID) *DriverLocation {
+	location.Metadata = Metadata{LocationMetaOrderID: orderID.String(), LocationMetaOnTrip: true}
+	return location
+}
+
+func TestSegmentTrips(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+	orderID := uuid.New()
+
+	track := []*DriverLocation{
+		// Свободная поездка, затем стоянка дольше трех минут
+		newTrackPoint(at(0), 55.70, 0),
+		newTrackPoint(at(1), 55.71, 40),
+		newTrackPoint(at(2), 55.72, 40),
+		newTrackPoint(at(3), 55.73, 0),
+		newTrackPoint(at(4), 55.73, 0),
+		newTrackPoint(at(6), 55.73, 0),
+		// Заказ: ожидание пассажира поездку не завершает
+		withOrder(newTrackPoint(at(7), 55.74, 50), orderID),
+		withOrder(newTrackPoint(at(8), 55.75, 0), orderID),
+		withOrder(newTrackPoint(at(12), 55.75, 0), orderID),
+		withOrder(newTrackPoint(at(13), 55.76, 50), orderID),
+		newTrackPoint(at(14), 55.77, 0),
+		// После разрыва трека
+		newTrackPoint(at(40), 55.78, 40),
+		newTrackPoint(at(41), 55.79, 40),
+	}
+
+	trips := SegmentTrips(track, TripSegmentation{
+		StopSpeed:       5,
+		MinStopDuration: 3 * time.Minute,
+		MaxGapInterval:  5 * time.Minute,
+		MinDistanceKm:   0.2,
+	})
+	require.Len(t, trips, 3)
+
+	assert.Equal(t, TripEndStop, trips[0].EndReason)
+	assert.Nil(t, trips[0].OrderID)
+	assert.Equal(t, at(0), trips[0].StartedAt)
+	assert.Equal(t, at(3), trips[0].EndedAt)
+	assert.Equal(t, 4, trips[0].PointCount)
+	assert.InDelta(t, 3.34, trips[0].DistanceKm, 0.01)
+	assert.Equal(t, int64(180), trips[0].DurationSeconds)
+	assert.InDelta(t, 66.7, trips[0].AverageSpeed, 0.2)
+	assert.Equal(t, 40.0, trips[0].MaxSpeed)
+
+	assert.Equal(t, TripEndOrderChange, trips[1].EndReason)
+	require.NotNil(t, trips[1].OrderID)
+	assert.Equal(t, orderID, *trips[1].OrderID)
+	assert.Equal(t, at(7), trips[1].StartedAt)
+	assert.Equal(t, at(13), trips[1].EndedAt)
+	assert.InDelta(t, 2.22, trips[1].DistanceKm, 0.01)
+
+	assert.Equal(t, TripEndRangeEnd, trips[2].EndReason)
+	assert.Equal(t, at(40), trips[2].StartedAt)
+	assert.Equal(t, 2, trips[2].PointCount)
+}
+
+func TestSegmentTrips_DropsGPSDrift(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	track := []*DriverLocation{
+		newTrackPoint(start, 55.7000, 0),
+		newTrackPoint(start.Add(time.Minute), 55.7005, 10),
+		newTrackPoint(start.Add(2*time.Minute), 55.7000, 0),
+	}
+
+	trips := SegmentTrips(track, TripSegmentation{StopSpeed: 5, MinStopDuration: time.Minute, MinDistanceKm: 0.2})
+	assert.Empty(t, trips)
+}
+
+func TestSimplifyTrack(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	point := func(minute int, lat, lon float64) *DriverLocation {
+		location := newTrackPoint(start.Add(time.Duration(minute)*time.Minute), lat, 40)
+		location.Longitude = lon
+		return location
+	}
+	track := []*DriverLocation{
+		point(0, 55.700, 37.600),
+		// Отклонение около трех метров от прямой
+		point(1, 55.705, 37.60005),
+		point(2, 55.710, 37.600),
+		// Поворот
+		point(3, 55.710, 37.620),
+	}
+
+	polyline := SimplifyTrack(track, 10)
+	require.Len(t, polyline, 3)
+	assert.Equal(t, 55.700, polyline[0].Latitude)
+	assert.Equal(t, 55.710, polyline[1].Latitude)
+	assert.Equal(t, 37.600, polyline[1].Longitude)
+	assert.Equal(t, 37.620, polyline[2].Longitude)
+
+	assert.Len(t, SimplifyTrack(track, 0), 4)
+	assert.Len(t, SimplifyTrack(track, 1), 4)
+}
@@ -0,0 +1,325 @@
+package entities
+
+import (
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// webhookMaxNameLength ограничение длины имени подписчика
+	webhookMaxNameLength = 100
+	// webhookMinSecretLength наименьшая длина ключа подписи, заданного подписчиком
+	webhookMinSecretLength = 16
+	// webhookMaxSecretLength наибольшая длина ключа подписи
+	webhookMaxSecretLength = 256
+	// webhookMaxErrorLength сколько символов ошибки последней попытки сохраняется
+	webhookMaxErrorLength = 500
+)
+
+// WebhookEventTypeList типы событий подписки, хранимые в JSONB
+type WebhookEventTypeList []string
+
+// Value реализует driver.Valuer
+func (l WebhookEventTypeList) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(l)
+}
+
+// Scan реализует sql.Scanner
+func (l *WebhookEventTypeList) Scan(value interface{}) error {
+	if value == nil {
+		*l = nil
+		return nil
+	}
+
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("cannot scan %T into WebhookEventTypeList", value)
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// Contains проверяет, подписан ли список на событие
+func (l WebhookEventTypeList) Contains(eventType string) bool {
+	for _, t := range l {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscription подписка внешнего получателя (партнера) на события водителей.
+// Ключ подписи хранится открыто: он нужен для HMAC каждого запроса, наружу не отдается
+type WebhookSubscription struct {
+	ID uuid.UUID `json:"id" db:"id"`
+	// Name получатель, например имя партнера
+	Name       string               `json:"name" db:"name"`
+	URL        string               `json:"url" db:"url"`
+	EventTypes WebhookEventTypeList `json:"event_types" db:"event_types"`
+	Secret     string               `json:"-" db:"secret"`
+	CreatedBy  string               `json:"created_by" db:"created_by"`
+	CreatedAt  time.Time            `json:"created_at" db:"created_at"`
+}
+
+// WebhookSubscriptionRequest запрос регистрации подписки
+type WebhookSubscriptionRequest struct {
+	Name       string   `json:"name" binding:"required"`
+	URL        string   `json:"url" binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+	// Secret ключ подписи; без него ключ генерируется и возвращается один раз в ответе
+	Secret string `json:"secret,omitempty"`
+	// CreatedBy администратор, зарегистрировавший подписку; субъект токена
+	CreatedBy string `json:"-"`
+}
+
+// Validate проверяет имя, адрес http(s), непустой список событий и длину ключа подписи.
+// Известность типов событий проверяет сервис по каталогу событий
+func (r *WebhookSubscriptionRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" || len(r.Name) > webhookMaxNameLength {
+		return ErrInvalidWebhook
+	}
+
+	target, err := url.Parse(strings.TrimSpace(r.URL))
+	if err != nil || (target.Scheme != "https" && target.Scheme != "http") || target.Host == "" {
+		return ErrInvalidWebhook
+	}
+	r.URL = target.String()
+
+	if len(r.EventTypes) == 0 {
+		return ErrInvalidWebhook
+	}
+	for i, eventType := range r.EventTypes {
+		r.EventTypes[i] = strings.TrimSpace(eventType)
+		if r.EventTypes[i] == "" {
+			return ErrInvalidWebhook
+		}
+	}
+
+	if r.Secret != "" && (len(r.Secret) < webhookMinSecretLength || len(r.Secret) > webhookMaxSecretLength) {
+		return ErrInvalidWebhook
+	}
+	return nil
+}
+
+// CreatedWebhookSubscription зарегистрированная подписка вместе с ключом подписи, который
+// больше не будет показан
+type CreatedWebhookSubscription struct {
+	*WebhookSubscription
+	SigningSecret string `json:"secret"`
+}
+
+// NewWebhookSubscription создает подписку по проверенному запросу
+func NewWebhookSubscription(req *WebhookSubscriptionRequest) (*CreatedWebhookSubscription, error) {
+	secret := req.Secret
+	if secret == "" {
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = base64.RawURLEncoding.EncodeToString(raw)
+	}
+
+	eventTypes := make(WebhookEventTypeList, 0, len(req.EventTypes))
+	for _, eventType := range req.EventTypes {
+		if !eventTypes.Contains(eventType) {
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+
+	return &CreatedWebhookSubscription{
+		WebhookSubscription: &WebhookSubscription{
+			ID:         uuid.New(),
+			Name:       req.Name,
+			URL:        req.URL,
+			EventTypes: eventTypes,
+			Secret:     secret,
+			CreatedBy:  req.CreatedBy,
+			CreatedAt:  time.Now(),
+		},
+		SigningSecret: secret,
+	}, nil
+}
+
+// WebhookPayload тело запроса к получателю (JSON), хранимое в JSONB. Подпись считается
+// по этим байтам, поэтому повторные попытки отправляют тело без изменений
+type WebhookPayload []byte
+
+// Value реализует driver.Valuer
+func (p WebhookPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return []byte("{}"), nil
+	}
+	return []byte(p), nil
+}
+
+// Scan реализует sql.Scanner
+func (p *WebhookPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(WebhookPayload(nil), v...)
+	case string:
+		*p = WebhookPayload(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookPayload", value)
+	}
+	return nil
+}
+
+// MarshalJSON отдает тело как вложенный JSON
+func (p WebhookPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+// WebhookEvent тело запроса к получателю: событие водителя в конверте
+type WebhookEvent struct {
+	// ID события; общий для всех подписок и повторных отправок, по нему получатель
+	// отбрасывает повторы
+	ID         uuid.UUID   `json:"id"`
+	Type       string      `json:"type"`
+	DriverID   uuid.UUID   `json:"driver_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookDeliveryStatus статус доставки события получателю
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending доставка ожидает очередной попытки
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered получатель ответил 2xx
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed попытки исчерпаны; доставку можно повторить вручную
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// IsValid проверяет, что статус доставки известен
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDelivery доставка одного события одной подписке; журнал доставок
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	SubscriptionID uuid.UUID             `json:"subscription_id" db:"subscription_id"`
+	EventID        uuid.UUID             `json:"event_id" db:"event_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	DriverID       uuid.UUID             `json:"driver_id" db:"driver_id"`
+	Payload        WebhookPayload        `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	// NextAttemptAt время следующей попытки ожидающей доставки
+	NextAttemptAt  time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	LastStatusCode *int       `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      *string    `json:"last_error,omitempty" db:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+	// RedeliveryOf доставка, которую повторил администратор
+	RedeliveryOf *uuid.UUID `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NewWebhookDelivery создает ожидающую доставку события подписке. nextAttemptAt позволяет
+// отложить подхват доставки задачей повторов, пока ее отправляет создавший экземпляр
+func NewWebhookDelivery(subscriptionID, eventID uuid.UUID, eventType string, driverID uuid.UUID, payload WebhookPayload, now, nextAttemptAt time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventType:      eventType,
+		DriverID:       driverID,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  nextAttemptAt,
+		CreatedAt:      now,
+	}
+}
+
+// Redeliver создает новую доставку того же события с тем же телом; исходная доставка
+// остается в журнале без изменений
+func (d *WebhookDelivery) Redeliver(now, nextAttemptAt time.Time) *WebhookDelivery {
+	redelivery := NewWebhookDelivery(d.SubscriptionID, d.EventID, d.EventType, d.DriverID, d.Payload, now, nextAttemptAt)
+	original := d.ID
+	redelivery.RedeliveryOf = &original
+	return redelivery
+}
+
+// RecordAttempt сохраняет итог попытки: ответ 2xx завершает доставку, иначе следующая
+// попытка назначается через backoff, а после maxAttempts попыток доставка считается неудачной
+func (d *WebhookDelivery) RecordAttempt(at time.Time, statusCode int, err error, maxAttempts int, backoff time.Duration) {
+	d.Attempts++
+	attemptAt := at
+	d.LastAttemptAt = &attemptAt
+	d.LastStatusCode = nil
+	d.LastError = nil
+	if statusCode != 0 {
+		code := statusCode
+		d.LastStatusCode = &code
+	}
+
+	if err == nil && statusCode >= 200 && statusCode < 300 {
+		d.Status = WebhookDeliveryDelivered
+		d.DeliveredAt = &attemptAt
+		return
+	}
+
+	message := fmt.Sprintf("unexpected status %d", statusCode)
+	if err != nil {
+		message = err.Error()
+	}
+	if len(message) > webhookMaxErrorLength {
+		message = message[:webhookMaxErrorLength]
+	}
+	d.LastError = &message
+
+	if d.Attempts >= maxAttempts {
+		d.Status = WebhookDeliveryFailed
+		return
+	}
+	d.Status = WebhookDeliveryPending
+	d.NextAttemptAt = at.Add(backoff)
+}
+
+// WebhookBackoff пауза перед повторной попыткой после attempts неудачных: initial,
+// затем вдвое больше после каждой попытки, но не больше max
+func WebhookBackoff(attempts int, initial, max time.Duration) time.Duration {
+	backoff := initial
+	for i := 1; i < attempts && backoff < max; i++ {
+		backoff *= 2
+	}
+	if backoff > max {
+		backoff = max
+	}
+	return backoff
+}
+
+// WebhookDeliveryFilters фильтры журнала доставок
+type WebhookDeliveryFilters struct {
+	SubscriptionID uuid.UUID
+	// Status отбирает доставки с этим статусом; пустой — все
+	Status WebhookDeliveryStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptionRequest_Validate(t *testing.T) {
+	valid := func() *WebhookSubscriptionRequest {
+		return &WebhookSubscriptionRequest{
+			Name:       "Partner",
+			URL:        " https://partner.example.com/hook ",
+			EventTypes: []string{" driver.status.changed "},
+		}
+	}
+
+	req := valid()
+	require.NoError(t, req.Validate())
+	assert.Equal(t, "https://partner.example.com/hook", req.URL)
+	assert.Equal(t, []string{"driver.status.changed"}, req.EventTypes)
+
+	invalid := []func(r *WebhookSubscriptionRequest){
+		func(r *WebhookSubscriptionRequest) { r.Name = "  " },
+		func(r *WebhookSubscriptionRequest) { r.URL = "partner.example.com/hook" },
+		func(r *WebhookSubscriptionRequest) { r.URL = "ftp://partner.example.com/hook" },
+		func(r *WebhookSubscriptionRequest) { r.EventTypes = nil },
+		func(r *WebhookSubscriptionRequest) { r.EventTypes = []string{""} },
+		func(r *WebhookSubscriptionRequest) { r.Secret = "short" },
+	}
+	for i, mutate := range invalid {
+		req := valid()
+		mutate(req)
+		assert.ErrorIs(t, req.Validate(), ErrInvalidWebhook, i)
+	}
+}
+
+func TestWebhookDelivery_RecordAttempt(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	delivery := NewWebhookDelivery(uuid.New(), uuid.New(), "driver.shift.ended", uuid.New(), WebhookPayload(`{}`), now, now)
+
+	delivery.RecordAttempt(now, 500, nil, 3, time.Minute)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, now.Add(time.Minute), delivery.NextAttemptAt)
+	assert.Equal(t, "unexpected status 500", *delivery.LastError)
+
+	delivery.RecordAttempt(now, 0, errors.New("timeout"), 3, 2*time.Minute)
+	assert.Equal(t, WebhookDeliveryPending, delivery.Status)
+	assert.Nil(t, delivery.LastStatusCode)
+
+	delivery.RecordAttempt(now, 502, nil, 3, 4*time.Minute)
+	assert.Equal(t, WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+
+	redelivery := delivery.Redeliver(now, now)
+	assert.Equal(t, WebhookDeliveryPending, redelivery.Status)
+	assert.Zero(t, redelivery.Attempts)
+	assert.Equal(t, delivery.EventID, redelivery.EventID)
+	assert.Equal(t, delivery.ID, *redelivery.RedeliveryOf)
+
+	redelivery.RecordAttempt(now, 200, nil, 3, time.Minute)
+	assert.Equal(t, WebhookDeliveryDelivered, redelivery.Status)
+	assert.Nil(t, redelivery.LastError)
+	assert.Equal(t, now, *redelivery.DeliveredAt)
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, WebhookBackoff(1, 30*time.Second, time.Hour))
+	assert.Equal(t, 2*time.Minute, WebhookBackoff(3, 30*time.Second, time.Hour))
+	assert.Equal(t, time.Hour, WebhookBackoff(20, 30*time.Second, time.Hour))
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyPolicy параметры ключей API внутренних сервисов
+type APIKeyPolicy struct {
+	// DefaultRateLimit ограничение запросов в минуту для ключей, выпущенных без своего; 0 — без ограничения
+	DefaultRateLimit int
+	// CacheTTL как долго проверенный ключ не перечитывается из хранилища. Ключ, отозванный
+	// на другом экземпляре, перестает приниматься здесь не позже чем через CacheTTL
+	CacheTTL time.Duration
+}
+
+// APIKeyService интерфейс для ключей API внутренних сервисов
+type APIKeyService interface {
+	// IssueKey выпускает ключ; сам ключ возвращается только здесь
+	IssueKey(ctx context.Context, req *entities.IssueAPIKeyRequest) (*entities.IssuedAPIKey, error)
+	ListKeys(ctx context.Context) ([]*entities.APIKey, error)
+	RevokeKey(ctx context.Context, id uuid.UUID, actorID string) (*entities.APIKey, error)
+	// AuthenticateAPIKey возвращает действующий ключ или entities.ErrInvalidAPIKey
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error)
+}
+
+// apiKeyService реализация APIKeyService
+type apiKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	policy     APIKeyPolicy
+	logger     *zap.Logger
+
+	// cache проверенные ключи по хешу
+	cache *TTLCache[string, *entities.APIKey]
+}
+
+// NewAPIKeyService создает новый APIKeyService
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, policy APIKeyPolicy, logger *zap.Logger) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo: apiKeyRepo,
+		policy:     policy,
+		logger:     logger,
+		cache:      NewTTLCache[string, *entities.APIKey](policy.CacheTTL),
+	}
+}
+
+// IssueKey проверяет запрос и сохраняет хеш нового ключа
+func (s *apiKeyService) IssueKey(ctx context.Context, req *entities.IssueAPIKeyRequest) (*entities.IssuedAPIKey, error) {
+	if err := req.Validate(time.Now()); err != nil {
+		return nil, err
+	}
+	if req.RateLimitPerMinute == 0 {
+		req.RateLimitPerMinute = s.policy.DefaultRateLimit
+	}
+
+	issued, err := entities.NewAPIKey(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Create(ctx, issued.APIKey); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("API key issued",
+		zap.String("api_key_id", issued.ID.String()),
+		zap.String("name", issued.Name),
+		zap.Strings("scopes", issued.Scopes),
+		zap.String("created_by", issued.CreatedBy),
+	)
+
+	return issued, nil
+}
+
+// ListKeys возвращает все ключи, новые первыми
+func (s *apiKeyService) ListKeys(ctx context.Context) ([]*entities.APIKey, error) {
+	return s.apiKeyRepo.List(ctx)
+}
+
+// RevokeKey отзывает ключ; на этом экземпляре ключ перестает приниматься сразу
+func (s *apiKeyService) RevokeKey(ctx context.Context, id uuid.UUID, actorID string) (*entities.APIKey, error) {
+	key, err := s.apiKeyRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := key.Revoke(actorID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Revoke(ctx, key); err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(key.KeyHash)
+
+	s.logger.Info("API key revoked",
+		zap.String("api_key_id", key.ID.String()),
+		zap.String("name", key.Name),
+		zap.String("revoked_by", actorID),
+	)
+
+	return key, nil
+}
+
+// AuthenticateAPIKey находит ключ по хешу и проверяет, что он не отозван и не истек
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error) {
+	if !strings.HasPrefix(key, entities.APIKeyPrefix) {
+		return nil, entities.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	keyHash := entities.HashAPIKey(key)
+	stored, ok := s.cache.Get(keyHash, now)
+	if !ok {
+		var err error
+		stored, err = s.apiKeyRepo.GetByHash(ctx, keyHash)
+		if err == entities.ErrAPIKeyNotFound {
+			return nil, entities.ErrInvalidAPIKey
+		}
+		if err != nil {
+			return nil, err
+		}
+		s.cache.Set(keyHash, stored, now)
+	}
+
+	if !stored.IsActive(now) {
+		return nil, entities.ErrInvalidAPIKey
+	}
+	return stored, nil
+}
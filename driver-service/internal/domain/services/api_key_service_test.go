@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAPIKeyService_IssueAuthenticateRevoke(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewAPIKeyRepository()
+	service := NewAPIKeyService(repo, APIKeyPolicy{DefaultRateLimit: 600, CacheTTL: time.Minute}, zap.NewNop())
+
+	issued, err := service.IssueKey(ctx, &entities.IssueAPIKeyRequest{
+		Name:      "order-service",
+		Scopes:    []string{entities.APIKeyScopeDispatcher},
+		CreatedBy: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 600, issued.RateLimitPerMinute)
+	assert.Contains(t, issued.Key, issued.Prefix)
+	assert.NotContains(t, issued.KeyHash, issued.Key)
+
+	stored, err := repo.GetByID(ctx, issued.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.HashAPIKey(issued.Key), stored.KeyHash)
+
+	key, err := service.AuthenticateAPIKey(ctx, issued.Key)
+	require.NoError(t, err)
+	assert.Equal(t, "service:order-service", key.Subject())
+
+	_, err = service.AuthenticateAPIKey(ctx, issued.Key+"x")
+	assert.Equal(t, entities.ErrInvalidAPIKey, err)
+	_, err = service.AuthenticateAPIKey(ctx, "not-a-key")
+	assert.Equal(t, entities.ErrInvalidAPIKey, err)
+
+	// Отзыв действует сразу, несмотря на кэш
+	revoked, err := service.RevokeKey(ctx, issued.ID, "admin-2")
+	require.NoError(t, err)
+	assert.Equal(t, "admin-2", *revoked.RevokedBy)
+	_, err = service.AuthenticateAPIKey(ctx, issued.Key)
+	assert.Equal(t, entities.ErrInvalidAPIKey, err)
+
+	_, err = service.RevokeKey(ctx, issued.ID, "admin-2")
+	assert.Equal(t, entities.ErrAPIKeyRevoked, err)
+
+	keys, err := service.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
+
+func TestAPIKeyService_IssueValidation(t *testing.T) {
+	ctx := context.Background()
+	service := NewAPIKeyService(memory.NewAPIKeyRepository(), APIKeyPolicy{}, zap.NewNop())
+	past := time.Now().Add(-time.Hour)
+
+	for name, req := range map[string]*entities.IssueAPIKeyRequest{
+		"driver scope":   {Name: "billing", Scopes: []string{"driver"}, CreatedBy: "admin-1"},
+		"no scopes":      {Name: "billing", CreatedBy: "admin-1"},
+		"no creator":     {Name: "billing", Scopes: []string{entities.APIKeyScopeAdmin}},
+		"negative limit": {Name: "billing", Scopes: []string{entities.APIKeyScopeAdmin}, CreatedBy: "admin-1", RateLimitPerMinute: -1},
+		"expired":        {Name: "billing", Scopes: []string{entities.APIKeyScopeAdmin}, CreatedBy: "admin-1", ExpiresAt: &past},
+		"only pii scope": {Name: "billing", Scopes: []string{entities.ScopePIIRead}, CreatedBy: "admin-1"},
+	} {
+		_, err := service.IssueKey(ctx, req)
+		assert.Equal(t, entities.ErrInvalidAPIKeyRequest, err, name)
+	}
+
+	// Доступ к персональным данным выдается вместе с ролью
+	key, err := service.IssueKey(ctx, &entities.IssueAPIKeyRequest{
+		Name:      "billing",
+		Scopes:    []string{entities.APIKeyScopeAdmin, entities.ScopePIIRead},
+		CreatedBy: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, []string(key.Scopes), entities.ScopePIIRead)
+}
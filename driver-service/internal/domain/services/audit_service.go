@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AuditService интерфейс для просмотра журнала аудита водителей
+type AuditService interface {
+	// ListDriverEntries возвращает записи журнала водителя, новые первыми
+	ListDriverEntries(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.AuditEntry, error)
+	// ListEntries возвращает записи журнала по фильтрам, новые первыми
+	ListEntries(ctx context.Context, filters *entities.AuditFilters) ([]*entities.AuditEntry, error)
+	// VerifyChain проверяет цепочку хешей на отрезке номеров [from, to]; to = 0 — до последней записи
+	VerifyChain(ctx context.Context, from, to int64) (*entities.AuditChainVerification, error)
+	// AnchorChain закрепляет хеш последней записи цепочки во внешнем журнале;
+	// возвращает nil, если с прошлого закрепления новых записей нет
+	AnchorChain(ctx context.Context) (*entities.AuditAnchor, error)
+}
+
+// AuditAnchorSink внешний журнал, в котором закрепляются хеши цепочки аудита
+type AuditAnchorSink interface {
+	// Name имя журнала, сохраняемое в закреплении
+	Name() string
+	// Anchor записывает хеш во внешний журнал и возвращает идентификатор записи в нем
+	Anchor(ctx context.Context, anchor *entities.AuditAnchor) (string, error)
+}
+
+// AuditPolicy параметры журнала аудита
+type AuditPolicy struct {
+	// HashChain новые записи журнала связываются цепочкой хешей
+	HashChain bool
+	// VerifyBatchSize сколько записей читается за один запрос при проверке цепочки
+	VerifyBatchSize int
+}
+
+// auditService реализация AuditService
+type auditService struct {
+	auditRepo  repositories.AuditRepository
+	driverRepo repositories.DriverRepository
+	anchorSink AuditAnchorSink
+	policy     AuditPolicy
+	logger     *zap.Logger
+}
+
+// NewAuditService создает новый AuditService.
+// anchorSink может быть nil, если закрепление хешей не настроено.
+func NewAuditService(
+	auditRepo repositories.AuditRepository,
+	driverRepo repositories.DriverRepository,
+	anchorSink AuditAnchorSink,
+	policy AuditPolicy,
+	logger *zap.Logger,
+) AuditService {
+	return &auditService{
+		auditRepo:  auditRepo,
+		driverRepo: driverRepo,
+		anchorSink: anchorSink,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// ListDriverEntries получает журнал аудита существующего водителя
+func (s *auditService) ListDriverEntries(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.AuditEntry, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.auditRepo.ListByDriver(ctx, driverID, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list driver audit entries",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ListEntries получает записи журнала аудита по фильтрам
+func (s *auditService) ListEntries(ctx context.Context, filters *entities.AuditFilters) ([]*entities.AuditEntry, error) {
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return nil, entities.ErrInvalidAuditFilters
+	}
+
+	entries, err := s.auditRepo.List(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list audit entries", zap.Error(err))
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// VerifyChain пересчитывает хеши записей отрезка и сверяет их со ссылками соседних записей
+// и с закреплениями во внешнем журнале. Проверка останавливается на первом нарушении
+func (s *auditService) VerifyChain(ctx context.Context, from, to int64) (*entities.AuditChainVerification, error) {
+	if !s.policy.HashChain {
+		return nil, entities.ErrAuditChainDisabled
+	}
+	if from <= 0 {
+		from = 1
+	}
+
+	var headSequence int64
+	head, err := s.auditRepo.GetChainHead(ctx)
+	switch err {
+	case nil:
+		headSequence = *head.Sequence
+	case entities.ErrAuditEntryNotFound:
+	default:
+		return nil, err
+	}
+	if to <= 0 || to > headSequence {
+		to = headSequence
+	}
+	if headSequence > 0 && from > to {
+		return nil, entities.ErrInvalidAuditRange
+	}
+
+	result := &entities.AuditChainVerification{
+		From:       from,
+		To:         to,
+		VerifiedAt: time.Now(),
+	}
+	if result.LatestAnchor, err = s.auditRepo.GetLatestAnchor(ctx); err != nil && err != entities.ErrAuditAnchorNotFound {
+		return nil, err
+	}
+
+	result.Break, err = s.verifyRange(ctx, result, headSequence)
+	if err != nil {
+		return nil, err
+	}
+	result.Valid = result.Break == nil
+
+	if !result.Valid {
+		s.logger.Warn("Audit hash chain is broken",
+			zap.Int64("sequence", result.Break.Sequence),
+			zap.String("reason", result.Break.Reason),
+		)
+	}
+
+	return result, nil
+}
+
+// verifyRange проверяет записи отрезка result.From..result.To и заполняет счетчики
+func (s *auditService) verifyRange(ctx context.Context, result *entities.AuditChainVerification, headSequence int64) (*entities.AuditChainBreak, error) {
+	// Удаленные записи в конце цепочки видны только по закреплению
+	if latest := result.LatestAnchor; latest != nil && latest.Sequence > headSequence && result.To == headSequence {
+		return &entities.AuditChainBreak{Sequence: headSequence + 1, Reason: entities.AuditBreakSequenceGap}, nil
+	}
+	if headSequence == 0 {
+		return nil, nil
+	}
+
+	prevHash := entities.AuditChainGenesis
+	if result.From > 1 {
+		prev, err := s.auditRepo.GetChainEntry(ctx, result.From-1)
+		if err == entities.ErrAuditEntryNotFound {
+			return &entities.AuditChainBreak{Sequence: result.From - 1, Reason: entities.AuditBreakSequenceGap}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if prev.Hash != nil {
+			prevHash = *prev.Hash
+		}
+	}
+
+	anchors, err := s.auditRepo.ListAnchors(ctx, result.From, result.To)
+	if err != nil {
+		return nil, err
+	}
+	anchorsBySequence := make(map[int64][]*entities.AuditAnchor, len(anchors))
+	for _, anchor := range anchors {
+		anchorsBySequence[anchor.Sequence] = append(anchorsBySequence[anchor.Sequence], anchor)
+	}
+
+	verifier := entities.NewAuditChainVerifier(result.From, prevHash)
+	defer func() { result.Checked = verifier.Checked() }()
+
+	for cursor := result.From; cursor <= result.To; {
+		batch, err := s.auditRepo.ListChain(ctx, cursor, result.To, s.policy.VerifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit chain: %w", err)
+		}
+		if len(batch) == 0 {
+			return &entities.AuditChainBreak{Sequence: cursor, Reason: entities.AuditBreakSequenceGap}, nil
+		}
+
+		for _, entry := range batch {
+			if chainBreak := verifier.Check(entry); chainBreak != nil {
+				return chainBreak, nil
+			}
+			for _, anchor := range anchorsBySequence[*entry.Sequence] {
+				if chainBreak := entities.CheckAnchor(entry, anchor); chainBreak != nil {
+					return chainBreak, nil
+				}
+				result.AnchorsChecked++
+			}
+		}
+		cursor = *batch[len(batch)-1].Sequence + 1
+	}
+
+	return nil, nil
+}
+
+// AnchorChain проверяет записи после прошлого закрепления и закрепляет хеш последней записи.
+// Нарушенная цепочка не закрепляется: иначе подделка получила бы подтверждение
+func (s *auditService) AnchorChain(ctx context.Context) (*entities.AuditAnchor, error) {
+	if !s.policy.HashChain || s.anchorSink == nil {
+		return nil, entities.ErrAuditChainDisabled
+	}
+
+	head, err := s.auditRepo.GetChainHead(ctx)
+	if err == entities.ErrAuditEntryNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	from := int64(1)
+	latest, err := s.auditRepo.GetLatestAnchor(ctx)
+	switch err {
+	case nil:
+		if latest.Sequence >= *head.Sequence {
+			return nil, nil
+		}
+		from = latest.Sequence
+	case entities.ErrAuditAnchorNotFound:
+	default:
+		return nil, err
+	}
+
+	verification, err := s.VerifyChain(ctx, from, *head.Sequence)
+	if err != nil {
+		return nil, err
+	}
+	if !verification.Valid {
+		return nil, entities.ErrAuditChainBroken
+	}
+
+	anchor := entities.NewAuditAnchor(head, s.anchorSink.Name())
+	if anchor.Reference, err = s.anchorSink.Anchor(ctx, anchor); err != nil {
+		return nil, fmt.Errorf("failed to anchor audit chain: %w", err)
+	}
+	if err := s.auditRepo.CreateAnchor(ctx, anchor); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Audit hash chain anchored",
+		zap.Int64("sequence", anchor.Sequence),
+		zap.String("hash", anchor.Hash),
+		zap.String("sink", anchor.Sink),
+		zap.String("reference", anchor.Reference),
+	)
+
+	return anchor, nil
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingAnchorSink запоминает закрепленные хеши
+type recordingAnchorSink struct {
+	anchors []*entities.AuditAnchor
+}
+
+func (s *recordingAnchorSink) Name() string { return "test" }
+
+func (s *recordingAnchorSink) Anchor(ctx context.Context, anchor *entities.AuditAnchor) (string, error) {
+	s.anchors = append(s.anchors, anchor)
+	return "ref-1", nil
+}
+
+func TestAuditService_VerifyAndAnchorChain(t *testing.T) {
+	ctx := context.Background()
+	raw := memory.NewAuditRepository()
+	auditRepo := repositories.WithHashChain(raw)
+	sink := &recordingAnchorSink{}
+	service := NewAuditService(auditRepo, memory.NewDriverRepository(), sink,
+		AuditPolicy{HashChain: true, VerifyBatchSize: 2}, zap.NewNop())
+
+	// Пустая цепочка целостна, закреплять нечего
+	verification, err := service.VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+	anchor, err := service.AnchorChain(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, anchor)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, auditRepo.Create(ctx, entities.NewAuditEntry(entities.AuditEventFleetValidation, "update", "approved")))
+	}
+
+	anchor, err = service.AnchorChain(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, anchor)
+	assert.Equal(t, int64(5), anchor.Sequence)
+	assert.Equal(t, "ref-1", anchor.Reference)
+	require.Len(t, sink.anchors, 1)
+
+	// Новых записей нет: повторное закрепление не нужно
+	anchor, err = service.AnchorChain(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, anchor)
+
+	verification, err = service.VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, int64(1), verification.From)
+	assert.Equal(t, int64(5), verification.To)
+	assert.Equal(t, 5, verification.Checked)
+	assert.Equal(t, 1, verification.AnchorsChecked)
+	require.NotNil(t, verification.LatestAnchor)
+
+	verification, err = service.VerifyChain(ctx, 3, 4)
+	require.NoError(t, err)
+	assert.True(t, verification.Valid)
+	assert.Equal(t, 2, verification.Checked)
+
+	_, err = service.VerifyChain(ctx, 7, 0)
+	assert.Equal(t, entities.ErrInvalidAuditRange, err)
+
+	// Запись, добавленная в обход цепочки, обнаруживается и не закрепляется
+	head, err := auditRepo.GetChainHead(ctx)
+	require.NoError(t, err)
+	forged := entities.NewAuditEntry(entities.AuditEventFleetValidation, "update", "approved")
+	sequence, bogus := *head.Sequence+1, "forged"
+	forged.Sequence, forged.PrevHash, forged.Hash = &sequence, head.Hash, &bogus
+	require.NoError(t, raw.Create(ctx, forged))
+
+	verification, err = service.VerifyChain(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.False(t, verification.Valid)
+	require.NotNil(t, verification.Break)
+	assert.Equal(t, int64(6), verification.Break.Sequence)
+	assert.Equal(t, entities.AuditBreakHash, verification.Break.Reason)
+
+	_, err = service.AnchorChain(ctx)
+	assert.Equal(t, entities.ErrAuditChainBroken, err)
+	assert.Len(t, sink.anchors, 1)
+}
+
+func TestAuditService_ChainDisabled(t *testing.T) {
+	service := NewAuditService(memory.NewAuditRepository(), memory.NewDriverRepository(), nil,
+		AuditPolicy{VerifyBatchSize: 100}, zap.NewNop())
+
+	_, err := service.VerifyChain(context.Background(), 0, 0)
+	assert.Equal(t, entities.ErrAuditChainDisabled, err)
+	_, err = service.AnchorChain(context.Background())
+	assert.Equal(t, entities.ErrAuditChainDisabled, err)
+}
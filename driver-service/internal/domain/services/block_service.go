@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// expiredBlocksBatch сколько блокировок с наступившим сроком снимается за один проход
+const expiredBlocksBatch = 100
+
+// BlockService интерфейс для блокировок водителей
+type BlockService interface {
+	// Block блокирует водителя с кодом причины и необязательным сроком автоматического снятия
+	Block(ctx context.Context, driverID uuid.UUID, req *entities.BlockRequest) (*entities.DriverBlock, error)
+	// Unblock снимает активную блокировку водителя и возвращает ему статус до блокировки
+	Unblock(ctx context.Context, driverID uuid.UUID, actorID, comment string) (*entities.DriverBlock, error)
+	// History возвращает историю блокировок водителя, последние первыми
+	History(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverBlock, error)
+	// ReleaseExpired снимает блокировки с наступившим сроком и возвращает их число
+	ReleaseExpired(ctx context.Context) (int, error)
+}
+
+// blockService реализация BlockService
+type blockService struct {
+	blockRepo  repositories.BlockRepository
+	driverRepo repositories.DriverRepository
+	auditRepo  repositories.AuditRepository
+	eventBus   EventPublisher
+	logger     *zap.Logger
+}
+
+// NewBlockService создает новый BlockService
+func NewBlockService(
+	blockRepo repositories.BlockRepository,
+	driverRepo repositories.DriverRepository,
+	auditRepo repositories.AuditRepository,
+	eventBus EventPublisher,
+	logger *zap.Logger,
+) BlockService {
+	return &blockService{
+		blockRepo:  blockRepo,
+		driverRepo: driverRepo,
+		auditRepo:  auditRepo,
+		eventBus:   eventBus,
+		logger:     logger,
+	}
+}
+
+// Block переводит водителя в статус blocked и сохраняет блокировку в истории. Водитель
+// получает уведомление driver.blocked через событие смены статуса
+func (s *blockService) Block(ctx context.Context, driverID uuid.UUID, req *entities.BlockRequest) (*entities.DriverBlock, error) {
+	now := time.Now()
+	if err := req.Validate(now); err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if driver.Status == entities.StatusBlocked {
+		return nil, entities.ErrDriverAlreadyBlocked
+	}
+
+	block := entities.NewDriverBlock(driverID, driver.Status, req, now)
+	if err := s.blockRepo.Create(ctx, block); err != nil {
+		return nil, err
+	}
+
+	entry := s.newEntry(ctx, entities.AuditEventDriverBlock, "block", driver)
+	entry.Before = entities.Metadata{"status": block.PreviousStatus}
+	entry.After = entities.Metadata{"status": entities.StatusBlocked}
+	entry.Details["reason"] = string(block.Reason)
+	entry.Details["blocked_by"] = block.BlockedBy
+	if block.UnblockAt != nil {
+		entry.Details["unblock_at"] = *block.UnblockAt
+	}
+	s.record(ctx, entry)
+
+	s.publishStatusChanged(ctx, driverID, block.PreviousStatus, entities.StatusBlocked, block.BlockedBy)
+	eventData := map[string]interface{}{
+		"reason":     string(block.Reason),
+		"blocked_by": block.BlockedBy,
+	}
+	if block.UnblockAt != nil {
+		eventData["unblock_at"] = *block.UnblockAt
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverBlocked, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish driver blocked event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Warn("Driver blocked",
+		zap.String("driver_id", driverID.String()),
+		zap.String("reason", string(block.Reason)),
+		zap.String("blocked_by", block.BlockedBy),
+		zap.String("previous_status", string(block.PreviousStatus)),
+	)
+	return block, nil
+}
+
+// Unblock снимает блокировку по решению администратора
+func (s *blockService) Unblock(ctx context.Context, driverID uuid.UUID, actorID, comment string) (*entities.DriverBlock, error) {
+	if strings.TrimSpace(actorID) == "" || utf8.RuneCountInString(comment) > entities.MaxBlockCommentLength {
+		return nil, entities.ErrInvalidBlock
+	}
+
+	block, err := s.blockRepo.GetActive(ctx, driverID)
+	if err == entities.ErrBlockNotFound {
+		if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+			return nil, err
+		}
+		return nil, entities.ErrDriverNotBlocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.release(ctx, block, actorID, entities.UnblockReasonManual, comment); err != nil {
+		if err == entities.ErrBlockNotFound {
+			return nil, entities.ErrDriverNotBlocked
+		}
+		return nil, err
+	}
+	return block, nil
+}
+
+// History получает историю блокировок водителя
+func (s *blockService) History(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverBlock, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return s.blockRepo.ListByDriverID(ctx, driverID)
+}
+
+// ReleaseExpired снимает блокировки с наступившим сроком пачками по expiredBlocksBatch.
+// Ошибка одной блокировки не останавливает снятие остальных в пачке; блокировка с ошибкой
+// будет снята при следующем запуске
+func (s *blockService) ReleaseExpired(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		blocks, err := s.blockRepo.ListExpired(ctx, time.Now(), expiredBlocksBatch)
+		if err != nil {
+			return released, err
+		}
+
+		failed := 0
+		for _, block := range blocks {
+			err := s.release(ctx, block, entities.BlockSystemActor, entities.UnblockReasonExpired, "")
+			switch {
+			case err == nil:
+				released++
+			case err == entities.ErrBlockNotFound:
+				// Блокировку уже снял администратор или другой запуск
+			default:
+				s.logger.Error("Failed to release expired driver block",
+					zap.Error(err),
+					zap.String("driver_id", block.DriverID.String()),
+				)
+				failed++
+			}
+		}
+
+		// Блокировки с ошибкой остались бы в следующей пачке
+		if len(blocks) < expiredBlocksBatch || failed > 0 {
+			break
+		}
+	}
+
+	if released > 0 {
+		s.logger.Info("Expired driver blocks released", zap.Int("released", released))
+	}
+	return released, nil
+}
+
+// release снимает блокировку, записывает снятие в журнал аудита и публикует события
+func (s *blockService) release(ctx context.Context, block *entities.DriverBlock, actorID string, reason entities.UnblockReason, comment string) error {
+	block.Lift(time.Now(), actorID, reason, comment)
+	status := block.RestoreStatus()
+
+	restored, err := s.blockRepo.Release(ctx, block, status)
+	if err != nil {
+		return err
+	}
+
+	entry := entities.NewAuditEntry(entities.AuditEventDriverUnblock, "unblock", "completed")
+	entry.DriverID = &block.DriverID
+	entry.AttachActor(ctx)
+	if restored {
+		entry.Before = entities.Metadata{"status": entities.StatusBlocked}
+		entry.After = entities.Metadata{"status": status}
+	}
+	entry.Details["reason"] = string(reason)
+	entry.Details["block_reason"] = string(block.Reason)
+	entry.Details["unblocked_by"] = *block.UnblockedBy
+	s.record(ctx, entry)
+
+	// Статус водителя, которого уже перевели из blocked вручную, не меняется
+	if !restored {
+		s.logger.Info("Driver block released without status change",
+			zap.String("driver_id", block.DriverID.String()),
+			zap.String("reason", string(reason)),
+		)
+		return nil
+	}
+
+	s.publishStatusChanged(ctx, block.DriverID, entities.StatusBlocked, status, *block.UnblockedBy)
+	eventData := map[string]interface{}{
+		"reason":       string(reason),
+		"block_reason": string(block.Reason),
+		"unblocked_by": *block.UnblockedBy,
+		"status":       string(status),
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverUnblocked, block.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish driver unblocked event",
+			zap.Error(err),
+			zap.String("driver_id", block.DriverID.String()),
+		)
+	}
+
+	s.logger.Info("Driver unblocked",
+		zap.String("driver_id", block.DriverID.String()),
+		zap.String("reason", string(reason)),
+		zap.String("unblocked_by", *block.UnblockedBy),
+		zap.String("status", string(status)),
+	)
+	return nil
+}
+
+// publishStatusChanged публикует смену статуса: на нее подписаны уведомления водителю
+func (s *blockService) publishStatusChanged(ctx context.Context, driverID uuid.UUID, from, to entities.Status, changedBy string) {
+	eventData := map[string]interface{}{
+		"old_status": string(from),
+		"new_status": string(to),
+		"changed_by": changedBy,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverStatusChanged, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish driver status changed event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+}
+
+// newEntry создает выполненную запись журнала по водителю с автором из контекста
+func (s *blockService) newEntry(ctx context.Context, event, action string, driver *entities.Driver) *entities.AuditEntry {
+	entry := entities.NewAuditEntry(event, action, "completed")
+	entry.DriverID = &driver.ID
+	entry.FleetID = driver.FleetID
+	entry.AttachActor(ctx)
+	return entry
+}
+
+// record сохраняет запись в журнал аудита; ошибка журнала не отменяет блокировку
+func (s *blockService) record(ctx context.Context, entry *entities.AuditEntry) {
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record driver block audit entry",
+			zap.Error(err),
+			zap.String("event", entry.Event),
+			zap.String("driver_id", entry.DriverID.String()),
+		)
+	}
+}
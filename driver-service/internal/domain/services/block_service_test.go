@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestBlockService(t *testing.T, status entities.Status) (BlockService, *memory.BlockRepository, *memory.DriverRepository, *memory.AuditRepository, *recordingEventPublisher, *entities.Driver) {
+	driverRepo := memory.NewDriverRepository()
+	blockRepo := memory.NewBlockRepository(driverRepo)
+	auditRepo := memory.NewAuditRepository()
+	events := &recordingEventPublisher{}
+
+	driver := newTestDriver("801")
+	driver.ID = uuid.New()
+	driver.Status = status
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	return NewBlockService(blockRepo, driverRepo, auditRepo, events, zap.NewNop()), blockRepo, driverRepo, auditRepo, events, driver
+}
+
+func TestBlockService_BlockAndUnblock(t *testing.T) {
+	ctx := context.Background()
+	service, _, driverRepo, auditRepo, events, driver := newTestBlockService(t, entities.StatusOnShift)
+
+	_, err := service.Block(ctx, driver.ID, &entities.BlockRequest{Reason: "rude", BlockedBy: "admin-1"})
+	assert.ErrorIs(t, err, entities.ErrInvalidBlock)
+
+	unblockAt := time.Now().Add(24 * time.Hour)
+	block, err := service.Block(ctx, driver.ID, &entities.BlockRequest{
+		Reason: entities.BlockReasonSafety, Comment: " жалоба пассажира ", UnblockAt: &unblockAt, BlockedBy: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusOnShift, block.PreviousStatus)
+	assert.Equal(t, "жалоба пассажира", block.Comment)
+	assert.True(t, events.has("driver.blocked"))
+	assert.True(t, events.has("driver.status.changed"))
+
+	blocked, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusBlocked, blocked.Status)
+
+	_, err = service.Block(ctx, driver.ID, &entities.BlockRequest{Reason: entities.BlockReasonFraud, BlockedBy: "admin-2"})
+	assert.ErrorIs(t, err, entities.ErrDriverAlreadyBlocked)
+
+	unblocked, err := service.Unblock(ctx, driver.ID, "admin-2", "разобрались")
+	require.NoError(t, err)
+	require.NotNil(t, unblocked.UnblockReason)
+	assert.Equal(t, entities.UnblockReasonManual, *unblocked.UnblockReason)
+	assert.Equal(t, "admin-2", *unblocked.UnblockedBy)
+	assert.True(t, events.has("driver.unblocked"))
+
+	// Водитель с линии возвращается неактивным
+	restored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusInactive, restored.Status)
+
+	_, err = service.Unblock(ctx, driver.ID, "admin-2", "")
+	assert.ErrorIs(t, err, entities.ErrDriverNotBlocked)
+
+	history, err := service.History(ctx, driver.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.False(t, history[0].IsActive())
+
+	entries, err := auditRepo.ListByDriver(ctx, driver.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.ElementsMatch(t,
+		[]string{entities.AuditEventDriverBlock, entities.AuditEventDriverUnblock},
+		[]string{entries[0].Event, entries[1].Event})
+}
+
+func TestBlockService_ReleaseExpired(t *testing.T) {
+	ctx := context.Background()
+	service, blockRepo, driverRepo, _, _, driver := newTestBlockService(t, entities.StatusVerified)
+
+	// Блокировка с прошедшим сроком, например сохраненная до простоя задачи
+	expiredAt := time.Now().Add(-time.Minute)
+	block := entities.NewDriverBlock(driver.ID, entities.StatusVerified, &entities.BlockRequest{
+		Reason: entities.BlockReasonDocumentFraud, UnblockAt: &expiredAt, BlockedBy: "admin-1",
+	}, time.Now().Add(-time.Hour))
+	require.NoError(t, blockRepo.Create(ctx, block))
+
+	// Бессрочная блокировка другого водителя не снимается
+	other := newTestDriver("802")
+	other.ID = uuid.New()
+	other.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, other))
+	_, err := service.Block(ctx, other.ID, &entities.BlockRequest{Reason: entities.BlockReasonFraud, BlockedBy: "admin-1"})
+	require.NoError(t, err)
+
+	released, err := service.ReleaseExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+
+	restored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusVerified, restored.Status)
+
+	history, err := service.History(ctx, driver.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, entities.UnblockReasonExpired, *history[0].UnblockReason)
+	assert.Equal(t, entities.BlockSystemActor, *history[0].UnblockedBy)
+
+	stillBlocked, err := driverRepo.GetByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusBlocked, stillBlocked.Status)
+
+	released, err = service.ReleaseExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, released)
+}
+
+func TestBlockService_ReleaseKeepsManuallyChangedStatus(t *testing.T) {
+	ctx := context.Background()
+	service, _, driverRepo, _, events, driver := newTestBlockService(t, entities.StatusAvailable)
+
+	_, err := service.Block(ctx, driver.ID, &entities.BlockRequest{Reason: entities.BlockReasonFraud, BlockedBy: "admin-1"})
+	require.NoError(t, err)
+
+	// Статус сменили в обход блокировки: снятие закрывает блокировку, не трогая статус
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusSuspended))
+	_, err = service.Unblock(ctx, driver.ID, "admin-1", "")
+	require.NoError(t, err)
+	assert.False(t, events.has("driver.unblocked"))
+
+	current, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusSuspended, current.Status)
+}
+
+func TestDriverService_ChangeStatusToBlockedRequiresBlockAPI(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, _ := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("803"))
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.ErrBlockRequiresReason, service.ChangeDriverStatus(ctx, driver.ID, entities.StatusBlocked))
+}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// bulkStatusChunkSize сколько водителей проверяется и обновляется одной транзакцией
+// в пакетном режиме массовой смены статуса
+const bulkStatusChunkSize = 100
+
+// errBulkStatusFailed сообщение в результате водителя при ошибке хранилища; подробности
+// только в логе
+var errBulkStatusFailed = errors.New("failed to change driver status")
+
+// BulkStatusService интерфейс для массовой смены статуса водителей
+type BulkStatusService interface {
+	// ChangeStatus переводит водителей в статус по обычным правилам переходов и возвращает
+	// итог по каждому водителю: updated, skipped или error
+	ChangeStatus(ctx context.Context, req *entities.BulkStatusRequest) (*entities.BulkStatusReport, error)
+}
+
+// bulkStatusService реализация BulkStatusService
+type bulkStatusService struct {
+	driverRepo repositories.DriverRepository
+	auditRepo  repositories.AuditRepository
+	eventBus   EventPublisher
+	logger     *zap.Logger
+}
+
+// NewBulkStatusService создает новый BulkStatusService
+func NewBulkStatusService(
+	driverRepo repositories.DriverRepository,
+	auditRepo repositories.AuditRepository,
+	eventBus EventPublisher,
+	logger *zap.Logger,
+) BulkStatusService {
+	return &bulkStatusService{
+		driverRepo: driverRepo,
+		auditRepo:  auditRepo,
+		eventBus:   eventBus,
+		logger:     logger,
+	}
+}
+
+// bulkStatusBatch проверенная часть массовой смены: результаты по водителям в порядке
+// запроса и смены, которые можно применить
+type bulkStatusBatch struct {
+	results []*entities.BulkStatusResult
+	updates []entities.StatusUpdate
+	drivers map[uuid.UUID]*entities.Driver
+	failed  bool
+}
+
+// ChangeStatus выполняет массовую смену статуса. В пакетном режиме водители проверяются
+// и обновляются пачками по bulkStatusChunkSize в отдельных транзакциях. В атомарном режиме
+// все смены применяются одной транзакцией и только если ни одна проверка не завершилась ошибкой
+func (s *bulkStatusService) ChangeStatus(ctx context.Context, req *entities.BulkStatusRequest) (*entities.BulkStatusReport, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	report := &entities.BulkStatusReport{
+		OperationID: uuid.New(),
+		Status:      req.Status,
+		Atomic:      req.Atomic,
+		Results:     make([]*entities.BulkStatusResult, 0, len(req.DriverIDs)),
+		StartedAt:   time.Now(),
+	}
+
+	if req.Atomic {
+		batch := s.check(ctx, req.DriverIDs, req.Status)
+		if err := s.applyAtomic(ctx, req, report, batch); err != nil {
+			return nil, err
+		}
+		for _, result := range batch.results {
+			report.Add(result)
+		}
+	} else {
+		for start := 0; start < len(req.DriverIDs); start += bulkStatusChunkSize {
+			end := start + bulkStatusChunkSize
+			if end > len(req.DriverIDs) {
+				end = len(req.DriverIDs)
+			}
+
+			batch := s.check(ctx, req.DriverIDs[start:end], req.Status)
+			s.apply(ctx, req, report, batch)
+			for _, result := range batch.results {
+				report.Add(result)
+			}
+		}
+	}
+	report.CompletedAt = time.Now()
+
+	s.logger.Info("Bulk driver status change completed",
+		zap.String("operation_id", report.OperationID.String()),
+		zap.String("status", string(req.Status)),
+		zap.Bool("atomic", req.Atomic),
+		zap.String("actor_id", req.ActorID),
+		zap.Int("updated", report.Updated),
+		zap.Int("skipped", report.Skipped),
+		zap.Int("failed", report.Failed),
+	)
+	return report, nil
+}
+
+// check проверяет переход каждого водителя в статус по правилам ChangeDriverStatus.
+// Водитель вне области автопарков запроса считается не найденным
+func (s *bulkStatusService) check(ctx context.Context, driverIDs []uuid.UUID, status entities.Status) *bulkStatusBatch {
+	batch := &bulkStatusBatch{
+		results: make([]*entities.BulkStatusResult, 0, len(driverIDs)),
+		drivers: make(map[uuid.UUID]*entities.Driver, len(driverIDs)),
+	}
+
+	for _, id := range driverIDs {
+		result := &entities.BulkStatusResult{DriverID: id}
+		batch.results = append(batch.results, result)
+
+		driver, err := s.driverRepo.GetByID(ctx, id)
+		if err == nil && !entities.FleetScopeAllows(ctx, driver.FleetID) {
+			err = entities.ErrDriverNotFound
+		}
+		if errors.Is(err, entities.ErrDriverNotFound) {
+			batch.fail(result, entities.BulkStatusCodeNotFound, err)
+			continue
+		}
+		if err != nil {
+			s.logger.Error("Failed to get driver for bulk status change",
+				zap.Error(err),
+				zap.String("driver_id", id.String()),
+			)
+			batch.fail(result, entities.BulkStatusCodeFailed, errBulkStatusFailed)
+			continue
+		}
+		result.PreviousStatus = driver.Status
+
+		if driver.Status == status {
+			result.Result = entities.BulkStatusSkipped
+			result.Code = entities.BulkStatusCodeUnchanged
+			continue
+		}
+		if err := validateStatusTransition(driver.Status, status); err != nil {
+			batch.fail(result, entities.BulkStatusCodeInvalidTransition, err)
+			continue
+		}
+		if err := driver.ValidateForStage(entities.StageForStatus(status)); err != nil {
+			batch.fail(result, entities.BulkStatusCodeProfileIncomplete, err)
+			continue
+		}
+
+		batch.drivers[id] = driver
+		batch.updates = append(batch.updates, entities.StatusUpdate{DriverID: id, From: driver.Status, To: status})
+	}
+	return batch
+}
+
+// apply применяет проверенные смены пачки; смена, статус водителя которой изменился после
+// проверки, завершается ошибкой STATUS_CONFLICT
+func (s *bulkStatusService) apply(ctx context.Context, req *entities.BulkStatusRequest, report *entities.BulkStatusReport, batch *bulkStatusBatch) {
+	if len(batch.updates) == 0 {
+		return
+	}
+
+	updated, err := s.driverRepo.UpdateStatuses(ctx, batch.updates, false)
+	if err != nil {
+		s.logger.Error("Failed to apply bulk status chunk",
+			zap.Error(err),
+			zap.String("operation_id", report.OperationID.String()),
+		)
+		batch.resolve(nil, entities.BulkStatusError, entities.BulkStatusCodeFailed, errBulkStatusFailed)
+		return
+	}
+
+	batch.resolve(updated, entities.BulkStatusError, entities.BulkStatusCodeConflict, entities.ErrStatusConflict)
+	s.completed(ctx, req, report, batch, updated)
+}
+
+// applyAtomic применяет все смены одной транзакцией. Если проверка хотя бы одного водителя
+// завершилась ошибкой, ни одна смена не применяется
+func (s *bulkStatusService) applyAtomic(ctx context.Context, req *entities.BulkStatusRequest, report *entities.BulkStatusReport, batch *bulkStatusBatch) error {
+	if batch.failed {
+		batch.resolve(nil, entities.BulkStatusSkipped, entities.BulkStatusCodeAborted, nil)
+		return nil
+	}
+	if len(batch.updates) == 0 {
+		return nil
+	}
+
+	updated, err := s.driverRepo.UpdateStatuses(ctx, batch.updates, true)
+	if errors.Is(err, entities.ErrStatusConflict) {
+		batch.resolve(nil, entities.BulkStatusError, entities.BulkStatusCodeConflict, err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	batch.resolve(updated, entities.BulkStatusError, entities.BulkStatusCodeConflict, entities.ErrStatusConflict)
+	s.completed(ctx, req, report, batch, updated)
+	return nil
+}
+
+// completed записывает аудит и публикует смену статуса по каждому обновленному водителю
+func (s *bulkStatusService) completed(ctx context.Context, req *entities.BulkStatusRequest, report *entities.BulkStatusReport, batch *bulkStatusBatch, updated []uuid.UUID) {
+	for _, id := range updated {
+		driver := batch.drivers[id]
+
+		entry := entities.NewAuditEntry(entities.AuditEventDriverStatus, "bulk_change_status", "completed")
+		entry.DriverID = &driver.ID
+		entry.FleetID = driver.FleetID
+		entry.AttachActor(ctx)
+		entry.Before = entities.Metadata{"status": driver.Status}
+		entry.After = entities.Metadata{"status": req.Status}
+		entry.Details["operation_id"] = report.OperationID.String()
+		entry.Details["actor_id"] = req.ActorID
+		if req.Reason != "" {
+			entry.Details["reason"] = req.Reason
+		}
+		if err := s.auditRepo.Create(ctx, entry); err != nil {
+			s.logger.Error("Failed to record bulk status audit entry",
+				zap.Error(err),
+				zap.String("driver_id", id.String()),
+			)
+		}
+
+		eventData := map[string]interface{}{
+			"old_status": string(driver.Status),
+			"new_status": string(req.Status),
+			"changed_by": req.ActorID,
+		}
+		if err := s.eventBus.PublishDriverEvent(ctx, eventDriverStatusChanged, id, eventData); err != nil {
+			s.logger.Error("Failed to publish driver status changed event",
+				zap.Error(err),
+				zap.String("driver_id", id.String()),
+			)
+		}
+	}
+}
+
+// fail отмечает ошибку проверки водителя
+func (b *bulkStatusBatch) fail(result *entities.BulkStatusResult, code string, err error) {
+	result.Result = entities.BulkStatusError
+	result.Code = code
+	result.Message = err.Error()
+	b.failed = true
+}
+
+// resolve отмечает обновленных водителей; остальные проверенные смены получают outcome и code
+func (b *bulkStatusBatch) resolve(updated []uuid.UUID, outcome entities.BulkStatusOutcome, code string, err error) {
+	applied := make(map[uuid.UUID]bool, len(updated))
+	for _, id := range updated {
+		applied[id] = true
+	}
+
+	for _, result := range b.results {
+		if result.Result != "" {
+			continue
+		}
+		if applied[result.DriverID] {
+			result.Result = entities.BulkStatusUpdated
+			continue
+		}
+		result.Result = outcome
+		result.Code = code
+		if err != nil {
+			result.Message = err.Error()
+		}
+	}
+}
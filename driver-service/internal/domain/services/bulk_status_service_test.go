@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestBulkStatusService(t *testing.T, statuses ...entities.Status) (BulkStatusService, *memory.DriverRepository, *memory.AuditRepository, *recordingEventPublisher, []uuid.UUID) {
+	driverRepo := memory.NewDriverRepository()
+	auditRepo := memory.NewAuditRepository()
+	events := &recordingEventPublisher{}
+
+	ids := make([]uuid.UUID, 0, len(statuses))
+	for i, status := range statuses {
+		driver := newTestDriver(string(rune('a' + i)))
+		driver.ID = uuid.New()
+		driver.Status = status
+		require.NoError(t, driverRepo.Create(context.Background(), driver))
+		ids = append(ids, driver.ID)
+	}
+
+	return NewBulkStatusService(driverRepo, auditRepo, events, zap.NewNop()), driverRepo, auditRepo, events, ids
+}
+
+func TestBulkStatusService_ChangeStatus(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, auditRepo, events, ids := newTestBulkStatusService(t,
+		entities.StatusAvailable, entities.StatusOnShift, entities.StatusInactive, entities.StatusRegistered)
+	missing := uuid.New()
+
+	report, err := service.ChangeStatus(ctx, &entities.BulkStatusRequest{
+		DriverIDs: append(append([]uuid.UUID{}, ids...), missing, ids[0]),
+		Status:    entities.StatusInactive,
+		Reason:    "плановые работы",
+		ActorID:   "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Updated)
+	assert.Equal(t, 1, report.Skipped)
+	assert.Equal(t, 2, report.Failed)
+
+	// Результаты в порядке запроса, повтор водителя отброшен
+	require.Len(t, report.Results, 5)
+	assert.Equal(t, entities.BulkStatusUpdated, report.Results[0].Result)
+	assert.Equal(t, entities.StatusAvailable, report.Results[0].PreviousStatus)
+	assert.Equal(t, entities.BulkStatusUpdated, report.Results[1].Result)
+	assert.Equal(t, entities.BulkStatusCodeUnchanged, report.Results[2].Code)
+	assert.Equal(t, entities.BulkStatusCodeInvalidTransition, report.Results[3].Code)
+	assert.Equal(t, entities.BulkStatusCodeNotFound, report.Results[4].Code)
+
+	for _, id := range ids[:3] {
+		driver, err := driverRepo.GetByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, entities.StatusInactive, driver.Status)
+	}
+	assert.True(t, events.has("driver.status.changed"))
+
+	entries, err := auditRepo.ListByDriver(ctx, ids[0], 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "bulk_change_status", entries[0].Action)
+	assert.Equal(t, report.OperationID.String(), entries[0].Details["operation_id"])
+}
+
+func TestBulkStatusService_Atomic(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, _, _, ids := newTestBulkStatusService(t,
+		entities.StatusAvailable, entities.StatusOnShift, entities.StatusRegistered)
+
+	// Одна недопустимая смена отменяет все
+	report, err := service.ChangeStatus(ctx, &entities.BulkStatusRequest{
+		DriverIDs: ids, Status: entities.StatusSuspended, Atomic: true, ActorID: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Updated)
+	assert.Equal(t, 2, report.Skipped)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, entities.BulkStatusCodeAborted, report.Results[0].Code)
+
+	driver, err := driverRepo.GetByID(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusAvailable, driver.Status)
+
+	report, err = service.ChangeStatus(ctx, &entities.BulkStatusRequest{
+		DriverIDs: ids[:2], Status: entities.StatusSuspended, Atomic: true, ActorID: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Updated)
+
+	_, err = service.ChangeStatus(ctx, &entities.BulkStatusRequest{DriverIDs: ids, Status: entities.StatusBlocked, ActorID: "admin-1"})
+	assert.ErrorIs(t, err, entities.ErrBlockRequiresReason)
+	_, err = service.ChangeStatus(ctx, &entities.BulkStatusRequest{Status: entities.StatusInactive, ActorID: "admin-1"})
+	assert.ErrorIs(t, err, entities.ErrInvalidBulkStatus)
+}
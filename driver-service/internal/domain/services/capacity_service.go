@@ -0,0 +1,300 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// UsageCollector накопитель метрик нагрузки экземпляра сервиса
+type UsageCollector interface {
+	RecordPoolSample(sample entities.PoolSample)
+	// Drain возвращает нагрузку с начала периода и начинает новый период
+	Drain() *entities.CapacityUsage
+}
+
+// PoolStatsSource источник состояния пула соединений с базой данных
+type PoolStatsSource interface {
+	GetStats() sql.DBStats
+}
+
+// CapacityPolicy настройки планирования мощностей
+type CapacityPolicy struct {
+	// Instance имя экземпляра сервиса, под которым сохраняются его отчеты
+	Instance string
+	// DefaultLimitRPS допустимая частота запросов к эндпоинту на экземпляр, если для него
+	// не задан собственный лимит в EndpointLimits (ключ — "GET /api/v1/drivers/:id")
+	DefaultLimitRPS float64
+	EndpointLimits  map[string]float64
+	// WindowDays за сколько последних суток отчеты участвуют в прогнозе
+	WindowDays    int
+	HorizonDays   int
+	RetentionDays int
+}
+
+// CapacityService интерфейс сбора отчетов о нагрузке и прогноза мощностей
+type CapacityService interface {
+	SamplePool(ctx context.Context) error
+	GenerateDailyReport(ctx context.Context) (*entities.CapacityReport, error)
+	GetForecast(ctx context.Context) (*entities.CapacityForecast, error)
+	// SetLimits заменяет допустимую частоту запросов без перезапуска; действует с ближайшего отчета
+	SetLimits(defaultLimitRPS float64, endpointLimits map[string]float64)
+}
+
+// capacityService реализация CapacityService
+type capacityService struct {
+	capacityRepo repositories.CapacityRepository
+	collector    UsageCollector
+	pool         PoolStatsSource
+	policy       CapacityPolicy
+	logger       *zap.Logger
+
+	limitsMu     sync.RWMutex
+	defaultLimit float64
+	limits       map[string]float64
+}
+
+// NewCapacityService создает новый CapacityService. pool может быть nil, если сервис
+// работает без базы данных
+func NewCapacityService(
+	capacityRepo repositories.CapacityRepository,
+	collector UsageCollector,
+	pool PoolStatsSource,
+	policy CapacityPolicy,
+	logger *zap.Logger,
+) CapacityService {
+	service := &capacityService{
+		capacityRepo: capacityRepo,
+		collector:    collector,
+		pool:         pool,
+		policy:       policy,
+		logger:       logger,
+	}
+	service.SetLimits(policy.DefaultLimitRPS, policy.EndpointLimits)
+	return service
+}
+
+// SetLimits заменяет лимиты частоты запросов по эндпоинтам
+func (s *capacityService) SetLimits(defaultLimitRPS float64, endpointLimits map[string]float64) {
+	limits := make(map[string]float64, len(endpointLimits))
+	for route, limit := range endpointLimits {
+		limits[normalizeRoute(route)] = limit
+	}
+
+	s.limitsMu.Lock()
+	defer s.limitsMu.Unlock()
+	s.defaultLimit = defaultLimitRPS
+	s.limits = limits
+}
+
+// SamplePool снимает состояние пула соединений для расчета его насыщенности
+func (s *capacityService) SamplePool(ctx context.Context) error {
+	if s.pool == nil {
+		return nil
+	}
+
+	stats := s.pool.GetStats()
+	s.collector.RecordPoolSample(entities.PoolSample{
+		InUse:        stats.InUse,
+		MaxOpen:      stats.MaxOpenConnections,
+		WaitCount:    stats.WaitCount,
+		WaitDuration: stats.WaitDuration,
+	})
+	return nil
+}
+
+// GenerateDailyReport сохраняет нагрузку, накопленную экземпляром с прошлого отчета,
+// и удаляет отчеты старше срока хранения. Отчет относится к суткам начала периода
+func (s *capacityService) GenerateDailyReport(ctx context.Context) (*entities.CapacityReport, error) {
+	usage := s.collector.Drain()
+
+	report := &entities.CapacityReport{
+		Date:       reportDate(usage.From),
+		Instance:   s.policy.Instance,
+		PeriodFrom: usage.From,
+		PeriodTo:   usage.To,
+		Endpoints:  make(entities.EndpointUsageList, 0, len(usage.Endpoints)),
+		Pool:       usage.Pool,
+		CreatedAt:  time.Now(),
+	}
+	for _, endpoint := range usage.Endpoints {
+		if limit := s.limitFor(endpoint.Route); limit > 0 {
+			endpoint.LimitRPS = limit
+			endpoint.Utilization = endpoint.PeakRPS / limit
+		}
+		report.TotalRequests += endpoint.Requests
+		report.Endpoints = append(report.Endpoints, endpoint)
+	}
+
+	if err := s.capacityRepo.SaveReport(ctx, report); err != nil {
+		return nil, err
+	}
+
+	if s.policy.RetentionDays > 0 {
+		if _, err := s.capacityRepo.DeleteBefore(ctx, report.Date.AddDate(0, 0, -s.policy.RetentionDays)); err != nil {
+			s.logger.Warn("Failed to delete old capacity reports", zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Capacity report generated",
+		zap.Time("date", report.Date),
+		zap.Int64("requests", report.TotalRequests),
+		zap.Float64("pool_peak_saturation", report.Pool.PeakSaturation),
+	)
+
+	return report, nil
+}
+
+// capacityDay нагрузка за сутки по всем экземплярам
+type capacityDay struct {
+	date      time.Time
+	endpoints map[string]entities.EndpointUsage
+	pool      entities.PoolUsage
+}
+
+// GetForecast строит прогноз по отчетам за последние WindowDays суток линейной
+// экстраполяцией пиковой нагрузки на HorizonDays вперед
+func (s *capacityService) GetForecast(ctx context.Context) (*entities.CapacityForecast, error) {
+	now := time.Now()
+	reports, err := s.capacityRepo.ListReports(ctx, reportDate(now).AddDate(0, 0, -s.policy.WindowDays))
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, entities.ErrCapacityReportNotFound
+	}
+
+	days := groupCapacityDays(reports)
+	forecast := &entities.CapacityForecast{
+		GeneratedAt: now,
+		From:        days[0].date,
+		To:          days[len(days)-1].date,
+		Days:        len(days),
+		HorizonDays: s.policy.HorizonDays,
+		Endpoints:   []entities.EndpointForecast{},
+	}
+
+	routes := make(map[string]struct{})
+	for _, day := range days {
+		for route := range day.endpoints {
+			routes[route] = struct{}{}
+		}
+	}
+
+	// Сутки без отчетов (например, сервис не работал) пропускаются, а не считаются нулевой нагрузкой
+	offsets := make([]float64, len(days))
+	for i, day := range days {
+		offsets[i] = day.date.Sub(days[0].date).Hours() / 24
+	}
+
+	horizon := float64(s.policy.HorizonDays)
+	for route := range routes {
+		series := make([]float64, len(days))
+		for i, day := range days {
+			series[i] = day.endpoints[route].PeakRPS
+		}
+
+		slope, current := entities.LinearTrend(offsets, series)
+		endpoint := entities.EndpointForecast{
+			Route:            route,
+			LimitRPS:         s.limitFor(route),
+			CurrentPeakRPS:   current,
+			TrendPerDay:      slope,
+			ProjectedPeakRPS: math.Max(0, current+slope*horizon),
+		}
+		if endpoint.LimitRPS > 0 {
+			endpoint.ProjectedUtilization = endpoint.ProjectedPeakRPS / endpoint.LimitRPS
+			endpoint.DaysUntilLimit = entities.DaysUntil(current, slope, endpoint.LimitRPS)
+		}
+		forecast.Endpoints = append(forecast.Endpoints, endpoint)
+	}
+
+	// Первыми идут эндпоинты, которые раньше других упрутся в лимит
+	sort.Slice(forecast.Endpoints, func(i, j int) bool {
+		a, b := forecast.Endpoints[i], forecast.Endpoints[j]
+		if a.ProjectedUtilization != b.ProjectedUtilization {
+			return a.ProjectedUtilization > b.ProjectedUtilization
+		}
+		if a.ProjectedPeakRPS != b.ProjectedPeakRPS {
+			return a.ProjectedPeakRPS > b.ProjectedPeakRPS
+		}
+		return a.Route < b.Route
+	})
+
+	saturation := make([]float64, len(days))
+	for i, day := range days {
+		saturation[i] = day.pool.PeakSaturation
+	}
+	slope, current := entities.LinearTrend(offsets, saturation)
+	latest := days[len(days)-1].pool
+	forecast.Pool = entities.PoolForecast{
+		MaxOpenConnections:      latest.MaxOpenConnections,
+		CurrentPeakSaturation:   current,
+		TrendPerDay:             slope,
+		ProjectedPeakSaturation: math.Min(1, math.Max(0, current+slope*horizon)),
+		DaysUntilSaturation:     entities.DaysUntil(current, slope, 1),
+		CurrentWaitCount:        latest.WaitCount,
+	}
+
+	return forecast, nil
+}
+
+// limitFor возвращает допустимую частоту запросов к эндпоинту
+func (s *capacityService) limitFor(route string) float64 {
+	s.limitsMu.RLock()
+	defer s.limitsMu.RUnlock()
+
+	if limit, ok := s.limits[normalizeRoute(route)]; ok {
+		return limit
+	}
+	return s.defaultLimit
+}
+
+// groupCapacityDays объединяет отчеты экземпляров по суткам. Лимиты и пул соединений
+// у каждого экземпляра свои, поэтому за сутки берется самый нагруженный экземпляр
+func groupCapacityDays(reports []*entities.CapacityReport) []*capacityDay {
+	var days []*capacityDay
+	for _, report := range reports {
+		if len(days) == 0 || !days[len(days)-1].date.Equal(report.Date) {
+			days = append(days, &capacityDay{
+				date:      report.Date,
+				endpoints: make(map[string]entities.EndpointUsage),
+			})
+		}
+		day := days[len(days)-1]
+
+		for _, endpoint := range report.Endpoints {
+			if endpoint.PeakRPS >= day.endpoints[endpoint.Route].PeakRPS {
+				day.endpoints[endpoint.Route] = endpoint
+			}
+		}
+		if report.Pool.PeakSaturation >= day.pool.PeakSaturation {
+			day.pool = report.Pool
+		}
+	}
+	return days
+}
+
+// reportDate возвращает календарную дату момента t как полночь UTC
+func reportDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// normalizeRoute приводит ключ маршрута к виду "GET /path": конфигурация может
+// передать метод в нижнем регистре
+func normalizeRoute(route string) string {
+	method, path, found := strings.Cut(strings.TrimSpace(route), " ")
+	if !found {
+		return route
+	}
+	return strings.ToUpper(method) + " " + strings.TrimSpace(path)
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeUsageCollector struct {
+	usage   *entities.CapacityUsage
+	samples []entities.PoolSample
+}
+
+func (c *fakeUsageCollector) RecordPoolSample(sample entities.PoolSample) {
+	c.samples = append(c.samples, sample)
+}
+
+func (c *fakeUsageCollector) Drain() *entities.CapacityUsage {
+	return c.usage
+}
+
+type fakePoolStats struct{}
+
+func (fakePoolStats) GetStats() sql.DBStats {
+	return sql.DBStats{MaxOpenConnections: 25, InUse: 10, WaitCount: 3}
+}
+
+func newTestCapacityService(collector *fakeUsageCollector) (CapacityService, *memory.CapacityRepository) {
+	repo := memory.NewCapacityRepository()
+	service := NewCapacityService(repo, collector, fakePoolStats{}, CapacityPolicy{
+		Instance:        "api-1",
+		DefaultLimitRPS: 100,
+		EndpointLimits:  map[string]float64{"get /api/v1/locations/nearby": 20},
+		WindowDays:      28,
+		HorizonDays:     30,
+		RetentionDays:   90,
+	}, zap.NewNop())
+	return service, repo
+}
+
+func TestCapacityService_GenerateDailyReport(t *testing.T) {
+	ctx := context.Background()
+	from := time.Now().Add(-24 * time.Hour)
+	collector := &fakeUsageCollector{usage: &entities.CapacityUsage{
+		From: from,
+		To:   time.Now(),
+		Endpoints: []entities.EndpointUsage{
+			{Route: "GET /api/v1/locations/nearby", Requests: 1000, PeakRPS: 10},
+			{Route: "GET /api/v1/drivers", Requests: 500, PeakRPS: 5},
+		},
+		Pool: entities.PoolUsage{MaxOpenConnections: 25, PeakSaturation: 0.4},
+	}}
+	service, repo := newTestCapacityService(collector)
+
+	require.NoError(t, service.SamplePool(ctx))
+	require.Len(t, collector.samples, 1)
+	assert.Equal(t, 10, collector.samples[0].InUse)
+
+	// Отчет за пределами срока хранения удаляется при сохранении нового
+	require.NoError(t, repo.SaveReport(ctx, &entities.CapacityReport{
+		Date:     reportDate(from).AddDate(0, 0, -100),
+		Instance: "api-1",
+	}))
+
+	report, err := service.GenerateDailyReport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, reportDate(from), report.Date)
+	assert.Equal(t, "api-1", report.Instance)
+	assert.Equal(t, int64(1500), report.TotalRequests)
+	assert.Equal(t, 20.0, report.Endpoints[0].LimitRPS)
+	assert.InDelta(t, 0.5, report.Endpoints[0].Utilization, 0.001)
+	assert.Equal(t, 100.0, report.Endpoints[1].LimitRPS)
+
+	reports, err := repo.ListReports(ctx, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, report.Date, reports[0].Date)
+}
+
+func TestCapacityService_GetForecast(t *testing.T) {
+	ctx := context.Background()
+	service, repo := newTestCapacityService(&fakeUsageCollector{})
+
+	_, err := service.GetForecast(ctx)
+	assert.Equal(t, entities.ErrCapacityReportNotFound, err)
+
+	// Пик nearby растет на 1 RPS в сутки, пул — на 1/16 в сутки; сутки -3 пропущены
+	today := reportDate(time.Now())
+	for _, daysAgo := range []int{5, 4, 2, 1} {
+		offset := float64(5 - daysAgo)
+		for _, instance := range []string{"api-1", "api-2"} {
+			peak := 10 + offset
+			if instance == "api-2" {
+				peak -= 5
+			}
+			require.NoError(t, repo.SaveReport(ctx, &entities.CapacityReport{
+				Date:     today.AddDate(0, 0, -daysAgo),
+				Instance: instance,
+				Endpoints: entities.EndpointUsageList{
+					{Route: "GET /api/v1/locations/nearby", PeakRPS: peak},
+					{Route: "GET /api/v1/drivers", PeakRPS: 3},
+				},
+				Pool: entities.PoolUsage{MaxOpenConnections: 25, PeakSaturation: 0.25 + offset/16},
+			}))
+		}
+	}
+
+	forecast, err := service.GetForecast(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, forecast.Days)
+	assert.Equal(t, 30, forecast.HorizonDays)
+	require.Len(t, forecast.Endpoints, 2)
+
+	nearby := forecast.Endpoints[0]
+	assert.Equal(t, "GET /api/v1/locations/nearby", nearby.Route)
+	assert.InDelta(t, 1, nearby.TrendPerDay, 0.001)
+	assert.InDelta(t, 14, nearby.CurrentPeakRPS, 0.001)
+	assert.InDelta(t, 44, nearby.ProjectedPeakRPS, 0.001)
+	assert.InDelta(t, 2.2, nearby.ProjectedUtilization, 0.001)
+	require.NotNil(t, nearby.DaysUntilLimit)
+	assert.Equal(t, 6, *nearby.DaysUntilLimit)
+
+	drivers := forecast.Endpoints[1]
+	assert.InDelta(t, 0, drivers.TrendPerDay, 0.001)
+	assert.Nil(t, drivers.DaysUntilLimit)
+
+	assert.Equal(t, 25, forecast.Pool.MaxOpenConnections)
+	assert.InDelta(t, 0.5, forecast.Pool.CurrentPeakSaturation, 0.001)
+	assert.InDelta(t, 1, forecast.Pool.ProjectedPeakSaturation, 0.001)
+	require.NotNil(t, forecast.Pool.DaysUntilSaturation)
+	assert.Equal(t, 8, *forecast.Pool.DaysUntilSaturation)
+}
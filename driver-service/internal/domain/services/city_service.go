@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CityService интерфейс для регионов, городов и распределения водителей по городам
+type CityService interface {
+	CreateRegion(ctx context.Context, req *entities.RegionRequest) (*entities.Region, error)
+	ListRegions(ctx context.Context) ([]*entities.Region, error)
+
+	CreateCity(ctx context.Context, req *entities.CityRequest) (*entities.City, error)
+	GetCity(ctx context.Context, id uuid.UUID) (*entities.City, error)
+	// UpdateCity заменяет город; ключ города не меняется
+	UpdateCity(ctx context.Context, id uuid.UUID, req *entities.CityRequest) (*entities.City, error)
+	DeleteCity(ctx context.Context, id uuid.UUID) error
+	ListCities(ctx context.Context, filters *entities.CityFilters) ([]*entities.City, error)
+	CountCities(ctx context.Context, filters *entities.CityFilters) (int, error)
+
+	// ActiveDriverCounts возвращает число активных водителей по городам, больше всего
+	// водителей первыми
+	ActiveDriverCounts(ctx context.Context) ([]*entities.CityDriverCount, error)
+	CityLocator
+}
+
+// CityLocator относит точки и водителей к городам по границам
+type CityLocator interface {
+	// LocateDriver записывает в метаданные точки город, в границы которого она попала, и
+	// переназначает водителя в этот город. Точка вне всех городов не меняет город водителя
+	LocateDriver(ctx context.Context, driver *entities.Driver, location *entities.DriverLocation) error
+}
+
+// CityPolicy параметры городов
+type CityPolicy struct {
+	// CacheTTL время жизни кэша активных городов; изменения, сделанные через другой
+	// экземпляр, учитываются не позже чем через CacheTTL. 0 — читать города при каждой точке
+	CacheTTL time.Duration
+}
+
+// cityService реализация CityService
+type cityService struct {
+	cityRepo repositories.CityRepository
+	policy   CityPolicy
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	active   []*entities.City
+	loadedAt time.Time
+}
+
+// NewCityService создает новый CityService
+func NewCityService(cityRepo repositories.CityRepository, policy CityPolicy, logger *zap.Logger) CityService {
+	return &cityService{
+		cityRepo: cityRepo,
+		policy:   policy,
+		logger:   logger,
+	}
+}
+
+// CreateRegion создает регион
+func (s *cityService) CreateRegion(ctx context.Context, req *entities.RegionRequest) (*entities.Region, error) {
+	region := entities.NewRegion(req)
+	if err := region.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.cityRepo.CreateRegion(ctx, region); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Region created",
+		zap.String("region_id", region.ID.String()),
+		zap.String("code", region.Code),
+	)
+	return region, nil
+}
+
+// ListRegions получает все регионы
+func (s *cityService) ListRegions(ctx context.Context) ([]*entities.Region, error) {
+	return s.cityRepo.ListRegions(ctx)
+}
+
+// CreateCity создает город в существующем регионе
+func (s *cityService) CreateCity(ctx context.Context, req *entities.CityRequest) (*entities.City, error) {
+	city := entities.NewCity(req)
+	if err := city.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.cityRepo.GetRegion(ctx, city.RegionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.cityRepo.CreateCity(ctx, city); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("City created",
+		zap.String("city_id", city.ID.String()),
+		zap.String("key", city.Key),
+	)
+	return city, nil
+}
+
+// GetCity получает город по ID
+func (s *cityService) GetCity(ctx context.Context, id uuid.UUID) (*entities.City, error) {
+	return s.cityRepo.GetCity(ctx, id)
+}
+
+// UpdateCity заменяет название, регион, границу и активность города
+func (s *cityService) UpdateCity(ctx context.Context, id uuid.UUID, req *entities.CityRequest) (*entities.City, error) {
+	city, err := s.cityRepo.GetCity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(city)
+	city.UpdatedAt = time.Now()
+	if err := city.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.cityRepo.GetRegion(ctx, city.RegionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.cityRepo.UpdateCity(ctx, city); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("City updated",
+		zap.String("city_id", city.ID.String()),
+		zap.String("key", city.Key),
+	)
+	return city, nil
+}
+
+// DeleteCity удаляет город; водители, отнесенные к нему, снова считаются по городу из метаданных
+func (s *cityService) DeleteCity(ctx context.Context, id uuid.UUID) error {
+	if err := s.cityRepo.DeleteCity(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	s.logger.Info("City deleted", zap.String("city_id", id.String()))
+	return nil
+}
+
+// ListCities получает страницу городов
+func (s *cityService) ListCities(ctx context.Context, filters *entities.CityFilters) ([]*entities.City, error) {
+	return s.cityRepo.List(ctx, filters)
+}
+
+// CountCities возвращает число городов по фильтрам
+func (s *cityService) CountCities(ctx context.Context, filters *entities.CityFilters) (int, error) {
+	return s.cityRepo.Count(ctx, filters)
+}
+
+// ActiveDriverCounts считает активных водителей по городам и дополняет счетчики городов из
+// справочника названием и регионом
+func (s *cityService) ActiveDriverCounts(ctx context.Context) ([]*entities.CityDriverCount, error) {
+	counts, err := s.cityRepo.CountActiveDrivers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cities, err := s.cityRepo.List(ctx, &entities.CityFilters{})
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*entities.City, len(cities))
+	for _, city := range cities {
+		byKey[city.Key] = city
+	}
+
+	for _, count := range counts {
+		if count.City == nil {
+			continue
+		}
+		if city, ok := byKey[*count.City]; ok {
+			name, regionID := city.Name, city.RegionID
+			count.Name = &name
+			count.RegionID = &regionID
+		}
+	}
+	return counts, nil
+}
+
+// LocateDriver относит точку к первому активному городу, в границы которого она попала.
+// Город водителя сохраняется только при смене, поэтому точки внутри того же города
+// не пишут в таблицу водителей
+func (s *cityService) LocateDriver(ctx context.Context, driver *entities.Driver, location *entities.DriverLocation) error {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		return err
+	}
+
+	var found *entities.City
+	for _, city := range cities {
+		if city.Contains(location.Latitude, location.Longitude) {
+			found = city
+			break
+		}
+	}
+	if found == nil {
+		location.SetCity("")
+		return nil
+	}
+	location.SetCity(found.Key)
+
+	if driver.ResolvedCity != nil && *driver.ResolvedCity == found.Key {
+		return nil
+	}
+	if err := s.cityRepo.AssignDriver(ctx, driver.ID, found.Key); err != nil {
+		return err
+	}
+	key := found.Key
+	driver.ResolvedCity = &key
+
+	s.logger.Info("Driver assigned to city",
+		zap.String("driver_id", driver.ID.String()),
+		zap.String("city", found.Key),
+	)
+	return nil
+}
+
+// activeCities возвращает активные города из кэша или из репозитория
+func (s *cityService) activeCities(ctx context.Context) ([]*entities.City, error) {
+	s.mu.RLock()
+	cities, loadedAt := s.active, s.loadedAt
+	s.mu.RUnlock()
+
+	if !loadedAt.IsZero() && time.Since(loadedAt) < s.policy.CacheTTL {
+		return cities, nil
+	}
+
+	cities, err := s.cityRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active cities: %w", err)
+	}
+
+	s.mu.Lock()
+	s.active, s.loadedAt = cities, time.Now()
+	s.mu.Unlock()
+
+	return cities, nil
+}
+
+// invalidate сбрасывает кэш активных городов после изменений через этот экземпляр
+func (s *cityService) invalidate() {
+	s.mu.Lock()
+	s.active, s.loadedAt = nil, time.Time{}
+	s.mu.Unlock()
+}
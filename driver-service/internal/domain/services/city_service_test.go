@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCityService_AssignsDriversByLocation(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	cityRepo := memory.NewCityRepository(driverRepo)
+	cities := NewCityService(cityRepo, CityPolicy{CacheTTL: time.Minute}, zap.NewNop())
+	locations := NewLocationService(memory.NewLocationRepository(), driverRepo, nil, &recordingEventPublisher{}, nil, nil,
+		cities, LocationPolicy{}, zap.NewNop())
+
+	region, err := cities.CreateRegion(ctx, &entities.RegionRequest{Code: "ru-mow", Name: "Москва и область"})
+	require.NoError(t, err)
+	assert.Equal(t, "RU-MOW", region.Code)
+
+	// Границы созданного города учитываются сразу, несмотря на кэш
+	moscow, err := cities.CreateCity(ctx, &entities.CityRequest{
+		RegionID: region.ID,
+		Name:     "Москва",
+		Boundary: []entities.GeoPoint{
+			{Latitude: 55.5, Longitude: 37.3},
+			{Latitude: 55.5, Longitude: 37.9},
+			{Latitude: 56.0, Longitude: 37.9},
+			{Latitude: 56.0, Longitude: 37.3},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "москва", moscow.Key)
+
+	_, err = cities.CreateCity(ctx, &entities.CityRequest{RegionID: region.ID, Name: "Москва", Boundary: moscow.Boundary})
+	assert.ErrorIs(t, err, entities.ErrCityExists)
+
+	// Город из метаданных — до первой точки внутри границ
+	driver := entities.NewDriver("+79000000801", "city@example.com", "Иван", "Городской", "LIC801")
+	driver.Status = entities.StatusAvailable
+	driver.Metadata = entities.Metadata{entities.DriverMetaCity: "Тула"}
+	driver.RefreshShardKey()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	tula := "Тула"
+	found, err := driverRepo.List(ctx, &entities.DriverFilters{City: &tula})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+
+	inside := entities.NewDriverLocation(driver.ID, 55.75, 37.61, time.Now())
+	require.NoError(t, locations.UpdateLocation(ctx, inside))
+	assert.Equal(t, "москва", inside.Metadata[entities.LocationMetaCity])
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ResolvedCity)
+	assert.Equal(t, "москва", *stored.ResolvedCity)
+
+	city := "Москва"
+	found, err = driverRepo.List(ctx, &entities.DriverFilters{City: &city})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	found, err = driverRepo.List(ctx, &entities.DriverFilters{City: &tula})
+	require.NoError(t, err)
+	assert.Empty(t, found)
+
+	// Точка вне всех городов не меняет город водителя
+	outside := entities.NewDriverLocation(driver.ID, 54.19, 37.61, time.Now())
+	require.NoError(t, locations.UpdateLocation(ctx, outside))
+	assert.NotContains(t, outside.Metadata, entities.LocationMetaCity)
+	stored, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "москва", stored.OperatingCity())
+
+	counts, err := cities.ActiveDriverCounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, counts, 1)
+	assert.Equal(t, "москва", *counts[0].City)
+	require.NotNil(t, counts[0].Name)
+	assert.Equal(t, "Москва", *counts[0].Name)
+	assert.Equal(t, 1, counts[0].Available)
+	assert.Equal(t, 1, counts[0].Total)
+
+	// Удаление города возвращает водителя в город из метаданных
+	require.NoError(t, cities.DeleteCity(ctx, moscow.ID))
+	stored, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "тула", stored.OperatingCity())
+}
+
+func TestCityService_Validation(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	cities := NewCityService(memory.NewCityRepository(driverRepo), CityPolicy{}, zap.NewNop())
+
+	_, err := cities.CreateRegion(ctx, &entities.RegionRequest{Code: " ", Name: "Пусто"})
+	assert.ErrorIs(t, err, entities.ErrInvalidRegion)
+
+	region, err := cities.CreateRegion(ctx, &entities.RegionRequest{Code: "RU-SPE", Name: "Санкт-Петербург"})
+	require.NoError(t, err)
+	_, err = cities.CreateRegion(ctx, &entities.RegionRequest{Code: "ru-spe", Name: "Дубликат"})
+	assert.ErrorIs(t, err, entities.ErrRegionExists)
+
+	// Вершины на одной прямой не образуют границу
+	_, err = cities.CreateCity(ctx, &entities.CityRequest{
+		RegionID: region.ID,
+		Name:     "Линия",
+		Boundary: []entities.GeoPoint{{Latitude: 59, Longitude: 30}, {Latitude: 60, Longitude: 30}, {Latitude: 61, Longitude: 30}},
+	})
+	assert.ErrorIs(t, err, entities.ErrInvalidCity)
+
+	boundary := []entities.GeoPoint{{Latitude: 59.7, Longitude: 30.0}, {Latitude: 59.7, Longitude: 30.6}, {Latitude: 60.1, Longitude: 30.3}}
+	city, err := cities.CreateCity(ctx, &entities.CityRequest{RegionID: region.ID, Key: "spb", Name: "Санкт-Петербург", Boundary: boundary})
+	require.NoError(t, err)
+	assert.True(t, city.Active)
+
+	// Ключ не меняется при замене города
+	inactive := false
+	updated, err := cities.UpdateCity(ctx, city.ID, &entities.CityRequest{
+		RegionID: region.ID, Key: "other", Name: "Петербург", Boundary: boundary, Active: &inactive,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "spb", updated.Key)
+	assert.False(t, updated.Active)
+
+	_, err = cities.CreateCity(ctx, &entities.CityRequest{RegionID: city.ID, Name: "Без региона", Boundary: boundary})
+	assert.ErrorIs(t, err, entities.ErrRegionNotFound)
+}
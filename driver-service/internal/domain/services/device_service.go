@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeviceService интерфейс для устройств водителей
+type DeviceService interface {
+	// Register регистрирует устройство водителя или обновляет данные приложения на нем.
+	// Повторная регистрация отозванного устройства снимает отзыв: водитель уже вошел заново
+	Register(ctx context.Context, driverID uuid.UUID, registration *entities.DeviceRegistration) (*entities.DriverDevice, error)
+	// List возвращает устройства водителя, последние активные первыми
+	List(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDevice, error)
+	// Revoke отзывает устройство: удаляет токен push-уведомлений и отзывает сессии
+	// водителя с этого устройства
+	Revoke(ctx context.Context, driverID uuid.UUID, deviceID string) (*entities.DriverDevice, error)
+	// ResolveTracker находит водителя, за которым закреплен GPS-трекер: последнее активное
+	// неотозванное устройство с платформой tracker и этим ID
+	ResolveTracker(ctx context.Context, trackerID string) (*entities.DriverDevice, error)
+}
+
+// deviceService реализация DeviceService
+type deviceService struct {
+	deviceRepo      repositories.DeviceRepository
+	driverRepo      repositories.DriverRepository
+	securityService SecurityService
+	logger          *zap.Logger
+}
+
+// NewDeviceService создает новый DeviceService
+func NewDeviceService(
+	deviceRepo repositories.DeviceRepository,
+	driverRepo repositories.DriverRepository,
+	securityService SecurityService,
+	logger *zap.Logger,
+) DeviceService {
+	return &deviceService{
+		deviceRepo:      deviceRepo,
+		driverRepo:      driverRepo,
+		securityService: securityService,
+		logger:          logger,
+	}
+}
+
+// Register регистрирует устройство водителя
+func (s *deviceService) Register(ctx context.Context, driverID uuid.UUID, registration *entities.DeviceRegistration) (*entities.DriverDevice, error) {
+	if err := registration.Validate(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	device := entities.NewDriverDevice(driverID, registration, time.Now())
+	if err := s.deviceRepo.Register(ctx, device); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver device registered",
+		zap.String("driver_id", driverID.String()),
+		zap.String("device_id", device.DeviceID),
+		zap.String("platform", string(device.Platform)),
+		zap.String("app_version", device.AppVersion),
+	)
+	return device, nil
+}
+
+// List получает устройства водителя
+func (s *deviceService) List(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDevice, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return s.deviceRepo.ListByDriverID(ctx, driverID)
+}
+
+// Revoke отзывает устройство водителя. Сессии отзываются и для уже отозванного
+// устройства: повторный запрос завершает отзыв, прерванный сбоем
+func (s *deviceService) Revoke(ctx context.Context, driverID uuid.UUID, deviceID string) (*entities.DriverDevice, error) {
+	device, err := s.deviceRepo.Get(ctx, driverID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !device.IsRevoked() {
+		device.Revoke(time.Now())
+		if err := s.deviceRepo.Revoke(ctx, device); err != nil {
+			return nil, err
+		}
+	}
+
+	sessions, err := s.securityService.RevokeDeviceSessions(ctx, driverID, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver device revoked",
+		zap.String("driver_id", driverID.String()),
+		zap.String("device_id", deviceID),
+		zap.Int("sessions", sessions),
+	)
+	return device, nil
+}
+
+// ResolveTracker получает трекер и водителя, за которым он закреплен
+func (s *deviceService) ResolveTracker(ctx context.Context, trackerID string) (*entities.DriverDevice, error) {
+	device, err := s.deviceRepo.GetActiveByDeviceID(ctx, trackerID)
+	if err != nil {
+		return nil, err
+	}
+	// ID приложения на телефоне не должен подменять трекер
+	if !device.IsTracker() {
+		return nil, entities.ErrDeviceNotFound
+	}
+
+	return device, nil
+}
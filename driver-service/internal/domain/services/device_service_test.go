@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeviceService_RegisterListRevoke(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	deviceRepo := memory.NewDeviceRepository()
+	securityService, securityRepo, _, _ := newTestSecurityService(SecurityPolicy{})
+	service := NewDeviceService(deviceRepo, driverRepo, securityService, zap.NewNop())
+
+	driver := newTestDriver("701")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	token := " push-1 "
+	device, err := service.Register(ctx, driver.ID, &entities.DeviceRegistration{
+		DeviceID: "phone-1", Platform: entities.DevicePlatformAndroid, AppVersion: "3.2.0", PushToken: &token,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "push-1", *device.PushToken)
+
+	// Повторная регистрация обновляет приложение и сохраняет ID устройства
+	updated, err := service.Register(ctx, driver.ID, &entities.DeviceRegistration{
+		DeviceID: "phone-1", Platform: entities.DevicePlatformAndroid, AppVersion: "3.3.0", PushToken: &token,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, device.ID, updated.ID)
+
+	_, err = service.Register(ctx, driver.ID, &entities.DeviceRegistration{
+		DeviceID: "tablet-1", Platform: "symbian", AppVersion: "1.0",
+	})
+	assert.ErrorIs(t, err, entities.ErrInvalidDevice)
+
+	_, err = service.Register(ctx, uuid.New(), &entities.DeviceRegistration{
+		DeviceID: "phone-2", Platform: entities.DevicePlatformIOS, AppVersion: "1.0",
+	})
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+
+	devices, err := service.List(ctx, driver.ID)
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "3.3.0", devices[0].AppVersion)
+
+	// Отзыв устройства завершает только его сессии
+	require.NoError(t, securityService.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driver.ID, SessionID: "s1", DeviceID: "phone-1", At: device.RegisteredAt,
+	}))
+	require.NoError(t, securityService.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driver.ID, SessionID: "s2", DeviceID: "phone-3", At: device.RegisteredAt,
+	}))
+
+	revoked, err := service.Revoke(ctx, driver.ID, "phone-1")
+	require.NoError(t, err)
+	assert.True(t, revoked.IsRevoked())
+	assert.False(t, revoked.HasPushToken())
+
+	s1, err := securityRepo.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.True(t, s1.IsRevoked())
+	s2, err := securityRepo.GetSession(ctx, "s2")
+	require.NoError(t, err)
+	assert.False(t, s2.IsRevoked())
+
+	_, err = service.Revoke(ctx, driver.ID, "phone-9")
+	assert.ErrorIs(t, err, entities.ErrDeviceNotFound)
+
+	// Повторная регистрация после входа снимает отзыв
+	restored, err := service.Register(ctx, driver.ID, &entities.DeviceRegistration{
+		DeviceID: "phone-1", Platform: entities.DevicePlatformAndroid, AppVersion: "3.3.0",
+	})
+	require.NoError(t, err)
+	assert.False(t, restored.IsRevoked())
+	assert.Equal(t, device.ID, restored.ID)
+}
+
+func TestDeviceService_ResolveTracker(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	securityService, _, _, _ := newTestSecurityService(SecurityPolicy{})
+	service := NewDeviceService(memory.NewDeviceRepository(), driverRepo, securityService, zap.NewNop())
+
+	first, second := newTestDriver("702"), newTestDriver("703")
+	first.ID, second.ID = uuid.New(), uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, first))
+	require.NoError(t, driverRepo.Create(ctx, second))
+
+	tracker := &entities.DeviceRegistration{DeviceID: "imei-860000000000001", Platform: entities.DevicePlatformTracker, AppVersion: "fw-2.1"}
+	_, err := service.Register(ctx, first.ID, tracker)
+	require.NoError(t, err)
+	_, err = service.Register(ctx, first.ID, &entities.DeviceRegistration{DeviceID: "phone-1", Platform: entities.DevicePlatformAndroid, AppVersion: "3.2.0"})
+	require.NoError(t, err)
+
+	device, err := service.ResolveTracker(ctx, "imei-860000000000001")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, device.DriverID)
+
+	// Трекер переходит к водителю, который зарегистрировал его последним
+	time.Sleep(time.Millisecond)
+	_, err = service.Register(ctx, second.ID, tracker)
+	require.NoError(t, err)
+	device, err = service.ResolveTracker(ctx, "imei-860000000000001")
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, device.DriverID)
+
+	_, err = service.Revoke(ctx, second.ID, "imei-860000000000001")
+	require.NoError(t, err)
+	device, err = service.ResolveTracker(ctx, "imei-860000000000001")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, device.DriverID)
+
+	_, err = service.ResolveTracker(ctx, "phone-1")
+	assert.ErrorIs(t, err, entities.ErrDeviceNotFound)
+	_, err = service.ResolveTracker(ctx, "imei-unknown")
+	assert.ErrorIs(t, err, entities.ErrDeviceNotFound)
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DispatchPolicy настройки оценки кандидатов для назначения заказа
+type DispatchPolicy struct {
+	Weights entities.DispatchWeights
+	// DefaultRadiusKm радиус поиска, если он не указан в запросе
+	DefaultRadiusKm float64
+	// MaxCandidates сколько ближайших водителей оценивается; кандидаты отбираются из них
+	MaxCandidates int
+	// MaxIdle время простоя, после которого составляющая простоя максимальна
+	MaxIdle time.Duration
+	// StatsWindow за какой период учитываются ответы на предложения
+	StatsWindow time.Duration
+	// MinOffers минимум ответов за окно, после которого учитывается доля принятых
+	MinOffers int
+	// DefaultRating рейтинг водителя, у которого еще нет оценок
+	DefaultRating float64
+}
+
+// DispatchScoringService интерфейс для подбора водителя на заказ
+type DispatchScoringService interface {
+	// GetCandidates возвращает свободных водителей поблизости от лучшего к худшему
+	GetCandidates(ctx context.Context, query *entities.DispatchCandidateQuery) ([]*entities.DispatchCandidate, error)
+	// RecordOffer учитывает ответ водителя на предложение заказа
+	RecordOffer(ctx context.Context, req *entities.DispatchOfferRequest) error
+	// RecordCancellation учитывает отмену водителем принятого заказа
+	RecordCancellation(ctx context.Context, req *entities.DispatchCancellationRequest) error
+	// SetWeights заменяет веса составляющих оценки без перезапуска
+	SetWeights(weights entities.DispatchWeights)
+}
+
+// dispatchScoringService реализация DispatchScoringService
+type dispatchScoringService struct {
+	locationService LocationService
+	driverRepo      repositories.DriverRepository
+	shiftRepo       repositories.ShiftRepository
+	dispatchRepo    repositories.DispatchRepository
+	policy          DispatchPolicy
+	logger          *zap.Logger
+
+	weightsMu sync.RWMutex
+	weights   entities.DispatchWeights
+}
+
+// NewDispatchScoringService создает новый DispatchScoringService
+func NewDispatchScoringService(
+	locationService LocationService,
+	driverRepo repositories.DriverRepository,
+	shiftRepo repositories.ShiftRepository,
+	dispatchRepo repositories.DispatchRepository,
+	policy DispatchPolicy,
+	logger *zap.Logger,
+) DispatchScoringService {
+	return &dispatchScoringService{
+		locationService: locationService,
+		driverRepo:      driverRepo,
+		shiftRepo:       shiftRepo,
+		dispatchRepo:    dispatchRepo,
+		policy:          policy,
+		logger:          logger,
+		weights:         policy.Weights,
+	}
+}
+
+// GetCandidates оценивает ближайших водителей, которые могут принять заказ, по расстоянию,
+// рейтингу, доле принятых предложений и времени простоя
+func (s *dispatchScoringService) GetCandidates(ctx context.Context, query *entities.DispatchCandidateQuery) ([]*entities.DispatchCandidate, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+	radiusKm := query.RadiusKm
+	if radiusKm == 0 {
+		radiusKm = s.policy.DefaultRadiusKm
+	}
+
+	locations, err := s.locationService.GetNearbyDrivers(ctx, query.Latitude, query.Longitude, radiusKm, s.policy.MaxCandidates, query.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	center := &entities.DriverLocation{Latitude: query.Latitude, Longitude: query.Longitude}
+	candidates := make([]*entities.DispatchCandidate, 0, len(locations))
+	idleSince := make(map[uuid.UUID]time.Time, len(locations))
+	for _, location := range locations {
+		driver, err := s.driverRepo.GetByID(ctx, location.DriverID)
+		if err != nil || !driver.CanReceiveOrders() {
+			continue
+		}
+
+		rating := driver.CurrentRating
+		if rating == entities.NoRating {
+			rating = s.policy.DefaultRating
+		}
+		candidates = append(candidates, &entities.DispatchCandidate{
+			DriverID:   driver.ID,
+			Latitude:   location.Latitude,
+			Longitude:  location.Longitude,
+			DistanceKm: center.DistanceTo(location),
+			Rating:     rating,
+			UpdatedAt:  location.RecordedAt,
+		})
+
+		if shift, err := s.shiftRepo.GetActiveByDriverID(ctx, driver.ID); err == nil {
+			idleSince[driver.ID] = shift.StartTime
+		}
+	}
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	driverIDs := make([]uuid.UUID, len(candidates))
+	for i, candidate := range candidates {
+		driverIDs[i] = candidate.DriverID
+	}
+	stats, err := s.dispatchRepo.GetStats(ctx, driverIDs, now.Add(-s.policy.StatsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	scoring := entities.DispatchScoring{
+		Weights:  s.currentWeights(),
+		RadiusKm: radiusKm,
+		MaxIdle:  s.policy.MaxIdle,
+	}
+	for _, candidate := range candidates {
+		driverStats := stats[candidate.DriverID]
+		candidate.AcceptanceRate = driverStats.AcceptanceRate(s.policy.MinOffers)
+		candidate.CancellationRate = driverStats.CancellationRate(s.policy.MinOffers)
+
+		// Водитель ждет заказа с последнего принятого предложения, но не дольше текущей смены
+		since, known := idleSince[candidate.DriverID]
+		if driverStats != nil && driverStats.LastAcceptedAt != nil && (!known || driverStats.LastAcceptedAt.After(since)) {
+			since, known = *driverStats.LastAcceptedAt, true
+		}
+		if known && now.After(since) {
+			candidate.IdleSeconds = int64(now.Sub(since).Seconds())
+		}
+
+		candidate.Score = scoring.Score(candidate)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].DistanceKm < candidates[j].DistanceKm
+	})
+	if query.Limit > 0 && len(candidates) > query.Limit {
+		candidates = candidates[:query.Limit]
+	}
+
+	return candidates, nil
+}
+
+// RecordOffer сохраняет ответ водителя на предложение заказа
+func (s *dispatchScoringService) RecordOffer(ctx context.Context, req *entities.DispatchOfferRequest) error {
+	offer := &entities.DispatchOffer{
+		DriverID:    req.DriverID,
+		OrderID:     req.OrderID,
+		Accepted:    req.Accepted != nil && *req.Accepted,
+		RespondedAt: time.Now(),
+	}
+	if req.RespondedAt != nil {
+		offer.RespondedAt = *req.RespondedAt
+	}
+	if err := offer.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, offer.DriverID); err != nil {
+		return err
+	}
+
+	if err := s.dispatchRepo.RecordOffer(ctx, offer); err != nil {
+		return err
+	}
+
+	s.logger.Debug("Dispatch offer recorded",
+		zap.String("driver_id", offer.DriverID.String()),
+		zap.String("order_id", offer.OrderID.String()),
+		zap.Bool("accepted", offer.Accepted),
+	)
+	s.refreshDriverRates(ctx, offer.DriverID)
+	return nil
+}
+
+// RecordCancellation сохраняет отмену принятого заказа водителем
+func (s *dispatchScoringService) RecordCancellation(ctx context.Context, req *entities.DispatchCancellationRequest) error {
+	if req.DriverID == uuid.Nil || req.OrderID == uuid.Nil {
+		return entities.ErrInvalidDispatchOffer
+	}
+	cancelledAt := time.Now()
+	if req.CancelledAt != nil {
+		if req.CancelledAt.IsZero() {
+			return entities.ErrInvalidTimestamp
+		}
+		cancelledAt = *req.CancelledAt
+	}
+
+	if err := s.dispatchRepo.RecordCancellation(ctx, req.DriverID, req.OrderID, cancelledAt); err != nil {
+		return err
+	}
+
+	s.logger.Debug("Dispatch cancellation recorded",
+		zap.String("driver_id", req.DriverID.String()),
+		zap.String("order_id", req.OrderID.String()),
+	)
+	s.refreshDriverRates(ctx, req.DriverID)
+	return nil
+}
+
+// refreshDriverRates пересчитывает доли принятых и отмененных предложений водителя за окно
+// StatsWindow и сохраняет их в профиле. Ответ уже учтен, поэтому ошибка только логируется:
+// доли обновятся при следующем ответе
+func (s *dispatchScoringService) refreshDriverRates(ctx context.Context, driverID uuid.UUID) {
+	stats, err := s.dispatchRepo.GetStats(ctx, []uuid.UUID{driverID}, time.Now().Add(-s.policy.StatsWindow))
+	if err != nil {
+		s.logger.Error("Failed to get driver dispatch stats",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return
+	}
+
+	var acceptanceRate, cancellationRate *float64
+	if driverStats := stats[driverID]; driverStats != nil && driverStats.OffersReceived >= s.policy.MinOffers {
+		acceptance := driverStats.AcceptanceRate(s.policy.MinOffers)
+		cancellation := driverStats.CancellationRate(s.policy.MinOffers)
+		acceptanceRate, cancellationRate = &acceptance, &cancellation
+	}
+
+	if err := s.driverRepo.UpdateDispatchRates(ctx, driverID, acceptanceRate, cancellationRate); err != nil {
+		s.logger.Error("Failed to update driver dispatch rates",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+}
+
+// SetWeights заменяет веса составляющих оценки
+func (s *dispatchScoringService) SetWeights(weights entities.DispatchWeights) {
+	s.weightsMu.Lock()
+	defer s.weightsMu.Unlock()
+	s.weights = weights
+}
+
+// currentWeights возвращает действующие веса
+func (s *dispatchScoringService) currentWeights() entities.DispatchWeights {
+	s.weightsMu.RLock()
+	defer s.weightsMu.RUnlock()
+	return s.weights
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDispatchScoringService_GetCandidates(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	shiftRepo := memory.NewShiftRepository()
+	locations := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+	service := NewDispatchScoringService(locations, driverRepo, shiftRepo, memory.NewDispatchRepository(), DispatchPolicy{
+		Weights:         entities.DispatchWeights{Distance: 0.4, Rating: 0.2, Acceptance: 0.2, Idle: 0.2},
+		DefaultRadiusKm: 5,
+		MaxCandidates:   50,
+		MaxIdle:         30 * time.Minute,
+		StatsWindow:     7 * 24 * time.Hour,
+		MinOffers:       10,
+		DefaultRating:   4.5,
+	}, zap.NewNop())
+
+	now := time.Now()
+	newDriver := func(suffix string, status entities.Status, rating float64, lat float64) *entities.Driver {
+		driver := entities.NewDriver("+7900000020"+suffix, "dispatch"+suffix+"@example.com", "Иван", "Кандидат", "LICD"+suffix)
+		driver.Status = status
+		driver.CurrentRating = rating
+		require.NoError(t, driverRepo.Create(ctx, driver))
+		require.NoError(t, locations.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, lat, 37.61, now)))
+		return driver
+	}
+
+	// Ближайший водитель часто отказывается и только что принял заказ
+	nearest := newDriver("1", entities.StatusAvailable, 4.0, 55.751)
+	respondedAt := now.Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		accepted := i < 2
+		require.NoError(t, service.RecordOffer(ctx, &entities.DispatchOfferRequest{
+			DriverID:    nearest.ID,
+			OrderID:     uuid.New(),
+			Accepted:    &accepted,
+			RespondedAt: &respondedAt,
+		}))
+	}
+
+	// Водитель дальше, без отказов и ждет заказа с начала смены
+	waiting := newDriver("2", entities.StatusAvailable, 5.0, 55.76)
+	require.NoError(t, shiftRepo.Create(ctx, &entities.DriverShift{
+		ID:        uuid.New(),
+		DriverID:  waiting.ID,
+		StartTime: now.Add(-45 * time.Minute),
+		Status:    entities.ShiftStatusActive,
+	}))
+
+	newcomer := newDriver("3", entities.StatusAvailable, 0, 55.77)
+	newDriver("4", entities.StatusBusy, 5.0, 55.75)
+
+	candidates, err := service.GetCandidates(ctx, &entities.DispatchCandidateQuery{Latitude: 55.75, Longitude: 37.61})
+	require.NoError(t, err)
+	require.Len(t, candidates, 3, "busy drivers are not candidates")
+	assert.Equal(t, waiting.ID, candidates[0].DriverID)
+	assert.Equal(t, nearest.ID, candidates[2].DriverID)
+	assert.InDelta(t, 0.2, candidates[2].AcceptanceRate, 0.001)
+	assert.GreaterOrEqual(t, candidates[0].IdleSeconds, int64(45*60))
+	assert.Greater(t, candidates[0].Score, candidates[1].Score)
+
+	assert.Equal(t, newcomer.ID, candidates[1].DriverID)
+	assert.Equal(t, 4.5, candidates[1].Rating, "drivers without ratings get the default rating")
+	assert.Equal(t, 1.0, candidates[1].AcceptanceRate, "too few offers do not penalize")
+
+	top, err := service.GetCandidates(ctx, &entities.DispatchCandidateQuery{Latitude: 55.75, Longitude: 37.61, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, waiting.ID, top[0].DriverID)
+
+	// Только расстояние: ближайший водитель первый
+	service.SetWeights(entities.DispatchWeights{Distance: 1})
+	candidates, err = service.GetCandidates(ctx, &entities.DispatchCandidateQuery{Latitude: 55.75, Longitude: 37.61})
+	require.NoError(t, err)
+	require.NotEmpty(t, candidates)
+	assert.Equal(t, nearest.ID, candidates[0].DriverID)
+
+	_, err = service.GetCandidates(ctx, &entities.DispatchCandidateQuery{Latitude: 95, Longitude: 37.61})
+	assert.Equal(t, entities.ErrInvalidLocation, err)
+}
+
+func TestDispatchScoringService_RecordOffer(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	dispatchRepo := memory.NewDispatchRepository()
+	service := NewDispatchScoringService(nil, driverRepo, nil, dispatchRepo, DispatchPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000301", "offer@example.com", "Иван", "Ответ", "LICO")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	accepted := true
+	offer := &entities.DispatchOfferRequest{DriverID: driver.ID, OrderID: uuid.New(), Accepted: &accepted}
+	require.NoError(t, service.RecordOffer(ctx, offer))
+	require.NoError(t, service.RecordOffer(ctx, offer), "redelivery is ignored")
+
+	stats, err := dispatchRepo.GetStats(ctx, []uuid.UUID{driver.ID}, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	require.Contains(t, stats, driver.ID)
+	assert.Equal(t, 1, stats[driver.ID].OffersReceived)
+	assert.Equal(t, 1, stats[driver.ID].OffersAccepted)
+	assert.NotNil(t, stats[driver.ID].LastAcceptedAt)
+
+	err = service.RecordOffer(ctx, &entities.DispatchOfferRequest{DriverID: uuid.New(), OrderID: uuid.New(), Accepted: &accepted})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
+
+func TestDispatchScoringService_RecordCancellation(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	dispatchRepo := memory.NewDispatchRepository()
+	service := NewDispatchScoringService(nil, driverRepo, nil, dispatchRepo, DispatchPolicy{
+		StatsWindow: 7 * 24 * time.Hour,
+		MinOffers:   3,
+	}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000302", "cancel@example.com", "Иван", "Отмена", "LICC")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	orders := make([]uuid.UUID, 4)
+	for i := range orders {
+		orders[i] = uuid.New()
+		accepted := i < 3
+		require.NoError(t, service.RecordOffer(ctx, &entities.DispatchOfferRequest{DriverID: driver.ID, OrderID: orders[i], Accepted: &accepted}))
+	}
+
+	cancellation := &entities.DispatchCancellationRequest{DriverID: driver.ID, OrderID: orders[0]}
+	require.NoError(t, service.RecordCancellation(ctx, cancellation))
+	require.NoError(t, service.RecordCancellation(ctx, cancellation), "redelivery is ignored")
+
+	// Отменить можно только принятый заказ
+	err := service.RecordCancellation(ctx, &entities.DispatchCancellationRequest{DriverID: driver.ID, OrderID: orders[3]})
+	assert.Equal(t, entities.ErrDispatchOfferNotAccepted, err)
+	err = service.RecordCancellation(ctx, &entities.DispatchCancellationRequest{DriverID: driver.ID, OrderID: uuid.New()})
+	assert.Equal(t, entities.ErrDispatchOfferNotAccepted, err)
+
+	// Доли за окно сохраняются в профиле водителя
+	updated, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	require.NotNil(t, updated.AcceptanceRate)
+	require.NotNil(t, updated.CancellationRate)
+	assert.InDelta(t, 0.75, *updated.AcceptanceRate, 0.001)
+	assert.InDelta(t, 1.0/3, *updated.CancellationRate, 0.001)
+
+	// Пока ответов меньше MinOffers, доли не показываются
+	newcomer := entities.NewDriver("+79000000303", "newcomer@example.com", "Петр", "Новичок", "LICN")
+	require.NoError(t, driverRepo.Create(ctx, newcomer))
+	accepted := true
+	require.NoError(t, service.RecordOffer(ctx, &entities.DispatchOfferRequest{DriverID: newcomer.ID, OrderID: uuid.New(), Accepted: &accepted}))
+	fresh, err := driverRepo.GetByID(ctx, newcomer.ID)
+	require.NoError(t, err)
+	assert.Nil(t, fresh.AcceptanceRate)
+	assert.Nil(t, fresh.CancellationRate)
+}
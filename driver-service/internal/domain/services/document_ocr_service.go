@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// ocrFailureReason причина, которую видит верификатор, если распознавание не удалось.
+// Ошибка сервиса распознавания только логируется
+const ocrFailureReason = "document recognition is unavailable"
+
+// DocumentOCRProvider сервис распознавания полей документа по файлу
+type DocumentOCRProvider interface {
+	// Name имя сервиса для результата распознавания и логов
+	Name() string
+	// Extract распознает номер, срок действия и ФИО владельца документа
+	Extract(ctx context.Context, req *entities.OCRRequest) (*entities.OCRExtraction, error)
+}
+
+// DocumentOCRPolicy параметры распознавания документов
+type DocumentOCRPolicy struct {
+	// Workers число параллельных распознаваний
+	Workers int
+	// QueueSize сколько документов может ждать распознавания; документы сверх очереди
+	// проверяются верификатором без распознавания
+	QueueSize int
+	// Timeout ограничение времени распознавания одного документа
+	Timeout time.Duration
+}
+
+// DocumentOCRService асинхронное распознавание загруженных документов
+type DocumentOCRService interface {
+	// Submit ставит файл документа в очередь распознавания, не дожидаясь результата.
+	// false, если очередь заполнена или распознавание остановлено
+	Submit(document *entities.DriverDocument, contentType string, content []byte) bool
+	// Drain перестает принимать документы и дожидается распознавания очереди. Если ctx
+	// истек раньше, возвращает число документов, оставшихся без распознавания
+	Drain(ctx context.Context) (int, error)
+}
+
+// ocrJob документ в очереди распознавания
+type ocrJob struct {
+	document *entities.DriverDocument
+	request  *entities.OCRRequest
+}
+
+// documentOCRService реализация DocumentOCRService
+type documentOCRService struct {
+	provider     DocumentOCRProvider
+	documentRepo repositories.DocumentRepository
+	driverRepo   repositories.DriverRepository
+	policy       DocumentOCRPolicy
+	logger       *zap.Logger
+
+	// mu защищает stopped: после остановки документы в очередь не принимаются
+	mu      sync.RWMutex
+	stopped bool
+	jobs    chan ocrJob
+	wg      sync.WaitGroup
+}
+
+// NewDocumentOCRService создает DocumentOCRService и запускает обработчики очереди.
+// Результат распознавания сохраняется в документе через documentRepo
+func NewDocumentOCRService(
+	provider DocumentOCRProvider,
+	documentRepo repositories.DocumentRepository,
+	driverRepo repositories.DriverRepository,
+	policy DocumentOCRPolicy,
+	logger *zap.Logger,
+) DocumentOCRService {
+	if policy.Workers <= 0 {
+		policy.Workers = 1
+	}
+	if policy.QueueSize < 0 {
+		policy.QueueSize = 0
+	}
+
+	s := &documentOCRService{
+		provider:     provider,
+		documentRepo: documentRepo,
+		driverRepo:   driverRepo,
+		policy:       policy,
+		logger:       logger,
+		jobs:         make(chan ocrJob, policy.QueueSize),
+	}
+	for i := 0; i < policy.Workers; i++ {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return s
+}
+
+// Submit ставит документ в очередь распознавания
+func (s *documentOCRService) Submit(document *entities.DriverDocument, contentType string, content []byte) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return false
+	}
+
+	job := ocrJob{
+		document: document,
+		request: &entities.OCRRequest{
+			DocumentID:   document.ID,
+			DocumentType: document.DocumentType,
+			ContentType:  contentType,
+			Content:      content,
+		},
+	}
+
+	select {
+	case s.jobs <- job:
+		return true
+	default:
+		s.logger.Warn("Document OCR queue is full, document is left for manual review",
+			zap.String("document_id", document.ID.String()),
+		)
+		return false
+	}
+}
+
+// Drain дожидается распознавания документов из очереди
+func (s *documentOCRService) Drain(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.jobs)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		return len(s.jobs), ctx.Err()
+	}
+}
+
+// run распознает документы из очереди до ее закрытия
+func (s *documentOCRService) run() {
+	defer s.wg.Done()
+
+	for job := range s.jobs {
+		s.process(job)
+	}
+}
+
+// process распознает документ, сверяет поля с данными водителя и сохраняет результат.
+// Неудачное распознавание тоже сохраняется, чтобы верификатор знал, что подсказок не будет
+func (s *documentOCRService) process(job ocrJob) {
+	ctx := context.Background()
+	if s.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.policy.Timeout)
+		defer cancel()
+	}
+
+	document := job.document
+	var result *entities.DocumentOCRResult
+
+	extracted, err := s.provider.Extract(ctx, job.request)
+	if err != nil {
+		s.logger.Error("Failed to recognize document",
+			zap.Error(err),
+			zap.String("provider", s.provider.Name()),
+			zap.String("document_id", document.ID.String()),
+		)
+		result = entities.NewFailedOCRResult(s.provider.Name(), ocrFailureReason, time.Now())
+	} else {
+		owner, err := s.driverRepo.GetByID(ctx, document.DriverID)
+		if err != nil {
+			// ФИО не сверяется, номер и срок документа сверяются без профиля
+			s.logger.Warn("Failed to get document owner for OCR comparison",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			owner = nil
+		}
+		result = entities.NewDocumentOCRResult(s.provider.Name(), document, owner, extracted, time.Now())
+	}
+
+	if err := s.documentRepo.SetOCRResult(context.Background(), document.ID, result); err != nil {
+		s.logger.Error("Failed to save document OCR result",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+		return
+	}
+
+	s.logger.Info("Document recognized",
+		zap.String("document_id", document.ID.String()),
+		zap.String("status", string(result.Status)),
+		zap.Float64("confidence", result.Confidence),
+		zap.Int("mismatches", len(result.Mismatches)),
+	)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeOCRProvider возвращает заданный результат и запоминает распознанные файлы
+type fakeOCRProvider struct {
+	mu         sync.Mutex
+	extraction *entities.OCRExtraction
+	err        error
+	contents   []string
+}
+
+func (p *fakeOCRProvider) Name() string {
+	return "fake"
+}
+
+func (p *fakeOCRProvider) Extract(ctx context.Context, req *entities.OCRRequest) (*entities.OCRExtraction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.contents = append(p.contents, string(req.Content))
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.extraction, nil
+}
+
+func TestDocumentOCRService_RecognizesRenewal(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+	provider := &fakeOCRProvider{extraction: &entities.OCRExtraction{
+		DocumentNumber: "LIC-OLD",
+		FullName:       f.driver.GetFullName(),
+		Confidence:     0.8,
+	}}
+	ocr := NewDocumentOCRService(provider, f.documentRepo, f.driverRepo,
+		DocumentOCRPolicy{Workers: 1, QueueSize: 4, Timeout: time.Second}, zap.NewNop())
+	f.renewals = NewDocumentRenewalService(f.documentRepo, f.driverRepo,
+		&fakeFileStorage{files: make(map[string][]byte)}, ocr, f.notifier, f.events,
+		DocumentRenewalPolicy{WindowDays: 30, MaxFileSize: 1 << 20}, zap.NewNop())
+
+	renewal, err := f.renew()
+	require.NoError(t, err)
+
+	dropped, err := ocr.Drain(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	assert.Equal(t, []string{"%PDF"}, provider.contents, "the stored file is recognized")
+
+	stored, err := f.documentRepo.GetByID(ctx, renewal.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.OCR)
+	assert.Equal(t, entities.OCRStatusCompleted, stored.OCR.Status)
+	assert.Equal(t, 0.8, stored.OCR.Confidence)
+	require.Len(t, stored.OCR.Mismatches, 1)
+	assert.Equal(t, entities.OCRFieldDocumentNumber, stored.OCR.Mismatches[0].Field)
+
+	// Документы после остановки не принимаются
+	assert.False(t, ocr.Submit(renewal, "application/pdf", []byte("%PDF")))
+}
+
+func TestDocumentOCRService_ProviderFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+	provider := &fakeOCRProvider{err: errors.New("connection refused")}
+	ocr := NewDocumentOCRService(provider, f.documentRepo, f.driverRepo,
+		DocumentOCRPolicy{Workers: 1, QueueSize: 1}, zap.NewNop())
+
+	require.True(t, ocr.Submit(f.license, "image/png", bytes.Repeat([]byte{1}, 8)))
+	_, err := ocr.Drain(ctx)
+	require.NoError(t, err)
+
+	stored, err := f.documentRepo.GetByID(ctx, f.license.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.OCR)
+	assert.Equal(t, entities.OCRStatusFailed, stored.OCR.Status)
+	assert.NotContains(t, stored.OCR.Error, "connection refused", "provider errors are not shown to verifiers")
+
+	// Обновление документа не стирает результат распознавания
+	require.NoError(t, f.documentRepo.Update(ctx, f.license))
+	stored, err = f.documentRepo.GetByID(ctx, f.license.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.OCR)
+}
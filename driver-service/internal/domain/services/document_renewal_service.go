@@ -0,0 +1,531 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// documentExtensions допустимые типы файлов документов и их расширения
+var documentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/heic":      ".heic",
+	"application/pdf": ".pdf",
+}
+
+// DocumentRenewalPolicy настройки продления документов
+type DocumentRenewalPolicy struct {
+	// WindowDays за сколько дней до истечения документ можно продлить; в начале окна
+	// водителю отправляется напоминание
+	WindowDays  int
+	MaxFileSize int64
+	// ResubmissionReminderDays через сколько дней после отказа водителю напоминают загрузить
+	// отклоненный документ повторно
+	ResubmissionReminderDays int
+}
+
+// DocumentRenewalService интерфейс самостоятельного продления документов водителем
+type DocumentRenewalService interface {
+	GetDriverDocuments(ctx context.Context, driverID uuid.UUID) (*entities.DriverDocumentsResponse, error)
+	RenewDocument(ctx context.Context, driverID, documentID uuid.UUID, req *entities.DocumentRenewalRequest, upload *FileUpload) (*entities.DriverDocument, error)
+	// ResolveRenewal завершает продление после решения верификатора по новой версии
+	ResolveRenewal(ctx context.Context, renewal *entities.DriverDocument) error
+	SendExpiryReminders(ctx context.Context) (int, error)
+	// ResubmitDocument загружает новую версию отклоненного документа; отклоненная версия
+	// сохраняется в истории
+	ResubmitDocument(ctx context.Context, driverID, documentID uuid.UUID, req *entities.DocumentRenewalRequest, upload *FileUpload) (*entities.DriverDocument, error)
+	GetDocumentVersions(ctx context.Context, driverID, documentID uuid.UUID) (*entities.DocumentVersionHistory, error)
+	// GetDocumentHistory возвращает текущую версию документа типа docType и прежние версии
+	GetDocumentHistory(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DocumentHistory, error)
+	SendResubmissionReminders(ctx context.Context) (int, error)
+}
+
+// documentRenewalService реализация DocumentRenewalService
+type documentRenewalService struct {
+	documentRepo repositories.DocumentRepository
+	driverRepo   repositories.DriverRepository
+	storage      FileStorage
+	ocr          DocumentOCRService
+	notifier     NotificationSender
+	eventBus     EventPublisher
+	policy       DocumentRenewalPolicy
+	logger       *zap.Logger
+}
+
+// NewDocumentRenewalService создает новый DocumentRenewalService. ocr распознает
+// загруженные файлы для верификатора и может быть nil
+func NewDocumentRenewalService(
+	documentRepo repositories.DocumentRepository,
+	driverRepo repositories.DriverRepository,
+	storage FileStorage,
+	ocr DocumentOCRService,
+	notifier NotificationSender,
+	eventBus EventPublisher,
+	policy DocumentRenewalPolicy,
+	logger *zap.Logger,
+) DocumentRenewalService {
+	return &documentRenewalService{
+		documentRepo: documentRepo,
+		driverRepo:   driverRepo,
+		storage:      storage,
+		ocr:          ocr,
+		notifier:     notifier,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// GetDriverDocuments возвращает действующие документы водителя с состоянием продления.
+// Замененные версии не возвращаются, а продление показывается рядом с продлеваемым документом
+func (s *documentRenewalService) GetDriverDocuments(ctx context.Context, driverID uuid.UUID) (*entities.DriverDocumentsResponse, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Документы отсортированы от новых к старым, поэтому первое найденное продление — последнее
+	renewals := make(map[uuid.UUID]*entities.DriverDocument)
+	for _, document := range documents {
+		if document.IsRenewal() && !document.IsCurrent {
+			if _, ok := renewals[*document.ReplacesID]; !ok {
+				renewals[*document.ReplacesID] = document
+			}
+		}
+	}
+
+	now := time.Now()
+	response := &entities.DriverDocumentsResponse{
+		DriverID:  driverID,
+		Documents: make([]*entities.DocumentRenewalStatus, 0, len(documents)),
+	}
+	for _, document := range documents {
+		if !document.IsCurrent {
+			continue
+		}
+
+		renewal := renewals[document.ID]
+		response.Documents = append(response.Documents, &entities.DocumentRenewalStatus{
+			Document:     document,
+			Renewal:      renewal,
+			CanRenew:     document.CanRenew(s.policy.WindowDays, now) && !isOpenRenewal(renewal),
+			CanResubmit:  document.CanResubmit(),
+			DaysToExpiry: document.DaysUntilExpiry(),
+		})
+	}
+
+	return response, nil
+}
+
+// RenewDocument загружает новую версию истекающего документа. Текущий документ остается
+// действующим до проверки новой версии; файл новой версии распознается после ответа
+func (s *documentRenewalService) RenewDocument(ctx context.Context, driverID, documentID uuid.UUID, req *entities.DocumentRenewalRequest, upload *FileUpload) (*entities.DriverDocument, error) {
+	extension, ok := documentExtensions[upload.ContentType]
+	if !ok || upload.Size <= 0 || (s.policy.MaxFileSize > 0 && upload.Size > s.policy.MaxFileSize) {
+		return nil, entities.ErrInvalidDocumentFile
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.DriverID != driverID {
+		return nil, entities.ErrDocumentNotFound
+	}
+	if !document.CanRenew(s.policy.WindowDays, time.Now()) {
+		return nil, entities.ErrDocumentNotRenewable
+	}
+	notAfter := document.ExpiryDate
+	if document.IsReverificationRequired() {
+		// Перепроверяется действующий документ: срок новой версии может совпадать с текущим
+		notAfter = time.Now()
+	}
+	if !req.ExpiryDate.After(notAfter) || !req.ExpiryDate.After(req.IssueDate) {
+		return nil, entities.ErrInvalidExpiryDate
+	}
+
+	if _, err := s.documentRepo.GetPendingRenewal(ctx, document.ID); err == nil {
+		return nil, entities.ErrRenewalAlreadyPending
+	} else if err != entities.ErrDocumentNotFound {
+		return nil, err
+	}
+
+	renewal := document.NewRenewal(req.DocumentNumber, req.IssueDate, req.ExpiryDate, "")
+	key := path.Join("documents", driverID.String(), renewal.ID.String()+extension)
+	url, content, err := s.saveFile(ctx, key, upload)
+	if err != nil {
+		s.logger.Error("Failed to save document file",
+			zap.Error(err),
+			zap.String("document_id", renewal.ID.String()),
+		)
+		return nil, err
+	}
+	renewal.FileURL = url
+
+	if err := renewal.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.documentRepo.Create(ctx, renewal); err != nil {
+		return nil, err
+	}
+	if s.ocr != nil {
+		s.ocr.Submit(renewal, upload.ContentType, content)
+	}
+
+	s.publishRenewalEvent(ctx, eventDocumentRenewalSubmitted, renewal)
+	s.notify(ctx, renewal, NotificationDocumentRenewalSubmitted, map[string]interface{}{
+		"verify_by": *renewal.VerifyBy,
+	})
+
+	s.logger.Info("Document renewal submitted",
+		zap.String("document_id", document.ID.String()),
+		zap.String("renewal_id", renewal.ID.String()),
+		zap.String("document_type", string(renewal.DocumentType)),
+	)
+
+	return renewal, nil
+}
+
+// ResolveRenewal завершает продление: подтвержденная версия заменяет прежний документ,
+// а при отказе прежний документ действует до своей даты истечения
+func (s *documentRenewalService) ResolveRenewal(ctx context.Context, renewal *entities.DriverDocument) error {
+	if !renewal.IsRenewal() {
+		return nil
+	}
+
+	switch renewal.Status {
+	case entities.VerificationStatusVerified:
+		if err := s.documentRepo.Supersede(ctx, *renewal.ReplacesID, renewal.ID); err != nil {
+			return fmt.Errorf("failed to supersede renewed document: %w", err)
+		}
+
+		if renewal.DocumentType == entities.DocumentTypeDriverLicense {
+			s.syncDriverLicense(ctx, renewal)
+		}
+
+		s.publishRenewalEvent(ctx, eventDocumentRenewed, renewal)
+		s.notify(ctx, renewal, NotificationDocumentRenewalVerified, map[string]interface{}{
+			"expiry_date": renewal.ExpiryDate,
+		})
+	case entities.VerificationStatusRejected:
+		data := map[string]interface{}{}
+		if renewal.RejectionReason != nil {
+			data["rejection_reason"] = *renewal.RejectionReason
+		}
+		if replaced, err := s.documentRepo.GetByID(ctx, *renewal.ReplacesID); err == nil {
+			data["current_expiry_date"] = replaced.ExpiryDate
+		}
+		s.notify(ctx, renewal, NotificationDocumentRenewalRejected, data)
+	}
+
+	return nil
+}
+
+// SendExpiryReminders напоминает водителям о продлении подтвержденных документов,
+// истекающих в окне продления. Напоминание отправляется один раз на документ
+func (s *documentRenewalService) SendExpiryReminders(ctx context.Context) (int, error) {
+	documents, err := s.documentRepo.GetExpiring(ctx, s.policy.WindowDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expiring documents: %w", err)
+	}
+
+	sent := 0
+	for _, document := range documents {
+		if document.ExpiryReminderSentAt != nil {
+			continue
+		}
+		if _, err := s.documentRepo.GetPendingRenewal(ctx, document.ID); err == nil {
+			continue
+		}
+
+		template := NotificationDocumentExpiring
+		if document.DocumentType == entities.DocumentTypeDriverLicense {
+			template = NotificationLicenseExpiring
+		}
+
+		data := map[string]interface{}{
+			"document_id":   document.ID.String(),
+			"document_type": document.DocumentType,
+			"expiry_date":   document.ExpiryDate,
+		}
+		if err := s.notifier.SendToDriver(ctx, document.DriverID, template, data); err != nil {
+			s.logger.Error("Failed to send document expiry reminder",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			continue
+		}
+
+		document.MarkExpiryReminderSent()
+		if err := s.documentRepo.Update(ctx, document); err != nil {
+			s.logger.Error("Failed to mark document expiry reminder as sent",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("Document expiry reminders sent", zap.Int("count", sent))
+	}
+
+	return sent, nil
+}
+
+// ResubmitDocument заменяет отклоненный документ новой версией, которая снова попадает
+// в очередь проверки. Отклоненная версия с решением верификатора сохраняется в истории
+func (s *documentRenewalService) ResubmitDocument(ctx context.Context, driverID, documentID uuid.UUID, req *entities.DocumentRenewalRequest, upload *FileUpload) (*entities.DriverDocument, error) {
+	extension, ok := documentExtensions[upload.ContentType]
+	if !ok || upload.Size <= 0 || (s.policy.MaxFileSize > 0 && upload.Size > s.policy.MaxFileSize) {
+		return nil, entities.ErrInvalidDocumentFile
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.DriverID != driverID {
+		return nil, entities.ErrDocumentNotFound
+	}
+	if !document.CanResubmit() {
+		return nil, entities.ErrDocumentNotResubmittable
+	}
+	if !req.ExpiryDate.After(time.Now()) || !req.ExpiryDate.After(req.IssueDate) {
+		return nil, entities.ErrInvalidExpiryDate
+	}
+
+	// Каждая версия хранится в своем файле, чтобы история ссылалась на отклоненный файл
+	version := document.CurrentVersion() + 1
+	key := path.Join("documents", driverID.String(), fmt.Sprintf("%s-v%d%s", document.ID, version, extension))
+	url, content, err := s.saveFile(ctx, key, upload)
+	if err != nil {
+		s.logger.Error("Failed to save document file",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+		return nil, err
+	}
+
+	previous := document.Resubmit(req.DocumentNumber, req.IssueDate, req.ExpiryDate, url, time.Now())
+	if err := document.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.documentRepo.Resubmit(ctx, document, previous); err != nil {
+		return nil, err
+	}
+	if s.ocr != nil {
+		s.ocr.Submit(document, upload.ContentType, content)
+	}
+
+	data := map[string]interface{}{
+		"document_id":        document.ID,
+		"document_type":      document.DocumentType,
+		"version":            document.CurrentVersion(),
+		"resubmission_count": document.ResubmissionCount,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDocumentResubmitted, driverID, data); err != nil {
+		s.logger.Error("Failed to publish document resubmission event",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+	}
+
+	s.logger.Info("Rejected document resubmitted",
+		zap.String("document_id", document.ID.String()),
+		zap.String("document_type", string(document.DocumentType)),
+		zap.Int("resubmission_count", document.ResubmissionCount),
+	)
+
+	return document, nil
+}
+
+// GetDocumentVersions возвращает документ водителя вместе с версиями, замененными
+// повторной загрузкой
+func (s *documentRenewalService) GetDocumentVersions(ctx context.Context, driverID, documentID uuid.UUID) (*entities.DocumentVersionHistory, error) {
+	document, err := s.documentRepo.GetByID(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.DriverID != driverID {
+		return nil, entities.ErrDocumentNotFound
+	}
+
+	versions, err := s.documentRepo.GetVersions(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.DocumentVersionHistory{
+		Document: document,
+		Versions: versions,
+	}, nil
+}
+
+// GetDocumentHistory возвращает текущую версию документа водителя типа docType вместе
+// с замененными версиями и непринятыми продлениями. Если документа этого типа нет,
+// текущая версия и история пусты
+func (s *documentRenewalService) GetDocumentHistory(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DocumentHistory, error) {
+	if docType == "" {
+		return nil, entities.ErrInvalidDocumentType
+	}
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	current, err := s.documentRepo.GetCurrent(ctx, driverID, docType)
+	if err != nil && err != entities.ErrDocumentNotFound {
+		return nil, err
+	}
+
+	history, err := s.documentRepo.GetHistory(ctx, driverID, docType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.DocumentHistory{
+		DriverID:     driverID,
+		DocumentType: docType,
+		Current:      current,
+		History:      history,
+	}, nil
+}
+
+// SendResubmissionReminders напоминает водителям о документах, отклоненных больше
+// ResubmissionReminderDays дней назад и не загруженных повторно. Напоминание отправляется
+// один раз на отклоненную версию
+func (s *documentRenewalService) SendResubmissionReminders(ctx context.Context) (int, error) {
+	rejectedBefore := time.Now().AddDate(0, 0, -s.policy.ResubmissionReminderDays)
+	documents, err := s.documentRepo.GetResubmissionDue(ctx, rejectedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list documents due for resubmission: %w", err)
+	}
+
+	sent := 0
+	for _, document := range documents {
+		data := map[string]interface{}{
+			"document_id":        document.ID.String(),
+			"document_type":      document.DocumentType,
+			"rejected_at":        *document.VerifiedAt,
+			"resubmission_count": document.ResubmissionCount,
+		}
+		if document.RejectionReason != nil {
+			data["rejection_reason"] = *document.RejectionReason
+		}
+		if err := s.notifier.SendToDriver(ctx, document.DriverID, NotificationDocumentResubmissionReminder, data); err != nil {
+			s.logger.Error("Failed to send document resubmission reminder",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			continue
+		}
+
+		document.MarkResubmissionReminderSent()
+		if err := s.documentRepo.Update(ctx, document); err != nil {
+			s.logger.Error("Failed to mark document resubmission reminder as sent",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("Document resubmission reminders sent", zap.Int("count", sent))
+	}
+
+	return sent, nil
+}
+
+// saveFile сохраняет загруженный файл документа под ключом key и возвращает его адрес.
+// Если настроено распознавание, возвращает и содержимое файла для него
+func (s *documentRenewalService) saveFile(ctx context.Context, key string, upload *FileUpload) (string, []byte, error) {
+	body := io.LimitReader(upload.Body, upload.Size)
+	var content []byte
+	if s.ocr != nil {
+		// Файл нужен и хранилищу, и распознаванию после ответа водителю
+		var err error
+		if content, err = io.ReadAll(body); err != nil {
+			return "", nil, fmt.Errorf("failed to read document file: %w", err)
+		}
+		body = bytes.NewReader(content)
+	}
+
+	url, err := s.storage.Save(ctx, key, upload.ContentType, body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to save document file: %w", err)
+	}
+	return url, content, nil
+}
+
+// syncDriverLicense переносит номер и срок действия продленного удостоверения в профиль водителя
+func (s *documentRenewalService) syncDriverLicense(ctx context.Context, renewal *entities.DriverDocument) {
+	driver, err := s.driverRepo.GetByID(ctx, renewal.DriverID)
+	if err == nil {
+		driver.LicenseNumber = renewal.DocumentNumber
+		driver.LicenseExpiry = renewal.ExpiryDate
+		driver.UpdatedAt = time.Now()
+		err = s.driverRepo.Update(ctx, driver)
+	}
+	if err != nil {
+		s.logger.Error("Failed to update driver license after renewal",
+			zap.Error(err),
+			zap.String("driver_id", renewal.DriverID.String()),
+		)
+	}
+}
+
+// notify отправляет водителю уведомление о шаге продления документа
+func (s *documentRenewalService) notify(ctx context.Context, renewal *entities.DriverDocument, template string, data map[string]interface{}) {
+	data["document_id"] = renewal.ReplacesID.String()
+	data["renewal_id"] = renewal.ID.String()
+	data["document_type"] = renewal.DocumentType
+
+	if err := s.notifier.SendToDriver(ctx, renewal.DriverID, template, data); err != nil {
+		s.logger.Error("Failed to send document renewal notification",
+			zap.Error(err),
+			zap.String("renewal_id", renewal.ID.String()),
+			zap.String("template", template),
+		)
+	}
+}
+
+// publishRenewalEvent публикует событие продления документа
+func (s *documentRenewalService) publishRenewalEvent(ctx context.Context, eventType string, renewal *entities.DriverDocument) {
+	data := map[string]interface{}{
+		"document_id":   renewal.ReplacesID,
+		"renewal_id":    renewal.ID,
+		"document_type": renewal.DocumentType,
+		"expiry_date":   renewal.ExpiryDate,
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, renewal.DriverID, data); err != nil {
+		s.logger.Error("Failed to publish document renewal event",
+			zap.Error(err),
+			zap.String("event_type", eventType),
+		)
+	}
+}
+
+// isOpenRenewal проверяет, ожидает ли продление проверки
+func isOpenRenewal(renewal *entities.DriverDocument) bool {
+	return renewal != nil &&
+		(renewal.Status == entities.VerificationStatusPending || renewal.Status == entities.VerificationStatusProcessing)
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type renewalFixture struct {
+	renewals     DocumentRenewalService
+	verification DocumentVerificationService
+	documentRepo *memory.DocumentRepository
+	driverRepo   *memory.DriverRepository
+	notifier     *recordingNotifier
+	events       *recordingEventPublisher
+	driver       *entities.Driver
+	license      *entities.DriverDocument
+}
+
+func newRenewalFixture(t *testing.T, expiresIn time.Duration) *renewalFixture {
+	ctx := context.Background()
+	f := &renewalFixture{
+		documentRepo: memory.NewDocumentRepository(),
+		driverRepo:   memory.NewDriverRepository(),
+		notifier:     &recordingNotifier{},
+		events:       &recordingEventPublisher{},
+	}
+
+	f.driver = newTestDriver("1")
+	f.driver.ID = uuid.New()
+	require.NoError(t, f.driverRepo.Create(ctx, f.driver))
+
+	f.license = entities.NewDriverDocument(f.driver.ID, entities.DocumentTypeDriverLicense, f.driver.LicenseNumber,
+		time.Now().AddDate(-10, 0, 0), time.Now().Add(expiresIn), "https://example.com/license.pdf")
+	f.license.Status = entities.VerificationStatusVerified
+	require.NoError(t, f.documentRepo.Create(ctx, f.license))
+
+	f.renewals = NewDocumentRenewalService(f.documentRepo, f.driverRepo,
+		&fakeFileStorage{files: make(map[string][]byte)}, nil, f.notifier, f.events,
+		DocumentRenewalPolicy{WindowDays: 30, MaxFileSize: 1 << 20, ResubmissionReminderDays: 3}, zap.NewNop())
+	f.verification = NewDocumentVerificationService(f.documentRepo, memory.NewAuditRepository(), f.renewals, f.notifier, nil, f.events,
+		VerificationQueuePolicy{ClaimTTL: time.Minute, MaxBatch: 10}, zap.NewNop())
+	return f
+}
+
+func (f *renewalFixture) renew() (*entities.DriverDocument, error) {
+	req := &entities.DocumentRenewalRequest{
+		DocumentNumber: "LIC-NEW",
+		IssueDate:      time.Now(),
+		ExpiryDate:     time.Now().AddDate(10, 0, 0),
+	}
+	upload := &FileUpload{ContentType: "application/pdf", Size: 4, Body: bytes.NewReader([]byte("%PDF"))}
+	return f.renewals.RenewDocument(context.Background(), f.driver.ID, f.license.ID, req, upload)
+}
+
+func (f *renewalFixture) decide(t *testing.T, status entities.VerificationStatus, reason *string) {
+	ctx := context.Background()
+	claimed, err := f.verification.ClaimNext(ctx, "verifier-1", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, claimed)
+
+	result, err := f.verification.SubmitDecisions(ctx, "verifier-1", []*entities.DocumentDecision{
+		{DocumentID: claimed[0].ID, Status: status, RejectionReason: reason},
+	})
+	require.NoError(t, err)
+	require.Zero(t, result.Failed)
+}
+
+func TestDocumentRenewalService_RenewAndVerify(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+
+	// Обычный документ, загруженный раньше, не должен опережать продление в очереди
+	other := entities.NewDriverDocument(uuid.New(), entities.DocumentTypeMedicalCert, "MED-1",
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(1, 0, 0), "https://example.com/med.pdf")
+	other.CreatedAt = time.Now().Add(-time.Hour)
+	require.NoError(t, f.documentRepo.Create(ctx, other))
+
+	renewal, err := f.renew()
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusPending, renewal.Status)
+	require.NotNil(t, renewal.ReplacesID)
+	assert.Equal(t, f.license.ID, *renewal.ReplacesID)
+	require.NotNil(t, renewal.VerifyBy)
+	assert.True(t, renewal.VerifyBy.Equal(f.license.ExpiryDate))
+	assert.True(t, f.events.has("driver.document.renewal_submitted"))
+
+	_, err = f.renew()
+	assert.Equal(t, entities.ErrRenewalAlreadyPending, err)
+
+	documents, err := f.renewals.GetDriverDocuments(ctx, f.driver.ID)
+	require.NoError(t, err)
+	require.Len(t, documents.Documents, 1)
+	assert.Equal(t, f.license.ID, documents.Documents[0].Document.ID)
+	assert.Equal(t, entities.VerificationStatusVerified, documents.Documents[0].Document.Status)
+	require.NotNil(t, documents.Documents[0].Renewal)
+	assert.False(t, documents.Documents[0].CanRenew)
+
+	claimed, err := f.verification.ClaimNext(ctx, "verifier-1", 1)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+	assert.Equal(t, renewal.ID, claimed[0].ID)
+
+	_, err = f.verification.SubmitDecisions(ctx, "verifier-1", []*entities.DocumentDecision{
+		{DocumentID: renewal.ID, Status: entities.VerificationStatusVerified},
+	})
+	require.NoError(t, err)
+
+	old, err := f.documentRepo.GetByID(ctx, f.license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusSuperseded, old.Status)
+
+	driver, err := f.driverRepo.GetByID(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "LIC-NEW", driver.LicenseNumber)
+	assert.True(t, driver.LicenseExpiry.Equal(renewal.ExpiryDate))
+
+	documents, err = f.renewals.GetDriverDocuments(ctx, f.driver.ID)
+	require.NoError(t, err)
+	require.Len(t, documents.Documents, 1)
+	assert.Equal(t, renewal.ID, documents.Documents[0].Document.ID)
+	assert.Nil(t, documents.Documents[0].Renewal)
+
+	assert.True(t, f.events.has("driver.document.renewed"))
+	assert.Contains(t, f.notifier.templates, NotificationDocumentRenewalVerified)
+}
+
+func TestDocumentRenewalService_RejectedRenewalKeepsCurrentDocument(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+
+	_, err := f.renew()
+	require.NoError(t, err)
+
+	reason := "нечитаемый скан"
+	f.decide(t, entities.VerificationStatusRejected, &reason)
+
+	old, err := f.documentRepo.GetByID(ctx, f.license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusVerified, old.Status)
+	assert.Contains(t, f.notifier.templates, NotificationDocumentRenewalRejected)
+
+	// После отказа водитель может загрузить документ повторно
+	_, err = f.renew()
+	assert.NoError(t, err)
+}
+
+func TestDocumentRenewalService_DocumentHistory(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+
+	rejected, err := f.renew()
+	require.NoError(t, err)
+	reason := "нечитаемый скан"
+	f.decide(t, entities.VerificationStatusRejected, &reason)
+
+	history, err := f.renewals.GetDocumentHistory(ctx, f.driver.ID, entities.DocumentTypeDriverLicense)
+	require.NoError(t, err)
+	require.NotNil(t, history.Current)
+	assert.Equal(t, f.license.ID, history.Current.ID)
+	require.Len(t, history.History, 1)
+	assert.Equal(t, rejected.ID, history.History[0].ID)
+
+	renewal, err := f.renew()
+	require.NoError(t, err)
+	f.decide(t, entities.VerificationStatusVerified, nil)
+
+	history, err = f.renewals.GetDocumentHistory(ctx, f.driver.ID, entities.DocumentTypeDriverLicense)
+	require.NoError(t, err)
+	require.NotNil(t, history.Current)
+	assert.Equal(t, renewal.ID, history.Current.ID)
+	assert.True(t, history.Current.IsCurrent)
+	require.Len(t, history.History, 2)
+	for _, document := range history.History {
+		assert.False(t, document.IsCurrent)
+	}
+
+	// Продлить можно только текущую версию
+	old, err := f.documentRepo.GetByID(ctx, f.license.ID)
+	require.NoError(t, err)
+	assert.False(t, old.CanRenew(30, time.Now()))
+
+	history, err = f.renewals.GetDocumentHistory(ctx, f.driver.ID, entities.DocumentTypePassport)
+	require.NoError(t, err)
+	assert.Nil(t, history.Current)
+	assert.Empty(t, history.History)
+
+	_, err = f.renewals.GetDocumentHistory(ctx, f.driver.ID, "")
+	assert.Equal(t, entities.ErrInvalidDocumentType, err)
+}
+
+func TestDocumentRenewalService_RenewValidation(t *testing.T) {
+	f := newRenewalFixture(t, 90*24*time.Hour)
+
+	_, err := f.renew()
+	assert.Equal(t, entities.ErrDocumentNotRenewable, err)
+
+	_, err = f.renewals.RenewDocument(context.Background(), f.driver.ID, f.license.ID,
+		&entities.DocumentRenewalRequest{DocumentNumber: "LIC-NEW"},
+		&FileUpload{ContentType: "text/plain", Size: 4, Body: bytes.NewReader([]byte("text"))})
+	assert.Equal(t, entities.ErrInvalidDocumentFile, err)
+
+	_, err = f.renewals.RenewDocument(context.Background(), uuid.New(), f.license.ID,
+		&entities.DocumentRenewalRequest{DocumentNumber: "LIC-NEW"},
+		&FileUpload{ContentType: "application/pdf", Size: 4, Body: bytes.NewReader([]byte("%PDF"))})
+	assert.Equal(t, entities.ErrDocumentNotFound, err)
+}
+
+func TestDocumentRenewalService_SendExpiryReminders(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 10*24*time.Hour)
+
+	sent, err := f.renewals.SendExpiryReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{NotificationLicenseExpiring}, f.notifier.templates)
+
+	sent, err = f.renewals.SendExpiryReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
+
+// rejectedPassport создает паспорт водителя, отклоненный верификатором
+func (f *renewalFixture) rejectedPassport(t *testing.T) *entities.DriverDocument {
+	passport := entities.NewDriverDocument(f.driver.ID, entities.DocumentTypePassport, "4500 123456",
+		time.Now().AddDate(-5, 0, 0), time.Now().AddDate(5, 0, 0), "https://example.com/passport.pdf")
+	require.NoError(t, f.documentRepo.Create(context.Background(), passport))
+
+	reason := "нечитаемый скан"
+	f.decide(t, entities.VerificationStatusRejected, &reason)
+	return passport
+}
+
+func (f *renewalFixture) resubmit(documentID uuid.UUID) (*entities.DriverDocument, error) {
+	req := &entities.DocumentRenewalRequest{
+		DocumentNumber: "4500 654321",
+		IssueDate:      time.Now().AddDate(-5, 0, 0),
+		ExpiryDate:     time.Now().AddDate(5, 0, 0),
+	}
+	upload := &FileUpload{ContentType: "application/pdf", Size: 4, Body: bytes.NewReader([]byte("%PDF"))}
+	return f.renewals.ResubmitDocument(context.Background(), f.driver.ID, documentID, req, upload)
+}
+
+func TestDocumentRenewalService_ResubmitRejectedDocument(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 90*24*time.Hour)
+
+	// Подтвержденный документ заново не загружается
+	_, err := f.resubmit(f.license.ID)
+	assert.Equal(t, entities.ErrDocumentNotResubmittable, err)
+
+	passport := f.rejectedPassport(t)
+
+	resubmitted, err := f.resubmit(passport.ID)
+	require.NoError(t, err)
+	assert.Equal(t, passport.ID, resubmitted.ID)
+	assert.Equal(t, entities.VerificationStatusPending, resubmitted.Status)
+	assert.Equal(t, "4500 654321", resubmitted.DocumentNumber)
+	assert.Equal(t, 1, resubmitted.ResubmissionCount)
+	assert.Nil(t, resubmitted.RejectionReason)
+	assert.True(t, f.events.has("driver.document.resubmitted"))
+
+	_, err = f.resubmit(passport.ID)
+	assert.Equal(t, entities.ErrDocumentNotResubmittable, err)
+
+	history, err := f.renewals.GetDocumentVersions(ctx, f.driver.ID, passport.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, history.Document.CurrentVersion())
+	require.Len(t, history.Versions, 1)
+	assert.Equal(t, 1, history.Versions[0].Version)
+	assert.Equal(t, "4500 123456", history.Versions[0].DocumentNumber)
+	assert.Equal(t, entities.VerificationStatusRejected, history.Versions[0].Status)
+	require.NotNil(t, history.Versions[0].RejectionReason)
+	assert.Equal(t, "нечитаемый скан", *history.Versions[0].RejectionReason)
+
+	// Новая версия снова попадает в очередь и после второго отказа сохраняется в истории
+	reason := "истек срок"
+	f.decide(t, entities.VerificationStatusRejected, &reason)
+	_, err = f.resubmit(passport.ID)
+	require.NoError(t, err)
+
+	history, err = f.renewals.GetDocumentVersions(ctx, f.driver.ID, passport.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, history.Document.ResubmissionCount)
+	require.Len(t, history.Versions, 2)
+	assert.Equal(t, 2, history.Versions[0].Version)
+	assert.Equal(t, "истек срок", *history.Versions[0].RejectionReason)
+
+	_, err = f.renewals.GetDocumentVersions(ctx, uuid.New(), passport.ID)
+	assert.Equal(t, entities.ErrDocumentNotFound, err)
+}
+
+func TestDocumentRenewalService_SendResubmissionReminders(t *testing.T) {
+	ctx := context.Background()
+	f := newRenewalFixture(t, 90*24*time.Hour)
+	passport := f.rejectedPassport(t)
+	f.notifier.templates = nil
+
+	// Документ отклонен только что
+	sent, err := f.renewals.SendResubmissionReminders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	rejected, err := f.documentRepo.GetByID(ctx, passport.ID)
+	require.NoError(t, err)
+	rejectedAt := time.Now().AddDate(0, 0, -4)
+	rejected.VerifiedAt = &rejectedAt
+	require.NoError(t, f.documentRepo.Update(ctx, rejected))
+
+	sent, err = f.renewals.SendResubmissionReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{NotificationDocumentResubmissionReminder}, f.notifier.templates)
+
+	sent, err = f.renewals.SendResubmissionReminders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	// Повторная загрузка сбрасывает напоминание для следующего отказа
+	resubmitted, err := f.resubmit(passport.ID)
+	require.NoError(t, err)
+	assert.Nil(t, resubmitted.ResubmissionReminderSentAt)
+}
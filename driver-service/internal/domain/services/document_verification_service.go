@@ -0,0 +1,370 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// VerificationQueuePolicy настройки очереди проверки документов
+type VerificationQueuePolicy struct {
+	// ClaimTTL время, на которое документ закрепляется за верификатором
+	ClaimTTL time.Duration
+	// MaxBatch максимальное число документов в одном захвате и в одной пачке решений
+	MaxBatch int
+}
+
+// LicenseRegistry проверяет водительские удостоверения во внешнем реестре (ГИБДД, страховщики)
+type LicenseRegistry interface {
+	// CheckLicense возвращает состояние удостоверения; ошибка означает, что реестр не ответил
+	CheckLicense(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error)
+	// FailOpen сообщает, подтверждать ли удостоверение, если реестр недоступен
+	FailOpen() bool
+}
+
+// DocumentVerificationService интерфейс очереди проверки документов
+type DocumentVerificationService interface {
+	ClaimNext(ctx context.Context, verifierID string, limit int) ([]*entities.DriverDocument, error)
+	SubmitDecisions(ctx context.Context, verifierID string, decisions []*entities.DocumentDecision) (*entities.DocumentDecisionBatchResult, error)
+	ReleaseStaleClaims(ctx context.Context) (int, error)
+	GetVerifierStats(ctx context.Context, from, to time.Time) (*entities.VerifierStatsReport, error)
+}
+
+// documentVerificationService реализация DocumentVerificationService
+type documentVerificationService struct {
+	documentRepo repositories.DocumentRepository
+	auditRepo    repositories.AuditRepository
+	renewals     DocumentRenewalService
+	notifier     NotificationSender
+	licenses     LicenseRegistry
+	eventBus     EventPublisher
+	policy       VerificationQueuePolicy
+	logger       *zap.Logger
+}
+
+// NewDocumentVerificationService создает новый DocumentVerificationService.
+// Решения записываются в журнал аудита auditRepo; renewals завершает продление, когда
+// решение принято по новой версии документа; notifier сообщает водителю об отклонении
+// документа и может быть nil; licenses сверяет подтверждаемые водительские удостоверения
+// с внешним реестром и может быть nil
+func NewDocumentVerificationService(
+	documentRepo repositories.DocumentRepository,
+	auditRepo repositories.AuditRepository,
+	renewals DocumentRenewalService,
+	notifier NotificationSender,
+	licenses LicenseRegistry,
+	eventBus EventPublisher,
+	policy VerificationQueuePolicy,
+	logger *zap.Logger,
+) DocumentVerificationService {
+	return &documentVerificationService{
+		documentRepo: documentRepo,
+		auditRepo:    auditRepo,
+		renewals:     renewals,
+		notifier:     notifier,
+		licenses:     licenses,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// ClaimNext закрепляет за верификатором до limit самых старых документов из очереди
+func (s *documentVerificationService) ClaimNext(ctx context.Context, verifierID string, limit int) ([]*entities.DriverDocument, error) {
+	if strings.TrimSpace(verifierID) == "" {
+		return nil, entities.ErrInvalidVerifierID
+	}
+	if limit <= 0 || limit > s.policy.MaxBatch {
+		limit = s.policy.MaxBatch
+	}
+
+	documents, err := s.documentRepo.ClaimPending(ctx, verifierID, limit, s.policy.ClaimTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Documents claimed for verification",
+		zap.String("verifier_id", verifierID),
+		zap.Int("requested", limit),
+		zap.Int("claimed", len(documents)),
+	)
+	return documents, nil
+}
+
+// SubmitDecisions применяет решения по захваченным документам.
+// Решения применяются независимо: ошибка по одному документу не отменяет остальные
+func (s *documentVerificationService) SubmitDecisions(ctx context.Context, verifierID string, decisions []*entities.DocumentDecision) (*entities.DocumentDecisionBatchResult, error) {
+	if strings.TrimSpace(verifierID) == "" {
+		return nil, entities.ErrInvalidVerifierID
+	}
+	if len(decisions) == 0 || len(decisions) > s.policy.MaxBatch {
+		return nil, entities.ErrInvalidDecisionBatch
+	}
+
+	batch := &entities.DocumentDecisionBatchResult{
+		Results: make([]*entities.DocumentDecisionResult, len(decisions)),
+	}
+
+	for i, decision := range decisions {
+		result := &entities.DocumentDecisionResult{DocumentID: decision.DocumentID, Status: decision.Status}
+		batch.Results[i] = result
+
+		if err := s.applyDecision(ctx, verifierID, decision); err != nil {
+			result.Error = decisionError(err)
+			batch.Failed++
+			continue
+		}
+
+		result.Applied = true
+		batch.Applied++
+	}
+
+	s.logger.Info("Verification decisions submitted",
+		zap.String("verifier_id", verifierID),
+		zap.Int("applied", batch.Applied),
+		zap.Int("failed", batch.Failed),
+	)
+	return batch, nil
+}
+
+// applyDecision применяет одно решение и публикует событие о результате проверки
+func (s *documentVerificationService) applyDecision(ctx context.Context, verifierID string, decision *entities.DocumentDecision) error {
+	if err := decision.Validate(); err != nil {
+		return err
+	}
+
+	var reason *string
+	if decision.Status == entities.VerificationStatusRejected {
+		reason = decision.RejectionReason
+	}
+
+	var registry *entities.LicenseCheckResult
+	if decision.Status == entities.VerificationStatusVerified {
+		result, err := s.checkLicense(ctx, verifierID, decision)
+		if err != nil {
+			return err
+		}
+		registry = result
+	}
+
+	if err := s.documentRepo.ResolveClaim(ctx, decision.DocumentID, verifierID, decision.Status, reason); err != nil {
+		if decisionError(err) == internalDecisionError {
+			s.logger.Error("Failed to apply verification decision",
+				zap.Error(err),
+				zap.String("document_id", decision.DocumentID.String()),
+			)
+		}
+		return err
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, decision.DocumentID)
+	if err != nil {
+		// Решение уже сохранено, событие не критично
+		s.logger.Warn("Failed to load document after verification", zap.Error(err))
+		return nil
+	}
+
+	s.record(ctx, document, verifierID, reason, registry)
+
+	eventData := map[string]interface{}{
+		"document_id":   document.ID,
+		"document_type": document.DocumentType,
+		"verifier_id":   verifierID,
+	}
+	eventType := eventDocumentVerified
+	if decision.Status == entities.VerificationStatusRejected {
+		eventType = eventDocumentRejected
+		eventData["rejection_reason"] = *reason
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, document.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish document verification event", zap.Error(err))
+	}
+
+	if document.IsRenewal() {
+		// Решение по новой версии уже сохранено, поэтому ошибка завершения продления только логируется
+		if err := s.renewals.ResolveRenewal(ctx, document); err != nil {
+			s.logger.Error("Failed to resolve document renewal",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+		}
+	} else if decision.Status == entities.VerificationStatusRejected {
+		s.notifyRejected(ctx, document, *reason)
+	}
+	return nil
+}
+
+// checkLicense сверяет подтверждаемое водительское удостоверение с внешним реестром.
+// Возвращает nil для других документов, без реестра и при недоступном реестре в режиме
+// FailOpen; удостоверение, не подтвержденное реестром, подтвердить нельзя
+func (s *documentVerificationService) checkLicense(ctx context.Context, verifierID string, decision *entities.DocumentDecision) (*entities.LicenseCheckResult, error) {
+	if s.licenses == nil {
+		return nil, nil
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, decision.DocumentID)
+	if err != nil {
+		return nil, err
+	}
+	if document.DocumentType != entities.DocumentTypeDriverLicense {
+		return nil, nil
+	}
+	// Реестр не запрашивается по документу, который верификатор все равно не может решить
+	if document.ClaimedBy == nil || *document.ClaimedBy != verifierID {
+		return nil, entities.ErrDocumentClaimNotHeld
+	}
+
+	result, err := s.licenses.CheckLicense(ctx, entities.NewLicenseCheckRequest(document))
+	if err != nil {
+		if s.licenses.FailOpen() {
+			s.logger.Warn("License registry unavailable, document verified without registry check",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+			return nil, nil
+		}
+		s.logger.Error("License registry unavailable",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+		return nil, entities.ErrLicenseRegistryUnavailable
+	}
+
+	if !result.Accepted() {
+		s.logger.Info("Driver license rejected by registry",
+			zap.String("document_id", document.ID.String()),
+			zap.String("registry", result.Registry),
+			zap.String("registry_status", string(result.Status)),
+		)
+		return nil, fmt.Errorf("%w: %s", entities.ErrLicenseRegistryRejected, result.Status)
+	}
+	return result, nil
+}
+
+// record сохраняет решение в журнал аудита; ошибка журнала не отменяет решение
+func (s *documentVerificationService) record(ctx context.Context, document *entities.DriverDocument, verifierID string, reason *string, registry *entities.LicenseCheckResult) {
+	entry := entities.NewAuditEntry(entities.AuditEventDocumentVerification, string(document.Status), "completed")
+	entry.DriverID = &document.DriverID
+	entry.AttachActor(ctx)
+	entry.Details["document_id"] = document.ID
+	entry.Details["document_type"] = document.DocumentType
+	entry.Details["verifier_id"] = verifierID
+	entry.Before = entities.Metadata{"status": entities.VerificationStatusPending}
+	entry.After = entities.Metadata{"status": document.Status}
+	if reason != nil {
+		entry.After["rejection_reason"] = *reason
+	}
+	if registry != nil {
+		entry.Details["license_registry"] = registry.Registry
+		entry.Details["license_registry_status"] = registry.Status
+	}
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record document verification audit entry",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+	}
+}
+
+// notifyRejected сообщает водителю об отклонении документа. Об отклоненном продлении
+// водителя уведомляет DocumentRenewalService
+func (s *documentVerificationService) notifyRejected(ctx context.Context, document *entities.DriverDocument, reason string) {
+	if s.notifier == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"document_id":      document.ID.String(),
+		"document_type":    document.DocumentType,
+		"rejection_reason": reason,
+	}
+	if err := s.notifier.SendToDriver(ctx, document.DriverID, NotificationDocumentRejected, data); err != nil {
+		s.logger.Error("Failed to notify about rejected document",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+	}
+}
+
+// internalDecisionError сообщение для ошибок, детали которых не передаются верификатору
+const internalDecisionError = "internal error"
+
+// decisionError возвращает сообщение об ошибке решения для ответа верификатору.
+// Отказ реестра передается вместе с состоянием удостоверения
+func decisionError(err error) string {
+	if errors.Is(err, entities.ErrLicenseRegistryRejected) {
+		return err.Error()
+	}
+	for _, known := range []error{
+		entities.ErrDocumentNotFound,
+		entities.ErrDocumentClaimNotHeld,
+		entities.ErrInvalidDecision,
+		entities.ErrLicenseRegistryUnavailable,
+	} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return internalDecisionError
+}
+
+// ReleaseStaleClaims возвращает в очередь документы, захват которых истек
+func (s *documentVerificationService) ReleaseStaleClaims(ctx context.Context) (int, error) {
+	released, err := s.documentRepo.ReleaseStaleClaims(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if released > 0 {
+		s.logger.Info("Stale document claims released", zap.Int("released", released))
+	}
+	return released, nil
+}
+
+// GetVerifierStats возвращает производительность верификаторов за период и текущий размер очереди
+func (s *documentVerificationService) GetVerifierStats(ctx context.Context, from, to time.Time) (*entities.VerifierStatsReport, error) {
+	if !to.After(from) {
+		return nil, entities.ErrInvalidTimestamp
+	}
+
+	stats, err := s.documentRepo.GetVerifierStats(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	hours := to.Sub(from).Hours()
+	for _, verifier := range stats {
+		verifier.DocumentsPerHour = float64(verifier.Total) / hours
+	}
+
+	pending, err := s.documentRepo.Count(ctx, &entities.DocumentFilters{
+		Status: []entities.VerificationStatus{entities.VerificationStatusPending},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	inProgress, err := s.documentRepo.Count(ctx, &entities.DocumentFilters{
+		Status: []entities.VerificationStatus{entities.VerificationStatusProcessing},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.VerifierStatsReport{
+		From:       from,
+		To:         to,
+		Verifiers:  stats,
+		Pending:    pending,
+		InProgress: inProgress,
+	}, nil
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestVerificationService(t *testing.T, claimTTL time.Duration, pending int) (DocumentVerificationService, *memory.DocumentRepository, *recordingEventPublisher) {
+	documentRepo := memory.NewDocumentRepository()
+	events := &recordingEventPublisher{}
+
+	types := []entities.DocumentType{
+		entities.DocumentTypeDriverLicense,
+		entities.DocumentTypePassport,
+		entities.DocumentTypeMedicalCert,
+	}
+	for i := 0; i < pending; i++ {
+		document := entities.NewDriverDocument(uuid.New(), types[i%len(types)], "DOC-1",
+			time.Now().AddDate(-1, 0, 0), time.Now().AddDate(1, 0, 0), "https://example.com/doc.pdf")
+		document.CreatedAt = time.Now().Add(time.Duration(i) * time.Second)
+		require.NoError(t, documentRepo.Create(context.Background(), document))
+	}
+
+	service := NewDocumentVerificationService(documentRepo, memory.NewAuditRepository(), nil, nil, nil, events,
+		VerificationQueuePolicy{ClaimTTL: claimTTL, MaxBatch: 10}, zap.NewNop())
+	return service, documentRepo, events
+}
+
+func TestDocumentVerificationService_ConcurrentClaims(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestVerificationService(t, time.Minute, 20)
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		claimed = make(map[uuid.UUID]string)
+	)
+	for _, verifier := range []string{"alice", "bob", "carol", "dave"} {
+		wg.Add(1)
+		go func(verifier string) {
+			defer wg.Done()
+			documents, err := service.ClaimNext(ctx, verifier, 6)
+			require.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, document := range documents {
+				owner, taken := claimed[document.ID]
+				assert.False(t, taken, "document %s claimed by %s and %s", document.ID, owner, verifier)
+				claimed[document.ID] = verifier
+			}
+		}(verifier)
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, 20, "all pending documents are handed out exactly once")
+
+	documents, err := service.ClaimNext(ctx, "erin", 5)
+	require.NoError(t, err)
+	assert.Empty(t, documents)
+}
+
+func TestDocumentVerificationService_SubmitDecisions(t *testing.T) {
+	ctx := context.Background()
+	service, documentRepo, events := newTestVerificationService(t, time.Minute, 3)
+
+	claimed, err := service.ClaimNext(ctx, "alice", 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+
+	others, err := service.ClaimNext(ctx, "bob", 0)
+	require.NoError(t, err)
+	require.Len(t, others, 1)
+
+	reason := "Нечитаемая копия"
+	result, err := service.SubmitDecisions(ctx, "alice", []*entities.DocumentDecision{
+		{DocumentID: claimed[0].ID, Status: entities.VerificationStatusVerified},
+		{DocumentID: claimed[1].ID, Status: entities.VerificationStatusRejected, RejectionReason: &reason},
+		{DocumentID: others[0].ID, Status: entities.VerificationStatusVerified},
+		{DocumentID: uuid.New(), Status: entities.VerificationStatusVerified},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Applied)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, entities.ErrDocumentClaimNotHeld.Error(), result.Results[2].Error)
+	assert.Equal(t, entities.ErrDocumentNotFound.Error(), result.Results[3].Error)
+	assert.True(t, events.has("driver.document.verified"))
+	assert.True(t, events.has("driver.document.rejected"))
+
+	rejected, err := documentRepo.GetByID(ctx, claimed[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusRejected, rejected.Status)
+	assert.Equal(t, "alice", *rejected.VerifiedBy)
+
+	// Отказ без причины не принимается
+	result, err = service.SubmitDecisions(ctx, "bob", []*entities.DocumentDecision{
+		{DocumentID: others[0].ID, Status: entities.VerificationStatusRejected},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ErrInvalidDecision.Error(), result.Results[0].Error)
+
+	report, err := service.GetVerifierStats(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, report.Verifiers, 1)
+	assert.Equal(t, "alice", report.Verifiers[0].VerifierID)
+	assert.Equal(t, 1, report.Verifiers[0].Verified)
+	assert.Equal(t, 1, report.Verifiers[0].Rejected)
+	assert.Equal(t, 1, report.InProgress)
+}
+
+func TestDocumentVerificationService_ReleaseStaleClaims(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestVerificationService(t, time.Millisecond, 2)
+
+	claimed, err := service.ClaimNext(ctx, "alice", 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	time.Sleep(5 * time.Millisecond)
+
+	// Истекший захват не позволяет сохранить решение
+	result, err := service.SubmitDecisions(ctx, "alice", []*entities.DocumentDecision{
+		{DocumentID: claimed[0].ID, Status: entities.VerificationStatusVerified},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+
+	released, err := service.ReleaseStaleClaims(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+
+	reclaimed, err := service.ClaimNext(ctx, "bob", 5)
+	require.NoError(t, err)
+	assert.Len(t, reclaimed, 2)
+	assert.Equal(t, claimed[0].ID, reclaimed[0].ID, "queue keeps the oldest documents first")
+}
+
+// stubLicenseRegistry отвечает заданным состоянием удостоверения или ошибкой
+type stubLicenseRegistry struct {
+	status   entities.LicenseRegistryStatus
+	err      error
+	failOpen bool
+	calls    int
+}
+
+func (r *stubLicenseRegistry) CheckLicense(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error) {
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &entities.LicenseCheckResult{Status: r.status, Registry: "stub", CheckedAt: time.Now()}, nil
+}
+
+func (r *stubLicenseRegistry) FailOpen() bool {
+	return r.failOpen
+}
+
+func TestDocumentVerificationService_LicenseRegistry(t *testing.T) {
+	ctx := context.Background()
+	documentRepo := memory.NewDocumentRepository()
+	registry := &stubLicenseRegistry{status: entities.LicenseRegistrySuspended}
+	service := NewDocumentVerificationService(documentRepo, memory.NewAuditRepository(), nil, nil, registry,
+		&recordingEventPublisher{}, VerificationQueuePolicy{ClaimTTL: time.Minute, MaxBatch: 10}, zap.NewNop())
+
+	license := entities.NewDriverDocument(uuid.New(), entities.DocumentTypeDriverLicense, "77 АВ 123456",
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(1, 0, 0), "https://example.com/license.pdf")
+	passport := entities.NewDriverDocument(uuid.New(), entities.DocumentTypePassport, "4510 123456",
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(9, 0, 0), "https://example.com/passport.pdf")
+	require.NoError(t, documentRepo.Create(ctx, license))
+	require.NoError(t, documentRepo.Create(ctx, passport))
+
+	_, err := service.ClaimNext(ctx, "alice", 2)
+	require.NoError(t, err)
+
+	verify := func(id uuid.UUID) *entities.DocumentDecisionResult {
+		result, err := service.SubmitDecisions(ctx, "alice", []*entities.DocumentDecision{
+			{DocumentID: id, Status: entities.VerificationStatusVerified},
+		})
+		require.NoError(t, err)
+		return result.Results[0]
+	}
+
+	// Паспорт с реестром удостоверений не сверяется
+	assert.True(t, verify(passport.ID).Applied)
+	assert.Zero(t, registry.calls)
+
+	result := verify(license.ID)
+	assert.False(t, result.Applied)
+	assert.Contains(t, result.Error, entities.ErrLicenseRegistryRejected.Error())
+	assert.Contains(t, result.Error, string(entities.LicenseRegistrySuspended))
+
+	// Недоступный реестр не дает подтвердить удостоверение, пока не разрешен FailOpen
+	registry.err = errors.New("connection refused")
+	result = verify(license.ID)
+	assert.False(t, result.Applied)
+	assert.Equal(t, entities.ErrLicenseRegistryUnavailable.Error(), result.Error)
+
+	registry.failOpen = true
+	assert.True(t, verify(license.ID).Applied)
+
+	stored, err := documentRepo.GetByID(ctx, license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusVerified, stored.Status)
+}
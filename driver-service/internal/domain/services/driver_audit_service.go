@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// auditedDriverService записывает в журнал аудита изменения профиля, смену статуса и удаление
+// водителя: автора и ID запроса из контекста (entities.WithAuditActor) и значения измененных
+// полей до и после. Записываются только выполненные изменения
+type auditedDriverService struct {
+	DriverService
+	auditRepo repositories.AuditRepository
+	logger    *zap.Logger
+}
+
+// NewAuditedDriverService оборачивает DriverService журналом аудита изменений
+func NewAuditedDriverService(next DriverService, auditRepo repositories.AuditRepository, logger *zap.Logger) DriverService {
+	return &auditedDriverService{
+		DriverService: next,
+		auditRepo:     auditRepo,
+		logger:        logger,
+	}
+}
+
+// UpdateDriver сохраняет изменения водителя и записывает измененные поля
+func (s *auditedDriverService) UpdateDriver(ctx context.Context, driver *entities.Driver) (*entities.Driver, error) {
+	before, err := s.DriverService.GetDriverByID(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := s.DriverService.UpdateDriver(ctx, driver)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := s.newEntry(ctx, entities.AuditEventDriverUpdate, "update", updated)
+	if err := entry.SetChanges(before, updated); err != nil {
+		s.logger.Error("Failed to snapshot driver update", zap.Error(err))
+	}
+	// Сохранение без изменений в журнал не попадает
+	if len(entry.After) > 0 || len(entry.Before) > 0 {
+		s.record(ctx, entry)
+	}
+	return updated, nil
+}
+
+// ChangeDriverStatus изменяет статус водителя и записывает прежний и новый статус
+func (s *auditedDriverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	before, err := s.DriverService.GetDriverByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DriverService.ChangeDriverStatus(ctx, id, status); err != nil {
+		return err
+	}
+
+	entry := s.newEntry(ctx, entities.AuditEventDriverStatus, "change_status", before)
+	entry.Before = entities.Metadata{"status": before.Status}
+	entry.After = entities.Metadata{"status": status}
+	s.record(ctx, entry)
+	return nil
+}
+
+// OverrideDriverStatus устанавливает статус водителя и записывает прежний и новый статус,
+// причину и автора смены
+func (s *auditedDriverService) OverrideDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status, reason, actorID string) (*entities.StatusOverride, error) {
+	before, err := s.DriverService.GetDriverByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := s.DriverService.OverrideDriverStatus(ctx, id, status, reason, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := s.newEntry(ctx, entities.AuditEventDriverStatusOverride, "override_status", before)
+	entry.Before = entities.Metadata{"status": override.PreviousStatus}
+	entry.After = entities.Metadata{"status": override.Status}
+	entry.Details["reason"] = override.Reason
+	entry.Details["actor_id"] = override.ActorID
+	s.record(ctx, entry)
+	return override, nil
+}
+
+// DeleteDriver удаляет водителя и записывает удаление
+func (s *auditedDriverService) DeleteDriver(ctx context.Context, id uuid.UUID) error {
+	before, err := s.DriverService.GetDriverByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.DriverService.DeleteDriver(ctx, id); err != nil {
+		return err
+	}
+
+	entry := s.newEntry(ctx, entities.AuditEventDriverDeletion, "delete", before)
+	entry.Before = entities.Metadata{"status": before.Status, "deleted": false}
+	entry.After = entities.Metadata{"deleted": true}
+	s.record(ctx, entry)
+	return nil
+}
+
+// newEntry создает выполненную запись журнала по водителю с автором из контекста
+func (s *auditedDriverService) newEntry(ctx context.Context, event, action string, driver *entities.Driver) *entities.AuditEntry {
+	entry := entities.NewAuditEntry(event, action, "completed")
+	entry.DriverID = &driver.ID
+	entry.FleetID = driver.FleetID
+	entry.AttachActor(ctx)
+	return entry
+}
+
+// record сохраняет запись в журнал аудита; ошибка журнала не отменяет изменение
+func (s *auditedDriverService) record(ctx context.Context, entry *entities.AuditEntry) {
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record driver audit entry",
+			zap.Error(err),
+			zap.String("event", entry.Event),
+			zap.String("driver_id", entry.DriverID.String()),
+		)
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAuditedDriverService(t *testing.T) {
+	next, _, _, _ := newTestDriverService()
+	auditRepo := memory.NewAuditRepository()
+	service := NewAuditedDriverService(next, auditRepo, zap.NewNop())
+	auditService := NewAuditService(auditRepo, nil, nil, AuditPolicy{}, zap.NewNop())
+
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{
+		Subject:   "admin-1",
+		Roles:     "admin",
+		RequestID: "req-1",
+	})
+	driver, err := service.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+
+	// Недопустимая смена статуса в журнал не попадает
+	assert.Error(t, service.ChangeDriverStatus(ctx, driver.ID, entities.StatusAvailable))
+	require.NoError(t, service.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification))
+	_, err = service.OverrideDriverStatus(ctx, driver.ID, entities.StatusBlocked, "fraud check", "admin-1")
+	require.NoError(t, err)
+
+	driver, err = service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	driver.Phone = "+79009999999"
+	driver.CurrentRating = 4.8
+	_, err = service.UpdateDriver(ctx, driver)
+	require.NoError(t, err)
+	_, err = service.UpdateDriver(ctx, driver)
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteDriver(ctx, driver.ID))
+
+	entries, err := auditService.ListEntries(ctx, &entities.AuditFilters{DriverID: &driver.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 4, "saving without changes is not recorded")
+
+	deletion, update, override, status := entries[0], entries[1], entries[2], entries[3]
+	assert.Equal(t, entities.AuditEventDriverStatusOverride, override.Event)
+	assert.Equal(t, entities.Metadata{"status": entities.StatusPendingVerification}, override.Before)
+	assert.Equal(t, entities.Metadata{"status": entities.StatusBlocked}, override.After)
+	assert.Equal(t, "fraud check", override.Details["reason"])
+	assert.Equal(t, "admin-1", override.Details["actor_id"])
+
+	assert.Equal(t, entities.AuditEventDriverStatus, status.Event)
+	assert.Equal(t, entities.Metadata{"status": entities.StatusRegistered}, status.Before)
+	assert.Equal(t, entities.Metadata{"status": entities.StatusPendingVerification}, status.After)
+	require.NotNil(t, status.Actor)
+	assert.Equal(t, "admin-1", *status.Actor)
+	assert.Equal(t, "admin", *status.ActorRoles)
+	assert.Equal(t, "req-1", *status.RequestID)
+
+	assert.Equal(t, entities.AuditEventDriverUpdate, update.Event)
+	assert.Equal(t, entities.AuditRedacted, update.After["phone"], "personal data is not kept in the log")
+	assert.Equal(t, 0.0, update.Before["current_rating"])
+	assert.Equal(t, 4.8, update.After["current_rating"])
+
+	assert.Equal(t, entities.AuditEventDriverDeletion, deletion.Event)
+	assert.Equal(t, true, deletion.After["deleted"])
+
+	actor, event := "admin-1", entities.AuditEventDriverUpdate
+	found, err := auditService.ListEntries(ctx, &entities.AuditFilters{Actor: &actor, Event: &event, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, update.ID, found[0].ID)
+}
@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverInvitePolicy параметры приглашений водителей
+type DriverInvitePolicy struct {
+	// Secret ключ подписи токенов приглашений; пустой — приглашения отключены
+	Secret string
+	// LinkBaseURL адрес ссылки для приложения водителя, к которому добавляется параметр token
+	LinkBaseURL string
+	// DefaultTTL срок действия приглашения, выпущенного без своего
+	DefaultTTL time.Duration
+	// MaxTTL наибольший срок действия приглашения
+	MaxTTL time.Duration
+}
+
+// DriverInviteService интерфейс приглашений водителей на самостоятельную регистрацию
+type DriverInviteService interface {
+	// CreateInvite выпускает приглашение в автопарк и подписанную ссылку для водителя
+	CreateInvite(ctx context.Context, req *entities.CreateDriverInviteRequest) (*entities.DriverInviteLink, error)
+	// ListInvites возвращает приглашения, новые первыми
+	ListInvites(ctx context.Context, limit, offset int) ([]*entities.DriverInvite, error)
+	// RevokeInvite отзывает неиспользованное приглашение
+	RevokeInvite(ctx context.Context, id uuid.UUID, actorID string) (*entities.DriverInvite, error)
+	// Register регистрирует водителя по токену приглашения; дальше водитель проходит
+	// обычную проверку документов
+	Register(ctx context.Context, token string, req *entities.DriverRegistrationRequest) (*entities.Driver, error)
+}
+
+// driverInviteService реализация DriverInviteService
+type driverInviteService struct {
+	inviteRepo    repositories.DriverInviteRepository
+	fleetRepo     repositories.FleetRepository
+	driverRepo    repositories.DriverRepository
+	driverService DriverService
+	txManager     repositories.TxManager
+	policy        DriverInvitePolicy
+	logger        *zap.Logger
+}
+
+// NewDriverInviteService создает новый DriverInviteService. txManager делает атомарными
+// создание водителя и использование приглашения; nil — без транзакций (хранение в памяти)
+func NewDriverInviteService(
+	inviteRepo repositories.DriverInviteRepository,
+	fleetRepo repositories.FleetRepository,
+	driverRepo repositories.DriverRepository,
+	driverService DriverService,
+	txManager repositories.TxManager,
+	policy DriverInvitePolicy,
+	logger *zap.Logger,
+) DriverInviteService {
+	return &driverInviteService{
+		inviteRepo:    inviteRepo,
+		fleetRepo:     fleetRepo,
+		driverRepo:    driverRepo,
+		driverService: driverService,
+		txManager:     txManager,
+		policy:        policy,
+		logger:        logger,
+	}
+}
+
+// CreateInvite проверяет автопарк и то, что телефон еще не занят водителем, и сохраняет
+// приглашение. Токен не хранится: он выпускается из приглашения и подписи
+func (s *driverInviteService) CreateInvite(ctx context.Context, req *entities.CreateDriverInviteRequest) (*entities.DriverInviteLink, error) {
+	if s.policy.Secret == "" {
+		return nil, entities.ErrDriverInvitesDisabled
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.policy.DefaultTTL)
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	invite, err := entities.NewDriverInvite(req, expiresAt, s.policy.MaxTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.fleetRepo.GetByID(ctx, invite.FleetID); err != nil {
+		return nil, err
+	}
+	exists, err := s.driverRepo.Exists(ctx, invite.Phone, "", "")
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, entities.ErrDriverExists
+	}
+
+	if err := s.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver invite created",
+		zap.String("invite_id", invite.ID.String()),
+		zap.String("fleet_id", invite.FleetID.String()),
+		zap.String("created_by", invite.CreatedBy),
+		zap.Time("expires_at", invite.ExpiresAt),
+	)
+
+	return s.link(invite), nil
+}
+
+// link выпускает токен и ссылку приглашения
+func (s *driverInviteService) link(invite *entities.DriverInvite) *entities.DriverInviteLink {
+	token := entities.SignDriverInviteToken([]byte(s.policy.Secret), invite)
+	link := s.policy.LinkBaseURL
+	if link != "" {
+		if parsed, err := url.Parse(link); err == nil {
+			query := parsed.Query()
+			query.Set("token", token)
+			parsed.RawQuery = query.Encode()
+			link = parsed.String()
+		}
+	}
+	return &entities.DriverInviteLink{DriverInvite: invite, Token: token, Link: link}
+}
+
+// ListInvites получает приглашения
+func (s *driverInviteService) ListInvites(ctx context.Context, limit, offset int) ([]*entities.DriverInvite, error) {
+	return s.inviteRepo.List(ctx, limit, offset)
+}
+
+// RevokeInvite отзывает приглашение; ссылка перестает действовать сразу
+func (s *driverInviteService) RevokeInvite(ctx context.Context, id uuid.UUID, actorID string) (*entities.DriverInvite, error) {
+	invite, err := s.inviteRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := invite.Revoke(actorID, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.inviteRepo.Revoke(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver invite revoked",
+		zap.String("invite_id", invite.ID.String()),
+		zap.String("revoked_by", actorID),
+	)
+
+	return invite, nil
+}
+
+// Register проверяет подпись токена и состояние приглашения и создает водителя с телефоном
+// и автопарком из приглашения. Водитель создается в статусе registered, как при обычной
+// регистрации. Приглашение используется в одной транзакции с созданием водителя, поэтому
+// повторная или параллельная регистрация по той же ссылке не создает второго водителя
+func (s *driverInviteService) Register(ctx context.Context, token string, req *entities.DriverRegistrationRequest) (*entities.Driver, error) {
+	if s.policy.Secret == "" {
+		return nil, entities.ErrDriverInvitesDisabled
+	}
+
+	now := time.Now()
+	inviteID, err := entities.ParseDriverInviteToken([]byte(s.policy.Secret), token, now)
+	if err != nil {
+		return nil, err
+	}
+	invite, err := s.inviteRepo.GetByID(ctx, inviteID)
+	if err == entities.ErrDriverInviteNotFound {
+		return nil, entities.ErrInvalidInviteToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := invite.CheckUsable(now); err != nil {
+		return nil, err
+	}
+
+	var driver *entities.Driver
+	err = repositories.WithTransaction(ctx, s.txManager, func(ctx context.Context) error {
+		created, err := s.driverService.CreateDriver(ctx, req.Driver(invite))
+		if err != nil {
+			return err
+		}
+		if err := invite.Accept(created.ID, now); err != nil {
+			return err
+		}
+		if err := s.inviteRepo.Accept(ctx, invite); err != nil {
+			return err
+		}
+		driver = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver registered by invite",
+		zap.String("invite_id", invite.ID.String()),
+		zap.String("driver_id", driver.ID.String()),
+		zap.String("fleet_id", invite.FleetID.String()),
+	)
+
+	return driver, nil
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestDriverInviteService(t *testing.T, policy DriverInvitePolicy) (DriverInviteService, *memory.DriverRepository, *entities.Fleet) {
+	drivers, driverRepo, _, _ := newTestDriverService()
+	fleetRepo := memory.NewFleetRepository()
+	fleet := entities.NewFleet(&entities.FleetRequest{Name: "Автопарк Север"})
+	require.NoError(t, fleetRepo.Create(context.Background(), fleet))
+
+	service := NewDriverInviteService(memory.NewDriverInviteRepository(), fleetRepo, driverRepo, drivers, nil, policy, zap.NewNop())
+	return service, driverRepo, fleet
+}
+
+func testInvitePolicy() DriverInvitePolicy {
+	return DriverInvitePolicy{
+		Secret:      "invite-secret",
+		LinkBaseURL: "driverapp://register?source=invite",
+		DefaultTTL:  7 * 24 * time.Hour,
+		MaxTTL:      30 * 24 * time.Hour,
+	}
+}
+
+func TestDriverInviteService_Register(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, fleet := newTestDriverInviteService(t, testInvitePolicy())
+
+	link, err := service.CreateInvite(ctx, &entities.CreateDriverInviteRequest{
+		Phone:     " +79001112233 ",
+		FleetID:   fleet.ID,
+		CreatedBy: "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "+79001112233", link.Phone)
+	assert.Equal(t, entities.DriverInviteStatusPending, link.Status)
+	assert.WithinDuration(t, time.Now().Add(7*24*time.Hour), link.ExpiresAt, time.Minute)
+
+	parsed, err := url.Parse(link.Link)
+	require.NoError(t, err)
+	assert.Equal(t, link.Token, parsed.Query().Get("token"))
+	assert.Equal(t, "invite", parsed.Query().Get("source"))
+
+	driver, err := service.Register(ctx, link.Token, &entities.DriverRegistrationRequest{
+		FirstName: "Петр",
+		LastName:  "Иванов",
+		Email:     "petr@example.com",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusRegistered, driver.Status)
+	assert.Equal(t, "+79001112233", driver.Phone)
+	require.NotNil(t, driver.FleetID)
+	assert.Equal(t, fleet.ID, *driver.FleetID)
+	assert.Equal(t, link.ID.String(), driver.Metadata[entities.InviteMetadataKey])
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Петр", stored.FirstName)
+
+	// Ссылка одноразовая
+	_, err = service.Register(ctx, link.Token, &entities.DriverRegistrationRequest{FirstName: "Петр", LastName: "Иванов"})
+	assert.Equal(t, entities.ErrDriverInviteUsed, err)
+
+	invites, err := service.ListInvites(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Equal(t, entities.DriverInviteStatusAccepted, invites[0].Status)
+	require.NotNil(t, invites[0].DriverID)
+	assert.Equal(t, driver.ID, *invites[0].DriverID)
+
+	// Телефон уже занят зарегистрированным водителем
+	_, err = service.CreateInvite(ctx, &entities.CreateDriverInviteRequest{Phone: "+79001112233", FleetID: fleet.ID, CreatedBy: "admin-1"})
+	assert.Equal(t, entities.ErrDriverExists, err)
+}
+
+func TestDriverInviteService_RejectsInvalidInvites(t *testing.T) {
+	ctx := context.Background()
+	service, _, fleet := newTestDriverInviteService(t, testInvitePolicy())
+
+	tooLate := time.Now().Add(31 * 24 * time.Hour)
+	past := time.Now().Add(-time.Minute)
+	requests := []*entities.CreateDriverInviteRequest{
+		{Phone: " ", FleetID: fleet.ID, CreatedBy: "admin-1"},
+		{Phone: "+79001112233", FleetID: fleet.ID},
+		{Phone: "+79001112233", FleetID: fleet.ID, CreatedBy: "admin-1", ExpiresAt: &tooLate},
+		{Phone: "+79001112233", FleetID: fleet.ID, CreatedBy: "admin-1", ExpiresAt: &past},
+	}
+	for _, req := range requests {
+		_, err := service.CreateInvite(ctx, req)
+		assert.Equal(t, entities.ErrInvalidDriverInvite, err)
+	}
+
+	_, err := service.CreateInvite(ctx, &entities.CreateDriverInviteRequest{Phone: "+79001112233", FleetID: uuid.New(), CreatedBy: "admin-1"})
+	assert.Equal(t, entities.ErrFleetNotFound, err)
+}
+
+func TestDriverInviteService_RejectsUnusableTokens(t *testing.T) {
+	ctx := context.Background()
+	service, _, fleet := newTestDriverInviteService(t, testInvitePolicy())
+	req := &entities.DriverRegistrationRequest{FirstName: "Петр", LastName: "Иванов"}
+
+	link, err := service.CreateInvite(ctx, &entities.CreateDriverInviteRequest{Phone: "+79001112233", FleetID: fleet.ID, CreatedBy: "admin-1"})
+	require.NoError(t, err)
+
+	// Подпись другим ключом и поврежденный токен
+	other, _, _ := newTestDriverInviteService(t, DriverInvitePolicy{Secret: "other", DefaultTTL: time.Hour, MaxTTL: time.Hour})
+	_, err = other.Register(ctx, link.Token, req)
+	assert.Equal(t, entities.ErrInvalidInviteToken, err)
+	_, err = service.Register(ctx, link.Token+"x", req)
+	assert.Equal(t, entities.ErrInvalidInviteToken, err)
+
+	revoked, err := service.RevokeInvite(ctx, link.ID, "admin-2")
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverInviteStatusRevoked, revoked.Status)
+	_, err = service.Register(ctx, link.Token, req)
+	assert.Equal(t, entities.ErrDriverInviteRevoked, err)
+	_, err = service.RevokeInvite(ctx, link.ID, "admin-2")
+	assert.Equal(t, entities.ErrDriverInviteRevoked, err)
+
+	disabled, _, _ := newTestDriverInviteService(t, DriverInvitePolicy{})
+	_, err = disabled.Register(ctx, link.Token, req)
+	assert.Equal(t, entities.ErrDriverInvitesDisabled, err)
+	_, err = disabled.CreateInvite(ctx, &entities.CreateDriverInviteRequest{Phone: "+79001112233", FleetID: fleet.ID, CreatedBy: "admin-1"})
+	assert.Equal(t, entities.ErrDriverInvitesDisabled, err)
+}
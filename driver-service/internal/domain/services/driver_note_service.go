@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverNoteService интерфейс для внутренних заметок сотрудников о водителях
+type DriverNoteService interface {
+	// AddNote добавляет заметку о водителе от автора из контекста (entities.WithAuditActor)
+	AddNote(ctx context.Context, driverID uuid.UUID, req *entities.DriverNoteRequest) (*entities.DriverNote, error)
+	// ListNotes возвращает заметки водителя, новые первыми
+	ListNotes(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.DriverNote, error)
+}
+
+// driverNoteService реализация DriverNoteService
+type driverNoteService struct {
+	driverRepo repositories.DriverRepository
+	noteRepo   repositories.DriverNoteRepository
+	auditRepo  repositories.AuditRepository
+	logger     *zap.Logger
+}
+
+// NewDriverNoteService создает новый DriverNoteService
+func NewDriverNoteService(
+	driverRepo repositories.DriverRepository,
+	noteRepo repositories.DriverNoteRepository,
+	auditRepo repositories.AuditRepository,
+	logger *zap.Logger,
+) DriverNoteService {
+	return &driverNoteService{
+		driverRepo: driverRepo,
+		noteRepo:   noteRepo,
+		auditRepo:  auditRepo,
+		logger:     logger,
+	}
+}
+
+// AddNote сохраняет заметку и записывает в журнал аудита ссылку на нее. Текст заметки в
+// журнал не попадает: журнал неизменяем и переживает удаление персональных данных
+func (s *driverNoteService) AddNote(ctx context.Context, driverID uuid.UUID, req *entities.DriverNoteRequest) (*entities.DriverNote, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, _ := entities.AuditActorFromContext(ctx)
+	note := entities.NewDriverNote(driverID, req.Body, actor, time.Now())
+	if err := s.noteRepo.Create(ctx, note); err != nil {
+		return nil, err
+	}
+
+	entry := entities.NewAuditEntry(entities.AuditEventDriverNote, "create", "completed")
+	entry.DriverID = &driver.ID
+	entry.FleetID = driver.FleetID
+	entry.AttachActor(ctx)
+	entry.Details["note_id"] = note.ID.String()
+	// Ошибка журнала не отменяет сохраненную заметку
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record driver note audit entry",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("note_id", note.ID.String()),
+		)
+	}
+
+	s.logger.Info("Driver note added",
+		zap.String("driver_id", driverID.String()),
+		zap.String("note_id", note.ID.String()),
+	)
+
+	return note, nil
+}
+
+// ListNotes получает заметки существующего водителя
+func (s *driverNoteService) ListNotes(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.DriverNote, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return s.noteRepo.ListByDriver(ctx, driverID, limit, offset)
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDriverNoteService(t *testing.T) {
+	drivers, driverRepo, _, _ := newTestDriverService()
+	auditRepo := memory.NewAuditRepository()
+	service := NewDriverNoteService(driverRepo, memory.NewDriverNoteRepository(), auditRepo, zap.NewNop())
+
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{
+		Subject:   "support-7",
+		Roles:     "dispatcher",
+		RequestID: "req-1",
+	})
+	driver, err := drivers.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+
+	first, err := service.AddNote(ctx, driver.ID, &entities.DriverNoteRequest{Body: "  Жалоба пассажира на опоздание  "})
+	require.NoError(t, err)
+	assert.Equal(t, "Жалоба пассажира на опоздание", first.Body)
+	require.NotNil(t, first.Author)
+	assert.Equal(t, "support-7", *first.Author)
+	assert.Equal(t, "dispatcher", *first.AuthorRoles)
+
+	second, err := service.AddNote(ctx, driver.ID, &entities.DriverNoteRequest{Body: "Проведена беседа"})
+	require.NoError(t, err)
+
+	for _, body := range []string{"   ", strings.Repeat("a", entities.MaxDriverNoteLength+1)} {
+		_, err = service.AddNote(ctx, driver.ID, &entities.DriverNoteRequest{Body: body})
+		assert.Equal(t, entities.ErrInvalidDriverNote, err)
+	}
+	_, err = service.AddNote(ctx, uuid.New(), &entities.DriverNoteRequest{Body: "Нет такого водителя"})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	notes, err := service.ListNotes(ctx, driver.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, second.ID, notes[0].ID, "newest first")
+	notes, err = service.ListNotes(ctx, driver.ID, 1, 1)
+	require.NoError(t, err)
+	require.Len(t, notes, 1)
+	assert.Equal(t, first.ID, notes[0].ID)
+
+	// Журнал ссылается на заметку, но не хранит ее текст
+	event := entities.AuditEventDriverNote
+	entries, err := auditRepo.List(ctx, &entities.AuditFilters{DriverID: &driver.ID, Event: &event, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.ElementsMatch(t, []interface{}{first.ID.String(), second.ID.String()},
+		[]interface{}{entries[0].Details["note_id"], entries[1].Details["note_id"]})
+	assert.NotContains(t, entries[0].Details, "body")
+	require.NotNil(t, entries[0].RequestID)
+	assert.Equal(t, "req-1", *entries[0].RequestID)
+
+	_, err = service.ListNotes(ctx, uuid.New(), 10, 0)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
@@ -23,17 +23,22 @@ type DriverService interface {
 	ListDrivers(ctx context.Context, filters *entities.DriverFilters) ([]*entities.Driver, error)
 	CountDrivers(ctx context.Context, filters *entities.DriverFilters) (int, error)
 	ChangeDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status) error
+	// OverrideDriverStatus устанавливает статус в обход правил переходов; для администраторов
+	OverrideDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status, reason, actorID string) (*entities.StatusOverride, error)
 	UpdateDriverRating(ctx context.Context, id uuid.UUID, rating float64) error
 	IncrementTripCount(ctx context.Context, id uuid.UUID) error
 	GetActiveDrivers(ctx context.Context) ([]*entities.Driver, error)
 	IsDriverAvailable(ctx context.Context, id uuid.UUID) (bool, error)
 	ValidateDriverForOrder(ctx context.Context, id uuid.UUID) error
+	PlacePaymentHold(ctx context.Context, id uuid.UUID, reason string) error
+	ReleasePaymentHold(ctx context.Context, id uuid.UUID) error
 }
 
 // driverService реализация DriverService
 type driverService struct {
 	driverRepo   repositories.DriverRepository
 	documentRepo repositories.DocumentRepository
+	scheduleRepo repositories.ScheduleRepository
 	logger       *zap.Logger
 	eventBus     EventPublisher // Интерфейс для публикации событий
 }
@@ -43,16 +48,19 @@ type EventPublisher interface {
 	PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error
 }
 
-// NewDriverService создает новый DriverService
+// NewDriverService создает новый DriverService.
+// scheduleRepo может быть nil, если расписания доступности не учитываются.
 func NewDriverService(
 	driverRepo repositories.DriverRepository,
 	documentRepo repositories.DocumentRepository,
+	scheduleRepo repositories.ScheduleRepository,
 	eventBus EventPublisher,
 	logger *zap.Logger,
 ) DriverService {
 	return &driverService{
 		driverRepo:   driverRepo,
 		documentRepo: documentRepo,
+		scheduleRepo: scheduleRepo,
 		eventBus:     eventBus,
 		logger:       logger,
 	}
@@ -65,8 +73,9 @@ func (s *driverService) CreateDriver(ctx context.Context, driver *entities.Drive
 		zap.String("email", driver.Email),
 	)
 
-	// Валидация входных данных
-	if err := driver.Validate(); err != nil {
+	// При регистрации обязательны только данные этапа регистрации,
+	// остальной профиль водитель заполняет позже
+	if err := driver.ValidateForStage(entities.ProfileStageRegistration); err != nil {
 		s.logger.Error("Driver validation failed",
 			zap.Error(err),
 			zap.String("phone", driver.Phone),
@@ -74,8 +83,9 @@ func (s *driverService) CreateDriver(ctx context.Context, driver *entities.Drive
 		return nil, fmt.Errorf("driver validation failed: %w", err)
 	}
 
-	// Проверяем, не существует ли уже водитель с таким телефоном или лицензией
-	exists, err := s.driverRepo.Exists(ctx, driver.Phone, driver.LicenseNumber)
+	// Проверяем, не существует ли уже водитель с таким телефоном, email или лицензией.
+	// Удаленные водители не учитываются: вернувшийся водитель регистрируется заново
+	exists, err := s.driverRepo.Exists(ctx, driver.Phone, driver.Email, driver.LicenseNumber)
 	if err != nil {
 		s.logger.Error("Failed to check driver existence",
 			zap.Error(err),
@@ -105,6 +115,20 @@ func (s *driverService) CreateDriver(ctx context.Context, driver *entities.Drive
 		driver.Metadata = make(entities.Metadata)
 	}
 
+	// Номер удаленного водителя привязывается к новой записи; история удаленной записи
+	// (документы, поездки, начисления) к новой не переносится
+	previous, err := s.driverRepo.GetDeletedByPhone(ctx, driver.Phone)
+	switch {
+	case err == nil:
+		driver.Metadata[entities.PreviousDriverMetadataKey] = previous.ID.String()
+		s.logger.Info("Driver re-registered with returning phone",
+			zap.String("driver_id", driver.ID.String()),
+			zap.String("previous_driver_id", previous.ID.String()),
+		)
+	case err != entities.ErrDriverNotFound:
+		return nil, fmt.Errorf("failed to check deleted driver: %w", err)
+	}
+
 	// Создаем водителя в базе данных
 	if err := s.driverRepo.Create(ctx, driver); err != nil {
 		s.logger.Error("Failed to create driver",
@@ -121,8 +145,11 @@ func (s *driverService) CreateDriver(ctx context.Context, driver *entities.Drive
 		"name":           driver.GetFullName(),
 		"license_number": driver.LicenseNumber,
 	}
+	if previous != nil {
+		eventData["previous_driver_id"] = previous.ID.String()
+	}
 
-	if err := s.eventBus.PublishDriverEvent(ctx, "driver.registered", driver.ID, eventData); err != nil {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverRegistered, driver.ID, eventData); err != nil {
 		s.logger.Error("Failed to publish driver registered event",
 			zap.Error(err),
 			zap.String("driver_id", driver.ID.String()),
@@ -186,8 +213,14 @@ func (s *driverService) UpdateDriver(ctx context.Context, driver *entities.Drive
 		zap.String("driver_id", driver.ID.String()),
 	)
 
-	// Валидация входных данных
-	if err := driver.Validate(); err != nil {
+	// Проверяем, существует ли водитель
+	existing, err := s.driverRepo.GetByID(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Проверяем только требования этапа, соответствующего текущему статусу
+	if err := driver.ValidateForStage(entities.StageForStatus(existing.Status)); err != nil {
 		s.logger.Error("Driver validation failed",
 			zap.Error(err),
 			zap.String("driver_id", driver.ID.String()),
@@ -195,14 +228,28 @@ func (s *driverService) UpdateDriver(ctx context.Context, driver *entities.Drive
 		return nil, fmt.Errorf("driver validation failed: %w", err)
 	}
 
-	// Проверяем, существует ли водитель
-	existing, err := s.driverRepo.GetByID(ctx, driver.ID)
-	if err != nil {
-		return nil, err
+	// Номер лицензии можно только заполнить: смена уже указанного номера требует повторной верификации
+	if driver.LicenseNumber != existing.LicenseNumber {
+		if existing.LicenseNumber != "" {
+			return nil, entities.ErrInvalidLicense
+		}
+
+		exists, err := s.driverRepo.Exists(ctx, "", "", driver.LicenseNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check driver existence: %w", err)
+		}
+		if exists {
+			return nil, entities.ErrDriverExists
+		}
 	}
 
 	// Сохраняем некоторые поля, которые не должны изменяться через Update
 	driver.CreatedAt = existing.CreatedAt
+	// Подтверждение телефона сохраняется, пока номер не изменился
+	driver.PhoneVerifiedAt = nil
+	if driver.Phone == existing.Phone {
+		driver.PhoneVerifiedAt = existing.PhoneVerifiedAt
+	}
 	driver.UpdatedAt = time.Now()
 
 	// Обновляем водителя в базе данных
@@ -247,7 +294,7 @@ func (s *driverService) DeleteDriver(ctx context.Context, id uuid.UUID) error {
 		"reason": "account_deleted",
 	}
 
-	if err := s.eventBus.PublishDriverEvent(ctx, "driver.blocked", driver.ID, eventData); err != nil {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverBlocked, driver.ID, eventData); err != nil {
 		s.logger.Error("Failed to publish driver blocked event",
 			zap.Error(err),
 			zap.String("driver_id", driver.ID.String()),
@@ -294,6 +341,11 @@ func (s *driverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, st
 		zap.String("new_status", string(status)),
 	)
 
+	// Блокировка требует кода причины и сохраняется в истории: см. BlockService
+	if status == entities.StatusBlocked {
+		return entities.ErrBlockRequiresReason
+	}
+
 	// Получаем текущего водителя
 	driver, err := s.driverRepo.GetByID(ctx, id)
 	if err != nil {
@@ -303,7 +355,7 @@ func (s *driverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, st
 	oldStatus := driver.Status
 
 	// Проверяем валидность перехода статуса
-	if err := s.validateStatusTransition(oldStatus, status); err != nil {
+	if err := validateStatusTransition(oldStatus, status); err != nil {
 		s.logger.Error("Invalid status transition",
 			zap.Error(err),
 			zap.String("driver_id", id.String()),
@@ -313,6 +365,16 @@ func (s *driverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, st
 		return err
 	}
 
+	// Для нового статуса профиль должен удовлетворять требованиям соответствующего этапа
+	if err := driver.ValidateForStage(entities.StageForStatus(status)); err != nil {
+		s.logger.Warn("Driver profile is incomplete for status",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+			zap.String("to_status", string(status)),
+		)
+		return entities.ErrProfileIncomplete
+	}
+
 	// Обновляем статус
 	if err := s.driverRepo.UpdateStatus(ctx, id, status); err != nil {
 		s.logger.Error("Failed to update driver status",
@@ -329,7 +391,7 @@ func (s *driverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, st
 		"changed_by": "system", // В реальном приложении здесь должен быть ID пользователя
 	}
 
-	if err := s.eventBus.PublishDriverEvent(ctx, "driver.status.changed", id, eventData); err != nil {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverStatusChanged, id, eventData); err != nil {
 		s.logger.Error("Failed to publish driver status changed event",
 			zap.Error(err),
 			zap.String("driver_id", id.String()),
@@ -345,6 +407,62 @@ func (s *driverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, st
 	return nil
 }
 
+// OverrideDriverStatus устанавливает статус водителя в обход правил переходов и требований
+// к профилю. Последняя ручная смена сохраняется в метаданных водителя и публикуется
+// отдельным событием, чтобы подписчики отличали ее от обычной смены статуса
+func (s *driverService) OverrideDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status, reason, actorID string) (*entities.StatusOverride, error) {
+	override := entities.NewStatusOverride(id, status, reason, actorID)
+	if err := override.Validate(); err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if driver.Status == status {
+		return nil, entities.ErrStatusUnchanged
+	}
+	override.PreviousStatus = driver.Status
+
+	if driver.Metadata == nil {
+		driver.Metadata = make(entities.Metadata)
+	}
+	driver.Metadata[entities.StatusOverrideMetadataKey] = override.Metadata()
+	driver.ChangeStatus(status)
+
+	if err := s.driverRepo.Update(ctx, driver); err != nil {
+		s.logger.Error("Failed to override driver status",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to override driver status: %w", err)
+	}
+
+	eventData := map[string]interface{}{
+		"old_status": string(override.PreviousStatus),
+		"new_status": string(status),
+		"reason":     override.Reason,
+		"actor_id":   override.ActorID,
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverStatusOverridden, id, eventData); err != nil {
+		s.logger.Error("Failed to publish driver status overridden event",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+	}
+
+	s.logger.Warn("Driver status overridden",
+		zap.String("driver_id", id.String()),
+		zap.String("old_status", string(override.PreviousStatus)),
+		zap.String("new_status", string(status)),
+		zap.String("actor_id", override.ActorID),
+	)
+
+	return override, nil
+}
+
 // UpdateDriverRating обновляет рейтинг водителя
 func (s *driverService) UpdateDriverRating(ctx context.Context, id uuid.UUID, rating float64) error {
 	if rating < 0 || rating > 5 {
@@ -374,7 +492,7 @@ func (s *driverService) UpdateDriverRating(ctx context.Context, id uuid.UUID, ra
 		"previous_rating": oldRating,
 	}
 
-	if err := s.eventBus.PublishDriverEvent(ctx, "driver.rating.updated", id, eventData); err != nil {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverRatingUpdated, id, eventData); err != nil {
 		s.logger.Error("Failed to publish driver rating updated event",
 			zap.Error(err),
 			zap.String("driver_id", id.String()),
@@ -394,14 +512,17 @@ func (s *driverService) GetActiveDrivers(ctx context.Context) ([]*entities.Drive
 	return s.driverRepo.GetActiveDrivers(ctx)
 }
 
-// IsDriverAvailable проверяет доступность водителя
+// IsDriverAvailable проверяет доступность водителя с учетом его расписания
 func (s *driverService) IsDriverAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
 	driver, err := s.driverRepo.GetByID(ctx, id)
 	if err != nil {
 		return false, err
 	}
 
-	return driver.CanReceiveOrders(), nil
+	if !driver.CanReceiveOrders() {
+		return false, nil
+	}
+	return withinSchedule(ctx, s.scheduleRepo, id, time.Now())
 }
 
 // ValidateDriverForOrder проверяет, может ли водитель получить заказ
@@ -411,10 +532,20 @@ func (s *driverService) ValidateDriverForOrder(ctx context.Context, id uuid.UUID
 		return err
 	}
 
-	// Проверяем статус водителя
+	// Водитель с блокировкой от биллинга не получает заказы
+	if driver.PaymentHold {
+		return entities.ErrDriverPaymentHold
+	}
+
+	// Проверяем статус водителя и окно расписания
 	if !driver.CanReceiveOrders() {
 		return entities.ErrDriverNotAvailable
 	}
+	if available, err := withinSchedule(ctx, s.scheduleRepo, id, time.Now()); err != nil {
+		return err
+	} else if !available {
+		return entities.ErrDriverNotAvailable
+	}
 
 	// Проверяем, не истекла ли лицензия
 	if driver.IsLicenseExpired() {
@@ -442,34 +573,102 @@ func (s *driverService) ValidateDriverForOrder(ctx context.Context, id uuid.UUID
 	return nil
 }
 
+// PlacePaymentHold устанавливает блокировку водителя по событию биллинга
+func (s *driverService) PlacePaymentHold(ctx context.Context, id uuid.UUID, reason string) error {
+	driver, err := s.driverRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	normalized := entities.NormalizePaymentHoldReason(reason)
+	if driver.PaymentHold && driver.PaymentHoldReason != nil && *driver.PaymentHoldReason == normalized {
+		// Повторная доставка события
+		return nil
+	}
+
+	if err := s.driverRepo.UpdatePaymentHold(ctx, id, true, &normalized); err != nil {
+		s.logger.Error("Failed to place payment hold",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return fmt.Errorf("failed to place payment hold: %w", err)
+	}
+
+	eventData := map[string]interface{}{
+		"reason": normalized,
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventPaymentHoldPlaced, id, eventData); err != nil {
+		s.logger.Error("Failed to publish payment hold placed event",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+	}
+
+	s.logger.Info("Driver payment hold placed",
+		zap.String("driver_id", id.String()),
+		zap.String("reason", normalized),
+	)
+
+	return nil
+}
+
+// ReleasePaymentHold снимает блокировку водителя по событию биллинга
+func (s *driverService) ReleasePaymentHold(ctx context.Context, id uuid.UUID) error {
+	driver, err := s.driverRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !driver.PaymentHold {
+		return nil
+	}
+
+	if err := s.driverRepo.UpdatePaymentHold(ctx, id, false, nil); err != nil {
+		s.logger.Error("Failed to release payment hold",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return fmt.Errorf("failed to release payment hold: %w", err)
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventPaymentHoldReleased, id, nil); err != nil {
+		s.logger.Error("Failed to publish payment hold released event",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+	}
+
+	s.logger.Info("Driver payment hold released",
+		zap.String("driver_id", id.String()),
+	)
+
+	return nil
+}
+
 // validateStatusTransition проверяет валидность перехода между статусами
-func (s *driverService) validateStatusTransition(from, to entities.Status) error {
+func validateStatusTransition(from, to entities.Status) error {
 	// Разрешенные переходы между статусами
 	allowedTransitions := map[entities.Status][]entities.Status{
 		entities.StatusRegistered: {
 			entities.StatusPendingVerification,
-			entities.StatusBlocked,
 		},
 		entities.StatusPendingVerification: {
 			entities.StatusVerified,
 			entities.StatusRejected,
 			entities.StatusRegistered,
-			entities.StatusBlocked,
 		},
 		entities.StatusVerified: {
 			entities.StatusAvailable,
 			entities.StatusSuspended,
-			entities.StatusBlocked,
 		},
 		entities.StatusRejected: {
 			entities.StatusPendingVerification,
-			entities.StatusBlocked,
 		},
 		entities.StatusAvailable: {
 			entities.StatusOnShift,
 			entities.StatusInactive,
 			entities.StatusSuspended,
-			entities.StatusBlocked,
 		},
 		entities.StatusOnShift: {
 			entities.StatusBusy,
@@ -485,17 +684,15 @@ func (s *driverService) validateStatusTransition(from, to entities.Status) error
 		entities.StatusInactive: {
 			entities.StatusAvailable,
 			entities.StatusSuspended,
-			entities.StatusBlocked,
 		},
 		entities.StatusSuspended: {
 			entities.StatusAvailable,
-			entities.StatusBlocked,
 		},
 	}
 
 	allowedStatuses, exists := allowedTransitions[from]
 	if !exists {
-		return fmt.Errorf("no transitions allowed from status: %s", from)
+		return fmt.Errorf("%w: no transitions allowed from status %s", entities.ErrInvalidStatusTransition, from)
 	}
 
 	for _, allowedStatus := range allowedStatuses {
@@ -504,5 +701,5 @@ func (s *driverService) validateStatusTransition(from, to entities.Status) error
 		}
 	}
 
-	return fmt.Errorf("invalid status transition from %s to %s", from, to)
+	return fmt.Errorf("%w from %s to %s", entities.ErrInvalidStatusTransition, from, to)
 }
\ No newline at end of file
@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingEventPublisher запоминает опубликованные события
+type recordingEventPublisher struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (p *recordingEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, eventType)
+	return nil
+}
+
+func (p *recordingEventPublisher) has(eventType string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range p.events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func newTestDriver(suffix string) *entities.Driver {
+	return &entities.Driver{
+		Phone:          "+7900000000" + suffix,
+		Email:          "driver" + suffix + "@example.com",
+		FirstName:      "Иван",
+		LastName:       "Тестовый",
+		BirthDate:      time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		PassportSeries: "1234",
+		PassportNumber: "56789" + suffix,
+		LicenseNumber:  "LIC" + suffix,
+		LicenseExpiry:  time.Now().AddDate(2, 0, 0),
+	}
+}
+
+func newTestDriverService() (DriverService, *memory.DriverRepository, *memory.DocumentRepository, *recordingEventPublisher) {
+	driverRepo := memory.NewDriverRepository()
+	documentRepo := memory.NewDocumentRepository()
+	events := &recordingEventPublisher{}
+	return NewDriverService(driverRepo, documentRepo, nil, events, zap.NewNop()), driverRepo, documentRepo, events
+}
+
+func TestDriverService_CreateDriver(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, events := newTestDriverService()
+
+	created, err := service.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, created.ID)
+	assert.Equal(t, entities.StatusRegistered, created.Status)
+	assert.True(t, events.has("driver.registered"))
+
+	fetched, err := service.GetDriverByPhone(ctx, created.Phone)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, fetched.ID)
+
+	_, err = service.CreateDriver(ctx, newTestDriver("1"))
+	assert.Equal(t, entities.ErrDriverExists, err)
+}
+
+func TestDriverService_ChangeDriverStatus(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, events := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("2"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), driver.StatusVersion)
+
+	err = service.ChangeDriverStatus(ctx, driver.ID, entities.StatusAvailable)
+	assert.Error(t, err, "registered -> available is not allowed")
+
+	require.NoError(t, service.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification))
+	assert.True(t, events.has("driver.status.changed"))
+
+	updated, err := service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusPendingVerification, updated.Status)
+	assert.Equal(t, int64(2), updated.StatusVersion, "only applied status changes bump the version")
+
+	// Изменение профиля с устаревшей версией в памяти не откатывает версию статуса
+	driver.FirstName = "Петр"
+	driver.Status = entities.StatusPendingVerification
+	_, err = service.UpdateDriver(ctx, driver)
+	require.NoError(t, err)
+	updated, err = service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated.StatusVersion)
+}
+
+func TestDriverService_OverrideDriverStatus(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, events := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("9"))
+	require.NoError(t, err)
+
+	_, err = service.OverrideDriverStatus(ctx, driver.ID, entities.StatusAvailable, "  ", "admin-1")
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusOverride, "a reason is required")
+	_, err = service.OverrideDriverStatus(ctx, driver.ID, entities.StatusAvailable, "appeal", "")
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusOverride, "an actor is required")
+	_, err = service.OverrideDriverStatus(ctx, driver.ID, "unknown", "appeal", "admin-1")
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusOverride)
+	_, err = service.OverrideDriverStatus(ctx, driver.ID, entities.StatusRegistered, "appeal", "admin-1")
+	assert.ErrorIs(t, err, entities.ErrStatusUnchanged)
+
+	// registered -> available недопустим для обычной смены, но доступен администратору
+	override, err := service.OverrideDriverStatus(ctx, driver.ID, entities.StatusAvailable, " appeal approved ", "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusRegistered, override.PreviousStatus)
+	assert.Equal(t, "appeal approved", override.Reason)
+	assert.True(t, events.has("driver.status.overridden"))
+	assert.False(t, events.has("driver.status.changed"))
+
+	updated, err := service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusAvailable, updated.Status)
+	recorded, ok := updated.Metadata[entities.StatusOverrideMetadataKey].(entities.Metadata)
+	require.True(t, ok)
+	assert.Equal(t, "admin-1", recorded["actor_id"])
+	assert.Equal(t, entities.StatusRegistered, recorded["previous_status"])
+}
+
+func TestDriverService_ValidateDriverForOrder(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, documentRepo, _ := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("3"))
+	require.NoError(t, err)
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusAvailable))
+
+	err = service.ValidateDriverForOrder(ctx, driver.ID)
+	assert.Equal(t, entities.ErrDocumentNotVerified, err)
+
+	document := entities.NewDriverDocument(driver.ID, entities.DocumentTypeDriverLicense, "77AA123456",
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(1, 0, 0), "https://example.com/license.pdf")
+	require.NoError(t, documentRepo.Create(ctx, document))
+	document.Verify("admin")
+	require.NoError(t, documentRepo.Update(ctx, document))
+
+	assert.NoError(t, service.ValidateDriverForOrder(ctx, driver.ID))
+}
+
+func TestDriverService_DeleteDriver(t *testing.T) {
+	ctx := context.Background()
+	service, _, _, events := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("4"))
+	require.NoError(t, err)
+
+	require.NoError(t, service.DeleteDriver(ctx, driver.ID))
+	assert.True(t, events.has("driver.blocked"))
+
+	_, err = service.GetDriverByID(ctx, driver.ID)
+	assert.True(t, errors.Is(err, entities.ErrDriverNotFound))
+}
+
+func TestDriverService_ReRegistration(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, _, _ := newTestDriverService()
+
+	first, err := service.CreateDriver(ctx, newTestDriver("5"))
+	require.NoError(t, err)
+	assert.NotContains(t, first.Metadata, entities.PreviousDriverMetadataKey)
+
+	// Email занят другим неудаленным водителем
+	duplicate := newTestDriver("6")
+	duplicate.Email = first.Email
+	_, err = service.CreateDriver(ctx, duplicate)
+	assert.Equal(t, entities.ErrDriverExists, err)
+
+	// Номер удаленного водителя привязывается к новой записи
+	require.NoError(t, service.DeleteDriver(ctx, first.ID))
+	second, err := service.CreateDriver(ctx, newTestDriver("5"))
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, second.ID)
+	assert.Equal(t, first.ID.String(), second.Metadata[entities.PreviousDriverMetadataKey])
+
+	fetched, err := service.GetDriverByPhone(ctx, second.Phone)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, fetched.ID)
+
+	deleted, err := driverRepo.GetDeletedByPhone(ctx, second.Phone)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, deleted.ID)
+}
+
+func TestDriverService_ListDrivers(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, _, _ := newTestDriverService()
+
+	for i, suffix := range []string{"5", "6", "7"} {
+		driver, err := service.CreateDriver(ctx, newTestDriver(suffix))
+		require.NoError(t, err)
+		require.NoError(t, driverRepo.UpdateRating(ctx, driver.ID, float64(i+3)))
+	}
+
+	minRating := 4.0
+	filters := &entities.DriverFilters{MinRating: &minRating, SortBy: "current_rating", SortDirection: "desc", Limit: 1}
+	drivers, err := service.ListDrivers(ctx, filters)
+	require.NoError(t, err)
+	require.Len(t, drivers, 1)
+	assert.Equal(t, 5.0, drivers[0].CurrentRating)
+
+	total, err := service.CountDrivers(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
+
+func TestDriverService_PaymentHold(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, _, events := newTestDriverService()
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("8"))
+	require.NoError(t, err)
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusAvailable))
+
+	require.NoError(t, service.PlacePaymentHold(ctx, driver.ID, "chargeback #42 on card *1234"))
+	assert.True(t, events.has("driver.payment_hold.placed"))
+
+	held, err := service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.True(t, held.PaymentHold)
+	require.NotNil(t, held.PaymentHoldReason)
+	assert.Equal(t, entities.PaymentHoldReasonOther, *held.PaymentHoldReason, "free-form details are not stored")
+	assert.False(t, held.CanReceiveOrders())
+	assert.Equal(t, entities.ErrDriverPaymentHold, service.ValidateDriverForOrder(ctx, driver.ID))
+
+	require.NoError(t, service.ReleasePaymentHold(ctx, driver.ID))
+	assert.True(t, events.has("driver.payment_hold.released"))
+
+	released, err := service.GetDriverByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.False(t, released.PaymentHold)
+	assert.Nil(t, released.PaymentHoldReason)
+	assert.True(t, released.CanReceiveOrders())
+
+	// Повторное снятие блокировки не является ошибкой
+	assert.NoError(t, service.ReleasePaymentHold(ctx, driver.ID))
+}
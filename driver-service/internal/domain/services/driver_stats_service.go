@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// statsShiftLookback насколько раньше начала месяца ищутся смены, захватывающие его начало
+const statsShiftLookback = 24 * time.Hour
+
+// DriverStatsService интерфейс для сводных показателей водителя
+type DriverStatsService interface {
+	// GetStatistics возвращает показатели водителя; результат кэшируется на StatsPolicy.CacheTTL
+	GetStatistics(ctx context.Context, driverID uuid.UUID) (*entities.DriverStatistics, error)
+	// GetDailyStatistics возвращает сохраненные суточные показатели водителя за [from, to)
+	GetDailyStatistics(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.DriverDailyStatsReport, error)
+	// RefreshDailyStats пересчитывает суточные показатели всех водителей за текущие сутки,
+	// StatsPolicy.DailyLookbackDays прошедших и пропущенные с прошлого пересчета. Возвращает
+	// число пересчитанных суток
+	RefreshDailyStats(ctx context.Context) (int, error)
+}
+
+// StatsPolicy параметры сводных показателей водителя
+type StatsPolicy struct {
+	// CacheTTL как долго показатели водителя не пересчитываются; 0 — считать при каждом запросе
+	CacheTTL time.Duration
+	// MaxGapInterval интервал между точками, после которого водитель считается не на связи
+	MaxGapInterval time.Duration
+	// SummaryTier уровень сводных точек, по которому считается пробег старше исходных точек;
+	// пусто — пробег только по исходным точкам
+	SummaryTier string
+	// DailyLookbackDays сколько прошедших суток пересчитывается каждый раз, чтобы учесть
+	// точки, смены и оценки, записанные с опозданием
+	DailyLookbackDays int
+	// DailyBackfillDays за сколько прошедших суток показатели считаются при первом пересчете
+	DailyBackfillDays int
+	// DispatchWindowDays окна в сутках, за которые считаются доли принятых и отмененных
+	// предложений заказов
+	DispatchWindowDays []int
+}
+
+// driverStatsService реализация DriverStatsService
+type driverStatsService struct {
+	driverRepo   repositories.DriverRepository
+	ratingRepo   repositories.RatingRepository
+	shiftRepo    repositories.ShiftRepository
+	documentRepo repositories.DocumentRepository
+	locationRepo repositories.LocationRepository
+	summaryRepo  repositories.LocationSummaryRepository
+	dailyRepo    repositories.DriverDailyStatsRepository
+	dispatchRepo repositories.DispatchRepository
+	policy       StatsPolicy
+	logger       *zap.Logger
+
+	cache *TTLCache[uuid.UUID, *entities.DriverStatistics]
+}
+
+// NewDriverStatsService создает новый DriverStatsService. Без dailyRepo пробег и время
+// на связи всегда считаются по исходным точкам
+func NewDriverStatsService(
+	driverRepo repositories.DriverRepository,
+	ratingRepo repositories.RatingRepository,
+	shiftRepo repositories.ShiftRepository,
+	documentRepo repositories.DocumentRepository,
+	locationRepo repositories.LocationRepository,
+	summaryRepo repositories.LocationSummaryRepository,
+	dailyRepo repositories.DriverDailyStatsRepository,
+	dispatchRepo repositories.DispatchRepository,
+	policy StatsPolicy,
+	logger *zap.Logger,
+) DriverStatsService {
+	return &driverStatsService{
+		driverRepo:   driverRepo,
+		ratingRepo:   ratingRepo,
+		shiftRepo:    shiftRepo,
+		documentRepo: documentRepo,
+		locationRepo: locationRepo,
+		summaryRepo:  summaryRepo,
+		dailyRepo:    dailyRepo,
+		dispatchRepo: dispatchRepo,
+		policy:       policy,
+		logger:       logger,
+		cache:        NewTTLCache[uuid.UUID, *entities.DriverStatistics](policy.CacheTTL),
+	}
+}
+
+// GetStatistics возвращает закэшированные показатели водителя или пересчитывает их
+func (s *driverStatsService) GetStatistics(ctx context.Context, driverID uuid.UUID) (*entities.DriverStatistics, error) {
+	now := time.Now()
+	if stats, ok := s.cache.Get(driverID, now); ok {
+		return stats, nil
+	}
+
+	stats, err := s.compute(ctx, driverID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache.Set(driverID, stats, now)
+	return stats, nil
+}
+
+// compute собирает показатели водителя из подсистем
+func (s *driverStatsService) compute(ctx context.Context, driverID uuid.UUID, now time.Time) (*entities.DriverStatistics, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &entities.DriverStatistics{
+		DriverID:   driverID,
+		TotalTrips: driver.TotalTrips,
+		WeekStart:  entities.WeekStart(now),
+		MonthStart: entities.MonthStart(now),
+		ComputedAt: now,
+	}
+
+	ratings, err := s.ratingRepo.GetStats(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	stats.Rating = &entities.RatingSummary{
+		Average:      ratings.AverageRating,
+		Total:        ratings.TotalRatings,
+		Percentile90: ratings.GetPercentile(90),
+		Percentile95: ratings.GetPercentile(95),
+	}
+
+	shiftsFrom := stats.MonthStart.Add(-statsShiftLookback)
+	shifts, err := s.shiftRepo.List(ctx, &entities.ShiftFilters{DriverID: &driverID, From: &shiftsFrom})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+	stats.ShiftHours = entities.PeriodHours{
+		Week:  entities.ShiftHours(shifts, stats.WeekStart, now),
+		Month: entities.ShiftHours(shifts, stats.MonthStart, now),
+	}
+
+	documents, err := s.documentRepo.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	stats.Documents = entities.NewDocumentStatistics(documents, now)
+
+	stats.Dispatch = make([]*entities.DispatchRates, 0, len(s.policy.DispatchWindowDays))
+	for _, days := range s.policy.DispatchWindowDays {
+		dispatchStats, err := s.dispatchRepo.GetStats(ctx, []uuid.UUID{driverID}, now.AddDate(0, 0, -days))
+		if err != nil {
+			return nil, err
+		}
+		stats.Dispatch = append(stats.Dispatch, entities.NewDispatchRates(days, dispatchStats[driverID]))
+	}
+
+	if err := s.addLocationActivity(ctx, stats, now); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// addLocationActivity считает пробег и время на связи. Сутки, показатели которых сохранены,
+// берутся из суточных показателей. Пробег за более ранний период, исходные точки которого уже
+// прорежены, берется из сводных точек SummaryTier, остальной — из исходных точек
+func (s *driverStatsService) addLocationActivity(ctx context.Context, stats *entities.DriverStatistics, now time.Time) error {
+	coverage, err := s.dailyCoverage(ctx)
+	if err != nil {
+		return err
+	}
+
+	var rawFrom time.Time
+	if s.policy.SummaryTier != "" {
+		summaries, err := s.summaryRepo.ListByDriverID(ctx, stats.DriverID)
+		if err != nil {
+			return fmt.Errorf("failed to list location summaries: %w", err)
+		}
+		for _, summary := range summaries {
+			if summary.Tier != s.policy.SummaryTier {
+				continue
+			}
+			// Пробег сохраненных суток берется из суточных показателей
+			if coverage.Covers() && !summary.LastRecordedAt.Before(coverage.CoveredFrom) {
+				continue
+			}
+			stats.TotalDistanceKm += summary.DistanceKm
+			// Исходные точки, уже учтенные в сводных, не считаются повторно
+			if summary.LastRecordedAt.After(rawFrom) {
+				rawFrom = summary.LastRecordedAt.Add(time.Nanosecond)
+			}
+		}
+	}
+
+	total, err := s.activity(ctx, stats.DriverID, rawFrom, now, coverage)
+	if err != nil {
+		return err
+	}
+	stats.TotalDistanceKm += total.DistanceKm
+
+	week, err := s.activity(ctx, stats.DriverID, stats.WeekStart, now, coverage)
+	if err != nil {
+		return err
+	}
+	month, err := s.activity(ctx, stats.DriverID, stats.MonthStart, now, coverage)
+	if err != nil {
+		return err
+	}
+	stats.OnlineHours = entities.PeriodHours{
+		Week:  time.Duration(week.OnlineSeconds * int64(time.Second)).Hours(),
+		Month: time.Duration(month.OnlineSeconds * int64(time.Second)).Hours(),
+	}
+	if coverage.Covers() {
+		through := coverage.RefreshedThrough
+		stats.AggregatedThrough = &through
+	}
+	return nil
+}
+
+// dailyCoverage возвращает сохраненные сутки; nil без суточных показателей
+func (s *driverStatsService) dailyCoverage(ctx context.Context) (*entities.DailyStatsCoverage, error) {
+	if s.dailyRepo == nil {
+		return nil, nil
+	}
+	return s.dailyRepo.GetCoverage(ctx)
+}
+
+// activity считает пробег и время на связи за [from, to): целые сутки из покрытия суммируются
+// по суточным показателям, остаток периода считается по исходным точкам
+func (s *driverStatsService) activity(ctx context.Context, driverID uuid.UUID, from, to time.Time, coverage *entities.DailyStatsCoverage) (*entities.LocationActivity, error) {
+	if !coverage.Covers() {
+		return s.locationRepo.GetActivity(ctx, driverID, from, to, s.policy.MaxGapInterval)
+	}
+
+	dailyFrom := coverage.CoveredFrom
+	if from.After(dailyFrom) {
+		dailyFrom = entities.DayStart(from)
+		if dailyFrom.Before(from) {
+			dailyFrom = dailyFrom.AddDate(0, 0, 1)
+		}
+	}
+	dailyTo := coverage.RefreshedThrough
+	if to.Before(dailyTo) {
+		dailyTo = entities.DayStart(to)
+	}
+	if !dailyTo.After(dailyFrom) {
+		return s.locationRepo.GetActivity(ctx, driverID, from, to, s.policy.MaxGapInterval)
+	}
+
+	total, err := s.dailyRepo.GetActivity(ctx, driverID, dailyFrom, dailyTo)
+	if err != nil {
+		return nil, err
+	}
+	for _, raw := range [][2]time.Time{{from, dailyFrom}, {dailyTo, to}} {
+		if !raw[1].After(raw[0]) {
+			continue
+		}
+		activity, err := s.locationRepo.GetActivity(ctx, driverID, raw[0], raw[1], s.policy.MaxGapInterval)
+		if err != nil {
+			return nil, err
+		}
+		total.DistanceKm += activity.DistanceKm
+		total.OnlineSeconds += activity.OnlineSeconds
+	}
+	return total, nil
+}
+
+// GetDailyStatistics получает сохраненные суточные показатели водителя
+func (s *driverStatsService) GetDailyStatistics(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.DriverDailyStatsReport, error) {
+	if err := entities.ValidateDailyStatsRange(from, to); err != nil {
+		return nil, err
+	}
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	report := &entities.DriverDailyStatsReport{
+		DriverID: driverID,
+		From:     from,
+		To:       to,
+		Days:     []*entities.DriverDailyStats{},
+	}
+	if s.dailyRepo == nil {
+		return report, nil
+	}
+
+	coverage, err := s.dailyRepo.GetCoverage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Coverage = coverage
+
+	days, err := s.dailyRepo.ListByDriverID(ctx, driverID, entities.DayStart(from), to)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range days {
+		day.FillAverageRating()
+		report.Days = append(report.Days, day)
+	}
+	return report, nil
+}
+
+// RefreshDailyStats пересчитывает суточные показатели от начала пропущенных или
+// DailyLookbackDays суток до текущих. Каждые сутки сохраняются отдельно, поэтому прерванный
+// пересчет продолжится со следующего запуска
+func (s *driverStatsService) RefreshDailyStats(ctx context.Context) (int, error) {
+	if s.dailyRepo == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	today := entities.DayStart(now)
+	coverage, err := s.dailyRepo.GetCoverage(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	from := today.AddDate(0, 0, -s.policy.DailyLookbackDays)
+	if coverage == nil {
+		from = today.AddDate(0, 0, -s.policy.DailyBackfillDays)
+		coverage = &entities.DailyStatsCoverage{CoveredFrom: from, RefreshedThrough: from}
+	} else if coverage.RefreshedThrough.Before(from) {
+		from = coverage.RefreshedThrough
+	}
+
+	days := 0
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		stats, err := s.aggregateDay(ctx, day, now)
+		if err != nil {
+			return days, fmt.Errorf("failed to aggregate driver stats of %s: %w", day.Format(time.DateOnly), err)
+		}
+
+		// Текущие сутки сохраняются, но окончательными станут после своего окончания
+		if next := day.AddDate(0, 0, 1); !next.After(today) && next.After(coverage.RefreshedThrough) {
+			coverage.RefreshedThrough = next
+		}
+		coverage.RefreshedAt = now
+		if err := s.dailyRepo.ReplaceDay(ctx, day, stats, coverage); err != nil {
+			return days, err
+		}
+		days++
+	}
+
+	s.logger.Info("Daily driver stats refreshed",
+		zap.Time("from", from),
+		zap.Int("days", days),
+		zap.Time("refreshed_through", coverage.RefreshedThrough),
+	)
+	return days, nil
+}
+
+// aggregateDay считает показатели водителей с точками, сменами или оценками за сутки
+func (s *driverStatsService) aggregateDay(ctx context.Context, day, now time.Time) ([]*entities.DriverDailyStats, error) {
+	dayEnd := day.AddDate(0, 0, 1)
+	// To в фильтрах смен и оценок включительный
+	last := dayEnd.Add(-time.Nanosecond)
+
+	byDriver := make(map[uuid.UUID]*entities.DriverDailyStats)
+	driverStats := func(driverID uuid.UUID) *entities.DriverDailyStats {
+		if stats, ok := byDriver[driverID]; ok {
+			return stats
+		}
+		stats := &entities.DriverDailyStats{DriverID: driverID, Day: day, UpdatedAt: now}
+		byDriver[driverID] = stats
+		return stats
+	}
+
+	driverIDs, err := s.locationRepo.ListDriverIDsInTimeRange(ctx, day, dayEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, driverID := range driverIDs {
+		activity, err := s.locationRepo.GetActivity(ctx, driverID, day, dayEnd, s.policy.MaxGapInterval)
+		if err == entities.ErrDriverNotFound {
+			// Водитель удален между запросами
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		stats := driverStats(driverID)
+		stats.DistanceKm = activity.DistanceKm
+		stats.OnlineSeconds = activity.OnlineSeconds
+	}
+
+	shifts, err := s.shiftRepo.List(ctx, &entities.ShiftFilters{
+		Status: []entities.ShiftStatus{entities.ShiftStatusActive, entities.ShiftStatusCompleted},
+		From:   &day,
+		To:     &last,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+	for _, shift := range shifts {
+		driverStats(shift.DriverID).Trips += shift.TotalTrips
+	}
+
+	ratings, err := s.ratingRepo.List(ctx, &entities.RatingFilters{From: &day, To: &last})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ratings: %w", err)
+	}
+	for _, rating := range ratings {
+		stats := driverStats(rating.DriverID)
+		stats.RatingSum += rating.Rating
+		stats.RatingCount++
+	}
+
+	result := make([]*entities.DriverDailyStats, 0, len(byDriver))
+	for _, stats := range byDriver {
+		if !stats.IsEmpty() {
+			result = append(result, stats)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDriverStatsService_GetStatistics(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	ratingRepo := memory.NewRatingRepository()
+	shiftRepo := memory.NewShiftRepository()
+	documentRepo := memory.NewDocumentRepository()
+	locationRepo := memory.NewLocationRepository()
+	summaryRepo := memory.NewLocationSummaryRepository()
+	dispatchRepo := memory.NewDispatchRepository()
+	service := NewDriverStatsService(driverRepo, ratingRepo, shiftRepo, documentRepo, locationRepo, summaryRepo, nil, dispatchRepo,
+		StatsPolicy{CacheTTL: time.Minute, MaxGapInterval: 5 * time.Minute, SummaryTier: "1h", DispatchWindowDays: []int{1, 7}}, zap.NewNop())
+
+	driver := newTestDriver("1")
+	driver.ID = uuid.New()
+	driver.TotalTrips = 42
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	for _, score := range []int{5, 5, 4, 3} {
+		require.NoError(t, ratingRepo.Create(ctx, entities.NewDriverRating(driver.ID, score, entities.RatingTypeSystem)))
+	}
+
+	now := time.Now()
+	shift := entities.NewDriverShift(driver.ID, nil, nil)
+	shift.StartTime = now.Add(-2 * time.Hour)
+	require.NoError(t, shiftRepo.Create(ctx, shift))
+
+	require.NoError(t, documentRepo.Create(ctx, entities.NewDriverDocument(driver.ID, entities.DocumentTypeDriverLicense,
+		"77 00 123456", now.AddDate(-1, 0, 0), now.AddDate(5, 0, 0), "")))
+
+	// Прореженная история уровня 1h и более мелкого уровня, который не учитывается
+	old := now.AddDate(0, -2, 0)
+	require.NoError(t, summaryRepo.Upsert(ctx, []*entities.LocationSummary{
+		{DriverID: driver.ID, Tier: "1h", BucketStart: old, BucketSeconds: 3600, DistanceKm: 100, LastRecordedAt: old.Add(time.Hour)},
+		{DriverID: driver.ID, Tier: "5m", BucketStart: old, BucketSeconds: 300, DistanceKm: 100, LastRecordedAt: old.Add(5 * time.Minute)},
+	}))
+
+	for i, latitude := range []float64{55.70, 55.71, 55.72} {
+		location := entities.NewDriverLocation(driver.ID, latitude, 37.61, now.Add(time.Duration(i-3)*time.Minute))
+		saveLocation(t, locationRepo, location)
+	}
+
+	// Два принятых предложения за сутки, одно из них отменено, и отказ три дня назад
+	for i, respondedAt := range []time.Time{now.Add(-time.Hour), now.Add(-2 * time.Hour), now.AddDate(0, 0, -3)} {
+		offer := &entities.DispatchOffer{DriverID: driver.ID, OrderID: uuid.New(), Accepted: i < 2, RespondedAt: respondedAt}
+		require.NoError(t, dispatchRepo.RecordOffer(ctx, offer))
+		if i == 0 {
+			require.NoError(t, dispatchRepo.RecordCancellation(ctx, driver.ID, offer.OrderID, now))
+		}
+	}
+
+	stats, err := service.GetStatistics(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 42, stats.TotalTrips)
+	require.Len(t, stats.Dispatch, 2)
+	assert.Equal(t, 1, stats.Dispatch[0].WindowDays)
+	assert.Equal(t, 1.0, *stats.Dispatch[0].AcceptanceRate)
+	assert.Equal(t, 0.5, *stats.Dispatch[0].CancellationRate)
+	assert.Equal(t, 3, stats.Dispatch[1].OffersReceived)
+	assert.InDelta(t, 2.0/3, *stats.Dispatch[1].AcceptanceRate, 0.001)
+	assert.InDelta(t, 102.22, stats.TotalDistanceKm, 0.01)
+	assert.Equal(t, 4, stats.Rating.Total)
+	assert.InDelta(t, 4.25, stats.Rating.Average, 0.001)
+	assert.Equal(t, 4.0, stats.Rating.Percentile90)
+	assert.Equal(t, 1, stats.Documents.Total)
+	assert.Equal(t, 1, stats.Documents.ByStatus[entities.VerificationStatusPending])
+	// Точки записаны за последние 3 минуты и попадают в неделю, если она началась раньше
+	if stats.WeekStart.Before(now.Add(-3 * time.Minute)) {
+		assert.InDelta(t, 2.0/60, stats.OnlineHours.Week, 0.001)
+	}
+	assert.LessOrEqual(t, stats.OnlineHours.Week, stats.OnlineHours.Month)
+	assert.GreaterOrEqual(t, stats.ShiftHours.Month, stats.ShiftHours.Week)
+	assert.LessOrEqual(t, stats.ShiftHours.Month, 2.01)
+
+	// Показатели берутся из кэша до истечения CacheTTL
+	require.NoError(t, driverRepo.IncrementTripCount(ctx, driver.ID))
+	cached, err := service.GetStatistics(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 42, cached.TotalTrips)
+
+	_, err = service.GetStatistics(ctx, uuid.New())
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
+
+func TestDriverStatsService_DailyStats(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	ratingRepo := memory.NewRatingRepository()
+	shiftRepo := memory.NewShiftRepository()
+	locationRepo := memory.NewLocationRepository()
+	dailyRepo := memory.NewDriverDailyStatsRepository()
+	service := NewDriverStatsService(driverRepo, ratingRepo, shiftRepo, memory.NewDocumentRepository(), locationRepo,
+		memory.NewLocationSummaryRepository(), dailyRepo, memory.NewDispatchRepository(),
+		StatsPolicy{MaxGapInterval: 5 * time.Minute, DailyLookbackDays: 1, DailyBackfillDays: 3}, zap.NewNop())
+
+	driver := newTestDriver("2")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	today := entities.DayStart(time.Now())
+	yesterday := today.AddDate(0, 0, -1)
+	noon := yesterday.Add(12 * time.Hour)
+	for i, latitude := range []float64{55.70, 55.71, 55.72} {
+		saveLocation(t, locationRepo, entities.NewDriverLocation(driver.ID, latitude, 37.61, noon.Add(time.Duration(i)*time.Minute)))
+	}
+	shift := entities.NewDriverShift(driver.ID, nil, nil)
+	shift.StartTime = noon
+	shift.TotalTrips = 3
+	require.NoError(t, shiftRepo.Create(ctx, shift))
+	for _, score := range []int{5, 4} {
+		rating := entities.NewDriverRating(driver.ID, score, entities.RatingTypeSystem)
+		rating.CreatedAt = noon
+		require.NoError(t, ratingRepo.Create(ctx, rating))
+	}
+
+	// Первый пересчет заполняет DailyBackfillDays прошедших суток и текущие
+	days, err := service.RefreshDailyStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, days)
+
+	report, err := service.GetDailyStatistics(ctx, driver.ID, yesterday, today.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.NotNil(t, report.Coverage)
+	assert.Equal(t, today.AddDate(0, 0, -3), report.Coverage.CoveredFrom)
+	assert.Equal(t, today, report.Coverage.RefreshedThrough)
+	require.Len(t, report.Days, 1)
+	assert.Equal(t, yesterday, report.Days[0].Day)
+	assert.Equal(t, 3, report.Days[0].Trips)
+	assert.InDelta(t, 2.22, report.Days[0].DistanceKm, 0.01)
+	assert.EqualValues(t, 120, report.Days[0].OnlineSeconds)
+	assert.InDelta(t, 4.5, report.Days[0].AverageRating, 0.001)
+
+	// Сохраненные сутки не читаются из исходных точек
+	saveLocation(t, locationRepo, entities.NewDriverLocation(driver.ID, 55.73, 37.61, noon.Add(3*time.Minute)))
+	stats, err := service.GetStatistics(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.22, stats.TotalDistanceKm, 0.01)
+	require.NotNil(t, stats.AggregatedThrough)
+	assert.Equal(t, today, *stats.AggregatedThrough)
+	if !yesterday.Before(stats.WeekStart) {
+		assert.InDelta(t, 2.0/60, stats.OnlineHours.Week, 0.001)
+	}
+
+	// Следующий пересчет учитывает точку, записанную с опозданием
+	days, err = service.RefreshDailyStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, days)
+	stats, err = service.GetStatistics(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3.33, stats.TotalDistanceKm, 0.01)
+
+	_, err = service.GetDailyStatistics(ctx, driver.ID, today, yesterday)
+	assert.ErrorIs(t, err, entities.ErrInvalidStatsRange)
+}
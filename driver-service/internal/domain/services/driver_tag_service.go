@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverTagPolicy правила меток водителей
+type DriverTagPolicy struct {
+	// Controlled контролируемые метки; приводятся к нормальной форме при создании сервиса
+	Controlled []string
+	// FreeForm разрешает метки вне Controlled
+	FreeForm bool
+	// MaxPerDriver ограничение числа меток у водителя; 0 — без ограничения
+	MaxPerDriver int
+}
+
+// DriverTagService интерфейс для меток водителей
+type DriverTagService interface {
+	// Catalog возвращает справочник меток
+	Catalog() *entities.DriverTagCatalog
+	// AddTags добавляет метки водителю и возвращает его метки после изменения
+	AddTags(ctx context.Context, driverID uuid.UUID, tags []string) (entities.DriverTagList, error)
+	// RemoveTag снимает метку с водителя и возвращает его метки после изменения
+	RemoveTag(ctx context.Context, driverID uuid.UUID, tag string) (entities.DriverTagList, error)
+}
+
+// driverTagService реализация DriverTagService
+type driverTagService struct {
+	driverRepo repositories.DriverRepository
+	policy     DriverTagPolicy
+	logger     *zap.Logger
+}
+
+// NewDriverTagService создает новый DriverTagService. Неверные контролируемые метки
+// пропускаются с предупреждением
+func NewDriverTagService(driverRepo repositories.DriverRepository, policy DriverTagPolicy, logger *zap.Logger) DriverTagService {
+	controlled := make([]string, 0, len(policy.Controlled))
+	for _, tag := range policy.Controlled {
+		normalized, err := entities.NormalizeDriverTag(tag)
+		if err != nil {
+			logger.Warn("Skipping invalid controlled driver tag", zap.String("tag", tag))
+			continue
+		}
+		if !entities.DriverTagList(controlled).Contains(normalized) {
+			controlled = append(controlled, normalized)
+		}
+	}
+	policy.Controlled = controlled
+
+	return &driverTagService{
+		driverRepo: driverRepo,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// Catalog возвращает справочник меток
+func (s *driverTagService) Catalog() *entities.DriverTagCatalog {
+	return &entities.DriverTagCatalog{
+		Controlled:   append([]string{}, s.policy.Controlled...),
+		FreeForm:     s.policy.FreeForm,
+		MaxPerDriver: s.policy.MaxPerDriver,
+	}
+}
+
+// AddTags добавляет метки водителю. Повторное добавление имеющейся метки не считается
+// ошибкой; без FreeForm допускаются только контролируемые метки
+func (s *driverTagService) AddTags(ctx context.Context, driverID uuid.UUID, tags []string) (entities.DriverTagList, error) {
+	normalized, err := entities.NormalizeDriverTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, entities.ErrInvalidDriverTag
+	}
+	if !s.policy.FreeForm {
+		for _, tag := range normalized {
+			if !entities.DriverTagList(s.policy.Controlled).Contains(tag) {
+				return nil, entities.ErrUnknownDriverTag
+			}
+		}
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := driver.Tags.With(normalized)
+	if len(updated) == len(driver.Tags) {
+		return updated, nil
+	}
+	if s.policy.MaxPerDriver > 0 && len(updated) > s.policy.MaxPerDriver {
+		return nil, entities.ErrTooManyDriverTags
+	}
+
+	if err := s.driverRepo.UpdateTags(ctx, driverID, updated); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver tags added",
+		zap.String("driver_id", driverID.String()),
+		zap.Strings("tags", normalized),
+	)
+	return updated, nil
+}
+
+// RemoveTag снимает метку с водителя; отсутствующая метка не считается ошибкой
+func (s *driverTagService) RemoveTag(ctx context.Context, driverID uuid.UUID, tag string) (entities.DriverTagList, error) {
+	normalized, err := entities.NormalizeDriverTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if !driver.Tags.Contains(normalized) {
+		return driver.Tags.Without(nil), nil
+	}
+
+	updated := driver.Tags.Without([]string{normalized})
+	if err := s.driverRepo.UpdateTags(ctx, driverID, updated); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver tag removed",
+		zap.String("driver_id", driverID.String()),
+		zap.String("tag", normalized),
+	)
+	return updated, nil
+}
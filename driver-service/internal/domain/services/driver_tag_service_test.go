@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDriverTagService_AddAndRemove(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	service := NewDriverTagService(driverRepo, DriverTagPolicy{
+		Controlled:   []string{"VIP-capable", "pet friendly", "bad tag!"},
+		MaxPerDriver: 2,
+	}, zap.NewNop())
+
+	assert.Equal(t, []string{"vip-capable", "pet-friendly"}, service.Catalog().Controlled)
+
+	driver := newTestDriver("601")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	tags, err := service.AddTags(ctx, driver.ID, []string{" VIP-Capable ", "vip_capable"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"vip-capable"}, tags)
+
+	_, err = service.AddTags(ctx, driver.ID, []string{"smoker"})
+	assert.ErrorIs(t, err, entities.ErrUnknownDriverTag)
+	_, err = service.AddTags(ctx, driver.ID, []string{"pet-friendly!"})
+	assert.ErrorIs(t, err, entities.ErrInvalidDriverTag)
+	_, err = service.AddTags(ctx, uuid.New(), []string{"pet-friendly"})
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+
+	tags, err = service.AddTags(ctx, driver.ID, []string{"pet-friendly"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"vip-capable", "pet-friendly"}, tags)
+
+	// Повторное добавление имеющихся меток не упирается в ограничение
+	_, err = service.AddTags(ctx, driver.ID, []string{"pet-friendly", "vip-capable"})
+	require.NoError(t, err)
+
+	tags, err = service.RemoveTag(ctx, driver.ID, "VIP-capable")
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"pet-friendly"}, tags)
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"pet-friendly"}, stored.Tags)
+
+	// Обновление профиля не затирает метки
+	stored.FirstName = "Петр"
+	stored.Tags = nil
+	require.NoError(t, driverRepo.Update(ctx, stored))
+	stored, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"pet-friendly"}, stored.Tags)
+}
+
+func TestDriverTagService_FreeFormLimit(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	service := NewDriverTagService(driverRepo, DriverTagPolicy{FreeForm: true, MaxPerDriver: 2}, zap.NewNop())
+
+	driver := newTestDriver("602")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	tags, err := service.AddTags(ctx, driver.ID, []string{"Wheelchair-accessible vehicle", "english"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.DriverTagList{"wheelchair-accessible-vehicle", "english"}, tags)
+
+	_, err = service.AddTags(ctx, driver.ID, []string{"airport"})
+	assert.ErrorIs(t, err, entities.ErrTooManyDriverTags)
+}
+
+func TestDriverTagFilters(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	tagService := NewDriverTagService(driverRepo, DriverTagPolicy{FreeForm: true}, zap.NewNop())
+	locationService := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	vip := newTestDriver("603")
+	vip.ID = uuid.New()
+	vip.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, vip))
+	_, err := tagService.AddTags(ctx, vip.ID, []string{"vip-capable", "pet-friendly"})
+	require.NoError(t, err)
+
+	plain := newTestDriver("604")
+	plain.ID = uuid.New()
+	plain.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, plain))
+	_, err = tagService.AddTags(ctx, plain.ID, []string{"pet-friendly"})
+	require.NoError(t, err)
+
+	drivers, err := driverRepo.List(ctx, &entities.DriverFilters{Tags: []string{"pet-friendly", "vip-capable"}})
+	require.NoError(t, err)
+	require.Len(t, drivers, 1)
+	assert.Equal(t, vip.ID, drivers[0].ID)
+
+	count, err := driverRepo.Count(ctx, &entities.DriverFilters{Tags: []string{"pet-friendly"}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	now := time.Now()
+	require.NoError(t, locationService.UpdateLocation(ctx, entities.NewDriverLocation(vip.ID, 55.7558, 37.6173, now)))
+	require.NoError(t, locationService.UpdateLocation(ctx, entities.NewDriverLocation(plain.ID, 55.7560, 37.6175, now)))
+
+	nearby, err := locationService.GetNearbyDrivers(ctx, 55.7558, 37.6173, 1, 10, []string{"vip-capable"})
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, vip.ID, nearby[0].DriverID)
+
+	nearby, err = locationService.GetNearbyDrivers(ctx, 55.7558, 37.6173, 1, 10, nil)
+	require.NoError(t, err)
+	assert.Len(t, nearby, 2)
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// verificationSuspendedAtKey ключ метаданных водителя, отстраненного из-за документов.
+// Автоматически возвращаются на линию только такие водители
+const verificationSuspendedAtKey = "verification_suspended_at"
+
+// DocumentEventTypes события документов, после которых пересматривается допуск водителя
+var DocumentEventTypes = []string{
+	eventDocumentVerified,
+	eventDocumentRejected,
+	eventDocumentRenewed,
+	eventDocumentExpired,
+	eventDocumentReverificationOverdue,
+	eventDocumentReverificationCancelled,
+}
+
+// DriverVerificationPolicy настройки допуска водителей
+type DriverVerificationPolicy struct {
+	// RequiredDocuments типы документов, подтверждение которых обязательно для допуска
+	RequiredDocuments []entities.DocumentType
+}
+
+// DriverVerificationService интерфейс проверки допуска водителя по документам
+type DriverVerificationService interface {
+	// Evaluate пересматривает статус водителя по состоянию обязательных документов
+	Evaluate(ctx context.Context, driverID uuid.UUID) (*entities.DriverVerificationResult, error)
+	HandleDocumentEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+	// ExpireDocuments помечает истекшие документы и публикует driver.document.expired
+	ExpireDocuments(ctx context.Context) (int, error)
+}
+
+// driverVerificationService реализация DriverVerificationService
+type driverVerificationService struct {
+	driverRepo    repositories.DriverRepository
+	documentRepo  repositories.DocumentRepository
+	driverService DriverService
+	eventBus      EventPublisher
+	policy        DriverVerificationPolicy
+	logger        *zap.Logger
+
+	// mu исключает параллельный пересмотр: события по документам одного водителя
+	// могут прийти одновременно, а переход статуса проверяется по прочитанному статусу
+	mu sync.Mutex
+}
+
+// NewDriverVerificationService создает новый DriverVerificationService
+func NewDriverVerificationService(
+	driverRepo repositories.DriverRepository,
+	documentRepo repositories.DocumentRepository,
+	driverService DriverService,
+	eventBus EventPublisher,
+	policy DriverVerificationPolicy,
+	logger *zap.Logger,
+) DriverVerificationService {
+	return &driverVerificationService{
+		driverRepo:    driverRepo,
+		documentRepo:  documentRepo,
+		driverService: driverService,
+		eventBus:      eventBus,
+		policy:        policy,
+		logger:        logger,
+	}
+}
+
+// Evaluate пересматривает статус водителя:
+//   - на верификации: все документы подтверждены — verified, документ отклонен или истек — rejected;
+//   - отклоненный водитель, загрузивший подтвержденные документы, возвращается в verified;
+//   - допущенный водитель с отклоненным или истекшим документом отстраняется (suspended);
+//   - отстраненный из-за документов водитель возвращается на линию после их подтверждения
+func (s *driverVerificationService) Evaluate(ctx context.Context, driverID uuid.UUID) (*entities.DriverVerificationResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver documents: %w", err)
+	}
+
+	requirements := entities.EvaluateRequirements(s.policy.RequiredDocuments, documents)
+	result := &entities.DriverVerificationResult{
+		DriverID:       driverID,
+		PreviousStatus: driver.Status,
+		Status:         driver.Status,
+		Outcome:        entities.VerificationOutcomeNone,
+		Requirements:   requirements,
+		EvaluatedAt:    time.Now(),
+	}
+
+	var transitions []entities.Status
+	switch driver.Status {
+	case entities.StatusPendingVerification:
+		if entities.AllVerified(requirements) {
+			result.Outcome = entities.VerificationOutcomeVerified
+			transitions = []entities.Status{entities.StatusVerified}
+		} else if entities.AnyFailed(requirements) {
+			result.Outcome = entities.VerificationOutcomeRejected
+			transitions = []entities.Status{entities.StatusRejected}
+		}
+	case entities.StatusRejected:
+		if entities.AllVerified(requirements) {
+			result.Outcome = entities.VerificationOutcomeVerified
+			transitions = []entities.Status{entities.StatusPendingVerification, entities.StatusVerified}
+		}
+	case entities.StatusVerified, entities.StatusAvailable, entities.StatusOnShift, entities.StatusInactive:
+		if entities.AnyFailed(requirements) {
+			result.Outcome = entities.VerificationOutcomeSuspended
+			transitions = []entities.Status{entities.StatusSuspended}
+		}
+	case entities.StatusBusy:
+		// Водителя не снимаем с заказа: отстранение произойдет при следующем пересмотре
+		if entities.AnyFailed(requirements) {
+			result.Outcome = entities.VerificationOutcomeDeferred
+		}
+	case entities.StatusSuspended:
+		if _, ok := driver.Metadata[verificationSuspendedAtKey]; ok && entities.AllVerified(requirements) {
+			result.Outcome = entities.VerificationOutcomeReinstated
+			transitions = []entities.Status{entities.StatusAvailable}
+		}
+	}
+
+	for _, status := range transitions {
+		if err := s.driverService.ChangeDriverStatus(ctx, driverID, status); err != nil {
+			s.logger.Error("Failed to apply driver verification outcome",
+				zap.Error(err),
+				zap.String("driver_id", driverID.String()),
+				zap.String("outcome", string(result.Outcome)),
+				zap.String("to_status", string(status)),
+			)
+			return nil, err
+		}
+		result.Status = status
+	}
+
+	switch result.Outcome {
+	case entities.VerificationOutcomeSuspended:
+		s.markSuspended(ctx, driverID, true)
+	case entities.VerificationOutcomeReinstated:
+		s.markSuspended(ctx, driverID, false)
+	}
+
+	if result.Outcome != entities.VerificationOutcomeNone {
+		s.logger.Info("Driver verification evaluated",
+			zap.String("driver_id", driverID.String()),
+			zap.String("outcome", string(result.Outcome)),
+			zap.String("previous_status", string(result.PreviousStatus)),
+			zap.String("status", string(result.Status)),
+		)
+	}
+
+	return result, nil
+}
+
+// HandleDocumentEvent пересматривает допуск водителя после события по его документу
+func (s *driverVerificationService) HandleDocumentEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	_, err := s.Evaluate(ctx, driverID)
+	if err == entities.ErrDriverNotFound {
+		// Водитель удален: пересматривать нечего
+		return nil
+	}
+	return err
+}
+
+// ExpireDocuments помечает документы с прошедшей датой истечения и сообщает о них,
+// чтобы подписчики пересмотрели допуск водителей
+func (s *driverVerificationService) ExpireDocuments(ctx context.Context) (int, error) {
+	documents, err := s.documentRepo.GetExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired documents: %w", err)
+	}
+	if len(documents) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, len(documents))
+	for i, document := range documents {
+		ids[i] = document.ID
+	}
+	if err := s.documentRepo.MarkExpired(ctx, ids); err != nil {
+		return 0, fmt.Errorf("failed to mark documents as expired: %w", err)
+	}
+
+	for _, document := range documents {
+		eventData := map[string]interface{}{
+			"document_id":   document.ID,
+			"document_type": document.DocumentType,
+			"expiry_date":   document.ExpiryDate,
+		}
+		if err := s.eventBus.PublishDriverEvent(ctx, eventDocumentExpired, document.DriverID, eventData); err != nil {
+			s.logger.Error("Failed to publish document expired event",
+				zap.Error(err),
+				zap.String("document_id", document.ID.String()),
+			)
+		}
+	}
+
+	s.logger.Info("Expired documents marked", zap.Int("count", len(documents)))
+	return len(documents), nil
+}
+
+// markSuspended отмечает в метаданных водителя отстранение из-за документов или снимает отметку
+func (s *driverVerificationService) markSuspended(ctx context.Context, driverID uuid.UUID, suspended bool) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err == nil {
+		if suspended {
+			if driver.Metadata == nil {
+				driver.Metadata = make(entities.Metadata)
+			}
+			driver.Metadata[verificationSuspendedAtKey] = time.Now().UTC().Format(time.RFC3339)
+		} else {
+			delete(driver.Metadata, verificationSuspendedAtKey)
+		}
+		err = s.driverRepo.Update(ctx, driver)
+	}
+	if err != nil {
+		s.logger.Error("Failed to update driver verification suspension mark",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+}
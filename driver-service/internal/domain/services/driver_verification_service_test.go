@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type verificationFixture struct {
+	service      DriverVerificationService
+	driverRepo   *memory.DriverRepository
+	documentRepo *memory.DocumentRepository
+	events       *recordingEventPublisher
+	driver       *entities.Driver
+}
+
+func newVerificationFixture(t *testing.T, status entities.Status) *verificationFixture {
+	driverService, driverRepo, documentRepo, events := newTestDriverService()
+	driver, err := driverService.CreateDriver(context.Background(), newTestDriver("1"))
+	require.NoError(t, err)
+	require.NoError(t, driverRepo.UpdateStatus(context.Background(), driver.ID, status))
+
+	service := NewDriverVerificationService(driverRepo, documentRepo, driverService, events,
+		DriverVerificationPolicy{RequiredDocuments: []entities.DocumentType{
+			entities.DocumentTypeDriverLicense,
+			entities.DocumentTypePassport,
+		}}, zap.NewNop())
+
+	return &verificationFixture{
+		service:      service,
+		driverRepo:   driverRepo,
+		documentRepo: documentRepo,
+		events:       events,
+		driver:       driver,
+	}
+}
+
+func (f *verificationFixture) addDocument(t *testing.T, docType entities.DocumentType, status entities.VerificationStatus, expiry time.Time) *entities.DriverDocument {
+	document := entities.NewDriverDocument(f.driver.ID, docType, "DOC-1",
+		time.Now().AddDate(-1, 0, 0), expiry, "https://example.com/doc.pdf")
+	document.Status = status
+	require.NoError(t, f.documentRepo.Create(context.Background(), document))
+	return document
+}
+
+func (f *verificationFixture) status(t *testing.T) entities.Status {
+	driver, err := f.driverRepo.GetByID(context.Background(), f.driver.ID)
+	require.NoError(t, err)
+	return driver.Status
+}
+
+func TestDriverVerificationService_Onboarding(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusPendingVerification)
+	nextYear := time.Now().AddDate(1, 0, 0)
+
+	// Пока паспорт на проверке, статус не меняется
+	f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusVerified, nextYear)
+	passport := f.addDocument(t, entities.DocumentTypePassport, entities.VerificationStatusPending, nextYear)
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeNone, result.Outcome)
+	assert.Equal(t, entities.RequirementPending, result.Requirements[1].State)
+	assert.Equal(t, entities.StatusPendingVerification, f.status(t))
+
+	require.NoError(t, f.documentRepo.UpdateStatus(ctx, passport.ID, entities.VerificationStatusVerified, nil, nil))
+	require.NoError(t, f.service.HandleDocumentEvent(ctx, "driver.document.verified", f.driver.ID))
+	assert.Equal(t, entities.StatusVerified, f.status(t))
+	assert.True(t, f.events.has("driver.status.changed"))
+}
+
+func TestDriverVerificationService_RejectedDocument(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusPendingVerification)
+	nextYear := time.Now().AddDate(1, 0, 0)
+
+	f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusVerified, nextYear)
+	passport := f.addDocument(t, entities.DocumentTypePassport, entities.VerificationStatusRejected, nextYear)
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeRejected, result.Outcome)
+	assert.Equal(t, entities.StatusRejected, f.status(t))
+
+	// Подтвержденный при повторной проверке паспорт возвращает водителя в verified
+	require.NoError(t, f.documentRepo.UpdateStatus(ctx, passport.ID, entities.VerificationStatusVerified, nil, nil))
+	result, err = f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeVerified, result.Outcome)
+	assert.Equal(t, entities.StatusRejected, result.PreviousStatus)
+	assert.Equal(t, entities.StatusVerified, f.status(t))
+}
+
+func TestDriverVerificationService_ExpiryAndReinstatement(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusAvailable)
+
+	f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusVerified, time.Now().AddDate(1, 0, 0))
+	passport := f.addDocument(t, entities.DocumentTypePassport, entities.VerificationStatusVerified, time.Now().AddDate(0, 0, -1))
+
+	expired, err := f.service.ExpireDocuments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, expired)
+	assert.True(t, f.events.has("driver.document.expired"))
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeSuspended, result.Outcome)
+	assert.Equal(t, entities.RequirementExpired, result.Requirements[1].State)
+	assert.Equal(t, entities.StatusSuspended, f.status(t))
+
+	driver, err := f.driverRepo.GetByID(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Contains(t, driver.Metadata, verificationSuspendedAtKey)
+
+	// Продление на проверке не заменяет истекшую текущую версию
+	renewal := entities.NewDriverDocument(f.driver.ID, entities.DocumentTypePassport, "DOC-2",
+		time.Now(), time.Now().AddDate(5, 0, 0), "https://example.com/doc.pdf")
+	renewal.ReplacesID = &passport.ID
+	renewal.IsCurrent = false
+	require.NoError(t, f.documentRepo.Create(ctx, renewal))
+	result, err = f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.RequirementExpired, result.Requirements[1].State)
+	assert.Equal(t, passport.ID, *result.Requirements[1].DocumentID)
+	assert.Equal(t, entities.StatusSuspended, f.status(t))
+
+	// Подтвержденное продление паспорта возвращает водителя на линию
+	renewal.Status = entities.VerificationStatusVerified
+	require.NoError(t, f.documentRepo.Update(ctx, renewal))
+	require.NoError(t, f.documentRepo.Supersede(ctx, passport.ID, renewal.ID))
+	result, err = f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeReinstated, result.Outcome)
+	assert.Equal(t, entities.StatusAvailable, f.status(t))
+
+	driver, err = f.driverRepo.GetByID(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.NotContains(t, driver.Metadata, verificationSuspendedAtKey)
+}
+
+func TestDriverVerificationService_ReverificationOverdue(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusAvailable)
+
+	license := f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusVerified, time.Now().AddDate(1, 0, 0))
+	f.addDocument(t, entities.DocumentTypePassport, entities.VerificationStatusVerified, time.Now().AddDate(5, 0, 0))
+
+	// До срока кампании водитель остается на линии
+	flagged, err := f.documentRepo.RequireReverification(ctx, []uuid.UUID{license.ID}, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, flagged)
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeNone, result.Outcome)
+	assert.Equal(t, entities.RequirementReverification, result.Requirements[0].State)
+
+	document, err := f.documentRepo.GetByID(ctx, license.ID)
+	require.NoError(t, err)
+	verifyBy := time.Now().Add(-time.Minute)
+	document.VerifyBy = &verifyBy
+	require.NoError(t, f.documentRepo.Update(ctx, document))
+
+	result, err = f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeSuspended, result.Outcome)
+	assert.Equal(t, entities.RequirementReverificationOverdue, result.Requirements[0].State)
+	assert.Equal(t, entities.StatusSuspended, f.status(t))
+
+	// Снятое требование перепроверки возвращает водителя на линию
+	_, err = f.documentRepo.ClearReverification(ctx, []uuid.UUID{license.ID})
+	require.NoError(t, err)
+	result, err = f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeReinstated, result.Outcome)
+}
+
+func TestDriverVerificationService_ManualSuspensionKept(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusSuspended)
+	nextYear := time.Now().AddDate(1, 0, 0)
+
+	// Водитель отстранен не из-за документов: автоматически не возвращается
+	f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusVerified, nextYear)
+	f.addDocument(t, entities.DocumentTypePassport, entities.VerificationStatusVerified, nextYear)
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeNone, result.Outcome)
+	assert.Equal(t, entities.StatusSuspended, f.status(t))
+}
+
+func TestDriverVerificationService_BusyDeferred(t *testing.T) {
+	ctx := context.Background()
+	f := newVerificationFixture(t, entities.StatusBusy)
+
+	f.addDocument(t, entities.DocumentTypeDriverLicense, entities.VerificationStatusRejected, time.Now().AddDate(1, 0, 0))
+
+	result, err := f.service.Evaluate(ctx, f.driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationOutcomeDeferred, result.Outcome)
+	assert.Equal(t, entities.StatusBusy, f.status(t))
+}
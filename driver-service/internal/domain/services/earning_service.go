@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EarningPolicy параметры начислений водителям
+type EarningPolicy struct {
+	// Currencies допустимые валюты; первая — валюта по умолчанию
+	Currencies []string
+	// Location часовой пояс периодов ведомости по умолчанию; nil — UTC
+	Location *time.Location
+}
+
+// defaultCurrency возвращает валюту начислений по умолчанию
+func (p EarningPolicy) defaultCurrency() string {
+	if len(p.Currencies) == 0 {
+		return "RUB"
+	}
+	return p.Currencies[0]
+}
+
+// EarningService интерфейс для начислений водителям и выплатных ведомостей
+type EarningService interface {
+	RecordEarning(ctx context.Context, driverID uuid.UUID, req *entities.EarningRequest) (*entities.DriverEarning, error)
+	ListEarnings(ctx context.Context, filters *entities.EarningFilters) ([]*entities.DriverEarning, error)
+	CountEarnings(ctx context.Context, filters *entities.EarningFilters) (int, error)
+	// GetStatement возвращает начисления за [from, to) по периодам; loc nil — часовой пояс политики
+	GetStatement(ctx context.Context, driverID uuid.UUID, from, to time.Time, groupBy entities.EarningsGrouping, loc *time.Location) (*entities.EarningsStatement, error)
+}
+
+// earningService реализация EarningService
+type earningService struct {
+	earningRepo repositories.EarningRepository
+	driverRepo  repositories.DriverRepository
+	shiftRepo   repositories.ShiftRepository
+	eventBus    EventPublisher
+	policy      EarningPolicy
+	logger      *zap.Logger
+}
+
+// NewEarningService создает новый EarningService
+func NewEarningService(
+	earningRepo repositories.EarningRepository,
+	driverRepo repositories.DriverRepository,
+	shiftRepo repositories.ShiftRepository,
+	eventBus EventPublisher,
+	policy EarningPolicy,
+	logger *zap.Logger,
+) EarningService {
+	return &earningService{
+		earningRepo: earningRepo,
+		driverRepo:  driverRepo,
+		shiftRepo:   shiftRepo,
+		eventBus:    eventBus,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// RecordEarning начисляет водителю оплату поездки или бонус
+func (s *earningService) RecordEarning(ctx context.Context, driverID uuid.UUID, req *entities.EarningRequest) (*entities.DriverEarning, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	if req.ShiftID != nil {
+		shift, err := s.shiftRepo.GetByID(ctx, *req.ShiftID)
+		if err != nil {
+			return nil, err
+		}
+		if shift.DriverID != driverID {
+			return nil, entities.ErrShiftNotFound
+		}
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = s.policy.defaultCurrency()
+	}
+
+	earnedAt := time.Now()
+	if req.EarnedAt != nil {
+		earnedAt = *req.EarnedAt
+	}
+
+	earning := entities.NewDriverEarning(driverID, req, currency, earnedAt)
+	if err := earning.Validate(); err != nil {
+		return nil, err
+	}
+	if !s.isAllowedCurrency(earning.Currency) {
+		return nil, entities.ErrInvalidCurrency
+	}
+
+	if err := s.earningRepo.Create(ctx, earning); err != nil {
+		if errors.Is(err, entities.ErrEarningExists) {
+			return nil, entities.ErrEarningExists
+		}
+		return nil, fmt.Errorf("failed to record earning: %w", err)
+	}
+
+	eventData := map[string]interface{}{
+		"earning_id": earning.ID.String(),
+		"type":       earning.Type,
+		"fare":       earning.Fare,
+		"commission": earning.Commission,
+		"bonus":      earning.Bonus,
+		"net_amount": earning.NetAmount,
+		"currency":   earning.Currency,
+		"earned_at":  earning.EarnedAt,
+	}
+	if earning.OrderID != nil {
+		eventData["order_id"] = earning.OrderID.String()
+	}
+	if earning.ShiftID != nil {
+		eventData["shift_id"] = earning.ShiftID.String()
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventEarningRecorded, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish earning recorded event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Earning recorded",
+		zap.String("earning_id", earning.ID.String()),
+		zap.String("driver_id", driverID.String()),
+		zap.String("type", string(earning.Type)),
+	)
+
+	return earning, nil
+}
+
+// ListEarnings получает страницу начислений, новые первыми
+func (s *earningService) ListEarnings(ctx context.Context, filters *entities.EarningFilters) ([]*entities.DriverEarning, error) {
+	if filters.From != nil && filters.To != nil && !filters.To.After(*filters.From) {
+		return nil, entities.ErrInvalidTimestamp
+	}
+	return s.earningRepo.List(ctx, filters)
+}
+
+// CountEarnings считает начисления
+func (s *earningService) CountEarnings(ctx context.Context, filters *entities.EarningFilters) (int, error) {
+	return s.earningRepo.Count(ctx, filters)
+}
+
+// GetStatement собирает выплатную ведомость водителя за период
+func (s *earningService) GetStatement(ctx context.Context, driverID uuid.UUID, from, to time.Time, groupBy entities.EarningsGrouping, loc *time.Location) (*entities.EarningsStatement, error) {
+	if !to.After(from) {
+		return nil, entities.ErrInvalidTimestamp
+	}
+	if !groupBy.IsValid() {
+		return nil, entities.ErrInvalidEarning
+	}
+	if loc == nil {
+		loc = s.policy.Location
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	totals, err := s.earningRepo.Summarize(ctx, &entities.EarningFilters{
+		DriverID: &driverID,
+		From:     &from,
+		To:       &to,
+	}, groupBy, loc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize earnings: %w", err)
+	}
+
+	return entities.NewEarningsStatement(driverID, from, to, groupBy, loc, totals), nil
+}
+
+// isAllowedCurrency проверяет валюту по списку допустимых
+func (s *earningService) isAllowedCurrency(currency string) bool {
+	if len(s.policy.Currencies) == 0 {
+		return true
+	}
+	for _, allowed := range s.policy.Currencies {
+		if allowed == currency {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type earningFixture struct {
+	service   EarningService
+	shiftRepo *memory.ShiftRepository
+	events    *recordingEventPublisher
+	driverID  uuid.UUID
+}
+
+func newEarningFixture(t *testing.T) *earningFixture {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	driverRepo := memory.NewDriverRepository()
+	driver := newTestDriver("79")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	f := &earningFixture{
+		shiftRepo: memory.NewShiftRepository(),
+		events:    &recordingEventPublisher{},
+		driverID:  driver.ID,
+	}
+	f.service = NewEarningService(memory.NewEarningRepository(), driverRepo, f.shiftRepo, f.events,
+		EarningPolicy{Currencies: []string{"RUB", "EUR"}, Location: moscow}, zap.NewNop())
+	return f
+}
+
+// trip начисляет оплату поездки по новому заказу
+func (f *earningFixture) trip(t *testing.T, at string, fare, commission, bonus float64, currency string) *entities.DriverEarning {
+	t.Helper()
+	earnedAt, err := time.Parse(time.RFC3339, at)
+	require.NoError(t, err)
+	orderID := uuid.New()
+	earning, err := f.service.RecordEarning(context.Background(), f.driverID, &entities.EarningRequest{
+		Type:       entities.EarningTypeTrip,
+		OrderID:    &orderID,
+		Fare:       fare,
+		Commission: commission,
+		Bonus:      bonus,
+		Currency:   currency,
+		EarnedAt:   &earnedAt,
+	})
+	require.NoError(t, err)
+	return earning
+}
+
+func TestEarningService_RecordEarning(t *testing.T) {
+	ctx := context.Background()
+	f := newEarningFixture(t)
+	shift := newTestShift(t, f.shiftRepo, f.driverID, time.Now().Add(-time.Hour))
+	orderID := uuid.New()
+
+	earning, err := f.service.RecordEarning(ctx, f.driverID, &entities.EarningRequest{
+		Type:       entities.EarningTypeTrip,
+		OrderID:    &orderID,
+		ShiftID:    &shift.ID,
+		Fare:       850,
+		Commission: 170,
+		Bonus:      50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 730.0, earning.NetAmount)
+	assert.Equal(t, "RUB", earning.Currency)
+	assert.True(t, f.events.has(eventEarningRecorded))
+
+	// Повторное начисление поездки по тому же заказу отклоняется
+	_, err = f.service.RecordEarning(ctx, f.driverID, &entities.EarningRequest{
+		Type: entities.EarningTypeTrip, OrderID: &orderID, Fare: 850,
+	})
+	assert.Equal(t, entities.ErrEarningExists, err)
+
+	bonus, err := f.service.RecordEarning(ctx, f.driverID, &entities.EarningRequest{
+		Type: entities.EarningTypeBonus, Bonus: 1000, Currency: "eur",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1000.0, bonus.NetAmount)
+	assert.Equal(t, "EUR", bonus.Currency)
+
+	invalid := map[string]*entities.EarningRequest{
+		"trip without order":      {Type: entities.EarningTypeTrip, Fare: 100},
+		"commission above fare":   {Type: entities.EarningTypeTrip, OrderID: &orderID, Fare: 100, Commission: 150},
+		"bonus with fare":         {Type: entities.EarningTypeBonus, Fare: 100, Bonus: 10},
+		"unknown type":            {Type: "refund", Bonus: 10},
+		"currency not allowed":    {Type: entities.EarningTypeBonus, Bonus: 10, Currency: "USD"},
+		"currency wrong format":   {Type: entities.EarningTypeBonus, Bonus: 10, Currency: "RUBL"},
+		"negative trip bonus":     {Type: entities.EarningTypeTrip, OrderID: &orderID, Fare: 100, Bonus: -10},
+		"bonus without an amount": {Type: entities.EarningTypeBonus},
+	}
+	for name, req := range invalid {
+		_, err := f.service.RecordEarning(ctx, f.driverID, req)
+		assert.Error(t, err, name)
+	}
+
+	otherShift := newTestShift(t, f.shiftRepo, uuid.New(), time.Now().Add(-time.Hour))
+	_, err = f.service.RecordEarning(ctx, f.driverID, &entities.EarningRequest{
+		Type: entities.EarningTypeBonus, Bonus: 10, ShiftID: &otherShift.ID,
+	})
+	assert.Equal(t, entities.ErrShiftNotFound, err)
+
+	_, err = f.service.RecordEarning(ctx, uuid.New(), &entities.EarningRequest{Type: entities.EarningTypeBonus, Bonus: 10})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	byShift, err := f.service.ListEarnings(ctx, &entities.EarningFilters{DriverID: &f.driverID, ShiftID: &shift.ID})
+	require.NoError(t, err)
+	require.Len(t, byShift, 1)
+	assert.Equal(t, earning.ID, byShift[0].ID)
+}
+
+func TestEarningService_GetStatement(t *testing.T) {
+	ctx := context.Background()
+	f := newEarningFixture(t)
+
+	f.trip(t, "2024-03-12T10:00:00Z", 1000, 200, 0, "RUB")
+	f.trip(t, "2024-03-12T11:00:00Z", 50, 10, 0, "EUR")
+	bonusAt := time.Date(2024, 3, 13, 10, 0, 0, 0, time.UTC)
+	_, err := f.service.RecordEarning(ctx, f.driverID, &entities.EarningRequest{
+		Type:     entities.EarningTypeBonus,
+		Bonus:    500,
+		EarnedAt: &bonusAt,
+	})
+	require.NoError(t, err)
+	// Воскресенье 22:00 UTC — уже понедельник следующей недели по Москве
+	f.trip(t, "2024-03-17T22:00:00Z", 600, 120, 30, "RUB")
+	// За пределами периода
+	f.trip(t, "2024-03-30T10:00:00Z", 100, 20, 0, "RUB")
+
+	from := time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 25, 0, 0, 0, 0, time.UTC)
+
+	statement, err := f.service.GetStatement(ctx, f.driverID, from, to, entities.EarningsGroupingWeek, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", statement.Timezone)
+	require.Len(t, statement.Periods, 2)
+
+	first := statement.Periods[0]
+	assert.Equal(t, "2024-03-11T00:00:00+03:00", first.Start.Format(time.RFC3339))
+	assert.Equal(t, "2024-03-18T00:00:00+03:00", first.End.Format(time.RFC3339))
+	require.Len(t, first.Totals, 2)
+	assert.Equal(t, "EUR", first.Totals[0].Currency)
+	assert.Equal(t, 40.0, first.Totals[0].NetAmount)
+	assert.Equal(t, entities.EarningAmounts{Currency: "RUB", Entries: 2, Trips: 1, Fare: 1000, Commission: 200, Bonus: 500, NetAmount: 1300}, *first.Totals[1])
+
+	second := statement.Periods[1]
+	assert.Equal(t, "2024-03-18T00:00:00+03:00", second.Start.Format(time.RFC3339))
+	require.Len(t, second.Totals, 1)
+	assert.Equal(t, 510.0, second.Totals[0].NetAmount)
+
+	require.Len(t, statement.Totals, 2)
+	assert.Equal(t, entities.EarningAmounts{Currency: "RUB", Entries: 3, Trips: 2, Fare: 1600, Commission: 320, Bonus: 530, NetAmount: 1810}, *statement.Totals[1])
+
+	// В UTC поездка воскресенья остается в первой неделе
+	statement, err = f.service.GetStatement(ctx, f.driverID, from, to, entities.EarningsGroupingWeek, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, statement.Periods, 1)
+
+	statement, err = f.service.GetStatement(ctx, f.driverID, from, to.AddDate(0, 1, 0), entities.EarningsGroupingMonth, nil)
+	require.NoError(t, err)
+	require.Len(t, statement.Periods, 1)
+	assert.Equal(t, "2024-04-01T00:00:00+03:00", statement.Periods[0].End.Format(time.RFC3339))
+	assert.Equal(t, 3, statement.Totals[1].Trips)
+
+	_, err = f.service.GetStatement(ctx, f.driverID, to, from, entities.EarningsGroupingDay, nil)
+	assert.Equal(t, entities.ErrInvalidTimestamp, err)
+}
@@ -0,0 +1,711 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+)
+
+// eventCatalogService имя сервиса в каталоге событий
+const eventCatalogService = "driver-service"
+
+var (
+	eventCatalogMu sync.RWMutex
+	eventCatalog   = make(map[string]*entities.EventDefinition)
+)
+
+// registerEvent добавляет событие в каталог и возвращает его имя. Сервисы публикуют
+// события только под зарегистрированными именами, поэтому каталог не расходится с кодом
+func registerEvent(name string, version int, description string, fields []entities.EventField, sample map[string]interface{}) string {
+	eventCatalogMu.Lock()
+	defer eventCatalogMu.Unlock()
+
+	if _, exists := eventCatalog[name]; exists {
+		panic(fmt.Sprintf("event %s registered twice", name))
+	}
+	eventCatalog[name] = &entities.EventDefinition{
+		Name:        name,
+		Version:     version,
+		Description: description,
+		Schema:      entities.NewEventSchema(fields),
+		Sample:      sample,
+	}
+	return name
+}
+
+// EventCatalog возвращает каталог публикуемых событий, отсортированный по имени
+func EventCatalog() *entities.EventCatalog {
+	eventCatalogMu.RLock()
+	defer eventCatalogMu.RUnlock()
+
+	catalog := &entities.EventCatalog{
+		Service: eventCatalogService,
+		Events:  make([]*entities.EventDefinition, 0, len(eventCatalog)),
+	}
+	for _, definition := range eventCatalog {
+		catalog.Events = append(catalog.Events, definition)
+	}
+	sort.Slice(catalog.Events, func(i, j int) bool {
+		return catalog.Events[i].Name < catalog.Events[j].Name
+	})
+	return catalog
+}
+
+// IsCataloguedEvent проверяет, описано ли событие в каталоге
+func IsCataloguedEvent(eventType string) bool {
+	eventCatalogMu.RLock()
+	defer eventCatalogMu.RUnlock()
+
+	_, ok := eventCatalog[eventType]
+	return ok
+}
+
+// MarshalEventCatalog сериализует каталог в формат публикуемого артефакта api/events/catalog.json
+func MarshalEventCatalog() ([]byte, error) {
+	data, err := json.MarshalIndent(EventCatalog(), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event catalog: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// field обязательное поле данных события
+func field(name, fieldType, description string) entities.EventField {
+	return entities.EventField{Name: name, Type: fieldType, Description: description}
+}
+
+// optionalField поле данных, которое передается не во всех событиях
+func optionalField(name, fieldType, description string) entities.EventField {
+	return entities.EventField{Name: name, Type: fieldType, Description: description, Optional: true}
+}
+
+// События профиля водителя
+var (
+	eventDriverRegistered = registerEvent("driver.registered", 1,
+		"Водитель зарегистрирован",
+		[]entities.EventField{
+			field("phone", entities.EventFieldString, "Телефон в формате E.164"),
+			field("email", entities.EventFieldString, "Email"),
+			field("name", entities.EventFieldString, "Полное имя"),
+			field("license_number", entities.EventFieldString, "Номер водительского удостоверения"),
+			optionalField("previous_driver_id", entities.EventFieldUUID, "ID удаленной записи водителя при повторной регистрации с тем же номером"),
+		},
+		map[string]interface{}{
+			"phone":          "+79001234567",
+			"email":          "ivan@example.com",
+			"name":           "Иванов Иван Иванович",
+			"license_number": "7700123456",
+		})
+
+	eventDriverStatusChanged = registerEvent("driver.status.changed", 1,
+		"Статус водителя изменен",
+		[]entities.EventField{
+			field("old_status", entities.EventFieldString, "Прежний статус"),
+			field("new_status", entities.EventFieldString, "Новый статус"),
+			field("changed_by", entities.EventFieldString, "Инициатор изменения"),
+		},
+		map[string]interface{}{
+			"old_status": "registered",
+			"new_status": "available",
+			"changed_by": "system",
+		})
+
+	eventDriverStatusOverridden = registerEvent("driver.status.overridden", 1,
+		"Статус водителя установлен администратором в обход правил переходов",
+		[]entities.EventField{
+			field("old_status", entities.EventFieldString, "Прежний статус"),
+			field("new_status", entities.EventFieldString, "Новый статус"),
+			field("reason", entities.EventFieldString, "Причина смены"),
+			field("actor_id", entities.EventFieldString, "Администратор, сменивший статус"),
+		},
+		map[string]interface{}{
+			"old_status": "blocked",
+			"new_status": "available",
+			"reason":     "Блокировка снята после разбора обращения",
+			"actor_id":   "admin-42",
+		})
+
+	eventDriverBlocked = registerEvent("driver.blocked", 1,
+		"Водитель заблокирован администратором или при удалении аккаунта",
+		[]entities.EventField{
+			field("reason", entities.EventFieldString, "Код причины: fraud, document_fraud, safety или account_deleted"),
+			optionalField("blocked_by", entities.EventFieldString, "Администратор, заблокировавший водителя"),
+			optionalField("unblock_at", entities.EventFieldTimestamp, "Время автоматического снятия блокировки"),
+		},
+		map[string]interface{}{
+			"reason":     "safety",
+			"blocked_by": "admin-42",
+			"unblock_at": "2024-03-08T00:00:00Z",
+		})
+
+	eventDriverUnblocked = registerEvent("driver.unblocked", 1,
+		"Блокировка водителя снята администратором или по сроку",
+		[]entities.EventField{
+			field("reason", entities.EventFieldString, "Способ снятия: manual или expired"),
+			field("block_reason", entities.EventFieldString, "Код причины снятой блокировки"),
+			field("unblocked_by", entities.EventFieldString, "Администратор, снявший блокировку, или system"),
+			field("status", entities.EventFieldString, "Статус водителя после снятия"),
+		},
+		map[string]interface{}{
+			"reason":       "expired",
+			"block_reason": "safety",
+			"unblocked_by": "system",
+			"status":       "inactive",
+		})
+
+	eventProfileChangeRequested = registerEvent("driver.profile_change.requested", 1,
+		"Водитель подал заявку на изменение ФИО или данных паспорта и удостоверения; значения полей в событие не попадают",
+		[]entities.EventField{
+			field("change_id", entities.EventFieldUUID, "ID заявки"),
+			field("fields", entities.EventFieldString, "Изменяемые поля через запятую"),
+		},
+		map[string]interface{}{
+			"change_id": "3c2b1a09-8f7e-4d6c-9b5a-4f3e2d1c0b9a",
+			"fields":    "last_name,passport_number,passport_series",
+		})
+
+	eventProfileChangeApproved = registerEvent("driver.profile_change.approved", 1,
+		"Администратор одобрил заявку на изменение профиля, новые значения внесены в профиль водителя",
+		[]entities.EventField{
+			field("change_id", entities.EventFieldUUID, "ID заявки"),
+			field("fields", entities.EventFieldString, "Измененные поля через запятую"),
+			field("reviewed_by", entities.EventFieldString, "Администратор, одобривший заявку"),
+		},
+		map[string]interface{}{
+			"change_id":   "3c2b1a09-8f7e-4d6c-9b5a-4f3e2d1c0b9a",
+			"fields":      "last_name,passport_number,passport_series",
+			"reviewed_by": "admin-42",
+		})
+
+	eventProfileChangeRejected = registerEvent("driver.profile_change.rejected", 1,
+		"Администратор отклонил заявку на изменение профиля, профиль водителя не изменился",
+		[]entities.EventField{
+			field("change_id", entities.EventFieldUUID, "ID заявки"),
+			field("fields", entities.EventFieldString, "Поля заявки через запятую"),
+			field("reviewed_by", entities.EventFieldString, "Администратор, отклонивший заявку"),
+			field("comment", entities.EventFieldString, "Причина отказа"),
+		},
+		map[string]interface{}{
+			"change_id":   "3c2b1a09-8f7e-4d6c-9b5a-4f3e2d1c0b9a",
+			"fields":      "last_name",
+			"reviewed_by": "admin-42",
+			"comment":     "Фамилия не совпадает с паспортом",
+		})
+
+	eventDriverRatingUpdated = registerEvent("driver.rating.updated", 1,
+		"Текущий рейтинг водителя пересчитан",
+		[]entities.EventField{
+			field("new_rating", entities.EventFieldNumber, "Новый рейтинг"),
+			field("previous_rating", entities.EventFieldNumber, "Прежний рейтинг"),
+		},
+		map[string]interface{}{
+			"new_rating":      4.85,
+			"previous_rating": 4.8,
+		})
+
+	eventPaymentHoldPlaced = registerEvent("driver.payment_hold.placed", 1,
+		"Выплаты водителю приостановлены по событию биллинга",
+		[]entities.EventField{
+			field("reason", entities.EventFieldString, "Причина блокировки выплат"),
+		},
+		map[string]interface{}{
+			"reason": "negative_balance",
+		})
+
+	eventPaymentHoldReleased = registerEvent("driver.payment_hold.released", 1,
+		"Блокировка выплат снята; событие без данных",
+		nil, nil)
+
+	eventPersonalDataErased = registerEvent("driver.personal_data.erased", 1,
+		"Персональные данные водителя удалены по запросу субъекта данных",
+		[]entities.EventField{
+			field("driver_id", entities.EventFieldUUID, "ID водителя"),
+			field("requested_by", entities.EventFieldString, "Субъект токена, запросившего удаление"),
+			field("locations_deleted", entities.EventFieldInteger, "Удалено точек местоположения"),
+			field("documents_erased", entities.EventFieldInteger, "Обезличено документов"),
+			field("files_deleted", entities.EventFieldInteger, "Удалено файлов документов"),
+			field("ratings_anonymized", entities.EventFieldInteger, "Оценок без комментариев"),
+			field("shifts_anonymized", entities.EventFieldInteger, "Смен без координат"),
+			field("erased_at", entities.EventFieldTimestamp, "Время удаления"),
+		},
+		map[string]interface{}{
+			"driver_id":          "7c0e5f8a-3b1d-4c2e-9f6a-1d2b3c4d5e6f",
+			"requested_by":       "7c0e5f8a-3b1d-4c2e-9f6a-1d2b3c4d5e6f",
+			"locations_deleted":  1520,
+			"documents_erased":   3,
+			"files_deleted":      3,
+			"ratings_anonymized": 12,
+			"shifts_anonymized":  40,
+			"erased_at":          "2024-03-11T14:30:00Z",
+		})
+)
+
+// События местоположения, смен и расходов
+var (
+	eventLocationUpdated = registerEvent("driver.location.updated", 1,
+		"Принята новая точка местоположения водителя",
+		[]entities.EventField{
+			field("location", entities.EventFieldObject, "Координаты: latitude, longitude и необязательный address"),
+			field("speed", entities.EventFieldNumber, "Скорость, км/ч; 0, если не передана"),
+			field("bearing", entities.EventFieldNumber, "Направление, градусы; 0, если не передано"),
+			field("accuracy", entities.EventFieldNumber, "Точность, м; 0, если не передана"),
+		},
+		map[string]interface{}{
+			"location": map[string]interface{}{"latitude": 55.7558, "longitude": 37.6173},
+			"speed":    60.5,
+			"bearing":  90,
+			"accuracy": 5,
+		})
+
+	eventShiftStarted = registerEvent("driver.shift.started", 1,
+		"Водитель начал смену",
+		[]entities.EventField{
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			optionalField("vehicle_id", entities.EventFieldUUID, "ID автомобиля, если указан при начале смены"),
+		},
+		map[string]interface{}{
+			"shift_id":   "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"vehicle_id": "5e2d1c0b-9a8f-4e7d-8c6b-4a3f2e1d0c9b",
+		})
+
+	eventShiftEnded = registerEvent("driver.shift.ended", 1,
+		"Водитель завершил смену",
+		[]entities.EventField{
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("duration_minutes", entities.EventFieldInteger, "Продолжительность смены, минуты"),
+			field("total_trips", entities.EventFieldInteger, "Поездок за смену"),
+			field("total_earnings", entities.EventFieldNumber, "Заработок за смену"),
+			optionalField("total_distance", entities.EventFieldNumber, "Пробег за смену по истории местоположений, км; без трека — по поездкам"),
+			optionalField("average_speed", entities.EventFieldNumber, "Средняя скорость за смену, км/ч; нет, если точек за смену не было"),
+			optionalField("idle_minutes", entities.EventFieldInteger, "Время простоя на связи за смену, минуты; нет, если точек за смену не было"),
+			optionalField("auto_ended", entities.EventFieldBoolean, "Смена закрыта автоматически: по превышению длительности или потере связи"),
+			optionalField("offline", entities.EventFieldBoolean, "Смена закрыта, потому что водитель перестал выходить на связь"),
+			optionalField("auto_closed", entities.EventFieldBoolean, "Смена закрыта по превышению длительности; подробности в driver.shift.auto_closed"),
+			optionalField("break_minutes", entities.EventFieldInteger, "Время перерывов за смену, минуты"),
+			optionalField("rest_compliant", entities.EventFieldBoolean, "Перерывы смены соответствуют требованиям к отдыху"),
+		},
+		map[string]interface{}{
+			"shift_id":         "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"duration_minutes": 480,
+			"total_trips":      14,
+			"total_earnings":   8250.5,
+			"total_distance":   182.4,
+			"average_speed":    31.6,
+			"idle_minutes":     95,
+			"break_minutes":    45,
+			"rest_compliant":   true,
+		})
+
+	eventShiftBreakStarted = registerEvent("driver.shift.break_started", 1,
+		"Водитель начал перерыв в смене",
+		[]entities.EventField{
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("break_id", entities.EventFieldUUID, "ID перерыва"),
+			field("started_at", entities.EventFieldTimestamp, "Начало перерыва"),
+		},
+		map[string]interface{}{
+			"shift_id":   "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"break_id":   "7c3b2a19-0f8e-4d7c-b6a5-9483d2c1b0af",
+			"started_at": "2024-01-15T10:00:00Z",
+		})
+
+	eventShiftBreakEnded = registerEvent("driver.shift.break_ended", 1,
+		"Водитель закончил перерыв в смене",
+		[]entities.EventField{
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("break_id", entities.EventFieldUUID, "ID перерыва"),
+			field("started_at", entities.EventFieldTimestamp, "Начало перерыва"),
+			field("ended_at", entities.EventFieldTimestamp, "Окончание перерыва"),
+			field("duration_minutes", entities.EventFieldInteger, "Длительность перерыва, минуты"),
+		},
+		map[string]interface{}{
+			"shift_id":         "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"break_id":         "7c3b2a19-0f8e-4d7c-b6a5-9483d2c1b0af",
+			"started_at":       "2024-01-15T10:00:00Z",
+			"ended_at":         "2024-01-15T10:30:00Z",
+			"duration_minutes": 30,
+		})
+
+	eventShiftAutoClosed = registerEvent("driver.shift.auto_closed", 1,
+		"Смена закрыта автоматически по превышению максимальной длительности; итоги посчитаны по истории местоположений",
+		[]entities.EventField{
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("started_at", entities.EventFieldTimestamp, "Начало смены"),
+			field("ended_at", entities.EventFieldTimestamp, "Время автоматического закрытия"),
+			field("duration_minutes", entities.EventFieldInteger, "Продолжительность смены, минуты"),
+			field("max_duration_hours", entities.EventFieldInteger, "Максимальная длительность смены, часы"),
+			field("total_distance", entities.EventFieldNumber, "Пробег за смену по GPS, км"),
+			field("online_minutes", entities.EventFieldInteger, "Время на связи за смену, минуты"),
+			field("total_trips", entities.EventFieldInteger, "Поездок за смену"),
+			field("total_earnings", entities.EventFieldNumber, "Заработок за смену"),
+		},
+		map[string]interface{}{
+			"shift_id":           "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"started_at":         "2024-01-15T06:00:00Z",
+			"ended_at":           "2024-01-15T22:05:00Z",
+			"duration_minutes":   965,
+			"max_duration_hours": 16,
+			"total_distance":     312.4,
+			"online_minutes":     901,
+			"total_trips":        21,
+			"total_earnings":     12480,
+		})
+
+	eventDriverWentOffline = registerEvent("driver.went.offline", 1,
+		"Водитель перестал выходить на связь и переведен в неактивные",
+		[]entities.EventField{
+			field("previous_status", entities.EventFieldString, "Статус до перевода в неактивные"),
+			field("last_seen_at", entities.EventFieldTimestamp, "Время последнего сигнала"),
+			field("offline_seconds", entities.EventFieldInteger, "Сколько секунд не было сигнала"),
+			optionalField("shift_id", entities.EventFieldUUID, "ID закрытой смены, если водитель был на смене"),
+		},
+		map[string]interface{}{
+			"previous_status": "on_shift",
+			"last_seen_at":    "2024-03-11T14:30:00Z",
+			"offline_seconds": 900,
+			"shift_id":        "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+		})
+
+	eventExpenseRecorded = registerEvent("driver.expense.recorded", 1,
+		"Водитель добавил расход в смену",
+		[]entities.EventField{
+			field("expense_id", entities.EventFieldUUID, "ID расхода"),
+			field("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("category", entities.EventFieldString, "Категория: fuel, toll, wash, parking, other"),
+			field("amount", entities.EventFieldNumber, "Сумма"),
+			field("currency", entities.EventFieldString, "Валюта ISO 4217"),
+			field("incurred_at", entities.EventFieldTimestamp, "Время расхода"),
+		},
+		map[string]interface{}{
+			"expense_id":  "3b9d2f1a-6c4e-4a8b-b2d1-7e6f5a4b3c2d",
+			"shift_id":    "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"category":    "fuel",
+			"amount":      2500,
+			"currency":    "RUB",
+			"incurred_at": "2024-03-11T14:30:00Z",
+		})
+
+	eventEarningRecorded = registerEvent("driver.earning.recorded", 1,
+		"Водителю начислена оплата поездки или бонус",
+		[]entities.EventField{
+			field("earning_id", entities.EventFieldUUID, "ID начисления"),
+			field("type", entities.EventFieldString, "Вид: trip, bonus"),
+			optionalField("order_id", entities.EventFieldUUID, "ID заказа поездки"),
+			optionalField("shift_id", entities.EventFieldUUID, "ID смены"),
+			field("fare", entities.EventFieldNumber, "Стоимость поездки"),
+			field("commission", entities.EventFieldNumber, "Комиссия сервиса"),
+			field("bonus", entities.EventFieldNumber, "Бонус"),
+			field("net_amount", entities.EventFieldNumber, "Сумма к выплате: стоимость минус комиссия плюс бонус"),
+			field("currency", entities.EventFieldString, "Валюта ISO 4217"),
+			field("earned_at", entities.EventFieldTimestamp, "Время начисления"),
+		},
+		map[string]interface{}{
+			"earning_id": "5c8e1d2a-3b4f-4a6c-9d7e-8f9a0b1c2d3e",
+			"type":       "trip",
+			"order_id":   "7d1e2f3a-4b5c-4d6e-8f9a-0b1c2d3e4f5a",
+			"shift_id":   "0a4f6c1e-8d2b-4e3a-9c7f-5b6a7d8e9f01",
+			"fare":       850,
+			"commission": 170,
+			"bonus":      50,
+			"net_amount": 730,
+			"currency":   "RUB",
+			"earned_at":  "2024-03-11T14:30:00Z",
+		})
+
+	eventRatingAdded = registerEvent("driver.rating.added", 1,
+		"Водитель получил оценку",
+		[]entities.EventField{
+			field("rating_id", entities.EventFieldUUID, "ID оценки"),
+			field("rating", entities.EventFieldInteger, "Оценка от 1 до 5"),
+			field("rating_type", entities.EventFieldString, "Источник: customer, system, admin, peer, automatic"),
+			optionalField("order_id", entities.EventFieldUUID, "ID заказа, если оценка за заказ"),
+		},
+		map[string]interface{}{
+			"rating_id":   "9f8e7d6c-5b4a-4392-8170-6f5e4d3c2b1a",
+			"rating":      5,
+			"rating_type": "customer",
+			"order_id":    "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+		})
+)
+
+// События документов водителя
+var (
+	eventDocumentVerified = registerEvent("driver.document.verified", 1,
+		"Документ подтвержден проверяющим",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID документа"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("verifier_id", entities.EventFieldString, "ID проверяющего"),
+		},
+		map[string]interface{}{
+			"document_id":   "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"document_type": "driver_license",
+			"verifier_id":   "verifier-1",
+		})
+
+	eventDocumentRejected = registerEvent("driver.document.rejected", 1,
+		"Документ отклонен проверяющим",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID документа"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("verifier_id", entities.EventFieldString, "ID проверяющего"),
+			field("rejection_reason", entities.EventFieldString, "Причина отклонения"),
+		},
+		map[string]interface{}{
+			"document_id":      "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"document_type":    "driver_license",
+			"verifier_id":      "verifier-1",
+			"rejection_reason": "Фото нечитаемо",
+		})
+
+	eventDocumentExpired = registerEvent("driver.document.expired", 1,
+		"Срок действия документа истек",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID документа"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("expiry_date", entities.EventFieldTimestamp, "Дата окончания действия"),
+		},
+		map[string]interface{}{
+			"document_id":   "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"document_type": "driver_license",
+			"expiry_date":   "2024-03-01T00:00:00Z",
+		})
+
+	eventDocumentRenewalSubmitted = registerEvent("driver.document.renewal_submitted", 1,
+		"Водитель загрузил новую версию документа",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID заменяемого документа"),
+			field("renewal_id", entities.EventFieldUUID, "ID новой версии"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("expiry_date", entities.EventFieldTimestamp, "Дата окончания действия новой версии"),
+		},
+		map[string]interface{}{
+			"document_id":   "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"renewal_id":    "2e3f4a5b-6c7d-4e8f-9a0b-1c2d3e4f5a6b",
+			"document_type": "driver_license",
+			"expiry_date":   "2034-03-01T00:00:00Z",
+		})
+
+	eventDocumentResubmitted = registerEvent("driver.document.resubmitted", 1,
+		"Водитель повторно загрузил отклоненный документ",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID документа"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("version", entities.EventFieldInteger, "Номер новой версии документа"),
+			field("resubmission_count", entities.EventFieldInteger, "Сколько раз документ загружен повторно"),
+		},
+		map[string]interface{}{
+			"document_id":        "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"document_type":      "driver_license",
+			"version":            2,
+			"resubmission_count": 1,
+		})
+
+	eventDocumentRenewed = registerEvent("driver.document.renewed", 1,
+		"Новая версия документа подтверждена и заменила прежнюю",
+		[]entities.EventField{
+			field("document_id", entities.EventFieldUUID, "ID замененного документа"),
+			field("renewal_id", entities.EventFieldUUID, "ID новой версии"),
+			field("document_type", entities.EventFieldString, "Тип документа"),
+			field("expiry_date", entities.EventFieldTimestamp, "Дата окончания действия новой версии"),
+		},
+		map[string]interface{}{
+			"document_id":   "6d5c4b3a-2f1e-4d0c-9b8a-7f6e5d4c3b2a",
+			"renewal_id":    "2e3f4a5b-6c7d-4e8f-9a0b-1c2d3e4f5a6b",
+			"document_type": "driver_license",
+			"expiry_date":   "2034-03-01T00:00:00Z",
+		})
+
+	eventDocumentReverificationOverdue = registerEvent("driver.document.reverification_overdue", 1,
+		"Документы водителя не перепроверены к сроку кампании",
+		[]entities.EventField{
+			field("campaign_id", entities.EventFieldUUID, "ID кампании перепроверки"),
+			field("deadline", entities.EventFieldTimestamp, "Срок кампании"),
+		},
+		map[string]interface{}{
+			"campaign_id": "8a7b6c5d-4e3f-4a2b-9c1d-0e9f8a7b6c5d",
+			"deadline":    "2024-04-01T00:00:00Z",
+		})
+
+	eventDocumentReverificationCancelled = registerEvent("driver.document.reverification_cancelled", 1,
+		"Кампания отменена, требование перепроверки документов водителя снято",
+		[]entities.EventField{
+			field("campaign_id", entities.EventFieldUUID, "ID кампании перепроверки"),
+			field("deadline", entities.EventFieldTimestamp, "Срок кампании"),
+		},
+		map[string]interface{}{
+			"campaign_id": "8a7b6c5d-4e3f-4a2b-9c1d-0e9f8a7b6c5d",
+			"deadline":    "2024-04-01T00:00:00Z",
+		})
+)
+
+// События техосмотров и безопасности
+var (
+	eventInspectionSubmitted = registerEvent("driver.inspection.submitted", 1,
+		"Фотографии техосмотра отправлены на проверку",
+		[]entities.EventField{
+			field("inspection_id", entities.EventFieldUUID, "ID техосмотра"),
+			field("vehicle_id", entities.EventFieldUUID, "ID автомобиля"),
+			field("status", entities.EventFieldString, "Статус техосмотра"),
+		},
+		map[string]interface{}{
+			"inspection_id": "4c3b2a1f-0e9d-4c8b-a7f6-5e4d3c2b1a0f",
+			"vehicle_id":    "5e2d1c0b-9a8f-4e7d-8c6b-4a3f2e1d0c9b",
+			"status":        "submitted",
+		})
+
+	eventInspectionCompleted = registerEvent("driver.inspection.completed", 1,
+		"Техосмотр пройден или не пройден",
+		[]entities.EventField{
+			field("inspection_id", entities.EventFieldUUID, "ID техосмотра"),
+			field("vehicle_id", entities.EventFieldUUID, "ID автомобиля"),
+			field("status", entities.EventFieldString, "Итог: passed или failed"),
+		},
+		map[string]interface{}{
+			"inspection_id": "4c3b2a1f-0e9d-4c8b-a7f6-5e4d3c2b1a0f",
+			"vehicle_id":    "5e2d1c0b-9a8f-4e7d-8c6b-4a3f2e1d0c9b",
+			"status":        "passed",
+		})
+
+	eventSecurityAlert = registerEvent("driver.security.alert", 1,
+		"Подозрительная активность в аккаунте водителя",
+		[]entities.EventField{
+			field("event_id", entities.EventFieldUUID, "ID события безопасности"),
+			field("type", entities.EventFieldString, "Тип: new_device, new_country, simultaneous_sessions, location_jump"),
+			field("details", entities.EventFieldObject, "Подробности: сессия, устройство, IP, страна или скорость"),
+			field("sessions_revoked", entities.EventFieldBoolean, "Сессии водителя отозваны автоматически"),
+		},
+		map[string]interface{}{
+			"event_id":         "b1a2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+			"type":             "new_device",
+			"details":          map[string]interface{}{"session_id": "s-1", "device_id": "d-2", "ip_address": "203.0.113.5"},
+			"sessions_revoked": false,
+		})
+)
+
+// События переписки водителя с диспетчером
+var (
+	eventMessageSent = registerEvent("driver.message.sent", 1,
+		"Водитель или диспетчер отправил сообщение в переписку",
+		[]entities.EventField{
+			field("message_id", entities.EventFieldUUID, "ID сообщения"),
+			optionalField("order_id", entities.EventFieldUUID, "ID заказа; без него — общая переписка с водителем"),
+			field("sender", entities.EventFieldString, "Отправитель: driver или dispatcher"),
+			field("kind", entities.EventFieldString, "Тип: arrived, delayed, on_the_way, call_me, text"),
+			optionalField("delay_minutes", entities.EventFieldInteger, "Задержка в минутах для delayed"),
+			optionalField("text", entities.EventFieldString, "Текст сообщения, если указан"),
+			field("created_at", entities.EventFieldTimestamp, "Время отправки"),
+		},
+		map[string]interface{}{
+			"message_id":    "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+			"order_id":      "3f2e1d0c-9b8a-4f7e-8d6c-5b4a3f2e1d0c",
+			"sender":        "driver",
+			"kind":          "delayed",
+			"delay_minutes": 5,
+			"created_at":    "2024-03-11T14:30:00Z",
+		})
+
+	eventMessageDelivered = registerEvent("driver.message.delivered", 1,
+		"Сообщение доставлено получателю",
+		[]entities.EventField{
+			field("message_id", entities.EventFieldUUID, "ID сообщения"),
+			optionalField("order_id", entities.EventFieldUUID, "ID заказа переписки"),
+			field("sender", entities.EventFieldString, "Отправитель сообщения: driver или dispatcher"),
+			field("at", entities.EventFieldTimestamp, "Время доставки"),
+		},
+		map[string]interface{}{
+			"message_id": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+			"order_id":   "3f2e1d0c-9b8a-4f7e-8d6c-5b4a3f2e1d0c",
+			"sender":     "driver",
+			"at":         "2024-03-11T14:30:02Z",
+		})
+
+	eventMessageRead = registerEvent("driver.message.read", 1,
+		"Сообщение прочитано получателем; прочтение подразумевает доставку",
+		[]entities.EventField{
+			field("message_id", entities.EventFieldUUID, "ID сообщения"),
+			optionalField("order_id", entities.EventFieldUUID, "ID заказа переписки"),
+			field("sender", entities.EventFieldString, "Отправитель сообщения: driver или dispatcher"),
+			field("at", entities.EventFieldTimestamp, "Время прочтения"),
+		},
+		map[string]interface{}{
+			"message_id": "9d8c7b6a-5f4e-4d3c-8b2a-1f0e9d8c7b6a",
+			"order_id":   "3f2e1d0c-9b8a-4f7e-8d6c-5b4a3f2e1d0c",
+			"sender":     "driver",
+			"at":         "2024-03-11T14:31:10Z",
+		})
+)
+
+// События геозон
+var (
+	eventGeofenceEntered = registerEvent("driver.geofence.entered", 1,
+		"Водитель вошел в активную геозону",
+		[]entities.EventField{
+			field("geofence_id", entities.EventFieldUUID, "ID геозоны"),
+			field("name", entities.EventFieldString, "Название геозоны"),
+			field("shape", entities.EventFieldString, "Форма: circle или polygon"),
+			field("metadata", entities.EventFieldObject, "Метаданные геозоны, например тип зоны"),
+			field("latitude", entities.EventFieldNumber, "Широта точки, в которой зафиксирован вход"),
+			field("longitude", entities.EventFieldNumber, "Долгота точки, в которой зафиксирован вход"),
+			field("entered_at", entities.EventFieldTimestamp, "Время точки входа"),
+		},
+		map[string]interface{}{
+			"geofence_id": "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d",
+			"name":        "Шереметьево, зона ожидания",
+			"shape":       "polygon",
+			"metadata":    map[string]interface{}{"type": "airport_queue"},
+			"latitude":    55.9726,
+			"longitude":   37.4146,
+			"entered_at":  "2024-03-11T14:30:00Z",
+		})
+
+	eventGeofenceExited = registerEvent("driver.geofence.exited", 1,
+		"Водитель вышел из активной геозоны",
+		[]entities.EventField{
+			field("geofence_id", entities.EventFieldUUID, "ID геозоны"),
+			field("name", entities.EventFieldString, "Название геозоны"),
+			field("shape", entities.EventFieldString, "Форма: circle или polygon"),
+			field("metadata", entities.EventFieldObject, "Метаданные геозоны, например тип зоны"),
+			field("latitude", entities.EventFieldNumber, "Широта точки, в которой зафиксирован выход"),
+			field("longitude", entities.EventFieldNumber, "Долгота точки, в которой зафиксирован выход"),
+			field("entered_at", entities.EventFieldTimestamp, "Время входа в геозону"),
+			field("exited_at", entities.EventFieldTimestamp, "Время точки выхода"),
+			field("dwell_seconds", entities.EventFieldInteger, "Сколько секунд водитель провел в геозоне"),
+		},
+		map[string]interface{}{
+			"geofence_id":   "5a4b3c2d-1e0f-4a9b-8c7d-6e5f4a3b2c1d",
+			"name":          "Шереметьево, зона ожидания",
+			"shape":         "polygon",
+			"metadata":      map[string]interface{}{"type": "airport_queue"},
+			"latitude":      55.9651,
+			"longitude":     37.4102,
+			"entered_at":    "2024-03-11T14:30:00Z",
+			"exited_at":     "2024-03-11T15:12:30Z",
+			"dwell_seconds": 2550,
+		})
+)
+
+// События реферальной программы
+var (
+	eventReferralCompleted = registerEvent("referral.completed", 1,
+		"Приглашенный водитель прошел проверку и выполнил нужное число поездок; пригласивший водитель (driver_id события) получает право на выплату",
+		[]entities.EventField{
+			field("referral_id", entities.EventFieldUUID, "ID реферала"),
+			field("referred_driver_id", entities.EventFieldUUID, "ID приглашенного водителя"),
+			field("code", entities.EventFieldString, "Реферальный код, по которому приглашен водитель"),
+			field("trips_completed", entities.EventFieldInteger, "Поездок приглашенного водителя на момент завершения"),
+			field("required_trips", entities.EventFieldInteger, "Сколько поездок требовалось для выплаты"),
+			field("completed_at", entities.EventFieldTimestamp, "Время завершения реферала"),
+		},
+		map[string]interface{}{
+			"referral_id":        "6e5d4c3b-2a19-4f08-9e7d-6c5b4a392817",
+			"referred_driver_id": "1f2e3d4c-5b6a-4978-8f6e-5d4c3b2a1908",
+			"code":               "K7M2QX9P",
+			"trips_completed":    10,
+			"required_trips":     10,
+			"completed_at":       "2024-03-11T14:30:00Z",
+		})
+)
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moduleRoot корень модуля относительно каталога пакета
+const moduleRoot = "../../.."
+
+// eventNamePattern строковые литералы, похожие на имена событий водителя
+var eventNamePattern = regexp.MustCompile(`^driver\.[a-z_]+(\.[a-z_]+)*$`)
+
+// TestEventCatalog_DocumentsAllEvents падает, если в коде сервиса встречается имя события,
+// не зарегистрированное в каталоге: новое событие нужно описать через registerEvent
+func TestEventCatalog_DocumentsAllEvents(t *testing.T) {
+	found := make(map[string]string)
+	fset := token.NewFileSet()
+
+	for _, dir := range []string{"internal", "cmd"} {
+		err := filepath.WalkDir(filepath.Join(moduleRoot, dir), func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+				return err
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, 0)
+			if err != nil {
+				return err
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				lit, ok := node.(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				if value, err := strconv.Unquote(lit.Value); err == nil && eventNamePattern.MatchString(value) {
+					found[value] = fset.Position(lit.Pos()).String()
+				}
+				return true
+			})
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	require.NotEmpty(t, found)
+	for name, position := range found {
+		assert.True(t, IsCataloguedEvent(name), "event %s used at %s is missing from the event catalog", name, position)
+	}
+}
+
+func TestEventCatalog_SamplesMatchSchema(t *testing.T) {
+	catalog := EventCatalog()
+	require.NotEmpty(t, catalog.Events)
+
+	for _, event := range catalog.Events {
+		assert.Positive(t, event.Version, event.Name)
+		assert.NotEmpty(t, event.Description, event.Name)
+
+		if event.Schema.Type == "null" {
+			assert.Nil(t, event.Sample, event.Name)
+			continue
+		}
+		assert.Equal(t, entities.EventFieldObject, event.Schema.Type, event.Name)
+		for _, required := range event.Schema.Required {
+			assert.Contains(t, event.Sample, required, "%s sample misses required field", event.Name)
+		}
+		for key := range event.Sample {
+			assert.Contains(t, event.Schema.Properties, key, "%s sample has undocumented field", event.Name)
+		}
+	}
+}
+
+// TestEventCatalog_ArtifactUpToDate проверяет, что опубликованный артефакт совпадает с каталогом.
+// Обновление: make events-catalog
+func TestEventCatalog_ArtifactUpToDate(t *testing.T) {
+	expected, err := MarshalEventCatalog()
+	require.NoError(t, err)
+
+	published, err := os.ReadFile(filepath.Join(moduleRoot, "api", "events", "catalog.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(published), "api/events/catalog.json is stale, run make events-catalog")
+}
@@ -0,0 +1,385 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// receiptExtensions допустимые типы файлов чеков и их расширения
+var receiptExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/heic":      ".heic",
+	"application/pdf": ".pdf",
+}
+
+// ExpensePolicy ограничения на расходы водителей
+type ExpensePolicy struct {
+	// Currencies допустимые валюты; первая — валюта заработка и валюта по умолчанию
+	Currencies []string
+	// MaxAmount максимальная сумма одного расхода по категориям (0 — без ограничения)
+	MaxAmount map[entities.ExpenseCategory]float64
+	// MaxPerShift максимальное число расходов в одной смене
+	MaxPerShift int
+	// EditWindow сколько времени после завершения смены можно добавлять расходы и чеки
+	EditWindow time.Duration
+	// MaxReceiptSize максимальный размер файла чека в байтах
+	MaxReceiptSize int64
+}
+
+// baseCurrency возвращает валюту заработка
+func (p ExpensePolicy) baseCurrency() string {
+	if len(p.Currencies) == 0 {
+		return "RUB"
+	}
+	return p.Currencies[0]
+}
+
+// ExpenseService интерфейс для учета расходов водителей во время смен
+type ExpenseService interface {
+	RecordExpense(ctx context.Context, driverID, shiftID uuid.UUID, req *entities.ExpenseRequest) (*entities.ShiftExpense, error)
+	AttachReceipt(ctx context.Context, driverID, expenseID uuid.UUID, upload *FileUpload) (*entities.ShiftExpense, error)
+	GetShiftReport(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.ShiftExpenseReport, error)
+	GetEarningsSummary(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.EarningsSummary, error)
+	ExportCSV(ctx context.Context, w io.Writer, filters *entities.ExpenseFilters, markExported bool) (int, error)
+}
+
+// expenseService реализация ExpenseService
+type expenseService struct {
+	expenseRepo repositories.ExpenseRepository
+	shiftRepo   repositories.ShiftRepository
+	storage     FileStorage
+	eventBus    EventPublisher
+	policy      ExpensePolicy
+	logger      *zap.Logger
+}
+
+// NewExpenseService создает новый ExpenseService
+func NewExpenseService(
+	expenseRepo repositories.ExpenseRepository,
+	shiftRepo repositories.ShiftRepository,
+	storage FileStorage,
+	eventBus EventPublisher,
+	policy ExpensePolicy,
+	logger *zap.Logger,
+) ExpenseService {
+	return &expenseService{
+		expenseRepo: expenseRepo,
+		shiftRepo:   shiftRepo,
+		storage:     storage,
+		eventBus:    eventBus,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// RecordExpense добавляет расход в смену водителя
+func (s *expenseService) RecordExpense(ctx context.Context, driverID, shiftID uuid.UUID, req *entities.ExpenseRequest) (*entities.ShiftExpense, error) {
+	shift, err := s.getOpenShift(ctx, driverID, shiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		currency = s.policy.baseCurrency()
+	}
+
+	incurredAt := time.Now()
+	if req.IncurredAt != nil {
+		incurredAt = *req.IncurredAt
+	}
+	// Расход должен приходиться на время смены
+	if incurredAt.Before(shift.StartTime) || (shift.EndTime != nil && incurredAt.After(*shift.EndTime)) {
+		return nil, entities.ErrInvalidTimestamp
+	}
+
+	expense := entities.NewShiftExpense(shift, req.Category, req.Amount, currency, incurredAt)
+	expense.Description = req.Description
+
+	if err := expense.Validate(); err != nil {
+		return nil, err
+	}
+	if !s.isAllowedCurrency(expense.Currency) {
+		return nil, entities.ErrInvalidCurrency
+	}
+	if limit := s.policy.MaxAmount[expense.Category]; limit > 0 && expense.Amount > limit {
+		return nil, entities.ErrExpenseLimitExceeded
+	}
+
+	if s.policy.MaxPerShift > 0 {
+		count, err := s.expenseRepo.Count(ctx, &entities.ExpenseFilters{ShiftID: &shift.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to count shift expenses: %w", err)
+		}
+		if count >= s.policy.MaxPerShift {
+			return nil, entities.ErrExpenseLimitExceeded
+		}
+	}
+
+	if err := s.expenseRepo.Create(ctx, expense); err != nil {
+		s.logger.Error("Failed to create expense",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+		return nil, fmt.Errorf("failed to record expense: %w", err)
+	}
+
+	// Учетная система получает расходы из событий
+	eventData := map[string]interface{}{
+		"expense_id":  expense.ID.String(),
+		"shift_id":    expense.ShiftID.String(),
+		"category":    expense.Category,
+		"amount":      expense.Amount,
+		"currency":    expense.Currency,
+		"incurred_at": expense.IncurredAt,
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventExpenseRecorded, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish expense recorded event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Expense recorded",
+		zap.String("expense_id", expense.ID.String()),
+		zap.String("shift_id", expense.ShiftID.String()),
+		zap.String("category", string(expense.Category)),
+	)
+
+	return expense, nil
+}
+
+// AttachReceipt загружает фотографию чека к расходу
+func (s *expenseService) AttachReceipt(ctx context.Context, driverID, expenseID uuid.UUID, upload *FileUpload) (*entities.ShiftExpense, error) {
+	extension, ok := receiptExtensions[upload.ContentType]
+	if !ok || upload.Size <= 0 || (s.policy.MaxReceiptSize > 0 && upload.Size > s.policy.MaxReceiptSize) {
+		return nil, entities.ErrInvalidReceipt
+	}
+
+	expense, err := s.expenseRepo.GetByID(ctx, expenseID)
+	if err != nil {
+		return nil, err
+	}
+	if expense.DriverID != driverID {
+		return nil, entities.ErrExpenseNotFound
+	}
+
+	if _, err := s.getOpenShift(ctx, driverID, expense.ShiftID); err != nil {
+		return nil, err
+	}
+
+	key := path.Join("receipts", driverID.String(), expense.ID.String()+extension)
+	url, err := s.storage.Save(ctx, key, upload.ContentType, io.LimitReader(upload.Body, upload.Size))
+	if err != nil {
+		s.logger.Error("Failed to save receipt",
+			zap.Error(err),
+			zap.String("expense_id", expense.ID.String()),
+		)
+		return nil, fmt.Errorf("failed to save receipt: %w", err)
+	}
+
+	expense.ReceiptURL = &url
+	if err := s.expenseRepo.Update(ctx, expense); err != nil {
+		return nil, fmt.Errorf("failed to attach receipt: %w", err)
+	}
+
+	return expense, nil
+}
+
+// GetShiftReport возвращает смену с расходами и заработком за вычетом расходов
+func (s *expenseService) GetShiftReport(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.ShiftExpenseReport, error) {
+	shift, err := s.getDriverShift(ctx, driverID, shiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := &entities.ExpenseFilters{ShiftID: &shift.ID}
+	expenses, err := s.expenseRepo.List(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift expenses: %w", err)
+	}
+
+	totals, err := s.expenseRepo.Summarize(ctx, filters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize shift expenses: %w", err)
+	}
+	summary := entities.NewExpenseSummary(totals)
+
+	return &entities.ShiftExpenseReport{
+		Shift:       shift.ToResponse(),
+		Expenses:    expenses,
+		Summary:     summary,
+		NetEarnings: shift.TotalEarnings - summary.Totals[s.policy.baseCurrency()],
+	}, nil
+}
+
+// GetEarningsSummary возвращает заработок и расходы водителя за период
+func (s *expenseService) GetEarningsSummary(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.EarningsSummary, error) {
+	if !to.After(from) {
+		return nil, entities.ErrInvalidTimestamp
+	}
+
+	shifts, err := s.shiftRepo.List(ctx, &entities.ShiftFilters{
+		DriverID: &driverID,
+		From:     &from,
+		To:       &to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+
+	totals, err := s.expenseRepo.Summarize(ctx, &entities.ExpenseFilters{
+		DriverID: &driverID,
+		From:     &from,
+		To:       &to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize expenses: %w", err)
+	}
+
+	summary := &entities.EarningsSummary{
+		DriverID: driverID,
+		From:     from,
+		To:       to,
+		Currency: s.policy.baseCurrency(),
+		Expenses: entities.NewExpenseSummary(totals),
+	}
+	for _, shift := range shifts {
+		if shift.Status == entities.ShiftStatusCancelled {
+			continue
+		}
+		summary.Shifts++
+		summary.Trips += shift.TotalTrips
+		summary.TotalEarnings += shift.TotalEarnings
+	}
+	summary.NetEarnings = summary.TotalEarnings - summary.Expenses.Totals[summary.Currency]
+
+	return summary, nil
+}
+
+// expenseCSVHeader колонки выгрузки расходов для учетной системы
+var expenseCSVHeader = []string{
+	"expense_id", "driver_id", "shift_id", "incurred_at", "category",
+	"amount", "currency", "description", "receipt_url",
+}
+
+// ExportCSV выгружает расходы в CSV для учетной системы и при необходимости отмечает их выгруженными
+func (s *expenseService) ExportCSV(ctx context.Context, w io.Writer, filters *entities.ExpenseFilters, markExported bool) (int, error) {
+	if filters == nil {
+		filters = &entities.ExpenseFilters{}
+	}
+	if filters.From != nil && filters.To != nil && !filters.To.After(*filters.From) {
+		return 0, entities.ErrInvalidTimestamp
+	}
+
+	expenses, err := s.expenseRepo.List(ctx, filters)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expenses: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(expenseCSVHeader); err != nil {
+		return 0, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(expenses))
+	for _, expense := range expenses {
+		var description, receiptURL string
+		if expense.Description != nil {
+			description = *expense.Description
+		}
+		if expense.ReceiptURL != nil {
+			receiptURL = *expense.ReceiptURL
+		}
+
+		if err := writer.Write([]string{
+			expense.ID.String(),
+			expense.DriverID.String(),
+			expense.ShiftID.String(),
+			expense.IncurredAt.UTC().Format(time.RFC3339),
+			string(expense.Category),
+			strconv.FormatFloat(expense.Amount, 'f', 2, 64),
+			expense.Currency,
+			description,
+			receiptURL,
+		}); err != nil {
+			return 0, err
+		}
+		ids = append(ids, expense.ID)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return 0, err
+	}
+
+	if markExported {
+		// Выгрузка уже отправлена клиенту: ошибка отметки приведет лишь к повторной выгрузке
+		if _, err := s.expenseRepo.MarkExported(ctx, ids, time.Now()); err != nil {
+			s.logger.Error("Failed to mark expenses exported", zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Expenses exported",
+		zap.Int("count", len(expenses)),
+		zap.Bool("mark_exported", markExported),
+	)
+
+	return len(expenses), nil
+}
+
+// getDriverShift получает смену и проверяет, что она принадлежит водителю
+func (s *expenseService) getDriverShift(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.DriverShift, error) {
+	shift, err := s.shiftRepo.GetByID(ctx, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	if shift.DriverID != driverID {
+		return nil, entities.ErrShiftNotFound
+	}
+	return shift, nil
+}
+
+// getOpenShift получает смену водителя, в которую еще можно добавлять расходы
+func (s *expenseService) getOpenShift(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.DriverShift, error) {
+	shift, err := s.getDriverShift(ctx, driverID, shiftID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch shift.Status {
+	case entities.ShiftStatusActive, entities.ShiftStatusSuspended:
+		return shift, nil
+	case entities.ShiftStatusCompleted:
+		if shift.EndTime != nil && time.Since(*shift.EndTime) <= s.policy.EditWindow {
+			return shift, nil
+		}
+	}
+	return nil, entities.ErrExpenseWindowClosed
+}
+
+// isAllowedCurrency проверяет валюту по списку допустимых
+func (s *expenseService) isAllowedCurrency(currency string) bool {
+	if len(s.policy.Currencies) == 0 {
+		return true
+	}
+	for _, allowed := range s.policy.Currencies {
+		if allowed == currency {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeFileStorage запоминает сохраненные файлы в памяти
+type fakeFileStorage struct {
+	files map[string][]byte
+}
+
+func (s *fakeFileStorage) Save(ctx context.Context, key string, contentType string, body io.Reader) (string, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	s.files[key] = data
+	return "https://receipts.example.com/" + key, nil
+}
+
+func (s *fakeFileStorage) Delete(ctx context.Context, url string) error {
+	delete(s.files, strings.TrimPrefix(url, "https://receipts.example.com/"))
+	return nil
+}
+
+func newTestExpenseService(t *testing.T) (ExpenseService, *memory.ShiftRepository, *fakeFileStorage, *recordingEventPublisher) {
+	shiftRepo := memory.NewShiftRepository()
+	receipts := &fakeFileStorage{files: make(map[string][]byte)}
+	events := &recordingEventPublisher{}
+
+	service := NewExpenseService(
+		memory.NewExpenseRepository(),
+		shiftRepo,
+		receipts,
+		events,
+		ExpensePolicy{
+			Currencies:     []string{"RUB", "EUR"},
+			MaxAmount:      map[entities.ExpenseCategory]float64{entities.ExpenseCategoryWash: 1000},
+			MaxPerShift:    3,
+			EditWindow:     24 * time.Hour,
+			MaxReceiptSize: 1024,
+		},
+		zap.NewNop(),
+	)
+	return service, shiftRepo, receipts, events
+}
+
+func newTestShift(t *testing.T, shiftRepo *memory.ShiftRepository, driverID uuid.UUID, started time.Time) *entities.DriverShift {
+	shift := entities.NewDriverShift(driverID, nil, nil)
+	shift.StartTime = started
+	require.NoError(t, shiftRepo.Create(context.Background(), shift))
+	return shift
+}
+
+func TestExpenseService_RecordExpense(t *testing.T) {
+	ctx := context.Background()
+	service, shiftRepo, _, events := newTestExpenseService(t)
+
+	driverID := uuid.New()
+	shift := newTestShift(t, shiftRepo, driverID, time.Now().Add(-2*time.Hour))
+
+	expense, err := service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{
+		Category: entities.ExpenseCategoryFuel,
+		Amount:   2500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "RUB", expense.Currency, "base currency is used by default")
+	assert.True(t, events.has("driver.expense.recorded"))
+
+	_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: "snacks", Amount: 100})
+	assert.Equal(t, entities.ErrInvalidExpenseCategory, err)
+
+	_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryToll, Amount: 100, Currency: "USD"})
+	assert.Equal(t, entities.ErrInvalidCurrency, err)
+
+	_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryWash, Amount: 1500})
+	assert.Equal(t, entities.ErrExpenseLimitExceeded, err)
+
+	before := shift.StartTime.Add(-time.Minute)
+	_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryToll, Amount: 100, IncurredAt: &before})
+	assert.Equal(t, entities.ErrInvalidTimestamp, err)
+
+	_, err = service.RecordExpense(ctx, uuid.New(), shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryToll, Amount: 100})
+	assert.Equal(t, entities.ErrShiftNotFound, err, "foreign shift is not visible")
+
+	for i := 0; i < 2; i++ {
+		_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryParking, Amount: 50})
+		require.NoError(t, err)
+	}
+	_, err = service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryParking, Amount: 50})
+	assert.Equal(t, entities.ErrExpenseLimitExceeded, err, "per-shift limit")
+}
+
+func TestExpenseService_EditWindow(t *testing.T) {
+	ctx := context.Background()
+	service, shiftRepo, _, _ := newTestExpenseService(t)
+
+	driverID := uuid.New()
+	shift := newTestShift(t, shiftRepo, driverID, time.Now().Add(-50*time.Hour))
+	shift.End(nil)
+	ended := time.Now().Add(-48 * time.Hour)
+	shift.EndTime = &ended
+	require.NoError(t, shiftRepo.Update(ctx, shift))
+
+	incurred := ended.Add(-time.Hour)
+	_, err := service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{
+		Category:   entities.ExpenseCategoryFuel,
+		Amount:     1000,
+		IncurredAt: &incurred,
+	})
+	assert.Equal(t, entities.ErrExpenseWindowClosed, err)
+
+	recent := newTestShift(t, shiftRepo, driverID, time.Now().Add(-3*time.Hour))
+	recent.End(nil)
+	require.NoError(t, shiftRepo.Update(ctx, recent))
+
+	incurred = time.Now().Add(-time.Hour)
+	_, err = service.RecordExpense(ctx, driverID, recent.ID, &entities.ExpenseRequest{
+		Category:   entities.ExpenseCategoryFuel,
+		Amount:     1000,
+		IncurredAt: &incurred,
+	})
+	assert.NoError(t, err, "completed shift accepts expenses within the edit window")
+}
+
+func TestExpenseService_AttachReceipt(t *testing.T) {
+	ctx := context.Background()
+	service, shiftRepo, receipts, _ := newTestExpenseService(t)
+
+	driverID := uuid.New()
+	shift := newTestShift(t, shiftRepo, driverID, time.Now().Add(-time.Hour))
+	expense, err := service.RecordExpense(ctx, driverID, shift.ID, &entities.ExpenseRequest{Category: entities.ExpenseCategoryToll, Amount: 300})
+	require.NoError(t, err)
+
+	body := []byte("receipt-image")
+	updated, err := service.AttachReceipt(ctx, driverID, expense.ID, &FileUpload{
+		ContentType: "image/png",
+		Size:        int64(len(body)),
+		Body:        bytes.NewReader(body),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, updated.ReceiptURL)
+
+	key := "receipts/" + driverID.String() + "/" + expense.ID.String() + ".png"
+	assert.Equal(t, "https://receipts.example.com/"+key, *updated.ReceiptURL)
+	assert.Equal(t, body, receipts.files[key])
+
+	_, err = service.AttachReceipt(ctx, driverID, expense.ID, &FileUpload{ContentType: "text/plain", Size: 10, Body: strings.NewReader("0123456789")})
+	assert.Equal(t, entities.ErrInvalidReceipt, err)
+
+	_, err = service.AttachReceipt(ctx, driverID, expense.ID, &FileUpload{ContentType: "image/jpeg", Size: 4096, Body: bytes.NewReader(make([]byte, 4096))})
+	assert.Equal(t, entities.ErrInvalidReceipt, err, "receipt exceeds size limit")
+
+	_, err = service.AttachReceipt(ctx, uuid.New(), expense.ID, &FileUpload{ContentType: "image/png", Size: int64(len(body)), Body: bytes.NewReader(body)})
+	assert.Equal(t, entities.ErrExpenseNotFound, err)
+}
+
+func TestExpenseService_ReportsAndExport(t *testing.T) {
+	ctx := context.Background()
+	service, shiftRepo, _, _ := newTestExpenseService(t)
+
+	driverID := uuid.New()
+	shift := newTestShift(t, shiftRepo, driverID, time.Now().Add(-3*time.Hour))
+	shift.AddTrip(12.5, 4000)
+	require.NoError(t, shiftRepo.Update(ctx, shift))
+
+	for _, req := range []*entities.ExpenseRequest{
+		{Category: entities.ExpenseCategoryFuel, Amount: 1200},
+		{Category: entities.ExpenseCategoryToll, Amount: 300},
+		{Category: entities.ExpenseCategoryToll, Amount: 10, Currency: "EUR"},
+	} {
+		_, err := service.RecordExpense(ctx, driverID, shift.ID, req)
+		require.NoError(t, err)
+	}
+
+	report, err := service.GetShiftReport(ctx, driverID, shift.ID)
+	require.NoError(t, err)
+	assert.Len(t, report.Expenses, 3)
+	assert.Equal(t, 1500.0, report.Summary.Totals["RUB"])
+	assert.Equal(t, 10.0, report.Summary.Totals["EUR"])
+	assert.Equal(t, 2500.0, report.NetEarnings, "only base currency expenses are deducted")
+
+	summary, err := service.GetEarningsSummary(ctx, driverID, time.Now().Add(-24*time.Hour), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.Shifts)
+	assert.Equal(t, 4000.0, summary.TotalEarnings)
+	assert.Equal(t, 2500.0, summary.NetEarnings)
+
+	_, err = service.GetEarningsSummary(ctx, driverID, time.Now(), time.Now().Add(-time.Hour))
+	assert.Equal(t, entities.ErrInvalidTimestamp, err)
+
+	notExported := false
+	var buf bytes.Buffer
+	count, err := service.ExportCSV(ctx, &buf, &entities.ExpenseFilters{DriverID: &driverID, Exported: &notExported}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+	assert.Equal(t, expenseCSVHeader, rows[0])
+
+	buf.Reset()
+	count, err = service.ExportCSV(ctx, &buf, &entities.ExpenseFilters{DriverID: &driverID, Exported: &notExported}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count, "exported expenses are not exported again")
+}
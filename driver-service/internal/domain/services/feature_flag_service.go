@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FeatureFlagService интерфейс для флагов функций и конфигурации приложения водителя
+type FeatureFlagService interface {
+	CreateFlag(ctx context.Context, req *entities.FeatureFlagRequest) (*entities.FeatureFlag, error)
+	GetFlag(ctx context.Context, id uuid.UUID) (*entities.FeatureFlag, error)
+	// UpdateFlag заменяет флаг; ключ флага не меняется
+	UpdateFlag(ctx context.Context, id uuid.UUID, req *entities.FeatureFlagRequest) (*entities.FeatureFlag, error)
+	DeleteFlag(ctx context.Context, id uuid.UUID) error
+	ListFlags(ctx context.Context) ([]*entities.FeatureFlag, error)
+
+	// GetAppConfig вычисляет флаги и параметры приложения водителя. Без appVersion версия
+	// берется из устройства запроса (X-Device-ID) или из последнего активного устройства водителя
+	GetAppConfig(ctx context.Context, driverID uuid.UUID, appVersion string) (*entities.AppConfig, error)
+}
+
+// FeatureFlagPolicy параметры флагов функций
+type FeatureFlagPolicy struct {
+	// CacheTTL время жизни кэша флагов; изменения, сделанные через другой экземпляр,
+	// учитываются не позже чем через CacheTTL. 0 — читать флаги при каждом запросе
+	CacheTTL time.Duration
+	// Defaults параметры приложения, которые не переопределил ни один включенный флаг
+	Defaults entities.AppParameters
+}
+
+// featureFlagService реализация FeatureFlagService
+type featureFlagService struct {
+	flagRepo   repositories.FeatureFlagRepository
+	driverRepo repositories.DriverRepository
+	deviceRepo repositories.DeviceRepository
+	policy     FeatureFlagPolicy
+	logger     *zap.Logger
+
+	mu       sync.RWMutex
+	flags    []*entities.FeatureFlag
+	loadedAt time.Time
+}
+
+// NewFeatureFlagService создает новый FeatureFlagService. deviceRepo может быть nil,
+// если версия приложения передается только в запросе
+func NewFeatureFlagService(
+	flagRepo repositories.FeatureFlagRepository,
+	driverRepo repositories.DriverRepository,
+	deviceRepo repositories.DeviceRepository,
+	policy FeatureFlagPolicy,
+	logger *zap.Logger,
+) FeatureFlagService {
+	return &featureFlagService{
+		flagRepo:   flagRepo,
+		driverRepo: driverRepo,
+		deviceRepo: deviceRepo,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// CreateFlag создает флаг
+func (s *featureFlagService) CreateFlag(ctx context.Context, req *entities.FeatureFlagRequest) (*entities.FeatureFlag, error) {
+	flag := entities.NewFeatureFlag(req)
+	if err := flag.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.flagRepo.Create(ctx, flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("Feature flag created",
+		zap.String("flag_id", flag.ID.String()),
+		zap.String("key", flag.Key),
+		zap.Bool("enabled", flag.Enabled),
+	)
+	return flag, nil
+}
+
+// GetFlag получает флаг по ID
+func (s *featureFlagService) GetFlag(ctx context.Context, id uuid.UUID) (*entities.FeatureFlag, error) {
+	return s.flagRepo.GetByID(ctx, id)
+}
+
+// UpdateFlag заменяет описание, состояние, правила и параметры флага
+func (s *featureFlagService) UpdateFlag(ctx context.Context, id uuid.UUID, req *entities.FeatureFlagRequest) (*entities.FeatureFlag, error) {
+	flag, err := s.flagRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(flag)
+	flag.UpdatedAt = time.Now()
+	if err := flag.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.flagRepo.Update(ctx, flag); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("Feature flag updated",
+		zap.String("flag_id", flag.ID.String()),
+		zap.String("key", flag.Key),
+		zap.Bool("enabled", flag.Enabled),
+	)
+	return flag, nil
+}
+
+// DeleteFlag удаляет флаг
+func (s *featureFlagService) DeleteFlag(ctx context.Context, id uuid.UUID) error {
+	if err := s.flagRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	s.logger.Info("Feature flag deleted", zap.String("flag_id", id.String()))
+	return nil
+}
+
+// ListFlags получает все флаги по ключу
+func (s *featureFlagService) ListFlags(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	return s.flagRepo.List(ctx)
+}
+
+// GetAppConfig вычисляет флаги по городу и версии приложения водителя. Параметры включенных
+// флагов применяются в порядке ключа, поэтому при пересечении действует флаг с большим ключом
+func (s *featureFlagService) GetAppConfig(ctx context.Context, driverID uuid.UUID, appVersion string) (*entities.AppConfig, error) {
+	if appVersion != "" && !entities.IsValidAppVersion(appVersion) {
+		return nil, entities.ErrInvalidAppVersion
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if appVersion == "" {
+		appVersion, err = s.deviceAppVersion(ctx, driverID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	flags, err := s.loadFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := entities.FeatureFlagTarget{
+		DriverID:   driverID,
+		City:       driver.OperatingCity(),
+		AppVersion: appVersion,
+	}
+	config := &entities.AppConfig{
+		DriverID:    driverID,
+		City:        target.City,
+		AppVersion:  appVersion,
+		Flags:       make(map[string]bool, len(flags)),
+		Parameters:  s.policy.Defaults,
+		GeneratedAt: time.Now(),
+	}
+	for _, flag := range flags {
+		enabled := flag.EnabledFor(target)
+		config.Flags[flag.Key] = enabled
+		if enabled {
+			flag.Parameters.Apply(&config.Parameters)
+		}
+	}
+
+	return config, nil
+}
+
+// deviceAppVersion возвращает версию приложения устройства запроса, а без него — последнего
+// активного устройства водителя (не трекера). Пустая строка, если версия неизвестна
+func (s *featureFlagService) deviceAppVersion(ctx context.Context, driverID uuid.UUID) (string, error) {
+	if s.deviceRepo == nil {
+		return "", nil
+	}
+
+	if actor, ok := entities.AuditActorFromContext(ctx); ok && actor.DeviceID != "" {
+		device, err := s.deviceRepo.Get(ctx, driverID, actor.DeviceID)
+		if err != nil && err != entities.ErrDeviceNotFound {
+			return "", err
+		}
+		if err == nil && !device.IsRevoked() {
+			return validAppVersion(device.AppVersion), nil
+		}
+	}
+
+	devices, err := s.deviceRepo.ListByDriverID(ctx, driverID)
+	if err != nil {
+		return "", err
+	}
+	for _, device := range devices {
+		if !device.IsRevoked() && !device.IsTracker() {
+			return validAppVersion(device.AppVersion), nil
+		}
+	}
+	return "", nil
+}
+
+// validAppVersion возвращает версию, если она разбирается, иначе пустую строку: версия
+// устройства не проверялась при регистрации
+func validAppVersion(version string) string {
+	if entities.IsValidAppVersion(version) {
+		return version
+	}
+	return ""
+}
+
+// loadFlags возвращает флаги из кэша или из репозитория
+func (s *featureFlagService) loadFlags(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	s.mu.RLock()
+	flags, loadedAt := s.flags, s.loadedAt
+	s.mu.RUnlock()
+
+	if !loadedAt.IsZero() && time.Since(loadedAt) < s.policy.CacheTTL {
+		return flags, nil
+	}
+
+	flags, err := s.flagRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	s.mu.Lock()
+	s.flags, s.loadedAt = flags, time.Now()
+	s.mu.Unlock()
+
+	return flags, nil
+}
+
+// invalidate сбрасывает кэш флагов после изменений через этот экземпляр
+func (s *featureFlagService) invalidate() {
+	s.mu.Lock()
+	s.flags, s.loadedAt = nil, time.Time{}
+	s.mu.Unlock()
+}
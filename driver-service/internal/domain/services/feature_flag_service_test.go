@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFeatureFlagService_GetAppConfig(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	deviceRepo := memory.NewDeviceRepository()
+	defaults := entities.AppParameters{GPSUploadIntervalSeconds: 5, LocationBatchSize: 20, HeartbeatIntervalSeconds: 60}
+	service := NewFeatureFlagService(memory.NewFeatureFlagRepository(), driverRepo, deviceRepo,
+		FeatureFlagPolicy{CacheTTL: time.Minute, Defaults: defaults}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000901", "flags@example.com", "Иван", "Флаговый", "LIC901")
+	driver.Metadata = entities.Metadata{entities.DriverMetaCity: "Москва"}
+	driver.RefreshShardKey()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	// Без флагов приложение получает параметры по умолчанию
+	config, err := service.GetAppConfig(ctx, driver.ID, "")
+	require.NoError(t, err)
+	assert.Empty(t, config.Flags)
+	assert.Equal(t, defaults, config.Parameters)
+	assert.Equal(t, "москва", config.City)
+
+	// Созданный флаг учитывается сразу, несмотря на кэш
+	interval := 2
+	_, err = service.CreateFlag(ctx, &entities.FeatureFlagRequest{
+		Key:        "fast_gps",
+		Enabled:    true,
+		Rules:      []entities.FeatureFlagRule{{Cities: []string{"Москва"}, MinAppVersion: "4.10"}},
+		Parameters: entities.AppParameterOverrides{GPSUploadIntervalSeconds: &interval},
+	})
+	require.NoError(t, err)
+	_, err = service.CreateFlag(ctx, &entities.FeatureFlagRequest{Key: "fast_gps"})
+	assert.ErrorIs(t, err, entities.ErrFeatureFlagExists)
+	disabled, err := service.CreateFlag(ctx, &entities.FeatureFlagRequest{Key: "chat"})
+	require.NoError(t, err)
+
+	// Версия неизвестна: правило с границей версии не проходит
+	config, err = service.GetAppConfig(ctx, driver.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"fast_gps": false, "chat": false}, config.Flags)
+	assert.Equal(t, 5, config.Parameters.GPSUploadIntervalSeconds)
+
+	// Версия берется из зарегистрированного устройства
+	require.NoError(t, deviceRepo.Register(ctx, entities.NewDriverDevice(driver.ID, &entities.DeviceRegistration{
+		DeviceID: "phone-1", Platform: entities.DevicePlatformAndroid, AppVersion: "4.12.0",
+	}, time.Now())))
+	config, err = service.GetAppConfig(ctx, driver.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, "4.12.0", config.AppVersion)
+	assert.True(t, config.Flags["fast_gps"])
+	assert.Equal(t, 2, config.Parameters.GPSUploadIntervalSeconds)
+	assert.Equal(t, 20, config.Parameters.LocationBatchSize)
+
+	// Версия из запроса важнее версии устройства
+	config, err = service.GetAppConfig(ctx, driver.ID, "4.9.3")
+	require.NoError(t, err)
+	assert.False(t, config.Flags["fast_gps"])
+
+	_, err = service.UpdateFlag(ctx, disabled.ID, &entities.FeatureFlagRequest{Key: "chat", Enabled: true})
+	require.NoError(t, err)
+	config, err = service.GetAppConfig(ctx, driver.ID, "4.9.3")
+	require.NoError(t, err)
+	assert.True(t, config.Flags["chat"])
+
+	_, err = service.GetAppConfig(ctx, driver.ID, "latest")
+	assert.ErrorIs(t, err, entities.ErrInvalidAppVersion)
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// fleetScopedDriverService ограничивает DriverService автопарками из контекста запроса
+// (entities.WithFleetScope): партнер видит и изменяет только водителей своих автопарков.
+// Водитель чужого автопарка для него не существует. Запросы без области не ограничиваются
+type fleetScopedDriverService struct {
+	DriverService
+	fleetRepo repositories.FleetRepository
+}
+
+// NewFleetScopedDriverService оборачивает DriverService ограничением по автопаркам.
+// Автопарк нового или измененного водителя должен существовать
+func NewFleetScopedDriverService(next DriverService, fleetRepo repositories.FleetRepository) DriverService {
+	return &fleetScopedDriverService{
+		DriverService: next,
+		fleetRepo:     fleetRepo,
+	}
+}
+
+// CreateDriver регистрирует водителя в автопарке из области запроса. Если у партнера
+// один автопарк, водитель без автопарка прикрепляется к нему
+func (s *fleetScopedDriverService) CreateDriver(ctx context.Context, driver *entities.Driver) (*entities.Driver, error) {
+	if fleetIDs, scoped := entities.FleetScopeFromContext(ctx); scoped && driver.FleetID == nil && len(fleetIDs) == 1 {
+		fleetID := fleetIDs[0]
+		driver.FleetID = &fleetID
+	}
+	if err := s.checkFleet(ctx, driver.FleetID); err != nil {
+		return nil, err
+	}
+	return s.DriverService.CreateDriver(ctx, driver)
+}
+
+// GetDriverByID получает водителя, если он доступен в области запроса
+func (s *fleetScopedDriverService) GetDriverByID(ctx context.Context, id uuid.UUID) (*entities.Driver, error) {
+	return s.visible(ctx)(s.DriverService.GetDriverByID(ctx, id))
+}
+
+// GetDriverByPhone получает водителя по телефону, если он доступен в области запроса
+func (s *fleetScopedDriverService) GetDriverByPhone(ctx context.Context, phone string) (*entities.Driver, error) {
+	return s.visible(ctx)(s.DriverService.GetDriverByPhone(ctx, phone))
+}
+
+// GetDriverByEmail получает водителя по email, если он доступен в области запроса
+func (s *fleetScopedDriverService) GetDriverByEmail(ctx context.Context, email string) (*entities.Driver, error) {
+	return s.visible(ctx)(s.DriverService.GetDriverByEmail(ctx, email))
+}
+
+// UpdateDriver сохраняет изменения водителя из области запроса; перевести его можно
+// только в автопарк из той же области
+func (s *fleetScopedDriverService) UpdateDriver(ctx context.Context, driver *entities.Driver) (*entities.Driver, error) {
+	existing, err := s.GetDriverByID(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !sameFleet(existing.FleetID, driver.FleetID) {
+		if err := s.checkFleet(ctx, driver.FleetID); err != nil {
+			return nil, err
+		}
+	}
+	return s.DriverService.UpdateDriver(ctx, driver)
+}
+
+// DeleteDriver удаляет водителя из области запроса
+func (s *fleetScopedDriverService) DeleteDriver(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.DeleteDriver(ctx, id)
+}
+
+// ListDrivers получает водителей автопарков из области запроса
+func (s *fleetScopedDriverService) ListDrivers(ctx context.Context, filters *entities.DriverFilters) ([]*entities.Driver, error) {
+	return s.DriverService.ListDrivers(ctx, scopeDriverFilters(ctx, filters))
+}
+
+// CountDrivers считает водителей автопарков из области запроса
+func (s *fleetScopedDriverService) CountDrivers(ctx context.Context, filters *entities.DriverFilters) (int, error) {
+	return s.DriverService.CountDrivers(ctx, scopeDriverFilters(ctx, filters))
+}
+
+// ChangeDriverStatus изменяет статус водителя из области запроса
+func (s *fleetScopedDriverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.ChangeDriverStatus(ctx, id, status)
+}
+
+// OverrideDriverStatus устанавливает статус водителя из области запроса
+func (s *fleetScopedDriverService) OverrideDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status, reason, actorID string) (*entities.StatusOverride, error) {
+	if err := s.authorize(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.DriverService.OverrideDriverStatus(ctx, id, status, reason, actorID)
+}
+
+// UpdateDriverRating обновляет рейтинг водителя из области запроса
+func (s *fleetScopedDriverService) UpdateDriverRating(ctx context.Context, id uuid.UUID, rating float64) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.UpdateDriverRating(ctx, id, rating)
+}
+
+// IncrementTripCount увеличивает счетчик поездок водителя из области запроса
+func (s *fleetScopedDriverService) IncrementTripCount(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.IncrementTripCount(ctx, id)
+}
+
+// GetActiveDrivers получает активных водителей автопарков из области запроса
+func (s *fleetScopedDriverService) GetActiveDrivers(ctx context.Context) ([]*entities.Driver, error) {
+	drivers, err := s.DriverService.GetActiveDrivers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, scoped := entities.FleetScopeFromContext(ctx); !scoped {
+		return drivers, nil
+	}
+
+	visible := make([]*entities.Driver, 0, len(drivers))
+	for _, driver := range drivers {
+		if entities.FleetScopeAllows(ctx, driver.FleetID) {
+			visible = append(visible, driver)
+		}
+	}
+	return visible, nil
+}
+
+// IsDriverAvailable проверяет доступность водителя из области запроса
+func (s *fleetScopedDriverService) IsDriverAvailable(ctx context.Context, id uuid.UUID) (bool, error) {
+	if err := s.authorize(ctx, id); err != nil {
+		return false, err
+	}
+	return s.DriverService.IsDriverAvailable(ctx, id)
+}
+
+// ValidateDriverForOrder проверяет водителя из области запроса перед назначением заказа
+func (s *fleetScopedDriverService) ValidateDriverForOrder(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.ValidateDriverForOrder(ctx, id)
+}
+
+// PlacePaymentHold блокирует выплаты водителю из области запроса
+func (s *fleetScopedDriverService) PlacePaymentHold(ctx context.Context, id uuid.UUID, reason string) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.PlacePaymentHold(ctx, id, reason)
+}
+
+// ReleasePaymentHold снимает блокировку выплат водителю из области запроса
+func (s *fleetScopedDriverService) ReleasePaymentHold(ctx context.Context, id uuid.UUID) error {
+	if err := s.authorize(ctx, id); err != nil {
+		return err
+	}
+	return s.DriverService.ReleasePaymentHold(ctx, id)
+}
+
+// authorize проверяет, что водитель доступен в области запроса. Без области водитель
+// не загружается
+func (s *fleetScopedDriverService) authorize(ctx context.Context, id uuid.UUID) error {
+	if _, scoped := entities.FleetScopeFromContext(ctx); !scoped {
+		return nil
+	}
+	_, err := s.GetDriverByID(ctx, id)
+	return err
+}
+
+// visible скрывает водителя, автопарк которого не входит в область запроса
+func (s *fleetScopedDriverService) visible(ctx context.Context) func(*entities.Driver, error) (*entities.Driver, error) {
+	return func(driver *entities.Driver, err error) (*entities.Driver, error) {
+		if err != nil {
+			return nil, err
+		}
+		if !entities.FleetScopeAllows(ctx, driver.FleetID) {
+			return nil, entities.ErrDriverNotFound
+		}
+		return driver, nil
+	}
+}
+
+// checkFleet проверяет, что автопарк водителя доступен в области запроса и существует
+func (s *fleetScopedDriverService) checkFleet(ctx context.Context, fleetID *uuid.UUID) error {
+	if !entities.FleetScopeAllows(ctx, fleetID) {
+		return entities.ErrFleetOutOfScope
+	}
+	if fleetID == nil {
+		return nil
+	}
+	_, err := s.fleetRepo.GetByID(ctx, *fleetID)
+	return err
+}
+
+// scopeDriverFilters возвращает копию фильтров, суженную до автопарков области запроса
+func scopeDriverFilters(ctx context.Context, filters *entities.DriverFilters) *entities.DriverFilters {
+	fleetIDs, scoped := entities.FleetScopeFromContext(ctx)
+	if !scoped {
+		return filters
+	}
+
+	restricted := entities.DriverFilters{}
+	if filters != nil {
+		restricted = *filters
+	}
+	restricted.FleetIDs = entities.RestrictFleets(restricted.FleetIDs, fleetIDs)
+	return &restricted
+}
+
+// sameFleet сравнивает автопарки водителя
+func sameFleet(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
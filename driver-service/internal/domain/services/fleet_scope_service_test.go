@@ -0,0 +1,83 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestFleetScopedDriverService(t *testing.T) {
+	ctx := context.Background()
+	next, _, _, _ := newTestDriverService()
+	fleetRepo := memory.NewFleetRepository()
+	service := NewFleetScopedDriverService(next, fleetRepo)
+	fleets := NewFleetService(fleetRepo, zap.NewNop())
+
+	own, err := fleets.CreateFleet(ctx, &entities.FleetRequest{Name: "Свой парк"})
+	require.NoError(t, err)
+	foreign, err := fleets.CreateFleet(ctx, &entities.FleetRequest{Name: "Чужой парк"})
+	require.NoError(t, err)
+
+	unknown := newTestDriver("1")
+	unknownFleet := uuid.New()
+	unknown.FleetID = &unknownFleet
+	_, err = service.CreateDriver(ctx, unknown)
+	assert.Equal(t, entities.ErrFleetNotFound, err)
+
+	foreignDriver := newTestDriver("2")
+	foreignDriver.FleetID = &foreign.ID
+	foreignDriver, err = service.CreateDriver(ctx, foreignDriver)
+	require.NoError(t, err)
+	_, err = service.CreateDriver(ctx, newTestDriver("3"))
+	require.NoError(t, err, "unscoped callers may create drivers without a fleet")
+
+	partner := entities.WithFleetScope(ctx, []uuid.UUID{own.ID})
+
+	ownDriver, err := service.CreateDriver(partner, newTestDriver("4"))
+	require.NoError(t, err)
+	require.NotNil(t, ownDriver.FleetID, "the partner's only fleet is assigned")
+	assert.Equal(t, own.ID, *ownDriver.FleetID)
+
+	misplaced := newTestDriver("5")
+	misplaced.FleetID = &foreign.ID
+	_, err = service.CreateDriver(partner, misplaced)
+	assert.Equal(t, entities.ErrFleetOutOfScope, err)
+
+	listed, err := service.ListDrivers(partner, &entities.DriverFilters{})
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, ownDriver.ID, listed[0].ID)
+
+	count, err := service.CountDrivers(partner, &entities.DriverFilters{FleetIDs: []uuid.UUID{foreign.ID}})
+	require.NoError(t, err)
+	assert.Zero(t, count, "requesting a foreign fleet does not widen the scope")
+
+	count, err = service.CountDrivers(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	_, err = service.GetDriverByID(partner, foreignDriver.ID)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+	assert.Equal(t, entities.ErrDriverNotFound, service.ChangeDriverStatus(partner, foreignDriver.ID, entities.StatusBlocked))
+
+	moved, err := service.GetDriverByID(partner, ownDriver.ID)
+	require.NoError(t, err)
+	moved.FleetID = &foreign.ID
+	_, err = service.UpdateDriver(partner, moved)
+	assert.Equal(t, entities.ErrFleetOutOfScope, err)
+
+	visible, err := fleets.ListFleets(partner, &entities.FleetFilters{})
+	require.NoError(t, err)
+	require.Len(t, visible, 1)
+	assert.Equal(t, own.ID, visible[0].ID)
+
+	_, err = fleets.GetFleet(partner, foreign.ID)
+	assert.Equal(t, entities.ErrFleetNotFound, err)
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FleetService интерфейс для автопарков (партнеров). Запрос, ограниченный автопарками
+// (entities.WithFleetScope), видит только свои автопарки
+type FleetService interface {
+	CreateFleet(ctx context.Context, req *entities.FleetRequest) (*entities.Fleet, error)
+	GetFleet(ctx context.Context, id uuid.UUID) (*entities.Fleet, error)
+	UpdateFleet(ctx context.Context, id uuid.UUID, req *entities.FleetRequest) (*entities.Fleet, error)
+	ListFleets(ctx context.Context, filters *entities.FleetFilters) ([]*entities.Fleet, error)
+	CountFleets(ctx context.Context, filters *entities.FleetFilters) (int, error)
+}
+
+// fleetService реализация FleetService
+type fleetService struct {
+	fleetRepo repositories.FleetRepository
+	logger    *zap.Logger
+}
+
+// NewFleetService создает новый FleetService
+func NewFleetService(fleetRepo repositories.FleetRepository, logger *zap.Logger) FleetService {
+	return &fleetService{
+		fleetRepo: fleetRepo,
+		logger:    logger,
+	}
+}
+
+// CreateFleet создает автопарк
+func (s *fleetService) CreateFleet(ctx context.Context, req *entities.FleetRequest) (*entities.Fleet, error) {
+	fleet := entities.NewFleet(req)
+	if err := fleet.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.fleetRepo.Create(ctx, fleet); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Fleet created",
+		zap.String("fleet_id", fleet.ID.String()),
+		zap.String("name", fleet.Name),
+	)
+
+	return fleet, nil
+}
+
+// GetFleet получает автопарк по ID; автопарк вне области запроса не найден
+func (s *fleetService) GetFleet(ctx context.Context, id uuid.UUID) (*entities.Fleet, error) {
+	if !entities.FleetScopeAllows(ctx, &id) {
+		return nil, entities.ErrFleetNotFound
+	}
+	return s.fleetRepo.GetByID(ctx, id)
+}
+
+// UpdateFleet заменяет название и метаданные автопарка
+func (s *fleetService) UpdateFleet(ctx context.Context, id uuid.UUID, req *entities.FleetRequest) (*entities.Fleet, error) {
+	fleet, err := s.GetFleet(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(fleet)
+	if err := fleet.Validate(); err != nil {
+		return nil, err
+	}
+	fleet.UpdatedAt = time.Now()
+
+	if err := s.fleetRepo.Update(ctx, fleet); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Fleet updated", zap.String("fleet_id", id.String()))
+	return fleet, nil
+}
+
+// ListFleets получает страницу автопарков в пределах области запроса
+func (s *fleetService) ListFleets(ctx context.Context, filters *entities.FleetFilters) ([]*entities.Fleet, error) {
+	return s.fleetRepo.List(ctx, scopeFleetFilters(ctx, filters))
+}
+
+// CountFleets считает автопарки в пределах области запроса
+func (s *fleetService) CountFleets(ctx context.Context, filters *entities.FleetFilters) (int, error) {
+	return s.fleetRepo.Count(ctx, scopeFleetFilters(ctx, filters))
+}
+
+// scopeFleetFilters возвращает копию фильтров, суженную до автопарков области запроса
+func scopeFleetFilters(ctx context.Context, filters *entities.FleetFilters) *entities.FleetFilters {
+	scoped := *filters
+	if fleetIDs, ok := entities.FleetScopeFromContext(ctx); ok {
+		scoped.IDs = entities.RestrictFleets(filters.IDs, fleetIDs)
+	}
+	return &scoped
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FleetValidator вызывает вебхук автопарка для проверки изменения профиля водителя
+type FleetValidator interface {
+	// Validate возвращает решение автопарка или nil, если у автопарка нет вебхука.
+	// Ошибка означает, что вебхук не ответил вовремя или ответил некорректно
+	Validate(ctx context.Context, req *entities.FleetValidationRequest) (*entities.FleetValidationResult, error)
+	// FailOpen сообщает, пропускать ли изменение, если вебхук автопарка недоступен
+	FailOpen(fleetID uuid.UUID) bool
+}
+
+// FleetValidationPolicy параметры проверки изменений вебхуками автопарков
+type FleetValidationPolicy struct {
+	// EnrichmentKeys ключи метаданных, которые автопарк может заполнить; прочие поля ответа игнорируются
+	EnrichmentKeys []string
+}
+
+// fleetValidatedDriverService проверяет регистрацию и изменение профилей водителей автопарков
+// вебхуком автопарка до сохранения. Остальные методы DriverService выполняются без проверки
+type fleetValidatedDriverService struct {
+	DriverService
+	validator FleetValidator
+	auditRepo repositories.AuditRepository
+	policy    FleetValidationPolicy
+	logger    *zap.Logger
+}
+
+// NewFleetValidatedDriverService оборачивает DriverService проверкой вебхуками автопарков
+func NewFleetValidatedDriverService(
+	next DriverService,
+	validator FleetValidator,
+	auditRepo repositories.AuditRepository,
+	policy FleetValidationPolicy,
+	logger *zap.Logger,
+) DriverService {
+	return &fleetValidatedDriverService{
+		DriverService: next,
+		validator:     validator,
+		auditRepo:     auditRepo,
+		policy:        policy,
+		logger:        logger,
+	}
+}
+
+// CreateDriver регистрирует водителя после одобрения автопарком, указанным в метаданных
+func (s *fleetValidatedDriverService) CreateDriver(ctx context.Context, driver *entities.Driver) (*entities.Driver, error) {
+	fleetID := driver.FleetID
+	if fleetID == nil {
+		return s.DriverService.CreateDriver(ctx, driver)
+	}
+
+	entry, err := s.validate(ctx, &entities.FleetValidationRequest{
+		Action:  entities.FleetValidationCreate,
+		FleetID: *fleetID,
+		Driver:  driver,
+	}, driver)
+	if entry == nil {
+		return s.DriverService.CreateDriver(ctx, driver)
+	}
+	// У отклоненной регистрации нет ID водителя: запись журнала находится по телефону
+	entry.Details["phone"] = driver.Phone
+	if err != nil {
+		s.record(ctx, entry)
+		return nil, err
+	}
+
+	created, err := s.DriverService.CreateDriver(ctx, driver)
+	if err == nil {
+		entry.DriverID = &created.ID
+	}
+	s.record(ctx, entry)
+	return created, err
+}
+
+// UpdateDriver сохраняет изменения после одобрения автопарком водителя.
+// Автопарк берется из нового профиля, а если водитель его покидает — из текущего
+func (s *fleetValidatedDriverService) UpdateDriver(ctx context.Context, driver *entities.Driver) (*entities.Driver, error) {
+	existing, err := s.DriverService.GetDriverByID(ctx, driver.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleetID := driver.FleetID
+	if fleetID == nil {
+		fleetID = existing.FleetID
+	}
+	if fleetID == nil {
+		return s.DriverService.UpdateDriver(ctx, driver)
+	}
+
+	entry, err := s.validate(ctx, &entities.FleetValidationRequest{
+		Action:   entities.FleetValidationUpdate,
+		FleetID:  *fleetID,
+		Driver:   driver,
+		Previous: existing,
+	}, driver)
+	if entry != nil {
+		driverID := driver.ID
+		entry.DriverID = &driverID
+		s.record(ctx, entry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.DriverService.UpdateDriver(ctx, driver)
+}
+
+// validate вызывает вебхук и применяет решение к водителю. Возвращает запись журнала
+// (nil, если у автопарка нет вебхука) и ошибку, если изменение не допускается
+func (s *fleetValidatedDriverService) validate(ctx context.Context, req *entities.FleetValidationRequest, driver *entities.Driver) (*entities.AuditEntry, error) {
+	started := time.Now()
+	result, err := s.validator.Validate(ctx, req)
+	if err == nil && result == nil {
+		return nil, nil
+	}
+
+	entry := entities.NewAuditEntry(entities.AuditEventFleetValidation, string(req.Action), "")
+	entry.FleetID = &req.FleetID
+	entry.AttachActor(ctx)
+	entry.Details["duration_ms"] = time.Since(started).Milliseconds()
+
+	log := s.logger.With(
+		zap.String("fleet_id", req.FleetID.String()),
+		zap.String("action", string(req.Action)),
+	)
+
+	if err != nil {
+		entry.Details["error"] = err.Error()
+		if s.validator.FailOpen(req.FleetID) {
+			entry.Outcome = entities.FleetOutcomeFailedOpen
+			log.Warn("Fleet webhook failed, change allowed", zap.Error(err))
+			return entry, nil
+		}
+		entry.Outcome = entities.FleetOutcomeFailedClosed
+		log.Error("Fleet webhook failed, change rejected", zap.Error(err))
+		return entry, fmt.Errorf("%w: %v", entities.ErrFleetValidationUnavailable, err)
+	}
+
+	if result.Reason != "" {
+		entry.Details["reason"] = result.Reason
+	}
+	if result.Decision == entities.FleetDecisionReject {
+		entry.Outcome = entities.FleetOutcomeRejected
+		log.Info("Driver change rejected by fleet", zap.String("reason", result.Reason))
+		return entry, fmt.Errorf("%w: %s", entities.ErrFleetValidationRejected, result.Reason)
+	}
+
+	entry.Outcome = entities.FleetOutcomeAllowed
+	applied, ignored := s.enrich(driver, result.Enrichment)
+	if len(applied) > 0 {
+		entry.Details["enrichment"] = applied
+	}
+	if len(ignored) > 0 {
+		entry.Details["ignored_keys"] = ignored
+		log.Warn("Fleet webhook returned unsupported enrichment keys", zap.Strings("keys", ignored))
+	}
+	return entry, nil
+}
+
+// enrich добавляет в метаданные водителя разрешенные поля ответа автопарка
+func (s *fleetValidatedDriverService) enrich(driver *entities.Driver, enrichment map[string]interface{}) (map[string]interface{}, []string) {
+	if len(enrichment) == 0 {
+		return nil, nil
+	}
+	if driver.Metadata == nil {
+		driver.Metadata = make(entities.Metadata)
+	}
+
+	allowed := make(map[string]bool, len(s.policy.EnrichmentKeys))
+	for _, key := range s.policy.EnrichmentKeys {
+		allowed[key] = true
+	}
+
+	applied := make(map[string]interface{})
+	var ignored []string
+	for key, value := range enrichment {
+		if !allowed[key] {
+			ignored = append(ignored, key)
+			continue
+		}
+		driver.Metadata[key] = value
+		applied[key] = value
+	}
+	sort.Strings(ignored)
+	return applied, ignored
+}
+
+// record сохраняет решение в журнал аудита; ошибка журнала не отменяет изменение
+func (s *fleetValidatedDriverService) record(ctx context.Context, entry *entities.AuditEntry) {
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record fleet validation audit entry",
+			zap.Error(err),
+			zap.String("outcome", entry.Outcome),
+		)
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubFleetValidator отвечает заданным решением и запоминает запросы
+type stubFleetValidator struct {
+	result   *entities.FleetValidationResult
+	err      error
+	failOpen bool
+	requests []*entities.FleetValidationRequest
+}
+
+func (v *stubFleetValidator) Validate(ctx context.Context, req *entities.FleetValidationRequest) (*entities.FleetValidationResult, error) {
+	v.requests = append(v.requests, req)
+	return v.result, v.err
+}
+
+func (v *stubFleetValidator) FailOpen(fleetID uuid.UUID) bool {
+	return v.failOpen
+}
+
+func newTestFleetValidatedService(validator FleetValidator) (DriverService, *memory.AuditRepository) {
+	next, _, _, _ := newTestDriverService()
+	auditRepo := memory.NewAuditRepository()
+	policy := FleetValidationPolicy{EnrichmentKeys: []string{"city", "external_id"}}
+	return NewFleetValidatedDriverService(next, validator, auditRepo, policy, zap.NewNop()), auditRepo
+}
+
+func newFleetDriver(suffix string, fleetID uuid.UUID) *entities.Driver {
+	driver := newTestDriver(suffix)
+	driver.FleetID = &fleetID
+	return driver
+}
+
+func TestFleetValidatedDriverService_CreateEnriches(t *testing.T) {
+	ctx := context.Background()
+	validator := &stubFleetValidator{result: &entities.FleetValidationResult{
+		Decision:   entities.FleetDecisionAllow,
+		Enrichment: map[string]interface{}{"city": "Казань", "fleet_id": uuid.NewString()},
+	}}
+	service, auditRepo := newTestFleetValidatedService(validator)
+	fleetID := uuid.New()
+
+	created, err := service.CreateDriver(ctx, newFleetDriver("1", fleetID))
+	require.NoError(t, err)
+	assert.Equal(t, "Казань", created.Metadata["city"])
+	assert.Equal(t, fleetID, *created.FleetID, "fleet_id is not an enrichment key")
+
+	require.Len(t, validator.requests, 1)
+	assert.Equal(t, entities.FleetValidationCreate, validator.requests[0].Action)
+
+	entries, err := auditRepo.ListByDriver(ctx, created.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entities.FleetOutcomeAllowed, entries[0].Outcome)
+	assert.Equal(t, []string{"fleet_id"}, entries[0].Details["ignored_keys"])
+}
+
+func TestFleetValidatedDriverService_UpdateRejected(t *testing.T) {
+	ctx := context.Background()
+	validator := &stubFleetValidator{result: &entities.FleetValidationResult{Decision: entities.FleetDecisionAllow}}
+	service, auditRepo := newTestFleetValidatedService(validator)
+
+	created, err := service.CreateDriver(ctx, newFleetDriver("1", uuid.New()))
+	require.NoError(t, err)
+
+	validator.result = &entities.FleetValidationResult{Decision: entities.FleetDecisionReject, Reason: "unknown contract"}
+	update := *created
+	update.FirstName = "Петр"
+	_, err = service.UpdateDriver(ctx, &update)
+	assert.ErrorIs(t, err, entities.ErrFleetValidationRejected)
+	assert.Contains(t, err.Error(), "unknown contract")
+
+	stored, err := service.GetDriverByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Иван", stored.FirstName)
+	assert.Equal(t, "Иван", validator.requests[1].Previous.FirstName)
+
+	entries, err := auditRepo.ListByDriver(ctx, created.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, entities.FleetOutcomeRejected, entries[0].Outcome)
+	assert.Equal(t, "unknown contract", entries[0].Details["reason"])
+}
+
+func TestFleetValidatedDriverService_WebhookFailure(t *testing.T) {
+	ctx := context.Background()
+	validator := &stubFleetValidator{err: errors.New("timeout")}
+	service, _ := newTestFleetValidatedService(validator)
+
+	_, err := service.CreateDriver(ctx, newFleetDriver("1", uuid.New()))
+	assert.ErrorIs(t, err, entities.ErrFleetValidationUnavailable)
+
+	validator.failOpen = true
+	_, err = service.CreateDriver(ctx, newFleetDriver("1", uuid.New()))
+	assert.NoError(t, err)
+}
+
+func TestFleetValidatedDriverService_SkipsDriversWithoutWebhook(t *testing.T) {
+	ctx := context.Background()
+	validator := &stubFleetValidator{}
+	service, auditRepo := newTestFleetValidatedService(validator)
+
+	withoutFleet, err := service.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+	assert.Empty(t, validator.requests)
+
+	// Автопарк без вебхука: Validate возвращает nil, журнал не пишется
+	withFleet, err := service.CreateDriver(ctx, newFleetDriver("2", uuid.New()))
+	require.NoError(t, err)
+	assert.Len(t, validator.requests, 1)
+
+	for _, id := range []uuid.UUID{withoutFleet.ID, withFleet.ID} {
+		entries, err := auditRepo.ListByDriver(ctx, id, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	}
+}
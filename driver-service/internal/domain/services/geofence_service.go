@@ -0,0 +1,282 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GeofenceService интерфейс для геозон и событий входа и выхода водителей
+type GeofenceService interface {
+	CreateGeofence(ctx context.Context, req *entities.GeofenceRequest) (*entities.Geofence, error)
+	GetGeofence(ctx context.Context, id uuid.UUID) (*entities.Geofence, error)
+	// UpdateGeofence заменяет геозону; при отключении водители внутри нее забываются без событий выхода
+	UpdateGeofence(ctx context.Context, id uuid.UUID, req *entities.GeofenceRequest) (*entities.Geofence, error)
+	DeleteGeofence(ctx context.Context, id uuid.UUID) error
+	ListGeofences(ctx context.Context, filters *entities.GeofenceFilters) ([]*entities.Geofence, error)
+	CountGeofences(ctx context.Context, filters *entities.GeofenceFilters) (int, error)
+	// ListDriversInside возвращает водителей внутри геозоны в порядке входа
+	ListDriversInside(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entities.GeofencePresence, int, error)
+	GeofenceEvaluator
+}
+
+// GeofenceEvaluator проверяет сохраненное местоположение по активным геозонам
+type GeofenceEvaluator interface {
+	// EvaluateLocation публикует события входа и выхода водителя по сохраненной точке
+	EvaluateLocation(ctx context.Context, location *entities.DriverLocation) error
+}
+
+// GeofencePolicy параметры геозон
+type GeofencePolicy struct {
+	// CacheTTL время жизни кэша активных геозон; изменения, сделанные через другой экземпляр,
+	// учитываются не позже чем через CacheTTL. 0 — читать геозоны при каждой точке
+	CacheTTL time.Duration
+}
+
+// geofenceService реализация GeofenceService
+type geofenceService struct {
+	geofenceRepo repositories.GeofenceRepository
+	eventBus     EventPublisher
+	policy       GeofencePolicy
+	logger       *zap.Logger
+
+	mu       sync.RWMutex
+	active   []*entities.Geofence
+	loadedAt time.Time
+}
+
+// NewGeofenceService создает новый GeofenceService
+func NewGeofenceService(
+	geofenceRepo repositories.GeofenceRepository,
+	eventBus EventPublisher,
+	policy GeofencePolicy,
+	logger *zap.Logger,
+) GeofenceService {
+	return &geofenceService{
+		geofenceRepo: geofenceRepo,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// CreateGeofence создает геозону
+func (s *geofenceService) CreateGeofence(ctx context.Context, req *entities.GeofenceRequest) (*entities.Geofence, error) {
+	geofence := entities.NewGeofence(req)
+	if err := geofence.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.geofenceRepo.Create(ctx, geofence); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+
+	s.logger.Info("Geofence created",
+		zap.String("geofence_id", geofence.ID.String()),
+		zap.String("name", geofence.Name),
+		zap.String("shape", string(geofence.Shape)),
+	)
+
+	return geofence, nil
+}
+
+// GetGeofence получает геозону по ID
+func (s *geofenceService) GetGeofence(ctx context.Context, id uuid.UUID) (*entities.Geofence, error) {
+	return s.geofenceRepo.GetByID(ctx, id)
+}
+
+// UpdateGeofence заменяет название, геометрию, метаданные и активность геозоны
+func (s *geofenceService) UpdateGeofence(ctx context.Context, id uuid.UUID, req *entities.GeofenceRequest) (*entities.Geofence, error) {
+	geofence, err := s.geofenceRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Apply(geofence)
+	if err := geofence.Validate(); err != nil {
+		return nil, err
+	}
+	geofence.UpdatedAt = time.Now()
+
+	if err := s.geofenceRepo.Update(ctx, geofence); err != nil {
+		return nil, err
+	}
+	// Отключенная зона больше не проверяется, и водители в ней не получили бы события выхода
+	if !geofence.Active {
+		if err := s.geofenceRepo.ClearPresence(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	s.invalidate()
+
+	s.logger.Info("Geofence updated",
+		zap.String("geofence_id", id.String()),
+		zap.Bool("active", geofence.Active),
+	)
+
+	return geofence, nil
+}
+
+// DeleteGeofence удаляет геозону вместе с присутствием водителей в ней
+func (s *geofenceService) DeleteGeofence(ctx context.Context, id uuid.UUID) error {
+	if err := s.geofenceRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.invalidate()
+
+	s.logger.Info("Geofence deleted", zap.String("geofence_id", id.String()))
+	return nil
+}
+
+// ListGeofences получает страницу геозон
+func (s *geofenceService) ListGeofences(ctx context.Context, filters *entities.GeofenceFilters) ([]*entities.Geofence, error) {
+	return s.geofenceRepo.List(ctx, filters)
+}
+
+// CountGeofences считает геозоны
+func (s *geofenceService) CountGeofences(ctx context.Context, filters *entities.GeofenceFilters) (int, error) {
+	return s.geofenceRepo.Count(ctx, filters)
+}
+
+// ListDriversInside получает страницу водителей внутри геозоны и их общее число
+func (s *geofenceService) ListDriversInside(ctx context.Context, id uuid.UUID, limit, offset int) ([]*entities.GeofencePresence, int, error) {
+	if _, err := s.geofenceRepo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	presence, err := s.geofenceRepo.ListPresenceByGeofenceID(ctx, id, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	total, err := s.geofenceRepo.CountPresenceByGeofenceID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return presence, total, nil
+}
+
+// EvaluateLocation сравнивает точку с активными геозонами и сохраненным присутствием
+// водителя. Вход и выход фиксируются в репозитории до публикации события, поэтому при
+// одновременной обработке точек одного водителя событие публикуется один раз
+func (s *geofenceService) EvaluateLocation(ctx context.Context, location *entities.DriverLocation) error {
+	zones, err := s.activeZones(ctx)
+	if err != nil {
+		return err
+	}
+	if len(zones) == 0 {
+		return nil
+	}
+
+	presence, err := s.geofenceRepo.ListPresenceByDriverID(ctx, location.DriverID)
+	if err != nil {
+		return err
+	}
+	enteredAt := make(map[uuid.UUID]time.Time, len(presence))
+	for _, p := range presence {
+		enteredAt[p.GeofenceID] = p.EnteredAt
+	}
+
+	for _, zone := range zones {
+		since, wasInside := enteredAt[zone.ID]
+		inside := zone.Contains(location.Latitude, location.Longitude)
+
+		switch {
+		case inside && !wasInside:
+			added, err := s.geofenceRepo.AddPresence(ctx, &entities.GeofencePresence{
+				GeofenceID: zone.ID,
+				DriverID:   location.DriverID,
+				EnteredAt:  location.RecordedAt,
+			})
+			if err != nil {
+				return err
+			}
+			if added {
+				data := geofenceEventData(zone, location)
+				data["entered_at"] = location.RecordedAt
+				s.publish(ctx, eventGeofenceEntered, location.DriverID, zone, data)
+			}
+		case !inside && wasInside:
+			removed, err := s.geofenceRepo.RemovePresence(ctx, zone.ID, location.DriverID)
+			if err != nil {
+				return err
+			}
+			if removed {
+				data := geofenceEventData(zone, location)
+				data["entered_at"] = since
+				data["exited_at"] = location.RecordedAt
+				data["dwell_seconds"] = int(location.RecordedAt.Sub(since).Seconds())
+				s.publish(ctx, eventGeofenceExited, location.DriverID, zone, data)
+			}
+		}
+	}
+
+	return nil
+}
+
+// publish публикует событие геозоны; ошибка публикации не отменяет фиксацию входа или выхода
+func (s *geofenceService) publish(ctx context.Context, eventType string, driverID uuid.UUID, zone *entities.Geofence, data map[string]interface{}) {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, driverID, data); err != nil {
+		s.logger.Error("Failed to publish geofence event",
+			zap.Error(err),
+			zap.String("event", eventType),
+			zap.String("geofence_id", zone.ID.String()),
+			zap.String("driver_id", driverID.String()),
+		)
+		return
+	}
+
+	s.logger.Debug("Geofence event published",
+		zap.String("event", eventType),
+		zap.String("geofence_id", zone.ID.String()),
+		zap.String("driver_id", driverID.String()),
+	)
+}
+
+// geofenceEventData общие поля событий входа и выхода
+func geofenceEventData(zone *entities.Geofence, location *entities.DriverLocation) map[string]interface{} {
+	return map[string]interface{}{
+		"geofence_id": zone.ID.String(),
+		"name":        zone.Name,
+		"shape":       string(zone.Shape),
+		"metadata":    zone.Metadata,
+		"latitude":    location.Latitude,
+		"longitude":   location.Longitude,
+	}
+}
+
+// activeZones возвращает активные геозоны из кэша или из репозитория
+func (s *geofenceService) activeZones(ctx context.Context) ([]*entities.Geofence, error) {
+	s.mu.RLock()
+	zones, loadedAt := s.active, s.loadedAt
+	s.mu.RUnlock()
+
+	if !loadedAt.IsZero() && time.Since(loadedAt) < s.policy.CacheTTL {
+		return zones, nil
+	}
+
+	zones, err := s.geofenceRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load active geofences: %w", err)
+	}
+
+	s.mu.Lock()
+	s.active, s.loadedAt = zones, time.Now()
+	s.mu.Unlock()
+
+	return zones, nil
+}
+
+// invalidate сбрасывает кэш активных геозон после изменений через этот экземпляр
+func (s *geofenceService) invalidate() {
+	s.mu.Lock()
+	s.active, s.loadedAt = nil, time.Time{}
+	s.mu.Unlock()
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// geofenceEventRecorder запоминает события геозон вместе с данными
+type geofenceEventRecorder struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+type recordedEvent struct {
+	eventType string
+	driverID  uuid.UUID
+	data      map[string]interface{}
+}
+
+func (r *geofenceEventRecorder) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payload, _ := data.(map[string]interface{})
+	r.events = append(r.events, recordedEvent{eventType: eventType, driverID: driverID, data: payload})
+	return nil
+}
+
+// ofType возвращает события геозон указанного типа
+func (r *geofenceEventRecorder) ofType(eventType string) []recordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var result []recordedEvent
+	for _, event := range r.events {
+		if event.eventType == eventType {
+			result = append(result, event)
+		}
+	}
+	return result
+}
+
+type geofenceFixture struct {
+	service      GeofenceService
+	geofenceRepo *memory.GeofenceRepository
+	events       *geofenceEventRecorder
+}
+
+func newGeofenceFixture(policy GeofencePolicy) *geofenceFixture {
+	f := &geofenceFixture{
+		geofenceRepo: memory.NewGeofenceRepository(),
+		events:       &geofenceEventRecorder{},
+	}
+	f.service = NewGeofenceService(f.geofenceRepo, f.events, policy, zap.NewNop())
+	return f
+}
+
+// airportQueue создает прямоугольную зону ожидания с метаданными
+func (f *geofenceFixture) airportQueue(t *testing.T) *entities.Geofence {
+	t.Helper()
+	geofence, err := f.service.CreateGeofence(context.Background(), &entities.GeofenceRequest{
+		Name:  "Зона ожидания",
+		Shape: entities.GeofenceShapePolygon,
+		Polygon: []entities.GeoPoint{
+			{Latitude: 55.970, Longitude: 37.405},
+			{Latitude: 55.970, Longitude: 37.425},
+			{Latitude: 55.980, Longitude: 37.425},
+			{Latitude: 55.980, Longitude: 37.405},
+		},
+		Metadata: entities.Metadata{"type": "airport_queue"},
+	})
+	require.NoError(t, err)
+	return geofence
+}
+
+func pointAt(driverID uuid.UUID, lat, lon float64, at time.Time) *entities.DriverLocation {
+	return &entities.DriverLocation{DriverID: driverID, Latitude: lat, Longitude: lon, RecordedAt: at}
+}
+
+func TestGeofenceService_EnterAndExit(t *testing.T) {
+	f := newGeofenceFixture(GeofencePolicy{})
+	ctx := context.Background()
+	zone := f.airportQueue(t)
+	driverID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.960, 37.415, start)))
+	assert.Empty(t, f.events.events, "outside the zone")
+
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.975, 37.415, start.Add(time.Minute))))
+	// Повторная точка внутри зоны не публикует событие входа
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.976, 37.416, start.Add(2*time.Minute))))
+
+	entered := f.events.ofType(eventGeofenceEntered)
+	require.Len(t, entered, 1)
+	assert.Equal(t, driverID, entered[0].driverID)
+	assert.Equal(t, zone.ID.String(), entered[0].data["geofence_id"])
+	assert.Equal(t, entities.Metadata{"type": "airport_queue"}, entered[0].data["metadata"])
+
+	drivers, total, err := f.service.ListDriversInside(ctx, zone.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	require.Len(t, drivers, 1)
+	assert.Equal(t, driverID, drivers[0].DriverID)
+
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.990, 37.415, start.Add(11*time.Minute))))
+	exited := f.events.ofType(eventGeofenceExited)
+	require.Len(t, exited, 1)
+	assert.Equal(t, 600, exited[0].data["dwell_seconds"])
+	assert.Equal(t, "Зона ожидания", exited[0].data["name"])
+
+	_, total, err = f.service.ListDriversInside(ctx, zone.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestGeofenceService_QueueOrder(t *testing.T) {
+	f := newGeofenceFixture(GeofencePolicy{})
+	ctx := context.Background()
+	zone := f.airportQueue(t)
+	first, second := uuid.New(), uuid.New()
+	now := time.Now()
+
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(second, 55.975, 37.415, now)))
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(first, 55.975, 37.415, now.Add(-time.Minute))))
+
+	drivers, total, err := f.service.ListDriversInside(ctx, zone.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, drivers, 2)
+	assert.Equal(t, first, drivers[0].DriverID, "ordered by entry time")
+	assert.Equal(t, second, drivers[1].DriverID)
+
+	_, _, err = f.service.ListDriversInside(ctx, uuid.New(), 10, 0)
+	assert.Equal(t, entities.ErrGeofenceNotFound, err)
+}
+
+func TestGeofenceService_DeactivateClearsPresence(t *testing.T) {
+	f := newGeofenceFixture(GeofencePolicy{CacheTTL: time.Hour})
+	ctx := context.Background()
+	zone := f.airportQueue(t)
+	driverID := uuid.New()
+	now := time.Now()
+
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.975, 37.415, now)))
+	require.Len(t, f.events.ofType(eventGeofenceEntered), 1)
+
+	inactive := false
+	updated, err := f.service.UpdateGeofence(ctx, zone.ID, &entities.GeofenceRequest{
+		Name:    zone.Name,
+		Shape:   zone.Shape,
+		Polygon: zone.Polygon,
+		Active:  &inactive,
+	})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	assert.Equal(t, zone.CreatedAt, updated.CreatedAt)
+
+	// Изменение сбрасывает кэш: отключенная зона сразу перестает проверяться
+	require.NoError(t, f.service.EvaluateLocation(ctx, pointAt(driverID, 55.990, 37.415, now.Add(time.Minute))))
+	assert.Empty(t, f.events.ofType(eventGeofenceExited))
+	_, total, err := f.service.ListDriversInside(ctx, zone.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+
+	_, err = f.service.UpdateGeofence(ctx, zone.ID, &entities.GeofenceRequest{Name: "Без формы", Shape: "square"})
+	assert.Equal(t, entities.ErrInvalidGeofence, err)
+
+	require.NoError(t, f.service.DeleteGeofence(ctx, zone.ID))
+	assert.Equal(t, entities.ErrGeofenceNotFound, f.service.DeleteGeofence(ctx, zone.ID))
+}
+
+func TestLocationService_BatchEvaluatesGeofencesInOrder(t *testing.T) {
+	f := newGeofenceFixture(GeofencePolicy{})
+	ctx := context.Background()
+	f.airportQueue(t)
+
+	driverRepo := memory.NewDriverRepository()
+	driver := newTestDriver("78")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+	locations := NewLocationService(memory.NewLocationRepository(), driverRepo, nil, &recordingEventPublisher{}, nil,
+		f.service, nil, LocationPolicy{}, zap.NewNop())
+
+	// Пакет пришел не по порядку: точка выхода записана позже точки входа
+	now := time.Now()
+	_, err := locations.BatchUpdateLocations(ctx, []*entities.DriverLocation{
+		pointAt(driver.ID, 55.990, 37.415, now),
+		pointAt(driver.ID, 55.975, 37.415, now.Add(-time.Minute)),
+	})
+	require.NoError(t, err)
+	assert.Len(t, f.events.ofType(eventGeofenceEntered), 1)
+	assert.Len(t, f.events.ofType(eventGeofenceExited), 1)
+
+	require.NoError(t, locations.UpdateLocation(ctx, pointAt(driver.ID, 55.975, 37.415, now.Add(time.Minute))))
+	assert.Len(t, f.events.ofType(eventGeofenceEntered), 2)
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// HeartbeatEventTypes события, которые считаются сигналом водителя; на них подписан
+// HeartbeatService.HandleLocationEvent
+var HeartbeatEventTypes = []string{
+	eventLocationUpdated,
+}
+
+// HeartbeatPolicy параметры определения водителей, переставших выходить на связь
+type HeartbeatPolicy struct {
+	// OfflineAfter через сколько без сигнала водитель переводится в неактивные; 0 — не переводить
+	OfflineAfter time.Duration
+	// TouchInterval как часто точки местоположения обновляют сохраненный сигнал водителя.
+	// Точки приходят раз в несколько секунд, и записывать каждую незачем
+	TouchInterval time.Duration
+}
+
+// HeartbeatService интерфейс для сигналов присутствия водителей
+type HeartbeatService interface {
+	// RecordHeartbeat сохраняет явный сигнал приложения водителя
+	RecordHeartbeat(ctx context.Context, driverID uuid.UUID) (*entities.DriverHeartbeat, error)
+	// HandleLocationEvent учитывает принятую точку как сигнал; подписчик HeartbeatEventTypes
+	HandleLocationEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+	// DetectOffline переводит в неактивные водителей без сигнала дольше HeartbeatPolicy.OfflineAfter
+	// и закрывает их смены
+	DetectOffline(ctx context.Context) (*entities.OfflineDetection, error)
+}
+
+// heartbeatService реализация HeartbeatService
+type heartbeatService struct {
+	heartbeatRepo repositories.HeartbeatRepository
+	driverRepo    repositories.DriverRepository
+	driverService DriverService
+	shiftService  ShiftService
+	eventBus      EventPublisher
+	policy        HeartbeatPolicy
+	logger        *zap.Logger
+
+	// touched когда сигнал водителя по точке местоположения последний раз записан в хранилище
+	mu      sync.Mutex
+	touched map[uuid.UUID]time.Time
+}
+
+// NewHeartbeatService создает новый HeartbeatService. Водители переводятся в неактивные
+// через driverService, чтобы смена статуса проходила проверки и публиковала событие
+func NewHeartbeatService(
+	heartbeatRepo repositories.HeartbeatRepository,
+	driverRepo repositories.DriverRepository,
+	driverService DriverService,
+	shiftService ShiftService,
+	eventBus EventPublisher,
+	policy HeartbeatPolicy,
+	logger *zap.Logger,
+) HeartbeatService {
+	return &heartbeatService{
+		heartbeatRepo: heartbeatRepo,
+		driverRepo:    driverRepo,
+		driverService: driverService,
+		shiftService:  shiftService,
+		eventBus:      eventBus,
+		policy:        policy,
+		logger:        logger,
+		touched:       make(map[uuid.UUID]time.Time),
+	}
+}
+
+// RecordHeartbeat сохраняет сигнал существующего водителя
+func (s *heartbeatService) RecordHeartbeat(ctx context.Context, driverID uuid.UUID) (*entities.DriverHeartbeat, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	heartbeat := entities.NewDriverHeartbeat(driverID, entities.HeartbeatSourceHeartbeat)
+	if err := s.heartbeatRepo.Touch(ctx, heartbeat); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.touched[driverID] = heartbeat.LastSeenAt
+	s.mu.Unlock()
+
+	return heartbeat, nil
+}
+
+// HandleLocationEvent записывает сигнал водителя по принятой точке не чаще TouchInterval
+func (s *heartbeatService) HandleLocationEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	heartbeat := entities.NewDriverHeartbeat(driverID, entities.HeartbeatSourceLocation)
+
+	s.mu.Lock()
+	if last, ok := s.touched[driverID]; ok && heartbeat.LastSeenAt.Sub(last) < s.policy.TouchInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.touched[driverID] = heartbeat.LastSeenAt
+	s.mu.Unlock()
+
+	if err := s.heartbeatRepo.Touch(ctx, heartbeat); err != nil {
+		// Следующая точка повторит запись
+		s.mu.Lock()
+		delete(s.touched, driverID)
+		s.mu.Unlock()
+		return fmt.Errorf("failed to record location heartbeat: %w", err)
+	}
+	return nil
+}
+
+// DetectOffline переводит в неактивные водителей на линии, от которых нет сигнала дольше
+// OfflineAfter, и закрывает их активные смены. Водитель, не присылавший сигналов, считается
+// на связи до последнего изменения профиля или статуса. Водители на заказе не трогаются до
+// завершения поездки
+func (s *heartbeatService) DetectOffline(ctx context.Context) (*entities.OfflineDetection, error) {
+	result := &entities.OfflineDetection{}
+	if s.policy.OfflineAfter <= 0 {
+		return result, nil
+	}
+
+	drivers, err := s.driverRepo.GetActiveDrivers(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to list active drivers: %w", err)
+	}
+
+	ids := make([]uuid.UUID, len(drivers))
+	for i, driver := range drivers {
+		ids[i] = driver.ID
+	}
+	heartbeats, err := s.heartbeatRepo.ListByDriverIDs(ctx, ids)
+	if err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	for _, driver := range drivers {
+		result.Checked++
+		if driver.Status == entities.StatusBusy {
+			continue
+		}
+
+		lastSeenAt := driver.UpdatedAt
+		if heartbeat, ok := heartbeats[driver.ID]; ok {
+			lastSeenAt = heartbeat.LastSeenAt
+		}
+		if now.Sub(lastSeenAt) < s.policy.OfflineAfter {
+			continue
+		}
+
+		shiftEnded, err := s.takeOffline(ctx, driver, lastSeenAt, now)
+		if shiftEnded {
+			result.ShiftsEnded++
+		}
+		if err != nil {
+			s.logger.Error("Failed to take offline driver inactive",
+				zap.Error(err),
+				zap.String("driver_id", driver.ID.String()),
+			)
+			result.Failed++
+			continue
+		}
+		result.WentOffline++
+	}
+
+	s.logger.Info("Offline drivers detected",
+		zap.Int("checked", result.Checked),
+		zap.Int("went_offline", result.WentOffline),
+		zap.Int("shifts_ended", result.ShiftsEnded),
+		zap.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
+// takeOffline закрывает активную смену водителя, переводит его в неактивные и публикует
+// событие. Возвращает, была ли закрыта смена, в том числе если перевести водителя не удалось
+func (s *heartbeatService) takeOffline(ctx context.Context, driver *entities.Driver, lastSeenAt, now time.Time) (bool, error) {
+	eventData := map[string]interface{}{
+		"previous_status": driver.Status,
+		"last_seen_at":    lastSeenAt,
+		"offline_seconds": int64(now.Sub(lastSeenAt).Seconds()),
+	}
+
+	shiftEnded := false
+	if driver.Status == entities.StatusOnShift {
+		shift, err := s.shiftService.EndOfflineShift(ctx, driver.ID)
+		switch {
+		case err == nil:
+			shiftEnded = true
+			eventData["shift_id"] = shift.ID.String()
+		case err != entities.ErrShiftNotActive:
+			return false, fmt.Errorf("failed to end shift: %w", err)
+		}
+	}
+
+	if err := s.driverService.ChangeDriverStatus(ctx, driver.ID, entities.StatusInactive); err != nil {
+		return shiftEnded, err
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventDriverWentOffline, driver.ID, eventData); err != nil {
+		s.logger.Error("Failed to publish driver went offline event",
+			zap.Error(err),
+			zap.String("driver_id", driver.ID.String()),
+		)
+	}
+
+	s.logger.Info("Driver went offline",
+		zap.String("driver_id", driver.ID.String()),
+		zap.Time("last_seen_at", lastSeenAt),
+		zap.Bool("shift_ended", shiftEnded),
+	)
+
+	return shiftEnded, nil
+}
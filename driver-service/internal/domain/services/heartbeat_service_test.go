@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHeartbeatService_DetectOffline(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	shiftRepo := memory.NewShiftRepository()
+	heartbeatRepo := memory.NewHeartbeatRepository()
+	events := &recordingEventPublisher{}
+	driverService := NewDriverService(driverRepo, memory.NewDocumentRepository(), nil, events, zap.NewNop())
+	shiftService := NewShiftService(shiftRepo, memory.NewShiftBreakRepository(), driverRepo, memory.NewLocationRepository(), nil, nil, nil, events, ShiftPolicy{}, zap.NewNop())
+	service := NewHeartbeatService(heartbeatRepo, driverRepo, driverService, shiftService, events,
+		HeartbeatPolicy{OfflineAfter: 10 * time.Minute, TouchInterval: time.Minute}, zap.NewNop())
+
+	newDriver := func(suffix string, status entities.Status, updatedAgo time.Duration) *entities.Driver {
+		driver := newTestDriver(suffix)
+		driver.ID = uuid.New()
+		driver.Status = status
+		driver.UpdatedAt = time.Now().Add(-updatedAgo)
+		require.NoError(t, driverRepo.Create(ctx, driver))
+		return driver
+	}
+
+	// Водитель на смене без сигнала час и свободный водитель без сигнала полчаса
+	silent := newDriver("1", entities.StatusOnShift, time.Hour)
+	shift := entities.NewDriverShift(silent.ID, nil, nil)
+	shift.StartTime = time.Now().Add(-time.Hour)
+	require.NoError(t, shiftRepo.Create(ctx, shift))
+	idle := newDriver("2", entities.StatusAvailable, 30*time.Minute)
+
+	// Сигнал, точка местоположения и заказ держат водителя на линии
+	pinged := newDriver("3", entities.StatusAvailable, time.Hour)
+	_, err := service.RecordHeartbeat(ctx, pinged.ID)
+	require.NoError(t, err)
+	located := newDriver("4", entities.StatusOnShift, time.Hour)
+	require.NoError(t, service.HandleLocationEvent(ctx, eventLocationUpdated, located.ID))
+	onTrip := newDriver("5", entities.StatusBusy, time.Hour)
+
+	result, err := service.DetectOffline(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &entities.OfflineDetection{Checked: 5, WentOffline: 2, ShiftsEnded: 1}, result)
+	assert.True(t, events.has(eventDriverWentOffline))
+	assert.True(t, events.has(eventShiftEnded))
+
+	stored, err := shiftRepo.GetByID(ctx, shift.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ShiftStatusCompleted, stored.Status)
+	assert.Equal(t, true, stored.Metadata["offline"])
+
+	for _, driver := range []*entities.Driver{silent, idle} {
+		updated, err := driverRepo.GetByID(ctx, driver.ID)
+		require.NoError(t, err)
+		assert.Equal(t, entities.StatusInactive, updated.Status)
+	}
+	for _, driver := range []*entities.Driver{pinged, located, onTrip} {
+		updated, err := driverRepo.GetByID(ctx, driver.ID)
+		require.NoError(t, err)
+		assert.Equal(t, driver.Status, updated.Status)
+	}
+}
+
+func TestHeartbeatService_RecordHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	heartbeatRepo := memory.NewHeartbeatRepository()
+	service := NewHeartbeatService(heartbeatRepo, driverRepo, nil, nil, &recordingEventPublisher{},
+		HeartbeatPolicy{OfflineAfter: 10 * time.Minute, TouchInterval: time.Minute}, zap.NewNop())
+
+	driver := newTestDriver("1")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	// Точки чаще TouchInterval не перезаписывают сигнал
+	require.NoError(t, service.HandleLocationEvent(ctx, eventLocationUpdated, driver.ID))
+	first, err := heartbeatRepo.ListByDriverIDs(ctx, []uuid.UUID{driver.ID})
+	require.NoError(t, err)
+	require.Contains(t, first, driver.ID)
+	assert.Equal(t, entities.HeartbeatSourceLocation, first[driver.ID].Source)
+
+	require.NoError(t, service.HandleLocationEvent(ctx, eventLocationUpdated, driver.ID))
+	second, err := heartbeatRepo.ListByDriverIDs(ctx, []uuid.UUID{driver.ID})
+	require.NoError(t, err)
+	assert.Equal(t, first[driver.ID].LastSeenAt, second[driver.ID].LastSeenAt)
+
+	heartbeat, err := service.RecordHeartbeat(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.HeartbeatSourceHeartbeat, heartbeat.Source)
+
+	_, err = service.RecordHeartbeat(ctx, uuid.New())
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
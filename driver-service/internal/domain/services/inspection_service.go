@@ -0,0 +1,704 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultInspectionType тип техосмотра по умолчанию
+const DefaultInspectionType = "periodic"
+
+// PhotoInspectionType тип техосмотра, который водитель проходит, отправляя фотографии автомобиля
+const PhotoInspectionType = "photo"
+
+// photoExtensions допустимые типы фотографий автомобиля и их расширения
+var photoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/heic": ".heic",
+}
+
+// InspectionPolicy правила планирования техосмотров и блокировки смен
+type InspectionPolicy struct {
+	// BlockShiftOnOverdue запрещает начало смены на автомобиле с просроченным техосмотром
+	BlockShiftOnOverdue bool
+	// IntervalDays через сколько дней после успешного техосмотра планируется следующий
+	IntervalDays int
+	// ReminderDays за сколько дней до срока отправляется напоминание
+	ReminderDays int
+	// PhotoIntervalDays через сколько дней после принятых фотографий планируется следующий фотоосмотр
+	PhotoIntervalDays int
+	// PhotoGraceDays сколько дней после срока фотоосмотра смены еще не блокируются
+	PhotoGraceDays int
+	// MaxPhotoSize максимальный размер одной фотографии в байтах
+	MaxPhotoSize int64
+}
+
+// InspectionService интерфейс для управления техосмотрами автомобилей
+type InspectionService interface {
+	ScheduleInspection(ctx context.Context, req *entities.ScheduleInspectionRequest) (*entities.VehicleInspection, error)
+	CompleteInspection(ctx context.Context, id uuid.UUID, req *entities.CompleteInspectionRequest) (*entities.VehicleInspection, error)
+	GetInspection(ctx context.Context, id uuid.UUID) (*entities.VehicleInspection, error)
+	ListInspections(ctx context.Context, filters *entities.InspectionFilters) ([]*entities.VehicleInspection, error)
+	CountInspections(ctx context.Context, filters *entities.InspectionFilters) (int, error)
+	EnsureVehicleCanOperate(ctx context.Context, vehicleID uuid.UUID) error
+	SendDueReminders(ctx context.Context) (int, error)
+	GetComplianceReport(ctx context.Context, fleetID *uuid.UUID) (*entities.InspectionComplianceReport, error)
+
+	UploadPhoto(ctx context.Context, driverID, inspectionID uuid.UUID, angle entities.PhotoAngle, upload *FileUpload) (*entities.InspectionPhoto, error)
+	SubmitPhotos(ctx context.Context, driverID, inspectionID uuid.UUID) (*entities.VehicleInspection, error)
+	ReviewPhotos(ctx context.Context, id uuid.UUID, req *entities.ReviewInspectionRequest) (*entities.VehicleInspection, error)
+	GetVehicleSummary(ctx context.Context, vehicleID uuid.UUID) (*entities.VehicleInspectionSummary, error)
+	GetDriverSummary(ctx context.Context, driverID uuid.UUID) (*entities.DriverInspectionSummary, error)
+}
+
+// inspectionService реализация InspectionService
+type inspectionService struct {
+	inspectionRepo repositories.InspectionRepository
+	notifier       NotificationSender
+	storage        FileStorage
+	eventBus       EventPublisher
+	policy         InspectionPolicy
+	logger         *zap.Logger
+}
+
+// NewInspectionService создает новый InspectionService
+func NewInspectionService(
+	inspectionRepo repositories.InspectionRepository,
+	notifier NotificationSender,
+	storage FileStorage,
+	eventBus EventPublisher,
+	policy InspectionPolicy,
+	logger *zap.Logger,
+) InspectionService {
+	return &inspectionService{
+		inspectionRepo: inspectionRepo,
+		notifier:       notifier,
+		storage:        storage,
+		eventBus:       eventBus,
+		policy:         policy,
+		logger:         logger,
+	}
+}
+
+// ScheduleInspection планирует техосмотр автомобиля
+func (s *inspectionService) ScheduleInspection(ctx context.Context, req *entities.ScheduleInspectionRequest) (*entities.VehicleInspection, error) {
+	inspectionType := req.InspectionType
+	if inspectionType == "" {
+		inspectionType = DefaultInspectionType
+	}
+
+	inspection := entities.NewVehicleInspection(req.VehicleID, inspectionType, req.DueDate)
+	inspection.FleetID = req.FleetID
+	inspection.DriverID = req.DriverID
+	inspection.Notes = req.Notes
+
+	if err := inspection.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.inspectionRepo.Create(ctx, inspection); err != nil {
+		s.logger.Error("Failed to schedule inspection",
+			zap.Error(err),
+			zap.String("vehicle_id", req.VehicleID.String()),
+		)
+		return nil, fmt.Errorf("failed to schedule inspection: %w", err)
+	}
+
+	s.logger.Info("Inspection scheduled",
+		zap.String("inspection_id", inspection.ID.String()),
+		zap.String("vehicle_id", inspection.VehicleID.String()),
+		zap.Time("due_date", inspection.DueDate),
+	)
+
+	return inspection, nil
+}
+
+// CompleteInspection фиксирует результат техосмотра и планирует следующий при успешном прохождении
+func (s *inspectionService) CompleteInspection(ctx context.Context, id uuid.UUID, req *entities.CompleteInspectionRequest) (*entities.VehicleInspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !inspection.IsPending() {
+		return nil, entities.ErrInspectionAlreadyCompleted
+	}
+
+	passed := req.Passed != nil && *req.Passed
+	inspection.Complete(passed, req.InspectedBy, req.Notes)
+
+	if err := s.inspectionRepo.Update(ctx, inspection); err != nil {
+		s.logger.Error("Failed to complete inspection",
+			zap.Error(err),
+			zap.String("inspection_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to complete inspection: %w", err)
+	}
+
+	if passed {
+		s.scheduleNext(ctx, inspection)
+	}
+	s.publishInspectionEvent(ctx, eventInspectionCompleted, inspection)
+
+	return inspection, nil
+}
+
+// GetInspection получает техосмотр по ID вместе с фотографиями
+func (s *inspectionService) GetInspection(ctx context.Context, id uuid.UUID) (*entities.VehicleInspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachPhotos(ctx, inspection); err != nil {
+		return nil, err
+	}
+
+	return inspection, nil
+}
+
+// ListInspections получает список техосмотров с фильтрами
+func (s *inspectionService) ListInspections(ctx context.Context, filters *entities.InspectionFilters) ([]*entities.VehicleInspection, error) {
+	inspections, err := s.inspectionRepo.List(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list inspections", zap.Error(err))
+		return nil, err
+	}
+
+	return inspections, nil
+}
+
+// CountInspections возвращает количество техосмотров с фильтрами
+func (s *inspectionService) CountInspections(ctx context.Context, filters *entities.InspectionFilters) (int, error) {
+	return s.inspectionRepo.Count(ctx, filters)
+}
+
+// EnsureVehicleCanOperate проверяет, что у автомобиля нет просроченных техосмотров
+func (s *inspectionService) EnsureVehicleCanOperate(ctx context.Context, vehicleID uuid.UUID) error {
+	if !s.policy.BlockShiftOnOverdue {
+		return nil
+	}
+
+	now := time.Now()
+	inspections, err := s.inspectionRepo.List(ctx, &entities.InspectionFilters{
+		VehicleID: &vehicleID,
+		Status:    []entities.InspectionStatus{entities.InspectionStatusScheduled},
+		DueBefore: &now,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check vehicle inspections: %w", err)
+	}
+
+	for _, inspection := range inspections {
+		if s.isBlocking(inspection, now) {
+			s.logger.Warn("Vehicle has overdue inspection",
+				zap.String("vehicle_id", vehicleID.String()),
+				zap.String("inspection_id", inspection.ID.String()),
+			)
+			return entities.ErrInspectionOverdue
+		}
+	}
+
+	return nil
+}
+
+// SendDueReminders отправляет напоминания водителям о приближающихся и просроченных техосмотрах
+func (s *inspectionService) SendDueReminders(ctx context.Context) (int, error) {
+	dueBefore := time.Now().AddDate(0, 0, s.policy.ReminderDays)
+	inspections, err := s.inspectionRepo.List(ctx, &entities.InspectionFilters{
+		Status:    []entities.InspectionStatus{entities.InspectionStatusScheduled},
+		DueBefore: &dueBefore,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due inspections: %w", err)
+	}
+
+	sent := 0
+	for _, inspection := range inspections {
+		if inspection.DriverID == nil || inspection.ReminderSentAt != nil {
+			continue
+		}
+
+		template := NotificationInspectionDue
+		if inspection.IsOverdue() {
+			template = NotificationInspectionOverdue
+		}
+
+		data := map[string]interface{}{
+			"inspection_id":   inspection.ID.String(),
+			"vehicle_id":      inspection.VehicleID.String(),
+			"inspection_type": inspection.InspectionType,
+			"due_date":        inspection.DueDate,
+		}
+
+		if err := s.notifier.SendToDriver(ctx, *inspection.DriverID, template, data); err != nil {
+			s.logger.Error("Failed to send inspection reminder",
+				zap.Error(err),
+				zap.String("inspection_id", inspection.ID.String()),
+			)
+			continue
+		}
+
+		inspection.MarkReminderSent()
+		if err := s.inspectionRepo.Update(ctx, inspection); err != nil {
+			s.logger.Error("Failed to mark inspection reminder as sent",
+				zap.Error(err),
+				zap.String("inspection_id", inspection.ID.String()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("Inspection reminders sent", zap.Int("count", sent))
+	}
+
+	return sent, nil
+}
+
+// GetComplianceReport строит отчет о соответствии автопарка требованиям техосмотра
+func (s *inspectionService) GetComplianceReport(ctx context.Context, fleetID *uuid.UUID) (*entities.InspectionComplianceReport, error) {
+	inspections, err := s.inspectionRepo.List(ctx, &entities.InspectionFilters{
+		FleetID: fleetID,
+		Status: []entities.InspectionStatus{
+			entities.InspectionStatusScheduled,
+			entities.InspectionStatusSubmitted,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list inspections: %w", err)
+	}
+
+	// Для каждого автомобиля учитываем ближайший незавершенный техосмотр
+	vehicles := make(map[uuid.UUID]*entities.VehicleCompliance)
+	for _, inspection := range inspections {
+		state := s.complianceState(inspection)
+
+		compliance, ok := vehicles[inspection.VehicleID]
+		if !ok {
+			dueDate := inspection.DueDate
+			vehicles[inspection.VehicleID] = &entities.VehicleCompliance{
+				VehicleID:   inspection.VehicleID,
+				FleetID:     inspection.FleetID,
+				State:       state,
+				NextDueDate: &dueDate,
+			}
+			continue
+		}
+
+		if inspection.DueDate.Before(*compliance.NextDueDate) {
+			dueDate := inspection.DueDate
+			compliance.NextDueDate = &dueDate
+			compliance.State = state
+		}
+	}
+
+	report := &entities.InspectionComplianceReport{
+		FleetID:     fleetID,
+		Vehicles:    make([]*entities.VehicleCompliance, 0, len(vehicles)),
+		GeneratedAt: time.Now(),
+	}
+
+	for _, compliance := range vehicles {
+		switch compliance.State {
+		case entities.ComplianceStateOverdue:
+			report.Overdue++
+		case entities.ComplianceStateDueSoon:
+			report.DueSoon++
+		case entities.ComplianceStateInReview:
+			report.InReview++
+		default:
+			report.Compliant++
+		}
+		report.Vehicles = append(report.Vehicles, compliance)
+	}
+
+	sort.Slice(report.Vehicles, func(i, j int) bool {
+		return report.Vehicles[i].NextDueDate.Before(*report.Vehicles[j].NextDueDate)
+	})
+
+	report.TotalVehicles = len(report.Vehicles)
+	if report.TotalVehicles > 0 {
+		report.ComplianceRate = float64(report.TotalVehicles-report.Overdue) / float64(report.TotalVehicles)
+	}
+
+	return report, nil
+}
+
+// UploadPhoto загружает фотографию автомобиля для фотоосмотра водителя.
+// Повторная загрузка того же ракурса заменяет фотографию, в том числе отклоненную
+func (s *inspectionService) UploadPhoto(ctx context.Context, driverID, inspectionID uuid.UUID, angle entities.PhotoAngle, upload *FileUpload) (*entities.InspectionPhoto, error) {
+	if !angle.IsValid() {
+		return nil, entities.ErrInvalidPhotoAngle
+	}
+
+	extension, ok := photoExtensions[upload.ContentType]
+	if !ok || upload.Size <= 0 || (s.policy.MaxPhotoSize > 0 && upload.Size > s.policy.MaxPhotoSize) {
+		return nil, entities.ErrInvalidInspectionPhoto
+	}
+
+	inspection, err := s.getDriverPhotoInspection(ctx, driverID, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !inspection.IsPending() {
+		return nil, entities.ErrInspectionNotEditable
+	}
+
+	key := path.Join("inspections", inspection.ID.String(), string(angle)+extension)
+	url, err := s.storage.Save(ctx, key, upload.ContentType, io.LimitReader(upload.Body, upload.Size))
+	if err != nil {
+		s.logger.Error("Failed to save inspection photo",
+			zap.Error(err),
+			zap.String("inspection_id", inspection.ID.String()),
+			zap.String("angle", string(angle)),
+		)
+		return nil, fmt.Errorf("failed to save inspection photo: %w", err)
+	}
+
+	photo := entities.NewInspectionPhoto(inspection.ID, angle, url, upload.ContentType, upload.Size)
+	if err := s.inspectionRepo.SavePhoto(ctx, photo); err != nil {
+		return nil, fmt.Errorf("failed to save inspection photo: %w", err)
+	}
+
+	return photo, nil
+}
+
+// SubmitPhotos отправляет фотографии на проверку. Требуются фотографии всех ракурсов
+// без отклоненных
+func (s *inspectionService) SubmitPhotos(ctx context.Context, driverID, inspectionID uuid.UUID) (*entities.VehicleInspection, error) {
+	inspection, err := s.getDriverPhotoInspection(ctx, driverID, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if !inspection.IsPending() {
+		return nil, entities.ErrInspectionNotEditable
+	}
+
+	if err := s.attachPhotos(ctx, inspection); err != nil {
+		return nil, err
+	}
+
+	byAngle := make(map[entities.PhotoAngle]*entities.InspectionPhoto, len(inspection.Photos))
+	for _, photo := range inspection.Photos {
+		byAngle[photo.Angle] = photo
+	}
+	for _, angle := range entities.RequiredPhotoAngles {
+		photo, ok := byAngle[angle]
+		if !ok || photo.Status == entities.PhotoStatusRejected {
+			return nil, entities.ErrInspectionPhotosMissing
+		}
+	}
+
+	inspection.Submit()
+	if err := s.inspectionRepo.Update(ctx, inspection); err != nil {
+		s.logger.Error("Failed to submit inspection",
+			zap.Error(err),
+			zap.String("inspection_id", inspection.ID.String()),
+		)
+		return nil, fmt.Errorf("failed to submit inspection: %w", err)
+	}
+
+	s.logger.Info("Inspection photos submitted",
+		zap.String("inspection_id", inspection.ID.String()),
+		zap.String("vehicle_id", inspection.VehicleID.String()),
+	)
+	s.publishInspectionEvent(ctx, eventInspectionSubmitted, inspection)
+
+	return inspection, nil
+}
+
+// ReviewPhotos фиксирует решения по каждой фотографии. Если приняты все фотографии,
+// техосмотр пройден и планируется следующий; иначе техосмотр возвращается водителю
+// для пересъемки отклоненных ракурсов
+func (s *inspectionService) ReviewPhotos(ctx context.Context, id uuid.UUID, req *entities.ReviewInspectionRequest) (*entities.VehicleInspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !inspection.IsSubmitted() {
+		return nil, entities.ErrInspectionNotSubmitted
+	}
+
+	if err := s.attachPhotos(ctx, inspection); err != nil {
+		return nil, err
+	}
+
+	decisions := make(map[entities.PhotoAngle]entities.PhotoDecision, len(req.Decisions))
+	for _, decision := range req.Decisions {
+		if !decision.Angle.IsValid() {
+			return nil, entities.ErrInvalidPhotoAngle
+		}
+		decisions[decision.Angle] = decision
+	}
+
+	// Решения проверяются целиком до изменения фотографий
+	for _, photo := range inspection.Photos {
+		if _, ok := decisions[photo.Angle]; !ok {
+			return nil, entities.ErrInspectionReviewIncomplete
+		}
+	}
+	if len(decisions) != len(inspection.Photos) {
+		return nil, entities.ErrInvalidPhotoAngle
+	}
+
+	var rejected []string
+	for _, photo := range inspection.Photos {
+		decision := decisions[photo.Angle]
+		if decision.Accepted != nil && *decision.Accepted {
+			photo.Accept()
+		} else {
+			photo.Reject(decision.Reason)
+			rejected = append(rejected, string(photo.Angle))
+		}
+
+		if err := s.inspectionRepo.UpdatePhoto(ctx, photo); err != nil {
+			return nil, fmt.Errorf("failed to review inspection photo: %w", err)
+		}
+	}
+
+	if len(rejected) == 0 {
+		inspection.Complete(true, req.ReviewedBy, req.Notes)
+	} else {
+		inspection.Reopen(req.Notes)
+	}
+
+	if err := s.inspectionRepo.Update(ctx, inspection); err != nil {
+		s.logger.Error("Failed to review inspection",
+			zap.Error(err),
+			zap.String("inspection_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to review inspection: %w", err)
+	}
+
+	if len(rejected) > 0 {
+		s.notifyPhotosRejected(ctx, inspection, rejected)
+		return inspection, nil
+	}
+
+	s.scheduleNext(ctx, inspection)
+	s.publishInspectionEvent(ctx, eventInspectionCompleted, inspection)
+
+	return inspection, nil
+}
+
+// GetVehicleSummary возвращает состояние техосмотров автомобиля
+func (s *inspectionService) GetVehicleSummary(ctx context.Context, vehicleID uuid.UUID) (*entities.VehicleInspectionSummary, error) {
+	inspections, err := s.inspectionRepo.List(ctx, &entities.InspectionFilters{VehicleID: &vehicleID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vehicle inspections: %w", err)
+	}
+
+	now := time.Now()
+	summary := &entities.VehicleInspectionSummary{
+		VehicleID:  vehicleID,
+		State:      entities.ComplianceStateCompliant,
+		CanOperate: true,
+		Open:       make([]*entities.VehicleInspection, 0),
+	}
+
+	for _, inspection := range inspections {
+		switch inspection.Status {
+		case entities.InspectionStatusScheduled, entities.InspectionStatusSubmitted:
+			if inspection.InspectionType == PhotoInspectionType {
+				if err := s.attachPhotos(ctx, inspection); err != nil {
+					return nil, err
+				}
+			}
+			summary.Open = append(summary.Open, inspection)
+
+			if state := s.complianceState(inspection); complianceRank[state] > complianceRank[summary.State] {
+				summary.State = state
+			}
+			if inspection.IsPending() && (summary.NextDueDate == nil || inspection.DueDate.Before(*summary.NextDueDate)) {
+				dueDate := inspection.DueDate
+				summary.NextDueDate = &dueDate
+			}
+			if s.policy.BlockShiftOnOverdue && s.isBlocking(inspection, now) {
+				summary.CanOperate = false
+			}
+		case entities.InspectionStatusPassed, entities.InspectionStatusFailed:
+			if summary.LastCompleted == nil || inspection.CompletedAt.After(*summary.LastCompleted.CompletedAt) {
+				summary.LastCompleted = inspection
+			}
+		}
+	}
+
+	return summary, nil
+}
+
+// GetDriverSummary возвращает состояние техосмотров автомобилей, закрепленных за водителем
+func (s *inspectionService) GetDriverSummary(ctx context.Context, driverID uuid.UUID) (*entities.DriverInspectionSummary, error) {
+	inspections, err := s.inspectionRepo.List(ctx, &entities.InspectionFilters{DriverID: &driverID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list driver inspections: %w", err)
+	}
+
+	summary := &entities.DriverInspectionSummary{
+		DriverID: driverID,
+		State:    entities.ComplianceStateCompliant,
+		Vehicles: make([]*entities.VehicleInspectionSummary, 0),
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, inspection := range inspections {
+		if seen[inspection.VehicleID] {
+			continue
+		}
+		seen[inspection.VehicleID] = true
+
+		vehicle, err := s.GetVehicleSummary(ctx, inspection.VehicleID)
+		if err != nil {
+			return nil, err
+		}
+		if complianceRank[vehicle.State] > complianceRank[summary.State] {
+			summary.State = vehicle.State
+		}
+		summary.Vehicles = append(summary.Vehicles, vehicle)
+	}
+
+	return summary, nil
+}
+
+// complianceRank приоритет состояний: чем выше, тем больше внимания требует автомобиль
+var complianceRank = map[entities.ComplianceState]int{
+	entities.ComplianceStateCompliant: 0,
+	entities.ComplianceStateInReview:  1,
+	entities.ComplianceStateDueSoon:   2,
+	entities.ComplianceStateOverdue:   3,
+}
+
+// complianceState определяет состояние незавершенного техосмотра
+func (s *inspectionService) complianceState(inspection *entities.VehicleInspection) entities.ComplianceState {
+	switch {
+	case inspection.IsSubmitted():
+		return entities.ComplianceStateInReview
+	case inspection.IsOverdue():
+		return entities.ComplianceStateOverdue
+	case inspection.IsDueWithin(s.policy.ReminderDays):
+		return entities.ComplianceStateDueSoon
+	default:
+		return entities.ComplianceStateCompliant
+	}
+}
+
+// isBlocking проверяет, блокирует ли техосмотр начало смены.
+// Для фотоосмотра после срока действует льготный период PhotoGraceDays
+func (s *inspectionService) isBlocking(inspection *entities.VehicleInspection, now time.Time) bool {
+	if !inspection.IsPending() {
+		return false
+	}
+
+	deadline := inspection.DueDate
+	if inspection.InspectionType == PhotoInspectionType {
+		deadline = deadline.AddDate(0, 0, s.policy.PhotoGraceDays)
+	}
+	return now.After(deadline)
+}
+
+// scheduleNext планирует следующий техосмотр после успешного прохождения
+func (s *inspectionService) scheduleNext(ctx context.Context, inspection *entities.VehicleInspection) {
+	intervalDays := s.policy.IntervalDays
+	if inspection.InspectionType == PhotoInspectionType {
+		intervalDays = s.policy.PhotoIntervalDays
+	}
+	if intervalDays <= 0 {
+		return
+	}
+
+	next := entities.NewVehicleInspection(
+		inspection.VehicleID,
+		inspection.InspectionType,
+		inspection.CompletedAt.AddDate(0, 0, intervalDays),
+	)
+	next.FleetID = inspection.FleetID
+	next.DriverID = inspection.DriverID
+
+	if err := s.inspectionRepo.Create(ctx, next); err != nil {
+		s.logger.Error("Failed to schedule next inspection",
+			zap.Error(err),
+			zap.String("vehicle_id", inspection.VehicleID.String()),
+		)
+		// Не возвращаем ошибку, так как результат техосмотра уже сохранен
+	}
+}
+
+// publishInspectionEvent публикует событие техосмотра водителю, закрепленному за техосмотром
+func (s *inspectionService) publishInspectionEvent(ctx context.Context, eventType string, inspection *entities.VehicleInspection) {
+	if inspection.DriverID == nil {
+		return
+	}
+
+	eventData := map[string]interface{}{
+		"inspection_id": inspection.ID.String(),
+		"vehicle_id":    inspection.VehicleID.String(),
+		"status":        string(inspection.Status),
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, *inspection.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish inspection event",
+			zap.Error(err),
+			zap.String("event_type", eventType),
+			zap.String("inspection_id", inspection.ID.String()),
+		)
+	}
+}
+
+// notifyPhotosRejected уведомляет водителя о ракурсах, которые нужно переснять
+func (s *inspectionService) notifyPhotosRejected(ctx context.Context, inspection *entities.VehicleInspection, angles []string) {
+	if inspection.DriverID == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"inspection_id": inspection.ID.String(),
+		"vehicle_id":    inspection.VehicleID.String(),
+		"angles":        angles,
+		"due_date":      inspection.DueDate,
+	}
+
+	if err := s.notifier.SendToDriver(ctx, *inspection.DriverID, NotificationInspectionPhotosRejected, data); err != nil {
+		s.logger.Error("Failed to notify about rejected inspection photos",
+			zap.Error(err),
+			zap.String("inspection_id", inspection.ID.String()),
+		)
+	}
+}
+
+// getDriverPhotoInspection получает фотоосмотр, закрепленный за водителем.
+// Чужой техосмотр не раскрывается и возвращается как не найденный
+func (s *inspectionService) getDriverPhotoInspection(ctx context.Context, driverID, inspectionID uuid.UUID) (*entities.VehicleInspection, error) {
+	inspection, err := s.inspectionRepo.GetByID(ctx, inspectionID)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.DriverID == nil || *inspection.DriverID != driverID {
+		return nil, entities.ErrInspectionNotFound
+	}
+	if inspection.InspectionType != PhotoInspectionType {
+		return nil, entities.ErrNotPhotoInspection
+	}
+	return inspection, nil
+}
+
+// attachPhotos загружает фотографии техосмотра
+func (s *inspectionService) attachPhotos(ctx context.Context, inspection *entities.VehicleInspection) error {
+	photos, err := s.inspectionRepo.ListPhotos(ctx, inspection.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list inspection photos: %w", err)
+	}
+	inspection.Photos = photos
+	return nil
+}
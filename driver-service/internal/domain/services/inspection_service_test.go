@@ -0,0 +1,268 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingNotifier запоминает отправленные уведомления
+type recordingNotifier struct {
+	templates []string
+}
+
+func (n *recordingNotifier) SendToDriver(ctx context.Context, driverID uuid.UUID, template string, data map[string]interface{}) error {
+	n.templates = append(n.templates, template)
+	return nil
+}
+
+func TestInspectionService_BlocksShiftOnOverdue(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	inspectionRepo := memory.NewInspectionRepository()
+	events := &recordingEventPublisher{}
+	policy := InspectionPolicy{BlockShiftOnOverdue: true, IntervalDays: 365, ReminderDays: 14}
+
+	inspections := NewInspectionService(inspectionRepo, &recordingNotifier{}, nil, events, policy, zap.NewNop())
+	shifts := NewShiftService(memory.NewShiftRepository(), memory.NewShiftBreakRepository(), driverRepo, memory.NewLocationRepository(), nil, inspections, nil, events, ShiftPolicy{}, zap.NewNop())
+
+	driver := newTestDriver("201")
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	vehicleID := uuid.New()
+	overdue, err := inspections.ScheduleInspection(ctx, &entities.ScheduleInspectionRequest{
+		VehicleID: vehicleID,
+		DriverID:  &driver.ID,
+		DueDate:   time.Now().Add(-24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = shifts.StartShift(ctx, driver.ID, &entities.ShiftStartRequest{VehicleID: &vehicleID})
+	assert.Equal(t, entities.ErrInspectionOverdue, err)
+
+	passed := true
+	_, err = inspections.CompleteInspection(ctx, overdue.ID, &entities.CompleteInspectionRequest{
+		Passed:      &passed,
+		InspectedBy: "Станция №1",
+	})
+	require.NoError(t, err)
+
+	// После прохождения автоматически планируется следующий техосмотр
+	next, err := inspections.ListInspections(ctx, &entities.InspectionFilters{
+		VehicleID: &vehicleID,
+		Status:    []entities.InspectionStatus{entities.InspectionStatusScheduled},
+	})
+	require.NoError(t, err)
+	require.Len(t, next, 1)
+	assert.True(t, next[0].DueDate.After(time.Now().AddDate(0, 0, 364)))
+
+	shift, err := shifts.StartShift(ctx, driver.ID, &entities.ShiftStartRequest{VehicleID: &vehicleID})
+	require.NoError(t, err)
+	assert.True(t, shift.IsActive())
+	assert.True(t, events.has("driver.shift.started"))
+
+	_, err = shifts.StartShift(ctx, driver.ID, &entities.ShiftStartRequest{})
+	assert.Equal(t, entities.ErrDriverNotAvailable, err)
+
+	_, err = shifts.EndShift(ctx, driver.ID, &entities.ShiftEndRequest{})
+	require.NoError(t, err)
+
+	updated, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusAvailable, updated.Status)
+}
+
+func TestInspectionService_RemindersAndCompliance(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordingNotifier{}
+	policy := InspectionPolicy{ReminderDays: 14}
+	service := NewInspectionService(memory.NewInspectionRepository(), notifier, nil, &recordingEventPublisher{}, policy, zap.NewNop())
+
+	fleetID := uuid.New()
+	driverID := uuid.New()
+	schedule := func(dueDate time.Time) {
+		_, err := service.ScheduleInspection(ctx, &entities.ScheduleInspectionRequest{
+			VehicleID: uuid.New(),
+			FleetID:   &fleetID,
+			DriverID:  &driverID,
+			DueDate:   dueDate,
+		})
+		require.NoError(t, err)
+	}
+
+	schedule(time.Now().Add(-48 * time.Hour))
+	schedule(time.Now().AddDate(0, 0, 7))
+	schedule(time.Now().AddDate(0, 0, 90))
+
+	sent, err := service.SendDueReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.ElementsMatch(t, []string{NotificationInspectionOverdue, NotificationInspectionDue}, notifier.templates)
+
+	// Повторно напоминания не отправляются
+	sent, err = service.SendDueReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+
+	report, err := service.GetComplianceReport(ctx, &fleetID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.TotalVehicles)
+	assert.Equal(t, 1, report.Overdue)
+	assert.Equal(t, 1, report.DueSoon)
+	assert.Equal(t, 1, report.Compliant)
+	assert.InDelta(t, 2.0/3.0, report.ComplianceRate, 0.001)
+	assert.Equal(t, entities.ComplianceStateOverdue, report.Vehicles[0].State)
+}
+
+func TestInspectionService_PhotoInspection(t *testing.T) {
+	ctx := context.Background()
+	notifier := &recordingNotifier{}
+	events := &recordingEventPublisher{}
+	photos := &fakeFileStorage{files: make(map[string][]byte)}
+	policy := InspectionPolicy{
+		BlockShiftOnOverdue: true,
+		ReminderDays:        14,
+		PhotoIntervalDays:   30,
+		PhotoGraceDays:      3,
+		MaxPhotoSize:        1024,
+	}
+	service := NewInspectionService(memory.NewInspectionRepository(), notifier, photos, events, policy, zap.NewNop())
+
+	driverID := uuid.New()
+	vehicleID := uuid.New()
+	inspection, err := service.ScheduleInspection(ctx, &entities.ScheduleInspectionRequest{
+		VehicleID:      vehicleID,
+		DriverID:       &driverID,
+		InspectionType: PhotoInspectionType,
+		DueDate:        time.Now().Add(-24 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	// Просрочка в пределах льготного периода не блокирует смены
+	assert.NoError(t, service.EnsureVehicleCanOperate(ctx, vehicleID))
+
+	upload := func(angle entities.PhotoAngle) error {
+		body := []byte("photo-" + string(angle))
+		_, err := service.UploadPhoto(ctx, driverID, inspection.ID, angle, &FileUpload{
+			ContentType: "image/jpeg",
+			Size:        int64(len(body)),
+			Body:        bytes.NewReader(body),
+		})
+		return err
+	}
+
+	assert.Equal(t, entities.ErrInvalidPhotoAngle, upload("roof"))
+	_, err = service.UploadPhoto(ctx, driverID, inspection.ID, entities.PhotoAngleFront, &FileUpload{ContentType: "application/pdf", Size: 10, Body: bytes.NewReader(make([]byte, 10))})
+	assert.Equal(t, entities.ErrInvalidInspectionPhoto, err)
+	_, err = service.UploadPhoto(ctx, uuid.New(), inspection.ID, entities.PhotoAngleFront, &FileUpload{ContentType: "image/png", Size: 1, Body: bytes.NewReader([]byte{1})})
+	assert.Equal(t, entities.ErrInspectionNotFound, err, "foreign inspection is not visible")
+
+	for _, angle := range entities.RequiredPhotoAngles[:4] {
+		require.NoError(t, upload(angle))
+	}
+	_, err = service.SubmitPhotos(ctx, driverID, inspection.ID)
+	assert.Equal(t, entities.ErrInspectionPhotosMissing, err, "interior photo is required")
+
+	require.NoError(t, upload(entities.PhotoAngleInterior))
+	assert.Len(t, photos.files, 5)
+	assert.Contains(t, photos.files, "inspections/"+inspection.ID.String()+"/interior.jpg")
+
+	submitted, err := service.SubmitPhotos(ctx, driverID, inspection.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.InspectionStatusSubmitted, submitted.Status)
+	assert.True(t, events.has("driver.inspection.submitted"))
+	assert.Equal(t, entities.ErrInspectionNotEditable, upload(entities.PhotoAngleFront))
+
+	summary, err := service.GetVehicleSummary(ctx, vehicleID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ComplianceStateInReview, summary.State)
+	assert.True(t, summary.CanOperate)
+
+	// Одна отклоненная фотография возвращает техосмотр водителю
+	accepted, rejected := true, false
+	reason := "Снимок размыт"
+	decisions := func(back *bool) []entities.PhotoDecision {
+		result := make([]entities.PhotoDecision, 0, len(entities.RequiredPhotoAngles))
+		for _, angle := range entities.RequiredPhotoAngles {
+			decision := entities.PhotoDecision{Angle: angle, Accepted: &accepted}
+			if angle == entities.PhotoAngleBack {
+				decision.Accepted = back
+				decision.Reason = &reason
+			}
+			result = append(result, decision)
+		}
+		return result
+	}
+
+	_, err = service.ReviewPhotos(ctx, inspection.ID, &entities.ReviewInspectionRequest{
+		ReviewedBy: "admin",
+		Decisions:  decisions(&rejected)[:4],
+	})
+	assert.Equal(t, entities.ErrInspectionReviewIncomplete, err)
+
+	reopened, err := service.ReviewPhotos(ctx, inspection.ID, &entities.ReviewInspectionRequest{
+		ReviewedBy: "admin",
+		Decisions:  decisions(&rejected),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.InspectionStatusScheduled, reopened.Status)
+	assert.Equal(t, []string{NotificationInspectionPhotosRejected}, notifier.templates)
+
+	_, err = service.SubmitPhotos(ctx, driverID, inspection.ID)
+	assert.Equal(t, entities.ErrInspectionPhotosMissing, err, "rejected photo must be retaken")
+
+	require.NoError(t, upload(entities.PhotoAngleBack))
+	_, err = service.SubmitPhotos(ctx, driverID, inspection.ID)
+	require.NoError(t, err)
+
+	passed, err := service.ReviewPhotos(ctx, inspection.ID, &entities.ReviewInspectionRequest{
+		ReviewedBy: "admin",
+		Decisions:  decisions(&accepted),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.InspectionStatusPassed, passed.Status)
+	assert.True(t, events.has("driver.inspection.completed"))
+
+	driverSummary, err := service.GetDriverSummary(ctx, driverID)
+	require.NoError(t, err)
+	require.Len(t, driverSummary.Vehicles, 1)
+	vehicle := driverSummary.Vehicles[0]
+	require.Len(t, vehicle.Open, 1, "next photo inspection is scheduled")
+	assert.True(t, vehicle.NextDueDate.After(time.Now().AddDate(0, 0, 29)))
+	require.NotNil(t, vehicle.LastCompleted)
+	assert.Equal(t, inspection.ID, vehicle.LastCompleted.ID)
+}
+
+func TestInspectionService_PhotoGracePeriod(t *testing.T) {
+	ctx := context.Background()
+	policy := InspectionPolicy{BlockShiftOnOverdue: true, PhotoGraceDays: 3}
+	service := NewInspectionService(memory.NewInspectionRepository(), &recordingNotifier{}, nil, &recordingEventPublisher{}, policy, zap.NewNop())
+
+	driverID := uuid.New()
+	vehicleID := uuid.New()
+	_, err := service.ScheduleInspection(ctx, &entities.ScheduleInspectionRequest{
+		VehicleID:      vehicleID,
+		DriverID:       &driverID,
+		InspectionType: PhotoInspectionType,
+		DueDate:        time.Now().AddDate(0, 0, -4),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, entities.ErrInspectionOverdue, service.EnsureVehicleCanOperate(ctx, vehicleID))
+
+	summary, err := service.GetDriverSummary(ctx, driverID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ComplianceStateOverdue, summary.State)
+	require.Len(t, summary.Vehicles, 1)
+	assert.False(t, summary.Vehicles[0].CanOperate)
+}
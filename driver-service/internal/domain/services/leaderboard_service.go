@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LeaderboardPolicy настройки рейтингов водителей
+type LeaderboardPolicy struct {
+	// Metrics показатели, по которым доступны рейтинги
+	Metrics []entities.LeaderboardMetric
+	// TieBreakers показатели для разрешения равенства, отдельно для каждого основного
+	TieBreakers map[entities.LeaderboardMetric][]entities.LeaderboardMetric
+	// MinRatings минимальное число оценок за неделю для рейтинга по оценкам
+	MinRatings int
+	// MaxLimit максимальный размер топа
+	MaxLimit int
+	// CacheTTL время жизни закэшированных агрегатов недели
+	CacheTTL time.Duration
+}
+
+// LeaderboardService интерфейс для рейтингов водителей
+type LeaderboardService interface {
+	GetLeaderboard(ctx context.Context, scope *entities.LeaderboardScope, limit int) (*entities.Leaderboard, error)
+	GetDriverRank(ctx context.Context, driverID uuid.UUID, scope *entities.LeaderboardScope) (*entities.DriverRank, error)
+	SetVisibility(ctx context.Context, driverID uuid.UUID, visibility entities.LeaderboardVisibility) error
+	RefreshLeaderboards(ctx context.Context) error
+	WarmCache(ctx context.Context) (int, error)
+}
+
+// leaderboardWeek закэшированные агрегаты недели
+type leaderboardWeek struct {
+	aggregates []*entities.LeaderboardAggregate
+	loadedAt   time.Time
+}
+
+// leaderboardService реализация LeaderboardService
+type leaderboardService struct {
+	leaderboardRepo repositories.LeaderboardRepository
+	driverRepo      repositories.DriverRepository
+	policy          LeaderboardPolicy
+	logger          *zap.Logger
+
+	mu    sync.RWMutex
+	weeks map[time.Time]*leaderboardWeek
+}
+
+// NewLeaderboardService создает новый LeaderboardService
+func NewLeaderboardService(
+	leaderboardRepo repositories.LeaderboardRepository,
+	driverRepo repositories.DriverRepository,
+	policy LeaderboardPolicy,
+	logger *zap.Logger,
+) LeaderboardService {
+	return &leaderboardService{
+		leaderboardRepo: leaderboardRepo,
+		driverRepo:      driverRepo,
+		policy:          policy,
+		logger:          logger,
+		weeks:           make(map[time.Time]*leaderboardWeek),
+	}
+}
+
+// GetLeaderboard возвращает топ водителей за неделю
+func (s *leaderboardService) GetLeaderboard(ctx context.Context, scope *entities.LeaderboardScope, limit int) (*entities.Leaderboard, error) {
+	ranked, generatedAt, err := s.rank(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || (s.policy.MaxLimit > 0 && limit > s.policy.MaxLimit) {
+		limit = s.policy.MaxLimit
+	}
+
+	top := ranked
+	if limit > 0 && limit < len(top) {
+		top = top[:limit]
+	}
+
+	entries := make([]*entities.LeaderboardEntry, len(top))
+	for i, r := range top {
+		entries[i] = r.Entry(scope.Metric)
+	}
+
+	return &entities.Leaderboard{
+		LeaderboardScope: *scope,
+		Entries:          entries,
+		Participants:     len(ranked),
+		GeneratedAt:      generatedAt,
+	}, nil
+}
+
+// GetDriverRank возвращает позицию водителя в рейтинге
+func (s *leaderboardService) GetDriverRank(ctx context.Context, driverID uuid.UUID, scope *entities.LeaderboardScope) (*entities.DriverRank, error) {
+	ranked, generatedAt, err := s.rank(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, r := range ranked {
+		if r.Aggregate.DriverID != driverID {
+			continue
+		}
+
+		rank := &entities.DriverRank{
+			LeaderboardScope: *scope,
+			DriverID:         driverID,
+			Rank:             r.Rank,
+			Participants:     len(ranked),
+			Value:            r.Aggregate.Value(scope.Metric),
+			Visibility:       r.Aggregate.Visibility,
+			GeneratedAt:      generatedAt,
+		}
+
+		// Ближайший водитель на более высоком месте
+		for j := i - 1; j >= 0; j-- {
+			if ranked[j].Rank < r.Rank {
+				rank.GapToNext = ranked[j].Aggregate.Value(scope.Metric) - rank.Value
+				break
+			}
+		}
+		return rank, nil
+	}
+
+	// Водителя нет в рейтинге: уточняем причину
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if driver.LeaderboardVisibility() == entities.LeaderboardVisibilityHidden {
+		return nil, entities.ErrLeaderboardOptedOut
+	}
+	return nil, entities.ErrDriverNotRanked
+}
+
+// SetVisibility изменяет настройку приватности водителя в рейтингах.
+// Кэш сбрасывается, чтобы отказ от участия применялся сразу
+func (s *leaderboardService) SetVisibility(ctx context.Context, driverID uuid.UUID, visibility entities.LeaderboardVisibility) error {
+	if !visibility.IsValid() {
+		return entities.ErrInvalidLeaderboardVisibility
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	if driver.Metadata == nil {
+		driver.Metadata = make(entities.Metadata)
+	}
+	driver.Metadata[entities.DriverMetaLeaderboardVisibility] = string(visibility)
+	driver.UpdatedAt = time.Now()
+
+	if err := s.driverRepo.Update(ctx, driver); err != nil {
+		return fmt.Errorf("failed to update leaderboard visibility: %w", err)
+	}
+
+	s.mu.Lock()
+	s.weeks = make(map[time.Time]*leaderboardWeek)
+	s.mu.Unlock()
+
+	s.logger.Info("Leaderboard visibility changed",
+		zap.String("driver_id", driverID.String()),
+		zap.String("visibility", string(visibility)),
+	)
+	return nil
+}
+
+// RefreshLeaderboards пересчитывает агрегаты и прогревает кэш текущей и предыдущей недели
+func (s *leaderboardService) RefreshLeaderboards(ctx context.Context) error {
+	if err := s.leaderboardRepo.Refresh(ctx); err != nil {
+		return err
+	}
+
+	current := entities.WeekStart(time.Now())
+	weeks := make(map[time.Time]*leaderboardWeek, 2)
+	for _, weekStart := range []time.Time{current, current.AddDate(0, 0, -7)} {
+		week, err := s.load(ctx, weekStart)
+		if err != nil {
+			return err
+		}
+		weeks[weekStart] = week
+	}
+
+	s.mu.Lock()
+	s.weeks = weeks
+	s.mu.Unlock()
+
+	s.logger.Info("Leaderboards refreshed",
+		zap.Int("current_week_participants", len(weeks[current].aggregates)),
+	)
+	return nil
+}
+
+// WarmCache загружает в кэш агрегаты текущей недели и возвращает число водителей в них
+func (s *leaderboardService) WarmCache(ctx context.Context) (int, error) {
+	week, err := s.aggregates(ctx, entities.WeekStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+	return len(week.aggregates), nil
+}
+
+// rank строит полный рейтинг для области
+func (s *leaderboardService) rank(ctx context.Context, scope *entities.LeaderboardScope) ([]*entities.RankedDriver, time.Time, error) {
+	if !s.isEnabled(scope.Metric) {
+		return nil, time.Time{}, entities.ErrInvalidLeaderboardMetric
+	}
+	scope.WeekStart = entities.WeekStart(scope.WeekStart)
+
+	week, err := s.aggregates(ctx, scope.WeekStart)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	ranked := entities.RankDrivers(week.aggregates, scope, entities.RankingRules{
+		TieBreakers: s.policy.TieBreakers[scope.Metric],
+		MinRatings:  s.policy.MinRatings,
+	})
+	return ranked, week.loadedAt, nil
+}
+
+// aggregates возвращает агрегаты недели из кэша или из репозитория
+func (s *leaderboardService) aggregates(ctx context.Context, weekStart time.Time) (*leaderboardWeek, error) {
+	s.mu.RLock()
+	week, ok := s.weeks[weekStart]
+	s.mu.RUnlock()
+
+	if ok && time.Since(week.loadedAt) < s.policy.CacheTTL {
+		return week, nil
+	}
+
+	week, err := s.load(ctx, weekStart)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.weeks[weekStart] = week
+	s.mu.Unlock()
+
+	return week, nil
+}
+
+// load загружает агрегаты недели из репозитория
+func (s *leaderboardService) load(ctx context.Context, weekStart time.Time) (*leaderboardWeek, error) {
+	aggregates, err := s.leaderboardRepo.GetWeeklyAggregates(ctx, weekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load leaderboard aggregates: %w", err)
+	}
+	return &leaderboardWeek{aggregates: aggregates, loadedAt: time.Now()}, nil
+}
+
+// isEnabled проверяет, доступен ли рейтинг по показателю
+func (s *leaderboardService) isEnabled(metric entities.LeaderboardMetric) bool {
+	for _, enabled := range s.policy.Metrics {
+		if enabled == metric {
+			return true
+		}
+	}
+	return false
+}
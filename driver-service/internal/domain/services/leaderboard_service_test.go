@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLeaderboardService(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	shiftRepo := memory.NewShiftRepository()
+	ratingRepo := memory.NewRatingRepository()
+
+	service := NewLeaderboardService(
+		memory.NewLeaderboardRepository(driverRepo, shiftRepo, ratingRepo),
+		driverRepo,
+		LeaderboardPolicy{
+			Metrics: []entities.LeaderboardMetric{entities.LeaderboardMetricTrips, entities.LeaderboardMetricRating},
+			TieBreakers: map[entities.LeaderboardMetric][]entities.LeaderboardMetric{
+				entities.LeaderboardMetricTrips: {entities.LeaderboardMetricRating},
+			},
+			MinRatings: 2,
+			MaxLimit:   10,
+			CacheTTL:   time.Hour,
+		},
+		zap.NewNop(),
+	)
+
+	fleetID := uuid.New()
+	addDriver := func(suffix, firstName string, trips int, ratings ...int) *entities.Driver {
+		driver := entities.NewDriver("+7900000050"+suffix, "", firstName, "Рейтингов", "")
+		driver.Metadata[entities.DriverMetaCity] = "Москва"
+		driver.FleetID = &fleetID
+		require.NoError(t, driverRepo.Create(ctx, driver))
+
+		shift := entities.NewDriverShift(driver.ID, nil, nil)
+		shift.TotalTrips = trips
+		require.NoError(t, shiftRepo.Create(ctx, shift))
+
+		for _, value := range ratings {
+			require.NoError(t, ratingRepo.Create(ctx, entities.NewDriverRating(driver.ID, value, entities.RatingTypeCustomer)))
+		}
+		return driver
+	}
+
+	leader := addDriver("1", "Анна", 30, 5, 5)
+	second := addDriver("2", "Борис", 20, 5, 4)
+	tied := addDriver("3", "Вера", 20, 4, 4)
+	single := addDriver("4", "Глеб", 5, 5)
+
+	scope := func(metric entities.LeaderboardMetric) *entities.LeaderboardScope {
+		return &entities.LeaderboardScope{Metric: metric, WeekStart: time.Now(), FleetID: &fleetID}
+	}
+
+	board, err := service.GetLeaderboard(ctx, scope(entities.LeaderboardMetricTrips), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 4, board.Participants)
+	require.Len(t, board.Entries, 3)
+	assert.Equal(t, "Анна Р.", board.Entries[0].DisplayName)
+	// Равенство по поездкам разрешается средней оценкой
+	assert.Equal(t, second.ID, *board.Entries[1].DriverID)
+	assert.Equal(t, 2, board.Entries[1].Rank)
+	assert.Equal(t, tied.ID, *board.Entries[2].DriverID)
+	assert.Equal(t, 3, board.Entries[2].Rank)
+
+	// Водитель с одной оценкой не участвует в рейтинге по оценкам
+	_, err = service.GetDriverRank(ctx, single.ID, scope(entities.LeaderboardMetricRating))
+	assert.Equal(t, entities.ErrDriverNotRanked, err)
+
+	rank, err := service.GetDriverRank(ctx, second.ID, scope(entities.LeaderboardMetricTrips))
+	require.NoError(t, err)
+	assert.Equal(t, 2, rank.Rank)
+	assert.Equal(t, 10.0, rank.GapToNext)
+
+	_, err = service.GetLeaderboard(ctx, scope(entities.LeaderboardMetricEarnings), 10)
+	assert.Equal(t, entities.ErrInvalidLeaderboardMetric, err)
+
+	// Псевдоним скрывает имя и ID, отказ от участия применяется без ожидания обновления кэша
+	require.NoError(t, service.SetVisibility(ctx, leader.ID, entities.LeaderboardVisibilityPseudonymous))
+	require.NoError(t, service.SetVisibility(ctx, tied.ID, entities.LeaderboardVisibilityHidden))
+
+	board, err = service.GetLeaderboard(ctx, scope(entities.LeaderboardMetricTrips), 10)
+	require.NoError(t, err)
+	assert.Equal(t, 3, board.Participants)
+	assert.Nil(t, board.Entries[0].DriverID)
+	assert.Equal(t, entities.LeaderboardPseudonym(leader.ID), board.Entries[0].DisplayName)
+
+	_, err = service.GetDriverRank(ctx, tied.ID, scope(entities.LeaderboardMetricTrips))
+	assert.Equal(t, entities.ErrLeaderboardOptedOut, err)
+
+	assert.Equal(t, entities.ErrInvalidLeaderboardVisibility, service.SetVisibility(ctx, leader.ID, "invisible"))
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+
+	"go.uber.org/zap"
+)
+
+// LocationAnalyticsStore аналитическое хранилище копий принятых местоположений. Запросы
+// по всему потоку точек, слишком тяжелые для основной базы, выполняются в нем
+type LocationAnalyticsStore interface {
+	// DistanceByCity считает пробег водителей по городам и суткам (UTC)
+	DistanceByCity(ctx context.Context, query *entities.CityDistanceQuery) ([]*entities.CityDistance, error)
+}
+
+// LocationBroadcasters рассылает сохраненное местоположение нескольким получателям,
+// например WebSocket подписчикам и аналитическому хранилищу
+type LocationBroadcasters []LocationBroadcaster
+
+// BroadcastLocation передает местоположение каждому получателю
+func (b LocationBroadcasters) BroadcastLocation(location *entities.DriverLocation) {
+	for _, broadcaster := range b {
+		broadcaster.BroadcastLocation(location)
+	}
+}
+
+// LocationAnalyticsService интерфейс для аналитики потока местоположений
+type LocationAnalyticsService interface {
+	// DistanceByCity возвращает пробег водителей по городам и суткам за период query
+	DistanceByCity(ctx context.Context, query *entities.CityDistanceQuery) (*entities.CityDistanceReport, error)
+}
+
+// locationAnalyticsService реализация LocationAnalyticsService
+type locationAnalyticsService struct {
+	store  LocationAnalyticsStore
+	logger *zap.Logger
+}
+
+// NewLocationAnalyticsService создает новый LocationAnalyticsService. store может быть nil,
+// если аналитическое хранилище не настроено: запросы тогда возвращают
+// ErrLocationAnalyticsDisabled
+func NewLocationAnalyticsService(store LocationAnalyticsStore, logger *zap.Logger) LocationAnalyticsService {
+	return &locationAnalyticsService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// DistanceByCity проверяет период и читает пробег из аналитического хранилища
+func (s *locationAnalyticsService) DistanceByCity(ctx context.Context, query *entities.CityDistanceQuery) (*entities.CityDistanceReport, error) {
+	if s.store == nil {
+		return nil, entities.ErrLocationAnalyticsDisabled
+	}
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	normalized := *query
+	normalized.From = query.From.UTC()
+	normalized.To = query.To.UTC()
+
+	items, err := s.store.DistanceByCity(ctx, &normalized)
+	if err != nil {
+		s.logger.Error("Failed to query distance by city",
+			zap.Error(err),
+			zap.Time("from", normalized.From),
+			zap.Time("to", normalized.To),
+		)
+		return nil, err
+	}
+	if items == nil {
+		items = []*entities.CityDistance{}
+	}
+
+	return &entities.CityDistanceReport{
+		From:  normalized.From,
+		To:    normalized.To,
+		Items: items,
+	}, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingAnalyticsStore запоминает последний запрос и возвращает заданный пробег
+type recordingAnalyticsStore struct {
+	query *entities.CityDistanceQuery
+	items []*entities.CityDistance
+}
+
+func (s *recordingAnalyticsStore) DistanceByCity(ctx context.Context, query *entities.CityDistanceQuery) ([]*entities.CityDistance, error) {
+	s.query = query
+	return s.items, nil
+}
+
+// countingBroadcaster считает разосланные точки
+type countingBroadcaster struct {
+	count int
+}
+
+func (b *countingBroadcaster) BroadcastLocation(location *entities.DriverLocation) {
+	b.count++
+}
+
+func TestLocationAnalyticsService_DistanceByCity(t *testing.T) {
+	ctx := context.Background()
+	moscow := time.FixedZone("MSK", 3*60*60)
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	store := &recordingAnalyticsStore{items: []*entities.CityDistance{
+		{City: "moscow", Day: day, DistanceKm: 120.5, Drivers: 3, Points: 900},
+	}}
+	service := NewLocationAnalyticsService(store, zap.NewNop())
+
+	report, err := service.DistanceByCity(ctx, &entities.CityDistanceQuery{
+		From: time.Date(2026, 10, 15, 3, 0, 0, 0, moscow),
+		To:   time.Date(2026, 10, 16, 3, 0, 0, 0, moscow),
+		City: "moscow",
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Items, 1)
+	assert.Equal(t, 120.5, report.Items[0].DistanceKm)
+
+	// Сутки считаются в UTC: период переводится в UTC до запроса
+	assert.Equal(t, day, store.query.From)
+	assert.Equal(t, day.AddDate(0, 0, 1), store.query.To)
+	assert.Equal(t, "moscow", store.query.City)
+
+	// Пустой результат отдается пустым списком
+	store.items = nil
+	report, err = service.DistanceByCity(ctx, &entities.CityDistanceQuery{From: day, To: day.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.NotNil(t, report.Items)
+	assert.Empty(t, report.Items)
+}
+
+func TestLocationAnalyticsService_InvalidRange(t *testing.T) {
+	ctx := context.Background()
+	service := NewLocationAnalyticsService(&recordingAnalyticsStore{}, zap.NewNop())
+	now := time.Now()
+
+	ranges := []*entities.CityDistanceQuery{
+		{From: now, To: now},
+		{From: now, To: now.Add(-time.Hour)},
+		{To: now},
+		{From: now.Add(-entities.MaxAnalyticsRange - time.Hour), To: now},
+	}
+	for _, query := range ranges {
+		_, err := service.DistanceByCity(ctx, query)
+		assert.ErrorIs(t, err, entities.ErrInvalidAnalyticsRange)
+	}
+}
+
+func TestLocationAnalyticsService_Disabled(t *testing.T) {
+	service := NewLocationAnalyticsService(nil, zap.NewNop())
+
+	_, err := service.DistanceByCity(context.Background(), &entities.CityDistanceQuery{
+		From: time.Now().Add(-time.Hour),
+		To:   time.Now(),
+	})
+	assert.ErrorIs(t, err, entities.ErrLocationAnalyticsDisabled)
+}
+
+func TestLocationBroadcasters(t *testing.T) {
+	first, second := &countingBroadcaster{}, &countingBroadcaster{}
+	broadcasters := LocationBroadcasters{first, second}
+
+	broadcasters.BroadcastLocation(&entities.DriverLocation{})
+
+	assert.Equal(t, 1, first.count)
+	assert.Equal(t, 1, second.count)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"driver-service/internal/domain/entities"
+)
+
+// locationBatchStats счетчики пакетной записи местоположений
+type locationBatchStats struct {
+	since      time.Time
+	batches    atomic.Int64
+	received   atomic.Int64
+	accepted   atomic.Int64
+	duplicates atomic.Int64
+	dropped    atomic.Int64
+	rejected   atomic.Int64
+
+	mu     sync.Mutex
+	byCode map[string]int64
+}
+
+// newLocationBatchStats создает счетчики, отсчитываемые с текущего момента
+func newLocationBatchStats() *locationBatchStats {
+	return &locationBatchStats{
+		since:  time.Now(),
+		byCode: make(map[string]int64),
+	}
+}
+
+// record учитывает итог сохранения пакета
+func (s *locationBatchStats) record(result *entities.LocationBatchResult) {
+	s.batches.Add(1)
+	s.received.Add(int64(result.Received))
+	s.accepted.Add(int64(result.Accepted))
+	s.duplicates.Add(int64(result.Duplicates))
+	s.dropped.Add(int64(result.Dropped))
+	if len(result.Rejected) == 0 {
+		return
+	}
+	s.rejected.Add(int64(len(result.Rejected)))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, rejection := range result.Rejected {
+		s.byCode[rejection.Code]++
+	}
+}
+
+// snapshot возвращает текущие значения счетчиков
+func (s *locationBatchStats) snapshot() entities.LocationBatchStats {
+	stats := entities.LocationBatchStats{
+		Batches:        s.batches.Load(),
+		Received:       s.received.Load(),
+		Accepted:       s.accepted.Load(),
+		Duplicates:     s.duplicates.Load(),
+		Dropped:        s.dropped.Load(),
+		Rejected:       s.rejected.Load(),
+		RejectedByCode: make(map[string]int64),
+		Since:          s.since,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for code, count := range s.byCode {
+		stats.RejectedByCode[code] = count
+	}
+	return stats
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// locationExportFailureReason причина, которую видит запросивший; сама ошибка только логируется
+	locationExportFailureReason = "location export failed"
+	// locationExportStaleReason причина для выгрузок, задания которых потеряны при перезапуске
+	locationExportStaleReason = "location export was interrupted"
+	// locationExportCleanupBatch сколько просроченных выгрузок удаляется за один запрос к репозиторию
+	locationExportCleanupBatch = 100
+)
+
+// LocationExportPolicy параметры выгрузки истории местоположений
+type LocationExportPolicy struct {
+	// Workers число параллельно формируемых файлов
+	Workers int
+	// QueueSize сколько выгрузок может ждать в очереди; запросы сверх очереди отклоняются
+	QueueSize int
+	// Timeout ограничение времени формирования одного файла
+	Timeout time.Duration
+	// MaxRange наибольший период одной выгрузки; 0 — не ограничен
+	MaxRange time.Duration
+	// ChunkInterval период истории, читаемый из репозитория за один запрос, чтобы
+	// не держать в памяти всю историю водителя
+	ChunkInterval time.Duration
+	// Retention сколько хранятся файл и запись о выгрузке после ее завершения
+	Retention time.Duration
+	// URLTTL время действия ссылки на скачивание файла
+	URLTTL time.Duration
+	// StaleAfter выгрузки, не завершенные за это время после запроса, считаются потерянными
+	StaleAfter time.Duration
+}
+
+// LocationExportService асинхронная выгрузка истории местоположений водителя в файл
+type LocationExportService interface {
+	// RequestExport ставит выгрузку в очередь. precise — получит ли запросивший точные
+	// координаты; без него точки в файле огрубляются
+	RequestExport(ctx context.Context, driverID uuid.UUID, req *entities.LocationExportRequest, precise bool) (*entities.LocationExport, error)
+	// GetExport возвращает состояние выгрузки водителя и ссылку на файл готовой выгрузки.
+	// Выгрузку с точными координатами видят только те, кому они доступны
+	GetExport(ctx context.Context, driverID, exportID uuid.UUID, precise bool) (*entities.LocationExport, error)
+	// DownloadURL возвращает временную ссылку на файл готовой выгрузки
+	DownloadURL(ctx context.Context, driverID, exportID uuid.UUID, precise bool) (string, error)
+	// CleanupExpired удаляет файлы и записи выгрузок с истекшим сроком хранения и завершает
+	// ошибкой потерянные выгрузки. Возвращает число удаленных выгрузок
+	CleanupExpired(ctx context.Context) (int, error)
+	// Drain перестает принимать выгрузки и дожидается формирования очереди. Если ctx
+	// истек раньше, возвращает число выгрузок, оставшихся в очереди
+	Drain(ctx context.Context) (int, error)
+}
+
+// locationExportService реализация LocationExportService
+type locationExportService struct {
+	exportRepo   repositories.LocationExportRepository
+	driverRepo   repositories.DriverRepository
+	locationRepo repositories.LocationRepository
+	storage      SignedFileStorage
+	policy       LocationExportPolicy
+	logger       *zap.Logger
+
+	// mu защищает stopped: после остановки выгрузки в очередь не принимаются
+	mu      sync.RWMutex
+	stopped bool
+	jobs    chan *entities.LocationExport
+	wg      sync.WaitGroup
+}
+
+// NewLocationExportService создает LocationExportService и запускает обработчики очереди
+func NewLocationExportService(
+	exportRepo repositories.LocationExportRepository,
+	driverRepo repositories.DriverRepository,
+	locationRepo repositories.LocationRepository,
+	storage SignedFileStorage,
+	policy LocationExportPolicy,
+	logger *zap.Logger,
+) LocationExportService {
+	if policy.Workers <= 0 {
+		policy.Workers = 1
+	}
+	if policy.QueueSize < 0 {
+		policy.QueueSize = 0
+	}
+	if policy.ChunkInterval <= 0 {
+		policy.ChunkInterval = 6 * time.Hour
+	}
+
+	s := &locationExportService{
+		exportRepo:   exportRepo,
+		driverRepo:   driverRepo,
+		locationRepo: locationRepo,
+		storage:      storage,
+		policy:       policy,
+		logger:       logger,
+		jobs:         make(chan *entities.LocationExport, policy.QueueSize),
+	}
+	for i := 0; i < policy.Workers; i++ {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return s
+}
+
+// RequestExport проверяет запрос, сохраняет выгрузку и ставит ее в очередь
+func (s *locationExportService) RequestExport(ctx context.Context, driverID uuid.UUID, req *entities.LocationExportRequest, precise bool) (*entities.LocationExport, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	requestedBy := ""
+	if actor, ok := entities.AuditActorFromContext(ctx); ok {
+		requestedBy = actor.Subject
+	}
+
+	export := entities.NewLocationExport(driverID, req, precise, requestedBy, time.Now())
+	if err := export.Validate(s.policy.MaxRange); err != nil {
+		return nil, err
+	}
+
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	// Обработчик меняет свою копию, возвращаемая выгрузка остается в состоянии pending
+	job := *export
+	if !s.submit(&job) {
+		// Запись без задания зависла бы в очереди до очистки
+		if err := s.exportRepo.Delete(ctx, export.ID); err != nil {
+			s.logger.Error("Failed to delete rejected location export",
+				zap.Error(err),
+				zap.String("export_id", export.ID.String()),
+			)
+		}
+		return nil, entities.ErrLocationExportQueueFull
+	}
+
+	s.logger.Info("Location export requested",
+		zap.String("export_id", export.ID.String()),
+		zap.String("driver_id", driverID.String()),
+		zap.Time("from", export.From),
+		zap.Time("to", export.To),
+		zap.Bool("precise", export.Precise),
+	)
+	return export, nil
+}
+
+// GetExport получает выгрузку водителя
+func (s *locationExportService) GetExport(ctx context.Context, driverID, exportID uuid.UUID, precise bool) (*entities.LocationExport, error) {
+	export, err := s.getExport(ctx, driverID, exportID, precise)
+	if err != nil {
+		return nil, err
+	}
+
+	if now := time.Now(); export.IsDownloadable(now) {
+		expiresAt := s.urlExpiresAt(export, now)
+		url, err := s.storage.SignURL(*export.FileURL, expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign export URL: %w", err)
+		}
+		export.DownloadURL = &url
+		export.DownloadURLExpiresAt = &expiresAt
+	}
+	return export, nil
+}
+
+// DownloadURL возвращает временную ссылку на файл выгрузки
+func (s *locationExportService) DownloadURL(ctx context.Context, driverID, exportID uuid.UUID, precise bool) (string, error) {
+	export, err := s.getExport(ctx, driverID, exportID, precise)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if export.Status != entities.LocationExportCompleted {
+		return "", entities.ErrLocationExportNotReady
+	}
+	if !export.IsDownloadable(now) {
+		return "", entities.ErrLocationExportExpired
+	}
+
+	url, err := s.storage.SignURL(*export.FileURL, s.urlExpiresAt(export, now))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign export URL: %w", err)
+	}
+	return url, nil
+}
+
+// getExport получает выгрузку и проверяет, что она принадлежит водителю и доступна вызывающему
+func (s *locationExportService) getExport(ctx context.Context, driverID, exportID uuid.UUID, precise bool) (*entities.LocationExport, error) {
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+	if export.DriverID != driverID {
+		return nil, entities.ErrLocationExportNotFound
+	}
+	if export.Precise && !precise {
+		return nil, entities.ErrPermissionDenied
+	}
+	return export, nil
+}
+
+// urlExpiresAt срок действия ссылки: URLTTL, но не дольше хранения файла
+func (s *locationExportService) urlExpiresAt(export *entities.LocationExport, now time.Time) time.Time {
+	expiresAt := now.Add(s.policy.URLTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(expiresAt) {
+		expiresAt = *export.ExpiresAt
+	}
+	return expiresAt
+}
+
+// CleanupExpired удаляет просроченные выгрузки пачками
+func (s *locationExportService) CleanupExpired(ctx context.Context) (int, error) {
+	now := time.Now()
+	if s.policy.StaleAfter > 0 {
+		failed, err := s.exportRepo.FailStale(ctx, now.Add(-s.policy.StaleAfter), locationExportStaleReason, now, s.policy.Retention)
+		if err != nil {
+			return 0, err
+		}
+		if failed > 0 {
+			s.logger.Warn("Stale location exports failed", zap.Int64("count", failed))
+		}
+	}
+
+	deleted := 0
+	for {
+		exports, err := s.exportRepo.ListExpired(ctx, now, locationExportCleanupBatch)
+		if err != nil {
+			return deleted, err
+		}
+
+		removed := 0
+		for _, export := range exports {
+			if export.FileURL != nil {
+				if err := s.storage.Delete(ctx, *export.FileURL); err != nil {
+					// Запись остается, чтобы файл удалился при следующем запуске
+					s.logger.Error("Failed to delete location export file",
+						zap.Error(err),
+						zap.String("export_id", export.ID.String()),
+					)
+					continue
+				}
+			}
+			if err := s.exportRepo.Delete(ctx, export.ID); err != nil {
+				return deleted, err
+			}
+			removed++
+		}
+		deleted += removed
+
+		// Пачка без удалений повторилась бы бесконечно
+		if len(exports) < locationExportCleanupBatch || removed == 0 {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		s.logger.Info("Expired location exports deleted", zap.Int("count", deleted))
+	}
+	return deleted, nil
+}
+
+// Drain дожидается формирования выгрузок из очереди
+func (s *locationExportService) Drain(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.jobs)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		return len(s.jobs), ctx.Err()
+	}
+}
+
+// submit ставит выгрузку в очередь; false, если очередь заполнена или остановлена
+func (s *locationExportService) submit(export *entities.LocationExport) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return false
+	}
+
+	select {
+	case s.jobs <- export:
+		return true
+	default:
+		s.logger.Warn("Location export queue is full",
+			zap.String("export_id", export.ID.String()),
+		)
+		return false
+	}
+}
+
+// run формирует выгрузки из очереди до ее закрытия
+func (s *locationExportService) run() {
+	defer s.wg.Done()
+
+	for export := range s.jobs {
+		s.process(export)
+	}
+}
+
+// process формирует файл выгрузки и сохраняет ее результат
+func (s *locationExportService) process(export *entities.LocationExport) {
+	ctx := context.Background()
+	if s.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.policy.Timeout)
+		defer cancel()
+	}
+
+	export.Start(time.Now())
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		s.logger.Error("Failed to start location export",
+			zap.Error(err),
+			zap.String("export_id", export.ID.String()),
+		)
+		return
+	}
+
+	key := fmt.Sprintf("drivers/%s/locations/%s.%s", export.DriverID, export.ID, export.Format)
+	fileURL, rows, size, err := s.writeFile(ctx, export, key)
+	if err != nil {
+		s.logger.Error("Failed to export locations",
+			zap.Error(err),
+			zap.String("export_id", export.ID.String()),
+			zap.String("driver_id", export.DriverID.String()),
+		)
+		export.Fail(locationExportFailureReason, time.Now(), s.policy.Retention)
+	} else {
+		export.Complete(fileURL, rows, size, time.Now(), s.policy.Retention)
+	}
+
+	// Результат сохраняется и после истечения Timeout
+	if err := s.exportRepo.Update(context.Background(), export); err != nil {
+		s.logger.Error("Failed to save location export result",
+			zap.Error(err),
+			zap.String("export_id", export.ID.String()),
+		)
+		return
+	}
+
+	s.logger.Info("Location export finished",
+		zap.String("export_id", export.ID.String()),
+		zap.String("status", string(export.Status)),
+		zap.Int64("rows", export.Rows),
+		zap.Int64("size_bytes", export.SizeBytes),
+	)
+}
+
+// writeFile передает CSV в хранилище по мере чтения истории, не собирая файл в памяти
+func (s *locationExportService) writeFile(ctx context.Context, export *entities.LocationExport, key string) (string, int64, int64, error) {
+	reader, writer := io.Pipe()
+	counter := &countingWriter{w: writer}
+
+	type result struct {
+		rows int64
+		err  error
+	}
+	written := make(chan result, 1)
+	go func() {
+		rows, err := s.writeCSV(ctx, export, counter)
+		writer.CloseWithError(err)
+		written <- result{rows: rows, err: err}
+	}()
+
+	fileURL, err := s.storage.Save(ctx, key, "text/csv", reader)
+	// Хранилище могло остановить чтение раньше конца файла
+	reader.CloseWithError(errors.New("storage stopped reading export"))
+	res := <-written
+	if res.err != nil {
+		if err == nil {
+			// Хранилище получило неполный файл
+			_ = s.storage.Delete(context.Background(), fileURL)
+		}
+		return "", 0, 0, res.err
+	}
+	if err != nil {
+		return "", 0, 0, err
+	}
+	return fileURL, res.rows, counter.n, nil
+}
+
+// writeCSV пишет историю водителя за [From, To) окнами ChunkInterval и возвращает число строк
+func (s *locationExportService) writeCSV(ctx context.Context, export *entities.LocationExport, w io.Writer) (int64, error) {
+	out := csv.NewWriter(w)
+	if err := out.Write(entities.LocationCSVHeader); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	for start := export.From; start.Before(export.To); start = start.Add(s.policy.ChunkInterval) {
+		end := start.Add(s.policy.ChunkInterval)
+		if end.After(export.To) {
+			end = export.To
+		}
+
+		locations, err := s.locationRepo.GetByDriverIDInTimeRange(ctx, export.DriverID, start, end)
+		if err != nil {
+			return rows, fmt.Errorf("failed to read locations: %w", err)
+		}
+		for _, location := range locations {
+			// Границы окна включаются репозиторием; точка на конце окна попадет в следующее
+			if !location.RecordedAt.Before(end) {
+				continue
+			}
+			if !export.Precise {
+				location.Blur()
+			}
+			if err := out.Write(location.CSVRecord()); err != nil {
+				return rows, err
+			}
+			rows++
+		}
+
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return rows, err
+		}
+	}
+
+	return rows, nil
+}
+
+// countingWriter считает записанные байты
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
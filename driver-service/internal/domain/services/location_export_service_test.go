@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeSignedStorage добавляет к fakeFileStorage подпись ссылок
+type fakeSignedStorage struct {
+	*fakeFileStorage
+}
+
+func (s *fakeSignedStorage) SignURL(url string, expiresAt time.Time) (string, error) {
+	return url + "?expires=" + expiresAt.UTC().Format(time.RFC3339), nil
+}
+
+func TestLocationExportService_Export(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	files := &fakeSignedStorage{&fakeFileStorage{files: make(map[string][]byte)}}
+	service := NewLocationExportService(memory.NewLocationExportRepository(), driverRepo, locationRepo, files,
+		LocationExportPolicy{Workers: 1, QueueSize: 10, MaxRange: 48 * time.Hour, ChunkInterval: time.Hour,
+			Retention: time.Hour, URLTTL: 15 * time.Minute}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000911", "export@example.com", "Иван", "Выгрузкин", "LIC911")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	from := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+	// Точка на границе окон выгружается один раз, точка на конце периода не выгружается
+	for _, at := range []time.Time{from, from.Add(30 * time.Minute), from.Add(time.Hour), from.Add(150 * time.Minute), to} {
+		saveLocation(t, locationRepo, entities.NewDriverLocation(driver.ID, 55.751244, 37.618423, at))
+	}
+
+	_, err := service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: to, To: from}, false)
+	assert.ErrorIs(t, err, entities.ErrInvalidLocationExport)
+	_, err = service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: from, To: from.Add(72 * time.Hour)}, false)
+	assert.ErrorIs(t, err, entities.ErrInvalidLocationExport)
+	_, err = service.RequestExport(ctx, uuid.New(), &entities.LocationExportRequest{From: from, To: to}, false)
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+
+	coarse, err := service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: from, To: to}, false)
+	require.NoError(t, err)
+	assert.Equal(t, entities.LocationExportPending, coarse.Status)
+	assert.Equal(t, entities.LocationExportCSV, coarse.Format)
+	precise, err := service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: from, To: to}, true)
+	require.NoError(t, err)
+
+	dropped, err := service.Drain(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+
+	export, err := service.GetExport(ctx, driver.ID, coarse.ID, false)
+	require.NoError(t, err)
+	require.Equal(t, entities.LocationExportCompleted, export.Status)
+	assert.EqualValues(t, 4, export.Rows)
+	require.NotNil(t, export.DownloadURL)
+	assert.Contains(t, *export.DownloadURL, "?expires=")
+
+	content := files.files["drivers/"+driver.ID.String()+"/locations/"+coarse.ID.String()+".csv"]
+	assert.EqualValues(t, len(content), export.SizeBytes)
+	records, err := csv.NewReader(strings.NewReader(string(content))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	assert.Equal(t, entities.LocationCSVHeader, records[0])
+	assert.Equal(t, "2024-03-01T10:00:00Z", records[1][0])
+	assert.Equal(t, "2024-03-01T11:00:00Z", records[3][0])
+	// Без доступа к точным координатам точки огрублены
+	assert.Equal(t, "55.75", records[1][1])
+
+	// Выгрузку с точными координатами не видит тот, кому они недоступны
+	_, err = service.GetExport(ctx, driver.ID, precise.ID, false)
+	assert.ErrorIs(t, err, entities.ErrPermissionDenied)
+	url, err := service.DownloadURL(ctx, driver.ID, precise.ID, true)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "https://receipts.example.com/drivers/"))
+
+	// Выгрузка другого водителя не находится
+	_, err = service.GetExport(ctx, uuid.New(), coarse.ID, true)
+	assert.ErrorIs(t, err, entities.ErrLocationExportNotFound)
+
+	// После остановки выгрузки не принимаются
+	_, err = service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: from, To: to}, false)
+	assert.ErrorIs(t, err, entities.ErrLocationExportQueueFull)
+}
+
+func TestLocationExportService_CleanupExpired(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	files := &fakeSignedStorage{&fakeFileStorage{files: make(map[string][]byte)}}
+	service := NewLocationExportService(memory.NewLocationExportRepository(), driverRepo, locationRepo, files,
+		LocationExportPolicy{Workers: 1, QueueSize: 10, ChunkInterval: time.Hour, URLTTL: time.Minute}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000912", "cleanup@example.com", "Иван", "Чистый", "LIC912")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+	now := time.Now()
+	saveLocation(t, locationRepo, entities.NewDriverLocation(driver.ID, 55.75, 37.61, now.Add(-time.Minute)))
+
+	// Без срока хранения файл просрочен сразу после формирования
+	export, err := service.RequestExport(ctx, driver.ID, &entities.LocationExportRequest{From: now.Add(-time.Hour), To: now}, true)
+	require.NoError(t, err)
+	_, err = service.Drain(ctx)
+	require.NoError(t, err)
+	require.Len(t, files.files, 1)
+
+	_, err = service.DownloadURL(ctx, driver.ID, export.ID, true)
+	assert.ErrorIs(t, err, entities.ErrLocationExportExpired)
+
+	deleted, err := service.CleanupExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Empty(t, files.files)
+	_, err = service.GetExport(ctx, driver.ID, export.ID, true)
+	assert.ErrorIs(t, err, entities.ErrLocationExportNotFound)
+}
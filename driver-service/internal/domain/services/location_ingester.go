@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+)
+
+// errIngestionStopped асинхронная запись остановлена, точка сохраняется синхронно
+var errIngestionStopped = errors.New("location ingestion stopped")
+
+// LocationIngestionPolicy параметры асинхронной записи местоположений
+type LocationIngestionPolicy struct {
+	// Async включает асинхронный режим: UpdateLocation ставит точку в буфер и возвращается,
+	// не дожидаясь записи в базу
+	Async bool
+	// BufferSize сколько точек может ждать записи; при заполненном буфере
+	// UpdateLocation возвращает ErrLocationIngestionOverloaded
+	BufferSize int
+	// Workers число обработчиков. Точки одного водителя попадают к одному обработчику,
+	// поэтому сохраняются и проверяются по геозонам в порядке поступления
+	Workers int
+	// BatchSize и FlushInterval: обработчик записывает пакет, когда набрано BatchSize точек
+	// или прошел FlushInterval
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+// locationRing кольцевой буфер точек фиксированного размера
+type locationRing struct {
+	items []*entities.DriverLocation
+	head  int
+	size  int
+}
+
+// push добавляет точку в конец буфера; false, если буфер заполнен
+func (r *locationRing) push(location *entities.DriverLocation) bool {
+	if r.size == len(r.items) {
+		return false
+	}
+	r.items[(r.head+r.size)%len(r.items)] = location
+	r.size++
+	return true
+}
+
+// pop извлекает до max точек из начала буфера
+func (r *locationRing) pop(max int) []*entities.DriverLocation {
+	if max > r.size {
+		max = r.size
+	}
+	batch := make([]*entities.DriverLocation, max)
+	for i := range batch {
+		batch[i] = r.items[r.head]
+		r.items[r.head] = nil
+		r.head = (r.head + 1) % len(r.items)
+	}
+	r.size -= max
+	return batch
+}
+
+// ingestWorker обработчик со своей частью буфера
+type ingestWorker struct {
+	mu   sync.Mutex
+	ring locationRing
+	// ready сигнал, что набран полный пакет
+	ready chan struct{}
+}
+
+// locationIngester буферизует точки и записывает их пакетами в обработчиках
+type locationIngester struct {
+	policy  LocationIngestionPolicy
+	workers []*ingestWorker
+	save    func(batch []*entities.DriverLocation)
+
+	// mu защищает stopped: после остановки точки в буфер не принимаются
+	mu      sync.RWMutex
+	stopped bool
+	stop    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newLocationIngester создает буфер и запускает обработчики; save записывает пакет
+func newLocationIngester(policy LocationIngestionPolicy, save func(batch []*entities.DriverLocation)) *locationIngester {
+	if policy.Workers <= 0 {
+		policy.Workers = 1
+	}
+	capacity := policy.BufferSize / policy.Workers
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	ingester := &locationIngester{
+		policy:  policy,
+		workers: make([]*ingestWorker, policy.Workers),
+		save:    save,
+		stop:    make(chan struct{}),
+	}
+	for i := range ingester.workers {
+		worker := &ingestWorker{
+			ring:  locationRing{items: make([]*entities.DriverLocation, capacity)},
+			ready: make(chan struct{}, 1),
+		}
+		ingester.workers[i] = worker
+		ingester.wg.Add(1)
+		go ingester.run(worker)
+	}
+	return ingester
+}
+
+// enqueue ставит точку в буфер обработчика ее водителя
+func (i *locationIngester) enqueue(location *entities.DriverLocation) error {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	if i.stopped {
+		return errIngestionStopped
+	}
+
+	id := location.DriverID
+	worker := i.workers[binary.BigEndian.Uint64(id[8:])%uint64(len(i.workers))]
+
+	worker.mu.Lock()
+	ok := worker.ring.push(location)
+	full := worker.ring.size >= i.policy.BatchSize
+	worker.mu.Unlock()
+
+	if !ok {
+		return entities.ErrLocationIngestionOverloaded
+	}
+	if full {
+		select {
+		case worker.ready <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// run записывает полные пакеты по сигналу, неполные — раз в FlushInterval, а при остановке
+// дописывает буфер целиком
+func (i *locationIngester) run(worker *ingestWorker) {
+	defer i.wg.Done()
+
+	ticker := time.NewTicker(i.policy.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-worker.ready:
+			i.flush(worker, false)
+		case <-ticker.C:
+			i.flush(worker, true)
+		case <-i.stop:
+			i.flush(worker, true)
+			return
+		}
+	}
+}
+
+// flush записывает пакеты из буфера обработчика; partial разрешает неполный пакет
+func (i *locationIngester) flush(worker *ingestWorker, partial bool) {
+	for {
+		worker.mu.Lock()
+		if worker.ring.size == 0 || (!partial && worker.ring.size < i.policy.BatchSize) {
+			worker.mu.Unlock()
+			return
+		}
+		batch := worker.ring.pop(i.policy.BatchSize)
+		worker.mu.Unlock()
+
+		i.save(batch)
+	}
+}
+
+// drain перестает принимать точки и дожидается записи буфера. Если ctx истек раньше,
+// оставшиеся в буфере точки отбрасываются и возвращается их количество
+func (i *locationIngester) drain(ctx context.Context) (int, error) {
+	i.mu.Lock()
+	if !i.stopped {
+		i.stopped = true
+		close(i.stop)
+	}
+	i.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		i.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		return i.discard(), ctx.Err()
+	}
+}
+
+// discard очищает буферы обработчиков и возвращает число отброшенных точек.
+// Пакет, который обработчик уже записывает, не отбрасывается
+func (i *locationIngester) discard() int {
+	dropped := 0
+	for _, worker := range i.workers {
+		worker.mu.Lock()
+		dropped += len(worker.ring.pop(worker.ring.size))
+		worker.mu.Unlock()
+	}
+	return dropped
+}
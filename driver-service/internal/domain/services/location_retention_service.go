@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxSummaryPoints максимальное число интервалов в запрашиваемом периоде сводных точек
+const maxSummaryPoints = 10000
+
+// LocationRetentionService интерфейс для хранения истории местоположений по уровням
+type LocationRetentionService interface {
+	// ApplyRetention прореживает исходные точки старше срока хранения в сводные точки уровней,
+	// удаляет исходные точки и сводные точки, срок хранения которых истек
+	ApplyRetention(ctx context.Context) (*entities.LocationRetentionResult, error)
+	// GetSummaries возвращает сводные точки водителя уровня tier за период
+	GetSummaries(ctx context.Context, driverID uuid.UUID, tier string, from, to time.Time) ([]*entities.LocationSummary, error)
+	// EnsurePartitions создает месячные секции истории местоположений с текущего месяца
+	// на PartitionsAhead месяцев вперед и возвращает число созданных
+	EnsurePartitions(ctx context.Context) (int, error)
+}
+
+// LocationRetentionPolicy параметры хранения истории местоположений
+type LocationRetentionPolicy struct {
+	// RawRetention сколько хранятся исходные точки
+	RawRetention time.Duration
+	// Tiers уровни сводных точек; без уровней исходные точки просто удаляются
+	Tiers []entities.LocationRetentionTier
+	// PartitionsAhead на сколько месяцев вперед создаются секции (задача location_partitions)
+	PartitionsAhead int
+}
+
+// locationRetentionService реализация LocationRetentionService
+type locationRetentionService struct {
+	locationRepo repositories.LocationRepository
+	summaryRepo  repositories.LocationSummaryRepository
+	driverRepo   repositories.DriverRepository
+	policy       LocationRetentionPolicy
+	logger       *zap.Logger
+}
+
+// NewLocationRetentionService создает новый LocationRetentionService
+func NewLocationRetentionService(
+	locationRepo repositories.LocationRepository,
+	summaryRepo repositories.LocationSummaryRepository,
+	driverRepo repositories.DriverRepository,
+	policy LocationRetentionPolicy,
+	logger *zap.Logger,
+) LocationRetentionService {
+	return &locationRetentionService{
+		locationRepo: locationRepo,
+		summaryRepo:  summaryRepo,
+		driverRepo:   driverRepo,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// ApplyRetention применяет политику хранения. Прореживаются только целые сутки UTC:
+// интервалы уровней делят сутки без остатка и не пересекают границу обрабатываемых суток
+func (s *locationRetentionService) ApplyRetention(ctx context.Context) (*entities.LocationRetentionResult, error) {
+	now := time.Now()
+	result := &entities.LocationRetentionResult{
+		Cutoff: now.Add(-s.policy.RawRetention).UTC().Truncate(24 * time.Hour),
+	}
+
+	if len(s.policy.Tiers) > 0 {
+		if err := s.downsample(ctx, result); err != nil {
+			return result, err
+		}
+	}
+	if err := s.locationRepo.DeleteOld(ctx, result.Cutoff); err != nil {
+		return result, fmt.Errorf("failed to delete old locations: %w", err)
+	}
+
+	for _, tier := range s.policy.Tiers {
+		if tier.Keep <= 0 {
+			continue
+		}
+		expired, err := s.summaryRepo.DeleteOld(ctx, tier.Name, now.Add(-tier.Keep))
+		if err != nil {
+			return result, err
+		}
+		result.SummariesExpired += expired
+	}
+
+	s.logger.Info("Location retention applied",
+		zap.Time("cutoff", result.Cutoff),
+		zap.Int("days", result.Days),
+		zap.Int("drivers", result.Drivers),
+		zap.Int("points_summarized", result.PointsSummarized),
+		zap.Int("summaries_written", result.SummariesWritten),
+		zap.Int("summaries_expired", result.SummariesExpired),
+	)
+
+	return result, nil
+}
+
+// downsample прореживает исходные точки по суткам, начиная с самых старых. Сутки удаляются
+// сразу после сохранения их сводных точек: после сбоя повторный запуск пересчитает
+// только незавершенные сутки, а повторно сохраненные сводные точки заменят прежние
+func (s *locationRetentionService) downsample(ctx context.Context, result *entities.LocationRetentionResult) error {
+	drivers := make(map[uuid.UUID]bool)
+	defer func() { result.Drivers = len(drivers) }()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		oldest, err := s.locationRepo.GetOldestRecordedAt(ctx)
+		if err == entities.ErrLocationNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		dayStart := oldest.UTC().Truncate(24 * time.Hour)
+		if !dayStart.Before(result.Cutoff) {
+			return nil
+		}
+		dayEnd := dayStart.Add(24 * time.Hour)
+
+		if err := s.downsampleDay(ctx, dayStart, dayEnd, result, drivers); err != nil {
+			return fmt.Errorf("failed to downsample locations of %s: %w", dayStart.Format(time.DateOnly), err)
+		}
+		if err := s.locationRepo.DeleteOld(ctx, dayEnd); err != nil {
+			return fmt.Errorf("failed to delete downsampled locations: %w", err)
+		}
+		result.Days++
+	}
+}
+
+// downsampleDay сохраняет сводные точки всех уровней для водителей с точками за сутки
+func (s *locationRetentionService) downsampleDay(ctx context.Context, dayStart, dayEnd time.Time, result *entities.LocationRetentionResult, drivers map[uuid.UUID]bool) error {
+	driverIDs, err := s.locationRepo.ListDriverIDsInTimeRange(ctx, dayStart, dayEnd)
+	if err != nil {
+		return err
+	}
+
+	for _, driverID := range driverIDs {
+		locations, err := s.locationRepo.GetByDriverIDInTimeRange(ctx, driverID, dayStart, dayEnd)
+		if err == entities.ErrDriverNotFound {
+			// Водитель удален: его точки удаляются вместе с сутками без прореживания
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		// Граница периода входит в выборку, но относится к следующим суткам
+		for len(locations) > 0 && !locations[len(locations)-1].RecordedAt.Before(dayEnd) {
+			locations = locations[:len(locations)-1]
+		}
+		if len(locations) == 0 {
+			continue
+		}
+
+		var summaries []*entities.LocationSummary
+		for _, tier := range s.policy.Tiers {
+			summaries = append(summaries, entities.DownsampleLocations(locations, tier)...)
+		}
+		if err := s.summaryRepo.Upsert(ctx, summaries); err != nil {
+			return err
+		}
+
+		drivers[driverID] = true
+		result.PointsSummarized += len(locations)
+		result.SummariesWritten += len(summaries)
+	}
+
+	return nil
+}
+
+// EnsurePartitions создает секции заранее, чтобы точки не попадали в секцию по умолчанию.
+// Секции старше срока хранения удаляет ApplyRetention после прореживания их точек
+func (s *locationRetentionService) EnsurePartitions(ctx context.Context) (int, error) {
+	now := time.Now()
+	created, err := s.locationRepo.EnsurePartitions(ctx, now, now.AddDate(0, s.policy.PartitionsAhead, 0))
+	if err != nil {
+		return created, fmt.Errorf("failed to create location partitions: %w", err)
+	}
+
+	s.logger.Info("Location partitions ensured",
+		zap.Int("created", created),
+		zap.Int("months_ahead", s.policy.PartitionsAhead),
+	)
+	return created, nil
+}
+
+// GetSummaries получает сводные точки существующего водителя
+func (s *locationRetentionService) GetSummaries(ctx context.Context, driverID uuid.UUID, tierName string, from, to time.Time) ([]*entities.LocationSummary, error) {
+	var tier *entities.LocationRetentionTier
+	for i := range s.policy.Tiers {
+		if s.policy.Tiers[i].Name == tierName {
+			tier = &s.policy.Tiers[i]
+			break
+		}
+	}
+	if tier == nil {
+		return nil, entities.ErrRetentionTierNotFound
+	}
+	if !to.After(from) || to.Sub(from)/tier.Interval > maxSummaryPoints {
+		return nil, entities.ErrInvalidSummaryRange
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return s.summaryRepo.GetByDriverIDInTimeRange(ctx, driverID, tier.Name, from, to)
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLocationRetentionService_ApplyRetention(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	summaryRepo := memory.NewLocationSummaryRepository()
+	service := NewLocationRetentionService(locationRepo, summaryRepo, driverRepo, LocationRetentionPolicy{
+		RawRetention: 30 * 24 * time.Hour,
+		Tiers: []entities.LocationRetentionTier{
+			{Name: "5m", Interval: 5 * time.Minute, Keep: 365 * 24 * time.Hour},
+			{Name: "1h", Interval: time.Hour},
+		},
+	}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000105", "retention@example.com", "Иван", "Хранение", "LICR")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	day := time.Now().AddDate(0, 0, -40).UTC().Truncate(24 * time.Hour).Add(10 * time.Hour)
+	batch := []*entities.DriverLocation{
+		entities.NewDriverLocation(driver.ID, 55.750, 37.61, day),
+		entities.NewDriverLocation(driver.ID, 55.751, 37.61, day.Add(1*time.Minute)),
+		entities.NewDriverLocation(driver.ID, 55.752, 37.61, day.Add(6*time.Minute)),
+		entities.NewDriverLocation(driver.ID, 55.760, 37.62, day.AddDate(0, 0, 5)),
+		entities.NewDriverLocation(driver.ID, 55.770, 37.63, time.Now().Add(-time.Hour)),
+	}
+	_, err := locationRepo.CreateBatch(ctx, batch)
+	require.NoError(t, err)
+
+	expired := &entities.LocationSummary{DriverID: driver.ID, Tier: "5m", BucketStart: day.AddDate(-1, 0, -1), PointCount: 1}
+	forever := &entities.LocationSummary{DriverID: driver.ID, Tier: "1h", BucketStart: day.AddDate(-3, 0, 0), PointCount: 1}
+	require.NoError(t, summaryRepo.Upsert(ctx, []*entities.LocationSummary{expired, forever}))
+
+	result, err := service.ApplyRetention(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Days)
+	assert.Equal(t, 1, result.Drivers)
+	assert.Equal(t, 4, result.PointsSummarized)
+	// 5m: два интервала первых суток и один вторых; 1h: по одному на сутки
+	assert.Equal(t, 5, result.SummariesWritten)
+	assert.Equal(t, 1, result.SummariesExpired)
+
+	remaining, err := locationRepo.List(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, batch[4].ID, remaining[0].ID)
+
+	stored, err := summaryRepo.ListByDriverID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored, 6)
+
+	summaries, err := service.GetSummaries(ctx, driver.ID, "5m", day.Add(-time.Hour), day.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, 2, summaries[0].PointCount)
+	assert.Equal(t, 55.751, summaries[0].Latitude)
+
+	// Повторный запуск ничего не меняет
+	result, err = service.ApplyRetention(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, result.Days)
+	assert.Zero(t, result.SummariesWritten)
+}
+
+func TestLocationRetentionService_WithoutTiersOnlyDeletes(t *testing.T) {
+	ctx := context.Background()
+	locationRepo := memory.NewLocationRepository()
+	summaryRepo := memory.NewLocationSummaryRepository()
+	service := NewLocationRetentionService(locationRepo, summaryRepo, memory.NewDriverRepository(),
+		LocationRetentionPolicy{RawRetention: 30 * 24 * time.Hour}, zap.NewNop())
+
+	driverID := uuid.New()
+	_, err := locationRepo.CreateBatch(ctx, []*entities.DriverLocation{
+		entities.NewDriverLocation(driverID, 55.75, 37.61, time.Now().AddDate(0, 0, -40)),
+		entities.NewDriverLocation(driverID, 55.76, 37.62, time.Now()),
+	})
+	require.NoError(t, err)
+
+	result, err := service.ApplyRetention(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, result.SummariesWritten)
+
+	remaining, err := locationRepo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+	stored, err := summaryRepo.ListByDriverID(ctx, driverID)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestLocationRetentionService_GetSummariesValidation(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	service := NewLocationRetentionService(memory.NewLocationRepository(), memory.NewLocationSummaryRepository(), driverRepo,
+		LocationRetentionPolicy{
+			RawRetention: 30 * 24 * time.Hour,
+			Tiers:        []entities.LocationRetentionTier{{Name: "5m", Interval: 5 * time.Minute}},
+		}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000106", "summaries@example.com", "Иван", "Сводка", "LICS")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+	now := time.Now()
+
+	_, err := service.GetSummaries(ctx, driver.ID, "1d", now.Add(-time.Hour), now)
+	assert.Equal(t, entities.ErrRetentionTierNotFound, err)
+
+	_, err = service.GetSummaries(ctx, driver.ID, "5m", now, now.Add(-time.Hour))
+	assert.Equal(t, entities.ErrInvalidSummaryRange, err)
+
+	// 5 лет по 5 минут — больше допустимого числа интервалов
+	_, err = service.GetSummaries(ctx, driver.ID, "5m", now.AddDate(-5, 0, 0), now)
+	assert.Equal(t, entities.ErrInvalidSummaryRange, err)
+
+	_, err = service.GetSummaries(ctx, uuid.New(), "5m", now.Add(-time.Hour), now)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	summaries, err := service.GetSummaries(ctx, driver.ID, "5m", now.AddDate(0, 0, -30), now)
+	require.NoError(t, err)
+	assert.Empty(t, summaries)
+}
@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"driver-service/internal/domain/entities"
@@ -17,36 +19,97 @@ type LocationService interface {
 	UpdateLocation(ctx context.Context, location *entities.DriverLocation) error
 	GetCurrentLocation(ctx context.Context, driverID uuid.UUID) (*entities.DriverLocation, error)
 	GetLocationHistory(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverLocation, error)
+	// SearchLocationHistory получает историю водителя filters.DriverID за период From–To
+	// с фильтрами по точности, скорости, области и заказу
+	SearchLocationHistory(ctx context.Context, filters *entities.LocationFilters) ([]*entities.DriverLocation, error)
 	GetLocationStats(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.LocationStats, error)
 	StreamLocations(ctx context.Context, driverID uuid.UUID) (<-chan *entities.DriverLocation, error)
 	StartOrderTracking(ctx context.Context, driverID, orderID uuid.UUID) error
 	StopOrderTracking(ctx context.Context, driverID, orderID uuid.UUID) error
-	GetNearbyDrivers(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error)
-	BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) error
+	GetNearbyDrivers(ctx context.Context, lat, lon, radiusKm float64, limit int, tags []string) ([]*entities.DriverLocation, error)
+	// BatchUpdateLocations сохраняет пакет точек и сообщает, какие точки отклонены
+	BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) (*entities.LocationBatchResult, error)
+	// BatchStats возвращает счетчики пакетной записи: принятые, повторные и отклоненные точки
+	BatchStats() entities.LocationBatchStats
 	CleanupOldLocations(ctx context.Context) error
+	// SetPrivacyMode изменяет режим приватности местоположения водителя вне смены
+	SetPrivacyMode(ctx context.Context, driverID uuid.UUID, mode entities.LocationPrivacyMode) error
+	// Drain дописывает точки из буфера асинхронной записи; после него UpdateLocation
+	// сохраняет точки синхронно. Возвращает число точек, не записанных до истечения ctx
+	Drain(ctx context.Context) (int, error)
 }
 
+// LocationBroadcaster рассылает сохраненные местоположения подписчикам в реальном времени.
+// Реализация не должна блокировать вызывающего.
+type LocationBroadcaster interface {
+	BroadcastLocation(location *entities.DriverLocation)
+}
+
+// LocationPolicy параметры обработки истории местоположений
+type LocationPolicy struct {
+	// MaxGapInterval интервал между точками, после которого трек считается разорванным;
+	// 0 отключает поиск разрывов
+	MaxGapInterval time.Duration
+	// Ingestion асинхронная запись точек из UpdateLocation
+	Ingestion LocationIngestionPolicy
+}
+
+// queuedSaveTimeout ограничение времени записи пакета из буфера
+const queuedSaveTimeout = 10 * time.Second
+
 // locationService реализация LocationService
 type locationService struct {
 	locationRepo repositories.LocationRepository
 	driverRepo   repositories.DriverRepository
+	scheduleRepo repositories.ScheduleRepository
 	eventBus     EventPublisher
+	broadcaster  LocationBroadcaster
+	// watchers подписки StreamLocations на местоположения отдельных водителей
+	watchers     *locationWatchers
+	geofences    GeofenceEvaluator
+	cities       CityLocator
+	policy       LocationPolicy
 	logger       *zap.Logger
+	// ingester буфер асинхронной записи; nil в синхронном режиме
+	ingester *locationIngester
+	// batchStats счетчики пакетной записи
+	batchStats *locationBatchStats
 }
 
-// NewLocationService создает новый LocationService
+// NewLocationService создает новый LocationService.
+// broadcaster может быть nil, если рассылка в реальном времени не нужна,
+// scheduleRepo — если расписания доступности не учитываются при поиске,
+// geofences — если точки не проверяются по геозонам,
+// cities — если точки и водители не относятся к городам по границам.
+// При policy.Ingestion.Async запускаются обработчики буфера; их останавливает Drain.
 func NewLocationService(
 	locationRepo repositories.LocationRepository,
 	driverRepo repositories.DriverRepository,
+	scheduleRepo repositories.ScheduleRepository,
 	eventBus EventPublisher,
+	broadcaster LocationBroadcaster,
+	geofences GeofenceEvaluator,
+	cities CityLocator,
+	policy LocationPolicy,
 	logger *zap.Logger,
 ) LocationService {
-	return &locationService{
+	s := &locationService{
 		locationRepo: locationRepo,
 		driverRepo:   driverRepo,
+		scheduleRepo: scheduleRepo,
 		eventBus:     eventBus,
+		broadcaster:  broadcaster,
+		watchers:     newLocationWatchers(),
+		geofences:    geofences,
+		cities:       cities,
+		policy:       policy,
 		logger:       logger,
+		batchStats:   newLocationBatchStats(),
+	}
+	if policy.Ingestion.Async {
+		s.ingester = newLocationIngester(policy.Ingestion, s.saveQueued)
 	}
+	return s
 }
 
 // UpdateLocation обновляет местоположение водителя
@@ -66,8 +129,17 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		return fmt.Errorf("location validation failed: %w", err)
 	}
 
+	if err := location.NormalizeMetadata(); err != nil {
+		s.logger.Warn("Location metadata rejected",
+			zap.Error(err),
+			zap.String("driver_id", location.DriverID.String()),
+		)
+		return err
+	}
+	stampLocationDevice(ctx, location)
+
 	// Проверяем, существует ли водитель
-	_, err := s.driverRepo.GetByID(ctx, location.DriverID)
+	driver, err := s.driverRepo.GetByID(ctx, location.DriverID)
 	if err != nil {
 		s.logger.Error("Driver not found for location update",
 			zap.Error(err),
@@ -75,6 +147,15 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		)
 		return err
 	}
+	location.ShardKey = driver.ShardKey
+
+	if !applyLocationPrivacy(driver, location) {
+		s.logger.Debug("Off-shift location dropped by driver privacy mode",
+			zap.String("driver_id", location.DriverID.String()),
+		)
+		return nil
+	}
+	s.locateCity(ctx, driver, location)
 
 	// Устанавливаем ID и время создания
 	if location.ID == uuid.Nil {
@@ -87,8 +168,16 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		location.RecordedAt = time.Now()
 	}
 
+	// В асинхронном режиме точку записывает обработчик буфера; после Drain — синхронно
+	if s.ingester != nil {
+		if err := s.ingester.enqueue(location); err != errIngestionStopped {
+			return err
+		}
+	}
+
 	// Сохраняем местоположение в базе данных
-	if err := s.locationRepo.Create(ctx, location); err != nil {
+	saved, err := s.locationRepo.Create(ctx, location)
+	if err != nil {
 		s.logger.Error("Failed to save location",
 			zap.Error(err),
 			zap.String("driver_id", location.DriverID.String()),
@@ -96,6 +185,22 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		return fmt.Errorf("failed to save location: %w", err)
 	}
 
+	// Повтор уже сохраненной точки не публикуется и не проверяется по геозонам повторно
+	if !saved {
+		s.logger.Debug("Duplicate location skipped",
+			zap.String("driver_id", location.DriverID.String()),
+			zap.Time("recorded_at", location.RecordedAt),
+		)
+		return nil
+	}
+
+	s.afterSave(ctx, location)
+	return nil
+}
+
+// afterSave публикует событие о сохраненной точке, рассылает ее подписчикам и проверяет
+// по геозонам
+func (s *locationService) afterSave(ctx context.Context, location *entities.DriverLocation) {
 	// Публикуем событие об обновлении местоположения
 	eventData := map[string]interface{}{
 		"location": location.ToLocation(),
@@ -104,7 +209,7 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		"accuracy": location.GetAccuracy(),
 	}
 
-	if err := s.eventBus.PublishDriverEvent(ctx, "driver.location.updated", location.DriverID, eventData); err != nil {
+	if err := s.eventBus.PublishDriverEvent(ctx, eventLocationUpdated, location.DriverID, eventData); err != nil {
 		s.logger.Error("Failed to publish location updated event",
 			zap.Error(err),
 			zap.String("driver_id", location.DriverID.String()),
@@ -112,7 +217,36 @@ func (s *locationService) UpdateLocation(ctx context.Context, location *entities
 		// Не возвращаем ошибку, так как местоположение уже сохранено
 	}
 
-	return nil
+	s.broadcast(location)
+	s.evaluateGeofences(ctx, location)
+}
+
+// saveQueued записывает пакет точек из буфера асинхронной записи. Клиенту уже ответили,
+// поэтому ошибка записи только логируется
+func (s *locationService) saveQueued(batch []*entities.DriverLocation) {
+	ctx, cancel := context.WithTimeout(context.Background(), queuedSaveTimeout)
+	defer cancel()
+
+	saved, err := s.locationRepo.CreateBatch(ctx, batch)
+	if err != nil {
+		s.logger.Error("Failed to save queued locations",
+			zap.Error(err),
+			zap.Int("count", len(batch)),
+		)
+		return
+	}
+
+	for _, location := range saved {
+		s.afterSave(ctx, location)
+	}
+}
+
+// Drain дописывает точки из буфера асинхронной записи
+func (s *locationService) Drain(ctx context.Context) (int, error) {
+	if s.ingester == nil {
+		return 0, nil
+	}
+	return s.ingester.drain(ctx)
 }
 
 // GetCurrentLocation получает текущее местоположение водителя
@@ -158,18 +292,53 @@ func (s *locationService) GetLocationHistory(ctx context.Context, driverID uuid.
 	return locations, nil
 }
 
-// GetLocationStats вычисляет статистику по местоположениям
+// SearchLocationHistory получает историю местоположений водителя в хронологическом порядке.
+// Фильтры по точкам выполняются в базе; без них запрос совпадает с GetLocationHistory
+func (s *locationService) SearchLocationHistory(ctx context.Context, filters *entities.LocationFilters) ([]*entities.DriverLocation, error) {
+	if filters.DriverID == nil || filters.From == nil || filters.To == nil {
+		return nil, entities.ErrInvalidLocationFilters
+	}
+	if !filters.HasPointFilters() {
+		return s.GetLocationHistory(ctx, *filters.DriverID, *filters.From, *filters.To)
+	}
+	if filters.To.Before(*filters.From) {
+		return nil, fmt.Errorf("invalid time range: 'to' time is before 'from' time")
+	}
+	if err := filters.Validate(); err != nil {
+		return nil, err
+	}
+
+	query := *filters
+	query.Limit, query.Offset = 0, 0
+	locations, err := s.locationRepo.List(ctx, &query)
+	if err != nil {
+		s.logger.Error("Failed to search location history",
+			zap.Error(err),
+			zap.String("driver_id", filters.DriverID.String()),
+		)
+		return nil, err
+	}
+
+	// Список возвращается от новых точек к старым
+	for i, j := 0, len(locations)-1; i < j; i, j = i+1, j-1 {
+		locations[i], locations[j] = locations[j], locations[i]
+	}
+	return locations, nil
+}
+
+// GetLocationStats вычисляет статистику по местоположениям с учетом разрывов трека
 func (s *locationService) GetLocationStats(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.LocationStats, error) {
 	locations, err := s.GetLocationHistory(ctx, driverID, from, to)
 	if err != nil {
 		return nil, err
 	}
 
-	stats := entities.CalculateLocationStats(locations)
+	stats := entities.CalculateLocationStats(locations, s.policy.MaxGapInterval)
 	return stats, nil
 }
 
-// StreamLocations создает поток для получения местоположений водителя в реальном времени
+// StreamLocations передает текущее местоположение водителя, а затем каждую сохраненную точку
+// до отмены ctx. Канал закрывается после отмены
 func (s *locationService) StreamLocations(ctx context.Context, driverID uuid.UUID) (<-chan *entities.DriverLocation, error) {
 	// Проверяем, существует ли водитель
 	_, err := s.driverRepo.GetByID(ctx, driverID)
@@ -177,18 +346,15 @@ func (s *locationService) StreamLocations(ctx context.Context, driverID uuid.UUI
 		return nil, err
 	}
 
-	// Создаем канал для передачи местоположений
-	locationChan := make(chan *entities.DriverLocation, 100)
+	// Подписываемся до чтения текущего местоположения, чтобы не пропустить точки между ними
+	updates, cancel := s.watchers.subscribe(driverID)
+	locationChan := make(chan *entities.DriverLocation, locationWatcherBuffer)
 
-	// В реальном приложении здесь должна быть реализация
-	// подписки на обновления через Redis/WebSocket/NATS
-	// Для демонстрации создаем заглушку
 	go func() {
 		defer close(locationChan)
-		
-		// Получаем последнее местоположение
-		location, err := s.GetCurrentLocation(ctx, driverID)
-		if err == nil {
+		defer cancel()
+
+		if location, err := s.GetCurrentLocation(ctx, driverID); err == nil {
 			select {
 			case locationChan <- location:
 			case <-ctx.Done():
@@ -196,8 +362,18 @@ func (s *locationService) StreamLocations(ctx context.Context, driverID uuid.UUI
 			}
 		}
 
-		// Ждем отмены контекста
-		<-ctx.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case location := <-updates:
+				select {
+				case locationChan <- location:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
 	}()
 
 	return locationChan, nil
@@ -224,14 +400,15 @@ func (s *locationService) StartOrderTracking(ctx context.Context, driverID, orde
 	if location.Metadata == nil {
 		location.Metadata = make(entities.Metadata)
 	}
-	location.Metadata["on_trip"] = true
-	location.Metadata["order_id"] = orderID.String()
+	location.Metadata[entities.LocationMetaOnTrip] = true
+	location.Metadata[entities.LocationMetaOrderID] = orderID.String()
+	location.Metadata[entities.LocationMetaSource] = entities.LocationSourceTracking
 
 	// Обновляем местоположение с информацией о заказе
 	location.ID = uuid.New() // Создаем новую запись
 	location.CreatedAt = time.Now()
 	
-	if err := s.locationRepo.Create(ctx, location); err != nil {
+	if _, err := s.locationRepo.Create(ctx, location); err != nil {
 		s.logger.Error("Failed to create tracking location record",
 			zap.Error(err),
 			zap.String("driver_id", driverID.String()),
@@ -263,15 +440,16 @@ func (s *locationService) StopOrderTracking(ctx context.Context, driverID, order
 	if location.Metadata == nil {
 		location.Metadata = make(entities.Metadata)
 	}
-	location.Metadata["on_trip"] = false
-	delete(location.Metadata, "order_id")
+	location.Metadata[entities.LocationMetaOnTrip] = false
+	location.Metadata[entities.LocationMetaSource] = entities.LocationSourceTracking
+	delete(location.Metadata, entities.LocationMetaOrderID)
 
 	// Создаем новую запись о завершении отслеживания
 	location.ID = uuid.New()
 	location.CreatedAt = time.Now()
 	location.RecordedAt = time.Now()
 
-	if err := s.locationRepo.Create(ctx, location); err != nil {
+	if _, err := s.locationRepo.Create(ctx, location); err != nil {
 		s.logger.Error("Failed to create stop tracking location record",
 			zap.Error(err),
 			zap.String("driver_id", driverID.String()),
@@ -282,8 +460,13 @@ func (s *locationService) StopOrderTracking(ctx context.Context, driverID, order
 	return nil
 }
 
-// GetNearbyDrivers получает водителей поблизости от указанной точки
-func (s *locationService) GetNearbyDrivers(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error) {
+// GetNearbyDrivers получает водителей поблизости от указанной точки; с tags — только
+// водителей, у которых есть все эти метки.
+//
+// Водители и местоположения могут храниться в разных базах (шарды), поэтому фильтры по
+// водителю применяются к выбранным точкам одним запросом на страницу. Если после фильтров
+// водителей меньше limit, выборка точек расширяется, пока они не закончатся в радиусе
+func (s *locationService) GetNearbyDrivers(ctx context.Context, lat, lon, radiusKm float64, limit int, tags []string) ([]*entities.DriverLocation, error) {
 	if radiusKm <= 0 {
 		return nil, fmt.Errorf("radius must be positive")
 	}
@@ -292,37 +475,94 @@ func (s *locationService) GetNearbyDrivers(ctx context.Context, lat, lon, radius
 		limit = 50 // Значение по умолчанию
 	}
 
-	locations, err := s.locationRepo.GetNearby(ctx, lat, lon, radiusKm, limit)
+	var activeDriverLocations []*entities.DriverLocation
+	seen := make(map[uuid.UUID]bool)
+	for fetch := limit; ; fetch *= 2 {
+		locations, err := s.locationRepo.GetNearby(ctx, lat, lon, radiusKm, fetch)
+		if err != nil {
+			s.logger.Error("Failed to get nearby drivers",
+				zap.Error(err),
+				zap.Float64("latitude", lat),
+				zap.Float64("longitude", lon),
+				zap.Float64("radius_km", radiusKm),
+			)
+			return nil, err
+		}
+
+		// Точки предыдущих страниц уже проверены
+		var unchecked []*entities.DriverLocation
+		for _, location := range locations {
+			if !seen[location.DriverID] {
+				seen[location.DriverID] = true
+				unchecked = append(unchecked, location)
+			}
+		}
+		matched, err := s.filterNearbyDrivers(ctx, unchecked, tags)
+		if err != nil {
+			return nil, err
+		}
+		activeDriverLocations = append(activeDriverLocations, matched...)
+
+		if len(activeDriverLocations) >= limit || len(locations) < fetch {
+			break
+		}
+	}
+	if len(activeDriverLocations) > limit {
+		activeDriverLocations = activeDriverLocations[:limit]
+	}
+
+	return activeDriverLocations, nil
+}
+
+// filterNearbyDrivers оставляет точки активных водителей без блокировки от биллинга, у которых
+// есть все метки tags, в окне их расписания; водители читаются одним запросом
+func (s *locationService) filterNearbyDrivers(ctx context.Context, locations []*entities.DriverLocation, tags []string) ([]*entities.DriverLocation, error) {
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uuid.UUID, len(locations))
+	for i, location := range locations {
+		ids[i] = location.DriverID
+	}
+	noHold := false
+	drivers, err := s.driverRepo.List(ctx, &entities.DriverFilters{
+		IDs:         ids,
+		Status:      []entities.Status{entities.StatusAvailable, entities.StatusOnShift, entities.StatusBusy},
+		PaymentHold: &noHold,
+		Tags:        tags,
+	})
 	if err != nil {
-		s.logger.Error("Failed to get nearby drivers",
-			zap.Error(err),
-			zap.Float64("latitude", lat),
-			zap.Float64("longitude", lon),
-			zap.Float64("radius_km", radiusKm),
-		)
+		s.logger.Error("Failed to get nearby drivers", zap.Error(err), zap.Int("count", len(ids)))
 		return nil, err
 	}
 
-	// Фильтруем только активных водителей
-	var activeDriverLocations []*entities.DriverLocation
+	active := make(map[uuid.UUID]bool, len(drivers))
+	for _, driver := range drivers {
+		active[driver.ID] = true
+	}
+	now := time.Now()
+	matched := make([]*entities.DriverLocation, 0, len(drivers))
 	for _, location := range locations {
-		driver, err := s.driverRepo.GetByID(ctx, location.DriverID)
-		if err != nil {
+		if !active[location.DriverID] {
 			continue
 		}
-
-		if driver.IsActive() {
-			activeDriverLocations = append(activeDriverLocations, location)
+		if available, err := withinSchedule(ctx, s.scheduleRepo, location.DriverID, now); err != nil || !available {
+			continue
 		}
+		matched = append(matched, location)
 	}
-
-	return activeDriverLocations, nil
+	return matched, nil
 }
 
-// BatchUpdateLocations обновляет множество местоположений за один запрос
-func (s *locationService) BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) error {
+// BatchUpdateLocations обновляет множество местоположений за один запрос. Точки с ошибкой
+// в данных или неизвестным водителем отклоняются по отдельности и не прерывают пакет;
+// повторы точек (водитель и время записи) пропускаются. Ошибка возвращается, только если
+// пакет не удалось сохранить
+func (s *locationService) BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) (*entities.LocationBatchResult, error) {
+	result := entities.NewLocationBatchResult(len(locations))
 	if len(locations) == 0 {
-		return nil
+		return result, nil
 	}
 
 	s.logger.Info("Batch updating locations",
@@ -331,15 +571,31 @@ func (s *locationService) BatchUpdateLocations(ctx context.Context, locations []
 
 	// Валидация всех местоположений
 	now := time.Now()
-	for _, location := range locations {
+	drivers := make(map[uuid.UUID]*entities.Driver)
+	seen := make(map[entities.LocationPointKey]bool, len(locations))
+	accepted := make([]*entities.DriverLocation, 0, len(locations))
+	for i, location := range locations {
 		if err := location.Validate(); err != nil {
-			s.logger.Error("Location validation failed in batch",
+			s.logger.Warn("Location validation failed in batch",
 				zap.Error(err),
 				zap.String("driver_id", location.DriverID.String()),
+				zap.Int("index", i),
 			)
-			return fmt.Errorf("location validation failed: %w", err)
+			result.Reject(i, err)
+			continue
 		}
 
+		if err := location.NormalizeMetadata(); err != nil {
+			s.logger.Warn("Location metadata rejected in batch",
+				zap.Error(err),
+				zap.String("driver_id", location.DriverID.String()),
+				zap.Int("index", i),
+			)
+			result.Reject(i, err)
+			continue
+		}
+		stampLocationDevice(ctx, location)
+
 		// Устанавливаем значения по умолчанию
 		if location.ID == uuid.Nil {
 			location.ID = uuid.New()
@@ -347,25 +603,91 @@ func (s *locationService) BatchUpdateLocations(ctx context.Context, locations []
 		if location.CreatedAt.IsZero() {
 			location.CreatedAt = now
 		}
-		if location.RecordedAt.IsZero() {
-			location.RecordedAt = now
+
+		// Ключ шарда берется у водителя: по нему пакет распределяется между шардами.
+		// Отсутствие водителя запоминается, чтобы не искать его для каждой точки
+		driver, ok := drivers[location.DriverID]
+		if !ok {
+			var err error
+			driver, err = s.driverRepo.GetByID(ctx, location.DriverID)
+			if err != nil && !errors.Is(err, entities.ErrDriverNotFound) {
+				s.logger.Error("Failed to get driver for batch location update",
+					zap.Error(err),
+					zap.String("driver_id", location.DriverID.String()),
+				)
+				return nil, err
+			}
+			drivers[location.DriverID] = driver
+		}
+		if driver == nil {
+			result.Reject(i, entities.ErrDriverNotFound)
+			continue
+		}
+		location.ShardKey = driver.ShardKey
+
+		key := location.PointKey()
+		if seen[key] {
+			result.Duplicates++
+			continue
+		}
+		seen[key] = true
+
+		if applyLocationPrivacy(driver, location) {
+			s.locateCity(ctx, driver, location)
+			accepted = append(accepted, location)
+		} else {
+			result.Dropped++
 		}
 	}
 
-	// Сохраняем все местоположения
-	if err := s.locationRepo.CreateBatch(ctx, locations); err != nil {
-		s.logger.Error("Failed to batch update locations",
-			zap.Error(err),
-			zap.Int("count", len(locations)),
+	if result.Dropped > 0 {
+		s.logger.Debug("Off-shift locations dropped by driver privacy mode",
+			zap.Int("dropped", result.Dropped),
 		)
-		return fmt.Errorf("failed to batch update locations: %w", err)
 	}
+	if len(accepted) > 0 {
+		// Сохраняем все местоположения; уже сохраненные ранее репозиторий пропускает
+		saved, err := s.locationRepo.CreateBatch(ctx, accepted)
+		if err != nil {
+			s.logger.Error("Failed to batch update locations",
+				zap.Error(err),
+				zap.Int("count", len(accepted)),
+			)
+			return nil, fmt.Errorf("failed to batch update locations: %w", err)
+		}
+		result.Accepted = len(saved)
+		result.Duplicates += len(accepted) - len(saved)
 
-	s.logger.Info("Batch location update completed successfully",
-		zap.Int("count", len(locations)),
+		for _, location := range saved {
+			s.broadcast(location)
+		}
+
+		// Геозоны проверяются в порядке записи точек, чтобы вход предшествовал выходу
+		if s.geofences != nil {
+			ordered := append([]*entities.DriverLocation(nil), saved...)
+			sort.SliceStable(ordered, func(i, j int) bool {
+				return ordered[i].RecordedAt.Before(ordered[j].RecordedAt)
+			})
+			for _, location := range ordered {
+				s.evaluateGeofences(ctx, location)
+			}
+		}
+	}
+	s.batchStats.record(result)
+
+	s.logger.Info("Batch location update completed",
+		zap.Int("received", result.Received),
+		zap.Int("accepted", result.Accepted),
+		zap.Int("duplicates", result.Duplicates),
+		zap.Int("rejected", len(result.Rejected)),
 	)
 
-	return nil
+	return result, nil
+}
+
+// BatchStats возвращает счетчики пакетной записи местоположений
+func (s *locationService) BatchStats() entities.LocationBatchStats {
+	return s.batchStats.snapshot()
 }
 
 // CleanupOldLocations удаляет старые данные о местоположении
@@ -386,4 +708,93 @@ func (s *locationService) CleanupOldLocations(ctx context.Context) error {
 
 	s.logger.Info("Location cleanup completed")
 	return nil
-}
\ No newline at end of file
+}
+
+// SetPrivacyMode сохраняет режим приватности в метаданных водителя. Режим применяется
+// к точкам, принятым после изменения; сохраненная история не меняется
+func (s *locationService) SetPrivacyMode(ctx context.Context, driverID uuid.UUID, mode entities.LocationPrivacyMode) error {
+	if !mode.IsValid() {
+		return entities.ErrInvalidLocationPrivacy
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	if driver.Metadata == nil {
+		driver.Metadata = make(entities.Metadata)
+	}
+	driver.Metadata[entities.DriverMetaLocationPrivacy] = string(mode)
+	driver.UpdatedAt = time.Now()
+
+	if err := s.driverRepo.Update(ctx, driver); err != nil {
+		return fmt.Errorf("failed to update location privacy mode: %w", err)
+	}
+
+	s.logger.Info("Location privacy mode changed",
+		zap.String("driver_id", driverID.String()),
+		zap.String("mode", string(mode)),
+	)
+	return nil
+}
+
+// applyLocationPrivacy применяет режим приватности водителя к точке вне смены: огрубляет
+// ее или сообщает, что точку сохранять нельзя (false). Точки в смене не меняются
+func applyLocationPrivacy(driver *entities.Driver, location *entities.DriverLocation) bool {
+	if driver.IsOnShift() {
+		return true
+	}
+
+	switch driver.LocationPrivacy() {
+	case entities.LocationPrivacyDrop:
+		return false
+	case entities.LocationPrivacyBlur:
+		location.Blur()
+	}
+	return true
+}
+
+// locateCity относит точку и водителя к городу, если города настроены. Ошибка не
+// возвращается: точка без города сохраняется, водитель остается в прежнем городе
+func (s *locationService) locateCity(ctx context.Context, driver *entities.Driver, location *entities.DriverLocation) {
+	if s.cities == nil {
+		return
+	}
+	if err := s.cities.LocateDriver(ctx, driver, location); err != nil {
+		s.logger.Error("Failed to locate driver city",
+			zap.Error(err),
+			zap.String("driver_id", location.DriverID.String()),
+		)
+	}
+}
+
+// evaluateGeofences проверяет сохраненную точку по геозонам, если они настроены.
+// Ошибка не возвращается: местоположение уже сохранено
+func (s *locationService) evaluateGeofences(ctx context.Context, location *entities.DriverLocation) {
+	if s.geofences == nil {
+		return
+	}
+	if err := s.geofences.EvaluateLocation(ctx, location); err != nil {
+		s.logger.Error("Failed to evaluate geofences",
+			zap.Error(err),
+			zap.String("driver_id", location.DriverID.String()),
+		)
+	}
+}
+
+// broadcast передает местоположение потокам StreamLocations и подписчикам, если рассылка настроена
+func (s *locationService) broadcast(location *entities.DriverLocation) {
+	s.watchers.BroadcastLocation(location)
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastLocation(location)
+	}
+}
+
+// stampLocationDevice записывает в метаданные точки устройство автора запроса. Запросы
+// без автора (внутренние источники) сохраняют устройство из метаданных клиента
+func stampLocationDevice(ctx context.Context, location *entities.DriverLocation) {
+	if actor, ok := entities.AuditActorFromContext(ctx); ok {
+		location.SetDevice(actor.DeviceID)
+	}
+}
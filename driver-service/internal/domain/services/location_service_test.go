@@ -0,0 +1,412 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLocationService_UpdateAndNearby(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	events := &recordingEventPublisher{}
+	service := NewLocationService(locationRepo, driverRepo, nil, events, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	active := entities.NewDriver("+79000000101", "a@example.com", "Иван", "Активный", "LICA")
+	active.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, active))
+
+	inactive := entities.NewDriver("+79000000102", "b@example.com", "Петр", "Неактивный", "LICB")
+	inactive.Status = entities.StatusInactive
+	require.NoError(t, driverRepo.Create(ctx, inactive))
+
+	now := time.Now()
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(active.ID, 55.7558, 37.6173, now)))
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(inactive.ID, 55.7560, 37.6175, now)))
+	assert.True(t, events.has("driver.location.updated"))
+
+	current, err := service.GetCurrentLocation(ctx, active.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 55.7558, current.Latitude)
+
+	nearby, err := service.GetNearbyDrivers(ctx, 55.7558, 37.6173, 1, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, active.ID, nearby[0].DriverID)
+}
+
+func TestLocationService_NearbyFillsLimitAfterFilters(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	service := NewLocationService(memory.NewLocationRepository(), driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	addDriver := func(suffix string, latitude float64, tags entities.DriverTagList, hold bool) *entities.Driver {
+		driver := entities.NewDriver("+7900000011"+suffix, "", "Иван", "Рядом", "LICN"+suffix)
+		driver.Status = entities.StatusAvailable
+		driver.Tags = tags
+		driver.PaymentHold = hold
+		require.NoError(t, driverRepo.Create(ctx, driver))
+		require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, latitude, 37.6173, time.Now())))
+		return driver
+	}
+	// Ближайшие водители без метки или с блокировкой выплат, водитель с меткой дальше всех
+	for i := 0; i < 3; i++ {
+		addDriver(string(rune('0'+i)), 55.7558+float64(i)*0.001, nil, false)
+	}
+	addDriver("3", 55.7590, entities.DriverTagList{"pet-friendly"}, true)
+	tagged := addDriver("4", 55.7600, entities.DriverTagList{"pet-friendly"}, false)
+
+	nearby, err := service.GetNearbyDrivers(ctx, 55.7558, 37.6173, 5, 1, []string{"pet-friendly"})
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, tagged.ID, nearby[0].DriverID)
+
+	nearby, err = service.GetNearbyDrivers(ctx, 55.7558, 37.6173, 5, 2, []string{"pet-friendly", "vip-capable"})
+	require.NoError(t, err)
+	assert.Empty(t, nearby)
+}
+
+func TestLocationService_RecordsDevice(t *testing.T) {
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000103", "c@example.com", "Иван", "Устройство", "LICC")
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	// Устройство запроса заменяет присланное клиентом
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: driver.ID.String(), DeviceID: "phone-1"})
+	location := entities.NewDriverLocation(driver.ID, 55.7558, 37.6173, time.Now())
+	location.Metadata = entities.Metadata{entities.LocationMetaDeviceID: "spoofed"}
+	require.NoError(t, service.UpdateLocation(ctx, location))
+
+	current, err := service.GetCurrentLocation(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "phone-1", current.DeviceID())
+
+	// Без устройства в запросе значение клиента не сохраняется
+	ctx = entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: "dispatcher"})
+	location = entities.NewDriverLocation(driver.ID, 55.7560, 37.6175, time.Now().Add(time.Second))
+	location.Metadata = entities.Metadata{entities.LocationMetaDeviceID: "spoofed"}
+	_, err = service.BatchUpdateLocations(ctx, []*entities.DriverLocation{location})
+	require.NoError(t, err)
+
+	current, err = service.GetCurrentLocation(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Empty(t, current.DeviceID())
+}
+
+func TestLocationService_HistoryAndCleanup(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000103", "c@example.com", "Иван", "История", "LICC")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	now := time.Now()
+	batch := []*entities.DriverLocation{
+		entities.NewDriverLocation(driver.ID, 55.75, 37.61, now.Add(-2*time.Hour)),
+		entities.NewDriverLocation(driver.ID, 55.76, 37.62, now.Add(-1*time.Hour)),
+		entities.NewDriverLocation(driver.ID, 55.77, 37.63, now.AddDate(0, 0, -40)),
+	}
+	_, err := service.BatchUpdateLocations(ctx, batch)
+	require.NoError(t, err)
+
+	history, err := service.GetLocationHistory(ctx, driver.ID, now.Add(-3*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.True(t, history[0].RecordedAt.Before(history[1].RecordedAt))
+
+	require.NoError(t, service.CleanupOldLocations(ctx))
+	all, err := locationRepo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+}
+
+func TestLocationService_SearchLocationHistory(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000104", "d@example.com", "Иван", "Фильтр", "LICD")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	orderID := uuid.New()
+	now := time.Now()
+	point := func(minutesAgo int, lat, accuracy, speed float64, onOrder bool) *entities.DriverLocation {
+		location := entities.NewDriverLocation(driver.ID, lat, 37.61, now.Add(-time.Duration(minutesAgo)*time.Minute))
+		location.Accuracy = &accuracy
+		location.Speed = &speed
+		if onOrder {
+			location.Metadata[entities.LocationMetaOrderID] = orderID.String()
+		}
+		return location
+	}
+	_, err := service.BatchUpdateLocations(ctx, []*entities.DriverLocation{
+		point(50, 55.70, 10, 0, false),
+		point(40, 55.75, 120, 30, true),
+		point(30, 55.76, 15, 45, true),
+		point(20, 55.90, 8, 60, true),
+	})
+	require.NoError(t, err)
+
+	from, to := now.Add(-time.Hour), now
+	search := func(filters entities.LocationFilters) []*entities.DriverLocation {
+		filters.DriverID, filters.From, filters.To = &driver.ID, &from, &to
+		locations, err := service.SearchLocationHistory(ctx, &filters)
+		require.NoError(t, err)
+		return locations
+	}
+
+	assert.Len(t, search(entities.LocationFilters{}), 4)
+
+	accurate := search(entities.LocationFilters{OnlyHighAccuracy: true})
+	require.Len(t, accurate, 3)
+	assert.True(t, accurate[0].RecordedAt.Before(accurate[1].RecordedAt))
+
+	minAccuracy := 12.0
+	assert.Len(t, search(entities.LocationFilters{MinAccuracy: &minAccuracy}), 2)
+
+	minSpeed, maxSpeed := 20.0, 50.0
+	assert.Len(t, search(entities.LocationFilters{MinSpeed: &minSpeed, MaxSpeed: &maxSpeed}), 2)
+
+	bounds := &entities.GeoBounds{
+		SouthWest: entities.Location{Latitude: 55.72, Longitude: 37.5},
+		NorthEast: entities.Location{Latitude: 55.80, Longitude: 37.7},
+	}
+	onOrder := search(entities.LocationFilters{OrderID: &orderID, Bounds: bounds, OnlyHighAccuracy: true})
+	require.Len(t, onOrder, 1)
+	assert.Equal(t, 55.76, onOrder[0].Latitude)
+
+	_, err = service.SearchLocationHistory(ctx, &entities.LocationFilters{
+		DriverID: &driver.ID, From: &from, To: &to, MinSpeed: &maxSpeed, MaxSpeed: &minSpeed,
+	})
+	assert.Equal(t, entities.ErrInvalidLocationFilters, err)
+}
+
+func TestLocationService_DuplicatePointProcessedOnce(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	events := &recordingEventPublisher{}
+	broadcaster := &countingBroadcaster{}
+	service := NewLocationService(memory.NewLocationRepository(), driverRepo, nil, events, broadcaster, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000108", "h@example.com", "Иван", "Повтор", "LICH")
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	// Клиент повторно отправляет ту же точку, например после таймаута ответа
+	recordedAt := time.Now().Truncate(time.Second)
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.75, 37.61, recordedAt)))
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.75, 37.61, recordedAt)))
+
+	assert.Equal(t, []string{"driver.location.updated"}, events.events)
+	assert.Equal(t, 1, broadcaster.count)
+}
+
+func TestLocationService_BatchRejectsAndDuplicates(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000104", "d@example.com", "Иван", "Пакетный", "LICD")
+	driver.Status = entities.StatusOnShift
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	now := time.Now().Truncate(time.Second)
+	first := []*entities.DriverLocation{
+		entities.NewDriverLocation(driver.ID, 55.75, 37.61, now.Add(-2*time.Minute)),
+		entities.NewDriverLocation(driver.ID, 95, 37.61, now.Add(-90*time.Second)),
+		entities.NewDriverLocation(uuid.New(), 55.75, 37.61, now.Add(-time.Minute)),
+		entities.NewDriverLocation(driver.ID, 55.76, 37.62, now.Add(-2*time.Minute)),
+		entities.NewDriverLocation(driver.ID, 55.77, 37.63, now.Add(-time.Minute)),
+	}
+	result, err := service.BatchUpdateLocations(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.Received)
+	assert.Equal(t, 2, result.Accepted)
+	assert.Equal(t, 1, result.Duplicates, "the point repeated within the batch is skipped")
+	require.Len(t, result.Rejected, 2)
+	assert.Equal(t, 1, result.Rejected[0].Index)
+	assert.Equal(t, "INVALID_LOCATION", result.Rejected[0].Code)
+	assert.Equal(t, 2, result.Rejected[1].Index)
+	assert.ErrorIs(t, result.Rejected[1].Err, entities.ErrDriverNotFound)
+
+	// Повторная отправка пакета ничего не сохраняет повторно
+	result, err = service.BatchUpdateLocations(ctx, []*entities.DriverLocation{
+		entities.NewDriverLocation(driver.ID, 55.75, 37.61, now.Add(-2*time.Minute)),
+		entities.NewDriverLocation(driver.ID, 55.78, 37.64, now),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Accepted)
+	assert.Equal(t, 1, result.Duplicates)
+	assert.Empty(t, result.Rejected)
+
+	all, err := locationRepo.List(ctx, nil)
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	stats := service.BatchStats()
+	assert.EqualValues(t, 2, stats.Batches)
+	assert.EqualValues(t, 7, stats.Received)
+	assert.EqualValues(t, 3, stats.Accepted)
+	assert.EqualValues(t, 2, stats.Duplicates)
+	assert.EqualValues(t, 2, stats.Rejected)
+	assert.Equal(t, map[string]int64{"INVALID_LOCATION": 1, "DRIVER_NOT_FOUND": 1}, stats.RejectedByCode)
+}
+
+func TestLocationService_AsyncIngestion(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	events := &recordingEventPublisher{}
+	service := NewLocationService(locationRepo, driverRepo, nil, events, nil, nil, nil, LocationPolicy{
+		Ingestion: LocationIngestionPolicy{Async: true, BufferSize: 100, Workers: 2, BatchSize: 2, FlushInterval: time.Hour},
+	}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000104", "d@example.com", "Иван", "Поток", "LICD")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.75+float64(i)/100, 37.61, now.Add(time.Duration(i-3)*time.Minute))))
+	}
+	// Проверки точки выполняются до постановки в буфер
+	assert.Equal(t, entities.ErrDriverNotFound, service.UpdateLocation(ctx, entities.NewDriverLocation(uuid.New(), 55.75, 37.61, now)))
+	assert.ErrorIs(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 95, 37.61, now)), entities.ErrInvalidLocation)
+
+	// Неполный пакет дописывается при остановке
+	dropped, err := service.Drain(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	history, err := service.GetLocationHistory(ctx, driver.ID, now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+	assert.True(t, events.has("driver.location.updated"))
+
+	// После остановки точки сохраняются синхронно
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.8, 37.61, now)))
+	current, err := service.GetCurrentLocation(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 55.8, current.Latitude)
+}
+
+func TestLocationIngester_Overloaded(t *testing.T) {
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var saved []*entities.DriverLocation
+	ingester := newLocationIngester(LocationIngestionPolicy{BufferSize: 2, Workers: 1, BatchSize: 1, FlushInterval: time.Hour},
+		func(batch []*entities.DriverLocation) {
+			started <- struct{}{}
+			<-release
+			saved = append(saved, batch...)
+		})
+
+	driverID := uuid.New()
+	require.NoError(t, ingester.enqueue(entities.NewDriverLocation(driverID, 55.75, 37.61, time.Now())))
+	<-started
+
+	// Пока пакет записывается, буфер заполняется
+	require.NoError(t, ingester.enqueue(entities.NewDriverLocation(driverID, 55.76, 37.61, time.Now())))
+	require.NoError(t, ingester.enqueue(entities.NewDriverLocation(driverID, 55.77, 37.61, time.Now())))
+	assert.Equal(t, entities.ErrLocationIngestionOverloaded, ingester.enqueue(entities.NewDriverLocation(driverID, 55.78, 37.61, time.Now())))
+
+	go func() {
+		for range started {
+		}
+	}()
+	close(release)
+	dropped, err := ingester.drain(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	close(started)
+
+	require.Len(t, saved, 3)
+	for i, latitude := range []float64{55.75, 55.76, 55.77} {
+		assert.Equal(t, latitude, saved[i].Latitude, "locations are saved in order")
+	}
+	assert.Equal(t, errIngestionStopped, ingester.enqueue(entities.NewDriverLocation(driverID, 55.79, 37.61, time.Now())))
+}
+
+func TestLocationIngester_DrainDeadline(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	ingester := newLocationIngester(LocationIngestionPolicy{BufferSize: 10, Workers: 1, BatchSize: 1, FlushInterval: time.Hour},
+		func(batch []*entities.DriverLocation) {
+			close(started)
+			<-release
+		})
+
+	driverID := uuid.New()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, ingester.enqueue(entities.NewDriverLocation(driverID, 55.75, 37.61, time.Now())))
+	}
+	<-started
+
+	// Первая точка записывается, остальные отбрасываются по истечении срока
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	dropped, err := ingester.drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 3, dropped)
+	close(release)
+}
+
+func TestLocationService_PrivacyMode(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000104", "d@example.com", "Иван", "Приватный", "LICD")
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	assert.ErrorIs(t, service.SetPrivacyMode(ctx, driver.ID, "hidden"), entities.ErrInvalidLocationPrivacy)
+	require.NoError(t, service.SetPrivacyMode(ctx, driver.ID, entities.LocationPrivacyDrop))
+
+	// Вне смены точки не сохраняются
+	now := time.Now()
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.75583, 37.61731, now)))
+	_, err := service.GetCurrentLocation(ctx, driver.ID)
+	assert.ErrorIs(t, err, entities.ErrLocationNotFound)
+
+	// Вне смены точки огрубляются, в смене сохраняются как есть
+	require.NoError(t, service.SetPrivacyMode(ctx, driver.ID, entities.LocationPrivacyBlur))
+	_, err = service.BatchUpdateLocations(ctx, []*entities.DriverLocation{
+		entities.NewDriverLocation(driver.ID, 55.75583, 37.61731, now.Add(time.Second)),
+	})
+	require.NoError(t, err)
+	current, err := service.GetCurrentLocation(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 55.76, current.Latitude)
+
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusOnShift))
+	require.NoError(t, service.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.75583, 37.61731, now.Add(2*time.Second))))
+	current, err = service.GetCurrentLocation(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 55.75583, current.Latitude)
+}
+
+// saveLocation сохраняет точку в репозитории в обход сервиса
+func saveLocation(t *testing.T, repo repositories.LocationRepository, location *entities.DriverLocation) {
+	t.Helper()
+	saved, err := repo.Create(context.Background(), location)
+	require.NoError(t, err)
+	require.True(t, saved, "location is not a duplicate")
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"sync"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// locationWatcherBuffer размер буфера подписки на местоположения водителя
+const locationWatcherBuffer = 100
+
+// locationWatchers подписки внутри процесса на местоположения отдельных водителей, например
+// потоки gRPC. Получает те же точки, что и LocationBroadcaster
+type locationWatchers struct {
+	mu       sync.RWMutex
+	watchers map[uuid.UUID]map[chan *entities.DriverLocation]struct{}
+}
+
+// newLocationWatchers создает пустой набор подписок
+func newLocationWatchers() *locationWatchers {
+	return &locationWatchers{
+		watchers: make(map[uuid.UUID]map[chan *entities.DriverLocation]struct{}),
+	}
+}
+
+// subscribe подписывается на местоположения водителя. cancel отменяет подписку;
+// канал после отмены не закрывается и больше не получает точек
+func (w *locationWatchers) subscribe(driverID uuid.UUID) (<-chan *entities.DriverLocation, func()) {
+	updates := make(chan *entities.DriverLocation, locationWatcherBuffer)
+
+	w.mu.Lock()
+	if w.watchers[driverID] == nil {
+		w.watchers[driverID] = make(map[chan *entities.DriverLocation]struct{})
+	}
+	w.watchers[driverID][updates] = struct{}{}
+	w.mu.Unlock()
+
+	cancel := func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.watchers[driverID], updates)
+		if len(w.watchers[driverID]) == 0 {
+			delete(w.watchers, driverID)
+		}
+	}
+	return updates, cancel
+}
+
+// BroadcastLocation передает местоположение подписчикам водителя. Не блокирует вызывающего:
+// подписчик с переполненным буфером пропускает точку
+func (w *locationWatchers) BroadcastLocation(location *entities.DriverLocation) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	for updates := range w.watchers[location.DriverID] {
+		select {
+		case updates <- location:
+		default:
+		}
+	}
+}
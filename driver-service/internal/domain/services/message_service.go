@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MessageService интерфейс для переписки водителей с диспетчерами
+type MessageService interface {
+	// SendMessage отправляет сообщение от стороны req.Sender; senderID — субъект токена
+	SendMessage(ctx context.Context, driverID uuid.UUID, senderID string, req *entities.SendMessageRequest) (*entities.DriverMessage, error)
+	ListMessages(ctx context.Context, filters *entities.MessageFilters) ([]*entities.DriverMessage, error)
+	CountMessages(ctx context.Context, filters *entities.MessageFilters) (int, error)
+	// AcknowledgeMessages ставит отметки о доставке или прочтении от стороны req.Reader
+	AcknowledgeMessages(ctx context.Context, driverID uuid.UUID, req *entities.MessageReceiptRequest) ([]*entities.DriverMessage, error)
+	// CleanupOldMessages удаляет сообщения старше срока хранения
+	CleanupOldMessages(ctx context.Context) (int, error)
+}
+
+// MessageBroadcaster рассылает новые сообщения и отметки подписчикам переписки в реальном
+// времени. Реализация не должна блокировать вызывающего
+type MessageBroadcaster interface {
+	BroadcastMessage(message *entities.DriverMessage)
+	BroadcastReceipt(message *entities.DriverMessage)
+}
+
+// MessagePolicy параметры переписки
+type MessagePolicy struct {
+	// Retention срок хранения сообщений; 0 — бессрочно
+	Retention time.Duration
+}
+
+// messageService реализация MessageService
+type messageService struct {
+	messageRepo repositories.MessageRepository
+	driverRepo  repositories.DriverRepository
+	eventBus    EventPublisher
+	broadcaster MessageBroadcaster
+	policy      MessagePolicy
+	logger      *zap.Logger
+}
+
+// NewMessageService создает новый MessageService.
+// broadcaster может быть nil, если рассылка в реальном времени не нужна
+func NewMessageService(
+	messageRepo repositories.MessageRepository,
+	driverRepo repositories.DriverRepository,
+	eventBus EventPublisher,
+	broadcaster MessageBroadcaster,
+	policy MessagePolicy,
+	logger *zap.Logger,
+) MessageService {
+	return &messageService{
+		messageRepo: messageRepo,
+		driverRepo:  driverRepo,
+		eventBus:    eventBus,
+		broadcaster: broadcaster,
+		policy:      policy,
+		logger:      logger,
+	}
+}
+
+// SendMessage сохраняет сообщение, публикует событие и рассылает его подписчикам переписки
+func (s *messageService) SendMessage(ctx context.Context, driverID uuid.UUID, senderID string, req *entities.SendMessageRequest) (*entities.DriverMessage, error) {
+	if !req.Sender.IsValid() {
+		return nil, entities.ErrInvalidMessage
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	message := entities.NewDriverMessage(driverID, req.Sender, senderID, req)
+	if err := s.messageRepo.Create(ctx, message); err != nil {
+		return nil, err
+	}
+
+	eventData := map[string]interface{}{
+		"message_id": message.ID.String(),
+		"sender":     string(message.Sender),
+		"kind":       string(message.Kind),
+		"created_at": message.CreatedAt,
+	}
+	if message.OrderID != nil {
+		eventData["order_id"] = message.OrderID.String()
+	}
+	if message.DelayMinutes != nil {
+		eventData["delay_minutes"] = *message.DelayMinutes
+	}
+	if message.Text != "" {
+		eventData["text"] = message.Text
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventMessageSent, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish message sent event",
+			zap.Error(err),
+			zap.String("message_id", message.ID.String()),
+		)
+	}
+
+	if s.broadcaster != nil {
+		s.broadcaster.BroadcastMessage(message)
+	}
+
+	s.logger.Info("Driver message sent",
+		zap.String("message_id", message.ID.String()),
+		zap.String("driver_id", driverID.String()),
+		zap.String("sender", string(message.Sender)),
+		zap.String("kind", string(message.Kind)),
+	)
+
+	return message, nil
+}
+
+// ListMessages получает страницу переписки существующего водителя
+func (s *messageService) ListMessages(ctx context.Context, filters *entities.MessageFilters) ([]*entities.DriverMessage, error) {
+	if _, err := s.driverRepo.GetByID(ctx, filters.DriverID); err != nil {
+		return nil, err
+	}
+	return s.messageRepo.List(ctx, filters)
+}
+
+// CountMessages считает сообщения переписки
+func (s *messageService) CountMessages(ctx context.Context, filters *entities.MessageFilters) (int, error) {
+	return s.messageRepo.Count(ctx, filters)
+}
+
+// AcknowledgeMessages отмечает сообщения, адресованные стороне req.Reader. Собственные
+// сообщения стороны и уже отмеченные пропускаются, поэтому повторная отметка безопасна
+func (s *messageService) AcknowledgeMessages(ctx context.Context, driverID uuid.UUID, req *entities.MessageReceiptRequest) ([]*entities.DriverMessage, error) {
+	if !req.Reader.IsValid() {
+		return nil, entities.ErrInvalidMessageReceipt
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	unique := make(map[uuid.UUID]bool, len(req.MessageIDs))
+	for _, id := range req.MessageIDs {
+		unique[id] = true
+	}
+
+	messages, err := s.messageRepo.GetByIDs(ctx, driverID, req.MessageIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) != len(unique) {
+		return nil, entities.ErrMessageNotFound
+	}
+
+	now := time.Now()
+	for _, message := range messages {
+		if message.Recipient() != req.Reader {
+			continue
+		}
+		wasRead := message.ReadAt != nil
+		if !message.Acknowledge(req.Status, now) {
+			continue
+		}
+		if err := s.messageRepo.UpdateReceipt(ctx, message); err != nil {
+			return nil, err
+		}
+
+		// Прочтение подразумевает доставку: при одновременной отметке публикуется только read
+		eventType := eventMessageDelivered
+		if !wasRead && message.ReadAt != nil {
+			eventType = eventMessageRead
+		}
+		eventData := map[string]interface{}{
+			"message_id": message.ID.String(),
+			"sender":     string(message.Sender),
+			"at":         now,
+		}
+		if message.OrderID != nil {
+			eventData["order_id"] = message.OrderID.String()
+		}
+		if err := s.eventBus.PublishDriverEvent(ctx, eventType, driverID, eventData); err != nil {
+			s.logger.Error("Failed to publish message receipt event",
+				zap.Error(err),
+				zap.String("message_id", message.ID.String()),
+			)
+		}
+
+		if s.broadcaster != nil {
+			s.broadcaster.BroadcastReceipt(message)
+		}
+	}
+
+	return messages, nil
+}
+
+// CleanupOldMessages удаляет сообщения старше MessagePolicy.Retention
+func (s *messageService) CleanupOldMessages(ctx context.Context) (int, error) {
+	if s.policy.Retention <= 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.messageRepo.DeleteOlderThan(ctx, time.Now().Add(-s.policy.Retention))
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("Old driver messages deleted",
+		zap.Int("deleted", deleted),
+		zap.Duration("retention", s.policy.Retention),
+	)
+
+	return deleted, nil
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingBroadcaster запоминает разосланные сообщения и отметки
+type recordingBroadcaster struct {
+	mu       sync.Mutex
+	messages []*entities.DriverMessage
+	receipts []*entities.DriverMessage
+}
+
+func (b *recordingBroadcaster) BroadcastMessage(message *entities.DriverMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.messages = append(b.messages, message)
+}
+
+func (b *recordingBroadcaster) BroadcastReceipt(message *entities.DriverMessage) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.receipts = append(b.receipts, message)
+}
+
+type messageFixture struct {
+	service     MessageService
+	messageRepo *memory.MessageRepository
+	events      *recordingEventPublisher
+	broadcaster *recordingBroadcaster
+	driver      *entities.Driver
+}
+
+func newMessageFixture(t *testing.T, policy MessagePolicy) *messageFixture {
+	t.Helper()
+	driverRepo := memory.NewDriverRepository()
+	driver := newTestDriver("71")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	f := &messageFixture{
+		messageRepo: memory.NewMessageRepository(),
+		events:      &recordingEventPublisher{},
+		broadcaster: &recordingBroadcaster{},
+		driver:      driver,
+	}
+	f.service = NewMessageService(f.messageRepo, driverRepo, f.events, f.broadcaster, policy, zap.NewNop())
+	return f
+}
+
+func (f *messageFixture) send(t *testing.T, sender entities.MessageSender, orderID *uuid.UUID, kind entities.MessageKind) *entities.DriverMessage {
+	t.Helper()
+	req := &entities.SendMessageRequest{OrderID: orderID, Sender: sender, Kind: kind}
+	if kind == entities.MessageKindText {
+		req.Text = "Подъезжаю"
+	}
+	message, err := f.service.SendMessage(context.Background(), f.driver.ID, "", req)
+	require.NoError(t, err)
+	return message
+}
+
+func TestMessageService_SendMessage(t *testing.T) {
+	f := newMessageFixture(t, MessagePolicy{})
+	ctx := context.Background()
+
+	message := f.send(t, entities.MessageSenderDriver, nil, entities.MessageKindText)
+	assert.Equal(t, f.driver.ID, message.DriverID)
+	assert.Equal(t, "Подъезжаю", message.Text)
+	assert.True(t, f.events.has(eventMessageSent))
+	require.Len(t, f.broadcaster.messages, 1)
+	assert.Equal(t, message.ID, f.broadcaster.messages[0].ID)
+
+	_, err := f.service.SendMessage(ctx, f.driver.ID, "", &entities.SendMessageRequest{Kind: entities.MessageKindArrived})
+	assert.Equal(t, entities.ErrInvalidMessage, err, "sender is required")
+
+	_, err = f.service.SendMessage(ctx, f.driver.ID, "", &entities.SendMessageRequest{
+		Sender: entities.MessageSenderDriver,
+		Kind:   entities.MessageKindDelayed,
+	})
+	assert.Equal(t, entities.ErrInvalidMessage, err)
+
+	_, err = f.service.SendMessage(ctx, uuid.New(), "", &entities.SendMessageRequest{
+		Sender: entities.MessageSenderDispatcher,
+		Kind:   entities.MessageKindCallMe,
+	})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+	assert.Len(t, f.broadcaster.messages, 1)
+}
+
+func TestMessageService_ListMessages_Threads(t *testing.T) {
+	f := newMessageFixture(t, MessagePolicy{})
+	ctx := context.Background()
+	orderID := uuid.New()
+
+	general := f.send(t, entities.MessageSenderDispatcher, nil, entities.MessageKindCallMe)
+	first := f.send(t, entities.MessageSenderDriver, &orderID, entities.MessageKindOnTheWay)
+	time.Sleep(time.Millisecond)
+	second := f.send(t, entities.MessageSenderDriver, &orderID, entities.MessageKindArrived)
+
+	messages, err := f.service.ListMessages(ctx, &entities.MessageFilters{DriverID: f.driver.ID, OrderID: &orderID})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, second.ID, messages[0].ID, "newest first")
+	assert.Equal(t, first.ID, messages[1].ID)
+
+	messages, err = f.service.ListMessages(ctx, &entities.MessageFilters{DriverID: f.driver.ID})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, general.ID, messages[0].ID)
+
+	total, err := f.service.CountMessages(ctx, &entities.MessageFilters{DriverID: f.driver.ID, OrderID: &orderID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+
+	_, err = f.service.ListMessages(ctx, &entities.MessageFilters{DriverID: uuid.New()})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
+
+func TestMessageService_AcknowledgeMessages(t *testing.T) {
+	f := newMessageFixture(t, MessagePolicy{})
+	ctx := context.Background()
+
+	fromDriver := f.send(t, entities.MessageSenderDriver, nil, entities.MessageKindArrived)
+	fromDispatcher := f.send(t, entities.MessageSenderDispatcher, nil, entities.MessageKindCallMe)
+
+	// Диспетчер отмечает оба сообщения: собственное пропускается
+	messages, err := f.service.AcknowledgeMessages(ctx, f.driver.ID, &entities.MessageReceiptRequest{
+		MessageIDs: []uuid.UUID{fromDriver.ID, fromDispatcher.ID},
+		Status:     entities.ReceiptRead,
+		Reader:     entities.MessageSenderDispatcher,
+	})
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.True(t, f.events.has(eventMessageRead))
+	assert.False(t, f.events.has(eventMessageDelivered), "read implies delivered")
+	require.Len(t, f.broadcaster.receipts, 1)
+	assert.Equal(t, fromDriver.ID, f.broadcaster.receipts[0].ID)
+
+	stored, err := f.messageRepo.GetByIDs(ctx, f.driver.ID, []uuid.UUID{fromDriver.ID, fromDispatcher.ID})
+	require.NoError(t, err)
+	for _, message := range stored {
+		if message.ID == fromDriver.ID {
+			assert.NotNil(t, message.ReadAt)
+			assert.NotNil(t, message.DeliveredAt)
+		} else {
+			assert.Nil(t, message.DeliveredAt, "own message is not acknowledged")
+		}
+	}
+
+	// Повторная отметка безопасна и ничего не рассылает
+	_, err = f.service.AcknowledgeMessages(ctx, f.driver.ID, &entities.MessageReceiptRequest{
+		MessageIDs: []uuid.UUID{fromDriver.ID, fromDriver.ID},
+		Status:     entities.ReceiptDelivered,
+		Reader:     entities.MessageSenderDispatcher,
+	})
+	require.NoError(t, err)
+	assert.Len(t, f.broadcaster.receipts, 1)
+
+	_, err = f.service.AcknowledgeMessages(ctx, f.driver.ID, &entities.MessageReceiptRequest{
+		MessageIDs: []uuid.UUID{fromDispatcher.ID},
+		Status:     entities.ReceiptDelivered,
+		Reader:     entities.MessageSenderDriver,
+	})
+	require.NoError(t, err)
+	assert.True(t, f.events.has(eventMessageDelivered))
+
+	_, err = f.service.AcknowledgeMessages(ctx, uuid.New(), &entities.MessageReceiptRequest{
+		MessageIDs: []uuid.UUID{fromDriver.ID},
+		Status:     entities.ReceiptRead,
+		Reader:     entities.MessageSenderDispatcher,
+	})
+	assert.Equal(t, entities.ErrMessageNotFound, err, "message of another driver")
+
+	_, err = f.service.AcknowledgeMessages(ctx, f.driver.ID, &entities.MessageReceiptRequest{
+		MessageIDs: []uuid.UUID{fromDriver.ID},
+		Status:     entities.ReceiptRead,
+	})
+	assert.Equal(t, entities.ErrInvalidMessageReceipt, err, "reader is required")
+}
+
+func TestMessageService_CleanupOldMessages(t *testing.T) {
+	ctx := context.Background()
+
+	f := newMessageFixture(t, MessagePolicy{})
+	f.send(t, entities.MessageSenderDriver, nil, entities.MessageKindArrived)
+	deleted, err := f.service.CleanupOldMessages(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted, "zero retention keeps messages")
+
+	f = newMessageFixture(t, MessagePolicy{Retention: 24 * time.Hour})
+	recent := f.send(t, entities.MessageSenderDriver, nil, entities.MessageKindArrived)
+	old := entities.NewDriverMessage(f.driver.ID, entities.MessageSenderDispatcher, "",
+		&entities.SendMessageRequest{Kind: entities.MessageKindCallMe})
+	old.CreatedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(t, f.messageRepo.Create(ctx, old))
+
+	deleted, err = f.service.CleanupOldMessages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	messages, err := f.messageRepo.ListByDriverID(ctx, f.driver.ID)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, recent.ID, messages[0].ID)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// Шаблоны уведомлений водителям
+const (
+	NotificationInspectionDue     = "inspection.due"
+	NotificationInspectionOverdue = "inspection.overdue"
+	// NotificationInspectionPhotosRejected фотографии техосмотра отклонены и требуют пересъемки
+	NotificationInspectionPhotosRejected = "inspection.photos_rejected"
+	// Продление документов: напоминание, получение новой версии и решение по ней
+	NotificationDocumentExpiring         = "document.expiring"
+	NotificationDocumentRenewalSubmitted = "document.renewal_submitted"
+	NotificationDocumentRenewalVerified  = "document.renewal_verified"
+	NotificationDocumentRenewalRejected  = "document.renewal_rejected"
+	// Кампании перепроверки: запуск кампании и повторные напоминания до срока
+	NotificationReverificationRequired = "document.reverification_required"
+	NotificationReverificationReminder = "document.reverification_reminder"
+	// NotificationDocumentRejected документ отклонен при проверке
+	NotificationDocumentRejected = "document.rejected"
+	// NotificationDocumentResubmissionReminder отклоненный документ не загружен повторно
+	NotificationDocumentResubmissionReminder = "document.resubmission_reminder"
+	// NotificationLicenseExpiring истекает водительское удостоверение; заменяет document.expiring
+	NotificationLicenseExpiring = "license.expiring"
+	// NotificationDriverBlocked водитель заблокирован
+	NotificationDriverBlocked = "driver.blocked"
+	// NotificationShiftAutoEnded смена закрыта автоматически по превышению длительности
+	NotificationShiftAutoEnded = "shift.auto_ended"
+)
+
+// NotificationSender интерфейс для отправки уведомлений водителям
+type NotificationSender interface {
+	SendToDriver(ctx context.Context, driverID uuid.UUID, template string, data map[string]interface{}) error
+}
+
+// NotificationProvider канал доставки уведомлений: SMS-шлюз, push или почта
+type NotificationProvider interface {
+	Channel() entities.NotificationChannel
+	// Send доставляет сообщение адресату; адрес без нужного канала — ошибка
+	Send(ctx context.Context, recipient entities.NotificationRecipient, message entities.NotificationMessage) error
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"text/template"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverStatusEventTypes события смены статуса, на которые подписан NotificationService
+var DriverStatusEventTypes = []string{
+	eventDriverStatusChanged,
+}
+
+// DefaultNotificationTemplates шаблоны уведомлений по умолчанию; конфигурация может
+// переопределить любой из них по имени
+var DefaultNotificationTemplates = []entities.NotificationTemplate{
+	{
+		Name:    NotificationInspectionDue,
+		Subject: "Техосмотр",
+		Body:    "{{.first_name}}, пройдите техосмотр до {{date .due_date}}.",
+	},
+	{
+		Name:    NotificationInspectionOverdue,
+		Subject: "Техосмотр просрочен",
+		Body:    "{{.first_name}}, срок техосмотра истек {{date .due_date}}. Пройдите его как можно скорее.",
+	},
+	{
+		Name:    NotificationInspectionPhotosRejected,
+		Subject: "Фотографии техосмотра отклонены",
+		Body:    "{{.first_name}}, переснимите фотографии автомобиля до {{date .due_date}}.",
+	},
+	{
+		Name:    NotificationDocumentExpiring,
+		Subject: "Истекает срок документа",
+		Body:    "{{.first_name}}, срок действия документа истекает {{date .expiry_date}}. Загрузите новую версию.",
+	},
+	{
+		Name:    NotificationDocumentRenewalSubmitted,
+		Subject: "Документ получен",
+		Body:    "{{.first_name}}, новая версия документа получена и будет проверена до {{date .verify_by}}.",
+	},
+	{
+		Name:    NotificationDocumentRenewalVerified,
+		Subject: "Документ продлен",
+		Body:    "{{.first_name}}, новая версия документа подтверждена и действует до {{date .expiry_date}}.",
+	},
+	{
+		Name:    NotificationDocumentRenewalRejected,
+		Subject: "Продление документа отклонено",
+		Body: "{{.first_name}}, новая версия документа отклонена{{with .rejection_reason}}: {{.}}{{end}}. " +
+			"Загрузите документ повторно.",
+	},
+	{
+		Name:    NotificationReverificationRequired,
+		Subject: "Требуется перепроверка документов",
+		Body:    "{{.first_name}}, загрузите документы повторно до {{date .deadline}}.",
+	},
+	{
+		Name:    NotificationReverificationReminder,
+		Subject: "Напоминание о перепроверке документов",
+		Body:    "{{.first_name}}, до {{date .deadline}} осталось загрузить документы для перепроверки.",
+	},
+	{
+		Name:    NotificationProfileIncomplete,
+		Subject: "Заполните профиль",
+		Body:    "{{.first_name}}, профиль заполнен на {{.score}}%. Добавьте недостающие данные, чтобы начать работу.",
+	},
+	{
+		Name:    NotificationDocumentRejected,
+		Subject: "Документ отклонен",
+		Body:    "{{.first_name}}, документ отклонен при проверке{{with .rejection_reason}}: {{.}}{{end}}. Загрузите его повторно.",
+	},
+	{
+		Name:    NotificationDocumentResubmissionReminder,
+		Subject: "Загрузите документ повторно",
+		Body: "{{.first_name}}, документ отклонен {{date .rejected_at}}{{with .rejection_reason}} ({{.}}){{end}} " +
+			"и до сих пор не загружен повторно. Загрузите новую версию, чтобы продолжить работу.",
+	},
+	{
+		Name:    NotificationLicenseExpiring,
+		Subject: "Истекает водительское удостоверение",
+		Body:    "{{.first_name}}, водительское удостоверение истекает {{date .expiry_date}}. Загрузите новое, чтобы продолжить работу.",
+	},
+	{
+		Name:    NotificationDriverBlocked,
+		Subject: "Аккаунт заблокирован",
+		Body:    "{{.first_name}}, ваш аккаунт заблокирован. Свяжитесь с поддержкой.",
+	},
+	{
+		Name:    NotificationShiftAutoEnded,
+		Subject: "Смена завершена",
+		Body:    "{{.first_name}}, смена длилась дольше {{.max_duration_hours}} ч и завершена автоматически в {{datetime .ended_at}}.",
+	},
+	{
+		// Код доставляется только на номер, который подтверждается
+		Name:     NotificationPhoneVerification,
+		Channels: []entities.NotificationChannel{entities.NotificationChannelSMS},
+		Subject:  "Подтверждение телефона",
+		Body:     "Код подтверждения: {{.code}}. Действует {{.ttl_minutes}} мин. Никому не сообщайте его.",
+	},
+}
+
+// NotificationPolicy параметры отправки уведомлений водителям
+type NotificationPolicy struct {
+	// DefaultChannels каналы шаблонов, для которых каналы не заданы
+	DefaultChannels []entities.NotificationChannel
+	// Templates переопределения шаблонов по умолчанию по имени
+	Templates []entities.NotificationTemplate
+	// Location часовой пояс дат в тексте уведомлений; nil — UTC
+	Location *time.Location
+}
+
+// NotificationService отправляет водителям уведомления по шаблонам через настроенные каналы
+type NotificationService interface {
+	NotificationSender
+	// HandleStatusEvent уведомляет водителя о блокировке; подписчик DriverStatusEventTypes
+	HandleStatusEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+}
+
+// compiledTemplate шаблон уведомления, готовый к подстановке данных
+type compiledTemplate struct {
+	channels []entities.NotificationChannel
+	subject  *template.Template
+	body     *template.Template
+}
+
+// notificationService реализация NotificationService
+type notificationService struct {
+	driverRepo repositories.DriverRepository
+	providers  map[entities.NotificationChannel]NotificationProvider
+	templates  map[string]*compiledTemplate
+	logger     *zap.Logger
+}
+
+// NewNotificationService создает новый NotificationService. Каналы без провайдера
+// пропускаются при отправке; ошибка возвращается, если шаблон не разбирается
+func NewNotificationService(
+	driverRepo repositories.DriverRepository,
+	providers []NotificationProvider,
+	policy NotificationPolicy,
+	logger *zap.Logger,
+) (NotificationService, error) {
+	loc := policy.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	defaultChannels := policy.DefaultChannels
+	if len(defaultChannels) == 0 {
+		defaultChannels = []entities.NotificationChannel{entities.NotificationChannelPush}
+	}
+
+	definitions := make(map[string]entities.NotificationTemplate, len(DefaultNotificationTemplates))
+	for _, definition := range DefaultNotificationTemplates {
+		definitions[definition.Name] = definition
+	}
+	for _, override := range policy.Templates {
+		definition := definitions[override.Name]
+		definition.Name = override.Name
+		if len(override.Channels) > 0 {
+			definition.Channels = override.Channels
+		}
+		if override.Subject != "" {
+			definition.Subject = override.Subject
+		}
+		if override.Body != "" {
+			definition.Body = override.Body
+		}
+		definitions[override.Name] = definition
+	}
+
+	funcs := template.FuncMap{
+		"date":     formatNotificationTime(loc, "02.01.2006"),
+		"datetime": formatNotificationTime(loc, "02.01.2006 15:04"),
+	}
+
+	templates := make(map[string]*compiledTemplate, len(definitions))
+	for name, definition := range definitions {
+		if definition.Body == "" {
+			return nil, fmt.Errorf("notification template %s has no body", name)
+		}
+		subject, err := template.New(name + ".subject").Option("missingkey=zero").Funcs(funcs).Parse(definition.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse notification template %s subject: %w", name, err)
+		}
+		body, err := template.New(name + ".body").Option("missingkey=zero").Funcs(funcs).Parse(definition.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse notification template %s body: %w", name, err)
+		}
+
+		channels := definition.Channels
+		if len(channels) == 0 {
+			channels = defaultChannels
+		}
+		templates[name] = &compiledTemplate{channels: channels, subject: subject, body: body}
+	}
+
+	byChannel := make(map[entities.NotificationChannel]NotificationProvider, len(providers))
+	for _, provider := range providers {
+		byChannel[provider.Channel()] = provider
+	}
+
+	return &notificationService{
+		driverRepo: driverRepo,
+		providers:  byChannel,
+		templates:  templates,
+		logger:     logger,
+	}, nil
+}
+
+// SendToDriver отправляет уведомление по шаблону во все его каналы. Ошибка возвращается,
+// только если ни один канал не доставил сообщение
+func (s *notificationService) SendToDriver(ctx context.Context, driverID uuid.UUID, templateName string, data map[string]interface{}) error {
+	tmpl, ok := s.templates[templateName]
+	if !ok {
+		return fmt.Errorf("unknown notification template %s", templateName)
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		values[key] = value
+	}
+	values["first_name"] = driver.FirstName
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, values); err != nil {
+		return fmt.Errorf("failed to render notification %s: %w", templateName, err)
+	}
+	if err := tmpl.body.Execute(&body, values); err != nil {
+		return fmt.Errorf("failed to render notification %s: %w", templateName, err)
+	}
+
+	recipient := entities.NotificationRecipient{
+		DriverID: driver.ID,
+		Phone:    driver.Phone,
+		Email:    driver.Email,
+	}
+	message := entities.NotificationMessage{
+		Template: templateName,
+		Subject:  subject.String(),
+		Body:     body.String(),
+	}
+
+	delivered := 0
+	var errs []error
+	for _, channel := range tmpl.channels {
+		provider, ok := s.providers[channel]
+		if !ok {
+			s.logger.Debug("Notification channel is not configured, skipping",
+				zap.String("channel", string(channel)),
+				zap.String("template", templateName),
+			)
+			continue
+		}
+
+		if err := provider.Send(ctx, recipient, message); err != nil {
+			s.logger.Warn("Failed to deliver driver notification",
+				zap.Error(err),
+				zap.String("channel", string(channel)),
+				zap.String("template", templateName),
+				zap.String("driver_id", driverID.String()),
+			)
+			errs = append(errs, fmt.Errorf("%s: %w", channel, err))
+			continue
+		}
+		delivered++
+	}
+
+	if delivered == 0 && len(errs) > 0 {
+		return fmt.Errorf("failed to send notification %s: %w", templateName, errors.Join(errs...))
+	}
+
+	s.logger.Info("Driver notification sent",
+		zap.String("template", templateName),
+		zap.String("driver_id", driverID.String()),
+		zap.Int("channels", delivered),
+	)
+
+	return nil
+}
+
+// HandleStatusEvent уведомляет водителя, если после смены статуса он заблокирован
+func (s *notificationService) HandleStatusEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err == entities.ErrDriverNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if driver.Status != entities.StatusBlocked {
+		return nil
+	}
+
+	return s.SendToDriver(ctx, driverID, NotificationDriverBlocked, map[string]interface{}{})
+}
+
+// formatNotificationTime возвращает функцию шаблона, печатающую время в часовом поясе loc
+func formatNotificationTime(loc *time.Location, layout string) func(value interface{}) string {
+	return func(value interface{}) string {
+		switch t := value.(type) {
+		case time.Time:
+			return t.In(loc).Format(layout)
+		case *time.Time:
+			if t != nil {
+				return t.In(loc).Format(layout)
+			}
+		}
+		return ""
+	}
+}
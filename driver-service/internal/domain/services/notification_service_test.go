@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeNotificationProvider запоминает отправленные сообщения и может отказывать
+type fakeNotificationProvider struct {
+	channel  entities.NotificationChannel
+	err      error
+	messages []entities.NotificationMessage
+}
+
+func (p *fakeNotificationProvider) Channel() entities.NotificationChannel {
+	return p.channel
+}
+
+func (p *fakeNotificationProvider) Send(ctx context.Context, recipient entities.NotificationRecipient, message entities.NotificationMessage) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.messages = append(p.messages, message)
+	return nil
+}
+
+func newNotificationFixture(t *testing.T, policy NotificationPolicy, providers ...NotificationProvider) (NotificationService, *memory.DriverRepository, *entities.Driver) {
+	driverRepo := memory.NewDriverRepository()
+	driver := newTestDriver("1")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	service, err := NewNotificationService(driverRepo, providers, policy, zap.NewNop())
+	require.NoError(t, err)
+	return service, driverRepo, driver
+}
+
+func TestNotificationService_RendersTemplateForConfiguredChannels(t *testing.T) {
+	ctx := context.Background()
+	moscow := time.FixedZone("MSK", 3*60*60)
+	sms := &fakeNotificationProvider{channel: entities.NotificationChannelSMS}
+	push := &fakeNotificationProvider{channel: entities.NotificationChannelPush}
+	service, _, driver := newNotificationFixture(t, NotificationPolicy{
+		DefaultChannels: []entities.NotificationChannel{entities.NotificationChannelPush},
+		Templates: []entities.NotificationTemplate{{
+			Name:     NotificationLicenseExpiring,
+			Channels: []entities.NotificationChannel{entities.NotificationChannelSMS, entities.NotificationChannelEmail},
+		}},
+		Location: moscow,
+	}, sms, push)
+
+	err := service.SendToDriver(ctx, driver.ID, NotificationLicenseExpiring, map[string]interface{}{
+		"expiry_date": time.Date(2024, 5, 31, 22, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	// Канал email не настроен и пропускается, push не указан в переопределении шаблона
+	require.Len(t, sms.messages, 1)
+	assert.Empty(t, push.messages)
+	assert.Equal(t, NotificationLicenseExpiring, sms.messages[0].Template)
+	assert.Contains(t, sms.messages[0].Body, "Иван")
+	assert.Contains(t, sms.messages[0].Body, "01.06.2024")
+
+	require.NoError(t, service.SendToDriver(ctx, driver.ID, NotificationDocumentRejected, map[string]interface{}{
+		"rejection_reason": "размытое фото",
+	}))
+	require.Len(t, push.messages, 1)
+	assert.Equal(t, "Документ отклонен", push.messages[0].Subject)
+	assert.Contains(t, push.messages[0].Body, "размытое фото")
+
+	assert.Error(t, service.SendToDriver(ctx, driver.ID, "unknown.template", nil))
+}
+
+func TestNotificationService_FailsOnlyWhenNothingDelivered(t *testing.T) {
+	ctx := context.Background()
+	sms := &fakeNotificationProvider{channel: entities.NotificationChannelSMS, err: errors.New("gateway down")}
+	push := &fakeNotificationProvider{channel: entities.NotificationChannelPush}
+	service, _, driver := newNotificationFixture(t, NotificationPolicy{
+		DefaultChannels: []entities.NotificationChannel{entities.NotificationChannelSMS, entities.NotificationChannelPush},
+	}, sms, push)
+
+	require.NoError(t, service.SendToDriver(ctx, driver.ID, NotificationDriverBlocked, nil))
+	assert.Len(t, push.messages, 1)
+
+	push.err = errors.New("gateway down")
+	assert.Error(t, service.SendToDriver(ctx, driver.ID, NotificationDriverBlocked, nil))
+}
+
+func TestNotificationService_HandleStatusEvent(t *testing.T) {
+	ctx := context.Background()
+	push := &fakeNotificationProvider{channel: entities.NotificationChannelPush}
+	service, driverRepo, driver := newNotificationFixture(t, NotificationPolicy{}, push)
+
+	require.NoError(t, service.HandleStatusEvent(ctx, eventDriverStatusChanged, driver.ID))
+	assert.Empty(t, push.messages)
+
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusBlocked))
+	require.NoError(t, service.HandleStatusEvent(ctx, eventDriverStatusChanged, driver.ID))
+	require.Len(t, push.messages, 1)
+	assert.Equal(t, NotificationDriverBlocked, push.messages[0].Template)
+
+	assert.NoError(t, service.HandleStatusEvent(ctx, eventDriverStatusChanged, uuid.New()))
+}
+
+func TestNewNotificationService_RejectsInvalidTemplate(t *testing.T) {
+	_, err := NewNotificationService(memory.NewDriverRepository(), nil, NotificationPolicy{
+		Templates: []entities.NotificationTemplate{{Name: NotificationDriverBlocked, Body: "{{.first_name"}},
+	}, zap.NewNop())
+	assert.Error(t, err)
+
+	_, err = NewNotificationService(memory.NewDriverRepository(), nil, NotificationPolicy{
+		Templates: []entities.NotificationTemplate{{Name: "custom.without_body", Subject: "Тема"}},
+	}, zap.NewNop())
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OrderTrackingPolicy параметры ссылок отслеживания заказов
+type OrderTrackingPolicy struct {
+	// Secret ключ подписи токенов ссылок; пустой — ссылки отслеживания отключены
+	Secret string
+	// LinkBaseURL адрес страницы отслеживания для клиента, к которому добавляется параметр token
+	LinkBaseURL string
+	// TTL наибольшая длительность отслеживания: после нее ссылка перестает действовать, даже
+	// если отслеживание не остановлено
+	TTL time.Duration
+}
+
+// OrderTrackingService интерфейс отслеживания заказов клиентами
+type OrderTrackingService interface {
+	// StartTracking начинает отслеживание заказа водителя и выпускает ссылку для клиента
+	StartTracking(ctx context.Context, driverID, orderID uuid.UUID, actorID string) (*entities.OrderTrackingLink, error)
+	// StopTracking останавливает отслеживание заказа водителя; ссылка перестает действовать сразу
+	StopTracking(ctx context.Context, driverID, orderID uuid.UUID) (*entities.OrderTrackingSession, error)
+	// GetTrackedLocation возвращает огрубленное местоположение водителя по токену ссылки,
+	// пока заказ отслеживается
+	GetTrackedLocation(ctx context.Context, token string) (*entities.TrackedLocation, error)
+}
+
+// orderTrackingService реализация OrderTrackingService
+type orderTrackingService struct {
+	trackingRepo    repositories.OrderTrackingRepository
+	driverRepo      repositories.DriverRepository
+	locationService LocationService
+	policy          OrderTrackingPolicy
+	logger          *zap.Logger
+}
+
+// NewOrderTrackingService создает новый OrderTrackingService
+func NewOrderTrackingService(
+	trackingRepo repositories.OrderTrackingRepository,
+	driverRepo repositories.DriverRepository,
+	locationService LocationService,
+	policy OrderTrackingPolicy,
+	logger *zap.Logger,
+) OrderTrackingService {
+	return &orderTrackingService{
+		trackingRepo:    trackingRepo,
+		driverRepo:      driverRepo,
+		locationService: locationService,
+		policy:          policy,
+		logger:          logger,
+	}
+}
+
+// StartTracking начинает отслеживание заказа. Отслеживание с истекшей ссылкой не мешает начать
+// новое: оно останавливается. Точка водителя с отметкой заказа сохраняется, если водитель
+// на связи; без свежей точки клиент увидит водителя после его следующей точки
+func (s *orderTrackingService) StartTracking(ctx context.Context, driverID, orderID uuid.UUID, actorID string) (*entities.OrderTrackingLink, error) {
+	if s.policy.Secret == "" {
+		return nil, entities.ErrOrderTrackingDisabled
+	}
+
+	now := time.Now()
+	session, err := entities.NewOrderTrackingSession(driverID, orderID, actorID, s.policy.TTL, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.trackingRepo.GetActiveByOrder(ctx, orderID)
+	switch {
+	case err == nil:
+		if existing.CheckActive(now) == nil {
+			return nil, entities.ErrOrderTrackingExists
+		}
+		if err := existing.Stop(now); err != nil {
+			return nil, err
+		}
+		if err := s.trackingRepo.Stop(ctx, existing); err != nil && err != entities.ErrOrderTrackingInactive {
+			return nil, err
+		}
+	case err != entities.ErrOrderTrackingNotFound:
+		return nil, err
+	}
+
+	if err := s.locationService.StartOrderTracking(ctx, driverID, orderID); err != nil {
+		if !errors.Is(err, entities.ErrLocationNotFound) && !errors.Is(err, entities.ErrLocationTooOld) {
+			return nil, err
+		}
+		s.logger.Warn("Order tracking started without a current driver location",
+			zap.String("driver_id", driverID.String()),
+			zap.String("order_id", orderID.String()),
+		)
+	}
+
+	if err := s.trackingRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Order tracking started",
+		zap.String("session_id", session.ID.String()),
+		zap.String("driver_id", driverID.String()),
+		zap.String("order_id", orderID.String()),
+		zap.Time("expires_at", session.ExpiresAt),
+	)
+
+	return s.link(session), nil
+}
+
+// link выпускает токен и ссылку отслеживания
+func (s *orderTrackingService) link(session *entities.OrderTrackingSession) *entities.OrderTrackingLink {
+	token := entities.SignOrderTrackingToken([]byte(s.policy.Secret), session)
+	link := s.policy.LinkBaseURL
+	if link != "" {
+		if parsed, err := url.Parse(link); err == nil {
+			query := parsed.Query()
+			query.Set("token", token)
+			parsed.RawQuery = query.Encode()
+			link = parsed.String()
+		}
+	}
+	return &entities.OrderTrackingLink{OrderTrackingSession: session, Token: token, Link: link}
+}
+
+// StopTracking останавливает отслеживание заказа. Заказ другого водителя не найден
+func (s *orderTrackingService) StopTracking(ctx context.Context, driverID, orderID uuid.UUID) (*entities.OrderTrackingSession, error) {
+	session, err := s.trackingRepo.GetActiveByOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if session.DriverID != driverID {
+		return nil, entities.ErrOrderTrackingNotFound
+	}
+
+	if err := session.Stop(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.trackingRepo.Stop(ctx, session); err != nil {
+		return nil, err
+	}
+
+	// Отметка окончания заказа в истории нужна только при свежей точке водителя
+	if err := s.locationService.StopOrderTracking(ctx, driverID, orderID); err != nil {
+		s.logger.Warn("Failed to record order tracking stop",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("order_id", orderID.String()),
+		)
+	}
+
+	s.logger.Info("Order tracking stopped",
+		zap.String("session_id", session.ID.String()),
+		zap.String("order_id", orderID.String()),
+	)
+
+	return session, nil
+}
+
+// GetTrackedLocation проверяет токен и отслеживание и возвращает текущую точку водителя,
+// огрубленную для клиента
+func (s *orderTrackingService) GetTrackedLocation(ctx context.Context, token string) (*entities.TrackedLocation, error) {
+	if s.policy.Secret == "" {
+		return nil, entities.ErrOrderTrackingDisabled
+	}
+
+	now := time.Now()
+	sessionID, err := entities.ParseOrderTrackingToken([]byte(s.policy.Secret), token, now)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.trackingRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == entities.ErrOrderTrackingNotFound {
+			return nil, entities.ErrInvalidTrackingToken
+		}
+		return nil, err
+	}
+	if err := session.CheckActive(now); err != nil {
+		return nil, err
+	}
+
+	location, err := s.locationService.GetCurrentLocation(ctx, session.DriverID)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewTrackedLocation(session, location), nil
+}
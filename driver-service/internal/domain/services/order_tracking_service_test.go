@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOrderTrackingService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	trackingRepo := memory.NewOrderTrackingRepository()
+	locations := NewLocationService(locationRepo, driverRepo, nil, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+	service := NewOrderTrackingService(trackingRepo, driverRepo, locations, OrderTrackingPolicy{
+		Secret:      "tracking-secret",
+		LinkBaseURL: "https://track.example.com/order",
+		TTL:         time.Hour,
+	}, zap.NewNop())
+
+	driver := newTestDriver("531")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusBusy
+	require.NoError(t, driverRepo.Create(ctx, driver))
+	orderID := uuid.New()
+
+	_, err := service.StartTracking(ctx, uuid.New(), orderID, "dispatcher-1")
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	// Отслеживание начинается и без точки водителя
+	link, err := service.StartTracking(ctx, driver.ID, orderID, "dispatcher-1")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link.Link, "https://track.example.com/order?token="))
+
+	_, err = service.StartTracking(ctx, driver.ID, orderID, "dispatcher-1")
+	assert.Equal(t, entities.ErrOrderTrackingExists, err)
+
+	_, err = service.GetTrackedLocation(ctx, link.Token)
+	assert.Equal(t, entities.ErrLocationNotFound, err)
+
+	require.NoError(t, locations.UpdateLocation(ctx, entities.NewDriverLocation(driver.ID, 55.755831, 37.617673, time.Now())))
+	tracked, err := service.GetTrackedLocation(ctx, link.Token)
+	require.NoError(t, err)
+	assert.Equal(t, orderID, tracked.OrderID)
+	assert.Equal(t, 55.756, tracked.Latitude)
+
+	_, err = service.GetTrackedLocation(ctx, link.Token+"A")
+	assert.Equal(t, entities.ErrInvalidTrackingToken, err)
+
+	// Чужой водитель не останавливает отслеживание
+	_, err = service.StopTracking(ctx, uuid.New(), orderID)
+	assert.Equal(t, entities.ErrOrderTrackingNotFound, err)
+
+	session, err := service.StopTracking(ctx, driver.ID, orderID)
+	require.NoError(t, err)
+	assert.NotNil(t, session.StoppedAt)
+
+	_, err = service.GetTrackedLocation(ctx, link.Token)
+	assert.Equal(t, entities.ErrOrderTrackingInactive, err)
+	_, err = service.StopTracking(ctx, driver.ID, orderID)
+	assert.Equal(t, entities.ErrOrderTrackingNotFound, err)
+
+	// После остановки заказ можно отслеживать снова
+	_, err = service.StartTracking(ctx, driver.ID, orderID, "dispatcher-1")
+	assert.NoError(t, err)
+}
+
+func TestOrderTrackingService_Disabled(t *testing.T) {
+	service := NewOrderTrackingService(memory.NewOrderTrackingRepository(), memory.NewDriverRepository(), nil,
+		OrderTrackingPolicy{TTL: time.Hour}, zap.NewNop())
+
+	_, err := service.StartTracking(context.Background(), uuid.New(), uuid.New(), "")
+	assert.Equal(t, entities.ErrOrderTrackingDisabled, err)
+	_, err = service.GetTrackedLocation(context.Background(), "token")
+	assert.Equal(t, entities.ErrOrderTrackingDisabled, err)
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NotificationPhoneVerification шаблон SMS с кодом подтверждения телефона
+const NotificationPhoneVerification = "phone.verification"
+
+// PhoneVerificationService интерфейс для подтверждения телефона водителя кодом из SMS
+type PhoneVerificationService interface {
+	// SendCode отправляет водителю новый код подтверждения телефона
+	SendCode(ctx context.Context, driverID uuid.UUID) (*entities.PhoneVerificationSent, error)
+	// ConfirmCode проверяет код и отмечает телефон водителя подтвержденным
+	ConfirmCode(ctx context.Context, driverID uuid.UUID, code string) (*entities.Driver, error)
+}
+
+// PhoneVerificationPolicy параметры кодов подтверждения телефона
+type PhoneVerificationPolicy struct {
+	// CodeTTL сколько действует отправленный код
+	CodeTTL time.Duration
+	// ResendInterval как часто водителю можно отправлять новый код
+	ResendInterval time.Duration
+	// MaxAttempts сколько раз можно ввести один код
+	MaxAttempts int
+}
+
+// phoneVerificationService реализация PhoneVerificationService
+type phoneVerificationService struct {
+	driverRepo       repositories.DriverRepository
+	verificationRepo repositories.PhoneVerificationRepository
+	notifier         NotificationSender
+	policy           PhoneVerificationPolicy
+	logger           *zap.Logger
+}
+
+// NewPhoneVerificationService создает новый PhoneVerificationService
+func NewPhoneVerificationService(
+	driverRepo repositories.DriverRepository,
+	verificationRepo repositories.PhoneVerificationRepository,
+	notifier NotificationSender,
+	policy PhoneVerificationPolicy,
+	logger *zap.Logger,
+) PhoneVerificationService {
+	return &phoneVerificationService{
+		driverRepo:       driverRepo,
+		verificationRepo: verificationRepo,
+		notifier:         notifier,
+		policy:           policy,
+		logger:           logger,
+	}
+}
+
+// SendCode отправляет код на текущий номер водителя. Новый код заменяет прежний и сбрасывает
+// счетчик попыток, поэтому отправка ограничена интервалом ResendInterval
+func (s *phoneVerificationService) SendCode(ctx context.Context, driverID uuid.UUID) (*entities.PhoneVerificationSent, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if driver.PhoneVerifiedAt != nil {
+		return nil, entities.ErrPhoneAlreadyVerified
+	}
+
+	now := time.Now()
+	previous, err := s.verificationRepo.Get(ctx, driverID)
+	switch {
+	case err == nil:
+		// После смены номера код на новый номер отправляется без ожидания
+		if previous.Phone == driver.Phone && now.Before(previous.ResendAfter(s.policy.ResendInterval)) {
+			return nil, entities.ErrVerificationCodeRecentlySent
+		}
+	case err != entities.ErrVerificationCodeNotFound:
+		return nil, err
+	}
+
+	verification, code, err := entities.NewPhoneVerification(driverID, driver.Phone, now, s.policy.CodeTTL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.verificationRepo.Save(ctx, verification); err != nil {
+		return nil, err
+	}
+
+	if err := s.notifier.SendToDriver(ctx, driverID, NotificationPhoneVerification, map[string]interface{}{
+		"code":        code,
+		"ttl_minutes": int(s.policy.CodeTTL.Minutes()),
+	}); err != nil {
+		// Неотправленный код не должен блокировать повторную отправку
+		if deleteErr := s.verificationRepo.Delete(ctx, driverID); deleteErr != nil {
+			s.logger.Error("Failed to delete unsent verification code",
+				zap.Error(deleteErr),
+				zap.String("driver_id", driverID.String()),
+			)
+		}
+		return nil, fmt.Errorf("failed to send verification code: %w", err)
+	}
+
+	s.logger.Info("Phone verification code sent",
+		zap.String("driver_id", driverID.String()),
+		zap.Time("expires_at", verification.ExpiresAt),
+	)
+
+	return &entities.PhoneVerificationSent{
+		DriverID:    driverID,
+		ExpiresAt:   verification.ExpiresAt,
+		ResendAfter: verification.ResendAfter(s.policy.ResendInterval),
+	}, nil
+}
+
+// ConfirmCode проверяет код. Попытка учитывается до сравнения кода, поэтому параллельные
+// запросы не дают перебрать больше MaxAttempts вариантов одного кода
+func (s *phoneVerificationService) ConfirmCode(ctx context.Context, driverID uuid.UUID, code string) (*entities.Driver, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if driver.PhoneVerifiedAt != nil {
+		return nil, entities.ErrPhoneAlreadyVerified
+	}
+
+	verification, err := s.verificationRepo.Get(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	// Код отправлен на прежний номер
+	if verification.Phone != driver.Phone {
+		return nil, entities.ErrVerificationCodeNotFound
+	}
+
+	now := time.Now()
+	if verification.IsExpired(now) {
+		return nil, entities.ErrVerificationCodeExpired
+	}
+
+	attempts, err := s.verificationRepo.IncrementAttempts(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > s.policy.MaxAttempts {
+		return nil, entities.ErrTooManyVerificationAttempts
+	}
+	if !verification.Matches(code) {
+		s.logger.Warn("Invalid phone verification code",
+			zap.String("driver_id", driverID.String()),
+			zap.Int("attempts", attempts),
+		)
+		return nil, entities.ErrInvalidVerificationCode
+	}
+
+	// Номер, изменившийся после проверки, остается неподтвержденным
+	if err := s.driverRepo.SetPhoneVerified(ctx, driverID, verification.Phone, now); err != nil {
+		if err == entities.ErrDriverNotFound {
+			return nil, entities.ErrVerificationCodeNotFound
+		}
+		return nil, err
+	}
+	if err := s.verificationRepo.Delete(ctx, driverID); err != nil {
+		s.logger.Error("Failed to delete used verification code",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Driver phone verified",
+		zap.String("driver_id", driverID.String()),
+	)
+
+	verifiedAt := now
+	driver.PhoneVerifiedAt = &verifiedAt
+	return driver, nil
+}
+
+// phoneVerifiedDriverService не допускает отправку водителя на верификацию без подтвержденного
+// телефона (phone.verification_required)
+type phoneVerifiedDriverService struct {
+	DriverService
+}
+
+// NewPhoneVerifiedDriverService оборачивает DriverService требованием подтвержденного телефона
+// для перехода в pending_verification
+func NewPhoneVerifiedDriverService(next DriverService) DriverService {
+	return &phoneVerifiedDriverService{DriverService: next}
+}
+
+// ChangeDriverStatus изменяет статус; переход в pending_verification требует подтвержденного
+// телефона
+func (s *phoneVerifiedDriverService) ChangeDriverStatus(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	if status == entities.StatusPendingVerification {
+		driver, err := s.DriverService.GetDriverByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if driver.PhoneVerifiedAt == nil {
+			return entities.ErrPhoneNotVerified
+		}
+	}
+
+	return s.DriverService.ChangeDriverStatus(ctx, id, status)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// codeNotifier запоминает последний отправленный код подтверждения
+type codeNotifier struct {
+	code string
+	err  error
+}
+
+func (n *codeNotifier) SendToDriver(ctx context.Context, driverID uuid.UUID, template string, data map[string]interface{}) error {
+	if n.err != nil {
+		return n.err
+	}
+	n.code, _ = data["code"].(string)
+	return nil
+}
+
+func TestPhoneVerificationService_SendAndConfirm(t *testing.T) {
+	ctx := context.Background()
+	driverService, driverRepo, _, _ := newTestDriverService()
+	verificationRepo := memory.NewPhoneVerificationRepository()
+	notifier := &codeNotifier{}
+	service := NewPhoneVerificationService(driverRepo, verificationRepo, notifier,
+		PhoneVerificationPolicy{CodeTTL: 10 * time.Minute, ResendInterval: time.Minute, MaxAttempts: 2}, zap.NewNop())
+
+	driver, err := driverService.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+
+	sent, err := service.SendCode(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Len(t, notifier.code, 6)
+	assert.Equal(t, sent.ExpiresAt.Add(-10*time.Minute).Add(time.Minute), sent.ResendAfter)
+
+	_, err = service.SendCode(ctx, driver.ID)
+	assert.ErrorIs(t, err, entities.ErrVerificationCodeRecentlySent)
+
+	wrong := "000000"
+	if notifier.code == wrong {
+		wrong = "111111"
+	}
+	_, err = service.ConfirmCode(ctx, driver.ID, wrong)
+	assert.ErrorIs(t, err, entities.ErrInvalidVerificationCode)
+
+	confirmed, err := service.ConfirmCode(ctx, driver.ID, notifier.code)
+	require.NoError(t, err)
+	require.NotNil(t, confirmed.PhoneVerifiedAt)
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, stored.PhoneVerifiedAt)
+
+	// Использованный код удален, повторное подтверждение не требуется
+	_, err = verificationRepo.Get(ctx, driver.ID)
+	assert.ErrorIs(t, err, entities.ErrVerificationCodeNotFound)
+	_, err = service.SendCode(ctx, driver.ID)
+	assert.ErrorIs(t, err, entities.ErrPhoneAlreadyVerified)
+
+	// Смена номера сбрасывает подтверждение
+	stored.Phone = "+79009999999"
+	updated, err := driverService.UpdateDriver(ctx, stored)
+	require.NoError(t, err)
+	assert.Nil(t, updated.PhoneVerifiedAt)
+	stored, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.PhoneVerifiedAt)
+}
+
+func TestPhoneVerificationService_Limits(t *testing.T) {
+	ctx := context.Background()
+	driverService, driverRepo, _, _ := newTestDriverService()
+	verificationRepo := memory.NewPhoneVerificationRepository()
+	notifier := &codeNotifier{}
+	service := NewPhoneVerificationService(driverRepo, verificationRepo, notifier,
+		PhoneVerificationPolicy{CodeTTL: 10 * time.Minute, MaxAttempts: 1}, zap.NewNop())
+
+	driver, err := driverService.CreateDriver(ctx, newTestDriver("2"))
+	require.NoError(t, err)
+
+	_, err = service.ConfirmCode(ctx, driver.ID, "123456")
+	assert.ErrorIs(t, err, entities.ErrVerificationCodeNotFound)
+
+	// Неотправленный код не сохраняется
+	notifier.err = errors.New("sms gateway unavailable")
+	_, err = service.SendCode(ctx, driver.ID)
+	require.Error(t, err)
+	_, err = verificationRepo.Get(ctx, driver.ID)
+	assert.ErrorIs(t, err, entities.ErrVerificationCodeNotFound)
+	notifier.err = nil
+
+	// После исчерпания попыток не принимается даже верный код
+	_, err = service.SendCode(ctx, driver.ID)
+	require.NoError(t, err)
+	_, err = service.ConfirmCode(ctx, driver.ID, "not-a-code")
+	assert.ErrorIs(t, err, entities.ErrInvalidVerificationCode)
+	_, err = service.ConfirmCode(ctx, driver.ID, notifier.code)
+	assert.ErrorIs(t, err, entities.ErrTooManyVerificationAttempts)
+
+	// Новый код сбрасывает попытки; просроченный код не принимается
+	_, err = service.SendCode(ctx, driver.ID)
+	require.NoError(t, err)
+	verification, err := verificationRepo.Get(ctx, driver.ID)
+	require.NoError(t, err)
+	verification.ExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, verificationRepo.Save(ctx, verification))
+	_, err = service.ConfirmCode(ctx, driver.ID, notifier.code)
+	assert.ErrorIs(t, err, entities.ErrVerificationCodeExpired)
+}
+
+func TestPhoneVerifiedDriverService_RequiresVerifiedPhone(t *testing.T) {
+	ctx := context.Background()
+	driverService, driverRepo, _, _ := newTestDriverService()
+	service := NewPhoneVerifiedDriverService(driverService)
+
+	driver, err := service.CreateDriver(ctx, newTestDriver("3"))
+	require.NoError(t, err)
+
+	err = service.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification)
+	assert.ErrorIs(t, err, entities.ErrPhoneNotVerified)
+
+	require.NoError(t, driverRepo.SetPhoneVerified(ctx, driver.ID, driver.Phone, time.Now()))
+	require.NoError(t, service.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification))
+}
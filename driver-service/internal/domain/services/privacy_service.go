@@ -0,0 +1,260 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PrivacyService интерфейс для запросов субъекта персональных данных: выгрузки и удаления
+type PrivacyService interface {
+	ExportData(ctx context.Context, driverID uuid.UUID) (*entities.DriverDataExport, error)
+	WriteArchive(w io.Writer, export *entities.DriverDataExport) error
+	ErasePersonalData(ctx context.Context, driverID uuid.UUID, requestedBy string) (*entities.PersonalDataErasure, error)
+}
+
+// privacyService реализация PrivacyService
+type privacyService struct {
+	driverRepo   repositories.DriverRepository
+	documentRepo repositories.DocumentRepository
+	locationRepo repositories.LocationRepository
+	summaryRepo  repositories.LocationSummaryRepository
+	messageRepo  repositories.MessageRepository
+	ratingRepo   repositories.RatingRepository
+	shiftRepo    repositories.ShiftRepository
+	auditRepo    repositories.AuditRepository
+	storage      FileStorage
+	eventBus     EventPublisher
+	logger       *zap.Logger
+}
+
+// NewPrivacyService создает новый PrivacyService
+func NewPrivacyService(
+	driverRepo repositories.DriverRepository,
+	documentRepo repositories.DocumentRepository,
+	locationRepo repositories.LocationRepository,
+	summaryRepo repositories.LocationSummaryRepository,
+	messageRepo repositories.MessageRepository,
+	ratingRepo repositories.RatingRepository,
+	shiftRepo repositories.ShiftRepository,
+	auditRepo repositories.AuditRepository,
+	storage FileStorage,
+	eventBus EventPublisher,
+	logger *zap.Logger,
+) PrivacyService {
+	return &privacyService{
+		driverRepo:   driverRepo,
+		documentRepo: documentRepo,
+		locationRepo: locationRepo,
+		summaryRepo:  summaryRepo,
+		messageRepo:  messageRepo,
+		ratingRepo:   ratingRepo,
+		shiftRepo:    shiftRepo,
+		auditRepo:    auditRepo,
+		storage:      storage,
+		eventBus:     eventBus,
+		logger:       logger,
+	}
+}
+
+// ExportData собирает все данные водителя: профиль, документы, историю местоположений
+// вместе с прореженной, переписку с диспетчерами, оценки и смены
+func (s *privacyService) ExportData(ctx context.Context, driverID uuid.UUID) (*entities.DriverDataExport, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	export := &entities.DriverDataExport{
+		ExportedAt: now,
+		Driver:     driver,
+	}
+
+	if export.Documents, err = s.documentRepo.GetByDriverID(ctx, driverID); err != nil {
+		return nil, fmt.Errorf("failed to export documents: %w", err)
+	}
+	if export.Locations, err = s.locationRepo.GetByDriverIDInTimeRange(ctx, driverID, time.Time{}, now); err != nil {
+		return nil, fmt.Errorf("failed to export locations: %w", err)
+	}
+	if export.LocationSummaries, err = s.summaryRepo.ListByDriverID(ctx, driverID); err != nil {
+		return nil, fmt.Errorf("failed to export location summaries: %w", err)
+	}
+	if export.Messages, err = s.messageRepo.ListByDriverID(ctx, driverID); err != nil {
+		return nil, fmt.Errorf("failed to export messages: %w", err)
+	}
+	if export.Ratings, err = s.ratingRepo.List(ctx, &entities.RatingFilters{DriverID: &driverID}); err != nil {
+		return nil, fmt.Errorf("failed to export ratings: %w", err)
+	}
+	if export.RatingStats, err = s.ratingRepo.GetStats(ctx, driverID); err != nil {
+		return nil, fmt.Errorf("failed to export rating stats: %w", err)
+	}
+	if export.Shifts, err = s.shiftRepo.List(ctx, &entities.ShiftFilters{DriverID: &driverID}); err != nil {
+		return nil, fmt.Errorf("failed to export shifts: %w", err)
+	}
+
+	s.logger.Info("Driver data exported",
+		zap.String("driver_id", driverID.String()),
+		zap.Int("documents", len(export.Documents)),
+		zap.Int("locations", len(export.Locations)),
+		zap.Int("location_summaries", len(export.LocationSummaries)),
+		zap.Int("messages", len(export.Messages)),
+		zap.Int("ratings", len(export.Ratings)),
+		zap.Int("shifts", len(export.Shifts)),
+	)
+
+	return export, nil
+}
+
+// WriteArchive записывает выгрузку в ZIP-архив: по JSON-файлу на раздел
+func (s *privacyService) WriteArchive(w io.Writer, export *entities.DriverDataExport) error {
+	sections := export.Sections()
+	names := make([]string, 0, len(sections))
+	for name := range sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	archive := zip.NewWriter(w)
+	for _, name := range names {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create archive entry %s: %w", name, err)
+		}
+
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(sections[name]); err != nil {
+			return fmt.Errorf("failed to write archive entry %s: %w", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finalize archive: %w", err)
+	}
+	return nil
+}
+
+// ErasePersonalData обезличивает водителя: удаляет файлы и номера документов, историю
+// местоположений вместе с прореженной, переписку, комментарии к оценкам и координаты смен, затем анонимизирует профиль
+// и помечает его удаленным. Рейтинг, число поездок и итоги смен сохраняются для статистики
+func (s *privacyService) ErasePersonalData(ctx context.Context, driverID uuid.UUID, requestedBy string) (*entities.PersonalDataErasure, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Водитель на линии: данные смены и заказа еще нужны для расчетов
+	if driver.Status == entities.StatusOnShift || driver.Status == entities.StatusBusy {
+		return nil, entities.ErrErasureBlocked
+	}
+	if _, err := s.shiftRepo.GetActiveByDriverID(ctx, driverID); err == nil {
+		return nil, entities.ErrErasureBlocked
+	} else if err != entities.ErrShiftNotFound {
+		return nil, fmt.Errorf("failed to check active shift: %w", err)
+	}
+
+	erasure := &entities.PersonalDataErasure{
+		DriverID:    driverID,
+		RequestedBy: requestedBy,
+	}
+
+	documents, err := s.documentRepo.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver documents: %w", err)
+	}
+	for _, document := range documents {
+		if document.FileURL == "" {
+			continue
+		}
+		if err := s.storage.Delete(ctx, document.FileURL); err != nil {
+			return nil, fmt.Errorf("failed to delete document file: %w", err)
+		}
+		erasure.FilesDeleted++
+	}
+
+	if erasure.DocumentsErased, err = s.documentRepo.ErasePersonalData(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if erasure.LocationsDeleted, err = s.locationRepo.DeleteByDriverID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if erasure.LocationSummariesDeleted, err = s.summaryRepo.DeleteByDriverID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if erasure.MessagesDeleted, err = s.messageRepo.DeleteByDriverID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if erasure.RatingsAnonymized, err = s.ratingRepo.EraseComments(ctx, driverID); err != nil {
+		return nil, err
+	}
+	if erasure.ShiftsAnonymized, err = s.shiftRepo.EraseLocations(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	// Профиль обезличивается последним: при сбое выше запрос можно повторить
+	erasure.ErasedAt = time.Now()
+	driver.Anonymize(erasure.ErasedAt)
+	driver.UpdatedAt = erasure.ErasedAt
+	if err := s.driverRepo.Update(ctx, driver); err != nil {
+		return nil, fmt.Errorf("failed to anonymize driver: %w", err)
+	}
+	if err := s.driverRepo.SoftDelete(ctx, driverID); err != nil {
+		return nil, fmt.Errorf("failed to delete driver: %w", err)
+	}
+
+	s.record(ctx, erasure)
+
+	if s.eventBus != nil {
+		if err := s.eventBus.PublishDriverEvent(ctx, eventPersonalDataErased, driverID, erasure); err != nil {
+			s.logger.Error("Failed to publish personal data erased event", zap.Error(err))
+		}
+	}
+
+	s.logger.Info("Driver personal data erased",
+		zap.String("driver_id", driverID.String()),
+		zap.String("requested_by", requestedBy),
+		zap.Int("documents", erasure.DocumentsErased),
+		zap.Int("files", erasure.FilesDeleted),
+		zap.Int("locations", erasure.LocationsDeleted),
+	)
+
+	return erasure, nil
+}
+
+// record сохраняет удаление в журнал аудита; ошибка журнала не отменяет удаление
+func (s *privacyService) record(ctx context.Context, erasure *entities.PersonalDataErasure) {
+	entry := entities.NewAuditEntry(entities.AuditEventPersonalDataErasure, "erase", "completed")
+	entry.DriverID = &erasure.DriverID
+	entry.CreatedAt = erasure.ErasedAt
+	entry.AttachActor(ctx)
+	entry.Details["requested_by"] = erasure.RequestedBy
+	entry.Details["documents_erased"] = erasure.DocumentsErased
+	entry.Details["files_deleted"] = erasure.FilesDeleted
+	entry.Details["locations_deleted"] = erasure.LocationsDeleted
+	entry.Details["location_summaries_deleted"] = erasure.LocationSummariesDeleted
+	entry.Details["messages_deleted"] = erasure.MessagesDeleted
+	entry.Details["ratings_anonymized"] = erasure.RatingsAnonymized
+	entry.Details["shifts_anonymized"] = erasure.ShiftsAnonymized
+
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record personal data erasure audit entry",
+			zap.Error(err),
+			zap.String("driver_id", erasure.DriverID.String()),
+		)
+	}
+}
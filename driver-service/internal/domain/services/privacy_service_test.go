@@ -0,0 +1,189 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type privacyFixture struct {
+	service      PrivacyService
+	driverRepo   *memory.DriverRepository
+	documentRepo *memory.DocumentRepository
+	locationRepo *memory.LocationRepository
+	summaryRepo  *memory.LocationSummaryRepository
+	messageRepo  *memory.MessageRepository
+	ratingRepo   *memory.RatingRepository
+	shiftRepo    *memory.ShiftRepository
+	auditRepo    *memory.AuditRepository
+	storage      *fakeFileStorage
+	events       *recordingEventPublisher
+}
+
+func newPrivacyFixture() *privacyFixture {
+	f := &privacyFixture{
+		driverRepo:   memory.NewDriverRepository(),
+		documentRepo: memory.NewDocumentRepository(),
+		locationRepo: memory.NewLocationRepository(),
+		summaryRepo:  memory.NewLocationSummaryRepository(),
+		messageRepo:  memory.NewMessageRepository(),
+		ratingRepo:   memory.NewRatingRepository(),
+		shiftRepo:    memory.NewShiftRepository(),
+		auditRepo:    memory.NewAuditRepository(),
+		storage:      &fakeFileStorage{files: make(map[string][]byte)},
+		events:       &recordingEventPublisher{},
+	}
+	f.service = NewPrivacyService(f.driverRepo, f.documentRepo, f.locationRepo, f.summaryRepo, f.messageRepo, f.ratingRepo,
+		f.shiftRepo, f.auditRepo, f.storage, f.events, zap.NewNop())
+	return f
+}
+
+// addDriver создает водителя с документом, точками маршрута, прореженной историей,
+// сообщением диспетчеру, оценкой и завершенной сменой
+func (f *privacyFixture) addDriver(t *testing.T) *entities.Driver {
+	ctx := context.Background()
+	driver := newTestDriver("1")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusAvailable
+	driver.TotalTrips = 42
+	driver.Metadata = entities.Metadata{entities.DriverMetaCity: "Москва"}
+	require.NoError(t, f.driverRepo.Create(ctx, driver))
+
+	url := "https://receipts.example.com/license.pdf"
+	f.storage.files["license.pdf"] = []byte("%PDF")
+	license := entities.NewDriverDocument(driver.ID, entities.DocumentTypeDriverLicense, driver.LicenseNumber,
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(5, 0, 0), url)
+	require.NoError(t, f.documentRepo.Create(ctx, license))
+
+	for i := 0; i < 3; i++ {
+		location := entities.NewDriverLocation(driver.ID, 55.75+float64(i)*0.001, 37.61, time.Now().Add(-time.Duration(i)*time.Minute))
+		saveLocation(t, f.locationRepo, location)
+	}
+	old := entities.NewDriverLocation(driver.ID, 55.7, 37.6, time.Now().AddDate(0, -2, 0))
+	require.NoError(t, f.summaryRepo.Upsert(ctx, entities.DownsampleLocations([]*entities.DriverLocation{old},
+		entities.LocationRetentionTier{Name: "5m", Interval: 5 * time.Minute})))
+
+	require.NoError(t, f.messageRepo.Create(ctx, entities.NewDriverMessage(driver.ID, entities.MessageSenderDriver, "",
+		&entities.SendMessageRequest{Kind: entities.MessageKindText, Text: "Стою у второго подъезда"})))
+
+	rating := entities.NewDriverRating(driver.ID, 5, entities.RatingTypeCustomer)
+	comment := "Водитель Иван, телефон в машине забыл"
+	rating.Comment = &comment
+	require.NoError(t, f.ratingRepo.Create(ctx, rating))
+
+	shift := entities.NewDriverShift(driver.ID, nil, entities.NewDriverLocation(driver.ID, 55.75, 37.61, time.Now()))
+	shift.End(entities.NewDriverLocation(driver.ID, 55.76, 37.62, time.Now()))
+	require.NoError(t, f.shiftRepo.Create(ctx, shift))
+	return driver
+}
+
+func TestPrivacyService_ExportData(t *testing.T) {
+	ctx := context.Background()
+	f := newPrivacyFixture()
+	driver := f.addDriver(t)
+
+	export, err := f.service.ExportData(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, driver.ID, export.Driver.ID)
+	assert.Len(t, export.Documents, 1)
+	assert.Len(t, export.Locations, 3)
+	assert.Len(t, export.LocationSummaries, 1)
+	assert.Len(t, export.Messages, 1)
+	assert.Len(t, export.Ratings, 1)
+	require.NotNil(t, export.RatingStats)
+	assert.Equal(t, 1, export.RatingStats.TotalRatings)
+	assert.Len(t, export.Shifts, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, f.service.WriteArchive(&buf, export))
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"documents.json", "location_summaries.json", "locations.json", "messages.json", "profile.json", "ratings.json", "shifts.json"}, names)
+
+	_, err = f.service.ExportData(ctx, uuid.New())
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
+
+func TestPrivacyService_ErasePersonalData(t *testing.T) {
+	ctx := context.Background()
+	f := newPrivacyFixture()
+	driver := f.addDriver(t)
+
+	erasure, err := f.service.ErasePersonalData(ctx, driver.ID, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, erasure.DocumentsErased)
+	assert.Equal(t, 1, erasure.FilesDeleted)
+	assert.Equal(t, 3, erasure.LocationsDeleted)
+	assert.Equal(t, 1, erasure.LocationSummariesDeleted)
+	assert.Equal(t, 1, erasure.MessagesDeleted)
+	assert.Equal(t, 1, erasure.RatingsAnonymized)
+	assert.Equal(t, 1, erasure.ShiftsAnonymized)
+	assert.Empty(t, f.storage.files)
+	assert.True(t, f.events.has("driver.personal_data.erased"))
+
+	_, err = f.driverRepo.GetByID(ctx, driver.ID)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+	_, err = f.driverRepo.GetByPhone(ctx, driver.Phone)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	documents, err := f.documentRepo.GetByDriverID(ctx, driver.ID)
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	assert.Empty(t, documents[0].DocumentNumber)
+	assert.Empty(t, documents[0].FileURL)
+	assert.Equal(t, entities.DocumentTypeDriverLicense, documents[0].DocumentType)
+
+	locations, err := f.locationRepo.GetByDriverIDInTimeRange(ctx, driver.ID, time.Time{}, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, locations)
+
+	// Оценки и итоги смен остаются для статистики без персональных данных
+	ratings, err := f.ratingRepo.List(ctx, &entities.RatingFilters{DriverID: &driver.ID})
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+	assert.Equal(t, 5, ratings[0].Rating)
+	assert.Nil(t, ratings[0].Comment)
+
+	shifts, err := f.shiftRepo.List(ctx, &entities.ShiftFilters{DriverID: &driver.ID})
+	require.NoError(t, err)
+	require.Len(t, shifts, 1)
+	assert.Nil(t, shifts[0].GetStartLocation())
+	assert.Nil(t, shifts[0].GetEndLocation())
+
+	entries, err := f.auditRepo.ListByDriver(ctx, driver.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, entities.AuditEventPersonalDataErasure, entries[0].Event)
+	assert.Equal(t, driver.ID.String(), entries[0].Details["requested_by"])
+}
+
+func TestPrivacyService_EraseBlockedOnShift(t *testing.T) {
+	ctx := context.Background()
+	f := newPrivacyFixture()
+	driver := f.addDriver(t)
+
+	shift := entities.NewDriverShift(driver.ID, nil, nil)
+	require.NoError(t, f.shiftRepo.Create(ctx, shift))
+
+	_, err := f.service.ErasePersonalData(ctx, driver.ID, "admin-1")
+	assert.Equal(t, entities.ErrErasureBlocked, err)
+
+	fetched, err := f.driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, driver.Phone, fetched.Phone)
+	assert.Len(t, f.storage.files, 1)
+}
@@ -0,0 +1,240 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ProfileChangeService интерфейс для заявок на изменение юридически значимых полей профиля:
+// ФИО, серии и номера паспорта и номера водительского удостоверения
+type ProfileChangeService interface {
+	// Submit сохраняет заявку водителя; профиль не меняется до одобрения
+	Submit(ctx context.Context, driverID uuid.UUID, req *entities.ProfileChangeRequest) (*entities.ProfileChange, error)
+	// ListByDriver возвращает заявки водителя, новые первыми
+	ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error)
+	// ListPending возвращает заявки, ожидающие решения, старые первыми
+	ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error)
+	// Approve одобряет заявку и вносит ее значения в профиль водителя
+	Approve(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error)
+	// Reject отклоняет заявку с причиной в комментарии
+	Reject(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error)
+}
+
+// profileChangeService реализация ProfileChangeService
+type profileChangeService struct {
+	changeRepo repositories.ProfileChangeRepository
+	driverRepo repositories.DriverRepository
+	auditRepo  repositories.AuditRepository
+	eventBus   EventPublisher
+	logger     *zap.Logger
+}
+
+// NewProfileChangeService создает новый ProfileChangeService
+func NewProfileChangeService(
+	changeRepo repositories.ProfileChangeRepository,
+	driverRepo repositories.DriverRepository,
+	auditRepo repositories.AuditRepository,
+	eventBus EventPublisher,
+	logger *zap.Logger,
+) ProfileChangeService {
+	return &profileChangeService{
+		changeRepo: changeRepo,
+		driverRepo: driverRepo,
+		auditRepo:  auditRepo,
+		eventBus:   eventBus,
+		logger:     logger,
+	}
+}
+
+// Submit проверяет значения заявки против текущего профиля и сохраняет ее. Новые значения
+// хранятся только в заявке: в журнал аудита и событие попадают имена полей
+func (s *profileChangeService) Submit(ctx context.Context, driverID uuid.UUID, req *entities.ProfileChangeRequest) (*entities.ProfileChange, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Validate(driver); err != nil {
+		return nil, err
+	}
+
+	actor, _ := entities.AuditActorFromContext(ctx)
+	change := entities.NewProfileChange(driverID, req, actor, time.Now())
+	if err := s.changeRepo.Create(ctx, change); err != nil {
+		return nil, err
+	}
+
+	fields := strings.Join(change.Fields(), ",")
+	s.record(ctx, s.newEntry(ctx, "request", driver, change))
+	s.publish(ctx, eventProfileChangeRequested, driverID, map[string]interface{}{
+		"change_id": change.ID.String(),
+		"fields":    fields,
+	})
+
+	s.logger.Info("Profile change requested",
+		zap.String("driver_id", driverID.String()),
+		zap.String("change_id", change.ID.String()),
+		zap.String("fields", fields),
+	)
+
+	return change, nil
+}
+
+// ListByDriver получает заявки существующего водителя
+func (s *profileChangeService) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	return s.changeRepo.ListByDriver(ctx, driverID, limit, offset)
+}
+
+// ListPending получает очередь заявок на рассмотрение
+func (s *profileChangeService) ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error) {
+	return s.changeRepo.ListPending(ctx, limit, offset)
+}
+
+// Approve повторно проверяет значения заявки против текущего профиля: с момента подачи
+// профиль мог измениться, а номер удостоверения — достаться другому водителю. Журнал аудита
+// получает измененные поля до и после без значений
+func (s *profileChangeService) Approve(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error) {
+	if err := req.Validate(false); err != nil {
+		return nil, err
+	}
+
+	change, err := s.pendingChange(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverRepo.GetByID(ctx, change.DriverID)
+	if err != nil {
+		return nil, err
+	}
+	before := *driver
+	if err := change.Apply(driver); err != nil {
+		return nil, err
+	}
+	if driver.LicenseNumber != before.LicenseNumber {
+		exists, err := s.driverRepo.Exists(ctx, "", "", driver.LicenseNumber)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, entities.ErrDriverExists
+		}
+	}
+
+	now := time.Now()
+	driver.UpdatedAt = now
+	change.Resolve(entities.ProfileChangeStatusApproved, req.ReviewerID, req.Comment, now)
+	if err := s.changeRepo.Approve(ctx, change, driver); err != nil {
+		return nil, err
+	}
+
+	entry := s.newEntry(ctx, "approve", driver, change)
+	if err := entry.SetChanges(&before, driver); err != nil {
+		s.logger.Error("Failed to snapshot profile change", zap.Error(err))
+	}
+	s.record(ctx, entry)
+	s.publish(ctx, eventProfileChangeApproved, change.DriverID, map[string]interface{}{
+		"change_id":   change.ID.String(),
+		"fields":      strings.Join(change.Fields(), ","),
+		"reviewed_by": req.ReviewerID,
+	})
+
+	s.logger.Info("Profile change approved",
+		zap.String("driver_id", change.DriverID.String()),
+		zap.String("change_id", change.ID.String()),
+		zap.String("reviewed_by", req.ReviewerID),
+	)
+
+	return change, nil
+}
+
+// Reject отклоняет заявку; профиль водителя не меняется
+func (s *profileChangeService) Reject(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error) {
+	if err := req.Validate(true); err != nil {
+		return nil, err
+	}
+
+	change, err := s.pendingChange(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	driver, err := s.driverRepo.GetByID(ctx, change.DriverID)
+	if err != nil {
+		return nil, err
+	}
+
+	change.Resolve(entities.ProfileChangeStatusRejected, req.ReviewerID, req.Comment, time.Now())
+	if err := s.changeRepo.Reject(ctx, change); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, s.newEntry(ctx, "reject", driver, change))
+	s.publish(ctx, eventProfileChangeRejected, change.DriverID, map[string]interface{}{
+		"change_id":   change.ID.String(),
+		"fields":      strings.Join(change.Fields(), ","),
+		"reviewed_by": req.ReviewerID,
+		"comment":     req.Comment,
+	})
+
+	s.logger.Info("Profile change rejected",
+		zap.String("driver_id", change.DriverID.String()),
+		zap.String("change_id", change.ID.String()),
+		zap.String("reviewed_by", req.ReviewerID),
+	)
+
+	return change, nil
+}
+
+// pendingChange получает заявку, ожидающую решения
+func (s *profileChangeService) pendingChange(ctx context.Context, changeID uuid.UUID) (*entities.ProfileChange, error) {
+	change, err := s.changeRepo.GetByID(ctx, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if !change.IsPending() {
+		return nil, entities.ErrProfileChangeResolved
+	}
+	return change, nil
+}
+
+// newEntry создает выполненную запись журнала по заявке с автором из контекста
+func (s *profileChangeService) newEntry(ctx context.Context, action string, driver *entities.Driver, change *entities.ProfileChange) *entities.AuditEntry {
+	entry := entities.NewAuditEntry(entities.AuditEventProfileChange, action, "completed")
+	entry.DriverID = &driver.ID
+	entry.FleetID = driver.FleetID
+	entry.AttachActor(ctx)
+	entry.Details["change_id"] = change.ID.String()
+	entry.Details["fields"] = strings.Join(change.Fields(), ",")
+	return entry
+}
+
+// record сохраняет запись журнала; ошибка журнала не отменяет сохраненное решение
+func (s *profileChangeService) record(ctx context.Context, entry *entities.AuditEntry) {
+	if err := s.auditRepo.Create(ctx, entry); err != nil {
+		s.logger.Error("Failed to record profile change audit entry",
+			zap.Error(err),
+			zap.String("action", entry.Action),
+		)
+	}
+}
+
+// publish публикует событие по заявке; ошибка публикации не отменяет решение
+func (s *profileChangeService) publish(ctx context.Context, event string, driverID uuid.UUID, data map[string]interface{}) {
+	if err := s.eventBus.PublishDriverEvent(ctx, event, driverID, data); err != nil {
+		s.logger.Error("Failed to publish profile change event",
+			zap.Error(err),
+			zap.String("event", event),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+}
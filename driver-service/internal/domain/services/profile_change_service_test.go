@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfileChangeService_ApproveAppliesChanges(t *testing.T) {
+	drivers, driverRepo, _, events := newTestDriverService()
+	auditRepo := memory.NewAuditRepository()
+	service := NewProfileChangeService(memory.NewProfileChangeRepository(driverRepo), driverRepo, auditRepo, events, zap.NewNop())
+
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: "driver-1", Roles: "driver"})
+	driver, err := drivers.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+
+	change, err := service.Submit(ctx, driver.ID, &entities.ProfileChangeRequest{
+		Changes: map[string]string{
+			"last_name":      " Петрова ",
+			"first_name":     driver.FirstName,
+			"license_number": "LIC-NEW",
+		},
+		Comment: "Смена фамилии после брака",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ProfileChangeStatusPending, change.Status)
+	assert.Equal(t, []string{"last_name", "license_number"}, change.Fields(), "unchanged values are dropped")
+	require.NotNil(t, change.RequestedBy)
+	assert.Equal(t, "driver-1", *change.RequestedBy)
+	assert.True(t, events.has(eventProfileChangeRequested))
+
+	// До одобрения профиль не меняется
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Тестовый", stored.LastName)
+
+	_, err = service.Submit(ctx, driver.ID, &entities.ProfileChangeRequest{Changes: map[string]string{"first_name": "Петр"}})
+	assert.Equal(t, entities.ErrProfileChangePending, err)
+
+	pending, err := service.ListPending(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, change.ID, pending[0].ID)
+
+	adminCtx := entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: "admin-42", Roles: "admin"})
+	_, err = service.Approve(adminCtx, change.ID, &entities.ProfileChangeReviewRequest{})
+	assert.Equal(t, entities.ErrInvalidProfileChange, err, "reviewer is required")
+
+	approved, err := service.Approve(adminCtx, change.ID, &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ProfileChangeStatusApproved, approved.Status)
+	require.NotNil(t, approved.ReviewedBy)
+	assert.Equal(t, "admin-42", *approved.ReviewedBy)
+	assert.True(t, events.has(eventProfileChangeApproved))
+
+	stored, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Петрова", stored.LastName)
+	assert.Equal(t, "LIC-NEW", stored.LicenseNumber)
+	assert.Equal(t, driver.FirstName, stored.FirstName)
+
+	_, err = service.Approve(adminCtx, change.ID, &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42"})
+	assert.Equal(t, entities.ErrProfileChangeResolved, err)
+
+	pending, err = service.ListPending(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	// Журнал хранит факт изменения полей и автора решения, но не значения
+	event := entities.AuditEventProfileChange
+	entries, err := auditRepo.List(ctx, &entities.AuditFilters{DriverID: &driver.ID, Event: &event, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, entry := range entries {
+		assert.Equal(t, change.ID.String(), entry.Details["change_id"])
+		if entry.Action != "approve" {
+			continue
+		}
+		require.NotNil(t, entry.Actor)
+		assert.Equal(t, "admin-42", *entry.Actor)
+		assert.Equal(t, entities.AuditRedacted, entry.After["last_name"])
+		assert.Equal(t, entities.AuditRedacted, entry.After["license_number"])
+		assert.NotContains(t, entry.After, "first_name")
+	}
+}
+
+func TestProfileChangeService_RejectAndValidation(t *testing.T) {
+	drivers, driverRepo, _, events := newTestDriverService()
+	service := NewProfileChangeService(memory.NewProfileChangeRepository(driverRepo), driverRepo, memory.NewAuditRepository(), events, zap.NewNop())
+	ctx := context.Background()
+
+	driver, err := drivers.CreateDriver(ctx, newTestDriver("1"))
+	require.NoError(t, err)
+	other, err := drivers.CreateDriver(ctx, newTestDriver("2"))
+	require.NoError(t, err)
+
+	for _, changes := range []map[string]string{
+		{},
+		{"email": "new@example.com"},
+		{"last_name": driver.LastName},
+		{"passport_number": "  "},
+	} {
+		_, err = service.Submit(ctx, driver.ID, &entities.ProfileChangeRequest{Changes: changes})
+		assert.Error(t, err, "changes %v", changes)
+	}
+	_, err = service.Submit(ctx, uuid.New(), &entities.ProfileChangeRequest{Changes: map[string]string{"last_name": "Петрова"}})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	change, err := service.Submit(ctx, driver.ID, &entities.ProfileChangeRequest{Changes: map[string]string{"first_name": "Петр"}})
+	require.NoError(t, err)
+
+	_, err = service.Reject(ctx, change.ID, &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42"})
+	assert.Equal(t, entities.ErrInvalidProfileChange, err, "rejection requires a comment")
+
+	rejected, err := service.Reject(ctx, change.ID, &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42", Comment: "Имя не совпадает с паспортом"})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ProfileChangeStatusRejected, rejected.Status)
+	assert.True(t, events.has(eventProfileChangeRejected))
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, driver.FirstName, stored.FirstName)
+
+	// После решения водитель может подать новую заявку; занятый номер удостоверения не одобряется
+	change, err = service.Submit(ctx, driver.ID, &entities.ProfileChangeRequest{Changes: map[string]string{"license_number": other.LicenseNumber}})
+	require.NoError(t, err)
+	_, err = service.Approve(ctx, change.ID, &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42"})
+	assert.Equal(t, entities.ErrDriverExists, err)
+
+	history, err := service.ListByDriver(ctx, driver.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, change.ID, history[0].ID, "newest first")
+	assert.True(t, history[0].IsPending())
+
+	_, err = service.Approve(ctx, uuid.New(), &entities.ProfileChangeReviewRequest{ReviewerID: "admin-42"})
+	assert.Equal(t, entities.ErrProfileChangeNotFound, err)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// NotificationProfileIncomplete шаблон напоминания о незаполненном профиле
+const NotificationProfileIncomplete = "profile.incomplete"
+
+// profileNudgedAtKey ключ метаданных водителя с временем последнего напоминания о профиле
+const profileNudgedAtKey = "profile_nudged_at"
+
+// nudgeStatuses статусы водителей, которые еще не вышли на линию
+var nudgeStatuses = []entities.Status{
+	entities.StatusRegistered,
+	entities.StatusRejected,
+	entities.StatusPendingVerification,
+	entities.StatusVerified,
+}
+
+// ProfileService интерфейс для оценки заполненности профиля водителя
+type ProfileService interface {
+	GetCompleteness(ctx context.Context, driverID uuid.UUID) (*entities.ProfileCompleteness, error)
+	SendProfileNudges(ctx context.Context) (int, error)
+}
+
+// profileService реализация ProfileService
+type profileService struct {
+	driverRepo    repositories.DriverRepository
+	documentRepo  repositories.DocumentRepository
+	notifier      NotificationSender
+	nudgeInterval time.Duration
+	logger        *zap.Logger
+}
+
+// NewProfileService создает новый ProfileService.
+// nudgeInterval задает минимальный интервал между напоминаниями одному водителю.
+func NewProfileService(
+	driverRepo repositories.DriverRepository,
+	documentRepo repositories.DocumentRepository,
+	notifier NotificationSender,
+	nudgeInterval time.Duration,
+	logger *zap.Logger,
+) ProfileService {
+	return &profileService{
+		driverRepo:    driverRepo,
+		documentRepo:  documentRepo,
+		notifier:      notifier,
+		nudgeInterval: nudgeInterval,
+		logger:        logger,
+	}
+}
+
+// GetCompleteness возвращает оценку заполненности профиля водителя
+func (s *profileService) GetCompleteness(ctx context.Context, driverID uuid.UUID) (*entities.ProfileCompleteness, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.evaluate(ctx, driver)
+}
+
+// SendProfileNudges напоминает водителям, не вышедшим на линию, о незаполненных элементах профиля
+func (s *profileService) SendProfileNudges(ctx context.Context) (int, error) {
+	drivers, err := s.driverRepo.List(ctx, &entities.DriverFilters{Status: nudgeStatuses})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list drivers with incomplete profile: %w", err)
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, driver := range drivers {
+		if nudgedAt, ok := lastProfileNudge(driver); ok && now.Sub(nudgedAt) < s.nudgeInterval {
+			continue
+		}
+
+		completeness, err := s.evaluate(ctx, driver)
+		if err != nil {
+			s.logger.Error("Failed to evaluate driver profile",
+				zap.Error(err),
+				zap.String("driver_id", driver.ID.String()),
+			)
+			continue
+		}
+
+		// Напоминаем только о том, что нужно для ближайшего этапа
+		if completeness.NextStage == nil {
+			continue
+		}
+		missing := make([]string, 0, len(completeness.Missing))
+		for _, item := range completeness.Missing {
+			if item.Stage == *completeness.NextStage {
+				missing = append(missing, item.Item)
+			}
+		}
+
+		data := map[string]interface{}{
+			"stage":   string(*completeness.NextStage),
+			"score":   completeness.Score,
+			"missing": missing,
+		}
+
+		if err := s.notifier.SendToDriver(ctx, driver.ID, NotificationProfileIncomplete, data); err != nil {
+			s.logger.Error("Failed to send profile nudge",
+				zap.Error(err),
+				zap.String("driver_id", driver.ID.String()),
+			)
+			continue
+		}
+
+		if driver.Metadata == nil {
+			driver.Metadata = make(entities.Metadata)
+		}
+		driver.Metadata[profileNudgedAtKey] = now.UTC().Format(time.RFC3339)
+		if err := s.driverRepo.Update(ctx, driver); err != nil {
+			s.logger.Error("Failed to mark profile nudge as sent",
+				zap.Error(err),
+				zap.String("driver_id", driver.ID.String()),
+			)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		s.logger.Info("Profile nudges sent", zap.Int("count", sent))
+	}
+
+	return sent, nil
+}
+
+// evaluate оценивает профиль водителя с учетом его документов
+func (s *profileService) evaluate(ctx context.Context, driver *entities.Driver) (*entities.ProfileCompleteness, error) {
+	documents, err := s.documentRepo.GetByDriverID(ctx, driver.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get driver documents: %w", err)
+	}
+
+	return entities.EvaluateProfile(driver, documents), nil
+}
+
+// lastProfileNudge возвращает время последнего напоминания о профиле из метаданных водителя
+func lastProfileNudge(driver *entities.Driver) (time.Time, bool) {
+	raw, ok := driver.Metadata[profileNudgedAtKey].(string)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	nudgedAt, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return nudgedAt, true
+}
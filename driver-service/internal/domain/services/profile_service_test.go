@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestProfileService_ProgressiveValidation(t *testing.T) {
+	ctx := context.Background()
+	driverService, driverRepo, documentRepo, _ := newTestDriverService()
+	profiles := NewProfileService(driverRepo, documentRepo, &recordingNotifier{}, time.Hour, zap.NewNop())
+
+	// Для регистрации достаточно телефона и имени
+	driver, err := driverService.CreateDriver(ctx, &entities.Driver{
+		Phone:     "+79000000301",
+		FirstName: "Петр",
+		LastName:  "Минимальный",
+	})
+	require.NoError(t, err)
+
+	// Второй водитель без email и лицензии не конфликтует с первым
+	_, err = driverService.CreateDriver(ctx, &entities.Driver{
+		Phone:     "+79000000302",
+		FirstName: "Павел",
+		LastName:  "Минимальный",
+	})
+	require.NoError(t, err)
+
+	completeness, err := profiles.GetCompleteness(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ProfileStageRegistration, completeness.CurrentStage)
+	require.NotNil(t, completeness.CompletedStage)
+	assert.Equal(t, entities.ProfileStageRegistration, *completeness.CompletedStage)
+	require.NotNil(t, completeness.NextStage)
+	assert.Equal(t, entities.ProfileStageVerification, *completeness.NextStage)
+	assert.Less(t, completeness.Score, 50)
+
+	err = driverService.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification)
+	assert.Equal(t, entities.ErrProfileIncomplete, err)
+
+	full := newTestDriver("301")
+	driver.Email = full.Email
+	driver.BirthDate = full.BirthDate
+	driver.PassportSeries = full.PassportSeries
+	driver.PassportNumber = full.PassportNumber
+	driver.LicenseNumber = full.LicenseNumber
+	driver.LicenseExpiry = full.LicenseExpiry
+	_, err = driverService.UpdateDriver(ctx, driver)
+	require.NoError(t, err)
+	require.NoError(t, driverService.ChangeDriverStatus(ctx, driver.ID, entities.StatusPendingVerification))
+
+	// Уже указанный номер лицензии нельзя заменить через обновление профиля
+	driver.LicenseNumber = "LIC-OTHER"
+	_, err = driverService.UpdateDriver(ctx, driver)
+	assert.Equal(t, entities.ErrInvalidLicense, err)
+
+	completeness, err = profiles.GetCompleteness(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.True(t, completeness.IsStageComplete(entities.ProfileStageVerification))
+	assert.False(t, completeness.IsStageComplete(entities.ProfileStageActivation))
+	for _, item := range completeness.Missing {
+		assert.Equal(t, entities.ProfileStageActivation, item.Stage)
+	}
+}
+
+func TestProfileService_SendProfileNudges(t *testing.T) {
+	ctx := context.Background()
+	driverService, driverRepo, documentRepo, _ := newTestDriverService()
+	notifier := &recordingNotifier{}
+	profiles := NewProfileService(driverRepo, documentRepo, notifier, 24*time.Hour, zap.NewNop())
+
+	_, err := driverService.CreateDriver(ctx, &entities.Driver{
+		Phone:     "+79000000303",
+		FirstName: "Семен",
+		LastName:  "Неполный",
+	})
+	require.NoError(t, err)
+
+	active, err := driverService.CreateDriver(ctx, newTestDriver("304"))
+	require.NoError(t, err)
+	require.NoError(t, driverRepo.UpdateStatus(ctx, active.ID, entities.StatusAvailable))
+
+	sent, err := profiles.SendProfileNudges(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent, "drivers already on the line are not nudged")
+	assert.Equal(t, []string{NotificationProfileIncomplete}, notifier.templates)
+
+	// Повторное напоминание не отправляется раньше интервала
+	sent, err = profiles.SendProfileNudges(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, sent)
+}
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ratingRecomputePageSize сколько водителей читается за раз при пересчете рейтингов
+const ratingRecomputePageSize = 500
+
+// RatingPolicy правила приема оценок и расчета рейтинга водителя
+type RatingPolicy struct {
+	// MaxPerCustomerPerDay сколько оценок клиент может поставить за сутки; 0 — без ограничения
+	MaxPerCustomerPerDay int
+	// SuspiciousMinRatings сколько оценок 1 подряд от одного клиента за SuspiciousWindow
+	// делают его оценки подозрительными; 0 — не проверять
+	SuspiciousMinRatings int
+	SuspiciousWindow     time.Duration
+	// DecayHalfLife за сколько вес оценки в рейтинге водителя уменьшается вдвое; 0 — простое среднее
+	DecayHalfLife time.Duration
+}
+
+// RatingService интерфейс для работы с оценками водителей
+type RatingService interface {
+	AddRating(ctx context.Context, driverID uuid.UUID, req *entities.RatingRequest) (*entities.DriverRating, error)
+	ListRatings(ctx context.Context, filters *entities.RatingFilters) ([]*entities.DriverRating, error)
+	CountRatings(ctx context.Context, filters *entities.RatingFilters) (int, error)
+	GetRatingStats(ctx context.Context, driverID uuid.UUID) (*entities.RatingStats, error)
+	// VerifyRating подтверждает оценку водителя; рейтинг считается только по подтвержденным оценкам
+	VerifyRating(ctx context.Context, driverID, ratingID uuid.UUID) (*entities.DriverRating, error)
+	// RecomputeRatings пересчитывает рейтинг всех водителей с учетом давности оценок
+	// и возвращает число водителей, чей рейтинг изменился
+	RecomputeRatings(ctx context.Context) (int, error)
+}
+
+// ratingService реализация RatingService
+type ratingService struct {
+	ratingRepo repositories.RatingRepository
+	driverRepo repositories.DriverRepository
+	eventBus   EventPublisher
+	txManager  repositories.TxManager
+	policy     RatingPolicy
+	logger     *zap.Logger
+
+	// recomputeMu упорядочивает пересчеты рейтинга без транзакций (хранение в памяти)
+	recomputeMu sync.Mutex
+}
+
+// NewRatingService создает новый RatingService. txManager выполняет пересчет рейтинга водителя
+// в транзакции с блокировкой его строки; nil — без транзакций (хранение в памяти)
+func NewRatingService(
+	ratingRepo repositories.RatingRepository,
+	driverRepo repositories.DriverRepository,
+	eventBus EventPublisher,
+	txManager repositories.TxManager,
+	policy RatingPolicy,
+	logger *zap.Logger,
+) RatingService {
+	return &ratingService{
+		ratingRepo: ratingRepo,
+		driverRepo: driverRepo,
+		eventBus:   eventBus,
+		txManager:  txManager,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// AddRating добавляет оценку водителю и пересчитывает его текущий рейтинг. Клиент может оценить
+// заказ один раз и не больше RatingPolicy.MaxPerCustomerPerDay раз за сутки; серия оценок 1 от
+// одного клиента отмечается подозрительной и в рейтинге не учитывается
+func (s *ratingService) AddRating(ctx context.Context, driverID uuid.UUID, req *entities.RatingRequest) (*entities.DriverRating, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	ratingType := req.RatingType
+	if ratingType == "" {
+		ratingType = entities.RatingTypeCustomer
+	}
+	if !ratingType.IsValid() {
+		return nil, entities.ErrInvalidRatingType
+	}
+
+	rating := entities.NewDriverRating(driverID, req.Rating, ratingType)
+	rating.OrderID = req.OrderID
+	rating.CustomerID = req.CustomerID
+	rating.Comment = req.Comment
+	for criteria, score := range req.CriteriaScores {
+		rating.CriteriaScores[criteria] = score
+	}
+	if req.IsAnonymous != nil {
+		rating.IsAnonymous = *req.IsAnonymous
+	}
+
+	if err := rating.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkCustomer(ctx, rating); err != nil {
+		return nil, err
+	}
+
+	if err := s.ratingRepo.Create(ctx, rating); err != nil {
+		if errors.Is(err, entities.ErrRatingExists) {
+			return nil, entities.ErrRatingExists
+		}
+		s.logger.Error("Failed to create rating",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	// Оценка уже сохранена: ошибки проверки клиента и пересчета рейтинга водителя не возвращаем
+	if rating.Rating == 1 && rating.CustomerID != nil {
+		s.flagOneStarSeries(ctx, rating)
+	}
+	if _, err := s.recompute(ctx, driverID, time.Now()); err != nil {
+		s.logger.Error("Failed to update driver rating",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	eventData := map[string]interface{}{
+		"rating_id":   rating.ID,
+		"rating":      rating.Rating,
+		"rating_type": rating.RatingType,
+	}
+	if rating.OrderID != nil {
+		eventData["order_id"] = *rating.OrderID
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventRatingAdded, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish rating added event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Rating added",
+		zap.String("rating_id", rating.ID.String()),
+		zap.String("driver_id", driverID.String()),
+		zap.Int("rating", rating.Rating),
+	)
+
+	return rating, nil
+}
+
+// VerifyRating подтверждает оценку и пересчитывает рейтинг водителя. Оценка другого водителя
+// возвращает ErrRatingNotFound; повторное подтверждение ничего не меняет
+func (s *ratingService) VerifyRating(ctx context.Context, driverID, ratingID uuid.UUID) (*entities.DriverRating, error) {
+	rating, err := s.ratingRepo.GetByID(ctx, ratingID)
+	if err != nil {
+		return nil, err
+	}
+	if rating.DriverID != driverID {
+		return nil, entities.ErrRatingNotFound
+	}
+	if rating.IsVerified {
+		return rating, nil
+	}
+
+	rating.Verify()
+	if err := s.ratingRepo.Update(ctx, rating); err != nil {
+		s.logger.Error("Failed to verify rating",
+			zap.Error(err),
+			zap.String("rating_id", ratingID.String()),
+		)
+		return nil, fmt.Errorf("failed to verify rating: %w", err)
+	}
+
+	// Оценка уже подтверждена: ошибку пересчета рейтинга водителя не возвращаем
+	if _, err := s.recompute(ctx, driverID, time.Now()); err != nil {
+		s.logger.Error("Failed to update driver rating",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Rating verified",
+		zap.String("rating_id", ratingID.String()),
+		zap.String("driver_id", driverID.String()),
+	)
+	return rating, nil
+}
+
+// checkCustomer проверяет, что клиент еще не оценивал заказ и не превысил дневной лимит оценок
+func (s *ratingService) checkCustomer(ctx context.Context, rating *entities.DriverRating) error {
+	if rating.CustomerID == nil {
+		return nil
+	}
+
+	if rating.OrderID != nil {
+		count, err := s.ratingRepo.Count(ctx, &entities.RatingFilters{CustomerID: rating.CustomerID, OrderID: rating.OrderID})
+		if err != nil {
+			return fmt.Errorf("failed to check order rating: %w", err)
+		}
+		if count > 0 {
+			return entities.ErrRatingExists
+		}
+	}
+
+	if s.policy.MaxPerCustomerPerDay > 0 {
+		since := rating.CreatedAt.Add(-24 * time.Hour)
+		count, err := s.ratingRepo.Count(ctx, &entities.RatingFilters{CustomerID: rating.CustomerID, From: &since})
+		if err != nil {
+			return fmt.Errorf("failed to count customer ratings: %w", err)
+		}
+		if count >= s.policy.MaxPerCustomerPerDay {
+			s.logger.Warn("Customer rating limit exceeded",
+				zap.String("customer_id", rating.CustomerID.String()),
+				zap.Int("ratings_per_day", count),
+			)
+			return entities.ErrRatingRateLimited
+		}
+	}
+	return nil
+}
+
+// flagOneStarSeries отмечает подозрительными оценки клиента, если за SuspiciousWindow он поставил
+// не меньше SuspiciousMinRatings оценок и все они равны 1. Рейтинг затронутых водителей пересчитывается
+func (s *ratingService) flagOneStarSeries(ctx context.Context, rating *entities.DriverRating) {
+	if s.policy.SuspiciousMinRatings <= 0 {
+		return
+	}
+
+	since := rating.CreatedAt.Add(-s.policy.SuspiciousWindow)
+	ratings, err := s.ratingRepo.List(ctx, &entities.RatingFilters{CustomerID: rating.CustomerID, From: &since})
+	if err != nil {
+		s.logger.Error("Failed to list customer ratings", zap.Error(err), zap.String("customer_id", rating.CustomerID.String()))
+		return
+	}
+	if len(ratings) < s.policy.SuspiciousMinRatings {
+		return
+	}
+	for _, existing := range ratings {
+		if existing.Rating != 1 {
+			return
+		}
+	}
+
+	drivers := make(map[uuid.UUID]bool)
+	for _, existing := range ratings {
+		if existing.IsSuspicious() {
+			continue
+		}
+		existing.FlagSuspicious(entities.RatingFlagCustomerAllOneStar)
+		if err := s.ratingRepo.Update(ctx, existing); err != nil {
+			s.logger.Error("Failed to flag suspicious rating", zap.Error(err), zap.String("rating_id", existing.ID.String()))
+			continue
+		}
+		if existing.ID == rating.ID {
+			rating.FlagSuspicious(entities.RatingFlagCustomerAllOneStar)
+		}
+		drivers[existing.DriverID] = true
+	}
+
+	s.logger.Warn("Customer ratings flagged as suspicious",
+		zap.String("customer_id", rating.CustomerID.String()),
+		zap.String("reason", entities.RatingFlagCustomerAllOneStar),
+		zap.Int("ratings", len(ratings)),
+	)
+
+	for driverID := range drivers {
+		if driverID == rating.DriverID {
+			continue
+		}
+		if _, err := s.recompute(ctx, driverID, time.Now()); err != nil {
+			s.logger.Error("Failed to update driver rating", zap.Error(err), zap.String("driver_id", driverID.String()))
+		}
+	}
+}
+
+// RecomputeRatings пересчитывает рейтинг водителей постранично. Ошибка одного водителя
+// не прерывает пересчет остальных
+func (s *ratingService) RecomputeRatings(ctx context.Context) (int, error) {
+	now := time.Now()
+	updated, failed := 0, 0
+
+	for offset := 0; ; offset += ratingRecomputePageSize {
+		drivers, err := s.driverRepo.List(ctx, &entities.DriverFilters{
+			Limit:         ratingRecomputePageSize,
+			Offset:        offset,
+			SortBy:        "created_at",
+			SortDirection: "asc",
+		})
+		if err != nil {
+			return updated, fmt.Errorf("failed to list drivers: %w", err)
+		}
+
+		for _, driver := range drivers {
+			changed, err := s.recompute(ctx, driver.ID, now)
+			if err != nil {
+				s.logger.Error("Failed to recompute driver rating",
+					zap.Error(err),
+					zap.String("driver_id", driver.ID.String()),
+				)
+				failed++
+				continue
+			}
+			if changed {
+				updated++
+			}
+		}
+
+		if len(drivers) < ratingRecomputePageSize {
+			break
+		}
+	}
+
+	s.logger.Info("Driver ratings recomputed", zap.Int("updated", updated), zap.Int("failed", failed))
+	return updated, nil
+}
+
+// recompute записывает водителю средневзвешенный с учетом давности рейтинг по его подтвержденным
+// оценкам, кроме подозрительных; без таких оценок — entities.NoRating. Возвращает, изменился
+// ли рейтинг.
+//
+// Рейтинг пересчитывается из оценок целиком, а не сдвигается на новую оценку. Строка водителя
+// блокируется до чтения оценок, поэтому параллельные пересчеты (две оценки одновременно,
+// оценка во время задачи rating_recompute) выполняются по очереди, и последний из них видит
+// все сохраненные оценки: более старый результат не перезапишет более новый
+func (s *ratingService) recompute(ctx context.Context, driverID uuid.UUID, now time.Time) (bool, error) {
+	if s.txManager == nil {
+		s.recomputeMu.Lock()
+		defer s.recomputeMu.Unlock()
+	}
+
+	changed := false
+	err := repositories.WithTransaction(ctx, s.txManager, func(ctx context.Context) error {
+		current, err := s.driverRepo.LockRating(ctx, driverID)
+		if err != nil {
+			return err
+		}
+		verified := true
+		ratings, err := s.ratingRepo.List(ctx, &entities.RatingFilters{DriverID: &driverID, IsVerified: &verified})
+		if err != nil {
+			return fmt.Errorf("failed to get ratings: %w", err)
+		}
+
+		average, ok := entities.DecayedRatingAverage(ratings, now, s.policy.DecayHalfLife)
+		if !ok {
+			average = entities.NoRating
+		}
+		if math.Abs(average-current) < 0.005 {
+			return nil
+		}
+		if err := s.driverRepo.UpdateRating(ctx, driverID, average); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
+
+// ListRatings получает список оценок с фильтрами
+func (s *ratingService) ListRatings(ctx context.Context, filters *entities.RatingFilters) ([]*entities.DriverRating, error) {
+	if err := validateRatingFilters(filters); err != nil {
+		return nil, err
+	}
+
+	ratings, err := s.ratingRepo.List(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list ratings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list ratings: %w", err)
+	}
+	return ratings, nil
+}
+
+// CountRatings возвращает количество оценок с фильтрами
+func (s *ratingService) CountRatings(ctx context.Context, filters *entities.RatingFilters) (int, error) {
+	if err := validateRatingFilters(filters); err != nil {
+		return 0, err
+	}
+	return s.ratingRepo.Count(ctx, filters)
+}
+
+// GetRatingStats возвращает агрегированную статистику оценок водителя
+func (s *ratingService) GetRatingStats(ctx context.Context, driverID uuid.UUID) (*entities.RatingStats, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.ratingRepo.GetStats(ctx, driverID)
+	if err != nil {
+		s.logger.Error("Failed to get rating stats",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get rating stats: %w", err)
+	}
+	return stats, nil
+}
+
+// validateRatingFilters проверяет границы оценок, типы и период в фильтрах
+func validateRatingFilters(filters *entities.RatingFilters) error {
+	if filters == nil {
+		return nil
+	}
+
+	for _, bound := range []*int{filters.MinRating, filters.MaxRating} {
+		if bound != nil && (*bound < 1 || *bound > 5) {
+			return entities.ErrInvalidRating
+		}
+	}
+	if filters.MinRating != nil && filters.MaxRating != nil && *filters.MinRating > *filters.MaxRating {
+		return entities.ErrInvalidRating
+	}
+
+	for _, ratingType := range filters.RatingType {
+		if !ratingType.IsValid() {
+			return entities.ErrInvalidRatingType
+		}
+	}
+
+	if filters.From != nil && filters.To != nil && filters.To.Before(*filters.From) {
+		return entities.ErrInvalidTimestamp
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestRatingService(t *testing.T) (RatingService, *memory.DriverRepository, *entities.Driver, *recordingEventPublisher) {
+	service, driverRepo, _, driver, events := newTestRatingServiceWithPolicy(t, RatingPolicy{})
+	return service, driverRepo, driver, events
+}
+
+func newTestRatingServiceWithPolicy(t *testing.T, policy RatingPolicy) (RatingService, *memory.DriverRepository, *memory.RatingRepository, *entities.Driver, *recordingEventPublisher) {
+	driverRepo := memory.NewDriverRepository()
+	ratingRepo := memory.NewRatingRepository()
+	events := &recordingEventPublisher{}
+
+	driver := newTestDriver("601")
+	driver.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	return NewRatingService(ratingRepo, driverRepo, events, nil, policy, zap.NewNop()), driverRepo, ratingRepo, driver, events
+}
+
+// addVerifiedRating добавляет оценку и подтверждает ее, чтобы она учитывалась в рейтинге
+func addVerifiedRating(t *testing.T, service RatingService, driverID uuid.UUID, req *entities.RatingRequest) *entities.DriverRating {
+	ctx := context.Background()
+	rating, err := service.AddRating(ctx, driverID, req)
+	require.NoError(t, err)
+	rating, err = service.VerifyRating(ctx, driverID, rating.ID)
+	require.NoError(t, err)
+	return rating
+}
+
+func TestRatingService_AddRating(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, driver, events := newTestRatingService(t)
+
+	orderID, customerID := uuid.New(), uuid.New()
+	anonymous := true
+	rating, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{
+		OrderID:        &orderID,
+		CustomerID:     &customerID,
+		Rating:         4,
+		CriteriaScores: map[string]int{"cleanliness": 5},
+		IsAnonymous:    &anonymous,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.RatingTypeCustomer, rating.RatingType)
+	assert.Nil(t, rating.ToResponse().CustomerID, "anonymous rating hides the customer")
+	assert.True(t, events.has("driver.rating.added"))
+
+	_, err = service.AddRating(ctx, driver.ID, &entities.RatingRequest{OrderID: &orderID, CustomerID: &customerID, Rating: 5})
+	assert.Equal(t, entities.ErrRatingExists, err)
+
+	_, err = service.AddRating(ctx, driver.ID, &entities.RatingRequest{Rating: 5, RatingType: "unknown"})
+	assert.Equal(t, entities.ErrInvalidRatingType, err)
+
+	_, err = service.AddRating(ctx, driver.ID, &entities.RatingRequest{Rating: 5, CriteriaScores: map[string]int{"speed": 7}})
+	assert.Equal(t, entities.ErrInvalidCriteriaScore, err)
+
+	_, err = service.AddRating(ctx, uuid.New(), &entities.RatingRequest{Rating: 5})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	adminRating, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{Rating: 2, RatingType: entities.RatingTypeAdmin})
+	require.NoError(t, err)
+
+	updated, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, driver.CurrentRating, updated.CurrentRating, "unverified ratings are not counted")
+
+	_, err = service.VerifyRating(ctx, uuid.New(), rating.ID)
+	assert.Equal(t, entities.ErrRatingNotFound, err, "rating of another driver")
+	_, err = service.VerifyRating(ctx, driver.ID, uuid.New())
+	assert.Equal(t, entities.ErrRatingNotFound, err)
+
+	for _, id := range []uuid.UUID{rating.ID, adminRating.ID} {
+		verified, err := service.VerifyRating(ctx, driver.ID, id)
+		require.NoError(t, err)
+		assert.True(t, verified.IsVerified)
+	}
+	updated, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, updated.CurrentRating, "driver rating follows the verified ratings average")
+}
+
+func TestRatingService_ListAndStats(t *testing.T) {
+	ctx := context.Background()
+	service, _, driver, _ := newTestRatingService(t)
+
+	for _, value := range []int{5, 5, 4, 3, 1} {
+		_, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{
+			Rating:         value,
+			CriteriaScores: map[string]int{"politeness": value},
+		})
+		require.NoError(t, err)
+	}
+
+	minRating := 4
+	filters := &entities.RatingFilters{DriverID: &driver.ID, MinRating: &minRating, Limit: 2}
+	ratings, err := service.ListRatings(ctx, filters)
+	require.NoError(t, err)
+	assert.Len(t, ratings, 2)
+
+	total, err := service.CountRatings(ctx, filters)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+
+	maxRating := 2
+	_, err = service.ListRatings(ctx, &entities.RatingFilters{MinRating: &minRating, MaxRating: &maxRating})
+	assert.Equal(t, entities.ErrInvalidRating, err)
+
+	stats, err := service.GetRatingStats(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats.TotalRatings)
+	assert.Equal(t, 3.6, stats.AverageRating)
+	assert.Equal(t, map[int]int{5: 2, 4: 1, 3: 1, 1: 1}, stats.RatingDistribution)
+	assert.Equal(t, 3.6, stats.CriteriaAverages["politeness"])
+
+	_, err = service.GetRatingStats(ctx, uuid.New())
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
+
+func TestRatingService_CustomerRules(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, _, driver, _ := newTestRatingServiceWithPolicy(t, RatingPolicy{MaxPerCustomerPerDay: 3})
+
+	other := newTestDriver("602")
+	other.ID = uuid.New()
+	require.NoError(t, driverRepo.Create(ctx, other))
+
+	// Заказ оценивается клиентом один раз, в том числе с другим водителем
+	customerID, orderID := uuid.New(), uuid.New()
+	_, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{OrderID: &orderID, CustomerID: &customerID, Rating: 5})
+	require.NoError(t, err)
+	_, err = service.AddRating(ctx, other.ID, &entities.RatingRequest{OrderID: &orderID, CustomerID: &customerID, Rating: 1})
+	assert.Equal(t, entities.ErrRatingExists, err)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{CustomerID: &customerID, Rating: 4})
+		require.NoError(t, err)
+	}
+	_, err = service.AddRating(ctx, driver.ID, &entities.RatingRequest{CustomerID: &customerID, Rating: 4})
+	assert.Equal(t, entities.ErrRatingRateLimited, err)
+
+	// Лимит считается по клиенту
+	anotherCustomer := uuid.New()
+	_, err = service.AddRating(ctx, driver.ID, &entities.RatingRequest{CustomerID: &anotherCustomer, Rating: 4})
+	assert.NoError(t, err)
+}
+
+func TestRatingService_FlagsOneStarSeries(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, ratingRepo, driver, _ := newTestRatingServiceWithPolicy(t, RatingPolicy{
+		SuspiciousMinRatings: 3,
+		SuspiciousWindow:     24 * time.Hour,
+	})
+
+	honest := uuid.New()
+	addVerifiedRating(t, service, driver.ID, &entities.RatingRequest{CustomerID: &honest, Rating: 5})
+
+	hater := uuid.New()
+	for i := 0; i < 2; i++ {
+		rating := addVerifiedRating(t, service, driver.ID, &entities.RatingRequest{CustomerID: &hater, Rating: 1})
+		assert.False(t, rating.IsSuspicious())
+	}
+	updated, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2.33, updated.CurrentRating)
+
+	rating := addVerifiedRating(t, service, driver.ID, &entities.RatingRequest{CustomerID: &hater, Rating: 1})
+	assert.True(t, rating.ToResponse().Suspicious)
+
+	// Серия оценок 1 помечается целиком и перестает влиять на рейтинг
+	ratings, err := ratingRepo.List(ctx, &entities.RatingFilters{CustomerID: &hater})
+	require.NoError(t, err)
+	require.Len(t, ratings, 3)
+	for _, rating := range ratings {
+		assert.True(t, rating.IsSuspicious())
+		assert.Equal(t, entities.RatingFlagCustomerAllOneStar, rating.Metadata["suspicious_reason"])
+	}
+	updated, err = driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, updated.CurrentRating)
+}
+
+func TestRatingService_RecomputeRatings(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, ratingRepo, driver, _ := newTestRatingServiceWithPolicy(t, RatingPolicy{DecayHalfLife: 30 * 24 * time.Hour})
+
+	// Старая плохая оценка весит вчетверо меньше свежей хорошей
+	old := entities.NewDriverRating(driver.ID, 1, entities.RatingTypeCustomer)
+	old.CreatedAt = time.Now().Add(-60 * 24 * time.Hour)
+	old.Verify()
+	require.NoError(t, ratingRepo.Create(ctx, old))
+	fresh := entities.NewDriverRating(driver.ID, 5, entities.RatingTypeCustomer)
+	fresh.Verify()
+	require.NoError(t, ratingRepo.Create(ctx, fresh))
+	// Неподтвержденная оценка не меняет рейтинг
+	require.NoError(t, ratingRepo.Create(ctx, entities.NewDriverRating(driver.ID, 1, entities.RatingTypeCustomer)))
+
+	updated, err := service.RecomputeRatings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 4.2, stored.CurrentRating, 0.01)
+
+	// Без изменений рейтинг не перезаписывается
+	updated, err = service.RecomputeRatings(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, updated)
+}
+
+func TestRatingService_RecomputeWithoutVerifiedRatings(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, ratingRepo, driver, _ := newTestRatingServiceWithPolicy(t, RatingPolicy{})
+
+	// Рейтинг, посчитанный раньше по неподтвержденным оценкам, сбрасывается
+	require.NoError(t, driverRepo.UpdateRating(ctx, driver.ID, 4.8))
+	for _, value := range []int{5, 5, 4} {
+		require.NoError(t, ratingRepo.Create(ctx, entities.NewDriverRating(driver.ID, value, entities.RatingTypeCustomer)))
+	}
+
+	updated, err := service.RecomputeRatings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, updated)
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.NoRating, stored.CurrentRating)
+}
+
+func TestRatingService_ConcurrentRatings(t *testing.T) {
+	ctx := context.Background()
+	service, driverRepo, driver, _ := newTestRatingService(t)
+
+	// Параллельные подтверждения не теряются: итоговый рейтинг считается по всем оценкам
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		score := 5
+		if i%2 == 0 {
+			score = 3
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rating, err := service.AddRating(ctx, driver.ID, &entities.RatingRequest{Rating: score})
+			if !assert.NoError(t, err) {
+				return
+			}
+			_, err = service.VerifyRating(ctx, driver.ID, rating.ID)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	stored, err := driverRepo.GetByID(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, stored.CurrentRating, 0.01)
+}
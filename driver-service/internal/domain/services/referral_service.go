@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// openReferralsBatch сколько незавершенных рефералов проверяется за один запрос
+	openReferralsBatch = 100
+	// referralCodeAttempts сколько раз генерируется код, если случайный код уже занят
+	referralCodeAttempts = 5
+)
+
+// ReferralEventTypes события водителя, после которых пересчитывается его прогресс как
+// приглашенного: допуск к работе и выполненные поездки
+var ReferralEventTypes = []string{
+	eventDriverStatusChanged,
+	eventDriverStatusOverridden,
+	eventShiftEnded,
+	eventEarningRecorded,
+}
+
+// ReferralPolicy настройки реферальной программы
+type ReferralPolicy struct {
+	// RequiredTrips сколько поездок после допуска должен выполнить приглашенный водитель,
+	// чтобы пригласивший получил право на выплату
+	RequiredTrips int
+}
+
+// ReferralService интерфейс реферальной программы водителей
+type ReferralService interface {
+	// GetCode возвращает реферальный код водителя, создавая его при первом обращении
+	GetCode(ctx context.Context, driverID uuid.UUID) (*entities.ReferralCode, error)
+	// RegisterReferral регистрирует водителя, приглашенного по коду referrerID
+	RegisterReferral(ctx context.Context, referrerID uuid.UUID, req *entities.ReferralRequest) (*entities.Referral, error)
+	// ListReferrals возвращает рефералы пригласившего водителя, последние первыми
+	ListReferrals(ctx context.Context, referrerID uuid.UUID) ([]*entities.Referral, error)
+	// HandleDriverEvent пересчитывает прогресс водителя, если он зарегистрирован приглашенным
+	HandleDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+	// ProcessOpen пересчитывает прогресс всех незавершенных рефералов и возвращает
+	// число завершенных
+	ProcessOpen(ctx context.Context) (int, error)
+	// PayoutReport возвращает рефералы с правом на выплату, завершенные в [from, to)
+	PayoutReport(ctx context.Context, from, to time.Time) (*entities.ReferralPayoutReport, error)
+}
+
+// referralService реализация ReferralService
+type referralService struct {
+	referralRepo repositories.ReferralRepository
+	driverRepo   repositories.DriverRepository
+	eventBus     EventPublisher
+	policy       ReferralPolicy
+	logger       *zap.Logger
+
+	// codeMu исключает параллельное создание кода одного водителя в пределах экземпляра;
+	// между экземплярами гонку разрешает уникальность driver_id
+	codeMu sync.Mutex
+}
+
+// NewReferralService создает новый ReferralService
+func NewReferralService(
+	referralRepo repositories.ReferralRepository,
+	driverRepo repositories.DriverRepository,
+	eventBus EventPublisher,
+	policy ReferralPolicy,
+	logger *zap.Logger,
+) ReferralService {
+	if policy.RequiredTrips <= 0 {
+		policy.RequiredTrips = 1
+	}
+
+	return &referralService{
+		referralRepo: referralRepo,
+		driverRepo:   driverRepo,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// GetCode получает реферальный код водителя. Код создается при первом запросе; если
+// случайный код совпал с чужим, генерируется новый
+func (s *referralService) GetCode(ctx context.Context, driverID uuid.UUID) (*entities.ReferralCode, error) {
+	code, err := s.referralRepo.GetCode(ctx, driverID)
+	if err != entities.ErrReferralCodeNotFound {
+		return code, err
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	s.codeMu.Lock()
+	defer s.codeMu.Unlock()
+
+	for attempt := 0; attempt < referralCodeAttempts; attempt++ {
+		code, err := entities.NewReferralCode(driverID, time.Now())
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.referralRepo.CreateCode(ctx, code)
+		if err == nil {
+			s.logger.Info("Referral code created",
+				zap.String("driver_id", driverID.String()),
+			)
+			return code, nil
+		}
+		if err != entities.ErrReferralCodeTaken {
+			return nil, err
+		}
+
+		// Код мог создать параллельный запрос другого экземпляра
+		if existing, err := s.referralRepo.GetCode(ctx, driverID); err == nil {
+			return existing, nil
+		} else if err != entities.ErrReferralCodeNotFound {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("failed to generate unique referral code after %d attempts", referralCodeAttempts)
+}
+
+// RegisterReferral проверяет код пригласившего и регистрирует приглашенного водителя.
+// Приглашенным может стать только водитель, еще не прошедший проверку
+func (s *referralService) RegisterReferral(ctx context.Context, referrerID uuid.UUID, req *entities.ReferralRequest) (*entities.Referral, error) {
+	if req.ReferredDriverID == uuid.Nil || req.ReferredDriverID == referrerID {
+		return nil, entities.ErrInvalidReferral
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, referrerID); err != nil {
+		return nil, err
+	}
+	code, err := s.referralRepo.GetCode(ctx, referrerID)
+	if err == entities.ErrReferralCodeNotFound {
+		return nil, entities.ErrInvalidReferralCode
+	}
+	if err != nil {
+		return nil, err
+	}
+	if code.Code != entities.NormalizeReferralCode(req.Code) {
+		return nil, entities.ErrInvalidReferralCode
+	}
+
+	referred, err := s.driverRepo.GetByID(ctx, req.ReferredDriverID)
+	if err != nil {
+		return nil, err
+	}
+	if !referred.CanBeReferred() {
+		return nil, entities.ErrReferralNotEligible
+	}
+
+	referral := entities.NewReferral(referrerID, referred.ID, code.Code, s.policy.RequiredTrips, time.Now())
+	if err := s.referralRepo.Create(ctx, referral); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Referral registered",
+		zap.String("referral_id", referral.ID.String()),
+		zap.String("referrer_id", referrerID.String()),
+		zap.String("referred_driver_id", referred.ID.String()),
+	)
+	return referral, nil
+}
+
+// ListReferrals получает рефералы пригласившего водителя
+func (s *referralService) ListReferrals(ctx context.Context, referrerID uuid.UUID) ([]*entities.Referral, error) {
+	if _, err := s.driverRepo.GetByID(ctx, referrerID); err != nil {
+		return nil, err
+	}
+
+	return s.referralRepo.ListByReferrer(ctx, referrerID)
+}
+
+// HandleDriverEvent пересчитывает прогресс реферала после события приглашенного водителя
+func (s *referralService) HandleDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	referral, err := s.referralRepo.GetByReferredDriver(ctx, driverID)
+	if err == entities.ErrReferralNotFound {
+		// Водитель пришел не по приглашению
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.advance(ctx, referral)
+	return err
+}
+
+// ProcessOpen проходит по незавершенным рефералам. Прогресс пересчитывается и по событиям,
+// проход нужен для поездок, учтенных без события, и событий, пропущенных при сбое
+func (s *referralService) ProcessOpen(ctx context.Context) (int, error) {
+	completed := 0
+	after := uuid.Nil
+	for {
+		referrals, err := s.referralRepo.ListOpen(ctx, after, openReferralsBatch)
+		if err != nil {
+			return completed, err
+		}
+
+		for _, referral := range referrals {
+			done, err := s.advance(ctx, referral)
+			if err != nil {
+				s.logger.Error("Failed to update referral progress",
+					zap.Error(err),
+					zap.String("referral_id", referral.ID.String()),
+				)
+				continue
+			}
+			if done {
+				completed++
+			}
+		}
+
+		if len(referrals) < openReferralsBatch {
+			break
+		}
+		after = referrals[len(referrals)-1].ID
+	}
+
+	if completed > 0 {
+		s.logger.Info("Referrals completed", zap.Int("completed", completed))
+	}
+	return completed, nil
+}
+
+// PayoutReport получает завершенные за период рефералы, сгруппированные по пригласившим
+func (s *referralService) PayoutReport(ctx context.Context, from, to time.Time) (*entities.ReferralPayoutReport, error) {
+	if !from.Before(to) {
+		return nil, entities.ErrInvalidReferralPeriod
+	}
+
+	referrals, err := s.referralRepo.ListCompleted(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return entities.NewReferralPayoutReport(from, to, referrals), nil
+}
+
+// advance переносит прогресс приглашенного водителя в реферал и публикует
+// referral.completed, если реферал завершен этим вызовом. Возвращает true при завершении
+func (s *referralService) advance(ctx context.Context, referral *entities.Referral) (bool, error) {
+	if !referral.IsOpen() {
+		return false, nil
+	}
+
+	referred, err := s.driverRepo.GetByID(ctx, referral.ReferredDriverID)
+	if err == entities.ErrDriverNotFound {
+		// Приглашенный водитель удален: реферал остается незавершенным
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	from := referral.Status
+	if !referral.Advance(referred, time.Now()) {
+		return false, nil
+	}
+
+	updated, err := s.referralRepo.UpdateProgress(ctx, referral, from)
+	if err != nil {
+		return false, err
+	}
+	// Реферал параллельно перевел другой обработчик, он же публикует событие
+	if !updated || referral.Status != entities.ReferralStatusCompleted {
+		return false, nil
+	}
+
+	eventData := map[string]interface{}{
+		"referral_id":        referral.ID,
+		"referred_driver_id": referral.ReferredDriverID,
+		"code":               referral.Code,
+		"trips_completed":    referral.TripsCompleted,
+		"required_trips":     referral.RequiredTrips,
+		"completed_at":       *referral.CompletedAt,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventReferralCompleted, referral.ReferrerID, eventData); err != nil {
+		s.logger.Error("Failed to publish referral completed event",
+			zap.Error(err),
+			zap.String("referral_id", referral.ID.String()),
+		)
+	}
+
+	s.logger.Info("Referral completed",
+		zap.String("referral_id", referral.ID.String()),
+		zap.String("referrer_id", referral.ReferrerID.String()),
+		zap.String("referred_driver_id", referral.ReferredDriverID.String()),
+	)
+	return true, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestReferralService(t *testing.T) (ReferralService, *memory.ReferralRepository, *memory.DriverRepository, *recordingEventPublisher) {
+	driverRepo := memory.NewDriverRepository()
+	referralRepo := memory.NewReferralRepository()
+	events := &recordingEventPublisher{}
+	service := NewReferralService(referralRepo, driverRepo, events, ReferralPolicy{RequiredTrips: 2}, zap.NewNop())
+	return service, referralRepo, driverRepo, events
+}
+
+func createReferralDriver(t *testing.T, driverRepo *memory.DriverRepository, suffix string, status entities.Status) *entities.Driver {
+	driver := newTestDriver(suffix)
+	driver.ID = uuid.New()
+	driver.Status = status
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+	return driver
+}
+
+func TestReferralService_RegisterAndComplete(t *testing.T) {
+	ctx := context.Background()
+	service, _, driverRepo, events := newTestReferralService(t)
+	referrer := createReferralDriver(t, driverRepo, "901", entities.StatusAvailable)
+	referred := createReferralDriver(t, driverRepo, "902", entities.StatusRegistered)
+
+	code, err := service.GetCode(ctx, referrer.ID)
+	require.NoError(t, err)
+	assert.Len(t, code.Code, entities.ReferralCodeLength)
+
+	again, err := service.GetCode(ctx, referrer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, code.Code, again.Code)
+
+	_, err = service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{ReferredDriverID: referred.ID, Code: "WRONG000"})
+	assert.ErrorIs(t, err, entities.ErrInvalidReferralCode)
+
+	referral, err := service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{
+		ReferredDriverID: referred.ID, Code: " " + strings.ToLower(code.Code) + " ",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReferralStatusRegistered, referral.Status)
+	assert.Equal(t, 2, referral.RequiredTrips)
+
+	_, err = service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{ReferredDriverID: referred.ID, Code: code.Code})
+	assert.ErrorIs(t, err, entities.ErrReferralExists)
+
+	// Поездки до допуска к работе не завершают реферал
+	require.NoError(t, driverRepo.UpdateStatus(ctx, referred.ID, entities.StatusVerified))
+	require.NoError(t, service.HandleDriverEvent(ctx, eventDriverStatusChanged, referred.ID))
+	require.NoError(t, driverRepo.IncrementTripCount(ctx, referred.ID))
+	require.NoError(t, service.HandleDriverEvent(ctx, eventShiftEnded, referred.ID))
+	assert.False(t, events.has(eventReferralCompleted))
+
+	referrals, err := service.ListReferrals(ctx, referrer.ID)
+	require.NoError(t, err)
+	require.Len(t, referrals, 1)
+	assert.Equal(t, entities.ReferralStatusVerified, referrals[0].Status)
+	assert.Equal(t, 1, referrals[0].TripsCompleted)
+
+	// Поездка без события учитывается периодическим проходом
+	require.NoError(t, driverRepo.IncrementTripCount(ctx, referred.ID))
+	completed, err := service.ProcessOpen(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, completed)
+	assert.True(t, events.has(eventReferralCompleted))
+
+	completed, err = service.ProcessOpen(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, completed)
+
+	report, err := service.PayoutReport(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Total)
+	require.Len(t, report.Referrers, 1)
+	assert.Equal(t, referrer.ID, report.Referrers[0].ReferrerID)
+
+	_, err = service.PayoutReport(ctx, time.Now(), time.Now().Add(-time.Hour))
+	assert.ErrorIs(t, err, entities.ErrInvalidReferralPeriod)
+}
+
+func TestReferralService_RegisterValidation(t *testing.T) {
+	ctx := context.Background()
+	service, _, driverRepo, _ := newTestReferralService(t)
+	referrer := createReferralDriver(t, driverRepo, "911", entities.StatusAvailable)
+	verified := createReferralDriver(t, driverRepo, "912", entities.StatusVerified)
+
+	code, err := service.GetCode(ctx, referrer.ID)
+	require.NoError(t, err)
+
+	_, err = service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{ReferredDriverID: referrer.ID, Code: code.Code})
+	assert.ErrorIs(t, err, entities.ErrInvalidReferral)
+
+	_, err = service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{ReferredDriverID: verified.ID, Code: code.Code})
+	assert.ErrorIs(t, err, entities.ErrReferralNotEligible)
+
+	_, err = service.RegisterReferral(ctx, referrer.ID, &entities.ReferralRequest{ReferredDriverID: uuid.New(), Code: code.Code})
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+
+	// Код, которого пригласивший не получал, не принимается
+	_, err = service.RegisterReferral(ctx, verified.ID, &entities.ReferralRequest{ReferredDriverID: referrer.ID, Code: code.Code})
+	assert.ErrorIs(t, err, entities.ErrInvalidReferralCode)
+
+	_, err = service.GetCode(ctx, uuid.New())
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+}
+
+func TestReferralService_HandleDriverEventIgnoresDirectDrivers(t *testing.T) {
+	service, _, driverRepo, _ := newTestReferralService(t)
+	driver := createReferralDriver(t, driverRepo, "921", entities.StatusAvailable)
+
+	assert.NoError(t, service.HandleDriverEvent(context.Background(), eventDriverStatusChanged, driver.ID))
+}
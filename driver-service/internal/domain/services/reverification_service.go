@@ -0,0 +1,535 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ReverificationPolicy настройки кампаний перепроверки документов
+type ReverificationPolicy struct {
+	// DefaultReminderIntervalDays интервал напоминаний для кампаний, где он не задан
+	DefaultReminderIntervalDays int
+	// BatchSize сколько водителей сегмента читается за один запрос при запуске кампании
+	BatchSize int
+}
+
+// ReverificationService интерфейс кампаний перепроверки документов водителей
+type ReverificationService interface {
+	// CreateCampaign запускает кампанию: подтвержденные документы выбранных типов у водителей
+	// сегмента требуют перепроверки до срока кампании
+	CreateCampaign(ctx context.Context, req *entities.CreateCampaignRequest) (*entities.CampaignDetails, error)
+	ListCampaigns(ctx context.Context, status entities.CampaignStatus, limit, offset int) ([]*entities.ReverificationCampaign, error)
+	// GetCampaign возвращает кампанию с ходом перепроверки
+	GetCampaign(ctx context.Context, id uuid.UUID) (*entities.CampaignDetails, error)
+	ListTargets(ctx context.Context, filters *entities.CampaignTargetFilters) ([]*entities.CampaignTarget, error)
+	// CancelCampaign отменяет кампанию: неперепроверенные документы снова считаются подтвержденными
+	CancelCampaign(ctx context.Context, id uuid.UUID) (*entities.CampaignDetails, error)
+	// HandleDocumentEvent отмечает документы водителя, замененные перепроверенной версией
+	HandleDocumentEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+	// SendReminders напоминает водителям о неперепроверенных документах активных кампаний
+	SendReminders(ctx context.Context) (int, error)
+	// EnforceDeadlines завершает кампании с прошедшим сроком и сообщает о просроченных документах,
+	// чтобы водители были отстранены
+	EnforceDeadlines(ctx context.Context) (int, error)
+}
+
+// reverificationService реализация ReverificationService
+type reverificationService struct {
+	campaignRepo repositories.CampaignRepository
+	documentRepo repositories.DocumentRepository
+	driverRepo   repositories.DriverRepository
+	notifier     NotificationSender
+	eventBus     EventPublisher
+	policy       ReverificationPolicy
+	logger       *zap.Logger
+}
+
+// NewReverificationService создает новый ReverificationService
+func NewReverificationService(
+	campaignRepo repositories.CampaignRepository,
+	documentRepo repositories.DocumentRepository,
+	driverRepo repositories.DriverRepository,
+	notifier NotificationSender,
+	eventBus EventPublisher,
+	policy ReverificationPolicy,
+	logger *zap.Logger,
+) ReverificationService {
+	return &reverificationService{
+		campaignRepo: campaignRepo,
+		documentRepo: documentRepo,
+		driverRepo:   driverRepo,
+		notifier:     notifier,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// CreateCampaign запускает кампанию перепроверки
+func (s *reverificationService) CreateCampaign(ctx context.Context, req *entities.CreateCampaignRequest) (*entities.CampaignDetails, error) {
+	now := time.Now()
+	if strings.TrimSpace(req.Name) == "" || !req.Deadline.After(now) || len(req.DocumentTypes) == 0 || req.ReminderIntervalDays < 0 {
+		return nil, entities.ErrInvalidCampaign
+	}
+	for _, docType := range req.DocumentTypes {
+		if docType == "" {
+			return nil, entities.ErrInvalidCampaign
+		}
+	}
+
+	campaign := &entities.ReverificationCampaign{
+		ID:                   uuid.New(),
+		Name:                 strings.TrimSpace(req.Name),
+		Reason:               req.Reason,
+		Segment:              req.Segment,
+		DocumentTypes:        req.DocumentTypes,
+		Deadline:             req.Deadline,
+		ReminderIntervalDays: req.ReminderIntervalDays,
+		Status:               entities.CampaignActive,
+		CreatedBy:            req.CreatedBy,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if campaign.ReminderIntervalDays == 0 {
+		campaign.ReminderIntervalDays = s.policy.DefaultReminderIntervalDays
+	}
+
+	drivers, err := s.segmentDrivers(ctx, &campaign.Segment)
+	if err != nil {
+		return nil, err
+	}
+
+	// Перепроверяются только подтвержденные документы: документ, уже ожидающий
+	// перепроверки в другой кампании, в новую не попадает
+	var (
+		targets     []*entities.CampaignTarget
+		documentIDs []uuid.UUID
+	)
+	for _, driver := range drivers {
+		documents, err := s.documentRepo.GetByDriverID(ctx, driver.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get driver documents: %w", err)
+		}
+		for _, document := range documents {
+			if document.Status != entities.VerificationStatusVerified || !campaign.DocumentTypes.Contains(document.DocumentType) {
+				continue
+			}
+			target := entities.NewCampaignTarget(campaign.ID, document, now)
+			// Уведомление о запуске кампании считается первым напоминанием
+			target.LastRemindedAt = &now
+			targets = append(targets, target)
+			documentIDs = append(documentIDs, document.ID)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, entities.ErrCampaignNoTargets
+	}
+
+	if err := s.campaignRepo.CreateCampaign(ctx, campaign, targets); err != nil {
+		return nil, err
+	}
+	flagged, err := s.documentRepo.RequireReverification(ctx, documentIDs, campaign.Deadline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to require document reverification: %w", err)
+	}
+
+	for driverID, docTypes := range targetsByDriver(targets) {
+		s.notifyDriver(ctx, driverID, NotificationReverificationRequired, campaign, docTypes)
+	}
+
+	s.logger.Info("Reverification campaign started",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.Int("drivers", len(targetsByDriver(targets))),
+		zap.Int("documents", flagged),
+		zap.Time("deadline", campaign.Deadline),
+	)
+
+	return s.details(ctx, campaign)
+}
+
+// ListCampaigns возвращает кампании, новые первыми
+func (s *reverificationService) ListCampaigns(ctx context.Context, status entities.CampaignStatus, limit, offset int) ([]*entities.ReverificationCampaign, error) {
+	return s.campaignRepo.ListCampaigns(ctx, status, limit, offset)
+}
+
+// GetCampaign возвращает кампанию с ходом перепроверки
+func (s *reverificationService) GetCampaign(ctx context.Context, id uuid.UUID) (*entities.CampaignDetails, error) {
+	campaign, err := s.campaignRepo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.details(ctx, campaign)
+}
+
+// ListTargets возвращает документы кампании
+func (s *reverificationService) ListTargets(ctx context.Context, filters *entities.CampaignTargetFilters) ([]*entities.CampaignTarget, error) {
+	if filters.CampaignID != nil {
+		if _, err := s.campaignRepo.GetCampaign(ctx, *filters.CampaignID); err != nil {
+			return nil, err
+		}
+	}
+	return s.campaignRepo.ListTargets(ctx, filters)
+}
+
+// CancelCampaign отменяет активную кампанию или снимает требование перепроверки
+// в завершенной кампании с просроченными документами. Водители, отстраненные из-за
+// просроченной перепроверки, пересматриваются заново
+func (s *reverificationService) CancelCampaign(ctx context.Context, id uuid.UUID) (*entities.CampaignDetails, error) {
+	campaign, err := s.campaignRepo.GetCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status == entities.CampaignCancelled {
+		return nil, entities.ErrCampaignClosed
+	}
+
+	targets, err := s.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{
+		CampaignID: &campaign.ID,
+		Status:     []entities.CampaignTargetStatus{entities.CampaignTargetPending, entities.CampaignTargetOverdue},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status == entities.CampaignCompleted && len(targets) == 0 {
+		return nil, entities.ErrCampaignClosed
+	}
+
+	now := time.Now()
+	if len(targets) > 0 {
+		documentIDs := make([]uuid.UUID, len(targets))
+		for i, target := range targets {
+			documentIDs[i] = target.DocumentID
+		}
+		if _, err := s.documentRepo.ClearReverification(ctx, documentIDs); err != nil {
+			return nil, fmt.Errorf("failed to clear document reverification: %w", err)
+		}
+	}
+	for _, target := range targets {
+		target.Status = entities.CampaignTargetCancelled
+		target.UpdatedAt = now
+		if err := s.campaignRepo.UpdateTarget(ctx, target); err != nil {
+			return nil, err
+		}
+	}
+
+	campaign.Close(entities.CampaignCancelled, now)
+	if err := s.campaignRepo.UpdateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	for driverID := range targetsByDriver(targets) {
+		s.publishDocumentEvent(ctx, eventDocumentReverificationCancelled, driverID, campaign)
+	}
+
+	s.logger.Info("Reverification campaign cancelled",
+		zap.String("campaign_id", campaign.ID.String()),
+		zap.Int("documents", len(targets)),
+	)
+
+	return s.details(ctx, campaign)
+}
+
+// HandleDocumentEvent отмечает перепроверенными документы водителя, которые заменены
+// подтвержденной новой версией. Кампания без ожидающих документов завершается досрочно
+func (s *reverificationService) HandleDocumentEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	targets, err := s.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{
+		DriverID: &driverID,
+		Status:   []entities.CampaignTargetStatus{entities.CampaignTargetPending, entities.CampaignTargetOverdue},
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	campaigns := make(map[uuid.UUID]bool)
+	for _, target := range targets {
+		document, err := s.documentRepo.GetByID(ctx, target.DocumentID)
+		if err == entities.ErrDocumentNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if document.Status != entities.VerificationStatusSuperseded {
+			continue
+		}
+
+		target.Status = entities.CampaignTargetCompleted
+		target.CompletedAt = &now
+		target.UpdatedAt = now
+		if err := s.campaignRepo.UpdateTarget(ctx, target); err != nil {
+			return err
+		}
+		campaigns[target.CampaignID] = true
+
+		s.logger.Info("Document reverified",
+			zap.String("campaign_id", target.CampaignID.String()),
+			zap.String("document_id", target.DocumentID.String()),
+		)
+	}
+
+	for campaignID := range campaigns {
+		s.completeIfDone(ctx, campaignID, now)
+	}
+
+	return nil
+}
+
+// SendReminders напоминает водителям о документах, ожидающих перепроверки, не чаще
+// интервала напоминаний кампании. Возвращает число уведомленных водителей
+func (s *reverificationService) SendReminders(ctx context.Context) (int, error) {
+	campaigns, err := s.campaignRepo.ListCampaigns(ctx, entities.CampaignActive, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active campaigns: %w", err)
+	}
+
+	now := time.Now()
+	sent := 0
+	for _, campaign := range campaigns {
+		if campaign.IsDue(now) || campaign.ReminderIntervalDays <= 0 {
+			continue
+		}
+
+		remindedBefore := now.AddDate(0, 0, -campaign.ReminderIntervalDays)
+		targets, err := s.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{
+			CampaignID:     &campaign.ID,
+			Status:         []entities.CampaignTargetStatus{entities.CampaignTargetPending},
+			RemindedBefore: &remindedBefore,
+		})
+		if err != nil {
+			return sent, fmt.Errorf("failed to list campaign targets: %w", err)
+		}
+
+		byDriver := make(map[uuid.UUID][]*entities.CampaignTarget)
+		for _, target := range targets {
+			byDriver[target.DriverID] = append(byDriver[target.DriverID], target)
+		}
+		for driverID, driverTargets := range byDriver {
+			docTypes := make([]entities.DocumentType, len(driverTargets))
+			for i, target := range driverTargets {
+				docTypes[i] = target.DocumentType
+			}
+			if !s.notifyDriver(ctx, driverID, NotificationReverificationReminder, campaign, docTypes) {
+				continue
+			}
+
+			for _, target := range driverTargets {
+				target.RemindersSent++
+				target.LastRemindedAt = &now
+				target.UpdatedAt = now
+				if err := s.campaignRepo.UpdateTarget(ctx, target); err != nil {
+					s.logger.Error("Failed to mark reverification reminder as sent",
+						zap.Error(err),
+						zap.String("campaign_id", campaign.ID.String()),
+						zap.String("document_id", target.DocumentID.String()),
+					)
+				}
+			}
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		s.logger.Info("Reverification reminders sent", zap.Int("count", sent))
+	}
+
+	return sent, nil
+}
+
+// EnforceDeadlines помечает неперепроверенные к сроку документы просроченными и завершает
+// кампанию. Событие driver.document.reverification_overdue пересматривает допуск водителя.
+// Возвращает число просроченных документов
+func (s *reverificationService) EnforceDeadlines(ctx context.Context) (int, error) {
+	campaigns, err := s.campaignRepo.ListCampaigns(ctx, entities.CampaignActive, 0, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active campaigns: %w", err)
+	}
+
+	now := time.Now()
+	overdue := 0
+	for _, campaign := range campaigns {
+		if !campaign.IsDue(now) {
+			continue
+		}
+
+		targets, err := s.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{
+			CampaignID: &campaign.ID,
+			Status:     []entities.CampaignTargetStatus{entities.CampaignTargetPending},
+		})
+		if err != nil {
+			return overdue, fmt.Errorf("failed to list campaign targets: %w", err)
+		}
+
+		for _, target := range targets {
+			target.Status = entities.CampaignTargetOverdue
+			target.UpdatedAt = now
+			if err := s.campaignRepo.UpdateTarget(ctx, target); err != nil {
+				return overdue, err
+			}
+		}
+		overdue += len(targets)
+
+		campaign.Close(entities.CampaignCompleted, now)
+		if err := s.campaignRepo.UpdateCampaign(ctx, campaign); err != nil {
+			return overdue, err
+		}
+
+		for driverID := range targetsByDriver(targets) {
+			s.publishDocumentEvent(ctx, eventDocumentReverificationOverdue, driverID, campaign)
+		}
+
+		s.logger.Info("Reverification campaign deadline reached",
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.Int("overdue", len(targets)),
+		)
+	}
+
+	return overdue, nil
+}
+
+// segmentDrivers возвращает водителей сегмента кампании
+func (s *reverificationService) segmentDrivers(ctx context.Context, segment *entities.CampaignSegment) ([]*entities.Driver, error) {
+	var drivers []*entities.Driver
+	if len(segment.DriverIDs) > 0 {
+		for _, driverID := range segment.DriverIDs {
+			driver, err := s.driverRepo.GetByID(ctx, driverID)
+			if err == entities.ErrDriverNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if segment.Matches(driver) {
+				drivers = append(drivers, driver)
+			}
+		}
+		return drivers, nil
+	}
+
+	filters := &entities.DriverFilters{
+		Status: segment.Statuses,
+		Limit:  s.policy.BatchSize,
+	}
+	if segment.City != "" {
+		filters.City = &segment.City
+	}
+	for {
+		batch, err := s.driverRepo.List(ctx, filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list segment drivers: %w", err)
+		}
+		for _, driver := range batch {
+			if segment.Matches(driver) {
+				drivers = append(drivers, driver)
+			}
+		}
+		if filters.Limit <= 0 || len(batch) < filters.Limit {
+			return drivers, nil
+		}
+		filters.Offset += len(batch)
+	}
+}
+
+// completeIfDone завершает активную кампанию, в которой не осталось ожидающих документов
+func (s *reverificationService) completeIfDone(ctx context.Context, campaignID uuid.UUID, now time.Time) {
+	campaign, err := s.campaignRepo.GetCampaign(ctx, campaignID)
+	if err != nil || campaign.Status != entities.CampaignActive {
+		return
+	}
+
+	pending, err := s.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{
+		CampaignID: &campaignID,
+		Status:     []entities.CampaignTargetStatus{entities.CampaignTargetPending},
+		Limit:      1,
+	})
+	if err != nil || len(pending) > 0 {
+		return
+	}
+
+	campaign.Close(entities.CampaignCompleted, now)
+	if err := s.campaignRepo.UpdateCampaign(ctx, campaign); err != nil {
+		s.logger.Error("Failed to complete reverification campaign",
+			zap.Error(err),
+			zap.String("campaign_id", campaignID.String()),
+		)
+		return
+	}
+}
+
+// details дополняет кампанию ходом перепроверки
+func (s *reverificationService) details(ctx context.Context, campaign *entities.ReverificationCampaign) (*entities.CampaignDetails, error) {
+	progress, err := s.campaignRepo.GetProgress(ctx, campaign.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stats := range progress.ByDocumentType {
+		progress.Total += stats.Total
+		progress.Pending += stats.Pending
+		progress.Completed += stats.Completed
+		progress.Overdue += stats.Overdue
+		progress.Cancelled += stats.Cancelled
+	}
+	// Отмененные документы не учитываются в доле перепроверенных
+	if considered := progress.Total - progress.Cancelled; considered > 0 {
+		progress.CompletionRate = float64(progress.Completed) / float64(considered) * 100
+	}
+	progress.TimeToDeadline = int64(time.Until(campaign.Deadline).Seconds())
+
+	return &entities.CampaignDetails{
+		ReverificationCampaign: campaign,
+		Progress:               progress,
+	}, nil
+}
+
+// notifyDriver отправляет водителю уведомление кампании и сообщает, удалась ли отправка
+func (s *reverificationService) notifyDriver(ctx context.Context, driverID uuid.UUID, template string, campaign *entities.ReverificationCampaign, docTypes []entities.DocumentType) bool {
+	data := map[string]interface{}{
+		"campaign_id":    campaign.ID.String(),
+		"campaign_name":  campaign.Name,
+		"document_types": docTypes,
+		"deadline":       campaign.Deadline,
+	}
+	if err := s.notifier.SendToDriver(ctx, driverID, template, data); err != nil {
+		s.logger.Error("Failed to send reverification notification",
+			zap.Error(err),
+			zap.String("campaign_id", campaign.ID.String()),
+			zap.String("driver_id", driverID.String()),
+			zap.String("template", template),
+		)
+		return false
+	}
+	return true
+}
+
+// publishDocumentEvent публикует событие по документам водителя в кампании
+func (s *reverificationService) publishDocumentEvent(ctx context.Context, eventType string, driverID uuid.UUID, campaign *entities.ReverificationCampaign) {
+	data := map[string]interface{}{
+		"campaign_id": campaign.ID,
+		"deadline":    campaign.Deadline,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, driverID, data); err != nil {
+		s.logger.Error("Failed to publish reverification document event",
+			zap.Error(err),
+			zap.String("event_type", eventType),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+}
+
+// targetsByDriver группирует типы документов кампании по водителям
+func targetsByDriver(targets []*entities.CampaignTarget) map[uuid.UUID][]entities.DocumentType {
+	byDriver := make(map[uuid.UUID][]entities.DocumentType)
+	for _, target := range targets {
+		byDriver[target.DriverID] = append(byDriver[target.DriverID], target.DocumentType)
+	}
+	return byDriver
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type reverificationFixture struct {
+	service      ReverificationService
+	renewals     DocumentRenewalService
+	verification DocumentVerificationService
+	campaignRepo *memory.CampaignRepository
+	documentRepo *memory.DocumentRepository
+	driverRepo   *memory.DriverRepository
+	notifier     *recordingNotifier
+}
+
+func newReverificationFixture() *reverificationFixture {
+	f := &reverificationFixture{
+		campaignRepo: memory.NewCampaignRepository(),
+		documentRepo: memory.NewDocumentRepository(),
+		driverRepo:   memory.NewDriverRepository(),
+		notifier:     &recordingNotifier{},
+	}
+	events := &recordingEventPublisher{}
+
+	f.service = NewReverificationService(f.campaignRepo, f.documentRepo, f.driverRepo, f.notifier, events,
+		ReverificationPolicy{DefaultReminderIntervalDays: 3, BatchSize: 1}, zap.NewNop())
+	f.renewals = NewDocumentRenewalService(f.documentRepo, f.driverRepo,
+		&fakeFileStorage{files: make(map[string][]byte)}, nil, f.notifier, events,
+		DocumentRenewalPolicy{WindowDays: 30, MaxFileSize: 1 << 20}, zap.NewNop())
+	f.verification = NewDocumentVerificationService(f.documentRepo, memory.NewAuditRepository(), f.renewals, f.notifier, nil, events,
+		VerificationQueuePolicy{ClaimTTL: time.Minute, MaxBatch: 10}, zap.NewNop())
+	return f
+}
+
+// addDriver создает водителя города с подтвержденным удостоверением
+func (f *reverificationFixture) addDriver(t *testing.T, suffix, city string) (*entities.Driver, *entities.DriverDocument) {
+	ctx := context.Background()
+	driver := newTestDriver(suffix)
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusAvailable
+	driver.Metadata = entities.Metadata{entities.DriverMetaCity: city}
+	// Сегмент читается страницами по created_at: одинаковое время сделало бы порядок случайным
+	driver.CreatedAt = time.Now()
+	require.NoError(t, f.driverRepo.Create(ctx, driver))
+
+	license := entities.NewDriverDocument(driver.ID, entities.DocumentTypeDriverLicense, driver.LicenseNumber,
+		time.Now().AddDate(-1, 0, 0), time.Now().AddDate(5, 0, 0), "https://example.com/license.pdf")
+	license.Status = entities.VerificationStatusVerified
+	require.NoError(t, f.documentRepo.Create(ctx, license))
+	return driver, license
+}
+
+// reverify загружает и подтверждает новую версию документа
+func (f *reverificationFixture) reverify(t *testing.T, document *entities.DriverDocument) {
+	ctx := context.Background()
+	req := &entities.DocumentRenewalRequest{
+		DocumentNumber: "LIC-NEW",
+		IssueDate:      time.Now(),
+		ExpiryDate:     time.Now().AddDate(10, 0, 0),
+	}
+	upload := &FileUpload{ContentType: "application/pdf", Size: 4, Body: bytes.NewReader([]byte("%PDF"))}
+	renewal, err := f.renewals.RenewDocument(ctx, document.DriverID, document.ID, req, upload)
+	require.NoError(t, err)
+
+	_, err = f.verification.ClaimNext(ctx, "verifier-1", 10)
+	require.NoError(t, err)
+	_, err = f.verification.SubmitDecisions(ctx, "verifier-1", []*entities.DocumentDecision{
+		{DocumentID: renewal.ID, Status: entities.VerificationStatusVerified},
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.service.HandleDocumentEvent(ctx, "driver.document.renewed", document.DriverID))
+}
+
+func TestReverificationService_CampaignLifecycle(t *testing.T) {
+	ctx := context.Background()
+	f := newReverificationFixture()
+	_, first := f.addDriver(t, "1", "Москва")
+	_, second := f.addDriver(t, "2", "москва ")
+	_, other := f.addDriver(t, "3", "Казань")
+
+	details, err := f.service.CreateCampaign(ctx, &entities.CreateCampaignRequest{
+		Name:          "Проверка удостоверений",
+		Segment:       entities.CampaignSegment{City: "Москва"},
+		DocumentTypes: entities.DocumentTypeList{entities.DocumentTypeDriverLicense},
+		Deadline:      time.Now().AddDate(0, 0, 14),
+		CreatedBy:     "admin-1",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.CampaignActive, details.Status)
+	assert.Equal(t, 3, details.ReminderIntervalDays)
+	assert.Equal(t, 2, details.Progress.Total)
+	assert.Equal(t, 2, details.Progress.Pending)
+	assert.Equal(t, 2, details.Progress.Drivers)
+	assert.Zero(t, details.Progress.CompliantDrivers)
+	assert.Len(t, f.notifier.templates, 2)
+	assert.Contains(t, f.notifier.templates, NotificationReverificationRequired)
+
+	flagged, err := f.documentRepo.GetByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusReverificationRequired, flagged.Status)
+	require.NotNil(t, flagged.VerifyBy)
+	assert.True(t, flagged.VerifyBy.Equal(details.Deadline))
+
+	untouched, err := f.documentRepo.GetByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusVerified, untouched.Status)
+
+	// Документ уже перепроверяется: вторая кампания по тому же сегменту пуста
+	_, err = f.service.CreateCampaign(ctx, &entities.CreateCampaignRequest{
+		Name:          "Повтор",
+		Segment:       entities.CampaignSegment{DriverIDs: []uuid.UUID{first.DriverID}},
+		DocumentTypes: entities.DocumentTypeList{entities.DocumentTypeDriverLicense},
+		Deadline:      time.Now().AddDate(0, 0, 7),
+	})
+	assert.Equal(t, entities.ErrCampaignNoTargets, err)
+
+	f.reverify(t, first)
+
+	details, err = f.service.GetCampaign(ctx, details.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, details.Progress.Completed)
+	assert.Equal(t, 1, details.Progress.Pending)
+	assert.Equal(t, 1, details.Progress.CompliantDrivers)
+	assert.InDelta(t, 50.0, details.Progress.CompletionRate, 0.001)
+	require.Len(t, details.Progress.Daily, 1)
+	assert.Equal(t, 1, details.Progress.Daily[0].Completed)
+
+	// Срок кампании прошел: неперепроверенный документ просрочен, кампания завершена
+	campaign, err := f.campaignRepo.GetCampaign(ctx, details.ID)
+	require.NoError(t, err)
+	campaign.Deadline = time.Now().Add(-time.Minute)
+	require.NoError(t, f.campaignRepo.UpdateCampaign(ctx, campaign))
+
+	overdue, err := f.service.EnforceDeadlines(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, overdue)
+
+	details, err = f.service.GetCampaign(ctx, details.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CampaignCompleted, details.Status)
+	assert.Equal(t, 1, details.Progress.Overdue)
+
+	targets, err := f.service.ListTargets(ctx, &entities.CampaignTargetFilters{
+		CampaignID: &details.ID,
+		Status:     []entities.CampaignTargetStatus{entities.CampaignTargetOverdue},
+	})
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	assert.Equal(t, second.ID, targets[0].DocumentID)
+
+	// Просроченный документ можно перепроверить и после срока
+	f.reverify(t, second)
+	details, err = f.service.GetCampaign(ctx, details.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, details.Progress.Completed)
+	assert.Equal(t, 2, details.Progress.CompliantDrivers)
+
+	_, err = f.service.CancelCampaign(ctx, details.ID)
+	assert.Equal(t, entities.ErrCampaignClosed, err)
+}
+
+func TestReverificationService_Reminders(t *testing.T) {
+	ctx := context.Background()
+	f := newReverificationFixture()
+	_, license := f.addDriver(t, "1", "Москва")
+
+	details, err := f.service.CreateCampaign(ctx, &entities.CreateCampaignRequest{
+		Name:                 "Проверка удостоверений",
+		DocumentTypes:        entities.DocumentTypeList{entities.DocumentTypeDriverLicense},
+		Deadline:             time.Now().AddDate(0, 0, 14),
+		ReminderIntervalDays: 2,
+	})
+	require.NoError(t, err)
+
+	// Уведомление о запуске считается напоминанием
+	sent, err := f.service.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	targets, err := f.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{CampaignID: &details.ID})
+	require.NoError(t, err)
+	require.Len(t, targets, 1)
+	remindedAt := time.Now().AddDate(0, 0, -2).Add(-time.Minute)
+	targets[0].LastRemindedAt = &remindedAt
+	require.NoError(t, f.campaignRepo.UpdateTarget(ctx, targets[0]))
+
+	sent, err = f.service.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Contains(t, f.notifier.templates, NotificationReverificationReminder)
+
+	sent, err = f.service.SendReminders(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	targets, err = f.campaignRepo.ListTargets(ctx, &entities.CampaignTargetFilters{CampaignID: &details.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, targets[0].RemindersSent)
+
+	// Отмена снимает требование перепроверки
+	details, err = f.service.CancelCampaign(ctx, details.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.CampaignCancelled, details.Status)
+	assert.Equal(t, 1, details.Progress.Cancelled)
+
+	document, err := f.documentRepo.GetByID(ctx, license.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.VerificationStatusVerified, document.Status)
+	assert.Nil(t, document.VerifyBy)
+}
+
+func TestReverificationService_CreateValidation(t *testing.T) {
+	ctx := context.Background()
+	f := newReverificationFixture()
+	f.addDriver(t, "1", "Москва")
+
+	_, err := f.service.CreateCampaign(ctx, &entities.CreateCampaignRequest{
+		Name:          "Прошедший срок",
+		DocumentTypes: entities.DocumentTypeList{entities.DocumentTypeDriverLicense},
+		Deadline:      time.Now().Add(-time.Hour),
+	})
+	assert.Equal(t, entities.ErrInvalidCampaign, err)
+
+	_, err = f.service.CreateCampaign(ctx, &entities.CreateCampaignRequest{
+		Name:          "Другой город",
+		Segment:       entities.CampaignSegment{City: "Казань"},
+		DocumentTypes: entities.DocumentTypeList{entities.DocumentTypeDriverLicense},
+		Deadline:      time.Now().AddDate(0, 0, 7),
+	})
+	assert.Equal(t, entities.ErrCampaignNoTargets, err)
+
+	_, err = f.service.GetCampaign(ctx, uuid.New())
+	assert.Equal(t, entities.ErrCampaignNotFound, err)
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// scheduleBatchSize сколько расписаний читается за один запрос при переводе в неактивные
+const scheduleBatchSize = 500
+
+// ScheduleService интерфейс для расписаний доступности водителей
+type ScheduleService interface {
+	GetSchedule(ctx context.Context, driverID uuid.UUID) (*entities.DriverSchedule, error)
+	SetSchedule(ctx context.Context, driverID uuid.UUID, req *entities.SetScheduleRequest) (*entities.DriverSchedule, error)
+	DeleteSchedule(ctx context.Context, driverID uuid.UUID) error
+	// EnforceSchedules переводит свободных водителей вне окон расписания в неактивные
+	EnforceSchedules(ctx context.Context) (*entities.ScheduleEnforcement, error)
+}
+
+// SchedulePolicy параметры расписаний доступности
+type SchedulePolicy struct {
+	// DefaultTimezone часовой пояс расписаний, в запросе которых он не указан
+	DefaultTimezone string
+}
+
+// scheduleService реализация ScheduleService
+type scheduleService struct {
+	scheduleRepo  repositories.ScheduleRepository
+	driverRepo    repositories.DriverRepository
+	driverService DriverService
+	policy        SchedulePolicy
+	logger        *zap.Logger
+}
+
+// NewScheduleService создает новый ScheduleService
+func NewScheduleService(
+	scheduleRepo repositories.ScheduleRepository,
+	driverRepo repositories.DriverRepository,
+	driverService DriverService,
+	policy SchedulePolicy,
+	logger *zap.Logger,
+) ScheduleService {
+	return &scheduleService{
+		scheduleRepo:  scheduleRepo,
+		driverRepo:    driverRepo,
+		driverService: driverService,
+		policy:        policy,
+		logger:        logger,
+	}
+}
+
+// GetSchedule получает расписание существующего водителя
+func (s *scheduleService) GetSchedule(ctx context.Context, driverID uuid.UUID) (*entities.DriverSchedule, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	return s.scheduleRepo.GetByDriverID(ctx, driverID)
+}
+
+// SetSchedule заменяет расписание водителя
+func (s *scheduleService) SetSchedule(ctx context.Context, driverID uuid.UUID, req *entities.SetScheduleRequest) (*entities.DriverSchedule, error) {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	schedule := &entities.DriverSchedule{
+		DriverID:  driverID,
+		Timezone:  req.Timezone,
+		Windows:   req.Windows,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if schedule.Timezone == "" {
+		schedule.Timezone = s.policy.DefaultTimezone
+	}
+	schedule.Normalize()
+	if err := schedule.Validate(); err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.scheduleRepo.GetByDriverID(ctx, driverID); err == nil {
+		schedule.CreatedAt = existing.CreatedAt
+	} else if err != entities.ErrScheduleNotFound {
+		return nil, err
+	}
+
+	if err := s.scheduleRepo.Upsert(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Driver schedule updated",
+		zap.String("driver_id", driverID.String()),
+		zap.String("timezone", schedule.Timezone),
+		zap.Int("windows", len(schedule.Windows)),
+	)
+
+	return schedule, nil
+}
+
+// DeleteSchedule удаляет расписание: водитель снова доступен в любое время
+func (s *scheduleService) DeleteSchedule(ctx context.Context, driverID uuid.UUID) error {
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return err
+	}
+	if err := s.scheduleRepo.Delete(ctx, driverID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Driver schedule deleted", zap.String("driver_id", driverID.String()))
+	return nil
+}
+
+// EnforceSchedules проходит все расписания и переводит водителей в статусе available
+// вне окон расписания в inactive. Водители на смене и на заказе не затрагиваются:
+// смену водитель завершает сам. Ошибка по одному водителю не останавливает обход
+func (s *scheduleService) EnforceSchedules(ctx context.Context) (*entities.ScheduleEnforcement, error) {
+	result := &entities.ScheduleEnforcement{}
+	now := time.Now()
+
+	var after uuid.UUID
+	for {
+		schedules, err := s.scheduleRepo.List(ctx, after, scheduleBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(schedules) == 0 {
+			break
+		}
+		after = schedules[len(schedules)-1].DriverID
+
+		for _, schedule := range schedules {
+			result.Checked++
+			if schedule.IsAvailableAt(now) {
+				continue
+			}
+
+			driver, err := s.driverRepo.GetByID(ctx, schedule.DriverID)
+			if err != nil {
+				if err != entities.ErrDriverNotFound {
+					s.logger.Error("Failed to get driver for schedule check",
+						zap.Error(err),
+						zap.String("driver_id", schedule.DriverID.String()),
+					)
+					result.Failed++
+				}
+				continue
+			}
+			if driver.Status != entities.StatusAvailable {
+				continue
+			}
+
+			if err := s.driverService.ChangeDriverStatus(ctx, driver.ID, entities.StatusInactive); err != nil {
+				s.logger.Error("Failed to deactivate driver outside schedule",
+					zap.Error(err),
+					zap.String("driver_id", driver.ID.String()),
+				)
+				result.Failed++
+				continue
+			}
+			result.Deactivated++
+		}
+	}
+
+	s.logger.Info("Driver schedules enforced",
+		zap.Int("checked", result.Checked),
+		zap.Int("deactivated", result.Deactivated),
+		zap.Int("failed", result.Failed),
+	)
+
+	return result, nil
+}
+
+// withinSchedule проверяет, попадает ли момент в расписание водителя. Водитель без
+// расписания, как и при выключенных расписаниях (scheduleRepo = nil), доступен всегда
+func withinSchedule(ctx context.Context, scheduleRepo repositories.ScheduleRepository, driverID uuid.UUID, at time.Time) (bool, error) {
+	if scheduleRepo == nil {
+		return true, nil
+	}
+
+	schedule, err := scheduleRepo.GetByDriverID(ctx, driverID)
+	if err == entities.ErrScheduleNotFound {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return schedule.IsAvailableAt(at), nil
+}
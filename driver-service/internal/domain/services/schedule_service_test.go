@@ -0,0 +1,194 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// alwaysWindow окно расписания, покрывающее всю неделю
+var alwaysWindow = entities.AvailabilityWindow{
+	Days:  []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+	Start: "00:00",
+	End:   "24:00",
+}
+
+// offHoursWindow окно текущего дня недели, которое уже закончилось или еще не началось
+func offHoursWindow() entities.AvailabilityWindow {
+	now := time.Now().UTC()
+	day := []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}[now.Weekday()]
+	start := now.Add(2 * time.Hour)
+	end := now.Add(3 * time.Hour)
+	if start.Day() != now.Day() || end.Day() != now.Day() {
+		// Ближе к полуночи берем окно в начале суток, которое уже закончилось
+		return entities.AvailabilityWindow{Days: []string{day}, Start: "00:00", End: "00:30"}
+	}
+	return entities.AvailabilityWindow{Days: []string{day}, Start: start.Format("15:04"), End: end.Format("15:04")}
+}
+
+type scheduleFixture struct {
+	schedules     ScheduleService
+	scheduleRepo  *memory.ScheduleRepository
+	driverRepo    *memory.DriverRepository
+	driverService DriverService
+}
+
+func newScheduleFixture() *scheduleFixture {
+	scheduleRepo := memory.NewScheduleRepository()
+	driverRepo := memory.NewDriverRepository()
+	driverService := NewDriverService(driverRepo, memory.NewDocumentRepository(), scheduleRepo, &recordingEventPublisher{}, zap.NewNop())
+	return &scheduleFixture{
+		schedules:     NewScheduleService(scheduleRepo, driverRepo, driverService, SchedulePolicy{DefaultTimezone: "UTC"}, zap.NewNop()),
+		scheduleRepo:  scheduleRepo,
+		driverRepo:    driverRepo,
+		driverService: driverService,
+	}
+}
+
+func (f *scheduleFixture) availableDriver(t *testing.T, suffix string) *entities.Driver {
+	t.Helper()
+	driver, err := f.driverService.CreateDriver(context.Background(), newTestDriver(suffix))
+	require.NoError(t, err)
+	require.NoError(t, f.driverRepo.UpdateStatus(context.Background(), driver.ID, entities.StatusAvailable))
+	return driver
+}
+
+func TestScheduleService_SetGetDelete(t *testing.T) {
+	ctx := context.Background()
+	f := newScheduleFixture()
+	driver := f.availableDriver(t, "71")
+
+	_, err := f.schedules.GetSchedule(ctx, driver.ID)
+	assert.Equal(t, entities.ErrScheduleNotFound, err)
+
+	_, err = f.schedules.SetSchedule(ctx, uuid.New(), &entities.SetScheduleRequest{Windows: []entities.AvailabilityWindow{alwaysWindow}})
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+
+	_, err = f.schedules.SetSchedule(ctx, driver.ID, &entities.SetScheduleRequest{
+		Windows: []entities.AvailabilityWindow{{Days: []string{"mon"}, Start: "18:00", End: "18:00"}},
+	})
+	assert.Equal(t, entities.ErrInvalidSchedule, err)
+
+	created, err := f.schedules.SetSchedule(ctx, driver.ID, &entities.SetScheduleRequest{
+		Windows: []entities.AvailabilityWindow{{Days: []string{"MON", "fri"}, Start: "08:00", End: "18:00"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", created.Timezone, "default timezone is applied")
+	assert.Equal(t, []string{"mon", "fri"}, created.Windows[0].Days)
+
+	updated, err := f.schedules.SetSchedule(ctx, driver.ID, &entities.SetScheduleRequest{
+		Timezone: "Europe/Moscow",
+		Windows:  []entities.AvailabilityWindow{alwaysWindow},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, created.CreatedAt, updated.CreatedAt)
+
+	fetched, err := f.schedules.GetSchedule(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Europe/Moscow", fetched.Timezone)
+	require.Len(t, fetched.Windows, 1)
+
+	require.NoError(t, f.schedules.DeleteSchedule(ctx, driver.ID))
+	assert.Equal(t, entities.ErrScheduleNotFound, f.schedules.DeleteSchedule(ctx, driver.ID))
+}
+
+func TestScheduleService_DriverAvailability(t *testing.T) {
+	ctx := context.Background()
+	f := newScheduleFixture()
+	driver := f.availableDriver(t, "72")
+
+	// Без расписания водитель доступен в любое время
+	available, err := f.driverService.IsDriverAvailable(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.True(t, available)
+
+	_, err = f.schedules.SetSchedule(ctx, driver.ID, &entities.SetScheduleRequest{
+		Windows: []entities.AvailabilityWindow{offHoursWindow()},
+	})
+	require.NoError(t, err)
+
+	available, err = f.driverService.IsDriverAvailable(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.False(t, available)
+
+	_, err = f.schedules.SetSchedule(ctx, driver.ID, &entities.SetScheduleRequest{
+		Windows: []entities.AvailabilityWindow{alwaysWindow},
+	})
+	require.NoError(t, err)
+
+	available, err = f.driverService.IsDriverAvailable(ctx, driver.ID)
+	require.NoError(t, err)
+	assert.True(t, available)
+}
+
+func TestScheduleService_NearbyRespectsSchedule(t *testing.T) {
+	ctx := context.Background()
+	f := newScheduleFixture()
+	locationRepo := memory.NewLocationRepository()
+	locations := NewLocationService(locationRepo, f.driverRepo, f.scheduleRepo, &recordingEventPublisher{}, nil, nil, nil, LocationPolicy{}, zap.NewNop())
+
+	onDuty := f.availableDriver(t, "73")
+	offDuty := f.availableDriver(t, "74")
+	_, err := f.schedules.SetSchedule(ctx, offDuty.ID, &entities.SetScheduleRequest{
+		Windows: []entities.AvailabilityWindow{offHoursWindow()},
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, locations.UpdateLocation(ctx, entities.NewDriverLocation(onDuty.ID, 55.7558, 37.6173, now)))
+	require.NoError(t, locations.UpdateLocation(ctx, entities.NewDriverLocation(offDuty.ID, 55.7559, 37.6174, now)))
+
+	nearby, err := locations.GetNearbyDrivers(ctx, 55.7558, 37.6173, 1, 10, nil)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, onDuty.ID, nearby[0].DriverID)
+
+	// Водитель вне расписания ближе к точке поиска и не занимает место в лимите
+	nearby, err = locations.GetNearbyDrivers(ctx, 55.7559, 37.6174, 1, 1, nil)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, onDuty.ID, nearby[0].DriverID)
+}
+
+func TestScheduleService_EnforceSchedules(t *testing.T) {
+	ctx := context.Background()
+	f := newScheduleFixture()
+
+	inWindow := f.availableDriver(t, "75")
+	outOfWindow := f.availableDriver(t, "76")
+	onShift := f.availableDriver(t, "77")
+	require.NoError(t, f.driverRepo.UpdateStatus(ctx, onShift.ID, entities.StatusOnShift))
+
+	set := func(driverID uuid.UUID, window entities.AvailabilityWindow) {
+		_, err := f.schedules.SetSchedule(ctx, driverID, &entities.SetScheduleRequest{
+			Windows: []entities.AvailabilityWindow{window},
+		})
+		require.NoError(t, err)
+	}
+	set(inWindow.ID, alwaysWindow)
+	set(outOfWindow.ID, offHoursWindow())
+	set(onShift.ID, offHoursWindow())
+
+	result, err := f.schedules.EnforceSchedules(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Checked)
+	assert.Equal(t, 1, result.Deactivated)
+	assert.Equal(t, 0, result.Failed)
+
+	status := func(id uuid.UUID) entities.Status {
+		driver, err := f.driverRepo.GetByID(ctx, id)
+		require.NoError(t, err)
+		return driver.Status
+	}
+	assert.Equal(t, entities.StatusAvailable, status(inWindow.ID))
+	assert.Equal(t, entities.StatusInactive, status(outOfWindow.ID))
+	assert.Equal(t, entities.StatusOnShift, status(onShift.ID), "drivers on shift are not interrupted")
+}
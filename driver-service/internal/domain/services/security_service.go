@@ -0,0 +1,401 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SecurityPolicy пороги обнаружения подозрительной активности
+type SecurityPolicy struct {
+	// HistoryWindow период сессий, с которыми сравниваются новое устройство и страна
+	HistoryWindow time.Duration
+	// ConcurrentWindow сессии с запросами в этом окне считаются одновременными
+	ConcurrentWindow      time.Duration
+	MaxConcurrentSessions int
+	// MaxPlausibleSpeedKmh скорость между соседними точками, выше которой перемещение — скачок
+	MaxPlausibleSpeedKmh float64
+	MinJumpDistanceKm    float64
+	// AlertCooldown окно, в котором повторное событие того же типа не создается
+	AlertCooldown time.Duration
+	// SessionCacheTTL как долго проверенная сессия не перечитывается из репозитория
+	SessionCacheTTL time.Duration
+	// AutoRevoke типы событий, при которых все сессии водителя отзываются сразу
+	AutoRevoke []entities.SecurityEventType
+}
+
+// SecurityService интерфейс обнаружения подозрительной активности в аккаунтах водителей
+type SecurityService interface {
+	// ObserveSession учитывает запрос водителя в сессии и проверяет новое устройство,
+	// новую страну и одновременные сессии. Для отозванной сессии возвращает ErrSessionRevoked
+	ObserveSession(ctx context.Context, obs *entities.SessionObservation) error
+	// HandleLocationEvent проверяет последнее перемещение водителя на невозможную скорость
+	HandleLocationEvent(ctx context.Context, eventType string, driverID uuid.UUID) error
+	ListEvents(ctx context.Context, filters *entities.SecurityEventFilters) ([]*entities.SecurityEvent, error)
+	// ReviewEvent закрывает открытое событие решением администратора
+	ReviewEvent(ctx context.Context, id uuid.UUID, review *entities.SecurityReview) (*entities.SecurityEvent, error)
+	// RevokeDeviceSessions отзывает сессии водителя с одного устройства и возвращает их число
+	RevokeDeviceSessions(ctx context.Context, driverID uuid.UUID, deviceID string) (int, error)
+}
+
+// securityService реализация SecurityService
+type securityService struct {
+	securityRepo repositories.SecurityRepository
+	locationRepo repositories.LocationRepository
+	eventBus     EventPublisher
+	policy       SecurityPolicy
+	logger       *zap.Logger
+
+	// verified водители недавно проверенных сессий по ID сессии: без кэша каждый запрос
+	// водителя читал бы и записывал сессию в базе
+	verified *TTLCache[string, uuid.UUID]
+}
+
+// NewSecurityService создает новый SecurityService
+func NewSecurityService(
+	securityRepo repositories.SecurityRepository,
+	locationRepo repositories.LocationRepository,
+	eventBus EventPublisher,
+	policy SecurityPolicy,
+	logger *zap.Logger,
+) SecurityService {
+	return &securityService{
+		securityRepo: securityRepo,
+		locationRepo: locationRepo,
+		eventBus:     eventBus,
+		policy:       policy,
+		logger:       logger,
+		verified:     NewTTLCache[string, uuid.UUID](policy.SessionCacheTTL),
+	}
+}
+
+// ObserveSession учитывает запрос водителя в сессии
+func (s *securityService) ObserveSession(ctx context.Context, obs *entities.SessionObservation) error {
+	if s.isVerified(obs.SessionID, obs.At) {
+		return nil
+	}
+
+	stored, err := s.securityRepo.GetSession(ctx, obs.SessionID)
+	switch {
+	case err == entities.ErrSessionNotFound:
+		stored = nil
+	case err != nil:
+		return err
+	case stored.IsRevoked():
+		return entities.ErrSessionRevoked
+	case stored.DriverID != obs.DriverID:
+		// Идентификатор сессии выдан другому водителю: токен подделан или перепутан
+		return entities.ErrSessionRevoked
+	}
+
+	history, err := s.securityRepo.ListSessions(ctx, obs.DriverID, obs.At.Add(-s.policy.HistoryWindow))
+	if err != nil {
+		return err
+	}
+
+	session := &entities.DriverSession{
+		ID:          obs.SessionID,
+		DriverID:    obs.DriverID,
+		DeviceID:    obs.DeviceID,
+		Country:     obs.Country,
+		IPAddress:   obs.IPAddress,
+		FirstSeenAt: obs.At,
+		LastSeenAt:  obs.At,
+	}
+	if stored != nil {
+		session.FirstSeenAt = stored.FirstSeenAt
+		session.DeviceID = stored.DeviceID
+	}
+	if err := s.securityRepo.SaveSession(ctx, session); err != nil {
+		return err
+	}
+
+	revoked := false
+	for _, event := range s.detectSessionAnomalies(session, stored, history) {
+		raised, err := s.raise(ctx, event)
+		if err != nil {
+			return err
+		}
+		revoked = revoked || (raised && event.SessionsRevoked)
+	}
+	if revoked {
+		return entities.ErrSessionRevoked
+	}
+
+	s.markVerified(session, obs.At)
+	return nil
+}
+
+// detectSessionAnomalies сравнивает сессию с предыдущими сессиями водителя.
+// Для первой сессии водителя новое устройство и страна не определяются. Одновременными
+// считаются только сессии с известным устройством: повторный вход с того же телефона
+// без device_id неотличим от входа с другого
+func (s *securityService) detectSessionAnomalies(
+	session, stored *entities.DriverSession,
+	history []*entities.DriverSession,
+) []*entities.SecurityEvent {
+	var (
+		events    []*entities.SecurityEvent
+		devices   = make(map[string]bool)
+		countries = make(map[string]bool)
+		active    = make(map[string]bool)
+		previous  int
+	)
+	for _, other := range history {
+		if other.ID == session.ID {
+			continue
+		}
+		previous++
+		if other.DeviceID != "" {
+			devices[other.DeviceID] = true
+		}
+		if other.Country != "" {
+			countries[other.Country] = true
+		}
+		if other.DeviceID != "" && !other.IsRevoked() &&
+			!other.LastSeenAt.Before(session.LastSeenAt.Add(-s.policy.ConcurrentWindow)) {
+			active[other.DeviceID] = true
+		}
+	}
+	if stored != nil && stored.Country != "" {
+		countries[stored.Country] = true
+	}
+
+	if stored == nil && previous > 0 && session.DeviceID != "" && !devices[session.DeviceID] {
+		events = append(events, entities.NewSecurityEvent(session.DriverID, entities.SecurityEventNewDevice, entities.Metadata{
+			"session_id": session.ID,
+			"device_id":  session.DeviceID,
+			"ip_address": session.IPAddress,
+		}))
+	}
+	if previous+len(countries) > 0 && session.Country != "" && !countries[session.Country] {
+		events = append(events, entities.NewSecurityEvent(session.DriverID, entities.SecurityEventNewCountry, entities.Metadata{
+			"session_id": session.ID,
+			"country":    session.Country,
+			"ip_address": session.IPAddress,
+		}))
+	}
+	if session.DeviceID != "" {
+		active[session.DeviceID] = true
+	}
+	if len(active) > s.policy.MaxConcurrentSessions {
+		events = append(events, entities.NewSecurityEvent(session.DriverID, entities.SecurityEventSimultaneousSessions, entities.Metadata{
+			"session_id": session.ID,
+			"devices":    len(active),
+		}))
+	}
+
+	return events
+}
+
+// HandleLocationEvent сравнивает две последние точки водителя
+func (s *securityService) HandleLocationEvent(ctx context.Context, eventType string, driverID uuid.UUID) error {
+	locations, err := s.locationRepo.List(ctx, &entities.LocationFilters{DriverID: &driverID, Limit: 2})
+	if err != nil {
+		return fmt.Errorf("failed to get latest locations: %w", err)
+	}
+	if len(locations) < 2 {
+		return nil
+	}
+
+	latest, previous := locations[0], locations[1]
+	distance := previous.DistanceTo(latest)
+	if distance < s.policy.MinJumpDistanceKm {
+		return nil
+	}
+
+	// Точки с одинаковым временем на заметном расстоянии — скачок при любой скорости
+	elapsed := latest.RecordedAt.Sub(previous.RecordedAt)
+	speed := -1.0
+	if elapsed > 0 {
+		speed = distance / elapsed.Hours()
+		if speed <= s.policy.MaxPlausibleSpeedKmh {
+			return nil
+		}
+	}
+
+	details := entities.Metadata{
+		"from_location_id": previous.ID.String(),
+		"to_location_id":   latest.ID.String(),
+		"distance_km":      distance,
+		"elapsed_seconds":  int64(elapsed.Seconds()),
+	}
+	if speed >= 0 {
+		details["speed_kmh"] = speed
+	}
+
+	_, err = s.raise(ctx, entities.NewSecurityEvent(driverID, entities.SecurityEventLocationJump, details))
+	return err
+}
+
+// raise сохраняет событие, если за окно AlertCooldown у водителя нет открытого события
+// того же типа, публикует driver.security.alert и при необходимости отзывает сессии
+func (s *securityService) raise(ctx context.Context, event *entities.SecurityEvent) (bool, error) {
+	if s.policy.AlertCooldown > 0 {
+		since := event.CreatedAt.Add(-s.policy.AlertCooldown)
+		recent, err := s.securityRepo.ListEvents(ctx, &entities.SecurityEventFilters{
+			DriverID: &event.DriverID,
+			Type:     event.Type,
+			Status:   entities.SecurityEventOpen,
+			Since:    &since,
+			Limit:    1,
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(recent) > 0 {
+			return false, nil
+		}
+	}
+
+	if s.autoRevokes(event.Type) {
+		revoked, err := s.revokeSessions(ctx, event.DriverID, event.CreatedAt)
+		if err != nil {
+			return false, err
+		}
+		event.SessionsRevoked = true
+		event.Details["revoked_sessions"] = revoked
+	}
+
+	if err := s.securityRepo.CreateEvent(ctx, event); err != nil {
+		return false, err
+	}
+
+	s.logger.Warn("Security event raised",
+		zap.String("event_id", event.ID.String()),
+		zap.String("driver_id", event.DriverID.String()),
+		zap.String("type", string(event.Type)),
+		zap.Bool("sessions_revoked", event.SessionsRevoked),
+	)
+
+	eventData := map[string]interface{}{
+		"event_id":         event.ID,
+		"type":             event.Type,
+		"details":          event.Details,
+		"sessions_revoked": event.SessionsRevoked,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventSecurityAlert, event.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish security alert event",
+			zap.Error(err),
+			zap.String("event_id", event.ID.String()),
+		)
+	}
+
+	return true, nil
+}
+
+// autoRevokes проверяет, отзываются ли сессии при событии этого типа
+func (s *securityService) autoRevokes(eventType entities.SecurityEventType) bool {
+	for _, t := range s.policy.AutoRevoke {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// revokeSessions отзывает сессии водителя и убирает их из кэша проверенных
+func (s *securityService) revokeSessions(ctx context.Context, driverID uuid.UUID, at time.Time) (int, error) {
+	revoked, err := s.securityRepo.RevokeSessions(ctx, driverID, at)
+	if err != nil {
+		return 0, err
+	}
+
+	s.forgetVerified(driverID)
+
+	s.logger.Info("Driver sessions revoked",
+		zap.String("driver_id", driverID.String()),
+		zap.Int("count", revoked),
+	)
+	return revoked, nil
+}
+
+// RevokeDeviceSessions отзывает сессии водителя с устройства. Кэш проверенных сессий
+// не хранит устройство, поэтому из него убираются все сессии водителя: остальные
+// будут проверены заново при следующем запросе
+func (s *securityService) RevokeDeviceSessions(ctx context.Context, driverID uuid.UUID, deviceID string) (int, error) {
+	revoked, err := s.securityRepo.RevokeDeviceSessions(ctx, driverID, deviceID, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	s.forgetVerified(driverID)
+
+	s.logger.Info("Driver device sessions revoked",
+		zap.String("driver_id", driverID.String()),
+		zap.String("device_id", deviceID),
+		zap.Int("count", revoked),
+	)
+	return revoked, nil
+}
+
+// forgetVerified убирает сессии водителя из кэша проверенных
+func (s *securityService) forgetVerified(driverID uuid.UUID) {
+	s.verified.DeleteFunc(func(sessionID string, sessionDriverID uuid.UUID) bool {
+		return sessionDriverID == driverID
+	})
+}
+
+// isVerified проверяет, что сессия недавно проверялась
+func (s *securityService) isVerified(sessionID string, at time.Time) bool {
+	_, ok := s.verified.Get(sessionID, at)
+	return ok
+}
+
+// markVerified запоминает проверенную сессию на SessionCacheTTL
+func (s *securityService) markVerified(session *entities.DriverSession, at time.Time) {
+	s.verified.Set(session.ID, session.DriverID, at)
+}
+
+// ListEvents получает события безопасности по фильтрам
+func (s *securityService) ListEvents(ctx context.Context, filters *entities.SecurityEventFilters) ([]*entities.SecurityEvent, error) {
+	events, err := s.securityRepo.ListEvents(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list security events", zap.Error(err))
+		return nil, err
+	}
+	return events, nil
+}
+
+// ReviewEvent закрывает открытое событие и по решению администратора отзывает сессии водителя
+func (s *securityService) ReviewEvent(ctx context.Context, id uuid.UUID, review *entities.SecurityReview) (*entities.SecurityEvent, error) {
+	event, err := s.securityRepo.GetEvent(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if event.Status != entities.SecurityEventOpen {
+		return nil, entities.ErrSecurityEventReviewed
+	}
+
+	now := time.Now()
+	if review.RevokeSessions {
+		if _, err := s.revokeSessions(ctx, event.DriverID, now); err != nil {
+			return nil, err
+		}
+		event.SessionsRevoked = true
+	}
+
+	event.Status = review.Status
+	event.ReviewedBy = &review.ReviewerID
+	event.ReviewedAt = &now
+	if review.Note != "" {
+		event.ReviewNote = &review.Note
+	}
+
+	if err := s.securityRepo.UpdateEvent(ctx, event); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Security event reviewed",
+		zap.String("event_id", event.ID.String()),
+		zap.String("status", string(event.Status)),
+		zap.String("reviewer_id", review.ReviewerID),
+	)
+	return event, nil
+}
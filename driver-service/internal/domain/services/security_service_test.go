@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestSecurityService(policy SecurityPolicy) (SecurityService, *memory.SecurityRepository, *memory.LocationRepository, *recordingEventPublisher) {
+	securityRepo := memory.NewSecurityRepository()
+	locationRepo := memory.NewLocationRepository()
+	events := &recordingEventPublisher{}
+	if policy.HistoryWindow == 0 {
+		policy.HistoryWindow = 90 * 24 * time.Hour
+	}
+	if policy.MaxConcurrentSessions == 0 {
+		policy.MaxConcurrentSessions = 1
+	}
+	if policy.MaxPlausibleSpeedKmh == 0 {
+		policy.MaxPlausibleSpeedKmh = 300
+	}
+	policy.ConcurrentWindow = 5 * time.Minute
+	policy.AlertCooldown = 15 * time.Minute
+	service := NewSecurityService(securityRepo, locationRepo, events, policy, zap.NewNop())
+	return service, securityRepo, locationRepo, events
+}
+
+func listSecurityEvents(t *testing.T, repo *memory.SecurityRepository, driverID uuid.UUID) []*entities.SecurityEvent {
+	t.Helper()
+	events, err := repo.ListEvents(context.Background(), &entities.SecurityEventFilters{DriverID: &driverID})
+	require.NoError(t, err)
+	return events
+}
+
+func TestSecurityService_NewDeviceAndCountry(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, events := newTestSecurityService(SecurityPolicy{})
+	driverID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+
+	// Первая сессия водителя не сравнивается с историей
+	require.NoError(t, service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s1", DeviceID: "phone-1", Country: "RU", At: start,
+	}))
+	assert.Empty(t, listSecurityEvents(t, repo, driverID))
+
+	require.NoError(t, service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s2", DeviceID: "phone-2", Country: "KZ", At: start.Add(30 * time.Minute),
+	}))
+
+	raised := listSecurityEvents(t, repo, driverID)
+	types := make(map[entities.SecurityEventType]bool)
+	for _, event := range raised {
+		types[event.Type] = true
+		assert.Equal(t, entities.SecurityEventOpen, event.Status)
+	}
+	assert.Equal(t, map[entities.SecurityEventType]bool{
+		entities.SecurityEventNewDevice:  true,
+		entities.SecurityEventNewCountry: true,
+	}, types, "first session is outside the concurrent window")
+	assert.True(t, events.has("driver.security.alert"))
+
+	// Новый вход с известного устройства не создает событий
+	require.NoError(t, service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s3", DeviceID: "phone-1", Country: "RU", At: start.Add(50 * time.Minute),
+	}))
+	assert.Len(t, listSecurityEvents(t, repo, driverID), 2)
+}
+
+func TestSecurityService_SimultaneousSessionsAutoRevoke(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestSecurityService(SecurityPolicy{
+		SessionCacheTTL: time.Minute,
+		AutoRevoke:      []entities.SecurityEventType{entities.SecurityEventSimultaneousSessions},
+	})
+	driverID := uuid.New()
+	now := time.Now()
+
+	require.NoError(t, service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s1", DeviceID: "phone-1", At: now.Add(-time.Minute),
+	}))
+
+	err := service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s2", DeviceID: "phone-2", At: now,
+	})
+	assert.ErrorIs(t, err, entities.ErrSessionRevoked)
+
+	raised := listSecurityEvents(t, repo, driverID)
+	var simultaneous *entities.SecurityEvent
+	for _, event := range raised {
+		if event.Type == entities.SecurityEventSimultaneousSessions {
+			simultaneous = event
+		}
+	}
+	require.NotNil(t, simultaneous)
+	assert.True(t, simultaneous.SessionsRevoked)
+
+	// Ранее проверенная сессия отзывается без ожидания истечения кэша
+	err = service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s1", DeviceID: "phone-1", At: now.Add(time.Second),
+	})
+	assert.ErrorIs(t, err, entities.ErrSessionRevoked)
+}
+
+func TestSecurityService_LocationJump(t *testing.T) {
+	ctx := context.Background()
+	service, repo, locationRepo, _ := newTestSecurityService(SecurityPolicy{MinJumpDistanceKm: 1})
+	driverID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+
+	// Москва → Санкт-Петербург за минуту
+	saveLocation(t, locationRepo, entities.NewDriverLocation(driverID, 55.75, 37.61, start))
+	saveLocation(t, locationRepo, entities.NewDriverLocation(driverID, 59.93, 30.31, start.Add(time.Minute)))
+	require.NoError(t, service.HandleLocationEvent(ctx, "driver.location.updated", driverID))
+
+	raised := listSecurityEvents(t, repo, driverID)
+	require.Len(t, raised, 1)
+	assert.Equal(t, entities.SecurityEventLocationJump, raised[0].Type)
+	assert.Greater(t, raised[0].Details["speed_kmh"], 300.0)
+
+	// Повторный скачок в окне AlertCooldown не создает нового события
+	saveLocation(t, locationRepo, entities.NewDriverLocation(driverID, 55.75, 37.61, start.Add(2*time.Minute)))
+	require.NoError(t, service.HandleLocationEvent(ctx, "driver.location.updated", driverID))
+	assert.Len(t, listSecurityEvents(t, repo, driverID), 1)
+
+	// Обычное движение по городу
+	other := uuid.New()
+	saveLocation(t, locationRepo, entities.NewDriverLocation(other, 55.75, 37.61, start))
+	saveLocation(t, locationRepo, entities.NewDriverLocation(other, 55.76, 37.62, start.Add(time.Minute)))
+	require.NoError(t, service.HandleLocationEvent(ctx, "driver.location.updated", other))
+	assert.Empty(t, listSecurityEvents(t, repo, other))
+}
+
+func TestSecurityService_ReviewEvent(t *testing.T) {
+	ctx := context.Background()
+	service, repo, _, _ := newTestSecurityService(SecurityPolicy{})
+	driverID := uuid.New()
+
+	require.NoError(t, service.ObserveSession(ctx, &entities.SessionObservation{
+		DriverID: driverID, SessionID: "s1", DeviceID: "phone-1", At: time.Now(),
+	}))
+	event := entities.NewSecurityEvent(driverID, entities.SecurityEventNewDevice, nil)
+	require.NoError(t, repo.CreateEvent(ctx, event))
+
+	reviewed, err := service.ReviewEvent(ctx, event.ID, &entities.SecurityReview{
+		Status:         entities.SecurityEventConfirmed,
+		ReviewerID:     "admin-1",
+		Note:           "driver confirmed phone was stolen",
+		RevokeSessions: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.SecurityEventConfirmed, reviewed.Status)
+	assert.True(t, reviewed.SessionsRevoked)
+	require.NotNil(t, reviewed.ReviewedBy)
+	assert.Equal(t, "admin-1", *reviewed.ReviewedBy)
+
+	session, err := repo.GetSession(ctx, "s1")
+	require.NoError(t, err)
+	assert.True(t, session.IsRevoked())
+
+	_, err = service.ReviewEvent(ctx, event.ID, &entities.SecurityReview{Status: entities.SecurityEventDismissed, ReviewerID: "admin-2"})
+	assert.Equal(t, entities.ErrSecurityEventReviewed, err)
+
+	_, err = service.ReviewEvent(ctx, uuid.New(), &entities.SecurityReview{Status: entities.SecurityEventDismissed, ReviewerID: "admin-2"})
+	assert.Equal(t, entities.ErrSecurityEventNotFound, err)
+}
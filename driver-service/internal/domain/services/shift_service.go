@@ -0,0 +1,621 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ShiftPolicy параметры смен водителей
+type ShiftPolicy struct {
+	// MaxDuration длительность, после которой смена закрывается автоматически; 0 — без ограничения
+	MaxDuration time.Duration
+	// MaxGapInterval интервал между точками, после которого водитель считается не на связи;
+	// по нему считается время на связи автоматически закрытой смены
+	MaxGapInterval time.Duration
+	// Rest требования к перерывам: по ним при завершении смены отмечается соблюдение отдыха
+	Rest entities.ShiftRestPolicy
+}
+
+// ShiftService интерфейс для управления сменами водителей
+type ShiftService interface {
+	StartShift(ctx context.Context, driverID uuid.UUID, req *entities.ShiftStartRequest) (*entities.DriverShift, error)
+	EndShift(ctx context.Context, driverID uuid.UUID, req *entities.ShiftEndRequest) (*entities.DriverShift, error)
+	GetActiveShift(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error)
+	ListShifts(ctx context.Context, filters *entities.ShiftFilters) ([]*entities.DriverShift, error)
+	CountShifts(ctx context.Context, filters *entities.ShiftFilters) (int, error)
+	// EndOverdueShifts закрывает смены длиннее ShiftPolicy.MaxDuration, считая их итоги по истории
+	// местоположений, и возвращает их число
+	EndOverdueShifts(ctx context.Context) (int, error)
+	// EndOfflineShift закрывает активную смену водителя, переставшего выходить на связь
+	EndOfflineShift(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error)
+	// StartBreak начинает перерыв в активной смене водителя
+	StartBreak(ctx context.Context, driverID uuid.UUID, req *entities.ShiftBreakStartRequest) (*entities.ShiftBreak, error)
+	// EndBreak заканчивает идущий перерыв в активной смене водителя
+	EndBreak(ctx context.Context, driverID uuid.UUID, req *entities.ShiftBreakEndRequest) (*entities.ShiftBreak, error)
+	// GetShiftBreaks возвращает перерывы смены водителя и соблюдение требований к отдыху
+	GetShiftBreaks(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.ShiftBreaksReport, error)
+	// GetShiftStats считает статистику по сменам с фильтрами (без учета Limit и Offset)
+	GetShiftStats(ctx context.Context, filters *entities.ShiftFilters) (*entities.ShiftStats, error)
+}
+
+// shiftService реализация ShiftService
+type shiftService struct {
+	shiftRepo         repositories.ShiftRepository
+	breakRepo         repositories.ShiftBreakRepository
+	driverRepo        repositories.DriverRepository
+	locationRepo      repositories.LocationRepository
+	txManager         repositories.TxManager
+	inspectionService InspectionService
+	notifier          NotificationSender
+	eventBus          EventPublisher
+	policy            ShiftPolicy
+	logger            *zap.Logger
+}
+
+// NewShiftService создает новый ShiftService.
+// txManager делает атомарными смену и статус водителя; nil — без транзакций (хранение в памяти).
+// notifier сообщает водителю об автоматическом закрытии смены и может быть nil
+func NewShiftService(
+	shiftRepo repositories.ShiftRepository,
+	breakRepo repositories.ShiftBreakRepository,
+	driverRepo repositories.DriverRepository,
+	locationRepo repositories.LocationRepository,
+	txManager repositories.TxManager,
+	inspectionService InspectionService,
+	notifier NotificationSender,
+	eventBus EventPublisher,
+	policy ShiftPolicy,
+	logger *zap.Logger,
+) ShiftService {
+	return &shiftService{
+		shiftRepo:         shiftRepo,
+		breakRepo:         breakRepo,
+		driverRepo:        driverRepo,
+		locationRepo:      locationRepo,
+		txManager:         txManager,
+		inspectionService: inspectionService,
+		notifier:          notifier,
+		eventBus:          eventBus,
+		policy:            policy,
+		logger:            logger,
+	}
+}
+
+// StartShift начинает смену водителя
+func (s *shiftService) StartShift(ctx context.Context, driverID uuid.UUID, req *entities.ShiftStartRequest) (*entities.DriverShift, error) {
+	driver, err := s.driverRepo.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	if driver.Status != entities.StatusAvailable {
+		return nil, entities.ErrDriverNotAvailable
+	}
+
+	if _, err := s.shiftRepo.GetActiveByDriverID(ctx, driverID); err == nil {
+		return nil, entities.ErrShiftExists
+	} else if err != entities.ErrShiftNotFound {
+		return nil, fmt.Errorf("failed to check active shift: %w", err)
+	}
+
+	// Проверяем техосмотр автомобиля
+	if req.VehicleID != nil && s.inspectionService != nil {
+		if err := s.inspectionService.EnsureVehicleCanOperate(ctx, *req.VehicleID); err != nil {
+			return nil, err
+		}
+	}
+
+	var startLocation *entities.DriverLocation
+	if req.Latitude != nil && req.Longitude != nil {
+		startLocation = &entities.DriverLocation{
+			Latitude:  *req.Latitude,
+			Longitude: *req.Longitude,
+		}
+	}
+
+	shift := entities.NewDriverShift(driverID, req.VehicleID, startLocation)
+	if req.Notes != nil {
+		shift.Metadata["notes"] = *req.Notes
+	}
+
+	// Смена и статус водителя сохраняются вместе: без статуса on_shift смена не начинается
+	err = repositories.WithTransaction(ctx, s.txManager, func(ctx context.Context) error {
+		if err := s.shiftRepo.Create(ctx, shift); err != nil {
+			return fmt.Errorf("failed to start shift: %w", err)
+		}
+		if err := s.driverRepo.UpdateStatus(ctx, driverID, entities.StatusOnShift); err != nil {
+			return fmt.Errorf("failed to update driver status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to start shift",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, err
+	}
+
+	eventData := map[string]interface{}{
+		"shift_id": shift.ID.String(),
+	}
+	if shift.VehicleID != nil {
+		eventData["vehicle_id"] = shift.VehicleID.String()
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventShiftStarted, driverID, eventData); err != nil {
+		s.logger.Error("Failed to publish shift started event",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	s.logger.Info("Shift started",
+		zap.String("shift_id", shift.ID.String()),
+		zap.String("driver_id", driverID.String()),
+	)
+
+	return shift, nil
+}
+
+// EndShift завершает активную смену водителя
+func (s *shiftService) EndShift(ctx context.Context, driverID uuid.UUID, req *entities.ShiftEndRequest) (*entities.DriverShift, error) {
+	shift, err := s.shiftRepo.GetActiveByDriverID(ctx, driverID)
+	if err != nil {
+		if err == entities.ErrShiftNotFound {
+			return nil, entities.ErrShiftNotActive
+		}
+		return nil, err
+	}
+
+	var endLocation *entities.DriverLocation
+	if req.Latitude != nil && req.Longitude != nil {
+		endLocation = &entities.DriverLocation{
+			Latitude:  *req.Latitude,
+			Longitude: *req.Longitude,
+		}
+	}
+
+	shift.End(endLocation)
+	if req.Notes != nil {
+		shift.Metadata["end_notes"] = *req.Notes
+	}
+
+	if err := s.finishShift(ctx, shift, nil); err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// EndOverdueShifts закрывает активные смены, которые длятся дольше ShiftPolicy.MaxDuration.
+// Пробег и время на связи закрытой смены считаются по истории местоположений, в метаданных
+// смены появляется auto_closed, а для расчета выплат публикуется driver.shift.auto_closed.
+// Смены водителей на заказе не трогаются до завершения поездки
+func (s *shiftService) EndOverdueShifts(ctx context.Context) (int, error) {
+	if s.policy.MaxDuration <= 0 {
+		return 0, nil
+	}
+
+	startedBefore := time.Now().Add(-s.policy.MaxDuration)
+	shifts, err := s.shiftRepo.List(ctx, &entities.ShiftFilters{
+		Status: []entities.ShiftStatus{entities.ShiftStatusActive},
+		To:     &startedBefore,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list overdue shifts: %w", err)
+	}
+
+	ended := 0
+	for _, shift := range shifts {
+		driver, err := s.driverRepo.GetByID(ctx, shift.DriverID)
+		if err != nil && err != entities.ErrDriverNotFound {
+			s.logger.Error("Failed to load driver of overdue shift",
+				zap.Error(err),
+				zap.String("shift_id", shift.ID.String()),
+			)
+			continue
+		}
+		if driver != nil && driver.Status == entities.StatusBusy {
+			continue
+		}
+
+		activity, err := s.closeOverdue(ctx, shift)
+		if err != nil {
+			s.logger.Error("Failed to compute overdue shift totals",
+				zap.Error(err),
+				zap.String("shift_id", shift.ID.String()),
+			)
+			continue
+		}
+
+		if err := s.finishShift(ctx, shift, map[string]interface{}{"auto_ended": true, "auto_closed": true}); err != nil {
+			continue
+		}
+		ended++
+
+		s.publishAutoClosed(ctx, shift, activity)
+		s.notifyAutoEnded(ctx, shift)
+	}
+
+	if ended > 0 {
+		s.logger.Info("Overdue shifts ended", zap.Int("count", ended))
+	}
+
+	return ended, nil
+}
+
+// closeOverdue завершает смену и считает ее итоги по точкам водителя за время смены:
+// пробег по GPS заменяет пробег поездок, а последняя точка становится концом смены
+func (s *shiftService) closeOverdue(ctx context.Context, shift *entities.DriverShift) (*entities.LocationActivity, error) {
+	shift.End(nil)
+
+	activity, err := s.locationRepo.GetActivity(ctx, shift.DriverID, shift.StartTime, *shift.EndTime, s.policy.MaxGapInterval)
+	if err != nil {
+		return nil, err
+	}
+	last, err := s.locationRepo.GetLatestByDriverID(ctx, shift.DriverID)
+	if err != nil && err != entities.ErrLocationNotFound {
+		return nil, err
+	}
+	if last != nil && !last.RecordedAt.Before(shift.StartTime) {
+		shift.EndLatitude = &last.Latitude
+		shift.EndLongitude = &last.Longitude
+	}
+
+	shift.TotalDistance = activity.DistanceKm
+	shift.Metadata["auto_ended"] = true
+	shift.Metadata["auto_closed"] = true
+	shift.Metadata["online_minutes"] = activity.OnlineSeconds / 60
+	return activity, nil
+}
+
+// publishAutoClosed публикует событие автоматического закрытия смены для расчета выплат
+func (s *shiftService) publishAutoClosed(ctx context.Context, shift *entities.DriverShift, activity *entities.LocationActivity) {
+	eventData := map[string]interface{}{
+		"shift_id":           shift.ID.String(),
+		"started_at":         shift.StartTime,
+		"ended_at":           *shift.EndTime,
+		"duration_minutes":   shift.GetDuration(),
+		"max_duration_hours": int(s.policy.MaxDuration.Hours()),
+		"total_distance":     shift.TotalDistance,
+		"online_minutes":     activity.OnlineSeconds / 60,
+		"total_trips":        shift.TotalTrips,
+		"total_earnings":     shift.TotalEarnings,
+	}
+	if err := s.eventBus.PublishDriverEvent(ctx, eventShiftAutoClosed, shift.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish shift auto closed event",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+	}
+}
+
+// EndOfflineShift закрывает активную смену водителя, от которого давно нет сигнала.
+// Возвращает ErrShiftNotActive, если активной смены нет
+func (s *shiftService) EndOfflineShift(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error) {
+	shift, err := s.shiftRepo.GetActiveByDriverID(ctx, driverID)
+	if err != nil {
+		if err == entities.ErrShiftNotFound {
+			return nil, entities.ErrShiftNotActive
+		}
+		return nil, err
+	}
+
+	shift.End(nil)
+	shift.Metadata["auto_ended"] = true
+	shift.Metadata["offline"] = true
+
+	if err := s.finishShift(ctx, shift, map[string]interface{}{"auto_ended": true, "offline": true}); err != nil {
+		return nil, err
+	}
+
+	return shift, nil
+}
+
+// finishShift считает итоги смены по истории местоположений, сохраняет завершенную смену,
+// освобождает водителя и публикует событие. extra дополняет данные события
+func (s *shiftService) finishShift(ctx context.Context, shift *entities.DriverShift, extra map[string]interface{}) error {
+	s.applyLocationStats(ctx, shift)
+
+	// Завершенная смена, закрытие идущего перерыва и освобождение водителя сохраняются вместе.
+	// Удаленного водителя освобождать не нужно: его смена закрывается без смены статуса
+	err := repositories.WithTransaction(ctx, s.txManager, func(ctx context.Context) error {
+		if err := s.closeBreaks(ctx, shift); err != nil {
+			return err
+		}
+		if err := s.shiftRepo.Update(ctx, shift); err != nil {
+			return fmt.Errorf("failed to end shift: %w", err)
+		}
+		err := s.driverRepo.UpdateStatus(ctx, shift.DriverID, entities.StatusAvailable)
+		if err != nil && err != entities.ErrDriverNotFound {
+			return fmt.Errorf("failed to update driver status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to end shift",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+		return err
+	}
+
+	eventData := map[string]interface{}{
+		"shift_id":         shift.ID.String(),
+		"duration_minutes": shift.GetDuration(),
+		"total_trips":      shift.TotalTrips,
+		"total_earnings":   shift.TotalEarnings,
+		"total_distance":   shift.TotalDistance,
+		"break_minutes":    shift.BreakMinutes,
+	}
+	if shift.RestCompliant != nil {
+		eventData["rest_compliant"] = *shift.RestCompliant
+	}
+	if shift.AverageSpeed != nil {
+		eventData["average_speed"] = *shift.AverageSpeed
+		eventData["idle_minutes"] = *shift.IdleMinutes
+	}
+	for key, value := range extra {
+		eventData[key] = value
+	}
+
+	if err := s.eventBus.PublishDriverEvent(ctx, eventShiftEnded, shift.DriverID, eventData); err != nil {
+		s.logger.Error("Failed to publish shift ended event",
+			zap.Error(err),
+			zap.String("driver_id", shift.DriverID.String()),
+		)
+	}
+
+	return nil
+}
+
+// closeBreaks заканчивает идущий перерыв завершаемой смены в момент ее окончания и записывает
+// в смену время перерывов и соблюдение требований к отдыху
+func (s *shiftService) closeBreaks(ctx context.Context, shift *entities.DriverShift) error {
+	breaks, err := s.breakRepo.ListByShift(ctx, shift.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list shift breaks: %w", err)
+	}
+
+	for _, shiftBreak := range breaks {
+		if !shiftBreak.IsOpen() {
+			continue
+		}
+		endedAt := *shift.EndTime
+		if endedAt.Before(shiftBreak.StartedAt) {
+			endedAt = shiftBreak.StartedAt
+		}
+		shiftBreak.EndedAt = &endedAt
+		if err := s.breakRepo.Update(ctx, shiftBreak); err != nil {
+			return fmt.Errorf("failed to end shift break: %w", err)
+		}
+	}
+
+	shift.ApplyRest(entities.EvaluateShiftRest(shift, breaks, s.policy.Rest, *shift.EndTime))
+	return nil
+}
+
+// applyLocationStats записывает в смену пробег, среднюю скорость и простой по точкам водителя
+// за время смены. Ошибка чтения истории не мешает завершить смену: остается пробег поездок
+func (s *shiftService) applyLocationStats(ctx context.Context, shift *entities.DriverShift) {
+	locations, err := s.locationRepo.GetByDriverIDInTimeRange(ctx, shift.DriverID, shift.StartTime, *shift.EndTime)
+	if err != nil {
+		s.logger.Error("Failed to load shift location history",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+		return
+	}
+
+	shift.ApplyLocationStats(entities.CalculateLocationStats(locations, s.policy.MaxGapInterval))
+}
+
+// notifyAutoEnded сообщает водителю, что смена закрыта автоматически
+func (s *shiftService) notifyAutoEnded(ctx context.Context, shift *entities.DriverShift) {
+	if s.notifier == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"shift_id":           shift.ID.String(),
+		"started_at":         shift.StartTime,
+		"ended_at":           *shift.EndTime,
+		"duration_minutes":   shift.GetDuration(),
+		"max_duration_hours": int(s.policy.MaxDuration.Hours()),
+	}
+	if err := s.notifier.SendToDriver(ctx, shift.DriverID, NotificationShiftAutoEnded, data); err != nil {
+		s.logger.Error("Failed to notify about auto-ended shift",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+	}
+}
+
+// GetActiveShift получает активную смену водителя
+func (s *shiftService) GetActiveShift(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error) {
+	return s.shiftRepo.GetActiveByDriverID(ctx, driverID)
+}
+
+// ListShifts получает список смен с фильтрами
+func (s *shiftService) ListShifts(ctx context.Context, filters *entities.ShiftFilters) ([]*entities.DriverShift, error) {
+	shifts, err := s.shiftRepo.List(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list shifts", zap.Error(err))
+		return nil, err
+	}
+
+	return shifts, nil
+}
+
+// CountShifts возвращает количество смен с фильтрами
+func (s *shiftService) CountShifts(ctx context.Context, filters *entities.ShiftFilters) (int, error) {
+	return s.shiftRepo.Count(ctx, filters)
+}
+
+// StartBreak начинает перерыв в активной смене водителя. Перерыв не может начаться, пока идет
+// прошлый, и не может пересекаться с прошлыми перерывами смены
+func (s *shiftService) StartBreak(ctx context.Context, driverID uuid.UUID, req *entities.ShiftBreakStartRequest) (*entities.ShiftBreak, error) {
+	shift, err := s.activeShift(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks, err := s.breakRepo.ListByShift(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift breaks: %w", err)
+	}
+
+	now := time.Now()
+	startedAt := now
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	shiftBreak, err := entities.NewShiftBreak(shift, breaks, startedAt, now, req.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.breakRepo.Create(ctx, shiftBreak); err != nil {
+		return nil, err
+	}
+
+	s.publishBreakEvent(ctx, eventShiftBreakStarted, shiftBreak, map[string]interface{}{
+		"started_at": shiftBreak.StartedAt,
+	})
+
+	s.logger.Info("Shift break started",
+		zap.String("shift_id", shift.ID.String()),
+		zap.String("driver_id", driverID.String()),
+	)
+
+	return shiftBreak, nil
+}
+
+// EndBreak заканчивает идущий перерыв в активной смене водителя
+func (s *shiftService) EndBreak(ctx context.Context, driverID uuid.UUID, req *entities.ShiftBreakEndRequest) (*entities.ShiftBreak, error) {
+	shift, err := s.activeShift(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	breaks, err := s.breakRepo.ListByShift(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift breaks: %w", err)
+	}
+
+	var shiftBreak *entities.ShiftBreak
+	for _, candidate := range breaks {
+		if candidate.IsOpen() {
+			shiftBreak = candidate
+			break
+		}
+	}
+	if shiftBreak == nil {
+		return nil, entities.ErrShiftBreakNotActive
+	}
+
+	now := time.Now()
+	endedAt := now
+	if req.EndedAt != nil {
+		endedAt = *req.EndedAt
+	}
+	if err := shiftBreak.End(endedAt, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.breakRepo.Update(ctx, shiftBreak); err != nil {
+		return nil, err
+	}
+
+	s.publishBreakEvent(ctx, eventShiftBreakEnded, shiftBreak, map[string]interface{}{
+		"started_at":       shiftBreak.StartedAt,
+		"ended_at":         *shiftBreak.EndedAt,
+		"duration_minutes": int64(shiftBreak.Duration(now).Minutes()),
+	})
+
+	return shiftBreak, nil
+}
+
+// activeShift возвращает активную смену водителя или ErrShiftNotActive
+func (s *shiftService) activeShift(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error) {
+	shift, err := s.shiftRepo.GetActiveByDriverID(ctx, driverID)
+	if err != nil {
+		if err == entities.ErrShiftNotFound {
+			return nil, entities.ErrShiftNotActive
+		}
+		return nil, err
+	}
+	return shift, nil
+}
+
+// publishBreakEvent публикует событие перерыва; data дополняет ID смены и перерыва
+func (s *shiftService) publishBreakEvent(ctx context.Context, eventType string, shiftBreak *entities.ShiftBreak, data map[string]interface{}) {
+	data["shift_id"] = shiftBreak.ShiftID.String()
+	data["break_id"] = shiftBreak.ID.String()
+	if err := s.eventBus.PublishDriverEvent(ctx, eventType, shiftBreak.DriverID, data); err != nil {
+		s.logger.Error("Failed to publish shift break event",
+			zap.Error(err),
+			zap.String("event_type", eventType),
+			zap.String("shift_id", shiftBreak.ShiftID.String()),
+		)
+	}
+}
+
+// GetShiftBreaks возвращает перерывы смены водителя и соблюдение требований к отдыху; у
+// активной смены отдых считается на текущий момент. Смена другого водителя не найдена
+func (s *shiftService) GetShiftBreaks(ctx context.Context, driverID, shiftID uuid.UUID) (*entities.ShiftBreaksReport, error) {
+	shift, err := s.shiftRepo.GetByID(ctx, shiftID)
+	if err != nil {
+		return nil, err
+	}
+	if shift.DriverID != driverID {
+		return nil, entities.ErrShiftNotFound
+	}
+
+	breaks, err := s.breakRepo.ListByShift(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shift breaks: %w", err)
+	}
+
+	return &entities.ShiftBreaksReport{
+		ShiftID: shift.ID,
+		Breaks:  breaks,
+		Rest:    entities.EvaluateShiftRest(shift, breaks, s.policy.Rest, time.Now()),
+	}, nil
+}
+
+// GetShiftStats считает статистику по сменам. Время перерывов активных смен считается по
+// их перерывам на текущий момент: в смене оно записывается только при завершении
+func (s *shiftService) GetShiftStats(ctx context.Context, filters *entities.ShiftFilters) (*entities.ShiftStats, error) {
+	all := *filters
+	all.Limit = 0
+	all.Offset = 0
+
+	shifts, err := s.shiftRepo.List(ctx, &all)
+	if err != nil {
+		s.logger.Error("Failed to list shifts for stats", zap.Error(err))
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, shift := range shifts {
+		if !shift.IsActive() {
+			continue
+		}
+		breaks, err := s.breakRepo.ListByShift(ctx, shift.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shift breaks: %w", err)
+		}
+		shift.BreakMinutes = int64(entities.TotalBreakDuration(breaks, now).Minutes())
+	}
+
+	return entities.NewShiftStats(shifts), nil
+}
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestShiftService_EndOverdueShifts(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	shiftRepo := memory.NewShiftRepository()
+	locationRepo := memory.NewLocationRepository()
+	notifier := &recordingNotifier{}
+	events := &recordingEventPublisher{}
+	service := NewShiftService(shiftRepo, memory.NewShiftBreakRepository(), driverRepo, locationRepo, nil, nil, notifier, events,
+		ShiftPolicy{MaxDuration: 16 * time.Hour, MaxGapInterval: 5 * time.Minute}, zap.NewNop())
+
+	// Водители с долгой сменой: свободный, на заказе и с короткой сменой
+	startShift := func(suffix string, status entities.Status, startedAgo time.Duration) *entities.DriverShift {
+		driver := newTestDriver(suffix)
+		driver.ID = uuid.New()
+		driver.Status = status
+		require.NoError(t, driverRepo.Create(ctx, driver))
+
+		shift := entities.NewDriverShift(driver.ID, nil, nil)
+		shift.StartTime = time.Now().Add(-startedAgo)
+		require.NoError(t, shiftRepo.Create(ctx, shift))
+		return shift
+	}
+	overdue := startShift("1", entities.StatusOnShift, 17*time.Hour)
+	onTrip := startShift("2", entities.StatusBusy, 20*time.Hour)
+	recent := startShift("3", entities.StatusOnShift, 2*time.Hour)
+
+	// Точки за смену: до начала смены, и две точки в пути с разницей 0.01° широты (~1.11 км)
+	now := time.Now()
+	for _, location := range []*entities.DriverLocation{
+		entities.NewDriverLocation(overdue.DriverID, 55.70, 37.61, now.Add(-18*time.Hour)),
+		entities.NewDriverLocation(overdue.DriverID, 55.75, 37.61, now.Add(-10*time.Hour)),
+		entities.NewDriverLocation(overdue.DriverID, 55.76, 37.61, now.Add(-10*time.Hour+2*time.Minute)),
+	} {
+		saveLocation(t, locationRepo, location)
+	}
+
+	ended, err := service.EndOverdueShifts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ended)
+	assert.Equal(t, []string{NotificationShiftAutoEnded}, notifier.templates)
+	assert.True(t, events.has(eventShiftEnded))
+	assert.True(t, events.has(eventShiftAutoClosed))
+
+	stored, err := shiftRepo.GetByID(ctx, overdue.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ShiftStatusCompleted, stored.Status)
+	assert.Equal(t, true, stored.Metadata["auto_ended"])
+	assert.Equal(t, true, stored.Metadata["auto_closed"])
+	assert.InDelta(t, 1.11, stored.TotalDistance, 0.01, "distance is computed from points within the shift")
+	require.NotNil(t, stored.EndLatitude)
+	assert.Equal(t, 55.76, *stored.EndLatitude)
+
+	driver, err := driverRepo.GetByID(ctx, overdue.DriverID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.StatusAvailable, driver.Status)
+
+	for _, shift := range []*entities.DriverShift{onTrip, recent} {
+		stored, err := shiftRepo.GetByID(ctx, shift.ID)
+		require.NoError(t, err)
+		assert.True(t, stored.IsActive())
+	}
+}
+
+func TestShiftService_EndShiftComputesLocationStats(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	shiftRepo := memory.NewShiftRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewShiftService(shiftRepo, memory.NewShiftBreakRepository(), driverRepo, locationRepo, nil, nil, nil, &recordingEventPublisher{},
+		ShiftPolicy{MaxGapInterval: 15 * time.Minute}, zap.NewNop())
+
+	startShift := func(suffix string, startedAgo time.Duration) *entities.DriverShift {
+		driver := newTestDriver(suffix)
+		driver.ID = uuid.New()
+		driver.Status = entities.StatusOnShift
+		require.NoError(t, driverRepo.Create(ctx, driver))
+
+		shift := entities.NewDriverShift(driver.ID, nil, nil)
+		shift.StartTime = time.Now().Add(-startedAgo)
+		shift.AddTrip(5, 700)
+		require.NoError(t, shiftRepo.Create(ctx, shift))
+		return shift
+	}
+	tracked := startShift("401", 2*time.Hour)
+	untracked := startShift("402", time.Hour)
+
+	// 0.01° широты (~1.11 км) за 2 минуты, затем 10 минут стоянки; скорость не передается
+	now := time.Now()
+	for _, location := range []*entities.DriverLocation{
+		entities.NewDriverLocation(tracked.DriverID, 55.70, 37.61, now.Add(-3*time.Hour)),
+		entities.NewDriverLocation(tracked.DriverID, 55.75, 37.61, now.Add(-time.Hour)),
+		entities.NewDriverLocation(tracked.DriverID, 55.76, 37.61, now.Add(-time.Hour+2*time.Minute)),
+		entities.NewDriverLocation(tracked.DriverID, 55.76, 37.61, now.Add(-time.Hour+12*time.Minute)),
+	} {
+		saveLocation(t, locationRepo, location)
+	}
+
+	shift, err := service.EndShift(ctx, tracked.DriverID, &entities.ShiftEndRequest{})
+	require.NoError(t, err)
+	assert.InDelta(t, 1.11, shift.TotalDistance, 0.01, "GPS distance replaces trip distance")
+	require.NotNil(t, shift.IdleMinutes)
+	assert.Equal(t, int64(10), *shift.IdleMinutes)
+	require.NotNil(t, shift.AverageSpeed)
+	assert.InDelta(t, 1.11/(12.0/60), *shift.AverageSpeed, 0.1)
+
+	stored, err := shiftRepo.GetByID(ctx, tracked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, shift.TotalDistance, stored.TotalDistance)
+	assert.Equal(t, int64(10), *stored.IdleMinutes)
+
+	// Без трека остается пробег поездок
+	shift, err = service.EndShift(ctx, untracked.DriverID, &entities.ShiftEndRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, 5.0, shift.TotalDistance)
+	assert.Nil(t, shift.AverageSpeed)
+	assert.Nil(t, shift.IdleMinutes)
+}
+
+// failingTxManager выполняет операции и сообщает об ошибке фиксации транзакции
+type failingTxManager struct {
+	calls int
+}
+
+func (m *failingTxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	if err := fn(ctx); err != nil {
+		return err
+	}
+	return errors.New("commit failed")
+}
+
+func TestShiftService_StartShiftInTransaction(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	txManager := &failingTxManager{}
+	events := &recordingEventPublisher{}
+	service := NewShiftService(memory.NewShiftRepository(), memory.NewShiftBreakRepository(), driverRepo, memory.NewLocationRepository(), txManager, nil, nil,
+		events, ShiftPolicy{}, zap.NewNop())
+
+	driver := newTestDriver("411")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusAvailable
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	// Смена не считается начатой, если транзакция не зафиксирована
+	_, err := service.StartShift(ctx, driver.ID, &entities.ShiftStartRequest{})
+	assert.Error(t, err)
+	assert.Equal(t, 1, txManager.calls)
+	assert.False(t, events.has(eventShiftStarted))
+}
+
+func TestShiftService_Breaks(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	shiftRepo := memory.NewShiftRepository()
+	events := &recordingEventPublisher{}
+	service := NewShiftService(shiftRepo, memory.NewShiftBreakRepository(), driverRepo, memory.NewLocationRepository(), nil, nil, nil,
+		events, ShiftPolicy{Rest: entities.ShiftRestPolicy{WorkLimit: 4 * time.Hour, MinBreak: 15 * time.Minute}}, zap.NewNop())
+
+	driver := newTestDriver("521")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusOnShift
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	// Перерыв вне смены не начинается
+	_, err := service.StartBreak(ctx, driver.ID, &entities.ShiftBreakStartRequest{})
+	assert.Equal(t, entities.ErrShiftNotActive, err)
+
+	shift := entities.NewDriverShift(driver.ID, nil, nil)
+	shift.StartTime = time.Now().Add(-6 * time.Hour)
+	require.NoError(t, shiftRepo.Create(ctx, shift))
+
+	_, err = service.EndBreak(ctx, driver.ID, &entities.ShiftBreakEndRequest{})
+	assert.Equal(t, entities.ErrShiftBreakNotActive, err)
+
+	// Перерыв после 3 часов работы на 30 минут
+	startedAt := shift.StartTime.Add(3 * time.Hour)
+	endedAt := startedAt.Add(30 * time.Minute)
+	_, err = service.StartBreak(ctx, driver.ID, &entities.ShiftBreakStartRequest{StartedAt: &startedAt})
+	require.NoError(t, err)
+	assert.True(t, events.has(eventShiftBreakStarted))
+
+	_, err = service.StartBreak(ctx, driver.ID, &entities.ShiftBreakStartRequest{})
+	assert.Equal(t, entities.ErrShiftBreakActive, err)
+
+	shiftBreak, err := service.EndBreak(ctx, driver.ID, &entities.ShiftBreakEndRequest{EndedAt: &endedAt})
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, shiftBreak.Duration(time.Now()))
+	assert.True(t, events.has(eventShiftBreakEnded))
+
+	// Перерыв не может пересекаться с закончившимся
+	overlapping := startedAt.Add(10 * time.Minute)
+	_, err = service.StartBreak(ctx, driver.ID, &entities.ShiftBreakStartRequest{StartedAt: &overlapping})
+	assert.Equal(t, entities.ErrShiftBreakOverlap, err)
+
+	// Идущий перерыв закрывается вместе со сменой
+	lastStartedAt := time.Now().Add(-10 * time.Minute)
+	_, err = service.StartBreak(ctx, driver.ID, &entities.ShiftBreakStartRequest{StartedAt: &lastStartedAt})
+	require.NoError(t, err)
+
+	stats, err := service.GetShiftStats(ctx, &entities.ShiftFilters{DriverID: &driver.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.ActiveShifts)
+	assert.InDelta(t, 40.0/60, stats.TotalBreakHours, 0.02, "break time of an active shift includes the open break")
+
+	ended, err := service.EndShift(ctx, driver.ID, &entities.ShiftEndRequest{})
+	require.NoError(t, err)
+	assert.InDelta(t, 40, ended.BreakMinutes, 1)
+	require.NotNil(t, ended.RestCompliant)
+	// После перерыва почти 2.5 часа работы, затем 10 минут перерыва — не отдых
+	assert.True(t, *ended.RestCompliant)
+
+	report, err := service.GetShiftBreaks(ctx, driver.ID, shift.ID)
+	require.NoError(t, err)
+	require.Len(t, report.Breaks, 2)
+	for _, shiftBreak := range report.Breaks {
+		assert.False(t, shiftBreak.IsOpen())
+	}
+	assert.True(t, report.Rest.Compliant)
+	assert.Equal(t, int64(180), report.Rest.LongestWorkMinutes)
+
+	_, err = service.GetShiftBreaks(ctx, uuid.New(), shift.ID)
+	assert.Equal(t, entities.ErrShiftNotFound, err, "shift of another driver")
+
+	stats, err = service.GetShiftStats(ctx, &entities.ShiftFilters{DriverID: &driver.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.CompletedShifts)
+	assert.Equal(t, 0, stats.RestViolations)
+}
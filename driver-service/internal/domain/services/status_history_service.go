@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// StatusHistoryService интерфейс для просмотра истории статусов водителей
+type StatusHistoryService interface {
+	// ListTransitions возвращает смены статуса водителя за период [From, To) в порядке времени
+	ListTransitions(ctx context.Context, filters *entities.StatusHistoryFilters) ([]*entities.StatusTransition, error)
+}
+
+// statusHistoryService реализация StatusHistoryService
+type statusHistoryService struct {
+	historyRepo repositories.StatusHistoryRepository
+	driverRepo  repositories.DriverRepository
+	logger      *zap.Logger
+}
+
+// NewStatusHistoryService создает новый StatusHistoryService
+func NewStatusHistoryService(
+	historyRepo repositories.StatusHistoryRepository,
+	driverRepo repositories.DriverRepository,
+	logger *zap.Logger,
+) StatusHistoryService {
+	return &statusHistoryService{
+		historyRepo: historyRepo,
+		driverRepo:  driverRepo,
+		logger:      logger,
+	}
+}
+
+// ListTransitions получает смены статуса водителя за период
+func (s *statusHistoryService) ListTransitions(ctx context.Context, filters *entities.StatusHistoryFilters) ([]*entities.StatusTransition, error) {
+	if filters.From != nil && filters.To != nil && !filters.From.Before(*filters.To) {
+		return nil, entities.ErrInvalidStatusHistoryRange
+	}
+	if _, err := s.driverRepo.GetByID(ctx, filters.DriverID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.historyRepo.List(ctx, filters)
+	if err != nil {
+		s.logger.Error("Failed to list driver status history",
+			zap.Error(err),
+			zap.String("driver_id", filters.DriverID.String()),
+		)
+		return nil, err
+	}
+
+	return history, nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStatusHistoryService_ListTransitions(t *testing.T) {
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: "dispatcher-1", RequestID: "req-1"})
+	driverRepo := memory.NewDriverRepository()
+	service := NewStatusHistoryService(memory.NewStatusHistoryRepository(driverRepo), driverRepo, zap.NewNop())
+
+	driver := newTestDriver("701")
+	driver.ID = uuid.New()
+	driver.Status = entities.StatusRegistered
+	driver.CreatedAt = time.Now()
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusAvailable))
+	// Повторная установка того же статуса не является сменой
+	require.NoError(t, driverRepo.UpdateStatus(ctx, driver.ID, entities.StatusAvailable))
+	_, err := driverRepo.UpdateStatuses(ctx, []entities.StatusUpdate{
+		{DriverID: driver.ID, From: entities.StatusAvailable, To: entities.StatusBlocked},
+	}, true)
+	require.NoError(t, err)
+
+	history, err := service.ListTransitions(ctx, &entities.StatusHistoryFilters{DriverID: driver.ID})
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	// Регистрация без автора: контекст без токена
+	assert.Nil(t, history[0].FromStatus)
+	assert.Equal(t, entities.StatusRegistered, history[0].ToStatus)
+	assert.Nil(t, history[0].Actor)
+
+	require.NotNil(t, history[1].FromStatus)
+	assert.Equal(t, entities.StatusRegistered, *history[1].FromStatus)
+	assert.Equal(t, entities.StatusAvailable, history[1].ToStatus)
+	require.NotNil(t, history[1].Actor)
+	assert.Equal(t, "dispatcher-1", *history[1].Actor)
+	require.NotNil(t, history[1].RequestID)
+	assert.Equal(t, "req-1", *history[1].RequestID)
+
+	assert.Equal(t, entities.StatusAvailable, *history[2].FromStatus)
+	assert.Equal(t, entities.StatusBlocked, history[2].ToStatus)
+
+	// Период [from, to) отсекает регистрацию
+	from := history[1].ChangedAt
+	page, err := service.ListTransitions(ctx, &entities.StatusHistoryFilters{DriverID: driver.ID, From: &from, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, entities.StatusAvailable, page[0].ToStatus)
+
+	to := from
+	_, err = service.ListTransitions(ctx, &entities.StatusHistoryFilters{DriverID: driver.ID, From: &from, To: &to})
+	assert.ErrorIs(t, err, entities.ErrInvalidStatusHistoryRange)
+
+	_, err = service.ListTransitions(ctx, &entities.StatusHistoryFilters{DriverID: uuid.New()})
+	assert.ErrorIs(t, err, entities.ErrDriverNotFound)
+}
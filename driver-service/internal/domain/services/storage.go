@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// FileStorage хранилище загружаемых файлов (чеки, фотографии автомобилей)
+type FileStorage interface {
+	// Save сохраняет файл под ключом key и возвращает URL для доступа к нему
+	Save(ctx context.Context, key string, contentType string, body io.Reader) (string, error)
+	// Delete удаляет файл по URL, который вернул Save; отсутствующий файл не считается ошибкой
+	Delete(ctx context.Context, url string) error
+}
+
+// SignedFileStorage хранилище, которое выдает временные ссылки на закрытые файлы (выгрузки)
+type SignedFileStorage interface {
+	FileStorage
+	// SignURL возвращает ссылку на файл по URL, который вернул Save, действующую до expiresAt
+	SignURL(url string, expiresAt time.Time) (string, error)
+}
+
+// FileUpload загружаемый файл
+type FileUpload struct {
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
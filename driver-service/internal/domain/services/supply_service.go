@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"go.uber.org/zap"
+)
+
+// SupplyPolicy параметры карты предложения водителей
+type SupplyPolicy struct {
+	// DefaultPrecision длина geohash ячейки, если запрос ее не задал
+	DefaultPrecision int
+	// MaxPrecision наибольшая допустимая длина geohash ячейки
+	MaxPrecision int
+	// LocationMaxAge водитель, последняя точка которого старше, на карту не попадает
+	LocationMaxAge time.Duration
+}
+
+// SupplyService интерфейс для карты предложения водителей
+type SupplyService interface {
+	// GetHeatmap считает водителей по ячейкам geohash длиной precision внутри bounds;
+	// precision 0 — SupplyPolicy.DefaultPrecision
+	GetHeatmap(ctx context.Context, bounds *entities.GeoBounds, precision int) (*entities.SupplyHeatmap, error)
+}
+
+// supplyService реализация SupplyService
+type supplyService struct {
+	supplyRepo repositories.SupplyRepository
+	policy     SupplyPolicy
+	logger     *zap.Logger
+}
+
+// NewSupplyService создает новый SupplyService
+func NewSupplyService(supplyRepo repositories.SupplyRepository, policy SupplyPolicy, logger *zap.Logger) SupplyService {
+	return &supplyService{
+		supplyRepo: supplyRepo,
+		policy:     policy,
+		logger:     logger,
+	}
+}
+
+// GetHeatmap строит карту по текущим местоположениям водителей
+func (s *supplyService) GetHeatmap(ctx context.Context, bounds *entities.GeoBounds, precision int) (*entities.SupplyHeatmap, error) {
+	if bounds == nil {
+		return nil, entities.ErrInvalidBoundingBox
+	}
+	if err := bounds.Validate(); err != nil {
+		return nil, err
+	}
+
+	if precision == 0 {
+		precision = s.policy.DefaultPrecision
+	}
+	if precision < 1 || precision > s.policy.MaxPrecision {
+		return nil, entities.ErrInvalidHeatmapCellSize
+	}
+
+	now := time.Now()
+	query := &entities.SupplyHeatmapQuery{
+		Bounds:    *bounds,
+		Precision: precision,
+		Since:     now.Add(-s.policy.LocationMaxAge),
+	}
+
+	counts, err := s.supplyRepo.CountByCell(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	heatmap := entities.NewSupplyHeatmap(query, counts, now)
+	s.logger.Debug("Supply heatmap built",
+		zap.Int("precision", precision),
+		zap.Int("cells", len(heatmap.Cells)),
+		zap.Int("drivers", heatmap.Total),
+	)
+	return heatmap, nil
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSupplyService_GetHeatmap(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewSupplyService(
+		memory.NewSupplyRepository(driverRepo, locationRepo),
+		SupplyPolicy{DefaultPrecision: 5, MaxPrecision: 8, LocationMaxAge: 5 * time.Minute},
+		zap.NewNop(),
+	)
+
+	now := time.Now()
+	// point точка без водителя; водитель задается в addDriver
+	point := func(lat, lon float64, age time.Duration) *entities.DriverLocation {
+		return entities.NewDriverLocation(uuid.Nil, lat, lon, now.Add(-age))
+	}
+	addDriver := func(suffix string, status entities.Status, points ...*entities.DriverLocation) {
+		driver := entities.NewDriver("+7900000060"+suffix, "", "Карта", "Предложения", "")
+		driver.Status = status
+		require.NoError(t, driverRepo.Create(ctx, driver))
+		for _, point := range points {
+			point.DriverID = driver.ID
+			saveLocation(t, locationRepo, point)
+		}
+	}
+
+	addDriver("1", entities.StatusAvailable, point(55.751, 37.618, time.Minute))
+	addDriver("2", entities.StatusBusy, point(55.752, 37.619, time.Minute))
+	// Последняя точка вне области: водитель не учитывается по старой точке
+	addDriver("3", entities.StatusAvailable, point(55.751, 37.618, 3*time.Minute), point(59.93, 30.33, time.Minute))
+	// Точка старше LocationMaxAge
+	addDriver("4", entities.StatusAvailable, point(55.751, 37.618, time.Hour))
+
+	bounds, err := entities.ParseGeoBounds("37.3,55.5,37.9,56.0")
+	require.NoError(t, err)
+
+	heatmap, err := service.GetHeatmap(ctx, bounds, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 5, heatmap.Precision)
+	assert.Equal(t, 2, heatmap.Total)
+	require.Len(t, heatmap.Cells, 1)
+	assert.Equal(t, entities.EncodeGeohash(55.751, 37.618, 5), heatmap.Cells[0].Geohash)
+	assert.Equal(t, map[entities.Status]int{entities.StatusAvailable: 1, entities.StatusBusy: 1}, heatmap.Cells[0].ByStatus)
+
+	_, err = service.GetHeatmap(ctx, bounds, 9)
+	assert.ErrorIs(t, err, entities.ErrInvalidHeatmapCellSize)
+	_, err = service.GetHeatmap(ctx, nil, 0)
+	assert.ErrorIs(t, err, entities.ErrInvalidBoundingBox)
+}
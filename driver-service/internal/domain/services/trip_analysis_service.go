@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TripAnalysisService интерфейс для восстановления поездок по истории местоположений
+type TripAnalysisService interface {
+	GetTrips(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.TripSummary, error)
+}
+
+// TripPolicy параметры восстановления поездок
+type TripPolicy struct {
+	// Segmentation пороги разбиения трека на поездки
+	Segmentation entities.TripSegmentation
+	// MaxRange максимальная длина запрашиваемого периода
+	MaxRange time.Duration
+}
+
+// tripAnalysisService реализация TripAnalysisService
+type tripAnalysisService struct {
+	locationRepo repositories.LocationRepository
+	driverRepo   repositories.DriverRepository
+	policy       TripPolicy
+	logger       *zap.Logger
+}
+
+// NewTripAnalysisService создает новый TripAnalysisService
+func NewTripAnalysisService(
+	locationRepo repositories.LocationRepository,
+	driverRepo repositories.DriverRepository,
+	policy TripPolicy,
+	logger *zap.Logger,
+) TripAnalysisService {
+	return &tripAnalysisService{
+		locationRepo: locationRepo,
+		driverRepo:   driverRepo,
+		policy:       policy,
+		logger:       logger,
+	}
+}
+
+// GetTrips разбивает историю местоположений водителя за период на поездки
+func (s *tripAnalysisService) GetTrips(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.TripSummary, error) {
+	if !to.After(from) || (s.policy.MaxRange > 0 && to.Sub(from) > s.policy.MaxRange) {
+		return nil, entities.ErrInvalidTripRange
+	}
+
+	if _, err := s.driverRepo.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	locations, err := s.locationRepo.GetByDriverIDInTimeRange(ctx, driverID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location history: %w", err)
+	}
+
+	summary := &entities.TripSummary{
+		DriverID: driverID,
+		From:     from,
+		To:       to,
+		Trips:    entities.SegmentTrips(locations, s.policy.Segmentation),
+	}
+	if summary.Trips == nil {
+		summary.Trips = []*entities.Trip{}
+	}
+	summary.Count = len(summary.Trips)
+	for _, trip := range summary.Trips {
+		summary.TotalDistanceKm += trip.DistanceKm
+		summary.TotalDurationSeconds += trip.DurationSeconds
+	}
+
+	s.logger.Debug("Driver trips reconstructed",
+		zap.String("driver_id", driverID.String()),
+		zap.Int("locations", len(locations)),
+		zap.Int("trips", summary.Count),
+	)
+
+	return summary, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTripAnalysisService_GetTrips(t *testing.T) {
+	ctx := context.Background()
+	driverRepo := memory.NewDriverRepository()
+	locationRepo := memory.NewLocationRepository()
+	service := NewTripAnalysisService(locationRepo, driverRepo, TripPolicy{
+		Segmentation: entities.TripSegmentation{StopSpeed: 5, MinStopDuration: 3 * time.Minute, SimplifyTolerance: 10},
+		MaxRange:     24 * time.Hour,
+	}, zap.NewNop())
+
+	driver := entities.NewDriver("+79000000104", "trips@example.com", "Иван", "Поездки", "LICT")
+	require.NoError(t, driverRepo.Create(ctx, driver))
+
+	now := time.Now().Truncate(time.Second)
+	var track []*entities.DriverLocation
+	for i, lat := range []float64{55.70, 55.71, 55.72, 55.72, 55.72, 55.72, 55.73, 55.74} {
+		location := entities.NewDriverLocation(driver.ID, lat, 37.60, now.Add(time.Duration(i-10)*2*time.Minute))
+		speed := 40.0
+		if i == 0 || (i >= 3 && i <= 5) {
+			speed = 0
+		}
+		location.Speed = &speed
+		track = append(track, location)
+	}
+	_, err := locationRepo.CreateBatch(ctx, track)
+	require.NoError(t, err)
+
+	summary, err := service.GetTrips(ctx, driver.ID, now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Equal(t, 2, summary.Count)
+	assert.Equal(t, entities.TripEndStop, summary.Trips[0].EndReason)
+	assert.Equal(t, entities.TripEndRangeEnd, summary.Trips[1].EndReason)
+	// Прямой участок упрощается до начала и конца
+	assert.Len(t, summary.Trips[0].Polyline, 2)
+	assert.InDelta(t, 4.45, summary.TotalDistanceKm, 0.01)
+	assert.Equal(t, int64(10*60), summary.TotalDurationSeconds)
+
+	_, err = service.GetTrips(ctx, driver.ID, now, now.Add(-time.Hour))
+	assert.Equal(t, entities.ErrInvalidTripRange, err)
+	_, err = service.GetTrips(ctx, driver.ID, now.Add(-48*time.Hour), now)
+	assert.Equal(t, entities.ErrInvalidTripRange, err)
+	_, err = service.GetTrips(ctx, uuid.New(), now.Add(-time.Hour), now)
+	assert.Equal(t, entities.ErrDriverNotFound, err)
+}
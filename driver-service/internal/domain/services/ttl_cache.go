@@ -0,0 +1,83 @@
+package services
+
+import (
+	"sync"
+	"time"
+)
+
+// ttlEntry значение кэша, действительное до expiresAt
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTLCache кэш значений по ключу, действительных ttl с момента записи. ttl <= 0 отключает кэш:
+// запись ничего не сохраняет. Безопасен для одновременного использования
+type TTLCache[K comparable, V any] struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[K]ttlEntry[V]
+	// pruned время последнего удаления просроченных записей
+	pruned time.Time
+}
+
+// NewTTLCache создает кэш со временем жизни записей ttl
+func NewTTLCache[K comparable, V any](ttl time.Duration) *TTLCache[K, V] {
+	return &TTLCache[K, V]{ttl: ttl, entries: make(map[K]ttlEntry[V])}
+}
+
+// Get возвращает значение ключа, если запись не устарела к моменту at
+func (c *TTLCache[K, V]) Get(key K, at time.Time) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !at.Before(entry.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set запоминает значение ключа на ttl с момента at
+func (c *TTLCache[K, V]) Set(key K, value V, at time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Просроченные записи удаляются не чаще раза в ttl: кэш не растет больше записей за два ttl,
+	// а запись не перебирает весь кэш
+	if at.Sub(c.pruned) >= c.ttl {
+		for k, entry := range c.entries {
+			if !at.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.pruned = at
+	}
+	c.entries[key] = ttlEntry[V]{value: value, expiresAt: at.Add(c.ttl)}
+}
+
+// Delete удаляет запись ключа
+func (c *TTLCache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// DeleteFunc удаляет записи, для которых match возвращает true
+func (c *TTLCache[K, V]) DeleteFunc(match func(key K, value V) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, entry := range c.entries {
+		if match(k, entry.value) {
+			delete(c.entries, k)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLCache(t *testing.T) {
+	cache := NewTTLCache[string, int](time.Minute)
+	start := time.Now()
+
+	cache.Set("a", 1, start)
+	value, ok := cache.Get("a", start.Add(59*time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	_, ok = cache.Get("a", start.Add(time.Minute))
+	assert.False(t, ok, "the entry expires after ttl")
+
+	// Просроченные записи удаляются не при каждой записи, а раз в ttl
+	cache.Set("b", 2, start.Add(30*time.Second))
+	cache.Set("c", 3, start.Add(50*time.Second))
+	cache.Set("d", 4, start.Add(65*time.Second))
+	assert.Len(t, cache.entries, 3, "a is pruned")
+	cache.Set("e", 5, start.Add(100*time.Second))
+	assert.Len(t, cache.entries, 4, "expired b is kept until the next prune")
+	_, ok = cache.Get("b", start.Add(100*time.Second))
+	assert.False(t, ok)
+	cache.Set("f", 6, start.Add(130*time.Second))
+	assert.Len(t, cache.entries, 2)
+
+	cache.Delete("e")
+	cache.DeleteFunc(func(key string, value int) bool { return value == 6 })
+	assert.Empty(t, cache.entries)
+
+	disabled := NewTTLCache[string, int](0)
+	disabled.Set("a", 1, start)
+	_, ok = disabled.Get("a", start)
+	assert.False(t, ok)
+}
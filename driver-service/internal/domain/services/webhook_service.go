@@ -0,0 +1,435 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// WebhookPolicy параметры доставки вебхуков
+type WebhookPolicy struct {
+	// Workers число параллельных отправок
+	Workers int
+	// QueueSize сколько доставок может ждать немедленной отправки; остальные отправит
+	// задача повторов
+	QueueSize int
+	// Timeout ограничение времени одного запроса к получателю
+	Timeout time.Duration
+	// MaxAttempts после стольких неудачных попыток доставка считается неудачной
+	MaxAttempts int
+	// InitialBackoff пауза перед второй попыткой; каждая следующая пауза вдвое больше
+	InitialBackoff time.Duration
+	// MaxBackoff наибольшая пауза между попытками
+	MaxBackoff time.Duration
+	// Lease на сколько откладывается следующая попытка доставки, взятой в отправку, чтобы ее
+	// не подхватили задача повторов или другие экземпляры
+	Lease time.Duration
+	// RetryBatch сколько доставок берет задача повторов за один запуск
+	RetryBatch int
+	// CacheTTL как долго используется список подписок без обращения к репозиторию;
+	// изменения подписок на других экземплярах видны через это время
+	CacheTTL time.Duration
+}
+
+// WebhookSender отправляет доставку получателю
+type WebhookSender interface {
+	// Send отправляет тело доставки на url, подписывая его ключом secret. Возвращает код
+	// ответа получателя; 0 — ответа нет
+	Send(ctx context.Context, url, secret string, delivery *entities.WebhookDelivery) (int, error)
+}
+
+// WebhookService подписки внешних получателей на события водителей и доставка событий.
+// Доставка выполняется хотя бы один раз: получатель отбрасывает повторы по ID события
+type WebhookService interface {
+	// CreateSubscription регистрирует подписку; ключ подписи возвращается только здесь
+	CreateSubscription(ctx context.Context, req *entities.WebhookSubscriptionRequest) (*entities.CreatedWebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	// DeleteSubscription удаляет подписку и журнал ее доставок
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	// ListDeliveries возвращает журнал доставок подписки
+	ListDeliveries(ctx context.Context, filters *entities.WebhookDeliveryFilters) ([]*entities.WebhookDelivery, error)
+	// Redeliver отправляет событие доставки повторно новой доставкой
+	Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entities.WebhookDelivery, error)
+	// Enqueue создает доставки события всем подписанным на него получателям и ставит их
+	// в очередь отправки
+	Enqueue(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error
+	// RetryDue ставит в очередь доставки, время повторной попытки которых наступило.
+	// Возвращает число поставленных доставок
+	RetryDue(ctx context.Context) (int, error)
+	// Drain перестает принимать доставки и дожидается отправки очереди. Если ctx истек
+	// раньше, возвращает число доставок, оставшихся в очереди; их отправит задача повторов
+	Drain(ctx context.Context) (int, error)
+}
+
+// webhookService реализация WebhookService
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+	sender      WebhookSender
+	policy      WebhookPolicy
+	logger      *zap.Logger
+
+	// cacheMu защищает кэш подписок
+	cacheMu       sync.RWMutex
+	subscriptions []*entities.WebhookSubscription
+	loadedAt      time.Time
+
+	// mu защищает stopped: после остановки доставки в очередь не принимаются
+	mu      sync.RWMutex
+	stopped bool
+	jobs    chan *entities.WebhookDelivery
+	wg      sync.WaitGroup
+}
+
+// NewWebhookService создает WebhookService и запускает обработчики очереди
+func NewWebhookService(
+	webhookRepo repositories.WebhookRepository,
+	sender WebhookSender,
+	policy WebhookPolicy,
+	logger *zap.Logger,
+) WebhookService {
+	if policy.Workers <= 0 {
+		policy.Workers = 1
+	}
+	if policy.QueueSize < 0 {
+		policy.QueueSize = 0
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.Lease <= 0 {
+		policy.Lease = 2 * time.Minute
+	}
+	if policy.RetryBatch <= 0 {
+		policy.RetryBatch = 100
+	}
+
+	s := &webhookService{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		policy:      policy,
+		logger:      logger,
+		jobs:        make(chan *entities.WebhookDelivery, policy.QueueSize),
+	}
+	for i := 0; i < policy.Workers; i++ {
+		s.wg.Add(1)
+		go s.run()
+	}
+	return s
+}
+
+// CreateSubscription проверяет запрос и сохраняет подписку
+func (s *webhookService) CreateSubscription(ctx context.Context, req *entities.WebhookSubscriptionRequest) (*entities.CreatedWebhookSubscription, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	for _, eventType := range req.EventTypes {
+		if !IsCataloguedEvent(eventType) {
+			return nil, entities.ErrUnknownWebhookEvent
+		}
+	}
+
+	if actor, ok := entities.AuditActorFromContext(ctx); ok {
+		req.CreatedBy = actor.Subject
+	}
+
+	created, err := entities.NewWebhookSubscription(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.CreateSubscription(ctx, created.WebhookSubscription); err != nil {
+		return nil, err
+	}
+	s.invalidateCache()
+
+	s.logger.Info("Webhook subscription created",
+		zap.String("subscription_id", created.ID.String()),
+		zap.String("name", created.Name),
+		zap.Strings("event_types", created.EventTypes),
+	)
+	return created, nil
+}
+
+// ListSubscriptions получает все подписки
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	return s.webhookRepo.ListSubscriptions(ctx)
+}
+
+// DeleteSubscription удаляет подписку
+func (s *webhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	if err := s.webhookRepo.DeleteSubscription(ctx, id); err != nil {
+		return err
+	}
+	s.invalidateCache()
+
+	s.logger.Info("Webhook subscription deleted", zap.String("subscription_id", id.String()))
+	return nil
+}
+
+// ListDeliveries получает журнал доставок существующей подписки
+func (s *webhookService) ListDeliveries(ctx context.Context, filters *entities.WebhookDeliveryFilters) ([]*entities.WebhookDelivery, error) {
+	if _, err := s.webhookRepo.GetSubscription(ctx, filters.SubscriptionID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.ListDeliveries(ctx, filters)
+}
+
+// Redeliver создает новую доставку с телом исходной и ставит ее в очередь
+func (s *webhookService) Redeliver(ctx context.Context, deliveryID uuid.UUID) (*entities.WebhookDelivery, error) {
+	original, err := s.webhookRepo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	redelivery := original.Redeliver(now, now.Add(s.policy.Lease))
+	if err := s.webhookRepo.CreateDeliveries(ctx, []*entities.WebhookDelivery{redelivery}); err != nil {
+		return nil, err
+	}
+
+	// Обработчик меняет свою копию, возвращаемая доставка остается в состоянии pending
+	job := *redelivery
+	s.submit(&job)
+
+	s.logger.Info("Webhook delivery redelivered",
+		zap.String("delivery_id", redelivery.ID.String()),
+		zap.String("redelivery_of", deliveryID.String()),
+	)
+	return redelivery, nil
+}
+
+// Enqueue создает доставки события подписчикам
+func (s *webhookService) Enqueue(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	subscriptions, err := s.subscribers(ctx, eventType)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	now := time.Now()
+	event := &entities.WebhookEvent{
+		ID:         uuid.New(),
+		Type:       eventType,
+		DriverID:   driverID,
+		OccurredAt: now.UTC(),
+		Data:       data,
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	// Следующая попытка откладывается на Lease: доставку сразу отправляет этот экземпляр
+	deliveries := make([]*entities.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, entities.NewWebhookDelivery(
+			subscription.ID, event.ID, eventType, driverID, payload, now, now.Add(s.policy.Lease),
+		))
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		s.submit(delivery)
+	}
+	return nil
+}
+
+// RetryDue захватывает доставки с наступившим временем попытки и ставит их в очередь
+func (s *webhookService) RetryDue(ctx context.Context) (int, error) {
+	deliveries, err := s.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), s.policy.Lease, s.policy.RetryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	// Доставки, не вошедшие в очередь, снова станут доступны по истечении Lease
+	submitted := 0
+	for _, delivery := range deliveries {
+		if s.submit(delivery) {
+			submitted++
+		}
+	}
+
+	if submitted > 0 {
+		s.logger.Info("Webhook deliveries retried", zap.Int("count", submitted))
+	}
+	return submitted, nil
+}
+
+// Drain дожидается отправки доставок из очереди
+func (s *webhookService) Drain(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	if !s.stopped {
+		s.stopped = true
+		close(s.jobs)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return 0, nil
+	case <-ctx.Done():
+		return len(s.jobs), ctx.Err()
+	}
+}
+
+// subscribers возвращает подписки на событие из кэша, обновляя его по истечении CacheTTL
+func (s *webhookService) subscribers(ctx context.Context, eventType string) ([]*entities.WebhookSubscription, error) {
+	s.cacheMu.RLock()
+	subscriptions, loadedAt := s.subscriptions, s.loadedAt
+	s.cacheMu.RUnlock()
+
+	if loadedAt.IsZero() || time.Since(loadedAt) >= s.policy.CacheTTL {
+		loaded, err := s.webhookRepo.ListSubscriptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		s.cacheMu.Lock()
+		s.subscriptions, s.loadedAt = loaded, time.Now()
+		s.cacheMu.Unlock()
+		subscriptions = loaded
+	}
+
+	var result []*entities.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.EventTypes.Contains(eventType) {
+			result = append(result, subscription)
+		}
+	}
+	return result, nil
+}
+
+// invalidateCache сбрасывает кэш подписок после их изменения
+func (s *webhookService) invalidateCache() {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+
+	s.subscriptions, s.loadedAt = nil, time.Time{}
+}
+
+// submit ставит доставку в очередь; false, если очередь заполнена или остановлена.
+// Доставка вне очереди остается ожидающей и будет отправлена задачей повторов
+func (s *webhookService) submit(delivery *entities.WebhookDelivery) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.stopped {
+		return false
+	}
+
+	select {
+	case s.jobs <- delivery:
+		return true
+	default:
+		s.logger.Warn("Webhook delivery queue is full",
+			zap.String("delivery_id", delivery.ID.String()),
+		)
+		return false
+	}
+}
+
+// run отправляет доставки из очереди до ее закрытия
+func (s *webhookService) run() {
+	defer s.wg.Done()
+
+	for delivery := range s.jobs {
+		s.deliver(delivery)
+	}
+}
+
+// deliver выполняет одну попытку доставки и сохраняет ее итог
+func (s *webhookService) deliver(delivery *entities.WebhookDelivery) {
+	ctx := context.Background()
+	subscription, err := s.webhookRepo.GetSubscription(ctx, delivery.SubscriptionID)
+	if err != nil {
+		// Удаленная подписка удаляет и журнал доставок
+		if err != entities.ErrWebhookNotFound {
+			s.logger.Error("Failed to get webhook subscription",
+				zap.Error(err),
+				zap.String("delivery_id", delivery.ID.String()),
+			)
+		}
+		return
+	}
+
+	sendCtx := ctx
+	if s.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		sendCtx, cancel = context.WithTimeout(ctx, s.policy.Timeout)
+		defer cancel()
+	}
+
+	statusCode, sendErr := s.sender.Send(sendCtx, subscription.URL, subscription.Secret, delivery)
+	backoff := entities.WebhookBackoff(delivery.Attempts+1, s.policy.InitialBackoff, s.policy.MaxBackoff)
+	delivery.RecordAttempt(time.Now(), statusCode, sendErr, s.policy.MaxAttempts, backoff)
+
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		if err != entities.ErrWebhookDeliveryNotFound {
+			s.logger.Error("Failed to save webhook delivery result",
+				zap.Error(err),
+				zap.String("delivery_id", delivery.ID.String()),
+			)
+		}
+		return
+	}
+
+	switch delivery.Status {
+	case entities.WebhookDeliveryDelivered:
+		s.logger.Debug("Webhook delivered",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.String("event_type", delivery.EventType),
+		)
+	case entities.WebhookDeliveryFailed:
+		s.logger.Warn("Webhook delivery failed",
+			zap.String("delivery_id", delivery.ID.String()),
+			zap.String("subscription_id", delivery.SubscriptionID.String()),
+			zap.Int("attempts", delivery.Attempts),
+		)
+	}
+}
+
+// webhookEventPublisher публикует событие и передает его подписчикам вебхуков
+type webhookEventPublisher struct {
+	next     EventPublisher
+	webhooks WebhookService
+	logger   *zap.Logger
+}
+
+// NewWebhookEventPublisher создает EventPublisher, который после публикации события
+// создает его доставки подписчикам вебхуков. Ошибка доставки не возвращается
+// источнику события, действие которого уже выполнено
+func NewWebhookEventPublisher(next EventPublisher, webhooks WebhookService, logger *zap.Logger) EventPublisher {
+	return &webhookEventPublisher{
+		next:     next,
+		webhooks: webhooks,
+		logger:   logger,
+	}
+}
+
+// PublishDriverEvent публикует событие и ставит его доставки в очередь
+func (p *webhookEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	err := p.next.PublishDriverEvent(ctx, eventType, driverID, data)
+
+	if werr := p.webhooks.Enqueue(ctx, eventType, driverID, data); werr != nil {
+		p.logger.Error("Failed to enqueue webhook deliveries",
+			zap.Error(werr),
+			zap.String("event_type", eventType),
+			zap.String("driver_id", driverID.String()),
+		)
+	}
+
+	return err
+}
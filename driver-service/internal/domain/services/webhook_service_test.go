@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeWebhookSender запоминает отправки и отвечает кодом status
+type fakeWebhookSender struct {
+	mu     sync.Mutex
+	status int
+	err    error
+	sent   []fakeWebhookSend
+}
+
+type fakeWebhookSend struct {
+	url      string
+	secret   string
+	delivery entities.WebhookDelivery
+}
+
+func (s *fakeWebhookSender) Send(ctx context.Context, url, secret string, delivery *entities.WebhookDelivery) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, fakeWebhookSend{url: url, secret: secret, delivery: *delivery})
+	return s.status, s.err
+}
+
+func testWebhookPolicy() WebhookPolicy {
+	return WebhookPolicy{Workers: 1, QueueSize: 10, MaxAttempts: 2, InitialBackoff: time.Millisecond, MaxBackoff: time.Second}
+}
+
+func TestWebhookService_CreateSubscription(t *testing.T) {
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{Subject: "admin-1"})
+	service := NewWebhookService(memory.NewWebhookRepository(), &fakeWebhookSender{}, testWebhookPolicy(), zap.NewNop())
+
+	_, err := service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name: "Partner", URL: "ftp://partner.example.com/hook", EventTypes: []string{eventDriverStatusChanged},
+	})
+	assert.ErrorIs(t, err, entities.ErrInvalidWebhook)
+	_, err = service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name: "Partner", URL: "https://partner.example.com/hook", EventTypes: []string{"driver.unknown"},
+	})
+	assert.ErrorIs(t, err, entities.ErrUnknownWebhookEvent)
+
+	created, err := service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name:       " Partner ",
+		URL:        "https://partner.example.com/hook",
+		EventTypes: []string{eventDriverStatusChanged, eventDriverStatusChanged},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Partner", created.Name)
+	assert.Equal(t, "admin-1", created.CreatedBy)
+	assert.Equal(t, entities.WebhookEventTypeList{eventDriverStatusChanged}, created.EventTypes)
+	assert.NotEmpty(t, created.SigningSecret)
+
+	// Ключ подписи не попадает в список подписок
+	subscriptions, err := service.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subscriptions, 1)
+	body, err := json.Marshal(subscriptions[0])
+	require.NoError(t, err)
+	assert.NotContains(t, string(body), created.SigningSecret)
+
+	require.NoError(t, service.DeleteSubscription(ctx, created.ID))
+	assert.ErrorIs(t, service.DeleteSubscription(ctx, created.ID), entities.ErrWebhookNotFound)
+}
+
+func TestWebhookService_Deliver(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewWebhookRepository()
+	sender := &fakeWebhookSender{status: 204}
+	service := NewWebhookService(repo, sender, testWebhookPolicy(), zap.NewNop())
+
+	statuses, err := service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name: "Statuses", URL: "https://statuses.example.com/hook", EventTypes: []string{eventDriverStatusChanged},
+		Secret: "statuses-secret-1234",
+	})
+	require.NoError(t, err)
+	_, err = service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name: "Shifts", URL: "https://shifts.example.com/hook", EventTypes: []string{eventShiftEnded},
+	})
+	require.NoError(t, err)
+
+	// Событие проходит к следующему издателю и доставляется только подписанным на него
+	next := &recordingEventPublisher{}
+	publisher := NewWebhookEventPublisher(next, service, zap.NewNop())
+	driverID := uuid.New()
+	require.NoError(t, publisher.PublishDriverEvent(ctx, eventDriverStatusChanged, driverID, map[string]interface{}{"status": "busy"}))
+	assert.True(t, next.has(eventDriverStatusChanged))
+
+	_, err = service.Drain(ctx)
+	require.NoError(t, err)
+
+	require.Len(t, sender.sent, 1)
+	sent := sender.sent[0]
+	assert.Equal(t, "https://statuses.example.com/hook", sent.url)
+	assert.Equal(t, "statuses-secret-1234", sent.secret)
+
+	var event entities.WebhookEvent
+	require.NoError(t, json.Unmarshal(sent.delivery.Payload, &event))
+	assert.Equal(t, eventDriverStatusChanged, event.Type)
+	assert.Equal(t, driverID, event.DriverID)
+	assert.Equal(t, map[string]interface{}{"status": "busy"}, event.Data)
+
+	deliveries, err := service.ListDeliveries(ctx, &entities.WebhookDeliveryFilters{SubscriptionID: statuses.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, entities.WebhookDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	require.NotNil(t, deliveries[0].LastStatusCode)
+	assert.Equal(t, 204, *deliveries[0].LastStatusCode)
+
+	_, err = service.ListDeliveries(ctx, &entities.WebhookDeliveryFilters{SubscriptionID: uuid.New()})
+	assert.ErrorIs(t, err, entities.ErrWebhookNotFound)
+}
+
+func TestWebhookService_RetryAndRedeliver(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewWebhookRepository()
+	sender := &fakeWebhookSender{status: 503}
+	service := NewWebhookService(repo, sender, testWebhookPolicy(), zap.NewNop())
+
+	subscription, err := service.CreateSubscription(ctx, &entities.WebhookSubscriptionRequest{
+		Name: "Partner", URL: "https://partner.example.com/hook", EventTypes: []string{eventDocumentVerified},
+	})
+	require.NoError(t, err)
+	require.NoError(t, service.Enqueue(ctx, eventDocumentVerified, uuid.New(), nil))
+	_, err = service.Drain(ctx)
+	require.NoError(t, err)
+
+	deliveries, err := repo.ListDeliveries(ctx, &entities.WebhookDeliveryFilters{SubscriptionID: subscription.ID})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	failing := deliveries[0]
+	assert.Equal(t, entities.WebhookDeliveryPending, failing.Status)
+	assert.Equal(t, 1, failing.Attempts)
+	require.NotNil(t, failing.LastError)
+
+	// Вторая попытка исчерпывает MaxAttempts
+	sender.err = errors.New("connection refused")
+	sender.status = 0
+	time.Sleep(5 * time.Millisecond)
+	service = NewWebhookService(repo, sender, testWebhookPolicy(), zap.NewNop())
+	retried, err := service.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, retried)
+	_, err = service.Drain(ctx)
+	require.NoError(t, err)
+
+	failed, err := repo.GetDelivery(ctx, failing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryFailed, failed.Status)
+	assert.Equal(t, 2, failed.Attempts)
+	assert.Nil(t, failed.LastStatusCode)
+	assert.Equal(t, "connection refused", *failed.LastError)
+
+	// Неудачная доставка больше не повторяется, но ее можно отправить вручную с тем же телом
+	sender.err = nil
+	sender.status = 200
+	service = NewWebhookService(repo, sender, testWebhookPolicy(), zap.NewNop())
+	retried, err = service.RetryDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, retried)
+
+	redelivery, err := service.Redeliver(ctx, failing.ID)
+	require.NoError(t, err)
+	require.NotNil(t, redelivery.RedeliveryOf)
+	assert.Equal(t, failing.ID, *redelivery.RedeliveryOf)
+	assert.Equal(t, failing.EventID, redelivery.EventID)
+	_, err = service.Drain(ctx)
+	require.NoError(t, err)
+
+	delivered, err := repo.GetDelivery(ctx, redelivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.WebhookDeliveryDelivered, delivered.Status)
+	assert.Equal(t, string(failing.Payload), string(sender.sent[len(sender.sent)-1].delivery.Payload))
+
+	_, err = service.Redeliver(ctx, uuid.New())
+	assert.ErrorIs(t, err, entities.ErrWebhookDeliveryNotFound)
+}
@@ -0,0 +1,241 @@
+// Package analytics копирует принятые местоположения в ClickHouse и выполняет по ним
+// аналитические запросы, слишком тяжелые для основной базы.
+//
+// ClickHouse вызывается через HTTP интерфейс. Точки вставляются асинхронными вставками
+// (async_insert) с ожиданием записи, поэтому подтвержденный пакет уже сохранен. Неудачная
+// вставка повторяется, и одна точка может быть записана дважды; таблица ReplacingMergeTree
+// схлопывает повторы по ID точки, а запросы читают ее с FINAL
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// clickHouseTimeLayout формат DateTime64(3) в запросах и вставках (UTC)
+const clickHouseTimeLayout = "2006-01-02 15:04:05.000"
+
+// maxErrorBodySize сколько байт ответа с ошибкой попадает в текст ошибки
+const maxErrorBodySize = 4 << 10
+
+// Client клиент HTTP интерфейса ClickHouse для таблицы копий местоположений
+type Client struct {
+	endpoint string
+	database string
+	table    string
+	user     string
+	password string
+	client   *http.Client
+}
+
+var _ services.LocationAnalyticsStore = (*Client)(nil)
+
+// NewClient создает клиента ClickHouse из конфигурации
+func NewClient(cfg config.LocationAnalyticsConfig) *Client {
+	return &Client{
+		endpoint: strings.TrimRight(cfg.URL, "/") + "/",
+		database: cfg.Database,
+		table:    cfg.Table,
+		user:     cfg.User,
+		password: cfg.Password,
+		client:   &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// tableName полное имя таблицы; имена проверены при загрузке конфигурации
+func (c *Client) tableName() string {
+	return c.database + "." + c.table
+}
+
+// EnsureSchema создает таблицу копий местоположений, если ее нет
+func (c *Client) EnsureSchema(ctx context.Context) error {
+	query := `CREATE TABLE IF NOT EXISTS ` + c.tableName() + ` (
+	id UUID,
+	driver_id UUID,
+	city LowCardinality(String),
+	latitude Float64,
+	longitude Float64,
+	speed Nullable(Float64),
+	accuracy Nullable(Float64),
+	on_trip Bool,
+	recorded_at DateTime64(3, 'UTC'),
+	inserted_at DateTime64(3, 'UTC') DEFAULT now64(3)
+)
+ENGINE = ReplacingMergeTree(inserted_at)
+PARTITION BY toYYYYMM(recorded_at)
+ORDER BY (city, toDate(recorded_at), driver_id, recorded_at, id)`
+
+	body, err := c.do(ctx, query, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create location analytics table: %w", err)
+	}
+	return body.Close()
+}
+
+// locationRow строка таблицы копий местоположений в формате JSONEachRow
+type locationRow struct {
+	ID         string   `json:"id"`
+	DriverID   string   `json:"driver_id"`
+	City       string   `json:"city"`
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	Speed      *float64 `json:"speed"`
+	Accuracy   *float64 `json:"accuracy"`
+	OnTrip     bool     `json:"on_trip"`
+	RecordedAt string   `json:"recorded_at"`
+}
+
+// newLocationRow копирует поля местоположения, нужные аналитике
+func newLocationRow(location *entities.DriverLocation) locationRow {
+	return locationRow{
+		ID:         location.ID.String(),
+		DriverID:   location.DriverID.String(),
+		City:       location.City(),
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		Speed:      location.Speed,
+		Accuracy:   location.Accuracy,
+		OnTrip:     location.IsOnTrip(),
+		RecordedAt: location.RecordedAt.UTC().Format(clickHouseTimeLayout),
+	}
+}
+
+// insertLocations вставляет пакет строк и ждет, пока ClickHouse запишет асинхронную вставку
+func (c *Client) insertLocations(ctx context.Context, rows []locationRow) error {
+	var payload bytes.Buffer
+	encoder := json.NewEncoder(&payload)
+	for i := range rows {
+		if err := encoder.Encode(&rows[i]); err != nil {
+			return fmt.Errorf("failed to encode location row: %w", err)
+		}
+	}
+
+	settings := url.Values{
+		"async_insert":          {"1"},
+		"wait_for_async_insert": {"1"},
+	}
+	body, err := c.do(ctx, "INSERT INTO "+c.tableName()+" FORMAT JSONEachRow", settings, &payload)
+	if err != nil {
+		return fmt.Errorf("failed to insert locations: %w", err)
+	}
+	return body.Close()
+}
+
+// distanceByCityQuery пробег по городам и суткам: сумма расстояний между соседними точками
+// водителя за сутки; первая точка суток водителя пробега не добавляет
+const distanceByCityQuery = `SELECT
+	city,
+	toString(day) AS day,
+	sum(distance_m) / 1000 AS distance_km,
+	uniqExact(driver_id) AS drivers,
+	count() AS points
+FROM (
+	SELECT
+		city,
+		toDate(recorded_at) AS day,
+		driver_id,
+		if(row_number() OVER w = 1, 0,
+			geoDistance(longitude, latitude, lagInFrame(longitude) OVER w, lagInFrame(latitude) OVER w)) AS distance_m
+	FROM %s FINAL
+	WHERE recorded_at >= {from:DateTime64(3, 'UTC')} AND recorded_at < {to:DateTime64(3, 'UTC')}
+		AND ({city:String} = '' OR city = {city:String})
+	WINDOW w AS (PARTITION BY driver_id, toDate(recorded_at) ORDER BY recorded_at ROWS BETWEEN 1 PRECEDING AND CURRENT ROW)
+)
+GROUP BY city, day
+ORDER BY day, city
+FORMAT JSONEachRow`
+
+// cityDistanceRow строка ответа distanceByCityQuery
+type cityDistanceRow struct {
+	City       string  `json:"city"`
+	Day        string  `json:"day"`
+	DistanceKm float64 `json:"distance_km"`
+	Drivers    int     `json:"drivers"`
+	Points     int64   `json:"points"`
+}
+
+// DistanceByCity считает пробег водителей по городам и суткам (UTC)
+func (c *Client) DistanceByCity(ctx context.Context, query *entities.CityDistanceQuery) ([]*entities.CityDistance, error) {
+	params := url.Values{
+		"param_from": {query.From.UTC().Format(clickHouseTimeLayout)},
+		"param_to":   {query.To.UTC().Format(clickHouseTimeLayout)},
+		"param_city": {query.City},
+		// Счетчики UInt64 возвращаются числами, а не строками
+		"output_format_json_quote_64bit_integers":     {"0"},
+		"do_not_merge_across_partitions_select_final": {"1"},
+	}
+	body, err := c.do(ctx, fmt.Sprintf(distanceByCityQuery, c.tableName()), params, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query distance by city: %w", err)
+	}
+	defer body.Close()
+
+	var items []*entities.CityDistance
+	decoder := json.NewDecoder(body)
+	for decoder.More() {
+		var row cityDistanceRow
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("invalid distance by city row: %w", err)
+		}
+		day, err := time.Parse("2006-01-02", row.Day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid distance by city day %q: %w", row.Day, err)
+		}
+		items = append(items, &entities.CityDistance{
+			City:       row.City,
+			Day:        day,
+			DistanceKm: row.DistanceKm,
+			Drivers:    row.Drivers,
+			Points:     row.Points,
+		})
+	}
+	return items, nil
+}
+
+// do отправляет запрос query с параметрами params; body — данные вставки. Возвращает тело
+// ответа, которое закрывает вызывающий
+func (c *Client) do(ctx context.Context, query string, params url.Values, body io.Reader) (io.ReadCloser, error) {
+	values := url.Values{}
+	for key, value := range params {
+		values[key] = value
+	}
+	values.Set("database", c.database)
+
+	// Запрос без данных передается телом, вставка — параметром query
+	if body == nil {
+		body = strings.NewReader(query)
+	} else {
+		values.Set("query", query)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"?"+values.Encode(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build clickhouse request: %w", err)
+	}
+	req.Header.Set("X-ClickHouse-User", c.user)
+	if c.password != "" {
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse request failed: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return nil, fmt.Errorf("clickhouse responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return resp.Body, nil
+}
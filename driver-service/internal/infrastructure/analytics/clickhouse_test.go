@@ -0,0 +1,195 @@
+package analytics
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeClickHouse HTTP интерфейс ClickHouse, запоминающий вставленные строки. Первые
+// failures вставок завершаются ошибкой
+type fakeClickHouse struct {
+	mu       sync.Mutex
+	rows     []locationRow
+	requests []*http.Request
+	failures int32
+	respond  string
+}
+
+func (f *fakeClickHouse) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r)
+
+	if !strings.HasPrefix(r.URL.Query().Get("query"), "INSERT") {
+		_, _ = io.WriteString(w, f.respond)
+		return
+	}
+	if atomic.AddInt32(&f.failures, -1) >= 0 {
+		http.Error(w, "Code: 252. DB::Exception: Too many parts", http.StatusInternalServerError)
+		return
+	}
+	scanner := bufio.NewScanner(strings.NewReader(string(body)))
+	for scanner.Scan() {
+		var row locationRow
+		if err := json.Unmarshal(scanner.Bytes(), &row); err == nil {
+			f.rows = append(f.rows, row)
+		}
+	}
+}
+
+func (f *fakeClickHouse) insertedRows() []locationRow {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]locationRow(nil), f.rows...)
+}
+
+func testAnalyticsConfig(url string) config.LocationAnalyticsConfig {
+	return config.LocationAnalyticsConfig{
+		Enabled:          true,
+		URL:              url,
+		Database:         "analytics",
+		Table:            "driver_locations",
+		User:             "writer",
+		Password:         "secret",
+		BufferSize:       100,
+		BatchSize:        3,
+		FlushInterval:    20 * time.Millisecond,
+		Timeout:          time.Second,
+		RetryInterval:    5 * time.Millisecond,
+		MaxRetryInterval: 20 * time.Millisecond,
+	}
+}
+
+func testLocation(city string) *entities.DriverLocation {
+	location := entities.NewDriverLocation(uuid.New(), 55.75, 37.61, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	location.ID = uuid.New()
+	location.SetCity(city)
+	return location
+}
+
+func TestLocationSink_InsertsBatches(t *testing.T) {
+	fake := &fakeClickHouse{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := testAnalyticsConfig(server.URL)
+	sink := NewLocationSink(NewClient(cfg), cfg, zap.NewNop())
+	for i := 0; i < 4; i++ {
+		sink.BroadcastLocation(testLocation("moscow"))
+	}
+
+	// Полный пакет из трех точек отправляется сразу, остаток — по FlushInterval
+	require.Eventually(t, func() bool { return len(fake.insertedRows()) == 4 }, time.Second, 5*time.Millisecond)
+
+	dropped, err := sink.Drain(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+
+	row := fake.insertedRows()[0]
+	assert.Equal(t, "moscow", row.City)
+	assert.Equal(t, "2026-10-16 09:30:00.000", row.RecordedAt)
+
+	fake.mu.Lock()
+	req := fake.requests[0]
+	fake.mu.Unlock()
+	assert.Equal(t, "analytics", req.URL.Query().Get("database"))
+	assert.Equal(t, "1", req.URL.Query().Get("wait_for_async_insert"))
+	assert.Equal(t, "INSERT INTO analytics.driver_locations FORMAT JSONEachRow", req.URL.Query().Get("query"))
+	assert.Equal(t, "writer", req.Header.Get("X-ClickHouse-User"))
+	assert.Equal(t, "secret", req.Header.Get("X-ClickHouse-Key"))
+}
+
+func TestLocationSink_RetriesFailedInsert(t *testing.T) {
+	fake := &fakeClickHouse{failures: 2}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := testAnalyticsConfig(server.URL)
+	sink := NewLocationSink(NewClient(cfg), cfg, zap.NewNop())
+	sink.BroadcastLocation(testLocation("moscow"))
+
+	dropped, err := sink.Drain(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, dropped)
+	assert.Len(t, fake.insertedRows(), 1)
+}
+
+func TestLocationSink_DrainReportsUnsent(t *testing.T) {
+	fake := &fakeClickHouse{failures: 1 << 20}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cfg := testAnalyticsConfig(server.URL)
+	sink := NewLocationSink(NewClient(cfg), cfg, zap.NewNop())
+	sink.BroadcastLocation(testLocation("moscow"))
+	sink.BroadcastLocation(testLocation("moscow"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	dropped, err := sink.Drain(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 2, dropped)
+
+	// После остановки точки не принимаются
+	sink.BroadcastLocation(testLocation("moscow"))
+	assert.Empty(t, fake.insertedRows())
+}
+
+func TestClient_DistanceByCity(t *testing.T) {
+	fake := &fakeClickHouse{respond: `{"city":"moscow","day":"2026-10-15","distance_km":1250.5,"drivers":42,"points":100500}
+{"city":"","day":"2026-10-15","distance_km":3.2,"drivers":1,"points":12}
+`}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(testAnalyticsConfig(server.URL))
+	items, err := client.DistanceByCity(context.Background(), &entities.CityDistanceQuery{
+		From: time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC),
+		City: "moscow",
+	})
+	require.NoError(t, err)
+	require.Len(t, items, 2)
+	assert.Equal(t, "moscow", items[0].City)
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), items[0].Day)
+	assert.Equal(t, 1250.5, items[0].DistanceKm)
+	assert.Equal(t, 42, items[0].Drivers)
+	assert.Equal(t, int64(100500), items[0].Points)
+
+	params := fake.requests[0].URL.Query()
+	assert.Equal(t, "2026-10-15 00:00:00.000", params.Get("param_from"))
+	assert.Equal(t, "2026-10-16 00:00:00.000", params.Get("param_to"))
+	assert.Equal(t, "moscow", params.Get("param_city"))
+}
+
+func TestClient_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Code: 60. DB::Exception: Table analytics.driver_locations does not exist", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(testAnalyticsConfig(server.URL)).DistanceByCity(context.Background(), &entities.CityDistanceQuery{
+		From: time.Now().Add(-time.Hour),
+		To:   time.Now(),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not exist")
+}
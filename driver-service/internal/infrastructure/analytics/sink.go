@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"go.uber.org/zap"
+)
+
+// sinkStats счетчики копирования местоположений (expvar location_analytics): inserted,
+// dropped — не попавшие в буфер или не записанные при остановке, retries — повторы вставок
+var sinkStats = expvar.NewMap("location_analytics")
+
+// LocationSink копирует принятые местоположения в ClickHouse пакетами в фоне.
+// Реализует services.LocationBroadcaster: точка ставится в буфер без ожидания, при
+// заполненном буфере не копируется. Пакет, вставка которого не удалась, повторяется с
+// растущей паузой, пока не будет записан или не истечет срок остановки
+type LocationSink struct {
+	client           *Client
+	queue            chan locationRow
+	batchSize        int
+	flushInterval    time.Duration
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+	logger           *zap.Logger
+
+	// ctx отменяется, когда истекает срок Drain; прерывает вставку и повторы
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+	once   sync.Once
+	// unsent точки, не записанные к отмене ctx
+	unsent int
+}
+
+var _ services.LocationBroadcaster = (*LocationSink)(nil)
+
+// NewLocationSink создает копирование местоположений и запускает его обработчик;
+// остановить его нужно через Drain
+func NewLocationSink(client *Client, cfg config.LocationAnalyticsConfig, logger *zap.Logger) *LocationSink {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &LocationSink{
+		client:           client,
+		queue:            make(chan locationRow, cfg.BufferSize),
+		batchSize:        cfg.BatchSize,
+		flushInterval:    cfg.FlushInterval,
+		retryInterval:    cfg.RetryInterval,
+		maxRetryInterval: cfg.MaxRetryInterval,
+		logger:           logger,
+		ctx:              ctx,
+		cancel:           cancel,
+		stop:             make(chan struct{}),
+		done:             make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// BroadcastLocation ставит копию точки в буфер отправки
+func (s *LocationSink) BroadcastLocation(location *entities.DriverLocation) {
+	select {
+	case <-s.stop:
+		sinkStats.Add("dropped", 1)
+		return
+	default:
+	}
+
+	select {
+	case s.queue <- newLocationRow(location):
+	default:
+		sinkStats.Add("dropped", 1)
+	}
+}
+
+// Drain отправляет точки из буфера до истечения ctx и останавливает обработчик.
+// Возвращает число точек, которые записать не удалось
+func (s *LocationSink) Drain(ctx context.Context) (int, error) {
+	s.once.Do(func() { close(s.stop) })
+
+	select {
+	case <-s.done:
+	case <-ctx.Done():
+		s.cancel()
+		<-s.done
+	}
+	s.cancel()
+
+	if s.unsent > 0 {
+		sinkStats.Add("dropped", int64(s.unsent))
+	}
+	return s.unsent, ctx.Err()
+}
+
+// run собирает пакеты из буфера: пакет отправляется, когда набрано batchSize точек
+// или прошел flushInterval
+func (s *LocationSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]locationRow, 0, s.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if !s.insert(batch) {
+			s.unsent += len(batch)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case row := <-s.queue:
+			batch = append(batch, row)
+			if len(batch) >= s.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-s.stop:
+			for {
+				select {
+				case row := <-s.queue:
+					batch = append(batch, row)
+					if len(batch) >= s.batchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// insert вставляет пакет, повторяя неудачные попытки с удвоением паузы до maxRetryInterval.
+// Возвращает false, если ctx отменен раньше, чем пакет записан
+func (s *LocationSink) insert(batch []locationRow) bool {
+	delay := s.retryInterval
+	for attempt := 1; ; attempt++ {
+		if s.ctx.Err() != nil {
+			return false
+		}
+
+		err := s.client.insertLocations(s.ctx, batch)
+		if err == nil {
+			sinkStats.Add("inserted", int64(len(batch)))
+			return true
+		}
+
+		s.logger.Warn("Failed to copy locations to analytics store, retrying",
+			zap.Error(err),
+			zap.Int("count", len(batch)),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+		)
+		sinkStats.Add("retries", 1)
+
+		select {
+		case <-time.After(delay):
+		case <-s.ctx.Done():
+			return false
+		}
+		delay *= 2
+		if delay > s.maxRetryInterval {
+			delay = s.maxRetryInterval
+		}
+	}
+}
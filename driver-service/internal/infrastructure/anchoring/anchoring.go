@@ -0,0 +1,43 @@
+// Package anchoring содержит внешние журналы, в которых закрепляются хеши цепочки аудита.
+package anchoring
+
+import (
+	"fmt"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// Record запись о закреплении во внешнем журнале
+type Record struct {
+	Service    string `json:"service"`
+	Sequence   int64  `json:"sequence"`
+	Hash       string `json:"hash"`
+	AnchoredAt string `json:"anchored_at"`
+}
+
+// NewRecord формирует запись о закреплении
+func NewRecord(anchor *entities.AuditAnchor) *Record {
+	return &Record{
+		Service:    "driver-service",
+		Sequence:   anchor.Sequence,
+		Hash:       anchor.Hash,
+		AnchoredAt: anchor.AnchoredAt.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// New создает внешний журнал из конфигурации; nil, если закрепление не настроено
+func New(cfg config.AuditAnchorConfig) (services.AuditAnchorSink, error) {
+	switch cfg.Sink {
+	case "":
+		return nil, nil
+	case config.AuditAnchorSinkFile:
+		return NewFileSink(cfg.FilePath), nil
+	case config.AuditAnchorSinkWebhook:
+		return NewWebhookSink(cfg.URL, cfg.Secret, cfg.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown audit anchor sink: %s", cfg.Sink)
+	}
+}
@@ -0,0 +1,78 @@
+package anchoring
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/webhooks"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAnchor(sequence int64) *entities.AuditAnchor {
+	return &entities.AuditAnchor{
+		ID:         uuid.New(),
+		Sequence:   sequence,
+		Hash:       entities.AuditChainGenesis,
+		AnchoredAt: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFileSink_AppendsRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anchors.log")
+	sink := NewFileSink(path)
+
+	first, err := sink.Anchor(context.Background(), newTestAnchor(1))
+	require.NoError(t, err)
+	second, err := sink.Anchor(context.Background(), newTestAnchor(2))
+	require.NoError(t, err)
+	assert.Equal(t, path+"@0", first)
+	assert.NotEqual(t, first, second)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2)
+	assert.Equal(t, int64(2), records[1].Sequence)
+	assert.Equal(t, "2024-03-01T10:00:00Z", records[1].AnchoredAt)
+}
+
+func TestWebhookSink_SignsRequest(t *testing.T) {
+	secret := "anchor-secret"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(webhooks.SignatureHeader) != webhooks.Sign([]byte(secret), body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"reference":"log-42"}`))
+	}))
+	defer server.Close()
+
+	reference, err := NewWebhookSink(server.URL, secret, time.Second).Anchor(context.Background(), newTestAnchor(7))
+	require.NoError(t, err)
+	assert.Equal(t, "log-42", reference)
+
+	_, err = NewWebhookSink(server.URL, "wrong", time.Second).Anchor(context.Background(), newTestAnchor(7))
+	assert.Error(t, err)
+}
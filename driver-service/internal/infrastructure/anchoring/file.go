@@ -0,0 +1,63 @@
+package anchoring
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// FileSink дописывает закрепления строками JSON в файл. Файл должен лежать на томе,
+// запрещающем перезапись (WORM, object lock), или забираться сборщиком логов
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+var _ services.AuditAnchorSink = (*FileSink)(nil)
+
+// NewFileSink создает FileSink
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Name возвращает имя журнала
+func (s *FileSink) Name() string {
+	return config.AuditAnchorSinkFile
+}
+
+// Anchor дописывает закрепление в файл и синхронизирует его с диском.
+// Идентификатор записи — путь к файлу и смещение строки
+func (s *FileSink) Anchor(ctx context.Context, anchor *entities.AuditAnchor) (string, error) {
+	line, err := json.Marshal(NewRecord(anchor))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode anchor record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to open anchor file: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat anchor file: %w", err)
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return "", fmt.Errorf("failed to write anchor record: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return "", fmt.Errorf("failed to sync anchor file: %w", err)
+	}
+
+	return fmt.Sprintf("%s@%d", s.path, info.Size()), nil
+}
@@ -0,0 +1,87 @@
+package anchoring
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/infrastructure/webhooks"
+)
+
+// maxResponseSize ограничение размера ответа внешнего журнала
+const maxResponseSize = 1 << 16
+
+// WebhookSink отправляет закрепления POST-запросом во внешний журнал
+// (сервис прозрачности, штампов времени или журнал другой команды)
+type WebhookSink struct {
+	url     string
+	secret  []byte
+	timeout time.Duration
+	client  *http.Client
+}
+
+var _ services.AuditAnchorSink = (*WebhookSink)(nil)
+
+// NewWebhookSink создает WebhookSink. Тело запроса подписывается заголовком
+// webhooks.SignatureHeader, если задан secret
+func NewWebhookSink(url, secret string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{
+		url:     url,
+		secret:  []byte(secret),
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// Name возвращает имя журнала
+func (s *WebhookSink) Name() string {
+	return config.AuditAnchorSinkWebhook
+}
+
+// webhookResponse ответ внешнего журнала; reference необязателен
+type webhookResponse struct {
+	Reference string `json:"reference"`
+}
+
+// Anchor отправляет закрепление и возвращает идентификатор записи из ответа
+func (s *WebhookSink) Anchor(ctx context.Context, anchor *entities.AuditAnchor) (string, error) {
+	body, err := json.Marshal(NewRecord(anchor))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode anchor record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to build anchor request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(s.secret) > 0 {
+		req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("anchor request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		return "", fmt.Errorf("anchor log responded with status %d", resp.StatusCode)
+	}
+
+	var result webhookResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil && err != io.EOF {
+		return "", fmt.Errorf("invalid anchor log response: %w", err)
+	}
+	return result.Reference, nil
+}
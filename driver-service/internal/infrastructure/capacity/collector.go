@@ -0,0 +1,201 @@
+// Package capacity сбор метрик нагрузки экземпляра сервиса для планирования мощностей.
+//
+// Collector накапливает частоту и стоимость запросов по эндпоинтам и снимки пула
+// соединений в памяти; ежесуточная задача забирает накопленное через Drain и сохраняет отчет
+package capacity
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+)
+
+// unmatchedRoute маршрут для запросов, не попавших ни в один обработчик
+const unmatchedRoute = "<unmatched>"
+
+// endpointCounter счетчики эндпоинта за период
+type endpointCounter struct {
+	requests   int64
+	errors     int64
+	busy       time.Duration
+	maxLatency time.Duration
+	bytesOut   int64
+
+	// Запросы за текущую минуту и максимум за период — для пиковой частоты
+	minute        int64
+	minuteCount   int64
+	peakPerMinute int64
+}
+
+// poolCounter снимки пула соединений за период
+type poolCounter struct {
+	samples       int
+	maxOpen       int
+	sumInUse      int
+	peakInUse     int
+	sumSaturation float64
+	peak          float64
+	// Счетчики ожиданий в пуле накопительные, поэтому период считается от базового снимка
+	waitBase         int64
+	waitDurationBase time.Duration
+	waitLast         int64
+	waitDurationLast time.Duration
+}
+
+// Collector накопитель метрик нагрузки. Безопасен для конкурентного использования
+type Collector struct {
+	mu        sync.Mutex
+	from      time.Time
+	endpoints map[string]*endpointCounter
+	pool      poolCounter
+	now       func() time.Time
+}
+
+// NewCollector создает новый Collector; период начинается в момент создания
+func NewCollector() *Collector {
+	return newCollector(time.Now)
+}
+
+// newCollector создает Collector с заданными часами
+func newCollector(now func() time.Time) *Collector {
+	return &Collector{
+		from:      now(),
+		endpoints: make(map[string]*endpointCounter),
+		now:       now,
+	}
+}
+
+// RecordRequest учитывает обработанный запрос. route — шаблон пути обработчика
+// (пустой, если маршрут не найден); ошибками считаются ответы 5xx
+func (c *Collector) RecordRequest(method, route string, status int, latency time.Duration, size int) {
+	key := unmatchedRoute
+	if route != "" {
+		key = method + " " + route
+	}
+	minute := c.now().Unix() / 60
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter, ok := c.endpoints[key]
+	if !ok {
+		counter = &endpointCounter{}
+		c.endpoints[key] = counter
+	}
+
+	counter.requests++
+	if status >= 500 {
+		counter.errors++
+	}
+	counter.busy += latency
+	if latency > counter.maxLatency {
+		counter.maxLatency = latency
+	}
+	if size > 0 {
+		counter.bytesOut += int64(size)
+	}
+
+	if counter.minute != minute {
+		counter.minute = minute
+		counter.minuteCount = 0
+	}
+	counter.minuteCount++
+	if counter.minuteCount > counter.peakPerMinute {
+		counter.peakPerMinute = counter.minuteCount
+	}
+}
+
+// RecordPoolSample учитывает снимок пула соединений
+func (c *Collector) RecordPoolSample(sample entities.PoolSample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := &c.pool
+	if p.samples == 0 && p.waitLast == 0 && p.waitDurationLast == 0 {
+		p.waitBase = sample.WaitCount
+		p.waitDurationBase = sample.WaitDuration
+	}
+
+	p.samples++
+	p.maxOpen = sample.MaxOpen
+	p.sumInUse += sample.InUse
+	if sample.InUse > p.peakInUse {
+		p.peakInUse = sample.InUse
+	}
+	// Без ограничения числа соединений насыщенность не определена
+	if sample.MaxOpen > 0 {
+		saturation := float64(sample.InUse) / float64(sample.MaxOpen)
+		p.sumSaturation += saturation
+		if saturation > p.peak {
+			p.peak = saturation
+		}
+	}
+	p.waitLast = sample.WaitCount
+	p.waitDurationLast = sample.WaitDuration
+}
+
+// Drain возвращает нагрузку с начала периода и начинает новый период
+func (c *Collector) Drain() *entities.CapacityUsage {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	usage := &entities.CapacityUsage{
+		From:      c.from,
+		To:        now,
+		Endpoints: make([]entities.EndpointUsage, 0, len(c.endpoints)),
+		Pool:      c.pool.usage(),
+	}
+
+	for route, counter := range c.endpoints {
+		usage.Endpoints = append(usage.Endpoints, entities.EndpointUsage{
+			Route:        route,
+			Requests:     counter.requests,
+			Errors:       counter.errors,
+			AvgLatencyMs: milliseconds(counter.busy) / float64(counter.requests),
+			MaxLatencyMs: milliseconds(counter.maxLatency),
+			BusySeconds:  counter.busy.Seconds(),
+			BytesOut:     counter.bytesOut,
+			PeakRPS:      float64(counter.peakPerMinute) / 60,
+		})
+	}
+	sort.Slice(usage.Endpoints, func(i, j int) bool {
+		return usage.Endpoints[i].Route < usage.Endpoints[j].Route
+	})
+
+	c.from = now
+	c.endpoints = make(map[string]*endpointCounter)
+	c.pool = poolCounter{
+		waitBase:         c.pool.waitLast,
+		waitDurationBase: c.pool.waitDurationLast,
+		waitLast:         c.pool.waitLast,
+		waitDurationLast: c.pool.waitDurationLast,
+	}
+
+	return usage
+}
+
+// usage сводит снимки пула за период
+func (p *poolCounter) usage() entities.PoolUsage {
+	usage := entities.PoolUsage{
+		MaxOpenConnections: p.maxOpen,
+		Samples:            p.samples,
+		PeakInUse:          p.peakInUse,
+		PeakSaturation:     p.peak,
+		WaitCount:          p.waitLast - p.waitBase,
+		WaitDurationMs:     (p.waitDurationLast - p.waitDurationBase).Milliseconds(),
+	}
+	if p.samples > 0 {
+		usage.AvgInUse = float64(p.sumInUse) / float64(p.samples)
+		usage.AvgSaturation = p.sumSaturation / float64(p.samples)
+	}
+	return usage
+}
+
+// milliseconds переводит длительность в дробные миллисекунды
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,66 @@
+package capacity
+
+import (
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_RecordRequest(t *testing.T) {
+	now := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+	collector := newCollector(func() time.Time { return now })
+
+	// 90 запросов в первую минуту и 30 во вторую: пик — первая минута
+	for i := 0; i < 90; i++ {
+		collector.RecordRequest("GET", "/api/v1/drivers/:id", 200, 10*time.Millisecond, 100)
+	}
+	now = now.Add(time.Minute)
+	for i := 0; i < 30; i++ {
+		collector.RecordRequest("GET", "/api/v1/drivers/:id", 503, 30*time.Millisecond, 0)
+	}
+	collector.RecordRequest("GET", "", 404, time.Millisecond, 10)
+
+	usage := collector.Drain()
+	require.Len(t, usage.Endpoints, 2)
+
+	endpoint := usage.Endpoints[1]
+	assert.Equal(t, "<unmatched>", usage.Endpoints[0].Route)
+	assert.Equal(t, "GET /api/v1/drivers/:id", endpoint.Route)
+	assert.Equal(t, int64(120), endpoint.Requests)
+	assert.Equal(t, int64(30), endpoint.Errors)
+	assert.InDelta(t, 15, endpoint.AvgLatencyMs, 0.001)
+	assert.InDelta(t, 30, endpoint.MaxLatencyMs, 0.001)
+	assert.InDelta(t, 1.8, endpoint.BusySeconds, 0.001)
+	assert.Equal(t, int64(9000), endpoint.BytesOut)
+	assert.InDelta(t, 1.5, endpoint.PeakRPS, 0.001)
+
+	// После Drain начинается новый период
+	next := collector.Drain()
+	assert.Empty(t, next.Endpoints)
+	assert.Equal(t, usage.To, next.From)
+}
+
+func TestCollector_PoolSamples(t *testing.T) {
+	collector := NewCollector()
+
+	collector.RecordPoolSample(entities.PoolSample{InUse: 5, MaxOpen: 25, WaitCount: 100, WaitDuration: time.Second})
+	collector.RecordPoolSample(entities.PoolSample{InUse: 20, MaxOpen: 25, WaitCount: 110, WaitDuration: 2 * time.Second})
+
+	pool := collector.Drain().Pool
+	assert.Equal(t, 25, pool.MaxOpenConnections)
+	assert.Equal(t, 2, pool.Samples)
+	assert.Equal(t, 20, pool.PeakInUse)
+	assert.InDelta(t, 12.5, pool.AvgInUse, 0.001)
+	assert.InDelta(t, 0.5, pool.AvgSaturation, 0.001)
+	assert.InDelta(t, 0.8, pool.PeakSaturation, 0.001)
+	assert.Equal(t, int64(10), pool.WaitCount)
+	assert.Equal(t, int64(1000), pool.WaitDurationMs)
+
+	// Ожидания следующего периода считаются от последнего снимка
+	collector.RecordPoolSample(entities.PoolSample{InUse: 1, MaxOpen: 25, WaitCount: 115, WaitDuration: 2 * time.Second})
+	assert.Equal(t, int64(5), collector.Drain().Pool.WaitCount)
+}
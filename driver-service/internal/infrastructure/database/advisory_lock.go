@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// advisoryUnlockTimeout срок снятия блокировки после завершения задачи
+const advisoryUnlockTimeout = 5 * time.Second
+
+// AdvisoryLocker блокировки по имени на advisory locks PostgreSQL. Блокировка сессионная:
+// она держит отдельное соединение из пула до освобождения и снимается сервером сама,
+// если экземпляр сервиса упал или соединение разорвалось
+type AdvisoryLocker struct {
+	db *DB
+	// namespace первый ключ блокировки, чтобы имена не пересекались с блокировками других систем
+	namespace string
+}
+
+// NewAdvisoryLocker создает блокировки в пространстве имен namespace
+func NewAdvisoryLocker(db *DB, namespace string) *AdvisoryLocker {
+	return &AdvisoryLocker{
+		db:        db,
+		namespace: namespace,
+	}
+}
+
+// TryLock захватывает блокировку name без ожидания. Если блокировку держит другая сессия,
+// возвращает acquired=false; release освобождает захваченную блокировку и соединение
+func (l *AdvisoryLocker) TryLock(ctx context.Context, name string) (release func(), acquired bool, err error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get connection for advisory lock: %w", err)
+	}
+
+	if err := conn.QueryRowContext(ctx,
+		`SELECT pg_try_advisory_lock(hashtext($1), hashtext($2))`, l.namespace, name,
+	).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire advisory lock %q: %w", name, err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release = func() {
+		ctx, cancel := context.WithTimeout(context.Background(), advisoryUnlockTimeout)
+		defer cancel()
+
+		if _, err := conn.ExecContext(ctx,
+			`SELECT pg_advisory_unlock(hashtext($1), hashtext($2))`, l.namespace, name,
+		); err != nil {
+			l.db.logger.Warn("Failed to release advisory lock, discarding connection",
+				zap.Error(err),
+				zap.String("lock", name),
+			)
+			// Соединение с неснятой блокировкой нельзя возвращать в пул: закрытие
+			// сессии снимает блокировку на сервере
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
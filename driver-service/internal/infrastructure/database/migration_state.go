@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// LatestMigrationVersion возвращает номер последней миграции в migrations
+// (встроенные миграции — Migrations())
+func LatestMigrationVersion(migrations fs.FS) (uint, error) {
+	versions, err := migrationVersions(migrations)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, nil
+	}
+	return versions[len(versions)-1], nil
+}
+
+// upMigrationVersion номер миграции по имени файла NNNNNN_name.up.sql
+func upMigrationVersion(entry fs.DirEntry) (uint, bool) {
+	name := entry.Name()
+	if entry.IsDir() || !strings.HasSuffix(name, ".up.sql") {
+		return 0, false
+	}
+	prefix, _, found := strings.Cut(name, "_")
+	if !found {
+		return 0, false
+	}
+	version, err := strconv.ParseUint(prefix, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(version), true
+}
+
+// CheckMigrations проверяет состояние схемы: последняя примененная миграция должна быть не
+// ниже expected и не должна быть прервана (dirty). Запрос выполняется без повторов: проба
+// должна быстро сообщать о недоступной базе
+func (db *DB) CheckMigrations(ctx context.Context, expected uint) error {
+	var state struct {
+		Version uint `db:"version"`
+		Dirty   bool `db:"dirty"`
+	}
+	err := db.DB.GetContext(ctx, &state, "SELECT version, dirty FROM schema_migrations LIMIT 1")
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("no migrations applied, expected version %d", expected)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration state: %w", err)
+	}
+
+	if state.Dirty {
+		return fmt.Errorf("migration %d is dirty", state.Version)
+	}
+	if state.Version < expected {
+		return fmt.Errorf("migration version %d is behind expected %d", state.Version, expected)
+	}
+	return nil
+}
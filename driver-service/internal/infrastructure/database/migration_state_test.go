@@ -0,0 +1,64 @@
+package database
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000001_create_drivers.up.sql",
+		"000001_create_drivers.down.sql",
+		"000012_add_index.up.sql",
+		"000013_drop_index.down.sql",
+		"README.md",
+		"draft_migration.up.sql",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o644))
+	}
+
+	latest, err := LatestMigrationVersion(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Equal(t, uint(12), latest)
+
+	// Встроенные миграции репозитория
+	latest, err = LatestMigrationVersion(Migrations())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, latest, uint(24))
+
+	_, err = LatestMigrationVersion(os.DirFS(filepath.Join(dir, "missing")))
+	assert.Error(t, err)
+}
+
+// Встроенные миграции идут подряд с 1 и у каждой есть откат
+func TestMigrations_Embedded(t *testing.T) {
+	migrations := Migrations()
+	versions, err := migrationVersions(migrations)
+	require.NoError(t, err)
+	require.NotEmpty(t, versions)
+
+	entries, err := fs.ReadDir(migrations, ".")
+	require.NoError(t, err)
+	downs := make(map[string]bool)
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".down.sql"); ok {
+			downs[name] = true
+		}
+	}
+
+	for i, version := range versions {
+		assert.Equal(t, uint(i+1), version, "migration versions must be sequential")
+	}
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".up.sql"); ok {
+			assert.True(t, downs[name], "migration %s has no down migration", name)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_vehicle_inspections_updated_at ON vehicle_inspections;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_vehicle_inspections_vehicle_id;
+DROP INDEX IF EXISTS idx_vehicle_inspections_fleet_id;
+DROP INDEX IF EXISTS idx_vehicle_inspections_driver_id;
+DROP INDEX IF EXISTS idx_vehicle_inspections_status;
+DROP INDEX IF EXISTS idx_vehicle_inspections_pending;
+
+-- Drop table
+DROP TABLE IF EXISTS vehicle_inspections;
@@ -0,0 +1,35 @@
+-- Create vehicle_inspections table
+CREATE TABLE vehicle_inspections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL, -- Reference to vehicle (external service)
+    fleet_id UUID, -- Reference to fleet (external service)
+    driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+    inspection_type VARCHAR(50) NOT NULL DEFAULT 'periodic',
+    due_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(50) NOT NULL DEFAULT 'scheduled',
+    completed_at TIMESTAMP WITH TIME ZONE,
+    inspected_by VARCHAR(255),
+    notes TEXT,
+    reminder_sent_at TIMESTAMP WITH TIME ZONE,
+    metadata JSONB DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for vehicle_inspections table
+CREATE INDEX idx_vehicle_inspections_vehicle_id ON vehicle_inspections(vehicle_id, due_date DESC);
+CREATE INDEX idx_vehicle_inspections_fleet_id ON vehicle_inspections(fleet_id);
+CREATE INDEX idx_vehicle_inspections_driver_id ON vehicle_inspections(driver_id);
+CREATE INDEX idx_vehicle_inspections_status ON vehicle_inspections(status);
+
+-- Create partial index for pending inspections used by reminders and shift checks
+CREATE INDEX idx_vehicle_inspections_pending ON vehicle_inspections(due_date)
+    WHERE status = 'scheduled';
+
+-- Add check constraints
+ALTER TABLE vehicle_inspections ADD CONSTRAINT check_vehicle_inspections_status
+    CHECK (status IN ('scheduled', 'passed', 'failed', 'cancelled'));
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_vehicle_inspections_updated_at BEFORE UPDATE ON vehicle_inspections
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
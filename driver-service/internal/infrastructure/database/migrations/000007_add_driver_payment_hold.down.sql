@@ -0,0 +1,6 @@
+-- Drop payment hold columns from drivers table
+ALTER TABLE drivers DROP CONSTRAINT IF EXISTS check_drivers_payment_hold_reason;
+DROP INDEX IF EXISTS idx_drivers_payment_hold;
+ALTER TABLE drivers DROP COLUMN IF EXISTS payment_hold_at;
+ALTER TABLE drivers DROP COLUMN IF EXISTS payment_hold_reason;
+ALTER TABLE drivers DROP COLUMN IF EXISTS payment_hold;
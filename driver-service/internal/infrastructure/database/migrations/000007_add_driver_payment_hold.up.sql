@@ -0,0 +1,12 @@
+-- Add payment hold flag set by billing events (chargeback, fraud review)
+ALTER TABLE drivers ADD COLUMN payment_hold BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE drivers ADD COLUMN payment_hold_reason VARCHAR(50);
+ALTER TABLE drivers ADD COLUMN payment_hold_at TIMESTAMP WITH TIME ZONE;
+
+-- Create partial index for drivers on hold
+CREATE INDEX idx_drivers_payment_hold ON drivers(payment_hold_at) WHERE payment_hold = TRUE;
+
+-- Add check constraints
+ALTER TABLE drivers ADD CONSTRAINT check_drivers_payment_hold_reason CHECK (
+    payment_hold = FALSE OR payment_hold_reason IS NOT NULL
+);
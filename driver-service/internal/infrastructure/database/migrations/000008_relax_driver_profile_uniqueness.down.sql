@@ -0,0 +1,6 @@
+-- Restore unconditional uniqueness of email and license number
+DROP INDEX IF EXISTS idx_drivers_license_unique;
+DROP INDEX IF EXISTS idx_drivers_email_unique;
+
+ALTER TABLE drivers ADD CONSTRAINT drivers_email_key UNIQUE (email);
+ALTER TABLE drivers ADD CONSTRAINT drivers_license_number_key UNIQUE (license_number);
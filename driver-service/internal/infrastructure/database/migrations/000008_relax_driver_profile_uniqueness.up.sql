@@ -0,0 +1,8 @@
+-- Allow drivers to register with minimal profile data:
+-- email and license number stay empty until the verification stage,
+-- so uniqueness is enforced only for filled values
+ALTER TABLE drivers DROP CONSTRAINT IF EXISTS drivers_email_key;
+ALTER TABLE drivers DROP CONSTRAINT IF EXISTS drivers_license_number_key;
+
+CREATE UNIQUE INDEX idx_drivers_email_unique ON drivers(email) WHERE email <> '';
+CREATE UNIQUE INDEX idx_drivers_license_unique ON drivers(license_number) WHERE license_number <> '';
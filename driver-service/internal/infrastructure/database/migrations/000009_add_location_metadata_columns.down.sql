@@ -0,0 +1,8 @@
+-- Drop generated location metadata columns
+ALTER TABLE driver_locations DROP CONSTRAINT IF EXISTS check_driver_locations_metadata_object;
+DROP INDEX IF EXISTS idx_driver_locations_source;
+DROP INDEX IF EXISTS idx_driver_locations_on_trip;
+DROP INDEX IF EXISTS idx_driver_locations_order_id;
+ALTER TABLE driver_locations DROP COLUMN IF EXISTS source;
+ALTER TABLE driver_locations DROP COLUMN IF EXISTS order_id;
+ALTER TABLE driver_locations DROP COLUMN IF EXISTS on_trip;
@@ -0,0 +1,19 @@
+-- Expose commonly queried location metadata keys as generated columns
+ALTER TABLE driver_locations ADD COLUMN on_trip BOOLEAN
+    GENERATED ALWAYS AS ((metadata->>'on_trip') = 'true') STORED;
+ALTER TABLE driver_locations ADD COLUMN order_id TEXT
+    GENERATED ALWAYS AS (metadata->>'order_id') STORED;
+ALTER TABLE driver_locations ADD COLUMN source VARCHAR(20)
+    GENERATED ALWAYS AS (metadata->>'source') STORED;
+
+-- Create indexes for trip tracking queries
+CREATE INDEX idx_driver_locations_order_id ON driver_locations(order_id, recorded_at)
+    WHERE order_id IS NOT NULL;
+CREATE INDEX idx_driver_locations_on_trip ON driver_locations(driver_id, recorded_at DESC)
+    WHERE on_trip = TRUE;
+CREATE INDEX idx_driver_locations_source ON driver_locations(source)
+    WHERE source IS NOT NULL;
+
+-- Add check constraints
+ALTER TABLE driver_locations ADD CONSTRAINT check_driver_locations_metadata_object
+    CHECK (metadata IS NULL OR jsonb_typeof(metadata) = 'object');
@@ -0,0 +1,4 @@
+-- Drop weekly leaderboard aggregates
+DROP INDEX IF EXISTS idx_driver_weekly_stats_driver;
+DROP INDEX IF EXISTS idx_driver_weekly_stats_week_driver;
+DROP MATERIALIZED VIEW IF EXISTS driver_weekly_stats;
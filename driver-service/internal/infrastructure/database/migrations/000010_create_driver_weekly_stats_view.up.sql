@@ -0,0 +1,34 @@
+-- Weekly per-driver aggregates for leaderboards (weeks start on Monday, UTC)
+CREATE MATERIALIZED VIEW driver_weekly_stats AS
+WITH shift_stats AS (
+    SELECT
+        driver_id,
+        date_trunc('week', start_time AT TIME ZONE 'UTC') AS week_start,
+        SUM(total_trips)::INTEGER AS trips,
+        SUM(total_earnings)::DOUBLE PRECISION AS earnings
+    FROM driver_shifts
+    WHERE status IN ('active', 'completed')
+    GROUP BY driver_id, date_trunc('week', start_time AT TIME ZONE 'UTC')
+),
+rating_stats AS (
+    SELECT
+        driver_id,
+        date_trunc('week', created_at AT TIME ZONE 'UTC') AS week_start,
+        AVG(rating)::DOUBLE PRECISION AS average_rating,
+        COUNT(*)::INTEGER AS rating_count
+    FROM driver_ratings
+    GROUP BY driver_id, date_trunc('week', created_at AT TIME ZONE 'UTC')
+)
+SELECT
+    COALESCE(s.driver_id, r.driver_id) AS driver_id,
+    COALESCE(s.week_start, r.week_start) AT TIME ZONE 'UTC' AS week_start,
+    COALESCE(s.trips, 0) AS trips,
+    COALESCE(s.earnings, 0) AS earnings,
+    COALESCE(r.average_rating, 0) AS average_rating,
+    COALESCE(r.rating_count, 0) AS rating_count
+FROM shift_stats s
+FULL OUTER JOIN rating_stats r ON r.driver_id = s.driver_id AND r.week_start = s.week_start;
+
+-- Unique index is required for REFRESH MATERIALIZED VIEW CONCURRENTLY
+CREATE UNIQUE INDEX idx_driver_weekly_stats_week_driver ON driver_weekly_stats(week_start, driver_id);
+CREATE INDEX idx_driver_weekly_stats_driver ON driver_weekly_stats(driver_id, week_start DESC);
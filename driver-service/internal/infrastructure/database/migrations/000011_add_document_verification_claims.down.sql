@@ -0,0 +1,8 @@
+-- Drop verification queue claims
+ALTER TABLE driver_documents DROP CONSTRAINT IF EXISTS check_driver_documents_claim;
+DROP INDEX IF EXISTS idx_driver_documents_verifier;
+DROP INDEX IF EXISTS idx_driver_documents_claim_expiry;
+DROP INDEX IF EXISTS idx_driver_documents_pending_queue;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS claim_expires_at;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS claimed_at;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS claimed_by;
@@ -0,0 +1,19 @@
+-- Track which verifier holds a document from the verification queue
+ALTER TABLE driver_documents ADD COLUMN claimed_by VARCHAR(255);
+ALTER TABLE driver_documents ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE driver_documents ADD COLUMN claim_expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Create indexes for the verification queue
+CREATE INDEX idx_driver_documents_pending_queue ON driver_documents(created_at)
+    WHERE status = 'pending';
+CREATE INDEX idx_driver_documents_claim_expiry ON driver_documents(claim_expires_at)
+    WHERE status = 'processing';
+CREATE INDEX idx_driver_documents_verifier ON driver_documents(verified_by, verified_at)
+    WHERE verified_by IS NOT NULL;
+
+-- Documents left in processing without a claim go back to the queue
+UPDATE driver_documents SET status = 'pending' WHERE status = 'processing';
+
+-- Add check constraints
+ALTER TABLE driver_documents ADD CONSTRAINT check_driver_documents_claim
+    CHECK (status <> 'processing' OR (claimed_by IS NOT NULL AND claim_expires_at IS NOT NULL));
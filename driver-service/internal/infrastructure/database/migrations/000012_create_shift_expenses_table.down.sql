@@ -0,0 +1,10 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_shift_expenses_updated_at ON shift_expenses;
+
+-- Drop indexes
+DROP INDEX IF EXISTS idx_shift_expenses_shift_id;
+DROP INDEX IF EXISTS idx_shift_expenses_driver_incurred;
+DROP INDEX IF EXISTS idx_shift_expenses_not_exported;
+
+-- Drop table
+DROP TABLE IF EXISTS shift_expenses;
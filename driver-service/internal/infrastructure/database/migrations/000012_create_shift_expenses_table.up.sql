@@ -0,0 +1,34 @@
+-- Create shift_expenses table
+CREATE TABLE shift_expenses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    shift_id UUID NOT NULL REFERENCES driver_shifts(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    category VARCHAR(50) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    description TEXT,
+    receipt_url TEXT,
+    incurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    exported_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for shift_expenses table
+CREATE INDEX idx_shift_expenses_shift_id ON shift_expenses(shift_id);
+CREATE INDEX idx_shift_expenses_driver_incurred ON shift_expenses(driver_id, incurred_at DESC);
+
+-- Create partial index for expenses not yet exported to accounting
+CREATE INDEX idx_shift_expenses_not_exported ON shift_expenses(incurred_at)
+    WHERE exported_at IS NULL;
+
+-- Add check constraints
+ALTER TABLE shift_expenses ADD CONSTRAINT check_shift_expenses_category
+    CHECK (category IN ('fuel', 'toll', 'wash', 'parking', 'other'));
+
+ALTER TABLE shift_expenses ADD CONSTRAINT check_shift_expenses_amount
+    CHECK (amount > 0);
+
+-- Create trigger for updated_at
+CREATE TRIGGER update_shift_expenses_updated_at BEFORE UPDATE ON shift_expenses
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Restore the planar point index and drop the geography column
+CREATE INDEX IF NOT EXISTS idx_driver_locations_spatial ON driver_locations USING GIST(point(longitude, latitude));
+DROP INDEX IF EXISTS idx_driver_locations_geog;
+ALTER TABLE driver_locations DROP COLUMN IF EXISTS geog;
@@ -0,0 +1,14 @@
+-- Geospatial index for nearby driver search (PostGIS)
+CREATE EXTENSION IF NOT EXISTS postgis;
+
+-- Geography point computed from coordinates: existing inserts stay unchanged
+ALTER TABLE driver_locations ADD COLUMN geog geography(Point, 4326)
+    GENERATED ALWAYS AS (
+        ST_SetSRID(ST_MakePoint(longitude::double precision, latitude::double precision), 4326)::geography
+    ) STORED;
+
+-- ST_DWithin and KNN ordering on geography use this index
+CREATE INDEX idx_driver_locations_geog ON driver_locations USING GIST(geog);
+
+-- The planar point index is superseded by the geography index
+DROP INDEX IF EXISTS idx_driver_locations_spatial;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS inspection_photos;
+
+ALTER TABLE vehicle_inspections DROP COLUMN IF EXISTS submitted_at;
+
+UPDATE vehicle_inspections SET status = 'scheduled' WHERE status = 'submitted';
+ALTER TABLE vehicle_inspections DROP CONSTRAINT check_vehicle_inspections_status;
+ALTER TABLE vehicle_inspections ADD CONSTRAINT check_vehicle_inspections_status
+    CHECK (status IN ('scheduled', 'passed', 'failed', 'cancelled'));
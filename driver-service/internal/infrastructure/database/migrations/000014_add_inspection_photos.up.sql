@@ -0,0 +1,27 @@
+-- Photo inspections: drivers upload photos of the vehicle and submit them for review
+ALTER TABLE vehicle_inspections DROP CONSTRAINT check_vehicle_inspections_status;
+ALTER TABLE vehicle_inspections ADD CONSTRAINT check_vehicle_inspections_status
+    CHECK (status IN ('scheduled', 'submitted', 'passed', 'failed', 'cancelled'));
+
+ALTER TABLE vehicle_inspections ADD COLUMN submitted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create inspection_photos table: one photo per angle, re-upload replaces it
+CREATE TABLE inspection_photos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inspection_id UUID NOT NULL REFERENCES vehicle_inspections(id) ON DELETE CASCADE,
+    angle VARCHAR(20) NOT NULL,
+    url TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    rejection_reason TEXT,
+    uploaded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (inspection_id, angle)
+);
+
+-- Add check constraints
+ALTER TABLE inspection_photos ADD CONSTRAINT check_inspection_photos_angle
+    CHECK (angle IN ('front', 'back', 'left', 'right', 'interior'));
+ALTER TABLE inspection_photos ADD CONSTRAINT check_inspection_photos_status
+    CHECK (status IN ('pending', 'accepted', 'rejected'));
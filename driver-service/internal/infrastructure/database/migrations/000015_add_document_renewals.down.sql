@@ -0,0 +1,26 @@
+-- Drop document renewals: only the latest version of each document type is kept
+DELETE FROM driver_documents d
+WHERE EXISTS (
+    SELECT 1 FROM driver_documents newer
+    WHERE newer.driver_id = d.driver_id
+      AND newer.document_type = d.document_type
+      AND newer.created_at > d.created_at
+);
+
+UPDATE driver_documents SET status = 'verified' WHERE status = 'superseded';
+ALTER TABLE driver_documents DROP CONSTRAINT check_driver_documents_status;
+ALTER TABLE driver_documents ADD CONSTRAINT check_driver_documents_status
+    CHECK (status IN ('pending', 'verified', 'rejected', 'expired', 'processing'));
+
+DROP INDEX IF EXISTS idx_driver_documents_pending_queue;
+CREATE INDEX idx_driver_documents_pending_queue ON driver_documents(created_at)
+    WHERE status = 'pending';
+
+DROP INDEX IF EXISTS idx_driver_documents_unique_renewal;
+DROP INDEX IF EXISTS idx_driver_documents_unique_type;
+
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS expiry_reminder_sent_at;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS verify_by;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS replaces_id;
+
+CREATE UNIQUE INDEX idx_driver_documents_unique_type ON driver_documents(driver_id, document_type);
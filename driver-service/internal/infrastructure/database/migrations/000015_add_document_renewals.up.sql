@@ -0,0 +1,21 @@
+-- Link a renewed document version to the document it replaces
+ALTER TABLE driver_documents ADD COLUMN replaces_id UUID REFERENCES driver_documents(id) ON DELETE SET NULL;
+ALTER TABLE driver_documents ADD COLUMN verify_by DATE;
+ALTER TABLE driver_documents ADD COLUMN expiry_reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+-- Keep several versions of a document type: one original per type
+-- and at most one open or verified renewal per document
+DROP INDEX IF EXISTS idx_driver_documents_unique_type;
+CREATE UNIQUE INDEX idx_driver_documents_unique_type ON driver_documents(driver_id, document_type)
+    WHERE replaces_id IS NULL;
+CREATE UNIQUE INDEX idx_driver_documents_unique_renewal ON driver_documents(replaces_id)
+    WHERE replaces_id IS NOT NULL AND status <> 'rejected';
+
+-- Renewals are verified before the replaced document expires
+DROP INDEX IF EXISTS idx_driver_documents_pending_queue;
+CREATE INDEX idx_driver_documents_pending_queue ON driver_documents(verify_by, created_at)
+    WHERE status = 'pending';
+
+ALTER TABLE driver_documents DROP CONSTRAINT check_driver_documents_status;
+ALTER TABLE driver_documents ADD CONSTRAINT check_driver_documents_status
+    CHECK (status IN ('pending', 'verified', 'rejected', 'expired', 'processing', 'superseded'));
@@ -0,0 +1,2 @@
+-- Drop capacity_reports table
+DROP TABLE IF EXISTS capacity_reports;
@@ -0,0 +1,16 @@
+-- Create capacity_reports table: суточные отчеты о нагрузке по экземплярам сервиса
+CREATE TABLE capacity_reports (
+    report_date DATE NOT NULL,
+    instance VARCHAR(255) NOT NULL,
+    period_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    period_to TIMESTAMP WITH TIME ZONE NOT NULL,
+    total_requests BIGINT NOT NULL DEFAULT 0,
+    endpoints JSONB NOT NULL DEFAULT '[]',
+    pool JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (report_date, instance)
+);
+
+-- Add check constraints
+ALTER TABLE capacity_reports ADD CONSTRAINT check_capacity_reports_period
+    CHECK (period_to >= period_from);
@@ -0,0 +1,12 @@
+-- Drop city_shards table
+DROP TABLE IF EXISTS city_shards;
+
+-- Restore the foreign key without validating rows moved between shards
+ALTER TABLE driver_locations ADD CONSTRAINT driver_locations_driver_id_fkey
+    FOREIGN KEY (driver_id) REFERENCES drivers(id) ON DELETE CASCADE NOT VALID;
+
+DROP INDEX IF EXISTS idx_driver_locations_shard_key_time;
+DROP INDEX IF EXISTS idx_drivers_shard_key;
+
+ALTER TABLE driver_locations DROP COLUMN IF EXISTS shard_key;
+ALTER TABLE drivers DROP COLUMN IF EXISTS shard_key;
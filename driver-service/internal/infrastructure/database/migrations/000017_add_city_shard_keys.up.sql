@@ -0,0 +1,24 @@
+-- The shard key is the driver's normalized city (metadata->>'city'):
+-- driver_locations of a city are stored on the shard assigned to it
+ALTER TABLE drivers ADD COLUMN shard_key VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE driver_locations ADD COLUMN shard_key VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Backfill from metadata; the service keeps the key in sync on every write
+UPDATE drivers SET shard_key = lower(btrim(metadata->>'city')) WHERE metadata->>'city' IS NOT NULL;
+UPDATE driver_locations l SET shard_key = d.shard_key
+    FROM drivers d WHERE d.id = l.driver_id AND d.shard_key <> '';
+
+CREATE INDEX idx_drivers_shard_key ON drivers(shard_key) WHERE deleted_at IS NULL;
+CREATE INDEX idx_driver_locations_shard_key_time ON driver_locations(shard_key, recorded_at, id);
+
+-- Locations may live on a shard without the driver row: drivers stay on the primary database
+ALTER TABLE driver_locations DROP CONSTRAINT IF EXISTS driver_locations_driver_id_fkey;
+
+-- Create city_shards table: city to shard assignments that override configuration.
+-- Only the primary database's copy is used.
+CREATE TABLE city_shards (
+    city VARCHAR(64) PRIMARY KEY,
+    shard VARCHAR(64) NOT NULL,
+    locked BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
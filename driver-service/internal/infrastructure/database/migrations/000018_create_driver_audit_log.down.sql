@@ -0,0 +1,2 @@
+-- Drop driver_audit_log table
+DROP TABLE IF EXISTS driver_audit_log;
@@ -0,0 +1,16 @@
+-- Create driver_audit_log table: журнал решений по изменениям профилей водителей
+CREATE TABLE driver_audit_log (
+    id UUID PRIMARY KEY,
+    -- Пустой ID у отклоненной регистрации: водитель не был создан
+    driver_id UUID,
+    fleet_id UUID,
+    event VARCHAR(64) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    outcome VARCHAR(32) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_driver_audit_log_driver_created ON driver_audit_log(driver_id, created_at DESC);
+CREATE INDEX idx_driver_audit_log_fleet_created ON driver_audit_log(fleet_id, created_at DESC);
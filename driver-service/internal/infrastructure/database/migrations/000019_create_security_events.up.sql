@@ -0,0 +1,37 @@
+-- Create driver_sessions table: сессии водителей по утверждениям токенов
+CREATE TABLE driver_sessions (
+    id VARCHAR(255) PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    device_id VARCHAR(255) NOT NULL DEFAULT '',
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_driver_sessions_driver_seen ON driver_sessions(driver_id, last_seen_at DESC);
+
+-- Create security_events table: подозрительная активность в аккаунтах водителей
+CREATE TABLE security_events (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    details JSONB NOT NULL DEFAULT '{}',
+    sessions_revoked BOOLEAN NOT NULL DEFAULT FALSE,
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add check constraints
+ALTER TABLE security_events ADD CONSTRAINT check_security_events_type
+    CHECK (event_type IN ('new_device', 'new_country', 'location_jump', 'simultaneous_sessions'));
+ALTER TABLE security_events ADD CONSTRAINT check_security_events_status
+    CHECK (status IN ('open', 'confirmed', 'dismissed'));
+
+-- Create indexes
+CREATE INDEX idx_security_events_status_created ON security_events(status, created_at DESC);
+CREATE INDEX idx_security_events_driver_type ON security_events(driver_id, event_type, created_at DESC);
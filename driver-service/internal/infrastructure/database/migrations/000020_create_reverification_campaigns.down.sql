@@ -0,0 +1,10 @@
+-- Drop reverification campaigns
+DROP TABLE IF EXISTS reverification_targets;
+DROP TABLE IF EXISTS reverification_campaigns;
+
+UPDATE driver_documents SET status = 'verified' WHERE status = 'reverification_required';
+ALTER TABLE driver_documents DROP CONSTRAINT check_driver_documents_status;
+ALTER TABLE driver_documents ADD CONSTRAINT check_driver_documents_status
+    CHECK (status IN ('pending', 'verified', 'rejected', 'expired', 'processing', 'superseded'));
+
+ALTER TABLE driver_documents ALTER COLUMN verify_by TYPE DATE;
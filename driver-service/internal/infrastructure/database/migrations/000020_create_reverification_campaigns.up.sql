@@ -0,0 +1,48 @@
+-- Documents can be sent for reverification with a precise campaign deadline
+ALTER TABLE driver_documents ALTER COLUMN verify_by TYPE TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE driver_documents DROP CONSTRAINT check_driver_documents_status;
+ALTER TABLE driver_documents ADD CONSTRAINT check_driver_documents_status
+    CHECK (status IN ('pending', 'verified', 'rejected', 'expired', 'processing', 'superseded', 'reverification_required'));
+
+-- Create reverification_campaigns table: кампании перепроверки документов водителей
+CREATE TABLE reverification_campaigns (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    segment JSONB NOT NULL DEFAULT '{}',
+    document_types JSONB NOT NULL DEFAULT '[]',
+    deadline TIMESTAMP WITH TIME ZONE NOT NULL,
+    reminder_interval_days INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    closed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create reverification_targets table: документы, которые нужно перепроверить в кампании
+CREATE TABLE reverification_targets (
+    campaign_id UUID NOT NULL REFERENCES reverification_campaigns(id) ON DELETE CASCADE,
+    document_id UUID NOT NULL REFERENCES driver_documents(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    document_type VARCHAR(50) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    reminders_sent INTEGER NOT NULL DEFAULT 0,
+    last_reminded_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (campaign_id, document_id)
+);
+
+-- Add check constraints
+ALTER TABLE reverification_campaigns ADD CONSTRAINT check_reverification_campaigns_status
+    CHECK (status IN ('active', 'completed', 'cancelled'));
+ALTER TABLE reverification_targets ADD CONSTRAINT check_reverification_targets_status
+    CHECK (status IN ('pending', 'completed', 'overdue', 'cancelled'));
+
+-- Create indexes
+CREATE INDEX idx_reverification_campaigns_status_deadline ON reverification_campaigns(status, deadline);
+CREATE INDEX idx_reverification_targets_driver_status ON reverification_targets(driver_id, status);
+CREATE INDEX idx_reverification_targets_campaign_status ON reverification_targets(campaign_id, status);
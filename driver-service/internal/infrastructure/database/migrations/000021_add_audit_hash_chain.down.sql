@@ -0,0 +1,14 @@
+-- Drop anchors
+DROP INDEX IF EXISTS idx_driver_audit_anchors_sequence;
+DROP TABLE IF EXISTS driver_audit_anchors;
+
+-- Drop triggers
+DROP TRIGGER IF EXISTS reject_driver_audit_log_change ON driver_audit_log;
+DROP FUNCTION IF EXISTS reject_chained_audit_change();
+
+-- Drop hash chain columns
+DROP INDEX IF EXISTS idx_driver_audit_log_sequence;
+ALTER TABLE driver_audit_log
+    DROP COLUMN IF EXISTS hash,
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS sequence;
@@ -0,0 +1,36 @@
+-- Hash chain for driver_audit_log: каждая запись цепочки хранит хеш предыдущей
+ALTER TABLE driver_audit_log
+    ADD COLUMN sequence BIGINT,
+    ADD COLUMN prev_hash VARCHAR(64),
+    ADD COLUMN hash VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_driver_audit_log_sequence ON driver_audit_log(sequence) WHERE sequence IS NOT NULL;
+
+-- Записи цепочки неизменяемы; записи, сделанные без цепочки, по-прежнему можно удалять
+CREATE OR REPLACE FUNCTION reject_chained_audit_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.hash IS NOT NULL THEN
+        RAISE EXCEPTION 'audit entry % is hash-chained and cannot be changed', OLD.id;
+    END IF;
+    IF TG_OP = 'DELETE' THEN
+        RETURN OLD;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER reject_driver_audit_log_change BEFORE UPDATE OR DELETE ON driver_audit_log
+    FOR EACH ROW EXECUTE FUNCTION reject_chained_audit_change();
+
+-- Create driver_audit_anchors table: хеши цепочки, закрепленные во внешнем журнале
+CREATE TABLE driver_audit_anchors (
+    id UUID PRIMARY KEY,
+    sequence BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    sink VARCHAR(32) NOT NULL,
+    reference TEXT NOT NULL DEFAULT '',
+    anchored_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_driver_audit_anchors_sequence ON driver_audit_anchors(sequence DESC);
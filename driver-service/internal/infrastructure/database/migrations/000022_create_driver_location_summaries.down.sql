@@ -0,0 +1,2 @@
+-- Drop driver_location_summaries table
+DROP TABLE IF EXISTS driver_location_summaries;
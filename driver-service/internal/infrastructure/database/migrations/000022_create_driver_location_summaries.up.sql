@@ -0,0 +1,22 @@
+-- Create driver_location_summaries table: прореженная история местоположений,
+-- одна точка водителя на интервал уровня хранения
+CREATE TABLE driver_location_summaries (
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    tier VARCHAR(32) NOT NULL,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    bucket_seconds INTEGER NOT NULL,
+    latitude DECIMAL(10, 7) NOT NULL,
+    longitude DECIMAL(10, 7) NOT NULL,
+    distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    average_speed DOUBLE PRECISION NOT NULL DEFAULT 0,
+    max_speed DOUBLE PRECISION NOT NULL DEFAULT 0,
+    point_count INTEGER NOT NULL,
+    on_trip BOOLEAN NOT NULL DEFAULT FALSE,
+    order_id UUID,
+    first_recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (driver_id, tier, bucket_start)
+);
+
+-- Create indexes
+CREATE INDEX idx_driver_location_summaries_tier_bucket ON driver_location_summaries(tier, bucket_start);
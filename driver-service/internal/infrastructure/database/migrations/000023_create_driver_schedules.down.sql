@@ -0,0 +1,2 @@
+-- Drop driver_schedules table
+DROP TABLE IF EXISTS driver_schedules;
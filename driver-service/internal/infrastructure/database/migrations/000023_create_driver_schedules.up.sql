@@ -0,0 +1,8 @@
+-- Create driver_schedules table: еженедельные окна доступности водителей
+CREATE TABLE driver_schedules (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    timezone VARCHAR(64) NOT NULL,
+    windows JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
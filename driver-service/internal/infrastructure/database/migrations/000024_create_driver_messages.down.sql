@@ -0,0 +1,2 @@
+-- Drop driver_messages table
+DROP TABLE IF EXISTS driver_messages;
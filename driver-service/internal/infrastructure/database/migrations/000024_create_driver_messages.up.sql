@@ -0,0 +1,18 @@
+-- Create driver_messages table: переписка водителя с диспетчером по заказу или общая
+CREATE TABLE driver_messages (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    order_id UUID,
+    sender VARCHAR(20) NOT NULL,
+    sender_id VARCHAR(255) NOT NULL DEFAULT '',
+    kind VARCHAR(20) NOT NULL,
+    delay_minutes INTEGER,
+    text TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    read_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Create indexes
+CREATE INDEX idx_driver_messages_thread ON driver_messages(driver_id, order_id, created_at DESC);
+CREATE INDEX idx_driver_messages_created_at ON driver_messages(created_at);
@@ -0,0 +1,3 @@
+-- Drop geofence tables
+DROP TABLE IF EXISTS geofence_presence;
+DROP TABLE IF EXISTS geofences;
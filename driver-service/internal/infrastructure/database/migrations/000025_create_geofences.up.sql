@@ -0,0 +1,27 @@
+-- Create geofences table: круглые и многоугольные зоны для событий входа и выхода
+CREATE TABLE geofences (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    shape VARCHAR(20) NOT NULL,
+    center_latitude DECIMAL(10, 8),
+    center_longitude DECIMAL(11, 8),
+    radius_meters DOUBLE PRECISION,
+    polygon JSONB,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create geofence_presence table: водители, находящиеся внутри геозон
+CREATE TABLE geofence_presence (
+    geofence_id UUID NOT NULL REFERENCES geofences(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    entered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (geofence_id, driver_id)
+);
+
+-- Create indexes
+CREATE INDEX idx_geofences_active ON geofences(active);
+CREATE INDEX idx_geofence_presence_driver_id ON geofence_presence(driver_id);
+CREATE INDEX idx_geofence_presence_entered_at ON geofence_presence(geofence_id, entered_at);
@@ -0,0 +1,2 @@
+-- Drop driver_earnings table
+DROP TABLE IF EXISTS driver_earnings;
@@ -0,0 +1,31 @@
+-- Create driver_earnings table: начисления водителям за поездки и бонусы
+CREATE TABLE driver_earnings (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    shift_id UUID REFERENCES driver_shifts(id) ON DELETE SET NULL,
+    order_id UUID,
+    type VARCHAR(20) NOT NULL,
+    fare DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    commission DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    bonus DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(10, 2) NOT NULL,
+    currency CHAR(3) NOT NULL DEFAULT 'RUB',
+    description TEXT,
+    earned_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes for driver_earnings table
+CREATE INDEX idx_driver_earnings_driver_earned ON driver_earnings(driver_id, earned_at DESC);
+CREATE INDEX idx_driver_earnings_shift_id ON driver_earnings(shift_id) WHERE shift_id IS NOT NULL;
+
+-- Поездка по заказу начисляется один раз
+CREATE UNIQUE INDEX idx_driver_earnings_unique_trip ON driver_earnings(order_id)
+    WHERE type = 'trip';
+
+-- Add check constraints
+ALTER TABLE driver_earnings ADD CONSTRAINT check_driver_earnings_type
+    CHECK (type IN ('trip', 'bonus'));
+
+ALTER TABLE driver_earnings ADD CONSTRAINT check_driver_earnings_amounts
+    CHECK (fare >= 0 AND commission >= 0 AND commission <= fare AND bonus >= 0);
@@ -0,0 +1,9 @@
+-- Return the driver's fleet to metadata
+UPDATE drivers SET metadata = metadata || jsonb_build_object('fleet_id', fleet_id::text)
+    WHERE fleet_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_drivers_fleet_id;
+ALTER TABLE drivers DROP COLUMN IF EXISTS fleet_id;
+
+-- Drop fleets table
+DROP TABLE IF EXISTS fleets;
@@ -0,0 +1,24 @@
+-- Create fleets table: автопарки (партнеры), к которым прикреплены водители
+CREATE TABLE fleets (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The driver's fleet moves from metadata->>'fleet_id' to a column: partner dashboards
+-- filter drivers by it
+ALTER TABLE drivers ADD COLUMN fleet_id UUID REFERENCES fleets(id);
+
+-- Backfill: every fleet referenced by a driver gets a row named after its ID
+INSERT INTO fleets (id, name)
+SELECT DISTINCT (metadata->>'fleet_id')::uuid, metadata->>'fleet_id'
+    FROM drivers
+    WHERE metadata->>'fleet_id' ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
+
+UPDATE drivers SET fleet_id = (metadata->>'fleet_id')::uuid
+    WHERE metadata->>'fleet_id' ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
+UPDATE drivers SET metadata = metadata - 'fleet_id' WHERE metadata ? 'fleet_id';
+
+CREATE INDEX idx_drivers_fleet_id ON drivers(fleet_id) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop dispatch_offers table
+DROP TABLE IF EXISTS dispatch_offers;
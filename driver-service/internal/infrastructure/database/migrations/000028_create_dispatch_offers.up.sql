@@ -0,0 +1,13 @@
+-- Create dispatch_offers table: ответы водителей на предложения заказов для оценки кандидатов
+CREATE TABLE dispatch_offers (
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL,
+    accepted BOOLEAN NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    -- Повторная доставка ответа на то же предложение не учитывается дважды
+    PRIMARY KEY (driver_id, order_id)
+);
+
+-- Статистика считается по окну последних ответов водителя
+CREATE INDEX idx_dispatch_offers_driver_responded ON dispatch_offers(driver_id, responded_at DESC);
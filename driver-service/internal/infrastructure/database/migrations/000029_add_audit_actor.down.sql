@@ -0,0 +1,13 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_driver_audit_log_request_id;
+DROP INDEX IF EXISTS idx_driver_audit_log_event_created;
+DROP INDEX IF EXISTS idx_driver_audit_log_actor_created;
+DROP INDEX IF EXISTS idx_driver_audit_log_created;
+
+-- Drop actor and snapshot columns
+ALTER TABLE driver_audit_log
+    DROP COLUMN IF EXISTS after_snapshot,
+    DROP COLUMN IF EXISTS before_snapshot,
+    DROP COLUMN IF EXISTS request_id,
+    DROP COLUMN IF EXISTS actor_roles,
+    DROP COLUMN IF EXISTS actor;
@@ -0,0 +1,14 @@
+-- Actor and snapshots for driver_audit_log: кто и в каком запросе изменил водителя,
+-- значения измененных полей до и после
+ALTER TABLE driver_audit_log
+    ADD COLUMN actor VARCHAR(255),
+    ADD COLUMN actor_roles VARCHAR(255),
+    ADD COLUMN request_id VARCHAR(128),
+    ADD COLUMN before_snapshot JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN after_snapshot JSONB NOT NULL DEFAULT '{}';
+
+-- Create indexes for compliance review filters
+CREATE INDEX idx_driver_audit_log_created ON driver_audit_log(created_at DESC);
+CREATE INDEX idx_driver_audit_log_actor_created ON driver_audit_log(actor, created_at DESC) WHERE actor IS NOT NULL;
+CREATE INDEX idx_driver_audit_log_event_created ON driver_audit_log(event, created_at DESC);
+CREATE INDEX idx_driver_audit_log_request_id ON driver_audit_log(request_id) WHERE request_id IS NOT NULL;
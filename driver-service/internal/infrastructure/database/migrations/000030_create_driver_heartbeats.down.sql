@@ -0,0 +1,2 @@
+-- Drop driver_heartbeats table
+DROP TABLE IF EXISTS driver_heartbeats;
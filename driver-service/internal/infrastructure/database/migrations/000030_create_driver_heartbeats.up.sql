@@ -0,0 +1,7 @@
+-- Create driver_heartbeats table: последний сигнал приложения водителя для определения
+-- водителей, переставших выходить на связь
+CREATE TABLE driver_heartbeats (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    source VARCHAR(20) NOT NULL
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_driver_ratings_customer_created;
+DROP INDEX IF EXISTS idx_driver_ratings_unique_customer_order;
+
+CREATE UNIQUE INDEX idx_driver_ratings_unique_order ON driver_ratings(driver_id, order_id, customer_id)
+    WHERE order_id IS NOT NULL AND customer_id IS NOT NULL;
@@ -0,0 +1,16 @@
+-- Клиент оценивает заказ один раз, даже если оценку пытаются привязать к другому водителю.
+-- Из повторных оценок заказа остается самая ранняя
+DELETE FROM driver_ratings r
+USING driver_ratings earlier
+WHERE r.order_id = earlier.order_id
+  AND r.customer_id = earlier.customer_id
+  AND (r.created_at, r.id) > (earlier.created_at, earlier.id);
+
+DROP INDEX IF EXISTS idx_driver_ratings_unique_order;
+
+CREATE UNIQUE INDEX idx_driver_ratings_unique_customer_order ON driver_ratings(order_id, customer_id)
+    WHERE order_id IS NOT NULL AND customer_id IS NOT NULL;
+
+-- Дневной лимит и поиск серий оценок клиента
+CREATE INDEX idx_driver_ratings_customer_created ON driver_ratings(customer_id, created_at DESC)
+    WHERE customer_id IS NOT NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_driver_locations_supply;
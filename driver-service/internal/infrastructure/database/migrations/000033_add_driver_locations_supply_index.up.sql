@@ -0,0 +1,4 @@
+-- Covering index for the supply heatmap: the latest point of every driver within the
+-- freshness window is found by an index-only scan without reading the table
+CREATE INDEX idx_driver_locations_supply ON driver_locations(recorded_at DESC, driver_id)
+    INCLUDE (latitude, longitude);
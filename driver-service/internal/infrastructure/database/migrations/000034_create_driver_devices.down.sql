@@ -0,0 +1,2 @@
+-- Drop driver_devices table
+DROP TABLE IF EXISTS driver_devices;
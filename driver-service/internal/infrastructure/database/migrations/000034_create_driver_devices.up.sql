@@ -0,0 +1,20 @@
+-- Create driver_devices table: устройства, с которых водитель работает в приложении.
+-- Повторная регистрация устройства обновляет запись и снимает отзыв
+CREATE TABLE driver_devices (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    device_id VARCHAR(128) NOT NULL,
+    platform VARCHAR(16) NOT NULL,
+    app_version VARCHAR(32) NOT NULL,
+    push_token TEXT,
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_seen_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Add check constraints
+ALTER TABLE driver_devices ADD CONSTRAINT check_driver_devices_platform
+    CHECK (platform IN ('android', 'ios', 'web'));
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_driver_devices_driver_device ON driver_devices(driver_id, device_id);
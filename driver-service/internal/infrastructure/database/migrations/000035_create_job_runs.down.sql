@@ -0,0 +1,2 @@
+-- Drop job_runs table
+DROP TABLE IF EXISTS job_runs;
@@ -0,0 +1,23 @@
+-- Create job_runs table: история запусков фоновых задач всех экземпляров сервиса
+CREATE TABLE job_runs (
+    id UUID PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    trigger VARCHAR(16) NOT NULL,
+    triggered_by VARCHAR(255) NOT NULL DEFAULT '',
+    instance VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+-- Add check constraints
+ALTER TABLE job_runs ADD CONSTRAINT check_job_runs_trigger
+    CHECK (trigger IN ('schedule', 'manual'));
+ALTER TABLE job_runs ADD CONSTRAINT check_job_runs_status
+    CHECK (status IN ('succeeded', 'failed'));
+
+-- Create indexes
+CREATE INDEX idx_job_runs_job_started ON job_runs(job_name, started_at DESC);
+CREATE INDEX idx_job_runs_started ON job_runs(started_at);
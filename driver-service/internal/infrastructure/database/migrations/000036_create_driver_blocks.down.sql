@@ -0,0 +1,2 @@
+-- Drop driver_blocks table
+DROP TABLE IF EXISTS driver_blocks;
@@ -0,0 +1,27 @@
+-- Create driver_blocks table: блокировки водителей с кодом причины и сроком автоматического снятия.
+-- Активная блокировка (unblocked_at IS NULL) у водителя может быть только одна
+CREATE TABLE driver_blocks (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    reason VARCHAR(32) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    previous_status VARCHAR(50) NOT NULL,
+    blocked_by VARCHAR(255) NOT NULL,
+    blocked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    unblock_at TIMESTAMP WITH TIME ZONE,
+    unblocked_at TIMESTAMP WITH TIME ZONE,
+    unblocked_by VARCHAR(255),
+    unblock_reason VARCHAR(16),
+    unblock_comment TEXT
+);
+
+-- Add check constraints
+ALTER TABLE driver_blocks ADD CONSTRAINT check_driver_blocks_reason
+    CHECK (reason IN ('fraud', 'document_fraud', 'safety'));
+ALTER TABLE driver_blocks ADD CONSTRAINT check_driver_blocks_unblock_reason
+    CHECK (unblock_reason IS NULL OR unblock_reason IN ('manual', 'expired'));
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_driver_blocks_active ON driver_blocks(driver_id) WHERE unblocked_at IS NULL;
+CREATE INDEX idx_driver_blocks_driver_blocked ON driver_blocks(driver_id, blocked_at DESC);
+CREATE INDEX idx_driver_blocks_unblock_at ON driver_blocks(unblock_at) WHERE unblocked_at IS NULL AND unblock_at IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_driver_devices_active_device;
+
+DELETE FROM driver_devices WHERE platform = 'tracker';
+ALTER TABLE driver_devices DROP CONSTRAINT check_driver_devices_platform;
+ALTER TABLE driver_devices ADD CONSTRAINT check_driver_devices_platform
+    CHECK (platform IN ('android', 'ios', 'web'));
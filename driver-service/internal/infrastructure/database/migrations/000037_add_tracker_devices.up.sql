@@ -0,0 +1,9 @@
+-- GPS-трекеры автомобилей регистрируются как устройства водителя с платформой tracker.
+-- Точки от трекера по MQTT привязываются к водителю по ID трекера
+ALTER TABLE driver_devices DROP CONSTRAINT check_driver_devices_platform;
+ALTER TABLE driver_devices ADD CONSTRAINT check_driver_devices_platform
+    CHECK (platform IN ('android', 'ios', 'web', 'tracker'));
+
+-- Create indexes
+CREATE INDEX idx_driver_devices_active_device ON driver_devices(device_id, last_seen_at DESC)
+    WHERE revoked_at IS NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE driver_shifts DROP CONSTRAINT IF EXISTS check_driver_shifts_idle_minutes;
+ALTER TABLE driver_shifts DROP CONSTRAINT IF EXISTS check_driver_shifts_average_speed;
+ALTER TABLE driver_shifts DROP COLUMN IF EXISTS idle_minutes;
+ALTER TABLE driver_shifts DROP COLUMN IF EXISTS average_speed;
@@ -0,0 +1,10 @@
+-- Средняя скорость и время простоя смены по истории местоположений; пробег смены
+-- (total_distance) при завершении тоже берется из истории
+ALTER TABLE driver_shifts ADD COLUMN average_speed DECIMAL(6, 2);
+ALTER TABLE driver_shifts ADD COLUMN idle_minutes INTEGER;
+
+-- Add check constraints
+ALTER TABLE driver_shifts ADD CONSTRAINT check_driver_shifts_average_speed
+    CHECK (average_speed IS NULL OR average_speed >= 0);
+ALTER TABLE driver_shifts ADD CONSTRAINT check_driver_shifts_idle_minutes
+    CHECK (idle_minutes IS NULL OR idle_minutes >= 0);
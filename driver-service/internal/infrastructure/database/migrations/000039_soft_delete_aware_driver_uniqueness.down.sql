@@ -0,0 +1,10 @@
+-- Restore uniqueness across deleted drivers. Fails while a re-registered driver
+-- shares a phone, email or license number with a deleted record
+DROP INDEX IF EXISTS idx_drivers_deleted_phone;
+DROP INDEX IF EXISTS idx_drivers_license_unique;
+DROP INDEX IF EXISTS idx_drivers_email_unique;
+DROP INDEX IF EXISTS idx_drivers_phone_unique;
+
+CREATE UNIQUE INDEX idx_drivers_email_unique ON drivers(email) WHERE email <> '';
+CREATE UNIQUE INDEX idx_drivers_license_unique ON drivers(license_number) WHERE license_number <> '';
+ALTER TABLE drivers ADD CONSTRAINT drivers_phone_key UNIQUE (phone);
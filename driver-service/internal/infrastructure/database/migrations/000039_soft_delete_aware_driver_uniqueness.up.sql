@@ -0,0 +1,13 @@
+-- Soft-deleted drivers no longer hold their phone, email and license number:
+-- uniqueness is enforced only among drivers that are not deleted, so a returning
+-- driver can register again as a fresh record
+ALTER TABLE drivers DROP CONSTRAINT IF EXISTS drivers_phone_key;
+DROP INDEX IF EXISTS idx_drivers_email_unique;
+DROP INDEX IF EXISTS idx_drivers_license_unique;
+
+CREATE UNIQUE INDEX idx_drivers_phone_unique ON drivers(phone) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_drivers_email_unique ON drivers(email) WHERE email <> '' AND deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_drivers_license_unique ON drivers(license_number) WHERE license_number <> '' AND deleted_at IS NULL;
+
+-- Lookup of the latest deleted record for a returning phone number
+CREATE INDEX idx_drivers_deleted_phone ON drivers(phone, deleted_at DESC) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS ocr_result;
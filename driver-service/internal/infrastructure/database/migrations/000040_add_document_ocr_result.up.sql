@@ -0,0 +1,3 @@
+-- Fields recognized in the uploaded document file and their mismatches with the
+-- submitted data; filled asynchronously after upload and shown to verifiers
+ALTER TABLE driver_documents ADD COLUMN ocr_result JSONB;
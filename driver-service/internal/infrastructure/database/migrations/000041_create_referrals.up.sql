@@ -0,0 +1,33 @@
+-- Create referral_codes table: реферальный код водителя, один на водителя
+CREATE TABLE referral_codes (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Create referrals table: приглашенные водители и их прогресс до выплаты пригласившему.
+-- Водитель может быть приглашен только один раз
+CREATE TABLE referrals (
+    id UUID PRIMARY KEY,
+    referrer_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    referred_driver_id UUID NOT NULL UNIQUE REFERENCES drivers(id) ON DELETE CASCADE,
+    code VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    trips_completed INTEGER NOT NULL DEFAULT 0,
+    required_trips INTEGER NOT NULL,
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Add check constraints
+ALTER TABLE referrals ADD CONSTRAINT check_referrals_status
+    CHECK (status IN ('registered', 'verified', 'completed'));
+ALTER TABLE referrals ADD CONSTRAINT check_referrals_not_self
+    CHECK (referrer_id <> referred_driver_id);
+
+-- Create indexes
+CREATE INDEX idx_referrals_referrer ON referrals(referrer_id, registered_at DESC);
+CREATE INDEX idx_referrals_open ON referrals(id) WHERE status <> 'completed';
+CREATE INDEX idx_referrals_completed_at ON referrals(completed_at) WHERE status = 'completed';
@@ -0,0 +1,2 @@
+-- Drop driver_status_history table
+DROP TABLE IF EXISTS driver_status_history;
@@ -0,0 +1,20 @@
+-- Create driver_status_history table: каждая смена статуса водителя с автором
+CREATE TABLE driver_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    actor VARCHAR(255),
+    request_id VARCHAR(128),
+    snapshot BOOLEAN NOT NULL DEFAULT FALSE,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+-- Create indexes
+CREATE INDEX idx_driver_status_history_driver ON driver_status_history(driver_id, changed_at);
+
+-- Backfill: снимок текущего статуса водителей, история которых до миграции не велась
+INSERT INTO driver_status_history (driver_id, to_status, snapshot, changed_at)
+SELECT id, status, TRUE, NOW()
+FROM drivers
+WHERE deleted_at IS NULL;
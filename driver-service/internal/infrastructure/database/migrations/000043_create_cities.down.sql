@@ -0,0 +1,5 @@
+-- Drop city zoning
+DROP INDEX IF EXISTS idx_drivers_operating_city;
+ALTER TABLE drivers DROP COLUMN IF EXISTS resolved_city;
+DROP TABLE IF EXISTS cities;
+DROP TABLE IF EXISTS regions;
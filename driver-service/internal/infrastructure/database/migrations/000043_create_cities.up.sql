@@ -0,0 +1,29 @@
+-- Create regions table: регионы, объединяющие города
+CREATE TABLE regions (
+    id UUID PRIMARY KEY,
+    code VARCHAR(16) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create cities table: города с границами, по которым определяется город водителя и точек.
+-- key совпадает с ключом шарда водителя (нормализованный город из метаданных)
+CREATE TABLE cities (
+    id UUID PRIMARY KEY,
+    region_id UUID NOT NULL REFERENCES regions(id),
+    key VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    boundary JSONB NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Город водителя, определенный по его последней точке
+ALTER TABLE drivers ADD COLUMN resolved_city VARCHAR(64);
+
+-- Create indexes
+CREATE INDEX idx_cities_region_id ON cities(region_id);
+CREATE INDEX idx_cities_active ON cities(active, created_at);
+CREATE INDEX idx_drivers_operating_city ON drivers(COALESCE(resolved_city, shard_key)) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Drop feature flags
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,12 @@
+-- Create feature_flags table: флаги функций приложения водителя с правилами включения
+-- и переопределениями рабочих параметров приложения
+CREATE TABLE feature_flags (
+    id UUID PRIMARY KEY,
+    key VARCHAR(64) NOT NULL UNIQUE,
+    description VARCHAR(500) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rules JSONB NOT NULL DEFAULT '[]',
+    parameters JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- Drop location exports
+DROP TABLE IF EXISTS location_exports;
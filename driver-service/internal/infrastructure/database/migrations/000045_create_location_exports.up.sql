@@ -0,0 +1,25 @@
+-- Create location_exports table: асинхронные выгрузки истории местоположений водителя в файл
+CREATE TABLE location_exports (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    format VARCHAR(16) NOT NULL,
+    from_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    precise BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(16) NOT NULL,
+    requested_by VARCHAR(255),
+    row_count BIGINT NOT NULL DEFAULT 0,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    file_url TEXT,
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT location_exports_range CHECK (to_time > from_time)
+);
+
+CREATE INDEX idx_location_exports_driver_id ON location_exports(driver_id, created_at DESC);
+-- Очистка ищет просроченные файлы и зависшие выгрузки
+CREATE INDEX idx_location_exports_expires_at ON location_exports(expires_at) WHERE expires_at IS NOT NULL;
+CREATE INDEX idx_location_exports_unfinished ON location_exports(created_at) WHERE status IN ('pending', 'running');
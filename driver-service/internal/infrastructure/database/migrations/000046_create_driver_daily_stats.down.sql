@@ -0,0 +1,3 @@
+-- Drop driver daily stats
+DROP TABLE IF EXISTS driver_daily_stats_coverage;
+DROP TABLE IF EXISTS driver_daily_stats;
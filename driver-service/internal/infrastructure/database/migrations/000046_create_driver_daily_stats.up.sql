@@ -0,0 +1,25 @@
+-- Create driver_daily_stats table: суточные показатели водителей (UTC), которые пересчитывает
+-- задача driver_daily_stats, чтобы статистика не читала всю историю driver_locations.
+-- Таблица, а не материализованное представление: при шардировании точки лежат в базах шардов,
+-- а порог разрыва трека задается конфигурацией
+CREATE TABLE driver_daily_stats (
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    trips INTEGER NOT NULL DEFAULT 0,
+    distance_km DOUBLE PRECISION NOT NULL DEFAULT 0,
+    online_seconds BIGINT NOT NULL DEFAULT 0,
+    rating_sum INTEGER NOT NULL DEFAULT 0,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (driver_id, day)
+);
+
+CREATE INDEX idx_driver_daily_stats_day ON driver_daily_stats(day);
+
+-- Сутки, за которые показатели сохранены для всех водителей: [covered_from, refreshed_through)
+CREATE TABLE driver_daily_stats_coverage (
+    id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+    covered_from DATE NOT NULL,
+    refreshed_through DATE NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
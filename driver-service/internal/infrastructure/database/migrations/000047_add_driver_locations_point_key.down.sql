@@ -0,0 +1,3 @@
+-- Drop the driver location point key; removed duplicates are not restored
+CREATE INDEX IF NOT EXISTS idx_driver_locations_driver_time ON driver_locations(driver_id, recorded_at DESC);
+DROP INDEX IF EXISTS idx_driver_locations_driver_point;
@@ -0,0 +1,12 @@
+-- A driver has at most one point per recorded_at: resent batches and mobile retries are
+-- skipped with ON CONFLICT (driver_id, recorded_at) DO NOTHING instead of failing the batch.
+-- Existing duplicates are removed first, keeping the earliest stored copy.
+DELETE FROM driver_locations l
+    USING driver_locations d
+    WHERE l.driver_id = d.driver_id
+      AND l.recorded_at = d.recorded_at
+      AND (l.created_at, l.id) > (d.created_at, d.id);
+
+-- The unique index replaces the plain (driver_id, recorded_at DESC) index for history reads
+CREATE UNIQUE INDEX idx_driver_locations_driver_point ON driver_locations(driver_id, recorded_at DESC);
+DROP INDEX IF EXISTS idx_driver_locations_driver_time;
@@ -0,0 +1,3 @@
+-- Drop driver tags
+DROP INDEX IF EXISTS idx_drivers_tags;
+ALTER TABLE drivers DROP COLUMN IF EXISTS tags;
@@ -0,0 +1,5 @@
+-- Driver tags (vip-capable, pet-friendly, ...) for targeted dispatch and promotions.
+-- Tags are stored normalized; list and nearby filters match all requested tags with @>.
+ALTER TABLE drivers ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX IF NOT EXISTS idx_drivers_tags ON drivers USING GIN (tags jsonb_path_ops);
@@ -0,0 +1,30 @@
+-- Drop driver_locations partitioning: all partitions are merged back into a plain table
+CREATE TABLE driver_locations_plain (
+    LIKE driver_locations INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING GENERATED
+);
+
+INSERT INTO driver_locations_plain (
+    id, driver_id, latitude, longitude, altitude, accuracy, speed, bearing,
+    address, metadata, recorded_at, created_at, shard_key
+)
+SELECT id, driver_id, latitude, longitude, altitude, accuracy, speed, bearing,
+    address, metadata, recorded_at, created_at, shard_key
+FROM driver_locations;
+
+DROP TABLE driver_locations;
+ALTER TABLE driver_locations_plain RENAME TO driver_locations;
+ALTER TABLE driver_locations ADD PRIMARY KEY (id);
+
+CREATE UNIQUE INDEX idx_driver_locations_driver_point ON driver_locations(driver_id, recorded_at DESC);
+CREATE INDEX idx_driver_locations_recorded_at ON driver_locations(recorded_at DESC);
+CREATE INDEX idx_driver_locations_created_at ON driver_locations(created_at DESC);
+CREATE INDEX idx_driver_locations_order_id ON driver_locations(order_id, recorded_at)
+    WHERE order_id IS NOT NULL;
+CREATE INDEX idx_driver_locations_on_trip ON driver_locations(driver_id, recorded_at DESC)
+    WHERE on_trip = TRUE;
+CREATE INDEX idx_driver_locations_source ON driver_locations(source)
+    WHERE source IS NOT NULL;
+CREATE INDEX idx_driver_locations_geog ON driver_locations USING GIST(geog);
+CREATE INDEX idx_driver_locations_shard_key_time ON driver_locations(shard_key, recorded_at, id);
+CREATE INDEX idx_driver_locations_supply ON driver_locations(recorded_at DESC, driver_id)
+    INCLUDE (latitude, longitude);
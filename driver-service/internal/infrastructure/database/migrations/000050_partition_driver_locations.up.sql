@@ -0,0 +1,78 @@
+-- Partition driver_locations by month of recorded_at (UTC): expired history is removed by
+-- dropping whole partitions instead of a long DELETE, and queries bounded by recorded_at
+-- scan only the matching months. Partitions driver_locations_pYYYYMM ahead of time are
+-- created by the location_partitions job; points outside them land in driver_locations_default.
+CREATE TABLE driver_locations_partitioned (
+    id UUID NOT NULL DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL,
+    latitude DECIMAL(10, 7) NOT NULL,
+    longitude DECIMAL(10, 7) NOT NULL,
+    altitude DECIMAL(8, 2),
+    accuracy DECIMAL(8, 2),
+    speed DECIMAL(8, 2),
+    bearing DECIMAL(6, 2),
+    address TEXT,
+    metadata JSONB DEFAULT '{}',
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    on_trip BOOLEAN GENERATED ALWAYS AS ((metadata->>'on_trip') = 'true') STORED,
+    order_id TEXT GENERATED ALWAYS AS (metadata->>'order_id') STORED,
+    source VARCHAR(20) GENERATED ALWAYS AS (metadata->>'source') STORED,
+    geog geography(Point, 4326) GENERATED ALWAYS AS (
+        ST_SetSRID(ST_MakePoint(longitude::double precision, latitude::double precision), 4326)::geography
+    ) STORED,
+    shard_key VARCHAR(64) NOT NULL DEFAULT '',
+    CONSTRAINT check_driver_locations_latitude CHECK (latitude >= -90.0 AND latitude <= 90.0),
+    CONSTRAINT check_driver_locations_longitude CHECK (longitude >= -180.0 AND longitude <= 180.0),
+    CONSTRAINT check_driver_locations_accuracy CHECK (accuracy IS NULL OR accuracy >= 0),
+    CONSTRAINT check_driver_locations_speed CHECK (speed IS NULL OR speed >= 0),
+    CONSTRAINT check_driver_locations_bearing CHECK (bearing IS NULL OR (bearing >= 0 AND bearing < 360)),
+    CONSTRAINT check_driver_locations_metadata_object CHECK (metadata IS NULL OR jsonb_typeof(metadata) = 'object')
+) PARTITION BY RANGE (recorded_at);
+
+CREATE TABLE driver_locations_default PARTITION OF driver_locations_partitioned DEFAULT;
+
+-- Monthly partitions from the oldest stored point through three months ahead
+DO $$
+DECLARE
+    month_start DATE := date_trunc('month', COALESCE((SELECT MIN(recorded_at) FROM driver_locations), NOW()) AT TIME ZONE 'UTC');
+    last_month DATE := date_trunc('month', NOW() AT TIME ZONE 'UTC') + INTERVAL '3 months';
+BEGIN
+    WHILE month_start <= last_month LOOP
+        EXECUTE format(
+            'CREATE TABLE %I PARTITION OF driver_locations_partitioned FOR VALUES FROM (%L) TO (%L)',
+            'driver_locations_p' || to_char(month_start, 'YYYYMM'),
+            month_start::text || ' 00:00:00+00',
+            (month_start + INTERVAL '1 month')::date::text || ' 00:00:00+00'
+        );
+        month_start := month_start + INTERVAL '1 month';
+    END LOOP;
+END $$;
+
+INSERT INTO driver_locations_partitioned (
+    id, driver_id, latitude, longitude, altitude, accuracy, speed, bearing,
+    address, metadata, recorded_at, created_at, shard_key
+)
+SELECT id, driver_id, latitude, longitude, altitude, accuracy, speed, bearing,
+    address, metadata, recorded_at, created_at, shard_key
+FROM driver_locations;
+
+DROP TABLE driver_locations;
+ALTER TABLE driver_locations_partitioned RENAME TO driver_locations;
+
+-- A unique key of a partitioned table must include the partition key
+ALTER TABLE driver_locations ADD PRIMARY KEY (id, recorded_at);
+
+CREATE UNIQUE INDEX idx_driver_locations_driver_point ON driver_locations(driver_id, recorded_at DESC);
+CREATE INDEX idx_driver_locations_recorded_at ON driver_locations(recorded_at DESC);
+CREATE INDEX idx_driver_locations_created_at ON driver_locations(created_at DESC);
+CREATE INDEX idx_driver_locations_order_id ON driver_locations(order_id, recorded_at)
+    WHERE order_id IS NOT NULL;
+CREATE INDEX idx_driver_locations_on_trip ON driver_locations(driver_id, recorded_at DESC)
+    WHERE on_trip = TRUE;
+CREATE INDEX idx_driver_locations_source ON driver_locations(source)
+    WHERE source IS NOT NULL;
+CREATE INDEX idx_driver_locations_geog ON driver_locations USING GIST(geog);
+CREATE INDEX idx_driver_locations_shard_key_time ON driver_locations(shard_key, recorded_at, id);
+CREATE INDEX idx_driver_locations_supply ON driver_locations(recorded_at DESC, driver_id)
+    INCLUDE (latitude, longitude);
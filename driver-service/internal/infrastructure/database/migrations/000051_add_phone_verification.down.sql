@@ -0,0 +1,3 @@
+-- Drop phone verification
+DROP TABLE IF EXISTS phone_verifications;
+ALTER TABLE drivers DROP COLUMN IF EXISTS phone_verified_at;
@@ -0,0 +1,13 @@
+-- Phone ownership confirmed by a one-time SMS code; reset when the phone number changes
+ALTER TABLE drivers ADD COLUMN phone_verified_at TIMESTAMP WITH TIME ZONE;
+
+-- Create phone_verifications table: the pending code of each driver. Only the code hash is
+-- stored; a new code replaces the previous one and a confirmed code is deleted
+CREATE TABLE phone_verifications (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    phone VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    sent_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
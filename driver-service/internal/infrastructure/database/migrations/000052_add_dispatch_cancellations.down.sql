@@ -0,0 +1,5 @@
+-- Drop dispatch cancellations and driver dispatch rates
+ALTER TABLE drivers DROP COLUMN IF EXISTS cancellation_rate;
+ALTER TABLE drivers DROP COLUMN IF EXISTS acceptance_rate;
+ALTER TABLE dispatch_offers DROP CONSTRAINT IF EXISTS dispatch_offers_cancelled_accepted;
+ALTER TABLE dispatch_offers DROP COLUMN IF EXISTS cancelled_at;
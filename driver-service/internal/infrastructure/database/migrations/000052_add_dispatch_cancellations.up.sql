@@ -0,0 +1,9 @@
+-- Cancellations of accepted orders by the driver, counted against the offer window
+ALTER TABLE dispatch_offers ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE dispatch_offers ADD CONSTRAINT dispatch_offers_cancelled_accepted
+    CHECK (cancelled_at IS NULL OR accepted);
+
+-- Acceptance and cancellation rates over dispatch.stats_window as of the last driver response;
+-- NULL until the driver has dispatch.min_offers responses in the window
+ALTER TABLE drivers ADD COLUMN acceptance_rate DECIMAL(5,4);
+ALTER TABLE drivers ADD COLUMN cancellation_rate DECIMAL(5,4);
@@ -0,0 +1,4 @@
+-- Drop driver status version
+DROP TRIGGER IF EXISTS increment_drivers_status_version ON drivers;
+DROP FUNCTION IF EXISTS increment_driver_status_version();
+ALTER TABLE drivers DROP COLUMN IF EXISTS status_version;
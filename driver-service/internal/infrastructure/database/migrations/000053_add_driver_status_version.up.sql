@@ -0,0 +1,18 @@
+-- Status version: incremented by every status change, whichever query changes it. Clients pass
+-- the version from a write back to reads to skip replicas that have not caught up yet
+ALTER TABLE drivers ADD COLUMN status_version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION increment_driver_status_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status IS DISTINCT FROM OLD.status THEN
+        NEW.status_version = OLD.status_version + 1;
+    ELSE
+        NEW.status_version = OLD.status_version;
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER increment_drivers_status_version BEFORE UPDATE ON drivers
+    FOR EACH ROW EXECUTE FUNCTION increment_driver_status_version();
@@ -0,0 +1,3 @@
+-- Drop driver_notes table
+DROP INDEX IF EXISTS idx_driver_notes_driver;
+DROP TABLE IF EXISTS driver_notes;
@@ -0,0 +1,12 @@
+-- Create driver_notes table: внутренние заметки поддержки и диспетчеров о водителе
+CREATE TABLE driver_notes (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 4000),
+    author VARCHAR(255),
+    author_roles VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE INDEX idx_driver_notes_driver ON driver_notes(driver_id, created_at DESC, id);
@@ -0,0 +1,9 @@
+-- Drop document_versions table and resubmission tracking
+DROP INDEX IF EXISTS idx_driver_documents_resubmission_due;
+DROP INDEX IF EXISTS idx_document_versions_driver;
+DROP INDEX IF EXISTS idx_document_versions_document;
+DROP TABLE IF EXISTS document_versions;
+
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS resubmission_reminder_sent_at;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS resubmitted_at;
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS resubmission_count;
@@ -0,0 +1,30 @@
+-- Track resubmissions of rejected documents
+ALTER TABLE driver_documents ADD COLUMN resubmission_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE driver_documents ADD COLUMN resubmitted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE driver_documents ADD COLUMN resubmission_reminder_sent_at TIMESTAMP WITH TIME ZONE;
+
+-- Create document_versions table: версии документа, замененные повторной загрузкой после отказа
+CREATE TABLE document_versions (
+    id UUID PRIMARY KEY,
+    document_id UUID NOT NULL REFERENCES driver_documents(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL CHECK (version > 0),
+    document_number VARCHAR(100) NOT NULL,
+    issue_date DATE NOT NULL,
+    expiry_date DATE NOT NULL,
+    file_url VARCHAR(500) NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    verified_by VARCHAR(255),
+    verified_at TIMESTAMP WITH TIME ZONE,
+    rejection_reason TEXT,
+    submitted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    replaced_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_document_versions_document ON document_versions(document_id, version);
+CREATE INDEX idx_document_versions_driver ON document_versions(driver_id);
+
+-- Rejected documents awaiting resubmission, for the reminder job
+CREATE INDEX idx_driver_documents_resubmission_due ON driver_documents(verified_at)
+    WHERE status = 'rejected' AND replaces_id IS NULL AND resubmission_reminder_sent_at IS NULL;
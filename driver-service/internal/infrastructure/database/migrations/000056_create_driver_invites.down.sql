@@ -0,0 +1,4 @@
+-- Drop driver_invites table
+DROP INDEX IF EXISTS idx_driver_invites_fleet;
+DROP INDEX IF EXISTS idx_driver_invites_created;
+DROP TABLE IF EXISTS driver_invites;
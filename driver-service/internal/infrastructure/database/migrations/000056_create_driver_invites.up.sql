@@ -0,0 +1,23 @@
+-- Create driver_invites table: приглашения водителей автопарков на самостоятельную регистрацию.
+-- Токен ссылки не хранится: он подписан и содержит ID приглашения
+CREATE TABLE driver_invites (
+    id UUID PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL,
+    fleet_id UUID NOT NULL REFERENCES fleets(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+    accepted_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by VARCHAR(255)
+);
+
+-- Add check constraints
+ALTER TABLE driver_invites ADD CONSTRAINT check_driver_invites_status
+    CHECK (status IN ('pending', 'accepted', 'revoked'));
+
+-- Create indexes
+CREATE INDEX idx_driver_invites_created ON driver_invites(created_at DESC, id);
+CREATE INDEX idx_driver_invites_fleet ON driver_invites(fleet_id);
@@ -0,0 +1,9 @@
+-- Drop shift rest totals
+ALTER TABLE driver_shifts DROP COLUMN IF EXISTS rest_compliant;
+ALTER TABLE driver_shifts DROP COLUMN IF EXISTS break_minutes;
+
+-- Drop shift_breaks table
+DROP INDEX IF EXISTS idx_shift_breaks_open;
+DROP INDEX IF EXISTS idx_shift_breaks_driver_started;
+DROP INDEX IF EXISTS idx_shift_breaks_shift_started;
+DROP TABLE IF EXISTS shift_breaks;
@@ -0,0 +1,26 @@
+-- Create shift_breaks table: перерывы водителя внутри смены для учета времени отдыха
+CREATE TABLE shift_breaks (
+    id UUID PRIMARY KEY,
+    shift_id UUID NOT NULL REFERENCES driver_shifts(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Add check constraints
+ALTER TABLE shift_breaks ADD CONSTRAINT check_shift_breaks_period
+    CHECK (ended_at IS NULL OR ended_at >= started_at);
+
+-- Create indexes
+CREATE INDEX idx_shift_breaks_shift_started ON shift_breaks(shift_id, started_at);
+CREATE INDEX idx_shift_breaks_driver_started ON shift_breaks(driver_id, started_at DESC);
+
+-- В смене не больше одного идущего перерыва
+CREATE UNIQUE INDEX idx_shift_breaks_open ON shift_breaks(shift_id)
+    WHERE ended_at IS NULL;
+
+-- Итоги отдыха завершенной смены: время перерывов и соблюдение требований к отдыху
+ALTER TABLE driver_shifts ADD COLUMN break_minutes BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE driver_shifts ADD COLUMN rest_compliant BOOLEAN;
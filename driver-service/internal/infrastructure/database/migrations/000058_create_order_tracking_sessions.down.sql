@@ -0,0 +1,4 @@
+-- Drop order_tracking_sessions table
+DROP INDEX IF EXISTS idx_order_tracking_sessions_driver;
+DROP INDEX IF EXISTS idx_order_tracking_sessions_active;
+DROP TABLE IF EXISTS order_tracking_sessions;
@@ -0,0 +1,18 @@
+-- Create order_tracking_sessions table: отслеживание заказов клиентами по подписанной ссылке.
+-- Токен ссылки не хранится: он подписан и содержит ID отслеживания
+CREATE TABLE order_tracking_sessions (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    stopped_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+-- Заказ отслеживается не больше одного раза одновременно
+CREATE UNIQUE INDEX idx_order_tracking_sessions_active ON order_tracking_sessions(order_id)
+    WHERE stopped_at IS NULL;
+
+-- Create indexes
+CREATE INDEX idx_order_tracking_sessions_driver ON order_tracking_sessions(driver_id, started_at DESC);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_driver_documents_history;
+DROP INDEX IF EXISTS idx_driver_documents_current_type;
+
+CREATE UNIQUE INDEX idx_driver_documents_unique_type ON driver_documents(driver_id, document_type)
+    WHERE replaces_id IS NULL;
+
+ALTER TABLE driver_documents DROP COLUMN IF EXISTS is_current;
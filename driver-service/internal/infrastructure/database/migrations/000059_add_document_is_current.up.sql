@@ -0,0 +1,26 @@
+-- Several versions of a document type per driver: the version in effect is marked current
+ALTER TABLE driver_documents ADD COLUMN is_current BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Replaced versions and renewals that are not verified yet (or were rejected) are history
+UPDATE driver_documents SET is_current = FALSE
+WHERE status = 'superseded'
+   OR (replaces_id IS NOT NULL AND status IN ('pending', 'processing', 'rejected'));
+
+-- Of several remaining versions of a type only the newest stays current
+UPDATE driver_documents d SET is_current = FALSE
+WHERE d.is_current AND EXISTS (
+    SELECT 1 FROM driver_documents newer
+    WHERE newer.driver_id = d.driver_id
+      AND newer.document_type = d.document_type
+      AND newer.is_current
+      AND (newer.created_at, newer.id) > (d.created_at, d.id)
+);
+
+-- One current version per type instead of one original per type
+DROP INDEX IF EXISTS idx_driver_documents_unique_type;
+CREATE UNIQUE INDEX idx_driver_documents_current_type ON driver_documents(driver_id, document_type)
+    WHERE is_current;
+
+-- History of a document type, newest first
+CREATE INDEX idx_driver_documents_history ON driver_documents(driver_id, document_type, created_at DESC)
+    WHERE NOT is_current;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_driver_locations_driver_order;
+DROP INDEX IF EXISTS idx_driver_locations_driver_quality;
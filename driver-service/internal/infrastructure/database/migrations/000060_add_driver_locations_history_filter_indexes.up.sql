@@ -0,0 +1,10 @@
+-- Location history filters are evaluated in SQL. Accuracy and speed are index key columns
+-- after the time range, so points that fail them are skipped in the index without reading
+-- the rows of a driver's history
+CREATE INDEX idx_driver_locations_driver_quality
+    ON driver_locations(driver_id, recorded_at DESC, accuracy, speed);
+
+-- History of one driver for one order (dispatch investigations)
+CREATE INDEX idx_driver_locations_driver_order
+    ON driver_locations(driver_id, order_id, recorded_at DESC)
+    WHERE order_id IS NOT NULL;
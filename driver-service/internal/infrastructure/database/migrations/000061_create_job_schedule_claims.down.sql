@@ -0,0 +1,2 @@
+-- Drop job_schedule_claims table
+DROP TABLE IF EXISTS job_schedule_claims;
@@ -0,0 +1,9 @@
+-- Create job_schedule_claims table: последний запуск каждой фоновой задачи по расписанию,
+-- отмеченный одним из экземпляров сервиса. Экземпляр, чье расписание сработало позже, видит
+-- отметку и пропускает уже выполненный запуск
+CREATE TABLE job_schedule_claims (
+    job_name VARCHAR(100) PRIMARY KEY,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    instance VARCHAR(255) NOT NULL DEFAULT '',
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+-- Drop driver_profile_changes table
+DROP TABLE IF EXISTS driver_profile_changes;
@@ -0,0 +1,24 @@
+-- Create driver_profile_changes table: заявки водителей на изменение ФИО и данных паспорта
+-- и удостоверения. Изменения попадают в drivers только после одобрения администратором;
+-- ожидающая решения заявка у водителя может быть только одна
+CREATE TABLE driver_profile_changes (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    changes JSONB NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    requested_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    reviewed_by VARCHAR(255),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_comment TEXT
+);
+
+-- Add check constraints
+ALTER TABLE driver_profile_changes ADD CONSTRAINT check_driver_profile_changes_status
+    CHECK (status IN ('pending', 'approved', 'rejected'));
+
+-- Create indexes
+CREATE UNIQUE INDEX idx_driver_profile_changes_pending ON driver_profile_changes(driver_id) WHERE status = 'pending';
+CREATE INDEX idx_driver_profile_changes_driver ON driver_profile_changes(driver_id, created_at DESC, id);
+CREATE INDEX idx_driver_profile_changes_queue ON driver_profile_changes(created_at, id) WHERE status = 'pending';
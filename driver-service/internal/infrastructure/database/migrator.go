@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"go.uber.org/zap"
+)
+
+// migrationFiles миграции, встроенные в бинарник: сервис и cmd/migrate не зависят
+// от рабочего каталога и файлов рядом с бинарником
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migrations возвращает встроенные миграции
+func Migrations() fs.FS {
+	sub, err := fs.Sub(migrationFiles, "migrations")
+	if err != nil {
+		// Каталог встроен при сборке, ошибка возможна только при изменении директивы embed
+		panic(err)
+	}
+	return sub
+}
+
+// ErrDirtyMigration миграция прервана; схему нужно исправить вручную и выполнить force
+var ErrDirtyMigration = errors.New("database migration is dirty")
+
+// migrationHistoryTable журнал выполненных команд миграции. Таблица создается мигратором,
+// а не миграцией: журнал должен пережить откат всех миграций
+const migrationHistoryTable = `
+	CREATE TABLE IF NOT EXISTS schema_migration_history (
+		id BIGSERIAL PRIMARY KEY,
+		command VARCHAR(16) NOT NULL,
+		from_version BIGINT,
+		to_version BIGINT,
+		dirty BOOLEAN NOT NULL DEFAULT FALSE,
+		error TEXT,
+		executed_by VARCHAR(255) NOT NULL DEFAULT '',
+		executed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+	)`
+
+// Команды миграции в журнале
+const (
+	MigrationCommandUp    = "up"
+	MigrationCommandDown  = "down"
+	MigrationCommandForce = "force"
+)
+
+// MigrationHistoryEntry выполненная команда миграции
+type MigrationHistoryEntry struct {
+	ID          int64     `json:"id" db:"id"`
+	Command     string    `json:"command" db:"command"`
+	FromVersion *int64    `json:"from_version,omitempty" db:"from_version"`
+	ToVersion   *int64    `json:"to_version,omitempty" db:"to_version"`
+	Dirty       bool      `json:"dirty" db:"dirty"`
+	Error       *string   `json:"error,omitempty" db:"error"`
+	ExecutedBy  string    `json:"executed_by" db:"executed_by"`
+	ExecutedAt  time.Time `json:"executed_at" db:"executed_at"`
+}
+
+// MigrationStatus состояние схемы относительно встроенных миграций
+type MigrationStatus struct {
+	// Version последняя примененная миграция; nil — миграции не применялись
+	Version *uint `json:"version,omitempty"`
+	Dirty   bool  `json:"dirty"`
+	// Latest последняя встроенная миграция
+	Latest  uint   `json:"latest"`
+	Pending []uint `json:"pending"`
+	// History последние команды миграции, новые первыми
+	History []MigrationHistoryEntry `json:"history"`
+}
+
+// Migrator применяет встроенные миграции и записывает выполненные команды в журнал
+type Migrator struct {
+	db      *DB
+	migrate *migrate.Migrate
+	// executedBy кто выполняет команды: hostname экземпляра сервиса или оператор cmd/migrate
+	executedBy string
+}
+
+// NewMigrator создает мигратор базы на отдельном соединении пула; Close возвращает его.
+// Блокировку от одновременного запуска миграций несколькими экземплярами берет
+// golang-migrate (advisory lock)
+func (db *DB) NewMigrator(ctx context.Context, executedBy string) (*Migrator, error) {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded migrations: %w", err)
+	}
+
+	// WithInstance закрыл бы при Close весь пул сервиса, поэтому драйверу отдается одно соединение
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration connection: %w", err)
+	}
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", source, "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+
+	if _, err := db.DB.ExecContext(ctx, migrationHistoryTable); err != nil {
+		m.Close()
+		return nil, fmt.Errorf("failed to create migration history table: %w", err)
+	}
+
+	if executedBy == "" {
+		executedBy, _ = os.Hostname()
+	}
+	return &Migrator{db: db, migrate: m, executedBy: executedBy}, nil
+}
+
+// Up применяет все новые миграции. Прерванная ранее миграция не применяется повторно:
+// возвращается ErrDirtyMigration
+func (m *Migrator) Up() error {
+	return m.run(MigrationCommandUp, func() error {
+		if err := m.migrate.Up(); err != nil && err != migrate.ErrNoChange {
+			return err
+		}
+		return nil
+	})
+}
+
+// Down откатывает steps последних миграций
+func (m *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("down steps must be positive")
+	}
+	return m.run(MigrationCommandDown, func() error {
+		return m.migrate.Steps(-steps)
+	})
+}
+
+// Force устанавливает версию схемы и снимает признак прерванной миграции, не выполняя
+// миграции. Применяется после ручного исправления схемы
+func (m *Migrator) Force(version int) error {
+	return m.run(MigrationCommandForce, func() error {
+		return m.migrate.Force(version)
+	})
+}
+
+// Status возвращает примененную и последнюю встроенную версии, ожидающие миграции
+// и последние limit команд журнала
+func (m *Migrator) Status(ctx context.Context, limit int) (*MigrationStatus, error) {
+	versions, err := migrationVersions(Migrations())
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MigrationStatus{Pending: []uint{}}
+	if len(versions) > 0 {
+		status.Latest = versions[len(versions)-1]
+	}
+
+	version, dirty, err := m.migrate.Version()
+	switch {
+	case err == migrate.ErrNilVersion:
+	case err != nil:
+		return nil, fmt.Errorf("failed to read migration version: %w", err)
+	default:
+		status.Version = &version
+		status.Dirty = dirty
+	}
+
+	for _, v := range versions {
+		if status.Version == nil || v > *status.Version {
+			status.Pending = append(status.Pending, v)
+		}
+	}
+
+	if err := m.db.DB.SelectContext(ctx, &status.History,
+		`SELECT * FROM schema_migration_history ORDER BY id DESC LIMIT $1`, limit); err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	return status, nil
+}
+
+// Close возвращает соединение мигратора в пул; пул базы остается открытым
+func (m *Migrator) Close() error {
+	sourceErr, dbErr := m.migrate.Close()
+	if sourceErr != nil {
+		return sourceErr
+	}
+	return dbErr
+}
+
+// run выполняет команду и записывает в журнал версии до и после нее
+func (m *Migrator) run(command string, fn func() error) error {
+	from := m.version()
+	err := fn()
+	to := m.version()
+
+	var dirtyErr migrate.ErrDirty
+	if errors.As(err, &dirtyErr) {
+		err = fmt.Errorf("%w: version %d, fix the schema and run force", ErrDirtyMigration, dirtyErr.Version)
+	}
+
+	entry := MigrationHistoryEntry{
+		Command:     command,
+		FromVersion: from,
+		ToVersion:   to,
+		ExecutedBy:  m.executedBy,
+	}
+	if _, dirty, versionErr := m.migrate.Version(); versionErr == nil {
+		entry.Dirty = dirty
+	}
+	if err != nil {
+		message := err.Error()
+		entry.Error = &message
+	}
+
+	// Команда без изменений (up без новых миграций при каждом запуске) журнал не засоряет
+	if err != nil || !sameVersion(from, to) || command != MigrationCommandUp {
+		if _, historyErr := m.db.DB.NamedExec(`
+			INSERT INTO schema_migration_history (command, from_version, to_version, dirty, error, executed_by)
+			VALUES (:command, :from_version, :to_version, :dirty, :error, :executed_by)`, &entry); historyErr != nil {
+			m.db.logger.Error("Failed to record migration history", zap.Error(historyErr))
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("migration %s failed: %w", command, err)
+	}
+	m.db.logger.Info("Database migration completed",
+		zap.String("command", command),
+		zap.Any("from_version", from),
+		zap.Any("to_version", to),
+	)
+	return nil
+}
+
+// version текущая версия схемы; nil — миграции не применялись или версию не удалось прочитать
+func (m *Migrator) version() *int64 {
+	version, _, err := m.migrate.Version()
+	if err != nil {
+		return nil
+	}
+	v := int64(version)
+	return &v
+}
+
+// sameVersion сравнивает версии схемы
+func sameVersion(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// migrationVersions номера миграций *.up.sql по возрастанию
+func migrationVersions(migrations fs.FS) ([]uint, error) {
+	entries, err := fs.ReadDir(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var versions []uint
+	for _, entry := range entries {
+		if version, ok := upMigrationVersion(entry); ok {
+			versions = append(versions, version)
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
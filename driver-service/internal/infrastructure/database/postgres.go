@@ -8,9 +8,6 @@ import (
 
 	"driver-service/internal/config"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	"go.uber.org/zap"
@@ -19,7 +16,13 @@ import (
 // DB представляет подключение к базе данных
 type DB struct {
 	*sqlx.DB
-	logger *zap.Logger
+	logger      *zap.Logger
+	retryPolicy RetryPolicy
+	retryStats  RetryStats
+	// replicas реплики для чтения; nil — все запросы идут в эту базу
+	replicas *replicaSet
+	// slowQueryThreshold запросы дольше логируются; 0 — не логировать
+	slowQueryThreshold time.Duration
 }
 
 // NewPostgresDB создает новое подключение к PostgreSQL
@@ -52,36 +55,31 @@ func NewPostgresDB(cfg *config.DatabaseConfig, logger *zap.Logger) (*DB, error)
 	return &DB{
 		DB:     db,
 		logger: logger,
+		retryPolicy: RetryPolicy{
+			MaxAttempts: cfg.RetryMaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay,
+			MaxDelay:    cfg.RetryMaxDelay,
+		},
+		slowQueryThreshold: cfg.SlowQueryThreshold,
 	}, nil
 }
 
-// RunMigrations выполняет миграции базы данных
-func (db *DB) RunMigrations(migrationsPath string) error {
-	driver, err := postgres.WithInstance(db.DB.DB, &postgres.Config{})
+// RunMigrations применяет встроенные миграции. Прерванная миграция (ErrDirtyMigration)
+// не исправляется автоматически: сервис не должен работать с наполовину измененной схемой
+func (db *DB) RunMigrations() error {
+	migrator, err := db.NewMigrator(context.Background(), "")
 	if err != nil {
-		return fmt.Errorf("failed to create migration driver: %w", err)
-	}
-
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migration instance: %w", err)
-	}
-
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+		return err
 	}
+	defer migrator.Close()
 
-	db.logger.Info("Database migrations completed successfully")
-	return nil
+	return migrator.Up()
 }
 
 // Close закрывает подключение к базе данных
 func (db *DB) Close() error {
 	db.logger.Info("Closing database connection")
+	db.closeReplicas()
 	return db.DB.Close()
 }
 
@@ -133,8 +131,13 @@ func (db *DB) Transaction(fn func(*sqlx.Tx) error) error {
 	return nil
 }
 
-// TransactionWithContext выполняет функцию в транзакции с контекстом
+// TransactionWithContext выполняет функцию в транзакции с контекстом. Если в контексте уже
+// открыта транзакция (WithTransaction), функция выполняется в ней, а фиксирует ее внешний вызов
 func (db *DB) TransactionWithContext(ctx context.Context, fn func(*sqlx.Tx) error) error {
+	if tx, ok := db.txFromContext(ctx); ok {
+		return fn(tx)
+	}
+
 	tx, err := db.BeginTxx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
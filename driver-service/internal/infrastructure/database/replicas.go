@@ -0,0 +1,283 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"driver-service/internal/config"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// replicaLagQuery отставание реплики в секундах. Реплика, применившая весь полученный WAL,
+// не отстает, даже если основная база давно не записывала
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() IS NULL OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// replicaReadsKey ключ контекста, разрешающего чтение с реплик
+type replicaReadsKey struct{}
+
+// WithReplicaReads разрешает читать с реплик в рамках контекста. Разрешение выдается только
+// операциям, которым допустимо отставание реплики: чтение с последующей записью по
+// прочитанному должно идти в основную базу
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// WithPrimaryReads запрещает чтение с реплик в рамках контекста, даже если его разрешил
+// WithReplicaReads: клиенту нужны данные не старше его собственной записи
+func WithPrimaryReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, false)
+}
+
+// ReplicaReadsAllowed сообщает, разрешено ли в контексте чтение с реплик
+func ReplicaReadsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return allowed
+}
+
+// replica реплика для чтения и результат ее последней проверки
+type replica struct {
+	name string
+	db   *DB
+
+	healthy atomic.Bool
+	mu      sync.Mutex
+	lastErr error
+}
+
+// ReplicaStatus состояние реплики
+type ReplicaStatus struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+// setHealth запоминает результат проверки реплики; смена состояния логируется
+func (r *replica) setHealth(err error, logger *zap.Logger) {
+	r.mu.Lock()
+	r.lastErr = err
+	r.mu.Unlock()
+
+	wasHealthy := r.healthy.Swap(err == nil)
+	switch {
+	case err != nil && wasHealthy:
+		logger.Warn("Database replica unavailable, reads fall back to primary",
+			zap.Error(err),
+			zap.String("replica", r.name),
+		)
+	case err == nil && !wasHealthy:
+		logger.Info("Database replica available", zap.String("replica", r.name))
+	}
+}
+
+// status возвращает состояние реплики
+func (r *replica) status() ReplicaStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	status := ReplicaStatus{Name: r.name, Healthy: r.healthy.Load()}
+	if r.lastErr != nil {
+		status.Error = r.lastErr.Error()
+	}
+	return status
+}
+
+// replicaSet реплики основной базы и их периодическая проверка
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	maxLag   time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// OpenReplicas подключается к репликам из секции database.replicas и начинает их проверку.
+// Недоступная при запуске реплика не мешает старту: чтение идет в основную базу, пока
+// проверка не вернет реплику в работу
+func OpenReplicas(cfg *config.Config, primary *DB, logger *zap.Logger) error {
+	if len(cfg.Database.Replicas) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(cfg.Database.Replicas))
+	for name := range cfg.Database.Replicas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	set := &replicaSet{
+		maxLag: cfg.Database.ReplicaMaxLag,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for _, name := range names {
+		dbCfg := cfg.ReplicaDatabase(name)
+		db, err := openReplica(&dbCfg, logger.With(zap.String("replica", name)))
+		if err != nil {
+			for _, opened := range set.replicas {
+				opened.db.DB.Close()
+			}
+			return fmt.Errorf("failed to open replica %s: %w", name, err)
+		}
+		r := &replica{name: name, db: db}
+		// До первой проверки реплика считается доступной, чтобы ее отказ при запуске попал в лог
+		r.healthy.Store(true)
+		set.replicas = append(set.replicas, r)
+	}
+
+	primary.replicas = set
+	primary.checkReplicas(context.Background())
+	go primary.runReplicaChecks(cfg.Database.ReplicaCheckInterval)
+	return nil
+}
+
+// openReplica создает пул соединений реплики без проверки подключения
+func openReplica(cfg *config.DatabaseConfig, logger *zap.Logger) (*DB, error) {
+	db, err := sqlx.Open("postgres", cfg.GetDSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	// Чтение с реплики не повторяется: при временной ошибке запрос сразу уходит в основную базу
+	return &DB{DB: db, logger: logger, retryPolicy: RetryPolicy{MaxAttempts: 1}, slowQueryThreshold: cfg.SlowQueryThreshold}, nil
+}
+
+// runReplicaChecks проверяет реплики раз в interval до закрытия основной базы
+func (db *DB) runReplicaChecks(interval time.Duration) {
+	defer close(db.replicas.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-db.replicas.stop:
+			return
+		case <-ticker.C:
+			db.checkReplicas(context.Background())
+		}
+	}
+}
+
+// checkReplicas проверяет соединение и отставание каждой реплики
+func (db *DB) checkReplicas(ctx context.Context) {
+	for _, r := range db.replicas.replicas {
+		checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		r.setHealth(db.replicas.probe(checkCtx, r), db.logger)
+		cancel()
+	}
+}
+
+// probe проверяет, можно ли читать с реплики
+func (s *replicaSet) probe(ctx context.Context, r *replica) error {
+	if err := r.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("replica health check failed: %w", err)
+	}
+	if s.maxLag <= 0 {
+		return nil
+	}
+
+	var lagSeconds float64
+	if err := r.db.DB.GetContext(ctx, &lagSeconds, replicaLagQuery); err != nil {
+		return fmt.Errorf("failed to get replica lag: %w", err)
+	}
+	if lag := time.Duration(lagSeconds * float64(time.Second)); lag > s.maxLag {
+		return fmt.Errorf("replica lag %s exceeds %s", lag.Round(time.Millisecond), s.maxLag)
+	}
+	return nil
+}
+
+// closeReplicas останавливает проверку реплик и закрывает их подключения
+func (db *DB) closeReplicas() {
+	if db.replicas == nil {
+		return
+	}
+	close(db.replicas.stop)
+	<-db.replicas.done
+	for _, r := range db.replicas.replicas {
+		if err := r.db.DB.Close(); err != nil {
+			db.logger.Error("Failed to close replica connection", zap.Error(err), zap.String("replica", r.name))
+		}
+	}
+}
+
+// ReplicaStatuses возвращает состояние реплик в порядке имен
+func (db *DB) ReplicaStatuses() []ReplicaStatus {
+	if db.replicas == nil {
+		return []ReplicaStatus{}
+	}
+	statuses := make([]ReplicaStatus, len(db.replicas.replicas))
+	for i, r := range db.replicas.replicas {
+		statuses[i] = r.status()
+	}
+	return statuses
+}
+
+// pickReplica выбирает по кругу доступную реплику; nil — читать из основной базы.
+// В транзакции чтение идет в нее: реплика не видит еще не зафиксированных изменений
+func (db *DB) pickReplica(ctx context.Context) *replica {
+	if db.replicas == nil || !ReplicaReadsAllowed(ctx) {
+		return nil
+	}
+	if _, inTx := db.txFromContext(ctx); inTx {
+		return nil
+	}
+
+	replicas := db.replicas.replicas
+	start := db.replicas.next.Add(1)
+	for i := range replicas {
+		r := replicas[(start+uint64(i))%uint64(len(replicas))]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// read выполняет чтение fn на реплике, если это разрешено, иначе на основной базе.
+// Временная ошибка реплики переводит чтение в основную базу; при обрыве соединения
+// реплика исключается до следующей успешной проверки
+func (db *DB) read(ctx context.Context, fn func(target *DB) error) error {
+	r := db.pickReplica(ctx)
+	if r == nil {
+		return fn(db)
+	}
+
+	err := fn(r.db)
+	class := ClassifyError(err)
+	if !class.Retryable() {
+		return err
+	}
+	if class == ErrorClassConnection || class == ErrorClassUnavailable {
+		r.setHealth(err, db.logger)
+	}
+	return fn(db)
+}
+
+// ReplicaGetContext выполняет запрос одной строки на реплике, если контекст разрешает
+// чтение с реплик (WithReplicaReads), иначе на основной базе
+func (db *DB) ReplicaGetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.read(ctx, func(target *DB) error {
+		return target.GetContext(ctx, dest, query, args...)
+	})
+}
+
+// ReplicaSelectContext выполняет запрос нескольких строк на реплике, если контекст разрешает
+// чтение с реплик (WithReplicaReads), иначе на основной базе
+func (db *DB) ReplicaSelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.read(ctx, func(target *DB) error {
+		return target.SelectContext(ctx, dest, query, args...)
+	})
+}
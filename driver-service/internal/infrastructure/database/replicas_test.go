@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func newTestReplicaDB(names ...string) *DB {
+	set := &replicaSet{}
+	for _, name := range names {
+		r := &replica{name: name, db: &DB{logger: zap.NewNop()}}
+		r.healthy.Store(true)
+		set.replicas = append(set.replicas, r)
+	}
+	return &DB{logger: zap.NewNop(), replicas: set}
+}
+
+func TestDB_ReadRoutesToReplicas(t *testing.T) {
+	db := newTestReplicaDB("replica1", "replica2")
+	ctx := WithReplicaReads(context.Background())
+
+	used := map[*DB]int{}
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, db.read(ctx, func(target *DB) error {
+			used[target]++
+			return nil
+		}))
+	}
+	assert.Equal(t, 2, used[db.replicas.replicas[0].db], "reads are spread across replicas")
+	assert.Equal(t, 2, used[db.replicas.replicas[1].db])
+	assert.Zero(t, used[db])
+
+	// Без разрешения в контексте чтение идет в основную базу
+	var primaryTarget *DB
+	assert.NoError(t, db.read(context.Background(), func(target *DB) error {
+		primaryTarget = target
+		return nil
+	}))
+	assert.Same(t, db, primaryTarget)
+
+	// WithPrimaryReads отменяет разрешение, выданное выше по цепочке
+	primaryTarget = nil
+	assert.NoError(t, db.read(WithPrimaryReads(ctx), func(target *DB) error {
+		primaryTarget = target
+		return nil
+	}))
+	assert.Same(t, db, primaryTarget)
+}
+
+func TestDB_ReadFailsOverToPrimary(t *testing.T) {
+	db := newTestReplicaDB("replica1")
+	ctx := WithReplicaReads(context.Background())
+	replica := db.replicas.replicas[0]
+
+	var targets []*DB
+	err := db.read(ctx, func(target *DB) error {
+		targets = append(targets, target)
+		if target != db {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*DB{replica.db, db}, targets)
+	assert.False(t, replica.healthy.Load(), "a broken replica is excluded until the next check")
+	assert.Equal(t, []ReplicaStatus{{Name: "replica1", Error: driver.ErrBadConn.Error()}}, db.ReplicaStatuses())
+
+	// Пока реплика исключена, чтение сразу идет в основную базу
+	targets = nil
+	assert.NoError(t, db.read(ctx, func(target *DB) error {
+		targets = append(targets, target)
+		return nil
+	}))
+	assert.Equal(t, []*DB{db}, targets)
+
+	// Постоянная ошибка запроса не переводит чтение в основную базу
+	replica.setHealth(nil, zap.NewNop())
+	targets = nil
+	permanent := &pq.Error{Code: "23505"}
+	err = db.read(ctx, func(target *DB) error {
+		targets = append(targets, target)
+		return permanent
+	})
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, []*DB{replica.db}, targets)
+	assert.True(t, replica.healthy.Load())
+}
@@ -0,0 +1,308 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ErrorClass класс ошибки базы данных с точки зрения повторных попыток
+type ErrorClass string
+
+const (
+	// ErrorClassNone операция выполнена без ошибки
+	ErrorClassNone ErrorClass = ""
+	// ErrorClassPermanent повтор не поможет: ошибки данных, ограничений, синтаксиса, отмена контекста
+	ErrorClassPermanent ErrorClass = "permanent"
+	// ErrorClassSerialization конфликт сериализации, транзакция откачена
+	ErrorClassSerialization ErrorClass = "serialization"
+	// ErrorClassDeadlock взаимная блокировка, транзакция откачена
+	ErrorClassDeadlock ErrorClass = "deadlock"
+	// ErrorClassConnection соединение разорвано; для записи результат неизвестен
+	ErrorClassConnection ErrorClass = "connection"
+	// ErrorClassUnavailable сервер временно не принимает запросы (перезапуск, лимит соединений)
+	ErrorClassUnavailable ErrorClass = "unavailable"
+)
+
+// Retryable сообщает, имеет ли смысл повторять операцию с ошибкой этого класса
+func (c ErrorClass) Retryable() bool {
+	switch c {
+	case ErrorClassSerialization, ErrorClassDeadlock, ErrorClassConnection, ErrorClassUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// ClassifyError определяет класс ошибки базы данных. Ошибки, обернутые через %w, разбираются
+func ClassifyError(err error) ErrorClass {
+	if err == nil {
+		return ErrorClassNone
+	}
+
+	// Отмена и таймаут запроса клиента не повторяются
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, sql.ErrNoRows) {
+		return ErrorClassPermanent
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "40001": // serialization_failure
+			return ErrorClassSerialization
+		case "40P01": // deadlock_detected
+			return ErrorClassDeadlock
+		case "53300", "57P01", "57P02", "57P03": // too_many_connections, admin_shutdown, crash_shutdown, cannot_connect_now
+			return ErrorClassUnavailable
+		}
+		if pqErr.Code.Class() == "08" { // connection_exception
+			return ErrorClassConnection
+		}
+		return ErrorClassPermanent
+	}
+
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return ErrorClassConnection
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorClassConnection
+	}
+
+	return ErrorClassPermanent
+}
+
+// IsRetryable сообщает, является ли ошибка временной
+func IsRetryable(err error) bool {
+	return ClassifyError(err).Retryable()
+}
+
+// RetryPolicy ограничения повторных попыток
+type RetryPolicy struct {
+	// MaxAttempts общее число попыток, включая первую; 1 отключает повторы
+	MaxAttempts int
+	// BaseDelay задержка перед первым повтором, далее удваивается
+	BaseDelay time.Duration
+	// MaxDelay максимальная задержка между попытками
+	MaxDelay time.Duration
+}
+
+// backoff возвращает задержку перед повтором номер attempt (с 1): половина экспоненциальной
+// задержки фиксирована, вторая половина случайна, чтобы повторы разных запросов не совпадали
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// RetryStats счетчики повторных попыток
+type RetryStats struct {
+	operations atomic.Int64
+	retried    atomic.Int64
+	retries    atomic.Int64
+	recovered  atomic.Int64
+	exhausted  atomic.Int64
+
+	mu      sync.Mutex
+	byClass map[ErrorClass]int64
+}
+
+// RetryStatsSnapshot снимок счетчиков повторных попыток
+type RetryStatsSnapshot struct {
+	// Operations число операций, выполненных с поддержкой повторов
+	Operations int64 `json:"operations"`
+	// Retries число повторных попыток
+	Retries int64 `json:"retries"`
+	// Recovered операции, успешно завершенные после повтора
+	Recovered int64 `json:"recovered"`
+	// Exhausted операции, для которых временная ошибка сохранилась после всех попыток
+	Exhausted int64 `json:"exhausted"`
+	// RetryRate доля операций, потребовавших хотя бы одного повтора
+	RetryRate float64              `json:"retry_rate"`
+	ByClass   map[ErrorClass]int64 `json:"by_class"`
+}
+
+// record учитывает повтор после ошибки класса class
+func (s *RetryStats) record(class ErrorClass) {
+	s.retries.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.byClass == nil {
+		s.byClass = make(map[ErrorClass]int64)
+	}
+	s.byClass[class]++
+}
+
+// Snapshot возвращает текущие значения счетчиков
+func (s *RetryStats) Snapshot() RetryStatsSnapshot {
+	snapshot := RetryStatsSnapshot{
+		Operations: s.operations.Load(),
+		Retries:    s.retries.Load(),
+		Recovered:  s.recovered.Load(),
+		Exhausted:  s.exhausted.Load(),
+		ByClass:    make(map[ErrorClass]int64),
+	}
+	if snapshot.Operations > 0 {
+		snapshot.RetryRate = float64(s.retried.Load()) / float64(snapshot.Operations)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for class, count := range s.byClass {
+		snapshot.ByClass[class] = count
+	}
+	return snapshot
+}
+
+// Retry выполняет идемпотентную операцию fn, повторяя ее при временных ошибках
+// с экспоненциальной задержкой и случайной составляющей. В транзакции (WithTransaction)
+// операция не повторяется: после ошибки транзакция прервана, и повторять нужно ее целиком
+func (db *DB) Retry(ctx context.Context, operation string, fn func() error) error {
+	db.retryStats.operations.Add(1)
+
+	maxAttempts := db.retryPolicy.MaxAttempts
+	if _, inTx := db.txFromContext(ctx); maxAttempts < 1 || inTx {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			if attempt > 1 {
+				db.retryStats.recovered.Add(1)
+			}
+			return nil
+		}
+
+		class := ClassifyError(err)
+		if !class.Retryable() {
+			return err
+		}
+		if attempt >= maxAttempts {
+			if maxAttempts > 1 {
+				db.retryStats.exhausted.Add(1)
+				db.logger.Error("Database operation failed after retries",
+					zap.Error(err),
+					zap.String("operation", operation),
+					zap.String("error_class", string(class)),
+					zap.Int("attempts", attempt),
+				)
+			}
+			return err
+		}
+
+		delay := db.retryPolicy.backoff(attempt)
+		if attempt == 1 {
+			db.retryStats.retried.Add(1)
+		}
+		db.retryStats.record(class)
+		db.logger.Warn("Retrying database operation",
+			zap.Error(err),
+			zap.String("operation", operation),
+			zap.String("error_class", string(class)),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// RetryStats возвращает счетчики повторных попыток
+func (db *DB) RetryStats() RetryStatsSnapshot {
+	return db.retryStats.Snapshot()
+}
+
+// GetContext выполняет запрос одной строки; чтение повторяется при временных ошибках
+func (db *DB) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.Retry(ctx, "get", func() error {
+		defer db.observe(ctx, "get", query, time.Now())
+		return sqlx.GetContext(ctx, db.conn(ctx), dest, query, args...)
+	})
+}
+
+// SelectContext выполняет запрос нескольких строк; чтение повторяется при временных ошибках.
+// Для запросов с изменением данных (UPDATE ... RETURNING) используйте SelectOnceContext
+func (db *DB) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	return db.Retry(ctx, "select", func() error {
+		// sqlx дописывает строки в срез: после обрыва на середине выборки начинаем с пустого
+		resetSlice(dest)
+		defer db.observe(ctx, "select", query, time.Now())
+		return sqlx.SelectContext(ctx, db.conn(ctx), dest, query, args...)
+	})
+}
+
+// SelectOnceContext выполняет запрос нескольких строк без повторов: для запросов с изменением
+// данных (UPDATE ... RETURNING), которые после обрыва соединения могли быть применены
+func (db *DB) SelectOnceContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	defer db.observe(ctx, "select", query, time.Now())
+	return sqlx.SelectContext(ctx, db.conn(ctx), dest, query, args...)
+}
+
+// ExecIdempotentContext выполняет запрос, повторное выполнение которого не меняет результат
+// (UPDATE с фиксированными значениями, DELETE по условию), с повторами при временных ошибках
+func (db *DB) ExecIdempotentContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := db.Retry(ctx, "exec", func() error {
+		defer db.observe(ctx, "exec", query, time.Now())
+		var err error
+		result, err = db.conn(ctx).ExecContext(ctx, query, args...)
+		return err
+	})
+	return result, err
+}
+
+// NamedExecIdempotentContext именованный вариант ExecIdempotentContext
+func (db *DB) NamedExecIdempotentContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	var result sql.Result
+	err := db.Retry(ctx, "named_exec", func() error {
+		defer db.observe(ctx, "named_exec", query, time.Now())
+		var err error
+		result, err = sqlx.NamedExecContext(ctx, db.conn(ctx), query, arg)
+		return err
+	})
+	return result, err
+}
+
+// resetSlice обнуляет срез, на который указывает dest
+func resetSlice(dest interface{}) {
+	value := reflect.ValueOf(dest)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return
+	}
+	if elem := value.Elem(); elem.Kind() == reflect.Slice {
+		elem.Set(reflect.Zero(elem.Type()))
+	}
+}
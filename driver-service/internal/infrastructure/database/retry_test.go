@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"nil", nil, ErrorClassNone},
+		{"serialization", &pq.Error{Code: "40001"}, ErrorClassSerialization},
+		{"deadlock wrapped", fmt.Errorf("failed to update shift: %w", &pq.Error{Code: "40P01"}), ErrorClassDeadlock},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, ErrorClassUnavailable},
+		{"too many connections", &pq.Error{Code: "53300"}, ErrorClassUnavailable},
+		{"connection failure", &pq.Error{Code: "08006"}, ErrorClassConnection},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), ErrorClassConnection},
+		{"bad connection", driver.ErrBadConn, ErrorClassConnection},
+		{"unique violation", &pq.Error{Code: "23505"}, ErrorClassPermanent},
+		{"no rows", sql.ErrNoRows, ErrorClassPermanent},
+		{"context cancelled", context.Canceled, ErrorClassPermanent},
+		{"unknown", errors.New("boom"), ErrorClassPermanent},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ClassifyError(tc.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for i := 0; i < 100; i++ {
+		first := policy.backoff(1)
+		assert.GreaterOrEqual(t, first, 50*time.Millisecond)
+		assert.LessOrEqual(t, first, 100*time.Millisecond)
+
+		capped := policy.backoff(10)
+		assert.GreaterOrEqual(t, capped, 150*time.Millisecond)
+		assert.LessOrEqual(t, capped, 300*time.Millisecond)
+	}
+}
+
+func newTestRetryDB(maxAttempts int) *DB {
+	return &DB{
+		logger:      zap.NewNop(),
+		retryPolicy: RetryPolicy{MaxAttempts: maxAttempts, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond},
+	}
+}
+
+func TestDB_Retry(t *testing.T) {
+	ctx := context.Background()
+	db := newTestRetryDB(3)
+
+	calls := 0
+	err := db.Retry(ctx, "test", func() error {
+		calls++
+		if calls < 3 {
+			return &pq.Error{Code: "40001"}
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls, "recovers from transient errors")
+
+	calls = 0
+	permanent := &pq.Error{Code: "23505"}
+	err = db.Retry(ctx, "test", func() error {
+		calls++
+		return permanent
+	})
+	assert.Equal(t, permanent, err)
+	assert.Equal(t, 1, calls, "permanent errors are not retried")
+
+	calls = 0
+	err = db.Retry(ctx, "test", func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.True(t, IsRetryable(err))
+	assert.Equal(t, 3, calls, "retries are bounded")
+
+	stats := db.RetryStats()
+	assert.Equal(t, int64(3), stats.Operations)
+	assert.Equal(t, int64(4), stats.Retries)
+	assert.Equal(t, int64(1), stats.Recovered)
+	assert.Equal(t, int64(1), stats.Exhausted)
+	assert.Equal(t, int64(2), stats.ByClass[ErrorClassSerialization])
+	assert.Equal(t, int64(2), stats.ByClass[ErrorClassConnection])
+	assert.InDelta(t, 2.0/3.0, stats.RetryRate, 0.001)
+}
+
+func TestDB_RetryStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	db := newTestRetryDB(10)
+	db.retryPolicy.BaseDelay = time.Hour
+	db.retryPolicy.MaxDelay = time.Hour
+
+	calls := 0
+	done := make(chan error, 1)
+	go func() {
+		done <- db.Retry(ctx, "test", func() error {
+			calls++
+			return &pq.Error{Code: "40P01"}
+		})
+	}()
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Equal(t, ErrorClassDeadlock, ClassifyError(err))
+		assert.Equal(t, 1, calls)
+	case <-time.After(time.Second):
+		t.Fatal("retry did not stop after context cancellation")
+	}
+}
@@ -0,0 +1,158 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"go.uber.org/zap"
+)
+
+// ShardRouter выбирает базу данных шарда по ключу города.
+// Назначения из таблицы city_shards имеют приоритет над секцией sharding.cities конфигурации
+type ShardRouter struct {
+	shards       map[string]*DB
+	defaultShard string
+	static       map[string]string
+	logger       *zap.Logger
+
+	mu          sync.RWMutex
+	assignments map[string]entities.CityShard
+}
+
+// NewShardRouter создает маршрутизатор по открытым базам шардов
+func NewShardRouter(shards map[string]*DB, defaultShard string, cities map[string]string, logger *zap.Logger) (*ShardRouter, error) {
+	if _, ok := shards[defaultShard]; !ok {
+		return nil, fmt.Errorf("default shard %s: %w", defaultShard, entities.ErrShardNotFound)
+	}
+
+	static := make(map[string]string, len(cities))
+	for city, shard := range cities {
+		if _, ok := shards[shard]; !ok {
+			return nil, fmt.Errorf("shard %s for city %s: %w", shard, city, entities.ErrShardNotFound)
+		}
+		static[entities.NormalizeCity(city)] = shard
+	}
+
+	return &ShardRouter{
+		shards:       shards,
+		defaultShard: defaultShard,
+		static:       static,
+		logger:       logger,
+		assignments:  make(map[string]entities.CityShard),
+	}, nil
+}
+
+// OpenShards подключается к основной базе и базам шардов из конфигурации.
+// Основная база данных доступна как шард entities.PrimaryShard
+func OpenShards(cfg *config.Config, primary *DB, logger *zap.Logger) (map[string]*DB, error) {
+	shards := map[string]*DB{entities.PrimaryShard: primary}
+	for name := range cfg.Sharding.Shards {
+		dbCfg := cfg.ShardDatabase(name)
+		db, err := NewPostgresDB(&dbCfg, logger.With(zap.String("shard", name)))
+		if err != nil {
+			CloseShards(shards, primary)
+			return nil, fmt.Errorf("failed to connect to shard %s: %w", name, err)
+		}
+		shards[name] = db
+	}
+	return shards, nil
+}
+
+// CloseShards закрывает подключения к шардам, кроме основной базы
+func CloseShards(shards map[string]*DB, primary *DB) {
+	for _, db := range shards {
+		if db != primary {
+			db.Close()
+		}
+	}
+}
+
+// Names возвращает имена шардов в алфавитном порядке
+func (r *ShardRouter) Names() []string {
+	names := make([]string, 0, len(r.shards))
+	for name := range r.shards {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Shard возвращает базу данных шарда по имени
+func (r *ShardRouter) Shard(name string) (*DB, error) {
+	db, ok := r.shards[name]
+	if !ok {
+		return nil, entities.ErrShardNotFound
+	}
+	return db, nil
+}
+
+// Resolve возвращает назначение города: шард и признак переноса.
+// Город без назначения и пустой ключ относятся к шарду по умолчанию
+func (r *ShardRouter) Resolve(city string) entities.CityShard {
+	city = entities.NormalizeCity(city)
+
+	r.mu.RLock()
+	assignment, ok := r.assignments[city]
+	r.mu.RUnlock()
+	if ok {
+		return assignment
+	}
+
+	if shard, ok := r.static[city]; ok {
+		return entities.CityShard{City: city, Shard: shard}
+	}
+	return entities.CityShard{City: city, Shard: r.defaultShard}
+}
+
+// ShardForWrite возвращает шард города для записи; во время переноса города запись запрещена
+func (r *ShardRouter) ShardForWrite(city string) (string, error) {
+	assignment := r.Resolve(city)
+	if assignment.Locked {
+		return "", entities.ErrCityRebalancing
+	}
+	return assignment.Shard, nil
+}
+
+// SetAssignments заменяет назначения из таблицы city_shards.
+// Назначения на неизвестные этому экземпляру шарды пропускаются
+func (r *ShardRouter) SetAssignments(assignments []*entities.CityShard) {
+	next := make(map[string]entities.CityShard, len(assignments))
+	for _, assignment := range assignments {
+		if _, ok := r.shards[assignment.Shard]; !ok {
+			r.logger.Warn("City assigned to unknown shard",
+				zap.String("city", assignment.City),
+				zap.String("shard", assignment.Shard),
+			)
+			continue
+		}
+		next[entities.NormalizeCity(assignment.City)] = *assignment
+	}
+
+	r.mu.Lock()
+	r.assignments = next
+	r.mu.Unlock()
+}
+
+// Assignments возвращает действующие назначения городов: из конфигурации и city_shards
+func (r *ShardRouter) Assignments() []entities.CityShard {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]entities.CityShard, 0, len(r.static)+len(r.assignments))
+	for city, shard := range r.static {
+		if _, ok := r.assignments[city]; !ok {
+			result = append(result, entities.CityShard{City: city, Shard: shard})
+		}
+	}
+	for _, assignment := range r.assignments {
+		result = append(result, assignment)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].City < result[j].City
+	})
+	return result
+}
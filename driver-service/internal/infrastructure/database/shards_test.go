@@ -0,0 +1,71 @@
+package database
+
+import (
+	"testing"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestShardRouter(t *testing.T) *ShardRouter {
+	t.Helper()
+	shards := map[string]*DB{
+		entities.PrimaryShard: {},
+		"east":                {},
+		"west":                {},
+	}
+	router, err := NewShardRouter(shards, entities.PrimaryShard, map[string]string{"Moscow": "west"}, zap.NewNop())
+	require.NoError(t, err)
+	return router
+}
+
+func TestNewShardRouter_UnknownShard(t *testing.T) {
+	shards := map[string]*DB{entities.PrimaryShard: {}}
+
+	_, err := NewShardRouter(shards, "east", nil, zap.NewNop())
+	assert.ErrorIs(t, err, entities.ErrShardNotFound)
+
+	_, err = NewShardRouter(shards, entities.PrimaryShard, map[string]string{"moscow": "east"}, zap.NewNop())
+	assert.ErrorIs(t, err, entities.ErrShardNotFound)
+}
+
+func TestShardRouter_Resolve(t *testing.T) {
+	router := newTestShardRouter(t)
+
+	assert.Equal(t, entities.PrimaryShard, router.Resolve("").Shard)
+	assert.Equal(t, entities.PrimaryShard, router.Resolve("kazan").Shard)
+	assert.Equal(t, "west", router.Resolve(" MOSCOW ").Shard)
+
+	// Назначение из city_shards имеет приоритет над конфигурацией
+	router.SetAssignments([]*entities.CityShard{{City: "moscow", Shard: "east"}})
+	assert.Equal(t, "east", router.Resolve("moscow").Shard)
+}
+
+func TestShardRouter_ShardForWrite(t *testing.T) {
+	router := newTestShardRouter(t)
+	router.SetAssignments([]*entities.CityShard{{City: "kazan", Shard: "east", Locked: true}})
+
+	_, err := router.ShardForWrite("kazan")
+	assert.ErrorIs(t, err, entities.ErrCityRebalancing)
+
+	shard, err := router.ShardForWrite("moscow")
+	require.NoError(t, err)
+	assert.Equal(t, "west", shard)
+}
+
+func TestShardRouter_SetAssignmentsSkipsUnknownShard(t *testing.T) {
+	router := newTestShardRouter(t)
+	router.SetAssignments([]*entities.CityShard{
+		{City: "kazan", Shard: "north"},
+		{City: "sochi", Shard: "east"},
+	})
+
+	assert.Equal(t, entities.PrimaryShard, router.Resolve("kazan").Shard)
+	assert.Equal(t, []entities.CityShard{
+		{City: "moscow", Shard: "west"},
+		{City: "sochi", Shard: "east"},
+	}, router.Assignments())
+}
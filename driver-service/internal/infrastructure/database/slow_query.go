@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// slowQueryMaxLength сколько символов текста медленного запроса попадает в лог
+const slowQueryMaxLength = 500
+
+// observe логирует запрос, выполнявшийся дольше database.slow_query_threshold, с ID запроса
+// клиента из контекста. Параметры запроса не логируются: в них бывают персональные данные
+func (db *DB) observe(ctx context.Context, operation, query string, started time.Time) {
+	elapsed := time.Since(started)
+	if db.slowQueryThreshold <= 0 || elapsed < db.slowQueryThreshold {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("operation", operation),
+		zap.Duration("duration", elapsed),
+		zap.Duration("threshold", db.slowQueryThreshold),
+		zap.String("query", compactQuery(query)),
+	}
+	if actor, ok := entities.AuditActorFromContext(ctx); ok && actor.RequestID != "" {
+		fields = append(fields, zap.String("request_id", actor.RequestID))
+	}
+	if err := ctx.Err(); err != nil {
+		fields = append(fields, zap.NamedError("context_error", err))
+	}
+	db.logger.Warn("Slow database query", fields...)
+}
+
+// compactQuery сворачивает пробелы в тексте запроса и обрезает его до slowQueryMaxLength
+func compactQuery(query string) string {
+	query = strings.Join(strings.Fields(query), " ")
+	if len(query) > slowQueryMaxLength {
+		query = query[:slowQueryMaxLength] + "..."
+	}
+	return query
+}
+
+// ExecContext выполняет запрос без повторов, логируя медленное выполнение
+func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	defer db.observe(ctx, "exec", query, time.Now())
+	return db.conn(ctx).ExecContext(ctx, query, args...)
+}
+
+// NamedExecContext именованный вариант ExecContext
+func (db *DB) NamedExecContext(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
+	defer db.observe(ctx, "named_exec", query, time.Now())
+	return sqlx.NamedExecContext(ctx, db.conn(ctx), query, arg)
+}
+
+// NamedQueryContext выполняет именованный запрос строк без повторов, логируя медленное выполнение
+func (db *DB) NamedQueryContext(ctx context.Context, query string, arg interface{}) (*sqlx.Rows, error) {
+	defer db.observe(ctx, "named_query", query, time.Now())
+	return sqlx.NamedQueryContext(ctx, db.conn(ctx), query, arg)
+}
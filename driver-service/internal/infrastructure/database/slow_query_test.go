@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDB_ObserveSlowQuery(t *testing.T) {
+	core, logs := observer.New(zapcore.WarnLevel)
+	db := &DB{logger: zap.New(core), slowQueryThreshold: 100 * time.Millisecond}
+	ctx := entities.WithAuditActor(context.Background(), entities.AuditActor{RequestID: "req-1"})
+
+	db.observe(ctx, "select", "SELECT 1", time.Now())
+	assert.Zero(t, logs.Len(), "fast query is not logged")
+
+	db.observe(ctx, "select", "SELECT *\n\t FROM drivers", time.Now().Add(-time.Second))
+	require.Equal(t, 1, logs.Len())
+	fields := logs.All()[0].ContextMap()
+	assert.Equal(t, "req-1", fields["request_id"])
+	assert.Equal(t, "SELECT * FROM drivers", fields["query"])
+
+	db.slowQueryThreshold = 0
+	db.observe(ctx, "select", "SELECT 1", time.Now().Add(-time.Hour))
+	assert.Equal(t, 1, logs.Len(), "zero threshold disables logging")
+}
+
+func TestCompactQuery(t *testing.T) {
+	long := compactQuery("SELECT " + strings.Repeat("x", 2*slowQueryMaxLength))
+	assert.Len(t, long, slowQueryMaxLength+len("..."))
+}
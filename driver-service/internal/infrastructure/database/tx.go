@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// txKey ключ контекста с открытой транзакцией
+type txKey struct{}
+
+// txState транзакция контекста и база, в которой она открыта: запросы к другим базам
+// (шардам, репликам) с этим контекстом идут мимо транзакции
+type txState struct {
+	owner *DB
+	tx    *sqlx.Tx
+}
+
+// WithTransaction выполняет fn в транзакции. Запросы репозиториев с контекстом fn выполняются
+// в этой транзакции; ошибка fn откатывает ее. Вложенный вызов присоединяется к внешней
+// транзакции, а фиксирует ее только внешний
+func (db *DB) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := db.txFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	return db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		return fn(context.WithValue(ctx, txKey{}, &txState{owner: db, tx: tx}))
+	})
+}
+
+// txFromContext возвращает транзакцию этой базы, открытую в контексте
+func (db *DB) txFromContext(ctx context.Context) (*sqlx.Tx, bool) {
+	state, ok := ctx.Value(txKey{}).(*txState)
+	if !ok || state.owner != db {
+		return nil, false
+	}
+	return state.tx, true
+}
+
+// conn возвращает исполнителя запросов: транзакцию из контекста или пул соединений
+func (db *DB) conn(ctx context.Context) sqlx.ExtContext {
+	if tx, ok := db.txFromContext(ctx); ok {
+		return tx
+	}
+	return db.DB
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDB_TransactionFromContext(t *testing.T) {
+	db := newTestRetryDB(3)
+	other := newTestRetryDB(3)
+	tx := &sqlx.Tx{}
+	ctx := context.WithValue(context.Background(), txKey{}, &txState{owner: db, tx: tx})
+
+	current, ok := db.txFromContext(ctx)
+	require.True(t, ok)
+	assert.Same(t, tx, current)
+
+	// Транзакция одной базы не используется запросами к другой (шарду, реплике)
+	_, ok = other.txFromContext(ctx)
+	assert.False(t, ok)
+
+	// Вложенный вызов присоединяется к открытой транзакции, не начиная новую
+	called := false
+	require.NoError(t, db.WithTransaction(ctx, func(inner context.Context) error {
+		called = true
+		assert.Equal(t, ctx, inner)
+		return nil
+	}))
+	assert.True(t, called)
+
+	// В транзакции ошибка не повторяется: транзакция уже прервана
+	calls := 0
+	err := db.Retry(ctx, "test", func() error {
+		calls++
+		return driver.ErrBadConn
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDB_TransactionReadsPrimary(t *testing.T) {
+	db := newTestReplicaDB("replica1")
+	ctx := WithReplicaReads(context.Background())
+	assert.NotNil(t, db.pickReplica(ctx))
+
+	ctx = context.WithValue(ctx, txKey{}, &txState{owner: db, tx: &sqlx.Tx{}})
+	assert.Nil(t, db.pickReplica(ctx), "uncommitted changes are visible only in the transaction")
+}
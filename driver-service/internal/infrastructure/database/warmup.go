@@ -0,0 +1,37 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WarmConnections заранее открывает до n соединений и возвращает их в пул простаивающими,
+// чтобы первые запросы после запуска не ждали установки соединения. Число ограничено
+// максимумом открытых соединений пула; возвращает число открытых соединений
+func (db *DB) WarmConnections(ctx context.Context, n int) (int, error) {
+	if max := db.Stats().MaxOpenConnections; max > 0 && n > max {
+		n = max
+	}
+
+	// Соединения удерживаются до конца прогрева, иначе пул выдавал бы одно и то же
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for len(conns) < n {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return len(conns), fmt.Errorf("failed to open connection: %w", err)
+		}
+		conns = append(conns, conn)
+
+		if err := conn.PingContext(ctx); err != nil {
+			return len(conns) - 1, fmt.Errorf("failed to ping connection: %w", err)
+		}
+	}
+	return len(conns), nil
+}
@@ -0,0 +1,60 @@
+package licenseregistry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen реестр не вызывается после серии отказов
+var ErrCircuitOpen = errors.New("license registry circuit is open")
+
+// circuitBreaker прекращает обращения к реестру после threshold отказов подряд. Через
+// openTimeout пропускается одна пробная проверка: успех закрывает цепь, отказ снова
+// размыкает ее на openTimeout
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	// probing пробная проверка уже выполняется
+	probing bool
+}
+
+// allow сообщает, можно ли обратиться к реестру в момент now
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return true
+	}
+	if b.probing || now.Sub(b.openedAt) < b.openTimeout {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// success закрывает цепь
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+}
+
+// failure учитывает отказ; при достижении порога цепь размыкается с момента now
+func (b *circuitBreaker) failure(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.openedAt = now
+	}
+}
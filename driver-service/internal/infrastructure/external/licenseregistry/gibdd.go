@@ -0,0 +1,114 @@
+package licenseregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+)
+
+// maxResponseSize ограничение размера ответа реестра
+const maxResponseSize = 1 << 20
+
+// GIBDDClient проверяет удостоверения через API ГИБДД (external.gibdd_api)
+type GIBDDClient struct {
+	url     string
+	apiKey  string
+	timeout time.Duration
+	client  *http.Client
+}
+
+var _ Client = (*GIBDDClient)(nil)
+
+// NewGIBDDClient создает клиента API ГИБДД; проверки отправляются на <base_url>/licenses/check
+func NewGIBDDClient(cfg config.GIBDDAPIConfig) *GIBDDClient {
+	return &GIBDDClient{
+		url:     strings.TrimRight(cfg.BaseURL, "/") + "/licenses/check",
+		apiKey:  cfg.APIKey,
+		timeout: cfg.Timeout,
+		client:  &http.Client{},
+	}
+}
+
+// gibddRequest тело запроса проверки удостоверения
+type gibddRequest struct {
+	LicenseNumber string `json:"license_number"`
+	ExpiryDate    string `json:"expiry_date"`
+}
+
+// gibddResponse ответ API ГИБДД
+type gibddResponse struct {
+	Status     entities.LicenseRegistryStatus `json:"status"`
+	ExpiryDate string                         `json:"expiry_date,omitempty"`
+}
+
+// Name возвращает имя реестра
+func (c *GIBDDClient) Name() string {
+	return "gibdd"
+}
+
+// Check запрашивает состояние удостоверения. Ответ 404 означает, что удостоверение
+// в реестре не найдено
+func (c *GIBDDClient) Check(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error) {
+	body, err := json.Marshal(&gibddRequest{
+		LicenseNumber: req.LicenseNumber,
+		ExpiryDate:    req.ExpiryDate.Format(time.DateOnly),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode license check: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build license check request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("license check request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	result := &entities.LicenseCheckResult{CheckedAt: time.Now()}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		result.Status = entities.LicenseRegistryNotFound
+		return result, nil
+	default:
+		details, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		return nil, fmt.Errorf("license registry responded with status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(details)))
+	}
+
+	var payload gibddResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid license registry response: %w", err)
+	}
+	if !payload.Status.IsValid() {
+		return nil, fmt.Errorf("invalid license registry status: %q", payload.Status)
+	}
+	result.Status = payload.Status
+	if payload.ExpiryDate != "" {
+		expiry, err := time.Parse(time.DateOnly, payload.ExpiryDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid license registry expiry date: %w", err)
+		}
+		result.ExpiryDate = &expiry
+	}
+	return result, nil
+}
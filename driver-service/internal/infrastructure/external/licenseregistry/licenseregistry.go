@@ -0,0 +1,116 @@
+// Package licenseregistry содержит клиентов внешних реестров водительских удостоверений
+// (ГИБДД, страховщики), которыми сверяются удостоверения при подтверждении документа.
+package licenseregistry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"go.uber.org/zap"
+)
+
+// Client клиент конкретного реестра
+type Client interface {
+	// Name имя реестра для журнала аудита и логов
+	Name() string
+	// Check возвращает состояние удостоверения; ошибка означает, что реестр не ответил
+	Check(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error)
+}
+
+// Policy параметры обращения к реестру
+type Policy struct {
+	// CacheTTL как долго ответ реестра по номеру удостоверения используется повторно; 0 — без кэша
+	CacheTTL time.Duration
+	// FailureThreshold после скольких отказов подряд реестр перестает вызываться; 0 — всегда вызывать
+	FailureThreshold int
+	// OpenTimeout через сколько после размыкания цепи реестр проверяется снова
+	OpenTimeout time.Duration
+	// FailOpen подтверждать удостоверение, если реестр недоступен
+	FailOpen bool
+}
+
+// Registry обращается к реестру через размыкатель цепи и кэширует ответы по номеру удостоверения.
+// Кэшируются только ответы реестра, отказы не кэшируются
+type Registry struct {
+	client  Client
+	policy  Policy
+	breaker *circuitBreaker
+	logger  *zap.Logger
+	now     func() time.Time
+
+	// cache ответы реестра по номеру удостоверения
+	cache *services.TTLCache[string, *entities.LicenseCheckResult]
+}
+
+var _ services.LicenseRegistry = (*Registry)(nil)
+
+// NewRegistry создает обращение к реестру client
+func NewRegistry(client Client, policy Policy, logger *zap.Logger) *Registry {
+	return &Registry{
+		client:  client,
+		policy:  policy,
+		breaker: &circuitBreaker{threshold: policy.FailureThreshold, openTimeout: policy.OpenTimeout},
+		logger:  logger,
+		now:     time.Now,
+		cache:   services.NewTTLCache[string, *entities.LicenseCheckResult](policy.CacheTTL),
+	}
+}
+
+// New создает обращение к реестру из конфигурации; nil, если проверка не настроена
+func New(cfg *config.Config, logger *zap.Logger) (services.LicenseRegistry, error) {
+	registry := cfg.External.LicenseRegistry
+	policy := Policy{
+		CacheTTL:         registry.CacheTTL,
+		FailureThreshold: registry.FailureThreshold,
+		OpenTimeout:      registry.OpenTimeout,
+		FailOpen:         registry.FailOpen,
+	}
+
+	switch registry.Provider {
+	case "":
+		return nil, nil
+	case config.LicenseRegistryProviderGIBDD:
+		return NewRegistry(NewGIBDDClient(cfg.External.GIBDDAPI), policy, logger), nil
+	case config.LicenseRegistryProviderStub:
+		return NewRegistry(NewStubClient(), policy, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown license registry provider: %s", registry.Provider)
+	}
+}
+
+// CheckLicense возвращает ответ реестра из кэша или запрашивает реестр
+func (r *Registry) CheckLicense(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error) {
+	now := r.now()
+	if result, ok := r.cache.Get(req.LicenseNumber, now); ok {
+		return result, nil
+	}
+
+	if !r.breaker.allow(now) {
+		return nil, ErrCircuitOpen
+	}
+
+	result, err := r.client.Check(ctx, req)
+	if err != nil {
+		r.breaker.failure(r.now())
+		r.logger.Warn("License registry check failed",
+			zap.Error(err),
+			zap.String("registry", r.client.Name()),
+		)
+		return nil, err
+	}
+	r.breaker.success()
+
+	result.Registry = r.client.Name()
+	r.cache.Set(req.LicenseNumber, result, now)
+	return result, nil
+}
+
+// FailOpen сообщает, подтверждать ли удостоверение, если реестр недоступен
+func (r *Registry) FailOpen() bool {
+	return r.policy.FailOpen
+}
@@ -0,0 +1,134 @@
+package licenseregistry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func licenseRequest(number string) *entities.LicenseCheckRequest {
+	return &entities.LicenseCheckRequest{
+		DriverID:      uuid.New(),
+		LicenseNumber: entities.NormalizeLicenseNumber(number),
+		ExpiryDate:    time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRegistry_CachesResults(t *testing.T) {
+	ctx := context.Background()
+	client := NewStubClient()
+	client.SetStatus("77 AB 000001", entities.LicenseRegistryRevoked)
+	registry := NewRegistry(client, Policy{CacheTTL: time.Hour}, zap.NewNop())
+
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	result, err := registry.CheckLicense(ctx, licenseRequest("77 ab 000001"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.LicenseRegistryRevoked, result.Status)
+	assert.Equal(t, "stub", result.Registry)
+
+	_, err = registry.CheckLicense(ctx, licenseRequest("77AB000001"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.Calls(), "the same license is answered from cache")
+
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000002"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, client.Calls())
+
+	now = now.Add(time.Hour)
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, client.Calls(), "expired cache entry is checked again")
+}
+
+func TestRegistry_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	client := NewStubClient()
+	client.SetError(errors.New("connection refused"))
+	registry := NewRegistry(client, Policy{FailureThreshold: 2, OpenTimeout: time.Minute}, zap.NewNop())
+
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		_, err := registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, err := registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, client.Calls(), "open circuit does not call the registry")
+
+	// Пробная проверка после OpenTimeout снова размыкает цепь при отказе
+	now = now.Add(time.Minute)
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, client.Calls())
+
+	// Успешная пробная проверка закрывает цепь
+	client.SetError(nil)
+	now = now.Add(time.Minute)
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000001"))
+	require.NoError(t, err)
+	_, err = registry.CheckLicense(ctx, licenseRequest("77 AB 000002"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, client.Calls())
+}
+
+func TestGIBDDClient_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/licenses/check", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		var body gibddRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "2030-05-01", body.ExpiryDate)
+
+		switch body.LicenseNumber {
+		case "77AB000001":
+			w.Write([]byte(`{"status":"expired","expiry_date":"2024-05-01"}`))
+		case "77AB000002":
+			w.WriteHeader(http.StatusNotFound)
+		case "77AB000003":
+			w.Write([]byte(`{"status":"unknown"}`))
+		default:
+			http.Error(w, "maintenance", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGIBDDClient(config.GIBDDAPIConfig{BaseURL: server.URL + "/", APIKey: "secret", Timeout: time.Second})
+	ctx := context.Background()
+
+	result, err := client.Check(ctx, licenseRequest("77 AB 000001"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.LicenseRegistryExpired, result.Status)
+	require.NotNil(t, result.ExpiryDate)
+	assert.Equal(t, "2024-05-01", result.ExpiryDate.Format(time.DateOnly))
+
+	result, err = client.Check(ctx, licenseRequest("77 AB 000002"))
+	require.NoError(t, err)
+	assert.Equal(t, entities.LicenseRegistryNotFound, result.Status)
+
+	_, err = client.Check(ctx, licenseRequest("77 AB 000003"))
+	assert.ErrorContains(t, err, "invalid license registry status")
+
+	_, err = client.Check(ctx, licenseRequest("77 AB 000004"))
+	assert.ErrorContains(t, err, "status 503: maintenance")
+}
@@ -0,0 +1,68 @@
+package licenseregistry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+)
+
+// StubClient заглушка реестра для тестов и локальной разработки: подтверждает любые
+// удостоверения, кроме заданных через SetStatus
+type StubClient struct {
+	mu       sync.Mutex
+	statuses map[string]entities.LicenseRegistryStatus
+	err      error
+	calls    int
+}
+
+var _ Client = (*StubClient)(nil)
+
+// NewStubClient создает заглушку реестра
+func NewStubClient() *StubClient {
+	return &StubClient{statuses: make(map[string]entities.LicenseRegistryStatus)}
+}
+
+// SetStatus задает ответ реестра для номера удостоверения
+func (c *StubClient) SetStatus(licenseNumber string, status entities.LicenseRegistryStatus) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statuses[entities.NormalizeLicenseNumber(licenseNumber)] = status
+}
+
+// SetError задает ошибку, которую возвращают все проверки; nil возвращает заглушку в работу
+func (c *StubClient) SetError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.err = err
+}
+
+// Calls возвращает число выполненных проверок
+func (c *StubClient) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// Name возвращает имя реестра
+func (c *StubClient) Name() string {
+	return "stub"
+}
+
+// Check возвращает заданное состояние удостоверения
+func (c *StubClient) Check(ctx context.Context, req *entities.LicenseCheckRequest) (*entities.LicenseCheckResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	status, ok := c.statuses[req.LicenseNumber]
+	if !ok {
+		status = entities.LicenseRegistryValid
+	}
+	return &entities.LicenseCheckResult{Status: status, CheckedAt: time.Now()}, nil
+}
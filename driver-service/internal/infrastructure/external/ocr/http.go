@@ -0,0 +1,105 @@
+package ocr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// maxResponseSize ограничение размера ответа сервиса распознавания
+const maxResponseSize = 1 << 20
+
+// HTTPProvider распознает документы через HTTP API (external.ocr)
+type HTTPProvider struct {
+	url     string
+	apiKey  string
+	timeout time.Duration
+	client  *http.Client
+}
+
+var _ services.DocumentOCRProvider = (*HTTPProvider)(nil)
+
+// NewHTTPProvider создает клиента API распознавания; файл отправляется телом запроса
+// на <base_url>/documents/extract?document_type=<тип>
+func NewHTTPProvider(cfg config.OCRConfig) *HTTPProvider {
+	return &HTTPProvider{
+		url:     strings.TrimRight(cfg.BaseURL, "/") + "/documents/extract",
+		apiKey:  cfg.APIKey,
+		timeout: cfg.Timeout,
+		client:  &http.Client{},
+	}
+}
+
+// extractResponse ответ API распознавания
+type extractResponse struct {
+	DocumentNumber string  `json:"document_number"`
+	ExpiryDate     string  `json:"expiry_date"`
+	FullName       string  `json:"full_name"`
+	Confidence     float64 `json:"confidence"`
+}
+
+// Name возвращает имя сервиса распознавания
+func (p *HTTPProvider) Name() string {
+	return "http"
+}
+
+// Extract отправляет файл документа и возвращает распознанные поля
+func (p *HTTPProvider) Extract(ctx context.Context, req *entities.OCRRequest) (*entities.OCRExtraction, error) {
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	target := p.url + "?" + url.Values{"document_type": {string(req.DocumentType)}}.Encode()
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(req.Content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OCR request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", req.ContentType)
+	httpReq.Header.Set("Accept", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("OCR request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		details, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		return nil, fmt.Errorf("OCR service responded with status %d: %s",
+			resp.StatusCode, strings.TrimSpace(string(details)))
+	}
+
+	var payload extractResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid OCR response: %w", err)
+	}
+
+	extraction := &entities.OCRExtraction{
+		DocumentNumber: strings.TrimSpace(payload.DocumentNumber),
+		FullName:       strings.TrimSpace(payload.FullName),
+		Confidence:     payload.Confidence,
+	}
+	if payload.ExpiryDate != "" {
+		expiry, err := time.Parse(time.DateOnly, payload.ExpiryDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OCR expiry date: %w", err)
+		}
+		extraction.ExpiryDate = &expiry
+	}
+	return extraction, nil
+}
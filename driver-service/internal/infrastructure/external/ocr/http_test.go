@@ -0,0 +1,59 @@
+package ocr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider_Extract(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/documents/extract", r.URL.Path)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/pdf", r.Header.Get("Content-Type"))
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		switch r.URL.Query().Get("document_type") {
+		case string(entities.DocumentTypeDriverLicense):
+			assert.Equal(t, "%PDF", string(body))
+			w.Write([]byte(`{"document_number":" 77 AB 000001 ","expiry_date":"2030-05-01","full_name":"Иванов Иван","confidence":0.93}`))
+		case string(entities.DocumentTypePassport):
+			w.Write([]byte(`{"expiry_date":"01.05.2030"}`))
+		default:
+			http.Error(w, "unsupported document", http.StatusUnprocessableEntity)
+		}
+	}))
+	defer server.Close()
+
+	provider := NewHTTPProvider(config.OCRConfig{BaseURL: server.URL + "/", APIKey: "secret", Timeout: time.Second})
+	ctx := context.Background()
+	request := func(docType entities.DocumentType) *entities.OCRRequest {
+		return &entities.OCRRequest{DocumentID: uuid.New(), DocumentType: docType, ContentType: "application/pdf", Content: []byte("%PDF")}
+	}
+
+	extraction, err := provider.Extract(ctx, request(entities.DocumentTypeDriverLicense))
+	require.NoError(t, err)
+	assert.Equal(t, "77 AB 000001", extraction.DocumentNumber)
+	assert.Equal(t, "Иванов Иван", extraction.FullName)
+	assert.Equal(t, 0.93, extraction.Confidence)
+	require.NotNil(t, extraction.ExpiryDate)
+	assert.Equal(t, "2030-05-01", extraction.ExpiryDate.Format(time.DateOnly))
+
+	_, err = provider.Extract(ctx, request(entities.DocumentTypePassport))
+	assert.ErrorContains(t, err, "invalid OCR expiry date")
+
+	_, err = provider.Extract(ctx, request(entities.DocumentTypeInsurance))
+	assert.ErrorContains(t, err, "status 422: unsupported document")
+}
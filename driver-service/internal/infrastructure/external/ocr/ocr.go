@@ -0,0 +1,25 @@
+// Package ocr содержит клиентов сервисов распознавания документов, которыми
+// заполняются подсказки верификатору после загрузки документа.
+package ocr
+
+import (
+	"fmt"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/services"
+)
+
+// New создает клиента сервиса распознавания из конфигурации; nil, если распознавание не настроено
+func New(cfg *config.Config) (services.DocumentOCRProvider, error) {
+	ocr := cfg.External.OCR
+	switch ocr.Provider {
+	case "":
+		return nil, nil
+	case config.OCRProviderHTTP:
+		return NewHTTPProvider(ocr), nil
+	case config.OCRProviderStub:
+		return NewStubProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown OCR provider: %s", ocr.Provider)
+	}
+}
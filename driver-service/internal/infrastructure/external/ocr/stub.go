@@ -0,0 +1,70 @@
+package ocr
+
+import (
+	"context"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+// StubProvider заглушка распознавания для тестов и локальной разработки: возвращает
+// заданные через SetExtraction поля, а для остальных документов — пустой результат
+type StubProvider struct {
+	mu          sync.Mutex
+	extractions map[uuid.UUID]*entities.OCRExtraction
+	err         error
+	calls       int
+}
+
+var _ services.DocumentOCRProvider = (*StubProvider)(nil)
+
+// NewStubProvider создает заглушку распознавания
+func NewStubProvider() *StubProvider {
+	return &StubProvider{extractions: make(map[uuid.UUID]*entities.OCRExtraction)}
+}
+
+// SetExtraction задает результат распознавания документа
+func (p *StubProvider) SetExtraction(documentID uuid.UUID, extraction *entities.OCRExtraction) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.extractions[documentID] = extraction
+}
+
+// SetError задает ошибку, которую возвращают все распознавания; nil возвращает заглушку в работу
+func (p *StubProvider) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+// Calls возвращает число выполненных распознаваний
+func (p *StubProvider) Calls() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.calls
+}
+
+// Name возвращает имя сервиса распознавания
+func (p *StubProvider) Name() string {
+	return "stub"
+}
+
+// Extract возвращает заданный результат распознавания
+func (p *StubProvider) Extract(ctx context.Context, req *entities.OCRRequest) (*entities.OCRExtraction, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if extraction, ok := p.extractions[req.DocumentID]; ok {
+		clone := *extraction
+		return &clone, nil
+	}
+	return &entities.OCRExtraction{}, nil
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CheckFunc проверка зависимости; ошибка означает, что зависимость недоступна
+type CheckFunc func(ctx context.Context) error
+
+// Состояния зависимости и экземпляра
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusReady    = "ready"
+	StatusNotReady = "not_ready"
+)
+
+// DependencyStatus итог проверки зависимости
+type DependencyStatus struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report итог проверки всех зависимостей
+type Report struct {
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+}
+
+// Ready сообщает, доступны ли все зависимости
+func (r Report) Ready() bool {
+	return r.Status == StatusReady
+}
+
+// check зарегистрированная проверка
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker проверяет зависимости экземпляра для пробы готовности. Проверки выполняются
+// параллельно, каждая ограничена таймаутом; экземпляр готов, если доступны все зависимости
+type Checker struct {
+	timeout time.Duration
+	checks  []check
+}
+
+// NewChecker создает проверку зависимостей с таймаутом одной проверки
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add регистрирует проверку зависимости
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// Run выполняет проверки и возвращает их итог в порядке имен зависимостей
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status:       StatusReady,
+		CheckedAt:    time.Now().UTC(),
+		Dependencies: make([]DependencyStatus, len(c.checks)),
+	}
+
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			report.Dependencies[i] = c.run(ctx, chk)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != StatusUp {
+			report.Status = StatusNotReady
+		}
+	}
+	sort.Slice(report.Dependencies, func(i, j int) bool {
+		return report.Dependencies[i].Name < report.Dependencies[j].Name
+	})
+	return report
+}
+
+// run выполняет одну проверку с таймаутом. Проверка, не уважающая контекст, по таймауту
+// считается неуспешной, а ее результат отбрасывается
+func (c *Checker) run(ctx context.Context, chk check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	started := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- chk.fn(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	status := DependencyStatus{
+		Name:      chk.name,
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(started).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
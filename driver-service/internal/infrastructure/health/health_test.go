@@ -0,0 +1,108 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Run(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+	checker.Add("nats", func(ctx context.Context) error { return errors.New("nats connection is RECONNECTING") })
+
+	report := checker.Run(context.Background())
+	assert.False(t, report.Ready())
+	assert.Equal(t, StatusNotReady, report.Status)
+	require.Len(t, report.Dependencies, 2)
+
+	// Зависимости упорядочены по имени
+	assert.Equal(t, "nats", report.Dependencies[0].Name)
+	assert.Equal(t, StatusDown, report.Dependencies[0].Status)
+	assert.Equal(t, "nats connection is RECONNECTING", report.Dependencies[0].Error)
+	assert.Equal(t, "postgres", report.Dependencies[1].Name)
+	assert.Equal(t, StatusUp, report.Dependencies[1].Status)
+	assert.Empty(t, report.Dependencies[1].Error)
+
+	assert.True(t, NewChecker(time.Second).Run(context.Background()).Ready(), "no dependencies")
+}
+
+func TestChecker_RunTimeout(t *testing.T) {
+	checker := NewChecker(20 * time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	// Проверка, не уважающая контекст, не задерживает пробу дольше таймаута
+	checker.Add("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	started := time.Now()
+	report := checker.Run(context.Background())
+	assert.Less(t, time.Since(started), time.Second)
+	require.Len(t, report.Dependencies, 1)
+	assert.Equal(t, StatusDown, report.Dependencies[0].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Dependencies[0].Error)
+	assert.GreaterOrEqual(t, report.Dependencies[0].LatencyMs, float64(20))
+}
+
+// fakeRedis отвечает на команды RESP заранее заданными строками
+func fakeRedis(t *testing.T, replies map[string]string) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					// *N, затем пары $len и значение; имя команды — первое значение
+					header, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					var args []string
+					for i := 0; i < int(header[1]-'0'); i++ {
+						reader.ReadString('\n')
+						arg, _ := reader.ReadString('\n')
+						args = append(args, strings.TrimRight(arg, "\r\n"))
+					}
+					conn.Write([]byte(replies[args[0]] + "\r\n"))
+				}
+			}(conn)
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestRedisPing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	addr := fakeRedis(t, map[string]string{"AUTH": "+OK", "SELECT": "+OK", "PING": "+PONG"})
+	assert.NoError(t, RedisPing(addr, "secret", 2)(ctx))
+
+	addr = fakeRedis(t, map[string]string{"PING": "-NOAUTH Authentication required."})
+	err := RedisPing(addr, "", 0)(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NOAUTH")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := listener.Addr().String()
+	listener.Close()
+	assert.Error(t, RedisPing(closed, "", 0)(ctx))
+}
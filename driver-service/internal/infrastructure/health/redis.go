@@ -0,0 +1,61 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// RedisPing возвращает проверку Redis командой PING по протоколу RESP. Сервис не держит
+// клиент Redis, поэтому проверка открывает отдельное соединение на каждый запуск
+func RedisPing(addr, password string, database int) CheckFunc {
+	return func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return fmt.Errorf("redis dial failed: %w", err)
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+
+		reader := bufio.NewReader(conn)
+		if password != "" {
+			if err := redisCommand(conn, reader, "+OK", "AUTH", password); err != nil {
+				return err
+			}
+		}
+		if database != 0 {
+			if err := redisCommand(conn, reader, "+OK", "SELECT", strconv.Itoa(database)); err != nil {
+				return err
+			}
+		}
+		return redisCommand(conn, reader, "+PONG", "PING")
+	}
+}
+
+// redisCommand отправляет команду и сверяет однострочный ответ с ожидаемым
+func redisCommand(conn net.Conn, reader *bufio.Reader, expected string, args ...string) error {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(command.String())); err != nil {
+		return fmt.Errorf("redis %s failed: %w", args[0], err)
+	}
+
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("redis %s failed: %w", args[0], err)
+	}
+	reply = strings.TrimRight(reply, "\r\n")
+	if reply != expected {
+		return fmt.Errorf("redis %s failed: %s", args[0], strings.TrimPrefix(reply, "-"))
+	}
+	return nil
+}
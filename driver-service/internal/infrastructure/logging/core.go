@@ -0,0 +1,76 @@
+package logging
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Options настройки маскирования персональных данных в логах
+type Options struct {
+	Mode Mode
+	// Fields дополнительные поля, значения которых считаются персональными данными
+	Fields []string
+	// RawDebug пропускает записи уровня debug без маскирования (только для локальной разработки)
+	RawDebug bool
+}
+
+// redactingCore обертка над zapcore.Core, маскирующая персональные данные перед записью
+type redactingCore struct {
+	zapcore.Core
+	// raw исходное ядро для записей уровня debug, nil если RawDebug выключен
+	raw      zapcore.Core
+	redactor *Redactor
+}
+
+// NewCore оборачивает ядро логгера маскированием персональных данных
+func NewCore(core zapcore.Core, opts Options) zapcore.Core {
+	if opts.Mode == ModeOff {
+		return core
+	}
+
+	c := &redactingCore{
+		Core:     core,
+		redactor: NewRedactor(opts.Mode, opts.Fields),
+	}
+	if opts.RawDebug {
+		c.raw = core
+	}
+	return c
+}
+
+// WrapCore возвращает опцию zap, подключающую маскирование при сборке логгера
+func WrapCore(opts Options) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return NewCore(core, opts)
+	})
+}
+
+// With добавляет контекстные поля; в маскируемое ядро они попадают уже замаскированными
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &redactingCore{
+		Core:     c.Core.With(c.redactor.RedactFields(fields)),
+		redactor: c.redactor,
+	}
+	if c.raw != nil {
+		clone.raw = c.raw.With(fields)
+	}
+	return clone
+}
+
+// Check регистрирует ядро для записи, чтобы Write получил управление
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write маскирует сообщение и поля записи и передает ее исходному ядру
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if c.raw != nil && entry.Level == zapcore.DebugLevel {
+		return c.raw.Write(entry, fields)
+	}
+
+	entry.Message = c.redactor.RedactString(entry.Message)
+	return c.Core.Write(entry, c.redactor.RedactFields(fields))
+}
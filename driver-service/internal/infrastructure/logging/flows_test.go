@@ -0,0 +1,142 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/infrastructure/logging"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggingEventPublisher публикует события в лог, как заглушка в cmd/server
+type loggingEventPublisher struct {
+	logger *zap.Logger
+}
+
+func (p *loggingEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	p.logger.Info("Publishing driver event",
+		zap.String("event_type", eventType),
+		zap.String("driver_id", driverID.String()),
+		zap.Any("data", data),
+	)
+	return nil
+}
+
+var piiValues = []string{"+79001234567", "ivan.petrov@example.com", "987654", "77AB123456"}
+
+func newTestDriver() *entities.Driver {
+	return &entities.Driver{
+		Phone:          "+79001234567",
+		Email:          "ivan.petrov@example.com",
+		FirstName:      "Иван",
+		LastName:       "Петров",
+		BirthDate:      time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC),
+		PassportSeries: "4510",
+		PassportNumber: "987654",
+		LicenseNumber:  "77AB123456",
+		LicenseExpiry:  time.Now().AddDate(2, 0, 0),
+	}
+}
+
+func assertNoPII(t *testing.T, out string) {
+	t.Helper()
+	require.NotEmpty(t, out)
+	for _, value := range piiValues {
+		assert.NotContains(t, out, value)
+	}
+}
+
+func TestNoPIIInLogs_DriverRegistration(t *testing.T) {
+	for _, mode := range []logging.Mode{logging.ModeStrict, logging.ModePartial} {
+		t.Run(string(mode), func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			logger := zap.New(logging.NewCore(zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.AddSync(buf),
+				zapcore.InfoLevel,
+			), logging.Options{Mode: mode}))
+
+			ctx := context.Background()
+			service := services.NewDriverService(memory.NewDriverRepository(), memory.NewDocumentRepository(), nil,
+				&loggingEventPublisher{logger: logger}, logger)
+
+			_, err := service.CreateDriver(ctx, newTestDriver())
+			require.NoError(t, err)
+
+			// Повторная регистрация, поиск несуществующих водителей и невалидные данные
+			_, err = service.CreateDriver(ctx, newTestDriver())
+			require.Error(t, err)
+			_, err = service.GetDriverByPhone(ctx, "+79007654321")
+			require.Error(t, err)
+			_, err = service.GetDriverByEmail(ctx, "nobody@example.com")
+			require.Error(t, err)
+			invalid := newTestDriver()
+			invalid.FirstName = ""
+			_, err = service.CreateDriver(ctx, invalid)
+			require.Error(t, err)
+
+			out := buf.String()
+			assertNoPII(t, out)
+			assert.NotContains(t, out, "+79007654321")
+			assert.NotContains(t, out, "nobody@example.com")
+		})
+	}
+}
+
+func TestNoPIIInLogs_HTTPRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	buf := &bytes.Buffer{}
+	logger := zap.New(logging.NewCore(zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(buf),
+		zapcore.InfoLevel,
+	), logging.Options{Mode: logging.ModePartial}))
+
+	router := gin.New()
+	router.Use(middleware.RequestLogger(logger, logging.NewRedactor(logging.ModePartial, nil), middleware.RequestLogConfig{
+		SampleRate:     1,
+		BodySampleRate: 1,
+		MaxBodySize:    4096,
+	}))
+	router.GET("/api/v1/drivers/lookup/:phone", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	router.POST("/api/v1/drivers", func(c *gin.Context) {
+		var body map[string]interface{}
+		require.NoError(t, c.ShouldBindJSON(&body))
+		c.JSON(http.StatusCreated, body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/drivers/lookup/+79001234567?email=ivan.petrov@example.com", nil)
+	req.Header.Set("User-Agent", "support-console ivan.petrov@example.com")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Тела логируются замаскированными, а обработчик получает тело запроса целиком
+	body := `{"phone":"+79001234567","first_name":"Иван","passport_number":"987654","license_number":"77AB123456"}`
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/drivers", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), "77AB123456")
+
+	out := buf.String()
+	assertNoPII(t, out)
+	assert.Contains(t, out, `"route":"/api/v1/drivers"`)
+	assert.Contains(t, out, `"first_name":"Иван"`)
+}
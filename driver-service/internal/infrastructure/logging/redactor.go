@@ -0,0 +1,276 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Mode строгость маскирования персональных данных
+type Mode string
+
+const (
+	// ModeStrict полностью заменяет персональные данные заглушкой
+	ModeStrict Mode = "strict"
+	// ModePartial оставляет часть значения, достаточную для поиска по логам (+7******4567, i***@example.com)
+	ModePartial Mode = "partial"
+	// ModeOff отключает маскирование (недопустимо в production)
+	ModeOff Mode = "off"
+)
+
+// Placeholder заглушка, которой заменяются значения в строгом режиме
+const Placeholder = "[REDACTED]"
+
+// DefaultFields поля логов, значения которых всегда считаются персональными данными
+var DefaultFields = []string{
+	"phone",
+	"email",
+	"passport",
+	"passport_series",
+	"passport_number",
+	"license",
+	"license_number",
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// phonePattern номера в формате E.164 и российские номера с разделителями
+	phonePattern = regexp.MustCompile(`\+\d{10,14}\b|(?:\+7|\b8)[\s\-(]*\d{3}[\s\-)]*\d{3}[\s\-]*\d{2}[\s\-]*\d{2}\b`)
+	// passportPattern серия и номер паспорта РФ: 1234 567890, 12 34 567890
+	passportPattern = regexp.MustCompile(`\b\d{2}\s?\d{2}\s\d{6}\b`)
+)
+
+// piiKind вид персональных данных, определяющий способ частичного маскирования
+type piiKind int
+
+const (
+	piiKindGeneric piiKind = iota
+	piiKindPhone
+	piiKindEmail
+	piiKindDocument
+)
+
+// Redactor маскирует персональные данные в сообщениях и полях логов
+type Redactor struct {
+	mode   Mode
+	fields map[string]bool
+}
+
+// NewRedactor создает Redactor; к DefaultFields добавляются поля из fields
+func NewRedactor(mode Mode, fields []string) *Redactor {
+	r := &Redactor{
+		mode:   mode,
+		fields: make(map[string]bool, len(DefaultFields)+len(fields)),
+	}
+	for _, field := range append(append([]string{}, DefaultFields...), fields...) {
+		r.fields[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+	return r
+}
+
+// RedactString маскирует телефоны, email и паспортные данные в произвольном тексте
+func (r *Redactor) RedactString(s string) string {
+	if r.mode == ModeOff || s == "" {
+		return s
+	}
+
+	s = emailPattern.ReplaceAllStringFunc(s, func(match string) string {
+		return r.mask(piiKindEmail, match)
+	})
+	s = phonePattern.ReplaceAllStringFunc(s, func(match string) string {
+		return r.mask(piiKindPhone, match)
+	})
+	return passportPattern.ReplaceAllStringFunc(s, func(match string) string {
+		return r.mask(piiKindDocument, match)
+	})
+}
+
+// RedactFields возвращает копию полей с замаскированными персональными данными
+func (r *Redactor) RedactFields(fields []zapcore.Field) []zapcore.Field {
+	if r.mode == ModeOff || len(fields) == 0 {
+		return fields
+	}
+
+	redacted := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		redacted = append(redacted, r.redactField(field)...)
+	}
+	return redacted
+}
+
+// RedactValue маскирует значение, записанное под ключом key: вложенные карты и списки
+// маскируются по своим ключам
+func (r *Redactor) RedactValue(key string, value interface{}) interface{} {
+	if r.mode == ModeOff {
+		return value
+	}
+	return r.redactValue(key, value)
+}
+
+// RedactJSON разбирает JSON документ и маскирует в нем персональные данные по именам полей
+// и по содержимому строк. false — data не является корректным JSON
+func (r *Redactor) RedactJSON(data []byte) (interface{}, bool) {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, false
+	}
+	return r.RedactValue("", value), true
+}
+
+// redactField маскирует одно поле. Составные значения (ошибки, объекты, структуры)
+// сначала кодируются в карту, чтобы персональные данные нашлись и во вложенных ключах
+func (r *Redactor) redactField(field zapcore.Field) []zapcore.Field {
+	switch field.Type {
+	case zapcore.StringType:
+		return []zapcore.Field{zap.String(field.Key, r.redactValue(field.Key, field.String).(string))}
+	case zapcore.NamespaceType, zapcore.SkipType:
+		return []zapcore.Field{field}
+	case zapcore.BoolType, zapcore.DurationType, zapcore.TimeType, zapcore.TimeFullType,
+		zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type,
+		zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type, zapcore.UintptrType,
+		zapcore.Float64Type, zapcore.Float32Type, zapcore.Complex128Type, zapcore.Complex64Type:
+		if r.isPIIKey(field.Key) {
+			return []zapcore.Field{zap.String(field.Key, Placeholder)}
+		}
+		return []zapcore.Field{field}
+	}
+
+	encoder := zapcore.NewMapObjectEncoder()
+	field.AddTo(encoder)
+
+	keys := make([]string, 0, len(encoder.Fields))
+	for key := range encoder.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	redacted := make([]zapcore.Field, 0, len(keys))
+	for _, key := range keys {
+		redacted = append(redacted, zap.Any(key, r.redactValue(key, encoder.Fields[key])))
+	}
+	return redacted
+}
+
+// redactValue рекурсивно маскирует значение поля с учетом его ключа
+func (r *Redactor) redactValue(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		if r.isPIIKey(key) {
+			return r.mask(kindForKey(key), v)
+		}
+		return r.RedactString(v)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		if r.isPIIKey(key) {
+			return Placeholder
+		}
+		return v
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			redacted[k] = r.redactValue(k, item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactValue(key, item)
+		}
+		return redacted
+	}
+
+	// Произвольные структуры приводятся к JSON-представлению, по которому видны имена полей
+	data, err := json.Marshal(value)
+	if err != nil {
+		return r.redactValue(key, fmt.Sprint(value))
+	}
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return r.redactValue(key, string(data))
+	}
+	return r.redactValue(key, generic)
+}
+
+// isPIIKey проверяет, относится ли ключ к персональным данным
+func (r *Redactor) isPIIKey(key string) bool {
+	return r.fields[strings.ToLower(key)]
+}
+
+// kindForKey определяет вид персональных данных по имени поля
+func kindForKey(key string) piiKind {
+	key = strings.ToLower(key)
+	switch {
+	case strings.Contains(key, "phone"):
+		return piiKindPhone
+	case strings.Contains(key, "email"):
+		return piiKindEmail
+	case strings.Contains(key, "passport"), strings.Contains(key, "license"):
+		return piiKindDocument
+	default:
+		return piiKindGeneric
+	}
+}
+
+// mask маскирует значение в соответствии с режимом
+func (r *Redactor) mask(kind piiKind, value string) string {
+	if r.mode == ModeOff || value == "" {
+		return value
+	}
+	if r.mode == ModeStrict {
+		return Placeholder
+	}
+
+	switch kind {
+	case piiKindPhone:
+		return maskDigits(value, 4)
+	case piiKindEmail:
+		at := strings.LastIndex(value, "@")
+		if at <= 0 {
+			return Placeholder
+		}
+		return string([]rune(value[:at])[0]) + "***" + value[at:]
+	case piiKindDocument:
+		return maskTail(value, 4)
+	default:
+		return string([]rune(value)[0]) + "***"
+	}
+}
+
+// maskDigits заменяет цифры звездочками, оставляя последние keep цифр и форматирование
+func maskDigits(value string, keep int) string {
+	digits := 0
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			digits++
+		}
+	}
+
+	var b strings.Builder
+	seen := 0
+	for _, c := range value {
+		if c >= '0' && c <= '9' {
+			seen++
+			if seen <= digits-keep {
+				b.WriteRune('*')
+				continue
+			}
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// maskTail заменяет все символы, кроме последних keep, звездочками
+func maskTail(value string, keep int) string {
+	runes := []rune(value)
+	if len(runes) <= keep {
+		return Placeholder
+	}
+	return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+}
@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newBufferLogger создает логгер, пишущий JSON в буфер через маскирующее ядро
+func newBufferLogger(opts Options) (*zap.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(buf),
+		zapcore.DebugLevel,
+	)
+	return zap.New(NewCore(core, opts)), buf
+}
+
+func TestRedactor_RedactString(t *testing.T) {
+	partial := NewRedactor(ModePartial, nil)
+	strict := NewRedactor(ModeStrict, nil)
+
+	text := "driver +79001234567 (ivan.petrov@example.com), паспорт 4510 123456, тел. 8 (900) 765-43-21"
+
+	assert.Equal(t,
+		"driver +*******4567 (i***@example.com), паспорт *******3456, тел. * (***) ***-43-21",
+		partial.RedactString(text))
+	assert.Equal(t,
+		"driver [REDACTED] ([REDACTED]), паспорт [REDACTED], тел. [REDACTED]",
+		strict.RedactString(text))
+
+	// Идентификаторы и суммы не похожи на персональные данные
+	id := "order 5f0c8a3e-1d2b-4c5d-8e9f-0a1b2c3d4e5f amount 1500.50"
+	assert.Equal(t, id, partial.RedactString(id))
+}
+
+func TestRedactingCore_Fields(t *testing.T) {
+	logger, buf := newBufferLogger(Options{Mode: ModePartial, Fields: []string{"first_name"}})
+
+	logger.With(zap.String("phone", "+79001234567")).Info("Driver lookup for ivan@example.com",
+		zap.String("email", "ivan@example.com"),
+		zap.String("first_name", "Иван"),
+		zap.String("passport_number", "123456"),
+		zap.Error(errors.New("driver with phone +79001234567 not found")),
+		zap.Any("data", map[string]interface{}{
+			"email":  "ivan@example.com",
+			"nested": map[string]interface{}{"phone": "+79001234567"},
+		}),
+		zap.Int("total_trips", 42),
+	)
+
+	out := buf.String()
+	assert.NotContains(t, out, "+79001234567")
+	assert.NotContains(t, out, "ivan@example.com")
+	assert.NotContains(t, out, "Иван")
+	assert.NotContains(t, out, "123456")
+	assert.Contains(t, out, `"phone":"+*******4567"`)
+	assert.Contains(t, out, `"email":"i***@example.com"`)
+	assert.Contains(t, out, `"first_name":"И***"`)
+	assert.Contains(t, out, `"total_trips":42`)
+}
+
+func TestRedactingCore_RawDebug(t *testing.T) {
+	logger, buf := newBufferLogger(Options{Mode: ModeStrict, RawDebug: true})
+
+	logger.Debug("debug", zap.String("phone", "+79001234567"))
+	assert.Contains(t, buf.String(), "+79001234567", "debug entries keep full values when allowed")
+
+	buf.Reset()
+	logger.Info("info", zap.String("phone", "+79001234567"))
+	assert.NotContains(t, buf.String(), "+79001234567")
+	assert.Contains(t, buf.String(), Placeholder)
+}
+
+func TestRedactingCore_Off(t *testing.T) {
+	logger, buf := newBufferLogger(Options{Mode: ModeOff})
+
+	logger.Info("info", zap.String("phone", "+79001234567"))
+	assert.Contains(t, buf.String(), "+79001234567")
+}
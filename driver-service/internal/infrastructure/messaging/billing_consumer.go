@@ -0,0 +1,127 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// Входящие события биллинга
+const (
+	SubjectBillingHoldPlaced   = "billing.hold.placed"
+	SubjectBillingHoldReleased = "billing.hold.released"
+)
+
+// queueGroup группа подписчиков, чтобы событие обрабатывал один экземпляр сервиса
+const queueGroup = "driver-service"
+
+// handleTimeout ограничение времени обработки одного события
+const handleTimeout = 10 * time.Second
+
+// BillingHoldEvent событие биллинга об установке или снятии блокировки водителя
+type BillingHoldEvent struct {
+	DriverID uuid.UUID `json:"driver_id"`
+	// Reason категория причины: chargeback, fraud_review и т.п.
+	Reason string `json:"reason,omitempty"`
+	// Details подробности для биллинга; в сервисе водителей не сохраняются
+	Details    string    `json:"details,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// BillingConsumer подписчик на события биллинга
+type BillingConsumer struct {
+	conn          *nats.Conn
+	driverService services.DriverService
+	logger        *zap.Logger
+	subscriptions []*nats.Subscription
+}
+
+// NewBillingConsumer создает нового подписчика на события биллинга
+func NewBillingConsumer(conn *nats.Conn, driverService services.DriverService, logger *zap.Logger) *BillingConsumer {
+	return &BillingConsumer{
+		conn:          conn,
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// Start подписывается на события биллинга
+func (c *BillingConsumer) Start() error {
+	handlers := map[string]func(ctx context.Context, event *BillingHoldEvent) error{
+		SubjectBillingHoldPlaced: func(ctx context.Context, event *BillingHoldEvent) error {
+			return c.driverService.PlacePaymentHold(ctx, event.DriverID, event.Reason)
+		},
+		SubjectBillingHoldReleased: func(ctx context.Context, event *BillingHoldEvent) error {
+			return c.driverService.ReleasePaymentHold(ctx, event.DriverID)
+		},
+	}
+
+	for subject, handle := range handlers {
+		sub, err := c.conn.QueueSubscribe(subject, queueGroup, c.wrap(subject, handle))
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+		}
+		c.subscriptions = append(c.subscriptions, sub)
+	}
+
+	c.logger.Info("Billing event consumer started")
+	return nil
+}
+
+// Stop отписывается от событий, дожидаясь обработки полученных сообщений
+func (c *BillingConsumer) Stop() {
+	for _, sub := range c.subscriptions {
+		if err := sub.Drain(); err != nil {
+			c.logger.Error("Failed to drain subscription",
+				zap.Error(err),
+				zap.String("subject", sub.Subject),
+			)
+		}
+	}
+	c.subscriptions = nil
+}
+
+// wrap декодирует сообщение и вызывает обработчик события
+func (c *BillingConsumer) wrap(subject string, handle func(ctx context.Context, event *BillingHoldEvent) error) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		var event BillingHoldEvent
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			c.logger.Error("Failed to decode billing event",
+				zap.Error(err),
+				zap.String("subject", subject),
+			)
+			return
+		}
+
+		if event.DriverID == uuid.Nil {
+			c.logger.Warn("Billing event without driver ID", zap.String("subject", subject))
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), handleTimeout)
+		defer cancel()
+
+		if err := handle(ctx, &event); err != nil {
+			if err == entities.ErrDriverNotFound {
+				c.logger.Warn("Billing event for unknown driver",
+					zap.String("subject", subject),
+					zap.String("driver_id", event.DriverID.String()),
+				)
+				return
+			}
+			c.logger.Error("Failed to handle billing event",
+				zap.Error(err),
+				zap.String("subject", subject),
+				zap.String("driver_id", event.DriverID.String()),
+			)
+		}
+	}
+}
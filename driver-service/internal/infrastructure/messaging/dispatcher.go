@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"context"
+	"sync"
+
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EventHandlerFunc внутренний подписчик на события водителя
+type EventHandlerFunc func(ctx context.Context, eventType string, driverID uuid.UUID) error
+
+// EventDispatcher публикует события водителя и передает их внутренним подписчикам.
+// Подписчики вызываются синхронно после публикации: их ошибки логируются и не
+// возвращаются источнику события, действие которого уже выполнено
+type EventDispatcher struct {
+	next   services.EventPublisher
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]EventHandlerFunc
+}
+
+var _ services.EventPublisher = (*EventDispatcher)(nil)
+
+// NewEventDispatcher создает новый EventDispatcher поверх внешнего издателя
+func NewEventDispatcher(next services.EventPublisher, logger *zap.Logger) *EventDispatcher {
+	return &EventDispatcher{
+		next:     next,
+		logger:   logger,
+		handlers: make(map[string][]EventHandlerFunc),
+	}
+}
+
+// Subscribe подписывает обработчик на перечисленные типы событий
+func (d *EventDispatcher) Subscribe(handler EventHandlerFunc, eventTypes ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, eventType := range eventTypes {
+		d.handlers[eventType] = append(d.handlers[eventType], handler)
+	}
+}
+
+// PublishDriverEvent публикует событие и вызывает подписчиков на его тип
+func (d *EventDispatcher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	if !services.IsCataloguedEvent(eventType) {
+		d.logger.Warn("Published event is missing from the event catalog", zap.String("event_type", eventType))
+	}
+
+	err := d.next.PublishDriverEvent(ctx, eventType, driverID, data)
+
+	d.mu.RLock()
+	handlers := d.handlers[eventType]
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if herr := handler(ctx, eventType, driverID); herr != nil {
+			d.logger.Error("Driver event handler failed",
+				zap.Error(herr),
+				zap.String("event_type", eventType),
+				zap.String("driver_id", driverID.String()),
+			)
+		}
+	}
+
+	return err
+}
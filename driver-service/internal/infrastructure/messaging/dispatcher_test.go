@@ -0,0 +1,46 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type countingPublisher struct {
+	published []string
+}
+
+func (p *countingPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	p.published = append(p.published, eventType)
+	return nil
+}
+
+func TestEventDispatcher_PublishDriverEvent(t *testing.T) {
+	next := &countingPublisher{}
+	dispatcher := NewEventDispatcher(next, zap.NewNop())
+	driverID := uuid.New()
+
+	var handled []string
+	dispatcher.Subscribe(func(ctx context.Context, eventType string, id uuid.UUID) error {
+		assert.Equal(t, driverID, id)
+		handled = append(handled, eventType)
+		return nil
+	}, "driver.document.verified", "driver.document.rejected")
+	// Ошибка подписчика не мешает остальным и не возвращается издателю
+	dispatcher.Subscribe(func(ctx context.Context, eventType string, id uuid.UUID) error {
+		return errors.New("handler failed")
+	}, "driver.document.verified")
+
+	ctx := context.Background()
+	require.NoError(t, dispatcher.PublishDriverEvent(ctx, "driver.document.verified", driverID, nil))
+	require.NoError(t, dispatcher.PublishDriverEvent(ctx, "driver.status.changed", driverID, nil))
+	require.NoError(t, dispatcher.PublishDriverEvent(ctx, "driver.document.rejected", driverID, nil))
+
+	assert.Equal(t, []string{"driver.document.verified", "driver.status.changed", "driver.document.rejected"}, next.published)
+	assert.Equal(t, []string{"driver.document.verified", "driver.document.rejected"}, handled)
+}
@@ -0,0 +1,57 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+
+	"driver-service/internal/config"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// Connect устанавливает подключение к NATS.
+// Если сервер недоступен при старте, клиент продолжает попытки подключения в фоне.
+func Connect(cfg *config.NATSConfig, logger *zap.Logger) (*nats.Conn, error) {
+	options := []nats.Option{
+		nats.Name(cfg.ClientID),
+		nats.Timeout(cfg.ConnectTimeout),
+		nats.ReconnectWait(cfg.ReconnectDelay),
+		nats.MaxReconnects(cfg.MaxReconnect),
+		nats.PingInterval(cfg.PingInterval),
+		nats.MaxPingsOutstanding(cfg.MaxPingsOut),
+		nats.RetryOnFailedConnect(true),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn("Disconnected from NATS", zap.Error(err))
+		}),
+		nats.ReconnectHandler(func(c *nats.Conn) {
+			logger.Info("Reconnected to NATS", zap.String("url", c.ConnectedUrl()))
+		}),
+	}
+	if cfg.User != "" {
+		options = append(options, nats.UserInfo(cfg.User, cfg.Password))
+	}
+	if cfg.Token != "" {
+		options = append(options, nats.Token(cfg.Token))
+	}
+
+	conn, err := nats.Connect(cfg.URL, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	logger.Info("NATS connection initialized", zap.String("url", cfg.URL))
+	return conn, nil
+}
+
+// Ping проверяет подключение к NATS: клиент должен быть подключен и получить ответ сервера
+// на PING до истечения ctx
+func Ping(ctx context.Context, conn *nats.Conn) error {
+	if status := conn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	if err := conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats ping failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package notifications содержит каналы доставки уведомлений водителям: SMS-шлюз,
+// push-шлюз и почту.
+package notifications
+
+import (
+	"fmt"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/services"
+)
+
+// maxResponseSize ограничение размера ответа шлюза, читаемого для диагностики
+const maxResponseSize = 1 << 12
+
+// New создает каналы, перечисленные в notifications.providers
+func New(cfg config.NotificationsConfig, sms config.SMSAPIConfig) ([]services.NotificationProvider, error) {
+	providers := make([]services.NotificationProvider, 0, len(cfg.Providers))
+	for _, name := range cfg.Providers {
+		switch name {
+		case config.NotificationChannelSMS:
+			providers = append(providers, NewSMSProvider(sms.BaseURL, sms.APIKey, sms.From, sms.Timeout))
+		case config.NotificationChannelPush:
+			providers = append(providers, NewPushProvider(cfg.Push.URL, cfg.Push.APIKey, cfg.Push.Timeout))
+		case config.NotificationChannelEmail:
+			providers = append(providers, NewEmailProvider(cfg.Email))
+		default:
+			return nil, fmt.Errorf("unknown notification provider: %s", name)
+		}
+	}
+	return providers, nil
+}
@@ -0,0 +1,88 @@
+package notifications
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMessage = entities.NotificationMessage{
+	Template: "driver.blocked",
+	Subject:  "Аккаунт заблокирован",
+	Body:     "Иван, ваш аккаунт заблокирован. Свяжитесь с поддержкой.",
+}
+
+func TestSMSProvider_PostsMessage(t *testing.T) {
+	var received smsMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages" || r.Header.Get("Authorization") != "Bearer sms-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	provider := NewSMSProvider(server.URL+"/", "sms-key", "TaxiService", time.Second)
+	recipient := entities.NotificationRecipient{DriverID: uuid.New(), Phone: "+79000000001"}
+	require.NoError(t, provider.Send(context.Background(), recipient, testMessage))
+	assert.Equal(t, smsMessage{To: "+79000000001", From: "TaxiService", Text: testMessage.Body}, received)
+
+	assert.Error(t, provider.Send(context.Background(), entities.NotificationRecipient{}, testMessage))
+	assert.Error(t, NewSMSProvider(server.URL, "wrong", "", time.Second).Send(context.Background(), recipient, testMessage))
+}
+
+func TestPushProvider_AddressesDriver(t *testing.T) {
+	var received pushMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	driverID := uuid.New()
+	provider := NewPushProvider(server.URL, "", time.Second)
+	require.NoError(t, provider.Send(context.Background(), entities.NotificationRecipient{DriverID: driverID}, testMessage))
+	assert.Equal(t, driverID.String(), received.DriverID)
+	assert.Equal(t, testMessage.Subject, received.Title)
+	assert.Equal(t, "driver.blocked", received.Data["template"])
+}
+
+func TestBuildEmail_EncodesUTF8(t *testing.T) {
+	raw := string(buildEmail("noreply@example.com", "driver@example.com", testMessage))
+
+	headers, body, found := strings.Cut(raw, "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, "Subject: =?utf-8?q?")
+	assert.Contains(t, headers, "X-Notification-Template: driver.blocked")
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	require.NoError(t, err)
+	assert.Equal(t, testMessage.Body, string(decoded))
+}
+
+func TestNew_SelectsConfiguredProviders(t *testing.T) {
+	providers, err := New(config.NotificationsConfig{
+		Providers: []string{config.NotificationChannelSMS, config.NotificationChannelEmail},
+	}, config.SMSAPIConfig{BaseURL: "https://sms.example.com", Timeout: time.Second})
+	require.NoError(t, err)
+	require.Len(t, providers, 2)
+	assert.Equal(t, entities.NotificationChannelSMS, providers[0].Channel())
+	assert.Equal(t, entities.NotificationChannelEmail, providers[1].Channel())
+
+	_, err = New(config.NotificationsConfig{Providers: []string{"telegram"}}, config.SMSAPIConfig{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package notifications
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// PushProvider отправляет уведомления через push-шлюз. Токены устройств хранит шлюз,
+// поэтому адресат задается ID водителя
+type PushProvider struct {
+	url     string
+	apiKey  string
+	timeout time.Duration
+	client  *http.Client
+}
+
+var _ services.NotificationProvider = (*PushProvider)(nil)
+
+// NewPushProvider создает PushProvider
+func NewPushProvider(url, apiKey string, timeout time.Duration) *PushProvider {
+	return &PushProvider{
+		url:     url,
+		apiKey:  apiKey,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// Channel возвращает канал провайдера
+func (p *PushProvider) Channel() entities.NotificationChannel {
+	return entities.NotificationChannelPush
+}
+
+// pushMessage тело запроса к push-шлюзу
+type pushMessage struct {
+	DriverID string            `json:"driver_id"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data"`
+}
+
+// Send отправляет push-уведомление на устройства водителя; шаблон передается в data,
+// чтобы приложение открыло нужный экран
+func (p *PushProvider) Send(ctx context.Context, recipient entities.NotificationRecipient, message entities.NotificationMessage) error {
+	return postJSON(ctx, p.client, p.url, p.apiKey, p.timeout, &pushMessage{
+		DriverID: recipient.DriverID.String(),
+		Title:    message.Subject,
+		Body:     message.Body,
+		Data:     map[string]string{"template": message.Template},
+	})
+}
@@ -0,0 +1,58 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+// SMSProvider отправляет текст уведомления через HTTP SMS-шлюз (external.sms_api)
+type SMSProvider struct {
+	url     string
+	apiKey  string
+	from    string
+	timeout time.Duration
+	client  *http.Client
+}
+
+var _ services.NotificationProvider = (*SMSProvider)(nil)
+
+// NewSMSProvider создает SMSProvider; сообщения отправляются на <baseURL>/messages
+func NewSMSProvider(baseURL, apiKey, from string, timeout time.Duration) *SMSProvider {
+	return &SMSProvider{
+		url:     strings.TrimRight(baseURL, "/") + "/messages",
+		apiKey:  apiKey,
+		from:    from,
+		timeout: timeout,
+		client:  &http.Client{},
+	}
+}
+
+// Channel возвращает канал провайдера
+func (p *SMSProvider) Channel() entities.NotificationChannel {
+	return entities.NotificationChannelSMS
+}
+
+// smsMessage тело запроса к SMS-шлюзу
+type smsMessage struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+// Send отправляет текст уведомления на телефон водителя; тема в SMS не передается
+func (p *SMSProvider) Send(ctx context.Context, recipient entities.NotificationRecipient, message entities.NotificationMessage) error {
+	if recipient.Phone == "" {
+		return errors.New("recipient has no phone")
+	}
+	return postJSON(ctx, p.client, p.url, p.apiKey, p.timeout, &smsMessage{
+		To:   recipient.Phone,
+		From: p.from,
+		Text: message.Body,
+	})
+}
@@ -0,0 +1,524 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// historyTimeout срок записи запуска в историю
+const historyTimeout = 5 * time.Second
+
+var (
+	// ErrJobNotFound задача не зарегистрирована или выключена
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning задача уже выполняется на этом экземпляре
+	ErrJobRunning = errors.New("job is already running")
+	// ErrJobLocked задачу выполняет другой экземпляр сервиса
+	ErrJobLocked = errors.New("job is running on another instance")
+)
+
+// JobFunc функция фоновой задачи
+type JobFunc func(ctx context.Context) error
+
+// Locker блокировка задач между экземплярами сервиса
+type Locker interface {
+	// TryLock захватывает блокировку name без ожидания; release освобождает ее
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
+
+// RunClaimer отметки запусков по расписанию, общие для экземпляров сервиса. Блокировка
+// не дает задаче выполняться на двух экземплярах одновременно, а отметка — выполнить один
+// запуск по расписанию дважды, если задача успела завершиться до срабатывания расписания
+// на другом экземпляре
+type RunClaimer interface {
+	// ClaimScheduledRun отмечает запуск задачи name, назначенный на scheduledAt. Возвращает
+	// false, если этот или более поздний запуск уже отметил другой экземпляр
+	ClaimScheduledRun(ctx context.Context, name string, scheduledAt time.Time, instance string) (bool, error)
+}
+
+// RunHistory история запусков задач
+type RunHistory interface {
+	Create(ctx context.Context, run *entities.JobRun) error
+	List(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error)
+}
+
+// JobStatus состояние фоновой задачи
+type JobStatus struct {
+	Name         string     `json:"name"`
+	Schedule     string     `json:"schedule"`
+	Running      bool       `json:"running"`
+	LastRun      *time.Time `json:"last_run,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRun      *time.Time `json:"next_run,omitempty"`
+	RunCount     int        `json:"run_count"`
+	SkippedCount int        `json:"skipped_count"`
+	// LockedCount запуски по расписанию, пропущенные, потому что задачу выполнял или уже
+	// выполнил другой экземпляр
+	LockedCount int `json:"locked_count"`
+	// PerInstance задача выполняется на каждом экземпляре без блокировки
+	PerInstance bool `json:"per_instance"`
+}
+
+// job зарегистрированная фоновая задача
+type job struct {
+	name     string
+	schedule string
+	timeout  time.Duration
+	fn       JobFunc
+	entryID  cron.EntryID
+	// perInstance задача обслуживает сам экземпляр (например, его пул соединений)
+	// и не блокируется между экземплярами
+	perInstance bool
+
+	mu           sync.Mutex
+	running      bool
+	lastRun      *time.Time
+	lastDuration time.Duration
+	lastError    error
+	runCount     int
+	skippedCount int
+	lockedCount  int
+}
+
+// Scheduler планировщик фоновых задач по cron-выражениям
+type Scheduler struct {
+	cron     *cron.Cron
+	location *time.Location
+	logger   *zap.Logger
+
+	// locker блокирует задачи между экземплярами; nil — задачи выполняет каждый экземпляр
+	locker Locker
+	// claimer отмечает запуски по расписанию; nil — запуск отмечается только блокировкой
+	claimer RunClaimer
+	// history сохраняет запуски; nil — история только в JobStatus
+	history  RunHistory
+	instance string
+
+	mu   sync.RWMutex
+	jobs map[string]*job
+	ctx  context.Context
+	stop context.CancelFunc
+	// manual запуски вне расписания, которых ждет Stop
+	manual sync.WaitGroup
+}
+
+// New создает планировщик в указанном часовом поясе (IANA, например Europe/Moscow)
+func New(timezone string, logger *zap.Logger) (*Scheduler, error) {
+	location := time.Local
+	if timezone != "" {
+		loc, err := time.LoadLocation(timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scheduler timezone %q: %w", timezone, err)
+		}
+		location = loc
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	return &Scheduler{
+		cron:     cron.New(cron.WithLocation(location)),
+		location: location,
+		logger:   logger,
+		jobs:     make(map[string]*job),
+		ctx:      ctx,
+		stop:     stop,
+	}, nil
+}
+
+// SetLocker включает блокировку задач между экземплярами сервиса: запуск по расписанию
+// пропускается, если задачу уже выполняет другой экземпляр. Вызывается до Start
+func (s *Scheduler) SetLocker(locker Locker) {
+	s.locker = locker
+}
+
+// SetClaimer включает отметку запусков по расписанию между экземплярами: каждый запуск
+// выполняет один экземпляр, даже если задача завершилась до срабатывания расписания на
+// остальных. Экземпляр отмечается именем из SetHistory. Действует вместе с SetLocker;
+// вызывается до Start
+func (s *Scheduler) SetClaimer(claimer RunClaimer) {
+	s.claimer = claimer
+}
+
+// SetHistory включает сохранение запусков задач; instance записывается в каждый запуск.
+// Вызывается до Start
+func (s *Scheduler) SetHistory(history RunHistory, instance string) {
+	s.history = history
+	s.instance = instance
+}
+
+// Register регистрирует задачу с cron-выражением (5 полей или дескрипторы вида @daily, @every 1h).
+// Запуск пропускается, если предыдущий запуск задачи еще не завершился, здесь или, при
+// заданном Locker, на другом экземпляре
+func (s *Scheduler) Register(name, schedule string, timeout time.Duration, fn JobFunc) error {
+	return s.register(name, schedule, timeout, fn, false)
+}
+
+// RegisterPerInstance регистрирует задачу, которая выполняется на каждом экземпляре
+// сервиса независимо от блокировок
+func (s *Scheduler) RegisterPerInstance(name, schedule string, timeout time.Duration, fn JobFunc) error {
+	return s.register(name, schedule, timeout, fn, true)
+}
+
+// register добавляет задачу в планировщик
+func (s *Scheduler) register(name, schedule string, timeout time.Duration, fn JobFunc, perInstance bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %q already registered", name)
+	}
+
+	j := &job{
+		name:        name,
+		schedule:    schedule,
+		timeout:     timeout,
+		fn:          fn,
+		perInstance: perInstance,
+	}
+
+	entryID, err := s.cron.AddFunc(schedule, func() { s.run(j) })
+	if err != nil {
+		return fmt.Errorf("invalid schedule %q for job %q: %w", schedule, name, err)
+	}
+	j.entryID = entryID
+	s.jobs[name] = j
+
+	s.logger.Info("Background job registered",
+		zap.String("job", name),
+		zap.String("schedule", schedule),
+		zap.String("timezone", s.location.String()),
+	)
+
+	return nil
+}
+
+// Reschedule меняет расписание и таймаут зарегистрированной задачи без перезапуска.
+// Выполняющийся запуск не прерывается; новый таймаут действует со следующего запуска
+func (s *Scheduler) Reschedule(name, schedule string, timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, exists := s.jobs[name]
+	if !exists {
+		return fmt.Errorf("job %q: %w", name, ErrJobNotFound)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.schedule != schedule {
+		entryID, err := s.cron.AddFunc(schedule, func() { s.run(j) })
+		if err != nil {
+			return fmt.Errorf("invalid schedule %q for job %q: %w", schedule, name, err)
+		}
+		s.cron.Remove(j.entryID)
+		j.entryID = entryID
+		j.schedule = schedule
+
+		s.logger.Info("Background job rescheduled",
+			zap.String("job", name),
+			zap.String("schedule", schedule),
+		)
+	}
+	j.timeout = timeout
+
+	return nil
+}
+
+// Start запускает планировщик
+func (s *Scheduler) Start() {
+	s.cron.Start()
+}
+
+// Stop останавливает планировщик и ждет завершения выполняющихся задач, в том числе
+// запущенных вне расписания
+func (s *Scheduler) Stop(ctx context.Context) error {
+	stopped := s.cron.Stop()
+	done := make(chan struct{})
+	go func() {
+		<-stopped.Done()
+		s.manual.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		s.stop()
+		return nil
+	case <-ctx.Done():
+		// Прерываем задачи, не успевшие завершиться
+		s.stop()
+		return ctx.Err()
+	}
+}
+
+// Jobs возвращает состояние всех задач, отсортированное по имени
+func (s *Scheduler) Jobs() []JobStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, s.status(j))
+	}
+
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].Name < statuses[k].Name
+	})
+	return statuses
+}
+
+// status формирует состояние задачи
+func (s *Scheduler) status(j *job) JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	status := JobStatus{
+		Name:         j.name,
+		Schedule:     j.schedule,
+		Running:      j.running,
+		LastRun:      j.lastRun,
+		RunCount:     j.runCount,
+		SkippedCount: j.skippedCount,
+		LockedCount:  j.lockedCount,
+		PerInstance:  j.perInstance,
+	}
+
+	if j.lastRun != nil {
+		status.LastDuration = j.lastDuration.String()
+	}
+	if j.lastError != nil {
+		status.LastError = j.lastError.Error()
+	}
+
+	if next := s.cron.Entry(j.entryID).Next; !next.IsZero() {
+		status.NextRun = &next
+	}
+
+	return status
+}
+
+// Trigger запускает задачу вне расписания и не ждет ее завершения. Возвращает
+// ErrJobRunning или ErrJobLocked, если задача уже выполняется здесь или на другом экземпляре
+func (s *Scheduler) Trigger(name, triggeredBy string) error {
+	s.mu.RLock()
+	j, exists := s.jobs[name]
+	s.mu.RUnlock()
+	if !exists {
+		return ErrJobNotFound
+	}
+
+	release, err := s.acquire(j)
+	if err != nil {
+		return err
+	}
+
+	s.manual.Add(1)
+	go func() {
+		defer s.manual.Done()
+		s.execute(j, release, entities.JobRunManual, triggeredBy)
+	}()
+
+	s.logger.Info("Background job triggered",
+		zap.String("job", name),
+		zap.String("triggered_by", triggeredBy),
+	)
+	return nil
+}
+
+// Runs возвращает сохраненные запуски задач, новые первыми
+func (s *Scheduler) Runs(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error) {
+	if s.history == nil {
+		return []*entities.JobRun{}, nil
+	}
+	if filters.JobName != "" {
+		s.mu.RLock()
+		_, exists := s.jobs[filters.JobName]
+		s.mu.RUnlock()
+		if !exists {
+			return nil, ErrJobNotFound
+		}
+	}
+	return s.history.List(ctx, filters)
+}
+
+// run выполняет задачу по расписанию с защитой от наложения запусков
+func (s *Scheduler) run(j *job) {
+	s.runScheduled(j, s.scheduledAt(j))
+}
+
+// scheduledAt время срабатывания расписания, по которому cron вызвал задачу. Оно одинаково
+// на всех экземплярах с тем же расписанием и поэтому служит ключом запуска. cron отмечает
+// срабатывание до ответа на запрос состояния, так что задача видит свое время; нулевое
+// время — cron не запущен
+func (s *Scheduler) scheduledAt(j *job) time.Time {
+	j.mu.Lock()
+	entryID := j.entryID
+	j.mu.Unlock()
+
+	return s.cron.Entry(entryID).Prev
+}
+
+// runScheduled выполняет запуск задачи, назначенный на scheduledAt
+func (s *Scheduler) runScheduled(j *job, scheduledAt time.Time) {
+	release, err := s.acquire(j)
+	if err == nil {
+		err = s.claim(j, scheduledAt, release)
+	}
+	switch {
+	case errors.Is(err, ErrJobRunning):
+		j.mu.Lock()
+		j.skippedCount++
+		j.mu.Unlock()
+		s.logger.Warn("Background job is still running, skipping",
+			zap.String("job", j.name),
+		)
+		return
+	case errors.Is(err, ErrJobLocked):
+		j.mu.Lock()
+		j.lockedCount++
+		j.mu.Unlock()
+		s.logger.Debug("Background job is running on another instance, skipping",
+			zap.String("job", j.name),
+		)
+		return
+	case err != nil:
+		s.logger.Error("Failed to lock background job",
+			zap.Error(err),
+			zap.String("job", j.name),
+		)
+		return
+	}
+
+	s.execute(j, release, entities.JobRunScheduled, "")
+}
+
+// claim отмечает запуск по расписанию между экземплярами. Если запуск уже отметил другой
+// экземпляр или отметка не удалась, освобождает задачу и возвращает ErrJobLocked или ошибку
+func (s *Scheduler) claim(j *job, scheduledAt time.Time, release func()) error {
+	if s.claimer == nil || s.locker == nil || j.perInstance || scheduledAt.IsZero() {
+		return nil
+	}
+
+	claimed, err := s.claimer.ClaimScheduledRun(s.ctx, j.name, scheduledAt, s.instance)
+	if err == nil && claimed {
+		return nil
+	}
+
+	release()
+	j.mu.Lock()
+	j.running = false
+	j.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to claim run of job %q: %w", j.name, err)
+	}
+	return ErrJobLocked
+}
+
+// acquire отмечает задачу выполняющейся и захватывает ее блокировку между экземплярами
+func (s *Scheduler) acquire(j *job) (func(), error) {
+	j.mu.Lock()
+	if j.running {
+		j.mu.Unlock()
+		return nil, ErrJobRunning
+	}
+	j.running = true
+	j.mu.Unlock()
+
+	if s.locker == nil || j.perInstance {
+		return func() {}, nil
+	}
+
+	release, acquired, err := s.locker.TryLock(s.ctx, j.name)
+	if err != nil || !acquired {
+		j.mu.Lock()
+		j.running = false
+		j.mu.Unlock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock job %q: %w", j.name, err)
+		}
+		return nil, ErrJobLocked
+	}
+	return release, nil
+}
+
+// execute выполняет захваченную задачу, освобождает блокировку и сохраняет запуск в историю
+func (s *Scheduler) execute(j *job, release func(), trigger entities.JobRunTrigger, triggeredBy string) {
+	defer release()
+
+	j.mu.Lock()
+	timeout := j.timeout
+	j.mu.Unlock()
+
+	ctx := s.ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	startedAt := time.Now().In(s.location)
+	err := s.safeRun(ctx, j)
+	duration := time.Since(startedAt)
+
+	j.mu.Lock()
+	j.running = false
+	j.lastRun = &startedAt
+	j.lastDuration = duration
+	j.lastError = err
+	j.runCount++
+	j.mu.Unlock()
+
+	s.record(entities.NewJobRun(j.name, trigger, triggeredBy, s.instance, startedAt, startedAt.Add(duration), err))
+
+	if err != nil {
+		s.logger.Error("Background job failed",
+			zap.Error(err),
+			zap.String("job", j.name),
+			zap.Duration("duration", duration),
+		)
+		return
+	}
+
+	s.logger.Info("Background job completed",
+		zap.String("job", j.name),
+		zap.Duration("duration", duration),
+	)
+}
+
+// record сохраняет запуск в историю; сбой записи не влияет на результат задачи
+func (s *Scheduler) record(run *entities.JobRun) {
+	if s.history == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), historyTimeout)
+	defer cancel()
+
+	if err := s.history.Create(ctx, run); err != nil {
+		s.logger.Warn("Failed to record background job run",
+			zap.Error(err),
+			zap.String("job", run.JobName),
+		)
+	}
+}
+
+// safeRun выполняет задачу, перехватывая панику
+func (s *Scheduler) safeRun(ctx context.Context, j *job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return j.fn(ctx)
+}
@@ -0,0 +1,246 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScheduler_RegisterValidatesSchedule(t *testing.T) {
+	s, err := New("Europe/Moscow", zap.NewNop())
+	require.NoError(t, err)
+
+	noop := func(ctx context.Context) error { return nil }
+
+	require.NoError(t, s.Register("cleanup", "0 3 * * *", time.Minute, noop))
+	assert.Error(t, s.Register("cleanup", "0 4 * * *", time.Minute, noop))
+	assert.Error(t, s.Register("broken", "not a cron", time.Minute, noop))
+
+	_, err = New("Mars/Olympus", zap.NewNop())
+	assert.Error(t, err)
+}
+
+func TestScheduler_NextRunUsesTimezone(t *testing.T) {
+	s, err := New("Europe/Moscow", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, s.Register("cleanup", "0 3 * * *", time.Minute, func(ctx context.Context) error { return nil }))
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	require.NotNil(t, jobs[0].NextRun)
+
+	next := jobs[0].NextRun.In(s.location)
+	assert.Equal(t, 3, next.Hour())
+	assert.Equal(t, 0, next.Minute())
+	assert.Nil(t, jobs[0].LastRun)
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	s, err := New("", zap.NewNop())
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, s.Register("slow", "@hourly", 0, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return errors.New("boom")
+	}))
+	j := s.jobs["slow"]
+
+	done := make(chan struct{})
+	go func() {
+		s.run(j)
+		close(done)
+	}()
+	<-started
+
+	// Второй запуск во время выполнения первого пропускается
+	s.run(j)
+	close(release)
+	<-done
+
+	status := s.Jobs()[0]
+	assert.Equal(t, 1, status.RunCount)
+	assert.Equal(t, 1, status.SkippedCount)
+	assert.Equal(t, "boom", status.LastError)
+	assert.False(t, status.Running)
+	assert.NotNil(t, status.LastRun)
+}
+
+func TestScheduler_Reschedule(t *testing.T) {
+	s, err := New("Europe/Moscow", zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, s.Register("cleanup", "0 3 * * *", time.Minute, func(ctx context.Context) error { return nil }))
+
+	s.Start()
+	defer s.Stop(context.Background())
+
+	require.NoError(t, s.Reschedule("cleanup", "30 4 * * *", 2*time.Minute))
+	jobs := s.Jobs()
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "30 4 * * *", jobs[0].Schedule)
+	require.NotNil(t, jobs[0].NextRun)
+	next := jobs[0].NextRun.In(s.location)
+	assert.Equal(t, 4, next.Hour())
+	assert.Equal(t, 30, next.Minute())
+	assert.Equal(t, 2*time.Minute, s.jobs["cleanup"].timeout)
+	assert.Len(t, s.cron.Entries(), 1)
+
+	// Некорректное расписание не трогает действующее
+	assert.Error(t, s.Reschedule("cleanup", "not a cron", time.Minute))
+	assert.Equal(t, "30 4 * * *", s.Jobs()[0].Schedule)
+	assert.Error(t, s.Reschedule("unknown", "@daily", time.Minute))
+}
+
+// fakeLocker блокировки, общие для нескольких планировщиков, как advisory locks в одной базе
+type fakeLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, false, nil
+	}
+	l.held[name] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.held, name)
+	}, true, nil
+}
+
+func TestScheduler_LockSkipsRunOnOtherInstance(t *testing.T) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	history := memory.NewJobRunRepository()
+
+	runs := 0
+	newInstance := func(instance string) *Scheduler {
+		s, err := New("", zap.NewNop())
+		require.NoError(t, err)
+		s.SetLocker(locker)
+		s.SetHistory(history, instance)
+		require.NoError(t, s.Register("cleanup", "@hourly", 0, func(ctx context.Context) error {
+			runs++
+			return nil
+		}))
+		require.NoError(t, s.RegisterPerInstance("sample", "@hourly", 0, func(ctx context.Context) error { return nil }))
+		return s
+	}
+	first, second := newInstance("pod-1"), newInstance("pod-2")
+
+	// Пока первый экземпляр держит блокировку, второй пропускает запуск
+	release, acquired, err := locker.TryLock(context.Background(), "cleanup")
+	require.NoError(t, err)
+	require.True(t, acquired)
+	second.run(second.jobs["cleanup"])
+	assert.Equal(t, 0, runs)
+	assert.Equal(t, 1, second.Jobs()[0].LockedCount)
+	assert.ErrorIs(t, second.Trigger("cleanup", "admin"), ErrJobLocked)
+
+	// Задачи экземпляра выполняются без блокировки
+	_, _, _ = locker.TryLock(context.Background(), "sample")
+	second.run(second.jobs["sample"])
+	assert.Equal(t, 1, second.Jobs()[1].RunCount)
+	release()
+
+	first.run(first.jobs["cleanup"])
+	assert.Equal(t, 1, runs)
+
+	recorded, err := first.Runs(context.Background(), &entities.JobRunFilters{JobName: "cleanup"})
+	require.NoError(t, err)
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "pod-1", recorded[0].Instance)
+	assert.Equal(t, entities.JobRunScheduled, recorded[0].Trigger)
+	assert.Equal(t, entities.JobRunSucceeded, recorded[0].Status)
+}
+
+func TestScheduler_ClaimRunsEachScheduleOnce(t *testing.T) {
+	locker := &fakeLocker{held: make(map[string]bool)}
+	history := memory.NewJobRunRepository()
+
+	runs := make(map[string]int)
+	newInstance := func(instance string) *Scheduler {
+		s, err := New("", zap.NewNop())
+		require.NoError(t, err)
+		s.SetLocker(locker)
+		s.SetClaimer(history)
+		s.SetHistory(history, instance)
+		require.NoError(t, s.Register("cleanup", "@hourly", 0, func(ctx context.Context) error {
+			runs[instance]++
+			return nil
+		}))
+		return s
+	}
+	first, second := newInstance("pod-1"), newInstance("pod-2")
+
+	// Первый экземпляр завершил запуск раньше, чем расписание сработало на втором:
+	// блокировка свободна, но запуск уже выполнен
+	slot := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	first.runScheduled(first.jobs["cleanup"], slot)
+	second.runScheduled(second.jobs["cleanup"], slot)
+	assert.Equal(t, map[string]int{"pod-1": 1}, runs)
+	assert.Equal(t, 1, second.Jobs()[0].LockedCount)
+	assert.False(t, second.Jobs()[0].Running)
+
+	// Следующий запуск выполняет экземпляр, чье расписание сработало первым
+	second.runScheduled(second.jobs["cleanup"], slot.Add(time.Hour))
+	first.runScheduled(first.jobs["cleanup"], slot.Add(time.Hour))
+	assert.Equal(t, map[string]int{"pod-1": 1, "pod-2": 1}, runs)
+
+	// Ручной запуск не отмечается и выполняется, если задача свободна
+	require.NoError(t, first.Trigger("cleanup", "admin"))
+	require.NoError(t, first.Stop(context.Background()))
+	assert.Equal(t, 2, runs["pod-1"])
+}
+
+func TestScheduler_Trigger(t *testing.T) {
+	s, err := New("", zap.NewNop())
+	require.NoError(t, err)
+	history := memory.NewJobRunRepository()
+	s.SetHistory(history, "pod-1")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	require.NoError(t, s.Register("recompute", "@daily", 0, func(ctx context.Context) error {
+		close(started)
+		<-release
+		return errors.New("boom")
+	}))
+
+	assert.ErrorIs(t, s.Trigger("unknown", "admin"), ErrJobNotFound)
+	_, err = s.Runs(context.Background(), &entities.JobRunFilters{JobName: "unknown"})
+	assert.ErrorIs(t, err, ErrJobNotFound)
+
+	require.NoError(t, s.Trigger("recompute", "admin"))
+	<-started
+	assert.ErrorIs(t, s.Trigger("recompute", "admin"), ErrJobRunning)
+	close(release)
+
+	// Stop дожидается запуска вне расписания
+	require.NoError(t, s.Stop(context.Background()))
+
+	runs, err := s.Runs(context.Background(), &entities.JobRunFilters{})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, entities.JobRunManual, runs[0].Trigger)
+	assert.Equal(t, "admin", runs[0].TriggeredBy)
+	assert.Equal(t, entities.JobRunFailed, runs[0].Status)
+	assert.Equal(t, "boom", runs[0].Error)
+}
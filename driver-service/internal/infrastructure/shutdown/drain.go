@@ -0,0 +1,83 @@
+// Package shutdown дописывание буферов подсистем при остановке сервиса.
+//
+// Каждая подсистема дописывается со своим сроком; отчет о потерянных элементах
+// логируется и публикуется счетчиком shutdown_dropped_items (expvar)
+package shutdown
+
+import (
+	"context"
+	"expvar"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// droppedItems счетчик элементов, потерянных при остановке, по подсистемам
+var droppedItems = expvar.NewMap("shutdown_dropped_items")
+
+// DrainFunc дописывает буфер подсистемы до истечения ctx и возвращает число
+// элементов, которые записать не удалось
+type DrainFunc func(ctx context.Context) (int, error)
+
+// Result итог дописывания одной подсистемы
+type Result struct {
+	Subsystem string
+	Dropped   int
+	Duration  time.Duration
+	Err       error
+}
+
+// Report отчет о дописывании подсистем
+type Report struct {
+	Results []Result
+}
+
+// Drain дописывает подсистему с собственным сроком timeout в пределах ctx.
+// Ошибка подсистемы не прерывает остановку и попадает в отчет
+func (r *Report) Drain(ctx context.Context, subsystem string, timeout time.Duration, fn DrainFunc) {
+	drainCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	started := time.Now()
+	dropped, err := fn(drainCtx)
+	r.Results = append(r.Results, Result{
+		Subsystem: subsystem,
+		Dropped:   dropped,
+		Duration:  time.Since(started),
+		Err:       err,
+	})
+}
+
+// Dropped возвращает общее число потерянных элементов
+func (r *Report) Dropped() int {
+	total := 0
+	for _, result := range r.Results {
+		total += result.Dropped
+	}
+	return total
+}
+
+// Publish логирует отчет и добавляет потерянные элементы в счетчик shutdown_dropped_items
+func (r *Report) Publish(logger *zap.Logger) {
+	fields := make([]zap.Field, 0, len(r.Results)+1)
+	for _, result := range r.Results {
+		droppedItems.Add(result.Subsystem, int64(result.Dropped))
+		fields = append(fields, zap.Int(result.Subsystem, result.Dropped))
+
+		if result.Err != nil {
+			logger.Error("Subsystem drain did not complete",
+				zap.Error(result.Err),
+				zap.String("subsystem", result.Subsystem),
+				zap.Int("dropped", result.Dropped),
+				zap.Duration("duration", result.Duration),
+			)
+		}
+	}
+	fields = append(fields, zap.Int("total", r.Dropped()))
+
+	if r.Dropped() > 0 {
+		logger.Warn("Shutdown dropped buffered items", fields...)
+		return
+	}
+	logger.Info("Shutdown drained all buffered items", fields...)
+}
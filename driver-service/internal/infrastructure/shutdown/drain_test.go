@@ -0,0 +1,32 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestReport_Drain(t *testing.T) {
+	var report Report
+
+	report.Drain(context.Background(), "locations", time.Second, func(ctx context.Context) (int, error) {
+		return 0, nil
+	})
+	report.Drain(context.Background(), "websocket", 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 3, ctx.Err()
+	})
+
+	require.Len(t, report.Results, 2)
+	assert.NoError(t, report.Results[0].Err)
+	assert.ErrorIs(t, report.Results[1].Err, context.DeadlineExceeded, "each subsystem has its own deadline")
+	assert.Equal(t, 3, report.Dropped())
+
+	report.Publish(zap.NewNop())
+	assert.Equal(t, "3", droppedItems.Get("websocket").String())
+	assert.Equal(t, "0", droppedItems.Get("locations").String())
+}
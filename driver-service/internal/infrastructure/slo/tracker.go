@@ -0,0 +1,304 @@
+// Package slo учет целевых показателей (SLO) ключевых операций экземпляра сервиса.
+//
+// Tracker относит обработанные HTTP запросы к операциям по маршрутам и хранит в памяти
+// скользящее окно: счетчики и гистограмму задержек по интервалам окна. Перцентили задержек
+// и расход бюджета ошибок считаются по окну в момент запроса отчета, без внешней системы метрик
+package slo
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBounds верхние границы интервалов гистограммы задержек. Задержки больше последней
+// границы попадают в отдельный последний интервал
+var latencyBounds = []time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	300 * time.Millisecond,
+	500 * time.Millisecond,
+	750 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+// Objective цели операции
+type Objective struct {
+	// Name имя операции в отчете
+	Name string
+	// Routes маршруты операции: метод и шаблон пути, "POST /api/v1/drivers"
+	Routes []string
+	// LatencyTarget порог задержки: более медленные запросы расходуют бюджет задержки
+	LatencyTarget time.Duration
+	// LatencyObjective доля запросов быстрее LatencyTarget (0 — цель не задана)
+	LatencyObjective float64
+	// AvailabilityObjective доля запросов без ответа 5xx (0 — цель не задана)
+	AvailabilityObjective float64
+}
+
+// Budget расход бюджета ошибок цели за окно
+type Budget struct {
+	Objective float64 `json:"objective"`
+	// Actual доля хороших запросов; без запросов — 1
+	Actual      float64 `json:"actual"`
+	BadRequests int64   `json:"bad_requests"`
+	// Remaining остаток бюджета: 1 — не израсходован, 0 и меньше — исчерпан
+	Remaining float64 `json:"remaining"`
+	// BurnRate скорость расхода: 1 — бюджет расходуется ровно за окно, больше 1 — быстрее
+	BurnRate  float64 `json:"burn_rate"`
+	Exhausted bool    `json:"exhausted"`
+}
+
+// OperationReport показатели операции за окно
+type OperationReport struct {
+	Name            string   `json:"name"`
+	Routes          []string `json:"routes"`
+	Requests        int64    `json:"requests"`
+	Errors          int64    `json:"errors"`
+	P50Ms           float64  `json:"p50_ms"`
+	P95Ms           float64  `json:"p95_ms"`
+	P99Ms           float64  `json:"p99_ms"`
+	MaxMs           float64  `json:"max_ms"`
+	LatencyTargetMs float64  `json:"latency_target_ms,omitempty"`
+	Availability    *Budget  `json:"availability,omitempty"`
+	Latency         *Budget  `json:"latency,omitempty"`
+}
+
+// Report показатели всех операций за окно
+type Report struct {
+	GeneratedAt   time.Time         `json:"generated_at"`
+	WindowSeconds int64             `json:"window_seconds"`
+	Operations    []OperationReport `json:"operations"`
+}
+
+// slot запросы операции за один интервал окна
+type slot struct {
+	index    int64
+	requests int64
+	errors   int64
+	slow     int64
+	max      time.Duration
+	counts   []int64
+}
+
+// reset очищает интервал для нового индекса
+func (s *slot) reset(index int64) {
+	s.index = index
+	s.requests, s.errors, s.slow, s.max = 0, 0, 0, 0
+	for i := range s.counts {
+		s.counts[i] = 0
+	}
+}
+
+// operation цели и кольцо интервалов операции
+type operation struct {
+	objective Objective
+	slots     []slot
+}
+
+// Tracker учет запросов ключевых операций в скользящем окне. Безопасен для конкурентного
+// использования
+type Tracker struct {
+	mu         sync.Mutex
+	window     time.Duration
+	resolution time.Duration
+	operations []*operation
+	routes     map[string]*operation
+	now        func() time.Time
+}
+
+// NewTracker создает Tracker с окном window, из которого запросы уходят интервалами по
+// resolution. Маршрут, указанный у нескольких операций, учитывается в первой по имени
+func NewTracker(objectives []Objective, window, resolution time.Duration) *Tracker {
+	return newTracker(objectives, window, resolution, time.Now)
+}
+
+// newTracker создает Tracker с заданными часами
+func newTracker(objectives []Objective, window, resolution time.Duration, now func() time.Time) *Tracker {
+	slots := int(window / resolution)
+	if slots < 1 {
+		slots = 1
+	}
+
+	t := &Tracker{
+		window:     time.Duration(slots) * resolution,
+		resolution: resolution,
+		routes:     make(map[string]*operation),
+		now:        now,
+	}
+	objectives = append([]Objective(nil), objectives...)
+	sort.Slice(objectives, func(i, j int) bool {
+		return objectives[i].Name < objectives[j].Name
+	})
+	for _, objective := range objectives {
+		op := &operation{objective: objective, slots: make([]slot, slots)}
+		for i := range op.slots {
+			op.slots[i] = slot{index: -1, counts: make([]int64, len(latencyBounds)+1)}
+		}
+		t.operations = append(t.operations, op)
+		for _, route := range objective.Routes {
+			if _, ok := t.routes[route]; !ok {
+				t.routes[route] = op
+			}
+		}
+	}
+	return t
+}
+
+// RecordRequest учитывает обработанный запрос, если его маршрут относится к операции.
+// Ошибками считаются ответы 5xx
+func (t *Tracker) RecordRequest(method, route string, status int, latency time.Duration, size int) {
+	op, ok := t.routes[method+" "+route]
+	if !ok {
+		return
+	}
+	index := t.now().UnixNano() / int64(t.resolution)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &op.slots[index%int64(len(op.slots))]
+	if s.index != index {
+		s.reset(index)
+	}
+	s.requests++
+	if status >= 500 {
+		s.errors++
+	}
+	if op.objective.LatencyTarget > 0 && latency > op.objective.LatencyTarget {
+		s.slow++
+	}
+	if latency > s.max {
+		s.max = latency
+	}
+	s.counts[bucketOf(latency)]++
+}
+
+// bucketOf номер интервала гистограммы для задержки
+func bucketOf(latency time.Duration) int {
+	return sort.Search(len(latencyBounds), func(i int) bool {
+		return latency <= latencyBounds[i]
+	})
+}
+
+// Report возвращает показатели операций за окно, заканчивающееся сейчас
+func (t *Tracker) Report() Report {
+	now := t.now()
+	current := now.UnixNano() / int64(t.resolution)
+
+	report := Report{
+		GeneratedAt:   now.UTC(),
+		WindowSeconds: int64(t.window / time.Second),
+		Operations:    make([]OperationReport, 0, len(t.operations)),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, op := range t.operations {
+		report.Operations = append(report.Operations, op.report(current))
+	}
+	return report
+}
+
+// report сводит интервалы окна, заканчивающегося интервалом current
+func (op *operation) report(current int64) OperationReport {
+	window := slot{counts: make([]int64, len(latencyBounds)+1)}
+	oldest := current - int64(len(op.slots)) + 1
+	for i := range op.slots {
+		s := &op.slots[i]
+		if s.index < oldest || s.index > current {
+			continue
+		}
+		window.requests += s.requests
+		window.errors += s.errors
+		window.slow += s.slow
+		if s.max > window.max {
+			window.max = s.max
+		}
+		for b, count := range s.counts {
+			window.counts[b] += count
+		}
+	}
+
+	objective := op.objective
+	report := OperationReport{
+		Name:     objective.Name,
+		Routes:   objective.Routes,
+		Requests: window.requests,
+		Errors:   window.errors,
+		P50Ms:    milliseconds(window.percentile(0.50)),
+		P95Ms:    milliseconds(window.percentile(0.95)),
+		P99Ms:    milliseconds(window.percentile(0.99)),
+		MaxMs:    milliseconds(window.max),
+	}
+	if objective.AvailabilityObjective > 0 {
+		report.Availability = newBudget(objective.AvailabilityObjective, window.requests, window.errors)
+	}
+	if objective.LatencyObjective > 0 && objective.LatencyTarget > 0 {
+		report.LatencyTargetMs = milliseconds(objective.LatencyTarget)
+		report.Latency = newBudget(objective.LatencyObjective, window.requests, window.slow)
+	}
+	return report
+}
+
+// percentile оценивает задержку перцентиля q по гистограмме: внутри интервала задержки
+// считаются распределенными равномерно, оценка не превышает наибольшей задержки
+func (s *slot) percentile(q float64) time.Duration {
+	if s.requests == 0 {
+		return 0
+	}
+
+	rank := q * float64(s.requests)
+	var seen int64
+	for b, count := range s.counts {
+		if count == 0 || float64(seen+count) < rank {
+			seen += count
+			continue
+		}
+		var lower time.Duration
+		if b > 0 {
+			lower = latencyBounds[b-1]
+		}
+		upper := s.max
+		if b < len(latencyBounds) && latencyBounds[b] < upper {
+			upper = latencyBounds[b]
+		}
+		if upper <= lower {
+			return upper
+		}
+		fraction := (rank - float64(seen)) / float64(count)
+		return lower + time.Duration(fraction*float64(upper-lower))
+	}
+	return s.max
+}
+
+// newBudget считает расход бюджета цели objective (меньше 1) по bad плохим запросам из requests
+func newBudget(objective float64, requests, bad int64) *Budget {
+	budget := &Budget{Objective: objective, Actual: 1, BadRequests: bad, Remaining: 1}
+	if requests == 0 {
+		return budget
+	}
+
+	badRatio := float64(bad) / float64(requests)
+	budget.Actual = 1 - badRatio
+	budget.BurnRate = badRatio / (1 - objective)
+	budget.Remaining = 1 - budget.BurnRate
+	budget.Exhausted = budget.Remaining <= 0
+	return budget
+}
+
+// milliseconds переводит длительность в дробные миллисекунды
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
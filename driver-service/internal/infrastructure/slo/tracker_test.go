@@ -0,0 +1,89 @@
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker_Report(t *testing.T) {
+	now := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+	tracker := newTracker([]Objective{
+		{
+			Name:                  "update_location",
+			Routes:                []string{"POST /api/v1/drivers/:id/locations"},
+			LatencyTarget:         100 * time.Millisecond,
+			LatencyObjective:      0.99,
+			AvailabilityObjective: 0.999,
+		},
+		{Name: "nearby_search", Routes: []string{"GET /api/v1/locations/nearby"}},
+	}, time.Hour, time.Minute, func() time.Time { return now })
+
+	// 980 быстрых запросов, 18 медленных и две медленные ошибки
+	for i := 0; i < 980; i++ {
+		tracker.RecordRequest("POST", "/api/v1/drivers/:id/locations", 201, 10*time.Millisecond, 0)
+	}
+	for i := 0; i < 18; i++ {
+		tracker.RecordRequest("POST", "/api/v1/drivers/:id/locations", 201, 400*time.Millisecond, 0)
+	}
+	for i := 0; i < 2; i++ {
+		tracker.RecordRequest("POST", "/api/v1/drivers/:id/locations", 503, 600*time.Millisecond, 0)
+	}
+	// Маршруты вне операций не учитываются
+	tracker.RecordRequest("GET", "/api/v1/drivers/:id", 200, time.Second, 0)
+
+	report := tracker.Report()
+	assert.Equal(t, int64(3600), report.WindowSeconds)
+	require.Len(t, report.Operations, 2)
+	assert.Equal(t, "nearby_search", report.Operations[0].Name)
+	assert.Zero(t, report.Operations[0].Requests)
+	assert.Nil(t, report.Operations[0].Availability)
+
+	op := report.Operations[1]
+	assert.Equal(t, int64(1000), op.Requests)
+	assert.Equal(t, int64(2), op.Errors)
+	assert.InDelta(t, 10, op.P50Ms, 5, "p50 within the 5-10ms bucket")
+	assert.InDelta(t, 10, op.P95Ms, 5)
+	assert.InDelta(t, 400, op.P99Ms, 100, "p99 falls among the slow requests")
+	assert.Equal(t, 600.0, op.MaxMs)
+	assert.Equal(t, 100.0, op.LatencyTargetMs)
+
+	require.NotNil(t, op.Availability)
+	assert.Equal(t, int64(2), op.Availability.BadRequests)
+	assert.InDelta(t, 0.998, op.Availability.Actual, 1e-9)
+	assert.InDelta(t, 2, op.Availability.BurnRate, 1e-6)
+	assert.True(t, op.Availability.Exhausted)
+
+	require.NotNil(t, op.Latency)
+	assert.Equal(t, int64(20), op.Latency.BadRequests)
+	assert.InDelta(t, -1, op.Latency.Remaining, 1e-6)
+	assert.True(t, op.Latency.Exhausted)
+
+	// Через час запросы уходят из окна
+	now = now.Add(time.Hour)
+	op = tracker.Report().Operations[1]
+	assert.Zero(t, op.Requests)
+	assert.Equal(t, 1.0, op.Availability.Remaining)
+	assert.False(t, op.Availability.Exhausted)
+}
+
+func TestTracker_RollingWindow(t *testing.T) {
+	now := time.Date(2024, 3, 11, 10, 0, 0, 0, time.UTC)
+	tracker := newTracker([]Objective{
+		{Name: "create_driver", Routes: []string{"POST /api/v1/drivers"}, AvailabilityObjective: 0.9},
+	}, 10*time.Minute, time.Minute, func() time.Time { return now })
+
+	for minute := 0; minute < 15; minute++ {
+		tracker.RecordRequest("POST", "/api/v1/drivers", 201, 50*time.Millisecond, 0)
+		now = now.Add(time.Minute)
+	}
+	now = now.Add(-time.Minute)
+
+	// В окне 10 минут только последние 10 запросов
+	op := tracker.Report().Operations[0]
+	assert.Equal(t, int64(10), op.Requests)
+	assert.InDelta(t, 50, op.P99Ms, 1)
+	assert.Equal(t, 0.0, op.Availability.BurnRate)
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage хранит файлы в локальном каталоге, раздаваемом по BaseURL (nginx, CDN или общий том)
+type LocalStorage struct {
+	dir     string
+	baseURL string
+	// urlSecret секрет подписи временных ссылок; пустой — ссылки не подписываются
+	urlSecret string
+}
+
+// NewLocalStorage создает LocalStorage и при необходимости каталог dir
+func NewLocalStorage(dir, baseURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &LocalStorage{
+		dir:     dir,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}, nil
+}
+
+// NewSignedLocalStorage создает LocalStorage, выдающий временные ссылки, подписанные urlSecret
+// в формате модуля nginx secure_link: secure_link_md5 "$secure_link_expires$uri <urlSecret>"
+func NewSignedLocalStorage(dir, baseURL, urlSecret string) (*LocalStorage, error) {
+	s, err := NewLocalStorage(dir, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	s.urlSecret = urlSecret
+	return s, nil
+}
+
+// Save сохраняет файл под ключом key и возвращает его URL
+func (s *LocalStorage) Save(ctx context.Context, key string, contentType string, body io.Reader) (string, error) {
+	cleanKey := filepath.ToSlash(filepath.Clean("/" + key))[1:]
+	if cleanKey == "" {
+		return "", fmt.Errorf("invalid storage key: %q", key)
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(cleanKey))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	// Пишем во временный файл, чтобы оборванная загрузка не оставила неполный файл
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to store file: %w", err)
+	}
+
+	return s.baseURL + "/" + cleanKey, nil
+}
+
+// Delete удаляет файл по URL, выданному Save
+func (s *LocalStorage) Delete(ctx context.Context, url string) error {
+	key := strings.TrimPrefix(url, s.baseURL+"/")
+	if key == url {
+		return fmt.Errorf("file URL is outside of storage: %q", url)
+	}
+
+	cleanKey := filepath.ToSlash(filepath.Clean("/" + key))[1:]
+	if cleanKey == "" {
+		return fmt.Errorf("invalid storage key: %q", key)
+	}
+
+	if err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(cleanKey))); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// SignURL добавляет к URL файла срок действия и подпись (параметры expires и md5). Без секрета
+// URL возвращается как есть: доступ к каталогу тогда ограничивает сама раздача файлов
+func (s *LocalStorage) SignURL(fileURL string, expiresAt time.Time) (string, error) {
+	if !strings.HasPrefix(fileURL, s.baseURL+"/") {
+		return "", fmt.Errorf("file URL is outside of storage: %q", fileURL)
+	}
+	if s.urlSecret == "" {
+		return fileURL, nil
+	}
+
+	parsed, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid file URL: %w", err)
+	}
+
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	sum := md5.Sum([]byte(expires + parsed.Path + " " + s.urlSecret))
+
+	query := parsed.Query()
+	query.Set("md5", base64.RawURLEncoding.EncodeToString(sum[:]))
+	query.Set("expires", expires)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
@@ -0,0 +1,147 @@
+package warmup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// StepFunc шаг прогрева; возвращает число загруженных элементов
+type StepFunc func(ctx context.Context) (int, error)
+
+// Состояния прогрева
+const (
+	StatePending = "pending"
+	StateWarming = "warming"
+	StateReady   = "ready"
+)
+
+// StepStatus итог шага прогрева
+type StepStatus struct {
+	Name     string `json:"name"`
+	Items    int    `json:"items"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Status состояние прогрева для пробы готовности
+type Status struct {
+	State      string     `json:"state"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	// TimedOut прогрев прерван по таймауту, оставшиеся шаги не выполнены
+	TimedOut bool         `json:"timed_out,omitempty"`
+	Steps    []StepStatus `json:"steps"`
+}
+
+// step зарегистрированный шаг прогрева
+type step struct {
+	name string
+	fn   StepFunc
+}
+
+// Warmer выполняет шаги прогрева после запуска экземпляра. Шаги выполняются
+// по порядку регистрации; ошибка шага логируется и не останавливает прогрев.
+// Экземпляр готов к трафику, когда все шаги завершены или истек таймаут
+type Warmer struct {
+	timeout time.Duration
+	logger  *zap.Logger
+	steps   []step
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// New создает прогрев с ограничением общей длительности
+func New(timeout time.Duration, logger *zap.Logger) *Warmer {
+	return &Warmer{
+		timeout: timeout,
+		logger:  logger,
+		status:  Status{State: StatePending, Steps: []StepStatus{}},
+	}
+}
+
+// Add регистрирует шаг прогрева
+func (w *Warmer) Add(name string, fn StepFunc) {
+	w.steps = append(w.steps, step{name: name, fn: fn})
+}
+
+// Run выполняет шаги прогрева и переводит экземпляр в состояние готовности
+func (w *Warmer) Run(ctx context.Context) Status {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	started := time.Now()
+	w.mu.Lock()
+	w.status.State = StateWarming
+	w.status.StartedAt = &started
+	w.mu.Unlock()
+
+	w.logger.Info("Warm-up started", zap.Int("steps", len(w.steps)), zap.Duration("timeout", w.timeout))
+
+	timedOut := false
+	for _, s := range w.steps {
+		if ctx.Err() != nil {
+			timedOut = true
+			break
+		}
+
+		stepStarted := time.Now()
+		items, err := s.fn(ctx)
+		result := StepStatus{
+			Name:     s.name,
+			Items:    items,
+			Duration: time.Since(stepStarted).String(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			w.logger.Warn("Warm-up step failed", zap.String("step", s.name), zap.Error(err))
+		}
+
+		w.mu.Lock()
+		w.status.Steps = append(w.status.Steps, result)
+		w.mu.Unlock()
+	}
+
+	finished := time.Now()
+	w.mu.Lock()
+	w.status.State = StateReady
+	w.status.FinishedAt = &finished
+	w.status.DurationMs = finished.Sub(started).Milliseconds()
+	w.status.TimedOut = timedOut || ctx.Err() != nil
+	status := w.copyStatus()
+	w.mu.Unlock()
+
+	w.logger.Info("Warm-up finished",
+		zap.Duration("duration", finished.Sub(started)),
+		zap.Bool("timed_out", status.TimedOut),
+	)
+	return status
+}
+
+// Ready проверяет, завершен ли прогрев
+func (w *Warmer) Ready() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.status.State == StateReady
+}
+
+// Status возвращает текущее состояние прогрева
+func (w *Warmer) Status() Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.copyStatus()
+}
+
+// copyStatus копирует состояние; вызывается под блокировкой
+func (w *Warmer) copyStatus() Status {
+	status := w.status
+	status.Steps = append([]StepStatus{}, w.status.Steps...)
+	if status.State == StateWarming && status.StartedAt != nil {
+		status.DurationMs = time.Since(*status.StartedAt).Milliseconds()
+	}
+	return status
+}
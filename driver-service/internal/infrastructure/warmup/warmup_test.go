@@ -0,0 +1,55 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWarmer_Run(t *testing.T) {
+	warmer := New(time.Second, zap.NewNop())
+	warmer.Add("drivers", func(ctx context.Context) (int, error) { return 3, nil })
+	warmer.Add("locations", func(ctx context.Context) (int, error) { return 1, errors.New("shard unavailable") })
+	warmer.Add("leaderboards", func(ctx context.Context) (int, error) { return 7, nil })
+
+	assert.False(t, warmer.Ready())
+	assert.Equal(t, StatePending, warmer.Status().State)
+
+	status := warmer.Run(context.Background())
+	assert.True(t, warmer.Ready())
+	assert.Equal(t, StateReady, status.State)
+	assert.False(t, status.TimedOut)
+	require.NotNil(t, status.StartedAt)
+	require.NotNil(t, status.FinishedAt)
+
+	// Ошибка шага не останавливает прогрев
+	require.Len(t, status.Steps, 3)
+	assert.Equal(t, 3, status.Steps[0].Items)
+	assert.Equal(t, "shard unavailable", status.Steps[1].Error)
+	assert.Equal(t, 7, status.Steps[2].Items)
+}
+
+func TestWarmer_Timeout(t *testing.T) {
+	warmer := New(20*time.Millisecond, zap.NewNop())
+	warmer.Add("slow", func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	skipped := true
+	warmer.Add("next", func(ctx context.Context) (int, error) {
+		skipped = false
+		return 0, nil
+	})
+
+	status := warmer.Run(context.Background())
+	assert.True(t, warmer.Ready())
+	assert.True(t, status.TimedOut)
+	assert.True(t, skipped)
+	require.Len(t, status.Steps, 1)
+	assert.NotEmpty(t, status.Steps[0].Error)
+}
@@ -0,0 +1,130 @@
+// Package webhooks содержит клиентов вебхуков партнеров.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SignatureHeader заголовок с подписью тела запроса: "sha256=" и HMAC-SHA256 в hex
+const SignatureHeader = "X-Signature"
+
+// maxResponseSize ограничение размера ответа вебхука
+const maxResponseSize = 1 << 20
+
+// fleetWebhook вебхук автопарка
+type fleetWebhook struct {
+	url      string
+	secret   []byte
+	timeout  time.Duration
+	failOpen bool
+}
+
+// FleetClient вызывает вебхуки автопарков синхронно при изменении профилей водителей
+type FleetClient struct {
+	webhooks map[uuid.UUID]fleetWebhook
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+var _ services.FleetValidator = (*FleetClient)(nil)
+
+// NewFleetClient создает клиента вебхуков автопарков из конфигурации
+func NewFleetClient(cfg config.WebhooksConfig, logger *zap.Logger) (*FleetClient, error) {
+	webhooks := make(map[uuid.UUID]fleetWebhook, len(cfg.Fleets))
+	for key, webhook := range cfg.Fleets {
+		fleetID, err := uuid.Parse(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fleet ID in webhooks config: %s", key)
+		}
+
+		timeout := webhook.Timeout
+		if timeout <= 0 {
+			timeout = cfg.DefaultTimeout
+		}
+		webhooks[fleetID] = fleetWebhook{
+			url:      webhook.URL,
+			secret:   []byte(webhook.Secret),
+			timeout:  timeout,
+			failOpen: webhook.FailOpen,
+		}
+	}
+
+	return &FleetClient{
+		webhooks: webhooks,
+		client:   &http.Client{},
+		logger:   logger,
+	}, nil
+}
+
+// Validate отправляет изменение вебхуку автопарка и возвращает его решение
+func (c *FleetClient) Validate(ctx context.Context, req *entities.FleetValidationRequest) (*entities.FleetValidationResult, error) {
+	webhook, ok := c.webhooks[req.FleetID]
+	if !ok {
+		return nil, nil
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhook.timeout)
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if len(webhook.secret) > 0 {
+		httpReq.Header.Set(SignatureHeader, Sign(webhook.secret, body))
+	}
+
+	resp, err := c.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	var result entities.FleetValidationResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid webhook response: %w", err)
+	}
+	if !result.Decision.IsValid() {
+		return nil, fmt.Errorf("invalid webhook decision: %q", result.Decision)
+	}
+
+	return &result, nil
+}
+
+// FailOpen сообщает, пропускается ли изменение при недоступном вебхуке автопарка
+func (c *FleetClient) FailOpen(fleetID uuid.UUID) bool {
+	return c.webhooks[fleetID].failOpen
+}
+
+// Sign возвращает подпись тела запроса для заголовка SignatureHeader
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestClient(t *testing.T, fleetID uuid.UUID, webhook config.FleetWebhookConfig) *FleetClient {
+	t.Helper()
+	client, err := NewFleetClient(config.WebhooksConfig{
+		DefaultTimeout: time.Second,
+		Fleets:         map[string]config.FleetWebhookConfig{fleetID.String(): webhook},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return client
+}
+
+func TestFleetClient_Validate(t *testing.T) {
+	fleetID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign([]byte("secret"), body), r.Header.Get(SignatureHeader))
+
+		var req entities.FleetValidationRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		assert.Equal(t, entities.FleetValidationCreate, req.Action)
+		assert.Equal(t, fleetID, req.FleetID)
+
+		w.Write([]byte(`{"decision":"reject","reason":"unknown contract"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(t, fleetID, config.FleetWebhookConfig{URL: server.URL, Secret: "secret"})
+	result, err := client.Validate(context.Background(), &entities.FleetValidationRequest{
+		Action:  entities.FleetValidationCreate,
+		FleetID: fleetID,
+		Driver:  &entities.Driver{Phone: "+79000000001"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, entities.FleetDecisionReject, result.Decision)
+	assert.Equal(t, "unknown contract", result.Reason)
+
+	// Автопарк без вебхука
+	result, err = client.Validate(context.Background(), &entities.FleetValidationRequest{FleetID: uuid.New()})
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestFleetClient_ValidateFailures(t *testing.T) {
+	cases := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"status", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) }},
+		{"decision", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"decision":"maybe"}`)) }},
+		{"timeout", func(w http.ResponseWriter, r *http.Request) { time.Sleep(200 * time.Millisecond) }},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			fleetID := uuid.New()
+			client := newTestClient(t, fleetID, config.FleetWebhookConfig{
+				URL:      server.URL,
+				Timeout:  50 * time.Millisecond,
+				FailOpen: true,
+			})
+			_, err := client.Validate(context.Background(), &entities.FleetValidationRequest{FleetID: fleetID})
+			assert.Error(t, err)
+			assert.True(t, client.FailOpen(fleetID))
+		})
+	}
+}
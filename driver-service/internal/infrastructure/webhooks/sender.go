@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+)
+
+const (
+	// EventHeader заголовок с типом события
+	EventHeader = "X-Webhook-Event"
+	// DeliveryHeader заголовок с ID доставки; при ручной повторной доставке он новый
+	DeliveryHeader = "X-Webhook-Delivery"
+	// TimestampHeader заголовок с временем отправки в Unix-секундах
+	TimestampHeader = "X-Webhook-Timestamp"
+)
+
+// Sender отправляет события водителей подписчикам вебхуков
+type Sender struct {
+	client *http.Client
+}
+
+var _ services.WebhookSender = (*Sender)(nil)
+
+// NewSender создает отправителя вебхуков; timeout ограничивает один запрос
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{
+		client: &http.Client{
+			Timeout: timeout,
+			// Перенаправления не выполняются: подпись выдана адресу подписки
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Send отправляет тело доставки POST-запросом, подписанным ключом подписки
+func (s *Sender) Send(ctx context.Context, url, secret string, delivery *entities.WebhookDelivery) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set(SignatureHeader, Sign([]byte(secret), delivery.Payload))
+	httpReq.Header.Set(EventHeader, delivery.EventType)
+	httpReq.Header.Set(DeliveryHeader, delivery.ID.String())
+	httpReq.Header.Set(TimestampHeader, fmt.Sprintf("%d", time.Now().Unix()))
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	// Тело читается, чтобы соединение вернулось в пул
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	return resp.StatusCode, nil
+}
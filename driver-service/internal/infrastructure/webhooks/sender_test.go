@@ -0,0 +1,56 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender_Send(t *testing.T) {
+	delivery := entities.NewWebhookDelivery(uuid.New(), uuid.New(), "driver.status.changed", uuid.New(),
+		entities.WebhookPayload(`{"type":"driver.status.changed"}`), time.Now(), time.Now())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"type":"driver.status.changed"}`, string(body))
+		assert.Equal(t, Sign([]byte("subscriber-secret"), body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "driver.status.changed", r.Header.Get(EventHeader))
+		assert.Equal(t, delivery.ID.String(), r.Header.Get(DeliveryHeader))
+		assert.NotEmpty(t, r.Header.Get(TimestampHeader))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), server.URL, "subscriber-secret", delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+}
+
+func TestSender_SendDoesNotFollowRedirects(t *testing.T) {
+	delivery := entities.NewWebhookDelivery(uuid.New(), uuid.New(), "driver.shift.ended", uuid.New(),
+		entities.WebhookPayload(`{}`), time.Now(), time.Now())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.invalid/", http.StatusFound)
+	}))
+	defer server.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), server.URL, "subscriber-secret", delivery)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusFound, status)
+
+	// Недоступный получатель: кода ответа нет
+	server.Close()
+	status, err = NewSender(time.Second).Send(context.Background(), server.URL, "subscriber-secret", delivery)
+	assert.Error(t, err)
+	assert.Zero(t, status)
+}
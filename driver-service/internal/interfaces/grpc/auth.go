@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/grpc/pb"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// authenticator проверяет bearer токен из метаданных вызова и ограничивает вызов
+// автопарками токена так же, как HTTP API
+type authenticator struct {
+	verifier middleware.TokenVerifier
+	// required отклонять вызовы без токена; иначе они считаются внутренними
+	required bool
+	logger   *zap.Logger
+}
+
+// authenticate возвращает контекст вызова с автором для журнала аудита и областью
+// автопарков из токена
+func (a *authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		if a.required {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata required")
+		}
+		return entities.WithAuditActor(ctx, auditActor(ctx, nil)), nil
+	}
+
+	claims, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		a.logger.Warn("Rejected gRPC access token",
+			zap.Error(err),
+			zap.String("method", method),
+		)
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+	ctx = entities.WithAuditActor(ctx, auditActor(ctx, claims))
+	ctx = context.WithValue(ctx, claimsKey{}, claims)
+	if !claims.FleetScoped() {
+		return ctx, nil
+	}
+
+	// Партнерам доступны только вызовы водителей: местоположения не ограничиваются автопарками
+	if !strings.HasPrefix(method, "/"+pb.DriverService_ServiceDesc.ServiceName+"/") {
+		return nil, status.Error(codes.PermissionDenied, "method is not available to fleet-scoped tokens")
+	}
+	return entities.WithFleetScope(ctx, claims.FleetIDs), nil
+}
+
+// claimsKey ключ утверждений токена в контексте вызова
+type claimsKey struct{}
+
+// claimsFromContext возвращает утверждения токена вызова; вызовы без токена их не имеют
+func claimsFromContext(ctx context.Context) (*middleware.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*middleware.Claims)
+	return claims, ok
+}
+
+// preciseLocationAllowed сообщает, можно ли отдать вызывающему точные координаты водителя
+// driverID: их видят вызывающие с областью entities.ScopeLocationReadPrecise и сам водитель.
+// Внутренние вызовы без токена получают точные координаты, как в HTTP API
+func preciseLocationAllowed(ctx context.Context, driverID string) bool {
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		return true
+	}
+	return claims.HasScope(entities.ScopeLocationReadPrecise) || claims.IsDriver(driverID)
+}
+
+// authUnaryInterceptor проверяет токен unary вызова
+func authUnaryInterceptor(auth *authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := auth.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// authStreamInterceptor проверяет токен потокового вызова
+func authStreamInterceptor(auth *authenticator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := auth.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &scopedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// scopedStream поток с контекстом, дополненным областью автопарков
+type scopedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока
+func (s *scopedStream) Context() context.Context {
+	return s.ctx
+}
+
+// auditActor автор вызова: субъект и роли токена, ID запроса из метаданных x-request-id и
+// устройство водителя из утверждения device_id или метаданных x-device-id
+func auditActor(ctx context.Context, claims *middleware.Claims) entities.AuditActor {
+	var actor entities.AuditActor
+	md, _ := metadata.FromIncomingContext(ctx)
+	if md != nil {
+		if values := md.Get("x-request-id"); len(values) > 0 {
+			actor.RequestID = values[0]
+		}
+	}
+	if claims != nil {
+		roles := make([]string, 0, len(claims.Roles))
+		for _, role := range claims.Roles {
+			roles = append(roles, string(role))
+		}
+		actor.Subject = claims.Subject
+		actor.Roles = strings.Join(roles, ",")
+		if claims.DriverID != nil {
+			actor.DeviceID = claims.DeviceID
+			if actor.DeviceID == "" && md != nil {
+				if values := md.Get("x-device-id"); len(values) > 0 {
+					actor.DeviceID = values[0]
+				}
+			}
+		}
+	}
+	return actor
+}
+
+// bearerToken извлекает токен из метаданных authorization
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, value := range md.Get("authorization") {
+		if scheme, token, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			return strings.TrimSpace(token)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/grpc/pb"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// DriverServer реализация pb.DriverServiceServer
+type DriverServer struct {
+	pb.UnimplementedDriverServiceServer
+
+	driverService services.DriverService
+	logger        *zap.Logger
+}
+
+// NewDriverServer создает новый DriverServer
+func NewDriverServer(driverService services.DriverService, logger *zap.Logger) *DriverServer {
+	return &DriverServer{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// CreateDriver создает нового водителя
+func (s *DriverServer) CreateDriver(ctx context.Context, req *pb.CreateDriverRequest) (*pb.Driver, error) {
+	if req.GetPhone() == "" || req.GetFirstName() == "" || req.GetLastName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "phone, first_name and last_name are required")
+	}
+
+	driver := &entities.Driver{
+		Phone:          req.GetPhone(),
+		Email:          req.GetEmail(),
+		FirstName:      req.GetFirstName(),
+		LastName:       req.GetLastName(),
+		MiddleName:     req.MiddleName,
+		PassportSeries: req.GetPassportSeries(),
+		PassportNumber: req.GetPassportNumber(),
+		LicenseNumber:  req.GetLicenseNumber(),
+	}
+	if req.BirthDate != nil {
+		driver.BirthDate = req.BirthDate.AsTime()
+	}
+	if req.LicenseExpiry != nil {
+		driver.LicenseExpiry = req.LicenseExpiry.AsTime()
+	}
+
+	created, err := s.driverService.CreateDriver(ctx, driver)
+	if err != nil {
+		return nil, toStatus(s.logger, err, "Failed to create driver")
+	}
+
+	return driverToProto(created), nil
+}
+
+// GetDriver получает водителя по ID
+func (s *DriverServer) GetDriver(ctx context.Context, req *pb.GetDriverRequest) (*pb.Driver, error) {
+	driverID, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := s.driverService.GetDriverByID(ctx, driverID)
+	if err != nil {
+		return nil, toStatus(s.logger, err, "Failed to get driver")
+	}
+
+	return driverToProto(driver), nil
+}
+
+// ChangeStatus изменяет статус водителя
+func (s *DriverServer) ChangeStatus(ctx context.Context, req *pb.ChangeStatusRequest) (*pb.Driver, error) {
+	driverID, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetStatus() == "" {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+
+	if err := s.driverService.ChangeDriverStatus(ctx, driverID, entities.Status(req.GetStatus())); err != nil {
+		return nil, toStatus(s.logger, err, "Failed to change driver status")
+	}
+
+	driver, err := s.driverService.GetDriverByID(ctx, driverID)
+	if err != nil {
+		return nil, toStatus(s.logger, err, "Failed to get driver after status change")
+	}
+
+	return driverToProto(driver), nil
+}
+
+// parseID разбирает UUID из поля запроса
+func parseID(value, field string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s format", field)
+	}
+	return id, nil
+}
+
+// driverToProto конвертирует водителя в сообщение API
+func driverToProto(driver *entities.Driver) *pb.Driver {
+	return &pb.Driver{
+		Id:            driver.ID.String(),
+		Phone:         driver.Phone,
+		Email:         driver.Email,
+		FirstName:     driver.FirstName,
+		LastName:      driver.LastName,
+		MiddleName:    driver.MiddleName,
+		BirthDate:     optionalTimestamp(driver.BirthDate),
+		LicenseNumber: driver.LicenseNumber,
+		LicenseExpiry: optionalTimestamp(driver.LicenseExpiry),
+		Status:        string(driver.Status),
+		CurrentRating: driver.CurrentRating,
+		TotalTrips:    int32(driver.TotalTrips),
+		PaymentHold:   driver.PaymentHold,
+		CreatedAt:     timestamppb.New(driver.CreatedAt),
+		UpdatedAt:     timestamppb.New(driver.UpdatedAt),
+	}
+}
+
+// optionalTimestamp возвращает nil для незаполненной даты профиля
+func optionalTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
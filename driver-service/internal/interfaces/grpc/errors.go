@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// kindCodes коды gRPC категорий доменных ошибок
+var kindCodes = map[entities.ErrorKind]codes.Code{
+	entities.ErrorKindValidation:        codes.InvalidArgument,
+	entities.ErrorKindNotFound:          codes.NotFound,
+	entities.ErrorKindConflict:          codes.Aborted,
+	entities.ErrorKindInvalidTransition: codes.FailedPrecondition,
+	entities.ErrorKindPrecondition:      codes.FailedPrecondition,
+	entities.ErrorKindUnauthorized:      codes.Unauthenticated,
+	entities.ErrorKindForbidden:         codes.PermissionDenied,
+	entities.ErrorKindRateLimited:       codes.ResourceExhausted,
+	entities.ErrorKindUnavailable:       codes.Unavailable,
+}
+
+// errorCodes коды gRPC, отличающиеся от кода категории ошибки
+var errorCodes = []struct {
+	err  error
+	code codes.Code
+}{
+	// Повторная регистрация — создание уже существующей сущности, а не параллельное изменение
+	{entities.ErrDriverExists, codes.AlreadyExists},
+	// Автопарк указан в запросе: его отсутствие — невыполненное условие, а не ненайденный ресурс
+	{entities.ErrFleetNotFound, codes.FailedPrecondition},
+}
+
+// toStatus преобразует ошибку сервиса в статус gRPC; неизвестные ошибки скрываются за codes.Internal
+func toStatus(logger *zap.Logger, err error, message string) error {
+	logger.Error(message, zap.Error(err))
+
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return status.Error(mapping.code, err.Error())
+		}
+	}
+	if domainErr, ok := entities.AsDomainError(err); ok {
+		if code, ok := kindCodes[domainErr.Kind]; ok {
+			return status.Error(code, err.Error())
+		}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+	}
+
+	// Временная ошибка базы данных, не устраненная повторами: клиент может повторить вызов
+	if database.IsRetryable(err) {
+		return status.Error(codes.Unavailable, "service temporarily unavailable")
+	}
+
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/grpc/pb"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UploadLocationBatch сохраняет пакет местоположений водителя. Отклоненные точки перечисляются
+// в ответе; вызов завершается ошибкой, только если водитель не найден или сервис недоступен
+func (s *LocationServer) UploadLocationBatch(ctx context.Context, batch *pb.LocationBatch) (*pb.LocationBatchResult, error) {
+	driverID, err := parseID(batch.GetDriverId(), "driver_id")
+	if err != nil {
+		return nil, err
+	}
+	if len(batch.GetPoints()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "points are required")
+	}
+
+	result, err := s.locationService.BatchUpdateLocations(ctx, LocationsFromBatch(driverID, batch, time.Now()))
+	if err != nil {
+		return nil, toStatus(s.logger, err, "Failed to upload location batch")
+	}
+
+	// Все точки пакета относятся к одному водителю: если его нет, не сохранена ни одна
+	for _, rejection := range result.Rejected {
+		if rejection.Err == entities.ErrDriverNotFound {
+			return nil, toStatus(s.logger, rejection.Err, "Failed to upload location batch")
+		}
+	}
+
+	return BatchResultToProto(result), nil
+}
+
+// LocationsFromBatch конвертирует точки пакета в местоположения водителя driverID; точки без
+// времени замера получают время now. Используется и HTTP API для пакетов в формате protobuf
+func LocationsFromBatch(driverID uuid.UUID, batch *pb.LocationBatch, now time.Time) []*entities.DriverLocation {
+	locations := make([]*entities.DriverLocation, len(batch.GetPoints()))
+	for i, point := range batch.GetPoints() {
+		recordedAt := now
+		if point.RecordedAt != nil {
+			recordedAt = point.RecordedAt.AsTime()
+		}
+
+		location := entities.NewDriverLocation(driverID, point.GetLatitude(), point.GetLongitude(), recordedAt)
+		location.Altitude = point.Altitude
+		location.Accuracy = point.Accuracy
+		location.Speed = point.Speed
+		location.Bearing = point.Bearing
+		location.Metadata = metadataFromProto(point.GetMetadata())
+
+		locations[i] = location
+	}
+	return locations
+}
+
+// BatchResultToProto конвертирует итог сохранения пакета в сообщение API
+func BatchResultToProto(result *entities.LocationBatchResult) *pb.LocationBatchResult {
+	message := &pb.LocationBatchResult{
+		Received:   int32(result.Received),
+		Accepted:   int32(result.Accepted),
+		Duplicates: int32(result.Duplicates),
+		Dropped:    int32(result.Dropped),
+		Rejected:   make([]*pb.LocationRejection, 0, len(result.Rejected)),
+	}
+	for _, rejection := range result.Rejected {
+		message.Rejected = append(message.Rejected, &pb.LocationRejection{
+			Index: int32(rejection.Index),
+			Code:  rejection.Code,
+			Error: rejection.Error,
+		})
+	}
+	return message
+}
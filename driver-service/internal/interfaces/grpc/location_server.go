@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/grpc/pb"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Значения по умолчанию для поиска водителей поблизости, совпадают с HTTP API
+const (
+	defaultNearbyRadiusKm = 5.0
+	defaultNearbyLimit    = 20
+)
+
+// LocationServer реализация pb.LocationServiceServer
+type LocationServer struct {
+	pb.UnimplementedLocationServiceServer
+
+	locationService services.LocationService
+	logger          *zap.Logger
+}
+
+// NewLocationServer создает новый LocationServer
+func NewLocationServer(locationService services.LocationService, logger *zap.Logger) *LocationServer {
+	return &LocationServer{
+		locationService: locationService,
+		logger:          logger,
+	}
+}
+
+// UpdateLocation обновляет местоположение водителя
+func (s *LocationServer) UpdateLocation(ctx context.Context, req *pb.UpdateLocationRequest) (*pb.Location, error) {
+	location, err := locationFromProto(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.locationService.UpdateLocation(ctx, location); err != nil {
+		return nil, toStatus(s.logger, err, "Failed to update location")
+	}
+
+	return locationToProto(location), nil
+}
+
+// StreamLocationUpdates принимает поток местоположений; невалидные точки пропускаются,
+// ошибка хранилища прерывает поток
+func (s *LocationServer) StreamLocationUpdates(stream pb.LocationService_StreamLocationUpdatesServer) error {
+	ctx := stream.Context()
+	summary := &pb.StreamLocationUpdatesResponse{}
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+
+		location, err := locationFromProto(req)
+		if err != nil {
+			summary.Rejected++
+			continue
+		}
+
+		if err := s.locationService.UpdateLocation(ctx, location); err != nil {
+			if isRejectedLocation(err) {
+				summary.Rejected++
+				continue
+			}
+			return toStatus(s.logger, err, "Failed to update location from stream")
+		}
+		summary.Accepted++
+	}
+}
+
+// WatchDriverLocation передает местоположения водителя до отмены вызова клиентом
+func (s *LocationServer) WatchDriverLocation(req *pb.WatchDriverLocationRequest, stream pb.LocationService_WatchDriverLocationServer) error {
+	driverID, err := parseID(req.GetDriverId(), "driver_id")
+	if err != nil {
+		return err
+	}
+
+	ctx := stream.Context()
+	precise := preciseLocationAllowed(ctx, driverID.String())
+	locations, err := s.locationService.StreamLocations(ctx, driverID)
+	if err != nil {
+		return toStatus(s.logger, err, "Failed to stream driver locations")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case location, ok := <-locations:
+			if !ok {
+				return nil
+			}
+			if err := stream.Send(visibleLocationToProto(location, precise)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// GetNearbyDrivers получает водителей поблизости
+func (s *LocationServer) GetNearbyDrivers(ctx context.Context, req *pb.GetNearbyDriversRequest) (*pb.GetNearbyDriversResponse, error) {
+	radiusKm := req.GetRadiusKm()
+	if radiusKm <= 0 {
+		radiusKm = defaultNearbyRadiusKm
+	}
+
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultNearbyLimit
+	}
+
+	locations, err := s.locationService.GetNearbyDrivers(ctx, req.GetLatitude(), req.GetLongitude(), radiusKm, limit, nil)
+	if err != nil {
+		return nil, toStatus(s.logger, err, "Failed to get nearby drivers")
+	}
+
+	response := &pb.GetNearbyDriversResponse{
+		Locations: make([]*pb.Location, 0, len(locations)),
+	}
+	for _, location := range locations {
+		precise := preciseLocationAllowed(ctx, location.DriverID.String())
+		response.Locations = append(response.Locations, visibleLocationToProto(location, precise))
+	}
+
+	return response, nil
+}
+
+// isRejectedLocation проверяет, относится ли ошибка к содержимому точки, а не к работе сервиса
+func isRejectedLocation(err error) bool {
+	return errors.Is(err, entities.ErrInvalidLocation) ||
+		errors.Is(err, entities.ErrInvalidTimestamp) ||
+		errors.Is(err, entities.ErrInvalidLocationMetadata) ||
+		errors.Is(err, entities.ErrDriverNotFound)
+}
+
+// locationFromProto конвертирует запрос в местоположение водителя
+func locationFromProto(req *pb.UpdateLocationRequest) (*entities.DriverLocation, error) {
+	driverID, err := parseID(req.GetDriverId(), "driver_id")
+	if err != nil {
+		return nil, err
+	}
+
+	recordedAt := time.Now()
+	if req.RecordedAt != nil {
+		recordedAt = req.RecordedAt.AsTime()
+	}
+
+	location := entities.NewDriverLocation(driverID, req.GetLatitude(), req.GetLongitude(), recordedAt)
+	location.Altitude = req.Altitude
+	location.Accuracy = req.Accuracy
+	location.Speed = req.Speed
+	location.Bearing = req.Bearing
+	location.Metadata = metadataFromProto(req.GetMetadata())
+
+	return location, nil
+}
+
+// metadataFromProto конвертирует типизированные метаданные в метаданные местоположения;
+// нормализация и проверка значений выполняются сервисом
+func metadataFromProto(metadata *pb.LocationMetadata) entities.Metadata {
+	result := make(entities.Metadata)
+	if metadata == nil {
+		return result
+	}
+
+	if metadata.OnTrip != nil {
+		result[entities.LocationMetaOnTrip] = metadata.GetOnTrip()
+	}
+	if metadata.OrderId != nil {
+		result[entities.LocationMetaOrderID] = metadata.GetOrderId()
+	}
+	if metadata.Source != nil {
+		result[entities.LocationMetaSource] = metadata.GetSource()
+	}
+	if metadata.Provider != nil {
+		result[entities.LocationMetaProvider] = metadata.GetProvider()
+	}
+	if metadata.BatteryLevel != nil {
+		result[entities.LocationMetaBatteryLevel] = int(metadata.GetBatteryLevel())
+	}
+	return result
+}
+
+// metadataToProto конвертирует известные ключи метаданных в сообщение API
+func metadataToProto(location *entities.DriverLocation) *pb.LocationMetadata {
+	metadata := &pb.LocationMetadata{}
+	if onTrip, ok := location.Metadata[entities.LocationMetaOnTrip].(bool); ok {
+		metadata.OnTrip = &onTrip
+	}
+	if orderID := location.OrderID(); orderID != nil {
+		value := orderID.String()
+		metadata.OrderId = &value
+	}
+	if source := location.Source(); source != "" {
+		metadata.Source = &source
+	}
+	if provider, ok := location.Metadata[entities.LocationMetaProvider].(string); ok {
+		metadata.Provider = &provider
+	}
+	if level, ok := batteryLevel(location.Metadata[entities.LocationMetaBatteryLevel]); ok {
+		metadata.BatteryLevel = &level
+	}
+	return metadata
+}
+
+// batteryLevel читает заряд батареи, сохраненный как int или как float64 после чтения из JSONB
+func batteryLevel(value interface{}) (int32, bool) {
+	switch v := value.(type) {
+	case int:
+		return int32(v), true
+	case float64:
+		return int32(v), true
+	}
+	return 0, false
+}
+
+// locationToProto конвертирует местоположение в сообщение API
+func locationToProto(location *entities.DriverLocation) *pb.Location {
+	return &pb.Location{
+		Id:         location.ID.String(),
+		DriverId:   location.DriverID.String(),
+		Latitude:   location.Latitude,
+		Longitude:  location.Longitude,
+		Altitude:   location.Altitude,
+		Accuracy:   location.Accuracy,
+		Speed:      location.Speed,
+		Bearing:    location.Bearing,
+		Address:    location.Address,
+		RecordedAt: timestamppb.New(location.RecordedAt),
+		Metadata:   metadataToProto(location),
+	}
+}
+
+// visibleLocationToProto конвертирует местоположение в сообщение API; без precise точка
+// огрубляется в копии, потому что местоположение может передаваться и другим получателям
+func visibleLocationToProto(location *entities.DriverLocation, precise bool) *pb.Location {
+	if !precise {
+		blurred := *location
+		blurred.Blur()
+		location = &blurred
+	}
+	return locationToProto(location)
+}
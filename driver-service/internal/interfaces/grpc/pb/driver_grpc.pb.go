@@ -0,0 +1,501 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.24.4
+// source: driver/v1/driver.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DriverService_CreateDriver_FullMethodName = "/driver.v1.DriverService/CreateDriver"
+	DriverService_GetDriver_FullMethodName    = "/driver.v1.DriverService/GetDriver"
+	DriverService_ChangeStatus_FullMethodName = "/driver.v1.DriverService/ChangeStatus"
+)
+
+// DriverServiceClient is the client API for DriverService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DriverServiceClient interface {
+	// CreateDriver регистрирует водителя (обязательны только телефон и имя)
+	CreateDriver(ctx context.Context, in *CreateDriverRequest, opts ...grpc.CallOption) (*Driver, error)
+	// GetDriver возвращает водителя по ID
+	GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*Driver, error)
+	// ChangeStatus изменяет статус водителя и возвращает обновленного водителя
+	ChangeStatus(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*Driver, error)
+}
+
+type driverServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDriverServiceClient(cc grpc.ClientConnInterface) DriverServiceClient {
+	return &driverServiceClient{cc}
+}
+
+func (c *driverServiceClient) CreateDriver(ctx context.Context, in *CreateDriverRequest, opts ...grpc.CallOption) (*Driver, error) {
+	out := new(Driver)
+	err := c.cc.Invoke(ctx, DriverService_CreateDriver_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) GetDriver(ctx context.Context, in *GetDriverRequest, opts ...grpc.CallOption) (*Driver, error) {
+	out := new(Driver)
+	err := c.cc.Invoke(ctx, DriverService_GetDriver_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *driverServiceClient) ChangeStatus(ctx context.Context, in *ChangeStatusRequest, opts ...grpc.CallOption) (*Driver, error) {
+	out := new(Driver)
+	err := c.cc.Invoke(ctx, DriverService_ChangeStatus_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DriverServiceServer is the server API for DriverService service.
+// All implementations must embed UnimplementedDriverServiceServer
+// for forward compatibility
+type DriverServiceServer interface {
+	// CreateDriver регистрирует водителя (обязательны только телефон и имя)
+	CreateDriver(context.Context, *CreateDriverRequest) (*Driver, error)
+	// GetDriver возвращает водителя по ID
+	GetDriver(context.Context, *GetDriverRequest) (*Driver, error)
+	// ChangeStatus изменяет статус водителя и возвращает обновленного водителя
+	ChangeStatus(context.Context, *ChangeStatusRequest) (*Driver, error)
+	mustEmbedUnimplementedDriverServiceServer()
+}
+
+// UnimplementedDriverServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDriverServiceServer struct {
+}
+
+func (UnimplementedDriverServiceServer) CreateDriver(context.Context, *CreateDriverRequest) (*Driver, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDriver not implemented")
+}
+func (UnimplementedDriverServiceServer) GetDriver(context.Context, *GetDriverRequest) (*Driver, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriver not implemented")
+}
+func (UnimplementedDriverServiceServer) ChangeStatus(context.Context, *ChangeStatusRequest) (*Driver, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeStatus not implemented")
+}
+func (UnimplementedDriverServiceServer) mustEmbedUnimplementedDriverServiceServer() {}
+
+// UnsafeDriverServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DriverServiceServer will
+// result in compilation errors.
+type UnsafeDriverServiceServer interface {
+	mustEmbedUnimplementedDriverServiceServer()
+}
+
+func RegisterDriverServiceServer(s grpc.ServiceRegistrar, srv DriverServiceServer) {
+	s.RegisterService(&DriverService_ServiceDesc, srv)
+}
+
+func _DriverService_CreateDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).CreateDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_CreateDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).CreateDriver(ctx, req.(*CreateDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_GetDriver_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).GetDriver(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_GetDriver_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).GetDriver(ctx, req.(*GetDriverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DriverService_ChangeStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangeStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServiceServer).ChangeStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DriverService_ChangeStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServiceServer).ChangeStatus(ctx, req.(*ChangeStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DriverService_ServiceDesc is the grpc.ServiceDesc for DriverService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DriverService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "driver.v1.DriverService",
+	HandlerType: (*DriverServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateDriver",
+			Handler:    _DriverService_CreateDriver_Handler,
+		},
+		{
+			MethodName: "GetDriver",
+			Handler:    _DriverService_GetDriver_Handler,
+		},
+		{
+			MethodName: "ChangeStatus",
+			Handler:    _DriverService_ChangeStatus_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "driver/v1/driver.proto",
+}
+
+const (
+	LocationService_UpdateLocation_FullMethodName        = "/driver.v1.LocationService/UpdateLocation"
+	LocationService_StreamLocationUpdates_FullMethodName = "/driver.v1.LocationService/StreamLocationUpdates"
+	LocationService_WatchDriverLocation_FullMethodName   = "/driver.v1.LocationService/WatchDriverLocation"
+	LocationService_GetNearbyDrivers_FullMethodName      = "/driver.v1.LocationService/GetNearbyDrivers"
+	LocationService_UploadLocationBatch_FullMethodName   = "/driver.v1.LocationService/UploadLocationBatch"
+)
+
+// LocationServiceClient is the client API for LocationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type LocationServiceClient interface {
+	// UpdateLocation сохраняет одно местоположение водителя
+	UpdateLocation(ctx context.Context, in *UpdateLocationRequest, opts ...grpc.CallOption) (*Location, error)
+	// StreamLocationUpdates принимает поток местоположений от приложения водителя
+	StreamLocationUpdates(ctx context.Context, opts ...grpc.CallOption) (LocationService_StreamLocationUpdatesClient, error)
+	// WatchDriverLocation отдает поток местоположений водителя до отмены вызова
+	WatchDriverLocation(ctx context.Context, in *WatchDriverLocationRequest, opts ...grpc.CallOption) (LocationService_WatchDriverLocationClient, error)
+	// GetNearbyDrivers ищет доступных водителей в радиусе от точки
+	GetNearbyDrivers(ctx context.Context, in *GetNearbyDriversRequest, opts ...grpc.CallOption) (*GetNearbyDriversResponse, error)
+	// UploadLocationBatch сохраняет пакет местоположений водителя; точки с ошибкой в данных
+	// отклоняются по отдельности
+	UploadLocationBatch(ctx context.Context, in *LocationBatch, opts ...grpc.CallOption) (*LocationBatchResult, error)
+}
+
+type locationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLocationServiceClient(cc grpc.ClientConnInterface) LocationServiceClient {
+	return &locationServiceClient{cc}
+}
+
+func (c *locationServiceClient) UpdateLocation(ctx context.Context, in *UpdateLocationRequest, opts ...grpc.CallOption) (*Location, error) {
+	out := new(Location)
+	err := c.cc.Invoke(ctx, LocationService_UpdateLocation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) StreamLocationUpdates(ctx context.Context, opts ...grpc.CallOption) (LocationService_StreamLocationUpdatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LocationService_ServiceDesc.Streams[0], LocationService_StreamLocationUpdates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationServiceStreamLocationUpdatesClient{stream}
+	return x, nil
+}
+
+type LocationService_StreamLocationUpdatesClient interface {
+	Send(*UpdateLocationRequest) error
+	CloseAndRecv() (*StreamLocationUpdatesResponse, error)
+	grpc.ClientStream
+}
+
+type locationServiceStreamLocationUpdatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationServiceStreamLocationUpdatesClient) Send(m *UpdateLocationRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *locationServiceStreamLocationUpdatesClient) CloseAndRecv() (*StreamLocationUpdatesResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(StreamLocationUpdatesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *locationServiceClient) WatchDriverLocation(ctx context.Context, in *WatchDriverLocationRequest, opts ...grpc.CallOption) (LocationService_WatchDriverLocationClient, error) {
+	stream, err := c.cc.NewStream(ctx, &LocationService_ServiceDesc.Streams[1], LocationService_WatchDriverLocation_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &locationServiceWatchDriverLocationClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LocationService_WatchDriverLocationClient interface {
+	Recv() (*Location, error)
+	grpc.ClientStream
+}
+
+type locationServiceWatchDriverLocationClient struct {
+	grpc.ClientStream
+}
+
+func (x *locationServiceWatchDriverLocationClient) Recv() (*Location, error) {
+	m := new(Location)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *locationServiceClient) GetNearbyDrivers(ctx context.Context, in *GetNearbyDriversRequest, opts ...grpc.CallOption) (*GetNearbyDriversResponse, error) {
+	out := new(GetNearbyDriversResponse)
+	err := c.cc.Invoke(ctx, LocationService_GetNearbyDrivers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *locationServiceClient) UploadLocationBatch(ctx context.Context, in *LocationBatch, opts ...grpc.CallOption) (*LocationBatchResult, error) {
+	out := new(LocationBatchResult)
+	err := c.cc.Invoke(ctx, LocationService_UploadLocationBatch_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LocationServiceServer is the server API for LocationService service.
+// All implementations must embed UnimplementedLocationServiceServer
+// for forward compatibility
+type LocationServiceServer interface {
+	// UpdateLocation сохраняет одно местоположение водителя
+	UpdateLocation(context.Context, *UpdateLocationRequest) (*Location, error)
+	// StreamLocationUpdates принимает поток местоположений от приложения водителя
+	StreamLocationUpdates(LocationService_StreamLocationUpdatesServer) error
+	// WatchDriverLocation отдает поток местоположений водителя до отмены вызова
+	WatchDriverLocation(*WatchDriverLocationRequest, LocationService_WatchDriverLocationServer) error
+	// GetNearbyDrivers ищет доступных водителей в радиусе от точки
+	GetNearbyDrivers(context.Context, *GetNearbyDriversRequest) (*GetNearbyDriversResponse, error)
+	// UploadLocationBatch сохраняет пакет местоположений водителя; точки с ошибкой в данных
+	// отклоняются по отдельности
+	UploadLocationBatch(context.Context, *LocationBatch) (*LocationBatchResult, error)
+	mustEmbedUnimplementedLocationServiceServer()
+}
+
+// UnimplementedLocationServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedLocationServiceServer struct {
+}
+
+func (UnimplementedLocationServiceServer) UpdateLocation(context.Context, *UpdateLocationRequest) (*Location, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLocation not implemented")
+}
+func (UnimplementedLocationServiceServer) StreamLocationUpdates(LocationService_StreamLocationUpdatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLocationUpdates not implemented")
+}
+func (UnimplementedLocationServiceServer) WatchDriverLocation(*WatchDriverLocationRequest, LocationService_WatchDriverLocationServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDriverLocation not implemented")
+}
+func (UnimplementedLocationServiceServer) GetNearbyDrivers(context.Context, *GetNearbyDriversRequest) (*GetNearbyDriversResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNearbyDrivers not implemented")
+}
+func (UnimplementedLocationServiceServer) UploadLocationBatch(context.Context, *LocationBatch) (*LocationBatchResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadLocationBatch not implemented")
+}
+func (UnimplementedLocationServiceServer) mustEmbedUnimplementedLocationServiceServer() {}
+
+// UnsafeLocationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LocationServiceServer will
+// result in compilation errors.
+type UnsafeLocationServiceServer interface {
+	mustEmbedUnimplementedLocationServiceServer()
+}
+
+func RegisterLocationServiceServer(s grpc.ServiceRegistrar, srv LocationServiceServer) {
+	s.RegisterService(&LocationService_ServiceDesc, srv)
+}
+
+func _LocationService_UpdateLocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).UpdateLocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_UpdateLocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).UpdateLocation(ctx, req.(*UpdateLocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_StreamLocationUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LocationServiceServer).StreamLocationUpdates(&locationServiceStreamLocationUpdatesServer{stream})
+}
+
+type LocationService_StreamLocationUpdatesServer interface {
+	SendAndClose(*StreamLocationUpdatesResponse) error
+	Recv() (*UpdateLocationRequest, error)
+	grpc.ServerStream
+}
+
+type locationServiceStreamLocationUpdatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationServiceStreamLocationUpdatesServer) SendAndClose(m *StreamLocationUpdatesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *locationServiceStreamLocationUpdatesServer) Recv() (*UpdateLocationRequest, error) {
+	m := new(UpdateLocationRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _LocationService_WatchDriverLocation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDriverLocationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LocationServiceServer).WatchDriverLocation(m, &locationServiceWatchDriverLocationServer{stream})
+}
+
+type LocationService_WatchDriverLocationServer interface {
+	Send(*Location) error
+	grpc.ServerStream
+}
+
+type locationServiceWatchDriverLocationServer struct {
+	grpc.ServerStream
+}
+
+func (x *locationServiceWatchDriverLocationServer) Send(m *Location) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _LocationService_GetNearbyDrivers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNearbyDriversRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).GetNearbyDrivers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_GetNearbyDrivers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).GetNearbyDrivers(ctx, req.(*GetNearbyDriversRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LocationService_UploadLocationBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocationBatch)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LocationServiceServer).UploadLocationBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LocationService_UploadLocationBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LocationServiceServer).UploadLocationBatch(ctx, req.(*LocationBatch))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// LocationService_ServiceDesc is the grpc.ServiceDesc for LocationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LocationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "driver.v1.LocationService",
+	HandlerType: (*LocationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "UpdateLocation",
+			Handler:    _LocationService_UpdateLocation_Handler,
+		},
+		{
+			MethodName: "GetNearbyDrivers",
+			Handler:    _LocationService_GetNearbyDrivers_Handler,
+		},
+		{
+			MethodName: "UploadLocationBatch",
+			Handler:    _LocationService_UploadLocationBatch_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLocationUpdates",
+			Handler:       _LocationService_StreamLocationUpdates_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchDriverLocation",
+			Handler:       _LocationService_WatchDriverLocation_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "driver/v1/driver.proto",
+}
@@ -0,0 +1,177 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/grpc/pb"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server gRPC сервер
+type Server struct {
+	config     *config.Config
+	logger     *zap.Logger
+	grpcServer *grpc.Server
+}
+
+// NewServer создает новый gRPC сервер с сервисами водителей и местоположений. Если verifier
+// не задан, токены не проверяются и вызовы не ограничиваются автопарками
+func NewServer(
+	cfg *config.Config,
+	logger *zap.Logger,
+	verifier middleware.TokenVerifier,
+	driverService services.DriverService,
+	locationService services.LocationService,
+) *Server {
+	unary := []grpc.UnaryServerInterceptor{
+		recoveryUnaryInterceptor(logger),
+		loggingUnaryInterceptor(logger),
+		timeoutUnaryInterceptor(cfg.Server.Timeout),
+	}
+	stream := []grpc.StreamServerInterceptor{recoveryStreamInterceptor(logger), loggingStreamInterceptor(logger)}
+	if verifier != nil {
+		auth := &authenticator{verifier: verifier, required: cfg.Auth.GRPCRequired, logger: logger}
+		unary = append(unary, authUnaryInterceptor(auth))
+		stream = append(stream, authStreamInterceptor(auth))
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
+	)
+
+	pb.RegisterDriverServiceServer(grpcServer, NewDriverServer(driverService, logger))
+	pb.RegisterLocationServiceServer(grpcServer, NewLocationServer(locationService, logger))
+
+	return &Server{
+		config:     cfg,
+		logger:     logger,
+		grpcServer: grpcServer,
+	}
+}
+
+// Start запускает gRPC сервер
+func (s *Server) Start() error {
+	s.logger.Info("Starting gRPC server",
+		zap.Int("port", s.config.Server.GRPCPort),
+	)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.config.Server.GRPCPort))
+	if err != nil {
+		return fmt.Errorf("failed to listen gRPC port: %w", err)
+	}
+
+	if err := s.grpcServer.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+		return fmt.Errorf("failed to start gRPC server: %w", err)
+	}
+
+	return nil
+}
+
+// Stop останавливает gRPC сервер, дожидаясь завершения активных вызовов до истечения контекста
+func (s *Server) Stop(ctx context.Context) error {
+	s.logger.Info("Stopping gRPC server")
+
+	done := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		// Открытые потоки местоположений не завершаются сами, поэтому обрываем их
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// loggingUnaryInterceptor логирует unary вызовы
+func loggingUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		logger.Info("gRPC request",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("latency", time.Since(start)),
+		)
+		return resp, err
+	}
+}
+
+// timeoutUnaryInterceptor ограничивает unary вызов сроком server.timeout, если клиент
+// не передал более ранний срок. Потоковые вызовы не ограничиваются
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// loggingStreamInterceptor логирует потоковые вызовы после их завершения
+func loggingStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+
+		logger.Info("gRPC stream",
+			zap.String("method", info.FullMethod),
+			zap.String("code", status.Code(err).String()),
+			zap.Duration("duration", time.Since(start)),
+		)
+		return err
+	}
+}
+
+// recoveryUnaryInterceptor преобразует панику обработчика в codes.Internal
+func recoveryUnaryInterceptor(logger *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// recoveryStreamInterceptor преобразует панику потокового обработчика в codes.Internal
+func recoveryStreamInterceptor(logger *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("gRPC stream handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.Any("panic", r),
+					zap.ByteString("stack", debug.Stack()),
+				)
+				err = status.Error(codes.Internal, "internal server error")
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,297 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/grpc/pb"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type nopEventPublisher struct{}
+
+func (nopEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	return nil
+}
+
+func newTestClientConn(t *testing.T) *grpc.ClientConn {
+	return newTestClientConnWithAuth(t, nil, memory.NewFleetRepository())
+}
+
+func newTestClientConnWithAuth(t *testing.T, verifier middleware.TokenVerifier, fleetRepo *memory.FleetRepository) *grpc.ClientConn {
+	return newTestClientConnWithRepos(t, verifier, fleetRepo, memory.NewDriverRepository())
+}
+
+func newTestClientConnWithRepos(t *testing.T, verifier middleware.TokenVerifier, fleetRepo *memory.FleetRepository, driverRepo *memory.DriverRepository) *grpc.ClientConn {
+	events := nopEventPublisher{}
+	driverService := services.NewDriverService(driverRepo, memory.NewDocumentRepository(), nil, events, zap.NewNop())
+	driverService = services.NewFleetScopedDriverService(driverService, fleetRepo)
+	locationService := services.NewLocationService(memory.NewLocationRepository(), driverRepo, nil, events, nil, nil, nil, services.LocationPolicy{}, zap.NewNop())
+
+	server := NewServer(&config.Config{}, zap.NewNop(), verifier, driverService, locationService)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.grpcServer.Serve(listener) }()
+	t.Cleanup(server.grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestDriverServer(t *testing.T) {
+	ctx := context.Background()
+	client := pb.NewDriverServiceClient(newTestClientConn(t))
+
+	created, err := client.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000401",
+		FirstName: "Иван",
+		LastName:  "Тестовый",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "registered", created.Status)
+	assert.Nil(t, created.BirthDate, "unfilled dates are omitted")
+
+	fetched, err := client.GetDriver(ctx, &pb.GetDriverRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, created.Phone, fetched.Phone)
+
+	_, err = client.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000401",
+		FirstName: "Петр",
+		LastName:  "Дубликат",
+	})
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.ChangeStatus(ctx, &pb.ChangeStatusRequest{Id: created.Id, Status: "pending_verification"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "profile is incomplete for verification")
+
+	_, err = client.ChangeStatus(ctx, &pb.ChangeStatusRequest{Id: created.Id, Status: "available"})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err), "registered -> available is not allowed")
+
+	_, err = client.GetDriver(ctx, &pb.GetDriverRequest{Id: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetDriver(ctx, &pb.GetDriverRequest{Id: "not-a-uuid"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestLocationServer_StreamLocationUpdates(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestClientConn(t)
+	drivers := pb.NewDriverServiceClient(conn)
+	locations := pb.NewLocationServiceClient(conn)
+
+	driver, err := drivers.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000402",
+		FirstName: "Иван",
+		LastName:  "Потоковый",
+	})
+	require.NoError(t, err)
+
+	stream, err := locations.StreamLocationUpdates(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: 55.75, Longitude: 37.61}))
+	require.NoError(t, stream.Send(&pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: 55.76, Longitude: 37.62}))
+	require.NoError(t, stream.Send(&pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: 123, Longitude: 37.62}))
+	require.NoError(t, stream.Send(&pb.UpdateLocationRequest{DriverId: "broken", Latitude: 55.76, Longitude: 37.62}))
+
+	summary, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), summary.Accepted)
+	assert.Equal(t, int32(2), summary.Rejected)
+}
+
+func TestLocationServer_WatchDriverLocation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn := newTestClientConn(t)
+	drivers := pb.NewDriverServiceClient(conn)
+	locations := pb.NewLocationServiceClient(conn)
+
+	driver, err := drivers.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000405",
+		FirstName: "Иван",
+		LastName:  "Наблюдаемый",
+	})
+	require.NoError(t, err)
+	_, err = locations.UpdateLocation(ctx, &pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: 55.75, Longitude: 37.61})
+	require.NoError(t, err)
+
+	stream, err := locations.WatchDriverLocation(ctx, &pb.WatchDriverLocationRequest{DriverId: driver.Id})
+	require.NoError(t, err)
+
+	// Первое сообщение — текущее местоположение; после него подписка уже действует
+	current, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 55.75, current.Latitude)
+
+	for _, latitude := range []float64{55.76, 55.77} {
+		_, err = locations.UpdateLocation(ctx, &pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: latitude, Longitude: 37.62})
+		require.NoError(t, err)
+	}
+	for _, latitude := range []float64{55.76, 55.77} {
+		update, err := stream.Recv()
+		require.NoError(t, err)
+		assert.Equal(t, latitude, update.Latitude)
+		assert.Equal(t, driver.Id, update.DriverId)
+	}
+}
+
+func TestLocationServer_UploadLocationBatch(t *testing.T) {
+	ctx := context.Background()
+	conn := newTestClientConn(t)
+	drivers := pb.NewDriverServiceClient(conn)
+	locations := pb.NewLocationServiceClient(conn)
+
+	driver, err := drivers.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000403",
+		FirstName: "Иван",
+		LastName:  "Пакетный",
+	})
+	require.NoError(t, err)
+
+	recordedAt := time.Now().Add(-time.Minute)
+	result, err := locations.UploadLocationBatch(ctx, &pb.LocationBatch{
+		DriverId: driver.Id,
+		Points: []*pb.LocationPoint{
+			{Latitude: 55.75, Longitude: 37.61, RecordedAt: timestamppb.New(recordedAt)},
+			{Latitude: 55.76, Longitude: 37.62, RecordedAt: timestamppb.New(recordedAt.Add(5 * time.Second))},
+			{Latitude: 123, Longitude: 37.62, RecordedAt: timestamppb.New(recordedAt.Add(10 * time.Second))},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), result.Received)
+	assert.Equal(t, int32(2), result.Accepted)
+	require.Len(t, result.Rejected, 1)
+	assert.Equal(t, int32(2), result.Rejected[0].Index)
+
+	_, err = locations.UploadLocationBatch(ctx, &pb.LocationBatch{DriverId: driver.Id})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = locations.UploadLocationBatch(ctx, &pb.LocationBatch{
+		DriverId: uuid.NewString(),
+		Points:   []*pb.LocationPoint{{Latitude: 55.75, Longitude: 37.61}},
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// stubVerifier принимает токены из набора
+type stubVerifier map[string]*middleware.Claims
+
+func (v stubVerifier) Verify(ctx context.Context, token string) (*middleware.Claims, error) {
+	claims, ok := v[token]
+	if !ok {
+		return nil, middleware.ErrInvalidSignature
+	}
+	return claims, nil
+}
+
+func TestLocationServer_MasksCoordinatesWithoutPreciseScope(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	verifier := stubVerifier{
+		"dispatcher": {Subject: "dispatcher-1", Roles: []middleware.Role{middleware.RoleDispatcher}},
+		"precise":    {Subject: "dispatcher-2", Roles: []middleware.Role{middleware.RoleDispatcher}, Scopes: []string{entities.ScopeLocationReadPrecise}},
+	}
+	driverRepo := memory.NewDriverRepository()
+	conn := newTestClientConnWithRepos(t, verifier, memory.NewFleetRepository(), driverRepo)
+	drivers := pb.NewDriverServiceClient(conn)
+	locations := pb.NewLocationServiceClient(conn)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	}
+
+	driver, err := drivers.CreateDriver(ctx, &pb.CreateDriverRequest{
+		Phone:     "+79000000406",
+		FirstName: "Иван",
+		LastName:  "Скрытный",
+	})
+	require.NoError(t, err)
+	// Поиск поблизости возвращает только активных водителей
+	require.NoError(t, driverRepo.UpdateStatus(ctx, uuid.MustParse(driver.Id), entities.StatusAvailable))
+	speed := 42.0
+	_, err = locations.UpdateLocation(ctx, &pb.UpdateLocationRequest{DriverId: driver.Id, Latitude: 55.75321, Longitude: 37.61987, Speed: &speed})
+	require.NoError(t, err)
+
+	nearby := &pb.GetNearbyDriversRequest{Latitude: 55.75, Longitude: 37.62, RadiusKm: 5}
+	coarse, err := locations.GetNearbyDrivers(withToken("dispatcher"), nearby)
+	require.NoError(t, err)
+	require.Len(t, coarse.Locations, 1)
+	assert.Equal(t, 55.75, coarse.Locations[0].Latitude)
+	assert.Equal(t, 37.62, coarse.Locations[0].Longitude)
+	assert.Nil(t, coarse.Locations[0].Speed)
+
+	exact, err := locations.GetNearbyDrivers(withToken("precise"), nearby)
+	require.NoError(t, err)
+	require.Len(t, exact.Locations, 1)
+	assert.Equal(t, 55.75321, exact.Locations[0].Latitude)
+
+	stream, err := locations.WatchDriverLocation(withToken("dispatcher"), &pb.WatchDriverLocationRequest{DriverId: driver.Id})
+	require.NoError(t, err)
+	watched, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 55.75, watched.Latitude)
+	assert.Equal(t, 37.62, watched.Longitude)
+}
+
+func TestAuthInterceptor_FleetScope(t *testing.T) {
+	fleetRepo := memory.NewFleetRepository()
+	own := entities.NewFleet(&entities.FleetRequest{Name: "Свой парк"})
+	require.NoError(t, fleetRepo.Create(context.Background(), own))
+
+	verifier := stubVerifier{
+		"partner": {Subject: "partner-1", Roles: []middleware.Role{middleware.RolePartner}, FleetIDs: []uuid.UUID{own.ID}},
+	}
+	conn := newTestClientConnWithAuth(t, verifier, fleetRepo)
+	drivers := pb.NewDriverServiceClient(conn)
+	withToken := func(token string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+	}
+
+	internal, err := drivers.CreateDriver(context.Background(), &pb.CreateDriverRequest{
+		Phone:     "+79000000411",
+		FirstName: "Иван",
+		LastName:  "Внутренний",
+	})
+	require.NoError(t, err, "calls without a token are internal")
+
+	_, err = drivers.GetDriver(withToken("partner"), &pb.GetDriverRequest{Id: internal.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err), "drivers outside the partner's fleets are hidden")
+
+	created, err := drivers.CreateDriver(withToken("partner"), &pb.CreateDriverRequest{
+		Phone:     "+79000000412",
+		FirstName: "Петр",
+		LastName:  "Партнерский",
+	})
+	require.NoError(t, err)
+	_, err = drivers.GetDriver(withToken("partner"), &pb.GetDriverRequest{Id: created.Id})
+	assert.NoError(t, err)
+
+	_, err = drivers.GetDriver(withToken("forged"), &pb.GetDriverRequest{Id: created.Id})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = pb.NewLocationServiceClient(conn).GetNearbyDrivers(withToken("partner"), &pb.GetNearbyDriversRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyHandler обработчик HTTP запросов управления ключами API внутренних сервисов
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+	logger        *zap.Logger
+}
+
+// NewAPIKeyHandler создает новый APIKeyHandler
+func NewAPIKeyHandler(apiKeyService services.APIKeyService, logger *zap.Logger) *APIKeyHandler {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		logger:        logger,
+	}
+}
+
+// APIKeysResponse ответ со списком ключей API
+type APIKeysResponse struct {
+	Keys []*entities.APIKey `json:"keys"`
+}
+
+// RegisterRoutes регистрирует маршруты управления ключами API
+func (h *APIKeyHandler) RegisterRoutes(api *gin.RouterGroup) {
+	admin := api.Group("/admin/api-keys", h.requireToken)
+	{
+		admin.POST("", h.IssueKey)
+		admin.GET("", h.ListKeys)
+		admin.DELETE("/:id", h.RevokeKey)
+	}
+}
+
+// requireToken запрещает управлять ключами по ключу API: иначе утекший ключ администратора
+// позволил бы выпустить себе новые
+func (h *APIKeyHandler) requireToken(c *gin.Context) {
+	if claims, ok := middleware.ClaimsFromContext(c); ok && claims.APIKeyID != nil {
+		c.AbortWithStatusJSON(http.StatusForbidden, ErrorResponse{
+			Error: "API keys cannot be managed with an API key",
+			Code:  "FORBIDDEN",
+		})
+		return
+	}
+	c.Next()
+}
+
+// IssueKey выпускает ключ API. Ключ возвращается только в этом ответе
+func (h *APIKeyHandler) IssueKey(c *gin.Context) {
+	var req entities.IssueAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	if req.CreatedBy == "" {
+		req.CreatedBy = c.GetString("user_id")
+	}
+
+	issued, err := h.apiKeyService.IssueKey(c.Request.Context(), &req)
+	if err != nil {
+		h.handleAPIKeyServiceError(c, err, "Failed to issue api key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+// ListKeys возвращает все ключи API, включая отозванные
+func (h *APIKeyHandler) ListKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		h.handleAPIKeyServiceError(c, err, "Failed to list api keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, &APIKeysResponse{Keys: keys})
+}
+
+// RevokeKey отзывает ключ API
+func (h *APIKeyHandler) RevokeKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid API key ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	key, err := h.apiKeyService.RevokeKey(c.Request.Context(), keyID, c.GetString("user_id"))
+	if err != nil {
+		h.handleAPIKeyServiceError(c, err, "Failed to revoke api key")
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// handleAPIKeyServiceError обрабатывает ошибки сервиса ключей API
+func (h *APIKeyHandler) handleAPIKeyServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrAPIKeyNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "API key not found",
+			Code:  "API_KEY_NOT_FOUND",
+		})
+	case entities.ErrAPIKeyRevoked:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "API key has already been revoked",
+			Code:  "API_KEY_REVOKED",
+		})
+	case entities.ErrInvalidAPIKeyRequest:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "API key requires a name, scopes (dispatcher or admin, optionally location:read:precise and pii:read), a creator, a non-negative rate limit and a future expiry",
+			Code:  "INVALID_API_KEY_REQUEST",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AuditHandler обработчик HTTP запросов журнала аудита водителей
+type AuditHandler struct {
+	auditService services.AuditService
+	logger       *zap.Logger
+}
+
+// NewAuditHandler создает новый AuditHandler
+func NewAuditHandler(auditService services.AuditService, logger *zap.Logger) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+		logger:       logger,
+	}
+}
+
+// AuditEntriesResponse ответ со страницей журнала аудита
+type AuditEntriesResponse struct {
+	Entries []*entities.AuditEntry `json:"entries"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты журнала аудита
+func (h *AuditHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/audit", h.ListEntries)
+	api.GET("/admin/drivers/:id/audit", h.ListDriverEntries)
+	api.GET("/admin/audit/chain/verify", h.VerifyChain)
+}
+
+// ListEntries ищет записи журнала аудита по водителю, автопарку, автору, событию, действию,
+// ID запроса и периоду created_at [from, to), новые записи первыми
+func (h *AuditHandler) ListEntries(c *gin.Context) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одну запись больше, чтобы определить наличие следующей страницы
+	filters := &entities.AuditFilters{
+		Limit:  page.Limit + 1,
+		Offset: page.Offset,
+	}
+	for name, value := range map[string]**uuid.UUID{"driver_id": &filters.DriverID, "fleet_id": &filters.FleetID} {
+		str := c.Query(name)
+		if str == "" {
+			continue
+		}
+		id, err := uuid.Parse(str)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid '" + name + "' format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		*value = &id
+	}
+	for name, value := range map[string]**string{
+		"actor":      &filters.Actor,
+		"event":      &filters.Event,
+		"action":     &filters.Action,
+		"request_id": &filters.RequestID,
+	} {
+		if str := c.Query(name); str != "" {
+			*value = &str
+		}
+	}
+	var from, to time.Time
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+	if !from.IsZero() {
+		filters.From = &from
+	}
+	if !to.IsZero() {
+		filters.To = &to
+	}
+
+	entries, err := h.auditService.ListEntries(c.Request.Context(), filters)
+	if err != nil {
+		h.handleAuditServiceError(c, err, "Failed to list audit entries")
+		return
+	}
+
+	hasMore := len(entries) > page.Limit
+	if hasMore {
+		entries = entries[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &AuditEntriesResponse{
+		Entries: entries,
+		Page:    pagination.Paginate(c, page, len(entries), nil, hasMore),
+	})
+}
+
+// ListDriverEntries получает журнал аудита водителя, новые записи первыми
+func (h *AuditHandler) ListDriverEntries(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одну запись больше, чтобы определить наличие следующей страницы
+	entries, err := h.auditService.ListDriverEntries(c.Request.Context(), driverID, page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleAuditServiceError(c, err, "Failed to list driver audit entries")
+		return
+	}
+
+	hasMore := len(entries) > page.Limit
+	if hasMore {
+		entries = entries[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &AuditEntriesResponse{
+		Entries: entries,
+		Page:    pagination.Paginate(c, page, len(entries), nil, hasMore),
+	})
+}
+
+// VerifyChain проверяет целостность цепочки хешей журнала. Параметры from и to — номера
+// записей; по умолчанию проверяется вся цепочка. Нарушение возвращается в поле break
+func (h *AuditHandler) VerifyChain(c *gin.Context) {
+	var bounds [2]int64
+	for i, name := range []string{"from", "to"} {
+		str := c.Query(name)
+		if str == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(str, 10, 64)
+		if err != nil || value < 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid '" + name + "' sequence",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected non-negative integer",
+			})
+			return
+		}
+		bounds[i] = value
+	}
+
+	verification, err := h.auditService.VerifyChain(c.Request.Context(), bounds[0], bounds[1])
+	if err != nil {
+		h.handleAuditServiceError(c, err, "Failed to verify audit chain")
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// handleAuditServiceError обрабатывает ошибки сервиса журнала аудита
+func (h *AuditHandler) handleAuditServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrAuditChainDisabled:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Audit hash chain is disabled",
+			Code:  "AUDIT_CHAIN_DISABLED",
+		})
+	case entities.ErrInvalidAuditRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid audit chain range",
+			Code:  "INVALID_AUDIT_RANGE",
+		})
+	case entities.ErrInvalidAuditFilters:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid audit filters",
+			Code:    "INVALID_AUDIT_FILTERS",
+			Details: "'from' must be before 'to'",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
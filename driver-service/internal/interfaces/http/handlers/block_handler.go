@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// BlockHandler обработчик HTTP запросов для блокировок водителей
+type BlockHandler struct {
+	blockService services.BlockService
+	logger       *zap.Logger
+}
+
+// NewBlockHandler создает новый BlockHandler
+func NewBlockHandler(blockService services.BlockService, logger *zap.Logger) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+		logger:       logger,
+	}
+}
+
+// UnblockRequest запрос снятия блокировки
+type UnblockRequest struct {
+	Comment string `json:"comment,omitempty"`
+}
+
+// RegisterRoutes регистрирует маршруты блокировок водителей
+func (h *BlockHandler) RegisterRoutes(api *gin.RouterGroup) {
+	blocks := api.Group("/admin/drivers/:id")
+	{
+		blocks.POST("/block", h.BlockDriver)
+		blocks.POST("/unblock", h.UnblockDriver)
+		blocks.GET("/blocks", h.ListBlocks)
+	}
+}
+
+// BlockDriver блокирует водителя с кодом причины; автор блокировки — субъект токена
+func (h *BlockHandler) BlockDriver(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.BlockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.BlockedBy = c.GetString("user_id")
+
+	block, err := h.blockService.Block(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleBlockServiceError(c, err, "Failed to block driver")
+		return
+	}
+
+	c.JSON(http.StatusCreated, block)
+}
+
+// UnblockDriver снимает активную блокировку водителя
+func (h *BlockHandler) UnblockDriver(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	// Комментарий необязателен, тело можно не передавать
+	var req UnblockRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request data",
+				Code:    "INVALID_REQUEST",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+
+	block, err := h.blockService.Unblock(c.Request.Context(), driverID, c.GetString("user_id"), req.Comment)
+	if err != nil {
+		h.handleBlockServiceError(c, err, "Failed to unblock driver")
+		return
+	}
+
+	c.JSON(http.StatusOK, block)
+}
+
+// ListBlocks возвращает историю блокировок водителя
+func (h *BlockHandler) ListBlocks(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	blocks, err := h.blockService.History(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleBlockServiceError(c, err, "Failed to list driver blocks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blocks": blocks,
+		"total":  len(blocks),
+	})
+}
+
+// handleBlockServiceError обрабатывает ошибки из BlockService
+func (h *BlockHandler) handleBlockServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidBlock:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Block requires a known reason code and an unblock time in the future",
+			Code:    "INVALID_BLOCK",
+			Details: "reason must be one of fraud, document_fraud, safety",
+		})
+	case entities.ErrDriverAlreadyBlocked:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver is already blocked",
+			Code:  "DRIVER_ALREADY_BLOCKED",
+		})
+	case entities.ErrDriverNotBlocked:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver has no active block",
+			Code:  "DRIVER_NOT_BLOCKED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
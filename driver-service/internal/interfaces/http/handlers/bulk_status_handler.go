@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// BulkStatusHandler обработчик HTTP запросов для массовой смены статуса водителей
+type BulkStatusHandler struct {
+	bulkStatusService services.BulkStatusService
+	logger            *zap.Logger
+}
+
+// NewBulkStatusHandler создает новый BulkStatusHandler
+func NewBulkStatusHandler(bulkStatusService services.BulkStatusService, logger *zap.Logger) *BulkStatusHandler {
+	return &BulkStatusHandler{
+		bulkStatusService: bulkStatusService,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты массовой смены статуса
+func (h *BulkStatusHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/admin/drivers/bulk-status", h.ChangeStatus)
+}
+
+// ChangeStatus переводит список водителей в статус и возвращает итог по каждому водителю.
+// Ошибки отдельных водителей не меняют код ответа; автор операции — субъект токена
+func (h *BulkStatusHandler) ChangeStatus(c *gin.Context) {
+	var req entities.BulkStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.ActorID = c.GetString("user_id")
+
+	report, err := h.bulkStatusService.ChangeStatus(c.Request.Context(), &req)
+	if err != nil {
+		h.handleBulkStatusServiceError(c, err, "Failed to change driver statuses")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleBulkStatusServiceError обрабатывает ошибки массовой смены статуса
+func (h *BulkStatusHandler) handleBulkStatusServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrInvalidBulkStatus:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Bulk status change requires a known status and a list of driver IDs",
+			Code:    "INVALID_BULK_STATUS",
+			Details: fmt.Sprintf("driver_ids must contain from 1 to %d drivers", entities.MaxBulkStatusDrivers),
+		})
+	case entities.ErrBlockRequiresReason:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Drivers are blocked via POST /admin/drivers/{id}/block with a reason code",
+			Code:  "BLOCK_REQUIRES_REASON",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// CapacityHandler обработчик HTTP запросов для планирования мощностей
+type CapacityHandler struct {
+	capacityService services.CapacityService
+	logger          *zap.Logger
+}
+
+// NewCapacityHandler создает новый CapacityHandler
+func NewCapacityHandler(capacityService services.CapacityService, logger *zap.Logger) *CapacityHandler {
+	return &CapacityHandler{
+		capacityService: capacityService,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты планирования мощностей
+func (h *CapacityHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/admin/capacity/forecast", h.GetForecast)
+}
+
+// GetForecast возвращает прогноз нагрузки по последним суточным отчетам
+func (h *CapacityHandler) GetForecast(c *gin.Context) {
+	forecast, err := h.capacityService.GetForecast(c.Request.Context())
+	if err != nil {
+		h.handleCapacityServiceError(c, err, "Failed to get capacity forecast")
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// handleCapacityServiceError обрабатывает ошибки сервиса планирования мощностей
+func (h *CapacityHandler) handleCapacityServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrCapacityReportNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "No capacity reports yet",
+			Code:  "CAPACITY_REPORT_NOT_FOUND",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CityHandler обработчик HTTP запросов регионов и городов
+type CityHandler struct {
+	cityService services.CityService
+	logger      *zap.Logger
+}
+
+// NewCityHandler создает новый CityHandler
+func NewCityHandler(cityService services.CityService, logger *zap.Logger) *CityHandler {
+	return &CityHandler{
+		cityService: cityService,
+		logger:      logger,
+	}
+}
+
+// ListRegionsResponse все регионы
+type ListRegionsResponse struct {
+	Regions []*entities.Region `json:"regions"`
+}
+
+// ListCitiesResponse страница городов
+type ListCitiesResponse struct {
+	Cities []*entities.City `json:"cities"`
+	pagination.Page
+}
+
+// CityCapacityResponse активные водители по городам
+type CityCapacityResponse struct {
+	Cities []*entities.CityDriverCount `json:"cities"`
+}
+
+// RegisterRoutes регистрирует маршруты регионов и городов
+func (h *CityHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/regions", h.CreateRegion)
+	api.GET("/regions", h.ListRegions)
+	api.POST("/cities", h.CreateCity)
+	api.GET("/cities", h.ListCities)
+	api.GET("/cities/:id", h.GetCity)
+	api.PUT("/cities/:id", h.UpdateCity)
+	api.DELETE("/cities/:id", h.DeleteCity)
+	api.GET("/admin/capacity/cities", h.GetCityCapacity)
+}
+
+// CreateRegion создает регион
+func (h *CityHandler) CreateRegion(c *gin.Context) {
+	var req entities.RegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid create region request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	region, err := h.cityService.CreateRegion(c.Request.Context(), &req)
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to create region")
+		return
+	}
+
+	c.JSON(http.StatusCreated, region)
+}
+
+// ListRegions возвращает все регионы по коду
+func (h *CityHandler) ListRegions(c *gin.Context) {
+	regions, err := h.cityService.ListRegions(c.Request.Context())
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to list regions")
+		return
+	}
+
+	c.JSON(http.StatusOK, &ListRegionsResponse{Regions: regions})
+}
+
+// CreateCity создает город
+func (h *CityHandler) CreateCity(c *gin.Context) {
+	var req entities.CityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid create city request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	city, err := h.cityService.CreateCity(c.Request.Context(), &req)
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to create city")
+		return
+	}
+
+	c.JSON(http.StatusCreated, city)
+}
+
+// ListCities возвращает города по названию; region_id отбирает города региона,
+// active=true|false — по активности
+func (h *CityHandler) ListCities(c *gin.Context) {
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 200})
+	if !ok {
+		return
+	}
+	filters := &entities.CityFilters{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if regionStr := c.Query("region_id"); regionStr != "" {
+		regionID, err := uuid.Parse(regionStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid region ID format",
+				Code:  "INVALID_ID",
+			})
+			return
+		}
+		filters.RegionID = &regionID
+	}
+	if activeStr := c.Query("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid active format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		filters.Active = &active
+	}
+
+	cities, err := h.cityService.ListCities(c.Request.Context(), filters)
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to list cities")
+		return
+	}
+
+	total, err := h.cityService.CountCities(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count cities",
+			zap.Error(err),
+		)
+		total = len(cities)
+	}
+
+	c.JSON(http.StatusOK, &ListCitiesResponse{
+		Cities: cities,
+		Page:   pagination.Paginate(c, page, len(cities), pagination.Total(total), false),
+	})
+}
+
+// GetCity возвращает город
+func (h *CityHandler) GetCity(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	city, err := h.cityService.GetCity(c.Request.Context(), id)
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to get city")
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+// UpdateCity заменяет город; ключ города не меняется
+func (h *CityHandler) UpdateCity(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.CityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid update city request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	city, err := h.cityService.UpdateCity(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to update city")
+		return
+	}
+
+	c.JSON(http.StatusOK, city)
+}
+
+// DeleteCity удаляет город
+func (h *CityHandler) DeleteCity(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.cityService.DeleteCity(c.Request.Context(), id); err != nil {
+		h.handleCityServiceError(c, err, "Failed to delete city")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetCityCapacity возвращает число активных водителей по городам для планирования мощностей
+func (h *CityHandler) GetCityCapacity(c *gin.Context) {
+	counts, err := h.cityService.ActiveDriverCounts(c.Request.Context())
+	if err != nil {
+		h.handleCityServiceError(c, err, "Failed to count active drivers by city")
+		return
+	}
+
+	c.JSON(http.StatusOK, &CityCapacityResponse{Cities: counts})
+}
+
+// parseID разбирает ID города из пути
+func (h *CityHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid city ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// handleCityServiceError обрабатывает ошибки из CityService
+func (h *CityHandler) handleCityServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrRegionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Region not found",
+			Code:  "REGION_NOT_FOUND",
+		})
+	case entities.ErrInvalidRegion:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid region",
+			Code:    "INVALID_REGION",
+			Details: "code is required (up to 16 characters); name is required (up to 100 characters)",
+		})
+	case entities.ErrRegionExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Region with this code already exists",
+			Code:  "REGION_EXISTS",
+		})
+	case entities.ErrCityNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "City not found",
+			Code:  "CITY_NOT_FOUND",
+		})
+	case entities.ErrInvalidCity:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid city",
+			Code:  "INVALID_CITY",
+			Details: "region_id and name (up to 100 characters) are required; key is up to 64 characters; " +
+				"boundary needs 3-5000 non-collinear vertices",
+		})
+	case entities.ErrCityExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "City with this key already exists",
+			Code:  "CITY_EXISTS",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DatabaseStatsProvider источник статистики подключения к базе данных
+type DatabaseStatsProvider interface {
+	GetStats() sql.DBStats
+	RetryStats() database.RetryStatsSnapshot
+	ReplicaStatuses() []database.ReplicaStatus
+}
+
+// DatabaseHandler обработчик HTTP запросов для статистики базы данных
+type DatabaseHandler struct {
+	provider DatabaseStatsProvider
+}
+
+// NewDatabaseHandler создает новый DatabaseHandler
+func NewDatabaseHandler(provider DatabaseStatsProvider) *DatabaseHandler {
+	return &DatabaseHandler{
+		provider: provider,
+	}
+}
+
+// DatabasePoolStats состояние пула соединений
+type DatabasePoolStats struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+}
+
+// RegisterRoutes регистрирует маршруты статистики базы данных
+func (h *DatabaseHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/admin/database/stats", h.GetStats)
+}
+
+// GetStats возвращает состояние пула соединений, частоту повторов при временных ошибках
+// и доступность реплик для чтения
+func (h *DatabaseHandler) GetStats(c *gin.Context) {
+	stats := h.provider.GetStats()
+
+	c.JSON(http.StatusOK, gin.H{
+		"pool": DatabasePoolStats{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		},
+		"retries":  h.provider.RetryStats(),
+		"replicas": h.provider.ReplicaStatuses(),
+	})
+}
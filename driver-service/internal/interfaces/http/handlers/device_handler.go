@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeviceHandler обработчик HTTP запросов для устройств водителей
+type DeviceHandler struct {
+	deviceService services.DeviceService
+	logger        *zap.Logger
+}
+
+// NewDeviceHandler создает новый DeviceHandler
+func NewDeviceHandler(deviceService services.DeviceService, logger *zap.Logger) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты устройств водителей
+func (h *DeviceHandler) RegisterRoutes(api *gin.RouterGroup) {
+	devices := api.Group("/drivers/:id/devices")
+	{
+		devices.POST("", h.RegisterDevice)
+		devices.GET("", h.ListDevices)
+		devices.DELETE("/:device_id", h.RevokeDevice)
+	}
+}
+
+// RegisterDevice регистрирует устройство водителя или обновляет версию приложения и
+// токен push-уведомлений. Приложение вызывает его после входа и при смене токена
+func (h *DeviceHandler) RegisterDevice(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.DeviceRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid register device request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	device, err := h.deviceService.Register(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleDeviceServiceError(c, err, "Failed to register driver device")
+		return
+	}
+
+	c.JSON(http.StatusOK, device.ToResponse())
+}
+
+// ListDevices возвращает устройства водителя, включая отозванные
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	devices, err := h.deviceService.List(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleDeviceServiceError(c, err, "Failed to list driver devices")
+		return
+	}
+
+	response := make([]*entities.DriverDeviceResponse, len(devices))
+	for i, device := range devices {
+		response[i] = device.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": response,
+		"total":   len(response),
+	})
+}
+
+// RevokeDevice отзывает устройство водителя и сессии с него
+func (h *DeviceHandler) RevokeDevice(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	device, err := h.deviceService.Revoke(c.Request.Context(), driverID, c.Param("device_id"))
+	if err != nil {
+		h.handleDeviceServiceError(c, err, "Failed to revoke driver device")
+		return
+	}
+
+	c.JSON(http.StatusOK, device.ToResponse())
+}
+
+// handleDeviceServiceError обрабатывает ошибки из DeviceService
+func (h *DeviceHandler) handleDeviceServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrDeviceNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Device not found",
+			Code:  "DEVICE_NOT_FOUND",
+		})
+	case entities.ErrInvalidDevice:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid device",
+			Code:    "INVALID_DEVICE",
+			Details: "expected device_id, platform (android, ios, web) and app_version",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
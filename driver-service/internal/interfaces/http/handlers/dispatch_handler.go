@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Ограничения числа кандидатов в ответе
+const (
+	defaultDispatchCandidates = 10
+	maxDispatchCandidates     = 50
+)
+
+// DispatchHandler обработчик HTTP запросов подбора водителя на заказ
+type DispatchHandler struct {
+	dispatchService services.DispatchScoringService
+	logger          *zap.Logger
+}
+
+// NewDispatchHandler создает новый DispatchHandler
+func NewDispatchHandler(dispatchService services.DispatchScoringService, logger *zap.Logger) *DispatchHandler {
+	return &DispatchHandler{
+		dispatchService: dispatchService,
+		logger:          logger,
+	}
+}
+
+// DispatchCandidatesResponse кандидаты на заказ от лучшего к худшему
+type DispatchCandidatesResponse struct {
+	Candidates []*entities.DispatchCandidate `json:"candidates"`
+	Count      int                           `json:"count"`
+}
+
+// RegisterRoutes регистрирует маршруты подбора водителя
+func (h *DispatchHandler) RegisterRoutes(api *gin.RouterGroup) {
+	dispatch := api.Group("/dispatch")
+	{
+		dispatch.GET("/candidates", h.GetCandidates)
+		dispatch.POST("/offers", h.RecordOffer)
+		dispatch.POST("/cancellations", h.RecordCancellation)
+	}
+}
+
+// GetCandidates возвращает свободных водителей около точки подачи, отсортированных по оценке
+func (h *DispatchHandler) GetCandidates(c *gin.Context) {
+	query := &entities.DispatchCandidateQuery{Limit: defaultDispatchCandidates}
+
+	var err error
+	if query.Latitude, err = strconv.ParseFloat(c.Query("lat"), 64); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid or missing lat",
+			Code:  "INVALID_PARAMETER",
+		})
+		return
+	}
+	if query.Longitude, err = strconv.ParseFloat(c.Query("lon"), 64); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid or missing lon",
+			Code:  "INVALID_PARAMETER",
+		})
+		return
+	}
+
+	if radiusStr := c.Query("radius_km"); radiusStr != "" {
+		radiusKm, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radiusKm <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid radius_km",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		query.RadiusKm = radiusKm
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxDispatchCandidates {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid limit",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected 1.." + strconv.Itoa(maxDispatchCandidates),
+			})
+			return
+		}
+		query.Limit = limit
+	}
+
+	// tag оставляет только водителей с нужными заказу метками, например pet-friendly
+	if !parseTagsParam(c, &query.Tags) {
+		return
+	}
+
+	// При шардировании город ограничивает поиск одним шардом
+	ctx := c.Request.Context()
+	if city := c.Query("city"); city != "" {
+		ctx = entities.WithShardCity(ctx, city)
+	}
+
+	candidates, err := h.dispatchService.GetCandidates(ctx, query)
+	if err != nil {
+		h.handleDispatchServiceError(c, err, "Failed to get dispatch candidates")
+		return
+	}
+
+	// Без доступа к точным координатам расстояние до точки подачи считается до огрубленной точки
+	if !preciseLocationAllowed(c) {
+		pickup := &entities.DriverLocation{Latitude: query.Latitude, Longitude: query.Longitude}
+		for _, candidate := range candidates {
+			point := &entities.DriverLocation{
+				Latitude:  entities.CoarsenCoordinate(candidate.Latitude),
+				Longitude: entities.CoarsenCoordinate(candidate.Longitude),
+			}
+			candidate.Latitude = point.Latitude
+			candidate.Longitude = point.Longitude
+			candidate.DistanceKm = pickup.DistanceTo(point)
+		}
+	}
+
+	c.JSON(http.StatusOK, &DispatchCandidatesResponse{
+		Candidates: candidates,
+		Count:      len(candidates),
+	})
+}
+
+// RecordOffer учитывает ответ водителя на предложение заказа
+func (h *DispatchHandler) RecordOffer(c *gin.Context) {
+	var req entities.DispatchOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid dispatch offer request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.dispatchService.RecordOffer(c.Request.Context(), &req); err != nil {
+		h.handleDispatchServiceError(c, err, "Failed to record dispatch offer")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// RecordCancellation учитывает отмену водителем принятого заказа
+func (h *DispatchHandler) RecordCancellation(c *gin.Context) {
+	var req entities.DispatchCancellationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid dispatch cancellation request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.dispatchService.RecordCancellation(c.Request.Context(), &req); err != nil {
+		h.handleDispatchServiceError(c, err, "Failed to record dispatch cancellation")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// handleDispatchServiceError обрабатывает ошибки из DispatchScoringService
+func (h *DispatchHandler) handleDispatchServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidLocation:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid pickup coordinates",
+			Code:  "INVALID_LOCATION",
+		})
+	case entities.ErrInvalidDispatchOffer, entities.ErrInvalidTimestamp:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid dispatch offer",
+			Code:  "INVALID_DISPATCH_OFFER",
+		})
+	case entities.ErrDispatchOfferNotAccepted:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Driver has not accepted this order",
+			Code:    "DISPATCH_OFFER_NOT_ACCEPTED",
+			Details: "report the acceptance via POST /dispatch/offers first",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DocumentHandler обработчик HTTP запросов для документов водителя
+type DocumentHandler struct {
+	renewalService services.DocumentRenewalService
+	logger         *zap.Logger
+}
+
+// NewDocumentHandler создает новый DocumentHandler
+func NewDocumentHandler(renewalService services.DocumentRenewalService, logger *zap.Logger) *DocumentHandler {
+	return &DocumentHandler{
+		renewalService: renewalService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты документов водителя
+func (h *DocumentHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.GET("/:id/documents", h.GetDriverDocuments)
+		drivers.GET("/:id/documents/history", h.GetDocumentHistory)
+		drivers.POST("/:id/documents/:document_id/renewals", h.RenewDocument)
+		drivers.POST("/:id/documents/:document_id/resubmissions", h.ResubmitDocument)
+		drivers.GET("/:id/documents/:document_id/versions", h.GetDocumentVersions)
+	}
+}
+
+// GetDriverDocuments возвращает действующие документы водителя и состояние их продления
+func (h *DocumentHandler) GetDriverDocuments(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	documents, err := h.renewalService.GetDriverDocuments(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleDocumentServiceError(c, err, "Failed to get driver documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+// RenewDocument загружает новую версию документа (multipart/form-data: поле file,
+// document_number, issue_date и expiry_date в формате YYYY-MM-DD)
+func (h *DocumentHandler) RenewDocument(c *gin.Context) {
+	h.uploadVersion(c, h.renewalService.RenewDocument, "Failed to renew document")
+}
+
+// ResubmitDocument загружает новую версию отклоненного документа в том же формате,
+// что и продление
+func (h *DocumentHandler) ResubmitDocument(c *gin.Context) {
+	h.uploadVersion(c, h.renewalService.ResubmitDocument, "Failed to resubmit document")
+}
+
+// GetDocumentVersions возвращает документ и его версии, замененные повторной загрузкой
+func (h *DocumentHandler) GetDocumentVersions(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid document ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	history, err := h.renewalService.GetDocumentVersions(c.Request.Context(), driverID, documentID)
+	if err != nil {
+		h.handleDocumentServiceError(c, err, "Failed to get document versions")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// GetDocumentHistory возвращает текущую версию документа типа ?type= и его прежние версии
+func (h *DocumentHandler) GetDocumentHistory(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	docType := entities.DocumentType(c.Query("type"))
+	history, err := h.renewalService.GetDocumentHistory(c.Request.Context(), driverID, docType)
+	if err != nil {
+		h.handleDocumentServiceError(c, err, "Failed to get document history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// documentVersionUpload загружает новую версию документа водителя
+type documentVersionUpload func(ctx context.Context, driverID, documentID uuid.UUID, req *entities.DocumentRenewalRequest, upload *services.FileUpload) (*entities.DriverDocument, error)
+
+// uploadVersion разбирает multipart-запрос с новой версией документа и передает его submit
+func (h *DocumentHandler) uploadVersion(c *gin.Context, submit documentVersionUpload, message string) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	documentID, err := uuid.Parse(c.Param("document_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid document ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req entities.DocumentRenewalRequest
+	if err := c.ShouldBind(&req); err != nil {
+		h.logger.Error("Invalid document upload request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Document file is required",
+			Code:    "FILE_REQUIRED",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	upload, file, err := openUpload(header)
+	if err != nil {
+		h.handleDocumentServiceError(c, err, "Failed to read document file")
+		return
+	}
+	defer file.Close()
+
+	document, err := submit(c.Request.Context(), driverID, documentID, &req, upload)
+	if err != nil {
+		h.handleDocumentServiceError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+// handleDocumentServiceError обрабатывает ошибки сервиса продления и повторной загрузки документов
+func (h *DocumentHandler) handleDocumentServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrDocumentNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Document not found",
+			Code:  "DOCUMENT_NOT_FOUND",
+		})
+	case entities.ErrDocumentNotRenewable:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Document is not due for renewal",
+			Code:  "DOCUMENT_NOT_RENEWABLE",
+		})
+	case entities.ErrDocumentNotResubmittable:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Only a rejected document can be resubmitted",
+			Code:  "DOCUMENT_NOT_RESUBMITTABLE",
+		})
+	case entities.ErrRenewalAlreadyPending, entities.ErrDocumentExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Document renewal is already pending review",
+			Code:  "RENEWAL_ALREADY_PENDING",
+		})
+	case entities.ErrInvalidDocumentFile:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Document must be a JPEG, PNG, HEIC or PDF file within the size limit",
+			Code:    "INVALID_DOCUMENT_FILE",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidDocumentType:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Document type is required",
+			Code:  "INVALID_DOCUMENT_TYPE",
+		})
+	case entities.ErrInvalidExpiryDate, entities.ErrInvalidDocumentNumber:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid document data",
+			Code:    "INVALID_DOCUMENT",
+			Details: err.Error(),
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -1,18 +1,31 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"driver-service/internal/domain/entities"
 	"driver-service/internal/domain/services"
+	"driver-service/internal/infrastructure/database"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/interfaces/http/pagination"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+const (
+	// headerStatusVersion версия статуса водителя в ответе (Driver.StatusVersion)
+	headerStatusVersion = "X-Driver-Status-Version"
+	// headerMinStatusVersion версия статуса из ответа на запись клиента: GET водителя не вернет
+	// статус старше нее, даже если реплика базы еще не получила изменение
+	headerMinStatusVersion = "X-Min-Status-Version"
+)
+
 // DriverHandler обработчик HTTP запросов для водителей
 type DriverHandler struct {
 	driverService services.DriverService
@@ -27,18 +40,21 @@ func NewDriverHandler(driverService services.DriverService, logger *zap.Logger)
 	}
 }
 
-// CreateDriverRequest запрос на создание водителя
+// CreateDriverRequest запрос на создание водителя.
+// Обязательны только данные этапа регистрации, остальное можно заполнить позже
 type CreateDriverRequest struct {
 	Phone          string    `json:"phone" binding:"required"`
-	Email          string    `json:"email" binding:"required,email"`
+	Email          string    `json:"email" binding:"omitempty,email"`
 	FirstName      string    `json:"first_name" binding:"required"`
 	LastName       string    `json:"last_name" binding:"required"`
 	MiddleName     *string   `json:"middle_name,omitempty"`
-	BirthDate      time.Time `json:"birth_date" binding:"required"`
-	PassportSeries string    `json:"passport_series" binding:"required"`
-	PassportNumber string    `json:"passport_number" binding:"required"`
-	LicenseNumber  string    `json:"license_number" binding:"required"`
-	LicenseExpiry  time.Time `json:"license_expiry" binding:"required"`
+	BirthDate      time.Time `json:"birth_date"`
+	PassportSeries string    `json:"passport_series"`
+	PassportNumber string    `json:"passport_number"`
+	LicenseNumber  string    `json:"license_number"`
+	LicenseExpiry  time.Time `json:"license_expiry"`
+	// FleetID автопарк водителя: регистрацию проверяет вебхук автопарка, если он настроен
+	FleetID *uuid.UUID `json:"fleet_id,omitempty"`
 }
 
 // UpdateDriverRequest запрос на обновление водителя
@@ -50,6 +66,7 @@ type UpdateDriverRequest struct {
 	BirthDate      *time.Time `json:"birth_date,omitempty"`
 	PassportSeries *string    `json:"passport_series,omitempty"`
 	PassportNumber *string    `json:"passport_number,omitempty"`
+	LicenseNumber  *string    `json:"license_number,omitempty"`
 	LicenseExpiry  *time.Time `json:"license_expiry,omitempty"`
 }
 
@@ -66,28 +83,57 @@ type DriverResponse struct {
 	FirstName       string            `json:"first_name"`
 	LastName        string            `json:"last_name"`
 	MiddleName      *string           `json:"middle_name,omitempty"`
-	BirthDate       time.Time         `json:"birth_date"`
-	PassportSeries  string            `json:"passport_series"`
-	PassportNumber  string            `json:"passport_number"`
-	LicenseNumber   string            `json:"license_number"`
-	LicenseExpiry   time.Time         `json:"license_expiry"`
+	// Паспортные данные, дата рождения и данные удостоверения отдаются только вызывающим
+	// с областью pii:read и самому водителю; скрытые поля перечислены в RedactedFields
+	BirthDate       *time.Time        `json:"birth_date,omitempty"`
+	PassportSeries  string            `json:"passport_series,omitempty"`
+	PassportNumber  string            `json:"passport_number,omitempty"`
+	LicenseNumber   string            `json:"license_number,omitempty"`
+	LicenseExpiry   *time.Time        `json:"license_expiry,omitempty"`
 	Status          entities.Status   `json:"status"`
 	CurrentRating   float64           `json:"current_rating"`
 	TotalTrips      int               `json:"total_trips"`
+	FleetID         *uuid.UUID        `json:"fleet_id,omitempty"`
 	Metadata        entities.Metadata `json:"metadata,omitempty"`
+	PaymentHold     *PaymentHoldInfo  `json:"payment_hold,omitempty"`
 	CreatedAt       time.Time         `json:"created_at"`
 	UpdatedAt       time.Time         `json:"updated_at"`
+
+	// Tags метки водителя; пустой список, если меток нет
+	Tags entities.DriverTagList `json:"tags"`
+	// PhoneVerifiedAt когда телефон подтвержден кодом из SMS
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at,omitempty"`
+	// Доли принятых и отмененных предложений заказов за окно учета (dispatch.stats_window)
+	AcceptanceRate   *float64 `json:"acceptance_rate,omitempty"`
+	CancellationRate *float64 `json:"cancellation_rate,omitempty"`
+	// StatusVersion версия статуса; повторяется в заголовке X-Driver-Status-Version
+	StatusVersion int64 `json:"status_version"`
+	// RedactedFields поля, скрытые от вызывающего без области pii:read
+	RedactedFields []string `json:"redacted_fields,omitempty"`
+}
+
+// PaymentHoldInfo сведения о блокировке со стороны биллинга (только категория причины)
+type PaymentHoldInfo struct {
+	Reason string     `json:"reason"`
+	Since  *time.Time `json:"since,omitempty"`
 }
 
 // ListDriversResponse ответ со списком водителей
 type ListDriversResponse struct {
-	Drivers    []*DriverResponse `json:"drivers"`
-	Total      int               `json:"total"`
-	Limit      int               `json:"limit"`
-	Offset     int               `json:"offset"`
-	HasMore    bool              `json:"has_more"`
+	Drivers []*DriverResponse `json:"drivers"`
+	pagination.Page
 }
 
+// ActiveDriversResponse ответ со списком активных водителей
+type ActiveDriversResponse struct {
+	Drivers []*DriverResponse `json:"drivers"`
+	Count   int               `json:"count"`
+	pagination.Page
+}
+
+// activeDriversPage ограничения страницы активных водителей: диспетчерская карта запрашивает крупные страницы
+var activeDriversPage = pagination.Options{DefaultLimit: 100, MaxLimit: 500}
+
 // ErrorResponse стандартный ответ с ошибкой
 type ErrorResponse struct {
 	Error   string `json:"error"`
@@ -104,6 +150,7 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 		)
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
 			Details: err.Error(),
 		})
 		return
@@ -122,6 +169,7 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 		LicenseNumber:  req.LicenseNumber,
 		LicenseExpiry:  req.LicenseExpiry,
 	}
+	driver.FleetID = req.FleetID
 
 	// Создаем водителя через сервис
 	createdDriver, err := h.driverService.CreateDriver(c.Request.Context(), driver)
@@ -130,7 +178,8 @@ func (h *DriverHandler) CreateDriver(c *gin.Context) {
 		return
 	}
 
-	response := h.toDriverResponse(createdDriver)
+	response := toDriverResponse(c, createdDriver)
+	setStatusVersion(c, createdDriver)
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -141,17 +190,39 @@ func (h *DriverHandler) GetDriver(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
 
+	minVersion, ok := parseMinStatusVersion(c)
+	if !ok {
+		return
+	}
+
 	driver, err := h.driverService.GetDriverByID(c.Request.Context(), driverID)
 	if err != nil {
 		h.handleServiceError(c, err, "Failed to get driver")
 		return
 	}
 
-	response := h.toDriverResponse(driver)
+	// Реплика еще не получила смену статуса, о которой клиент уже знает: читаем из основной базы
+	if driver.StatusVersion < minVersion {
+		h.logger.Debug("Stale driver status version, reading from primary",
+			zap.String("driver_id", driverID.String()),
+			zap.Int64("status_version", driver.StatusVersion),
+			zap.Int64("min_status_version", minVersion),
+		)
+		driver, err = h.driverService.GetDriverByID(database.WithPrimaryReads(c.Request.Context()), driverID)
+		if err != nil {
+			h.handleServiceError(c, err, "Failed to get driver")
+			return
+		}
+	}
+
+	response := toDriverResponse(c, driver)
+	setStatusVersion(c, driver)
+	middleware.SetLastModified(c, driver.UpdatedAt)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -162,6 +233,7 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
@@ -173,6 +245,7 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 		)
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
 			Details: err.Error(),
 		})
 		return
@@ -184,6 +257,7 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 		h.handleServiceError(c, err, "Failed to get driver for update")
 		return
 	}
+	before := *driver
 
 	// Обновляем только переданные поля
 	if req.Email != nil {
@@ -207,9 +281,15 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 	if req.PassportNumber != nil {
 		driver.PassportNumber = *req.PassportNumber
 	}
+	if req.LicenseNumber != nil {
+		driver.LicenseNumber = *req.LicenseNumber
+	}
 	if req.LicenseExpiry != nil {
 		driver.LicenseExpiry = *req.LicenseExpiry
 	}
+	if rejectUnapprovedProfileChange(c, &before, driver) {
+		return
+	}
 
 	// Обновляем водителя через сервис
 	updatedDriver, err := h.driverService.UpdateDriver(c.Request.Context(), driver)
@@ -218,10 +298,116 @@ func (h *DriverHandler) UpdateDriver(c *gin.Context) {
 		return
 	}
 
-	response := h.toDriverResponse(updatedDriver)
+	response := toDriverResponse(c, updatedDriver)
+	setStatusVersion(c, updatedDriver)
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchDriver частично обновляет профиль водителя. Тело — JSON merge patch (RFC 7396):
+// переданные поля изменяются, null очищает поле. Параметр update_mask ("email,last_name")
+// ограничивает изменяемые поля; поле из маски, отсутствующее в теле, очищается
+func (h *DriverHandler) PatchDriver(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		h.handlePatchError(c, err, "Failed to read driver patch")
+		return
+	}
+
+	var mask []string
+	for _, value := range c.QueryArray("update_mask") {
+		mask = append(mask, strings.Split(value, ",")...)
+	}
+
+	patch, err := entities.ParseDriverPatch(body, mask)
+	if err != nil {
+		h.handlePatchError(c, err, "Invalid driver patch")
+		return
+	}
+
+	driver, err := h.driverService.GetDriverByID(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleServiceError(c, err, "Failed to get driver for patch")
+		return
+	}
+
+	if patch.IsEmpty() {
+		setStatusVersion(c, driver)
+		c.JSON(http.StatusOK, toDriverResponse(c, driver))
+		return
+	}
+
+	before := *driver
+	if err := patch.Apply(driver); err != nil {
+		h.handlePatchError(c, err, "Invalid driver patch")
+		return
+	}
+	if rejectUnapprovedProfileChange(c, &before, driver) {
+		return
+	}
+
+	// Обновление идет через UpdateDriver: проверки автопарка и аудит действуют так же, как для PUT
+	updatedDriver, err := h.driverService.UpdateDriver(c.Request.Context(), driver)
+	if err != nil {
+		h.handlePatchError(c, err, "Failed to patch driver")
+		return
+	}
+
+	h.logger.Info("Driver patched",
+		zap.String("driver_id", driverID.String()),
+		zap.Strings("fields", patch.Fields()),
+	)
+	setStatusVersion(c, updatedDriver)
+	c.JSON(http.StatusOK, toDriverResponse(c, updatedDriver))
+}
+
+// handlePatchError обрабатывает ошибки частичного обновления; остальные ошибки
+// обрабатываются как ошибки DriverService
+func (h *DriverHandler) handlePatchError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, entities.ErrProtectedField):
+		h.logger.Warn(message, zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Field cannot be changed",
+			Code:    "PROTECTED_FIELD",
+			Details: err.Error(),
+		})
+	case errors.Is(err, entities.ErrUnknownField):
+		h.logger.Warn(message, zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Unknown field",
+			Code:    "UNKNOWN_FIELD",
+			Details: err.Error(),
+		})
+	case errors.Is(err, entities.ErrInvalidPatch):
+		h.logger.Warn(message, zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_PATCH",
+			Details: err.Error(),
+		})
+	case errors.Is(err, entities.ErrInvalidEmail), errors.Is(err, entities.ErrInvalidName),
+		errors.Is(err, entities.ErrInvalidLicense), errors.Is(err, entities.ErrInvalidPassport),
+		errors.Is(err, entities.ErrInvalidBirthDate), errors.Is(err, entities.ErrLicenseExpired):
+		h.logger.Warn(message, zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver data",
+			Code:    "INVALID_DATA",
+			Details: err.Error(),
+		})
+	default:
+		h.handleServiceError(c, err, message)
+	}
+}
+
 // DeleteDriver удаляет водителя
 func (h *DriverHandler) DeleteDriver(c *gin.Context) {
 	driverIDStr := c.Param("id")
@@ -229,6 +415,7 @@ func (h *DriverHandler) DeleteDriver(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
@@ -244,7 +431,15 @@ func (h *DriverHandler) DeleteDriver(c *gin.Context) {
 
 // ListDrivers получает список водителей с фильтрами
 func (h *DriverHandler) ListDrivers(c *gin.Context) {
-	filters := &entities.DriverFilters{}
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	filters := &entities.DriverFilters{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
 
 	// Парсим параметры запроса
 	if statusStr := c.Query("status"); statusStr != "" {
@@ -263,20 +458,27 @@ func (h *DriverHandler) ListDrivers(c *gin.Context) {
 		}
 	}
 
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			filters.Limit = limit
-		} else {
-			filters.Limit = 20 // значение по умолчанию
-		}
-	} else {
-		filters.Limit = 20
+	// city отбирает водителей по городу, определенному по местоположениям, или по городу из метаданных
+	if city := c.Query("city"); city != "" {
+		filters.City = &city
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
-			filters.Offset = offset
+	// fleet_id можно передать несколько раз; партнеру доступны только его автопарки
+	for _, fleetIDStr := range c.QueryArray("fleet_id") {
+		fleetID, err := uuid.Parse(fleetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid fleet ID format",
+				Code:  "INVALID_ID",
+			})
+			return
 		}
+		filters.FleetIDs = append(filters.FleetIDs, fleetID)
+	}
+
+	// tag отбирает водителей со всеми перечисленными метками
+	if !parseTagsParam(c, &filters.Tags) {
+		return
 	}
 
 	if sortBy := c.Query("sort_by"); sortBy != "" {
@@ -307,15 +509,12 @@ func (h *DriverHandler) ListDrivers(c *gin.Context) {
 	// Преобразуем в ответ
 	driverResponses := make([]*DriverResponse, len(drivers))
 	for i, driver := range drivers {
-		driverResponses[i] = h.toDriverResponse(driver)
+		driverResponses[i] = toDriverResponse(c, driver)
 	}
 
 	response := &ListDriversResponse{
 		Drivers: driverResponses,
-		Total:   total,
-		Limit:   filters.Limit,
-		Offset:  filters.Offset,
-		HasMore: filters.Offset+len(drivers) < total,
+		Page:    pagination.Paginate(c, page, len(drivers), pagination.Total(total), false),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -328,6 +527,7 @@ func (h *DriverHandler) ChangeStatus(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
@@ -339,6 +539,7 @@ func (h *DriverHandler) ChangeStatus(c *gin.Context) {
 		)
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
 			Details: err.Error(),
 		})
 		return
@@ -353,52 +554,127 @@ func (h *DriverHandler) ChangeStatus(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "Driver status changed successfully",
 		"status":  status,
-	})
+	}
+
+	// Версию нового статуса назначает база; запрос не GET, поэтому водитель читается из основной
+	// базы. Статус уже изменен, так что ошибка чтения только лишает клиента версии
+	driver, err := h.driverService.GetDriverByID(c.Request.Context(), driverID)
+	if err != nil {
+		h.logger.Warn("Failed to get driver status version",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+	} else {
+		response["status_version"] = driver.StatusVersion
+		setStatusVersion(c, driver)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// setStatusVersion передает версию статуса водителя в заголовке X-Driver-Status-Version
+func setStatusVersion(c *gin.Context, driver *entities.Driver) {
+	c.Header(headerStatusVersion, strconv.FormatInt(driver.StatusVersion, 10))
+}
+
+// parseMinStatusVersion разбирает заголовок X-Min-Status-Version; без заголовка возвращает 0.
+// Если значение некорректно, отвечает 400 и возвращает false
+func parseMinStatusVersion(c *gin.Context) (int64, bool) {
+	value := c.GetHeader(headerMinStatusVersion)
+	if value == "" {
+		return 0, true
+	}
+
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid " + headerMinStatusVersion + " header",
+			Code:  "INVALID_STATUS_VERSION",
+		})
+		return 0, false
+	}
+	return version, true
 }
 
 // GetActiveDrivers получает список активных водителей
 func (h *DriverHandler) GetActiveDrivers(c *gin.Context) {
+	page, ok := parsePage(c, activeDriversPage)
+	if !ok {
+		return
+	}
+
 	drivers, err := h.driverService.GetActiveDrivers(c.Request.Context())
 	if err != nil {
 		h.handleServiceError(c, err, "Failed to get active drivers")
 		return
 	}
+	pageDrivers := pagination.Slice(drivers, page)
 
 	// Преобразуем в ответ
-	driverResponses := make([]*DriverResponse, len(drivers))
-	for i, driver := range drivers {
-		driverResponses[i] = h.toDriverResponse(driver)
+	driverResponses := make([]*DriverResponse, len(pageDrivers))
+	for i, driver := range pageDrivers {
+		driverResponses[i] = toDriverResponse(c, driver)
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"drivers": driverResponses,
-		"count":   len(driverResponses),
+	c.JSON(http.StatusOK, &ActiveDriversResponse{
+		Drivers: driverResponses,
+		Count:   len(driverResponses),
+		Page:    pagination.Paginate(c, page, len(pageDrivers), pagination.Total(len(drivers)), false),
 	})
 }
 
-// toDriverResponse преобразует Driver entity в DriverResponse
-func (h *DriverHandler) toDriverResponse(driver *entities.Driver) *DriverResponse {
-	return &DriverResponse{
+// toDriverResponse преобразует Driver entity в DriverResponse для вызывающего запроса c:
+// персональные данные скрываются, если вызывающему они недоступны (piiAllowed)
+func toDriverResponse(c *gin.Context, driver *entities.Driver) *DriverResponse {
+	response := &DriverResponse{
 		ID:              driver.ID,
 		Phone:           driver.Phone,
 		Email:           driver.Email,
 		FirstName:       driver.FirstName,
 		LastName:        driver.LastName,
 		MiddleName:      driver.MiddleName,
-		BirthDate:       driver.BirthDate,
+		BirthDate:       &driver.BirthDate,
 		PassportSeries:  driver.PassportSeries,
 		PassportNumber:  driver.PassportNumber,
 		LicenseNumber:   driver.LicenseNumber,
-		LicenseExpiry:   driver.LicenseExpiry,
+		LicenseExpiry:   &driver.LicenseExpiry,
 		Status:          driver.Status,
 		CurrentRating:   driver.CurrentRating,
 		TotalTrips:      driver.TotalTrips,
+		FleetID:         driver.FleetID,
 		Metadata:        driver.Metadata,
+		Tags:            driver.Tags.Without(nil),
+		PhoneVerifiedAt: driver.PhoneVerifiedAt,
+		AcceptanceRate:   driver.AcceptanceRate,
+		CancellationRate: driver.CancellationRate,
+		StatusVersion:   driver.StatusVersion,
 		CreatedAt:       driver.CreatedAt,
 		UpdatedAt:       driver.UpdatedAt,
+		PaymentHold:     toPaymentHoldInfo(driver),
+	}
+	if !piiAllowed(c, driver.ID) {
+		redactDriverResponse(response)
+	}
+	return response
+}
+
+// toPaymentHoldInfo возвращает сведения о блокировке водителя, если она установлена
+func toPaymentHoldInfo(driver *entities.Driver) *PaymentHoldInfo {
+	if !driver.PaymentHold {
+		return nil
+	}
+
+	reason := entities.PaymentHoldReasonOther
+	if driver.PaymentHoldReason != nil {
+		reason = *driver.PaymentHoldReason
+	}
+
+	return &PaymentHoldInfo{
+		Reason: reason,
+		Since:  driver.PaymentHoldAt,
 	}
 }
 
@@ -406,6 +682,24 @@ func (h *DriverHandler) toDriverResponse(driver *entities.Driver) *DriverRespons
 func (h *DriverHandler) handleServiceError(c *gin.Context, err error, message string) {
 	h.logger.Error(message, zap.Error(err))
 
+	// Решение вебхука автопарка приходит с причиной отказа
+	switch {
+	case errors.Is(err, entities.ErrFleetValidationRejected):
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Change rejected by fleet",
+			Code:    "FLEET_VALIDATION_REJECTED",
+			Details: err.Error(),
+		})
+		return
+	case errors.Is(err, entities.ErrFleetValidationUnavailable):
+		c.Header("Retry-After", retryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Fleet validation is unavailable",
+			Code:  "FLEET_VALIDATION_UNAVAILABLE",
+		})
+		return
+	}
+
 	switch err {
 	case entities.ErrDriverNotFound:
 		c.JSON(http.StatusNotFound, ErrorResponse{
@@ -418,7 +712,7 @@ func (h *DriverHandler) handleServiceError(c *gin.Context, err error, message st
 			Code:  "DRIVER_EXISTS",
 		})
 	case entities.ErrInvalidPhone, entities.ErrInvalidEmail, entities.ErrInvalidName,
-		 entities.ErrInvalidLicense, entities.ErrInvalidPassport:
+		 entities.ErrInvalidLicense, entities.ErrInvalidPassport, entities.ErrInvalidBirthDate:
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver data",
 			Code:  "INVALID_DATA",
@@ -429,15 +723,43 @@ func (h *DriverHandler) handleServiceError(c *gin.Context, err error, message st
 			Error: "Driver is not available",
 			Code:  "DRIVER_NOT_AVAILABLE",
 		})
+	case entities.ErrDriverPaymentHold:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver is on payment hold",
+			Code:  "DRIVER_PAYMENT_HOLD",
+		})
+	case entities.ErrBlockRequiresReason:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Drivers are blocked via POST /admin/drivers/{id}/block with a reason code",
+			Code:  "BLOCK_REQUIRES_REASON",
+		})
+	case entities.ErrProfileIncomplete:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Driver profile is incomplete",
+			Code:  "PROFILE_INCOMPLETE",
+		})
+	case entities.ErrPhoneNotVerified:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Driver phone is not verified",
+			Code:    "PHONE_NOT_VERIFIED",
+			Details: "confirm the phone via POST /drivers/{id}/verify-phone",
+		})
 	case entities.ErrDriverBlocked, entities.ErrDriverSuspended:
 		c.JSON(http.StatusForbidden, ErrorResponse{
 			Error: "Driver is blocked or suspended",
 			Code:  "DRIVER_BLOCKED",
 		})
-	default:
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Internal server error",
-			Code:  "INTERNAL_ERROR",
+	case entities.ErrFleetNotFound:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Fleet not found",
+			Code:  "FLEET_NOT_FOUND",
+		})
+	case entities.ErrFleetOutOfScope:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Fleet is outside of the caller's scope",
+			Code:  "FLEET_OUT_OF_SCOPE",
 		})
+	default:
+		respondInternalError(c, err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverInviteHandler обработчик HTTP запросов приглашений водителей и регистрации по ним
+type DriverInviteHandler struct {
+	inviteService services.DriverInviteService
+	logger        *zap.Logger
+}
+
+// NewDriverInviteHandler создает новый DriverInviteHandler
+func NewDriverInviteHandler(inviteService services.DriverInviteService, logger *zap.Logger) *DriverInviteHandler {
+	return &DriverInviteHandler{
+		inviteService: inviteService,
+		logger:        logger,
+	}
+}
+
+// DriverInvitesResponse ответ со страницей приглашений
+type DriverInvitesResponse struct {
+	Invites []*entities.DriverInvite `json:"invites"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты управления приглашениями
+func (h *DriverInviteHandler) RegisterRoutes(api *gin.RouterGroup) {
+	admin := api.Group("/admin/driver-invites")
+	{
+		admin.POST("", h.CreateInvite)
+		admin.GET("", h.ListInvites)
+		admin.DELETE("/:id", h.RevokeInvite)
+	}
+}
+
+// RegisterPublicRoutes регистрирует регистрацию по приглашению: у водителя еще нет токена,
+// доступ дает подписанный токен приглашения в пути
+func (h *DriverInviteHandler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	public.POST("/registrations/:token", h.Register)
+}
+
+// CreateInvite выпускает приглашение водителя в автопарк и ссылку для приложения водителя
+func (h *DriverInviteHandler) CreateInvite(c *gin.Context) {
+	var req entities.CreateDriverInviteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	if req.CreatedBy == "" {
+		req.CreatedBy = c.GetString("user_id")
+	}
+
+	link, err := h.inviteService.CreateInvite(c.Request.Context(), &req)
+	if err != nil {
+		h.handleInviteServiceError(c, err, "Failed to create driver invite")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// ListInvites получает приглашения, новые первыми
+func (h *DriverInviteHandler) ListInvites(c *gin.Context) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одно приглашение больше, чтобы определить наличие следующей страницы
+	invites, err := h.inviteService.ListInvites(c.Request.Context(), page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleInviteServiceError(c, err, "Failed to list driver invites")
+		return
+	}
+
+	hasMore := len(invites) > page.Limit
+	if hasMore {
+		invites = invites[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &DriverInvitesResponse{
+		Invites: invites,
+		Page:    pagination.Paginate(c, page, len(invites), nil, hasMore),
+	})
+}
+
+// RevokeInvite отзывает неиспользованное приглашение
+func (h *DriverInviteHandler) RevokeInvite(c *gin.Context) {
+	inviteID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid invite ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	invite, err := h.inviteService.RevokeInvite(c.Request.Context(), inviteID, c.GetString("user_id"))
+	if err != nil {
+		h.handleInviteServiceError(c, err, "Failed to revoke driver invite")
+		return
+	}
+
+	c.JSON(http.StatusOK, invite)
+}
+
+// Register регистрирует водителя по ссылке приглашения. Водитель указывает только имя и
+// почту; телефон и автопарк берутся из приглашения
+func (h *DriverInviteHandler) Register(c *gin.Context) {
+	var req entities.DriverRegistrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	driver, err := h.inviteService.Register(c.Request.Context(), c.Param("token"), &req)
+	if err != nil {
+		h.handleInviteServiceError(c, err, "Failed to register driver by invite")
+		return
+	}
+
+	setStatusVersion(c, driver)
+	c.JSON(http.StatusCreated, toDriverResponse(c, driver))
+}
+
+// handleInviteServiceError обрабатывает ошибки из DriverInviteService
+func (h *DriverInviteHandler) handleInviteServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverInviteNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver invite not found",
+			Code:  "DRIVER_INVITE_NOT_FOUND",
+		})
+	case entities.ErrInvalidInviteToken:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Invite link is invalid",
+			Code:  "INVALID_INVITE_TOKEN",
+		})
+	case entities.ErrDriverInviteExpired:
+		c.JSON(http.StatusGone, ErrorResponse{
+			Error: "Invite link has expired",
+			Code:  "DRIVER_INVITE_EXPIRED",
+		})
+	case entities.ErrDriverInviteUsed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Invite has already been used",
+			Code:  "DRIVER_INVITE_USED",
+		})
+	case entities.ErrDriverInviteRevoked:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Invite has been revoked",
+			Code:  "DRIVER_INVITE_REVOKED",
+		})
+	case entities.ErrInvalidDriverInvite:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invite requires a phone, a fleet, a creator and an expiry within the allowed period",
+			Code:  "INVALID_DRIVER_INVITE",
+		})
+	case entities.ErrFleetNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Fleet not found",
+			Code:  "FLEET_NOT_FOUND",
+		})
+	case entities.ErrDriverExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver with this phone already exists",
+			Code:  "DRIVER_EXISTS",
+		})
+	case entities.ErrDriverInvitesDisabled:
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Driver invites are disabled",
+			Code:  "DRIVER_INVITES_DISABLED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
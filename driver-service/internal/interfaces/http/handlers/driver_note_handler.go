@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverNoteHandler обработчик HTTP запросов внутренних заметок о водителях
+type DriverNoteHandler struct {
+	noteService services.DriverNoteService
+	logger      *zap.Logger
+}
+
+// NewDriverNoteHandler создает новый DriverNoteHandler
+func NewDriverNoteHandler(noteService services.DriverNoteService, logger *zap.Logger) *DriverNoteHandler {
+	return &DriverNoteHandler{
+		noteService: noteService,
+		logger:      logger,
+	}
+}
+
+// DriverNotesResponse ответ со страницей заметок о водителе
+type DriverNotesResponse struct {
+	Notes []*entities.DriverNote `json:"notes"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты заметок о водителях
+func (h *DriverNoteHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/notes", h.AddNote)
+	api.GET("/drivers/:id/notes", h.ListNotes)
+}
+
+// AddNote добавляет заметку о водителе; автор определяется по токену
+func (h *DriverNoteHandler) AddNote(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.DriverNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	note, err := h.noteService.AddNote(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleNoteServiceError(c, err, "Failed to add driver note")
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+// ListNotes получает заметки о водителе, новые первыми
+func (h *DriverNoteHandler) ListNotes(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одну заметку больше, чтобы определить наличие следующей страницы
+	notes, err := h.noteService.ListNotes(c.Request.Context(), driverID, page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleNoteServiceError(c, err, "Failed to list driver notes")
+		return
+	}
+
+	hasMore := len(notes) > page.Limit
+	if hasMore {
+		notes = notes[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &DriverNotesResponse{
+		Notes: notes,
+		Page:  pagination.Paginate(c, page, len(notes), nil, hasMore),
+	})
+}
+
+// parseDriverID разбирает ID водителя из пути, отвечая 400 на неверный формат
+func (h *DriverNoteHandler) parseDriverID(c *gin.Context) (uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, false
+	}
+	return driverID, true
+}
+
+// handleNoteServiceError обрабатывает ошибки из DriverNoteService
+func (h *DriverNoteHandler) handleNoteServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidDriverNote:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Note must be non-empty and at most 4000 characters",
+			Code:  "INVALID_DRIVER_NOTE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
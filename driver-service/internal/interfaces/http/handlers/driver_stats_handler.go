@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverStatsHandler обработчик HTTP запросов сводных показателей водителя
+type DriverStatsHandler struct {
+	statsService services.DriverStatsService
+	logger       *zap.Logger
+}
+
+// NewDriverStatsHandler создает новый DriverStatsHandler
+func NewDriverStatsHandler(statsService services.DriverStatsService, logger *zap.Logger) *DriverStatsHandler {
+	return &DriverStatsHandler{
+		statsService: statsService,
+		logger:       logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты сводных показателей
+func (h *DriverStatsHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/statistics", h.GetStatistics)
+	api.GET("/drivers/:id/statistics/daily", h.GetDailyStatistics)
+}
+
+// GetStatistics возвращает сводные показатели водителя: поездки, пробег, оценки, часы смен
+// и на связи, состояние документов
+func (h *DriverStatsHandler) GetStatistics(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	stats, err := h.statsService.GetStatistics(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleStatsServiceError(c, err, "Failed to get driver statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetDailyStatistics возвращает суточные показатели водителя за период [from, to);
+// по умолчанию — за последние 30 суток
+func (h *DriverStatsHandler) GetDailyStatistics(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	to := time.Now()
+	if !parseTimeParam(c, "to", &to) {
+		return
+	}
+	from := to.AddDate(0, 0, -30)
+	if !parseTimeParam(c, "from", &from) {
+		return
+	}
+
+	report, err := h.statsService.GetDailyStatistics(c.Request.Context(), driverID, from, to)
+	if err != nil {
+		h.handleStatsServiceError(c, err, "Failed to get daily driver statistics")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleStatsServiceError обрабатывает ошибки из DriverStatsService
+func (h *DriverStatsHandler) handleStatsServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidStatsRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid statistics range",
+			Code:    "INVALID_STATS_RANGE",
+			Details: "to must be after from and the period must not exceed 366 days",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverTagHandler обработчик HTTP запросов для меток водителей
+type DriverTagHandler struct {
+	tagService services.DriverTagService
+	logger     *zap.Logger
+}
+
+// NewDriverTagHandler создает новый DriverTagHandler
+func NewDriverTagHandler(tagService services.DriverTagService, logger *zap.Logger) *DriverTagHandler {
+	return &DriverTagHandler{
+		tagService: tagService,
+		logger:     logger,
+	}
+}
+
+// AddDriverTagsRequest запрос добавления меток водителю
+type AddDriverTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1"`
+}
+
+// DriverTagsResponse метки водителя после изменения
+type DriverTagsResponse struct {
+	DriverID uuid.UUID              `json:"driver_id"`
+	Tags     entities.DriverTagList `json:"tags"`
+}
+
+// RegisterRoutes регистрирует маршруты меток водителей
+func (h *DriverTagHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/driver-tags", h.GetCatalog)
+
+	tags := api.Group("/drivers/:id/tags")
+	{
+		tags.POST("", h.AddTags)
+		tags.DELETE("/:tag", h.RemoveTag)
+	}
+}
+
+// GetCatalog возвращает справочник меток
+func (h *DriverTagHandler) GetCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, h.tagService.Catalog())
+}
+
+// AddTags добавляет метки водителю
+func (h *DriverTagHandler) AddTags(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req AddDriverTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	tags, err := h.tagService.AddTags(c.Request.Context(), driverID, req.Tags)
+	if err != nil {
+		h.handleTagServiceError(c, err, "Failed to add driver tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, &DriverTagsResponse{DriverID: driverID, Tags: tags})
+}
+
+// RemoveTag снимает метку с водителя
+func (h *DriverTagHandler) RemoveTag(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	tags, err := h.tagService.RemoveTag(c.Request.Context(), driverID, c.Param("tag"))
+	if err != nil {
+		h.handleTagServiceError(c, err, "Failed to remove driver tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, &DriverTagsResponse{DriverID: driverID, Tags: tags})
+}
+
+// handleTagServiceError обрабатывает ошибки из DriverTagService
+func (h *DriverTagHandler) handleTagServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidDriverTag:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver tag",
+			Code:    "INVALID_DRIVER_TAG",
+			Details: "tags may contain only letters, digits, dashes and spaces, up to 50 characters",
+		})
+	case entities.ErrUnknownDriverTag:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Driver tag is not in the controlled tags list",
+			Code:    "UNKNOWN_DRIVER_TAG",
+			Details: "see GET /driver-tags for allowed tags",
+		})
+	case entities.ErrTooManyDriverTags:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Driver has too many tags",
+			Code:  "TOO_MANY_DRIVER_TAGS",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
+
+// parseTagsParam разбирает фильтр по меткам: параметр tag можно передать несколько раз или
+// перечислить метки через запятую. Отвечает 400 на неверную метку
+func parseTagsParam(c *gin.Context, tags *[]string) bool {
+	var raw []string
+	for _, value := range c.QueryArray("tag") {
+		raw = append(raw, strings.Split(value, ",")...)
+	}
+	if len(raw) == 0 {
+		return true
+	}
+
+	normalized, err := entities.NormalizeDriverTags(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid 'tag' filter",
+			Code:  "INVALID_DRIVER_TAG",
+		})
+		return false
+	}
+	*tags = normalized
+	return true
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverVerificationHandler обработчик HTTP запросов для допуска водителей по документам
+type DriverVerificationHandler struct {
+	verificationService services.DriverVerificationService
+	logger              *zap.Logger
+}
+
+// NewDriverVerificationHandler создает новый DriverVerificationHandler
+func NewDriverVerificationHandler(verificationService services.DriverVerificationService, logger *zap.Logger) *DriverVerificationHandler {
+	return &DriverVerificationHandler{
+		verificationService: verificationService,
+		logger:              logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты допуска водителей
+func (h *DriverVerificationHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/admin/drivers/:id/verification/evaluate", h.EvaluateDriver)
+}
+
+// EvaluateDriver пересматривает статус водителя по состоянию обязательных документов
+func (h *DriverVerificationHandler) EvaluateDriver(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	result, err := h.verificationService.Evaluate(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleDriverVerificationServiceError(c, err, "Failed to evaluate driver verification")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// handleDriverVerificationServiceError обрабатывает ошибки сервиса допуска водителей
+func (h *DriverVerificationHandler) handleDriverVerificationServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrProfileIncomplete:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Driver profile is incomplete",
+			Code:  "PROFILE_INCOMPLETE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
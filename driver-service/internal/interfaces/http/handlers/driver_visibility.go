@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// piiFields поля профиля водителя, которые видят только вызывающие с областью
+// entities.ScopePIIRead, в порядке перечисления в DriverResponse.RedactedFields
+var piiFields = []string{"birth_date", "passport_series", "passport_number", "license_number", "license_expiry"}
+
+// piiAllowed сообщает, можно ли отдать вызывающему персональные данные водителя driverID: их
+// видят сам водитель и вызывающие с областью entities.ScopePIIRead. Роль доступа не дает:
+// диспетчеру и партнеру профиль нужен без паспорта. Без аутентификации данные не скрываются
+func piiAllowed(c *gin.Context, driverID uuid.UUID) bool {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		return true
+	}
+	return claims.HasScope(entities.ScopePIIRead) || claims.IsDriver(driverID.String())
+}
+
+// isPIIField проверяет, относится ли поле профиля к персональным данным (piiFields)
+func isPIIField(name string) bool {
+	for _, field := range piiFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// redactDriverResponse убирает из ответа персональные данные водителя
+func redactDriverResponse(response *DriverResponse) {
+	response.BirthDate = nil
+	response.PassportSeries = ""
+	response.PassportNumber = ""
+	response.LicenseNumber = ""
+	response.LicenseExpiry = nil
+	response.RedactedFields = piiFields
+}
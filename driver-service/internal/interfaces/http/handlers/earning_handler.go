@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EarningHandler обработчик HTTP запросов начислений водителям
+type EarningHandler struct {
+	earningService services.EarningService
+	logger         *zap.Logger
+}
+
+// NewEarningHandler создает новый EarningHandler
+func NewEarningHandler(earningService services.EarningService, logger *zap.Logger) *EarningHandler {
+	return &EarningHandler{
+		earningService: earningService,
+		logger:         logger,
+	}
+}
+
+// ListEarningsResponse страница начислений водителя
+type ListEarningsResponse struct {
+	Earnings []*entities.DriverEarning `json:"earnings"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты начислений
+func (h *EarningHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.POST("/:id/earnings/entries", h.RecordEarning)
+		drivers.GET("/:id/earnings/entries", h.ListEarnings)
+		drivers.GET("/:id/earnings/summary", h.GetStatement)
+	}
+}
+
+// RecordEarning начисляет водителю оплату поездки или бонус
+func (h *EarningHandler) RecordEarning(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.EarningRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid record earning request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	earning, err := h.earningService.RecordEarning(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleEarningServiceError(c, err, "Failed to record earning")
+		return
+	}
+
+	c.JSON(http.StatusCreated, earning)
+}
+
+// ListEarnings возвращает начисления водителя, новые первыми; фильтры from, to, type и shift_id
+func (h *EarningHandler) ListEarnings(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 500})
+	if !ok {
+		return
+	}
+	filters := &entities.EarningFilters{
+		DriverID: &driverID,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+
+	var from, to time.Time
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+	if !from.IsZero() {
+		filters.From = &from
+	}
+	if !to.IsZero() {
+		filters.To = &to
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		earningType := entities.EarningType(typeStr)
+		if !earningType.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid type",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected trip or bonus",
+			})
+			return
+		}
+		filters.Type = &earningType
+	}
+
+	if shiftIDStr := c.Query("shift_id"); shiftIDStr != "" {
+		shiftID, err := uuid.Parse(shiftIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid shift_id format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		filters.ShiftID = &shiftID
+	}
+
+	earnings, err := h.earningService.ListEarnings(c.Request.Context(), filters)
+	if err != nil {
+		h.handleEarningServiceError(c, err, "Failed to list earnings")
+		return
+	}
+
+	total, err := h.earningService.CountEarnings(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count earnings",
+			zap.Error(err),
+		)
+		total = len(earnings)
+	}
+
+	c.JSON(http.StatusOK, &ListEarningsResponse{
+		Earnings: earnings,
+		Page:     pagination.Paginate(c, page, len(earnings), pagination.Total(total), false),
+	})
+}
+
+// GetStatement возвращает выплатную ведомость водителя: начисления по дням, неделям или
+// месяцам (group_by, по умолчанию day) за период (по умолчанию последние 30 дней).
+// timezone задает часовой пояс IANA границ периодов
+func (h *EarningHandler) GetStatement(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	groupBy := entities.EarningsGroupingDay
+	if groupByStr := c.Query("group_by"); groupByStr != "" {
+		groupBy = entities.EarningsGrouping(groupByStr)
+		if !groupBy.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid group_by",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected day, week or month",
+			})
+			return
+		}
+	}
+
+	var loc *time.Location
+	if timezone := c.Query("timezone"); timezone != "" {
+		parsed, err := time.LoadLocation(timezone)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid timezone",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected IANA time zone, e.g. Europe/Moscow",
+			})
+			return
+		}
+		loc = parsed
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+
+	statement, err := h.earningService.GetStatement(c.Request.Context(), driverID, from, to, groupBy, loc)
+	if err != nil {
+		h.handleEarningServiceError(c, err, "Failed to get earnings statement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// parseDriverID разбирает ID водителя из пути
+func (h *EarningHandler) parseDriverID(c *gin.Context) (uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, false
+	}
+	return driverID, true
+}
+
+// handleEarningServiceError обрабатывает ошибки из EarningService
+func (h *EarningHandler) handleEarningServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrShiftNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Shift not found",
+			Code:  "SHIFT_NOT_FOUND",
+		})
+	case entities.ErrEarningExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Trip earning for this order already recorded",
+			Code:  "EARNING_EXISTS",
+		})
+	case entities.ErrInvalidEarning, entities.ErrInvalidCurrency:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid earning data",
+			Code:  "INVALID_EARNING",
+			Details: "trip needs order_id, fare > 0 and 0 <= commission <= fare; bonus needs bonus > 0 " +
+				"without fare or commission; currency must be an allowed ISO 4217 code",
+		})
+	case entities.ErrInvalidTimestamp:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid time range",
+			Code:  "INVALID_TIME_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
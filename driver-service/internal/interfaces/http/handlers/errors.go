@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// retryAfterSeconds через сколько клиенту повторить запрос после временной ошибки базы данных
+const retryAfterSeconds = "1"
+
+// kindStatuses HTTP-статусы категорий доменных ошибок
+var kindStatuses = map[entities.ErrorKind]int{
+	entities.ErrorKindValidation:        http.StatusBadRequest,
+	entities.ErrorKindNotFound:          http.StatusNotFound,
+	entities.ErrorKindConflict:          http.StatusConflict,
+	entities.ErrorKindInvalidTransition: http.StatusConflict,
+	entities.ErrorKindPrecondition:      http.StatusUnprocessableEntity,
+	entities.ErrorKindUnauthorized:      http.StatusUnauthorized,
+	entities.ErrorKindForbidden:         http.StatusForbidden,
+	entities.ErrorKindRateLimited:       http.StatusTooManyRequests,
+	entities.ErrorKindUnavailable:       http.StatusServiceUnavailable,
+}
+
+// respondDomainError отвечает на доменную ошибку статусом ее категории и ее кодом;
+// возвращает false, если в цепочке err нет доменной ошибки
+func respondDomainError(c *gin.Context, err error) bool {
+	domainErr, ok := entities.AsDomainError(err)
+	if !ok {
+		return false
+	}
+
+	status, ok := kindStatuses[domainErr.Kind]
+	if !ok {
+		return false
+	}
+	if status == http.StatusServiceUnavailable {
+		c.Header("Retry-After", retryAfterSeconds)
+	}
+
+	response := ErrorResponse{
+		Error: domainErr.Message,
+		Code:  domainErr.Code,
+	}
+	// Обернутая ошибка уточняет сообщение, например переход статуса "from registered to available"
+	if message := err.Error(); message != domainErr.Message {
+		response.Details = message
+	}
+	c.JSON(status, response)
+	return true
+}
+
+// respondInternalError отвечает на ошибку, не разобранную обработчиком. Доменные ошибки
+// возвращаются со статусом своей категории и своим кодом (respondDomainError). Временные
+// ошибки базы данных, не устраненные повторами, возвращаются как 503, чтобы клиент повторил
+// запрос; истекший срок обработки запроса (server.timeout) — как 504
+func respondInternalError(c *gin.Context, err error) {
+	if respondDomainError(c, err) {
+		return
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		c.JSON(http.StatusGatewayTimeout, ErrorResponse{
+			Error: "Request timed out",
+			Code:  "REQUEST_TIMEOUT",
+		})
+		return
+	}
+
+	if database.IsRetryable(err) {
+		c.Header("Retry-After", retryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Service temporarily unavailable",
+			Code:  "SERVICE_UNAVAILABLE",
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error: "Internal server error",
+		Code:  "INTERNAL_ERROR",
+	})
+}
@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventCatalogHandler обработчик HTTP запросов каталога событий
+type EventCatalogHandler struct{}
+
+// NewEventCatalogHandler создает новый EventCatalogHandler
+func NewEventCatalogHandler() *EventCatalogHandler {
+	return &EventCatalogHandler{}
+}
+
+// RegisterRoutes регистрирует маршрут каталога событий
+func (h *EventCatalogHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/events/catalog", h.GetCatalog)
+}
+
+// GetCatalog возвращает публикуемые сервисом события: имя, версию схемы, JSON Schema данных и пример
+func (h *EventCatalogHandler) GetCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, services.EventCatalog())
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ExpenseHandler обработчик HTTP запросов для расходов водителей
+type ExpenseHandler struct {
+	expenseService services.ExpenseService
+	logger         *zap.Logger
+}
+
+// NewExpenseHandler создает новый ExpenseHandler
+func NewExpenseHandler(expenseService services.ExpenseService, logger *zap.Logger) *ExpenseHandler {
+	return &ExpenseHandler{
+		expenseService: expenseService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты расходов
+func (h *ExpenseHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.POST("/:id/shifts/:shift_id/expenses", h.RecordExpense)
+		drivers.GET("/:id/shifts/:shift_id/expenses", h.GetShiftReport)
+		drivers.POST("/:id/expenses/:expense_id/receipt", h.UploadReceipt)
+		drivers.GET("/:id/earnings", h.GetEarningsSummary)
+	}
+
+	api.GET("/admin/expenses/export", h.ExportExpenses)
+}
+
+// RecordExpense добавляет расход в смену
+func (h *ExpenseHandler) RecordExpense(c *gin.Context) {
+	driverID, shiftID, ok := h.parseDriverShift(c)
+	if !ok {
+		return
+	}
+
+	var req entities.ExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid record expense request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	expense, err := h.expenseService.RecordExpense(c.Request.Context(), driverID, shiftID, &req)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to record expense")
+		return
+	}
+
+	c.JSON(http.StatusCreated, expense)
+}
+
+// GetShiftReport возвращает расходы смены и заработок за вычетом расходов
+func (h *ExpenseHandler) GetShiftReport(c *gin.Context) {
+	driverID, shiftID, ok := h.parseDriverShift(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.expenseService.GetShiftReport(c.Request.Context(), driverID, shiftID)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to get shift expenses")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// UploadReceipt загружает фотографию чека (multipart/form-data, поле receipt)
+func (h *ExpenseHandler) UploadReceipt(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expense_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid expense ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	header, err := c.FormFile("receipt")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Receipt file is required",
+			Code:    "FILE_REQUIRED",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	upload, file, err := openUpload(header)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to read receipt file")
+		return
+	}
+	defer file.Close()
+
+	expense, err := h.expenseService.AttachReceipt(c.Request.Context(), driverID, expenseID, upload)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to attach receipt")
+		return
+	}
+
+	c.JSON(http.StatusOK, expense)
+}
+
+// GetEarningsSummary возвращает заработок и расходы водителя за период (по умолчанию последние 30 дней)
+func (h *ExpenseHandler) GetEarningsSummary(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	if !h.parsePeriod(c, &from, &to) {
+		return
+	}
+
+	summary, err := h.expenseService.GetEarningsSummary(c.Request.Context(), driverID, from, to)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to get earnings summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ExportExpenses выгружает расходы в CSV для учетной системы.
+// mark_exported=true отмечает выгруженные расходы, exported=false отбирает только новые
+func (h *ExpenseHandler) ExportExpenses(c *gin.Context) {
+	filters := &entities.ExpenseFilters{}
+
+	if driverIDStr := c.Query("driver_id"); driverIDStr != "" {
+		driverID, err := uuid.Parse(driverIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid driver_id format",
+				Code:  "INVALID_DRIVER_ID",
+			})
+			return
+		}
+		filters.DriverID = &driverID
+	}
+
+	if categoriesStr := c.Query("category"); categoriesStr != "" {
+		for _, category := range strings.Split(categoriesStr, ",") {
+			filters.Category = append(filters.Category, entities.ExpenseCategory(strings.TrimSpace(category)))
+		}
+	}
+
+	var from, to time.Time
+	if !h.parsePeriod(c, &from, &to) {
+		return
+	}
+	if !from.IsZero() {
+		filters.From = &from
+	}
+	if !to.IsZero() {
+		filters.To = &to
+	}
+
+	if exportedStr := c.Query("exported"); exportedStr != "" {
+		exported, err := strconv.ParseBool(exportedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid exported format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		filters.Exported = &exported
+	}
+
+	markExported := c.Query("mark_exported") == "true"
+
+	// CSV формируется в буфере, чтобы ошибка не оборвала ответ на середине
+	var buf bytes.Buffer
+	count, err := h.expenseService.ExportCSV(c.Request.Context(), &buf, filters, markExported)
+	if err != nil {
+		h.handleExpenseServiceError(c, err, "Failed to export expenses")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="expenses-%s.csv"`, time.Now().Format("20060102-150405")))
+	c.Header("X-Total-Count", strconv.Itoa(count))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// parseDriverShift разбирает ID водителя и смены из пути
+func (h *ExpenseHandler) parseDriverShift(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	shiftID, err := uuid.Parse(c.Param("shift_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid shift ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return driverID, shiftID, true
+}
+
+// parsePeriod разбирает параметры from и to в формате RFC3339
+func (h *ExpenseHandler) parsePeriod(c *gin.Context, from, to *time.Time) bool {
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{"from", from},
+		{"to", to},
+	} {
+		if str := c.Query(param.name); str != "" {
+			parsed, err := time.Parse(time.RFC3339, str)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid " + param.name + " format",
+					Code:    "INVALID_PARAMETER",
+					Details: "expected RFC3339",
+				})
+				return false
+			}
+			*param.value = parsed
+		}
+	}
+	return true
+}
+
+// handleExpenseServiceError обрабатывает ошибки из ExpenseService
+func (h *ExpenseHandler) handleExpenseServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrShiftNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Shift not found",
+			Code:  "SHIFT_NOT_FOUND",
+		})
+	case entities.ErrExpenseNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Expense not found",
+			Code:  "EXPENSE_NOT_FOUND",
+		})
+	case entities.ErrExpenseWindowClosed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift is closed for expenses",
+			Code:  "EXPENSE_WINDOW_CLOSED",
+		})
+	case entities.ErrExpenseLimitExceeded:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Expense limit exceeded",
+			Code:  "EXPENSE_LIMIT_EXCEEDED",
+		})
+	case entities.ErrInvalidExpenseCategory, entities.ErrInvalidExpenseAmount, entities.ErrInvalidCurrency:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid expense data",
+			Code:    "INVALID_EXPENSE",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidReceipt:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Receipt must be a JPEG, PNG, HEIC or PDF file within the size limit",
+			Code:    "INVALID_RECEIPT",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidTimestamp:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid time range",
+			Code:  "INVALID_TIME_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
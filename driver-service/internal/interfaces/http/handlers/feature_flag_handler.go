@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FeatureFlagHandler обработчик HTTP запросов флагов функций и конфигурации приложения водителя
+type FeatureFlagHandler struct {
+	flagService services.FeatureFlagService
+	logger      *zap.Logger
+}
+
+// NewFeatureFlagHandler создает новый FeatureFlagHandler
+func NewFeatureFlagHandler(flagService services.FeatureFlagService, logger *zap.Logger) *FeatureFlagHandler {
+	return &FeatureFlagHandler{
+		flagService: flagService,
+		logger:      logger,
+	}
+}
+
+// ListFeatureFlagsResponse все флаги функций
+type ListFeatureFlagsResponse struct {
+	Flags []*entities.FeatureFlag `json:"flags"`
+}
+
+// RegisterRoutes регистрирует маршруты флагов функций
+func (h *FeatureFlagHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/app-config", h.GetAppConfig)
+
+	api.POST("/admin/feature-flags", h.CreateFlag)
+	api.GET("/admin/feature-flags", h.ListFlags)
+	api.GET("/admin/feature-flags/:id", h.GetFlag)
+	api.PUT("/admin/feature-flags/:id", h.UpdateFlag)
+	api.DELETE("/admin/feature-flags/:id", h.DeleteFlag)
+}
+
+// GetAppConfig возвращает флаги функций и рабочие параметры приложения водителя;
+// app_version задает версию приложения вместо версии зарегистрированного устройства
+func (h *FeatureFlagHandler) GetAppConfig(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	config, err := h.flagService.GetAppConfig(c.Request.Context(), driverID, c.Query("app_version"))
+	if err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to get app config")
+		return
+	}
+
+	c.JSON(http.StatusOK, config)
+}
+
+// CreateFlag создает флаг
+func (h *FeatureFlagHandler) CreateFlag(c *gin.Context) {
+	var req entities.FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid create feature flag request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	flag, err := h.flagService.CreateFlag(c.Request.Context(), &req)
+	if err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to create feature flag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, flag)
+}
+
+// ListFlags возвращает все флаги по ключу
+func (h *FeatureFlagHandler) ListFlags(c *gin.Context) {
+	flags, err := h.flagService.ListFlags(c.Request.Context())
+	if err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to list feature flags")
+		return
+	}
+
+	c.JSON(http.StatusOK, &ListFeatureFlagsResponse{Flags: flags})
+}
+
+// GetFlag возвращает флаг
+func (h *FeatureFlagHandler) GetFlag(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	flag, err := h.flagService.GetFlag(c.Request.Context(), id)
+	if err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to get feature flag")
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// UpdateFlag заменяет флаг; ключ флага не меняется
+func (h *FeatureFlagHandler) UpdateFlag(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.FeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid update feature flag request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	flag, err := h.flagService.UpdateFlag(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to update feature flag")
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// DeleteFlag удаляет флаг
+func (h *FeatureFlagHandler) DeleteFlag(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.flagService.DeleteFlag(c.Request.Context(), id); err != nil {
+		h.handleFeatureFlagServiceError(c, err, "Failed to delete feature flag")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// parseID разбирает ID флага из пути
+func (h *FeatureFlagHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid feature flag ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// handleFeatureFlagServiceError обрабатывает ошибки из FeatureFlagService
+func (h *FeatureFlagHandler) handleFeatureFlagServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidAppVersion:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid app version",
+			Code:    "INVALID_APP_VERSION",
+			Details: "expected dot-separated numbers, e.g. 4.12.1",
+		})
+	case entities.ErrFeatureFlagNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Feature flag not found",
+			Code:  "FEATURE_FLAG_NOT_FOUND",
+		})
+	case entities.ErrInvalidFeatureFlag:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid feature flag",
+			Code:  "INVALID_FEATURE_FLAG",
+			Details: "key is required (up to 64 characters: a-z, 0-9, _, -, .); up to 20 rules with percentage 0-100 " +
+				"and dot-separated app versions; parameters must be positive, location_batch_size up to 1000",
+		})
+	case entities.ErrFeatureFlagExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Feature flag with this key already exists",
+			Code:  "FEATURE_FLAG_EXISTS",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FleetHandler обработчик HTTP запросов автопарков
+type FleetHandler struct {
+	fleetService services.FleetService
+	logger       *zap.Logger
+}
+
+// NewFleetHandler создает новый FleetHandler
+func NewFleetHandler(fleetService services.FleetService, logger *zap.Logger) *FleetHandler {
+	return &FleetHandler{
+		fleetService: fleetService,
+		logger:       logger,
+	}
+}
+
+// ListFleetsResponse страница автопарков
+type ListFleetsResponse struct {
+	Fleets []*entities.Fleet `json:"fleets"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты автопарков
+func (h *FleetHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/fleets", h.CreateFleet)
+	api.GET("/fleets", h.ListFleets)
+	api.GET("/fleets/:id", h.GetFleet)
+	api.PUT("/fleets/:id", h.UpdateFleet)
+}
+
+// CreateFleet создает автопарк
+func (h *FleetHandler) CreateFleet(c *gin.Context) {
+	var req entities.FleetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid create fleet request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	fleet, err := h.fleetService.CreateFleet(c.Request.Context(), &req)
+	if err != nil {
+		h.handleFleetServiceError(c, err, "Failed to create fleet")
+		return
+	}
+
+	c.JSON(http.StatusCreated, fleet)
+}
+
+// ListFleets возвращает автопарки по названию; партнер видит только свои
+func (h *FleetHandler) ListFleets(c *gin.Context) {
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 200})
+	if !ok {
+		return
+	}
+	filters := &entities.FleetFilters{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	fleets, err := h.fleetService.ListFleets(c.Request.Context(), filters)
+	if err != nil {
+		h.handleFleetServiceError(c, err, "Failed to list fleets")
+		return
+	}
+
+	total, err := h.fleetService.CountFleets(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count fleets",
+			zap.Error(err),
+		)
+		total = len(fleets)
+	}
+
+	c.JSON(http.StatusOK, &ListFleetsResponse{
+		Fleets: fleets,
+		Page:   pagination.Paginate(c, page, len(fleets), pagination.Total(total), false),
+	})
+}
+
+// GetFleet возвращает автопарк
+func (h *FleetHandler) GetFleet(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	fleet, err := h.fleetService.GetFleet(c.Request.Context(), id)
+	if err != nil {
+		h.handleFleetServiceError(c, err, "Failed to get fleet")
+		return
+	}
+
+	c.JSON(http.StatusOK, fleet)
+}
+
+// UpdateFleet заменяет название и метаданные автопарка
+func (h *FleetHandler) UpdateFleet(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.FleetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid update fleet request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	fleet, err := h.fleetService.UpdateFleet(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleFleetServiceError(c, err, "Failed to update fleet")
+		return
+	}
+
+	c.JSON(http.StatusOK, fleet)
+}
+
+// parseID разбирает ID автопарка из пути
+func (h *FleetHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid fleet ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// handleFleetServiceError обрабатывает ошибки из FleetService
+func (h *FleetHandler) handleFleetServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrFleetNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Fleet not found",
+			Code:  "FLEET_NOT_FOUND",
+		})
+	case entities.ErrInvalidFleet:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid fleet",
+			Code:    "INVALID_FLEET",
+			Details: "name is required (up to 100 characters)",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
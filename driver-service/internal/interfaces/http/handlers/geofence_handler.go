@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GeofenceHandler обработчик HTTP запросов геозон
+type GeofenceHandler struct {
+	geofenceService services.GeofenceService
+	logger          *zap.Logger
+}
+
+// NewGeofenceHandler создает новый GeofenceHandler
+func NewGeofenceHandler(geofenceService services.GeofenceService, logger *zap.Logger) *GeofenceHandler {
+	return &GeofenceHandler{
+		geofenceService: geofenceService,
+		logger:          logger,
+	}
+}
+
+// ListGeofencesResponse страница геозон
+type ListGeofencesResponse struct {
+	Geofences []*entities.Geofence `json:"geofences"`
+	pagination.Page
+}
+
+// ListGeofenceDriversResponse страница водителей внутри геозоны
+type ListGeofenceDriversResponse struct {
+	Drivers []*entities.GeofencePresence `json:"drivers"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты геозон
+func (h *GeofenceHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/geofences", h.CreateGeofence)
+	api.GET("/geofences", h.ListGeofences)
+	api.GET("/geofences/:id", h.GetGeofence)
+	api.PUT("/geofences/:id", h.UpdateGeofence)
+	api.DELETE("/geofences/:id", h.DeleteGeofence)
+	api.GET("/geofences/:id/drivers", h.ListDriversInside)
+}
+
+// CreateGeofence создает геозону
+func (h *GeofenceHandler) CreateGeofence(c *gin.Context) {
+	var req entities.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid create geofence request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	geofence, err := h.geofenceService.CreateGeofence(c.Request.Context(), &req)
+	if err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to create geofence")
+		return
+	}
+
+	c.JSON(http.StatusCreated, geofence)
+}
+
+// ListGeofences возвращает геозоны по названию; active=true|false отбирает по активности
+func (h *GeofenceHandler) ListGeofences(c *gin.Context) {
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 200})
+	if !ok {
+		return
+	}
+	filters := &entities.GeofenceFilters{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if activeStr := c.Query("active"); activeStr != "" {
+		active, err := strconv.ParseBool(activeStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid active format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		filters.Active = &active
+	}
+
+	geofences, err := h.geofenceService.ListGeofences(c.Request.Context(), filters)
+	if err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to list geofences")
+		return
+	}
+
+	total, err := h.geofenceService.CountGeofences(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count geofences",
+			zap.Error(err),
+		)
+		total = len(geofences)
+	}
+
+	c.JSON(http.StatusOK, &ListGeofencesResponse{
+		Geofences: geofences,
+		Page:      pagination.Paginate(c, page, len(geofences), pagination.Total(total), false),
+	})
+}
+
+// GetGeofence возвращает геозону
+func (h *GeofenceHandler) GetGeofence(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	geofence, err := h.geofenceService.GetGeofence(c.Request.Context(), id)
+	if err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to get geofence")
+		return
+	}
+
+	c.JSON(http.StatusOK, geofence)
+}
+
+// UpdateGeofence заменяет геозону
+func (h *GeofenceHandler) UpdateGeofence(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.GeofenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid update geofence request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	geofence, err := h.geofenceService.UpdateGeofence(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to update geofence")
+		return
+	}
+
+	c.JSON(http.StatusOK, geofence)
+}
+
+// DeleteGeofence удаляет геозону
+func (h *GeofenceHandler) DeleteGeofence(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.geofenceService.DeleteGeofence(c.Request.Context(), id); err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to delete geofence")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListDriversInside возвращает водителей внутри геозоны, вошедших раньше — первыми,
+// например очередь в аэропорту
+func (h *GeofenceHandler) ListDriversInside(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 500})
+	if !ok {
+		return
+	}
+
+	drivers, total, err := h.geofenceService.ListDriversInside(c.Request.Context(), id, page.Limit, page.Offset)
+	if err != nil {
+		h.handleGeofenceServiceError(c, err, "Failed to list drivers inside geofence")
+		return
+	}
+
+	c.JSON(http.StatusOK, &ListGeofenceDriversResponse{
+		Drivers: drivers,
+		Page:    pagination.Paginate(c, page, len(drivers), pagination.Total(total), false),
+	})
+}
+
+// parseID разбирает ID геозоны из пути
+func (h *GeofenceHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid geofence ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// handleGeofenceServiceError обрабатывает ошибки из GeofenceService
+func (h *GeofenceHandler) handleGeofenceServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrGeofenceNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Geofence not found",
+			Code:  "GEOFENCE_NOT_FOUND",
+		})
+	case entities.ErrInvalidGeofence:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid geofence",
+			Code:  "INVALID_GEOFENCE",
+			Details: "name is required (up to 100 characters); circle needs center_latitude, center_longitude and " +
+				"radius_meters (up to 50000) without polygon; polygon needs 3-500 non-collinear vertices without center or radius",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// HeartbeatHandler обработчик HTTP запросов сигналов присутствия водителей
+type HeartbeatHandler struct {
+	heartbeatService services.HeartbeatService
+	logger           *zap.Logger
+}
+
+// NewHeartbeatHandler создает новый HeartbeatHandler
+func NewHeartbeatHandler(heartbeatService services.HeartbeatService, logger *zap.Logger) *HeartbeatHandler {
+	return &HeartbeatHandler{
+		heartbeatService: heartbeatService,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты сигналов присутствия
+func (h *HeartbeatHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/heartbeat", h.RecordHeartbeat)
+}
+
+// RecordHeartbeat принимает сигнал приложения водителя. Приложение присылает его, пока
+// водитель на линии, но точки местоположения не отправляются, например на стоянке
+func (h *HeartbeatHandler) RecordHeartbeat(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	heartbeat, err := h.heartbeatService.RecordHeartbeat(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleHeartbeatServiceError(c, err, "Failed to record driver heartbeat")
+		return
+	}
+
+	c.JSON(http.StatusOK, heartbeat)
+}
+
+// handleHeartbeatServiceError обрабатывает ошибки из HeartbeatService
+func (h *HeartbeatHandler) handleHeartbeatServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,469 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InspectionHandler обработчик HTTP запросов для техосмотров автомобилей
+type InspectionHandler struct {
+	inspectionService services.InspectionService
+	logger            *zap.Logger
+}
+
+// NewInspectionHandler создает новый InspectionHandler
+func NewInspectionHandler(inspectionService services.InspectionService, logger *zap.Logger) *InspectionHandler {
+	return &InspectionHandler{
+		inspectionService: inspectionService,
+		logger:            logger,
+	}
+}
+
+// ListInspectionsResponse ответ со списком техосмотров
+type ListInspectionsResponse struct {
+	Inspections []*entities.VehicleInspection `json:"inspections"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты техосмотров
+func (h *InspectionHandler) RegisterRoutes(api *gin.RouterGroup) {
+	inspections := api.Group("/inspections")
+	{
+		inspections.POST("", h.ScheduleInspection)
+		inspections.GET("", h.ListInspections)
+		inspections.GET("/compliance", h.GetComplianceReport)
+		inspections.GET("/:id", h.GetInspection)
+		inspections.POST("/:id/complete", h.CompleteInspection)
+		inspections.POST("/:id/review", h.ReviewPhotos)
+	}
+
+	api.GET("/vehicles/:vehicle_id/inspections", h.ListVehicleInspections)
+	api.GET("/vehicles/:vehicle_id/inspections/summary", h.GetVehicleSummary)
+
+	drivers := api.Group("/drivers")
+	{
+		drivers.GET("/:id/inspections/summary", h.GetDriverSummary)
+		drivers.POST("/:id/inspections/:inspection_id/photos", h.UploadPhoto)
+		drivers.POST("/:id/inspections/:inspection_id/submit", h.SubmitPhotos)
+	}
+}
+
+// ScheduleInspection планирует техосмотр автомобиля
+func (h *InspectionHandler) ScheduleInspection(c *gin.Context) {
+	var req entities.ScheduleInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid schedule inspection request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	inspection, err := h.inspectionService.ScheduleInspection(c.Request.Context(), &req)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to schedule inspection")
+		return
+	}
+
+	c.JSON(http.StatusCreated, inspection)
+}
+
+// GetInspection получает техосмотр по ID
+func (h *InspectionHandler) GetInspection(c *gin.Context) {
+	inspectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid inspection ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	inspection, err := h.inspectionService.GetInspection(c.Request.Context(), inspectionID)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to get inspection")
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+// CompleteInspection фиксирует результат техосмотра
+func (h *InspectionHandler) CompleteInspection(c *gin.Context) {
+	inspectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid inspection ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req entities.CompleteInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid complete inspection request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	inspection, err := h.inspectionService.CompleteInspection(c.Request.Context(), inspectionID, &req)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to complete inspection")
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+// ReviewPhotos фиксирует решения по фотографиям фотоосмотра
+func (h *InspectionHandler) ReviewPhotos(c *gin.Context) {
+	inspectionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid inspection ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req entities.ReviewInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid review inspection request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	inspection, err := h.inspectionService.ReviewPhotos(c.Request.Context(), inspectionID, &req)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to review inspection photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+// UploadPhoto загружает фотографию автомобиля (multipart/form-data, поля angle и photo)
+func (h *InspectionHandler) UploadPhoto(c *gin.Context) {
+	driverID, inspectionID, ok := h.parseDriverInspection(c)
+	if !ok {
+		return
+	}
+
+	header, err := c.FormFile("photo")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Photo file is required",
+			Code:    "FILE_REQUIRED",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	upload, file, err := openUpload(header)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to read inspection photo")
+		return
+	}
+	defer file.Close()
+
+	angle := entities.PhotoAngle(c.PostForm("angle"))
+	photo, err := h.inspectionService.UploadPhoto(c.Request.Context(), driverID, inspectionID, angle, upload)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to upload inspection photo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, photo)
+}
+
+// SubmitPhotos отправляет фотографии фотоосмотра на проверку
+func (h *InspectionHandler) SubmitPhotos(c *gin.Context) {
+	driverID, inspectionID, ok := h.parseDriverInspection(c)
+	if !ok {
+		return
+	}
+
+	inspection, err := h.inspectionService.SubmitPhotos(c.Request.Context(), driverID, inspectionID)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to submit inspection photos")
+		return
+	}
+
+	c.JSON(http.StatusOK, inspection)
+}
+
+// GetVehicleSummary возвращает состояние техосмотров автомобиля
+func (h *InspectionHandler) GetVehicleSummary(c *gin.Context) {
+	vehicleID, err := uuid.Parse(c.Param("vehicle_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid vehicle ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	summary, err := h.inspectionService.GetVehicleSummary(c.Request.Context(), vehicleID)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to get vehicle inspection summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetDriverSummary возвращает состояние техосмотров автомобилей водителя
+func (h *InspectionHandler) GetDriverSummary(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	summary, err := h.inspectionService.GetDriverSummary(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to get driver inspection summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// ListInspections получает список техосмотров с фильтрами
+func (h *InspectionHandler) ListInspections(c *gin.Context) {
+	page, filters, ok := h.parseFilters(c)
+	if !ok {
+		return
+	}
+
+	h.listInspections(c, page, filters)
+}
+
+// ListVehicleInspections получает историю техосмотров автомобиля
+func (h *InspectionHandler) ListVehicleInspections(c *gin.Context) {
+	vehicleID, err := uuid.Parse(c.Param("vehicle_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid vehicle ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	page, filters, ok := h.parseFilters(c)
+	if !ok {
+		return
+	}
+	filters.VehicleID = &vehicleID
+
+	h.listInspections(c, page, filters)
+}
+
+// GetComplianceReport возвращает отчет о соответствии автопарка требованиям техосмотра
+func (h *InspectionHandler) GetComplianceReport(c *gin.Context) {
+	var fleetID *uuid.UUID
+	if fleetIDStr := c.Query("fleet_id"); fleetIDStr != "" {
+		id, err := uuid.Parse(fleetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid fleet ID format",
+				Code:  "INVALID_ID",
+			})
+			return
+		}
+		fleetID = &id
+	}
+
+	report, err := h.inspectionService.GetComplianceReport(c.Request.Context(), fleetID)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to build compliance report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// listInspections возвращает страницу техосмотров по фильтрам
+func (h *InspectionHandler) listInspections(c *gin.Context, page pagination.Request, filters *entities.InspectionFilters) {
+	inspections, err := h.inspectionService.ListInspections(c.Request.Context(), filters)
+	if err != nil {
+		h.handleInspectionServiceError(c, err, "Failed to list inspections")
+		return
+	}
+
+	total, err := h.inspectionService.CountInspections(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count inspections",
+			zap.Error(err),
+		)
+		total = len(inspections)
+	}
+
+	c.JSON(http.StatusOK, &ListInspectionsResponse{
+		Inspections: inspections,
+		Page:        pagination.Paginate(c, page, len(inspections), pagination.Total(total), false),
+	})
+}
+
+// parseDriverInspection разбирает ID водителя и техосмотра из пути
+func (h *InspectionHandler) parseDriverInspection(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	inspectionID, err := uuid.Parse(c.Param("inspection_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid inspection ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return driverID, inspectionID, true
+}
+
+// parseFilters разбирает параметры запроса в страницу и фильтры техосмотров
+func (h *InspectionHandler) parseFilters(c *gin.Context) (pagination.Request, *entities.InspectionFilters, bool) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return page, nil, false
+	}
+	filters := &entities.InspectionFilters{
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **uuid.UUID
+	}{
+		{"vehicle_id", &filters.VehicleID},
+		{"fleet_id", &filters.FleetID},
+		{"driver_id", &filters.DriverID},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid " + param.name + " format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return page, nil, false
+		}
+		*param.target = &id
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		filters.Status = []entities.InspectionStatus{entities.InspectionStatus(statusStr)}
+	}
+
+	if dueBeforeStr := c.Query("due_before"); dueBeforeStr != "" {
+		if dueBefore, err := time.Parse(time.RFC3339, dueBeforeStr); err == nil {
+			filters.DueBefore = &dueBefore
+		}
+	}
+
+	if dueAfterStr := c.Query("due_after"); dueAfterStr != "" {
+		if dueAfter, err := time.Parse(time.RFC3339, dueAfterStr); err == nil {
+			filters.DueAfter = &dueAfter
+		}
+	}
+
+	return page, filters, true
+}
+
+// handleInspectionServiceError обрабатывает ошибки из InspectionService
+func (h *InspectionHandler) handleInspectionServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrInspectionNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Inspection not found",
+			Code:  "INSPECTION_NOT_FOUND",
+		})
+	case entities.ErrInspectionAlreadyCompleted:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Inspection already completed",
+			Code:  "INSPECTION_COMPLETED",
+		})
+	case entities.ErrInspectionNotEditable, entities.ErrInspectionNotSubmitted:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Inspection is in a different state",
+			Code:    "INVALID_INSPECTION_STATE",
+			Details: err.Error(),
+		})
+	case entities.ErrNotPhotoInspection:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Inspection does not accept photos",
+			Code:  "NOT_PHOTO_INSPECTION",
+		})
+	case entities.ErrInspectionPhotosMissing:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   "Photos of all angles are required",
+			Code:    "PHOTOS_MISSING",
+			Details: err.Error(),
+		})
+	case entities.ErrInspectionReviewIncomplete:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Review must include a decision for every photo",
+			Code:  "REVIEW_INCOMPLETE",
+		})
+	case entities.ErrInvalidPhotoAngle:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Angle must be one of front, back, left, right, interior",
+			Code:    "INVALID_PHOTO_ANGLE",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidInspectionPhoto:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Photo must be a JPEG, PNG or HEIC file within the size limit",
+			Code:    "INVALID_PHOTO",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidVehicleID, entities.ErrInvalidDueDate:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid inspection data",
+			Code:    "INVALID_DATA",
+			Details: err.Error(),
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/scheduler"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultJobRunsLimit число запусков в истории по умолчанию
+	defaultJobRunsLimit = 50
+	// maxJobRunsLimit наибольшее число запусков в одном ответе
+	maxJobRunsLimit = 500
+)
+
+// JobStatusProvider планировщик фоновых задач: состояние, история и запуск вне расписания
+type JobStatusProvider interface {
+	Jobs() []scheduler.JobStatus
+	Trigger(name, triggeredBy string) error
+	Runs(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error)
+}
+
+// JobsHandler обработчик HTTP запросов для фоновых задач
+type JobsHandler struct {
+	provider JobStatusProvider
+	logger   *zap.Logger
+}
+
+// NewJobsHandler создает новый JobsHandler
+func NewJobsHandler(provider JobStatusProvider, logger *zap.Logger) *JobsHandler {
+	return &JobsHandler{
+		provider: provider,
+		logger:   logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты фоновых задач
+func (h *JobsHandler) RegisterRoutes(api *gin.RouterGroup) {
+	jobs := api.Group("/admin/jobs")
+	{
+		jobs.GET("", h.ListJobs)
+		jobs.GET("/runs", h.ListRuns)
+		jobs.GET("/:name/runs", h.ListRuns)
+		jobs.POST("/:name/run", h.TriggerJob)
+	}
+}
+
+// ListJobs возвращает время последнего и следующего запуска фоновых задач
+func (h *JobsHandler) ListJobs(c *gin.Context) {
+	jobs := h.provider.Jobs()
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+// ListRuns возвращает историю запусков всех экземпляров сервиса, новые первыми;
+// фильтры status (succeeded, failed) и limit
+func (h *JobsHandler) ListRuns(c *gin.Context) {
+	filters := &entities.JobRunFilters{
+		JobName: c.Param("name"),
+		Status:  entities.JobRunStatus(c.Query("status")),
+		Limit:   defaultJobRunsLimit,
+	}
+	if filters.Status != "" && filters.Status != entities.JobRunSucceeded && filters.Status != entities.JobRunFailed {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Code:    "INVALID_PARAMETER",
+			Details: "expected succeeded or failed",
+		})
+		return
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxJobRunsLimit {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid limit",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected 1.." + strconv.Itoa(maxJobRunsLimit),
+			})
+			return
+		}
+		filters.Limit = limit
+	}
+
+	runs, err := h.provider.Runs(c.Request.Context(), filters)
+	if err != nil {
+		h.handleSchedulerError(c, err, "Failed to list job runs")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"runs":  runs,
+		"count": len(runs),
+	})
+}
+
+// TriggerJob запускает задачу вне расписания. Ответ не ждет завершения задачи:
+// результат появляется в истории запусков
+func (h *JobsHandler) TriggerJob(c *gin.Context) {
+	name := c.Param("name")
+	if err := h.provider.Trigger(name, c.GetString("user_id")); err != nil {
+		h.handleSchedulerError(c, err, "Failed to trigger job")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"job":    name,
+		"status": "started",
+	})
+}
+
+// handleSchedulerError обрабатывает ошибки планировщика
+func (h *JobsHandler) handleSchedulerError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch {
+	case errors.Is(err, scheduler.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Job not found",
+			Code:  "JOB_NOT_FOUND",
+		})
+	case errors.Is(err, scheduler.ErrJobRunning), errors.Is(err, scheduler.ErrJobLocked):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Job is already running",
+			Code:    "JOB_RUNNING",
+			Details: err.Error(),
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LeaderboardHandler обработчик HTTP запросов для рейтингов водителей
+type LeaderboardHandler struct {
+	leaderboardService services.LeaderboardService
+	logger             *zap.Logger
+}
+
+// NewLeaderboardHandler создает новый LeaderboardHandler
+func NewLeaderboardHandler(leaderboardService services.LeaderboardService, logger *zap.Logger) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardService: leaderboardService,
+		logger:             logger,
+	}
+}
+
+// SetVisibilityRequest запрос на изменение приватности в рейтингах
+type SetVisibilityRequest struct {
+	Visibility entities.LeaderboardVisibility `json:"visibility" binding:"required"`
+}
+
+// LeaderboardResponse страница топа водителей
+type LeaderboardResponse struct {
+	*entities.Leaderboard
+	pagination.Page
+}
+
+// leaderboardPage ограничения страницы топа; глубина топа ограничена leaderboard.max_limit
+var leaderboardPage = pagination.Options{DefaultLimit: 10, MaxLimit: 100}
+
+// RegisterRoutes регистрирует маршруты рейтингов
+func (h *LeaderboardHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/leaderboards", h.GetLeaderboard)
+
+	drivers := api.Group("/drivers")
+	{
+		drivers.GET("/:id/leaderboard/rank", h.GetDriverRank)
+		drivers.PUT("/:id/leaderboard/visibility", h.SetVisibility)
+	}
+}
+
+// GetLeaderboard возвращает топ водителей за неделю по показателю
+func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
+	scope, ok := h.parseScope(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, leaderboardPage)
+	if !ok {
+		return
+	}
+
+	// Топ ограничен политикой сервиса, поэтому следующая страница определяется запросом одного лишнего места
+	leaderboard, err := h.leaderboardService.GetLeaderboard(c.Request.Context(), scope, page.Offset+page.Limit+1)
+	if err != nil {
+		h.handleLeaderboardServiceError(c, err, "Failed to get leaderboard")
+		return
+	}
+	hasMore := len(leaderboard.Entries) > page.Offset+page.Limit
+	leaderboard.Entries = pagination.Slice(leaderboard.Entries, page)
+
+	c.JSON(http.StatusOK, &LeaderboardResponse{
+		Leaderboard: leaderboard,
+		Page:        pagination.Paginate(c, page, len(leaderboard.Entries), nil, hasMore),
+	})
+}
+
+// GetDriverRank возвращает место водителя в рейтинге
+func (h *LeaderboardHandler) GetDriverRank(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	scope, ok := h.parseScope(c)
+	if !ok {
+		return
+	}
+
+	rank, err := h.leaderboardService.GetDriverRank(c.Request.Context(), driverID, scope)
+	if err != nil {
+		h.handleLeaderboardServiceError(c, err, "Failed to get driver rank")
+		return
+	}
+
+	c.JSON(http.StatusOK, rank)
+}
+
+// SetVisibility изменяет настройку приватности водителя в рейтингах
+func (h *LeaderboardHandler) SetVisibility(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req SetVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.leaderboardService.SetVisibility(c.Request.Context(), driverID, req.Visibility); err != nil {
+		h.handleLeaderboardServiceError(c, err, "Failed to set leaderboard visibility")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"driver_id":  driverID,
+		"visibility": req.Visibility,
+	})
+}
+
+// parseScope разбирает параметры области рейтинга: metric, week, city, fleet_id
+func (h *LeaderboardHandler) parseScope(c *gin.Context) (*entities.LeaderboardScope, bool) {
+	scope := &entities.LeaderboardScope{
+		Metric:    entities.LeaderboardMetric(c.DefaultQuery("metric", string(entities.LeaderboardMetricTrips))),
+		WeekStart: time.Now(),
+	}
+
+	if weekStr := c.Query("week"); weekStr != "" {
+		week, err := time.Parse("2006-01-02", weekStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid week format",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected YYYY-MM-DD",
+			})
+			return nil, false
+		}
+		scope.WeekStart = week
+	}
+
+	if city := c.Query("city"); city != "" {
+		scope.City = &city
+	}
+
+	if fleetIDStr := c.Query("fleet_id"); fleetIDStr != "" {
+		fleetID, err := uuid.Parse(fleetIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid fleet ID format",
+				Code:  "INVALID_ID",
+			})
+			return nil, false
+		}
+		scope.FleetID = &fleetID
+	}
+
+	return scope, true
+}
+
+// handleLeaderboardServiceError обрабатывает ошибки из LeaderboardService
+func (h *LeaderboardHandler) handleLeaderboardServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrDriverNotRanked:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver is not ranked on this leaderboard",
+			Code:  "DRIVER_NOT_RANKED",
+		})
+	case entities.ErrLeaderboardOptedOut:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver opted out of leaderboards",
+			Code:  "LEADERBOARD_OPTED_OUT",
+		})
+	case entities.ErrInvalidLeaderboardMetric:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid leaderboard metric",
+			Code:  "INVALID_METRIC",
+		})
+	case entities.ErrInvalidLeaderboardVisibility:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid leaderboard visibility",
+			Code:  "INVALID_VISIBILITY",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// LocationAnalyticsHandler обработчик HTTP запросов аналитики потока местоположений
+type LocationAnalyticsHandler struct {
+	analyticsService services.LocationAnalyticsService
+	logger           *zap.Logger
+}
+
+// NewLocationAnalyticsHandler создает новый LocationAnalyticsHandler
+func NewLocationAnalyticsHandler(analyticsService services.LocationAnalyticsService, logger *zap.Logger) *LocationAnalyticsHandler {
+	return &LocationAnalyticsHandler{
+		analyticsService: analyticsService,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты аналитики местоположений
+func (h *LocationAnalyticsHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/analytics/locations/distance", h.GetDistanceByCity)
+}
+
+// GetDistanceByCity возвращает пробег водителей по городам и суткам (UTC). Параметры from и to
+// принимают Unix timestamp или RFC3339; по умолчанию — последние 7 суток. city ограничивает
+// выборку одним городом
+func (h *LocationAnalyticsHandler) GetDistanceByCity(c *gin.Context) {
+	query := &entities.CityDistanceQuery{
+		To:   time.Now(),
+		City: c.Query("city"),
+	}
+	query.From = query.To.AddDate(0, 0, -7)
+	if !parseTimeParam(c, "from", &query.From) || !parseTimeParam(c, "to", &query.To) {
+		return
+	}
+
+	report, err := h.analyticsService.DistanceByCity(c.Request.Context(), query)
+	if err != nil {
+		h.handleAnalyticsServiceError(c, err, "Failed to get distance by city")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleAnalyticsServiceError обрабатывает ошибки из LocationAnalyticsService
+func (h *LocationAnalyticsHandler) handleAnalyticsServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrInvalidAnalyticsRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time range",
+			Code:    "INVALID_ANALYTICS_RANGE",
+			Details: "'from' must be before 'to' and the range must cover at most 92 days",
+		})
+	case entities.ErrLocationAnalyticsDisabled:
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Location analytics is disabled",
+			Code:  "LOCATION_ANALYTICS_DISABLED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
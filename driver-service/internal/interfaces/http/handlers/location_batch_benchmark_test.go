@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/grpc/pb"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// benchmarkBatchSize точек в пакете: приложение водителя отправляет их раз в несколько секунд
+const benchmarkBatchSize = 100
+
+// acceptingLocationService принимает все точки пакета, не сохраняя их, чтобы замерялся только
+// разбор запроса
+type acceptingLocationService struct {
+	services.LocationService
+}
+
+func (acceptingLocationService) BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) (*entities.LocationBatchResult, error) {
+	result := entities.NewLocationBatchResult(len(locations))
+	result.Accepted = len(locations)
+	return result, nil
+}
+
+func newBatchBenchmarkRouter() *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	handler := NewLocationHandler(acceptingLocationService{}, zap.NewNop())
+	router.POST("/drivers/:id/locations/batch", handler.BatchUpdateLocations)
+	return router
+}
+
+// benchmarkBatchBodies возвращает один и тот же пакет в JSON и protobuf
+func benchmarkBatchBodies(driverID uuid.UUID) ([]byte, []byte) {
+	start := time.Now().Add(-time.Hour)
+	jsonBatch := BatchLocationRequest{Locations: make([]UpdateLocationRequest, benchmarkBatchSize)}
+	protoBatch := &pb.LocationBatch{DriverId: driverID.String(), Points: make([]*pb.LocationPoint, benchmarkBatchSize)}
+	for i := 0; i < benchmarkBatchSize; i++ {
+		latitude, longitude := 55.75+float64(i)*0.0001, 37.61+float64(i)*0.0001
+		accuracy, speed, bearing := 5.0, 12.5, 90.0
+		recordedAt := start.Add(time.Duration(i) * time.Second)
+		timestamp := recordedAt.Unix()
+		onTrip, source, provider, battery := true, "app", "gps", int32(80)
+
+		jsonBatch.Locations[i] = UpdateLocationRequest{
+			Latitude:  latitude,
+			Longitude: longitude,
+			Accuracy:  &accuracy,
+			Speed:     &speed,
+			Bearing:   &bearing,
+			Timestamp: &timestamp,
+			Metadata: entities.Metadata{
+				entities.LocationMetaOnTrip:       onTrip,
+				entities.LocationMetaSource:       source,
+				entities.LocationMetaProvider:     provider,
+				entities.LocationMetaBatteryLevel: battery,
+			},
+		}
+		protoBatch.Points[i] = &pb.LocationPoint{
+			Latitude:   latitude,
+			Longitude:  longitude,
+			Accuracy:   &accuracy,
+			Speed:      &speed,
+			Bearing:    &bearing,
+			RecordedAt: timestamppb.New(recordedAt),
+			Metadata: &pb.LocationMetadata{
+				OnTrip:       &onTrip,
+				Source:       &source,
+				Provider:     &provider,
+				BatteryLevel: &battery,
+			},
+		}
+	}
+
+	jsonBody, err := json.Marshal(jsonBatch)
+	if err != nil {
+		panic(err)
+	}
+	protoBody, err := proto.Marshal(protoBatch)
+	if err != nil {
+		panic(err)
+	}
+	return jsonBody, protoBody
+}
+
+func benchmarkBatchUpload(b *testing.B, contentType string, pickBody func(jsonBody, protoBody []byte) []byte) {
+	router := newBatchBenchmarkRouter()
+	driverID := uuid.New()
+	body := pickBody(benchmarkBatchBodies(driverID))
+	path := "/drivers/" + driverID.String() + "/locations/batch"
+
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusOK {
+			b.Fatalf("unexpected status %d: %s", recorder.Code, recorder.Body.String())
+		}
+	}
+	b.ReportMetric(float64(b.N*benchmarkBatchSize)/b.Elapsed().Seconds(), "points/s")
+}
+
+// BenchmarkBatchUpdateLocations_JSON и BenchmarkBatchUpdateLocations_Protobuf сравнивают
+// пропускную способность POST /drivers/{id}/locations/batch для одного и того же пакета:
+//
+//	go test ./internal/interfaces/http/handlers -run '^$' -bench BatchUpdateLocations
+func BenchmarkBatchUpdateLocations_JSON(b *testing.B) {
+	benchmarkBatchUpload(b, "application/json", func(jsonBody, _ []byte) []byte { return jsonBody })
+}
+
+func BenchmarkBatchUpdateLocations_Protobuf(b *testing.B) {
+	benchmarkBatchUpload(b, contentTypeProtobuf, func(_, protoBody []byte) []byte { return protoBody })
+}
@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LocationExportHandler обработчик HTTP запросов выгрузки истории местоположений в файл
+type LocationExportHandler struct {
+	exportService services.LocationExportService
+	logger        *zap.Logger
+}
+
+// NewLocationExportHandler создает новый LocationExportHandler
+func NewLocationExportHandler(exportService services.LocationExportService, logger *zap.Logger) *LocationExportHandler {
+	return &LocationExportHandler{
+		exportService: exportService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты выгрузок истории местоположений
+func (h *LocationExportHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/locations/export", h.RequestExport)
+	api.GET("/drivers/:id/locations/exports/:export_id", h.GetExport)
+	api.GET("/drivers/:id/locations/exports/:export_id/download", h.Download)
+}
+
+// RequestExport ставит выгрузку истории за период [from, to) в очередь и отвечает 202.
+// Вызывающий без доступа к точным координатам получит файл с огрубленными точками
+func (h *LocationExportHandler) RequestExport(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.LocationExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid location export request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	export, err := h.exportService.RequestExport(c.Request.Context(), driverID, &req, preciseLocationAllowed(c))
+	if err != nil {
+		h.handleLocationExportServiceError(c, err, "Failed to request location export")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// GetExport возвращает состояние выгрузки и, для готовой выгрузки, временную ссылку на файл
+func (h *LocationExportHandler) GetExport(c *gin.Context) {
+	driverID, exportID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.GetExport(c.Request.Context(), driverID, exportID, preciseLocationAllowed(c))
+	if err != nil {
+		h.handleLocationExportServiceError(c, err, "Failed to get location export")
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// Download перенаправляет на временную ссылку на файл готовой выгрузки
+func (h *LocationExportHandler) Download(c *gin.Context) {
+	driverID, exportID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.exportService.DownloadURL(c.Request.Context(), driverID, exportID, preciseLocationAllowed(c))
+	if err != nil {
+		h.handleLocationExportServiceError(c, err, "Failed to get location export download URL")
+		return
+	}
+
+	c.Redirect(http.StatusFound, url)
+}
+
+// parseIDs разбирает ID водителя и выгрузки из пути
+func (h *LocationExportHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	exportID, err := uuid.Parse(c.Param("export_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid export ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return driverID, exportID, true
+}
+
+// handleLocationExportServiceError обрабатывает ошибки из LocationExportService
+func (h *LocationExportHandler) handleLocationExportServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrLocationExportNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Location export not found",
+			Code:  "LOCATION_EXPORT_NOT_FOUND",
+		})
+	case entities.ErrInvalidLocationExport:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid location export request",
+			Code:    "INVALID_LOCATION_EXPORT",
+			Details: "format must be csv, to must be after from and the period must not exceed the configured maximum",
+		})
+	case entities.ErrPermissionDenied:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Export contains precise coordinates",
+			Code:  "PERMISSION_DENIED",
+		})
+	case entities.ErrLocationExportNotReady:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Location export is not completed",
+			Code:    "LOCATION_EXPORT_NOT_READY",
+			Details: "check the export status; failed exports must be requested again",
+		})
+	case entities.ErrLocationExportExpired:
+		c.JSON(http.StatusGone, ErrorResponse{
+			Error: "Location export file has expired",
+			Code:  "LOCATION_EXPORT_EXPIRED",
+		})
+	case entities.ErrLocationExportQueueFull:
+		c.Header("Retry-After", retryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Location export queue is full, retry later",
+			Code:  "LOCATION_EXPORT_QUEUE_FULL",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -1,18 +1,26 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"driver-service/internal/domain/entities"
 	"driver-service/internal/domain/services"
+	grpcapi "driver-service/internal/interfaces/grpc"
+	"driver-service/internal/interfaces/grpc/pb"
+	"driver-service/internal/interfaces/http/pagination"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
 )
 
+// contentTypeProtobuf тип тела пакета местоположений в формате protobuf (сообщение LocationBatch)
+const contentTypeProtobuf = "application/x-protobuf"
+
 // LocationHandler обработчик HTTP запросов для местоположений
 type LocationHandler struct {
 	locationService services.LocationService
@@ -36,6 +44,8 @@ type UpdateLocationRequest struct {
 	Speed     *float64 `json:"speed,omitempty"`
 	Bearing   *float64 `json:"bearing,omitempty"`
 	Timestamp *int64   `json:"timestamp,omitempty"`
+	// Metadata известные ключи: on_trip, order_id, source, provider, battery_level
+	Metadata entities.Metadata `json:"metadata,omitempty"`
 }
 
 // BatchLocationRequest запрос на пакетное обновление местоположений
@@ -43,34 +53,57 @@ type BatchLocationRequest struct {
 	Locations []UpdateLocationRequest `json:"locations" binding:"required,min=1"`
 }
 
+// BatchLocationResponse ответ на пакетное обновление местоположений. Count число
+// сохраненных точек; отклоненные перечислены в rejected с позицией в запросе
+type BatchLocationResponse struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+	*entities.LocationBatchResult
+}
+
+// SetLocationPrivacyRequest запрос на изменение режима приватности местоположения
+type SetLocationPrivacyRequest struct {
+	Mode entities.LocationPrivacyMode `json:"mode" binding:"required"`
+}
+
 // LocationResponse ответ с местоположением
 type LocationResponse struct {
-	ID         uuid.UUID `json:"id"`
-	DriverID   uuid.UUID `json:"driver_id"`
-	Latitude   float64   `json:"latitude"`
-	Longitude  float64   `json:"longitude"`
-	Altitude   *float64  `json:"altitude,omitempty"`
-	Accuracy   *float64  `json:"accuracy,omitempty"`
-	Speed      *float64  `json:"speed,omitempty"`
-	Bearing    *float64  `json:"bearing,omitempty"`
-	Address    *string   `json:"address,omitempty"`
-	RecordedAt time.Time `json:"recorded_at"`
-	CreatedAt  time.Time `json:"created_at"`
+	ID         uuid.UUID         `json:"id"`
+	DriverID   uuid.UUID         `json:"driver_id"`
+	Latitude   float64           `json:"latitude"`
+	Longitude  float64           `json:"longitude"`
+	Altitude   *float64          `json:"altitude,omitempty"`
+	Accuracy   *float64          `json:"accuracy,omitempty"`
+	Speed      *float64          `json:"speed,omitempty"`
+	Bearing    *float64          `json:"bearing,omitempty"`
+	Address    *string           `json:"address,omitempty"`
+	Metadata   entities.Metadata `json:"metadata,omitempty"`
+	RecordedAt time.Time         `json:"recorded_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+	// GapBefore перед точкой разрыв трека (stats.gaps): путь от предыдущей точки неизвестен,
+	// и соединять точки линией нельзя — водитель мог двигаться
+	GapBefore bool `json:"gap_before,omitempty"`
 }
 
-// LocationHistoryResponse ответ с историей местоположений
+// LocationHistoryResponse ответ с историей местоположений.
+// Stats рассчитывается за весь период, а не за страницу
 type LocationHistoryResponse struct {
 	Locations []*LocationResponse    `json:"locations"`
 	Stats     *entities.LocationStats `json:"stats"`
 	Count     int                    `json:"count"`
+	pagination.Page
 }
 
 // NearbyDriversResponse ответ с водителями поблизости
 type NearbyDriversResponse struct {
 	Drivers []*NearbyDriverInfo `json:"drivers"`
 	Count   int                 `json:"count"`
+	pagination.Page
 }
 
+// locationHistoryPage ограничения страницы истории местоположений: точек за период много
+var locationHistoryPage = pagination.Options{DefaultLimit: 500, MaxLimit: 1000}
+
 // NearbyDriverInfo информация о водителе поблизости
 type NearbyDriverInfo struct {
 	DriverID  uuid.UUID `json:"driver_id"`
@@ -87,6 +120,7 @@ func (h *LocationHandler) UpdateLocation(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
@@ -98,6 +132,7 @@ func (h *LocationHandler) UpdateLocation(c *gin.Context) {
 		)
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
 			Details: err.Error(),
 		})
 		return
@@ -114,6 +149,9 @@ func (h *LocationHandler) UpdateLocation(c *gin.Context) {
 	location.Accuracy = req.Accuracy
 	location.Speed = req.Speed
 	location.Bearing = req.Bearing
+	if req.Metadata != nil {
+		location.Metadata = req.Metadata
+	}
 
 	// Обновляем местоположение через сервис
 	err = h.locationService.UpdateLocation(c.Request.Context(), location)
@@ -123,6 +161,9 @@ func (h *LocationHandler) UpdateLocation(c *gin.Context) {
 	}
 
 	response := h.toLocationResponse(location)
+	if !preciseLocationAllowed(c) {
+		maskLocationResponse(response)
+	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -133,10 +174,57 @@ func (h *LocationHandler) BatchUpdateLocations(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
 
+	// Приложения с частой отправкой GPS передают пакет в protobuf: его разбор дешевле JSON
+	var locations []*entities.DriverLocation
+	var ok bool
+	if c.ContentType() == contentTypeProtobuf {
+		locations, ok = h.bindProtobufBatch(c, driverID)
+	} else {
+		locations, ok = h.bindJSONBatch(c, driverID)
+	}
+	if !ok {
+		return
+	}
+
+	// Пакетно обновляем местоположения
+	result, err := h.locationService.BatchUpdateLocations(c.Request.Context(), locations)
+	if err != nil {
+		h.handleLocationServiceError(c, err, "Failed to batch update locations")
+		return
+	}
+
+	// Все точки пакета относятся к водителю из пути: если его нет, не сохранена ни одна
+	for _, rejection := range result.Rejected {
+		if rejection.Err == entities.ErrDriverNotFound {
+			h.handleLocationServiceError(c, rejection.Err, "Failed to batch update locations")
+			return
+		}
+	}
+
+	// Пакет, в котором отклонены все точки, не обработан
+	status := http.StatusOK
+	message := "Locations updated successfully"
+	if len(result.Rejected) == result.Received {
+		status = http.StatusUnprocessableEntity
+		message = "All locations were rejected"
+	} else if len(result.Rejected) > 0 {
+		message = "Locations updated partially"
+	}
+
+	c.JSON(status, &BatchLocationResponse{
+		Message:             message,
+		Count:               result.Accepted,
+		LocationBatchResult: result,
+	})
+}
+
+// bindJSONBatch разбирает пакет местоположений в формате JSON
+func (h *LocationHandler) bindJSONBatch(c *gin.Context, driverID uuid.UUID) ([]*entities.DriverLocation, bool) {
 	var req BatchLocationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		h.logger.Error("Invalid batch update request",
@@ -144,9 +232,10 @@ func (h *LocationHandler) BatchUpdateLocations(c *gin.Context) {
 		)
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
 			Details: err.Error(),
 		})
-		return
+		return nil, false
 	}
 
 	// Создаем объекты местоположений
@@ -162,21 +251,62 @@ func (h *LocationHandler) BatchUpdateLocations(c *gin.Context) {
 		location.Accuracy = locReq.Accuracy
 		location.Speed = locReq.Speed
 		location.Bearing = locReq.Bearing
+		if locReq.Metadata != nil {
+			location.Metadata = locReq.Metadata
+		}
 
 		locations[i] = location
 	}
+	return locations, true
+}
 
-	// Пакетно обновляем местоположения
-	err = h.locationService.BatchUpdateLocations(c.Request.Context(), locations)
+// bindProtobufBatch разбирает пакет местоположений в формате protobuf (сообщение LocationBatch
+// gRPC API). driver_id пакета можно не указывать, но если указан, он должен совпадать с путем
+func (h *LocationHandler) bindProtobufBatch(c *gin.Context, driverID uuid.UUID) ([]*entities.DriverLocation, bool) {
+	body, err := c.GetRawData()
 	if err != nil {
-		h.handleLocationServiceError(c, err, "Failed to batch update locations")
-		return
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Failed to read request body",
+			Code:  "INVALID_REQUEST",
+		})
+		return nil, false
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Locations updated successfully",
-		"count":   len(locations),
-	})
+	var batch pb.LocationBatch
+	if err := proto.Unmarshal(body, &batch); err != nil {
+		h.logger.Error("Invalid protobuf batch update request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return nil, false
+	}
+	if batchDriverID, err := uuid.Parse(batch.DriverId); batch.DriverId != "" && (err != nil || batchDriverID != driverID) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Batch driver_id does not match the driver in the path",
+			Code:  "INVALID_REQUEST",
+		})
+		return nil, false
+	}
+	if len(batch.Points) == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid request data",
+			Code: "INVALID_REQUEST",
+			Details: "points are required",
+		})
+		return nil, false
+	}
+
+	return grpcapi.LocationsFromBatch(driverID, &batch, time.Now()), true
+}
+
+// GetBatchStats возвращает счетчики пакетной записи местоположений экземпляра: принятые,
+// повторные и отклоненные точки с разбивкой по кодам ошибок
+func (h *LocationHandler) GetBatchStats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.locationService.BatchStats())
 }
 
 // GetCurrentLocation получает текущее местоположение водителя
@@ -186,6 +316,7 @@ func (h *LocationHandler) GetCurrentLocation(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
@@ -197,6 +328,9 @@ func (h *LocationHandler) GetCurrentLocation(c *gin.Context) {
 	}
 
 	response := h.toLocationResponse(location)
+	if !preciseLocationAllowed(c) {
+		maskLocationResponse(response)
+	}
 	c.JSON(http.StatusOK, response)
 }
 
@@ -207,10 +341,16 @@ func (h *LocationHandler) GetLocationHistory(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
 		})
 		return
 	}
 
+	page, ok := parsePage(c, locationHistoryPage)
+	if !ok {
+		return
+	}
+
 	// Парсим параметры времени
 	var from, to time.Time
 	
@@ -222,6 +362,7 @@ func (h *LocationHandler) GetLocationHistory(c *gin.Context) {
 		} else {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Invalid 'from' time format",
+				Code: "INVALID_PARAMETER",
 				Details: "Use Unix timestamp or RFC3339 format",
 			})
 			return
@@ -238,6 +379,7 @@ func (h *LocationHandler) GetLocationHistory(c *gin.Context) {
 		} else {
 			c.JSON(http.StatusBadRequest, ErrorResponse{
 				Error: "Invalid 'to' time format",
+				Code: "INVALID_PARAMETER",
 				Details: "Use Unix timestamp or RFC3339 format",
 			})
 			return
@@ -246,8 +388,13 @@ func (h *LocationHandler) GetLocationHistory(c *gin.Context) {
 		to = time.Now()
 	}
 
-	// Получаем историю местоположений
-	locations, err := h.locationService.GetLocationHistory(c.Request.Context(), driverID, from, to)
+	filters := &entities.LocationFilters{DriverID: &driverID, From: &from, To: &to}
+	if !parseLocationHistoryFilters(c, filters) {
+		return
+	}
+
+	// Получаем историю местоположений; фильтры по точкам не влияют на статистику трека
+	locations, err := h.locationService.SearchLocationHistory(c.Request.Context(), filters)
 	if err != nil {
 		h.handleLocationServiceError(c, err, "Failed to get location history")
 		return
@@ -263,21 +410,104 @@ func (h *LocationHandler) GetLocationHistory(c *gin.Context) {
 		// Не прерываем выполнение, просто не возвращаем статистику
 	}
 
+	// Отмечаем точки после разрывов трека
+	gapAfter := make(map[uuid.UUID]bool)
+	if stats != nil {
+		for _, gap := range stats.Gaps {
+			gapAfter[gap.ToLocationID] = true
+		}
+	}
+
 	// Преобразуем в ответ
-	locationResponses := make([]*LocationResponse, len(locations))
-	for i, location := range locations {
+	precise := preciseLocationAllowed(c)
+	pageLocations := pagination.Slice(locations, page)
+	locationResponses := make([]*LocationResponse, len(pageLocations))
+	for i, location := range pageLocations {
 		locationResponses[i] = h.toLocationResponse(location)
+		locationResponses[i].GapBefore = gapAfter[location.ID]
+		if !precise {
+			maskLocationResponse(locationResponses[i])
+		}
 	}
 
 	response := &LocationHistoryResponse{
 		Locations: locationResponses,
 		Stats:     stats,
 		Count:     len(locationResponses),
+		Page:      pagination.Paginate(c, page, len(pageLocations), pagination.Total(len(locations)), false),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// parseLocationHistoryFilters разбирает фильтры истории по точкам: min_accuracy (метры),
+// only_high_accuracy, min_speed и max_speed (км/ч), bbox ("min_lon,min_lat,max_lon,max_lat")
+// и order_id, отвечая 400 на неверное значение
+func parseLocationHistoryFilters(c *gin.Context, filters *entities.LocationFilters) bool {
+	floats := []struct {
+		name  string
+		value **float64
+	}{
+		{"min_accuracy", &filters.MinAccuracy},
+		{"min_speed", &filters.MinSpeed},
+		{"max_speed", &filters.MaxSpeed},
+	}
+	for _, param := range floats {
+		str := c.Query(param.name)
+		if str == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid '" + param.name + "' format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return false
+		}
+		*param.value = &value
+	}
+
+	if str := c.Query("only_high_accuracy"); str != "" {
+		value, err := strconv.ParseBool(str)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid 'only_high_accuracy' format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return false
+		}
+		filters.OnlyHighAccuracy = value
+	}
+
+	if str := c.Query("bbox"); str != "" {
+		bounds, err := entities.ParseGeoBounds(str)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid bounding box",
+				Code:    "INVALID_BBOX",
+				Details: "Use min_lon,min_lat,max_lon,max_lat",
+			})
+			return false
+		}
+		filters.Bounds = bounds
+	}
+
+	if str := c.Query("order_id"); str != "" {
+		orderID, err := uuid.Parse(str)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid order ID format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return false
+		}
+		filters.OrderID = &orderID
+	}
+
+	return true
+}
+
 // GetNearbyDrivers получает водителей поблизости
 func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 	// Парсим координаты
@@ -286,6 +516,7 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 	if latStr == "" || lonStr == "" {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Latitude and longitude are required",
+			Code:  "INVALID_PARAMETER",
 		})
 		return
 	}
@@ -294,6 +525,7 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid latitude format",
+			Code:  "INVALID_PARAMETER",
 		})
 		return
 	}
@@ -302,6 +534,7 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 	if err != nil {
 		c.JSON(http.StatusBadRequest, ErrorResponse{
 			Error: "Invalid longitude format",
+			Code:  "INVALID_PARAMETER",
 		})
 		return
 	}
@@ -314,20 +547,31 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 		}
 	}
 
-	// Парсим лимит
-	limit := 20 // По умолчанию 20
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
-			limit = parsed
-		}
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
 	}
 
-	// Получаем водителей поблизости
-	locations, err := h.locationService.GetNearbyDrivers(c.Request.Context(), lat, lon, radiusKm, limit)
+	// tag отбирает водителей со всеми перечисленными метками
+	var tags []string
+	if !parseTagsParam(c, &tags) {
+		return
+	}
+
+	// При шардировании город ограничивает поиск одним шардом
+	ctx := c.Request.Context()
+	if city := c.Query("city"); city != "" {
+		ctx = entities.WithShardCity(ctx, city)
+	}
+
+	// Получаем водителей поблизости с запасом в один элемент, чтобы определить наличие следующей страницы
+	locations, err := h.locationService.GetNearbyDrivers(ctx, lat, lon, radiusKm, page.Offset+page.Limit+1, tags)
 	if err != nil {
 		h.handleLocationServiceError(c, err, "Failed to get nearby drivers")
 		return
 	}
+	hasMore := len(locations) > page.Offset+page.Limit
+	locations = pagination.Slice(locations, page)
 
 	// Преобразуем в ответ
 	centerLocation := &entities.DriverLocation{
@@ -335,14 +579,23 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 		Longitude: lon,
 	}
 
+	// Без доступа к точным координатам расстояние считается до огрубленной точки,
+	// иначе по нему можно восстановить точную
+	precise := preciseLocationAllowed(c)
 	nearbyDrivers := make([]*NearbyDriverInfo, len(locations))
 	for i, location := range locations {
-		distance := centerLocation.DistanceTo(location)
+		point := location
+		if !precise {
+			point = &entities.DriverLocation{
+				Latitude:  entities.CoarsenCoordinate(location.Latitude),
+				Longitude: entities.CoarsenCoordinate(location.Longitude),
+			}
+		}
 		nearbyDrivers[i] = &NearbyDriverInfo{
 			DriverID:  location.DriverID,
-			Latitude:  location.Latitude,
-			Longitude: location.Longitude,
-			Distance:  distance,
+			Latitude:  point.Latitude,
+			Longitude: point.Longitude,
+			Distance:  centerLocation.DistanceTo(point),
 			UpdatedAt: location.RecordedAt,
 		}
 	}
@@ -350,11 +603,45 @@ func (h *LocationHandler) GetNearbyDrivers(c *gin.Context) {
 	response := &NearbyDriversResponse{
 		Drivers: nearbyDrivers,
 		Count:   len(nearbyDrivers),
+		// Общее количество не считается: поиск ограничен запрошенной страницей
+		Page: pagination.Paginate(c, page, len(nearbyDrivers), nil, hasMore),
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// SetLocationPrivacy изменяет режим приватности местоположения водителя вне смены
+func (h *LocationHandler) SetLocationPrivacy(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req SetLocationPrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	if err := h.locationService.SetPrivacyMode(c.Request.Context(), driverID, req.Mode); err != nil {
+		h.handleLocationServiceError(c, err, "Failed to set location privacy")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"driver_id": driverID,
+		"mode":      req.Mode,
+	})
+}
+
 // toLocationResponse преобразует DriverLocation entity в LocationResponse
 func (h *LocationHandler) toLocationResponse(location *entities.DriverLocation) *LocationResponse {
 	return &LocationResponse{
@@ -367,6 +654,7 @@ func (h *LocationHandler) toLocationResponse(location *entities.DriverLocation)
 		Speed:      location.Speed,
 		Bearing:    location.Bearing,
 		Address:    location.Address,
+		Metadata:   location.Metadata,
 		RecordedAt: location.RecordedAt,
 		CreatedAt:  location.CreatedAt,
 	}
@@ -376,6 +664,35 @@ func (h *LocationHandler) toLocationResponse(location *entities.DriverLocation)
 func (h *LocationHandler) handleLocationServiceError(c *gin.Context, err error, message string) {
 	h.logger.Error(message, zap.Error(err))
 
+	if errors.Is(err, entities.ErrInvalidLocationMetadata) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid location metadata",
+			Code:    "INVALID_LOCATION_METADATA",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	// Город водителя переносится на другой шард: запись возобновится после переноса
+	if errors.Is(err, entities.ErrCityRebalancing) {
+		c.Header("Retry-After", retryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "City is being moved to another shard, retry later",
+			Code:  "CITY_REBALANCING",
+		})
+		return
+	}
+
+	// Буфер асинхронной записи заполнен: точку можно отправить повторно
+	if errors.Is(err, entities.ErrLocationIngestionOverloaded) {
+		c.Header("Retry-After", retryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Location ingestion is overloaded, retry later",
+			Code:  "LOCATION_INGESTION_OVERLOADED",
+		})
+		return
+	}
+
 	switch err {
 	case entities.ErrLocationNotFound:
 		c.JSON(http.StatusNotFound, ErrorResponse{
@@ -402,10 +719,17 @@ func (h *LocationHandler) handleLocationServiceError(c *gin.Context, err error,
 			Error: "Driver not found",
 			Code:  "DRIVER_NOT_FOUND",
 		})
-	default:
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Internal server error",
-			Code:  "INTERNAL_ERROR",
+	case entities.ErrInvalidLocationFilters:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid location history filters",
+			Code:  "INVALID_LOCATION_FILTERS",
+		})
+	case entities.ErrInvalidLocationPrivacy:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid location privacy mode",
+			Code:  "INVALID_LOCATION_PRIVACY",
 		})
+	default:
+		respondInternalError(c, err)
 	}
 }
\ No newline at end of file
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+// preciseLocationAllowed сообщает, можно ли отдать вызывающему точные координаты: их видят
+// водитель из пути запроса и вызывающие с областью entities.ScopeLocationReadPrecise.
+// Без аутентификации координаты не огрубляются
+func preciseLocationAllowed(c *gin.Context) bool {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		return true
+	}
+	return claims.HasScope(entities.ScopeLocationReadPrecise) || claims.IsDriver(c.Param("id"))
+}
+
+// maskLocationResponse огрубляет точку ответа так же, как DriverLocation.Blur
+func maskLocationResponse(response *LocationResponse) {
+	response.Latitude = entities.CoarsenCoordinate(response.Latitude)
+	response.Longitude = entities.CoarsenCoordinate(response.Longitude)
+	accuracy := entities.CoarseLocationAccuracy
+	response.Accuracy = &accuracy
+	response.Altitude = nil
+	response.Speed = nil
+	response.Bearing = nil
+	response.Address = nil
+}
+
+// maskTripSummary огрубляет начало, конец и маршрут поездок
+func maskTripSummary(summary *entities.TripSummary) {
+	for _, trip := range summary.Trips {
+		maskTripPoint(&trip.Start)
+		maskTripPoint(&trip.End)
+		for i := range trip.Polyline {
+			maskTripPoint(&trip.Polyline[i])
+		}
+	}
+}
+
+// maskTripPoint огрубляет координаты точки маршрута
+func maskTripPoint(point *entities.TripPoint) {
+	point.Latitude = entities.CoarsenCoordinate(point.Latitude)
+	point.Longitude = entities.CoarsenCoordinate(point.Longitude)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LocationSummaryHandler обработчик HTTP запросов прореженной истории местоположений
+type LocationSummaryHandler struct {
+	retentionService services.LocationRetentionService
+	logger           *zap.Logger
+}
+
+// NewLocationSummaryHandler создает новый LocationSummaryHandler
+func NewLocationSummaryHandler(retentionService services.LocationRetentionService, logger *zap.Logger) *LocationSummaryHandler {
+	return &LocationSummaryHandler{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты прореженной истории
+func (h *LocationSummaryHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/locations/summaries", h.GetSummaries)
+}
+
+// GetSummaries возвращает сводные точки водителя уровня tier за период. Параметры from и to
+// принимают Unix timestamp или RFC3339; по умолчанию — последние 30 суток
+func (h *LocationSummaryHandler) GetSummaries(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	tier := c.Query("tier")
+	if tier == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Parameter 'tier' is required",
+			Code:  "INVALID_PARAMETER",
+		})
+		return
+	}
+
+	to := time.Now()
+	from := to.AddDate(0, 0, -30)
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+
+	summaries, err := h.retentionService.GetSummaries(c.Request.Context(), driverID, tier, from, to)
+	if err != nil {
+		h.handleSummaryServiceError(c, err, "Failed to get location summaries")
+		return
+	}
+	if !preciseLocationAllowed(c) {
+		for _, summary := range summaries {
+			summary.Latitude = entities.CoarsenCoordinate(summary.Latitude)
+			summary.Longitude = entities.CoarsenCoordinate(summary.Longitude)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"driver_id": driverID,
+		"tier":      tier,
+		"from":      from,
+		"to":        to,
+		"summaries": summaries,
+		"count":     len(summaries),
+	})
+}
+
+// handleSummaryServiceError обрабатывает ошибки из LocationRetentionService
+func (h *LocationSummaryHandler) handleSummaryServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrRetentionTierNotFound:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Unknown retention tier",
+			Code:  "UNKNOWN_RETENTION_TIER",
+		})
+	case entities.ErrInvalidSummaryRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time range",
+			Code:    "INVALID_SUMMARY_RANGE",
+			Details: "Range must be positive and cover at most 10000 tier intervals",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MessageHandler обработчик HTTP запросов переписки водителя с диспетчерами
+type MessageHandler struct {
+	messageService services.MessageService
+	logger         *zap.Logger
+}
+
+// NewMessageHandler создает новый MessageHandler
+func NewMessageHandler(messageService services.MessageService, logger *zap.Logger) *MessageHandler {
+	return &MessageHandler{
+		messageService: messageService,
+		logger:         logger,
+	}
+}
+
+// ListMessagesResponse страница переписки
+type ListMessagesResponse struct {
+	Messages []*entities.DriverMessage `json:"messages"`
+	pagination.Page
+}
+
+// AcknowledgeMessagesResponse сообщения после отметки
+type AcknowledgeMessagesResponse struct {
+	Messages []*entities.DriverMessage `json:"messages"`
+}
+
+// RegisterRoutes регистрирует маршруты переписки
+func (h *MessageHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/messages", h.SendMessage)
+	api.GET("/drivers/:id/messages", h.ListMessages)
+	api.POST("/drivers/:id/messages/receipts", h.AcknowledgeMessages)
+}
+
+// MessageParty определяет сторону переписки и субъект токена. Водитель пишет в свою
+// переписку, остальные роли — как диспетчер; без аутентификации используется fallback
+func MessageParty(c *gin.Context, driverID uuid.UUID, fallback entities.MessageSender) (entities.MessageSender, string) {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok {
+		return fallback, ""
+	}
+	if claims.IsDriver(driverID.String()) {
+		return entities.MessageSenderDriver, claims.Subject
+	}
+	return entities.MessageSenderDispatcher, claims.Subject
+}
+
+// SendMessage отправляет сообщение в переписку по заказу (order_id) или в общую
+func (h *MessageHandler) SendMessage(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid send message request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	var senderID string
+	req.Sender, senderID = MessageParty(c, driverID, req.Sender)
+
+	message, err := h.messageService.SendMessage(c.Request.Context(), driverID, senderID, &req)
+	if err != nil {
+		h.handleMessageServiceError(c, err, "Failed to send message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, message)
+}
+
+// ListMessages возвращает переписку, новые сообщения первыми. Без order_id — общая переписка
+func (h *MessageHandler) ListMessages(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.Options{DefaultLimit: 50, MaxLimit: 200})
+	if !ok {
+		return
+	}
+	filters := &entities.MessageFilters{
+		DriverID: driverID,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+	if orderIDStr := c.Query("order_id"); orderIDStr != "" {
+		orderID, err := uuid.Parse(orderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid order_id format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+		filters.OrderID = &orderID
+	}
+
+	messages, err := h.messageService.ListMessages(c.Request.Context(), filters)
+	if err != nil {
+		h.handleMessageServiceError(c, err, "Failed to list messages")
+		return
+	}
+
+	total, err := h.messageService.CountMessages(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count messages",
+			zap.Error(err),
+		)
+		total = len(messages)
+	}
+
+	c.JSON(http.StatusOK, &ListMessagesResponse{
+		Messages: messages,
+		Page:     pagination.Paginate(c, page, len(messages), pagination.Total(total), false),
+	})
+}
+
+// AcknowledgeMessages отмечает сообщения другой стороны доставленными или прочитанными
+func (h *MessageHandler) AcknowledgeMessages(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.MessageReceiptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid message receipt request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	req.Reader, _ = MessageParty(c, driverID, req.Reader)
+
+	messages, err := h.messageService.AcknowledgeMessages(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleMessageServiceError(c, err, "Failed to acknowledge messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, &AcknowledgeMessagesResponse{Messages: messages})
+}
+
+// handleMessageServiceError обрабатывает ошибки из MessageService
+func (h *MessageHandler) handleMessageServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrMessageNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Message not found",
+			Code:  "MESSAGE_NOT_FOUND",
+		})
+	case entities.ErrInvalidMessage:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid message",
+			Code:    "INVALID_MESSAGE",
+			Details: MessageRules,
+		})
+	case entities.ErrInvalidMessageReceipt:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid message receipt",
+			Code:    "INVALID_MESSAGE_RECEIPT",
+			Details: ReceiptRules,
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
+
+// Правила сообщений и отметок для ответов об ошибках REST и WebSocket
+const (
+	MessageRules = "kind must be arrived, delayed, on_the_way, call_me or text; text is required for text and limited to 500 characters; " +
+		"delay_minutes (1-120) is required for delayed only; sender (driver or dispatcher) is required when auth is disabled"
+	ReceiptRules = "status must be delivered or read; 1-100 message_ids; reader (driver or dispatcher) is required when auth is disabled"
+)
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// OrderTrackingHandler обработчик HTTP запросов отслеживания заказов
+type OrderTrackingHandler struct {
+	trackingService services.OrderTrackingService
+	// requestsPerMinute ограничение запросов к ссылке отслеживания; 0 — без ограничения
+	requestsPerMinute int
+	logger            *zap.Logger
+}
+
+// NewOrderTrackingHandler создает новый OrderTrackingHandler
+func NewOrderTrackingHandler(trackingService services.OrderTrackingService, requestsPerMinute int, logger *zap.Logger) *OrderTrackingHandler {
+	return &OrderTrackingHandler{
+		trackingService:   trackingService,
+		requestsPerMinute: requestsPerMinute,
+		logger:            logger,
+	}
+}
+
+// RegisterRoutes регистрирует начало и остановку отслеживания заказа
+func (h *OrderTrackingHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.POST("/:id/orders/:order_id/tracking", h.StartTracking)
+		drivers.DELETE("/:id/orders/:order_id/tracking", h.StopTracking)
+	}
+}
+
+// RegisterPublicRoutes регистрирует страницу отслеживания для клиента: доступ дает подписанный
+// токен в пути, запросы по одному токену ограничены по частоте
+func (h *OrderTrackingHandler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	limit := middleware.RateLimitPerMinute(h.requestsPerMinute, func(c *gin.Context) string {
+		return c.Param("token")
+	})
+	public.GET("/tracking/:token", limit, h.GetTrackedLocation)
+}
+
+// StartTracking начинает отслеживание заказа водителя и возвращает ссылку для клиента
+func (h *OrderTrackingHandler) StartTracking(c *gin.Context) {
+	driverID, orderID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	link, err := h.trackingService.StartTracking(c.Request.Context(), driverID, orderID, c.GetString("user_id"))
+	if err != nil {
+		h.handleTrackingServiceError(c, err, "Failed to start order tracking")
+		return
+	}
+
+	c.JSON(http.StatusCreated, link)
+}
+
+// StopTracking останавливает отслеживание заказа водителя
+func (h *OrderTrackingHandler) StopTracking(c *gin.Context) {
+	driverID, orderID, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	session, err := h.trackingService.StopTracking(c.Request.Context(), driverID, orderID)
+	if err != nil {
+		h.handleTrackingServiceError(c, err, "Failed to stop order tracking")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// GetTrackedLocation возвращает клиенту огрубленное местоположение водителя по ссылке
+func (h *OrderTrackingHandler) GetTrackedLocation(c *gin.Context) {
+	location, err := h.trackingService.GetTrackedLocation(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		h.handleTrackingServiceError(c, err, "Failed to get tracked location")
+		return
+	}
+
+	// Точка меняется с каждым обновлением водителя
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, location)
+}
+
+// parseIDs разбирает ID водителя и заказа из пути, отвечая 400 на неверный формат
+func (h *OrderTrackingHandler) parseIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	orderID, err := uuid.Parse(c.Param("order_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid order ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return driverID, orderID, true
+}
+
+// handleTrackingServiceError обрабатывает ошибки из OrderTrackingService
+func (h *OrderTrackingHandler) handleTrackingServiceError(c *gin.Context, err error, message string) {
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidOrderTracking:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Order tracking requires a driver and an order",
+			Code:  "INVALID_ORDER_TRACKING",
+		})
+	case entities.ErrOrderTrackingNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Order tracking not found",
+			Code:  "ORDER_TRACKING_NOT_FOUND",
+		})
+	case entities.ErrOrderTrackingExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Order is already tracked",
+			Code:  "ORDER_TRACKING_EXISTS",
+		})
+	case entities.ErrInvalidTrackingToken:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Tracking link is invalid",
+			Code:  "INVALID_TRACKING_TOKEN",
+		})
+	case entities.ErrOrderTrackingInactive:
+		c.JSON(http.StatusGone, ErrorResponse{
+			Error: "Order tracking is no longer active",
+			Code:  "ORDER_TRACKING_INACTIVE",
+		})
+	case entities.ErrOrderTrackingDisabled:
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error: "Order tracking links are disabled",
+			Code:  "ORDER_TRACKING_DISABLED",
+		})
+	case entities.ErrLocationNotFound, entities.ErrLocationTooOld:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Driver location is not available yet",
+			Code:    "LOCATION_NOT_FOUND",
+			Details: "the driver has not reported a recent location",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		respondInternalError(c, err)
+	}
+}
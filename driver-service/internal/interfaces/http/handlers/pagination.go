@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePage разбирает параметры страницы списочного эндпоинта, отвечая 400 на неверные значения
+func parsePage(c *gin.Context, opts pagination.Options) (pagination.Request, bool) {
+	req, err := pagination.Parse(c, opts)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid pagination parameters",
+			Code:    "INVALID_PAGINATION",
+			Details: err.Error(),
+		})
+		return pagination.Request{}, false
+	}
+	return req, true
+}
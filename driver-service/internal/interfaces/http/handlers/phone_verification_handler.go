@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PhoneVerificationHandler обработчик HTTP запросов подтверждения телефона водителя
+type PhoneVerificationHandler struct {
+	verificationService services.PhoneVerificationService
+	logger              *zap.Logger
+}
+
+// NewPhoneVerificationHandler создает новый PhoneVerificationHandler
+func NewPhoneVerificationHandler(verificationService services.PhoneVerificationService, logger *zap.Logger) *PhoneVerificationHandler {
+	return &PhoneVerificationHandler{
+		verificationService: verificationService,
+		logger:              logger,
+	}
+}
+
+// PhoneVerifiedResponse ответ на подтверждение телефона
+type PhoneVerifiedResponse struct {
+	DriverID        uuid.UUID `json:"driver_id"`
+	PhoneVerifiedAt time.Time `json:"phone_verified_at"`
+}
+
+// RegisterRoutes регистрирует маршруты подтверждения телефона
+func (h *PhoneVerificationHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/verify-phone", h.SendCode)
+	api.POST("/drivers/:id/verify-phone/confirm", h.ConfirmCode)
+}
+
+// SendCode отправляет код подтверждения на телефон водителя
+func (h *PhoneVerificationHandler) SendCode(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	sent, err := h.verificationService.SendCode(c.Request.Context(), driverID)
+	if err != nil {
+		h.handlePhoneVerificationError(c, err, "Failed to send phone verification code")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, sent)
+}
+
+// ConfirmCode подтверждает телефон кодом и возвращает водителя
+func (h *PhoneVerificationHandler) ConfirmCode(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ConfirmPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	driver, err := h.verificationService.ConfirmCode(c.Request.Context(), driverID, req.Code)
+	if err != nil {
+		h.handlePhoneVerificationError(c, err, "Failed to confirm phone verification code")
+		return
+	}
+
+	c.JSON(http.StatusOK, &PhoneVerifiedResponse{
+		DriverID:        driver.ID,
+		PhoneVerifiedAt: *driver.PhoneVerifiedAt,
+	})
+}
+
+// handlePhoneVerificationError обрабатывает ошибки сервиса подтверждения телефона
+func (h *PhoneVerificationHandler) handlePhoneVerificationError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrPhoneAlreadyVerified:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver phone is already verified",
+			Code:  "PHONE_ALREADY_VERIFIED",
+		})
+	case entities.ErrVerificationCodeNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "No pending verification code",
+			Code:    "VERIFICATION_CODE_NOT_FOUND",
+			Details: "request a new code via POST /drivers/{id}/verify-phone",
+		})
+	case entities.ErrVerificationCodeExpired:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Verification code has expired",
+			Code:  "VERIFICATION_CODE_EXPIRED",
+		})
+	case entities.ErrInvalidVerificationCode:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid verification code",
+			Code:  "INVALID_VERIFICATION_CODE",
+		})
+	case entities.ErrVerificationCodeRecentlySent:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: "Verification code was sent recently",
+			Code:  "VERIFICATION_CODE_RECENTLY_SENT",
+		})
+	case entities.ErrTooManyVerificationAttempts:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Too many verification attempts",
+			Code:    "TOO_MANY_VERIFICATION_ATTEMPTS",
+			Details: "request a new code via POST /drivers/{id}/verify-phone",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
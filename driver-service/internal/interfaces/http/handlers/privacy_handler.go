@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PrivacyHandler обработчик HTTP запросов субъекта персональных данных
+type PrivacyHandler struct {
+	privacyService services.PrivacyService
+	logger         *zap.Logger
+}
+
+// NewPrivacyHandler создает новый PrivacyHandler
+func NewPrivacyHandler(privacyService services.PrivacyService, logger *zap.Logger) *PrivacyHandler {
+	return &PrivacyHandler{
+		privacyService: privacyService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты выгрузки и удаления персональных данных
+func (h *PrivacyHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.GET("/:id/export", h.ExportData)
+		drivers.DELETE("/:id/personal-data", h.ErasePersonalData)
+	}
+}
+
+// ExportData выгружает все данные водителя. Параметр format: json (по умолчанию) или zip
+func (h *PrivacyHandler) ExportData(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	format := entities.ExportFormat(c.DefaultQuery("format", string(entities.ExportFormatJSON)))
+	if format != entities.ExportFormatJSON && format != entities.ExportFormatZIP {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid export format",
+			Code:  "INVALID_PARAMETER",
+		})
+		return
+	}
+
+	export, err := h.privacyService.ExportData(c.Request.Context(), driverID)
+	if err != nil {
+		h.handlePrivacyServiceError(c, err, "Failed to export driver data")
+		return
+	}
+
+	filename := fmt.Sprintf("driver-%s-%s.%s", driverID, export.ExportedAt.Format("20060102-150405"), format)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	if format == entities.ExportFormatJSON {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	// Архив формируется в буфере, чтобы ошибка не оборвала ответ на середине
+	var buf bytes.Buffer
+	if err := h.privacyService.WriteArchive(&buf, export); err != nil {
+		h.handlePrivacyServiceError(c, err, "Failed to build driver data archive")
+		return
+	}
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// ErasePersonalData обезличивает водителя, сохраняя сводную статистику
+func (h *PrivacyHandler) ErasePersonalData(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	erasure, err := h.privacyService.ErasePersonalData(c.Request.Context(), driverID, c.GetString("user_id"))
+	if err != nil {
+		h.handlePrivacyServiceError(c, err, "Failed to erase driver personal data")
+		return
+	}
+
+	c.JSON(http.StatusOK, erasure)
+}
+
+// parseDriverID разбирает ID водителя из пути
+func (h *PrivacyHandler) parseDriverID(c *gin.Context) (uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, false
+	}
+	return driverID, true
+}
+
+// handlePrivacyServiceError обрабатывает ошибки сервиса персональных данных
+func (h *PrivacyHandler) handlePrivacyServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrErasureBlocked:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Personal data cannot be erased while the driver is on shift or on order",
+			Code:  "ERASURE_BLOCKED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
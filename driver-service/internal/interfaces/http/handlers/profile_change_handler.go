@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ProfileChangeHandler обработчик HTTP запросов заявок на изменение юридически значимых
+// полей профиля
+type ProfileChangeHandler struct {
+	changeService services.ProfileChangeService
+	logger        *zap.Logger
+}
+
+// NewProfileChangeHandler создает новый ProfileChangeHandler
+func NewProfileChangeHandler(changeService services.ProfileChangeService, logger *zap.Logger) *ProfileChangeHandler {
+	return &ProfileChangeHandler{
+		changeService: changeService,
+		logger:        logger,
+	}
+}
+
+// ProfileChangeResponse заявка на изменение профиля для вызывающего запроса. Новые значения
+// паспорта и номера удостоверения видят только вызывающие с доступом к персональным данным
+// водителя (piiAllowed); остальным эти поля перечислены в RedactedFields без значений
+type ProfileChangeResponse struct {
+	*entities.ProfileChange
+	// RedactedFields поля заявки, значения которых скрыты от вызывающего без области pii:read
+	RedactedFields []string `json:"redacted_fields,omitempty"`
+}
+
+// ProfileChangesResponse ответ со страницей заявок на изменение профиля
+type ProfileChangesResponse struct {
+	Changes []*ProfileChangeResponse `json:"changes"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты заявок водителя и очереди рассмотрения
+func (h *ProfileChangeHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.POST("/drivers/:id/profile-changes", h.SubmitChange)
+	api.GET("/drivers/:id/profile-changes", h.ListDriverChanges)
+
+	admin := api.Group("/admin/profile-changes")
+	{
+		admin.GET("", h.ListPendingChanges)
+		admin.POST("/:change_id/approve", h.ApproveChange)
+		admin.POST("/:change_id/reject", h.RejectChange)
+	}
+}
+
+// SubmitChange сохраняет заявку водителя на изменение ФИО, паспорта или номера удостоверения
+func (h *ProfileChangeHandler) SubmitChange(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ProfileChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	change, err := h.changeService.Submit(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleChangeServiceError(c, err, "Failed to submit profile change")
+		return
+	}
+
+	c.JSON(http.StatusCreated, toProfileChangeResponse(c, change))
+}
+
+// ListDriverChanges возвращает заявки водителя, новые первыми
+func (h *ProfileChangeHandler) ListDriverChanges(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	changes, err := h.changeService.ListByDriver(c.Request.Context(), driverID, page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleChangeServiceError(c, err, "Failed to list driver profile changes")
+		return
+	}
+	h.respondPage(c, page, changes)
+}
+
+// ListPendingChanges возвращает заявки, ожидающие решения, старые первыми
+func (h *ProfileChangeHandler) ListPendingChanges(c *gin.Context) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	changes, err := h.changeService.ListPending(c.Request.Context(), page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleChangeServiceError(c, err, "Failed to list pending profile changes")
+		return
+	}
+	h.respondPage(c, page, changes)
+}
+
+// ApproveChange одобряет заявку и вносит ее значения в профиль; автор решения — субъект токена
+func (h *ProfileChangeHandler) ApproveChange(c *gin.Context) {
+	h.review(c, h.changeService.Approve, "Failed to approve profile change")
+}
+
+// RejectChange отклоняет заявку; причина отказа передается в комментарии
+func (h *ProfileChangeHandler) RejectChange(c *gin.Context) {
+	h.review(c, h.changeService.Reject, "Failed to reject profile change")
+}
+
+// review разбирает решение по заявке и передает его в resolve
+func (h *ProfileChangeHandler) review(
+	c *gin.Context,
+	resolve func(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error),
+	message string,
+) {
+	changeID, err := uuid.Parse(c.Param("change_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid profile change ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	// Комментарий к одобрению необязателен, тело можно не передавать
+	var req entities.ProfileChangeReviewRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request data",
+				Code:    "INVALID_REQUEST",
+				Details: err.Error(),
+			})
+			return
+		}
+	}
+	req.ReviewerID = c.GetString("user_id")
+
+	change, err := resolve(c.Request.Context(), changeID, &req)
+	if err != nil {
+		h.handleChangeServiceError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, toProfileChangeResponse(c, change))
+}
+
+// respondPage отвечает страницей заявок; changes запрошены с одной лишней заявкой, чтобы
+// определить наличие следующей страницы
+func (h *ProfileChangeHandler) respondPage(c *gin.Context, page pagination.Request, changes []*entities.ProfileChange) {
+	hasMore := len(changes) > page.Limit
+	if hasMore {
+		changes = changes[:page.Limit]
+	}
+
+	responses := make([]*ProfileChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = toProfileChangeResponse(c, change)
+	}
+
+	c.JSON(http.StatusOK, &ProfileChangesResponse{
+		Changes: responses,
+		Page:    pagination.Paginate(c, page, len(changes), nil, hasMore),
+	})
+}
+
+// toProfileChangeResponse преобразует заявку в ответ для вызывающего запроса c: значения
+// персональных полей скрываются, если вызывающему они недоступны (piiAllowed)
+func toProfileChangeResponse(c *gin.Context, change *entities.ProfileChange) *ProfileChangeResponse {
+	if piiAllowed(c, change.DriverID) {
+		return &ProfileChangeResponse{ProfileChange: change}
+	}
+
+	visible := *change
+	visible.Changes = make(entities.Metadata, len(change.Changes))
+	var redacted []string
+	for _, name := range change.Fields() {
+		if isPIIField(name) {
+			redacted = append(redacted, name)
+			continue
+		}
+		visible.Changes[name] = change.Changes[name]
+	}
+	return &ProfileChangeResponse{ProfileChange: &visible, RedactedFields: redacted}
+}
+
+// handleChangeServiceError обрабатывает ошибки из ProfileChangeService
+func (h *ProfileChangeHandler) handleChangeServiceError(c *gin.Context, err error, message string) {
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrProfileChangeNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Profile change request not found",
+			Code:  "PROFILE_CHANGE_NOT_FOUND",
+		})
+	case entities.ErrInvalidProfileChange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid profile change request",
+			Code:    "INVALID_PROFILE_CHANGE",
+			Details: "changes must include at least one new value for first_name, last_name, middle_name, passport_series, passport_number or license_number; rejection requires a comment",
+		})
+	case entities.ErrInvalidName, entities.ErrInvalidPassport, entities.ErrInvalidLicense:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver data",
+			Code:    "INVALID_DATA",
+			Details: err.Error(),
+		})
+	case entities.ErrProfileChangePending:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver already has a pending profile change request",
+			Code:  "PROFILE_CHANGE_PENDING",
+		})
+	case entities.ErrProfileChangeResolved:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Profile change request has already been resolved",
+			Code:  "PROFILE_CHANGE_RESOLVED",
+		})
+	case entities.ErrDriverExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "License number belongs to another driver",
+			Code:  "DRIVER_EXISTS",
+		})
+	default:
+		h.logger.Error(message, zap.Error(err))
+		respondInternalError(c, err)
+	}
+}
+
+// rejectUnapprovedProfileChange отвечает 403, если водитель своим токеном меняет заполненные
+// юридически значимые поля профиля: такие изменения подаются заявкой и вступают в силу после
+// одобрения. Сотрудники меняют профиль напрямую, изменение попадает в журнал аудита
+func rejectUnapprovedProfileChange(c *gin.Context, before, after *entities.Driver) bool {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok || !claims.IsDriver(before.ID.String()) || claims.HasRole(middleware.RoleDispatcher, middleware.RoleAdmin) {
+		return false
+	}
+
+	fields := entities.LegalProfileChanges(before, after)
+	if len(fields) == 0 {
+		return false
+	}
+	respondDomainError(c, fmt.Errorf("%w: %s", entities.ErrProfileChangeRequiresApproval, strings.Join(fields, ", ")))
+	return true
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubVerifier принимает токены из набора
+type stubVerifier map[string]*middleware.Claims
+
+func (v stubVerifier) Verify(ctx context.Context, token string) (*middleware.Claims, error) {
+	claims, ok := v[token]
+	if !ok {
+		return nil, middleware.ErrInvalidSignature
+	}
+	return claims, nil
+}
+
+// fixedProfileChangeService возвращает одну и ту же заявку на любой запрос
+type fixedProfileChangeService struct {
+	services.ProfileChangeService
+	change *entities.ProfileChange
+}
+
+func (s fixedProfileChangeService) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error) {
+	return []*entities.ProfileChange{s.change}, nil
+}
+
+func (s fixedProfileChangeService) ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error) {
+	return []*entities.ProfileChange{s.change}, nil
+}
+
+func (s fixedProfileChangeService) Approve(ctx context.Context, changeID uuid.UUID, req *entities.ProfileChangeReviewRequest) (*entities.ProfileChange, error) {
+	return s.change, nil
+}
+
+func TestProfileChangeHandler_RedactsPII(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	driverID := uuid.New()
+	change := entities.NewProfileChange(driverID, &entities.ProfileChangeRequest{
+		Changes: map[string]string{
+			"last_name":       "Петрова",
+			"passport_number": "654321",
+			"license_number":  "77AB123456",
+		},
+	}, entities.AuditActor{}, time.Now())
+
+	verifier := stubVerifier{
+		"dispatcher": {Subject: "dispatcher-1", Roles: []middleware.Role{middleware.RoleDispatcher}},
+		"admin":      {Subject: "admin-1", Roles: []middleware.Role{middleware.RoleAdmin}},
+		"driver":     {Subject: "driver-1", Roles: []middleware.Role{middleware.RoleDriver}, DriverID: &driverID},
+	}
+	router := gin.New()
+	router.Use(middleware.Authenticate(verifier, zap.NewNop()))
+	NewProfileChangeHandler(fixedProfileChangeService{change: change}, zap.NewNop()).RegisterRoutes(router.Group("/api/v1"))
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	assertRedacted := func(w *httptest.ResponseRecorder, response *ProfileChangeResponse) {
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.NotContains(t, w.Body.String(), "654321")
+		assert.NotContains(t, w.Body.String(), "77AB123456")
+		require.NotNil(t, response.ProfileChange)
+		assert.Equal(t, entities.Metadata{"last_name": "Петрова"}, response.Changes)
+		assert.Equal(t, []string{"license_number", "passport_number"}, response.RedactedFields)
+	}
+
+	for _, path := range []string{"/api/v1/drivers/" + driverID.String() + "/profile-changes", "/api/v1/admin/profile-changes"} {
+		w := request(http.MethodGet, path, "dispatcher")
+		var page ProfileChangesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page), path)
+		require.Len(t, page.Changes, 1, path)
+		assertRedacted(w, page.Changes[0])
+	}
+
+	w := request(http.MethodPost, "/api/v1/admin/profile-changes/"+change.ID.String()+"/approve", "admin")
+	var approved ProfileChangeResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &approved))
+	assertRedacted(w, &approved)
+
+	// Сам водитель видит значения своей заявки
+	w = request(http.MethodGet, "/api/v1/drivers/"+driverID.String()+"/profile-changes", "driver")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "654321")
+	assert.NotContains(t, w.Body.String(), "redacted_fields")
+	assert.Equal(t, "654321", change.Changes["passport_number"], "the stored change is not modified")
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ProfileHandler обработчик HTTP запросов для профиля водителя
+type ProfileHandler struct {
+	profileService services.ProfileService
+	logger         *zap.Logger
+}
+
+// NewProfileHandler создает новый ProfileHandler
+func NewProfileHandler(profileService services.ProfileService, logger *zap.Logger) *ProfileHandler {
+	return &ProfileHandler{
+		profileService: profileService,
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты профиля
+func (h *ProfileHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/profile/completeness", h.GetCompleteness)
+}
+
+// GetCompleteness возвращает заполненность профиля водителя и список недостающих данных
+func (h *ProfileHandler) GetCompleteness(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	completeness, err := h.profileService.GetCompleteness(c.Request.Context(), driverID)
+	if err != nil {
+		h.logger.Error("Failed to get profile completeness", zap.Error(err))
+
+		if err == entities.ErrDriverNotFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error: "Driver not found",
+				Code:  "DRIVER_NOT_FOUND",
+			})
+			return
+		}
+
+		respondInternalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, completeness)
+}
@@ -0,0 +1,313 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// RatingHandler обработчик HTTP запросов для оценок водителей
+type RatingHandler struct {
+	ratingService services.RatingService
+	logger        *zap.Logger
+}
+
+// NewRatingHandler создает новый RatingHandler
+func NewRatingHandler(ratingService services.RatingService, logger *zap.Logger) *RatingHandler {
+	return &RatingHandler{
+		ratingService: ratingService,
+		logger:        logger,
+	}
+}
+
+// ListRatingsResponse ответ со списком оценок
+type ListRatingsResponse struct {
+	Ratings []*entities.RatingResponse `json:"ratings"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты оценок водителей
+func (h *RatingHandler) RegisterRoutes(api *gin.RouterGroup) {
+	ratings := api.Group("/drivers/:id/ratings")
+	{
+		ratings.POST("", h.AddRating)
+		ratings.GET("", h.ListRatings)
+		ratings.GET("/stats", h.GetRatingStats)
+		ratings.POST("/:rating_id/verify", h.VerifyRating)
+	}
+}
+
+// AddRating добавляет оценку водителю
+func (h *RatingHandler) AddRating(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.RatingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid add rating request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	rating, err := h.ratingService.AddRating(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleRatingServiceError(c, err, "Failed to add rating")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rating.ToResponse())
+}
+
+// ListRatings получает оценки водителя с фильтрами
+func (h *RatingHandler) ListRatings(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, filters, ok := h.parseFilters(c)
+	if !ok {
+		return
+	}
+	filters.DriverID = &driverID
+
+	ratings, err := h.ratingService.ListRatings(c.Request.Context(), filters)
+	if err != nil {
+		h.handleRatingServiceError(c, err, "Failed to list ratings")
+		return
+	}
+
+	total, err := h.ratingService.CountRatings(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count ratings",
+			zap.Error(err),
+		)
+		total = len(ratings)
+	}
+
+	responses := make([]*entities.RatingResponse, len(ratings))
+	for i, rating := range ratings {
+		responses[i] = rating.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, &ListRatingsResponse{
+		Ratings: responses,
+		Page:    pagination.Paginate(c, page, len(ratings), pagination.Total(total), false),
+	})
+}
+
+// GetRatingStats возвращает распределение оценок, средние по критериям и процентили
+func (h *RatingHandler) GetRatingStats(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	stats, err := h.ratingService.GetRatingStats(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleRatingServiceError(c, err, "Failed to get rating stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats.ToResponse())
+}
+
+// VerifyRating подтверждает оценку: в рейтинге водителя учитываются только подтвержденные оценки
+func (h *RatingHandler) VerifyRating(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	ratingID, err := uuid.Parse(c.Param("rating_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid rating ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	rating, err := h.ratingService.VerifyRating(c.Request.Context(), driverID, ratingID)
+	if err != nil {
+		h.handleRatingServiceError(c, err, "Failed to verify rating")
+		return
+	}
+
+	c.JSON(http.StatusOK, rating.ToResponse())
+}
+
+// parseFilters разбирает параметры запроса в страницу и фильтры оценок
+func (h *RatingHandler) parseFilters(c *gin.Context) (pagination.Request, *entities.RatingFilters, bool) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return page, nil, false
+	}
+	filters := &entities.RatingFilters{
+		Limit:         page.Limit,
+		Offset:        page.Offset,
+		SortBy:        c.DefaultQuery("sort_by", "created_at"),
+		SortDirection: c.DefaultQuery("sort_direction", "desc"),
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **uuid.UUID
+	}{
+		{"customer_id", &filters.CustomerID},
+		{"order_id", &filters.OrderID},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid " + param.name + " format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return page, nil, false
+		}
+		*param.target = &id
+	}
+
+	if typesStr := c.Query("rating_type"); typesStr != "" {
+		for _, ratingType := range strings.Split(typesStr, ",") {
+			filters.RatingType = append(filters.RatingType, entities.RatingType(strings.TrimSpace(ratingType)))
+		}
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **int
+	}{
+		{"min_rating", &filters.MinRating},
+		{"max_rating", &filters.MaxRating},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		rating, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid " + param.name + " format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return page, nil, false
+		}
+		*param.target = &rating
+	}
+
+	if verifiedStr := c.Query("is_verified"); verifiedStr != "" {
+		verified, err := strconv.ParseBool(verifiedStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid is_verified format",
+				Code:  "INVALID_PARAMETER",
+			})
+			return page, nil, false
+		}
+		filters.IsVerified = &verified
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"from", &filters.From},
+		{"to", &filters.To},
+	} {
+		value := c.Query(param.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid " + param.name + " format",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected RFC3339",
+			})
+			return page, nil, false
+		}
+		*param.target = &parsed
+	}
+
+	return page, filters, true
+}
+
+// handleRatingServiceError обрабатывает ошибки из RatingService
+func (h *RatingHandler) handleRatingServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrRatingNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Rating not found",
+			Code:  "RATING_NOT_FOUND",
+		})
+	case entities.ErrRatingExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Order has already been rated",
+			Code:  "RATING_EXISTS",
+		})
+	case entities.ErrRatingRateLimited:
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: "Too many ratings from customer",
+			Code:  "RATING_RATE_LIMITED",
+		})
+	case entities.ErrInvalidRating, entities.ErrInvalidCriteriaScore, entities.ErrInvalidRatingType:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid rating data",
+			Code:    "INVALID_RATING",
+			Details: err.Error(),
+		})
+	case entities.ErrInvalidTimestamp:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid time range",
+			Code:  "INVALID_TIME_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
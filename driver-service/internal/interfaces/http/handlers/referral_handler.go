@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultPayoutPeriod период отчета о выплатах, если from не указан
+const defaultPayoutPeriod = 30 * 24 * time.Hour
+
+// ReferralHandler обработчик HTTP запросов реферальной программы
+type ReferralHandler struct {
+	referralService services.ReferralService
+	logger          *zap.Logger
+}
+
+// NewReferralHandler создает новый ReferralHandler
+func NewReferralHandler(referralService services.ReferralService, logger *zap.Logger) *ReferralHandler {
+	return &ReferralHandler{
+		referralService: referralService,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты реферальной программы
+func (h *ReferralHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers/:id")
+	{
+		drivers.GET("/referral-code", h.GetReferralCode)
+		drivers.POST("/referrals", h.RegisterReferral)
+		drivers.GET("/referrals", h.ListReferrals)
+	}
+
+	api.GET("/admin/referrals/payouts", h.PayoutReport)
+}
+
+// GetReferralCode возвращает реферальный код водителя, создавая его при первом запросе
+func (h *ReferralHandler) GetReferralCode(c *gin.Context) {
+	driverID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	code, err := h.referralService.GetCode(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleReferralServiceError(c, err, "Failed to get referral code")
+		return
+	}
+
+	c.JSON(http.StatusOK, code)
+}
+
+// RegisterReferral регистрирует водителя, приглашенного водителем из пути по его коду
+func (h *ReferralHandler) RegisterReferral(c *gin.Context) {
+	referrerID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	var req entities.ReferralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	referral, err := h.referralService.RegisterReferral(c.Request.Context(), referrerID, &req)
+	if err != nil {
+		h.handleReferralServiceError(c, err, "Failed to register referral")
+		return
+	}
+
+	c.JSON(http.StatusCreated, referral)
+}
+
+// ListReferrals возвращает водителей, приглашенных водителем, и их прогресс
+func (h *ReferralHandler) ListReferrals(c *gin.Context) {
+	referrerID, ok := h.parseDriverID(c)
+	if !ok {
+		return
+	}
+
+	referrals, err := h.referralService.ListReferrals(c.Request.Context(), referrerID)
+	if err != nil {
+		h.handleReferralServiceError(c, err, "Failed to list referrals")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"referrals": referrals,
+		"total":     len(referrals),
+	})
+}
+
+// PayoutReport возвращает рефералы с правом на выплату, завершенные в [from, to).
+// По умолчанию to — текущее время, from — за 30 дней до to
+func (h *ReferralHandler) PayoutReport(c *gin.Context) {
+	var from, to time.Time
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultPayoutPeriod)
+	}
+
+	report, err := h.referralService.PayoutReport(c.Request.Context(), from, to)
+	if err != nil {
+		h.handleReferralServiceError(c, err, "Failed to build referral payout report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// parseDriverID разбирает ID водителя из пути
+func (h *ReferralHandler) parseDriverID(c *gin.Context) (uuid.UUID, bool) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return uuid.Nil, false
+	}
+	return driverID, true
+}
+
+// handleReferralServiceError обрабатывает ошибки из ReferralService
+func (h *ReferralHandler) handleReferralServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidReferral:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Referred driver must differ from the referrer",
+			Code:  "INVALID_REFERRAL",
+		})
+	case entities.ErrInvalidReferralCode:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Referral code does not belong to the referrer",
+			Code:  "INVALID_REFERRAL_CODE",
+		})
+	case entities.ErrReferralNotEligible:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "Only drivers that have not passed verification can be referred",
+			Code:  "REFERRAL_NOT_ELIGIBLE",
+		})
+	case entities.ErrReferralExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver is already referred",
+			Code:  "REFERRAL_EXISTS",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ReverificationHandler обработчик HTTP запросов кампаний перепроверки документов
+type ReverificationHandler struct {
+	reverificationService services.ReverificationService
+	logger                *zap.Logger
+}
+
+// NewReverificationHandler создает новый ReverificationHandler
+func NewReverificationHandler(reverificationService services.ReverificationService, logger *zap.Logger) *ReverificationHandler {
+	return &ReverificationHandler{
+		reverificationService: reverificationService,
+		logger:                logger,
+	}
+}
+
+// CampaignsResponse ответ со страницей кампаний перепроверки
+type CampaignsResponse struct {
+	Campaigns []*entities.ReverificationCampaign `json:"campaigns"`
+	pagination.Page
+}
+
+// CampaignTargetsResponse ответ со страницей документов кампании
+type CampaignTargetsResponse struct {
+	Targets []*entities.CampaignTarget `json:"targets"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты кампаний перепроверки
+func (h *ReverificationHandler) RegisterRoutes(api *gin.RouterGroup) {
+	admin := api.Group("/admin/reverification/campaigns")
+	{
+		admin.POST("", h.CreateCampaign)
+		admin.GET("", h.ListCampaigns)
+		admin.GET("/:id", h.GetCampaign)
+		admin.GET("/:id/targets", h.ListTargets)
+		admin.POST("/:id/cancel", h.CancelCampaign)
+	}
+}
+
+// CreateCampaign запускает кампанию перепроверки документов сегмента водителей
+func (h *ReverificationHandler) CreateCampaign(c *gin.Context) {
+	var req entities.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid reverification campaign request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	if req.CreatedBy == "" {
+		req.CreatedBy = c.GetString("user_id")
+	}
+
+	details, err := h.reverificationService.CreateCampaign(c.Request.Context(), &req)
+	if err != nil {
+		h.handleReverificationServiceError(c, err, "Failed to create reverification campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, details)
+}
+
+// ListCampaigns получает кампании перепроверки, новые первыми. Фильтр: status
+func (h *ReverificationHandler) ListCampaigns(c *gin.Context) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	status := entities.CampaignStatus(c.Query("status"))
+	switch status {
+	case "", entities.CampaignActive, entities.CampaignCompleted, entities.CampaignCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid campaign status",
+			Code:  "INVALID_PARAMETER",
+		})
+		return
+	}
+
+	// Запрашиваем на одну запись больше, чтобы определить наличие следующей страницы
+	campaigns, err := h.reverificationService.ListCampaigns(c.Request.Context(), status, page.Limit+1, page.Offset)
+	if err != nil {
+		h.handleReverificationServiceError(c, err, "Failed to list reverification campaigns")
+		return
+	}
+
+	hasMore := len(campaigns) > page.Limit
+	if hasMore {
+		campaigns = campaigns[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &CampaignsResponse{
+		Campaigns: campaigns,
+		Page:      pagination.Paginate(c, page, len(campaigns), nil, hasMore),
+	})
+}
+
+// GetCampaign получает кампанию с ходом перепроверки
+func (h *ReverificationHandler) GetCampaign(c *gin.Context) {
+	campaignID, ok := h.parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.reverificationService.GetCampaign(c.Request.Context(), campaignID)
+	if err != nil {
+		h.handleReverificationServiceError(c, err, "Failed to get reverification campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// ListTargets получает документы кампании. Фильтры: status, driver_id
+func (h *ReverificationHandler) ListTargets(c *gin.Context) {
+	campaignID, ok := h.parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	filters := &entities.CampaignTargetFilters{
+		CampaignID: &campaignID,
+		Limit:      page.Limit + 1,
+		Offset:     page.Offset,
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := entities.CampaignTargetStatus(statusStr)
+		switch status {
+		case entities.CampaignTargetPending, entities.CampaignTargetCompleted,
+			entities.CampaignTargetOverdue, entities.CampaignTargetCancelled:
+			filters.Status = []entities.CampaignTargetStatus{status}
+		default:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid campaign target status",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+	}
+
+	if driverIDStr := c.Query("driver_id"); driverIDStr != "" {
+		driverID, err := uuid.Parse(driverIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid driver ID format",
+				Code:  "INVALID_DRIVER_ID",
+			})
+			return
+		}
+		filters.DriverID = &driverID
+	}
+
+	targets, err := h.reverificationService.ListTargets(c.Request.Context(), filters)
+	if err != nil {
+		h.handleReverificationServiceError(c, err, "Failed to list reverification targets")
+		return
+	}
+
+	hasMore := len(targets) > page.Limit
+	if hasMore {
+		targets = targets[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &CampaignTargetsResponse{
+		Targets: targets,
+		Page:    pagination.Paginate(c, page, len(targets), nil, hasMore),
+	})
+}
+
+// CancelCampaign отменяет кампанию и снимает требование перепроверки с неперепроверенных документов
+func (h *ReverificationHandler) CancelCampaign(c *gin.Context) {
+	campaignID, ok := h.parseCampaignID(c)
+	if !ok {
+		return
+	}
+
+	details, err := h.reverificationService.CancelCampaign(c.Request.Context(), campaignID)
+	if err != nil {
+		h.handleReverificationServiceError(c, err, "Failed to cancel reverification campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, details)
+}
+
+// parseCampaignID разбирает ID кампании из пути
+func (h *ReverificationHandler) parseCampaignID(c *gin.Context) (uuid.UUID, bool) {
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid campaign ID format",
+			Code:  "INVALID_ID",
+		})
+		return uuid.Nil, false
+	}
+	return campaignID, true
+}
+
+// handleReverificationServiceError обрабатывает ошибки сервиса кампаний перепроверки
+func (h *ReverificationHandler) handleReverificationServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrCampaignNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Reverification campaign not found",
+			Code:  "CAMPAIGN_NOT_FOUND",
+		})
+	case entities.ErrCampaignClosed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Reverification campaign is already closed",
+			Code:  "CAMPAIGN_CLOSED",
+		})
+	case entities.ErrCampaignNoTargets:
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Error: "No verified documents match the campaign segment",
+			Code:  "CAMPAIGN_NO_TARGETS",
+		})
+	case entities.ErrInvalidCampaign:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Campaign deadline must be in the future and document types must be set",
+			Code:  "INVALID_CAMPAIGN",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ScheduleHandler обработчик HTTP запросов расписаний доступности
+type ScheduleHandler struct {
+	scheduleService services.ScheduleService
+	logger          *zap.Logger
+}
+
+// NewScheduleHandler создает новый ScheduleHandler
+func NewScheduleHandler(scheduleService services.ScheduleService, logger *zap.Logger) *ScheduleHandler {
+	return &ScheduleHandler{
+		scheduleService: scheduleService,
+		logger:          logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты расписаний
+func (h *ScheduleHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/schedule", h.GetSchedule)
+	api.PUT("/drivers/:id/schedule", h.SetSchedule)
+	api.DELETE("/drivers/:id/schedule", h.DeleteSchedule)
+}
+
+// GetSchedule возвращает расписание доступности водителя
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	schedule, err := h.scheduleService.GetSchedule(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleScheduleServiceError(c, err, "Failed to get driver schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// SetSchedule заменяет расписание доступности водителя
+func (h *ScheduleHandler) SetSchedule(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.SetScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid set schedule request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.scheduleService.SetSchedule(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleScheduleServiceError(c, err, "Failed to set driver schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteSchedule удаляет расписание: водитель снова доступен в любое время
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	if err := h.scheduleService.DeleteSchedule(c.Request.Context(), driverID); err != nil {
+		h.handleScheduleServiceError(c, err, "Failed to delete driver schedule")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// handleScheduleServiceError обрабатывает ошибки из ScheduleService
+func (h *ScheduleHandler) handleScheduleServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrScheduleNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Schedule not found",
+			Code:  "SCHEDULE_NOT_FOUND",
+		})
+	case entities.ErrInvalidSchedule:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid schedule",
+			Code:    "INVALID_SCHEDULE",
+			Details: "Timezone must be a valid IANA name; 1-50 windows with unique days mon..sun, start and end as HH:MM (end may be 24:00, end before start spans midnight)",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
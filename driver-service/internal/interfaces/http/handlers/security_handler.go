@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SecurityHandler обработчик HTTP запросов событий безопасности аккаунтов водителей
+type SecurityHandler struct {
+	securityService services.SecurityService
+	logger          *zap.Logger
+}
+
+// NewSecurityHandler создает новый SecurityHandler
+func NewSecurityHandler(securityService services.SecurityService, logger *zap.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		securityService: securityService,
+		logger:          logger,
+	}
+}
+
+// SecurityEventsResponse ответ со страницей событий безопасности
+type SecurityEventsResponse struct {
+	Events []*entities.SecurityEvent `json:"events"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты событий безопасности
+func (h *SecurityHandler) RegisterRoutes(api *gin.RouterGroup) {
+	admin := api.Group("/admin/security/events")
+	{
+		admin.GET("", h.ListEvents)
+		admin.POST("/:id/review", h.ReviewEvent)
+	}
+}
+
+// ListEvents получает события безопасности, новые первыми.
+// Фильтры: status, type, driver_id
+func (h *SecurityHandler) ListEvents(c *gin.Context) {
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одну запись больше, чтобы определить наличие следующей страницы
+	filters := &entities.SecurityEventFilters{
+		Status: entities.SecurityEventStatus(c.Query("status")),
+		Limit:  page.Limit + 1,
+		Offset: page.Offset,
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		filters.Type = entities.SecurityEventType(typeStr)
+		if !filters.Type.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid security event type",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+	}
+
+	if driverIDStr := c.Query("driver_id"); driverIDStr != "" {
+		driverID, err := uuid.Parse(driverIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid driver ID format",
+				Code:  "INVALID_DRIVER_ID",
+			})
+			return
+		}
+		filters.DriverID = &driverID
+	}
+
+	events, err := h.securityService.ListEvents(c.Request.Context(), filters)
+	if err != nil {
+		h.handleSecurityServiceError(c, err, "Failed to list security events")
+		return
+	}
+
+	hasMore := len(events) > page.Limit
+	if hasMore {
+		events = events[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &SecurityEventsResponse{
+		Events: events,
+		Page:   pagination.Paginate(c, page, len(events), nil, hasMore),
+	})
+}
+
+// ReviewEvent подтверждает или отклоняет событие и при необходимости отзывает сессии водителя
+func (h *SecurityHandler) ReviewEvent(c *gin.Context) {
+	eventID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid security event ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	var req entities.SecurityReview
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.Error("Invalid security review request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+	if req.ReviewerID == "" {
+		req.ReviewerID = c.GetString("user_id")
+	}
+	if req.ReviewerID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Reviewer ID is required",
+			Code:  "INVALID_REQUEST",
+		})
+		return
+	}
+
+	event, err := h.securityService.ReviewEvent(c.Request.Context(), eventID, &req)
+	if err != nil {
+		h.handleSecurityServiceError(c, err, "Failed to review security event")
+		return
+	}
+
+	c.JSON(http.StatusOK, event)
+}
+
+// handleSecurityServiceError обрабатывает ошибки сервиса событий безопасности
+func (h *SecurityHandler) handleSecurityServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrSecurityEventNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Security event not found",
+			Code:  "SECURITY_EVENT_NOT_FOUND",
+		})
+	case entities.ErrSecurityEventReviewed:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Security event has already been reviewed",
+			Code:  "SECURITY_EVENT_REVIEWED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
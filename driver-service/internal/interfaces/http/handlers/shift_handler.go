@@ -0,0 +1,376 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ShiftHandler обработчик HTTP запросов для смен водителей
+type ShiftHandler struct {
+	shiftService services.ShiftService
+	logger       *zap.Logger
+}
+
+// NewShiftHandler создает новый ShiftHandler
+func NewShiftHandler(shiftService services.ShiftService, logger *zap.Logger) *ShiftHandler {
+	return &ShiftHandler{
+		shiftService: shiftService,
+		logger:       logger,
+	}
+}
+
+// ListShiftsResponse ответ со списком смен
+type ListShiftsResponse struct {
+	Shifts []*entities.ShiftResponse `json:"shifts"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты смен
+func (h *ShiftHandler) RegisterRoutes(api *gin.RouterGroup) {
+	drivers := api.Group("/drivers")
+	{
+		drivers.POST("/:id/shifts/start", h.StartShift)
+		drivers.POST("/:id/shifts/end", h.EndShift)
+		drivers.GET("/:id/shifts/active", h.GetActiveShift)
+		drivers.GET("/:id/shifts", h.ListShifts)
+		drivers.GET("/:id/shifts/stats", h.GetShiftStats)
+		drivers.POST("/:id/shifts/breaks/start", h.StartBreak)
+		drivers.POST("/:id/shifts/breaks/end", h.EndBreak)
+		drivers.GET("/:id/shifts/:shift_id/breaks", h.GetShiftBreaks)
+	}
+}
+
+// StartShift начинает смену водителя
+func (h *ShiftHandler) StartShift(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ShiftStartRequest
+	// Тело запроса необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.logger.Error("Invalid start shift request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shift, err := h.shiftService.StartShift(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to start shift")
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift.ToResponse())
+}
+
+// EndShift завершает активную смену водителя
+func (h *ShiftHandler) EndShift(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ShiftEndRequest
+	// Тело запроса необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		h.logger.Error("Invalid end shift request",
+			zap.Error(err),
+		)
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shift, err := h.shiftService.EndShift(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to end shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift.ToResponse())
+}
+
+// GetActiveShift получает активную смену водителя
+func (h *ShiftHandler) GetActiveShift(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	shift, err := h.shiftService.GetActiveShift(c.Request.Context(), driverID)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to get active shift")
+		return
+	}
+
+	c.JSON(http.StatusOK, shift.ToResponse())
+}
+
+// ListShifts получает историю смен водителя
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	filters := &entities.ShiftFilters{
+		DriverID: &driverID,
+		Limit:    page.Limit,
+		Offset:   page.Offset,
+	}
+
+	if statusStr := c.Query("status"); statusStr != "" {
+		filters.Status = []entities.ShiftStatus{entities.ShiftStatus(statusStr)}
+	}
+
+	filters.SortBy = c.Query("sort_by")
+	filters.SortDirection = c.Query("sort_direction")
+
+	shifts, err := h.shiftService.ListShifts(c.Request.Context(), filters)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to list shifts")
+		return
+	}
+
+	total, err := h.shiftService.CountShifts(c.Request.Context(), filters)
+	if err != nil {
+		h.logger.Error("Failed to count shifts",
+			zap.Error(err),
+		)
+		total = len(shifts)
+	}
+
+	responses := make([]*entities.ShiftResponse, len(shifts))
+	for i, shift := range shifts {
+		responses[i] = shift.ToResponse()
+	}
+
+	c.JSON(http.StatusOK, &ListShiftsResponse{
+		Shifts: responses,
+		Page:   pagination.Paginate(c, page, len(shifts), pagination.Total(total), false),
+	})
+}
+
+// StartBreak начинает перерыв в активной смене водителя
+func (h *ShiftHandler) StartBreak(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ShiftBreakStartRequest
+	// Тело запроса необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shiftBreak, err := h.shiftService.StartBreak(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to start shift break")
+		return
+	}
+
+	c.JSON(http.StatusCreated, shiftBreak)
+}
+
+// EndBreak заканчивает идущий перерыв в активной смене водителя
+func (h *ShiftHandler) EndBreak(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req entities.ShiftBreakEndRequest
+	// Тело запроса необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	shiftBreak, err := h.shiftService.EndBreak(c.Request.Context(), driverID, &req)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to end shift break")
+		return
+	}
+
+	c.JSON(http.StatusOK, shiftBreak)
+}
+
+// GetShiftBreaks возвращает перерывы смены и соблюдение требований к отдыху
+func (h *ShiftHandler) GetShiftBreaks(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	shiftID, err := uuid.Parse(c.Param("shift_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid shift ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	report, err := h.shiftService.GetShiftBreaks(c.Request.Context(), driverID, shiftID)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to get shift breaks")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetShiftStats возвращает статистику смен водителя за период по началу смены
+func (h *ShiftHandler) GetShiftStats(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	filters := &entities.ShiftFilters{DriverID: &driverID}
+	var from, to time.Time
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+	if !from.IsZero() {
+		filters.From = &from
+	}
+	if !to.IsZero() {
+		filters.To = &to
+	}
+
+	stats, err := h.shiftService.GetShiftStats(c.Request.Context(), filters)
+	if err != nil {
+		h.handleShiftServiceError(c, err, "Failed to get shift stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// handleShiftServiceError обрабатывает ошибки из ShiftService
+func (h *ShiftHandler) handleShiftServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrShiftNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Shift not found",
+			Code:  "SHIFT_NOT_FOUND",
+		})
+	case entities.ErrShiftExists:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Active shift already exists",
+			Code:  "SHIFT_EXISTS",
+		})
+	case entities.ErrShiftNotActive:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift is not active",
+			Code:  "SHIFT_NOT_ACTIVE",
+		})
+	case entities.ErrInvalidShiftBreak:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid shift break",
+			Code:    "INVALID_SHIFT_BREAK",
+			Details: "break must start within the shift and end after it starts, not in the future",
+		})
+	case entities.ErrShiftBreakActive:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift break already in progress",
+			Code:  "SHIFT_BREAK_ACTIVE",
+		})
+	case entities.ErrShiftBreakNotActive:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "No shift break in progress",
+			Code:  "SHIFT_BREAK_NOT_ACTIVE",
+		})
+	case entities.ErrShiftBreakOverlap:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Shift break overlaps another break",
+			Code:  "SHIFT_BREAK_OVERLAP",
+		})
+	case entities.ErrDriverNotAvailable:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver is not available",
+			Code:  "DRIVER_NOT_AVAILABLE",
+		})
+	case entities.ErrInspectionOverdue:
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error: "Vehicle inspection is overdue",
+			Code:  "INSPECTION_OVERDUE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
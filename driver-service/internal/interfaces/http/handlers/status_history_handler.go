@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StatusHistoryHandler обработчик HTTP запросов истории статусов водителей
+type StatusHistoryHandler struct {
+	historyService services.StatusHistoryService
+	logger         *zap.Logger
+}
+
+// NewStatusHistoryHandler создает новый StatusHistoryHandler
+func NewStatusHistoryHandler(historyService services.StatusHistoryService, logger *zap.Logger) *StatusHistoryHandler {
+	return &StatusHistoryHandler{
+		historyService: historyService,
+		logger:         logger,
+	}
+}
+
+// StatusHistoryResponse ответ со страницей истории статусов водителя
+type StatusHistoryResponse struct {
+	Transitions []*entities.StatusTransition `json:"transitions"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты истории статусов
+func (h *StatusHistoryHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/status-history", h.ListTransitions)
+}
+
+// ListTransitions возвращает смены статуса водителя за период changed_at [from, to) в порядке
+// времени: по ним видно, когда водитель был свободен, на смене или заблокирован
+func (h *StatusHistoryHandler) ListTransitions(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	// Запрашиваем на одну запись больше, чтобы определить наличие следующей страницы
+	filters := &entities.StatusHistoryFilters{
+		DriverID: driverID,
+		Limit:    page.Limit + 1,
+		Offset:   page.Offset,
+	}
+	var from, to time.Time
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+	if !from.IsZero() {
+		filters.From = &from
+	}
+	if !to.IsZero() {
+		filters.To = &to
+	}
+
+	transitions, err := h.historyService.ListTransitions(c.Request.Context(), filters)
+	if err != nil {
+		h.handleStatusHistoryServiceError(c, err, "Failed to list driver status history")
+		return
+	}
+
+	hasMore := len(transitions) > page.Limit
+	if hasMore {
+		transitions = transitions[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &StatusHistoryResponse{
+		Transitions: transitions,
+		Page:        pagination.Paginate(c, page, len(transitions), nil, hasMore),
+	})
+}
+
+// handleStatusHistoryServiceError обрабатывает ошибки из StatusHistoryService
+func (h *StatusHistoryHandler) handleStatusHistoryServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidStatusHistoryRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Parameter 'from' must be before 'to'",
+			Code:  "INVALID_STATUS_HISTORY_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// StatusOverrideHandler обработчик HTTP запросов для ручной смены статуса водителя
+type StatusOverrideHandler struct {
+	driverService services.DriverService
+	logger        *zap.Logger
+}
+
+// NewStatusOverrideHandler создает новый StatusOverrideHandler
+func NewStatusOverrideHandler(driverService services.DriverService, logger *zap.Logger) *StatusOverrideHandler {
+	return &StatusOverrideHandler{
+		driverService: driverService,
+		logger:        logger,
+	}
+}
+
+// OverrideStatusRequest запрос ручной смены статуса
+type OverrideStatusRequest struct {
+	Status  string `json:"status" binding:"required"`
+	Reason  string `json:"reason" binding:"required"`
+	ActorID string `json:"actor_id" binding:"required"`
+}
+
+// RegisterRoutes регистрирует маршруты ручной смены статуса
+func (h *StatusOverrideHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.PATCH("/admin/drivers/:id/status", h.OverrideStatus)
+}
+
+// OverrideStatus устанавливает статус водителя в обход правил переходов
+func (h *StatusOverrideHandler) OverrideStatus(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	var req OverrideStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	override, err := h.driverService.OverrideDriverStatus(c.Request.Context(), driverID, entities.Status(req.Status), req.Reason, req.ActorID)
+	if err != nil {
+		h.handleStatusOverrideServiceError(c, err, "Failed to override driver status")
+		return
+	}
+
+	c.JSON(http.StatusOK, override)
+}
+
+// handleStatusOverrideServiceError обрабатывает ошибки ручной смены статуса
+func (h *StatusOverrideHandler) handleStatusOverrideServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidStatusOverride:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Status override requires a known status, a reason and an actor ID",
+			Code:  "INVALID_STATUS_OVERRIDE",
+		})
+	case entities.ErrStatusUnchanged:
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error: "Driver already has this status",
+			Code:  "STATUS_UNCHANGED",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// SupplyHandler обработчик HTTP запросов для карты предложения водителей
+type SupplyHandler struct {
+	supplyService services.SupplyService
+	logger        *zap.Logger
+}
+
+// NewSupplyHandler создает новый SupplyHandler
+func NewSupplyHandler(supplyService services.SupplyService, logger *zap.Logger) *SupplyHandler {
+	return &SupplyHandler{
+		supplyService: supplyService,
+		logger:        logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты аналитики предложения
+func (h *SupplyHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/analytics/supply-heatmap", h.GetSupplyHeatmap)
+}
+
+// GetSupplyHeatmap возвращает число водителей по ячейкам geohash внутри области bbox
+// ("min_lon,min_lat,max_lon,max_lat"); cell — длина geohash ячейки
+func (h *SupplyHandler) GetSupplyHeatmap(c *gin.Context) {
+	bounds, err := entities.ParseGeoBounds(c.Query("bbox"))
+	if err != nil {
+		h.handleSupplyServiceError(c, err, "Invalid supply heatmap bounds")
+		return
+	}
+
+	precision := 0
+	if cellStr := c.Query("cell"); cellStr != "" {
+		precision, err = strconv.Atoi(cellStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid cell format",
+				Code:    "INVALID_PARAMETER",
+				Details: "expected geohash precision",
+			})
+			return
+		}
+	}
+
+	heatmap, err := h.supplyService.GetHeatmap(c.Request.Context(), bounds, precision)
+	if err != nil {
+		h.handleSupplyServiceError(c, err, "Failed to get supply heatmap")
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+// handleSupplyServiceError обрабатывает ошибки из SupplyService
+func (h *SupplyHandler) handleSupplyServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrInvalidBoundingBox:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid bounding box",
+			Code:    "INVALID_BBOX",
+			Details: "expected bbox=min_lon,min_lat,max_lon,max_lat",
+		})
+	case entities.ErrInvalidHeatmapCellSize:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid heatmap cell precision",
+			Code:  "INVALID_CELL",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
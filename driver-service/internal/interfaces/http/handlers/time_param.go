@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseTimeParam разбирает параметр времени в формате Unix timestamp или RFC3339, отвечая 400
+// на неверное значение; при отсутствии параметра значение не меняется
+func parseTimeParam(c *gin.Context, name string, value *time.Time) bool {
+	str := c.Query(name)
+	if str == "" {
+		return true
+	}
+
+	if unix, err := strconv.ParseInt(str, 10, 64); err == nil {
+		*value = time.Unix(unix, 0)
+		return true
+	}
+	if parsed, err := time.Parse(time.RFC3339, str); err == nil {
+		*value = parsed
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Invalid '" + name + "' time format",
+		Code:    "INVALID_PARAMETER",
+		Details: "Use Unix timestamp or RFC3339 format",
+	})
+	return false
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// TripHandler обработчик HTTP запросов поездок водителя
+type TripHandler struct {
+	tripService services.TripAnalysisService
+	logger      *zap.Logger
+}
+
+// NewTripHandler создает новый TripHandler
+func NewTripHandler(tripService services.TripAnalysisService, logger *zap.Logger) *TripHandler {
+	return &TripHandler{
+		tripService: tripService,
+		logger:      logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты поездок
+func (h *TripHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/drivers/:id/trips", h.GetTrips)
+}
+
+// GetTrips восстанавливает поездки водителя за период. Параметры from и to принимают
+// Unix timestamp или RFC3339; по умолчанию — последние 24 часа
+func (h *TripHandler) GetTrips(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid driver ID format",
+			Code:  "INVALID_DRIVER_ID",
+		})
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+	if !parseTimeParam(c, "from", &from) || !parseTimeParam(c, "to", &to) {
+		return
+	}
+
+	summary, err := h.tripService.GetTrips(c.Request.Context(), driverID, from, to)
+	if err != nil {
+		h.handleTripServiceError(c, err, "Failed to get driver trips")
+		return
+	}
+
+	if !preciseLocationAllowed(c) {
+		maskTripSummary(summary)
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// handleTripServiceError обрабатывает ошибки из TripAnalysisService
+func (h *TripHandler) handleTripServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrDriverNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Driver not found",
+			Code:  "DRIVER_NOT_FOUND",
+		})
+	case entities.ErrInvalidTripRange:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid time range",
+			Code:  "INVALID_TRIP_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"driver-service/internal/domain/services"
+)
+
+// openUpload открывает загруженный файл. Тип файла определяется по содержимому,
+// заголовку клиента доверяем только для неизвестных форматов. Возвращаемый io.Closer
+// нужно закрыть после сохранения файла
+func openUpload(header *multipart.FileHeader) (*services.FileUpload, io.Closer, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		file.Close()
+		return nil, nil, err
+	}
+
+	contentType := http.DetectContentType(head[:n])
+	if contentType == "application/octet-stream" {
+		contentType = header.Header.Get("Content-Type")
+	}
+
+	return &services.FileUpload{
+		ContentType: strings.TrimSpace(strings.Split(contentType, ";")[0]),
+		Size:        header.Size,
+		Body:        io.MultiReader(bytes.NewReader(head[:n]), file),
+	}, file, nil
+}
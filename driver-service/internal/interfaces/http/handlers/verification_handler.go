@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// VerificationHandler обработчик HTTP запросов очереди проверки документов
+type VerificationHandler struct {
+	verificationService services.DocumentVerificationService
+	logger              *zap.Logger
+}
+
+// NewVerificationHandler создает новый VerificationHandler
+func NewVerificationHandler(verificationService services.DocumentVerificationService, logger *zap.Logger) *VerificationHandler {
+	return &VerificationHandler{
+		verificationService: verificationService,
+		logger:              logger,
+	}
+}
+
+// ClaimDocumentsRequest запрос на захват документов из очереди
+type ClaimDocumentsRequest struct {
+	VerifierID string `json:"verifier_id" binding:"required"`
+	Limit      int    `json:"limit" binding:"omitempty,min=1"`
+}
+
+// ClaimDocumentsResponse захваченные документы
+type ClaimDocumentsResponse struct {
+	Documents []*entities.DriverDocument `json:"documents"`
+	Count     int                        `json:"count"`
+}
+
+// SubmitDecisionsRequest пакет решений верификатора
+type SubmitDecisionsRequest struct {
+	VerifierID string                       `json:"verifier_id" binding:"required"`
+	Decisions  []*entities.DocumentDecision `json:"decisions" binding:"required,min=1,dive"`
+}
+
+// RegisterRoutes регистрирует маршруты очереди проверки документов
+func (h *VerificationHandler) RegisterRoutes(api *gin.RouterGroup) {
+	verification := api.Group("/admin/documents/verification")
+	{
+		verification.POST("/claim", h.ClaimDocuments)
+		verification.POST("/decisions", h.SubmitDecisions)
+		verification.GET("/stats", h.GetVerifierStats)
+	}
+}
+
+// ClaimDocuments закрепляет за верификатором следующие документы из очереди
+func (h *VerificationHandler) ClaimDocuments(c *gin.Context) {
+	var req ClaimDocumentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	documents, err := h.verificationService.ClaimNext(c.Request.Context(), req.VerifierID, req.Limit)
+	if err != nil {
+		h.handleVerificationServiceError(c, err, "Failed to claim documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, &ClaimDocumentsResponse{
+		Documents: documents,
+		Count:     len(documents),
+	})
+}
+
+// SubmitDecisions применяет решения по захваченным документам
+func (h *VerificationHandler) SubmitDecisions(c *gin.Context) {
+	var req SubmitDecisionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request body",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	result, err := h.verificationService.SubmitDecisions(c.Request.Context(), req.VerifierID, req.Decisions)
+	if err != nil {
+		h.handleVerificationServiceError(c, err, "Failed to submit decisions")
+		return
+	}
+
+	// Частичный успех: результат по каждому документу в теле ответа
+	status := http.StatusOK
+	if result.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	c.JSON(status, result)
+}
+
+// GetVerifierStats возвращает производительность верификаторов за период (по умолчанию последние 24 часа)
+func (h *VerificationHandler) GetVerifierStats(c *gin.Context) {
+	to := time.Now()
+	from := to.Add(-24 * time.Hour)
+
+	for _, param := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{"from", &from},
+		{"to", &to},
+	} {
+		if str := c.Query(param.name); str != "" {
+			parsed, err := time.Parse(time.RFC3339, str)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, ErrorResponse{
+					Error:   "Invalid " + param.name + " format",
+					Code:    "INVALID_PARAMETER",
+					Details: "expected RFC3339",
+				})
+				return
+			}
+			*param.value = parsed
+		}
+	}
+
+	report, err := h.verificationService.GetVerifierStats(c.Request.Context(), from, to)
+	if err != nil {
+		h.handleVerificationServiceError(c, err, "Failed to get verifier stats")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// handleVerificationServiceError обрабатывает ошибки из DocumentVerificationService
+func (h *VerificationHandler) handleVerificationServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrInvalidVerifierID:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid verifier ID",
+			Code:  "INVALID_VERIFIER_ID",
+		})
+	case entities.ErrInvalidDecisionBatch:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Decision batch is empty or too large",
+			Code:  "INVALID_DECISION_BATCH",
+		})
+	case entities.ErrInvalidTimestamp:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid time range",
+			Code:  "INVALID_TIME_RANGE",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/pagination"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// WebhookHandler обработчик HTTP запросов управления подписками вебхуков
+type WebhookHandler struct {
+	webhookService services.WebhookService
+	logger         *zap.Logger
+}
+
+// NewWebhookHandler создает новый WebhookHandler
+func NewWebhookHandler(webhookService services.WebhookService, logger *zap.Logger) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// WebhookSubscriptionsResponse ответ со списком подписок
+type WebhookSubscriptionsResponse struct {
+	Subscriptions []*entities.WebhookSubscription `json:"subscriptions"`
+}
+
+// WebhookDeliveriesResponse страница журнала доставок подписки
+type WebhookDeliveriesResponse struct {
+	Deliveries []*entities.WebhookDelivery `json:"deliveries"`
+	pagination.Page
+}
+
+// RegisterRoutes регистрирует маршруты управления вебхуками
+func (h *WebhookHandler) RegisterRoutes(api *gin.RouterGroup) {
+	webhooks := api.Group("/admin/webhooks")
+	{
+		webhooks.POST("", h.CreateSubscription)
+		webhooks.GET("", h.ListSubscriptions)
+		webhooks.DELETE("/:id", h.DeleteSubscription)
+		webhooks.GET("/:id/deliveries", h.ListDeliveries)
+	}
+	api.POST("/admin/webhook-deliveries/:id/redeliver", h.Redeliver)
+}
+
+// CreateSubscription регистрирует подписку. Ключ подписи возвращается только в этом ответе
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req entities.WebhookSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request data",
+			Code:    "INVALID_REQUEST",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	created, err := h.webhookService.CreateSubscription(c.Request.Context(), &req)
+	if err != nil {
+		h.handleWebhookServiceError(c, err, "Failed to create webhook subscription")
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListSubscriptions возвращает все подписки
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.handleWebhookServiceError(c, err, "Failed to list webhook subscriptions")
+		return
+	}
+
+	c.JSON(http.StatusOK, &WebhookSubscriptionsResponse{Subscriptions: subscriptions})
+}
+
+// DeleteSubscription удаляет подписку и журнал ее доставок
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid webhook subscription ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), subscriptionID); err != nil {
+		h.handleWebhookServiceError(c, err, "Failed to delete webhook subscription")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries возвращает журнал доставок подписки, начиная с новых. Фильтр: status
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	subscriptionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid webhook subscription ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	page, ok := parsePage(c, pagination.DefaultOptions)
+	if !ok {
+		return
+	}
+
+	filters := &entities.WebhookDeliveryFilters{
+		SubscriptionID: subscriptionID,
+		Limit:          page.Limit + 1,
+		Offset:         page.Offset,
+	}
+	if statusStr := c.Query("status"); statusStr != "" {
+		filters.Status = entities.WebhookDeliveryStatus(statusStr)
+		if !filters.Status.IsValid() {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: "Invalid webhook delivery status",
+				Code:  "INVALID_PARAMETER",
+			})
+			return
+		}
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), filters)
+	if err != nil {
+		h.handleWebhookServiceError(c, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	hasMore := len(deliveries) > page.Limit
+	if hasMore {
+		deliveries = deliveries[:page.Limit]
+	}
+
+	c.JSON(http.StatusOK, &WebhookDeliveriesResponse{
+		Deliveries: deliveries,
+		Page:       pagination.Paginate(c, page, len(deliveries), nil, hasMore),
+	})
+}
+
+// Redeliver отправляет событие доставки повторно и возвращает новую доставку
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid webhook delivery ID format",
+			Code:  "INVALID_ID",
+		})
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), deliveryID)
+	if err != nil {
+		h.handleWebhookServiceError(c, err, "Failed to redeliver webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// handleWebhookServiceError обрабатывает ошибки сервиса вебхуков
+func (h *WebhookHandler) handleWebhookServiceError(c *gin.Context, err error, message string) {
+	h.logger.Error(message, zap.Error(err))
+
+	switch err {
+	case entities.ErrWebhookNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Webhook subscription not found",
+			Code:  "WEBHOOK_NOT_FOUND",
+		})
+	case entities.ErrWebhookDeliveryNotFound:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error: "Webhook delivery not found",
+			Code:  "WEBHOOK_DELIVERY_NOT_FOUND",
+		})
+	case entities.ErrInvalidWebhook:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Webhook subscription requires a name, an http(s) URL, event types and an optional secret of 16 to 256 characters",
+			Code:  "INVALID_WEBHOOK",
+		})
+	case entities.ErrUnknownWebhookEvent:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Event type is not in the event catalog",
+			Code:    "UNKNOWN_WEBHOOK_EVENT",
+			Details: "see GET /events/catalog for event types",
+		})
+	default:
+		respondInternalError(c, err)
+	}
+}
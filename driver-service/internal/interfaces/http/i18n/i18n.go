@@ -0,0 +1,96 @@
+// Package i18n переводит сообщения об ошибках API. Сообщение выбирается по машиночитаемому
+// коду ошибки; сами коды не переводятся и остаются стабильными для клиентов
+package i18n
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Language язык сообщений API (основной подтег BCP 47)
+type Language string
+
+// Поддерживаемые языки
+const (
+	English Language = "en"
+	Russian Language = "ru"
+)
+
+// SourceLanguage язык, на котором сообщения написаны в коде; для него перевод не нужен
+const SourceLanguage = English
+
+// catalogs переводы сообщений по языку и коду ошибки
+var catalogs = map[Language]map[string]string{
+	Russian: russianMessages,
+}
+
+// Supported поддерживаемые языки
+func Supported() []Language {
+	return []Language{English, Russian}
+}
+
+// ParseLanguage возвращает поддерживаемый язык по тегу вида "ru" или "ru-RU"
+func ParseLanguage(tag string) (Language, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	lang := Language(tag)
+	if lang == SourceLanguage {
+		return lang, true
+	}
+	_, ok := catalogs[lang]
+	return lang, ok
+}
+
+// Negotiate выбирает язык ответа по заголовку Accept-Language: поддерживаемый язык с
+// наибольшим весом q, при равных весах — первый в заголовке. "*" означает fallback,
+// языки с q=0 исключаются. Без подходящего языка возвращается fallback
+func Negotiate(acceptLanguage string, fallback Language) Language {
+	best, bestQ := fallback, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, q := parseLanguageRange(part)
+		if q <= bestQ {
+			continue
+		}
+		if tag == "*" {
+			best, bestQ = fallback, q
+			continue
+		}
+		if lang, ok := ParseLanguage(tag); ok {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+// parseLanguageRange разбирает элемент Accept-Language вида "ru-RU;q=0.8"; вес неверного
+// формата считается нулевым
+func parseLanguageRange(part string) (string, float64) {
+	tag, params, _ := strings.Cut(part, ";")
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return "", 0
+	}
+
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(name) != "q" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return tag, 0
+		}
+		q = parsed
+	}
+	return tag, q
+}
+
+// Message возвращает сообщение об ошибке с кодом code на языке lang; false, если перевода
+// нет и нужно оставить исходное сообщение
+func Message(lang Language, code string) (string, bool) {
+	message, ok := catalogs[lang][code]
+	return message, ok
+}
@@ -0,0 +1,135 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		fallback Language
+		want     Language
+	}{
+		{"empty header", "", English, English},
+		{"empty header with russian fallback", "", Russian, Russian},
+		{"exact match", "ru", English, Russian},
+		{"region subtag", "ru-RU", English, Russian},
+		{"case insensitive", "RU-ru", English, Russian},
+		{"highest weight wins", "en;q=0.5, ru;q=0.9", English, Russian},
+		{"first of equal weights", "en, ru", Russian, English},
+		{"unsupported languages skipped", "de-DE, fr;q=0.9, ru;q=0.1", English, Russian},
+		{"nothing supported", "de, fr", Russian, Russian},
+		{"wildcard means fallback", "*, ru;q=0.5", English, English},
+		{"excluded language", "ru;q=0, en;q=0.1", Russian, English},
+		{"invalid weight ignored", "ru;q=abc", English, English},
+		{"browser header", "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", English, Russian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.header, tt.fallback))
+		})
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	for _, lang := range Supported() {
+		parsed, ok := ParseLanguage(string(lang))
+		assert.True(t, ok, lang)
+		assert.Equal(t, lang, parsed)
+	}
+
+	_, ok := ParseLanguage("de")
+	assert.False(t, ok)
+}
+
+func TestMessage_SourceLanguageIsNotTranslated(t *testing.T) {
+	_, ok := Message(English, "DRIVER_NOT_FOUND")
+	assert.False(t, ok)
+
+	message, ok := Message(Russian, "DRIVER_NOT_FOUND")
+	require.True(t, ok)
+	assert.Equal(t, "Водитель не найден", message)
+}
+
+// Каждый код доменной ошибки переведен на все языки
+func TestCatalogs_CoverDomainErrors(t *testing.T) {
+	for lang, messages := range catalogs {
+		for _, domainErr := range entities.ErrorCatalog() {
+			_, ok := messages[domainErr.Code]
+			assert.True(t, ok, "%s: no translation for domain error %s", lang, domainErr.Code)
+		}
+	}
+}
+
+// Каждый код, который обработчики и middleware возвращают в ответе, переведен на все языки
+func TestCatalogs_CoverHTTPErrors(t *testing.T) {
+	codes := responseCodes(t, "../handlers", "../middleware")
+	require.NotEmpty(t, codes)
+
+	for lang, messages := range catalogs {
+		for _, code := range codes {
+			_, ok := messages[code]
+			assert.True(t, ok, "%s: no translation for HTTP error %s", lang, code)
+		}
+	}
+}
+
+// responseCodes собирает строковые коды ошибок из ErrorResponse{Code: ...} и
+// gin.H{"code": ...} в исходниках пакетов dirs, без тестов
+func responseCodes(t *testing.T, dirs ...string) []string {
+	t.Helper()
+
+	seen := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, dir := range dirs {
+		packages, err := parser.ParseDir(fset, dir, func(info fs.FileInfo) bool {
+			return !strings.HasSuffix(info.Name(), "_test.go")
+		}, 0)
+		require.NoError(t, err)
+		for _, pkg := range packages {
+			ast.Inspect(pkg, func(node ast.Node) bool {
+				kv, ok := node.(*ast.KeyValueExpr)
+				if !ok || !isCodeKey(kv.Key) {
+					return true
+				}
+				if value, ok := kv.Value.(*ast.BasicLit); ok && value.Kind == token.STRING {
+					code, err := strconv.Unquote(value.Value)
+					require.NoError(t, err)
+					seen[code] = true
+				}
+				return true
+			})
+		}
+	}
+
+	codes := make([]string, 0, len(seen))
+	for code := range seen {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+func isCodeKey(key ast.Expr) bool {
+	switch key := key.(type) {
+	case *ast.Ident:
+		return key.Name == "Code"
+	case *ast.BasicLit:
+		return key.Value == `"code"`
+	}
+	return false
+}
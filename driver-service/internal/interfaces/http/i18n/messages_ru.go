@@ -0,0 +1,278 @@
+package i18n
+
+// russianMessages сообщения об ошибках на русском по коду ошибки. Коды с несколькими
+// исходными сообщениями (INVALID_PARAMETER, INVALID_ID) получают общее сообщение
+var russianMessages = map[string]string{
+	// Общие ошибки запроса
+	"INTERNAL_ERROR":          "Внутренняя ошибка сервера",
+	"PANIC_RECOVERED":         "Внутренняя ошибка сервера",
+	"REQUEST_TIMEOUT":         "Превышено время обработки запроса",
+	"SERVICE_UNAVAILABLE":     "Сервис временно недоступен",
+	"UNAUTHORIZED":            "Требуется авторизация",
+	"FORBIDDEN":               "Доступ запрещен",
+	"PERMISSION_DENIED":       "Недостаточно прав",
+	"RATE_LIMIT_EXCEEDED":     "Превышен лимит запросов",
+	"INVALID_REQUEST":         "Неверные данные запроса",
+	"INVALID_PARAMETER":       "Неверный параметр запроса",
+	"INVALID_ID":              "Неверный формат идентификатора",
+	"INVALID_PAGINATION":      "Неверные параметры пагинации",
+	"INVALID_TIME_RANGE":      "Неверный интервал времени",
+	"INVALID_TIMESTAMP":       "Неверная метка времени",
+	"INVALID_DATA":            "Неверные данные",
+	"INVALID_OPERATION":       "Недопустимая операция",
+	"FILE_REQUIRED":           "Требуется файл",
+	"CONCURRENT_MODIFICATION": "Данные изменены параллельным запросом",
+
+	// API-ключи и сессии
+	"INVALID_API_KEY":         "Неверный, просроченный или отозванный API-ключ",
+	"INVALID_API_KEY_REQUEST": "API-ключу нужны название, области доступа (dispatcher или admin, дополнительно location:read:precise и pii:read), создатель, неотрицательный лимит запросов и срок действия в будущем",
+	"API_KEY_NOT_FOUND":       "API-ключ не найден",
+	"API_KEY_REVOKED":         "API-ключ уже отозван",
+	"SESSION_NOT_FOUND":       "Сессия не найдена",
+	"SESSION_REVOKED":         "Сессия отозвана",
+
+	// Водители
+	"INVALID_DRIVER_ID":            "Неверный формат идентификатора водителя",
+	"DRIVER_NOT_FOUND":             "Водитель не найден",
+	"DRIVER_EXISTS":                "Водитель уже существует",
+	"DRIVER_NOT_AVAILABLE":         "Водитель недоступен",
+	"DRIVER_BLOCKED":               "Водитель заблокирован",
+	"DRIVER_SUSPENDED":             "Водитель отстранен",
+	"DRIVER_PAYMENT_HOLD":          "Выплаты водителю приостановлены",
+	"INVALID_PHONE":                "Неверный номер телефона",
+	"INVALID_EMAIL":                "Неверный адрес электронной почты",
+	"INVALID_NAME":                 "Неверное имя",
+	"INVALID_BIRTH_DATE":           "Неверная дата рождения",
+	"INVALID_LICENSE":              "Неверный номер водительского удостоверения",
+	"INVALID_PASSPORT":             "Неверные паспортные данные",
+	"INVALID_VEHICLE_ID":           "Неверный идентификатор автомобиля",
+	"LICENSE_EXPIRED":              "Срок действия водительского удостоверения истек",
+	"LICENSE_REGISTRY_REJECTED":    "Водительское удостоверение отклонено реестром",
+	"LICENSE_REGISTRY_UNAVAILABLE": "Реестр водительских удостоверений недоступен",
+	"PROFILE_INCOMPLETE":           "Профиль водителя не заполнен для этого этапа",
+	"INVALID_PATCH":                "Неверные изменения профиля водителя",
+	"PROTECTED_FIELD":              "Поле нельзя изменить",
+	"UNKNOWN_FIELD":                "Неизвестное поле",
+	"ERASURE_BLOCKED":              "Персональные данные нельзя удалить, пока водитель на смене или на заказе",
+
+	// Статусы водителей
+	"INVALID_STATUS":               "Неверный статус водителя",
+	"INVALID_STATUS_TRANSITION":    "Недопустимый переход статуса",
+	"STATUS_UNCHANGED":             "Водитель уже в этом статусе",
+	"STATUS_CONFLICT":              "Статус водителя изменен параллельным запросом",
+	"INVALID_STATUS_VERSION":       "Неверный заголовок версии статуса",
+	"INVALID_STATUS_OVERRIDE":      "Для смены статуса нужны известный статус, причина и идентификатор исполнителя",
+	"INVALID_STATUS_HISTORY_RANGE": "Параметр 'from' должен быть раньше 'to'",
+	"INVALID_BULK_STATUS":          "Для массовой смены статуса нужны известный статус и список идентификаторов водителей",
+
+	// Блокировки
+	"BLOCK_NOT_FOUND":        "Блокировка водителя не найдена",
+	"BLOCK_REQUIRES_REASON":  "Водителя можно заблокировать только с кодом причины",
+	"INVALID_BLOCK":          "Для блокировки нужны известный код причины и время разблокировки в будущем",
+	"DRIVER_ALREADY_BLOCKED": "Водитель уже заблокирован",
+	"DRIVER_NOT_BLOCKED":     "У водителя нет действующей блокировки",
+
+	// Подтверждение телефона
+	"PHONE_ALREADY_VERIFIED":          "Телефон водителя уже подтвержден",
+	"PHONE_NOT_VERIFIED":              "Телефон водителя не подтвержден",
+	"INVALID_VERIFICATION_CODE":       "Неверный код подтверждения",
+	"VERIFICATION_CODE_EXPIRED":       "Срок действия кода подтверждения истек",
+	"VERIFICATION_CODE_NOT_FOUND":     "Нет отправленного кода подтверждения",
+	"VERIFICATION_CODE_RECENTLY_SENT": "Код подтверждения недавно отправлен",
+	"TOO_MANY_VERIFICATION_ATTEMPTS":  "Слишком много попыток подтверждения",
+
+	// Документы и верификация
+	"INVALID_DOCUMENT":           "Неверные данные документа",
+	"INVALID_DOCUMENT_TYPE":      "Неверный тип документа",
+	"INVALID_DOCUMENT_NUMBER":    "Неверный номер документа",
+	"INVALID_DOCUMENT_FILE":      "Документ должен быть файлом JPEG, PNG, HEIC или PDF допустимого размера",
+	"INVALID_EXPIRY_DATE":        "Неверный срок действия",
+	"INVALID_FILE_U_R_L":         "Неверная ссылка на файл",
+	"DOCUMENT_NOT_FOUND":         "Документ не найден",
+	"DOCUMENT_EXISTS":            "Документ уже существует",
+	"DOCUMENT_EXPIRED":           "Срок действия документа истек",
+	"DOCUMENT_NOT_VERIFIED":      "Документ не проверен",
+	"DOCUMENT_NOT_RENEWABLE":     "Документ не подлежит продлению",
+	"DOCUMENT_NOT_RESUBMITTABLE": "Загрузить заново можно только отклоненный документ",
+	"DOCUMENT_CLAIM_NOT_HELD":    "Документ не взят в работу этим верификатором",
+	"RENEWAL_ALREADY_PENDING":    "Продление документа уже ожидает проверки",
+	"INVALID_DECISION":           "Неверное решение верификатора",
+	"INVALID_DECISION_BATCH":     "Пакет решений пуст или слишком велик",
+	"INVALID_VERIFIER_ID":        "Неверный идентификатор верификатора",
+	"INVALID_CAMPAIGN":           "Срок кампании должен быть в будущем, а типы документов заданы",
+	"CAMPAIGN_NOT_FOUND":         "Кампания повторной проверки не найдена",
+	"CAMPAIGN_CLOSED":            "Кампания повторной проверки уже закрыта",
+	"CAMPAIGN_NO_TARGETS":        "Ни один проверенный документ не подходит под сегмент кампании",
+
+	// Осмотры автомобилей
+	"INVALID_INSPECTION_STATE": "Осмотр в другом состоянии",
+	"INSPECTION_NOT_FOUND":     "Осмотр не найден",
+	"INSPECTION_COMPLETED":     "Осмотр уже завершен",
+	"INSPECTION_OVERDUE":       "Осмотр автомобиля просрочен",
+	"INSPECTION_NOT_EDITABLE":  "Осмотр не принимает фотографии",
+	"INSPECTION_NOT_SUBMITTED": "Осмотр не отправлен на проверку",
+	"NOT_PHOTO_INSPECTION":     "Осмотр не принимает фотографии",
+	"INVALID_DUE_DATE":         "Неверный срок",
+	"INVALID_PHOTO":            "Фотография должна быть файлом JPEG, PNG или HEIC допустимого размера",
+	"INVALID_PHOTO_ANGLE":      "Ракурс должен быть одним из front, back, left, right, interior",
+	"PHOTOS_MISSING":           "Нужны фотографии всех ракурсов",
+	"REVIEW_INCOMPLETE":        "Проверка должна содержать решение по каждой фотографии",
+
+	// Местоположения
+	"INVALID_LOCATION":              "Неверные координаты",
+	"INVALID_LOCATION_METADATA":     "Неверные метаданные местоположения",
+	"INVALID_LOCATION_PRIVACY":      "Неверный режим приватности местоположения",
+	"INVALID_LOCATION_FILTERS":      "Неверные фильтры истории местоположений",
+	"INVALID_BBOX":                  "Неверная область на карте",
+	"INVALID_CELL":                  "Неверная точность ячейки тепловой карты",
+	"INVALID_SUMMARY_RANGE":         "Неверный интервал времени",
+	"INVALID_TRIP_RANGE":            "Неверный интервал времени",
+	"LOCATION_NOT_FOUND":            "Местоположение не найдено",
+	"LOCATION_TOO_OLD":              "Данные о местоположении устарели",
+	"LOCATION_INGESTION_OVERLOADED": "Прием местоположений перегружен, повторите позже",
+	"UNKNOWN_RETENTION_TIER":        "Неизвестный уровень хранения",
+	"INVALID_LOCATION_EXPORT":       "Неверный запрос выгрузки местоположений",
+	"LOCATION_EXPORT_NOT_FOUND":     "Выгрузка местоположений не найдена",
+	"LOCATION_EXPORT_NOT_READY":     "Выгрузка местоположений еще не готова",
+	"LOCATION_EXPORT_EXPIRED":       "Срок хранения файла выгрузки истек",
+	"LOCATION_EXPORT_QUEUE_FULL":    "Очередь выгрузок заполнена, повторите позже",
+	"INVALID_ANALYTICS_RANGE":       "Неверный интервал аналитики",
+	"LOCATION_ANALYTICS_DISABLED":   "Аналитика местоположений отключена",
+
+	// Смены, расписания и расходы
+	"SHIFT_NOT_FOUND":          "Смена не найдена",
+	"SHIFT_EXISTS":             "Активная смена уже существует",
+	"SHIFT_NOT_ACTIVE":         "Смена не активна",
+	"SHIFT_ALREADY_ENDED":      "Смена уже завершена",
+	"INVALID_SHIFT_BREAK":      "Неверное время перерыва",
+	"SHIFT_BREAK_ACTIVE":       "Перерыв в смене уже идет",
+	"SHIFT_BREAK_NOT_ACTIVE":   "В смене нет идущего перерыва",
+	"SHIFT_BREAK_OVERLAP":      "Перерыв пересекается с другим перерывом смены",
+	"INVALID_START_TIME":       "Неверное время начала",
+	"INVALID_END_TIME":         "Неверное время окончания",
+	"INVALID_SCHEDULE":         "Неверное расписание",
+	"SCHEDULE_NOT_FOUND":       "Расписание не найдено",
+	"INVALID_EXPENSE":          "Неверные данные расхода",
+	"INVALID_EXPENSE_AMOUNT":   "Неверная сумма расхода",
+	"INVALID_EXPENSE_CATEGORY": "Неверная категория расхода",
+	"INVALID_RECEIPT":          "Чек должен быть файлом JPEG, PNG, HEIC или PDF допустимого размера",
+	"EXPENSE_NOT_FOUND":        "Расход не найден",
+	"EXPENSE_LIMIT_EXCEEDED":   "Превышен лимит расходов",
+	"EXPENSE_WINDOW_CLOSED":    "Смена закрыта для расходов",
+
+	// Заработок и рейтинги
+	"INVALID_EARNING":        "Неверные данные заработка",
+	"INVALID_CURRENCY":       "Неверная валюта",
+	"EARNING_EXISTS":         "Заработок за этот заказ уже учтен",
+	"INVALID_RATING":         "Неверные данные оценки",
+	"INVALID_RATING_TYPE":    "Неверный тип оценки",
+	"INVALID_CRITERIA_SCORE": "Неверная оценка по критерию",
+	"RATING_NOT_FOUND":       "Оценка не найдена",
+	"RATING_EXISTS":          "Заказ уже оценен",
+	"RATING_RATE_LIMITED":    "Слишком много оценок от клиента",
+	"INVALID_STATS_RANGE":    "Неверный интервал статистики",
+
+	// Рейтинговые таблицы
+	"INVALID_METRIC":        "Неверная метрика рейтинга",
+	"INVALID_VISIBILITY":    "Неверная видимость рейтинга",
+	"DRIVER_NOT_RANKED":     "Водителя нет в этом рейтинге",
+	"LEADERBOARD_OPTED_OUT": "Водитель отказался от участия в рейтингах",
+
+	// Рефералы
+	"INVALID_REFERRAL":        "Приглашенный водитель должен отличаться от пригласившего",
+	"INVALID_REFERRAL_CODE":   "Реферальный код не принадлежит пригласившему",
+	"INVALID_REFERRAL_PERIOD": "Начало периода отчета должно быть раньше его конца",
+	"REFERRAL_CODE_NOT_FOUND": "Реферальный код не найден",
+	"REFERRAL_CODE_TAKEN":     "Реферальный код уже занят",
+	"REFERRAL_NOT_FOUND":      "Приглашение не найдено",
+	"REFERRAL_EXISTS":         "Водитель уже приглашен",
+	"REFERRAL_NOT_ELIGIBLE":   "Пригласить можно только водителя, не прошедшего верификацию",
+
+	// Приглашения водителей
+	"DRIVER_INVITE_NOT_FOUND": "Приглашение водителя не найдено",
+	"INVALID_DRIVER_INVITE":   "Приглашению нужны телефон, автопарк, автор и срок действия в допустимых пределах",
+	"INVALID_INVITE_TOKEN":    "Ссылка приглашения недействительна",
+	"DRIVER_INVITE_EXPIRED":   "Срок действия приглашения истек",
+	"DRIVER_INVITE_USED":      "Приглашение уже использовано",
+	"DRIVER_INVITE_REVOKED":   "Приглашение отозвано",
+	"DRIVER_INVITES_DISABLED": "Приглашения водителей отключены",
+
+	// Отслеживание заказов
+	"INVALID_ORDER_TRACKING":   "Для отслеживания нужны водитель и заказ",
+	"ORDER_TRACKING_NOT_FOUND": "Заказ водителя не отслеживается",
+	"ORDER_TRACKING_EXISTS":    "Заказ уже отслеживается",
+	"INVALID_TRACKING_TOKEN":   "Ссылка отслеживания недействительна",
+	"ORDER_TRACKING_INACTIVE":  "Отслеживание заказа завершено",
+	"ORDER_TRACKING_DISABLED":  "Ссылки отслеживания заказов отключены",
+
+	// Заказы
+	"INVALID_DISPATCH_OFFER":      "Неверное предложение заказа",
+	"DISPATCH_OFFER_NOT_ACCEPTED": "Водитель не принял этот заказ",
+
+	// Метки и заметки
+	"INVALID_DRIVER_TAG":   "Метка может содержать только буквы, цифры и дефисы",
+	"UNKNOWN_DRIVER_TAG":   "Метки нет в списке разрешенных",
+	"TOO_MANY_DRIVER_TAGS": "У водителя слишком много меток",
+	"INVALID_DRIVER_NOTE":  "Заметка должна быть непустой и не длиннее 4000 символов",
+
+	// Заявки на изменение профиля
+	"PROFILE_CHANGE_NOT_FOUND":         "Заявка на изменение профиля не найдена",
+	"INVALID_PROFILE_CHANGE":           "Неверная заявка на изменение профиля",
+	"PROFILE_CHANGE_PENDING":           "У водителя уже есть заявка на изменение профиля",
+	"PROFILE_CHANGE_RESOLVED":          "По заявке на изменение профиля уже принято решение",
+	"PROFILE_CHANGE_REQUIRES_APPROVAL": "Изменение ФИО, паспорта и номера удостоверения требует одобрения",
+
+	// Устройства и сообщения
+	"INVALID_DEVICE":          "Неверное устройство",
+	"DEVICE_NOT_FOUND":        "Устройство не найдено",
+	"INVALID_APP_VERSION":     "Неверная версия приложения",
+	"INVALID_MESSAGE":         "Неверное сообщение",
+	"INVALID_MESSAGE_RECEIPT": "Неверное подтверждение сообщения",
+	"MESSAGE_NOT_FOUND":       "Сообщение не найдено",
+
+	// Автопарки, города, регионы и шарды
+	"INVALID_FLEET":                "Неверный автопарк",
+	"FLEET_NOT_FOUND":              "Автопарк не найден",
+	"FLEET_OUT_OF_SCOPE":           "Автопарк недоступен вызывающей стороне",
+	"FLEET_VALIDATION_REJECTED":    "Изменение отклонено автопарком",
+	"FLEET_VALIDATION_UNAVAILABLE": "Проверка автопарком недоступна",
+	"INVALID_CITY":                 "Неверный город",
+	"CITY_NOT_FOUND":               "Город не найден",
+	"CITY_EXISTS":                  "Город с таким ключом уже существует",
+	"CITY_REBALANCING":             "Город переносится на другой шард, повторите позже",
+	"INVALID_REGION":               "Неверный регион",
+	"REGION_NOT_FOUND":             "Регион не найден",
+	"REGION_EXISTS":                "Регион с таким кодом уже существует",
+	"SHARD_NOT_FOUND":              "Шард не найден",
+	"SHARD_UNCHANGED":              "Город уже на этом шарде",
+	"CAPACITY_REPORT_NOT_FOUND":    "Отчетов о загрузке еще нет",
+
+	// Геозоны
+	"INVALID_GEOFENCE":   "Неверная геозона",
+	"GEOFENCE_NOT_FOUND": "Геозона не найдена",
+
+	// Флаги функций
+	"INVALID_FEATURE_FLAG":   "Неверный флаг функции",
+	"FEATURE_FLAG_NOT_FOUND": "Флаг функции не найден",
+	"FEATURE_FLAG_EXISTS":    "Флаг функции с таким ключом уже существует",
+
+	// Вебхуки
+	"INVALID_WEBHOOK":            "Для подписки нужны название, URL http(s), типы событий и необязательный секрет длиной от 16 до 256 символов",
+	"UNKNOWN_WEBHOOK_EVENT":      "Типа события нет в каталоге событий",
+	"WEBHOOK_NOT_FOUND":          "Подписка на вебхуки не найдена",
+	"WEBHOOK_DELIVERY_NOT_FOUND": "Доставка вебхука не найдена",
+
+	// Аудит и события безопасности
+	"INVALID_AUDIT_FILTERS":    "Неверные фильтры журнала аудита",
+	"INVALID_AUDIT_RANGE":      "Неверный диапазон цепочки аудита",
+	"AUDIT_ENTRY_NOT_FOUND":    "Запись аудита не найдена",
+	"AUDIT_ANCHOR_NOT_FOUND":   "Якорь цепочки аудита не найден",
+	"AUDIT_CHAIN_BROKEN":       "Цепочка хешей аудита нарушена",
+	"AUDIT_CHAIN_DISABLED":     "Цепочка хешей аудита отключена",
+	"SECURITY_EVENT_NOT_FOUND": "Событие безопасности не найдено",
+	"SECURITY_EVENT_REVIEWED":  "Событие безопасности уже рассмотрено",
+
+	// Фоновые задачи
+	"JOB_NOT_FOUND": "Задача не найдена",
+	"JOB_RUNNING":   "Задача уже выполняется",
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyHeader заголовок с ключом API внутреннего сервиса. Ключ также принимается
+// в заголовке Authorization со схемой ApiKey
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator проверка ключей API
+type APIKeyAuthenticator interface {
+	// AuthenticateAPIKey возвращает действующий ключ или entities.ErrInvalidAPIKey
+	AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error)
+}
+
+// AuthenticateAPIKeys проверяет ключ API и сохраняет в контексте запроса утверждения
+// вызывающего сервиса: субъект service:<имя ключа> и роли из областей действия ключа.
+// Запросы без ключа передаются дальше в Authenticate. Запросы сверх ограничения ключа
+// отклоняются с 429; ограничение считается на каждом экземпляре сервиса отдельно
+func AuthenticateAPIKeys(keys APIKeyAuthenticator, logger *zap.Logger) gin.HandlerFunc {
+	limiter := newAPIKeyLimiter()
+
+	return func(c *gin.Context) {
+		token := apiKey(c)
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		key, err := keys.AuthenticateAPIKey(c.Request.Context(), token)
+		if err != nil {
+			if !errors.Is(err, entities.ErrInvalidAPIKey) {
+				logger.Error("Failed to authenticate api key", zap.Error(err))
+				c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+					"error": "API key cannot be checked",
+					"code":  "SERVICE_UNAVAILABLE",
+				})
+				return
+			}
+			logger.Warn("Rejected api key",
+				zap.String("path", c.Request.URL.Path),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid, expired or revoked API key",
+				"code":  "UNAUTHORIZED",
+			})
+			return
+		}
+
+		if key.RateLimitPerMinute > 0 && !limiter.allow(c, key.ID, key.RateLimitPerMinute, "API key rate limit exceeded") {
+			return
+		}
+
+		// Области действия ключа, совпадающие с ролями, проверяются правилами доступа,
+		// остальные передаются как области доступа
+		roles := make([]Role, 0, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			if role := Role(scope); role.IsValid() {
+				roles = append(roles, role)
+			}
+		}
+		claims := &Claims{
+			Subject:   key.Subject(),
+			Roles:     roles,
+			Scopes:    append([]string(nil), key.Scopes...),
+			APIKeyID:  &key.ID,
+			ExpiresAt: timeOrZero(key.ExpiresAt),
+		}
+		c.Set(claimsKey, claims)
+		c.Set("user_id", claims.Subject)
+		c.Next()
+	}
+}
+
+// apiKey извлекает ключ API из заголовков запроса
+func apiKey(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader(APIKeyHeader)); key != "" {
+		return key
+	}
+	header := c.GetHeader("Authorization")
+	if scheme, key, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(key)
+	}
+	return ""
+}
+
+// timeOrZero значение времени или нулевое время
+func timeOrZero(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// newAPIKeyLimiter создает ограничение частоты запросов по ключам
+func newAPIKeyLimiter() *windowLimiter[uuid.UUID] {
+	return newWindowLimiter[uuid.UUID]()
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubAPIKeys принимает только ключи из keys
+type stubAPIKeys struct {
+	keys map[string]*entities.APIKey
+}
+
+func (s *stubAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*entities.APIKey, error) {
+	if apiKey, ok := s.keys[key]; ok {
+		return apiKey, nil
+	}
+	return nil, entities.ErrInvalidAPIKey
+}
+
+func TestAuthenticateAPIKeys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := newHMACVerifier(t)
+	keys := &stubAPIKeys{keys: map[string]*entities.APIKey{
+		"dsk_orders":  {ID: uuid.New(), Name: "order-service", Scopes: entities.APIKeyScopeList{entities.APIKeyScopeDispatcher}, RateLimitPerMinute: 2},
+		"dsk_billing": {ID: uuid.New(), Name: "billing", Scopes: entities.APIKeyScopeList{entities.APIKeyScopeAdmin}},
+	}}
+
+	router := gin.New()
+	router.Use(
+		AuthenticateAPIKeys(keys, zap.NewNop()),
+		Authenticate(verifier, zap.NewNop()),
+		Authorize(PolicySet{
+			Default: Policy{Roles: []Role{RoleDispatcher, RoleAdmin}},
+			Routes:  map[string]Policy{"POST /admin": {Roles: []Role{RoleAdmin}}},
+		}, zap.NewNop()),
+		TrackActor(),
+	)
+	actor := func(c *gin.Context) {
+		actor, _ := entities.AuditActorFromContext(c.Request.Context())
+		c.String(http.StatusOK, actor.Subject)
+	}
+	router.GET("/drivers", actor)
+	router.POST("/admin", actor)
+
+	request := func(method, path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := request(http.MethodGet, "/drivers", map[string]string{APIKeyHeader: "dsk_orders"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "service:order-service", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-RateLimit-Remaining"))
+
+	// Область действия ключа проверяется правилами доступа к маршрутам
+	w = request(http.MethodPost, "/admin", map[string]string{APIKeyHeader: "dsk_orders"})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = request(http.MethodGet, "/drivers", map[string]string{APIKeyHeader: "dsk_orders"})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+
+	w = request(http.MethodPost, "/admin", map[string]string{"Authorization": "ApiKey dsk_billing"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "service:billing", w.Body.String())
+	assert.Empty(t, w.Header().Get("X-RateLimit-Limit"), "key without limit")
+
+	w = request(http.MethodGet, "/drivers", map[string]string{APIKeyHeader: "dsk_unknown"})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	// Без ключа запрос аутентифицируется токеном
+	w = request(http.MethodGet, "/drivers", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAPIKeyLimiter(t *testing.T) {
+	limiter := newAPIKeyLimiter()
+	keyID := uuid.New()
+	now := time.Date(2024, 3, 11, 12, 0, 50, 0, time.UTC)
+
+	remaining, retryAfter := limiter.take(keyID, 2, now)
+	assert.Equal(t, 1, remaining)
+	assert.Zero(t, retryAfter)
+	limiter.take(keyID, 2, now)
+
+	_, retryAfter = limiter.take(keyID, 2, now)
+	assert.Equal(t, 10*time.Second, retryAfter)
+
+	// Другой ключ считается отдельно, новая минута начинает счет заново
+	_, retryAfter = limiter.take(uuid.New(), 2, now)
+	assert.Zero(t, retryAfter)
+	remaining, retryAfter = limiter.take(keyID, 2, now.Add(10*time.Second))
+	assert.Equal(t, 1, remaining)
+	assert.Zero(t, retryAfter)
+	assert.Len(t, limiter.windows, 1, "windows of past minutes are pruned")
+}
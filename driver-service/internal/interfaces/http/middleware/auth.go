@@ -0,0 +1,253 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Role роль пользователя API
+type Role string
+
+const (
+	RoleDriver     Role = "driver"
+	RoleDispatcher Role = "dispatcher"
+	RoleAdmin      Role = "admin"
+	// RolePartner кабинет автопарка: видит только водителей автопарков из токена
+	RolePartner Role = "partner"
+)
+
+// IsValid проверяет, что роль известна сервису
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleDriver, RoleDispatcher, RoleAdmin, RolePartner:
+		return true
+	}
+	return false
+}
+
+// claimsKey ключ утверждений токена в контексте запроса
+const claimsKey = "auth_claims"
+
+// Claims утверждения проверенного токена
+type Claims struct {
+	Subject string
+	Roles   []Role
+	// DriverID ID водителя, заполняется для токенов с ролью driver
+	DriverID *uuid.UUID
+	// FleetIDs автопарки, которыми ограничен доступ; обязательны для роли partner
+	FleetIDs []uuid.UUID
+	// Scopes дополнительные области доступа, например entities.ScopeLocationReadPrecise
+	Scopes []string
+	// SessionID идентификатор сессии (sid, при его отсутствии jti); DeviceID — устройства (device_id)
+	SessionID string
+	DeviceID  string
+	// APIKeyID ключ API, которым аутентифицирован внутренний сервис; пустой для токенов
+	APIKeyID  *uuid.UUID
+	ExpiresAt time.Time
+}
+
+// HasRole проверяет наличие хотя бы одной из ролей
+func (c *Claims) HasRole(roles ...Role) bool {
+	for _, have := range c.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasScope проверяет наличие области доступа
+func (c *Claims) HasScope(scope string) bool {
+	for _, have := range c.Scopes {
+		if have == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDriver проверяет, что токен принадлежит водителю с указанным ID
+func (c *Claims) IsDriver(driverID string) bool {
+	return c.HasRole(RoleDriver) && c.DriverID != nil && c.DriverID.String() == driverID
+}
+
+// FleetScoped сообщает, ограничен ли доступ автопарками: токены партнеров ограничены
+// всегда, остальные — если в них переданы автопарки
+func (c *Claims) FleetScoped() bool {
+	return c.HasRole(RolePartner) || len(c.FleetIDs) > 0
+}
+
+// ClaimsFromContext возвращает утверждения токена текущего запроса
+func ClaimsFromContext(c *gin.Context) (*Claims, bool) {
+	value, ok := c.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*Claims)
+	return claims, ok
+}
+
+// Policy правило доступа к маршруту
+type Policy struct {
+	// Roles роли, которым маршрут доступен полностью
+	Roles []Role
+	// SelfParam параметр пути с ID водителя: водитель получает доступ только к своим данным
+	SelfParam string
+}
+
+// allows проверяет доступ по правилу
+func (p Policy) allows(c *gin.Context, claims *Claims) bool {
+	if claims.HasRole(p.Roles...) {
+		return true
+	}
+	return p.SelfParam != "" && claims.IsDriver(c.Param(p.SelfParam))
+}
+
+// PolicySet правила доступа к маршрутам по ключу "METHOD /полный/путь/:param".
+// Маршруты без правила доступны по правилу Default
+type PolicySet struct {
+	Default Policy
+	Routes  map[string]Policy
+}
+
+// Authenticate проверяет bearer токен и сохраняет его утверждения в контексте запроса.
+// Для WebSocket токен также принимается в параметре access_token, так как браузеры
+// не передают заголовки при открытии соединения. Запрос, уже аутентифицированный
+// ключом API (AuthenticateAPIKeys), передается дальше без токена
+func Authenticate(verifier TokenVerifier, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := ClaimsFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		token := bearerToken(c)
+		if token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Authorization header required",
+				"code":  "UNAUTHORIZED",
+			})
+			return
+		}
+
+		claims, err := verifier.Verify(c.Request.Context(), token)
+		if err != nil {
+			logger.Warn("Rejected access token",
+				zap.Error(err),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Invalid or expired token",
+				"code":  "UNAUTHORIZED",
+			})
+			return
+		}
+
+		c.Set(claimsKey, claims)
+		c.Set("user_id", claims.Subject)
+		c.Next()
+	}
+}
+
+// Authorize применяет правило доступа маршрута. Должен выполняться после Authenticate
+func Authorize(policies PolicySet, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Несуществующий маршрут: ответ 404 дает роутер
+		route := c.FullPath()
+		if route == "" {
+			c.Next()
+			return
+		}
+
+		claims, ok := ClaimsFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Authorization header required",
+				"code":  "UNAUTHORIZED",
+			})
+			return
+		}
+
+		policy, ok := policies.Routes[c.Request.Method+" "+route]
+		if !ok {
+			policy = policies.Default
+		}
+
+		if !policy.allows(c, claims) {
+			logger.Warn("Access denied",
+				zap.String("subject", claims.Subject),
+				zap.String("method", c.Request.Method),
+				zap.String("route", route),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Access denied",
+				"code":  "FORBIDDEN",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ScopeFleets ограничивает запрос автопарками из токена (entities.WithFleetScope), чтобы
+// сервисы отдавали партнеру только его водителей. Должен выполняться после Authenticate
+func ScopeFleets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, ok := ClaimsFromContext(c); ok && claims.FleetScoped() {
+			c.Request = c.Request.WithContext(entities.WithFleetScope(c.Request.Context(), claims.FleetIDs))
+		}
+		c.Next()
+	}
+}
+
+// TrackActor передает сервисам автора запроса для журнала аудита (entities.WithAuditActor):
+// субъект и роли токена, ID запроса и устройство водителя (утверждение device_id или
+// заголовок X-Device-ID). Без аутентификации передается только ID запроса
+func TrackActor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := entities.AuditActor{RequestID: c.GetString("request_id")}
+		if claims, ok := ClaimsFromContext(c); ok {
+			roles := make([]string, 0, len(claims.Roles))
+			for _, role := range claims.Roles {
+				roles = append(roles, string(role))
+			}
+			actor.Subject = claims.Subject
+			actor.Roles = strings.Join(roles, ",")
+			if claims.DriverID != nil {
+				actor.DeviceID = claims.DeviceID
+				if actor.DeviceID == "" {
+					actor.DeviceID = c.GetHeader(DeviceIDHeader)
+				}
+			}
+		}
+		c.Request = c.Request.WithContext(entities.WithAuditActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
+
+// bearerToken извлекает токен из заголовка Authorization
+func bearerToken(c *gin.Context) string {
+	header := c.GetHeader("Authorization")
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		return c.Query("access_token")
+	}
+	return ""
+}
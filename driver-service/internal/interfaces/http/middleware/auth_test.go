@@ -0,0 +1,270 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testSecret = "test-secret"
+
+func encodeSegment(t *testing.T, value interface{}) string {
+	data, err := json.Marshal(value)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signHS256(t *testing.T, header, claims map[string]interface{}) string {
+	input := encodeSegment(t, header) + "." + encodeSegment(t, claims)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	input := encodeSegment(t, map[string]interface{}{"alg": "RS256", "kid": kid}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func driverClaims(driverID uuid.UUID) map[string]interface{} {
+	return map[string]interface{}{
+		"sub":       "user-1",
+		"iss":       "crm-auth",
+		"aud":       []string{"driver-service"},
+		"exp":       time.Now().Add(time.Hour).Unix(),
+		"roles":     []string{"driver"},
+		"driver_id": driverID.String(),
+	}
+}
+
+func newHMACVerifier(t *testing.T) *JWTVerifier {
+	verifier, err := NewJWTVerifier(JWTConfig{
+		Issuer:     "crm-auth",
+		Audience:   "driver-service",
+		HMACSecret: testSecret,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	return verifier
+}
+
+func TestJWTVerifier_HS256(t *testing.T) {
+	ctx := context.Background()
+	verifier := newHMACVerifier(t)
+	header := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+	driverID := uuid.New()
+
+	claims, err := verifier.Verify(ctx, signHS256(t, header, driverClaims(driverID)))
+	require.NoError(t, err)
+	assert.Equal(t, []Role{RoleDriver}, claims.Roles)
+	require.NotNil(t, claims.DriverID)
+	assert.Equal(t, driverID, *claims.DriverID)
+
+	expired := driverClaims(driverID)
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	_, err = verifier.Verify(ctx, signHS256(t, header, expired))
+	assert.Equal(t, ErrTokenExpired, err)
+
+	foreign := driverClaims(driverID)
+	foreign["aud"] = "billing"
+	_, err = verifier.Verify(ctx, signHS256(t, header, foreign))
+	assert.Equal(t, ErrInvalidAudience, err)
+
+	noDriverID := driverClaims(driverID)
+	delete(noDriverID, "driver_id")
+	_, err = verifier.Verify(ctx, signHS256(t, header, noDriverID))
+	assert.Equal(t, ErrMissingDriverID, err, "sub is not a driver UUID")
+
+	tampered := signHS256(t, header, driverClaims(driverID))
+	tampered = tampered[:len(tampered)-2] + "AA"
+	_, err = verifier.Verify(ctx, tampered)
+	assert.Equal(t, ErrInvalidSignature, err)
+
+	unsigned := encodeSegment(t, map[string]interface{}{"alg": "none"}) + "." + encodeSegment(t, driverClaims(driverID)) + "."
+	_, err = verifier.Verify(ctx, unsigned)
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+
+	_, err = verifier.Verify(ctx, "not-a-token")
+	assert.Equal(t, ErrMalformedToken, err)
+}
+
+func TestJWTVerifier_Scopes(t *testing.T) {
+	verifier := newHMACVerifier(t)
+	header := map[string]interface{}{"alg": "HS256", "typ": "JWT"}
+
+	payload := driverClaims(uuid.New())
+	payload["scope"] = "openid location:read:precise"
+	claims, err := verifier.Verify(context.Background(), signHS256(t, header, payload))
+	require.NoError(t, err)
+	assert.True(t, claims.HasScope(entities.ScopeLocationReadPrecise))
+
+	claims, err = verifier.Verify(context.Background(), signHS256(t, header, driverClaims(uuid.New())))
+	require.NoError(t, err)
+	assert.False(t, claims.HasScope(entities.ScopeLocationReadPrecise))
+}
+
+func TestJWTVerifier_JWKS(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fetches := 0
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	defer jwks.Close()
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSURL: jwks.URL, JWKSRefreshInterval: time.Hour}, zap.NewNop())
+	require.NoError(t, err)
+
+	claims := map[string]interface{}{
+		"sub":   "dispatcher-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": "dispatcher admin",
+	}
+
+	verified, err := verifier.Verify(ctx, signRS256(t, key, "key-1", claims))
+	require.NoError(t, err)
+	assert.True(t, verified.HasRole(RoleAdmin))
+	assert.Nil(t, verified.DriverID)
+
+	_, err = verifier.Verify(ctx, signRS256(t, key, "key-2", claims))
+	assert.Equal(t, ErrUnknownSigningKey, err)
+	assert.Equal(t, 1, fetches, "unknown kid does not trigger an immediate refetch")
+
+	// Токен HS256 не принимается, если секрет не настроен
+	_, err = verifier.Verify(ctx, signHS256(t, map[string]interface{}{"alg": "HS256"}, claims))
+	assert.Equal(t, ErrUnsupportedAlgorithm, err)
+}
+
+func TestAuthorize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := newHMACVerifier(t)
+	header := map[string]interface{}{"alg": "HS256"}
+
+	router := gin.New()
+	api := router.Group("/api/v1")
+	api.Use(Authenticate(verifier, zap.NewNop()), Authorize(PolicySet{
+		Default: Policy{Roles: []Role{RoleDispatcher, RoleAdmin}},
+		Routes: map[string]Policy{
+			"POST /api/v1/drivers/:id/locations":  {SelfParam: "id"},
+			"POST /api/v1/admin/documents/decide": {Roles: []Role{RoleAdmin}},
+		},
+	}, zap.NewNop()))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	api.POST("/drivers/:id/locations", ok)
+	api.POST("/admin/documents/decide", ok)
+	api.GET("/drivers", ok)
+
+	driverID := uuid.New()
+	driverToken := signHS256(t, header, driverClaims(driverID))
+	dispatcher := driverClaims(driverID)
+	dispatcher["roles"] = []string{"dispatcher"}
+	dispatcherToken := signHS256(t, header, dispatcher)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{"no token", http.MethodGet, "/api/v1/drivers", "", http.StatusUnauthorized},
+		{"invalid token", http.MethodGet, "/api/v1/drivers", "garbage", http.StatusUnauthorized},
+		{"driver own location", http.MethodPost, "/api/v1/drivers/" + driverID.String() + "/locations", driverToken, http.StatusOK},
+		{"driver foreign location", http.MethodPost, "/api/v1/drivers/" + uuid.New().String() + "/locations", driverToken, http.StatusForbidden},
+		{"dispatcher location", http.MethodPost, "/api/v1/drivers/" + driverID.String() + "/locations", dispatcherToken, http.StatusForbidden},
+		{"driver default policy", http.MethodGet, "/api/v1/drivers", driverToken, http.StatusForbidden},
+		{"dispatcher default policy", http.MethodGet, "/api/v1/drivers", dispatcherToken, http.StatusOK},
+		{"dispatcher admin route", http.MethodPost, "/api/v1/admin/documents/decide", dispatcherToken, http.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tc.want, w.Code)
+		})
+	}
+}
+
+func TestScopeFleets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := newHMACVerifier(t)
+	header := map[string]interface{}{"alg": "HS256"}
+	fleetID := uuid.New()
+
+	partner := driverClaims(uuid.New())
+	partner["roles"] = []string{"partner"}
+	partner["fleet_ids"] = []string{fleetID.String()}
+
+	claims, err := verifier.Verify(context.Background(), signHS256(t, header, partner))
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{fleetID}, claims.FleetIDs)
+	assert.Nil(t, claims.DriverID)
+
+	noFleets := driverClaims(uuid.New())
+	noFleets["roles"] = []string{"partner"}
+	_, err = verifier.Verify(context.Background(), signHS256(t, header, noFleets))
+	assert.Equal(t, ErrMissingFleetID, err)
+
+	invalid := driverClaims(uuid.New())
+	invalid["roles"] = []string{"dispatcher"}
+	invalid["fleet_ids"] = "not-a-fleet"
+	_, err = verifier.Verify(context.Background(), signHS256(t, header, invalid))
+	assert.Equal(t, ErrInvalidFleetID, err)
+
+	router := gin.New()
+	router.Use(Authenticate(verifier, zap.NewNop()), ScopeFleets())
+	router.GET("/drivers", func(c *gin.Context) {
+		fleetIDs, scoped := entities.FleetScopeFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"scoped": scoped, "fleets": len(fleetIDs)})
+	})
+
+	dispatcher := driverClaims(uuid.New())
+	dispatcher["roles"] = []string{"dispatcher"}
+
+	for token, want := range map[string]string{
+		signHS256(t, header, partner):    `{"fleets":1,"scoped":true}`,
+		signHS256(t, header, dispatcher): `{"fleets":0,"scoped":false}`,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/drivers", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.JSONEq(t, want, w.Body.String())
+	}
+}
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Кодировки сжатия ответов
+const (
+	EncodingZstd = "zstd"
+	EncodingGzip = "gzip"
+)
+
+// gzipWriters и zstdWriters переиспользуют кодировщики: создание кодировщика zstd
+// дороже сжатия типичного ответа API
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	}}
+	zstdWriters = sync.Pool{New: func() interface{} {
+		encoder, _ := zstd.NewWriter(io.Discard, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(1<<20))
+		return encoder
+	}}
+)
+
+// Compress middleware сжимает ответы кодировкой из Accept-Encoding: zstd, если клиент его
+// принимает, иначе gzip. Сжимаются только ответы с данными (JSON, protobuf, CSV, текст) не короче
+// minSize байт: короткие ответы сжатие не уменьшает. Ответы, уже имеющие Content-Encoding,
+// частичные ответы и WebSocket подключения не сжимаются
+func Compress(minSize int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+		encoding := NegotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			writer.close()
+		}()
+		c.Next()
+	}
+}
+
+// NegotiateEncoding выбирает кодировку сжатия по заголовку Accept-Encoding: поддерживаемую
+// с наибольшим весом q, при равных весах zstd. Пустая строка — отвечать без сжатия
+func NegotiateEncoding(header string) string {
+	weights := map[string]float64{}
+	wildcard := -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		switch name {
+		case EncodingZstd, EncodingGzip:
+			weights[name] = q
+		case "*":
+			wildcard = q
+		}
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range []string{EncodingZstd, EncodingGzip} {
+		q, ok := weights[encoding]
+		if !ok {
+			q = wildcard
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// compressWriter копит начало тела ответа, пока не наберется minSize байт, затем пишет тело
+// через кодировщик. Решение принимается один раз: ответ короче minSize уходит без сжатия
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	minSize  int
+
+	pending    bytes.Buffer
+	decided    bool
+	compressed bool
+	encoder    io.WriteCloser
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.compressed {
+			return w.encoder.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	if !w.compressible() {
+		w.decided = true
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.pending.Write(data)
+	if w.pending.Len() >= w.minSize {
+		if err := w.start(); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush отправляет накопленное: потоковые ответы (выгрузки) доходят до клиента по частям
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.finish()
+	}
+	if w.compressed {
+		if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+			_ = flusher.Flush()
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// compressible проверяет по статусу и заголовкам, подходит ли ответ для сжатия
+func (w *compressWriter) compressible() bool {
+	// Заголовки уже отправлены: Content-Encoding добавить нельзя
+	if w.Written() {
+		return false
+	}
+	switch status := w.Status(); {
+	case status < http.StatusOK, status == http.StatusNoContent,
+		status == http.StatusPartialContent, status == http.StatusNotModified:
+		return false
+	}
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	return strings.Contains(contentType, "json") || strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "xml") || strings.Contains(contentType, "csv") ||
+		strings.Contains(contentType, "protobuf")
+}
+
+// start включает сжатие и пишет через кодировщик накопленное начало тела
+func (w *compressWriter) start() error {
+	w.decided = true
+	w.compressed = true
+
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		// Сжатое представление отличается побайтно: сильный ETag становится слабым
+		header.Set("ETag", "W/"+etag)
+	}
+
+	switch w.encoding {
+	case EncodingZstd:
+		encoder := zstdWriters.Get().(*zstd.Encoder)
+		encoder.Reset(w.ResponseWriter)
+		w.encoder = encoder
+	default:
+		encoder := gzipWriters.Get().(*gzip.Writer)
+		encoder.Reset(w.ResponseWriter)
+		w.encoder = encoder
+	}
+
+	_, err := w.encoder.Write(w.pending.Bytes())
+	w.pending.Reset()
+	return err
+}
+
+// finish решает судьбу ответа, не набравшего minSize байт: он пишется без сжатия
+func (w *compressWriter) finish() {
+	w.decided = true
+	if w.pending.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.pending.Bytes())
+		w.pending.Reset()
+	}
+}
+
+// close завершает сжатый поток и возвращает кодировщик в пул
+func (w *compressWriter) close() {
+	if !w.decided {
+		w.finish()
+		return
+	}
+	if !w.compressed {
+		return
+	}
+
+	_ = w.encoder.Close()
+	switch encoder := w.encoder.(type) {
+	case *zstd.Encoder:
+		encoder.Reset(io.Discard)
+		zstdWriters.Put(encoder)
+	case *gzip.Writer:
+		encoder.Reset(io.Discard)
+		gzipWriters.Put(encoder)
+	}
+	w.encoder = nil
+}
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Compress(64))
+	router.GET("/history", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"points": strings.Repeat("55.7558,37.6173;", 100)})
+	})
+	router.GET("/short", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", bytes.Repeat([]byte{1}, 512))
+	})
+	return router
+}
+
+func getCompressed(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestCompress(t *testing.T) {
+	router := newCompressedRouter()
+	plain := getCompressed(router, "/history", "")
+	assert.Empty(t, plain.Header().Get("Content-Encoding"))
+	assert.Contains(t, plain.Header().Values("Vary"), "Accept-Encoding")
+
+	zstdResponse := getCompressed(router, "/history", "gzip, deflate, br, zstd")
+	require.Equal(t, EncodingZstd, zstdResponse.Header().Get("Content-Encoding"))
+	assert.Less(t, zstdResponse.Body.Len(), plain.Body.Len())
+	decoder, err := zstd.NewReader(zstdResponse.Body)
+	require.NoError(t, err)
+	defer decoder.Close()
+	body, err := io.ReadAll(decoder)
+	require.NoError(t, err)
+	assert.Equal(t, plain.Body.String(), string(body))
+
+	gzipResponse := getCompressed(router, "/history", "gzip")
+	require.Equal(t, EncodingGzip, gzipResponse.Header().Get("Content-Encoding"))
+	reader, err := gzip.NewReader(gzipResponse.Body)
+	require.NoError(t, err)
+	body, err = io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, plain.Body.String(), string(body))
+
+	// Короткие и уже сжатые форматы отправляются как есть
+	short := getCompressed(router, "/short", "gzip")
+	assert.Empty(t, short.Header().Get("Content-Encoding"))
+	assert.JSONEq(t, `{"ok":true}`, short.Body.String())
+	image := getCompressed(router, "/image", "gzip")
+	assert.Empty(t, image.Header().Get("Content-Encoding"))
+	assert.Equal(t, 512, image.Body.Len())
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	cases := map[string]string{
+		"":                       "",
+		"identity":               "",
+		"gzip":                   EncodingGzip,
+		"gzip, zstd":             EncodingZstd,
+		"zstd;q=0.5, gzip;q=0.8": EncodingGzip,
+		"zstd;q=0, gzip":         EncodingGzip,
+		"*":                      EncodingZstd,
+		"*;q=0.2, zstd;q=0":      EncodingGzip,
+		"GZIP;q=1.0":             EncodingGzip,
+	}
+	for header, expected := range cases {
+		assert.Equal(t, expected, NegotiateEncoding(header), header)
+	}
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Conditional middleware поддерживает условные GET запросы: успешный ответ получает слабый
+// ETag по хешу тела, и если он совпадает с If-None-Match, клиент получает 304 без тела.
+// Без If-None-Match сравнивается If-Modified-Since с заголовком Last-Modified, который
+// выставляет обработчик. Тело ответа задерживается до конца обработки, поэтому middleware
+// ставится только на маршруты с ответом целиком в памяти (списки, история местоположений)
+func Conditional() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &conditionalWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			writer.flush(c.Request)
+		}()
+		c.Next()
+	}
+}
+
+// conditionalWriter задерживает тело успешного ответа до вычисления ETag; остальные
+// ответы пишутся сразу
+type conditionalWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (w *conditionalWriter) Write(data []byte) (int, error) {
+	if w.buffer() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *conditionalWriter) WriteString(s string) (int, error) {
+	if w.buffer() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// buffer решает при первой записи, задерживать ли тело: только ответ 200, заголовки
+// которого еще не отправлены
+func (w *conditionalWriter) buffer() bool {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.Status() == http.StatusOK && !w.Written()
+	}
+	return w.buffering
+}
+
+// flush выставляет ETag и пишет задержанное тело или 304, если представление у клиента
+// не изменилось
+func (w *conditionalWriter) flush(req *http.Request) {
+	if !w.buffering {
+		return
+	}
+
+	body := w.body.Bytes()
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+	header := w.Header()
+	header.Set("ETag", etag)
+
+	if notModified(req, etag, header.Get("Last-Modified")) {
+		header.Del("Content-Type")
+		header.Del("Content-Length")
+		w.ResponseWriter.WriteHeader(http.StatusNotModified)
+		w.ResponseWriter.WriteHeaderNow()
+		return
+	}
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// notModified проверяет условия запроса: If-None-Match сравнивается с etag слабым сравнением
+// и имеет приоритет над If-Modified-Since
+func notModified(req *http.Request, etag, lastModified string) bool {
+	if match := req.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since := req.Header.Get("If-Modified-Since")
+	if since == "" || lastModified == "" {
+		return false
+	}
+	sinceAt, err := http.ParseTime(since)
+	if err != nil {
+		return false
+	}
+	modifiedAt, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !modifiedAt.After(sinceAt)
+}
+
+// SetLastModified выставляет заголовок Last-Modified по времени последнего изменения данных
+// ответа. Нулевое время не выставляется
+func SetLastModified(c *gin.Context, modifiedAt time.Time) {
+	if modifiedAt.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", modifiedAt.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	updatedAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	router := gin.New()
+	router.GET("/drivers", Conditional(), func(c *gin.Context) {
+		SetLastModified(c, updatedAt)
+		c.JSON(http.StatusOK, gin.H{"drivers": []string{"a", "b"}})
+	})
+	router.GET("/missing", Conditional(), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found"})
+	})
+
+	get := func(path string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := get("/drivers", nil)
+	require.Equal(t, http.StatusOK, first.Code)
+	etag := first.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	assert.Equal(t, "Fri, 16 Oct 2026 12:00:00 GMT", first.Header().Get("Last-Modified"))
+	assert.JSONEq(t, `{"drivers":["a","b"]}`, first.Body.String())
+
+	cached := get("/drivers", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+	assert.Empty(t, cached.Body.String())
+	assert.Equal(t, etag, cached.Header().Get("ETag"))
+
+	changed := get("/drivers", map[string]string{"If-None-Match": `W/"stale"`})
+	assert.Equal(t, http.StatusOK, changed.Code)
+
+	// If-None-Match имеет приоритет над If-Modified-Since
+	since := updatedAt.Add(time.Hour).Format(http.TimeFormat)
+	assert.Equal(t, http.StatusNotModified, get("/drivers", map[string]string{"If-Modified-Since": since}).Code)
+	assert.Equal(t, http.StatusOK, get("/drivers", map[string]string{"If-Modified-Since": since, "If-None-Match": `W/"stale"`}).Code)
+	older := updatedAt.Add(-time.Hour).Format(http.TimeFormat)
+	assert.Equal(t, http.StatusOK, get("/drivers", map[string]string{"If-Modified-Since": older}).Code)
+
+	missing := get("/missing", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusNotFound, missing.Code)
+	assert.Empty(t, missing.Header().Get("ETag"))
+}
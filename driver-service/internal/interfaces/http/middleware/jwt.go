@@ -0,0 +1,486 @@
+package middleware
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Ошибки проверки токена
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidSignature     = errors.New("invalid token signature")
+	ErrUnknownSigningKey    = errors.New("unknown signing key")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrTokenNotYetValid     = errors.New("token not yet valid")
+	ErrInvalidIssuer        = errors.New("invalid token issuer")
+	ErrInvalidAudience      = errors.New("invalid token audience")
+	ErrMissingDriverID      = errors.New("driver token has no driver ID")
+	ErrMissingFleetID       = errors.New("partner token has no fleet IDs")
+	ErrInvalidFleetID       = errors.New("token has an invalid fleet ID")
+)
+
+// jwksMinRefetch минимальный интервал между загрузками JWKS при неизвестном kid,
+// чтобы токены с произвольным kid не превращались в поток запросов к провайдеру
+const jwksMinRefetch = 30 * time.Second
+
+// TokenVerifier проверяет токен доступа и возвращает его утверждения
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+// JWTConfig параметры проверки JWT. Должен быть задан хотя бы один источник ключей:
+// HMACSecret для HS256, PublicKeyPEM или JWKSURL для RS256
+type JWTConfig struct {
+	Issuer   string
+	Audience string
+	// HMACSecret общий секрет для токенов HS256
+	HMACSecret string
+	// PublicKeyPEM открытый ключ RSA для токенов RS256 без kid
+	PublicKeyPEM []byte
+	// JWKSURL адрес набора ключей провайдера; ключ выбирается по kid из заголовка токена
+	JWKSURL             string
+	JWKSRefreshInterval time.Duration
+	// ClockSkew допустимое расхождение часов при проверке exp и nbf
+	ClockSkew time.Duration
+	// RolesClaim утверждение со списком ролей; DriverIDClaim — с ID водителя (по умолчанию sub)
+	RolesClaim    string
+	DriverIDClaim string
+	// FleetIDsClaim утверждение со списком автопарков, которыми ограничен доступ
+	FleetIDsClaim string
+	// ScopesClaim утверждение со списком областей доступа (по умолчанию scope)
+	ScopesClaim string
+}
+
+// JWTVerifier проверяет подпись и срок действия JWT
+type JWTVerifier struct {
+	config    JWTConfig
+	hmacKey   []byte
+	publicKey *rsa.PublicKey
+	jwks      *jwksKeySet
+	now       func() time.Time
+}
+
+var _ TokenVerifier = (*JWTVerifier)(nil)
+
+// NewJWTVerifier создает JWTVerifier
+func NewJWTVerifier(cfg JWTConfig, logger *zap.Logger) (*JWTVerifier, error) {
+	if cfg.RolesClaim == "" {
+		cfg.RolesClaim = "roles"
+	}
+	if cfg.DriverIDClaim == "" {
+		cfg.DriverIDClaim = "driver_id"
+	}
+	if cfg.FleetIDsClaim == "" {
+		cfg.FleetIDsClaim = "fleet_ids"
+	}
+	if cfg.ScopesClaim == "" {
+		cfg.ScopesClaim = "scope"
+	}
+
+	verifier := &JWTVerifier{
+		config: cfg,
+		now:    time.Now,
+	}
+
+	if cfg.HMACSecret != "" {
+		verifier.hmacKey = []byte(cfg.HMACSecret)
+	}
+
+	if len(cfg.PublicKeyPEM) > 0 {
+		key, err := parseRSAPublicKey(cfg.PublicKeyPEM)
+		if err != nil {
+			return nil, err
+		}
+		verifier.publicKey = key
+	}
+
+	if cfg.JWKSURL != "" {
+		verifier.jwks = &jwksKeySet{
+			url:     cfg.JWKSURL,
+			client:  &http.Client{Timeout: 10 * time.Second},
+			refresh: cfg.JWKSRefreshInterval,
+			logger:  logger,
+		}
+	}
+
+	if verifier.hmacKey == nil && verifier.publicKey == nil && verifier.jwks == nil {
+		return nil, errors.New("no JWT signing keys configured")
+	}
+
+	return verifier, nil
+}
+
+// jwtHeader заголовок JWT
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// Verify проверяет токен и возвращает его утверждения
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	if err := v.verifySignature(ctx, header, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var payload map[string]json.RawMessage
+	if err := decodeSegment(parts[1], &payload); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	return v.parseClaims(payload)
+}
+
+// verifySignature проверяет подпись ключом, соответствующим алгоритму. Алгоритм из
+// заголовка допускается только при настроенном ключе этого типа, поэтому открытый
+// ключ RSA не может быть использован как секрет HS256
+func (v *JWTVerifier) verifySignature(ctx context.Context, header jwtHeader, signingInput string, signature []byte) error {
+	switch header.Algorithm {
+	case "HS256":
+		if v.hmacKey == nil {
+			return ErrUnsupportedAlgorithm
+		}
+		mac := hmac.New(sha256.New, v.hmacKey)
+		mac.Write([]byte(signingInput))
+		if !hmac.Equal(signature, mac.Sum(nil)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case "RS256":
+		key, err := v.rsaKey(ctx, header.KeyID)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256([]byte(signingInput))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlgorithm
+	}
+}
+
+// rsaKey выбирает открытый ключ: по kid из JWKS, иначе статический ключ из конфигурации
+func (v *JWTVerifier) rsaKey(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	if v.jwks != nil && (keyID != "" || v.publicKey == nil) {
+		return v.jwks.key(ctx, keyID)
+	}
+	if v.publicKey == nil {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return v.publicKey, nil
+}
+
+// parseClaims проверяет стандартные утверждения и извлекает роли, ID водителя и автопарки
+func (v *JWTVerifier) parseClaims(payload map[string]json.RawMessage) (*Claims, error) {
+	now := v.now()
+
+	var expiresAt float64
+	if err := json.Unmarshal(payload["exp"], &expiresAt); err != nil {
+		return nil, ErrMalformedToken
+	}
+	claims := &Claims{ExpiresAt: time.Unix(int64(expiresAt), 0)}
+	if now.After(claims.ExpiresAt.Add(v.config.ClockSkew)) {
+		return nil, ErrTokenExpired
+	}
+
+	if raw, ok := payload["nbf"]; ok {
+		var notBefore float64
+		if err := json.Unmarshal(raw, &notBefore); err != nil {
+			return nil, ErrMalformedToken
+		}
+		if now.Add(v.config.ClockSkew).Before(time.Unix(int64(notBefore), 0)) {
+			return nil, ErrTokenNotYetValid
+		}
+	}
+
+	if raw, ok := payload["sub"]; ok {
+		if err := json.Unmarshal(raw, &claims.Subject); err != nil {
+			return nil, ErrMalformedToken
+		}
+	}
+
+	// Сессия и устройство нужны только для обнаружения подозрительной активности,
+	// поэтому неверный формат этих утверждений не делает токен недействительным
+	for _, name := range []string{"sid", "jti"} {
+		if raw, ok := payload[name]; ok {
+			if json.Unmarshal(raw, &claims.SessionID) == nil && claims.SessionID != "" {
+				break
+			}
+		}
+	}
+	if raw, ok := payload["device_id"]; ok {
+		_ = json.Unmarshal(raw, &claims.DeviceID)
+	}
+
+	if v.config.Issuer != "" {
+		var issuer string
+		if err := json.Unmarshal(payload["iss"], &issuer); err != nil || issuer != v.config.Issuer {
+			return nil, ErrInvalidIssuer
+		}
+	}
+
+	if v.config.Audience != "" && !containsString(stringOrList(payload["aud"]), v.config.Audience) {
+		return nil, ErrInvalidAudience
+	}
+
+	// Роли могут передаваться списком или строкой через пробел; неизвестные роли игнорируются
+	for _, name := range stringOrList(payload[v.config.RolesClaim]) {
+		for _, field := range strings.Fields(name) {
+			if role := Role(field); role.IsValid() {
+				claims.Roles = append(claims.Roles, role)
+			}
+		}
+	}
+
+	if claims.HasRole(RoleDriver) {
+		var driverID string
+		if raw, ok := payload[v.config.DriverIDClaim]; ok {
+			_ = json.Unmarshal(raw, &driverID)
+		} else {
+			driverID = claims.Subject
+		}
+
+		id, err := uuid.Parse(driverID)
+		if err != nil {
+			return nil, ErrMissingDriverID
+		}
+		claims.DriverID = &id
+	}
+
+	// Автопарки передаются так же, как роли. Неверный ID отклоняет токен: иначе
+	// партнер молча потерял бы доступ к части водителей
+	for _, value := range stringOrList(payload[v.config.FleetIDsClaim]) {
+		for _, field := range strings.Fields(value) {
+			id, err := uuid.Parse(field)
+			if err != nil {
+				return nil, ErrInvalidFleetID
+			}
+			claims.FleetIDs = append(claims.FleetIDs, id)
+		}
+	}
+	if claims.HasRole(RolePartner) && len(claims.FleetIDs) == 0 {
+		return nil, ErrMissingFleetID
+	}
+
+	// Области доступа передаются так же, как роли; неизвестные области не проверяются
+	for _, value := range stringOrList(payload[v.config.ScopesClaim]) {
+		claims.Scopes = append(claims.Scopes, strings.Fields(value)...)
+	}
+
+	return claims, nil
+}
+
+// jwksKeySet кэш ключей RSA, загружаемых из JWKS провайдера
+type jwksKeySet struct {
+	url     string
+	client  *http.Client
+	refresh time.Duration
+	logger  *zap.Logger
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// key возвращает ключ по kid. Набор перезагружается по истечении refresh или при
+// неизвестном kid (ротация ключей у провайдера)
+func (s *jwksKeySet) key(ctx context.Context, keyID string) (*rsa.PublicKey, error) {
+	s.mu.RLock()
+	key, ok := s.keys[keyID]
+	fetchedAt := s.fetchedAt
+	s.mu.RUnlock()
+
+	stale := s.refresh > 0 && time.Since(fetchedAt) > s.refresh
+	if ok && !stale {
+		return key, nil
+	}
+	if !ok && !fetchedAt.IsZero() && !stale && time.Since(fetchedAt) < jwksMinRefetch {
+		return nil, ErrUnknownSigningKey
+	}
+
+	if err := s.fetch(ctx, fetchedAt); err != nil {
+		s.logger.Error("Failed to fetch JWKS", zap.Error(err), zap.String("url", s.url))
+		if ok {
+			// Провайдер недоступен: продолжаем принимать токены с известными ключами
+			return key, nil
+		}
+		return nil, ErrUnknownSigningKey
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if key, ok := s.keys[keyID]; ok {
+		return key, nil
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+// jsonWebKey ключ из JWKS
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// fetch загружает набор ключей. Если набор уже обновлен параллельным запросом
+// после seen, повторная загрузка не выполняется
+func (s *jwksKeySet) fetch(ctx context.Context, seen time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fetchedAt.After(seen) {
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected JWKS response status: %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.KeyType != "RSA" || (jwk.Use != "" && jwk.Use != "sig") || (jwk.Algorithm != "" && jwk.Algorithm != "RS256") {
+			continue
+		}
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			s.logger.Warn("Skipping invalid JWKS key", zap.Error(err), zap.String("kid", jwk.KeyID))
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	return nil
+}
+
+// rsaPublicKey собирает открытый ключ RSA из модуля и экспоненты
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	modulus, err := base64.RawURLEncoding.DecodeString(k.Modulus)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	exponent, err := base64.RawURLEncoding.DecodeString(k.Exponent)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	e := new(big.Int).SetBytes(exponent)
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid exponent value")
+	}
+
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(modulus), E: int(e.Int64())}, nil
+}
+
+// parseRSAPublicKey разбирает открытый ключ RSA в формате PEM (PKIX или PKCS#1)
+func parseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid PEM public key")
+	}
+
+	if key, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not RSA")
+	}
+	return key, nil
+}
+
+// decodeSegment декодирует base64url сегмент токена в JSON
+func decodeSegment(segment string, dest interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+// stringOrList разбирает утверждение, которое может быть строкой или списком строк
+func stringOrList(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+	return nil
+}
+
+// containsString проверяет наличие строки в списке
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
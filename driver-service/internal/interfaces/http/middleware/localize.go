@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"driver-service/internal/interfaces/http/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Localize middleware переводит сообщения об ошибках на язык из заголовка Accept-Language
+// (по умолчанию fallback, без него — исходный английский). Перевод выбирается по полю "code"
+// JSON ответа со статусом 4xx/5xx и заменяет поле "error"; код и details не меняются
+func Localize(fallback i18n.Language) gin.HandlerFunc {
+	if fallback == "" {
+		fallback = i18n.SourceLanguage
+	}
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"), fallback)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		if lang == i18n.SourceLanguage {
+			c.Next()
+			return
+		}
+
+		writer := &localizedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		// Ответ, записанный при панике обработчика, тоже отправляется
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			writer.flush(lang)
+		}()
+		c.Next()
+	}
+}
+
+// localizedWriter задерживает JSON тело ответа с ошибкой до перевода; остальные ответы
+// пишутся сразу
+type localizedWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	decided   bool
+	buffering bool
+}
+
+func (w *localizedWriter) Write(data []byte) (int, error) {
+	if w.buffer() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *localizedWriter) WriteString(s string) (int, error) {
+	if w.buffer() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// buffer решает при первой записи, задерживать ли тело: к этому моменту статус и
+// Content-Type уже выставлены
+func (w *localizedWriter) buffer() bool {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.Status() >= http.StatusBadRequest &&
+			strings.Contains(w.Header().Get("Content-Type"), "json")
+	}
+	return w.buffering
+}
+
+// flush пишет задержанное тело, переведенное на lang, если для кода ошибки есть перевод
+func (w *localizedWriter) flush(lang i18n.Language) {
+	if !w.buffering {
+		return
+	}
+
+	body := w.body.Bytes()
+	contentLanguage := i18n.SourceLanguage
+	if localized, ok := localizeError(body, lang); ok {
+		body, contentLanguage = localized, lang
+	}
+	w.Header().Set("Content-Language", string(contentLanguage))
+	_, _ = w.ResponseWriter.Write(body)
+}
+
+// localizeError заменяет поле "error" переводом сообщения по полю "code"
+func localizeError(body []byte, lang i18n.Language) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, false
+	}
+	if _, ok := fields["error"]; !ok {
+		return nil, false
+	}
+
+	var code string
+	if err := json.Unmarshal(fields["code"], &code); err != nil || code == "" {
+		return nil, false
+	}
+	message, ok := i18n.Message(lang, code)
+	if !ok {
+		return nil, false
+	}
+
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, false
+	}
+	fields["error"] = encoded
+	localized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false
+	}
+	return localized, true
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"driver-service/internal/interfaces/http/i18n"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalizedRouter(fallback i18n.Language) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Localize(fallback))
+	router.GET("/drivers/:id", func(c *gin.Context) {
+		switch c.Param("id") {
+		case "missing":
+			c.JSON(http.StatusNotFound, gin.H{"error": "Driver not found", "code": "DRIVER_NOT_FOUND", "details": "no rows"})
+		case "unknown":
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Something odd", "code": "NOT_A_CODE"})
+		case "plain":
+			c.String(http.StatusBadRequest, "bad request")
+		default:
+			c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "code": "DRIVER_NOT_FOUND"})
+		}
+	})
+	return router
+}
+
+func getLocalized(router *gin.Engine, path, acceptLanguage string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func decodeBody(t *testing.T, recorder *httptest.ResponseRecorder) map[string]string {
+	t.Helper()
+	var body map[string]string
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+	return body
+}
+
+func TestLocalize_TranslatesErrorByCode(t *testing.T) {
+	recorder := getLocalized(newLocalizedRouter(i18n.English), "/drivers/missing", "ru-RU,ru;q=0.9,en;q=0.8")
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "ru", recorder.Header().Get("Content-Language"))
+	assert.Contains(t, recorder.Header().Values("Vary"), "Accept-Language")
+	body := decodeBody(t, recorder)
+	assert.Equal(t, "Водитель не найден", body["error"])
+	assert.Equal(t, "DRIVER_NOT_FOUND", body["code"])
+	assert.Equal(t, "no rows", body["details"])
+}
+
+func TestLocalize_SourceLanguagePassesThrough(t *testing.T) {
+	recorder := getLocalized(newLocalizedRouter(i18n.English), "/drivers/missing", "en")
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.Equal(t, "Driver not found", decodeBody(t, recorder)["error"])
+	assert.Contains(t, recorder.Header().Values("Vary"), "Accept-Language")
+}
+
+func TestLocalize_FallbackLanguage(t *testing.T) {
+	router := newLocalizedRouter(i18n.Russian)
+
+	assert.Equal(t, "Водитель не найден", decodeBody(t, getLocalized(router, "/drivers/missing", ""))["error"])
+	assert.Equal(t, "Driver not found", decodeBody(t, getLocalized(router, "/drivers/missing", "en"))["error"])
+}
+
+func TestLocalize_UntranslatedResponsesUnchanged(t *testing.T) {
+	router := newLocalizedRouter(i18n.English)
+
+	unknown := getLocalized(router, "/drivers/unknown", "ru")
+	assert.Equal(t, http.StatusBadRequest, unknown.Code)
+	assert.Equal(t, "en", unknown.Header().Get("Content-Language"))
+	assert.Equal(t, "Something odd", decodeBody(t, unknown)["error"])
+
+	plain := getLocalized(router, "/drivers/plain", "ru")
+	assert.Equal(t, http.StatusBadRequest, plain.Code)
+	assert.Equal(t, "bad request", plain.Body.String())
+
+	ok := getLocalized(router, "/drivers/42", "ru")
+	assert.Equal(t, http.StatusOK, ok.Code)
+	assert.Equal(t, "42", decodeBody(t, ok)["id"])
+	assert.Empty(t, ok.Header().Get("Content-Language"))
+}
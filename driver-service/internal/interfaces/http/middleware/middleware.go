@@ -2,33 +2,17 @@ package middleware
 
 import (
 	"context"
+	"net/http"
+	"strings"
 	"time"
 
+	"driver-service/internal/infrastructure/database"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-// Logger middleware для логирования HTTP запросов
-func Logger(logger *zap.Logger) gin.HandlerFunc {
-	return gin.LoggerWithConfig(gin.LoggerConfig{
-		Formatter: func(param gin.LogFormatterParams) string {
-			logger.Info("HTTP Request",
-				zap.String("client_ip", param.ClientIP),
-				zap.String("method", param.Method),
-				zap.String("path", param.Path),
-				zap.Int("status_code", param.StatusCode),
-				zap.Duration("latency", param.Latency),
-				zap.String("user_agent", param.Request.UserAgent()),
-				zap.Int("body_size", param.BodySize),
-				zap.String("request_id", param.Request.Header.Get("X-Request-ID")),
-			)
-			return ""
-		},
-		Output: nil,
-	})
-}
-
 // RequestID middleware для добавления уникального ID к каждому запросу
 func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -51,8 +35,9 @@ func CORS() gin.HandlerFunc {
 		// В production среде здесь должны быть проверки разрешенных доменов
 		c.Header("Access-Control-Allow-Origin", origin)
 		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, X-Min-Status-Version, If-None-Match, If-Modified-Since")
 		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
+		c.Header("Access-Control-Expose-Headers", "Link, X-Request-ID, X-Driver-Status-Version, ETag, Last-Modified")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -72,63 +57,74 @@ func RateLimit() gin.HandlerFunc {
 	}
 }
 
-// Timeout middleware для установки таймаута запроса
-func Timeout(timeout time.Duration) gin.HandlerFunc {
+// Timeout middleware ограничивает время обработки запроса: контекст запроса получает срок
+// timeout, и запросы к базе, выполняемые с этим контекстом, отменяются по его истечении.
+// WebSocket подключения живут дольше запроса и не ограничиваются
+func Timeout(timeout time.Duration, logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx := c.Request.Context()
-		
-		// Устанавливаем таймаут для контекста
-		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+		if timeout <= 0 || strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 		defer cancel()
-		
-		// Заменяем контекст запроса
-		c.Request = c.Request.WithContext(timeoutCtx)
-		
+		c.Request = c.Request.WithContext(ctx)
+
 		c.Next()
+
+		if ctx.Err() == context.DeadlineExceeded {
+			logger.Warn("Request deadline exceeded",
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.FullPath()),
+				zap.Duration("timeout", timeout),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+		}
 	}
 }
 
-// Metrics middleware для сбора метрик
-func Metrics() gin.HandlerFunc {
+// ReplicaReads middleware разрешает читать с реплик базы в запросах, которые не изменяют
+// данные (GET, HEAD). Изменяющие запросы читают из основной базы, чтобы решение не
+// принималось по отстающей копии
+func ReplicaReads() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		start := time.Now()
-		path := c.FullPath()
-		method := c.Request.Method
-		
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Request = c.Request.WithContext(database.WithReplicaReads(c.Request.Context()))
+		}
 		c.Next()
-		
-		duration := time.Since(start)
-		status := c.Writer.Status()
-		
-		// Здесь должна быть отправка метрик в Prometheus
-		// Например:
-		// httpRequestDuration.WithLabelValues(method, path, fmt.Sprintf("%d", status)).Observe(duration.Seconds())
-		// httpRequestsTotal.WithLabelValues(method, path, fmt.Sprintf("%d", status)).Inc()
-		
-		_ = duration
-		_ = status
-		_ = path
-		_ = method
 	}
 }
 
-// Auth middleware для аутентификации
-func Auth() gin.HandlerFunc {
+// RequestRecorder получатель метрик обработанных запросов
+type RequestRecorder interface {
+	RecordRequest(method, route string, status int, latency time.Duration, size int)
+}
+
+// Recorders объединяет получателей метрик: каждый запрос передается всем по порядку
+func Recorders(recorders ...RequestRecorder) RequestRecorder {
+	return requestRecorders(recorders)
+}
+
+// requestRecorders набор получателей метрик
+type requestRecorders []RequestRecorder
+
+// RecordRequest передает запрос каждому получателю
+func (r requestRecorders) RecordRequest(method, route string, status int, latency time.Duration, size int) {
+	for _, recorder := range r {
+		recorder.RecordRequest(method, route, status, latency, size)
+	}
+}
+
+// Metrics middleware для сбора метрик нагрузки по эндпоинтам
+func Metrics(recorder RequestRecorder) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// В реальном приложении здесь должна быть проверка JWT токена
-		// или другой механизм аутентификации
-		
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" {
-			c.JSON(401, gin.H{"error": "Authorization header required"})
-			c.Abort()
-			return
-		}
+		start := time.Now()
 		
-		// Проверка токена...
-		// В данном примере пропускаем все запросы
-		c.Set("user_id", "authenticated_user")
 		c.Next()
+		
+		// FullPath возвращает шаблон маршрута, поэтому метрики не дробятся по ID в пути
+		recorder.RecordRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start), c.Writer.Size())
 	}
 }
 
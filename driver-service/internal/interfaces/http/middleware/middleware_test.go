@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var hasDeadline bool
+	router := gin.New()
+	router.Use(Timeout(50*time.Millisecond, zap.NewNop()))
+	router.GET("/drivers", func(c *gin.Context) {
+		_, hasDeadline = c.Request.Context().Deadline()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/drivers", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, hasDeadline)
+
+	// WebSocket соединение живет дольше запроса и срок не получает
+	req = httptest.NewRequest(http.MethodGet, "/drivers", nil)
+	req.Header.Set("Upgrade", "websocket")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, hasDeadline)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitPerMinute ограничивает частоту запросов с одинаковым ключом key: не больше limit
+// запросов в минуту. Запросы с пустым ключом не ограничиваются. Ограничение считается на каждом
+// экземпляре сервиса отдельно
+func RateLimitPerMinute(limit int, key func(c *gin.Context) string) gin.HandlerFunc {
+	limiter := newWindowLimiter[string]()
+
+	return func(c *gin.Context) {
+		k := key(c)
+		if limit <= 0 || k == "" {
+			c.Next()
+			return
+		}
+		if !limiter.allow(c, k, limit, "Rate limit exceeded") {
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateWindow запросы ключа в текущей минуте
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// windowLimiter ограничение частоты запросов по ключам фиксированным окном в минуту
+type windowLimiter[K comparable] struct {
+	mu      sync.Mutex
+	windows map[K]*rateWindow
+	// pruned начало окна, в котором удалены окна прошлых минут
+	pruned time.Time
+}
+
+// newWindowLimiter создает ограничение частоты запросов
+func newWindowLimiter[K comparable]() *windowLimiter[K] {
+	return &windowLimiter[K]{windows: make(map[K]*rateWindow)}
+}
+
+// allow учитывает запрос и выставляет заголовки X-RateLimit-*. Запрос сверх лимита
+// отклоняется с 429 и Retry-After, и allow возвращает false
+func (l *windowLimiter[K]) allow(c *gin.Context, key K, limit int, message string) bool {
+	remaining, retryAfter := l.take(key, limit, time.Now())
+	c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if retryAfter <= 0 {
+		return true
+	}
+
+	c.Header("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+		"error": message,
+		"code":  "RATE_LIMIT_EXCEEDED",
+	})
+	return false
+}
+
+// take учитывает запрос ключа в момент now. Возвращает, сколько запросов осталось в минуте,
+// и через сколько повторить запрос, если лимит исчерпан (0 — запрос принят)
+func (l *windowLimiter[K]) take(key K, limit int, now time.Time) (int, time.Duration) {
+	start := now.Truncate(time.Minute)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Окна ключей, не вызывавшихся в этой минуте, больше не нужны
+	if start.After(l.pruned) {
+		for k, window := range l.windows {
+			if window.start.Before(start) {
+				delete(l.windows, k)
+			}
+		}
+		l.pruned = start
+	}
+
+	window, ok := l.windows[key]
+	if !ok {
+		window = &rateWindow{start: start}
+		l.windows[key] = window
+	}
+	if window.count >= limit {
+		return 0, start.Add(time.Minute).Sub(now)
+	}
+	window.count++
+	return limit - window.count, 0
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitPerMinute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/tracking/:token", RateLimitPerMinute(2, func(c *gin.Context) string {
+		return c.Param("token")
+	}), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	get := func(token string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tracking/"+token, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, get("a").Code)
+	w := get("a")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-RateLimit-Remaining"))
+
+	w = get("a")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "RATE_LIMIT_EXCEEDED")
+
+	// Лимит считается для каждого ключа отдельно
+	assert.Equal(t, http.StatusOK, get("b").Code)
+}
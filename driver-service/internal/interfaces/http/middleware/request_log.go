@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/infrastructure/logging"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// RequestLogConfig настройки журнала HTTP запросов
+type RequestLogConfig struct {
+	// SampleRate доля запросов, попадающих в журнал (0-1); ответы 5xx логируются всегда
+	SampleRate float64
+	// BodySampleRate доля записей журнала с телами запроса и ответа (0-1)
+	BodySampleRate float64
+	// MaxBodySize тела длиннее не логируются: обрезанный JSON нельзя надежно замаскировать
+	MaxBodySize int
+	// Routes настройки отдельных маршрутов по ключу "METHOD /path" с шаблоном пути
+	// (например, "POST /api/v1/drivers/:id/locations")
+	Routes map[string]RouteLogConfig
+}
+
+// RouteLogConfig настройки журнала для маршрута; nil — как для всех запросов
+type RouteLogConfig struct {
+	SampleRate     *float64
+	BodySampleRate *float64
+}
+
+// rates возвращает доли запросов и тел для маршрута
+func (cfg RequestLogConfig) rates(method, route string) (float64, float64) {
+	sampleRate, bodySampleRate := cfg.SampleRate, cfg.BodySampleRate
+	if override, ok := cfg.Routes[method+" "+route]; ok {
+		if override.SampleRate != nil {
+			sampleRate = *override.SampleRate
+		}
+		if override.BodySampleRate != nil {
+			bodySampleRate = *override.BodySampleRate
+		}
+	}
+	return sampleRate, bodySampleRate
+}
+
+// sampled решает, попадает ли запрос в выборку с долей rate
+func sampled(rate float64) bool {
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// bodyRecorder копирует начало тела ответа, не больше limit+1 байт: по лишнему байту
+// видно, что тело длиннее ограничения
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body  bytes.Buffer
+	limit int
+}
+
+func (w *bodyRecorder) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyRecorder) capture(data []byte) {
+	if room := w.limit + 1 - w.body.Len(); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.body.Write(data)
+	}
+}
+
+// RequestLogger middleware журнала HTTP запросов: метод, шаблон маршрута, статус, задержка и
+// ID запроса. Для выборки запросов логируются JSON тела запроса и ответа, параметры запроса
+// маскируются redactor: телефоны, email, паспортные данные и номера удостоверений не
+// попадают в журнал ни по имени поля, ни по содержимому строк
+func RequestLogger(logger *zap.Logger, redactor *logging.Redactor, cfg RequestLogConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		sampleRate, bodySampleRate := cfg.rates(c.Request.Method, route)
+
+		logBody := cfg.MaxBodySize > 0 && sampled(bodySampleRate)
+		var requestBody []byte
+		var recorder *bodyRecorder
+		if logBody {
+			requestBody = peekBody(c.Request, cfg.MaxBodySize)
+			recorder = &bodyRecorder{ResponseWriter: c.Writer, limit: cfg.MaxBodySize}
+			c.Writer = recorder
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		if status < http.StatusInternalServerError && !sampled(sampleRate) {
+			return
+		}
+
+		requestID := c.GetString("request_id")
+		if requestID == "" {
+			requestID = c.GetHeader("X-Request-ID")
+		}
+		fields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("route", route),
+			zap.Int("status_code", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.String("request_id", requestID),
+			zap.String("client_ip", c.ClientIP()),
+			zap.String("user_agent", c.Request.UserAgent()),
+			zap.Int("body_size", c.Writer.Size()),
+		}
+		if query := c.Request.URL.Query(); len(query) > 0 {
+			values := make(map[string]interface{}, len(query))
+			for key, items := range query {
+				list := make([]interface{}, len(items))
+				for i, item := range items {
+					list[i] = item
+				}
+				values[key] = list
+			}
+			fields = append(fields, zap.Any("query", redactor.RedactValue("", values)))
+		}
+		if logBody {
+			fields = appendBody(fields, "request_body", requestBody, c.Request.Header.Get("Content-Type"), redactor, cfg.MaxBodySize)
+			fields = appendBody(fields, "response_body", recorder.body.Bytes(), c.Writer.Header().Get("Content-Type"), redactor, cfg.MaxBodySize)
+		}
+
+		if status >= http.StatusInternalServerError {
+			logger.Error("HTTP Request", fields...)
+			return
+		}
+		logger.Info("HTTP Request", fields...)
+	}
+}
+
+// peekBody читает начало тела запроса, не больше limit+1 байт, и возвращает тело на место
+func peekBody(req *http.Request, limit int) []byte {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(req.Body, int64(limit)+1))
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), req.Body), req.Body}
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// appendBody добавляет в поля замаскированное JSON тело. Тела других типов и длиннее
+// limit не логируются, указывается только их наличие
+func appendBody(fields []zap.Field, key string, body []byte, contentType string, redactor *logging.Redactor, limit int) []zap.Field {
+	switch {
+	case len(body) == 0:
+		return fields
+	case !strings.Contains(contentType, "json"):
+		return append(fields, zap.String(key, "[non-JSON body omitted]"))
+	case len(body) > limit:
+		return append(fields, zap.String(key, "[body too large]"))
+	}
+
+	redacted, ok := redactor.RedactJSON(body)
+	if !ok {
+		return append(fields, zap.String(key, "[invalid JSON omitted]"))
+	}
+	return append(fields, zap.Any(key, redacted))
+}
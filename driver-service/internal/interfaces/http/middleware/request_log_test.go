@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"driver-service/internal/infrastructure/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestLogger_Sampling(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zap.InfoLevel)
+
+	never := 0.0
+	router := gin.New()
+	router.Use(RequestLogger(zap.New(core), logging.NewRedactor(logging.ModeStrict, nil), RequestLogConfig{
+		SampleRate:     1,
+		BodySampleRate: 1,
+		MaxBodySize:    16,
+		Routes: map[string]RouteLogConfig{
+			"POST /drivers/:id/locations": {SampleRate: &never},
+		},
+	}), RequestID())
+	router.POST("/drivers/:id/locations", func(c *gin.Context) {
+		if c.Query("fail") != "" {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/drivers/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "status": "available"})
+	})
+
+	request := func(method, path string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Маршрут исключен из выборки, но ответы 5xx логируются всегда
+	request(http.MethodPost, "/drivers/1/locations")
+	assert.Zero(t, logs.Len())
+	request(http.MethodPost, "/drivers/1/locations?fail=1")
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, zap.ErrorLevel, logs.All()[0].Level)
+
+	// Тело ответа длиннее MaxBodySize не логируется
+	request(http.MethodGet, "/drivers/1")
+	require.Equal(t, 2, logs.Len())
+	fields := logs.All()[1].ContextMap()
+	assert.Equal(t, "/drivers/:id", fields["route"])
+	assert.NotEmpty(t, fields["request_id"])
+	assert.Equal(t, "[body too large]", fields["response_body"])
+}
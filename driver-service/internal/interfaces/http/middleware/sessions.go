@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DeviceIDHeader заголовок с ID устройства, если токен не содержит утверждения device_id
+const DeviceIDHeader = "X-Device-ID"
+
+// SessionObserver учитывает запросы водителей в их сессиях
+type SessionObserver interface {
+	// ObserveSession возвращает entities.ErrSessionRevoked для отозванной сессии
+	ObserveSession(ctx context.Context, obs *entities.SessionObservation) error
+}
+
+// TrackSessions передает сессию водителя на проверку подозрительной активности.
+// Должен выполняться после Authenticate. Запросы без сессии в токене и запросы
+// диспетчеров не учитываются; сбой проверки не блокирует запрос
+func TrackSessions(observer SessionObserver, countryHeader string, logger *zap.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := ClaimsFromContext(c)
+		if !ok || claims.DriverID == nil || claims.SessionID == "" {
+			c.Next()
+			return
+		}
+
+		deviceID := claims.DeviceID
+		if deviceID == "" {
+			deviceID = c.GetHeader(DeviceIDHeader)
+		}
+
+		// Ожидается код ISO 3166-1 alpha-2; иное значение заголовка игнорируется
+		country := strings.ToUpper(strings.TrimSpace(c.GetHeader(countryHeader)))
+		if len(country) != 2 {
+			country = ""
+		}
+
+		err := observer.ObserveSession(c.Request.Context(), &entities.SessionObservation{
+			DriverID:  *claims.DriverID,
+			SessionID: claims.SessionID,
+			DeviceID:  deviceID,
+			Country:   country,
+			IPAddress: c.ClientIP(),
+			At:        time.Now(),
+		})
+		if errors.Is(err, entities.ErrSessionRevoked) {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Session has been revoked",
+				"code":  "SESSION_REVOKED",
+			})
+			return
+		}
+		if err != nil {
+			logger.Warn("Failed to observe driver session",
+				zap.Error(err),
+				zap.String("driver_id", claims.DriverID.String()),
+				zap.String("request_id", c.GetString("request_id")),
+			)
+		}
+
+		c.Next()
+	}
+}
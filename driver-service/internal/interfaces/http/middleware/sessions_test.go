@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubSessionObserver запоминает наблюдения и отзывает заданные сессии
+type stubSessionObserver struct {
+	revoked      map[string]bool
+	observations []*entities.SessionObservation
+}
+
+func (o *stubSessionObserver) ObserveSession(ctx context.Context, obs *entities.SessionObservation) error {
+	o.observations = append(o.observations, obs)
+	if o.revoked[obs.SessionID] {
+		return entities.ErrSessionRevoked
+	}
+	return nil
+}
+
+func TestTrackSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	verifier := newHMACVerifier(t)
+	header := map[string]interface{}{"alg": "HS256"}
+	observer := &stubSessionObserver{revoked: map[string]bool{"revoked-session": true}}
+
+	router := gin.New()
+	router.Use(Authenticate(verifier, zap.NewNop()), TrackSessions(observer, "X-Country-Code", zap.NewNop()))
+	router.GET("/me", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(claims map[string]interface{}) int {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+signHS256(t, header, claims))
+		req.Header.Set("X-Country-Code", "RU")
+		req.Header.Set(DeviceIDHeader, "phone-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	driverID := uuid.New()
+	active := driverClaims(driverID)
+	active["jti"] = "active-session"
+	assert.Equal(t, http.StatusOK, request(active))
+	require.Len(t, observer.observations, 1)
+	assert.Equal(t, "active-session", observer.observations[0].SessionID)
+	assert.Equal(t, "phone-1", observer.observations[0].DeviceID)
+	assert.Equal(t, "RU", observer.observations[0].Country)
+
+	revoked := driverClaims(driverID)
+	revoked["sid"] = "revoked-session"
+	revoked["jti"] = "token-id"
+	assert.Equal(t, http.StatusUnauthorized, request(revoked))
+
+	// Токен без сессии не учитывается
+	assert.Equal(t, http.StatusOK, request(driverClaims(driverID)))
+	assert.Len(t, observer.observations, 2)
+}
@@ -0,0 +1,195 @@
+// Package pagination единые параметры и метаданные постраничной выдачи REST API.
+//
+// Все списочные эндпоинты принимают limit и offset либо непрозрачный cursor,
+// возвращают метаданные страницы в полях ответа и заголовок Link (RFC 5988)
+// со ссылками first, prev, next и last
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Ошибки разбора параметров страницы
+var (
+	ErrInvalidLimit  = errors.New("limit must be a positive integer")
+	ErrInvalidOffset = errors.New("offset must be a non-negative integer")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// cursorPrefix префикс содержимого курсора до кодирования
+const cursorPrefix = "offset:"
+
+// Options ограничения размера страницы эндпоинта
+type Options struct {
+	// DefaultLimit размер страницы, если limit не указан
+	DefaultLimit int
+	// MaxLimit максимальный размер страницы; большие значения limit уменьшаются до него
+	MaxLimit int
+}
+
+// DefaultOptions ограничения для большинства списочных эндпоинтов
+var DefaultOptions = Options{DefaultLimit: 20, MaxLimit: 100}
+
+// Request запрошенная страница
+type Request struct {
+	Limit  int
+	Offset int
+}
+
+// Page метаданные страницы. Встраивается в ответы списочных эндпоинтов
+type Page struct {
+	// Total общее количество элементов; не заполняется, если подсчет дорог
+	Total      *int   `json:"total,omitempty"`
+	Limit      int    `json:"limit"`
+	Offset     int    `json:"offset"`
+	HasMore    bool   `json:"has_more"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+}
+
+// Parse разбирает параметры limit, offset и cursor. Курсор имеет приоритет над offset
+func Parse(c *gin.Context, opts Options) (Request, error) {
+	req := Request{Limit: opts.DefaultLimit}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			return Request{}, ErrInvalidLimit
+		}
+		req.Limit = limit
+	}
+	if opts.MaxLimit > 0 && req.Limit > opts.MaxLimit {
+		req.Limit = opts.MaxLimit
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		offset, err := DecodeCursor(cursor)
+		if err != nil {
+			return Request{}, err
+		}
+		req.Offset = offset
+		return req, nil
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			return Request{}, ErrInvalidOffset
+		}
+		req.Offset = offset
+	}
+
+	return req, nil
+}
+
+// Slice возвращает часть items, попадающую в страницу. Используется, когда
+// сервис возвращает весь список целиком
+func Slice[T any](items []T, req Request) []T {
+	if req.Offset >= len(items) {
+		return []T{}
+	}
+	end := req.Offset + req.Limit
+	if end > len(items) {
+		end = len(items)
+	}
+	return items[req.Offset:end]
+}
+
+// Paginate формирует метаданные страницы из count элементов и выставляет заголовок Link.
+// Если total неизвестен, hasMore определяет вызывающий (например, запросив на один элемент больше)
+func Paginate(c *gin.Context, req Request, count int, total *int, hasMore bool) Page {
+	page := Page{
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: hasMore,
+	}
+	if total != nil {
+		page.HasMore = req.Offset+count < *total
+	}
+
+	if page.HasMore {
+		page.NextCursor = EncodeCursor(req.Offset + count)
+	}
+	if req.Offset > 0 {
+		page.PrevCursor = EncodeCursor(prevOffset(req))
+	}
+
+	setLinkHeader(c, page, count)
+	return page
+}
+
+// Total возвращает указатель на общее количество для Paginate
+func Total(total int) *int {
+	return &total
+}
+
+// EncodeCursor кодирует смещение в непрозрачный курсор
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor возвращает смещение из курсора
+func DecodeCursor(cursor string) (int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	value, ok := strings.CutPrefix(string(raw), cursorPrefix)
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+
+	offset, err := strconv.Atoi(value)
+	if err != nil || offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return offset, nil
+}
+
+// prevOffset смещение предыдущей страницы
+func prevOffset(req Request) int {
+	if req.Offset > req.Limit {
+		return req.Offset - req.Limit
+	}
+	return 0
+}
+
+// setLinkHeader выставляет заголовок Link со ссылками на соседние страницы.
+// Ссылки относительные и сохраняют остальные параметры запроса
+func setLinkHeader(c *gin.Context, page Page, count int) {
+	links := []string{pageLink(c, "first", page.Limit, 0)}
+	if page.Offset > 0 {
+		links = append(links, pageLink(c, "prev", page.Limit, prevOffset(Request{Limit: page.Limit, Offset: page.Offset})))
+	}
+	if page.HasMore {
+		links = append(links, pageLink(c, "next", page.Limit, page.Offset+count))
+	}
+	if page.Total != nil && *page.Total > 0 {
+		links = append(links, pageLink(c, "last", page.Limit, (*page.Total-1)/page.Limit*page.Limit))
+	}
+
+	c.Header("Link", strings.Join(links, ", "))
+}
+
+// pageLink формирует ссылку на страницу с указанным смещением
+func pageLink(c *gin.Context, rel string, limit, offset int) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("offset")
+	query.Del("cursor")
+	query.Set("limit", strconv.Itoa(limit))
+	if offset > 0 {
+		query.Set("cursor", EncodeCursor(offset))
+	}
+	u.RawQuery = query.Encode()
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.RequestURI(), rel)
+}
@@ -0,0 +1,92 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+	return c, w
+}
+
+func TestParse(t *testing.T) {
+	opts := Options{DefaultLimit: 20, MaxLimit: 100}
+
+	cases := []struct {
+		name  string
+		query string
+		want  Request
+		err   error
+	}{
+		{"defaults", "", Request{Limit: 20}, nil},
+		{"limit and offset", "?limit=5&offset=10", Request{Limit: 5, Offset: 10}, nil},
+		{"limit capped", "?limit=1000", Request{Limit: 100}, nil},
+		{"cursor overrides offset", "?offset=3&cursor=" + EncodeCursor(40), Request{Limit: 20, Offset: 40}, nil},
+		{"zero limit", "?limit=0", Request{}, ErrInvalidLimit},
+		{"non-numeric limit", "?limit=all", Request{}, ErrInvalidLimit},
+		{"negative offset", "?offset=-1", Request{}, ErrInvalidOffset},
+		{"garbage cursor", "?cursor=***", Request{}, ErrInvalidCursor},
+		{"negative cursor", "?cursor=b2Zmc2V0Oi0x", Request{}, ErrInvalidCursor},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, _ := newContext("/api/v1/drivers" + tc.query)
+			got, err := Parse(c, opts)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	c, w := newContext("/api/v1/drivers?status=active&limit=10&offset=10")
+	req, err := Parse(c, DefaultOptions)
+	require.NoError(t, err)
+
+	page := Paginate(c, req, 10, Total(35), false)
+
+	require.NotNil(t, page.Total)
+	assert.Equal(t, 35, *page.Total)
+	assert.True(t, page.HasMore)
+	assert.Equal(t, EncodeCursor(20), page.NextCursor)
+	assert.Equal(t, EncodeCursor(0), page.PrevCursor)
+
+	assert.Equal(t,
+		`</api/v1/drivers?limit=10&status=active>; rel="first", `+
+			`</api/v1/drivers?limit=10&status=active>; rel="prev", `+
+			`</api/v1/drivers?cursor=`+EncodeCursor(20)+`&limit=10&status=active>; rel="next", `+
+			`</api/v1/drivers?cursor=`+EncodeCursor(30)+`&limit=10&status=active>; rel="last"`,
+		w.Header().Get("Link"))
+}
+
+func TestPaginate_UnknownTotal(t *testing.T) {
+	c, w := newContext("/api/v1/locations/nearby?limit=5")
+	req, err := Parse(c, DefaultOptions)
+	require.NoError(t, err)
+
+	page := Paginate(c, req, 5, nil, false)
+	assert.Nil(t, page.Total)
+	assert.False(t, page.HasMore)
+	assert.Empty(t, page.NextCursor)
+	assert.Empty(t, page.PrevCursor)
+	assert.Equal(t, `</api/v1/locations/nearby?limit=5>; rel="first"`, w.Header().Get("Link"))
+
+	page = Paginate(c, req, 5, nil, true)
+	assert.Equal(t, EncodeCursor(5), page.NextCursor)
+}
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	assert.Equal(t, []int{3, 4}, Slice(items, Request{Limit: 2, Offset: 2}))
+	assert.Equal(t, []int{5}, Slice(items, Request{Limit: 2, Offset: 4}))
+	assert.Empty(t, Slice(items, Request{Limit: 2, Offset: 10}))
+}
@@ -0,0 +1,236 @@
+package http
+
+import (
+	"net/http"
+
+	"driver-service/internal/interfaces/http/middleware"
+)
+
+// apiPrefix префикс маршрутов API
+const apiPrefix = "/api/v1"
+
+var (
+	// staff диспетчеры и администраторы
+	staff = []middleware.Role{middleware.RoleDispatcher, middleware.RoleAdmin}
+	// adminOnly только администраторы
+	adminOnly = []middleware.Role{middleware.RoleAdmin}
+	// everyone любой аутентифицированный пользователь
+	everyone = []middleware.Role{middleware.RoleDriver, middleware.RoleDispatcher, middleware.RoleAdmin}
+	// staffAndPartners сотрудники и кабинеты автопарков; партнеру сервисы отдают только
+	// водителей и автопарки из его токена
+	staffAndPartners = []middleware.Role{middleware.RoleDispatcher, middleware.RoleAdmin, middleware.RolePartner}
+)
+
+// selfOr открывает маршрут водителю с ID из параметра :id и ролям roles
+func selfOr(roles ...middleware.Role) middleware.Policy {
+	return middleware.Policy{Roles: roles, SelfParam: "id"}
+}
+
+// routePolicies правила доступа к маршрутам API. Маршруты без правила доступны
+// диспетчерам и администраторам; водителям открыты только перечисленные маршруты
+// с их собственным ID, партнерам — только чтение водителей и автопарков
+var routePolicies = middleware.PolicySet{
+	Default: middleware.Policy{Roles: staff},
+	Routes: map[string]middleware.Policy{
+		// Водители
+		route(http.MethodGet, "/drivers"):              {Roles: staffAndPartners},
+		route(http.MethodGet, "/drivers/active"):       {Roles: staffAndPartners},
+		route(http.MethodGet, "/drivers/:id"):          selfOr(staffAndPartners...),
+		route(http.MethodPut, "/drivers/:id"):          selfOr(staff...),
+		route(http.MethodPatch, "/drivers/:id"):        selfOr(staff...),
+		route(http.MethodPatch, "/drivers/:id/status"): selfOr(staff...),
+		route(http.MethodDelete, "/drivers/:id"):       {Roles: adminOnly},
+
+		// Выгрузка и удаление персональных данных по запросу самого водителя
+		route(http.MethodGet, "/drivers/:id/export"):           selfOr(adminOnly...),
+		route(http.MethodDelete, "/drivers/:id/personal-data"): selfOr(adminOnly...),
+
+		// Местоположение и сигнал присутствия публикует только сам водитель
+		route(http.MethodPost, "/drivers/:id/locations"):           selfOr(),
+		route(http.MethodPost, "/drivers/:id/locations/batch"):     selfOr(),
+		route(http.MethodPost, "/drivers/:id/heartbeat"):           selfOr(),
+		route(http.MethodGet, "/drivers/:id/locations/current"):    selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/locations/history"):    selfOr(staff...),
+		route(http.MethodPut, "/drivers/:id/locations/privacy"):    selfOr(adminOnly...),
+		route(http.MethodGet, "/drivers/:id/trips"):                selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/locations/summaries"):  selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/profile/completeness"): selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/statistics"):           selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/statistics/daily"):     selfOr(staff...),
+
+		// Выгрузка истории местоположений; точность координат файла определяется запросившим
+		route(http.MethodPost, "/drivers/:id/locations/export"):                     selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/locations/exports/:export_id"):          selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/locations/exports/:export_id/download"): selfOr(staff...),
+
+		// Код подтверждения телефона может запросить и поддержка, ввести — только сам водитель
+		route(http.MethodPost, "/drivers/:id/verify-phone"):         selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/verify-phone/confirm"): selfOr(),
+
+		// Устройство регистрирует приложение водителя; отозвать его может и диспетчер
+		route(http.MethodPost, "/drivers/:id/devices"):              selfOr(),
+		route(http.MethodGet, "/drivers/:id/devices"):               selfOr(staff...),
+		route(http.MethodDelete, "/drivers/:id/devices/:device_id"): selfOr(staff...),
+
+		// Конфигурацию приложения запрашивает сам водитель; флаги функций меняют администраторы
+		route(http.MethodGet, "/drivers/:id/app-config"):     selfOr(staff...),
+		route(http.MethodPost, "/admin/feature-flags"):       {Roles: adminOnly},
+		route(http.MethodGet, "/admin/feature-flags"):        {Roles: adminOnly},
+		route(http.MethodGet, "/admin/feature-flags/:id"):    {Roles: adminOnly},
+		route(http.MethodPut, "/admin/feature-flags/:id"):    {Roles: adminOnly},
+		route(http.MethodDelete, "/admin/feature-flags/:id"): {Roles: adminOnly},
+
+		// Расписание доступности задает сам водитель или диспетчер
+		route(http.MethodGet, "/drivers/:id/schedule"):    selfOr(staff...),
+		route(http.MethodPut, "/drivers/:id/schedule"):    selfOr(staff...),
+		route(http.MethodDelete, "/drivers/:id/schedule"): selfOr(staff...),
+
+		// Переписку с диспетчером ведет сам водитель; сторона определяется по токену
+		route(http.MethodPost, "/drivers/:id/messages"):          selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/messages"):           selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/messages/receipts"): selfOr(staff...),
+		route(http.MethodGet, "/ws/drivers/:id/messages"):        selfOr(staff...),
+
+		// Геозоны видят сотрудники, изменяют только администраторы
+		route(http.MethodPost, "/geofences"):       {Roles: adminOnly},
+		route(http.MethodPut, "/geofences/:id"):    {Roles: adminOnly},
+		route(http.MethodDelete, "/geofences/:id"): {Roles: adminOnly},
+
+		// Регионы и города видят сотрудники, изменяют только администраторы
+		route(http.MethodPost, "/regions"):              {Roles: adminOnly},
+		route(http.MethodPost, "/cities"):               {Roles: adminOnly},
+		route(http.MethodPut, "/cities/:id"):            {Roles: adminOnly},
+		route(http.MethodDelete, "/cities/:id"):         {Roles: adminOnly},
+		route(http.MethodGet, "/admin/capacity/cities"): {Roles: adminOnly},
+
+		// Автопарки заводят администраторы, партнер видит только свои
+		route(http.MethodGet, "/fleets"):     {Roles: staffAndPartners},
+		route(http.MethodGet, "/fleets/:id"): {Roles: staffAndPartners},
+		route(http.MethodPost, "/fleets"):    {Roles: adminOnly},
+		route(http.MethodPut, "/fleets/:id"): {Roles: adminOnly},
+
+		// Справочник меток нужен и кабинетам автопарков для фильтра списка водителей;
+		// метки ставят и снимают сотрудники (правило по умолчанию)
+		route(http.MethodGet, "/driver-tags"): {Roles: staffAndPartners},
+
+		// Смены и расходы
+		route(http.MethodPost, "/drivers/:id/shifts/start"):                      selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/shifts/end"):                        selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/shifts/active"):                      selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/shifts"):                             selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/shifts/stats"):                       selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/shifts/breaks/start"):               selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/shifts/breaks/end"):                 selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/shifts/:shift_id/breaks"):            selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/shifts/:shift_id/expenses"):         selfOr(),
+		route(http.MethodGet, "/drivers/:id/shifts/:shift_id/expenses"):          selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/expenses/:expense_id/receipt"):      selfOr(),
+		route(http.MethodGet, "/drivers/:id/earnings"):                           selfOr(staff...),
+		route(http.MethodGet, "/admin/expenses/export"):                          {Roles: adminOnly},
+		route(http.MethodGet, "/drivers/:id/inspections/summary"):                selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/inspections/:inspection_id/photos"): selfOr(),
+		route(http.MethodPost, "/drivers/:id/inspections/:inspection_id/submit"): selfOr(),
+
+		// Начисления поступают от биллинга, ведомость видит сам водитель
+		route(http.MethodPost, "/drivers/:id/earnings/entries"): {Roles: adminOnly},
+		route(http.MethodGet, "/drivers/:id/earnings/entries"):  selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/earnings/summary"):  selfOr(staff...),
+
+		// Техосмотры: результат фиксирует администратор
+		route(http.MethodPost, "/inspections/:id/complete"): {Roles: adminOnly},
+		route(http.MethodPost, "/inspections/:id/review"):   {Roles: adminOnly},
+
+		// Оценки и рейтинги
+		route(http.MethodGet, "/drivers/:id/ratings"):                    selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/ratings/stats"):              selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/ratings/:rating_id/verify"): {Roles: adminOnly},
+		route(http.MethodGet, "/leaderboards"):                           {Roles: everyone},
+		route(http.MethodGet, "/drivers/:id/leaderboard/rank"):           selfOr(staff...),
+		route(http.MethodPut, "/drivers/:id/leaderboard/visibility"):     selfOr(adminOnly...),
+
+		// Документы: продлевает и загружает повторно сам водитель
+		route(http.MethodGet, "/drivers/:id/documents"):                             selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/documents/history"):                     selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/documents/:document_id/renewals"):      selfOr(),
+		route(http.MethodPost, "/drivers/:id/documents/:document_id/resubmissions"): selfOr(),
+		route(http.MethodGet, "/drivers/:id/documents/:document_id/versions"):       selfOr(staff...),
+
+		// Реферальная программа: отчет о выплатах строится для биллинга
+		route(http.MethodGet, "/drivers/:id/referral-code"): selfOr(staff...),
+		route(http.MethodPost, "/drivers/:id/referrals"):    selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/referrals"):     selfOr(staff...),
+		route(http.MethodGet, "/admin/referrals/payouts"):   {Roles: adminOnly},
+
+		// Приглашения водителей выпускают администраторы; регистрация по ссылке приглашения
+		// открыта без токена (RegisterPublicRoutes) и правил доступа не имеет
+		route(http.MethodPost, "/admin/driver-invites"):       {Roles: adminOnly},
+		route(http.MethodGet, "/admin/driver-invites"):        {Roles: adminOnly},
+		route(http.MethodDelete, "/admin/driver-invites/:id"): {Roles: adminOnly},
+
+		// Отслеживание заказа начинает и останавливает приложение водителя или диспетчер;
+		// клиент смотрит его по ссылке без токена (RegisterPublicRoutes)
+		route(http.MethodPost, "/drivers/:id/orders/:order_id/tracking"):   selfOr(staff...),
+		route(http.MethodDelete, "/drivers/:id/orders/:order_id/tracking"): selfOr(staff...),
+
+		// История статусов нужна поддержке; в ней авторы смен, поэтому водителю не отдается
+		route(http.MethodGet, "/drivers/:id/status-history"): {Roles: staff},
+
+		// Внутренние заметки о водителе видят и добавляют только сотрудники
+		route(http.MethodPost, "/drivers/:id/notes"): {Roles: staff},
+		route(http.MethodGet, "/drivers/:id/notes"):  {Roles: staff},
+
+		// Заявки на изменение ФИО, паспорта и номера удостоверения подает водитель или сотрудник
+		// от его имени; решение по заявке принимает администратор
+		route(http.MethodPost, "/drivers/:id/profile-changes"):              selfOr(staff...),
+		route(http.MethodGet, "/drivers/:id/profile-changes"):               selfOr(staff...),
+		route(http.MethodGet, "/admin/profile-changes"):                     {Roles: adminOnly},
+		route(http.MethodPost, "/admin/profile-changes/:change_id/approve"): {Roles: adminOnly},
+		route(http.MethodPost, "/admin/profile-changes/:change_id/reject"):  {Roles: adminOnly},
+
+		// Каталог событий не содержит данных водителей
+		route(http.MethodGet, "/events/catalog"): {Roles: everyone},
+
+		// Проверка документов и служебные маршруты
+		route(http.MethodPost, "/admin/documents/verification/claim"):        {Roles: adminOnly},
+		route(http.MethodPost, "/admin/documents/verification/decisions"):    {Roles: adminOnly},
+		route(http.MethodGet, "/admin/documents/verification/stats"):         {Roles: adminOnly},
+		route(http.MethodGet, "/admin/jobs"):                                 {Roles: adminOnly},
+		route(http.MethodGet, "/admin/jobs/runs"):                            {Roles: adminOnly},
+		route(http.MethodGet, "/admin/jobs/:name/runs"):                      {Roles: adminOnly},
+		route(http.MethodPost, "/admin/jobs/:name/run"):                      {Roles: adminOnly},
+		route(http.MethodGet, "/admin/database/stats"):                       {Roles: adminOnly},
+		route(http.MethodGet, "/admin/locations/batch-stats"):                {Roles: adminOnly},
+		route(http.MethodGet, "/admin/capacity/forecast"):                    {Roles: adminOnly},
+		route(http.MethodPost, "/admin/drivers/:id/verification/evaluate"):   {Roles: adminOnly},
+		route(http.MethodPatch, "/admin/drivers/:id/status"):                 {Roles: adminOnly},
+		route(http.MethodPost, "/admin/drivers/bulk-status"):                 {Roles: adminOnly},
+		route(http.MethodPost, "/admin/drivers/:id/block"):                   {Roles: adminOnly},
+		route(http.MethodPost, "/admin/drivers/:id/unblock"):                 {Roles: adminOnly},
+		route(http.MethodGet, "/admin/drivers/:id/blocks"):                   {Roles: adminOnly},
+		route(http.MethodPost, "/admin/api-keys"):                            {Roles: adminOnly},
+		route(http.MethodGet, "/admin/api-keys"):                             {Roles: adminOnly},
+		route(http.MethodDelete, "/admin/api-keys/:id"):                      {Roles: adminOnly},
+		route(http.MethodGet, "/audit"):                                      {Roles: adminOnly},
+		route(http.MethodGet, "/admin/drivers/:id/audit"):                    {Roles: adminOnly},
+		route(http.MethodGet, "/admin/audit/chain/verify"):                   {Roles: adminOnly},
+		route(http.MethodGet, "/admin/security/events"):                      {Roles: adminOnly},
+		route(http.MethodPost, "/admin/security/events/:id/review"):          {Roles: adminOnly},
+		route(http.MethodPost, "/admin/reverification/campaigns"):            {Roles: adminOnly},
+		route(http.MethodGet, "/admin/reverification/campaigns"):             {Roles: adminOnly},
+		route(http.MethodGet, "/admin/reverification/campaigns/:id"):         {Roles: adminOnly},
+		route(http.MethodGet, "/admin/reverification/campaigns/:id/targets"): {Roles: adminOnly},
+		route(http.MethodPost, "/admin/reverification/campaigns/:id/cancel"): {Roles: adminOnly},
+
+		// Подписки вебхуков регистрируют администраторы по заявке партнера
+		route(http.MethodPost, "/admin/webhooks"):                         {Roles: adminOnly},
+		route(http.MethodGet, "/admin/webhooks"):                          {Roles: adminOnly},
+		route(http.MethodDelete, "/admin/webhooks/:id"):                   {Roles: adminOnly},
+		route(http.MethodGet, "/admin/webhooks/:id/deliveries"):           {Roles: adminOnly},
+		route(http.MethodPost, "/admin/webhook-deliveries/:id/redeliver"): {Roles: adminOnly},
+	},
+}
+
+// route формирует ключ правила доступа
+func route(method, path string) string {
+	return method + " " + apiPrefix + path
+}
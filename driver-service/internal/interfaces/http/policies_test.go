@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"driver-service/internal/config"
+	"driver-service/internal/interfaces/http/handlers"
+	"driver-service/internal/interfaces/http/middleware"
+	"driver-service/internal/interfaces/websocket"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// stubVerifier не используется при регистрации маршрутов
+type stubVerifier struct{}
+
+func (stubVerifier) Verify(ctx context.Context, token string) (*middleware.Claims, error) {
+	return nil, middleware.ErrMalformedToken
+}
+
+// Опечатка в ключе правила молча применила бы правило по умолчанию
+func TestRoutePoliciesMatchRegisteredRoutes(t *testing.T) {
+	logger := zap.NewNop()
+	server := NewServer(&config.Config{}, logger, stubVerifier{}, nil, nil, nil,
+		handlers.NewDriverHandler(nil, logger),
+		handlers.NewLocationHandler(nil, logger),
+		handlers.NewInspectionHandler(nil, logger),
+		handlers.NewShiftHandler(nil, logger),
+		handlers.NewProfileHandler(nil, logger),
+		handlers.NewLeaderboardHandler(nil, logger),
+		handlers.NewVerificationHandler(nil, logger),
+		handlers.NewRatingHandler(nil, logger),
+		handlers.NewExpenseHandler(nil, logger),
+		handlers.NewEarningHandler(nil, logger),
+		handlers.NewDocumentHandler(nil, logger),
+		handlers.NewCapacityHandler(nil, logger),
+		handlers.NewDriverVerificationHandler(nil, logger),
+		handlers.NewAuditHandler(nil, logger),
+		handlers.NewSecurityHandler(nil, logger),
+		handlers.NewReverificationHandler(nil, logger),
+		handlers.NewPrivacyHandler(nil, logger),
+		handlers.NewTripHandler(nil, logger),
+		handlers.NewLocationSummaryHandler(nil, logger),
+		handlers.NewScheduleHandler(nil, logger),
+		handlers.NewMessageHandler(nil, logger),
+		handlers.NewGeofenceHandler(nil, logger),
+		handlers.NewCityHandler(nil, logger),
+		handlers.NewFeatureFlagHandler(nil, logger),
+		handlers.NewLocationExportHandler(nil, logger),
+		handlers.NewDriverTagHandler(nil, logger),
+		handlers.NewWebhookHandler(nil, logger),
+		handlers.NewPhoneVerificationHandler(nil, logger),
+		handlers.NewDriverNoteHandler(nil, logger),
+		handlers.NewProfileChangeHandler(nil, logger),
+		handlers.NewFleetHandler(nil, logger),
+		handlers.NewDispatchHandler(nil, logger),
+		handlers.NewHeartbeatHandler(nil, logger),
+		handlers.NewDriverStatsHandler(nil, logger),
+		handlers.NewSupplyHandler(nil, logger),
+		handlers.NewDeviceHandler(nil, logger),
+		handlers.NewBlockHandler(nil, logger),
+		handlers.NewReferralHandler(nil, logger),
+		handlers.NewDriverInviteHandler(nil, logger),
+		handlers.NewOrderTrackingHandler(nil, 0, logger),
+		handlers.NewStatusHistoryHandler(nil, logger),
+		handlers.NewStatusOverrideHandler(nil, logger),
+		handlers.NewBulkStatusHandler(nil, logger),
+		handlers.NewAPIKeyHandler(nil, logger),
+		handlers.NewEventCatalogHandler(),
+		handlers.NewJobsHandler(nil, logger),
+		handlers.NewDatabaseHandler(nil),
+		websocket.NewHandler(nil, logger),
+		websocket.NewChatHandler(nil, nil, logger),
+	)
+
+	registered := make(map[string]bool)
+	for _, info := range server.GetRouter().Routes() {
+		registered[info.Method+" "+info.Path] = true
+	}
+
+	for key := range routePolicies.Routes {
+		assert.True(t, registered[key], "policy for unknown route %s", key)
+	}
+}
@@ -4,10 +4,16 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"driver-service/internal/config"
+	"driver-service/internal/infrastructure/health"
+	"driver-service/internal/infrastructure/logging"
+	"driver-service/internal/infrastructure/slo"
+	"driver-service/internal/infrastructure/warmup"
 	"driver-service/internal/interfaces/http/handlers"
+	"driver-service/internal/interfaces/http/i18n"
 	"driver-service/internal/interfaces/http/middleware"
 
 	"github.com/gin-gonic/gin"
@@ -20,14 +26,36 @@ type Server struct {
 	logger     *zap.Logger
 	httpServer *http.Server
 	router     *gin.Engine
+	warmer     *warmup.Warmer
+	health     *health.Checker
+	slo        *slo.Tracker
 }
 
-// NewServer создает новый HTTP сервер
+// RouteRegistrar регистрирует дополнительные маршруты API
+type RouteRegistrar interface {
+	RegisterRoutes(api *gin.RouterGroup)
+}
+
+// PublicRouteRegistrar регистрирует маршруты API, открытые без аутентификации. Проверяется
+// у каждого RouteRegistrar; доступ к таким маршрутам дает сам запрос, например подписанный
+// токен приглашения
+type PublicRouteRegistrar interface {
+	RegisterPublicRoutes(public *gin.RouterGroup)
+}
+
+// NewServer создает новый HTTP сервер. Если verifier не задан, API доступно без аутентификации;
+// если не задан apiKeys, внутренние сервисы аутентифицируются только токенами;
+// если не задан recorder, метрики нагрузки не собираются
 func NewServer(
 	cfg *config.Config,
 	logger *zap.Logger,
+	verifier middleware.TokenVerifier,
+	recorder middleware.RequestRecorder,
+	sessions middleware.SessionObserver,
+	apiKeys middleware.APIKeyAuthenticator,
 	driverHandler *handlers.DriverHandler,
 	locationHandler *handlers.LocationHandler,
+	registrars ...RouteRegistrar,
 ) *Server {
 	// Настройка Gin
 	if cfg.Server.Environment == "production" {
@@ -38,30 +66,57 @@ func NewServer(
 	
 	// Middleware
 	router.Use(gin.Recovery())
-	router.Use(middleware.Logger(logger))
+	router.Use(middleware.RequestLogger(logger, logging.NewRedactor(logging.Mode(cfg.Logger.Redaction.Mode), cfg.Logger.Redaction.Fields), requestLogConfig(cfg.Logger.Requests)))
 	router.Use(middleware.CORS())
 	router.Use(middleware.RequestID())
+	if recorder != nil {
+		router.Use(middleware.Metrics(recorder))
+	}
+	if cfg.Server.Compression.Enabled {
+		router.Use(middleware.Compress(cfg.Server.Compression.MinSize))
+	}
 
-	// Health check
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{
-			"status":    "healthy",
-			"service":   "driver-service",
-			"timestamp": time.Now().UTC(),
-		})
-	})
+	// Проба живости: процесс отвечает на запросы, зависимости не проверяются
+	router.GET("/health", live)
+	router.GET("/health/live", live)
+
+	// Открытые маршруты API: те же язык ошибок, срок обработки и автор запроса, но без
+	// аутентификации и правил доступа
+	public := router.Group(apiPrefix)
+	public.Use(middleware.Localize(i18n.Language(cfg.Server.Language)))
+	public.Use(middleware.Timeout(cfg.Server.Timeout, logger))
+	public.Use(middleware.TrackActor())
 
 	// API routes
-	api := router.Group("/api/v1")
+	api := router.Group(apiPrefix)
+	// Срок обработки задается первым, чтобы ограничить и обращения к базе при аутентификации
+	api.Use(middleware.Localize(i18n.Language(cfg.Server.Language)))
+	api.Use(middleware.Timeout(cfg.Server.Timeout, logger))
+	if verifier != nil {
+		if apiKeys != nil {
+			api.Use(middleware.AuthenticateAPIKeys(apiKeys, logger))
+		}
+		api.Use(
+			middleware.Authenticate(verifier, logger),
+			middleware.Authorize(routePolicies, logger),
+			middleware.ScopeFleets(),
+		)
+		if sessions != nil {
+			api.Use(middleware.TrackSessions(sessions, cfg.Security.CountryHeader, logger))
+		}
+	}
+	api.Use(middleware.TrackActor())
+	api.Use(middleware.ReplicaReads())
 	
 	// Driver routes
 	drivers := api.Group("/drivers")
 	{
 		drivers.POST("", driverHandler.CreateDriver)
-		drivers.GET("", driverHandler.ListDrivers)
-		drivers.GET("/active", driverHandler.GetActiveDrivers)
-		drivers.GET("/:id", driverHandler.GetDriver)
+		drivers.GET("", middleware.Conditional(), driverHandler.ListDrivers)
+		drivers.GET("/active", middleware.Conditional(), driverHandler.GetActiveDrivers)
+		drivers.GET("/:id", middleware.Conditional(), driverHandler.GetDriver)
 		drivers.PUT("/:id", driverHandler.UpdateDriver)
+		drivers.PATCH("/:id", driverHandler.PatchDriver)
 		drivers.DELETE("/:id", driverHandler.DeleteDriver)
 		drivers.PATCH("/:id/status", driverHandler.ChangeStatus)
 		
@@ -69,7 +124,8 @@ func NewServer(
 		drivers.POST("/:id/locations", locationHandler.UpdateLocation)
 		drivers.POST("/:id/locations/batch", locationHandler.BatchUpdateLocations)
 		drivers.GET("/:id/locations/current", locationHandler.GetCurrentLocation)
-		drivers.GET("/:id/locations/history", locationHandler.GetLocationHistory)
+		drivers.GET("/:id/locations/history", middleware.Conditional(), locationHandler.GetLocationHistory)
+		drivers.PUT("/:id/locations/privacy", locationHandler.SetLocationPrivacy)
 	}
 
 	// Location routes
@@ -77,6 +133,15 @@ func NewServer(
 	{
 		locations.GET("/nearby", locationHandler.GetNearbyDrivers)
 	}
+	api.GET("/admin/locations/batch-stats", locationHandler.GetBatchStats)
+
+	// Маршруты остальных модулей
+	for _, registrar := range registrars {
+		registrar.RegisterRoutes(api)
+		if publicRegistrar, ok := registrar.(PublicRouteRegistrar); ok {
+			publicRegistrar.RegisterPublicRoutes(public)
+		}
+	}
 
 	server := &Server{
 		config: cfg,
@@ -91,10 +156,106 @@ func NewServer(
 			MaxHeaderBytes: 1 << 20, // 1 MB
 		},
 	}
+	// Проба готовности: трафик направляется на экземпляр только после прогрева и
+	// при доступных зависимостях
+	router.GET("/ready", server.ready)
+	router.GET("/health/ready", server.ready)
+	// Отчет о целевых показателях для внутреннего мониторинга и оповещений
+	router.GET("/debug/slo", server.sloReport)
 
 	return server
 }
 
+// requestLogConfig переводит настройки журнала запросов в настройки middleware
+func requestLogConfig(cfg config.RequestLogConfig) middleware.RequestLogConfig {
+	routes := make(map[string]middleware.RouteLogConfig, len(cfg.Routes))
+	for _, route := range cfg.Routes {
+		parts := strings.Fields(route.Route)
+		if len(parts) != 2 {
+			continue
+		}
+		routes[strings.ToUpper(parts[0])+" "+parts[1]] = middleware.RouteLogConfig{
+			SampleRate:     route.SampleRate,
+			BodySampleRate: route.BodySampleRate,
+		}
+	}
+
+	return middleware.RequestLogConfig{
+		SampleRate:     cfg.SampleRate,
+		BodySampleRate: cfg.BodySampleRate,
+		MaxBodySize:    cfg.MaxBodySize,
+		Routes:         routes,
+	}
+}
+
+// SetWarmer подключает прогрев к пробе готовности; без прогрева экземпляр готов сразу
+func (s *Server) SetWarmer(warmer *warmup.Warmer) {
+	s.warmer = warmer
+}
+
+// SetHealthChecker подключает проверку зависимостей к пробе готовности; без нее
+// зависимости не проверяются
+func (s *Server) SetHealthChecker(checker *health.Checker) {
+	s.health = checker
+}
+
+// SetSLOTracker подключает учет целевых показателей к отчету /debug/slo; запросы в учет
+// передаются через recorder сервера. Без учета отчет недоступен
+func (s *Server) SetSLOTracker(tracker *slo.Tracker) {
+	s.slo = tracker
+}
+
+// sloReport отвечает задержками и расходом бюджета ошибок ключевых операций за окно
+func (s *Server) sloReport(c *gin.Context) {
+	if s.slo == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "SLO tracking is disabled",
+			"code":  "SLO_DISABLED",
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, s.slo.Report())
+}
+
+// live отвечает, что процесс жив
+func live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "healthy",
+		"service":   "driver-service",
+		"timestamp": time.Now().UTC(),
+	})
+}
+
+// ready отвечает 503, пока идет прогрев или недоступна одна из зависимостей, и сообщает
+// длительность прогрева, состояние и задержку каждой зависимости
+func (s *Server) ready(c *gin.Context) {
+	code := http.StatusOK
+	body := gin.H{"status": health.StatusReady}
+
+	if s.health != nil {
+		report := s.health.Run(c.Request.Context())
+		body["checked_at"] = report.CheckedAt
+		body["dependencies"] = report.Dependencies
+		if !report.Ready() {
+			code = http.StatusServiceUnavailable
+			body["status"] = health.StatusNotReady
+		}
+	}
+
+	if s.warmer != nil {
+		status := s.warmer.Status()
+		body["warmup"] = status
+		if status.State != warmup.StateReady {
+			code = http.StatusServiceUnavailable
+			body["status"] = "warming"
+		}
+	}
+
+	c.JSON(code, body)
+}
+
 // Start запускает HTTP сервер
 func (s *Server) Start() error {
 	s.logger.Info("Starting HTTP server",
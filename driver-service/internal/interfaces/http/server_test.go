@@ -0,0 +1,124 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/infrastructure/health"
+	"driver-service/internal/infrastructure/slo"
+	"driver-service/internal/infrastructure/warmup"
+	"driver-service/internal/interfaces/http/handlers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServer_ReadyWaitsForWarmup(t *testing.T) {
+	logger := zap.NewNop()
+	server := NewServer(&config.Config{}, logger, nil, nil, nil, nil,
+		handlers.NewDriverHandler(nil, logger),
+		handlers.NewLocationHandler(nil, logger),
+	)
+
+	probe := func() int {
+		recorder := httptest.NewRecorder()
+		server.GetRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusOK, probe())
+
+	warmer := warmup.New(time.Second, logger)
+	warmer.Add("noop", func(ctx context.Context) (int, error) { return 0, nil })
+	server.SetWarmer(warmer)
+	assert.Equal(t, http.StatusServiceUnavailable, probe())
+
+	warmer.Run(context.Background())
+	assert.Equal(t, http.StatusOK, probe())
+}
+
+func TestServer_ReadyReportsDependencies(t *testing.T) {
+	logger := zap.NewNop()
+	server := NewServer(&config.Config{}, logger, nil, nil, nil, nil,
+		handlers.NewDriverHandler(nil, logger),
+		handlers.NewLocationHandler(nil, logger),
+	)
+
+	var natsErr error
+	checker := health.NewChecker(time.Second)
+	checker.Add("postgres", func(ctx context.Context) error { return nil })
+	checker.Add("nats", func(ctx context.Context) error { return natsErr })
+	server.SetHealthChecker(checker)
+
+	probe := func(path string) (int, map[string]interface{}) {
+		recorder := httptest.NewRecorder()
+		server.GetRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &body))
+		return recorder.Code, body
+	}
+
+	code, body := probe("/health/ready")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, health.StatusReady, body["status"])
+	assert.Len(t, body["dependencies"], 2)
+
+	natsErr = errors.New("nats connection is CLOSED")
+	code, body = probe("/health/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, health.StatusNotReady, body["status"])
+	nats := body["dependencies"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "nats", nats["name"])
+	assert.Equal(t, health.StatusDown, nats["status"])
+	assert.Equal(t, "nats connection is CLOSED", nats["error"])
+
+	code, _ = probe("/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	// Живость не зависит от зависимостей
+	for _, path := range []string{"/health", "/health/live"} {
+		code, body = probe(path)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "healthy", body["status"])
+	}
+}
+
+func TestServer_SLOReport(t *testing.T) {
+	logger := zap.NewNop()
+	tracker := slo.NewTracker([]slo.Objective{
+		{Name: "liveness", Routes: []string{"GET /health/live"}, AvailabilityObjective: 0.99},
+	}, time.Hour, time.Minute)
+	server := NewServer(&config.Config{}, logger, nil, tracker, nil, nil,
+		handlers.NewDriverHandler(nil, logger),
+		handlers.NewLocationHandler(nil, logger),
+	)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.GetRouter().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusNotFound, get("/debug/slo").Code)
+	server.SetSLOTracker(tracker)
+
+	for i := 0; i < 3; i++ {
+		get("/health/live")
+	}
+	recorder := get("/debug/slo")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var report slo.Report
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	require.Len(t, report.Operations, 1)
+	assert.Equal(t, int64(3), report.Operations[0].Requests)
+	require.NotNil(t, report.Operations[0].Availability)
+	assert.Equal(t, 1.0, report.Operations[0].Availability.Remaining)
+}
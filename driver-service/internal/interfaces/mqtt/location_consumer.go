@@ -0,0 +1,403 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// saveTimeout ограничение времени сохранения одной точки
+const saveTimeout = 10 * time.Second
+
+// maxTrackerCacheEntries размер кэша трекеров, после которого из него удаляются устаревшие записи
+const maxTrackerCacheEntries = 10000
+
+// disconnectQuiesce сколько Stop ждет обработки полученных сообщений, миллисекунды
+const disconnectQuiesce = 5000
+
+// LocationConsumer принимает точки GPS-трекеров автопарков по MQTT. ID трекера берется из
+// топика (сегмент на месте "+"), водитель — по трекеру, зарегистрированному как устройство
+// с платформой tracker. Сообщение QoS 1 подтверждается брокеру после обработки, поэтому
+// после сбоя брокер доставляет его повторно; повторы в пределах dedup_window
+// отбрасываются. Точки неизвестных трекеров и неразобранные сообщения логируются и
+// подтверждаются
+type LocationConsumer struct {
+	clientID        string
+	locationService services.LocationService
+	deviceService   services.DeviceService
+	cfg             config.MQTTConfig
+	logger          *zap.Logger
+
+	client   paho.Client
+	started  atomic.Bool
+	dedup    *dedupCache
+	trackers *trackerCache
+}
+
+// NewLocationConsumer создает подписчика на точки трекеров; clientID должен быть уникален
+// для экземпляра сервиса
+func NewLocationConsumer(clientID string, locationService services.LocationService, deviceService services.DeviceService, cfg config.MQTTConfig, logger *zap.Logger) *LocationConsumer {
+	return &LocationConsumer{
+		clientID:        clientID,
+		locationService: locationService,
+		deviceService:   deviceService,
+		cfg:             cfg,
+		logger:          logger,
+		dedup:           newDedupCache(cfg.DedupWindow, cfg.DedupSize),
+		trackers:        newTrackerCache(cfg.TrackerCacheTTL),
+	}
+}
+
+// Start подключается к брокеру и подписывается на топики трекеров. После переподключения
+// подписка восстанавливается
+func (c *LocationConsumer) Start() error {
+	opts := paho.NewClientOptions().
+		AddBroker(c.cfg.BrokerURL).
+		SetClientID(c.clientID).
+		SetUsername(c.cfg.Username).
+		SetPassword(c.cfg.Password).
+		SetCleanSession(c.cfg.CleanSession).
+		SetKeepAlive(c.cfg.KeepAlive).
+		SetConnectTimeout(c.cfg.ConnectTimeout).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(c.onConnect).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			c.logger.Warn("MQTT connection lost", zap.Error(err))
+		})
+	c.client = paho.NewClient(opts)
+
+	token := c.client.Connect()
+	if !token.WaitTimeout(c.cfg.ConnectTimeout) {
+		return fmt.Errorf("timed out connecting to MQTT broker %s", c.cfg.BrokerURL)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", c.cfg.BrokerURL, err)
+	}
+	if err := c.subscribe(); err != nil {
+		c.client.Disconnect(0)
+		return err
+	}
+	c.started.Store(true)
+
+	c.logger.Info("MQTT location consumer started",
+		zap.String("broker", c.cfg.BrokerURL),
+		zap.Strings("topics", c.cfg.Topics),
+		zap.String("shared_group", c.cfg.SharedGroup),
+	)
+	return nil
+}
+
+// Stop отключается от брокера, дожидаясь обработки полученных сообщений
+func (c *LocationConsumer) Stop() {
+	if c.client == nil {
+		return
+	}
+	c.started.Store(false)
+	c.client.Disconnect(disconnectQuiesce)
+	c.client = nil
+}
+
+// Ping проверяет подключение к брокеру для проверки готовности
+func (c *LocationConsumer) Ping(ctx context.Context) error {
+	if c.client == nil || !c.client.IsConnectionOpen() {
+		return errors.New("MQTT broker is not connected")
+	}
+	return nil
+}
+
+// onConnect восстанавливает подписку после переподключения. Первую подписку оформляет Start
+func (c *LocationConsumer) onConnect(client paho.Client) {
+	if !c.started.Load() {
+		return
+	}
+	if err := c.subscribe(); err != nil {
+		c.logger.Error("Failed to resubscribe to tracker topics", zap.Error(err))
+	}
+}
+
+// subscribe подписывается на топики трекеров, в общей подписке — через $share/<group>/
+func (c *LocationConsumer) subscribe() error {
+	filters := make(map[string]byte, len(c.cfg.Topics))
+	for _, topic := range c.cfg.Topics {
+		if c.cfg.SharedGroup != "" {
+			topic = "$share/" + c.cfg.SharedGroup + "/" + topic
+		}
+		filters[topic] = byte(c.cfg.QoS)
+	}
+
+	token := c.client.SubscribeMultiple(filters, c.handle)
+	if !token.WaitTimeout(c.cfg.ConnectTimeout) {
+		return fmt.Errorf("timed out subscribing to MQTT topics %v", c.cfg.Topics)
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("failed to subscribe to MQTT topics %v: %w", c.cfg.Topics, err)
+	}
+	return nil
+}
+
+// handle сохраняет точку трекера. Клиент вызывает обработчик последовательно и
+// подтверждает сообщение QoS 1 после возврата из него
+func (c *LocationConsumer) handle(_ paho.Client, msg paho.Message) {
+	// Удержанное сообщение — последняя точка, уже полученная раньше
+	if msg.Retained() {
+		return
+	}
+
+	now := time.Now()
+	key := messageKey(msg.Topic(), msg.Payload())
+	if c.dedup.seen(key, now) {
+		c.logger.Debug("Duplicate tracker message skipped",
+			zap.String("topic", msg.Topic()),
+			zap.Bool("dup_flag", msg.Duplicate()),
+		)
+		return
+	}
+
+	if err := c.process(msg, now); err != nil {
+		c.reject(msg, err)
+		return
+	}
+	c.dedup.remember(key, now)
+}
+
+// process привязывает точку трекера к водителю и сохраняет ее
+func (c *LocationConsumer) process(msg paho.Message, now time.Time) error {
+	trackerID, ok := trackerFromTopic(c.cfg.Topics, msg.Topic())
+	if !ok {
+		return fmt.Errorf("%w: no tracker ID in topic", errInvalidPayload)
+	}
+	point, err := decodePayload(msg.Payload(), now)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+	defer cancel()
+
+	driverID, err := c.resolveTracker(ctx, trackerID, now)
+	if err != nil {
+		return err
+	}
+	location, err := point.location(driverID, trackerID)
+	if err != nil {
+		return err
+	}
+	return c.locationService.UpdateLocation(ctx, location)
+}
+
+// resolveTracker находит водителя, за которым закреплен трекер. Результат, в том числе
+// незарегистрированный трекер, запоминается на tracker_cache_ttl
+func (c *LocationConsumer) resolveTracker(ctx context.Context, trackerID string, now time.Time) (uuid.UUID, error) {
+	if driverID, ok := c.trackers.get(trackerID, now); ok {
+		if driverID == uuid.Nil {
+			return uuid.Nil, entities.ErrDeviceNotFound
+		}
+		return driverID, nil
+	}
+
+	device, err := c.deviceService.ResolveTracker(ctx, trackerID)
+	if errors.Is(err, entities.ErrDeviceNotFound) {
+		c.trackers.put(trackerID, uuid.Nil, now)
+		return uuid.Nil, err
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	c.trackers.put(trackerID, device.DriverID, now)
+	return device.DriverID, nil
+}
+
+// reject логирует необработанное сообщение. Сообщение уже не будет доставлено повторно:
+// ошибки содержимого логируются как предупреждения, ошибки сервиса — как ошибки
+func (c *LocationConsumer) reject(msg paho.Message, err error) {
+	fields := []zap.Field{
+		zap.Error(err),
+		zap.String("topic", msg.Topic()),
+	}
+	if isRejectedMessage(err) {
+		c.logger.Warn("Tracker message rejected", fields...)
+		return
+	}
+	c.logger.Error("Failed to save tracker location", fields...)
+}
+
+// isRejectedMessage проверяет, относится ли ошибка к содержимому сообщения или трекеру,
+// а не к работе сервиса
+func isRejectedMessage(err error) bool {
+	return errors.Is(err, errInvalidPayload) ||
+		errors.Is(err, errNoFix) ||
+		errors.Is(err, entities.ErrDeviceNotFound) ||
+		errors.Is(err, entities.ErrInvalidLocation) ||
+		errors.Is(err, entities.ErrInvalidTimestamp) ||
+		errors.Is(err, entities.ErrInvalidLocationMetadata) ||
+		errors.Is(err, entities.ErrDriverNotFound)
+}
+
+// trackerFromTopic возвращает ID трекера из топика: сегмент на месте первого "+" в первом
+// подходящем шаблоне
+func trackerFromTopic(patterns []string, topic string) (string, bool) {
+	segments := strings.Split(topic, "/")
+	for _, pattern := range patterns {
+		trackerID, ok := matchTopic(strings.Split(pattern, "/"), segments)
+		if ok && trackerID != "" {
+			return trackerID, true
+		}
+	}
+	return "", false
+}
+
+// matchTopic сопоставляет топик с шаблоном и возвращает сегмент на месте первого "+"
+func matchTopic(pattern, topic []string) (string, bool) {
+	trackerID, wildcard := "", false
+	for i, segment := range pattern {
+		if segment == "#" {
+			return trackerID, wildcard
+		}
+		if i >= len(topic) {
+			return "", false
+		}
+		switch segment {
+		case "+":
+			if !wildcard {
+				trackerID, wildcard = topic[i], true
+			}
+		case topic[i]:
+		default:
+			return "", false
+		}
+	}
+	return trackerID, wildcard && len(pattern) == len(topic)
+}
+
+// messageKey ключ сообщения для отбрасывания повторной доставки: топик и содержимое.
+// ID сообщения MQTT не подходит — брокер переиспользует его в пределах сессии
+func messageKey(topic string, payload []byte) uint64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(topic))
+	hash.Write([]byte{0})
+	hash.Write(payload)
+	return hash.Sum64()
+}
+
+// dedupCache помнит обработанные сообщения в течение окна, не больше size последних
+type dedupCache struct {
+	mu     sync.Mutex
+	window time.Duration
+	size   int
+	seenAt map[uint64]time.Time
+	order  []uint64
+}
+
+func newDedupCache(window time.Duration, size int) *dedupCache {
+	return &dedupCache{
+		window: window,
+		size:   size,
+		seenAt: make(map[uint64]time.Time),
+	}
+}
+
+// seen проверяет, обработано ли сообщение в пределах окна
+func (d *dedupCache) seen(key uint64, now time.Time) bool {
+	if d.window <= 0 {
+		return false
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	at, ok := d.seenAt[key]
+	return ok && now.Sub(at) < d.window
+}
+
+// remember запоминает обработанное сообщение, вытесняя самые старые и вышедшие из окна
+func (d *dedupCache) remember(key uint64, now time.Time) {
+	if d.window <= 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seenAt[key]; !ok {
+		d.order = append(d.order, key)
+	}
+	d.seenAt[key] = now
+
+	expired := 0
+	for expired < len(d.order) {
+		oldest := d.order[expired]
+		if len(d.order)-expired <= d.size && now.Sub(d.seenAt[oldest]) < d.window {
+			break
+		}
+		delete(d.seenAt, oldest)
+		expired++
+	}
+	d.order = d.order[expired:]
+}
+
+// trackerEntry водитель трекера; uuid.Nil — трекер не зарегистрирован
+type trackerEntry struct {
+	driverID  uuid.UUID
+	expiresAt time.Time
+}
+
+// trackerCache помнит, за каким водителем закреплен трекер
+type trackerCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]trackerEntry
+}
+
+func newTrackerCache(ttl time.Duration) *trackerCache {
+	return &trackerCache{
+		ttl:     ttl,
+		entries: make(map[string]trackerEntry),
+	}
+}
+
+// get возвращает водителя трекера, если запись еще не устарела
+func (t *trackerCache) get(trackerID string, now time.Time) (uuid.UUID, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[trackerID]
+	if !ok || !now.Before(entry.expiresAt) {
+		delete(t.entries, trackerID)
+		return uuid.Nil, false
+	}
+	return entry.driverID, true
+}
+
+// put запоминает водителя трекера
+func (t *trackerCache) put(trackerID string, driverID uuid.UUID, now time.Time) {
+	if t.ttl <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// Трекеры, от которых перестали приходить точки, удаляются при росте кэша
+	if len(t.entries) >= maxTrackerCacheEntries {
+		for id, entry := range t.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(t.entries, id)
+			}
+		}
+	}
+	t.entries[trackerID] = trackerEntry{driverID: driverID, expiresAt: now.Add(t.ttl)}
+}
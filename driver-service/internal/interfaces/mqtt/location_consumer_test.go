@@ -0,0 +1,169 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testMessage сообщение MQTT для обработчика
+type testMessage struct {
+	topic     string
+	payload   []byte
+	duplicate bool
+	retained  bool
+}
+
+func (m *testMessage) Duplicate() bool   { return m.duplicate }
+func (m *testMessage) Qos() byte         { return 1 }
+func (m *testMessage) Retained() bool    { return m.retained }
+func (m *testMessage) Topic() string     { return m.topic }
+func (m *testMessage) MessageID() uint16 { return 1 }
+func (m *testMessage) Payload() []byte   { return m.payload }
+func (m *testMessage) Ack()              {}
+
+// stubLocationService запоминает сохраненные точки; первые failures вызовов завершаются ошибкой
+type stubLocationService struct {
+	services.LocationService
+
+	mu        sync.Mutex
+	failures  int
+	locations []*entities.DriverLocation
+}
+
+func (s *stubLocationService) UpdateLocation(ctx context.Context, location *entities.DriverLocation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("connection refused")
+	}
+	s.locations = append(s.locations, location)
+	return nil
+}
+
+// stubDeviceService закрепляет трекеры за водителями и считает обращения
+type stubDeviceService struct {
+	services.DeviceService
+
+	trackers map[string]uuid.UUID
+	lookups  int
+}
+
+func (s *stubDeviceService) ResolveTracker(ctx context.Context, trackerID string) (*entities.DriverDevice, error) {
+	s.lookups++
+	driverID, ok := s.trackers[trackerID]
+	if !ok {
+		return nil, entities.ErrDeviceNotFound
+	}
+	return &entities.DriverDevice{DriverID: driverID, DeviceID: trackerID, Platform: entities.DevicePlatformTracker}, nil
+}
+
+func newTestConsumer(trackers map[string]uuid.UUID) (*LocationConsumer, *stubLocationService, *stubDeviceService) {
+	locationService := &stubLocationService{}
+	deviceService := &stubDeviceService{trackers: trackers}
+	consumer := NewLocationConsumer("driver-service-test", locationService, deviceService, config.MQTTConfig{
+		Topics:          []string{"fleet/+/gps"},
+		QoS:             1,
+		DedupWindow:     time.Minute,
+		DedupSize:       100,
+		TrackerCacheTTL: time.Minute,
+	}, zap.NewNop())
+	return consumer, locationService, deviceService
+}
+
+func TestLocationConsumer_Handle(t *testing.T) {
+	driverID := uuid.New()
+	consumer, locations, devices := newTestConsumer(map[string]uuid.UUID{"imei-1": driverID})
+
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`{"lat":55.75,"lon":37.61,"battery":64}`)})
+	require.Len(t, locations.locations, 1)
+	location := locations.locations[0]
+	assert.Equal(t, driverID, location.DriverID)
+	assert.Equal(t, "imei-1", location.Metadata[entities.LocationMetaDeviceID])
+	assert.Equal(t, entities.LocationSourceTracker, location.Metadata[entities.LocationMetaSource])
+	assert.Equal(t, 64, location.Metadata[entities.LocationMetaBatteryLevel])
+
+	// Повторная доставка QoS 1 отбрасывается, новая точка сохраняется без обращения к устройствам
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`{"lat":55.75,"lon":37.61,"battery":64}`), duplicate: true})
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`{"lat":55.76,"lon":37.62}`)})
+	assert.Len(t, locations.locations, 2)
+	assert.Equal(t, 1, devices.lookups)
+
+	// Незарегистрированный трекер, удержанное и неразобранное сообщения не сохраняются
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-2/gps", payload: []byte(`{"lat":55.75,"lon":37.61}`)})
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-2/gps", payload: []byte(`{"lat":55.77,"lon":37.61}`)})
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`{"lat":55.78,"lon":37.61}`), retained: true})
+	consumer.handle(nil, &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`$GPRMC,123519,V,,,,,,,100324,,`)})
+	assert.Len(t, locations.locations, 2)
+	assert.Equal(t, 2, devices.lookups, "unknown tracker is cached too")
+}
+
+func TestLocationConsumer_RetriesFailedMessage(t *testing.T) {
+	consumer, locations, _ := newTestConsumer(map[string]uuid.UUID{"imei-1": uuid.New()})
+	locations.failures = 1
+
+	// Сообщение, которое не удалось сохранить, не запоминается: повторная доставка сохраняет его
+	msg := &testMessage{topic: "fleet/imei-1/gps", payload: []byte(`{"lat":55.75,"lon":37.61}`)}
+	consumer.handle(nil, msg)
+	assert.Empty(t, locations.locations)
+
+	consumer.handle(nil, msg)
+	assert.Len(t, locations.locations, 1)
+}
+
+func TestTrackerFromTopic(t *testing.T) {
+	patterns := []string{"fleet/+/gps", "tenants/+/trackers/+/#"}
+
+	tests := []struct {
+		topic string
+		want  string
+		ok    bool
+	}{
+		{"fleet/imei-1/gps", "imei-1", true},
+		{"tenants/acme/trackers/t-9/nmea/raw", "acme", true},
+		{"fleet/imei-1/status", "", false},
+		{"fleet/imei-1", "", false},
+		{"fleet//gps", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			got, ok := trackerFromTopic(patterns, tt.topic)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDedupCache(t *testing.T) {
+	now := time.Now()
+	cache := newDedupCache(time.Minute, 2)
+
+	cache.remember(1, now)
+	assert.True(t, cache.seen(1, now.Add(30*time.Second)))
+	assert.False(t, cache.seen(1, now.Add(time.Minute)), "outside window")
+
+	// Сверх size вытесняются самые старые
+	cache.remember(2, now)
+	cache.remember(3, now)
+	assert.False(t, cache.seen(1, now))
+	assert.True(t, cache.seen(2, now))
+	assert.True(t, cache.seen(3, now))
+
+	// Вышедшие из окна удаляются при следующем запоминании
+	cache.remember(4, now.Add(2*time.Minute))
+	assert.Len(t, cache.order, 1)
+	assert.Len(t, cache.seenAt, 1)
+}
@@ -0,0 +1,342 @@
+package mqtt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// errInvalidPayload сообщение трекера не удалось разобрать
+var errInvalidPayload = errors.New("invalid tracker payload")
+
+// errNoFix трекер прислал точку без навигационного решения
+var errNoFix = errors.New("tracker has no GPS fix")
+
+// knotsToKmh перевод скорости NMEA из узлов в км/ч
+const knotsToKmh = 1.852
+
+// hdopAccuracyMeters оценка точности в метрах на единицу HDOP
+const hdopAccuracyMeters = 5.0
+
+// TrackerMessage точка от трекера в формате JSON
+type TrackerMessage struct {
+	Latitude  *float64 `json:"lat"`
+	Longitude *float64 `json:"lon"`
+	Altitude  *float64 `json:"alt,omitempty"`
+	// Speed скорость, км/ч
+	Speed *float64 `json:"speed,omitempty"`
+	// Course направление движения, градусы
+	Course *float64 `json:"course,omitempty"`
+	// Accuracy точность, метры
+	Accuracy *float64 `json:"accuracy,omitempty"`
+	// Battery заряд батареи трекера, проценты
+	Battery *float64 `json:"battery,omitempty"`
+	// Timestamp время фиксации точки, Unix; по умолчанию — время получения
+	Timestamp *int64 `json:"ts,omitempty"`
+}
+
+// trackerPoint точка трекера до привязки к водителю
+type trackerPoint struct {
+	latitude   float64
+	longitude  float64
+	altitude   *float64
+	speed      *float64
+	bearing    *float64
+	accuracy   *float64
+	battery    *float64
+	recordedAt time.Time
+}
+
+// location создает местоположение водителя из точки трекера
+func (p *trackerPoint) location(driverID uuid.UUID, trackerID string) (*entities.DriverLocation, error) {
+	location := entities.NewDriverLocation(driverID, p.latitude, p.longitude, p.recordedAt)
+	location.Altitude = p.altitude
+	location.Speed = p.speed
+	location.Bearing = p.bearing
+	location.Accuracy = p.accuracy
+	location.Metadata[entities.LocationMetaSource] = entities.LocationSourceTracker
+	location.Metadata[entities.LocationMetaProvider] = entities.LocationProviderGPS
+	if p.battery != nil {
+		location.Metadata[entities.LocationMetaBatteryLevel] = *p.battery
+	}
+	location.SetDevice(trackerID)
+
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
+	metadata, err := entities.NormalizeLocationMetadata(location.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	location.Metadata = metadata
+	return location, nil
+}
+
+// decodePayload разбирает сообщение трекера: JSON или предложения NMEA 0183 ($GPRMC, $GPGGA)
+func decodePayload(payload []byte, now time.Time) (*trackerPoint, error) {
+	payload = bytes.TrimSpace(payload)
+	switch {
+	case len(payload) == 0:
+		return nil, fmt.Errorf("%w: empty message", errInvalidPayload)
+	case payload[0] == '{':
+		return decodeJSON(payload, now)
+	case payload[0] == '$':
+		return decodeNMEA(string(payload), now)
+	default:
+		return nil, fmt.Errorf("%w: unknown format", errInvalidPayload)
+	}
+}
+
+// decodeJSON разбирает точку в формате TrackerMessage
+func decodeJSON(payload []byte, now time.Time) (*trackerPoint, error) {
+	var message TrackerMessage
+	if err := json.Unmarshal(payload, &message); err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidPayload, err)
+	}
+	if message.Latitude == nil || message.Longitude == nil {
+		return nil, fmt.Errorf("%w: lat and lon are required", errInvalidPayload)
+	}
+
+	point := &trackerPoint{
+		latitude:   *message.Latitude,
+		longitude:  *message.Longitude,
+		altitude:   message.Altitude,
+		speed:      message.Speed,
+		bearing:    message.Course,
+		accuracy:   message.Accuracy,
+		battery:    message.Battery,
+		recordedAt: now,
+	}
+	if message.Timestamp != nil {
+		point.recordedAt = time.Unix(*message.Timestamp, 0)
+	}
+	return point, nil
+}
+
+// decodeNMEA разбирает предложения NMEA, по одному на строку. Координаты, время, скорость
+// и курс берутся из RMC, высота и точность — из GGA; без RMC точка строится по GGA со
+// временем на дату получения. Остальные предложения пропускаются
+func decodeNMEA(payload string, now time.Time) (*trackerPoint, error) {
+	var rmc, gga *trackerPoint
+	for _, line := range strings.Split(payload, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields, err := nmeaFields(line)
+		if err != nil {
+			return nil, err
+		}
+		switch fields[0][len(fields[0])-3:] {
+		case "RMC":
+			if rmc, err = parseRMC(fields); err != nil {
+				return nil, err
+			}
+		case "GGA":
+			if gga, err = parseGGA(fields, now); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch {
+	case rmc != nil && gga != nil:
+		rmc.altitude = gga.altitude
+		rmc.accuracy = gga.accuracy
+		return rmc, nil
+	case rmc != nil:
+		return rmc, nil
+	case gga != nil:
+		return gga, nil
+	default:
+		return nil, fmt.Errorf("%w: no RMC or GGA sentence", errInvalidPayload)
+	}
+}
+
+// nmeaFields проверяет контрольную сумму предложения, если она есть, и разбивает его на поля
+func nmeaFields(sentence string) ([]string, error) {
+	body := strings.TrimPrefix(sentence, "$")
+	if i := strings.IndexByte(body, '*'); i >= 0 {
+		expected, err := strconv.ParseUint(body[i+1:], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed NMEA checksum", errInvalidPayload)
+		}
+		body = body[:i]
+
+		var checksum byte
+		for j := 0; j < len(body); j++ {
+			checksum ^= body[j]
+		}
+		if uint64(checksum) != expected {
+			return nil, fmt.Errorf("%w: NMEA checksum mismatch", errInvalidPayload)
+		}
+	}
+
+	fields := strings.Split(body, ",")
+	// Адрес предложения: двухбуквенный источник (GP, GN, GL...) и тип
+	if len(fields[0]) != 5 {
+		return nil, fmt.Errorf("%w: malformed NMEA sentence", errInvalidPayload)
+	}
+	return fields, nil
+}
+
+// parseRMC разбирает $xxRMC: время, статус, широта, долгота, скорость в узлах, курс, дата
+func parseRMC(fields []string) (*trackerPoint, error) {
+	if len(fields) < 10 {
+		return nil, fmt.Errorf("%w: short RMC sentence", errInvalidPayload)
+	}
+	if fields[2] != "A" {
+		return nil, errNoFix
+	}
+
+	recordedAt, err := time.Parse("020106150405", fields[9]+nmeaTime(fields[1]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid RMC date or time", errInvalidPayload)
+	}
+	point, err := nmeaPosition(fields[3], fields[4], fields[5], fields[6])
+	if err != nil {
+		return nil, err
+	}
+	point.recordedAt = recordedAt.Add(nmeaFraction(fields[1]))
+
+	if fields[7] != "" {
+		knots, err := strconv.ParseFloat(fields[7], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid RMC speed", errInvalidPayload)
+		}
+		speed := knots * knotsToKmh
+		point.speed = &speed
+	}
+	if fields[8] != "" {
+		course, err := strconv.ParseFloat(fields[8], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid RMC course", errInvalidPayload)
+		}
+		point.bearing = &course
+	}
+	return point, nil
+}
+
+// parseGGA разбирает $xxGGA: время, широта, долгота, качество решения, HDOP и высота.
+// В GGA нет даты: берется дата получения по UTC, с поправкой на переход через полночь
+func parseGGA(fields []string, now time.Time) (*trackerPoint, error) {
+	if len(fields) < 10 {
+		return nil, fmt.Errorf("%w: short GGA sentence", errInvalidPayload)
+	}
+	if fields[6] == "" || fields[6] == "0" {
+		return nil, errNoFix
+	}
+
+	clock, err := time.Parse("150405", nmeaTime(fields[1]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid GGA time", errInvalidPayload)
+	}
+	point, err := nmeaPosition(fields[2], fields[3], fields[4], fields[5])
+	if err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	recordedAt := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), clock.Second(), 0, time.UTC).
+		Add(nmeaFraction(fields[1]))
+	switch {
+	case recordedAt.Sub(now) > 12*time.Hour:
+		recordedAt = recordedAt.AddDate(0, 0, -1)
+	case now.Sub(recordedAt) > 12*time.Hour:
+		recordedAt = recordedAt.AddDate(0, 0, 1)
+	}
+	point.recordedAt = recordedAt
+
+	if fields[8] != "" {
+		hdop, err := strconv.ParseFloat(fields[8], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid GGA HDOP", errInvalidPayload)
+		}
+		accuracy := hdop * hdopAccuracyMeters
+		point.accuracy = &accuracy
+	}
+	if fields[9] != "" {
+		altitude, err := strconv.ParseFloat(fields[9], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid GGA altitude", errInvalidPayload)
+		}
+		point.altitude = &altitude
+	}
+	return point, nil
+}
+
+// nmeaPosition переводит координаты NMEA (ddmm.mmmm, dddmm.mmmm и полушарие) в градусы
+func nmeaPosition(lat, latHemisphere, lon, lonHemisphere string) (*trackerPoint, error) {
+	latitude, err := nmeaDegrees(lat, 2)
+	if err != nil {
+		return nil, err
+	}
+	longitude, err := nmeaDegrees(lon, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	switch latHemisphere {
+	case "N":
+	case "S":
+		latitude = -latitude
+	default:
+		return nil, fmt.Errorf("%w: invalid NMEA latitude hemisphere", errInvalidPayload)
+	}
+	switch lonHemisphere {
+	case "E":
+	case "W":
+		longitude = -longitude
+	default:
+		return nil, fmt.Errorf("%w: invalid NMEA longitude hemisphere", errInvalidPayload)
+	}
+
+	return &trackerPoint{latitude: latitude, longitude: longitude}, nil
+}
+
+// nmeaDegrees переводит значение в формате градусы и минуты с degreeDigits цифрами градусов
+func nmeaDegrees(value string, degreeDigits int) (float64, error) {
+	if len(value) < degreeDigits+2 {
+		return 0, fmt.Errorf("%w: invalid NMEA coordinate", errInvalidPayload)
+	}
+	degrees, err := strconv.Atoi(value[:degreeDigits])
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid NMEA coordinate", errInvalidPayload)
+	}
+	minutes, err := strconv.ParseFloat(value[degreeDigits:], 64)
+	if err != nil || minutes >= 60 {
+		return 0, fmt.Errorf("%w: invalid NMEA coordinate", errInvalidPayload)
+	}
+	return float64(degrees) + minutes/60, nil
+}
+
+// nmeaTime целые секунды времени NMEA (hhmmss.ss)
+func nmeaTime(value string) string {
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		return value[:i]
+	}
+	return value
+}
+
+// nmeaFraction дробная часть секунды времени NMEA
+func nmeaFraction(value string) time.Duration {
+	i := strings.IndexByte(value, '.')
+	if i < 0 {
+		return 0
+	}
+	fraction, err := strconv.ParseFloat("0"+value[i:], 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(math.Round(fraction * float64(time.Second)))
+}
@@ -0,0 +1,86 @@
+package mqtt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePayload_JSON(t *testing.T) {
+	now := time.Now()
+
+	point, err := decodePayload([]byte(`{"lat":55.7558,"lon":37.6173,"speed":42.5,"course":90,"battery":81,"ts":1700000000}`), now)
+	require.NoError(t, err)
+	assert.InDelta(t, 55.7558, point.latitude, 1e-9)
+	assert.InDelta(t, 37.6173, point.longitude, 1e-9)
+	assert.Equal(t, 42.5, *point.speed)
+	assert.Equal(t, 90.0, *point.bearing)
+	assert.Equal(t, time.Unix(1700000000, 0), point.recordedAt)
+
+	// Без ts точка получает время приема
+	point, err = decodePayload([]byte(`{"lat":0,"lon":0}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, now, point.recordedAt)
+
+	_, err = decodePayload([]byte(`{"lon":37.6}`), now)
+	assert.ErrorIs(t, err, errInvalidPayload)
+	_, err = decodePayload([]byte(`{"lat":"north"}`), now)
+	assert.ErrorIs(t, err, errInvalidPayload)
+	_, err = decodePayload([]byte(`garbage`), now)
+	assert.ErrorIs(t, err, errInvalidPayload)
+}
+
+func TestDecodePayload_NMEA(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 40, 0, 0, time.UTC)
+
+	rmc := "$GPRMC,123519.50,A,5545.348,N,03737.038,E,022.4,084.4,100324,003.1,W*4D"
+	gga := "$GPGGA,123519,5545.348,N,03737.038,E,1,08,0.9,545.4,M,46.9,M,,*40"
+
+	point, err := decodePayload([]byte(rmc+"\r\n"+gga), now)
+	require.NoError(t, err)
+	assert.InDelta(t, 55.7558, point.latitude, 1e-4)
+	assert.InDelta(t, 37.6173, point.longitude, 1e-4)
+	assert.Equal(t, time.Date(2024, 3, 10, 12, 35, 19, 500000000, time.UTC), point.recordedAt)
+	assert.InDelta(t, 22.4*knotsToKmh, *point.speed, 1e-9)
+	assert.Equal(t, 84.4, *point.bearing)
+	assert.Equal(t, 545.4, *point.altitude)
+	assert.InDelta(t, 4.5, *point.accuracy, 1e-9)
+
+	// GGA без RMC: дата берется из времени приема
+	point, err = decodePayload([]byte(gga), now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 12, 35, 19, 0, time.UTC), point.recordedAt)
+	assert.Nil(t, point.speed)
+
+	// Точка перед полуночью, принятая после нее, относится к предыдущим суткам
+	point, err = decodePayload([]byte("$GNGGA,235959,3345.000,S,07030.000,W,1,05,1.2,10.0,M,,M,,"), now.Add(11*time.Hour+30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC), point.recordedAt)
+	assert.InDelta(t, -33.75, point.latitude, 1e-9)
+	assert.InDelta(t, -70.5, point.longitude, 1e-9)
+}
+
+func TestDecodePayload_NMEARejected(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		payload string
+		want    error
+	}{
+		{"no fix", "$GPRMC,123519,V,5545.348,N,03737.038,E,0,0,100324,,", errNoFix},
+		{"GGA without fix", "$GPGGA,123519,5545.348,N,03737.038,E,0,00,,,M,,M,,", errNoFix},
+		{"checksum mismatch", "$GPRMC,123519.50,A,5545.348,N,03737.038,E,022.4,084.4,100324,003.1,W*00", errInvalidPayload},
+		{"bad hemisphere", "$GPRMC,123519,A,5545.348,X,03737.038,E,0,0,100324,,", errInvalidPayload},
+		{"only other sentences", "$GPGSA,A,3,04,05,,09,12,,,24,,,,,2.5,1.3,2.1", errInvalidPayload},
+		{"short sentence", "$GPRMC,123519,A", errInvalidPayload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodePayload([]byte(tt.payload), now)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
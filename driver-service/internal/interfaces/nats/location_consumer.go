@@ -0,0 +1,306 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// Заголовки сообщения, пересланного в dead letter
+const (
+	HeaderDeadLetterReason = "Dead-Letter-Reason"
+	HeaderOriginalSubject  = "Original-Subject"
+)
+
+// saveTimeout ограничение времени одной попытки сохранения пакета
+const saveTimeout = 10 * time.Second
+
+// drainTimeout сколько Stop ждет обработки полученных сообщений
+const drainTimeout = 10 * time.Second
+
+// errConsumerStopped сообщение получено, когда пакеты уже не принимаются
+var errConsumerStopped = errors.New("location consumer stopped")
+
+// LocationMessage точка GPS от мобильного шлюза
+type LocationMessage struct {
+	DriverID  uuid.UUID `json:"driver_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Altitude  *float64  `json:"altitude,omitempty"`
+	Accuracy  *float64  `json:"accuracy,omitempty"`
+	Speed     *float64  `json:"speed,omitempty"`
+	Bearing   *float64  `json:"bearing,omitempty"`
+	// Timestamp время записи точки, Unix; по умолчанию — время получения
+	Timestamp *int64            `json:"timestamp,omitempty"`
+	Metadata  entities.Metadata `json:"metadata,omitempty"`
+}
+
+// queuedLocation разобранная точка вместе с исходным сообщением для dead letter
+type queuedLocation struct {
+	msg      *nats.Msg
+	location *entities.DriverLocation
+}
+
+// LocationConsumer принимает местоположения из NATS в группе подписчиков и сохраняет их
+// пакетами через LocationService.BatchUpdateLocations. Пока пакет сохраняется, новые
+// сообщения ждут в буфере клиента NATS (max_pending); переполненный буфер отбрасывает
+// сообщения, их число логируется. Сообщения, которые не удалось разобрать или сохранить,
+// пересылаются в dead letter с причиной в заголовке
+type LocationConsumer struct {
+	conn            *nats.Conn
+	locationService services.LocationService
+	cfg             config.LocationConsumerConfig
+	logger          *zap.Logger
+
+	// publish отправляет сообщения в dead letter
+	publish func(msg *nats.Msg) error
+	sub     *nats.Subscription
+	closed  chan struct{}
+	queue   chan *queuedLocation
+	stop    chan struct{}
+	done    chan struct{}
+	// dropped сколько сообщений клиент NATS отбросил к последней проверке
+	dropped int
+}
+
+// NewLocationConsumer создает нового подписчика на местоположения
+func NewLocationConsumer(conn *nats.Conn, locationService services.LocationService, cfg config.LocationConsumerConfig, logger *zap.Logger) *LocationConsumer {
+	return &LocationConsumer{
+		conn:            conn,
+		locationService: locationService,
+		cfg:             cfg,
+		logger:          logger,
+		publish:         conn.PublishMsg,
+		closed:          make(chan struct{}),
+		queue:           make(chan *queuedLocation, cfg.BatchSize),
+		stop:            make(chan struct{}),
+	}
+}
+
+// Start подписывается на местоположения и запускает сохранение пакетов
+func (c *LocationConsumer) Start() error {
+	sub, err := c.conn.QueueSubscribe(c.cfg.Subject, c.cfg.QueueGroup, c.handle)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", c.cfg.Subject, err)
+	}
+	if err := sub.SetPendingLimits(c.cfg.MaxPending, nats.DefaultSubPendingBytesLimit); err != nil {
+		_ = sub.Unsubscribe()
+		return fmt.Errorf("failed to set pending limits for %s: %w", c.cfg.Subject, err)
+	}
+	sub.SetClosedHandler(func(string) { close(c.closed) })
+	c.sub = sub
+
+	c.startBatching()
+	c.logger.Info("Location consumer started",
+		zap.String("subject", c.cfg.Subject),
+		zap.String("queue_group", c.cfg.QueueGroup),
+	)
+	return nil
+}
+
+// Stop отписывается от местоположений, дожидаясь обработки полученных сообщений, и
+// сохраняет последний пакет
+func (c *LocationConsumer) Stop() {
+	if c.done == nil {
+		return
+	}
+
+	if c.sub != nil {
+		if err := c.sub.Drain(); err != nil {
+			c.logger.Error("Failed to drain location subscription", zap.Error(err))
+		}
+		select {
+		case <-c.closed:
+		case <-time.After(drainTimeout):
+			c.logger.Warn("Location subscription drain timed out")
+		}
+	}
+
+	close(c.stop)
+	<-c.done
+	c.done = nil
+}
+
+// handle разбирает сообщение и ставит точку в очередь пакета. Пока очередь заполнена,
+// обработчик ждет, и сообщения накапливаются в буфере клиента NATS
+func (c *LocationConsumer) handle(msg *nats.Msg) {
+	location, err := decodeLocation(msg.Data)
+	if err != nil {
+		c.deadLetter([]*nats.Msg{msg}, err)
+		return
+	}
+
+	select {
+	case <-c.stop:
+		c.deadLetter([]*nats.Msg{msg}, errConsumerStopped)
+	case c.queue <- &queuedLocation{msg: msg, location: location}:
+	}
+}
+
+// startBatching собирает пакеты из очереди: пакет сохраняется, когда набрано batch_size
+// точек или прошел flush_interval
+func (c *LocationConsumer) startBatching() {
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.cfg.FlushInterval)
+		defer ticker.Stop()
+
+		batch := make([]*queuedLocation, 0, c.cfg.BatchSize)
+		for {
+			select {
+			case item := <-c.queue:
+				batch = append(batch, item)
+				if len(batch) < c.cfg.BatchSize {
+					continue
+				}
+			case <-ticker.C:
+			case <-c.stop:
+				// Сохраняем точки, уже попавшие в очередь
+				for {
+					select {
+					case item := <-c.queue:
+						batch = append(batch, item)
+					default:
+						c.flush(batch)
+						return
+					}
+				}
+			}
+
+			c.flush(batch)
+			batch = make([]*queuedLocation, 0, c.cfg.BatchSize)
+		}
+	}()
+}
+
+// flush сохраняет пакет. Отклоненные точки пакета попадают в dead letter по одной, пакет
+// целиком — только если его не удалось сохранить после всех повторов
+func (c *LocationConsumer) flush(batch []*queuedLocation) {
+	c.reportDropped()
+	if len(batch) == 0 {
+		return
+	}
+
+	result, err := c.save(batch)
+	if err != nil {
+		c.deadLetter(messages(batch), err)
+		return
+	}
+
+	for _, rejection := range result.Rejected {
+		c.deadLetter([]*nats.Msg{batch[rejection.Index].msg}, rejection.Err)
+	}
+}
+
+// save сохраняет точки, повторяя попытку при ошибке хранилища до max_retries раз
+func (c *LocationConsumer) save(batch []*queuedLocation) (*entities.LocationBatchResult, error) {
+	locations := make([]*entities.DriverLocation, len(batch))
+	for i, item := range batch {
+		locations[i] = item.location
+	}
+
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), saveTimeout)
+		result, err := c.locationService.BatchUpdateLocations(ctx, locations)
+		cancel()
+		if err == nil || attempt >= c.cfg.MaxRetries {
+			return result, err
+		}
+
+		c.logger.Warn("Failed to save location batch, retrying",
+			zap.Error(err),
+			zap.Int("count", len(locations)),
+			zap.Int("attempt", attempt+1),
+		)
+		time.Sleep(c.cfg.RetryBackoff * time.Duration(attempt+1))
+	}
+}
+
+// deadLetter пересылает сообщения в dead letter с причиной отказа
+func (c *LocationConsumer) deadLetter(msgs []*nats.Msg, reason error) {
+	c.logger.Warn("Location messages rejected",
+		zap.Error(reason),
+		zap.Int("count", len(msgs)),
+		zap.String("dead_letter_subject", c.cfg.DeadLetterSubject),
+	)
+	if c.cfg.DeadLetterSubject == "" {
+		return
+	}
+
+	for _, msg := range msgs {
+		dead := nats.NewMsg(c.cfg.DeadLetterSubject)
+		dead.Data = msg.Data
+		dead.Header.Set(HeaderDeadLetterReason, reason.Error())
+		dead.Header.Set(HeaderOriginalSubject, msg.Subject)
+		if err := c.publish(dead); err != nil {
+			c.logger.Error("Failed to publish location dead letter", zap.Error(err))
+		}
+	}
+}
+
+// reportDropped логирует сообщения, отброшенные клиентом NATS при переполнении буфера
+func (c *LocationConsumer) reportDropped() {
+	if c.sub == nil {
+		return
+	}
+	dropped, err := c.sub.Dropped()
+	if err != nil || dropped <= c.dropped {
+		return
+	}
+
+	c.logger.Warn("Location messages dropped by slow consumer",
+		zap.Int("dropped", dropped-c.dropped),
+		zap.Int("max_pending", c.cfg.MaxPending),
+	)
+	c.dropped = dropped
+}
+
+// decodeLocation разбирает и проверяет точку из сообщения
+func decodeLocation(data []byte) (*entities.DriverLocation, error) {
+	var message LocationMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return nil, fmt.Errorf("invalid location message: %w", err)
+	}
+	if message.DriverID == uuid.Nil {
+		return nil, entities.ErrDriverNotFound
+	}
+
+	recordedAt := time.Now()
+	if message.Timestamp != nil {
+		recordedAt = time.Unix(*message.Timestamp, 0)
+	}
+
+	location := entities.NewDriverLocation(message.DriverID, message.Latitude, message.Longitude, recordedAt)
+	location.Altitude = message.Altitude
+	location.Accuracy = message.Accuracy
+	location.Speed = message.Speed
+	location.Bearing = message.Bearing
+	if message.Metadata != nil {
+		location.Metadata = message.Metadata
+	}
+	if err := location.Validate(); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+// messages исходные сообщения пакета
+func messages(batch []*queuedLocation) []*nats.Msg {
+	msgs := make([]*nats.Msg, len(batch))
+	for i, item := range batch {
+		msgs[i] = item.msg
+	}
+	return msgs
+}
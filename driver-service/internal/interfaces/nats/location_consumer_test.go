@@ -0,0 +1,158 @@
+package nats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+
+	"github.com/google/uuid"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// stubLocationService запоминает сохраненные пакеты; водители из unknown не найдены,
+// первые failures вызовов завершаются ошибкой хранилища
+type stubLocationService struct {
+	services.LocationService
+
+	mu       sync.Mutex
+	unknown  map[uuid.UUID]bool
+	failures int
+	calls    int
+	batches  [][]*entities.DriverLocation
+}
+
+func (s *stubLocationService) BatchUpdateLocations(ctx context.Context, locations []*entities.DriverLocation) (*entities.LocationBatchResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+	if s.failures > 0 {
+		s.failures--
+		return nil, errors.New("connection refused")
+	}
+	result := entities.NewLocationBatchResult(len(locations))
+	saved := make([]*entities.DriverLocation, 0, len(locations))
+	for i, location := range locations {
+		if s.unknown[location.DriverID] {
+			result.Reject(i, entities.ErrDriverNotFound)
+			continue
+		}
+		saved = append(saved, location)
+	}
+	result.Accepted = len(saved)
+	s.batches = append(s.batches, saved)
+	return result, nil
+}
+
+// recordingPublisher запоминает сообщения, отправленные в dead letter
+type recordingPublisher struct {
+	mu   sync.Mutex
+	msgs []*nats.Msg
+}
+
+func (p *recordingPublisher) publish(msg *nats.Msg) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.msgs = append(p.msgs, msg)
+	return nil
+}
+
+func newTestConsumer(service *stubLocationService, batchSize, maxRetries int) (*LocationConsumer, *recordingPublisher) {
+	consumer := NewLocationConsumer(nil, service, config.LocationConsumerConfig{
+		Subject:           "drivers.locations",
+		DeadLetterSubject: "drivers.locations.dead",
+		BatchSize:         batchSize,
+		FlushInterval:     time.Hour,
+		MaxPending:        100,
+		MaxRetries:        maxRetries,
+	}, zap.NewNop())
+	publisher := &recordingPublisher{}
+	consumer.publish = publisher.publish
+	consumer.startBatching()
+	return consumer, publisher
+}
+
+func locationMsg(t *testing.T, driverID uuid.UUID, latitude float64) *nats.Msg {
+	data, err := json.Marshal(&LocationMessage{DriverID: driverID, Latitude: latitude, Longitude: 37.61})
+	require.NoError(t, err)
+	return &nats.Msg{Subject: "drivers.locations", Data: data}
+}
+
+func TestLocationConsumer_Batches(t *testing.T) {
+	service := &stubLocationService{}
+	consumer, publisher := newTestConsumer(service, 2, 0)
+
+	driverID := uuid.New()
+	for _, latitude := range []float64{55.75, 55.76, 55.77} {
+		consumer.handle(locationMsg(t, driverID, latitude))
+	}
+	consumer.Stop()
+
+	require.Len(t, service.batches, 2)
+	assert.Len(t, service.batches[0], 2)
+	assert.Len(t, service.batches[1], 1, "the last batch is saved on stop")
+	assert.Equal(t, 55.77, service.batches[1][0].Latitude)
+	assert.Empty(t, publisher.msgs)
+}
+
+func TestLocationConsumer_DeadLetter(t *testing.T) {
+	unknown := uuid.New()
+	service := &stubLocationService{unknown: map[uuid.UUID]bool{unknown: true}}
+	consumer, publisher := newTestConsumer(service, 3, 0)
+
+	consumer.handle(&nats.Msg{Subject: "drivers.locations", Data: []byte("not json")})
+	consumer.handle(locationMsg(t, uuid.New(), 95))
+	known := uuid.New()
+	consumer.handle(locationMsg(t, known, 55.75))
+	consumer.handle(locationMsg(t, unknown, 55.76))
+	consumer.handle(locationMsg(t, known, 55.77))
+	consumer.Stop()
+
+	// Точка неизвестного водителя не мешает сохранить остальные точки пакета
+	require.Len(t, service.batches, 1)
+	require.Len(t, service.batches[0], 2)
+	for _, location := range service.batches[0] {
+		assert.Equal(t, known, location.DriverID)
+	}
+
+	require.Len(t, publisher.msgs, 3)
+	for _, msg := range publisher.msgs {
+		assert.Equal(t, "drivers.locations.dead", msg.Subject)
+		assert.Equal(t, "drivers.locations", msg.Header.Get(HeaderOriginalSubject))
+	}
+	assert.Contains(t, publisher.msgs[0].Header.Get(HeaderDeadLetterReason), "invalid location message")
+	assert.Equal(t, entities.ErrInvalidLocation.Error(), publisher.msgs[1].Header.Get(HeaderDeadLetterReason))
+	assert.Equal(t, entities.ErrDriverNotFound.Error(), publisher.msgs[2].Header.Get(HeaderDeadLetterReason))
+}
+
+func TestLocationConsumer_Retries(t *testing.T) {
+	service := &stubLocationService{failures: 2}
+	consumer, publisher := newTestConsumer(service, 1, 2)
+	consumer.handle(locationMsg(t, uuid.New(), 55.75))
+	consumer.Stop()
+
+	assert.Equal(t, 3, service.calls)
+	assert.Len(t, service.batches, 1)
+	assert.Empty(t, publisher.msgs)
+
+	// Повторы исчерпаны: пакет уходит в dead letter
+	service = &stubLocationService{failures: 3}
+	consumer, publisher = newTestConsumer(service, 1, 2)
+	consumer.handle(locationMsg(t, uuid.New(), 55.75))
+	consumer.Stop()
+
+	assert.Equal(t, 3, service.calls)
+	assert.Empty(t, service.batches)
+	require.Len(t, publisher.msgs, 1)
+	assert.Equal(t, "connection refused", publisher.msgs[0].Header.Get(HeaderDeadLetterReason))
+}
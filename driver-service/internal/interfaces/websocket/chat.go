@@ -0,0 +1,224 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/interfaces/http/handlers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Типы кадров переписки
+const (
+	// MessageTypeChatMessage новое сообщение переписки водителя
+	MessageTypeChatMessage = "chat_message"
+	// MessageTypeChatReceipt сообщение отмечено доставленным или прочитанным
+	MessageTypeChatReceipt = "chat_receipt"
+	// MessageTypeChatError ошибка обработки кадра клиента
+	MessageTypeChatError = "chat_error"
+
+	// chatRequestSend и chatRequestReceipt типы входящих кадров
+	chatRequestSend    = "send"
+	chatRequestReceipt = "receipt"
+)
+
+// maxChatFrameSize максимальный размер входящего кадра участника переписки
+const maxChatFrameSize = 4096
+
+// chatRequestTimeout время на обработку одного входящего кадра
+const chatRequestTimeout = 10 * time.Second
+
+// ChatFrame кадр переписки, отправляемый участнику
+type ChatFrame struct {
+	Type    string                  `json:"type"`
+	Message *entities.DriverMessage `json:"message,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+	Code    string                  `json:"code,omitempty"`
+	Details string                  `json:"details,omitempty"`
+}
+
+// chatRequest входящий кадр участника: отправка сообщения (поля SendMessageRequest)
+// или отметка (поля MessageReceiptRequest)
+type chatRequest struct {
+	Type string `json:"type"`
+	entities.SendMessageRequest
+	entities.MessageReceiptRequest
+}
+
+// chatSession переписка водителя, к которой подключен клиент, и сторона клиента в ней
+type chatSession struct {
+	driverID uuid.UUID
+	party    entities.MessageSender
+	subject  string
+	service  services.MessageService
+	logger   *zap.Logger
+}
+
+// handle обрабатывает кадр клиента; об ошибке сообщается только отправителю кадра.
+// Результат успешной отправки или отметки клиент получает общей рассылкой
+func (s *chatSession) handle(c *client, data []byte) {
+	var req chatRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		s.fail(c, "Invalid frame", "INVALID_FRAME", err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), chatRequestTimeout)
+	defer cancel()
+
+	var err error
+	switch req.Type {
+	case chatRequestSend:
+		req.SendMessageRequest.Sender = s.party
+		_, err = s.service.SendMessage(ctx, s.driverID, s.subject, &req.SendMessageRequest)
+	case chatRequestReceipt:
+		req.MessageReceiptRequest.Reader = s.party
+		_, err = s.service.AcknowledgeMessages(ctx, s.driverID, &req.MessageReceiptRequest)
+	default:
+		s.fail(c, "Invalid frame", "INVALID_FRAME", "type must be send or receipt")
+		return
+	}
+
+	switch err {
+	case nil:
+	case entities.ErrDriverNotFound:
+		s.fail(c, "Driver not found", "DRIVER_NOT_FOUND", "")
+	case entities.ErrMessageNotFound:
+		s.fail(c, "Message not found", "MESSAGE_NOT_FOUND", "")
+	case entities.ErrInvalidMessage:
+		s.fail(c, "Invalid message", "INVALID_MESSAGE", handlers.MessageRules)
+	case entities.ErrInvalidMessageReceipt:
+		s.fail(c, "Invalid message receipt", "INVALID_MESSAGE_RECEIPT", handlers.ReceiptRules)
+	default:
+		s.logger.Error("Failed to handle chat frame",
+			zap.Error(err),
+			zap.String("driver_id", s.driverID.String()),
+			zap.String("type", req.Type),
+		)
+		s.fail(c, "Internal server error", "INTERNAL_ERROR", "")
+	}
+}
+
+// fail отправляет клиенту кадр с ошибкой
+func (s *chatSession) fail(c *client, message, code, details string) {
+	payload, err := json.Marshal(ChatFrame{Type: MessageTypeChatError, Error: message, Code: code, Details: details})
+	if err != nil {
+		s.logger.Error("Failed to marshal chat error", zap.Error(err))
+		return
+	}
+	c.reply(payload)
+}
+
+// BroadcastMessage отправляет новое сообщение участникам переписки водителя
+func (h *Hub) BroadcastMessage(message *entities.DriverMessage) {
+	h.broadcastChat(MessageTypeChatMessage, message)
+}
+
+// BroadcastReceipt отправляет участникам переписки сообщение с новой отметкой
+func (h *Hub) BroadcastReceipt(message *entities.DriverMessage) {
+	h.broadcastChat(MessageTypeChatReceipt, message)
+}
+
+// broadcastChat рассылает кадр переписки клиентам, подключенным к переписке водителя
+func (h *Hub) broadcastChat(frameType string, message *entities.DriverMessage) {
+	var (
+		payload []byte
+		slow    []*client
+	)
+
+	h.mu.RLock()
+	for c := range h.clients {
+		if c.chat == nil || c.chat.driverID != message.DriverID {
+			continue
+		}
+
+		if payload == nil {
+			data, err := json.Marshal(ChatFrame{Type: frameType, Message: message})
+			if err != nil {
+				h.mu.RUnlock()
+				h.logger.Error("Failed to marshal chat frame", zap.Error(err))
+				return
+			}
+			payload = data
+		}
+
+		select {
+		case c.send <- payload:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	h.evict(slow)
+}
+
+// ChatHandler обработчик WebSocket подключений к переписке водителя с диспетчерами
+type ChatHandler struct {
+	hub            *Hub
+	messageService services.MessageService
+	upgrader       gorilla.Upgrader
+	logger         *zap.Logger
+}
+
+// NewChatHandler создает новый ChatHandler
+func NewChatHandler(hub *Hub, messageService services.MessageService, logger *zap.Logger) *ChatHandler {
+	return &ChatHandler{
+		hub:            hub,
+		messageService: messageService,
+		upgrader:       newUpgrader(),
+		logger:         logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршрут подключения к переписке
+func (h *ChatHandler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/ws/drivers/:id/messages", h.JoinChat)
+}
+
+// JoinChat подключает клиента к переписке водителя: клиент получает новые сообщения и
+// отметки всех переписок водителя и может отправлять кадры send и receipt. Сторона
+// определяется по токену, без аутентификации — параметром sender
+func (h *ChatHandler) JoinChat(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error: "Invalid driver ID format",
+		})
+		return
+	}
+
+	party, subject := handlers.MessageParty(c, driverID, entities.MessageSender(c.Query("sender")))
+	if !party.IsValid() {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error: "Parameter 'sender' must be driver or dispatcher",
+			Code:  "INVALID_MESSAGE",
+		})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader уже отправил ответ с ошибкой
+		h.logger.Warn("Failed to upgrade websocket connection", zap.Error(err))
+		return
+	}
+
+	client := newClient(h.hub, conn, nil)
+	client.chat = &chatSession{
+		driverID: driverID,
+		party:    party,
+		subject:  subject,
+		service:  h.messageService,
+		logger:   h.logger,
+	}
+	start(h.hub, client, h.logger)
+}
@@ -0,0 +1,149 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// nopEventPublisher отбрасывает события
+type nopEventPublisher struct{}
+
+func (nopEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	return nil
+}
+
+// newChatServer поднимает сервер переписки с водителем и возвращает функцию подключения
+func newChatServer(t *testing.T, hub *Hub) (uuid.UUID, func(sender string) *gorilla.Conn) {
+	driverRepo := memory.NewDriverRepository()
+	driver := entities.NewDriver("+79000000301", "chat@example.com", "Иван", "Чат", "LICCHAT")
+	require.NoError(t, driverRepo.Create(context.Background(), driver))
+	messageService := services.NewMessageService(memory.NewMessageRepository(), driverRepo, nopEventPublisher{}, hub,
+		services.MessagePolicy{}, zap.NewNop())
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	NewChatHandler(hub, messageService, zap.NewNop()).RegisterRoutes(router.Group("/api/v1"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	dial := func(sender string) *gorilla.Conn {
+		clients := hub.ClientCount()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws/drivers/" + driver.ID.String() + "/messages?sender=" + sender
+		conn, _, err := gorilla.DefaultDialer.Dial(wsURL, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { conn.Close() })
+
+		require.Eventually(t, func() bool { return hub.ClientCount() > clients }, time.Second, 10*time.Millisecond)
+		return conn
+	}
+	return driver.ID, dial
+}
+
+func readChatFrame(t *testing.T, conn *gorilla.Conn) ChatFrame {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var frame ChatFrame
+	require.NoError(t, json.Unmarshal(data, &frame))
+	return frame
+}
+
+func TestChat_SendAndReceipt(t *testing.T) {
+	hub := newTestHub(8)
+	defer hub.Close()
+
+	driverID, dial := newChatServer(t, hub)
+	dispatcher := dial("dispatcher")
+	driver := dial("driver")
+
+	orderID := uuid.New()
+	require.NoError(t, driver.WriteJSON(map[string]interface{}{
+		"type":          "send",
+		"order_id":      orderID,
+		"kind":          "delayed",
+		"delay_minutes": 5,
+	}))
+
+	// Сообщение получают все участники переписки, включая отправителя
+	for _, conn := range []*gorilla.Conn{dispatcher, driver} {
+		frame := readChatFrame(t, conn)
+		assert.Equal(t, MessageTypeChatMessage, frame.Type)
+		require.NotNil(t, frame.Message)
+		assert.Equal(t, driverID, frame.Message.DriverID)
+		assert.Equal(t, entities.MessageSenderDriver, frame.Message.Sender)
+		assert.Equal(t, &orderID, frame.Message.OrderID)
+	}
+	require.NoError(t, dispatcher.WriteJSON(map[string]interface{}{
+		"type": "send",
+		"kind": "text",
+		"text": "Принято",
+	}))
+	reply := readChatFrame(t, driver)
+	require.Equal(t, MessageTypeChatMessage, reply.Type)
+	assert.Equal(t, entities.MessageSenderDispatcher, reply.Message.Sender)
+	assert.Nil(t, reply.Message.OrderID)
+	readChatFrame(t, dispatcher)
+
+	// Прочтение подразумевает доставку; отметку видят обе стороны
+	require.NoError(t, driver.WriteJSON(map[string]interface{}{
+		"type":        "receipt",
+		"message_ids": []uuid.UUID{reply.Message.ID},
+		"status":      "read",
+	}))
+	for _, conn := range []*gorilla.Conn{dispatcher, driver} {
+		frame := readChatFrame(t, conn)
+		assert.Equal(t, MessageTypeChatReceipt, frame.Type)
+		assert.Equal(t, reply.Message.ID, frame.Message.ID)
+		assert.NotNil(t, frame.Message.ReadAt)
+		assert.NotNil(t, frame.Message.DeliveredAt)
+	}
+}
+
+func TestChat_InvalidFrames(t *testing.T) {
+	hub := newTestHub(8)
+	defer hub.Close()
+
+	_, dial := newChatServer(t, hub)
+	driver := dial("driver")
+	dispatcher := dial("dispatcher")
+
+	require.NoError(t, driver.WriteMessage(gorilla.TextMessage, []byte("not json")))
+	frame := readChatFrame(t, driver)
+	assert.Equal(t, MessageTypeChatError, frame.Type)
+	assert.Equal(t, "INVALID_FRAME", frame.Code)
+
+	require.NoError(t, driver.WriteJSON(map[string]interface{}{"type": "send", "kind": "delayed"}))
+	frame = readChatFrame(t, driver)
+	assert.Equal(t, "INVALID_MESSAGE", frame.Code)
+
+	require.NoError(t, driver.WriteJSON(map[string]interface{}{
+		"type":        "receipt",
+		"message_ids": []uuid.UUID{uuid.New()},
+		"status":      "read",
+	}))
+	frame = readChatFrame(t, driver)
+	assert.Equal(t, "MESSAGE_NOT_FOUND", frame.Code)
+
+	// Ошибки приходят только отправителю кадра
+	dispatcher.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	_, _, err := dispatcher.ReadMessage()
+	assert.Error(t, err)
+}
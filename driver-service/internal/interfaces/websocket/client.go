@@ -0,0 +1,126 @@
+package websocket
+
+import (
+	"sync"
+	"time"
+
+	gorilla "github.com/gorilla/websocket"
+)
+
+// maxIncomingMessageSize подписчики на местоположения ничего не отправляют, кроме управляющих кадров
+const maxIncomingMessageSize = 512
+
+// client подключение одного подписчика
+type client struct {
+	hub          *Hub
+	conn         *gorilla.Conn
+	subscription *Subscription
+	// precise клиенту доступны точные координаты; решение принимается при подписке
+	precise bool
+	// chat переписка, к которой подключен клиент; nil для подписчиков на местоположения
+	chat       *chatSession
+	remoteAddr string
+
+	// send буфер исходящих сообщений; никогда не закрывается,
+	// завершение сигнализируется через done
+	send chan []byte
+
+	closeOnce   sync.Once
+	done        chan struct{}
+	closeCode   int
+	closeReason string
+}
+
+// newClient создает клиента для установленного соединения
+func newClient(hub *Hub, conn *gorilla.Conn, subscription *Subscription) *client {
+	bufferSize := hub.config.SendBuffer
+	if bufferSize <= 0 {
+		bufferSize = 1
+	}
+
+	return &client{
+		hub:          hub,
+		conn:         conn,
+		subscription: subscription,
+		remoteAddr:   conn.RemoteAddr().String(),
+		send:         make(chan []byte, bufferSize),
+		done:         make(chan struct{}),
+	}
+}
+
+// close инициирует закрытие соединения с указанным кодом; повторные вызовы игнорируются
+func (c *client) close(code int, reason string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeReason = reason
+		close(c.done)
+	})
+}
+
+// writePump отправляет сообщения из буфера и пинги; единственный писатель в соединение
+func (c *client) writePump() {
+	ticker := time.NewTicker(c.hub.config.PingInterval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+
+	for {
+		select {
+		case message := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(c.hub.config.WriteTimeout))
+			if err := c.conn.WriteMessage(gorilla.TextMessage, message); err != nil {
+				c.hub.remove(c, gorilla.CloseAbnormalClosure, "write failed")
+				return
+			}
+
+		case <-ticker.C:
+			deadline := time.Now().Add(c.hub.config.WriteTimeout)
+			if err := c.conn.WriteControl(gorilla.PingMessage, nil, deadline); err != nil {
+				c.hub.remove(c, gorilla.CloseAbnormalClosure, "ping failed")
+				return
+			}
+
+		case <-c.done:
+			deadline := time.Now().Add(c.hub.config.WriteTimeout)
+			c.conn.WriteControl(gorilla.CloseMessage, gorilla.FormatCloseMessage(c.closeCode, c.closeReason), deadline)
+			return
+		}
+	}
+}
+
+// readPump читает входящие кадры, чтобы обрабатывать pong и закрытие соединения клиентом.
+// Кадры участника переписки передаются в chatSession
+func (c *client) readPump() {
+	pongWait := c.hub.config.PingInterval + c.hub.config.WriteTimeout
+
+	readLimit := int64(maxIncomingMessageSize)
+	if c.chat != nil {
+		readLimit = maxChatFrameSize
+	}
+	c.conn.SetReadLimit(readLimit)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			c.hub.remove(c, gorilla.CloseNormalClosure, "")
+			return
+		}
+		if c.chat != nil {
+			c.chat.handle(c, data)
+		}
+	}
+}
+
+// reply отправляет кадр только этому клиенту; клиент с переполненным буфером отключается
+func (c *client) reply(payload []byte) {
+	select {
+	case c.send <- payload:
+	default:
+		c.hub.evict([]*client{c})
+	}
+}
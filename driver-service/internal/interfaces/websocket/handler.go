@@ -0,0 +1,103 @@
+package websocket
+
+import (
+	"errors"
+	"net/http"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/http/handlers"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// Handler обработчик WebSocket подписок на обновления местоположения
+type Handler struct {
+	hub      *Hub
+	upgrader gorilla.Upgrader
+	logger   *zap.Logger
+}
+
+// NewHandler создает новый Handler
+func NewHandler(hub *Hub, logger *zap.Logger) *Handler {
+	return &Handler{
+		hub:      hub,
+		upgrader: newUpgrader(),
+		logger:   logger,
+	}
+}
+
+// newUpgrader создает Upgrader для WebSocket обработчиков
+func newUpgrader() gorilla.Upgrader {
+	return gorilla.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		// Источники запросов ограничиваются так же, как для REST API (CORS middleware)
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+}
+
+// RegisterRoutes регистрирует маршруты WebSocket подписок
+func (h *Handler) RegisterRoutes(api *gin.RouterGroup) {
+	api.GET("/ws/locations", h.SubscribeLocations)
+}
+
+// SubscribeLocations устанавливает WebSocket соединение и подписывает клиента
+// на обновления местоположения водителя или области
+func (h *Handler) SubscribeLocations(c *gin.Context) {
+	subscription, err := ParseSubscription(c.Request.URL.Query())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, handlers.ErrorResponse{
+			Error:   "Invalid subscription",
+			Code:    "INVALID_SUBSCRIPTION",
+			Details: err.Error(),
+		})
+		return
+	}
+
+	conn, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// Upgrader уже отправил ответ с ошибкой
+		h.logger.Warn("Failed to upgrade websocket connection", zap.Error(err))
+		return
+	}
+
+	client := newClient(h.hub, conn, subscription)
+	client.precise = preciseLocationAllowed(c, subscription)
+	start(h.hub, client, h.logger)
+}
+
+// preciseLocationAllowed сообщает, получает ли подписчик точные координаты: их видят
+// вызывающие с областью entities.ScopeLocationReadPrecise и водитель, подписанный на себя.
+// Без аутентификации координаты не огрубляются, как в HTTP API
+func preciseLocationAllowed(c *gin.Context, subscription *Subscription) bool {
+	claims, ok := middleware.ClaimsFromContext(c)
+	if !ok || claims.HasScope(entities.ScopeLocationReadPrecise) {
+		return true
+	}
+	return subscription.DriverID != nil && claims.IsDriver(subscription.DriverID.String())
+}
+
+// start регистрирует клиента в хабе и запускает обработку соединения. Если хаб не принимает
+// клиента, соединение закрывается с кодом причины
+func start(hub *Hub, client *client, logger *zap.Logger) {
+	if err := hub.register(client); err != nil {
+		code := gorilla.CloseTryAgainLater
+		if errors.Is(err, ErrHubClosed) {
+			code = gorilla.CloseGoingAway
+		}
+		client.close(code, err.Error())
+		go client.writePump()
+		return
+	}
+
+	logger.Debug("Websocket client subscribed",
+		zap.String("remote_addr", client.remoteAddr),
+		zap.Int("clients", hub.ClientCount()),
+	)
+
+	go client.writePump()
+	go client.readPump()
+}
@@ -0,0 +1,210 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+
+	gorilla "github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// MessageTypeLocation тип сообщения с обновлением местоположения
+const MessageTypeLocation = "location"
+
+// drainPollInterval как часто Drain проверяет, отправлены ли буферы клиентов
+const drainPollInterval = 10 * time.Millisecond
+
+var (
+	// ErrHubClosed хаб остановлен и не принимает новых подписчиков
+	ErrHubClosed = errors.New("websocket hub is closed")
+	// ErrTooManyConnections достигнут лимит одновременных подключений
+	ErrTooManyConnections = errors.New("too many websocket connections")
+)
+
+// Message сообщение, отправляемое подписчику
+type Message struct {
+	Type     string                     `json:"type"`
+	Location *entities.LocationResponse `json:"location"`
+}
+
+// Hub рассылает обновления местоположения и сообщения переписки подписанным WebSocket
+// клиентам. Реализует services.LocationBroadcaster и services.MessageBroadcaster
+type Hub struct {
+	config config.WebSocketConfig
+	logger *zap.Logger
+
+	mu      sync.RWMutex
+	clients map[*client]struct{}
+	closed  bool
+}
+
+// NewHub создает новый Hub
+func NewHub(cfg config.WebSocketConfig, logger *zap.Logger) *Hub {
+	if cfg.WriteTimeout <= 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	if cfg.PingInterval <= 0 {
+		cfg.PingInterval = 30 * time.Second
+	}
+
+	return &Hub{
+		config:  cfg,
+		logger:  logger,
+		clients: make(map[*client]struct{}),
+	}
+}
+
+// BroadcastLocation отправляет обновление всем клиентам, чья подписка ему соответствует.
+// Клиенты без доступа к точным координатам получают огрубленную точку.
+// Отправка не блокирует вызывающего: клиент с переполненным буфером отключается
+func (h *Hub) BroadcastLocation(location *entities.DriverLocation) {
+	var (
+		precise, coarse []byte
+		slow            []*client
+	)
+
+	h.mu.RLock()
+	for c := range h.clients {
+		if c.subscription == nil || !c.subscription.Matches(location) {
+			continue
+		}
+
+		// Сериализуем каждый вариант сообщения один раз и только если есть получатели
+		payload := &coarse
+		if c.precise {
+			payload = &precise
+		}
+		if *payload == nil {
+			data, err := marshalLocation(location, c.precise)
+			if err != nil {
+				h.mu.RUnlock()
+				h.logger.Error("Failed to marshal location update", zap.Error(err))
+				return
+			}
+			*payload = data
+		}
+
+		select {
+		case c.send <- *payload:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	h.evict(slow)
+}
+
+// marshalLocation сериализует сообщение с точкой; без precise точка огрубляется
+// в копии, потому что местоположение передается и другим получателям
+func marshalLocation(location *entities.DriverLocation, precise bool) ([]byte, error) {
+	if !precise {
+		blurred := *location
+		blurred.Blur()
+		location = &blurred
+	}
+	return json.Marshal(Message{Type: MessageTypeLocation, Location: location.ToResponse()})
+}
+
+// evict отключает клиентов, не успевающих читать сообщения
+func (h *Hub) evict(slow []*client) {
+	for _, c := range slow {
+		h.logger.Warn("Evicting slow websocket consumer",
+			zap.String("remote_addr", c.remoteAddr),
+			zap.Int("send_buffer", cap(c.send)),
+		)
+		h.remove(c, gorilla.ClosePolicyViolation, "slow consumer")
+	}
+}
+
+// ClientCount возвращает количество подключенных клиентов
+func (h *Hub) ClientCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+// Close отключает всех клиентов и перестает принимать новые подключения.
+// Нужен при остановке сервиса: http.Server.Shutdown не закрывает перехваченные соединения
+func (h *Hub) Close() {
+	h.disconnect()
+}
+
+// Drain перестает принимать новые подключения, дожидается отправки буферов клиентов,
+// но не дольше ctx, и отключает клиентов. Возвращает число неотправленных сообщений
+func (h *Hub) Drain(ctx context.Context) int {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for h.pending() > 0 {
+		select {
+		case <-ctx.Done():
+			return h.disconnect()
+		case <-ticker.C:
+		}
+	}
+	return h.disconnect()
+}
+
+// pending возвращает число сообщений в буферах клиентов
+func (h *Hub) pending() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	pending := 0
+	for c := range h.clients {
+		pending += len(c.send)
+	}
+	return pending
+}
+
+// disconnect отключает всех клиентов и возвращает число сообщений, оставшихся в их буферах
+func (h *Hub) disconnect() int {
+	h.mu.Lock()
+	h.closed = true
+	clients := h.clients
+	h.clients = make(map[*client]struct{})
+	h.mu.Unlock()
+
+	dropped := 0
+	for c := range clients {
+		dropped += len(c.send)
+		c.close(gorilla.CloseGoingAway, "server shutdown")
+	}
+	return dropped
+}
+
+// register добавляет клиента в рассылку
+func (h *Hub) register(c *client) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return ErrHubClosed
+	}
+	if h.config.MaxConnections > 0 && len(h.clients) >= h.config.MaxConnections {
+		return ErrTooManyConnections
+	}
+
+	h.clients[c] = struct{}{}
+	return nil
+}
+
+// remove исключает клиента из рассылки и закрывает соединение
+func (h *Hub) remove(c *client, code int, reason string) {
+	h.mu.Lock()
+	delete(h.clients, c)
+	h.mu.Unlock()
+
+	c.close(code, reason)
+}
@@ -0,0 +1,248 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/interfaces/http/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	gorilla "github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestHub(sendBuffer int) *Hub {
+	return NewHub(config.WebSocketConfig{
+		SendBuffer:   sendBuffer,
+		WriteTimeout: time.Second,
+		PingInterval: time.Minute,
+	}, zap.NewNop())
+}
+
+func dialLocations(t *testing.T, hub *Hub, query string) *gorilla.Conn {
+	return dialLocationsAuthenticated(t, hub, query, nil)
+}
+
+// stubVerifier принимает токены из набора
+type stubVerifier map[string]*middleware.Claims
+
+func (v stubVerifier) Verify(ctx context.Context, token string) (*middleware.Claims, error) {
+	claims, ok := v[token]
+	if !ok {
+		return nil, middleware.ErrInvalidSignature
+	}
+	return claims, nil
+}
+
+// dialLocationsAuthenticated подключает подписчика; с verifier токен передается в access_token
+func dialLocationsAuthenticated(t *testing.T, hub *Hub, query string, verifier middleware.TokenVerifier) *gorilla.Conn {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if verifier != nil {
+		router.Use(middleware.Authenticate(verifier, zap.NewNop()))
+	}
+	NewHandler(hub, zap.NewNop()).RegisterRoutes(router.Group("/api/v1"))
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	clients := hub.ClientCount()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/ws/locations?" + query
+	conn, _, err := gorilla.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.Eventually(t, func() bool { return hub.ClientCount() > clients }, time.Second, 10*time.Millisecond)
+	return conn
+}
+
+func TestHub_DriverSubscription(t *testing.T) {
+	hub := newTestHub(8)
+	defer hub.Close()
+
+	driverID := uuid.New()
+	conn := dialLocations(t, hub, "driver_id="+driverID.String())
+
+	hub.BroadcastLocation(entities.NewDriverLocation(uuid.New(), 55.75, 37.61, time.Now()))
+	hub.BroadcastLocation(entities.NewDriverLocation(driverID, 55.76, 37.62, time.Now()))
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	var message Message
+	require.NoError(t, json.Unmarshal(data, &message))
+	assert.Equal(t, MessageTypeLocation, message.Type)
+	assert.Equal(t, driverID, message.Location.DriverID, "updates of other drivers are filtered out")
+	assert.Equal(t, 55.76, message.Location.Latitude)
+}
+
+func TestHub_MasksCoordinatesWithoutPreciseScope(t *testing.T) {
+	hub := newTestHub(8)
+	defer hub.Close()
+
+	driverID := uuid.New()
+	verifier := stubVerifier{
+		"dispatcher": {Subject: "dispatcher-1", Roles: []middleware.Role{middleware.RoleDispatcher}},
+		"precise":    {Subject: "dispatcher-2", Roles: []middleware.Role{middleware.RoleDispatcher}, Scopes: []string{entities.ScopeLocationReadPrecise}},
+		"driver":     {Subject: "driver-1", Roles: []middleware.Role{middleware.RoleDriver}, DriverID: &driverID},
+	}
+	query := "driver_id=" + driverID.String() + "&access_token="
+	dispatcher := dialLocationsAuthenticated(t, hub, query+"dispatcher", verifier)
+	precise := dialLocationsAuthenticated(t, hub, query+"precise", verifier)
+	driver := dialLocationsAuthenticated(t, hub, query+"driver", verifier)
+
+	speed := 42.0
+	location := entities.NewDriverLocation(driverID, 55.75321, 37.61987, time.Now())
+	location.Speed = &speed
+	hub.BroadcastLocation(location)
+
+	read := func(conn *gorilla.Conn) *entities.LocationResponse {
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var message Message
+		require.NoError(t, json.Unmarshal(data, &message))
+		return message.Location
+	}
+
+	coarse := read(dispatcher)
+	assert.Equal(t, 55.75, coarse.Latitude)
+	assert.Equal(t, 37.62, coarse.Longitude)
+	assert.Nil(t, coarse.Speed)
+
+	for _, conn := range []*gorilla.Conn{precise, driver} {
+		exact := read(conn)
+		assert.Equal(t, 55.75321, exact.Latitude)
+		assert.Equal(t, 37.61987, exact.Longitude)
+	}
+	assert.Equal(t, 55.75321, location.Latitude, "the broadcast location is not modified")
+}
+
+func TestHub_CloseDisconnectsClients(t *testing.T) {
+	hub := newTestHub(8)
+	conn := dialLocations(t, hub, "lat=55.75&lon=37.61&radius_km=5")
+
+	hub.Close()
+	assert.Equal(t, 0, hub.ClientCount())
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, gorilla.IsCloseError(err, gorilla.CloseGoingAway), "unexpected error: %v", err)
+}
+
+func TestHub_Drain(t *testing.T) {
+	hub := newTestHub(8)
+	driverID := uuid.New()
+	conn := dialLocations(t, hub, "driver_id="+driverID.String())
+
+	// Клиент без writePump никогда не вычитывает буфер
+	stuck := &client{
+		hub:          hub,
+		subscription: &Subscription{DriverID: &driverID},
+		send:         make(chan []byte, 8),
+		done:         make(chan struct{}),
+	}
+	require.NoError(t, hub.register(stuck))
+
+	hub.BroadcastLocation(entities.NewDriverLocation(driverID, 55.75, 37.61, time.Now()))
+	hub.BroadcastLocation(entities.NewDriverLocation(driverID, 55.76, 37.62, time.Now()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, 2, hub.Drain(ctx), "only the stuck client's messages are dropped")
+	assert.Equal(t, 0, hub.ClientCount())
+	assert.Equal(t, ErrHubClosed, hub.register(&client{hub: hub}))
+
+	// Подключенный клиент получает отправленные до остановки сообщения
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	for _, latitude := range []float64{55.75, 55.76} {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var message Message
+		require.NoError(t, json.Unmarshal(data, &message))
+		assert.Equal(t, latitude, message.Location.Latitude)
+	}
+	_, _, err := conn.ReadMessage()
+	assert.True(t, gorilla.IsCloseError(err, gorilla.CloseGoingAway), "unexpected error: %v", err)
+}
+
+func TestHub_EvictsSlowConsumer(t *testing.T) {
+	hub := newTestHub(1)
+	driverID := uuid.New()
+
+	// Клиент без writePump никогда не вычитывает буфер
+	slow := &client{
+		hub:          hub,
+		subscription: &Subscription{DriverID: &driverID},
+		send:         make(chan []byte, 1),
+		done:         make(chan struct{}),
+	}
+	require.NoError(t, hub.register(slow))
+
+	hub.BroadcastLocation(entities.NewDriverLocation(driverID, 55.75, 37.61, time.Now()))
+	assert.Equal(t, 1, hub.ClientCount())
+
+	hub.BroadcastLocation(entities.NewDriverLocation(driverID, 55.76, 37.62, time.Now()))
+	assert.Equal(t, 0, hub.ClientCount())
+
+	select {
+	case <-slow.done:
+		assert.Equal(t, gorilla.ClosePolicyViolation, slow.closeCode)
+	default:
+		t.Fatal("slow consumer was not closed")
+	}
+}
+
+func TestParseSubscription(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		valid bool
+	}{
+		{"driver", "driver_id=" + uuid.NewString(), true},
+		{"bounds", "sw_lat=55.5&sw_lon=37.3&ne_lat=56&ne_lon=37.9", true},
+		{"center", "lat=55.75&lon=37.61&radius_km=3", true},
+		{"empty", "", false},
+		{"invalid driver", "driver_id=abc", false},
+		{"inverted bounds", "sw_lat=56&sw_lon=37.3&ne_lat=55.5&ne_lon=37.9", false},
+		{"missing radius", "lat=55.75&lon=37.61", false},
+		{"radius too large", "lat=55.75&lon=37.61&radius_km=500", false},
+		{"ambiguous", "driver_id=" + uuid.NewString() + "&lat=55.75&lon=37.61&radius_km=3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			require.NoError(t, err)
+
+			_, err = ParseSubscription(query)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidSubscription)
+			}
+		})
+	}
+}
+
+func TestBounds_ContainsAcrossAntimeridian(t *testing.T) {
+	bounds := &Bounds{
+		SouthWest: entities.Location{Latitude: 60, Longitude: 170},
+		NorthEast: entities.Location{Latitude: 70, Longitude: -170},
+	}
+
+	assert.True(t, bounds.Contains(65, 175))
+	assert.True(t, bounds.Contains(65, -175))
+	assert.False(t, bounds.Contains(65, 0))
+}
@@ -0,0 +1,132 @@
+package websocket
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"driver-service/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// MaxSubscriptionRadiusKm максимальный радиус подписки на область
+const MaxSubscriptionRadiusKm = 50.0
+
+// ErrInvalidSubscription некорректные параметры подписки
+var ErrInvalidSubscription = errors.New("invalid subscription")
+
+// Bounds прямоугольная область, заданная юго-западным и северо-восточным углами
+type Bounds struct {
+	SouthWest entities.Location `json:"south_west"`
+	NorthEast entities.Location `json:"north_east"`
+}
+
+// Contains проверяет, попадает ли точка в область
+func (b *Bounds) Contains(latitude, longitude float64) bool {
+	if latitude < b.SouthWest.Latitude || latitude > b.NorthEast.Latitude {
+		return false
+	}
+	// Область может пересекать 180-й меридиан
+	if b.SouthWest.Longitude <= b.NorthEast.Longitude {
+		return longitude >= b.SouthWest.Longitude && longitude <= b.NorthEast.Longitude
+	}
+	return longitude >= b.SouthWest.Longitude || longitude <= b.NorthEast.Longitude
+}
+
+// Subscription фильтр обновлений местоположения, на которые подписан клиент.
+// Задается ровно одно из условий: водитель, прямоугольная область или круг
+type Subscription struct {
+	DriverID *uuid.UUID         `json:"driver_id,omitempty"`
+	Bounds   *Bounds            `json:"bounds,omitempty"`
+	Center   *entities.Location `json:"center,omitempty"`
+	RadiusKm float64            `json:"radius_km,omitempty"`
+}
+
+// Matches проверяет, должно ли обновление быть отправлено подписчику
+func (s *Subscription) Matches(location *entities.DriverLocation) bool {
+	switch {
+	case s.DriverID != nil:
+		return location.DriverID == *s.DriverID
+	case s.Bounds != nil:
+		return s.Bounds.Contains(location.Latitude, location.Longitude)
+	case s.Center != nil:
+		center := &entities.DriverLocation{Latitude: s.Center.Latitude, Longitude: s.Center.Longitude}
+		return center.DistanceTo(location) <= s.RadiusKm
+	default:
+		return false
+	}
+}
+
+// ParseSubscription разбирает параметры подписки из query строки:
+// driver_id, либо sw_lat/sw_lon/ne_lat/ne_lon, либо lat/lon/radius_km
+func ParseSubscription(query url.Values) (*Subscription, error) {
+	hasDriver := query.Get("driver_id") != ""
+	hasBounds := query.Get("sw_lat") != "" || query.Get("sw_lon") != "" ||
+		query.Get("ne_lat") != "" || query.Get("ne_lon") != ""
+	hasCenter := query.Get("lat") != "" || query.Get("lon") != ""
+
+	count := 0
+	for _, has := range []bool{hasDriver, hasBounds, hasCenter} {
+		if has {
+			count++
+		}
+	}
+	if count != 1 {
+		return nil, fmt.Errorf("%w: exactly one of driver_id, bounds (sw_lat, sw_lon, ne_lat, ne_lon) or center (lat, lon, radius_km) is required", ErrInvalidSubscription)
+	}
+
+	switch {
+	case hasDriver:
+		driverID, err := uuid.Parse(query.Get("driver_id"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid driver_id", ErrInvalidSubscription)
+		}
+		return &Subscription{DriverID: &driverID}, nil
+
+	case hasBounds:
+		swLat, swLon, err := parsePoint(query, "sw_lat", "sw_lon")
+		if err != nil {
+			return nil, err
+		}
+		neLat, neLon, err := parsePoint(query, "ne_lat", "ne_lon")
+		if err != nil {
+			return nil, err
+		}
+		if swLat > neLat {
+			return nil, fmt.Errorf("%w: sw_lat must not exceed ne_lat", ErrInvalidSubscription)
+		}
+		return &Subscription{Bounds: &Bounds{
+			SouthWest: entities.Location{Latitude: swLat, Longitude: swLon},
+			NorthEast: entities.Location{Latitude: neLat, Longitude: neLon},
+		}}, nil
+
+	default:
+		lat, lon, err := parsePoint(query, "lat", "lon")
+		if err != nil {
+			return nil, err
+		}
+		radiusKm, err := strconv.ParseFloat(query.Get("radius_km"), 64)
+		if err != nil || radiusKm <= 0 || radiusKm > MaxSubscriptionRadiusKm {
+			return nil, fmt.Errorf("%w: radius_km must be in (0, %.0f]", ErrInvalidSubscription, MaxSubscriptionRadiusKm)
+		}
+		return &Subscription{
+			Center:   &entities.Location{Latitude: lat, Longitude: lon},
+			RadiusKm: radiusKm,
+		}, nil
+	}
+}
+
+// parsePoint разбирает пару координат из query строки
+func parsePoint(query url.Values, latKey, lonKey string) (float64, float64, error) {
+	lat, err := strconv.ParseFloat(query.Get(latKey), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return 0, 0, fmt.Errorf("%w: invalid %s", ErrInvalidSubscription, latKey)
+	}
+	lon, err := strconv.ParseFloat(query.Get(lonKey), 64)
+	if err != nil || lon < -180 || lon > 180 {
+		return 0, 0, fmt.Errorf("%w: invalid %s", ErrInvalidSubscription, lonKey)
+	}
+	return lat, lon, nil
+}
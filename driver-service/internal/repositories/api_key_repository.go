@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// APIKeyRepository интерфейс для ключей API внутренних сервисов
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *entities.APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error)
+	// GetByHash получает ключ по хешу (entities.HashAPIKey)
+	GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error)
+	// List возвращает все ключи, включая отозванные, новые первыми
+	List(ctx context.Context) ([]*entities.APIKey, error)
+	// Revoke сохраняет отзыв ключа
+	Revoke(ctx context.Context, key *entities.APIKey) error
+}
+
+// apiKeyRepository реализация APIKeyRepository
+type apiKeyRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewAPIKeyRepository создает новый репозиторий ключей API
+func NewAPIKeyRepository(db *database.DB, logger *zap.Logger) APIKeyRepository {
+	return &apiKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет выпущенный ключ
+func (r *apiKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	query := `
+		INSERT INTO api_keys (
+			id, name, prefix, key_hash, scopes, rate_limit_per_minute, created_by, created_at, expires_at
+		) VALUES (
+			:id, :name, :prefix, :key_hash, :scopes, :rate_limit_per_minute, :created_by, :created_at, :expires_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, key); err != nil {
+		r.logger.Error("Failed to create api key", zap.Error(err), zap.String("name", key.Name))
+		return fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает ключ по ID
+func (r *apiKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	var key entities.APIKey
+	if err := r.db.GetContext(ctx, &key, `SELECT * FROM api_keys WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrAPIKeyNotFound
+		}
+		r.logger.Error("Failed to get api key", zap.Error(err), zap.String("api_key_id", id.String()))
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// GetByHash получает ключ по хешу
+func (r *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	if err := r.db.GetContext(ctx, &key, `SELECT * FROM api_keys WHERE key_hash = $1`, keyHash); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrAPIKeyNotFound
+		}
+		r.logger.Error("Failed to get api key by hash", zap.Error(err))
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// List получает все ключи, новые первыми
+func (r *apiKeyRepository) List(ctx context.Context) ([]*entities.APIKey, error) {
+	var keys []*entities.APIKey
+	if err := r.db.SelectContext(ctx, &keys, `SELECT * FROM api_keys ORDER BY created_at DESC, id`); err != nil {
+		r.logger.Error("Failed to list api keys", zap.Error(err))
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// Revoke сохраняет отзыв ключа; уже отозванный ключ не изменяется
+func (r *apiKeyRepository) Revoke(ctx context.Context, key *entities.APIKey) error {
+	query := `
+		UPDATE api_keys SET revoked_at = :revoked_at, revoked_by = :revoked_by
+		WHERE id = :id AND revoked_at IS NULL`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, key)
+	if err != nil {
+		r.logger.Error("Failed to revoke api key", zap.Error(err), zap.String("api_key_id", key.ID.String()))
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		if _, err := r.GetByID(ctx, key.ID); err != nil {
+			return err
+		}
+		return entities.ErrAPIKeyRevoked
+	}
+
+	return nil
+}
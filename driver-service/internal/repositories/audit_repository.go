@@ -0,0 +1,341 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// AuditRepository интерфейс для журнала аудита водителей
+type AuditRepository interface {
+	Create(ctx context.Context, entry *entities.AuditEntry) error
+	// ListByDriver возвращает записи водителя, новые первыми
+	ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.AuditEntry, error)
+	// List возвращает записи по фильтрам, новые первыми
+	List(ctx context.Context, filters *entities.AuditFilters) ([]*entities.AuditEntry, error)
+
+	// CreateChained добавляет запись в конец цепочки хешей
+	CreateChained(ctx context.Context, entry *entities.AuditEntry) error
+	// GetChainHead возвращает последнюю запись цепочки
+	GetChainHead(ctx context.Context) (*entities.AuditEntry, error)
+	// GetChainEntry возвращает запись цепочки по номеру
+	GetChainEntry(ctx context.Context, sequence int64) (*entities.AuditEntry, error)
+	// ListChain возвращает записи цепочки с номерами из [from, to] по возрастанию номеров
+	ListChain(ctx context.Context, from, to int64, limit int) ([]*entities.AuditEntry, error)
+
+	CreateAnchor(ctx context.Context, anchor *entities.AuditAnchor) error
+	GetLatestAnchor(ctx context.Context) (*entities.AuditAnchor, error)
+	// ListAnchors возвращает закрепления записей с номерами из [from, to]
+	ListAnchors(ctx context.Context, from, to int64) ([]*entities.AuditAnchor, error)
+}
+
+// auditChainLockKey ключ advisory-блокировки, сериализующей добавление в цепочку
+// между экземплярами сервиса
+const auditChainLockKey = 0x61756469745f6368
+
+// insertAuditEntryQuery вставка записи журнала аудита
+const insertAuditEntryQuery = `
+		INSERT INTO driver_audit_log (
+			id, driver_id, fleet_id, event, action, outcome, details, created_at,
+			actor, actor_roles, request_id, before_snapshot, after_snapshot,
+			sequence, prev_hash, hash
+		) VALUES (
+			:id, :driver_id, :fleet_id, :event, :action, :outcome, :details, :created_at,
+			:actor, :actor_roles, :request_id, :before_snapshot, :after_snapshot,
+			:sequence, :prev_hash, :hash
+		)`
+
+// WithHashChain возвращает репозиторий, который сохраняет новые записи в цепочку хешей
+func WithHashChain(repo AuditRepository) AuditRepository {
+	return &chainedAuditRepository{AuditRepository: repo}
+}
+
+// chainedAuditRepository направляет Create в CreateChained
+type chainedAuditRepository struct {
+	AuditRepository
+}
+
+// Create добавляет запись в конец цепочки хешей
+func (r *chainedAuditRepository) Create(ctx context.Context, entry *entities.AuditEntry) error {
+	return r.CreateChained(ctx, entry)
+}
+
+// auditRepository реализация AuditRepository
+type auditRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewAuditRepository создает новый репозиторий журнала аудита
+func NewAuditRepository(db *database.DB, logger *zap.Logger) AuditRepository {
+	return &auditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет запись журнала аудита
+func (r *auditRepository) Create(ctx context.Context, entry *entities.AuditEntry) error {
+	query := insertAuditEntryQuery + `
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, entry); err != nil {
+		r.logger.Error("Failed to create audit entry",
+			zap.Error(err),
+			zap.String("event", entry.Event),
+			zap.String("outcome", entry.Outcome),
+		)
+		return fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDriver получает записи журнала аудита водителя
+func (r *auditRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.AuditEntry, error) {
+	query := `
+		SELECT * FROM driver_audit_log
+		WHERE driver_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`
+
+	var entries []*entities.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, driverID, limit, offset); err != nil {
+		r.logger.Error("Failed to list audit entries",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// List получает записи журнала аудита по фильтрам
+func (r *auditRepository) List(ctx context.Context, filters *entities.AuditFilters) ([]*entities.AuditEntry, error) {
+	where, args := auditConditions(filters)
+	args = append(args, filters.Limit, filters.Offset)
+	query := fmt.Sprintf(`
+		SELECT * FROM driver_audit_log
+		WHERE 1=1%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	var entries []*entities.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, args...); err != nil {
+		r.logger.Error("Failed to list audit entries", zap.Error(err))
+		return nil, fmt.Errorf("failed to list audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// auditConditions строит условия WHERE по фильтрам журнала аудита
+func auditConditions(filters *entities.AuditFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filters.DriverID != nil {
+		add("driver_id = $%d", *filters.DriverID)
+	}
+	if filters.FleetID != nil {
+		add("fleet_id = $%d", *filters.FleetID)
+	}
+	if filters.Actor != nil {
+		add("actor = $%d", *filters.Actor)
+	}
+	if filters.Event != nil {
+		add("event = $%d", *filters.Event)
+	}
+	if filters.Action != nil {
+		add("action = $%d", *filters.Action)
+	}
+	if filters.RequestID != nil {
+		add("request_id = $%d", *filters.RequestID)
+	}
+	if filters.From != nil {
+		add("created_at >= $%d", *filters.From)
+	}
+	if filters.To != nil {
+		add("created_at < $%d", *filters.To)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
+
+// CreateChained добавляет запись в конец цепочки хешей. Чтение последней записи и вставка
+// выполняются в одной транзакции под advisory-блокировкой: две записи не получат один номер
+func (r *auditRepository) CreateChained(ctx context.Context, entry *entities.AuditEntry) error {
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(auditChainLockKey)); err != nil {
+			return fmt.Errorf("failed to lock audit chain: %w", err)
+		}
+
+		sequence, prevHash := int64(1), entities.AuditChainGenesis
+		var head entities.AuditEntry
+		err := tx.GetContext(ctx, &head, `
+			SELECT * FROM driver_audit_log
+			WHERE sequence IS NOT NULL
+			ORDER BY sequence DESC
+			LIMIT 1`)
+		switch {
+		case err == nil:
+			sequence, prevHash = *head.Sequence+1, *head.Hash
+		case err != sql.ErrNoRows:
+			return fmt.Errorf("failed to get audit chain head: %w", err)
+		}
+
+		if err := entry.Seal(sequence, prevHash); err != nil {
+			return err
+		}
+		if _, err := tx.NamedExecContext(ctx, insertAuditEntryQuery, entry); err != nil {
+			return fmt.Errorf("failed to insert chained audit entry: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		r.logger.Error("Failed to create chained audit entry",
+			zap.Error(err),
+			zap.String("event", entry.Event),
+			zap.String("outcome", entry.Outcome),
+		)
+		return fmt.Errorf("failed to create chained audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetChainHead получает последнюю запись цепочки
+func (r *auditRepository) GetChainHead(ctx context.Context) (*entities.AuditEntry, error) {
+	query := `
+		SELECT * FROM driver_audit_log
+		WHERE sequence IS NOT NULL
+		ORDER BY sequence DESC
+		LIMIT 1`
+
+	var entry entities.AuditEntry
+	if err := r.db.GetContext(ctx, &entry, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrAuditEntryNotFound
+		}
+		r.logger.Error("Failed to get audit chain head", zap.Error(err))
+		return nil, fmt.Errorf("failed to get audit chain head: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// GetChainEntry получает запись цепочки по номеру
+func (r *auditRepository) GetChainEntry(ctx context.Context, sequence int64) (*entities.AuditEntry, error) {
+	query := `SELECT * FROM driver_audit_log WHERE sequence = $1`
+
+	var entry entities.AuditEntry
+	if err := r.db.GetContext(ctx, &entry, query, sequence); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrAuditEntryNotFound
+		}
+		r.logger.Error("Failed to get audit chain entry",
+			zap.Error(err),
+			zap.Int64("sequence", sequence),
+		)
+		return nil, fmt.Errorf("failed to get audit chain entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// ListChain получает записи цепочки по возрастанию номеров
+func (r *auditRepository) ListChain(ctx context.Context, from, to int64, limit int) ([]*entities.AuditEntry, error) {
+	query := `
+		SELECT * FROM driver_audit_log
+		WHERE sequence BETWEEN $1 AND $2
+		ORDER BY sequence
+		LIMIT $3`
+
+	var entries []*entities.AuditEntry
+	if err := r.db.SelectContext(ctx, &entries, query, from, to, limit); err != nil {
+		r.logger.Error("Failed to list audit chain",
+			zap.Error(err),
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+		)
+		return nil, fmt.Errorf("failed to list audit chain: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CreateAnchor сохраняет закрепление хеша цепочки
+func (r *auditRepository) CreateAnchor(ctx context.Context, anchor *entities.AuditAnchor) error {
+	query := `
+		INSERT INTO driver_audit_anchors (
+			id, sequence, hash, sink, reference, anchored_at
+		) VALUES (
+			:id, :sequence, :hash, :sink, :reference, :anchored_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, anchor); err != nil {
+		r.logger.Error("Failed to create audit anchor",
+			zap.Error(err),
+			zap.Int64("sequence", anchor.Sequence),
+		)
+		return fmt.Errorf("failed to create audit anchor: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestAnchor получает последнее закрепление
+func (r *auditRepository) GetLatestAnchor(ctx context.Context) (*entities.AuditAnchor, error) {
+	query := `
+		SELECT * FROM driver_audit_anchors
+		ORDER BY sequence DESC, anchored_at DESC
+		LIMIT 1`
+
+	var anchor entities.AuditAnchor
+	if err := r.db.GetContext(ctx, &anchor, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrAuditAnchorNotFound
+		}
+		r.logger.Error("Failed to get latest audit anchor", zap.Error(err))
+		return nil, fmt.Errorf("failed to get latest audit anchor: %w", err)
+	}
+
+	return &anchor, nil
+}
+
+// ListAnchors получает закрепления записей из отрезка номеров
+func (r *auditRepository) ListAnchors(ctx context.Context, from, to int64) ([]*entities.AuditAnchor, error) {
+	query := `
+		SELECT * FROM driver_audit_anchors
+		WHERE sequence BETWEEN $1 AND $2
+		ORDER BY sequence`
+
+	var anchors []*entities.AuditAnchor
+	if err := r.db.SelectContext(ctx, &anchors, query, from, to); err != nil {
+		r.logger.Error("Failed to list audit anchors",
+			zap.Error(err),
+			zap.Int64("from", from),
+			zap.Int64("to", to),
+		)
+		return nil, fmt.Errorf("failed to list audit anchors: %w", err)
+	}
+
+	return anchors, nil
+}
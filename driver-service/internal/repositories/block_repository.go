@@ -0,0 +1,203 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// BlockRepository интерфейс для блокировок водителей. Блокировка и статус водителя
+// меняются вместе: водитель с активной блокировкой находится в статусе blocked
+type BlockRepository interface {
+	// Create сохраняет блокировку и переводит водителя в статус blocked. Активная блокировка
+	// у водителя может быть только одна: вторая возвращает ErrDriverAlreadyBlocked
+	Create(ctx context.Context, block *entities.DriverBlock) error
+	// GetActive получает активную блокировку водителя
+	GetActive(ctx context.Context, driverID uuid.UUID) (*entities.DriverBlock, error)
+	// ListByDriverID возвращает историю блокировок водителя, последние первыми
+	ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverBlock, error)
+	// ListExpired возвращает не больше limit активных блокировок со сроком снятия не позже at
+	ListExpired(ctx context.Context, at time.Time, limit int) ([]*entities.DriverBlock, error)
+	// Release сохраняет снятие блокировки и возвращает водителю status, если он все еще
+	// в статусе blocked; restored сообщает, изменился ли статус. Уже снятая блокировка
+	// возвращает ErrBlockNotFound
+	Release(ctx context.Context, block *entities.DriverBlock, status entities.Status) (restored bool, err error)
+}
+
+// blockRepository реализация BlockRepository
+type blockRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewBlockRepository создает новый репозиторий блокировок водителей
+func NewBlockRepository(db *database.DB, logger *zap.Logger) BlockRepository {
+	return &blockRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет блокировку и статус водителя одной транзакцией
+func (r *blockRepository) Create(ctx context.Context, block *entities.DriverBlock) error {
+	query := `
+		INSERT INTO driver_blocks (
+			id, driver_id, reason, comment, previous_status, blocked_by, blocked_at, unblock_at
+		) VALUES (
+			:id, :driver_id, :reason, :comment, :previous_status, :blocked_by, :blocked_at, :unblock_at
+		)`
+
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		previous, err := lockDriverStatus(ctx, tx, block.DriverID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.NamedExecContext(ctx, query, block); err != nil {
+			// Вторая активная блокировка нарушает idx_driver_blocks_active
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return entities.ErrDriverAlreadyBlocked
+			}
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE drivers SET status = $1, updated_at = $2
+			WHERE id = $3 AND deleted_at IS NULL`,
+			entities.StatusBlocked, block.BlockedAt, block.DriverID,
+		); err != nil {
+			return err
+		}
+		if previous == entities.StatusBlocked {
+			return nil
+		}
+		return recordStatusTransition(ctx, tx, block.DriverID, &previous, entities.StatusBlocked, block.BlockedAt)
+	})
+	if err != nil {
+		if err == entities.ErrDriverAlreadyBlocked || err == entities.ErrDriverNotFound {
+			return err
+		}
+		r.logger.Error("Failed to create driver block",
+			zap.Error(err),
+			zap.String("driver_id", block.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create driver block: %w", err)
+	}
+
+	return nil
+}
+
+// GetActive получает активную блокировку водителя
+func (r *blockRepository) GetActive(ctx context.Context, driverID uuid.UUID) (*entities.DriverBlock, error) {
+	var block entities.DriverBlock
+	query := `SELECT * FROM driver_blocks WHERE driver_id = $1 AND unblocked_at IS NULL`
+	if err := r.db.GetContext(ctx, &block, query, driverID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrBlockNotFound
+		}
+		r.logger.Error("Failed to get active driver block", zap.Error(err))
+		return nil, fmt.Errorf("failed to get active driver block: %w", err)
+	}
+
+	return &block, nil
+}
+
+// ListByDriverID получает историю блокировок водителя
+func (r *blockRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverBlock, error) {
+	query := `
+		SELECT * FROM driver_blocks
+		WHERE driver_id = $1
+		ORDER BY blocked_at DESC`
+
+	var blocks []*entities.DriverBlock
+	if err := r.db.SelectContext(ctx, &blocks, query, driverID); err != nil {
+		r.logger.Error("Failed to list driver blocks",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// ListExpired получает блокировки с наступившим сроком снятия, самые старые первыми
+func (r *blockRepository) ListExpired(ctx context.Context, at time.Time, limit int) ([]*entities.DriverBlock, error) {
+	query := `
+		SELECT * FROM driver_blocks
+		WHERE unblocked_at IS NULL AND unblock_at IS NOT NULL AND unblock_at <= $1
+		ORDER BY unblock_at
+		LIMIT $2`
+
+	var blocks []*entities.DriverBlock
+	if err := r.db.SelectContext(ctx, &blocks, query, at, limit); err != nil {
+		r.logger.Error("Failed to list expired driver blocks", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expired driver blocks: %w", err)
+	}
+
+	return blocks, nil
+}
+
+// Release сохраняет снятие блокировки и статус водителя одной транзакцией. Статус не
+// меняется, если водителя уже перевели из blocked, например ручной сменой статуса
+func (r *blockRepository) Release(ctx context.Context, block *entities.DriverBlock, status entities.Status) (bool, error) {
+	restored := false
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.NamedExecContext(ctx, `
+			UPDATE driver_blocks SET
+				unblocked_at = :unblocked_at, unblocked_by = :unblocked_by,
+				unblock_reason = :unblock_reason, unblock_comment = :unblock_comment
+			WHERE id = :id AND unblocked_at IS NULL`, block)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return entities.ErrBlockNotFound
+		}
+
+		result, err = tx.ExecContext(ctx, `
+			UPDATE drivers SET status = $1, updated_at = $2
+			WHERE id = $3 AND status = $4 AND deleted_at IS NULL`,
+			status, *block.UnblockedAt, block.DriverID, entities.StatusBlocked,
+		)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		restored = rowsAffected > 0
+		if !restored || status == entities.StatusBlocked {
+			return nil
+		}
+		from := entities.StatusBlocked
+		return recordStatusTransition(ctx, tx, block.DriverID, &from, status, *block.UnblockedAt)
+	})
+	if err != nil {
+		if err == entities.ErrBlockNotFound {
+			return false, err
+		}
+		r.logger.Error("Failed to release driver block",
+			zap.Error(err),
+			zap.String("driver_id", block.DriverID.String()),
+		)
+		return false, fmt.Errorf("failed to release driver block: %w", err)
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// CampaignRepository интерфейс для кампаний перепроверки документов
+type CampaignRepository interface {
+	// CreateCampaign сохраняет кампанию вместе с документами, которые нужно перепроверить
+	CreateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign, targets []*entities.CampaignTarget) error
+	GetCampaign(ctx context.Context, id uuid.UUID) (*entities.ReverificationCampaign, error)
+	// ListCampaigns возвращает кампании, новые первыми; пустой статус не ограничивает выборку
+	ListCampaigns(ctx context.Context, status entities.CampaignStatus, limit, offset int) ([]*entities.ReverificationCampaign, error)
+	UpdateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign) error
+
+	ListTargets(ctx context.Context, filters *entities.CampaignTargetFilters) ([]*entities.CampaignTarget, error)
+	UpdateTarget(ctx context.Context, target *entities.CampaignTarget) error
+	// GetProgress считает документы кампании по типам и статусам, водителей и перепроверки по дням
+	GetProgress(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignProgress, error)
+}
+
+// campaignRepository реализация CampaignRepository
+type campaignRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewCampaignRepository создает новый репозиторий кампаний перепроверки
+func NewCampaignRepository(db *database.DB, logger *zap.Logger) CampaignRepository {
+	return &campaignRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// targetBatchSize число документов кампании в одном INSERT
+const targetBatchSize = 1000
+
+// CreateCampaign сохраняет кампанию и ее документы
+func (r *campaignRepository) CreateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign, targets []*entities.CampaignTarget) error {
+	campaignQuery := `
+		INSERT INTO reverification_campaigns (
+			id, name, reason, segment, document_types, deadline, reminder_interval_days,
+			status, created_by, created_at, updated_at
+		) VALUES (
+			:id, :name, :reason, :segment, :document_types, :deadline, :reminder_interval_days,
+			:status, :created_by, :created_at, :updated_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, campaignQuery, campaign); err != nil {
+		r.logger.Error("Failed to create reverification campaign",
+			zap.Error(err),
+			zap.String("campaign_id", campaign.ID.String()),
+		)
+		return fmt.Errorf("failed to create reverification campaign: %w", err)
+	}
+
+	targetQuery := `
+		INSERT INTO reverification_targets (
+			campaign_id, document_id, driver_id, document_type, status, last_reminded_at, created_at, updated_at
+		) VALUES (
+			:campaign_id, :document_id, :driver_id, :document_type, :status, :last_reminded_at, :created_at, :updated_at
+		)
+		ON CONFLICT (campaign_id, document_id) DO NOTHING`
+
+	for start := 0; start < len(targets); start += targetBatchSize {
+		end := start + targetBatchSize
+		if end > len(targets) {
+			end = len(targets)
+		}
+		if _, err := r.db.NamedExecIdempotentContext(ctx, targetQuery, targets[start:end]); err != nil {
+			r.logger.Error("Failed to create reverification targets",
+				zap.Error(err),
+				zap.String("campaign_id", campaign.ID.String()),
+			)
+			return fmt.Errorf("failed to create reverification targets: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetCampaign получает кампанию по ID
+func (r *campaignRepository) GetCampaign(ctx context.Context, id uuid.UUID) (*entities.ReverificationCampaign, error) {
+	var campaign entities.ReverificationCampaign
+	if err := r.db.GetContext(ctx, &campaign, `SELECT * FROM reverification_campaigns WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCampaignNotFound
+		}
+		r.logger.Error("Failed to get reverification campaign", zap.Error(err), zap.String("campaign_id", id.String()))
+		return nil, fmt.Errorf("failed to get reverification campaign: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// ListCampaigns получает кампании по статусу
+func (r *campaignRepository) ListCampaigns(ctx context.Context, status entities.CampaignStatus, limit, offset int) ([]*entities.ReverificationCampaign, error) {
+	query := `
+		SELECT * FROM reverification_campaigns
+		WHERE ($1 = '' OR status = $1)
+		ORDER BY created_at DESC, id
+		LIMIT NULLIF($2, 0) OFFSET $3`
+
+	var campaigns []*entities.ReverificationCampaign
+	if err := r.db.SelectContext(ctx, &campaigns, query, string(status), limit, offset); err != nil {
+		r.logger.Error("Failed to list reverification campaigns", zap.Error(err))
+		return nil, fmt.Errorf("failed to list reverification campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// UpdateCampaign сохраняет статус кампании
+func (r *campaignRepository) UpdateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign) error {
+	query := `
+		UPDATE reverification_campaigns SET
+			status = :status, updated_at = :updated_at, closed_at = :closed_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, campaign)
+	if err != nil {
+		r.logger.Error("Failed to update reverification campaign",
+			zap.Error(err),
+			zap.String("campaign_id", campaign.ID.String()),
+		)
+		return fmt.Errorf("failed to update reverification campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrCampaignNotFound
+	}
+
+	return nil
+}
+
+// ListTargets получает документы кампаний по фильтрам
+func (r *campaignRepository) ListTargets(ctx context.Context, filters *entities.CampaignTargetFilters) ([]*entities.CampaignTarget, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filters.CampaignID != nil {
+		args = append(args, *filters.CampaignID)
+		conditions = append(conditions, fmt.Sprintf("campaign_id = $%d", len(args)))
+	}
+	if filters.DriverID != nil {
+		args = append(args, *filters.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if len(filters.Status) > 0 {
+		statuses := make([]string, len(filters.Status))
+		for i, status := range filters.Status {
+			statuses[i] = string(status)
+		}
+		args = append(args, pq.Array(statuses))
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d)", len(args)))
+	}
+	if filters.RemindedBefore != nil {
+		args = append(args, *filters.RemindedBefore)
+		conditions = append(conditions, fmt.Sprintf("(last_reminded_at IS NULL OR last_reminded_at < $%d)", len(args)))
+	}
+
+	query := `SELECT * FROM reverification_targets`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY driver_id, document_type"
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	var targets []*entities.CampaignTarget
+	if err := r.db.SelectContext(ctx, &targets, query, args...); err != nil {
+		r.logger.Error("Failed to list reverification targets", zap.Error(err))
+		return nil, fmt.Errorf("failed to list reverification targets: %w", err)
+	}
+
+	return targets, nil
+}
+
+// UpdateTarget сохраняет состояние документа кампании
+func (r *campaignRepository) UpdateTarget(ctx context.Context, target *entities.CampaignTarget) error {
+	query := `
+		UPDATE reverification_targets SET
+			status = :status, reminders_sent = :reminders_sent, last_reminded_at = :last_reminded_at,
+			completed_at = :completed_at, updated_at = :updated_at
+		WHERE campaign_id = :campaign_id AND document_id = :document_id`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, target); err != nil {
+		r.logger.Error("Failed to update reverification target",
+			zap.Error(err),
+			zap.String("campaign_id", target.CampaignID.String()),
+			zap.String("document_id", target.DocumentID.String()),
+		)
+		return fmt.Errorf("failed to update reverification target: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgress считает ход кампании
+func (r *campaignRepository) GetProgress(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignProgress, error) {
+	progress := &entities.CampaignProgress{
+		CampaignID:     campaignID,
+		ByDocumentType: []*entities.CampaignTypeProgress{},
+		Daily:          []*entities.CampaignDailyProgress{},
+	}
+
+	byTypeQuery := `
+		SELECT
+			document_type,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'pending') AS pending,
+			COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE status = 'overdue') AS overdue,
+			COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled
+		FROM reverification_targets
+		WHERE campaign_id = $1
+		GROUP BY document_type
+		ORDER BY document_type`
+	if err := r.db.SelectContext(ctx, &progress.ByDocumentType, byTypeQuery, campaignID); err != nil {
+		r.logger.Error("Failed to count reverification targets", zap.Error(err), zap.String("campaign_id", campaignID.String()))
+		return nil, fmt.Errorf("failed to count reverification targets: %w", err)
+	}
+
+	var drivers struct {
+		Total        int `db:"total"`
+		NonCompliant int `db:"non_compliant"`
+	}
+	driversQuery := `
+		SELECT
+			COUNT(DISTINCT driver_id) AS total,
+			COUNT(DISTINCT driver_id) FILTER (WHERE status IN ('pending', 'overdue')) AS non_compliant
+		FROM reverification_targets
+		WHERE campaign_id = $1`
+	if err := r.db.GetContext(ctx, &drivers, driversQuery, campaignID); err != nil {
+		r.logger.Error("Failed to count reverification drivers", zap.Error(err), zap.String("campaign_id", campaignID.String()))
+		return nil, fmt.Errorf("failed to count reverification drivers: %w", err)
+	}
+	progress.Drivers = drivers.Total
+	progress.CompliantDrivers = drivers.Total - drivers.NonCompliant
+
+	dailyQuery := `
+		SELECT date_trunc('day', completed_at) AS date, COUNT(*) AS completed
+		FROM reverification_targets
+		WHERE campaign_id = $1 AND completed_at IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1`
+	if err := r.db.SelectContext(ctx, &progress.Daily, dailyQuery, campaignID); err != nil {
+		r.logger.Error("Failed to count daily reverifications", zap.Error(err), zap.String("campaign_id", campaignID.String()))
+		return nil, fmt.Errorf("failed to count daily reverifications: %w", err)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+// CapacityRepository интерфейс для суточных отчетов о нагрузке
+type CapacityRepository interface {
+	// SaveReport сохраняет отчет; повторный отчет экземпляра за те же сутки заменяет прежний
+	SaveReport(ctx context.Context, report *entities.CapacityReport) error
+	// ListReports возвращает отчеты всех экземпляров начиная с даты from по возрастанию даты
+	ListReports(ctx context.Context, from time.Time) ([]*entities.CapacityReport, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// capacityRepository реализация CapacityRepository
+type capacityRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewCapacityRepository создает новый репозиторий отчетов о нагрузке
+func NewCapacityRepository(db *database.DB, logger *zap.Logger) CapacityRepository {
+	return &capacityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveReport сохраняет суточный отчет экземпляра сервиса
+func (r *capacityRepository) SaveReport(ctx context.Context, report *entities.CapacityReport) error {
+	query := `
+		INSERT INTO capacity_reports (
+			report_date, instance, period_from, period_to, total_requests, endpoints, pool, created_at
+		) VALUES (
+			:report_date, :instance, :period_from, :period_to, :total_requests, :endpoints, :pool, :created_at
+		)
+		ON CONFLICT (report_date, instance) DO UPDATE SET
+			period_from = EXCLUDED.period_from,
+			period_to = EXCLUDED.period_to,
+			total_requests = EXCLUDED.total_requests,
+			endpoints = EXCLUDED.endpoints,
+			pool = EXCLUDED.pool,
+			created_at = EXCLUDED.created_at`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, report); err != nil {
+		r.logger.Error("Failed to save capacity report",
+			zap.Error(err),
+			zap.Time("date", report.Date),
+			zap.String("instance", report.Instance),
+		)
+		return fmt.Errorf("failed to save capacity report: %w", err)
+	}
+
+	return nil
+}
+
+// ListReports получает отчеты начиная с даты from
+func (r *capacityRepository) ListReports(ctx context.Context, from time.Time) ([]*entities.CapacityReport, error) {
+	query := `
+		SELECT * FROM capacity_reports
+		WHERE report_date >= $1
+		ORDER BY report_date ASC, instance ASC`
+
+	var reports []*entities.CapacityReport
+	if err := r.db.SelectContext(ctx, &reports, query, from); err != nil {
+		r.logger.Error("Failed to list capacity reports", zap.Error(err))
+		return nil, fmt.Errorf("failed to list capacity reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// DeleteBefore удаляет отчеты старше даты before
+func (r *capacityRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM capacity_reports WHERE report_date < $1`, before)
+	if err != nil {
+		r.logger.Error("Failed to delete old capacity reports", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete old capacity reports: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,299 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// CityRepository интерфейс для регионов, городов и города водителей
+type CityRepository interface {
+	CreateRegion(ctx context.Context, region *entities.Region) error
+	GetRegion(ctx context.Context, id uuid.UUID) (*entities.Region, error)
+	// ListRegions возвращает все регионы по коду
+	ListRegions(ctx context.Context) ([]*entities.Region, error)
+
+	CreateCity(ctx context.Context, city *entities.City) error
+	GetCity(ctx context.Context, id uuid.UUID) (*entities.City, error)
+	UpdateCity(ctx context.Context, city *entities.City) error
+	// DeleteCity удаляет город и снимает его с водителей, отнесенных к нему по местоположению
+	DeleteCity(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filters *entities.CityFilters) ([]*entities.City, error)
+	Count(ctx context.Context, filters *entities.CityFilters) (int, error)
+	// ListActive возвращает активные города в порядке создания
+	ListActive(ctx context.Context) ([]*entities.City, error)
+
+	// AssignDriver относит водителя к городу по ключу
+	AssignDriver(ctx context.Context, driverID uuid.UUID, city string) error
+	// CountActiveDrivers возвращает число активных водителей по городам (Driver.OperatingCity)
+	CountActiveDrivers(ctx context.Context) ([]*entities.CityDriverCount, error)
+}
+
+// cityRepository реализация CityRepository
+type cityRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewCityRepository создает новый репозиторий городов
+func NewCityRepository(db *database.DB, logger *zap.Logger) CityRepository {
+	return &cityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateRegion сохраняет регион
+func (r *cityRepository) CreateRegion(ctx context.Context, region *entities.Region) error {
+	query := `
+		INSERT INTO regions (id, code, name, created_at, updated_at)
+		VALUES (:id, :code, :name, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, region); err != nil {
+		// Регион с тем же кодом уже создан
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrRegionExists
+		}
+		r.logger.Error("Failed to create region",
+			zap.Error(err),
+			zap.String("code", region.Code),
+		)
+		return fmt.Errorf("failed to create region: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegion получает регион по ID
+func (r *cityRepository) GetRegion(ctx context.Context, id uuid.UUID) (*entities.Region, error) {
+	var region entities.Region
+	if err := r.db.GetContext(ctx, &region, `SELECT * FROM regions WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrRegionNotFound
+		}
+		r.logger.Error("Failed to get region",
+			zap.Error(err),
+			zap.String("region_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get region: %w", err)
+	}
+	return &region, nil
+}
+
+// ListRegions получает все регионы
+func (r *cityRepository) ListRegions(ctx context.Context) ([]*entities.Region, error) {
+	var regions []*entities.Region
+	if err := r.db.SelectContext(ctx, &regions, `SELECT * FROM regions ORDER BY code`); err != nil {
+		r.logger.Error("Failed to list regions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list regions: %w", err)
+	}
+	return regions, nil
+}
+
+// CreateCity сохраняет город
+func (r *cityRepository) CreateCity(ctx context.Context, city *entities.City) error {
+	query := `
+		INSERT INTO cities (id, region_id, key, name, boundary, active, created_at, updated_at)
+		VALUES (:id, :region_id, :key, :name, :boundary, :active, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, city); err != nil {
+		// Город с тем же ключом уже создан
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrCityExists
+		}
+		r.logger.Error("Failed to create city",
+			zap.Error(err),
+			zap.String("key", city.Key),
+		)
+		return fmt.Errorf("failed to create city: %w", err)
+	}
+
+	return nil
+}
+
+// GetCity получает город по ID
+func (r *cityRepository) GetCity(ctx context.Context, id uuid.UUID) (*entities.City, error) {
+	var city entities.City
+	if err := r.db.GetContext(ctx, &city, `SELECT * FROM cities WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrCityNotFound
+		}
+		r.logger.Error("Failed to get city",
+			zap.Error(err),
+			zap.String("city_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get city: %w", err)
+	}
+	return &city, nil
+}
+
+// UpdateCity сохраняет изменения города; ключ города не меняется
+func (r *cityRepository) UpdateCity(ctx context.Context, city *entities.City) error {
+	query := `
+		UPDATE cities SET
+			region_id = :region_id, name = :name, boundary = :boundary, active = :active,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, city)
+	if err != nil {
+		r.logger.Error("Failed to update city",
+			zap.Error(err),
+			zap.String("city_id", city.ID.String()),
+		)
+		return fmt.Errorf("failed to update city: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrCityNotFound
+	}
+
+	return nil
+}
+
+// DeleteCity удаляет город в одной транзакции со снятием его с водителей
+func (r *cityRepository) DeleteCity(ctx context.Context, id uuid.UUID) error {
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		var key string
+		if err := tx.GetContext(ctx, &key, `DELETE FROM cities WHERE id = $1 RETURNING key`, id); err != nil {
+			if err == sql.ErrNoRows {
+				return entities.ErrCityNotFound
+			}
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `UPDATE drivers SET resolved_city = NULL WHERE resolved_city = $1`, key)
+		return err
+	})
+	if err != nil {
+		if err == entities.ErrCityNotFound {
+			return err
+		}
+		r.logger.Error("Failed to delete city",
+			zap.Error(err),
+			zap.String("city_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete city: %w", err)
+	}
+
+	return nil
+}
+
+// List получает страницу городов в порядке названия
+func (r *cityRepository) List(ctx context.Context, filters *entities.CityFilters) ([]*entities.City, error) {
+	where, args := cityConditions(filters)
+	query := `SELECT * FROM cities` + where + ` ORDER BY name, id`
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit, filters.Offset)
+		query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+	}
+
+	var cities []*entities.City
+	if err := r.db.SelectContext(ctx, &cities, query, args...); err != nil {
+		r.logger.Error("Failed to list cities", zap.Error(err))
+		return nil, fmt.Errorf("failed to list cities: %w", err)
+	}
+	return cities, nil
+}
+
+// Count возвращает число городов по фильтрам
+func (r *cityRepository) Count(ctx context.Context, filters *entities.CityFilters) (int, error) {
+	where, args := cityConditions(filters)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM cities`+where, args...); err != nil {
+		r.logger.Error("Failed to count cities", zap.Error(err))
+		return 0, fmt.Errorf("failed to count cities: %w", err)
+	}
+	return count, nil
+}
+
+// cityConditions строит условие WHERE по фильтрам городов
+func cityConditions(filters *entities.CityFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if filters.RegionID != nil {
+		args = append(args, *filters.RegionID)
+		conditions = append(conditions, fmt.Sprintf("region_id = $%d", len(args)))
+	}
+	if filters.Active != nil {
+		args = append(args, *filters.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListActive получает активные города
+func (r *cityRepository) ListActive(ctx context.Context) ([]*entities.City, error) {
+	var cities []*entities.City
+	if err := r.db.SelectContext(ctx, &cities, `SELECT * FROM cities WHERE active ORDER BY created_at, id`); err != nil {
+		r.logger.Error("Failed to list active cities", zap.Error(err))
+		return nil, fmt.Errorf("failed to list active cities: %w", err)
+	}
+	return cities, nil
+}
+
+// AssignDriver записывает водителю город, определенный по местоположению
+func (r *cityRepository) AssignDriver(ctx context.Context, driverID uuid.UUID, city string) error {
+	result, err := r.db.ExecIdempotentContext(ctx,
+		`UPDATE drivers SET resolved_city = $1 WHERE id = $2 AND deleted_at IS NULL`, city, driverID)
+	if err != nil {
+		r.logger.Error("Failed to assign driver city",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("city", city),
+		)
+		return fmt.Errorf("failed to assign driver city: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrDriverNotFound
+	}
+
+	return nil
+}
+
+// CountActiveDrivers считает активных водителей по городам
+func (r *cityRepository) CountActiveDrivers(ctx context.Context) ([]*entities.CityDriverCount, error) {
+	query := `
+		SELECT
+			NULLIF(COALESCE(resolved_city, shard_key), '') AS city,
+			COUNT(*) FILTER (WHERE status = 'available') AS available,
+			COUNT(*) FILTER (WHERE status = 'on_shift') AS on_shift,
+			COUNT(*) FILTER (WHERE status = 'busy') AS busy,
+			COUNT(*) AS total
+		FROM drivers
+		WHERE status IN ('available', 'on_shift', 'busy') AND deleted_at IS NULL
+		GROUP BY 1
+		ORDER BY total DESC, city`
+
+	var counts []*entities.CityDriverCount
+	if err := r.db.SelectContext(ctx, &counts, query); err != nil {
+		r.logger.Error("Failed to count active drivers by city", zap.Error(err))
+		return nil, fmt.Errorf("failed to count active drivers by city: %w", err)
+	}
+	return counts, nil
+}
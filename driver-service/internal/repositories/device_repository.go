@@ -0,0 +1,159 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DeviceRepository интерфейс для устройств водителей
+type DeviceRepository interface {
+	// Register сохраняет устройство. Повторная регистрация того же устройства водителя
+	// обновляет данные приложения и снимает отзыв; ID и время первой регистрации
+	// сохраняются и записываются в device
+	Register(ctx context.Context, device *entities.DriverDevice) error
+	// Get получает устройство водителя по ID устройства
+	Get(ctx context.Context, driverID uuid.UUID, deviceID string) (*entities.DriverDevice, error)
+	// GetActiveByDeviceID получает неотозванное устройство по его ID среди всех водителей.
+	// Если устройство зарегистрировано у нескольких водителей, возвращается последнее
+	// активное: трекер автомобиля переходит к водителю, который зарегистрировал его последним
+	GetActiveByDeviceID(ctx context.Context, deviceID string) (*entities.DriverDevice, error)
+	// ListByDriverID возвращает устройства водителя, последние активные первыми
+	ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDevice, error)
+	// Revoke сохраняет отзыв устройства
+	Revoke(ctx context.Context, device *entities.DriverDevice) error
+}
+
+// deviceRepository реализация DeviceRepository
+type deviceRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewDeviceRepository создает новый репозиторий устройств водителей
+func NewDeviceRepository(db *database.DB, logger *zap.Logger) DeviceRepository {
+	return &deviceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Register создает или обновляет устройство водителя
+func (r *deviceRepository) Register(ctx context.Context, device *entities.DriverDevice) error {
+	query := `
+		INSERT INTO driver_devices (
+			id, driver_id, device_id, platform, app_version, push_token,
+			registered_at, last_seen_at, revoked_at
+		) VALUES (
+			:id, :driver_id, :device_id, :platform, :app_version, :push_token,
+			:registered_at, :last_seen_at, :revoked_at
+		)
+		ON CONFLICT (driver_id, device_id) DO UPDATE SET
+			platform = EXCLUDED.platform, app_version = EXCLUDED.app_version,
+			push_token = EXCLUDED.push_token,
+			last_seen_at = GREATEST(driver_devices.last_seen_at, EXCLUDED.last_seen_at),
+			revoked_at = NULL
+		RETURNING id, registered_at, last_seen_at`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, device)
+	if err != nil {
+		r.logger.Error("Failed to register driver device",
+			zap.Error(err),
+			zap.String("driver_id", device.DriverID.String()),
+		)
+		return fmt.Errorf("failed to register driver device: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&device.ID, &device.RegisteredAt, &device.LastSeenAt); err != nil {
+			return fmt.Errorf("failed to scan driver device: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+// Get получает устройство водителя
+func (r *deviceRepository) Get(ctx context.Context, driverID uuid.UUID, deviceID string) (*entities.DriverDevice, error) {
+	var device entities.DriverDevice
+	query := `SELECT * FROM driver_devices WHERE driver_id = $1 AND device_id = $2`
+	if err := r.db.GetContext(ctx, &device, query, driverID, deviceID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDeviceNotFound
+		}
+		r.logger.Error("Failed to get driver device", zap.Error(err))
+		return nil, fmt.Errorf("failed to get driver device: %w", err)
+	}
+
+	return &device, nil
+}
+
+// GetActiveByDeviceID получает последнее активное неотозванное устройство с этим ID
+func (r *deviceRepository) GetActiveByDeviceID(ctx context.Context, deviceID string) (*entities.DriverDevice, error) {
+	var device entities.DriverDevice
+	query := `
+		SELECT * FROM driver_devices
+		WHERE device_id = $1 AND revoked_at IS NULL
+		ORDER BY last_seen_at DESC
+		LIMIT 1`
+	if err := r.db.GetContext(ctx, &device, query, deviceID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDeviceNotFound
+		}
+		r.logger.Error("Failed to get driver device by device ID", zap.Error(err))
+		return nil, fmt.Errorf("failed to get driver device: %w", err)
+	}
+
+	return &device, nil
+}
+
+// ListByDriverID получает устройства водителя
+func (r *deviceRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDevice, error) {
+	query := `
+		SELECT * FROM driver_devices
+		WHERE driver_id = $1
+		ORDER BY last_seen_at DESC`
+
+	var devices []*entities.DriverDevice
+	if err := r.db.SelectContext(ctx, &devices, query, driverID); err != nil {
+		r.logger.Error("Failed to list driver devices",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// Revoke отзывает устройство и удаляет его токен push-уведомлений
+func (r *deviceRepository) Revoke(ctx context.Context, device *entities.DriverDevice) error {
+	result, err := r.db.ExecIdempotentContext(ctx,
+		`UPDATE driver_devices SET revoked_at = $2, push_token = NULL WHERE id = $1`,
+		device.ID, device.RevokedAt,
+	)
+	if err != nil {
+		r.logger.Error("Failed to revoke driver device",
+			zap.Error(err),
+			zap.String("driver_id", device.DriverID.String()),
+		)
+		return fmt.Errorf("failed to revoke driver device: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrDeviceNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// DispatchRepository интерфейс для ответов водителей на предложения заказов
+type DispatchRepository interface {
+	// RecordOffer сохраняет ответ водителя; повторный ответ на то же предложение игнорируется
+	RecordOffer(ctx context.Context, offer *entities.DispatchOffer) error
+	// RecordCancellation отмечает принятое предложение отмененным водителем; повторная отмена
+	// игнорируется. Если водитель не принимал этот заказ, возвращает ErrDispatchOfferNotAccepted
+	RecordCancellation(ctx context.Context, driverID, orderID uuid.UUID, cancelledAt time.Time) error
+	// GetStats возвращает статистику ответов водителей начиная с since; водители без
+	// ответов в результат не попадают
+	GetStats(ctx context.Context, driverIDs []uuid.UUID, since time.Time) (map[uuid.UUID]*entities.DispatchStats, error)
+}
+
+// dispatchRepository реализация DispatchRepository
+type dispatchRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewDispatchRepository создает новый репозиторий ответов на предложения заказов
+func NewDispatchRepository(db *database.DB, logger *zap.Logger) DispatchRepository {
+	return &dispatchRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// RecordOffer сохраняет ответ водителя на предложение заказа
+func (r *dispatchRepository) RecordOffer(ctx context.Context, offer *entities.DispatchOffer) error {
+	query := `
+		INSERT INTO dispatch_offers (driver_id, order_id, accepted, responded_at)
+		VALUES (:driver_id, :order_id, :accepted, :responded_at)
+		ON CONFLICT (driver_id, order_id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, offer); err != nil {
+		r.logger.Error("Failed to record dispatch offer",
+			zap.Error(err),
+			zap.String("driver_id", offer.DriverID.String()),
+			zap.String("order_id", offer.OrderID.String()),
+		)
+		return fmt.Errorf("failed to record dispatch offer: %w", err)
+	}
+
+	return nil
+}
+
+// RecordCancellation сохраняет отмену принятого предложения
+func (r *dispatchRepository) RecordCancellation(ctx context.Context, driverID, orderID uuid.UUID, cancelledAt time.Time) error {
+	query := `
+		UPDATE dispatch_offers
+		SET cancelled_at = COALESCE(cancelled_at, $3)
+		WHERE driver_id = $1 AND order_id = $2 AND accepted`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, driverID, orderID, cancelledAt)
+	if err != nil {
+		r.logger.Error("Failed to record dispatch cancellation",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("order_id", orderID.String()),
+		)
+		return fmt.Errorf("failed to record dispatch cancellation: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDispatchOfferNotAccepted
+	}
+
+	return nil
+}
+
+// GetStats считает ответы водителей за окно
+func (r *dispatchRepository) GetStats(ctx context.Context, driverIDs []uuid.UUID, since time.Time) (map[uuid.UUID]*entities.DispatchStats, error) {
+	stats := make(map[uuid.UUID]*entities.DispatchStats, len(driverIDs))
+	if len(driverIDs) == 0 {
+		return stats, nil
+	}
+
+	query := `
+		SELECT driver_id,
+			COUNT(*) AS offers_received,
+			COUNT(*) FILTER (WHERE accepted) AS offers_accepted,
+			COUNT(*) FILTER (WHERE cancelled_at IS NOT NULL) AS offers_cancelled,
+			MAX(responded_at) FILTER (WHERE accepted) AS last_accepted_at
+		FROM dispatch_offers
+		WHERE driver_id = ANY($1) AND responded_at >= $2
+		GROUP BY driver_id`
+
+	var rows []*entities.DispatchStats
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(driverIDs), since); err != nil {
+		r.logger.Error("Failed to get dispatch stats",
+			zap.Error(err),
+			zap.Int("drivers", len(driverIDs)),
+		)
+		return nil, fmt.Errorf("failed to get dispatch stats: %w", err)
+	}
+
+	for _, row := range rows {
+		stats[row.DriverID] = row
+	}
+	return stats, nil
+}
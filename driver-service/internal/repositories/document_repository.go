@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -11,6 +12,8 @@ import (
 	"driver-service/internal/infrastructure/database"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -19,7 +22,6 @@ type DocumentRepository interface {
 	Create(ctx context.Context, document *entities.DriverDocument) error
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverDocument, error)
 	GetByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDocument, error)
-	GetByDriverIDAndType(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DriverDocument, error)
 	Update(ctx context.Context, document *entities.DriverDocument) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filters *entities.DocumentFilters) ([]*entities.DriverDocument, error)
@@ -28,6 +30,36 @@ type DocumentRepository interface {
 	GetExpiring(ctx context.Context, days int) ([]*entities.DriverDocument, error)
 	GetExpired(ctx context.Context) ([]*entities.DriverDocument, error)
 	MarkExpired(ctx context.Context, documentIDs []uuid.UUID) error
+	ClaimPending(ctx context.Context, verifierID string, limit int, ttl time.Duration) ([]*entities.DriverDocument, error)
+	ResolveClaim(ctx context.Context, id uuid.UUID, verifierID string, status entities.VerificationStatus, reason *string) error
+	ReleaseStaleClaims(ctx context.Context) (int, error)
+	GetVerifierStats(ctx context.Context, from, to time.Time) ([]*entities.VerifierStats, error)
+	GetPendingRenewal(ctx context.Context, documentID uuid.UUID) (*entities.DriverDocument, error)
+	// Supersede отмечает документ id замененным и делает текущей подтвержденную новую версию
+	// currentID атомарно
+	Supersede(ctx context.Context, id, currentID uuid.UUID) error
+	// GetCurrent возвращает текущую версию документа водителя типа docType
+	GetCurrent(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DriverDocument, error)
+	// GetHistory возвращает прежние версии документа водителя типа docType от новых к старым:
+	// замененные версии и продления, еще не ставшие текущими
+	GetHistory(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) ([]*entities.DriverDocument, error)
+	// RequireReverification переводит подтвержденные документы в перепроверку со сроком verifyBy
+	RequireReverification(ctx context.Context, documentIDs []uuid.UUID, verifyBy time.Time) (int, error)
+	// ClearReverification возвращает документы, ожидающие перепроверки, в подтвержденные
+	ClearReverification(ctx context.Context, documentIDs []uuid.UUID) (int, error)
+	// ErasePersonalData удаляет номера, ссылки на файлы и метаданные всех документов водителя,
+	// сохраняя типы, сроки и статусы проверки, и возвращает число документов
+	ErasePersonalData(ctx context.Context, driverID uuid.UUID) (int, error)
+	// SetOCRResult сохраняет результат распознавания документа, не меняя остальных полей
+	SetOCRResult(ctx context.Context, id uuid.UUID, result *entities.DocumentOCRResult) error
+	// Resubmit сохраняет прежнюю версию отклоненного документа previous и новую версию
+	// document атомарно; документ, уже не отклоненный, возвращает ErrDocumentNotResubmittable
+	Resubmit(ctx context.Context, document *entities.DriverDocument, previous *entities.DocumentVersion) error
+	// GetVersions возвращает прежние версии документа от новых к старым
+	GetVersions(ctx context.Context, documentID uuid.UUID) ([]*entities.DocumentVersion, error)
+	// GetResubmissionDue возвращает отклоненные документы без напоминания о повторной
+	// загрузке, отклоненные не позже rejectedBefore
+	GetResubmissionDue(ctx context.Context, rejectedBefore time.Time) ([]*entities.DriverDocument, error)
 }
 
 // documentRepository реализация DocumentRepository
@@ -49,14 +81,20 @@ func (r *documentRepository) Create(ctx context.Context, document *entities.Driv
 	query := `
 		INSERT INTO driver_documents (
 			id, driver_id, document_type, document_number, issue_date,
-			expiry_date, file_url, status, metadata, created_at, updated_at
+			expiry_date, file_url, status, replaces_id, verify_by, is_current,
+			metadata, created_at, updated_at
 		) VALUES (
 			:id, :driver_id, :document_type, :document_number, :issue_date,
-			:expiry_date, :file_url, :status, :metadata, :created_at, :updated_at
+			:expiry_date, :file_url, :status, :replaces_id, :verify_by, :is_current,
+			:metadata, :created_at, :updated_at
 		)`
 
 	_, err := r.db.NamedExecContext(ctx, query, document)
 	if err != nil {
+		// Вторая текущая версия того же типа или второе продление нарушают уникальные индексы
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("failed to create document: %w", entities.ErrDocumentExists)
+		}
 		r.logger.Error("Failed to create document",
 			zap.Error(err),
 			zap.String("document_id", document.ID.String()),
@@ -80,7 +118,7 @@ func (r *documentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entiti
 	var document entities.DriverDocument
 	query := `SELECT * FROM driver_documents WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &document, query, id)
+	err := r.db.ReplicaGetContext(ctx, &document, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, entities.ErrDocumentNotFound
@@ -115,31 +153,6 @@ func (r *documentRepository) GetByDriverID(ctx context.Context, driverID uuid.UU
 	return documents, nil
 }
 
-// GetByDriverIDAndType получает документ водителя определенного типа
-func (r *documentRepository) GetByDriverIDAndType(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DriverDocument, error) {
-	var document entities.DriverDocument
-	query := `
-		SELECT * FROM driver_documents 
-		WHERE driver_id = $1 AND document_type = $2
-		ORDER BY created_at DESC
-		LIMIT 1`
-
-	err := r.db.GetContext(ctx, &document, query, driverID, docType)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, entities.ErrDocumentNotFound
-		}
-		r.logger.Error("Failed to get document by driver ID and type",
-			zap.Error(err),
-			zap.String("driver_id", driverID.String()),
-			zap.String("document_type", string(docType)),
-		)
-		return nil, fmt.Errorf("failed to get document by driver ID and type: %w", err)
-	}
-
-	return &document, nil
-}
-
 // Update обновляет документ
 func (r *documentRepository) Update(ctx context.Context, document *entities.DriverDocument) error {
 	document.UpdatedAt = time.Now()
@@ -150,6 +163,8 @@ func (r *documentRepository) Update(ctx context.Context, document *entities.Driv
 			expiry_date = :expiry_date, file_url = :file_url, status = :status,
 			verified_by = :verified_by, verified_at = :verified_at,
 			rejection_reason = :rejection_reason, metadata = :metadata,
+			expiry_reminder_sent_at = :expiry_reminder_sent_at,
+			resubmission_reminder_sent_at = :resubmission_reminder_sent_at,
 			updated_at = :updated_at
 		WHERE id = :id`
 
@@ -215,7 +230,7 @@ func (r *documentRepository) List(ctx context.Context, filters *entities.Documen
 	}
 
 	var documents []*entities.DriverDocument
-	err = r.db.SelectContext(ctx, &documents, query, args...)
+	err = r.db.ReplicaSelectContext(ctx, &documents, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to list documents",
 			zap.Error(err),
@@ -234,7 +249,7 @@ func (r *documentRepository) Count(ctx context.Context, filters *entities.Docume
 	}
 
 	var count int
-	err = r.db.GetContext(ctx, &count, query, args...)
+	err = r.db.ReplicaGetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count documents",
 			zap.Error(err),
@@ -310,7 +325,7 @@ func (r *documentRepository) GetExpired(ctx context.Context) ([]*entities.Driver
 	query := `
 		SELECT * FROM driver_documents
 		WHERE expiry_date < NOW()
-		AND status IN ('verified', 'pending')
+		AND status IN ('verified', 'pending', 'reverification_required')
 		ORDER BY expiry_date DESC`
 
 	var documents []*entities.DriverDocument
@@ -368,6 +383,467 @@ func (r *documentRepository) MarkExpired(ctx context.Context, documentIDs []uuid
 	return nil
 }
 
+// ClaimPending захватывает до limit документов, ожидающих проверки: сначала продления
+// с ближайшим сроком проверки, затем самые старые документы.
+// FOR UPDATE SKIP LOCKED гарантирует, что параллельные верификаторы получат разные документы
+func (r *documentRepository) ClaimPending(ctx context.Context, verifierID string, limit int, ttl time.Duration) ([]*entities.DriverDocument, error) {
+	now := time.Now()
+	query := `
+		UPDATE driver_documents SET
+			status = 'processing', claimed_by = $1, claimed_at = $2,
+			claim_expires_at = $3, updated_at = $2
+		WHERE id IN (
+			SELECT id FROM driver_documents
+			WHERE status = 'pending'
+			ORDER BY verify_by ASC NULLS LAST, created_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	// Захват не повторяется: после обрыва соединения он мог быть применен
+	var documents []*entities.DriverDocument
+	err := r.db.SelectOnceContext(ctx, &documents, query, verifierID, now, now.Add(ttl), limit)
+	if err != nil {
+		r.logger.Error("Failed to claim pending documents",
+			zap.Error(err),
+			zap.String("verifier_id", verifierID),
+		)
+		return nil, fmt.Errorf("failed to claim pending documents: %w", err)
+	}
+
+	// RETURNING не сохраняет порядок подзапроса
+	sort.Slice(documents, func(i, j int) bool {
+		return entities.QueueBefore(documents[i], documents[j])
+	})
+
+	return documents, nil
+}
+
+// ResolveClaim сохраняет решение по документу, если верификатор удерживает действующий захват
+func (r *documentRepository) ResolveClaim(ctx context.Context, id uuid.UUID, verifierID string, status entities.VerificationStatus, reason *string) error {
+	now := time.Now()
+	query := `
+		UPDATE driver_documents SET
+			status = $1, verified_by = $2, verified_at = $3,
+			rejection_reason = $4, claim_expires_at = NULL, updated_at = $3
+		WHERE id = $5 AND status = 'processing'
+			AND claimed_by = $2 AND claim_expires_at > $3`
+
+	result, err := r.db.ExecContext(ctx, query, status, verifierID, now, reason, id)
+	if err != nil {
+		r.logger.Error("Failed to resolve document claim",
+			zap.Error(err),
+			zap.String("document_id", id.String()),
+			zap.String("verifier_id", verifierID),
+		)
+		return fmt.Errorf("failed to resolve document claim: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return err
+		}
+		return entities.ErrDocumentClaimNotHeld
+	}
+
+	return nil
+}
+
+// ReleaseStaleClaims возвращает в очередь документы с истекшим захватом
+func (r *documentRepository) ReleaseStaleClaims(ctx context.Context) (int, error) {
+	now := time.Now()
+	query := `
+		UPDATE driver_documents SET
+			status = 'pending', claimed_by = NULL, claimed_at = NULL,
+			claim_expires_at = NULL, updated_at = $1
+		WHERE status = 'processing' AND claim_expires_at <= $1`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, now)
+	if err != nil {
+		r.logger.Error("Failed to release stale document claims", zap.Error(err))
+		return 0, fmt.Errorf("failed to release stale document claims: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// GetVerifierStats получает число решений и среднее время обработки по верификаторам за период.
+// Истекшие, замененные и отправленные на перепроверку документы учитываются как подтвержденные
+func (r *documentRepository) GetVerifierStats(ctx context.Context, from, to time.Time) ([]*entities.VerifierStats, error) {
+	query := `
+		SELECT
+			verified_by AS verifier_id,
+			COUNT(*) FILTER (WHERE status IN ('verified', 'expired', 'superseded', 'reverification_required')) AS verified,
+			COUNT(*) FILTER (WHERE status = 'rejected') AS rejected,
+			COUNT(*) AS total,
+			COALESCE(AVG(EXTRACT(EPOCH FROM verified_at - claimed_at))
+				FILTER (WHERE claimed_at IS NOT NULL), 0)::DOUBLE PRECISION AS average_handling_seconds
+		FROM driver_documents
+		WHERE verified_by IS NOT NULL
+			AND verified_at >= $1 AND verified_at < $2
+			AND status IN ('verified', 'rejected', 'expired', 'superseded', 'reverification_required')
+		GROUP BY verified_by
+		ORDER BY total DESC, verified_by ASC`
+
+	var stats []*entities.VerifierStats
+	if err := r.db.SelectContext(ctx, &stats, query, from, to); err != nil {
+		r.logger.Error("Failed to get verifier stats", zap.Error(err))
+		return nil, fmt.Errorf("failed to get verifier stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetPendingRenewal получает новую версию документа, ожидающую проверки
+func (r *documentRepository) GetPendingRenewal(ctx context.Context, documentID uuid.UUID) (*entities.DriverDocument, error) {
+	var document entities.DriverDocument
+	query := `
+		SELECT * FROM driver_documents
+		WHERE replaces_id = $1 AND status IN ('pending', 'processing')
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &document, query, documentID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDocumentNotFound
+		}
+		r.logger.Error("Failed to get pending document renewal",
+			zap.Error(err),
+			zap.String("document_id", documentID.String()),
+		)
+		return nil, fmt.Errorf("failed to get pending document renewal: %w", err)
+	}
+
+	return &document, nil
+}
+
+// Supersede отмечает документ замененным подтвержденной новой версией и делает новую версию
+// текущей одной транзакцией: прежняя версия снимается первой, чтобы не нарушить индекс одной
+// текущей версии типа. Решение верификатора по самому документу сохраняется для статистики
+func (r *documentRepository) Supersede(ctx context.Context, id, currentID uuid.UUID) error {
+	supersedeQuery := `
+		UPDATE driver_documents SET status = 'superseded', is_current = FALSE, updated_at = $1
+		WHERE id = $2 AND status IN ('verified', 'expired', 'superseded', 'reverification_required')`
+	currentQuery := `
+		UPDATE driver_documents SET is_current = TRUE, updated_at = $1
+		WHERE id = $2 AND replaces_id = $3`
+
+	now := time.Now()
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, supersedeQuery, now, id)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return entities.ErrDocumentNotVerified
+		}
+
+		result, err = tx.ExecContext(ctx, currentQuery, now, currentID, id)
+		if err != nil {
+			return err
+		}
+		if rowsAffected, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return entities.ErrDocumentNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if err == entities.ErrDocumentNotVerified {
+			if _, err := r.GetByID(ctx, id); err != nil {
+				return err
+			}
+			return entities.ErrDocumentNotVerified
+		}
+		if err == entities.ErrDocumentNotFound {
+			return err
+		}
+		r.logger.Error("Failed to supersede document",
+			zap.Error(err),
+			zap.String("document_id", id.String()),
+			zap.String("current_id", currentID.String()),
+		)
+		return fmt.Errorf("failed to supersede document: %w", err)
+	}
+
+	return nil
+}
+
+// GetCurrent получает текущую версию документа водителя определенного типа
+func (r *documentRepository) GetCurrent(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DriverDocument, error) {
+	var document entities.DriverDocument
+	query := `
+		SELECT * FROM driver_documents
+		WHERE driver_id = $1 AND document_type = $2 AND is_current`
+
+	err := r.db.GetContext(ctx, &document, query, driverID, docType)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDocumentNotFound
+		}
+		r.logger.Error("Failed to get current document",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("document_type", string(docType)),
+		)
+		return nil, fmt.Errorf("failed to get current document: %w", err)
+	}
+
+	return &document, nil
+}
+
+// GetHistory получает прежние версии документа водителя определенного типа
+func (r *documentRepository) GetHistory(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) ([]*entities.DriverDocument, error) {
+	query := `
+		SELECT * FROM driver_documents
+		WHERE driver_id = $1 AND document_type = $2 AND NOT is_current
+		ORDER BY created_at DESC`
+
+	var documents []*entities.DriverDocument
+	if err := r.db.ReplicaSelectContext(ctx, &documents, query, driverID, docType); err != nil {
+		r.logger.Error("Failed to get document history",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("document_type", string(docType)),
+		)
+		return nil, fmt.Errorf("failed to get document history: %w", err)
+	}
+
+	return documents, nil
+}
+
+// RequireReverification переводит подтвержденные документы в перепроверку
+func (r *documentRepository) RequireReverification(ctx context.Context, documentIDs []uuid.UUID, verifyBy time.Time) (int, error) {
+	if len(documentIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE driver_documents SET status = 'reverification_required', verify_by = $1, updated_at = $2
+		WHERE id = ANY($3) AND status = 'verified'`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, verifyBy, time.Now(), pq.Array(documentIDs))
+	if err != nil {
+		r.logger.Error("Failed to require document reverification",
+			zap.Error(err),
+			zap.Int("document_count", len(documentIDs)),
+		)
+		return 0, fmt.Errorf("failed to require document reverification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ClearReverification возвращает документы, ожидающие перепроверки, в подтвержденные
+func (r *documentRepository) ClearReverification(ctx context.Context, documentIDs []uuid.UUID) (int, error) {
+	if len(documentIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		UPDATE driver_documents SET status = 'verified', verify_by = NULL, updated_at = $1
+		WHERE id = ANY($2) AND status = 'reverification_required'`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, time.Now(), pq.Array(documentIDs))
+	if err != nil {
+		r.logger.Error("Failed to clear document reverification",
+			zap.Error(err),
+			zap.Int("document_count", len(documentIDs)),
+		)
+		return 0, fmt.Errorf("failed to clear document reverification: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ErasePersonalData обезличивает документы водителя вместе с их прежними версиями
+func (r *documentRepository) ErasePersonalData(ctx context.Context, driverID uuid.UUID) (int, error) {
+	query := `
+		UPDATE driver_documents SET document_number = '', file_url = '', metadata = '{}',
+			ocr_result = NULL, updated_at = $1
+		WHERE driver_id = $2`
+
+	if _, err := r.db.ExecIdempotentContext(ctx, `
+		UPDATE document_versions SET document_number = '', file_url = ''
+		WHERE driver_id = $1`, driverID); err != nil {
+		r.logger.Error("Failed to erase document versions personal data",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to erase document versions personal data: %w", err)
+	}
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, time.Now(), driverID)
+	if err != nil {
+		r.logger.Error("Failed to erase document personal data",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to erase document personal data: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// SetOCRResult сохраняет результат распознавания документа
+func (r *documentRepository) SetOCRResult(ctx context.Context, id uuid.UUID, result *entities.DocumentOCRResult) error {
+	query := `UPDATE driver_documents SET ocr_result = $1 WHERE id = $2`
+
+	res, err := r.db.ExecIdempotentContext(ctx, query, result, id)
+	if err != nil {
+		r.logger.Error("Failed to save document OCR result",
+			zap.Error(err),
+			zap.String("document_id", id.String()),
+		)
+		return fmt.Errorf("failed to save document OCR result: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDocumentNotFound
+	}
+
+	return nil
+}
+
+// Resubmit сохраняет прежнюю версию и обновляет документ одной транзакцией. Условие на статус
+// не дает двум параллельным загрузкам заменить одну и ту же отклоненную версию
+func (r *documentRepository) Resubmit(ctx context.Context, document *entities.DriverDocument, previous *entities.DocumentVersion) error {
+	insertQuery := `
+		INSERT INTO document_versions (
+			id, document_id, driver_id, version, document_number, issue_date,
+			expiry_date, file_url, status, verified_by, verified_at,
+			rejection_reason, submitted_at, replaced_at
+		) VALUES (
+			:id, :document_id, :driver_id, :version, :document_number, :issue_date,
+			:expiry_date, :file_url, :status, :verified_by, :verified_at,
+			:rejection_reason, :submitted_at, :replaced_at
+		)`
+	updateQuery := `
+		UPDATE driver_documents SET
+			document_number = :document_number, issue_date = :issue_date,
+			expiry_date = :expiry_date, file_url = :file_url, status = :status,
+			verified_by = NULL, verified_at = NULL, rejection_reason = NULL,
+			claimed_by = NULL, claimed_at = NULL, claim_expires_at = NULL,
+			ocr_result = NULL, resubmission_count = :resubmission_count,
+			resubmitted_at = :resubmitted_at, resubmission_reminder_sent_at = NULL,
+			updated_at = :updated_at
+		WHERE id = :id AND status = 'rejected' AND replaces_id IS NULL`
+
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.NamedExecContext(ctx, insertQuery, previous); err != nil {
+			// Версия с тем же номером уже сохранена параллельной загрузкой
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return entities.ErrDocumentNotResubmittable
+			}
+			return err
+		}
+
+		result, err := tx.NamedExecContext(ctx, updateQuery, document)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return entities.ErrDocumentNotResubmittable
+		}
+		return nil
+	})
+	if err != nil {
+		if err == entities.ErrDocumentNotResubmittable {
+			return err
+		}
+		r.logger.Error("Failed to resubmit document",
+			zap.Error(err),
+			zap.String("document_id", document.ID.String()),
+		)
+		return fmt.Errorf("failed to resubmit document: %w", err)
+	}
+
+	r.logger.Info("Document resubmitted successfully",
+		zap.String("document_id", document.ID.String()),
+		zap.Int("resubmission_count", document.ResubmissionCount),
+	)
+
+	return nil
+}
+
+// GetVersions получает прежние версии документа
+func (r *documentRepository) GetVersions(ctx context.Context, documentID uuid.UUID) ([]*entities.DocumentVersion, error) {
+	query := `
+		SELECT * FROM document_versions
+		WHERE document_id = $1
+		ORDER BY version DESC`
+
+	var versions []*entities.DocumentVersion
+	if err := r.db.ReplicaSelectContext(ctx, &versions, query, documentID); err != nil {
+		r.logger.Error("Failed to get document versions",
+			zap.Error(err),
+			zap.String("document_id", documentID.String()),
+		)
+		return nil, fmt.Errorf("failed to get document versions: %w", err)
+	}
+
+	return versions, nil
+}
+
+// GetResubmissionDue получает отклоненные документы, ожидающие повторной загрузки
+func (r *documentRepository) GetResubmissionDue(ctx context.Context, rejectedBefore time.Time) ([]*entities.DriverDocument, error) {
+	query := `
+		SELECT * FROM driver_documents
+		WHERE status = 'rejected' AND replaces_id IS NULL
+		AND resubmission_reminder_sent_at IS NULL
+		AND verified_at <= $1
+		ORDER BY verified_at ASC`
+
+	var documents []*entities.DriverDocument
+	if err := r.db.SelectContext(ctx, &documents, query, rejectedBefore); err != nil {
+		r.logger.Error("Failed to get documents due for resubmission", zap.Error(err))
+		return nil, fmt.Errorf("failed to get documents due for resubmission: %w", err)
+	}
+
+	return documents, nil
+}
+
 // buildListQuery строит SQL запрос для получения списка документов
 func (r *documentRepository) buildListQuery(filters *entities.DocumentFilters, isCount bool) (string, []interface{}, error) {
 	var conditions []string
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// DriverDailyStatsRepository интерфейс для суточных показателей водителей
+type DriverDailyStatsRepository interface {
+	// GetCoverage возвращает сохраненные сутки; nil, если показатели еще не сохранялись
+	GetCoverage(ctx context.Context) (*entities.DailyStatsCoverage, error)
+	// ReplaceDay заменяет показатели всех водителей за сутки day и сохраняет покрытие
+	// одной транзакцией, чтобы прерванный пересчет не оставил сутки неполными
+	ReplaceDay(ctx context.Context, day time.Time, stats []*entities.DriverDailyStats, coverage *entities.DailyStatsCoverage) error
+	// ListByDriverID возвращает показатели водителя за сутки в [from, to) по возрастанию
+	ListByDriverID(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverDailyStats, error)
+	// GetActivity суммирует пробег и время на связи водителя за сутки в [from, to)
+	GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.LocationActivity, error)
+}
+
+// driverDailyStatsRepository реализация DriverDailyStatsRepository
+type driverDailyStatsRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewDriverDailyStatsRepository создает новый репозиторий суточных показателей водителей
+func NewDriverDailyStatsRepository(db *database.DB, logger *zap.Logger) DriverDailyStatsRepository {
+	return &driverDailyStatsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCoverage получает сохраненные сутки
+func (r *driverDailyStatsRepository) GetCoverage(ctx context.Context) (*entities.DailyStatsCoverage, error) {
+	var coverage entities.DailyStatsCoverage
+	query := `SELECT covered_from, refreshed_through, refreshed_at FROM driver_daily_stats_coverage`
+	if err := r.db.GetContext(ctx, &coverage, query); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to get daily stats coverage", zap.Error(err))
+		return nil, fmt.Errorf("failed to get daily stats coverage: %w", err)
+	}
+	return &coverage, nil
+}
+
+// ReplaceDay заменяет показатели за сутки
+func (r *driverDailyStatsRepository) ReplaceDay(ctx context.Context, day time.Time, stats []*entities.DriverDailyStats, coverage *entities.DailyStatsCoverage) error {
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM driver_daily_stats WHERE day = $1`, day); err != nil {
+			return err
+		}
+
+		if len(stats) > 0 {
+			_, err := tx.NamedExecContext(ctx, `
+				INSERT INTO driver_daily_stats (
+					driver_id, day, trips, distance_km, online_seconds, rating_sum, rating_count, updated_at
+				) VALUES (
+					:driver_id, :day, :trips, :distance_km, :online_seconds, :rating_sum, :rating_count, :updated_at
+				)`, stats)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.NamedExecContext(ctx, `
+			INSERT INTO driver_daily_stats_coverage (id, covered_from, refreshed_through, refreshed_at)
+			VALUES (TRUE, :covered_from, :refreshed_through, :refreshed_at)
+			ON CONFLICT (id) DO UPDATE SET
+				covered_from = EXCLUDED.covered_from,
+				refreshed_through = EXCLUDED.refreshed_through,
+				refreshed_at = EXCLUDED.refreshed_at`, coverage)
+		return err
+	})
+	if err != nil {
+		r.logger.Error("Failed to replace daily driver stats",
+			zap.Error(err),
+			zap.Time("day", day),
+			zap.Int("count", len(stats)),
+		)
+		return fmt.Errorf("failed to replace daily driver stats: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDriverID получает показатели водителя за период
+func (r *driverDailyStatsRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverDailyStats, error) {
+	query := `
+		SELECT * FROM driver_daily_stats
+		WHERE driver_id = $1 AND day >= $2 AND day < $3
+		ORDER BY day ASC`
+
+	var stats []*entities.DriverDailyStats
+	if err := r.db.ReplicaSelectContext(ctx, &stats, query, driverID, from, to); err != nil {
+		r.logger.Error("Failed to list daily driver stats",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list daily driver stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetActivity суммирует показатели местоположений водителя за период
+func (r *driverDailyStatsRepository) GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.LocationActivity, error) {
+	query := `
+		SELECT COALESCE(SUM(distance_km), 0) AS distance_km, COALESCE(SUM(online_seconds), 0)::BIGINT AS online_seconds
+		FROM driver_daily_stats
+		WHERE driver_id = $1 AND day >= $2 AND day < $3`
+
+	var activity entities.LocationActivity
+	if err := r.db.ReplicaGetContext(ctx, &activity, query, driverID, from, to); err != nil {
+		r.logger.Error("Failed to get daily driver activity",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get daily driver activity: %w", err)
+	}
+	return &activity, nil
+}
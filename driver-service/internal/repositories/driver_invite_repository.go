@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverInviteRepository интерфейс для приглашений водителей
+type DriverInviteRepository interface {
+	Create(ctx context.Context, invite *entities.DriverInvite) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverInvite, error)
+	// List возвращает приглашения, новые первыми
+	List(ctx context.Context, limit, offset int) ([]*entities.DriverInvite, error)
+	// Accept сохраняет регистрацию водителя по приглашению. Приглашение, которое уже
+	// использовано или отозвано, не изменяется: возвращается ErrDriverInviteUsed или ErrDriverInviteRevoked
+	Accept(ctx context.Context, invite *entities.DriverInvite) error
+	// Revoke сохраняет отзыв приглашения с теми же ошибками, что и Accept
+	Revoke(ctx context.Context, invite *entities.DriverInvite) error
+}
+
+// driverInviteRepository реализация DriverInviteRepository
+type driverInviteRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewDriverInviteRepository создает новый репозиторий приглашений водителей
+func NewDriverInviteRepository(db *database.DB, logger *zap.Logger) DriverInviteRepository {
+	return &driverInviteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет приглашение
+func (r *driverInviteRepository) Create(ctx context.Context, invite *entities.DriverInvite) error {
+	query := `
+		INSERT INTO driver_invites (id, phone, fleet_id, status, expires_at, created_by, created_at)
+		VALUES (:id, :phone, :fleet_id, :status, :expires_at, :created_by, :created_at)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, invite); err != nil {
+		r.logger.Error("Failed to create driver invite",
+			zap.Error(err),
+			zap.String("fleet_id", invite.FleetID.String()),
+		)
+		return fmt.Errorf("failed to create driver invite: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает приглашение по ID
+func (r *driverInviteRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverInvite, error) {
+	var invite entities.DriverInvite
+	if err := r.db.GetContext(ctx, &invite, `SELECT * FROM driver_invites WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDriverInviteNotFound
+		}
+		r.logger.Error("Failed to get driver invite", zap.Error(err), zap.String("invite_id", id.String()))
+		return nil, fmt.Errorf("failed to get driver invite: %w", err)
+	}
+
+	return &invite, nil
+}
+
+// List получает приглашения, новые первыми
+func (r *driverInviteRepository) List(ctx context.Context, limit, offset int) ([]*entities.DriverInvite, error) {
+	query := `
+		SELECT * FROM driver_invites
+		ORDER BY created_at DESC, id
+		LIMIT $1 OFFSET $2`
+
+	var invites []*entities.DriverInvite
+	if err := r.db.ReplicaSelectContext(ctx, &invites, query, limit, offset); err != nil {
+		r.logger.Error("Failed to list driver invites", zap.Error(err))
+		return nil, fmt.Errorf("failed to list driver invites: %w", err)
+	}
+
+	return invites, nil
+}
+
+// Accept сохраняет регистрацию по приглашению, если оно еще не использовано и не отозвано
+func (r *driverInviteRepository) Accept(ctx context.Context, invite *entities.DriverInvite) error {
+	query := `
+		UPDATE driver_invites SET status = :status, driver_id = :driver_id, accepted_at = :accepted_at
+		WHERE id = :id AND status = 'pending'`
+
+	return r.transition(ctx, query, invite, "accept")
+}
+
+// Revoke сохраняет отзыв приглашения, если оно еще не использовано и не отозвано
+func (r *driverInviteRepository) Revoke(ctx context.Context, invite *entities.DriverInvite) error {
+	query := `
+		UPDATE driver_invites SET status = :status, revoked_at = :revoked_at, revoked_by = :revoked_by
+		WHERE id = :id AND status = 'pending'`
+
+	return r.transition(ctx, query, invite, "revoke")
+}
+
+// transition выполняет переход из pending. Если приглашение уже не в pending, возвращает
+// ошибку по его сохраненному состоянию
+func (r *driverInviteRepository) transition(ctx context.Context, query string, invite *entities.DriverInvite, action string) error {
+	result, err := r.db.NamedExecContext(ctx, query, invite)
+	if err != nil {
+		r.logger.Error("Failed to update driver invite",
+			zap.Error(err),
+			zap.String("invite_id", invite.ID.String()),
+			zap.String("action", action),
+		)
+		return fmt.Errorf("failed to %s driver invite: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		stored, err := r.GetByID(ctx, invite.ID)
+		if err != nil {
+			return err
+		}
+		if stored.Status == entities.DriverInviteStatusRevoked {
+			return entities.ErrDriverInviteRevoked
+		}
+		return entities.ErrDriverInviteUsed
+	}
+
+	return nil
+}
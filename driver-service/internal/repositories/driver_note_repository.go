@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DriverNoteRepository интерфейс для внутренних заметок о водителях
+type DriverNoteRepository interface {
+	Create(ctx context.Context, note *entities.DriverNote) error
+	// ListByDriver возвращает заметки водителя, новые первыми
+	ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.DriverNote, error)
+}
+
+// driverNoteRepository реализация DriverNoteRepository
+type driverNoteRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewDriverNoteRepository создает новый репозиторий заметок о водителях
+func NewDriverNoteRepository(db *database.DB, logger *zap.Logger) DriverNoteRepository {
+	return &driverNoteRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет заметку
+func (r *driverNoteRepository) Create(ctx context.Context, note *entities.DriverNote) error {
+	query := `
+		INSERT INTO driver_notes (id, driver_id, body, author, author_roles, created_at)
+		VALUES (:id, :driver_id, :body, :author, :author_roles, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, note); err != nil {
+		r.logger.Error("Failed to create driver note",
+			zap.Error(err),
+			zap.String("driver_id", note.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create driver note: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDriver получает заметки водителя, новые первыми
+func (r *driverNoteRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.DriverNote, error) {
+	query := `
+		SELECT * FROM driver_notes
+		WHERE driver_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`
+
+	var notes []*entities.DriverNote
+	if err := r.db.ReplicaSelectContext(ctx, &notes, query, driverID, limit, offset); err != nil {
+		r.logger.Error("Failed to list driver notes",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver notes: %w", err)
+	}
+
+	return notes, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +13,8 @@ import (
 	"driver-service/internal/infrastructure/database"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
@@ -27,10 +30,29 @@ type DriverRepository interface {
 	SoftDelete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, filters *entities.DriverFilters) ([]*entities.Driver, error)
 	Count(ctx context.Context, filters *entities.DriverFilters) (int, error)
-	Exists(ctx context.Context, phone, licenseNumber string) (bool, error)
+	// Exists проверяет, есть ли неудаленный водитель с таким телефоном, email или номером
+	// лицензии; пустые значения не проверяются
+	Exists(ctx context.Context, phone, email, licenseNumber string) (bool, error)
+	// GetDeletedByPhone получает последнего удаленного водителя с номером телефона
+	GetDeletedByPhone(ctx context.Context, phone string) (*entities.Driver, error)
 	UpdateStatus(ctx context.Context, id uuid.UUID, status entities.Status) error
+	// UpdateStatuses применяет смены статуса в одной транзакции; смена пропускается, если статус
+	// водителя уже не равен From. Возвращает водителей, статус которых изменен. С allOrNothing
+	// любая пропущенная смена откатывает все и возвращает ErrStatusConflict
+	UpdateStatuses(ctx context.Context, updates []entities.StatusUpdate, allOrNothing bool) ([]uuid.UUID, error)
 	UpdateRating(ctx context.Context, id uuid.UUID, rating float64) error
+	// LockRating блокирует строку водителя до конца транзакции из контекста и возвращает его
+	// текущий рейтинг: параллельные пересчеты рейтинга одного водителя выполняются по очереди
+	LockRating(ctx context.Context, id uuid.UUID) (float64, error)
 	IncrementTripCount(ctx context.Context, id uuid.UUID) error
+	UpdatePaymentHold(ctx context.Context, id uuid.UUID, hold bool, reason *string) error
+	// UpdateTags заменяет метки водителя
+	UpdateTags(ctx context.Context, id uuid.UUID, tags entities.DriverTagList) error
+	// SetPhoneVerified отмечает телефон водителя подтвержденным, если номер все еще равен phone;
+	// иначе возвращает ErrDriverNotFound
+	SetPhoneVerified(ctx context.Context, id uuid.UUID, phone string, at time.Time) error
+	// UpdateDispatchRates сохраняет доли принятых и отмененных предложений водителя
+	UpdateDispatchRates(ctx context.Context, id uuid.UUID, acceptanceRate, cancellationRate *float64) error
 	GetActiveDrivers(ctx context.Context) ([]*entities.Driver, error)
 }
 
@@ -55,14 +77,19 @@ func (r *driverRepository) Create(ctx context.Context, driver *entities.Driver)
 			id, phone, email, first_name, last_name, middle_name,
 			birth_date, passport_series, passport_number, license_number,
 			license_expiry, status, current_rating, total_trips, metadata,
-			created_at, updated_at
+			shard_key, fleet_id, tags, created_at, updated_at
 		) VALUES (
 			:id, :phone, :email, :first_name, :last_name, :middle_name,
 			:birth_date, :passport_series, :passport_number, :license_number,
 			:license_expiry, :status, :current_rating, :total_trips, :metadata,
-			:created_at, :updated_at
+			:shard_key, :fleet_id, :tags, :created_at, :updated_at
 		)`
 
+	// Ключ шарда вычисляется из города в метаданных
+	driver.RefreshShardKey()
+	// Версия статуса новой записи задается умолчанием колонки
+	driver.StatusVersion = 1
+
 	// Сериализуем metadata в JSON
 	metadataBytes, err := json.Marshal(driver.Metadata)
 	if err != nil {
@@ -86,12 +113,26 @@ func (r *driverRepository) Create(ctx context.Context, driver *entities.Driver)
 		"current_rating":  driver.CurrentRating,
 		"total_trips":     driver.TotalTrips,
 		"metadata":        string(metadataBytes),
+		"shard_key":       driver.ShardKey,
+		"fleet_id":        driver.FleetID,
+		"tags":            driver.Tags,
 		"created_at":      driver.CreatedAt,
 		"updated_at":      driver.UpdatedAt,
 	}
 
-	_, err = r.db.NamedExecContext(ctx, query, params)
+	// Первая запись истории статусов создается вместе с водителем
+	err = r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.NamedExecContext(ctx, query, params); err != nil {
+			return err
+		}
+		return recordStatusTransition(ctx, tx, driver.ID, nil, driver.Status, driver.CreatedAt)
+	})
 	if err != nil {
+		// Неудаленный водитель с тем же телефоном, email или лицензией зарегистрирован параллельно
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return fmt.Errorf("failed to create driver: %w", entities.ErrDriverExists)
+		}
 		r.logger.Error("Failed to create driver",
 			zap.Error(err),
 			zap.String("driver_id", driver.ID.String()),
@@ -115,7 +156,7 @@ func (r *driverRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities
 		SELECT * FROM drivers 
 		WHERE id = $1 AND deleted_at IS NULL`
 
-	err := r.db.GetContext(ctx, &driver, query, id)
+	err := r.db.ReplicaGetContext(ctx, &driver, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, entities.ErrDriverNotFound
@@ -199,6 +240,7 @@ func (r *driverRepository) GetByLicenseNumber(ctx context.Context, licenseNumber
 // Update обновляет данные водителя
 func (r *driverRepository) Update(ctx context.Context, driver *entities.Driver) error {
 	driver.UpdatedAt = time.Now()
+	driver.RefreshShardKey()
 
 	query := `
 		UPDATE drivers SET
@@ -208,11 +250,29 @@ func (r *driverRepository) Update(ctx context.Context, driver *entities.Driver)
 			passport_number = :passport_number, license_number = :license_number,
 			license_expiry = :license_expiry, status = :status,
 			current_rating = :current_rating, total_trips = :total_trips,
-			metadata = :metadata, updated_at = :updated_at
+			metadata = :metadata, shard_key = :shard_key, fleet_id = :fleet_id,
+			phone_verified_at = CASE WHEN phone = :phone THEN phone_verified_at END,
+			updated_at = :updated_at
 		WHERE id = :id AND deleted_at IS NULL`
 
-	result, err := r.db.NamedExecContext(ctx, query, driver)
+	// Прежний статус читается под блокировкой строки, чтобы смена попала в историю
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		previous, err := lockDriverStatus(ctx, tx, driver.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.NamedExecContext(ctx, query, driver); err != nil {
+			return err
+		}
+		if previous == driver.Status {
+			return nil
+		}
+		return recordStatusTransition(ctx, tx, driver.ID, &previous, driver.Status, driver.UpdatedAt)
+	})
 	if err != nil {
+		if err == entities.ErrDriverNotFound {
+			return err
+		}
 		r.logger.Error("Failed to update driver",
 			zap.Error(err),
 			zap.String("driver_id", driver.ID.String()),
@@ -220,15 +280,6 @@ func (r *driverRepository) Update(ctx context.Context, driver *entities.Driver)
 		return fmt.Errorf("failed to update driver: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return entities.ErrDriverNotFound
-	}
-
 	r.logger.Info("Driver updated successfully",
 		zap.String("driver_id", driver.ID.String()),
 	)
@@ -306,7 +357,7 @@ func (r *driverRepository) List(ctx context.Context, filters *entities.DriverFil
 	}
 
 	var drivers []*entities.Driver
-	err = r.db.SelectContext(ctx, &drivers, query, args...)
+	err = r.db.ReplicaSelectContext(ctx, &drivers, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to list drivers",
 			zap.Error(err),
@@ -325,7 +376,7 @@ func (r *driverRepository) Count(ctx context.Context, filters *entities.DriverFi
 	}
 
 	var count int
-	err = r.db.GetContext(ctx, &count, query, args...)
+	err = r.db.ReplicaGetContext(ctx, &count, query, args...)
 	if err != nil {
 		r.logger.Error("Failed to count drivers",
 			zap.Error(err),
@@ -337,16 +388,16 @@ func (r *driverRepository) Count(ctx context.Context, filters *entities.DriverFi
 }
 
 // Exists проверяет существование водителя по телефону или номеру лицензии
-func (r *driverRepository) Exists(ctx context.Context, phone, licenseNumber string) (bool, error) {
+func (r *driverRepository) Exists(ctx context.Context, phone, email, licenseNumber string) (bool, error) {
 	query := `
 		SELECT EXISTS(
 			SELECT 1 FROM drivers 
-			WHERE (phone = $1 OR license_number = $2) 
+			WHERE (($1 <> '' AND phone = $1) OR ($2 <> '' AND email = $2) OR ($3 <> '' AND license_number = $3))
 			AND deleted_at IS NULL
 		)`
 
 	var exists bool
-	err := r.db.GetContext(ctx, &exists, query, phone, licenseNumber)
+	err := r.db.GetContext(ctx, &exists, query, phone, email, licenseNumber)
 	if err != nil {
 		r.logger.Error("Failed to check driver existence",
 			zap.Error(err),
@@ -359,15 +410,55 @@ func (r *driverRepository) Exists(ctx context.Context, phone, licenseNumber stri
 	return exists, nil
 }
 
-// UpdateStatus обновляет статус водителя
+// GetDeletedByPhone получает последнего удаленного водителя с номером телефона
+func (r *driverRepository) GetDeletedByPhone(ctx context.Context, phone string) (*entities.Driver, error) {
+	var driver entities.Driver
+	query := `
+		SELECT * FROM drivers
+		WHERE phone = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &driver, query, phone)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrDriverNotFound
+		}
+		r.logger.Error("Failed to get deleted driver by phone",
+			zap.Error(err),
+			zap.String("phone", phone),
+		)
+		return nil, fmt.Errorf("failed to get deleted driver by phone: %w", err)
+	}
+
+	return &driver, nil
+}
+
+// UpdateStatus обновляет статус водителя и записывает смену в историю статусов
 func (r *driverRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.Status) error {
 	query := `
 		UPDATE drivers 
 		SET status = $1, updated_at = $2 
 		WHERE id = $3 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, status, time.Now(), id)
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		previous, err := lockDriverStatus(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if _, err := tx.ExecContext(ctx, query, status, now, id); err != nil {
+			return err
+		}
+		if previous == status {
+			return nil
+		}
+		return recordStatusTransition(ctx, tx, id, &previous, status, now)
+	})
 	if err != nil {
+		if err == entities.ErrDriverNotFound {
+			return err
+		}
 		r.logger.Error("Failed to update driver status",
 			zap.Error(err),
 			zap.String("driver_id", id.String()),
@@ -376,15 +467,6 @@ func (r *driverRepository) UpdateStatus(ctx context.Context, id uuid.UUID, statu
 		return fmt.Errorf("failed to update driver status: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return entities.ErrDriverNotFound
-	}
-
 	r.logger.Info("Driver status updated successfully",
 		zap.String("driver_id", id.String()),
 		zap.String("status", string(status)),
@@ -393,6 +475,73 @@ func (r *driverRepository) UpdateStatus(ctx context.Context, id uuid.UUID, statu
 	return nil
 }
 
+// UpdateStatuses применяет смены статуса в одной транзакции с проверкой текущего статуса
+func (r *driverRepository) UpdateStatuses(ctx context.Context, updates []entities.StatusUpdate, allOrNothing bool) ([]uuid.UUID, error) {
+	query := `
+		UPDATE drivers
+		SET status = $1, updated_at = $2
+		WHERE id = $3 AND status = $4 AND deleted_at IS NULL`
+
+	updated := make([]uuid.UUID, 0, len(updates))
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		now := time.Now()
+		for _, update := range updates {
+			result, err := tx.ExecContext(ctx, query, update.To, now, update.DriverID, update.From)
+			if err != nil {
+				return fmt.Errorf("failed to update driver status: %w", err)
+			}
+			rowsAffected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if rowsAffected == 0 {
+				if allOrNothing {
+					return entities.ErrStatusConflict
+				}
+				continue
+			}
+			if update.From != update.To {
+				from := update.From
+				if err := recordStatusTransition(ctx, tx, update.DriverID, &from, update.To, now); err != nil {
+					return err
+				}
+			}
+			updated = append(updated, update.DriverID)
+		}
+		return nil
+	})
+	if err != nil {
+		if err != entities.ErrStatusConflict {
+			r.logger.Error("Failed to update driver statuses",
+				zap.Error(err),
+				zap.Int("count", len(updates)),
+			)
+		}
+		return nil, err
+	}
+
+	r.logger.Info("Driver statuses updated",
+		zap.Int("requested", len(updates)),
+		zap.Int("updated", len(updated)),
+	)
+	return updated, nil
+}
+
+// LockRating блокирует строку водителя (SELECT ... FOR UPDATE) и возвращает текущий рейтинг.
+// Вне транзакции блокировка снимается сразу после запроса
+func (r *driverRepository) LockRating(ctx context.Context, id uuid.UUID) (float64, error) {
+	var rating float64
+	err := r.db.GetContext(ctx, &rating,
+		`SELECT current_rating FROM drivers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, entities.ErrDriverNotFound
+		}
+		return 0, fmt.Errorf("failed to lock driver rating: %w", err)
+	}
+	return rating, nil
+}
+
 // UpdateRating обновляет рейтинг водителя
 func (r *driverRepository) UpdateRating(ctx context.Context, id uuid.UUID, rating float64) error {
 	query := `
@@ -400,7 +549,7 @@ func (r *driverRepository) UpdateRating(ctx context.Context, id uuid.UUID, ratin
 		SET current_rating = $1, updated_at = $2 
 		WHERE id = $3 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, rating, time.Now(), id)
+	result, err := r.db.ExecIdempotentContext(ctx, query, rating, time.Now(), id)
 	if err != nil {
 		r.logger.Error("Failed to update driver rating",
 			zap.Error(err),
@@ -450,6 +599,127 @@ func (r *driverRepository) IncrementTripCount(ctx context.Context, id uuid.UUID)
 	return nil
 }
 
+// UpdatePaymentHold устанавливает или снимает блокировку водителя со стороны биллинга
+func (r *driverRepository) UpdatePaymentHold(ctx context.Context, id uuid.UUID, hold bool, reason *string) error {
+	now := time.Now()
+	var holdAt *time.Time
+	if hold {
+		holdAt = &now
+	} else {
+		reason = nil
+	}
+
+	query := `
+		UPDATE drivers
+		SET payment_hold = $1, payment_hold_reason = $2, payment_hold_at = $3, updated_at = $4
+		WHERE id = $5 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, hold, reason, holdAt, now, id)
+	if err != nil {
+		r.logger.Error("Failed to update driver payment hold",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+			zap.Bool("payment_hold", hold),
+		)
+		return fmt.Errorf("failed to update driver payment hold: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDriverNotFound
+	}
+
+	return nil
+}
+
+// UpdateTags заменяет метки водителя
+func (r *driverRepository) UpdateTags(ctx context.Context, id uuid.UUID, tags entities.DriverTagList) error {
+	query := `
+		UPDATE drivers
+		SET tags = $1, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, tags, time.Now(), id)
+	if err != nil {
+		r.logger.Error("Failed to update driver tags",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return fmt.Errorf("failed to update driver tags: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDriverNotFound
+	}
+
+	return nil
+}
+
+// SetPhoneVerified сохраняет время подтверждения телефона
+func (r *driverRepository) SetPhoneVerified(ctx context.Context, id uuid.UUID, phone string, at time.Time) error {
+	query := `
+		UPDATE drivers
+		SET phone_verified_at = $1, updated_at = $2
+		WHERE id = $3 AND phone = $4 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, at, time.Now(), id, phone)
+	if err != nil {
+		r.logger.Error("Failed to set driver phone verified",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return fmt.Errorf("failed to set driver phone verified: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDriverNotFound
+	}
+
+	return nil
+}
+
+// UpdateDispatchRates обновляет доли принятых и отмененных предложений водителя
+func (r *driverRepository) UpdateDispatchRates(ctx context.Context, id uuid.UUID, acceptanceRate, cancellationRate *float64) error {
+	query := `
+		UPDATE drivers
+		SET acceptance_rate = $1, cancellation_rate = $2, updated_at = $3
+		WHERE id = $4 AND deleted_at IS NULL`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, acceptanceRate, cancellationRate, time.Now(), id)
+	if err != nil {
+		r.logger.Error("Failed to update driver dispatch rates",
+			zap.Error(err),
+			zap.String("driver_id", id.String()),
+		)
+		return fmt.Errorf("failed to update driver dispatch rates: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrDriverNotFound
+	}
+
+	return nil
+}
+
 // GetActiveDrivers получает список активных водителей
 func (r *driverRepository) GetActiveDrivers(ctx context.Context) ([]*entities.Driver, error) {
 	query := `
@@ -510,6 +780,50 @@ func (r *driverRepository) buildListQuery(filters *entities.DriverFilters, isCou
 			args = append(args, *filters.MaxRating)
 		}
 
+		if filters.City != nil {
+			argCount++
+			// Город, определенный по местоположениям, важнее города из метаданных
+			conditions = append(conditions, fmt.Sprintf("COALESCE(resolved_city, shard_key) = $%d", argCount))
+			args = append(args, entities.NormalizeCity(*filters.City))
+		}
+
+		if filters.FleetIDs != nil {
+			if len(filters.FleetIDs) == 0 {
+				conditions = append(conditions, "FALSE")
+			} else {
+				placeholders := make([]string, len(filters.FleetIDs))
+				for i, fleetID := range filters.FleetIDs {
+					argCount++
+					placeholders[i] = fmt.Sprintf("$%d", argCount)
+					args = append(args, fleetID)
+				}
+				conditions = append(conditions, fmt.Sprintf("fleet_id IN (%s)", strings.Join(placeholders, ",")))
+			}
+		}
+
+		if filters.IDs != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("id = ANY($%d)", argCount))
+			args = append(args, pq.Array(filters.IDs))
+		}
+
+		if filters.PaymentHold != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("payment_hold = $%d", argCount))
+			args = append(args, *filters.PaymentHold)
+		}
+
+		if len(filters.Tags) > 0 {
+			// Вхождение массива использует GIN индекс по tags
+			tags, err := json.Marshal(filters.Tags)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to marshal tags filter: %w", err)
+			}
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("tags @> $%d::jsonb", argCount))
+			args = append(args, string(tags))
+		}
+
 		if filters.CreatedAfter != nil {
 			argCount++
 			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argCount))
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// EarningRepository интерфейс для работы с начислениями водителям
+type EarningRepository interface {
+	// Create сохраняет начисление; повторная поездка по тому же заказу — entities.ErrEarningExists
+	Create(ctx context.Context, earning *entities.DriverEarning) error
+	List(ctx context.Context, filters *entities.EarningFilters) ([]*entities.DriverEarning, error)
+	Count(ctx context.Context, filters *entities.EarningFilters) (int, error)
+	// Summarize возвращает суммы по периодам группировки в часовом поясе loc и валютам
+	// (без учета Limit и Offset)
+	Summarize(ctx context.Context, filters *entities.EarningFilters, groupBy entities.EarningsGrouping, loc *time.Location) ([]*entities.EarningsPeriodTotal, error)
+}
+
+// earningRepository реализация EarningRepository
+type earningRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewEarningRepository создает новый репозиторий начислений
+func NewEarningRepository(db *database.DB, logger *zap.Logger) EarningRepository {
+	return &earningRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новое начисление
+func (r *earningRepository) Create(ctx context.Context, earning *entities.DriverEarning) error {
+	query := `
+		INSERT INTO driver_earnings (
+			id, driver_id, shift_id, order_id, type, fare, commission, bonus,
+			net_amount, currency, description, earned_at, created_at
+		) VALUES (
+			:id, :driver_id, :shift_id, :order_id, :type, :fare, :commission, :bonus,
+			:net_amount, :currency, :description, :earned_at, :created_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, earning)
+	if err != nil {
+		// Повторное начисление поездки по тому же заказу нарушает idx_driver_earnings_unique_trip
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("failed to create earning: %w", entities.ErrEarningExists)
+		}
+		r.logger.Error("Failed to create earning",
+			zap.Error(err),
+			zap.String("earning_id", earning.ID.String()),
+			zap.String("driver_id", earning.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create earning: %w", err)
+	}
+
+	return nil
+}
+
+// List получает список начислений с фильтрами, новые первыми
+func (r *earningRepository) List(ctx context.Context, filters *entities.EarningFilters) ([]*entities.DriverEarning, error) {
+	where, args := r.buildConditions(filters)
+	query := "SELECT * FROM driver_earnings WHERE 1=1" + where + " ORDER BY earned_at DESC, id"
+
+	if filters != nil {
+		if filters.Limit > 0 {
+			args = append(args, filters.Limit)
+			query += fmt.Sprintf(" LIMIT $%d", len(args))
+		}
+
+		if filters.Offset > 0 {
+			args = append(args, filters.Offset)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
+
+	var earnings []*entities.DriverEarning
+	if err := r.db.SelectContext(ctx, &earnings, query, args...); err != nil {
+		r.logger.Error("Failed to list earnings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list earnings: %w", err)
+	}
+
+	return earnings, nil
+}
+
+// Count возвращает количество начислений с фильтрами
+func (r *earningRepository) Count(ctx context.Context, filters *entities.EarningFilters) (int, error) {
+	where, args := r.buildConditions(filters)
+	query := "SELECT COUNT(*) FROM driver_earnings WHERE 1=1" + where
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count earnings", zap.Error(err))
+		return 0, fmt.Errorf("failed to count earnings: %w", err)
+	}
+
+	return count, nil
+}
+
+// Summarize возвращает суммы начислений по периодам и валютам. Периоды отсчитываются
+// в часовом поясе loc: date_trunc по 'week' начинает неделю с понедельника
+func (r *earningRepository) Summarize(ctx context.Context, filters *entities.EarningFilters, groupBy entities.EarningsGrouping, loc *time.Location) ([]*entities.EarningsPeriodTotal, error) {
+	where, args := r.buildConditions(filters)
+	args = append(args, string(groupBy), loc.String())
+	query := fmt.Sprintf(`
+		SELECT date_trunc($%d, earned_at AT TIME ZONE $%d) AS period_start, currency,
+			COUNT(*) AS entries, COUNT(*) FILTER (WHERE type = 'trip') AS trips,
+			SUM(fare) AS fare, SUM(commission) AS commission, SUM(bonus) AS bonus,
+			SUM(net_amount) AS net_amount
+		FROM driver_earnings WHERE 1=1`+where+`
+		GROUP BY period_start, currency
+		ORDER BY period_start, currency`, len(args)-1, len(args))
+
+	var totals []*entities.EarningsPeriodTotal
+	if err := r.db.SelectContext(ctx, &totals, query, args...); err != nil {
+		r.logger.Error("Failed to summarize earnings", zap.Error(err))
+		return nil, fmt.Errorf("failed to summarize earnings: %w", err)
+	}
+
+	// date_trunc возвращает местное время без пояса, а драйвер читает его как UTC
+	for _, total := range totals {
+		start := total.PeriodStart
+		total.PeriodStart = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	}
+
+	return totals, nil
+}
+
+// buildConditions строит условия WHERE по фильтрам начислений
+func (r *earningRepository) buildConditions(filters *entities.EarningFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filters == nil {
+		return "", nil
+	}
+
+	if filters.DriverID != nil {
+		args = append(args, *filters.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+
+	if filters.ShiftID != nil {
+		args = append(args, *filters.ShiftID)
+		conditions = append(conditions, fmt.Sprintf("shift_id = $%d", len(args)))
+	}
+
+	if filters.Type != nil {
+		args = append(args, *filters.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		conditions = append(conditions, fmt.Sprintf("earned_at >= $%d", len(args)))
+	}
+
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		conditions = append(conditions, fmt.Sprintf("earned_at < $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
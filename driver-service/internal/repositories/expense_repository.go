@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ExpenseRepository интерфейс для работы с расходами водителей
+type ExpenseRepository interface {
+	Create(ctx context.Context, expense *entities.ShiftExpense) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ShiftExpense, error)
+	Update(ctx context.Context, expense *entities.ShiftExpense) error
+	List(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ShiftExpense, error)
+	Count(ctx context.Context, filters *entities.ExpenseFilters) (int, error)
+	Summarize(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ExpenseTotal, error)
+	MarkExported(ctx context.Context, ids []uuid.UUID, exportedAt time.Time) (int, error)
+}
+
+// expenseRepository реализация ExpenseRepository
+type expenseRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewExpenseRepository создает новый репозиторий расходов
+func NewExpenseRepository(db *database.DB, logger *zap.Logger) ExpenseRepository {
+	return &expenseRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новый расход
+func (r *expenseRepository) Create(ctx context.Context, expense *entities.ShiftExpense) error {
+	query := `
+		INSERT INTO shift_expenses (
+			id, shift_id, driver_id, category, amount, currency, description,
+			receipt_url, incurred_at, exported_at, created_at, updated_at
+		) VALUES (
+			:id, :shift_id, :driver_id, :category, :amount, :currency, :description,
+			:receipt_url, :incurred_at, :exported_at, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, expense)
+	if err != nil {
+		r.logger.Error("Failed to create expense",
+			zap.Error(err),
+			zap.String("expense_id", expense.ID.String()),
+			zap.String("shift_id", expense.ShiftID.String()),
+		)
+		return fmt.Errorf("failed to create expense: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает расход по ID
+func (r *expenseRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ShiftExpense, error) {
+	var expense entities.ShiftExpense
+	query := `SELECT * FROM shift_expenses WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &expense, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrExpenseNotFound
+		}
+		r.logger.Error("Failed to get expense by ID",
+			zap.Error(err),
+			zap.String("expense_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get expense by ID: %w", err)
+	}
+
+	return &expense, nil
+}
+
+// Update обновляет расход
+func (r *expenseRepository) Update(ctx context.Context, expense *entities.ShiftExpense) error {
+	expense.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE shift_expenses SET
+			category = :category, amount = :amount, currency = :currency,
+			description = :description, receipt_url = :receipt_url,
+			incurred_at = :incurred_at, exported_at = :exported_at, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, expense)
+	if err != nil {
+		r.logger.Error("Failed to update expense",
+			zap.Error(err),
+			zap.String("expense_id", expense.ID.String()),
+		)
+		return fmt.Errorf("failed to update expense: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrExpenseNotFound
+	}
+
+	return nil
+}
+
+// List получает список расходов с фильтрами, новые первыми
+func (r *expenseRepository) List(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ShiftExpense, error) {
+	where, args := r.buildConditions(filters)
+	query := "SELECT * FROM shift_expenses WHERE 1=1" + where + " ORDER BY incurred_at DESC, id"
+
+	if filters != nil {
+		if filters.Limit > 0 {
+			args = append(args, filters.Limit)
+			query += fmt.Sprintf(" LIMIT $%d", len(args))
+		}
+
+		if filters.Offset > 0 {
+			args = append(args, filters.Offset)
+			query += fmt.Sprintf(" OFFSET $%d", len(args))
+		}
+	}
+
+	var expenses []*entities.ShiftExpense
+	if err := r.db.SelectContext(ctx, &expenses, query, args...); err != nil {
+		r.logger.Error("Failed to list expenses", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expenses: %w", err)
+	}
+
+	return expenses, nil
+}
+
+// Count возвращает количество расходов с фильтрами
+func (r *expenseRepository) Count(ctx context.Context, filters *entities.ExpenseFilters) (int, error) {
+	where, args := r.buildConditions(filters)
+	query := "SELECT COUNT(*) FROM shift_expenses WHERE 1=1" + where
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count expenses", zap.Error(err))
+		return 0, fmt.Errorf("failed to count expenses: %w", err)
+	}
+
+	return count, nil
+}
+
+// Summarize возвращает суммы расходов по категориям и валютам (без учета Limit и Offset)
+func (r *expenseRepository) Summarize(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ExpenseTotal, error) {
+	where, args := r.buildConditions(filters)
+	query := `
+		SELECT category, currency, SUM(amount) AS amount, COUNT(*) AS count
+		FROM shift_expenses WHERE 1=1` + where + `
+		GROUP BY category, currency
+		ORDER BY category, currency`
+
+	var totals []*entities.ExpenseTotal
+	if err := r.db.SelectContext(ctx, &totals, query, args...); err != nil {
+		r.logger.Error("Failed to summarize expenses", zap.Error(err))
+		return nil, fmt.Errorf("failed to summarize expenses: %w", err)
+	}
+
+	return totals, nil
+}
+
+// MarkExported отмечает расходы выгруженными в учетную систему; уже выгруженные не меняются
+func (r *expenseRepository) MarkExported(ctx context.Context, ids []uuid.UUID, exportedAt time.Time) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = id.String()
+	}
+
+	query := `
+		UPDATE shift_expenses SET exported_at = $1
+		WHERE id = ANY($2::uuid[]) AND exported_at IS NULL`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, exportedAt, pq.Array(values))
+	if err != nil {
+		r.logger.Error("Failed to mark expenses exported", zap.Error(err))
+		return 0, fmt.Errorf("failed to mark expenses exported: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// buildConditions строит условия WHERE по фильтрам расходов
+func (r *expenseRepository) buildConditions(filters *entities.ExpenseFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filters == nil {
+		return "", nil
+	}
+
+	if filters.DriverID != nil {
+		args = append(args, *filters.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+
+	if filters.ShiftID != nil {
+		args = append(args, *filters.ShiftID)
+		conditions = append(conditions, fmt.Sprintf("shift_id = $%d", len(args)))
+	}
+
+	if len(filters.Category) > 0 {
+		placeholders := make([]string, len(filters.Category))
+		for i, category := range filters.Category {
+			args = append(args, category)
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		conditions = append(conditions, fmt.Sprintf("category IN (%s)", strings.Join(placeholders, ",")))
+	}
+
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		conditions = append(conditions, fmt.Sprintf("incurred_at >= $%d", len(args)))
+	}
+
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		conditions = append(conditions, fmt.Sprintf("incurred_at < $%d", len(args)))
+	}
+
+	if filters.Exported != nil {
+		if *filters.Exported {
+			conditions = append(conditions, "exported_at IS NOT NULL")
+		} else {
+			conditions = append(conditions, "exported_at IS NULL")
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " AND " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// FeatureFlagRepository интерфейс для флагов функций приложения водителя
+type FeatureFlagRepository interface {
+	Create(ctx context.Context, flag *entities.FeatureFlag) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.FeatureFlag, error)
+	// Update сохраняет изменения флага; ключ флага не меняется
+	Update(ctx context.Context, flag *entities.FeatureFlag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// List возвращает все флаги по ключу
+	List(ctx context.Context) ([]*entities.FeatureFlag, error)
+}
+
+// featureFlagRepository реализация FeatureFlagRepository
+type featureFlagRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewFeatureFlagRepository создает новый репозиторий флагов функций
+func NewFeatureFlagRepository(db *database.DB, logger *zap.Logger) FeatureFlagRepository {
+	return &featureFlagRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет флаг
+func (r *featureFlagRepository) Create(ctx context.Context, flag *entities.FeatureFlag) error {
+	query := `
+		INSERT INTO feature_flags (id, key, description, enabled, rules, parameters, created_at, updated_at)
+		VALUES (:id, :key, :description, :enabled, :rules, :parameters, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, flag); err != nil {
+		// Флаг с тем же ключом уже создан
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrFeatureFlagExists
+		}
+		r.logger.Error("Failed to create feature flag",
+			zap.Error(err),
+			zap.String("key", flag.Key),
+		)
+		return fmt.Errorf("failed to create feature flag: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает флаг по ID
+func (r *featureFlagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.FeatureFlag, error) {
+	var flag entities.FeatureFlag
+	if err := r.db.GetContext(ctx, &flag, `SELECT * FROM feature_flags WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrFeatureFlagNotFound
+		}
+		r.logger.Error("Failed to get feature flag",
+			zap.Error(err),
+			zap.String("flag_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get feature flag: %w", err)
+	}
+	return &flag, nil
+}
+
+// Update сохраняет изменения флага
+func (r *featureFlagRepository) Update(ctx context.Context, flag *entities.FeatureFlag) error {
+	query := `
+		UPDATE feature_flags SET
+			description = :description, enabled = :enabled, rules = :rules, parameters = :parameters,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, flag)
+	if err != nil {
+		r.logger.Error("Failed to update feature flag",
+			zap.Error(err),
+			zap.String("flag_id", flag.ID.String()),
+		)
+		return fmt.Errorf("failed to update feature flag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет флаг
+func (r *featureFlagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM feature_flags WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete feature flag",
+			zap.Error(err),
+			zap.String("flag_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete feature flag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
+
+// List получает все флаги
+func (r *featureFlagRepository) List(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	var flags []*entities.FeatureFlag
+	if err := r.db.SelectContext(ctx, &flags, `SELECT * FROM feature_flags ORDER BY key`); err != nil {
+		r.logger.Error("Failed to list feature flags", zap.Error(err))
+		return nil, fmt.Errorf("failed to list feature flags: %w", err)
+	}
+	return flags, nil
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// FleetRepository интерфейс для автопарков
+type FleetRepository interface {
+	Create(ctx context.Context, fleet *entities.Fleet) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Fleet, error)
+	Update(ctx context.Context, fleet *entities.Fleet) error
+	List(ctx context.Context, filters *entities.FleetFilters) ([]*entities.Fleet, error)
+	Count(ctx context.Context, filters *entities.FleetFilters) (int, error)
+}
+
+// fleetRepository реализация FleetRepository
+type fleetRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewFleetRepository создает новый репозиторий автопарков
+func NewFleetRepository(db *database.DB, logger *zap.Logger) FleetRepository {
+	return &fleetRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет автопарк
+func (r *fleetRepository) Create(ctx context.Context, fleet *entities.Fleet) error {
+	query := `
+		INSERT INTO fleets (id, name, metadata, created_at, updated_at)
+		VALUES (:id, :name, :metadata, :created_at, :updated_at)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, fleet); err != nil {
+		r.logger.Error("Failed to create fleet",
+			zap.Error(err),
+			zap.String("fleet_id", fleet.ID.String()),
+		)
+		return fmt.Errorf("failed to create fleet: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает автопарк по ID
+func (r *fleetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Fleet, error) {
+	var fleet entities.Fleet
+	if err := r.db.GetContext(ctx, &fleet, `SELECT * FROM fleets WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrFleetNotFound
+		}
+		r.logger.Error("Failed to get fleet",
+			zap.Error(err),
+			zap.String("fleet_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get fleet: %w", err)
+	}
+	return &fleet, nil
+}
+
+// Update сохраняет изменения автопарка
+func (r *fleetRepository) Update(ctx context.Context, fleet *entities.Fleet) error {
+	query := `
+		UPDATE fleets SET
+			name = :name, metadata = :metadata, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, fleet)
+	if err != nil {
+		r.logger.Error("Failed to update fleet",
+			zap.Error(err),
+			zap.String("fleet_id", fleet.ID.String()),
+		)
+		return fmt.Errorf("failed to update fleet: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrFleetNotFound
+	}
+
+	return nil
+}
+
+// List получает страницу автопарков, упорядоченных по названию
+func (r *fleetRepository) List(ctx context.Context, filters *entities.FleetFilters) ([]*entities.Fleet, error) {
+	where, args := fleetConditions(filters)
+	query := `SELECT * FROM fleets` + where + ` ORDER BY name ASC, id ASC`
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	var fleets []*entities.Fleet
+	if err := r.db.SelectContext(ctx, &fleets, query, args...); err != nil {
+		r.logger.Error("Failed to list fleets", zap.Error(err))
+		return nil, fmt.Errorf("failed to list fleets: %w", err)
+	}
+	return fleets, nil
+}
+
+// Count считает автопарки по фильтрам
+func (r *fleetRepository) Count(ctx context.Context, filters *entities.FleetFilters) (int, error) {
+	where, args := fleetConditions(filters)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM fleets`+where, args...); err != nil {
+		r.logger.Error("Failed to count fleets", zap.Error(err))
+		return 0, fmt.Errorf("failed to count fleets: %w", err)
+	}
+	return count, nil
+}
+
+// fleetConditions строит условие WHERE по фильтрам автопарков
+func fleetConditions(filters *entities.FleetFilters) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filters.IDs != nil {
+		if len(filters.IDs) == 0 {
+			conditions = append(conditions, "FALSE")
+		} else {
+			placeholders := make([]string, len(filters.IDs))
+			for i, id := range filters.IDs {
+				args = append(args, id)
+				placeholders[i] = fmt.Sprintf("$%d", len(args))
+			}
+			conditions = append(conditions, fmt.Sprintf("id IN (%s)", strings.Join(placeholders, ",")))
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
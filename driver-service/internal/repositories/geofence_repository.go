@@ -0,0 +1,308 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// GeofenceRepository интерфейс для геозон и присутствия водителей в них
+type GeofenceRepository interface {
+	Create(ctx context.Context, geofence *entities.Geofence) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.Geofence, error)
+	Update(ctx context.Context, geofence *entities.Geofence) error
+	// Delete удаляет геозону вместе с присутствием водителей в ней
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filters *entities.GeofenceFilters) ([]*entities.Geofence, error)
+	Count(ctx context.Context, filters *entities.GeofenceFilters) (int, error)
+	// ListActive возвращает все активные геозоны
+	ListActive(ctx context.Context) ([]*entities.Geofence, error)
+
+	// ListPresenceByDriverID возвращает геозоны, внутри которых находится водитель
+	ListPresenceByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.GeofencePresence, error)
+	// AddPresence отмечает вход водителя; возвращает false, если водитель уже внутри
+	AddPresence(ctx context.Context, presence *entities.GeofencePresence) (bool, error)
+	// RemovePresence отмечает выход водителя; возвращает false, если водителя не было внутри
+	RemovePresence(ctx context.Context, geofenceID, driverID uuid.UUID) (bool, error)
+	// ClearPresence удаляет присутствие всех водителей в геозоне
+	ClearPresence(ctx context.Context, geofenceID uuid.UUID) error
+	// ListPresenceByGeofenceID возвращает водителей внутри геозоны в порядке входа
+	ListPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID, limit, offset int) ([]*entities.GeofencePresence, error)
+	CountPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID) (int, error)
+}
+
+// geofenceRepository реализация GeofenceRepository
+type geofenceRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewGeofenceRepository создает новый репозиторий геозон
+func NewGeofenceRepository(db *database.DB, logger *zap.Logger) GeofenceRepository {
+	return &geofenceRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет геозону
+func (r *geofenceRepository) Create(ctx context.Context, geofence *entities.Geofence) error {
+	query := `
+		INSERT INTO geofences (
+			id, name, shape, center_latitude, center_longitude, radius_meters, polygon,
+			metadata, active, created_at, updated_at
+		) VALUES (
+			:id, :name, :shape, :center_latitude, :center_longitude, :radius_meters, :polygon,
+			:metadata, :active, :created_at, :updated_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, geofence); err != nil {
+		r.logger.Error("Failed to create geofence",
+			zap.Error(err),
+			zap.String("geofence_id", geofence.ID.String()),
+		)
+		return fmt.Errorf("failed to create geofence: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает геозону по ID
+func (r *geofenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Geofence, error) {
+	var geofence entities.Geofence
+	if err := r.db.GetContext(ctx, &geofence, `SELECT * FROM geofences WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrGeofenceNotFound
+		}
+		r.logger.Error("Failed to get geofence",
+			zap.Error(err),
+			zap.String("geofence_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get geofence: %w", err)
+	}
+	return &geofence, nil
+}
+
+// Update сохраняет изменения геозоны
+func (r *geofenceRepository) Update(ctx context.Context, geofence *entities.Geofence) error {
+	query := `
+		UPDATE geofences SET
+			name = :name, shape = :shape, center_latitude = :center_latitude,
+			center_longitude = :center_longitude, radius_meters = :radius_meters, polygon = :polygon,
+			metadata = :metadata, active = :active, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, geofence)
+	if err != nil {
+		r.logger.Error("Failed to update geofence",
+			zap.Error(err),
+			zap.String("geofence_id", geofence.ID.String()),
+		)
+		return fmt.Errorf("failed to update geofence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrGeofenceNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет геозону
+func (r *geofenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM geofences WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete geofence",
+			zap.Error(err),
+			zap.String("geofence_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete geofence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrGeofenceNotFound
+	}
+
+	return nil
+}
+
+// List получает страницу геозон, упорядоченных по названию
+func (r *geofenceRepository) List(ctx context.Context, filters *entities.GeofenceFilters) ([]*entities.Geofence, error) {
+	where, args := geofenceConditions(filters)
+	query := `SELECT * FROM geofences` + where + ` ORDER BY name ASC, id ASC`
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	var geofences []*entities.Geofence
+	if err := r.db.SelectContext(ctx, &geofences, query, args...); err != nil {
+		r.logger.Error("Failed to list geofences", zap.Error(err))
+		return nil, fmt.Errorf("failed to list geofences: %w", err)
+	}
+	return geofences, nil
+}
+
+// Count считает геозоны по фильтрам
+func (r *geofenceRepository) Count(ctx context.Context, filters *entities.GeofenceFilters) (int, error) {
+	where, args := geofenceConditions(filters)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM geofences`+where, args...); err != nil {
+		r.logger.Error("Failed to count geofences", zap.Error(err))
+		return 0, fmt.Errorf("failed to count geofences: %w", err)
+	}
+	return count, nil
+}
+
+// ListActive получает активные геозоны
+func (r *geofenceRepository) ListActive(ctx context.Context) ([]*entities.Geofence, error) {
+	var geofences []*entities.Geofence
+	if err := r.db.SelectContext(ctx, &geofences, `SELECT * FROM geofences WHERE active = TRUE`); err != nil {
+		r.logger.Error("Failed to list active geofences", zap.Error(err))
+		return nil, fmt.Errorf("failed to list active geofences: %w", err)
+	}
+	return geofences, nil
+}
+
+// ListPresenceByDriverID получает присутствие водителя в геозонах
+func (r *geofenceRepository) ListPresenceByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.GeofencePresence, error) {
+	var presence []*entities.GeofencePresence
+	query := `SELECT * FROM geofence_presence WHERE driver_id = $1`
+	if err := r.db.SelectContext(ctx, &presence, query, driverID); err != nil {
+		r.logger.Error("Failed to list driver geofence presence",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver geofence presence: %w", err)
+	}
+	return presence, nil
+}
+
+// AddPresence отмечает вход водителя в геозону. Запрос не повторяется: по числу
+// вставленных строк определяется, публиковать ли событие входа
+func (r *geofenceRepository) AddPresence(ctx context.Context, presence *entities.GeofencePresence) (bool, error) {
+	query := `
+		INSERT INTO geofence_presence (geofence_id, driver_id, entered_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (geofence_id, driver_id) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query, presence.GeofenceID, presence.DriverID, presence.EnteredAt)
+	if err != nil {
+		r.logger.Error("Failed to add geofence presence",
+			zap.Error(err),
+			zap.String("geofence_id", presence.GeofenceID.String()),
+			zap.String("driver_id", presence.DriverID.String()),
+		)
+		return false, fmt.Errorf("failed to add geofence presence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// RemovePresence отмечает выход водителя из геозоны; запрос не повторяется, как и в AddPresence
+func (r *geofenceRepository) RemovePresence(ctx context.Context, geofenceID, driverID uuid.UUID) (bool, error) {
+	query := `DELETE FROM geofence_presence WHERE geofence_id = $1 AND driver_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, geofenceID, driverID)
+	if err != nil {
+		r.logger.Error("Failed to remove geofence presence",
+			zap.Error(err),
+			zap.String("geofence_id", geofenceID.String()),
+			zap.String("driver_id", driverID.String()),
+		)
+		return false, fmt.Errorf("failed to remove geofence presence: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// ClearPresence удаляет присутствие водителей в геозоне
+func (r *geofenceRepository) ClearPresence(ctx context.Context, geofenceID uuid.UUID) error {
+	if _, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM geofence_presence WHERE geofence_id = $1`, geofenceID); err != nil {
+		r.logger.Error("Failed to clear geofence presence",
+			zap.Error(err),
+			zap.String("geofence_id", geofenceID.String()),
+		)
+		return fmt.Errorf("failed to clear geofence presence: %w", err)
+	}
+	return nil
+}
+
+// ListPresenceByGeofenceID получает водителей внутри геозоны, вошедших раньше — первыми
+func (r *geofenceRepository) ListPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID, limit, offset int) ([]*entities.GeofencePresence, error) {
+	query := `
+		SELECT * FROM geofence_presence
+		WHERE geofence_id = $1
+		ORDER BY entered_at ASC, driver_id ASC
+		LIMIT $2 OFFSET $3`
+
+	var presence []*entities.GeofencePresence
+	if err := r.db.SelectContext(ctx, &presence, query, geofenceID, limit, offset); err != nil {
+		r.logger.Error("Failed to list geofence presence",
+			zap.Error(err),
+			zap.String("geofence_id", geofenceID.String()),
+		)
+		return nil, fmt.Errorf("failed to list geofence presence: %w", err)
+	}
+	return presence, nil
+}
+
+// CountPresenceByGeofenceID считает водителей внутри геозоны
+func (r *geofenceRepository) CountPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM geofence_presence WHERE geofence_id = $1`
+	if err := r.db.GetContext(ctx, &count, query, geofenceID); err != nil {
+		r.logger.Error("Failed to count geofence presence",
+			zap.Error(err),
+			zap.String("geofence_id", geofenceID.String()),
+		)
+		return 0, fmt.Errorf("failed to count geofence presence: %w", err)
+	}
+	return count, nil
+}
+
+// geofenceConditions собирает условие WHERE по фильтрам геозон
+func geofenceConditions(filters *entities.GeofenceFilters) (string, []interface{}) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filters.Active != nil {
+		args = append(args, *filters.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// HeartbeatRepository интерфейс для последних сигналов водителей
+type HeartbeatRepository interface {
+	// Touch сохраняет сигнал водителя; более ранний сигнал не заменяет сохраненный
+	Touch(ctx context.Context, heartbeat *entities.DriverHeartbeat) error
+	// ListByDriverIDs возвращает последние сигналы водителей; водители без сигналов в
+	// результат не попадают
+	ListByDriverIDs(ctx context.Context, driverIDs []uuid.UUID) (map[uuid.UUID]*entities.DriverHeartbeat, error)
+}
+
+// heartbeatRepository реализация HeartbeatRepository
+type heartbeatRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewHeartbeatRepository создает новый репозиторий сигналов водителей
+func NewHeartbeatRepository(db *database.DB, logger *zap.Logger) HeartbeatRepository {
+	return &heartbeatRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Touch сохраняет последний сигнал водителя
+func (r *heartbeatRepository) Touch(ctx context.Context, heartbeat *entities.DriverHeartbeat) error {
+	query := `
+		INSERT INTO driver_heartbeats (driver_id, last_seen_at, source)
+		VALUES (:driver_id, :last_seen_at, :source)
+		ON CONFLICT (driver_id) DO UPDATE SET
+			last_seen_at = EXCLUDED.last_seen_at,
+			source = EXCLUDED.source
+		WHERE driver_heartbeats.last_seen_at < EXCLUDED.last_seen_at`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, heartbeat); err != nil {
+		r.logger.Error("Failed to touch driver heartbeat",
+			zap.Error(err),
+			zap.String("driver_id", heartbeat.DriverID.String()),
+		)
+		return fmt.Errorf("failed to touch driver heartbeat: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDriverIDs получает последние сигналы водителей
+func (r *heartbeatRepository) ListByDriverIDs(ctx context.Context, driverIDs []uuid.UUID) (map[uuid.UUID]*entities.DriverHeartbeat, error) {
+	heartbeats := make(map[uuid.UUID]*entities.DriverHeartbeat, len(driverIDs))
+	if len(driverIDs) == 0 {
+		return heartbeats, nil
+	}
+
+	query := `
+		SELECT driver_id, last_seen_at, source
+		FROM driver_heartbeats
+		WHERE driver_id = ANY($1)`
+
+	var rows []*entities.DriverHeartbeat
+	if err := r.db.SelectContext(ctx, &rows, query, pq.Array(driverIDs)); err != nil {
+		r.logger.Error("Failed to list driver heartbeats",
+			zap.Error(err),
+			zap.Int("drivers", len(driverIDs)),
+		)
+		return nil, fmt.Errorf("failed to list driver heartbeats: %w", err)
+	}
+
+	for _, row := range rows {
+		heartbeats[row.DriverID] = row
+	}
+	return heartbeats, nil
+}
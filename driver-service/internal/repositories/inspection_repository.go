@@ -0,0 +1,306 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// InspectionRepository интерфейс для работы с техосмотрами автомобилей
+type InspectionRepository interface {
+	Create(ctx context.Context, inspection *entities.VehicleInspection) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.VehicleInspection, error)
+	Update(ctx context.Context, inspection *entities.VehicleInspection) error
+	List(ctx context.Context, filters *entities.InspectionFilters) ([]*entities.VehicleInspection, error)
+	Count(ctx context.Context, filters *entities.InspectionFilters) (int, error)
+
+	// SavePhoto сохраняет фотографию; фотография того же ракурса заменяется
+	SavePhoto(ctx context.Context, photo *entities.InspectionPhoto) error
+	UpdatePhoto(ctx context.Context, photo *entities.InspectionPhoto) error
+	ListPhotos(ctx context.Context, inspectionID uuid.UUID) ([]*entities.InspectionPhoto, error)
+}
+
+// inspectionRepository реализация InspectionRepository
+type inspectionRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewInspectionRepository создает новый репозиторий техосмотров
+func NewInspectionRepository(db *database.DB, logger *zap.Logger) InspectionRepository {
+	return &inspectionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новый техосмотр
+func (r *inspectionRepository) Create(ctx context.Context, inspection *entities.VehicleInspection) error {
+	query := `
+		INSERT INTO vehicle_inspections (
+			id, vehicle_id, fleet_id, driver_id, inspection_type, due_date, status,
+			completed_at, inspected_by, notes, reminder_sent_at, submitted_at, metadata,
+			created_at, updated_at
+		) VALUES (
+			:id, :vehicle_id, :fleet_id, :driver_id, :inspection_type, :due_date, :status,
+			:completed_at, :inspected_by, :notes, :reminder_sent_at, :submitted_at, :metadata,
+			:created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, inspection)
+	if err != nil {
+		r.logger.Error("Failed to create inspection",
+			zap.Error(err),
+			zap.String("inspection_id", inspection.ID.String()),
+			zap.String("vehicle_id", inspection.VehicleID.String()),
+		)
+		return fmt.Errorf("failed to create inspection: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает техосмотр по ID
+func (r *inspectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.VehicleInspection, error) {
+	var inspection entities.VehicleInspection
+	query := `SELECT * FROM vehicle_inspections WHERE id = $1`
+
+	err := r.db.GetContext(ctx, &inspection, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrInspectionNotFound
+		}
+		r.logger.Error("Failed to get inspection by ID",
+			zap.Error(err),
+			zap.String("inspection_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get inspection by ID: %w", err)
+	}
+
+	return &inspection, nil
+}
+
+// Update обновляет техосмотр
+func (r *inspectionRepository) Update(ctx context.Context, inspection *entities.VehicleInspection) error {
+	inspection.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE vehicle_inspections SET
+			fleet_id = :fleet_id, driver_id = :driver_id, inspection_type = :inspection_type,
+			due_date = :due_date, status = :status, completed_at = :completed_at,
+			inspected_by = :inspected_by, notes = :notes, reminder_sent_at = :reminder_sent_at,
+			submitted_at = :submitted_at, metadata = :metadata, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, inspection)
+	if err != nil {
+		r.logger.Error("Failed to update inspection",
+			zap.Error(err),
+			zap.String("inspection_id", inspection.ID.String()),
+		)
+		return fmt.Errorf("failed to update inspection: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrInspectionNotFound
+	}
+
+	return nil
+}
+
+// List получает список техосмотров с фильтрами, ближайшие по сроку первыми
+func (r *inspectionRepository) List(ctx context.Context, filters *entities.InspectionFilters) ([]*entities.VehicleInspection, error) {
+	query, args := r.buildListQuery(filters, false)
+
+	var inspections []*entities.VehicleInspection
+	if err := r.db.SelectContext(ctx, &inspections, query, args...); err != nil {
+		r.logger.Error("Failed to list inspections", zap.Error(err))
+		return nil, fmt.Errorf("failed to list inspections: %w", err)
+	}
+
+	return inspections, nil
+}
+
+// Count возвращает количество техосмотров с фильтрами
+func (r *inspectionRepository) Count(ctx context.Context, filters *entities.InspectionFilters) (int, error) {
+	query, args := r.buildListQuery(filters, true)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count inspections", zap.Error(err))
+		return 0, fmt.Errorf("failed to count inspections: %w", err)
+	}
+
+	return count, nil
+}
+
+// SavePhoto сохраняет фотографию; фотография того же ракурса заменяется
+func (r *inspectionRepository) SavePhoto(ctx context.Context, photo *entities.InspectionPhoto) error {
+	query := `
+		INSERT INTO inspection_photos (
+			id, inspection_id, angle, url, content_type, size, status,
+			rejection_reason, uploaded_at, reviewed_at
+		) VALUES (
+			:id, :inspection_id, :angle, :url, :content_type, :size, :status,
+			:rejection_reason, :uploaded_at, :reviewed_at
+		)
+		ON CONFLICT (inspection_id, angle) DO UPDATE SET
+			url = EXCLUDED.url, content_type = EXCLUDED.content_type, size = EXCLUDED.size,
+			status = EXCLUDED.status, rejection_reason = EXCLUDED.rejection_reason,
+			uploaded_at = EXCLUDED.uploaded_at, reviewed_at = EXCLUDED.reviewed_at
+		RETURNING id`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, photo)
+	if err != nil {
+		r.logger.Error("Failed to save inspection photo",
+			zap.Error(err),
+			zap.String("inspection_id", photo.InspectionID.String()),
+			zap.String("angle", string(photo.Angle)),
+		)
+		return fmt.Errorf("failed to save inspection photo: %w", err)
+	}
+	defer rows.Close()
+
+	// При замене фотографии сохраняется ID исходной записи
+	if rows.Next() {
+		if err := rows.Scan(&photo.ID); err != nil {
+			return fmt.Errorf("failed to scan inspection photo ID: %w", err)
+		}
+	}
+
+	return rows.Err()
+}
+
+// UpdatePhoto обновляет результат проверки фотографии
+func (r *inspectionRepository) UpdatePhoto(ctx context.Context, photo *entities.InspectionPhoto) error {
+	query := `
+		UPDATE inspection_photos SET
+			status = :status, rejection_reason = :rejection_reason, reviewed_at = :reviewed_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, photo)
+	if err != nil {
+		r.logger.Error("Failed to update inspection photo",
+			zap.Error(err),
+			zap.String("photo_id", photo.ID.String()),
+		)
+		return fmt.Errorf("failed to update inspection photo: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrInvalidInspectionPhoto
+	}
+
+	return nil
+}
+
+// ListPhotos возвращает фотографии техосмотра в порядке загрузки
+func (r *inspectionRepository) ListPhotos(ctx context.Context, inspectionID uuid.UUID) ([]*entities.InspectionPhoto, error) {
+	query := `SELECT * FROM inspection_photos WHERE inspection_id = $1 ORDER BY uploaded_at ASC`
+
+	var photos []*entities.InspectionPhoto
+	if err := r.db.SelectContext(ctx, &photos, query, inspectionID); err != nil {
+		r.logger.Error("Failed to list inspection photos",
+			zap.Error(err),
+			zap.String("inspection_id", inspectionID.String()),
+		)
+		return nil, fmt.Errorf("failed to list inspection photos: %w", err)
+	}
+
+	return photos, nil
+}
+
+// buildListQuery строит SQL запрос для получения списка техосмотров
+func (r *inspectionRepository) buildListQuery(filters *entities.InspectionFilters, isCount bool) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argCount := 0
+
+	selectClause := "SELECT * "
+	if isCount {
+		selectClause = "SELECT COUNT(*) "
+	}
+	query := selectClause + "FROM vehicle_inspections WHERE 1=1"
+
+	if filters != nil {
+		if filters.VehicleID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", argCount))
+			args = append(args, *filters.VehicleID)
+		}
+
+		if filters.FleetID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("fleet_id = $%d", argCount))
+			args = append(args, *filters.FleetID)
+		}
+
+		if filters.DriverID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("driver_id = $%d", argCount))
+			args = append(args, *filters.DriverID)
+		}
+
+		if len(filters.Status) > 0 {
+			placeholders := make([]string, len(filters.Status))
+			for i, status := range filters.Status {
+				argCount++
+				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				args = append(args, status)
+			}
+			conditions = append(conditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ",")))
+		}
+
+		if filters.DueBefore != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("due_date <= $%d", argCount))
+			args = append(args, *filters.DueBefore)
+		}
+
+		if filters.DueAfter != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("due_date >= $%d", argCount))
+			args = append(args, *filters.DueAfter)
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	if !isCount && filters != nil {
+		query += " ORDER BY due_date ASC"
+
+		if filters.Limit > 0 {
+			argCount++
+			query += fmt.Sprintf(" LIMIT $%d", argCount)
+			args = append(args, filters.Limit)
+		}
+
+		if filters.Offset > 0 {
+			argCount++
+			query += fmt.Sprintf(" OFFSET $%d", argCount)
+			args = append(args, filters.Offset)
+		}
+	}
+
+	return query, args
+}
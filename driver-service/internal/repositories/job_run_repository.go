@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+// JobRunRepository интерфейс для истории запусков фоновых задач
+type JobRunRepository interface {
+	Create(ctx context.Context, run *entities.JobRun) error
+	// List возвращает запуски, новые первыми
+	List(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error)
+	// DeleteOlderThan удаляет запуски, начатые до before
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+	// ClaimScheduledRun отмечает запуск задачи по расписанию, назначенный на scheduledAt,
+	// за экземпляром instance. Возвращает false, если этот или более поздний запуск уже
+	// отметил другой экземпляр
+	ClaimScheduledRun(ctx context.Context, name string, scheduledAt time.Time, instance string) (bool, error)
+}
+
+// jobRunRepository реализация JobRunRepository
+type jobRunRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewJobRunRepository создает новый репозиторий истории запусков
+func NewJobRunRepository(db *database.DB, logger *zap.Logger) JobRunRepository {
+	return &jobRunRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет запуск задачи
+func (r *jobRunRepository) Create(ctx context.Context, run *entities.JobRun) error {
+	query := `
+		INSERT INTO job_runs (
+			id, job_name, trigger, triggered_by, instance, status, error,
+			started_at, finished_at, duration_ms
+		) VALUES (
+			:id, :job_name, :trigger, :triggered_by, :instance, :status, :error,
+			:started_at, :finished_at, :duration_ms
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, run); err != nil {
+		r.logger.Error("Failed to create job run",
+			zap.Error(err),
+			zap.String("job", run.JobName),
+		)
+		return fmt.Errorf("failed to create job run: %w", err)
+	}
+
+	return nil
+}
+
+// List получает запуски задач по фильтрам
+func (r *jobRunRepository) List(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filters.JobName != "" {
+		args = append(args, filters.JobName)
+		conditions = append(conditions, fmt.Sprintf("job_name = $%d", len(args)))
+	}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := `SELECT * FROM job_runs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at DESC, id"
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	var runs []*entities.JobRun
+	if err := r.db.SelectContext(ctx, &runs, query, args...); err != nil {
+		r.logger.Error("Failed to list job runs", zap.Error(err))
+		return nil, fmt.Errorf("failed to list job runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// ClaimScheduledRun отмечает запуск по расписанию. Повторная отметка того же запуска тем же
+// экземпляром успешна, поэтому запрос можно повторить после сбоя соединения
+func (r *jobRunRepository) ClaimScheduledRun(ctx context.Context, name string, scheduledAt time.Time, instance string) (bool, error) {
+	query := `
+		INSERT INTO job_schedule_claims (job_name, scheduled_at, instance, claimed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (job_name) DO UPDATE
+		SET scheduled_at = EXCLUDED.scheduled_at, instance = EXCLUDED.instance, claimed_at = EXCLUDED.claimed_at
+		WHERE job_schedule_claims.scheduled_at < EXCLUDED.scheduled_at
+			OR (job_schedule_claims.scheduled_at = EXCLUDED.scheduled_at AND job_schedule_claims.instance = EXCLUDED.instance)`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, name, scheduledAt, instance)
+	if err != nil {
+		r.logger.Error("Failed to claim scheduled job run",
+			zap.Error(err),
+			zap.String("job", name),
+		)
+		return false, fmt.Errorf("failed to claim scheduled job run: %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return claimed > 0, nil
+}
+
+// DeleteOlderThan удаляет запуски старше срока хранения истории
+func (r *jobRunRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM job_runs WHERE started_at < $1`, before)
+	if err != nil {
+		r.logger.Error("Failed to delete old job runs", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete old job runs: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(deleted), nil
+}
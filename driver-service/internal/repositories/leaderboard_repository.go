@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LeaderboardRepository интерфейс для недельных агрегатов рейтингов водителей
+type LeaderboardRepository interface {
+	GetWeeklyAggregates(ctx context.Context, weekStart time.Time) ([]*entities.LeaderboardAggregate, error)
+	Refresh(ctx context.Context) error
+}
+
+// leaderboardRepository реализация LeaderboardRepository поверх материализованного представления
+type leaderboardRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewLeaderboardRepository создает новый репозиторий рейтингов
+func NewLeaderboardRepository(db *database.DB, logger *zap.Logger) LeaderboardRepository {
+	return &leaderboardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// leaderboardRow строка driver_weekly_stats вместе с данными водителя
+type leaderboardRow struct {
+	DriverID      uuid.UUID         `db:"driver_id"`
+	FirstName     string            `db:"first_name"`
+	LastName      string            `db:"last_name"`
+	Metadata      entities.Metadata `db:"metadata"`
+	WeekStart     time.Time         `db:"week_start"`
+	Trips         int               `db:"trips"`
+	Earnings      float64           `db:"earnings"`
+	AverageRating float64           `db:"average_rating"`
+	RatingCount   int               `db:"rating_count"`
+}
+
+// GetWeeklyAggregates получает показатели водителей за неделю.
+// Имя, город, автопарк и настройки приватности берутся из актуальной записи водителя
+func (r *leaderboardRepository) GetWeeklyAggregates(ctx context.Context, weekStart time.Time) ([]*entities.LeaderboardAggregate, error) {
+	query := `
+		SELECT s.driver_id, d.first_name, d.last_name, d.metadata, s.week_start,
+			s.trips, s.earnings, s.average_rating, s.rating_count
+		FROM driver_weekly_stats s
+		JOIN drivers d ON d.id = s.driver_id
+		WHERE s.week_start = $1 AND d.deleted_at IS NULL`
+
+	var rows []leaderboardRow
+	if err := r.db.SelectContext(ctx, &rows, query, weekStart); err != nil {
+		r.logger.Error("Failed to get weekly leaderboard aggregates",
+			zap.Error(err),
+			zap.Time("week_start", weekStart),
+		)
+		return nil, fmt.Errorf("failed to get weekly leaderboard aggregates: %w", err)
+	}
+
+	aggregates := make([]*entities.LeaderboardAggregate, len(rows))
+	for i, row := range rows {
+		driver := &entities.Driver{Metadata: row.Metadata}
+		aggregates[i] = &entities.LeaderboardAggregate{
+			DriverID:      row.DriverID,
+			FirstName:     row.FirstName,
+			LastName:      row.LastName,
+			City:          driver.City(),
+			FleetID:       driver.FleetID,
+			Visibility:    driver.LeaderboardVisibility(),
+			WeekStart:     row.WeekStart,
+			Trips:         row.Trips,
+			Earnings:      row.Earnings,
+			AverageRating: row.AverageRating,
+			RatingCount:   row.RatingCount,
+		}
+	}
+
+	return aggregates, nil
+}
+
+// Refresh пересчитывает материализованное представление, не блокируя чтение
+func (r *leaderboardRepository) Refresh(ctx context.Context) error {
+	if _, err := r.db.ExecIdempotentContext(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY driver_weekly_stats`); err != nil {
+		r.logger.Error("Failed to refresh weekly driver stats", zap.Error(err))
+		return fmt.Errorf("failed to refresh weekly driver stats: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LocationExportRepository интерфейс для выгрузок истории местоположений
+type LocationExportRepository interface {
+	Create(ctx context.Context, export *entities.LocationExport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.LocationExport, error)
+	// Update сохраняет состояние и результат выгрузки
+	Update(ctx context.Context, export *entities.LocationExport) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListExpired возвращает до limit выгрузок, срок хранения которых истек к before
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.LocationExport, error)
+	// FailStale завершает ошибкой выгрузки в очереди или в работе, созданные раньше createdBefore:
+	// их задания потеряны при перезапуске экземпляра. Возвращает число завершенных выгрузок
+	FailStale(ctx context.Context, createdBefore time.Time, reason string, now time.Time, retention time.Duration) (int64, error)
+}
+
+// locationExportRepository реализация LocationExportRepository
+type locationExportRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewLocationExportRepository создает новый репозиторий выгрузок истории местоположений
+func NewLocationExportRepository(db *database.DB, logger *zap.Logger) LocationExportRepository {
+	return &locationExportRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет выгрузку
+func (r *locationExportRepository) Create(ctx context.Context, export *entities.LocationExport) error {
+	query := `
+		INSERT INTO location_exports (
+			id, driver_id, format, from_time, to_time, precise, status, requested_by,
+			row_count, size_bytes, file_url, error, created_at, started_at, completed_at, expires_at
+		) VALUES (
+			:id, :driver_id, :format, :from_time, :to_time, :precise, :status, :requested_by,
+			:row_count, :size_bytes, :file_url, :error, :created_at, :started_at, :completed_at, :expires_at
+		)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, export); err != nil {
+		r.logger.Error("Failed to create location export",
+			zap.Error(err),
+			zap.String("driver_id", export.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create location export: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает выгрузку по ID
+func (r *locationExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.LocationExport, error) {
+	var export entities.LocationExport
+	if err := r.db.GetContext(ctx, &export, `SELECT * FROM location_exports WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrLocationExportNotFound
+		}
+		r.logger.Error("Failed to get location export",
+			zap.Error(err),
+			zap.String("export_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get location export: %w", err)
+	}
+	return &export, nil
+}
+
+// Update сохраняет состояние и результат выгрузки
+func (r *locationExportRepository) Update(ctx context.Context, export *entities.LocationExport) error {
+	query := `
+		UPDATE location_exports SET
+			status = :status, row_count = :row_count, size_bytes = :size_bytes, file_url = :file_url,
+			error = :error, started_at = :started_at, completed_at = :completed_at, expires_at = :expires_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, export)
+	if err != nil {
+		r.logger.Error("Failed to update location export",
+			zap.Error(err),
+			zap.String("export_id", export.ID.String()),
+		)
+		return fmt.Errorf("failed to update location export: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrLocationExportNotFound
+	}
+
+	return nil
+}
+
+// Delete удаляет выгрузку
+func (r *locationExportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM location_exports WHERE id = $1`, id); err != nil {
+		r.logger.Error("Failed to delete location export",
+			zap.Error(err),
+			zap.String("export_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete location export: %w", err)
+	}
+	return nil
+}
+
+// ListExpired получает выгрузки с истекшим сроком хранения, начиная с самых старых
+func (r *locationExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.LocationExport, error) {
+	query := `
+		SELECT * FROM location_exports
+		WHERE expires_at IS NOT NULL AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+
+	var exports []*entities.LocationExport
+	if err := r.db.SelectContext(ctx, &exports, query, before, limit); err != nil {
+		r.logger.Error("Failed to list expired location exports", zap.Error(err))
+		return nil, fmt.Errorf("failed to list expired location exports: %w", err)
+	}
+	return exports, nil
+}
+
+// FailStale завершает ошибкой зависшие выгрузки
+func (r *locationExportRepository) FailStale(ctx context.Context, createdBefore time.Time, reason string, now time.Time, retention time.Duration) (int64, error) {
+	query := `
+		UPDATE location_exports SET
+			status = $1, error = $2, completed_at = $3, expires_at = $4
+		WHERE status IN ($5, $6) AND created_at < $7`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query,
+		entities.LocationExportFailed, reason, now, now.Add(retention),
+		entities.LocationExportPending, entities.LocationExportRunning, createdBefore,
+	)
+	if err != nil {
+		r.logger.Error("Failed to fail stale location exports", zap.Error(err))
+		return 0, fmt.Errorf("failed to fail stale location exports: %w", err)
+	}
+
+	return result.RowsAffected()
+}
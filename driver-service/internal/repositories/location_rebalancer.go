@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LocationRebalancer переносит местоположения города на другой шард
+type LocationRebalancer struct {
+	router *database.ShardRouter
+	repo   ShardRepository
+	logger *zap.Logger
+}
+
+// NewLocationRebalancer создает новый LocationRebalancer
+func NewLocationRebalancer(router *database.ShardRouter, repo ShardRepository, logger *zap.Logger) *LocationRebalancer {
+	return &LocationRebalancer{
+		router: router,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Rebalance переносит город на шард target:
+//  1. блокирует запись местоположений города и ждет lockWait, пока экземпляры сервиса
+//     перечитают назначения;
+//  2. копирует местоположения пакетами по batchSize (повторный запуск пропускает скопированные);
+//  3. закрепляет город за новым шардом и снимает блокировку;
+//  4. снова ждет lockWait, пока чтения переключатся на новый шард, и удаляет местоположения
+//     города со старого шарда.
+//
+// При ошибке копирования блокировка снимается и город остается на прежнем шарде
+func (b *LocationRebalancer) Rebalance(ctx context.Context, city, target string, batchSize int, lockWait time.Duration) (*entities.ShardRebalanceResult, error) {
+	started := time.Now()
+	city = entities.NormalizeCity(city)
+	if err := LoadShardAssignments(ctx, b.repo, b.router); err != nil {
+		return nil, err
+	}
+
+	source := b.router.Resolve(city).Shard
+	if source == target {
+		return nil, entities.ErrShardUnchanged
+	}
+	sourceDB, err := b.router.Shard(source)
+	if err != nil {
+		return nil, err
+	}
+	targetDB, err := b.router.Shard(target)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.ShardRebalanceResult{City: city, FromShard: source, ToShard: target}
+	log := b.logger.With(zap.String("city", city), zap.String("from", source), zap.String("to", target))
+
+	if err := b.repo.SaveAssignment(ctx, &entities.CityShard{City: city, Shard: source, Locked: true}); err != nil {
+		return nil, err
+	}
+	log.Info("City locked for rebalancing", zap.Duration("lock_wait", lockWait))
+
+	copied, err := b.waitAndCopy(ctx, sourceDB, targetDB, city, batchSize, lockWait)
+	result.Copied = copied
+	if err != nil {
+		// Город остается на прежнем шарде; скопированные строки будут пропущены при повторе
+		if unlockErr := b.repo.SaveAssignment(context.Background(), &entities.CityShard{City: city, Shard: source}); unlockErr != nil {
+			log.Error("Failed to unlock city after rebalance failure", zap.Error(unlockErr))
+		}
+		log.Error("City rebalance failed", zap.Error(err), zap.Int64("copied", copied))
+		return result, err
+	}
+
+	if err := b.repo.SaveAssignment(ctx, &entities.CityShard{City: city, Shard: target}); err != nil {
+		return result, err
+	}
+	log.Info("City moved to new shard", zap.Int64("copied", copied))
+
+	// После переключения чтений оставшиеся на старом шарде строки ни на что не влияют
+	deleted, err := b.waitAndDelete(ctx, sourceDB, city, batchSize, lockWait)
+	result.Deleted = deleted
+	result.Duration = time.Since(started)
+	result.CompletedAt = time.Now()
+	if err != nil {
+		log.Error("Failed to delete moved locations from old shard", zap.Error(err), zap.Int64("deleted", deleted))
+		return result, err
+	}
+
+	log.Info("City rebalance completed",
+		zap.Int64("copied", result.Copied),
+		zap.Int64("deleted", result.Deleted),
+		zap.Duration("duration", result.Duration),
+	)
+	return result, nil
+}
+
+// waitAndCopy ждет, пока блокировка записи дойдет до всех экземпляров, и копирует местоположения
+func (b *LocationRebalancer) waitAndCopy(ctx context.Context, sourceDB, targetDB *database.DB, city string, batchSize int, lockWait time.Duration) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(lockWait):
+	}
+
+	selectQuery := `
+		SELECT ` + locationColumns + ` FROM driver_locations
+		WHERE shard_key = $1 AND (recorded_at, id) > ($2, $3)
+		ORDER BY recorded_at, id
+		LIMIT $4`
+	insertQuery := `
+		INSERT INTO driver_locations (
+			id, driver_id, latitude, longitude, altitude, accuracy,
+			speed, bearing, address, metadata, shard_key, recorded_at, created_at
+		) VALUES (
+			:id, :driver_id, :latitude, :longitude, :altitude, :accuracy,
+			:speed, :bearing, :address, :metadata, :shard_key, :recorded_at, :created_at
+		)
+		ON CONFLICT DO NOTHING`
+
+	var (
+		copied int64
+		lastAt time.Time
+		lastID uuid.UUID
+	)
+	for {
+		var batch []*entities.DriverLocation
+		if err := sourceDB.SelectContext(ctx, &batch, selectQuery, city, lastAt, lastID, batchSize); err != nil {
+			return copied, fmt.Errorf("failed to read locations from source shard: %w", err)
+		}
+		if len(batch) == 0 {
+			return copied, nil
+		}
+
+		if _, err := targetDB.NamedExecIdempotentContext(ctx, insertQuery, batch); err != nil {
+			return copied, fmt.Errorf("failed to copy locations to target shard: %w", err)
+		}
+		copied += int64(len(batch))
+
+		last := batch[len(batch)-1]
+		lastAt, lastID = last.RecordedAt, last.ID
+	}
+}
+
+// waitAndDelete ждет, пока экземпляры перейдут на новый шард, и удаляет местоположения
+// города со старого шарда пакетами
+func (b *LocationRebalancer) waitAndDelete(ctx context.Context, sourceDB *database.DB, city string, batchSize int, lockWait time.Duration) (int64, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-time.After(lockWait):
+	}
+
+	// Ключ (id, recorded_at) находит строку по первичному ключу своей секции
+	query := `
+		DELETE FROM driver_locations
+		WHERE (id, recorded_at) IN (
+			SELECT id, recorded_at FROM driver_locations WHERE shard_key = $1 LIMIT $2
+		)`
+
+	var deleted int64
+	for {
+		res, err := sourceDB.ExecIdempotentContext(ctx, query, city, batchSize)
+		if err != nil {
+			return deleted, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		deleted += n
+		if n == 0 {
+			return deleted, nil
+		}
+	}
+}
@@ -4,27 +4,74 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"driver-service/internal/domain/entities"
 	"driver-service/internal/infrastructure/database"
 
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"go.uber.org/zap"
 )
 
 // LocationRepository интерфейс для работы с местоположениями водителей
 type LocationRepository interface {
-	Create(ctx context.Context, location *entities.DriverLocation) error
+	// Create сохраняет местоположение; возвращает false, если точка водителя с тем же
+	// временем записи уже сохранена
+	Create(ctx context.Context, location *entities.DriverLocation) (bool, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverLocation, error)
 	GetLatestByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverLocation, error)
 	GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverLocation, error)
 	List(ctx context.Context, filters *entities.LocationFilters) ([]*entities.DriverLocation, error)
-	CreateBatch(ctx context.Context, locations []*entities.DriverLocation) error
+	// CreateBatch сохраняет пакет, пропуская точки, уже сохраненные для водителя с тем же
+	// временем записи, и возвращает сохраненные
+	CreateBatch(ctx context.Context, locations []*entities.DriverLocation) ([]*entities.DriverLocation, error)
+	// DeleteOld удаляет местоположения старше olderThan
 	DeleteOld(ctx context.Context, olderThan time.Time) error
+	// EnsurePartitions создает недостающие месячные секции с месяца from по месяц to
+	// включительно и возвращает число созданных
+	EnsurePartitions(ctx context.Context, from, to time.Time) (int, error)
+	// GetOldestRecordedAt возвращает время записи самого старого местоположения
+	GetOldestRecordedAt(ctx context.Context) (time.Time, error)
+	// GetActivity считает пробег и время на связи водителя по точкам в полуинтервале [from, to);
+	// интервалы между точками длиннее maxGap во время на связи не входят, 0 — учитываются все
+	GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time, maxGap time.Duration) (*entities.LocationActivity, error)
+	// ListDriverIDsInTimeRange возвращает водителей с местоположениями в полуинтервале [from, to)
+	ListDriverIDsInTimeRange(ctx context.Context, from, to time.Time) ([]uuid.UUID, error)
+	// DeleteByDriverID удаляет все местоположения водителя и возвращает их число
+	DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error)
 	GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error)
 }
 
+// locationColumns колонки driver_locations, отображаемые на entities.DriverLocation.
+// Генерируемые из metadata колонки используются только для фильтрации.
+const locationColumns = `id, driver_id, latitude, longitude, altitude, accuracy,
+	speed, bearing, address, metadata, shard_key, recorded_at, created_at`
+
+// locationPartitionPrefix префикс месячных секций driver_locations: driver_locations_pYYYYMM.
+// Точки вне созданных секций попадают в секцию по умолчанию driver_locations_default
+const locationPartitionPrefix = "driver_locations_p"
+
+// locationPartition месячная секция driver_locations: полуинтервал [start, end) в UTC
+type locationPartition struct {
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+// monthPartition возвращает секцию месяца, в который попадает t
+func monthPartition(t time.Time) locationPartition {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return locationPartition{
+		name:  locationPartitionPrefix + start.Format("200601"),
+		start: start,
+		end:   start.AddDate(0, 1, 0),
+	}
+}
+
 type locationRepository struct {
 	db     *database.DB
 	logger *zap.Logger
@@ -37,25 +84,36 @@ func NewLocationRepository(db *database.DB, logger *zap.Logger) LocationReposito
 	}
 }
 
-func (r *locationRepository) Create(ctx context.Context, location *entities.DriverLocation) error {
+// Create сохраняет местоположение. Повтор пропускается по уникальному ключу (driver_id, recorded_at);
+// по числу вставленных строк вызывающий определяет, обрабатывать ли точку дальше
+func (r *locationRepository) Create(ctx context.Context, location *entities.DriverLocation) (bool, error) {
 	query := `
 		INSERT INTO driver_locations (
 			id, driver_id, latitude, longitude, altitude, accuracy,
-			speed, bearing, address, metadata, recorded_at, created_at
+			speed, bearing, address, metadata, shard_key, recorded_at, created_at
 		) VALUES (
 			:id, :driver_id, :latitude, :longitude, :altitude, :accuracy,
-			:speed, :bearing, :address, :metadata, :recorded_at, :created_at
-		)`
+			:speed, :bearing, :address, :metadata, :shard_key, :recorded_at, :created_at
+		)
+		ON CONFLICT (driver_id, recorded_at) DO NOTHING`
 
-	_, err := r.db.NamedExecContext(ctx, query, location)
-	return err
+	result, err := r.db.NamedExecContext(ctx, query, location)
+	if err != nil {
+		return false, err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return rowsAffected > 0, nil
 }
 
 func (r *locationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverLocation, error) {
 	var location entities.DriverLocation
-	query := `SELECT * FROM driver_locations WHERE id = $1`
+	query := `SELECT ` + locationColumns + ` FROM driver_locations WHERE id = $1`
 
-	err := r.db.GetContext(ctx, &location, query, id)
+	err := r.db.ReplicaGetContext(ctx, &location, query, id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, entities.ErrLocationNotFound
@@ -68,7 +126,7 @@ func (r *locationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entiti
 func (r *locationRepository) GetLatestByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverLocation, error) {
 	var location entities.DriverLocation
 	query := `
-		SELECT * FROM driver_locations 
+		SELECT ` + locationColumns + ` FROM driver_locations 
 		WHERE driver_id = $1 
 		ORDER BY recorded_at DESC 
 		LIMIT 1`
@@ -86,7 +144,7 @@ func (r *locationRepository) GetLatestByDriverID(ctx context.Context, driverID u
 func (r *locationRepository) GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverLocation, error) {
 	var locations []*entities.DriverLocation
 	query := `
-		SELECT * FROM driver_locations 
+		SELECT ` + locationColumns + ` FROM driver_locations 
 		WHERE driver_id = $1 AND recorded_at BETWEEN $2 AND $3
 		ORDER BY recorded_at ASC`
 
@@ -98,31 +156,76 @@ func (r *locationRepository) List(ctx context.Context, filters *entities.Locatio
 	query, args := r.buildListQuery(filters)
 
 	var locations []*entities.DriverLocation
-	err := r.db.SelectContext(ctx, &locations, query, args...)
+	err := r.db.ReplicaSelectContext(ctx, &locations, query, args...)
 	return locations, err
 }
 
-func (r *locationRepository) CreateBatch(ctx context.Context, locations []*entities.DriverLocation) error {
+func (r *locationRepository) CreateBatch(ctx context.Context, locations []*entities.DriverLocation) ([]*entities.DriverLocation, error) {
 	if len(locations) == 0 {
-		return nil
+		return nil, nil
 	}
 
+	// Повторы пропускаются по уникальному ключу (driver_id, recorded_at); RETURNING
+	// возвращает только вставленные строки
 	query := `
 		INSERT INTO driver_locations (
 			id, driver_id, latitude, longitude, altitude, accuracy,
-			speed, bearing, address, metadata, recorded_at, created_at
+			speed, bearing, address, metadata, shard_key, recorded_at, created_at
 		) VALUES (
 			:id, :driver_id, :latitude, :longitude, :altitude, :accuracy,
-			:speed, :bearing, :address, :metadata, :recorded_at, :created_at
-		)`
+			:speed, :bearing, :address, :metadata, :shard_key, :recorded_at, :created_at
+		)
+		ON CONFLICT (driver_id, recorded_at) DO NOTHING
+		RETURNING id`
+
+	rows, err := r.db.NamedQueryContext(ctx, query, locations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	_, err := r.db.NamedExecContext(ctx, query, locations)
-	return err
+	inserted := make(map[uuid.UUID]bool, len(locations))
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		inserted[id] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	saved := make([]*entities.DriverLocation, 0, len(inserted))
+	for _, location := range locations {
+		if inserted[location.ID] {
+			saved = append(saved, location)
+		}
+	}
+	return saved, nil
 }
 
+// DeleteOld удаляет местоположения старше olderThan. Месячные секции, целиком лежащие
+// раньше olderThan, удаляются через DROP TABLE без построчного удаления; DELETE остается
+// только для точек пограничного месяца и секции по умолчанию
 func (r *locationRepository) DeleteOld(ctx context.Context, olderThan time.Time) error {
+	partitions, err := r.listPartitions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, partition := range partitions {
+		if partition.end.After(olderThan) {
+			continue
+		}
+		if _, err := r.db.ExecIdempotentContext(ctx, `DROP TABLE IF EXISTS `+pq.QuoteIdentifier(partition.name)); err != nil {
+			r.logger.Error("Failed to drop location partition", zap.String("partition", partition.name), zap.Error(err))
+			return fmt.Errorf("failed to drop partition %s: %w", partition.name, err)
+		}
+		r.logger.Info("Dropped location partition", zap.String("partition", partition.name))
+	}
+
 	query := `DELETE FROM driver_locations WHERE recorded_at < $1`
-	result, err := r.db.ExecContext(ctx, query, olderThan)
+	result, err := r.db.ExecIdempotentContext(ctx, query, olderThan)
 	if err != nil {
 		return err
 	}
@@ -132,21 +235,198 @@ func (r *locationRepository) DeleteOld(ctx context.Context, olderThan time.Time)
 	return nil
 }
 
+// EnsurePartitions создает недостающие месячные секции. Точки месяца, уже попавшие в секцию
+// по умолчанию, переносятся в новую секцию в той же транзакции
+func (r *locationRepository) EnsurePartitions(ctx context.Context, from, to time.Time) (int, error) {
+	partitions, err := r.listPartitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for month := monthPartition(from).start; !month.After(to); month = month.AddDate(0, 1, 0) {
+		partition := monthPartition(month)
+		if _, ok := partitions[partition.name]; ok {
+			continue
+		}
+		if err := r.createPartition(ctx, partition); err != nil {
+			r.logger.Error("Failed to create location partition", zap.String("partition", partition.name), zap.Error(err))
+			return created, fmt.Errorf("failed to create partition %s: %w", partition.name, err)
+		}
+		r.logger.Info("Created location partition", zap.String("partition", partition.name))
+		created++
+	}
+	return created, nil
+}
+
+// createPartition создает секцию: PostgreSQL не присоединяет секцию, пока ее точки лежат
+// в секции по умолчанию, поэтому они переносятся через временную таблицу
+func (r *locationRepository) createPartition(ctx context.Context, partition locationPartition) error {
+	return r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `
+			CREATE TEMP TABLE driver_locations_moved ON COMMIT DROP AS
+			SELECT `+locationColumns+` FROM driver_locations_default WITH NO DATA`); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `
+			WITH moved AS (
+				DELETE FROM driver_locations_default
+				WHERE recorded_at >= $1 AND recorded_at < $2
+				RETURNING `+locationColumns+`
+			)
+			INSERT INTO driver_locations_moved SELECT * FROM moved`, partition.start, partition.end); err != nil {
+			return err
+		}
+
+		// Границы секции не передаются параметрами в DDL; значения формирует сервис
+		create := fmt.Sprintf(`CREATE TABLE %s PARTITION OF driver_locations FOR VALUES FROM ('%s') TO ('%s')`,
+			pq.QuoteIdentifier(partition.name), partition.start.Format(time.RFC3339), partition.end.Format(time.RFC3339))
+		if _, err := tx.ExecContext(ctx, create); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO driver_locations (`+locationColumns+`)
+			SELECT `+locationColumns+` FROM driver_locations_moved`)
+		return err
+	})
+}
+
+// listPartitions возвращает месячные секции driver_locations по именам
+func (r *locationRepository) listPartitions(ctx context.Context) (map[string]locationPartition, error) {
+	var names []string
+	query := `
+		SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = 'driver_locations'::regclass`
+	if err := r.db.SelectContext(ctx, &names, query); err != nil {
+		r.logger.Error("Failed to list location partitions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list location partitions: %w", err)
+	}
+
+	partitions := make(map[string]locationPartition, len(names))
+	for _, name := range names {
+		if !strings.HasPrefix(name, locationPartitionPrefix) {
+			continue
+		}
+		month, err := time.Parse("200601", strings.TrimPrefix(name, locationPartitionPrefix))
+		if err != nil {
+			continue
+		}
+		partitions[name] = monthPartition(month)
+	}
+	return partitions, nil
+}
+
+// GetOldestRecordedAt получает время записи самого старого местоположения
+func (r *locationRepository) GetOldestRecordedAt(ctx context.Context) (time.Time, error) {
+	var oldest sql.NullTime
+	if err := r.db.GetContext(ctx, &oldest, `SELECT MIN(recorded_at) FROM driver_locations`); err != nil {
+		r.logger.Error("Failed to get oldest location", zap.Error(err))
+		return time.Time{}, fmt.Errorf("failed to get oldest location: %w", err)
+	}
+	if !oldest.Valid {
+		return time.Time{}, entities.ErrLocationNotFound
+	}
+	return oldest.Time, nil
+}
+
+// GetActivity считает пробег по формуле гаверсинуса и время на связи между соседними точками
+func (r *locationRepository) GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time, maxGap time.Duration) (*entities.LocationActivity, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(12742 * ASIN(LEAST(1, SQRT(
+				POWER(SIN(RADIANS(latitude - prev_latitude) / 2), 2) +
+				COS(RADIANS(prev_latitude)) * COS(RADIANS(latitude)) *
+				POWER(SIN(RADIANS(longitude - prev_longitude) / 2), 2)
+			)))), 0) AS distance_km,
+			COALESCE(SUM(EXTRACT(EPOCH FROM recorded_at - prev_recorded_at))
+				FILTER (WHERE $4 <= 0 OR recorded_at - prev_recorded_at <= make_interval(secs => $4)), 0)::BIGINT AS online_seconds
+		FROM (
+			SELECT latitude, longitude, recorded_at,
+				LAG(latitude) OVER w AS prev_latitude,
+				LAG(longitude) OVER w AS prev_longitude,
+				LAG(recorded_at) OVER w AS prev_recorded_at
+			FROM driver_locations
+			WHERE driver_id = $1 AND recorded_at >= $2 AND recorded_at < $3
+			WINDOW w AS (ORDER BY recorded_at)
+		) segments
+		WHERE prev_recorded_at IS NOT NULL`
+
+	var activity entities.LocationActivity
+	if err := r.db.GetContext(ctx, &activity, query, driverID, from, to, maxGap.Seconds()); err != nil {
+		r.logger.Error("Failed to get location activity",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get location activity: %w", err)
+	}
+	return &activity, nil
+}
+
+// ListDriverIDsInTimeRange получает водителей, местоположения которых записаны в [from, to)
+func (r *locationRepository) ListDriverIDsInTimeRange(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT driver_id FROM driver_locations
+		WHERE recorded_at >= $1 AND recorded_at < $2`
+
+	var driverIDs []uuid.UUID
+	if err := r.db.SelectContext(ctx, &driverIDs, query, from, to); err != nil {
+		r.logger.Error("Failed to list drivers with locations", zap.Error(err))
+		return nil, fmt.Errorf("failed to list drivers with locations: %w", err)
+	}
+	return driverIDs, nil
+}
+
+// DeleteByDriverID удаляет все местоположения водителя
+func (r *locationRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM driver_locations WHERE driver_id = $1`, driverID)
+	if err != nil {
+		r.logger.Error("Failed to delete driver locations",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to delete driver locations: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+// GetNearby возвращает последние местоположения водителей в радиусе, ближайшие первыми.
+// Кандидаты отбираются по GIST индексу на geog (ST_DWithin), затем остаются только
+// последние точки водителей — проверка идет по индексу (driver_id, recorded_at DESC)
 func (r *locationRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error) {
 	query := `
-		SELECT DISTINCT ON (driver_id) *
-		FROM driver_locations 
-		WHERE point(longitude, latitude) <@> point($1, $2) <= $3
-		ORDER BY driver_id, recorded_at DESC
-		LIMIT $4`
+		WITH center AS (
+			SELECT ST_SetSRID(ST_MakePoint($1::double precision, $2::double precision), 4326)::geography AS geog
+		)
+		SELECT ` + locationColumns + `
+		FROM driver_locations l, center c
+		WHERE ST_DWithin(l.geog, c.geog, $3::double precision)
+			AND NOT EXISTS (
+				SELECT 1 FROM driver_locations newer
+				WHERE newer.driver_id = l.driver_id AND newer.recorded_at > l.recorded_at
+			)
+		ORDER BY l.geog <-> c.geog, l.driver_id`
+
+	args := []interface{}{lon, lat, radiusKm * 1000}
+	if limit > 0 {
+		args = append(args, limit)
+		query += " LIMIT $4"
+	}
 
 	var locations []*entities.DriverLocation
-	err := r.db.SelectContext(ctx, &locations, query, lon, lat, radiusKm, limit)
+	err := r.db.ReplicaSelectContext(ctx, &locations, query, args...)
 	return locations, err
 }
 
 func (r *locationRepository) buildListQuery(filters *entities.LocationFilters) (string, []interface{}) {
-	query := "SELECT * FROM driver_locations WHERE 1=1"
+	query := "SELECT " + locationColumns + " FROM driver_locations WHERE 1=1"
 	var args []interface{}
 	argCount := 0
 
@@ -157,6 +437,12 @@ func (r *locationRepository) buildListQuery(filters *entities.LocationFilters) (
 			args = append(args, *filters.DriverID)
 		}
 
+		if filters.City != "" {
+			argCount++
+			query += fmt.Sprintf(" AND shard_key = $%d", argCount)
+			args = append(args, entities.NormalizeCity(filters.City))
+		}
+
 		if filters.From != nil {
 			argCount++
 			query += fmt.Sprintf(" AND recorded_at >= $%d", argCount)
@@ -169,6 +455,58 @@ func (r *locationRepository) buildListQuery(filters *entities.LocationFilters) (
 			args = append(args, *filters.To)
 		}
 
+		if filters.OnTrip != nil {
+			argCount++
+			query += fmt.Sprintf(" AND COALESCE(on_trip, FALSE) = $%d", argCount)
+			args = append(args, *filters.OnTrip)
+		}
+
+		if filters.OrderID != nil {
+			argCount++
+			query += fmt.Sprintf(" AND order_id = $%d", argCount)
+			args = append(args, filters.OrderID.String())
+		}
+
+		if filters.Source != nil {
+			argCount++
+			query += fmt.Sprintf(" AND source = $%d", argCount)
+			args = append(args, *filters.Source)
+		}
+
+		// Точки без погрешности не проходят фильтры по точности
+		if filters.MinAccuracy != nil {
+			argCount++
+			query += fmt.Sprintf(" AND accuracy <= $%d", argCount)
+			args = append(args, *filters.MinAccuracy)
+		}
+
+		if filters.OnlyHighAccuracy {
+			argCount++
+			query += fmt.Sprintf(" AND accuracy > 0 AND accuracy < $%d", argCount)
+			args = append(args, entities.HighAccuracyMeters)
+		}
+
+		// Точка без скорости считается стоящей, как в DriverLocation.GetSpeed
+		if filters.MinSpeed != nil && *filters.MinSpeed > 0 {
+			argCount++
+			query += fmt.Sprintf(" AND speed >= $%d", argCount)
+			args = append(args, *filters.MinSpeed)
+		}
+
+		if filters.MaxSpeed != nil {
+			argCount++
+			query += fmt.Sprintf(" AND (speed IS NULL OR speed <= $%d)", argCount)
+			args = append(args, *filters.MaxSpeed)
+		}
+
+		if filters.Bounds != nil {
+			sw, ne := filters.Bounds.SouthWest, filters.Bounds.NorthEast
+			query += fmt.Sprintf(" AND latitude BETWEEN $%d AND $%d AND longitude BETWEEN $%d AND $%d",
+				argCount+1, argCount+2, argCount+3, argCount+4)
+			args = append(args, sw.Latitude, ne.Latitude, sw.Longitude, ne.Longitude)
+			argCount += 4
+		}
+
 		query += " ORDER BY recorded_at DESC"
 
 		if filters.Limit > 0 {
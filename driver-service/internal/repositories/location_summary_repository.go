@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LocationSummaryRepository интерфейс для прореженной истории местоположений.
+// Сводные точки хранятся в основной базе и при шардировании
+type LocationSummaryRepository interface {
+	// Upsert сохраняет сводные точки; точка того же водителя, уровня и интервала заменяется
+	Upsert(ctx context.Context, summaries []*entities.LocationSummary) error
+	// GetByDriverIDInTimeRange возвращает сводные точки уровня, интервалы которых начинаются
+	// в [from, to], в хронологическом порядке
+	GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, tier string, from, to time.Time) ([]*entities.LocationSummary, error)
+	// ListByDriverID возвращает сводные точки водителя всех уровней
+	ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.LocationSummary, error)
+	// DeleteOld удаляет сводные точки уровня с интервалами, начавшимися до olderThan
+	DeleteOld(ctx context.Context, tier string, olderThan time.Time) (int, error)
+	// DeleteByDriverID удаляет все сводные точки водителя и возвращает их число
+	DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error)
+}
+
+// locationSummaryColumns колонки driver_location_summaries
+const locationSummaryColumns = `driver_id, tier, bucket_start, bucket_seconds, latitude, longitude,
+	distance_km, average_speed, max_speed, point_count, on_trip, order_id,
+	first_recorded_at, last_recorded_at`
+
+// locationSummaryRepository реализация LocationSummaryRepository
+type locationSummaryRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewLocationSummaryRepository создает новый репозиторий сводных точек
+func NewLocationSummaryRepository(db *database.DB, logger *zap.Logger) LocationSummaryRepository {
+	return &locationSummaryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Upsert сохраняет сводные точки одним запросом
+func (r *locationSummaryRepository) Upsert(ctx context.Context, summaries []*entities.LocationSummary) error {
+	if len(summaries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO driver_location_summaries (` + locationSummaryColumns + `) VALUES (
+			:driver_id, :tier, :bucket_start, :bucket_seconds, :latitude, :longitude,
+			:distance_km, :average_speed, :max_speed, :point_count, :on_trip, :order_id,
+			:first_recorded_at, :last_recorded_at
+		)
+		ON CONFLICT (driver_id, tier, bucket_start) DO UPDATE SET
+			bucket_seconds = EXCLUDED.bucket_seconds,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			distance_km = EXCLUDED.distance_km,
+			average_speed = EXCLUDED.average_speed,
+			max_speed = EXCLUDED.max_speed,
+			point_count = EXCLUDED.point_count,
+			on_trip = EXCLUDED.on_trip,
+			order_id = EXCLUDED.order_id,
+			first_recorded_at = EXCLUDED.first_recorded_at,
+			last_recorded_at = EXCLUDED.last_recorded_at`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, summaries); err != nil {
+		r.logger.Error("Failed to save location summaries",
+			zap.Error(err),
+			zap.Int("count", len(summaries)),
+		)
+		return fmt.Errorf("failed to save location summaries: %w", err)
+	}
+
+	return nil
+}
+
+// GetByDriverIDInTimeRange получает сводные точки водителя за период
+func (r *locationSummaryRepository) GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, tier string, from, to time.Time) ([]*entities.LocationSummary, error) {
+	query := `
+		SELECT ` + locationSummaryColumns + ` FROM driver_location_summaries
+		WHERE driver_id = $1 AND tier = $2 AND bucket_start BETWEEN $3 AND $4
+		ORDER BY bucket_start ASC`
+
+	var summaries []*entities.LocationSummary
+	if err := r.db.SelectContext(ctx, &summaries, query, driverID, tier, from, to); err != nil {
+		r.logger.Error("Failed to get location summaries",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("tier", tier),
+		)
+		return nil, fmt.Errorf("failed to get location summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// ListByDriverID получает сводные точки водителя всех уровней
+func (r *locationSummaryRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.LocationSummary, error) {
+	query := `
+		SELECT ` + locationSummaryColumns + ` FROM driver_location_summaries
+		WHERE driver_id = $1
+		ORDER BY tier ASC, bucket_start ASC`
+
+	var summaries []*entities.LocationSummary
+	if err := r.db.SelectContext(ctx, &summaries, query, driverID); err != nil {
+		r.logger.Error("Failed to list driver location summaries",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver location summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// DeleteOld удаляет устаревшие сводные точки уровня
+func (r *locationSummaryRepository) DeleteOld(ctx context.Context, tier string, olderThan time.Time) (int, error) {
+	query := `DELETE FROM driver_location_summaries WHERE tier = $1 AND bucket_start < $2`
+	result, err := r.db.ExecIdempotentContext(ctx, query, tier, olderThan)
+	if err != nil {
+		r.logger.Error("Failed to delete old location summaries",
+			zap.Error(err),
+			zap.String("tier", tier),
+		)
+		return 0, fmt.Errorf("failed to delete old location summaries: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+// DeleteByDriverID удаляет все сводные точки водителя
+func (r *locationSummaryRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM driver_location_summaries WHERE driver_id = $1`, driverID)
+	if err != nil {
+		r.logger.Error("Failed to delete driver location summaries",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to delete driver location summaries: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,102 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository in-memory реализация repositories.APIKeyRepository
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[uuid.UUID]*entities.APIKey
+}
+
+var _ repositories.APIKeyRepository = (*APIKeyRepository)(nil)
+
+// NewAPIKeyRepository создает новый in-memory репозиторий ключей API
+func NewAPIKeyRepository() *APIKeyRepository {
+	return &APIKeyRepository{
+		keys: make(map[uuid.UUID]*entities.APIKey),
+	}
+}
+
+// Create сохраняет выпущенный ключ
+func (r *APIKeyRepository) Create(ctx context.Context, key *entities.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.keys[key.ID]; !exists {
+		r.keys[key.ID] = copyAPIKey(key)
+	}
+	return nil
+}
+
+// GetByID получает ключ по ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, entities.ErrAPIKeyNotFound
+	}
+	return copyAPIKey(key), nil
+}
+
+// GetByHash получает ключ по хешу
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, key := range r.keys {
+		if key.KeyHash == keyHash {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, entities.ErrAPIKeyNotFound
+}
+
+// List получает все ключи, новые первыми
+func (r *APIKeyRepository) List(ctx context.Context) ([]*entities.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]*entities.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Revoke сохраняет отзыв ключа; уже отозванный ключ не изменяется
+func (r *APIKeyRepository) Revoke(ctx context.Context, key *entities.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.keys[key.ID]
+	if !ok {
+		return entities.ErrAPIKeyNotFound
+	}
+	if stored.RevokedAt != nil {
+		return entities.ErrAPIKeyRevoked
+	}
+	stored.RevokedAt = key.RevokedAt
+	stored.RevokedBy = key.RevokedBy
+	return nil
+}
+
+// copyAPIKey возвращает независимую копию ключа
+func copyAPIKey(key *entities.APIKey) *entities.APIKey {
+	clone := *key
+	clone.Scopes = append(entities.APIKeyScopeList(nil), key.Scopes...)
+	return &clone
+}
@@ -0,0 +1,239 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// AuditRepository in-memory реализация repositories.AuditRepository
+type AuditRepository struct {
+	mu      sync.RWMutex
+	entries []*entities.AuditEntry
+	anchors []*entities.AuditAnchor
+}
+
+var _ repositories.AuditRepository = (*AuditRepository)(nil)
+
+// NewAuditRepository создает новый in-memory репозиторий журнала аудита
+func NewAuditRepository() *AuditRepository {
+	return &AuditRepository{}
+}
+
+// Create сохраняет запись журнала аудита
+func (r *AuditRepository) Create(ctx context.Context, entry *entities.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, copyAuditEntry(entry))
+	return nil
+}
+
+// ListByDriver получает записи водителя, новые первыми
+func (r *AuditRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.AuditEntry
+	for _, entry := range r.entries {
+		if entry.DriverID != nil && *entry.DriverID == driverID {
+			result = append(result, copyAuditEntry(entry))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return paginate(result, limit, offset), nil
+}
+
+// List получает записи по фильтрам, новые первыми
+func (r *AuditRepository) List(ctx context.Context, filters *entities.AuditFilters) ([]*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.AuditEntry
+	for _, entry := range r.entries {
+		if matchAuditEntry(entry, filters) {
+			result = append(result, copyAuditEntry(entry))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// matchAuditEntry проверяет запись на соответствие фильтрам
+func matchAuditEntry(entry *entities.AuditEntry, filters *entities.AuditFilters) bool {
+	if filters.DriverID != nil && (entry.DriverID == nil || *entry.DriverID != *filters.DriverID) {
+		return false
+	}
+	if filters.FleetID != nil && (entry.FleetID == nil || *entry.FleetID != *filters.FleetID) {
+		return false
+	}
+	if filters.Actor != nil && (entry.Actor == nil || *entry.Actor != *filters.Actor) {
+		return false
+	}
+	if filters.Event != nil && entry.Event != *filters.Event {
+		return false
+	}
+	if filters.Action != nil && entry.Action != *filters.Action {
+		return false
+	}
+	if filters.RequestID != nil && (entry.RequestID == nil || *entry.RequestID != *filters.RequestID) {
+		return false
+	}
+	if filters.From != nil && entry.CreatedAt.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && !entry.CreatedAt.Before(*filters.To) {
+		return false
+	}
+	return true
+}
+
+// CreateChained добавляет запись в конец цепочки хешей
+func (r *AuditRepository) CreateChained(ctx context.Context, entry *entities.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sequence, prevHash := int64(1), entities.AuditChainGenesis
+	if head := r.chainHead(); head != nil {
+		sequence, prevHash = *head.Sequence+1, *head.Hash
+	}
+	if err := entry.Seal(sequence, prevHash); err != nil {
+		return err
+	}
+
+	r.entries = append(r.entries, copyAuditEntry(entry))
+	return nil
+}
+
+// GetChainHead получает последнюю запись цепочки
+func (r *AuditRepository) GetChainHead(ctx context.Context) (*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	head := r.chainHead()
+	if head == nil {
+		return nil, entities.ErrAuditEntryNotFound
+	}
+	return copyAuditEntry(head), nil
+}
+
+// GetChainEntry получает запись цепочки по номеру
+func (r *AuditRepository) GetChainEntry(ctx context.Context, sequence int64) (*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.entries {
+		if entry.Sequence != nil && *entry.Sequence == sequence {
+			return copyAuditEntry(entry), nil
+		}
+	}
+	return nil, entities.ErrAuditEntryNotFound
+}
+
+// ListChain получает записи цепочки по возрастанию номеров
+func (r *AuditRepository) ListChain(ctx context.Context, from, to int64, limit int) ([]*entities.AuditEntry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.AuditEntry
+	for _, entry := range r.entries {
+		if entry.Sequence != nil && *entry.Sequence >= from && *entry.Sequence <= to {
+			result = append(result, copyAuditEntry(entry))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return *result[i].Sequence < *result[j].Sequence
+	})
+	return paginate(result, limit, 0), nil
+}
+
+// CreateAnchor сохраняет закрепление хеша цепочки
+func (r *AuditRepository) CreateAnchor(ctx context.Context, anchor *entities.AuditAnchor) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *anchor
+	r.anchors = append(r.anchors, &clone)
+	return nil
+}
+
+// GetLatestAnchor получает последнее закрепление
+func (r *AuditRepository) GetLatestAnchor(ctx context.Context) (*entities.AuditAnchor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entities.AuditAnchor
+	for _, anchor := range r.anchors {
+		if latest == nil || anchor.Sequence >= latest.Sequence {
+			latest = anchor
+		}
+	}
+	if latest == nil {
+		return nil, entities.ErrAuditAnchorNotFound
+	}
+	clone := *latest
+	return &clone, nil
+}
+
+// ListAnchors получает закрепления записей из отрезка номеров
+func (r *AuditRepository) ListAnchors(ctx context.Context, from, to int64) ([]*entities.AuditAnchor, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.AuditAnchor
+	for _, anchor := range r.anchors {
+		if anchor.Sequence >= from && anchor.Sequence <= to {
+			clone := *anchor
+			result = append(result, &clone)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Sequence < result[j].Sequence
+	})
+	return result, nil
+}
+
+// chainHead возвращает последнюю запись цепочки; вызывается под блокировкой
+func (r *AuditRepository) chainHead() *entities.AuditEntry {
+	var head *entities.AuditEntry
+	for _, entry := range r.entries {
+		if entry.Sequence != nil && (head == nil || *entry.Sequence > *head.Sequence) {
+			head = entry
+		}
+	}
+	return head
+}
+
+// copyAuditEntry возвращает независимую копию записи
+func copyAuditEntry(entry *entities.AuditEntry) *entities.AuditEntry {
+	clone := *entry
+	clone.Details = cloneMetadata(entry.Details)
+	clone.Before = cloneMetadata(entry.Before)
+	clone.After = cloneMetadata(entry.After)
+	if entry.Sequence != nil {
+		sequence := *entry.Sequence
+		clone.Sequence = &sequence
+	}
+	if entry.PrevHash != nil {
+		prevHash := *entry.PrevHash
+		clone.PrevHash = &prevHash
+	}
+	if entry.Hash != nil {
+		hash := *entry.Hash
+		clone.Hash = &hash
+	}
+	return &clone
+}
@@ -0,0 +1,149 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// BlockRepository in-memory реализация repositories.BlockRepository. Статус водителя
+// меняется в связанном DriverRepository
+type BlockRepository struct {
+	mu      sync.RWMutex
+	blocks  map[uuid.UUID]*entities.DriverBlock
+	drivers *DriverRepository
+}
+
+var _ repositories.BlockRepository = (*BlockRepository)(nil)
+
+// NewBlockRepository создает новый in-memory репозиторий блокировок водителей
+func NewBlockRepository(drivers *DriverRepository) *BlockRepository {
+	return &BlockRepository{
+		blocks:  make(map[uuid.UUID]*entities.DriverBlock),
+		drivers: drivers,
+	}
+}
+
+// Create сохраняет блокировку и переводит водителя в статус blocked
+func (r *BlockRepository) Create(ctx context.Context, block *entities.DriverBlock) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, current := range r.blocks {
+		if current.DriverID == block.DriverID && current.IsActive() {
+			return entities.ErrDriverAlreadyBlocked
+		}
+	}
+	if err := r.drivers.UpdateStatus(ctx, block.DriverID, entities.StatusBlocked); err != nil {
+		return err
+	}
+
+	r.blocks[block.ID] = copyBlock(block)
+	return nil
+}
+
+// GetActive получает активную блокировку водителя
+func (r *BlockRepository) GetActive(ctx context.Context, driverID uuid.UUID) (*entities.DriverBlock, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, block := range r.blocks {
+		if block.DriverID == driverID && block.IsActive() {
+			return copyBlock(block), nil
+		}
+	}
+	return nil, entities.ErrBlockNotFound
+}
+
+// ListByDriverID получает историю блокировок водителя, последние первыми
+func (r *BlockRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverBlock, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blocks := make([]*entities.DriverBlock, 0)
+	for _, block := range r.blocks {
+		if block.DriverID == driverID {
+			blocks = append(blocks, copyBlock(block))
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].BlockedAt.After(blocks[j].BlockedAt)
+	})
+	return blocks, nil
+}
+
+// ListExpired получает блокировки с наступившим сроком снятия, самые старые первыми
+func (r *BlockRepository) ListExpired(ctx context.Context, at time.Time, limit int) ([]*entities.DriverBlock, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	blocks := make([]*entities.DriverBlock, 0)
+	for _, block := range r.blocks {
+		if block.IsExpired(at) {
+			blocks = append(blocks, copyBlock(block))
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].UnblockAt.Before(*blocks[j].UnblockAt)
+	})
+	return paginate(blocks, limit, 0), nil
+}
+
+// Release сохраняет снятие блокировки и возвращает водителю статус, если он все еще заблокирован
+func (r *BlockRepository) Release(ctx context.Context, block *entities.DriverBlock, status entities.Status) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.blocks[block.ID]
+	if !ok || !current.IsActive() {
+		return false, entities.ErrBlockNotFound
+	}
+	r.blocks[block.ID] = copyBlock(block)
+
+	driver, err := r.drivers.GetByID(ctx, block.DriverID)
+	if err == entities.ErrDriverNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if driver.Status != entities.StatusBlocked {
+		return false, nil
+	}
+	if err := r.drivers.UpdateStatus(ctx, block.DriverID, status); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// copyBlock возвращает независимую копию блокировки
+func copyBlock(block *entities.DriverBlock) *entities.DriverBlock {
+	clone := *block
+	if block.UnblockAt != nil {
+		unblockAt := *block.UnblockAt
+		clone.UnblockAt = &unblockAt
+	}
+	if block.UnblockedAt != nil {
+		unblockedAt := *block.UnblockedAt
+		clone.UnblockedAt = &unblockedAt
+	}
+	if block.UnblockedBy != nil {
+		unblockedBy := *block.UnblockedBy
+		clone.UnblockedBy = &unblockedBy
+	}
+	if block.UnblockReason != nil {
+		reason := *block.UnblockReason
+		clone.UnblockReason = &reason
+	}
+	if block.UnblockComment != nil {
+		comment := *block.UnblockComment
+		clone.UnblockComment = &comment
+	}
+	return &clone
+}
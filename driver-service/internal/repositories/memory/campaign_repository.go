@@ -0,0 +1,232 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// campaignTargetKey ключ документа кампании
+type campaignTargetKey struct {
+	campaignID uuid.UUID
+	documentID uuid.UUID
+}
+
+// CampaignRepository in-memory реализация repositories.CampaignRepository
+type CampaignRepository struct {
+	mu        sync.RWMutex
+	campaigns map[uuid.UUID]*entities.ReverificationCampaign
+	targets   map[campaignTargetKey]*entities.CampaignTarget
+}
+
+var _ repositories.CampaignRepository = (*CampaignRepository)(nil)
+
+// NewCampaignRepository создает новый in-memory репозиторий кампаний перепроверки
+func NewCampaignRepository() *CampaignRepository {
+	return &CampaignRepository{
+		campaigns: make(map[uuid.UUID]*entities.ReverificationCampaign),
+		targets:   make(map[campaignTargetKey]*entities.CampaignTarget),
+	}
+}
+
+// CreateCampaign сохраняет кампанию и ее документы
+func (r *CampaignRepository) CreateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign, targets []*entities.CampaignTarget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.campaigns[campaign.ID]; !exists {
+		r.campaigns[campaign.ID] = copyCampaign(campaign)
+	}
+	for _, target := range targets {
+		key := campaignTargetKey{campaignID: target.CampaignID, documentID: target.DocumentID}
+		if _, exists := r.targets[key]; !exists {
+			r.targets[key] = copyCampaignTarget(target)
+		}
+	}
+	return nil
+}
+
+// GetCampaign получает кампанию по ID
+func (r *CampaignRepository) GetCampaign(ctx context.Context, id uuid.UUID) (*entities.ReverificationCampaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	campaign, ok := r.campaigns[id]
+	if !ok {
+		return nil, entities.ErrCampaignNotFound
+	}
+	return copyCampaign(campaign), nil
+}
+
+// ListCampaigns получает кампании по статусу, новые первыми
+func (r *CampaignRepository) ListCampaigns(ctx context.Context, status entities.CampaignStatus, limit, offset int) ([]*entities.ReverificationCampaign, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.ReverificationCampaign
+	for _, campaign := range r.campaigns {
+		if status != "" && campaign.Status != status {
+			continue
+		}
+		result = append(result, copyCampaign(campaign))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return paginate(result, limit, offset), nil
+}
+
+// UpdateCampaign сохраняет статус кампании
+func (r *CampaignRepository) UpdateCampaign(ctx context.Context, campaign *entities.ReverificationCampaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.campaigns[campaign.ID]; !ok {
+		return entities.ErrCampaignNotFound
+	}
+	r.campaigns[campaign.ID] = copyCampaign(campaign)
+	return nil
+}
+
+// ListTargets получает документы кампаний по фильтрам
+func (r *CampaignRepository) ListTargets(ctx context.Context, filters *entities.CampaignTargetFilters) ([]*entities.CampaignTarget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.CampaignTarget
+	for _, target := range r.targets {
+		if filters.CampaignID != nil && target.CampaignID != *filters.CampaignID {
+			continue
+		}
+		if filters.DriverID != nil && target.DriverID != *filters.DriverID {
+			continue
+		}
+		if len(filters.Status) > 0 && !containsTargetStatus(filters.Status, target.Status) {
+			continue
+		}
+		if filters.RemindedBefore != nil && target.LastRemindedAt != nil && !target.LastRemindedAt.Before(*filters.RemindedBefore) {
+			continue
+		}
+		result = append(result, copyCampaignTarget(target))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].DriverID != result[j].DriverID {
+			return result[i].DriverID.String() < result[j].DriverID.String()
+		}
+		return result[i].DocumentType < result[j].DocumentType
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// UpdateTarget сохраняет состояние документа кампании
+func (r *CampaignRepository) UpdateTarget(ctx context.Context, target *entities.CampaignTarget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := campaignTargetKey{campaignID: target.CampaignID, documentID: target.DocumentID}
+	if _, ok := r.targets[key]; ok {
+		r.targets[key] = copyCampaignTarget(target)
+	}
+	return nil
+}
+
+// GetProgress считает ход кампании
+func (r *CampaignRepository) GetProgress(ctx context.Context, campaignID uuid.UUID) (*entities.CampaignProgress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	progress := &entities.CampaignProgress{
+		CampaignID:     campaignID,
+		ByDocumentType: []*entities.CampaignTypeProgress{},
+		Daily:          []*entities.CampaignDailyProgress{},
+	}
+	byType := make(map[entities.DocumentType]*entities.CampaignTypeProgress)
+	daily := make(map[time.Time]*entities.CampaignDailyProgress)
+	drivers := make(map[uuid.UUID]bool)
+
+	for _, target := range r.targets {
+		if target.CampaignID != campaignID {
+			continue
+		}
+
+		stats, ok := byType[target.DocumentType]
+		if !ok {
+			stats = &entities.CampaignTypeProgress{DocumentType: target.DocumentType}
+			byType[target.DocumentType] = stats
+			progress.ByDocumentType = append(progress.ByDocumentType, stats)
+		}
+		stats.Total++
+		switch target.Status {
+		case entities.CampaignTargetPending:
+			stats.Pending++
+		case entities.CampaignTargetCompleted:
+			stats.Completed++
+		case entities.CampaignTargetOverdue:
+			stats.Overdue++
+		case entities.CampaignTargetCancelled:
+			stats.Cancelled++
+		}
+
+		compliant, seen := drivers[target.DriverID]
+		drivers[target.DriverID] = (compliant || !seen) && target.Status != entities.CampaignTargetPending && target.Status != entities.CampaignTargetOverdue
+
+		if target.CompletedAt != nil {
+			date := target.CompletedAt.UTC().Truncate(24 * time.Hour)
+			day, ok := daily[date]
+			if !ok {
+				day = &entities.CampaignDailyProgress{Date: date}
+				daily[date] = day
+				progress.Daily = append(progress.Daily, day)
+			}
+			day.Completed++
+		}
+	}
+
+	progress.Drivers = len(drivers)
+	for _, compliant := range drivers {
+		if compliant {
+			progress.CompliantDrivers++
+		}
+	}
+
+	sort.Slice(progress.ByDocumentType, func(i, j int) bool {
+		return progress.ByDocumentType[i].DocumentType < progress.ByDocumentType[j].DocumentType
+	})
+	sort.Slice(progress.Daily, func(i, j int) bool {
+		return progress.Daily[i].Date.Before(progress.Daily[j].Date)
+	})
+	return progress, nil
+}
+
+func containsTargetStatus(statuses []entities.CampaignTargetStatus, status entities.CampaignTargetStatus) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func copyCampaign(campaign *entities.ReverificationCampaign) *entities.ReverificationCampaign {
+	clone := *campaign
+	clone.DocumentTypes = append(entities.DocumentTypeList(nil), campaign.DocumentTypes...)
+	clone.Segment.Statuses = append([]entities.Status(nil), campaign.Segment.Statuses...)
+	clone.Segment.DriverIDs = append([]uuid.UUID(nil), campaign.Segment.DriverIDs...)
+	return &clone
+}
+
+func copyCampaignTarget(target *entities.CampaignTarget) *entities.CampaignTarget {
+	clone := *target
+	return &clone
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+)
+
+// CapacityRepository in-memory реализация repositories.CapacityRepository
+type CapacityRepository struct {
+	mu      sync.RWMutex
+	reports map[capacityReportKey]*entities.CapacityReport
+}
+
+// capacityReportKey отчет уникален в пределах суток и экземпляра сервиса
+type capacityReportKey struct {
+	date     string
+	instance string
+}
+
+var _ repositories.CapacityRepository = (*CapacityRepository)(nil)
+
+// NewCapacityRepository создает новый in-memory репозиторий отчетов о нагрузке
+func NewCapacityRepository() *CapacityRepository {
+	return &CapacityRepository{
+		reports: make(map[capacityReportKey]*entities.CapacityReport),
+	}
+}
+
+// SaveReport сохраняет отчет, заменяя отчет экземпляра за те же сутки
+func (r *CapacityRepository) SaveReport(ctx context.Context, report *entities.CapacityReport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports[capacityReportKey{date: report.Date.Format("2006-01-02"), instance: report.Instance}] = copyCapacityReport(report)
+	return nil
+}
+
+// ListReports получает отчеты начиная с даты from по возрастанию даты
+func (r *CapacityRepository) ListReports(ctx context.Context, from time.Time) ([]*entities.CapacityReport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.CapacityReport, 0, len(r.reports))
+	for _, report := range r.reports {
+		if !report.Date.Before(from) {
+			result = append(result, copyCapacityReport(report))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Date.Equal(result[j].Date) {
+			return result[i].Date.Before(result[j].Date)
+		}
+		return result[i].Instance < result[j].Instance
+	})
+	return result, nil
+}
+
+// DeleteBefore удаляет отчеты старше даты before
+func (r *CapacityRepository) DeleteBefore(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, report := range r.reports {
+		if report.Date.Before(before) {
+			delete(r.reports, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// copyCapacityReport возвращает независимую копию отчета
+func copyCapacityReport(report *entities.CapacityReport) *entities.CapacityReport {
+	clone := *report
+	clone.Endpoints = append(entities.EndpointUsageList(nil), report.Endpoints...)
+	return &clone
+}
@@ -0,0 +1,235 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// CityRepository in-memory реализация repositories.CityRepository. Город водителя хранится
+// в DriverRepository
+type CityRepository struct {
+	mu      sync.RWMutex
+	regions map[uuid.UUID]*entities.Region
+	cities  map[uuid.UUID]*entities.City
+	drivers *DriverRepository
+}
+
+var _ repositories.CityRepository = (*CityRepository)(nil)
+
+// NewCityRepository создает новый in-memory репозиторий городов
+func NewCityRepository(drivers *DriverRepository) *CityRepository {
+	return &CityRepository{
+		regions: make(map[uuid.UUID]*entities.Region),
+		cities:  make(map[uuid.UUID]*entities.City),
+		drivers: drivers,
+	}
+}
+
+// CreateRegion сохраняет регион
+func (r *CityRepository) CreateRegion(ctx context.Context, region *entities.Region) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.regions {
+		if existing.Code == region.Code {
+			return entities.ErrRegionExists
+		}
+	}
+	clone := *region
+	r.regions[region.ID] = &clone
+	return nil
+}
+
+// GetRegion получает регион по ID
+func (r *CityRepository) GetRegion(ctx context.Context, id uuid.UUID) (*entities.Region, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	region, ok := r.regions[id]
+	if !ok {
+		return nil, entities.ErrRegionNotFound
+	}
+	clone := *region
+	return &clone, nil
+}
+
+// ListRegions получает все регионы
+func (r *CityRepository) ListRegions(ctx context.Context) ([]*entities.Region, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.Region, 0, len(r.regions))
+	for _, region := range r.regions {
+		clone := *region
+		result = append(result, &clone)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Code < result[j].Code })
+	return result, nil
+}
+
+// CreateCity сохраняет город
+func (r *CityRepository) CreateCity(ctx context.Context, city *entities.City) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.cities {
+		if existing.Key == city.Key {
+			return entities.ErrCityExists
+		}
+	}
+	r.cities[city.ID] = copyCity(city)
+	return nil
+}
+
+// GetCity получает город по ID
+func (r *CityRepository) GetCity(ctx context.Context, id uuid.UUID) (*entities.City, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	city, ok := r.cities[id]
+	if !ok {
+		return nil, entities.ErrCityNotFound
+	}
+	return copyCity(city), nil
+}
+
+// UpdateCity сохраняет изменения города; ключ и дата создания не меняются
+func (r *CityRepository) UpdateCity(ctx context.Context, city *entities.City) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.cities[city.ID]
+	if !ok {
+		return entities.ErrCityNotFound
+	}
+	stored := copyCity(city)
+	stored.Key = existing.Key
+	stored.CreatedAt = existing.CreatedAt
+	r.cities[city.ID] = stored
+	return nil
+}
+
+// DeleteCity удаляет город и снимает его с водителей
+func (r *CityRepository) DeleteCity(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	city, ok := r.cities[id]
+	if !ok {
+		return entities.ErrCityNotFound
+	}
+	delete(r.cities, id)
+
+	r.drivers.mu.Lock()
+	defer r.drivers.mu.Unlock()
+	for _, driver := range r.drivers.drivers {
+		if driver.ResolvedCity != nil && *driver.ResolvedCity == city.Key {
+			driver.ResolvedCity = nil
+		}
+	}
+	return nil
+}
+
+// List получает страницу городов в порядке названия
+func (r *CityRepository) List(ctx context.Context, filters *entities.CityFilters) ([]*entities.City, error) {
+	cities := r.filter(filters)
+	sort.Slice(cities, func(i, j int) bool {
+		if cities[i].Name != cities[j].Name {
+			return cities[i].Name < cities[j].Name
+		}
+		return cities[i].ID.String() < cities[j].ID.String()
+	})
+	return paginate(cities, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает число городов по фильтрам
+func (r *CityRepository) Count(ctx context.Context, filters *entities.CityFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// ListActive получает активные города в порядке создания
+func (r *CityRepository) ListActive(ctx context.Context) ([]*entities.City, error) {
+	active := true
+	cities := r.filter(&entities.CityFilters{Active: &active})
+	sort.Slice(cities, func(i, j int) bool { return cities[i].CreatedAt.Before(cities[j].CreatedAt) })
+	return cities, nil
+}
+
+// AssignDriver записывает водителю город, определенный по местоположению
+func (r *CityRepository) AssignDriver(ctx context.Context, driverID uuid.UUID, city string) error {
+	return r.drivers.mutate(driverID, func(d *entities.Driver, now time.Time) {
+		d.ResolvedCity = &city
+	})
+}
+
+// CountActiveDrivers считает активных водителей по городам
+func (r *CityRepository) CountActiveDrivers(ctx context.Context) ([]*entities.CityDriverCount, error) {
+	r.drivers.mu.RLock()
+	defer r.drivers.mu.RUnlock()
+
+	byCity := make(map[string]*entities.CityDriverCount)
+	for _, driver := range r.drivers.drivers {
+		if driver.DeletedAt != nil || !driver.IsActive() {
+			continue
+		}
+		key := driver.OperatingCity()
+		count, ok := byCity[key]
+		if !ok {
+			count = &entities.CityDriverCount{}
+			if key != "" {
+				city := key
+				count.City = &city
+			}
+			byCity[key] = count
+		}
+		count.Add(driver.Status)
+	}
+
+	result := make([]*entities.CityDriverCount, 0, len(byCity))
+	for _, count := range byCity {
+		result = append(result, count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		// Водители без города идут последними, как NULL в PostgreSQL
+		if result[i].City == nil || result[j].City == nil {
+			return result[j].City == nil && result[i].City != nil
+		}
+		return *result[i].City < *result[j].City
+	})
+	return result, nil
+}
+
+// filter возвращает копии городов, удовлетворяющих фильтрам
+func (r *CityRepository) filter(filters *entities.CityFilters) []*entities.City {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.City, 0, len(r.cities))
+	for _, city := range r.cities {
+		if filters.RegionID != nil && city.RegionID != *filters.RegionID {
+			continue
+		}
+		if filters.Active != nil && city.Active != *filters.Active {
+			continue
+		}
+		result = append(result, copyCity(city))
+	}
+	return result
+}
+
+// copyCity возвращает независимую копию города
+func copyCity(city *entities.City) *entities.City {
+	clone := *city
+	clone.Boundary = append(entities.GeoPointList(nil), city.Boundary...)
+	return &clone
+}
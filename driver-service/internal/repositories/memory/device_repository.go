@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// deviceKey ключ устройства водителя
+type deviceKey struct {
+	driverID uuid.UUID
+	deviceID string
+}
+
+// DeviceRepository in-memory реализация repositories.DeviceRepository
+type DeviceRepository struct {
+	mu      sync.RWMutex
+	devices map[deviceKey]*entities.DriverDevice
+}
+
+var _ repositories.DeviceRepository = (*DeviceRepository)(nil)
+
+// NewDeviceRepository создает новый in-memory репозиторий устройств водителей
+func NewDeviceRepository() *DeviceRepository {
+	return &DeviceRepository{
+		devices: make(map[deviceKey]*entities.DriverDevice),
+	}
+}
+
+// Register создает или обновляет устройство водителя
+func (r *DeviceRepository) Register(ctx context.Context, device *entities.DriverDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := deviceKey{driverID: device.DriverID, deviceID: device.DeviceID}
+	if current, ok := r.devices[key]; ok {
+		device.ID = current.ID
+		device.RegisteredAt = current.RegisteredAt
+		if current.LastSeenAt.After(device.LastSeenAt) {
+			device.LastSeenAt = current.LastSeenAt
+		}
+	}
+	device.RevokedAt = nil
+	r.devices[key] = copyDevice(device)
+	return nil
+}
+
+// Get получает устройство водителя
+func (r *DeviceRepository) Get(ctx context.Context, driverID uuid.UUID, deviceID string) (*entities.DriverDevice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	device, ok := r.devices[deviceKey{driverID: driverID, deviceID: deviceID}]
+	if !ok {
+		return nil, entities.ErrDeviceNotFound
+	}
+	return copyDevice(device), nil
+}
+
+// GetActiveByDeviceID получает последнее активное неотозванное устройство с этим ID
+func (r *DeviceRepository) GetActiveByDeviceID(ctx context.Context, deviceID string) (*entities.DriverDevice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *entities.DriverDevice
+	for key, device := range r.devices {
+		if key.deviceID != deviceID || device.IsRevoked() {
+			continue
+		}
+		if found == nil || device.LastSeenAt.After(found.LastSeenAt) {
+			found = device
+		}
+	}
+	if found == nil {
+		return nil, entities.ErrDeviceNotFound
+	}
+	return copyDevice(found), nil
+}
+
+// ListByDriverID получает устройства водителя, последние активные первыми
+func (r *DeviceRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDevice, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	devices := make([]*entities.DriverDevice, 0)
+	for key, device := range r.devices {
+		if key.driverID == driverID {
+			devices = append(devices, copyDevice(device))
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].LastSeenAt.After(devices[j].LastSeenAt)
+	})
+	return devices, nil
+}
+
+// Revoke отзывает устройство и удаляет его токен push-уведомлений
+func (r *DeviceRepository) Revoke(ctx context.Context, device *entities.DriverDevice) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, current := range r.devices {
+		if current.ID == device.ID {
+			current.RevokedAt = device.RevokedAt
+			current.PushToken = nil
+			return nil
+		}
+	}
+	return entities.ErrDeviceNotFound
+}
+
+// copyDevice возвращает независимую копию устройства
+func copyDevice(device *entities.DriverDevice) *entities.DriverDevice {
+	clone := *device
+	if device.PushToken != nil {
+		token := *device.PushToken
+		clone.PushToken = &token
+	}
+	if device.RevokedAt != nil {
+		revokedAt := *device.RevokedAt
+		clone.RevokedAt = &revokedAt
+	}
+	return &clone
+}
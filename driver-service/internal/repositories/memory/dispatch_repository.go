@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// dispatchOfferKey ключ предложения заказа водителю
+type dispatchOfferKey struct {
+	driverID uuid.UUID
+	orderID  uuid.UUID
+}
+
+// DispatchRepository in-memory реализация repositories.DispatchRepository
+type DispatchRepository struct {
+	mu     sync.RWMutex
+	offers map[dispatchOfferKey]entities.DispatchOffer
+}
+
+var _ repositories.DispatchRepository = (*DispatchRepository)(nil)
+
+// NewDispatchRepository создает новый in-memory репозиторий ответов на предложения заказов
+func NewDispatchRepository() *DispatchRepository {
+	return &DispatchRepository{
+		offers: make(map[dispatchOfferKey]entities.DispatchOffer),
+	}
+}
+
+// RecordOffer сохраняет ответ водителя; повторный ответ на то же предложение игнорируется
+func (r *DispatchRepository) RecordOffer(ctx context.Context, offer *entities.DispatchOffer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := dispatchOfferKey{driverID: offer.DriverID, orderID: offer.OrderID}
+	if _, exists := r.offers[key]; !exists {
+		r.offers[key] = *offer
+	}
+	return nil
+}
+
+// RecordCancellation отмечает принятое предложение отмененным; повторная отмена игнорируется
+func (r *DispatchRepository) RecordCancellation(ctx context.Context, driverID, orderID uuid.UUID, cancelledAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := dispatchOfferKey{driverID: driverID, orderID: orderID}
+	offer, exists := r.offers[key]
+	if !exists || !offer.Accepted {
+		return entities.ErrDispatchOfferNotAccepted
+	}
+	if offer.CancelledAt == nil {
+		offer.CancelledAt = &cancelledAt
+		r.offers[key] = offer
+	}
+	return nil
+}
+
+// GetStats считает ответы водителей начиная с since
+func (r *DispatchRepository) GetStats(ctx context.Context, driverIDs []uuid.UUID, since time.Time) (map[uuid.UUID]*entities.DispatchStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	wanted := make(map[uuid.UUID]bool, len(driverIDs))
+	for _, id := range driverIDs {
+		wanted[id] = true
+	}
+
+	stats := make(map[uuid.UUID]*entities.DispatchStats)
+	for _, offer := range r.offers {
+		if !wanted[offer.DriverID] || offer.RespondedAt.Before(since) {
+			continue
+		}
+
+		driverStats, ok := stats[offer.DriverID]
+		if !ok {
+			driverStats = &entities.DispatchStats{DriverID: offer.DriverID}
+			stats[offer.DriverID] = driverStats
+		}
+		driverStats.OffersReceived++
+		if !offer.Accepted {
+			continue
+		}
+		driverStats.OffersAccepted++
+		if offer.CancelledAt != nil {
+			driverStats.OffersCancelled++
+		}
+		if driverStats.LastAcceptedAt == nil || offer.RespondedAt.After(*driverStats.LastAcceptedAt) {
+			respondedAt := offer.RespondedAt
+			driverStats.LastAcceptedAt = &respondedAt
+		}
+	}
+	return stats, nil
+}
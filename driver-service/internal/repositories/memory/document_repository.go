@@ -0,0 +1,591 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DocumentRepository in-memory реализация repositories.DocumentRepository
+type DocumentRepository struct {
+	mu        sync.RWMutex
+	documents map[uuid.UUID]*entities.DriverDocument
+	// versions прежние версии по ID документа в порядке замены
+	versions map[uuid.UUID][]*entities.DocumentVersion
+}
+
+var _ repositories.DocumentRepository = (*DocumentRepository)(nil)
+
+// NewDocumentRepository создает новый in-memory репозиторий документов
+func NewDocumentRepository() *DocumentRepository {
+	return &DocumentRepository{
+		documents: make(map[uuid.UUID]*entities.DriverDocument),
+		versions:  make(map[uuid.UUID][]*entities.DocumentVersion),
+	}
+}
+
+// Create создает новый документ водителя
+func (r *DocumentRepository) Create(ctx context.Context, document *entities.DriverDocument) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Одна текущая версия каждого типа на водителя и одно неотклоненное продление
+	// каждого документа, как и уникальные индексы в PostgreSQL
+	for _, existing := range r.documents {
+		if existing.ID == document.ID ||
+			(document.IsCurrent && existing.IsCurrent &&
+				existing.DriverID == document.DriverID && existing.DocumentType == document.DocumentType) ||
+			(document.ReplacesID != nil && existing.ReplacesID != nil &&
+				*existing.ReplacesID == *document.ReplacesID && existing.Status != entities.VerificationStatusRejected) {
+			return fmt.Errorf("failed to create document: %w", entities.ErrDocumentExists)
+		}
+	}
+
+	r.documents[document.ID] = copyDocument(document)
+	return nil
+}
+
+// GetByID получает документ по ID
+func (r *DocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverDocument, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	document, ok := r.documents[id]
+	if !ok {
+		return nil, entities.ErrDocumentNotFound
+	}
+	return copyDocument(document), nil
+}
+
+// GetByDriverID получает все документы водителя
+func (r *DocumentRepository) GetByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverDocument, error) {
+	return r.filter(&entities.DocumentFilters{DriverID: &driverID}), nil
+}
+
+// Update обновляет документ
+func (r *DocumentRepository) Update(ctx context.Context, document *entities.DriverDocument) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.documents[document.ID]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+
+	// Результат распознавания меняется только через SetOCRResult, как и в PostgreSQL
+	document.UpdatedAt = time.Now()
+	clone := copyDocument(document)
+	clone.OCR = stored.OCR
+	r.documents[document.ID] = clone
+	return nil
+}
+
+// Delete удаляет документ
+func (r *DocumentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.documents[id]; !ok {
+		return entities.ErrDocumentNotFound
+	}
+
+	delete(r.documents, id)
+	return nil
+}
+
+// List получает список документов с фильтрами
+func (r *DocumentRepository) List(ctx context.Context, filters *entities.DocumentFilters) ([]*entities.DriverDocument, error) {
+	documents := r.filter(filters)
+	if filters == nil {
+		return documents, nil
+	}
+	return paginate(documents, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество документов с фильтрами
+func (r *DocumentRepository) Count(ctx context.Context, filters *entities.DocumentFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// UpdateStatus обновляет статус документа
+func (r *DocumentRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.VerificationStatus, verifierID, reason *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	document, ok := r.documents[id]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+
+	now := time.Now()
+	document.Status = status
+	document.VerifiedBy = verifierID
+	document.VerifiedAt = &now
+	document.RejectionReason = reason
+	document.UpdatedAt = now
+	return nil
+}
+
+// GetExpiring получает верифицированные документы, истекающие в ближайшие days дней
+func (r *DocumentRepository) GetExpiring(ctx context.Context, days int) ([]*entities.DriverDocument, error) {
+	now := time.Now()
+	deadline := now.AddDate(0, 0, days)
+
+	documents := r.filter(&entities.DocumentFilters{
+		Status: []entities.VerificationStatus{entities.VerificationStatusVerified},
+	})
+
+	result := make([]*entities.DriverDocument, 0, len(documents))
+	for _, document := range documents {
+		if document.ExpiryDate.After(now) && !document.ExpiryDate.After(deadline) {
+			result = append(result, document)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ExpiryDate.Before(result[j].ExpiryDate)
+	})
+	return result, nil
+}
+
+// GetExpired получает истекшие верифицированные и ожидающие проверки документы
+func (r *DocumentRepository) GetExpired(ctx context.Context) ([]*entities.DriverDocument, error) {
+	expired := true
+	documents := r.filter(&entities.DocumentFilters{
+		Status: []entities.VerificationStatus{
+			entities.VerificationStatusVerified,
+			entities.VerificationStatusPending,
+			entities.VerificationStatusReverificationRequired,
+		},
+		Expired: &expired,
+	})
+
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].ExpiryDate.After(documents[j].ExpiryDate)
+	})
+	return documents, nil
+}
+
+// MarkExpired помечает документы как истекшие
+func (r *DocumentRepository) MarkExpired(ctx context.Context, documentIDs []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, id := range documentIDs {
+		if document, ok := r.documents[id]; ok {
+			document.Status = entities.VerificationStatusExpired
+			document.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+// ClaimPending захватывает до limit документов, ожидающих проверки, в порядке очереди
+func (r *DocumentRepository) ClaimPending(ctx context.Context, verifierID string, limit int, ttl time.Duration) ([]*entities.DriverDocument, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make([]*entities.DriverDocument, 0)
+	for _, document := range r.documents {
+		if document.Status == entities.VerificationStatusPending {
+			pending = append(pending, document)
+		}
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return entities.QueueBefore(pending[i], pending[j])
+	})
+	pending = paginate(pending, limit, 0)
+
+	now := time.Now()
+	claimed := make([]*entities.DriverDocument, len(pending))
+	for i, document := range pending {
+		document.Claim(verifierID, now, ttl)
+		claimed[i] = copyDocument(document)
+	}
+	return claimed, nil
+}
+
+// ResolveClaim сохраняет решение по документу, если верификатор удерживает действующий захват
+func (r *DocumentRepository) ResolveClaim(ctx context.Context, id uuid.UUID, verifierID string, status entities.VerificationStatus, reason *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	document, ok := r.documents[id]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+
+	now := time.Now()
+	if !document.IsClaimedBy(verifierID, now) {
+		return entities.ErrDocumentClaimNotHeld
+	}
+
+	document.Status = status
+	document.VerifiedBy = &verifierID
+	document.VerifiedAt = &now
+	document.RejectionReason = reason
+	document.ClaimExpiresAt = nil
+	document.UpdatedAt = now
+	return nil
+}
+
+// ReleaseStaleClaims возвращает в очередь документы с истекшим захватом
+func (r *DocumentRepository) ReleaseStaleClaims(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	released := 0
+	for _, document := range r.documents {
+		if document.IsClaimStale(now) {
+			document.ReleaseClaim(now)
+			released++
+		}
+	}
+	return released, nil
+}
+
+// GetVerifierStats получает число решений и среднее время обработки по верификаторам за период
+func (r *DocumentRepository) GetVerifierStats(ctx context.Context, from, to time.Time) ([]*entities.VerifierStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	byVerifier := make(map[string]*entities.VerifierStats)
+	handling := make(map[string]struct {
+		seconds float64
+		count   int
+	})
+
+	for _, document := range r.documents {
+		if document.VerifiedBy == nil || document.VerifiedAt == nil ||
+			document.VerifiedAt.Before(from) || !document.VerifiedAt.Before(to) {
+			continue
+		}
+
+		verifierID := *document.VerifiedBy
+		stats, ok := byVerifier[verifierID]
+		if !ok {
+			stats = &entities.VerifierStats{VerifierID: verifierID}
+			byVerifier[verifierID] = stats
+		}
+
+		switch document.Status {
+		case entities.VerificationStatusVerified, entities.VerificationStatusExpired,
+			entities.VerificationStatusSuperseded, entities.VerificationStatusReverificationRequired:
+			stats.Verified++
+		case entities.VerificationStatusRejected:
+			stats.Rejected++
+		default:
+			continue
+		}
+		stats.Total++
+
+		if document.ClaimedAt != nil {
+			h := handling[verifierID]
+			h.seconds += document.VerifiedAt.Sub(*document.ClaimedAt).Seconds()
+			h.count++
+			handling[verifierID] = h
+		}
+	}
+
+	result := make([]*entities.VerifierStats, 0, len(byVerifier))
+	for verifierID, stats := range byVerifier {
+		if stats.Total == 0 {
+			continue
+		}
+		if h := handling[verifierID]; h.count > 0 {
+			stats.AverageHandlingSeconds = h.seconds / float64(h.count)
+		}
+		result = append(result, stats)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].VerifierID < result[j].VerifierID
+	})
+	return result, nil
+}
+
+// GetPendingRenewal получает новую версию документа, ожидающую проверки
+func (r *DocumentRepository) GetPendingRenewal(ctx context.Context, documentID uuid.UUID) (*entities.DriverDocument, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, document := range r.documents {
+		if document.ReplacesID != nil && *document.ReplacesID == documentID &&
+			(document.Status == entities.VerificationStatusPending || document.Status == entities.VerificationStatusProcessing) {
+			return copyDocument(document), nil
+		}
+	}
+	return nil, entities.ErrDocumentNotFound
+}
+
+// Supersede отмечает документ замененным подтвержденной новой версией и делает ее текущей
+func (r *DocumentRepository) Supersede(ctx context.Context, id, currentID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	document, ok := r.documents[id]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+
+	switch document.Status {
+	case entities.VerificationStatusVerified, entities.VerificationStatusExpired,
+		entities.VerificationStatusSuperseded, entities.VerificationStatusReverificationRequired:
+	default:
+		return entities.ErrDocumentNotVerified
+	}
+
+	current, ok := r.documents[currentID]
+	if !ok || current.ReplacesID == nil || *current.ReplacesID != id {
+		return entities.ErrDocumentNotFound
+	}
+
+	document.Supersede()
+	current.IsCurrent = true
+	current.UpdatedAt = time.Now()
+	return nil
+}
+
+// GetCurrent получает текущую версию документа водителя определенного типа
+func (r *DocumentRepository) GetCurrent(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) (*entities.DriverDocument, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, document := range r.documents {
+		if document.DriverID == driverID && document.DocumentType == docType && document.IsCurrent {
+			return copyDocument(document), nil
+		}
+	}
+	return nil, entities.ErrDocumentNotFound
+}
+
+// GetHistory получает прежние версии документа водителя определенного типа от новых к старым
+func (r *DocumentRepository) GetHistory(ctx context.Context, driverID uuid.UUID, docType entities.DocumentType) ([]*entities.DriverDocument, error) {
+	documents := r.filter(&entities.DocumentFilters{
+		DriverID:     &driverID,
+		DocumentType: []entities.DocumentType{docType},
+	})
+
+	history := make([]*entities.DriverDocument, 0, len(documents))
+	for _, document := range documents {
+		if !document.IsCurrent {
+			history = append(history, document)
+		}
+	}
+	return history, nil
+}
+
+// RequireReverification переводит подтвержденные документы в перепроверку
+func (r *DocumentRepository) RequireReverification(ctx context.Context, documentIDs []uuid.UUID, verifyBy time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	updated := 0
+	for _, id := range documentIDs {
+		if document, ok := r.documents[id]; ok && document.Status == entities.VerificationStatusVerified {
+			deadline := verifyBy
+			document.Status = entities.VerificationStatusReverificationRequired
+			document.VerifyBy = &deadline
+			document.UpdatedAt = now
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// ClearReverification возвращает документы, ожидающие перепроверки, в подтвержденные
+func (r *DocumentRepository) ClearReverification(ctx context.Context, documentIDs []uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	updated := 0
+	for _, id := range documentIDs {
+		if document, ok := r.documents[id]; ok && document.IsReverificationRequired() {
+			document.Status = entities.VerificationStatusVerified
+			document.VerifyBy = nil
+			document.UpdatedAt = now
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// ErasePersonalData обезличивает документы водителя
+func (r *DocumentRepository) ErasePersonalData(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	erased := 0
+	for _, document := range r.documents {
+		if document.DriverID == driverID {
+			document.DocumentNumber = ""
+			document.FileURL = ""
+			document.Metadata = make(entities.Metadata)
+			document.OCR = nil
+			document.UpdatedAt = now
+			erased++
+
+			for _, version := range r.versions[document.ID] {
+				version.DocumentNumber = ""
+				version.FileURL = ""
+			}
+		}
+	}
+	return erased, nil
+}
+
+// SetOCRResult сохраняет результат распознавания документа
+func (r *DocumentRepository) SetOCRResult(ctx context.Context, id uuid.UUID, result *entities.DocumentOCRResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	document, ok := r.documents[id]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+	document.OCR = result
+	return nil
+}
+
+// Resubmit сохраняет прежнюю версию отклоненного документа и новую версию
+func (r *DocumentRepository) Resubmit(ctx context.Context, document *entities.DriverDocument, previous *entities.DocumentVersion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.documents[document.ID]
+	if !ok {
+		return entities.ErrDocumentNotFound
+	}
+	if !stored.CanResubmit() {
+		return entities.ErrDocumentNotResubmittable
+	}
+
+	version := *previous
+	r.versions[document.ID] = append(r.versions[document.ID], &version)
+	r.documents[document.ID] = copyDocument(document)
+	return nil
+}
+
+// GetVersions получает прежние версии документа, новые первыми
+func (r *DocumentRepository) GetVersions(ctx context.Context, documentID uuid.UUID) ([]*entities.DocumentVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.versions[documentID]
+	versions := make([]*entities.DocumentVersion, 0, len(stored))
+	for i := len(stored) - 1; i >= 0; i-- {
+		version := *stored[i]
+		versions = append(versions, &version)
+	}
+	return versions, nil
+}
+
+// GetResubmissionDue получает отклоненные документы, ожидающие повторной загрузки
+func (r *DocumentRepository) GetResubmissionDue(ctx context.Context, rejectedBefore time.Time) ([]*entities.DriverDocument, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverDocument, 0)
+	for _, document := range r.documents {
+		if document.CanResubmit() && document.ResubmissionReminderSentAt == nil &&
+			document.VerifiedAt != nil && !document.VerifiedAt.After(rejectedBefore) {
+			result = append(result, copyDocument(document))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].VerifiedAt.Before(*result[j].VerifiedAt)
+	})
+	return result, nil
+}
+
+// filter возвращает копии документов по фильтрам, новые первыми
+func (r *DocumentRepository) filter(filters *entities.DocumentFilters) []*entities.DriverDocument {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	result := make([]*entities.DriverDocument, 0)
+	for _, document := range r.documents {
+		if matchDocument(document, filters, now) {
+			result = append(result, copyDocument(document))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return result
+}
+
+// matchDocument проверяет документ на соответствие фильтрам
+func matchDocument(document *entities.DriverDocument, filters *entities.DocumentFilters, now time.Time) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && document.DriverID != *filters.DriverID {
+		return false
+	}
+
+	if len(filters.DocumentType) > 0 {
+		found := false
+		for _, docType := range filters.DocumentType {
+			if document.DocumentType == docType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(filters.Status) > 0 {
+		found := false
+		for _, status := range filters.Status {
+			if document.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.ExpiringIn != nil {
+		deadline := now.AddDate(0, 0, *filters.ExpiringIn)
+		if !document.ExpiryDate.After(now) || document.ExpiryDate.After(deadline) {
+			return false
+		}
+	}
+
+	if filters.Expired != nil && *filters.Expired && !document.ExpiryDate.Before(now) {
+		return false
+	}
+
+	return true
+}
+
+// copyDocument возвращает независимую копию документа
+func copyDocument(document *entities.DriverDocument) *entities.DriverDocument {
+	clone := *document
+	clone.Metadata = cloneMetadata(document.Metadata)
+	return &clone
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DriverDailyStatsRepository in-memory реализация repositories.DriverDailyStatsRepository
+type DriverDailyStatsRepository struct {
+	mu       sync.RWMutex
+	days     map[int64]map[uuid.UUID]*entities.DriverDailyStats
+	coverage *entities.DailyStatsCoverage
+}
+
+var _ repositories.DriverDailyStatsRepository = (*DriverDailyStatsRepository)(nil)
+
+// NewDriverDailyStatsRepository создает новый in-memory репозиторий суточных показателей водителей
+func NewDriverDailyStatsRepository() *DriverDailyStatsRepository {
+	return &DriverDailyStatsRepository{
+		days: make(map[int64]map[uuid.UUID]*entities.DriverDailyStats),
+	}
+}
+
+// GetCoverage получает сохраненные сутки
+func (r *DriverDailyStatsRepository) GetCoverage(ctx context.Context) (*entities.DailyStatsCoverage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.coverage == nil {
+		return nil, nil
+	}
+	coverage := *r.coverage
+	return &coverage, nil
+}
+
+// ReplaceDay заменяет показатели за сутки
+func (r *DriverDailyStatsRepository) ReplaceDay(ctx context.Context, day time.Time, stats []*entities.DriverDailyStats, coverage *entities.DailyStatsCoverage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	drivers := make(map[uuid.UUID]*entities.DriverDailyStats, len(stats))
+	for _, s := range stats {
+		clone := *s
+		drivers[s.DriverID] = &clone
+	}
+	r.days[day.Unix()] = drivers
+
+	saved := *coverage
+	r.coverage = &saved
+	return nil
+}
+
+// ListByDriverID получает показатели водителя за период по возрастанию суток
+func (r *DriverDailyStatsRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverDailyStats, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.DriverDailyStats
+	for _, drivers := range r.days {
+		s, ok := drivers[driverID]
+		if !ok || s.Day.Before(from) || !s.Day.Before(to) {
+			continue
+		}
+		clone := *s
+		result = append(result, &clone)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day.Before(result[j].Day) })
+	return result, nil
+}
+
+// GetActivity суммирует показатели местоположений водителя за период
+func (r *DriverDailyStatsRepository) GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*entities.LocationActivity, error) {
+	stats, err := r.ListByDriverID(ctx, driverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := &entities.LocationActivity{}
+	for _, s := range stats {
+		activity.DistanceKm += s.DistanceKm
+		activity.OnlineSeconds += s.OnlineSeconds
+	}
+	return activity, nil
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DriverInviteRepository in-memory реализация repositories.DriverInviteRepository
+type DriverInviteRepository struct {
+	mu      sync.RWMutex
+	invites map[uuid.UUID]*entities.DriverInvite
+}
+
+var _ repositories.DriverInviteRepository = (*DriverInviteRepository)(nil)
+
+// NewDriverInviteRepository создает новый in-memory репозиторий приглашений водителей
+func NewDriverInviteRepository() *DriverInviteRepository {
+	return &DriverInviteRepository{
+		invites: make(map[uuid.UUID]*entities.DriverInvite),
+	}
+}
+
+// Create сохраняет приглашение
+func (r *DriverInviteRepository) Create(ctx context.Context, invite *entities.DriverInvite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.invites[invite.ID]; !exists {
+		clone := *invite
+		r.invites[invite.ID] = &clone
+	}
+	return nil
+}
+
+// GetByID получает приглашение по ID
+func (r *DriverInviteRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverInvite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	invite, ok := r.invites[id]
+	if !ok {
+		return nil, entities.ErrDriverInviteNotFound
+	}
+	clone := *invite
+	return &clone, nil
+}
+
+// List получает приглашения, новые первыми
+func (r *DriverInviteRepository) List(ctx context.Context, limit, offset int) ([]*entities.DriverInvite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverInvite, 0, len(r.invites))
+	for _, invite := range r.invites {
+		clone := *invite
+		result = append(result, &clone)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return paginate(result, limit, offset), nil
+}
+
+// Accept сохраняет регистрацию по приглашению, если оно еще не использовано и не отозвано
+func (r *DriverInviteRepository) Accept(ctx context.Context, invite *entities.DriverInvite) error {
+	return r.transition(invite)
+}
+
+// Revoke сохраняет отзыв приглашения, если оно еще не использовано и не отозвано
+func (r *DriverInviteRepository) Revoke(ctx context.Context, invite *entities.DriverInvite) error {
+	return r.transition(invite)
+}
+
+// transition заменяет приглашение в pending новым состоянием
+func (r *DriverInviteRepository) transition(invite *entities.DriverInvite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.invites[invite.ID]
+	if !ok {
+		return entities.ErrDriverInviteNotFound
+	}
+	switch stored.Status {
+	case entities.DriverInviteStatusAccepted:
+		return entities.ErrDriverInviteUsed
+	case entities.DriverInviteStatusRevoked:
+		return entities.ErrDriverInviteRevoked
+	}
+
+	clone := *invite
+	r.invites[invite.ID] = &clone
+	return nil
+}
@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DriverNoteRepository in-memory реализация repositories.DriverNoteRepository
+type DriverNoteRepository struct {
+	mu    sync.RWMutex
+	notes []*entities.DriverNote
+}
+
+var _ repositories.DriverNoteRepository = (*DriverNoteRepository)(nil)
+
+// NewDriverNoteRepository создает новый in-memory репозиторий заметок о водителях
+func NewDriverNoteRepository() *DriverNoteRepository {
+	return &DriverNoteRepository{}
+}
+
+// Create сохраняет заметку
+func (r *DriverNoteRepository) Create(ctx context.Context, note *entities.DriverNote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *note
+	r.notes = append(r.notes, &clone)
+	return nil
+}
+
+// ListByDriver получает заметки водителя, новые первыми
+func (r *DriverNoteRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.DriverNote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []*entities.DriverNote{}
+	for _, note := range r.notes {
+		if note.DriverID == driverID {
+			clone := *note
+			result = append(result, &clone)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+	return paginate(result, limit, offset), nil
+}
@@ -0,0 +1,485 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// DriverRepository in-memory реализация repositories.DriverRepository
+type DriverRepository struct {
+	mu      sync.RWMutex
+	drivers map[uuid.UUID]*entities.Driver
+	// history смены статусов водителей для StatusHistoryRepository
+	history []*entities.StatusTransition
+}
+
+var _ repositories.DriverRepository = (*DriverRepository)(nil)
+
+// NewDriverRepository создает новый in-memory репозиторий водителей
+func NewDriverRepository() *DriverRepository {
+	return &DriverRepository{
+		drivers: make(map[uuid.UUID]*entities.Driver),
+	}
+}
+
+// Create создает нового водителя
+func (r *DriverRepository) Create(ctx context.Context, driver *entities.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Уникальные ограничения таблицы drivers распространяются только на неудаленные записи;
+	// незаполненные email и номер лицензии не участвуют в проверке
+	for _, existing := range r.drivers {
+		if existing.ID == driver.ID {
+			return fmt.Errorf("failed to create driver: %w", entities.ErrDriverExists)
+		}
+		if existing.DeletedAt == nil && matchUnique(existing, driver.Phone, driver.Email, driver.LicenseNumber) {
+			return fmt.Errorf("failed to create driver: %w", entities.ErrDriverExists)
+		}
+	}
+
+	driver.RefreshShardKey()
+	driver.StatusVersion = 1
+	r.drivers[driver.ID] = copyDriver(driver)
+	r.recordStatus(ctx, driver.ID, nil, driver.Status, driver.CreatedAt)
+	return nil
+}
+
+// GetByID получает водителя по ID
+func (r *DriverRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Driver, error) {
+	return r.findOne(func(d *entities.Driver) bool { return d.ID == id })
+}
+
+// GetByPhone получает водителя по номеру телефона
+func (r *DriverRepository) GetByPhone(ctx context.Context, phone string) (*entities.Driver, error) {
+	return r.findOne(func(d *entities.Driver) bool { return d.Phone == phone })
+}
+
+// GetByEmail получает водителя по email
+func (r *DriverRepository) GetByEmail(ctx context.Context, email string) (*entities.Driver, error) {
+	return r.findOne(func(d *entities.Driver) bool { return d.Email == email })
+}
+
+// GetByLicenseNumber получает водителя по номеру водительского удостоверения
+func (r *DriverRepository) GetByLicenseNumber(ctx context.Context, licenseNumber string) (*entities.Driver, error) {
+	return r.findOne(func(d *entities.Driver) bool { return d.LicenseNumber == licenseNumber })
+}
+
+// Update обновляет данные водителя
+func (r *DriverRepository) Update(ctx context.Context, driver *entities.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.drivers[driver.ID]
+	if !ok || existing.DeletedAt != nil {
+		return entities.ErrDriverNotFound
+	}
+
+	driver.UpdatedAt = time.Now()
+	driver.RefreshShardKey()
+	updated := copyDriver(driver)
+	updated.DeletedAt = existing.DeletedAt
+	// Город по местоположениям назначает только CityRepository.AssignDriver
+	updated.ResolvedCity = existing.ResolvedCity
+	// Метки меняются только через UpdateTags
+	updated.Tags = existing.Tags
+	// Доли предложений меняются только через UpdateDispatchRates
+	updated.AcceptanceRate = existing.AcceptanceRate
+	updated.CancellationRate = existing.CancellationRate
+	// Версию статуса увеличивает только смена статуса
+	updated.StatusVersion = existing.StatusVersion
+	// Подтверждение телефона сохраняется, пока номер не изменился
+	updated.PhoneVerifiedAt = nil
+	if existing.Phone == driver.Phone && existing.PhoneVerifiedAt != nil {
+		verifiedAt := *existing.PhoneVerifiedAt
+		updated.PhoneVerifiedAt = &verifiedAt
+	}
+	r.drivers[driver.ID] = updated
+	if existing.Status != driver.Status {
+		from := existing.Status
+		r.recordStatus(ctx, driver.ID, &from, driver.Status, driver.UpdatedAt)
+	}
+	return nil
+}
+
+// Delete удаляет водителя (жесткое удаление)
+func (r *DriverRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drivers[id]; !ok {
+		return entities.ErrDriverNotFound
+	}
+
+	delete(r.drivers, id)
+	return nil
+}
+
+// SoftDelete мягкое удаление водителя
+func (r *DriverRepository) SoftDelete(ctx context.Context, id uuid.UUID) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.DeletedAt = &now
+	})
+}
+
+// List получает список водителей с фильтрами
+func (r *DriverRepository) List(ctx context.Context, filters *entities.DriverFilters) ([]*entities.Driver, error) {
+	drivers := r.filter(filters)
+	if filters == nil {
+		return drivers, nil
+	}
+
+	sortDrivers(drivers, filters.SortBy, filters.SortDirection)
+	return paginate(drivers, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество водителей с фильтрами
+func (r *DriverRepository) Count(ctx context.Context, filters *entities.DriverFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// Exists проверяет существование водителя по телефону или номеру лицензии
+func (r *DriverRepository) Exists(ctx context.Context, phone, email, licenseNumber string) (bool, error) {
+	_, err := r.findOne(func(d *entities.Driver) bool {
+		return matchUnique(d, phone, email, licenseNumber)
+	})
+	if err == entities.ErrDriverNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetDeletedByPhone получает последнего удаленного водителя с номером телефона
+func (r *DriverRepository) GetDeletedByPhone(ctx context.Context, phone string) (*entities.Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var latest *entities.Driver
+	for _, driver := range r.drivers {
+		if driver.DeletedAt == nil || driver.Phone != phone {
+			continue
+		}
+		if latest == nil || driver.DeletedAt.After(*latest.DeletedAt) {
+			latest = driver
+		}
+	}
+	if latest == nil {
+		return nil, entities.ErrDriverNotFound
+	}
+	return copyDriver(latest), nil
+}
+
+// UpdateStatus обновляет статус водителя и записывает смену в историю статусов
+func (r *DriverRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		if d.Status != status {
+			from := d.Status
+			r.recordStatus(ctx, id, &from, status, now)
+		}
+		d.Status = status
+	})
+}
+
+// UpdateStatuses применяет смены статуса под одной блокировкой с проверкой текущего статуса
+func (r *DriverRepository) UpdateStatuses(ctx context.Context, updates []entities.StatusUpdate, allOrNothing bool) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	applicable := make([]entities.StatusUpdate, 0, len(updates))
+	for _, update := range updates {
+		driver, ok := r.drivers[update.DriverID]
+		if !ok || driver.DeletedAt != nil || driver.Status != update.From {
+			if allOrNothing {
+				return nil, entities.ErrStatusConflict
+			}
+			continue
+		}
+		applicable = append(applicable, update)
+	}
+
+	now := time.Now()
+	updated := make([]uuid.UUID, 0, len(applicable))
+	for _, update := range applicable {
+		driver := r.drivers[update.DriverID]
+		if update.From != update.To {
+			from := update.From
+			r.recordStatus(ctx, update.DriverID, &from, update.To, now)
+		}
+		driver.Status = update.To
+		driver.UpdatedAt = now
+		updated = append(updated, update.DriverID)
+	}
+	return updated, nil
+}
+
+// UpdateRating обновляет рейтинг водителя
+func (r *DriverRepository) UpdateRating(ctx context.Context, id uuid.UUID, rating float64) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.CurrentRating = rating
+	})
+}
+
+// LockRating возвращает текущий рейтинг водителя. Транзакций в памяти нет: пересчеты рейтинга
+// упорядочивает вызывающий сервис
+func (r *DriverRepository) LockRating(ctx context.Context, id uuid.UUID) (float64, error) {
+	driver, err := r.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return driver.CurrentRating, nil
+}
+
+// IncrementTripCount увеличивает счетчик поездок
+func (r *DriverRepository) IncrementTripCount(ctx context.Context, id uuid.UUID) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.TotalTrips++
+	})
+}
+
+// UpdatePaymentHold устанавливает или снимает блокировку водителя со стороны биллинга
+func (r *DriverRepository) UpdatePaymentHold(ctx context.Context, id uuid.UUID, hold bool, reason *string) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.PaymentHold = hold
+		d.PaymentHoldReason = nil
+		d.PaymentHoldAt = nil
+		if !hold {
+			return
+		}
+		holdAt := now
+		d.PaymentHoldAt = &holdAt
+		if reason != nil {
+			holdReason := *reason
+			d.PaymentHoldReason = &holdReason
+		}
+	})
+}
+
+// UpdateTags заменяет метки водителя
+func (r *DriverRepository) UpdateTags(ctx context.Context, id uuid.UUID, tags entities.DriverTagList) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.Tags = append(entities.DriverTagList{}, tags...)
+	})
+}
+
+// SetPhoneVerified отмечает телефон водителя подтвержденным, если номер не изменился
+func (r *DriverRepository) SetPhoneVerified(ctx context.Context, id uuid.UUID, phone string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	driver, ok := r.drivers[id]
+	if !ok || driver.DeletedAt != nil || driver.Phone != phone {
+		return entities.ErrDriverNotFound
+	}
+
+	verifiedAt := at
+	driver.PhoneVerifiedAt = &verifiedAt
+	driver.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateDispatchRates сохраняет доли принятых и отмененных предложений водителя
+func (r *DriverRepository) UpdateDispatchRates(ctx context.Context, id uuid.UUID, acceptanceRate, cancellationRate *float64) error {
+	return r.mutate(id, func(d *entities.Driver, now time.Time) {
+		d.AcceptanceRate = copyFloat(acceptanceRate)
+		d.CancellationRate = copyFloat(cancellationRate)
+	})
+}
+
+// GetActiveDrivers получает список активных водителей
+func (r *DriverRepository) GetActiveDrivers(ctx context.Context) ([]*entities.Driver, error) {
+	drivers := r.filter(&entities.DriverFilters{
+		Status: []entities.Status{entities.StatusAvailable, entities.StatusOnShift, entities.StatusBusy},
+	})
+	sortDrivers(drivers, "current_rating", "desc")
+	return drivers, nil
+}
+
+// matchUnique проверяет совпадение водителя по уникальным полям; пустые значения не сравниваются
+func matchUnique(d *entities.Driver, phone, email, licenseNumber string) bool {
+	return (phone != "" && d.Phone == phone) ||
+		(email != "" && d.Email == email) ||
+		(licenseNumber != "" && d.LicenseNumber == licenseNumber)
+}
+
+// findOne ищет первого неудаленного водителя, удовлетворяющего условию
+func (r *DriverRepository) findOne(match func(*entities.Driver) bool) (*entities.Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, driver := range r.drivers {
+		if driver.DeletedAt == nil && match(driver) {
+			return copyDriver(driver), nil
+		}
+	}
+	return nil, entities.ErrDriverNotFound
+}
+
+// mutate применяет изменение к неудаленному водителю и обновляет updated_at
+func (r *DriverRepository) mutate(id uuid.UUID, apply func(*entities.Driver, time.Time)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	driver, ok := r.drivers[id]
+	if !ok || driver.DeletedAt != nil {
+		return entities.ErrDriverNotFound
+	}
+
+	now := time.Now()
+	apply(driver, now)
+	driver.UpdatedAt = now
+	return nil
+}
+
+// recordStatus добавляет смену статуса в историю и, как триггер в базе, увеличивает версию
+// статуса водителя; вызывается под r.mu
+func (r *DriverRepository) recordStatus(ctx context.Context, id uuid.UUID, from *entities.Status, to entities.Status, at time.Time) {
+	r.history = append(r.history, entities.NewStatusTransition(ctx, id, from, to, at))
+	if driver, ok := r.drivers[id]; ok && from != nil {
+		driver.StatusVersion++
+	}
+}
+
+// filter возвращает копии неудаленных водителей, удовлетворяющих фильтрам
+func (r *DriverRepository) filter(filters *entities.DriverFilters) []*entities.Driver {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.Driver, 0, len(r.drivers))
+	for _, driver := range r.drivers {
+		if driver.DeletedAt != nil || !matchDriver(driver, filters) {
+			continue
+		}
+		result = append(result, copyDriver(driver))
+	}
+
+	// Порядок по умолчанию совпадает с PostgreSQL реализацией
+	sortDrivers(result, "", "")
+	return result
+}
+
+// matchDriver проверяет водителя на соответствие фильтрам
+func matchDriver(driver *entities.Driver, filters *entities.DriverFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if len(filters.Status) > 0 {
+		found := false
+		for _, status := range filters.Status {
+			if driver.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.MinRating != nil && driver.CurrentRating < *filters.MinRating {
+		return false
+	}
+	if filters.MaxRating != nil && driver.CurrentRating > *filters.MaxRating {
+		return false
+	}
+	if filters.City != nil && driver.OperatingCity() != entities.NormalizeCity(*filters.City) {
+		return false
+	}
+	if filters.FleetIDs != nil && !containsFleet(filters.FleetIDs, driver.FleetID) {
+		return false
+	}
+	if filters.IDs != nil && !containsID(filters.IDs, driver.ID) {
+		return false
+	}
+	if filters.PaymentHold != nil && driver.PaymentHold != *filters.PaymentHold {
+		return false
+	}
+	if !driver.HasTags(filters.Tags) {
+		return false
+	}
+	if filters.CreatedAfter != nil && driver.CreatedAt.Before(*filters.CreatedAfter) {
+		return false
+	}
+	if filters.CreatedBefore != nil && driver.CreatedAt.After(*filters.CreatedBefore) {
+		return false
+	}
+
+	return true
+}
+
+// containsFleet проверяет, входит ли автопарк водителя в список
+func containsFleet(fleetIDs []uuid.UUID, fleetID *uuid.UUID) bool {
+	if fleetID == nil {
+		return false
+	}
+	for _, id := range fleetIDs {
+		if id == *fleetID {
+			return true
+		}
+	}
+	return false
+}
+
+// containsID проверяет, есть ли id в списке
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortDrivers сортирует водителей; без sortBy используется created_at DESC
+func sortDrivers(drivers []*entities.Driver, sortBy, direction string) {
+	if sortBy == "" {
+		sortBy, direction = "created_at", "desc"
+	} else if direction != "desc" {
+		direction = "asc"
+	}
+
+	var compare func(a, b *entities.Driver) int
+	switch sortBy {
+	case "current_rating":
+		compare = func(a, b *entities.Driver) int { return cmp.Compare(a.CurrentRating, b.CurrentRating) }
+	case "total_trips":
+		compare = func(a, b *entities.Driver) int { return cmp.Compare(a.TotalTrips, b.TotalTrips) }
+	case "first_name":
+		compare = func(a, b *entities.Driver) int { return cmp.Compare(a.FirstName, b.FirstName) }
+	case "last_name":
+		compare = func(a, b *entities.Driver) int { return cmp.Compare(a.LastName, b.LastName) }
+	case "updated_at":
+		compare = func(a, b *entities.Driver) int { return a.UpdatedAt.Compare(b.UpdatedAt) }
+	default:
+		compare = func(a, b *entities.Driver) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	}
+
+	sortItems(drivers, compare, direction)
+}
+
+// copyDriver возвращает независимую копию водителя
+func copyDriver(driver *entities.Driver) *entities.Driver {
+	clone := *driver
+	clone.Metadata = cloneMetadata(driver.Metadata)
+	if driver.Tags != nil {
+		clone.Tags = append(entities.DriverTagList{}, driver.Tags...)
+	}
+	return &clone
+}
+
+// copyFloat копирует необязательное значение
+func copyFloat(value *float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// EarningRepository in-memory реализация repositories.EarningRepository
+type EarningRepository struct {
+	mu       sync.RWMutex
+	earnings map[uuid.UUID]*entities.DriverEarning
+}
+
+var _ repositories.EarningRepository = (*EarningRepository)(nil)
+
+// NewEarningRepository создает новый in-memory репозиторий начислений
+func NewEarningRepository() *EarningRepository {
+	return &EarningRepository{
+		earnings: make(map[uuid.UUID]*entities.DriverEarning),
+	}
+}
+
+// Create сохраняет начисление; повторная поездка по тому же заказу — entities.ErrEarningExists
+func (r *EarningRepository) Create(ctx context.Context, earning *entities.DriverEarning) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if earning.Type == entities.EarningTypeTrip && earning.OrderID != nil {
+		for _, existing := range r.earnings {
+			if existing.Type == entities.EarningTypeTrip && existing.OrderID != nil && *existing.OrderID == *earning.OrderID {
+				return entities.ErrEarningExists
+			}
+		}
+	}
+
+	r.earnings[earning.ID] = copyEarning(earning)
+	return nil
+}
+
+// List получает список начислений с фильтрами, новые первыми
+func (r *EarningRepository) List(ctx context.Context, filters *entities.EarningFilters) ([]*entities.DriverEarning, error) {
+	earnings := r.filter(filters)
+	if filters == nil {
+		return earnings, nil
+	}
+	return paginate(earnings, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество начислений с фильтрами
+func (r *EarningRepository) Count(ctx context.Context, filters *entities.EarningFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// Summarize возвращает суммы начислений по периодам и валютам (без учета Limit и Offset)
+func (r *EarningRepository) Summarize(ctx context.Context, filters *entities.EarningFilters, groupBy entities.EarningsGrouping, loc *time.Location) ([]*entities.EarningsPeriodTotal, error) {
+	type key struct {
+		start    time.Time
+		currency string
+	}
+
+	totals := make(map[key]*entities.EarningsPeriodTotal)
+	for _, earning := range r.filter(filters) {
+		k := key{groupBy.PeriodStart(earning.EarnedAt, loc), earning.Currency}
+		total, ok := totals[k]
+		if !ok {
+			total = &entities.EarningsPeriodTotal{PeriodStart: k.start}
+			total.Currency = earning.Currency
+			totals[k] = total
+		}
+		total.AddEarning(earning)
+	}
+
+	result := make([]*entities.EarningsPeriodTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].PeriodStart.Equal(result[j].PeriodStart) {
+			return result[i].PeriodStart.Before(result[j].PeriodStart)
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
+
+// filter возвращает копии начислений по фильтрам, новые первыми
+func (r *EarningRepository) filter(filters *entities.EarningFilters) []*entities.DriverEarning {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverEarning, 0)
+	for _, earning := range r.earnings {
+		if matchEarning(earning, filters) {
+			result = append(result, copyEarning(earning))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].EarnedAt.Equal(result[j].EarnedAt) {
+			return result[i].EarnedAt.After(result[j].EarnedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return result
+}
+
+// matchEarning проверяет начисление на соответствие фильтрам
+func matchEarning(earning *entities.DriverEarning, filters *entities.EarningFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && earning.DriverID != *filters.DriverID {
+		return false
+	}
+	if filters.ShiftID != nil && (earning.ShiftID == nil || *earning.ShiftID != *filters.ShiftID) {
+		return false
+	}
+	if filters.Type != nil && earning.Type != *filters.Type {
+		return false
+	}
+	if filters.From != nil && earning.EarnedAt.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && !earning.EarnedAt.Before(*filters.To) {
+		return false
+	}
+
+	return true
+}
+
+// copyEarning возвращает независимую копию начисления
+func copyEarning(earning *entities.DriverEarning) *entities.DriverEarning {
+	clone := *earning
+	return &clone
+}
@@ -0,0 +1,193 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ExpenseRepository in-memory реализация repositories.ExpenseRepository
+type ExpenseRepository struct {
+	mu       sync.RWMutex
+	expenses map[uuid.UUID]*entities.ShiftExpense
+}
+
+var _ repositories.ExpenseRepository = (*ExpenseRepository)(nil)
+
+// NewExpenseRepository создает новый in-memory репозиторий расходов
+func NewExpenseRepository() *ExpenseRepository {
+	return &ExpenseRepository{
+		expenses: make(map[uuid.UUID]*entities.ShiftExpense),
+	}
+}
+
+// Create создает новый расход
+func (r *ExpenseRepository) Create(ctx context.Context, expense *entities.ShiftExpense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expenses[expense.ID] = copyExpense(expense)
+	return nil
+}
+
+// GetByID получает расход по ID
+func (r *ExpenseRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ShiftExpense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	expense, ok := r.expenses[id]
+	if !ok {
+		return nil, entities.ErrExpenseNotFound
+	}
+	return copyExpense(expense), nil
+}
+
+// Update обновляет расход
+func (r *ExpenseRepository) Update(ctx context.Context, expense *entities.ShiftExpense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.expenses[expense.ID]; !ok {
+		return entities.ErrExpenseNotFound
+	}
+
+	expense.UpdatedAt = time.Now()
+	r.expenses[expense.ID] = copyExpense(expense)
+	return nil
+}
+
+// List получает список расходов с фильтрами, новые первыми
+func (r *ExpenseRepository) List(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ShiftExpense, error) {
+	expenses := r.filter(filters)
+	if filters == nil {
+		return expenses, nil
+	}
+	return paginate(expenses, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество расходов с фильтрами
+func (r *ExpenseRepository) Count(ctx context.Context, filters *entities.ExpenseFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// Summarize возвращает суммы расходов по категориям и валютам (без учета Limit и Offset)
+func (r *ExpenseRepository) Summarize(ctx context.Context, filters *entities.ExpenseFilters) ([]*entities.ExpenseTotal, error) {
+	type key struct {
+		category entities.ExpenseCategory
+		currency string
+	}
+
+	totals := make(map[key]*entities.ExpenseTotal)
+	for _, expense := range r.filter(filters) {
+		k := key{expense.Category, expense.Currency}
+		total, ok := totals[k]
+		if !ok {
+			total = &entities.ExpenseTotal{Category: expense.Category, Currency: expense.Currency}
+			totals[k] = total
+		}
+		total.Amount += expense.Amount
+		total.Count++
+	}
+
+	result := make([]*entities.ExpenseTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Category != result[j].Category {
+			return result[i].Category < result[j].Category
+		}
+		return result[i].Currency < result[j].Currency
+	})
+	return result, nil
+}
+
+// MarkExported отмечает расходы выгруженными в учетную систему; уже выгруженные не меняются
+func (r *ExpenseRepository) MarkExported(ctx context.Context, ids []uuid.UUID, exportedAt time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	marked := 0
+	for _, id := range ids {
+		expense, ok := r.expenses[id]
+		if !ok || expense.ExportedAt != nil {
+			continue
+		}
+		at := exportedAt
+		expense.ExportedAt = &at
+		marked++
+	}
+	return marked, nil
+}
+
+// filter возвращает копии расходов по фильтрам
+func (r *ExpenseRepository) filter(filters *entities.ExpenseFilters) []*entities.ShiftExpense {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.ShiftExpense, 0)
+	for _, expense := range r.expenses {
+		if matchExpense(expense, filters) {
+			result = append(result, copyExpense(expense))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].IncurredAt.Equal(result[j].IncurredAt) {
+			return result[i].IncurredAt.After(result[j].IncurredAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return result
+}
+
+// matchExpense проверяет расход на соответствие фильтрам
+func matchExpense(expense *entities.ShiftExpense, filters *entities.ExpenseFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && expense.DriverID != *filters.DriverID {
+		return false
+	}
+	if filters.ShiftID != nil && expense.ShiftID != *filters.ShiftID {
+		return false
+	}
+
+	if len(filters.Category) > 0 {
+		found := false
+		for _, category := range filters.Category {
+			if expense.Category == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.From != nil && expense.IncurredAt.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && !expense.IncurredAt.Before(*filters.To) {
+		return false
+	}
+	if filters.Exported != nil && (expense.ExportedAt != nil) != *filters.Exported {
+		return false
+	}
+
+	return true
+}
+
+// copyExpense возвращает независимую копию расхода
+func copyExpense(expense *entities.ShiftExpense) *entities.ShiftExpense {
+	clone := *expense
+	return &clone
+}
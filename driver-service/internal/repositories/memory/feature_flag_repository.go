@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// FeatureFlagRepository in-memory реализация repositories.FeatureFlagRepository
+type FeatureFlagRepository struct {
+	mu    sync.RWMutex
+	flags map[uuid.UUID]*entities.FeatureFlag
+}
+
+var _ repositories.FeatureFlagRepository = (*FeatureFlagRepository)(nil)
+
+// NewFeatureFlagRepository создает новый in-memory репозиторий флагов функций
+func NewFeatureFlagRepository() *FeatureFlagRepository {
+	return &FeatureFlagRepository{
+		flags: make(map[uuid.UUID]*entities.FeatureFlag),
+	}
+}
+
+// Create сохраняет флаг
+func (r *FeatureFlagRepository) Create(ctx context.Context, flag *entities.FeatureFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.flags {
+		if existing.Key == flag.Key {
+			return entities.ErrFeatureFlagExists
+		}
+	}
+	r.flags[flag.ID] = copyFeatureFlag(flag)
+	return nil
+}
+
+// GetByID получает флаг по ID
+func (r *FeatureFlagRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.FeatureFlag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	flag, ok := r.flags[id]
+	if !ok {
+		return nil, entities.ErrFeatureFlagNotFound
+	}
+	return copyFeatureFlag(flag), nil
+}
+
+// Update сохраняет изменения флага; ключ и дата создания не меняются
+func (r *FeatureFlagRepository) Update(ctx context.Context, flag *entities.FeatureFlag) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.flags[flag.ID]
+	if !ok {
+		return entities.ErrFeatureFlagNotFound
+	}
+	stored := copyFeatureFlag(flag)
+	stored.Key = existing.Key
+	stored.CreatedAt = existing.CreatedAt
+	r.flags[flag.ID] = stored
+	return nil
+}
+
+// Delete удаляет флаг
+func (r *FeatureFlagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.flags[id]; !ok {
+		return entities.ErrFeatureFlagNotFound
+	}
+	delete(r.flags, id)
+	return nil
+}
+
+// List получает все флаги по ключу
+func (r *FeatureFlagRepository) List(ctx context.Context) ([]*entities.FeatureFlag, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.FeatureFlag, 0, len(r.flags))
+	for _, flag := range r.flags {
+		result = append(result, copyFeatureFlag(flag))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result, nil
+}
+
+// copyFeatureFlag возвращает независимую копию флага
+func copyFeatureFlag(flag *entities.FeatureFlag) *entities.FeatureFlag {
+	clone := *flag
+	clone.Rules = make(entities.FeatureFlagRuleList, 0, len(flag.Rules))
+	for _, rule := range flag.Rules {
+		rule.Cities = append([]string(nil), rule.Cities...)
+		clone.Rules = append(clone.Rules, rule)
+	}
+	return &clone
+}
@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// FleetRepository in-memory реализация repositories.FleetRepository
+type FleetRepository struct {
+	mu     sync.RWMutex
+	fleets map[uuid.UUID]*entities.Fleet
+}
+
+var _ repositories.FleetRepository = (*FleetRepository)(nil)
+
+// NewFleetRepository создает новый in-memory репозиторий автопарков
+func NewFleetRepository() *FleetRepository {
+	return &FleetRepository{
+		fleets: make(map[uuid.UUID]*entities.Fleet),
+	}
+}
+
+// Create сохраняет автопарк; повторное сохранение того же ID игнорируется
+func (r *FleetRepository) Create(ctx context.Context, fleet *entities.Fleet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.fleets[fleet.ID]; !exists {
+		r.fleets[fleet.ID] = copyFleet(fleet)
+	}
+	return nil
+}
+
+// GetByID получает автопарк по ID
+func (r *FleetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Fleet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fleet, ok := r.fleets[id]
+	if !ok {
+		return nil, entities.ErrFleetNotFound
+	}
+	return copyFleet(fleet), nil
+}
+
+// Update сохраняет изменения автопарка; дата создания не меняется
+func (r *FleetRepository) Update(ctx context.Context, fleet *entities.Fleet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.fleets[fleet.ID]
+	if !ok {
+		return entities.ErrFleetNotFound
+	}
+	stored := copyFleet(fleet)
+	stored.CreatedAt = existing.CreatedAt
+	r.fleets[fleet.ID] = stored
+	return nil
+}
+
+// List получает страницу автопарков, упорядоченных по названию
+func (r *FleetRepository) List(ctx context.Context, filters *entities.FleetFilters) ([]*entities.Fleet, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := r.filter(filters)
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// Count считает автопарки по фильтрам
+func (r *FleetRepository) Count(ctx context.Context, filters *entities.FleetFilters) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.filter(filters)), nil
+}
+
+// filter отбирает копии автопарков по фильтрам. Вызывается под блокировкой
+func (r *FleetRepository) filter(filters *entities.FleetFilters) []*entities.Fleet {
+	result := make([]*entities.Fleet, 0)
+	for _, fleet := range r.fleets {
+		if filters.IDs != nil && !containsFleet(filters.IDs, &fleet.ID) {
+			continue
+		}
+		result = append(result, copyFleet(fleet))
+	}
+	return result
+}
+
+// copyFleet возвращает независимую копию автопарка
+func copyFleet(fleet *entities.Fleet) *entities.Fleet {
+	clone := *fleet
+	clone.Metadata = cloneMetadata(fleet.Metadata)
+	return &clone
+}
@@ -0,0 +1,209 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// GeofenceRepository in-memory реализация repositories.GeofenceRepository
+type GeofenceRepository struct {
+	mu        sync.RWMutex
+	geofences map[uuid.UUID]*entities.Geofence
+	// presence присутствие водителей по геозонам
+	presence map[uuid.UUID]map[uuid.UUID]entities.GeofencePresence
+}
+
+var _ repositories.GeofenceRepository = (*GeofenceRepository)(nil)
+
+// NewGeofenceRepository создает новый in-memory репозиторий геозон
+func NewGeofenceRepository() *GeofenceRepository {
+	return &GeofenceRepository{
+		geofences: make(map[uuid.UUID]*entities.Geofence),
+		presence:  make(map[uuid.UUID]map[uuid.UUID]entities.GeofencePresence),
+	}
+}
+
+// Create сохраняет геозону; повторное сохранение того же ID игнорируется
+func (r *GeofenceRepository) Create(ctx context.Context, geofence *entities.Geofence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.geofences[geofence.ID]; !exists {
+		r.geofences[geofence.ID] = copyGeofence(geofence)
+	}
+	return nil
+}
+
+// GetByID получает геозону по ID
+func (r *GeofenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	geofence, ok := r.geofences[id]
+	if !ok {
+		return nil, entities.ErrGeofenceNotFound
+	}
+	return copyGeofence(geofence), nil
+}
+
+// Update сохраняет изменения геозоны; дата создания не меняется
+func (r *GeofenceRepository) Update(ctx context.Context, geofence *entities.Geofence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.geofences[geofence.ID]
+	if !ok {
+		return entities.ErrGeofenceNotFound
+	}
+	stored := copyGeofence(geofence)
+	stored.CreatedAt = existing.CreatedAt
+	r.geofences[geofence.ID] = stored
+	return nil
+}
+
+// Delete удаляет геозону вместе с присутствием водителей в ней
+func (r *GeofenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.geofences[id]; !ok {
+		return entities.ErrGeofenceNotFound
+	}
+	delete(r.geofences, id)
+	delete(r.presence, id)
+	return nil
+}
+
+// List получает страницу геозон, упорядоченных по названию
+func (r *GeofenceRepository) List(ctx context.Context, filters *entities.GeofenceFilters) ([]*entities.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := r.filter(filters)
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// Count считает геозоны по фильтрам
+func (r *GeofenceRepository) Count(ctx context.Context, filters *entities.GeofenceFilters) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.filter(filters)), nil
+}
+
+// ListActive получает активные геозоны
+func (r *GeofenceRepository) ListActive(ctx context.Context) ([]*entities.Geofence, error) {
+	active := true
+	return r.List(ctx, &entities.GeofenceFilters{Active: &active})
+}
+
+// ListPresenceByDriverID получает присутствие водителя в геозонах
+func (r *GeofenceRepository) ListPresenceByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.GeofencePresence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.GeofencePresence, 0)
+	for _, drivers := range r.presence {
+		if presence, ok := drivers[driverID]; ok {
+			result = append(result, &presence)
+		}
+	}
+	return result, nil
+}
+
+// AddPresence отмечает вход водителя в геозону
+func (r *GeofenceRepository) AddPresence(ctx context.Context, presence *entities.GeofencePresence) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	drivers, ok := r.presence[presence.GeofenceID]
+	if !ok {
+		drivers = make(map[uuid.UUID]entities.GeofencePresence)
+		r.presence[presence.GeofenceID] = drivers
+	}
+	if _, inside := drivers[presence.DriverID]; inside {
+		return false, nil
+	}
+	drivers[presence.DriverID] = *presence
+	return true, nil
+}
+
+// RemovePresence отмечает выход водителя из геозоны
+func (r *GeofenceRepository) RemovePresence(ctx context.Context, geofenceID, driverID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, inside := r.presence[geofenceID][driverID]; !inside {
+		return false, nil
+	}
+	delete(r.presence[geofenceID], driverID)
+	return true, nil
+}
+
+// ClearPresence удаляет присутствие водителей в геозоне
+func (r *GeofenceRepository) ClearPresence(ctx context.Context, geofenceID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.presence, geofenceID)
+	return nil
+}
+
+// ListPresenceByGeofenceID получает водителей внутри геозоны, вошедших раньше — первыми
+func (r *GeofenceRepository) ListPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID, limit, offset int) ([]*entities.GeofencePresence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.GeofencePresence, 0, len(r.presence[geofenceID]))
+	for _, presence := range r.presence[geofenceID] {
+		presence := presence
+		result = append(result, &presence)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].EnteredAt.Equal(result[j].EnteredAt) {
+			return result[i].EnteredAt.Before(result[j].EnteredAt)
+		}
+		return result[i].DriverID.String() < result[j].DriverID.String()
+	})
+	return paginate(result, limit, offset), nil
+}
+
+// CountPresenceByGeofenceID считает водителей внутри геозоны
+func (r *GeofenceRepository) CountPresenceByGeofenceID(ctx context.Context, geofenceID uuid.UUID) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.presence[geofenceID]), nil
+}
+
+// filter возвращает копии геозон по фильтрам; вызывается под блокировкой
+func (r *GeofenceRepository) filter(filters *entities.GeofenceFilters) []*entities.Geofence {
+	result := make([]*entities.Geofence, 0)
+	for _, geofence := range r.geofences {
+		if filters.Active != nil && geofence.Active != *filters.Active {
+			continue
+		}
+		result = append(result, copyGeofence(geofence))
+	}
+	return result
+}
+
+// copyGeofence возвращает независимую копию геозоны
+func copyGeofence(geofence *entities.Geofence) *entities.Geofence {
+	clone := *geofence
+	clone.Polygon = append(entities.GeoPointList(nil), geofence.Polygon...)
+	clone.Metadata = cloneMetadata(geofence.Metadata)
+	return &clone
+}
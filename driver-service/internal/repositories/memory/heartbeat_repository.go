@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// HeartbeatRepository in-memory реализация repositories.HeartbeatRepository
+type HeartbeatRepository struct {
+	mu         sync.RWMutex
+	heartbeats map[uuid.UUID]entities.DriverHeartbeat
+}
+
+var _ repositories.HeartbeatRepository = (*HeartbeatRepository)(nil)
+
+// NewHeartbeatRepository создает новый in-memory репозиторий сигналов водителей
+func NewHeartbeatRepository() *HeartbeatRepository {
+	return &HeartbeatRepository{
+		heartbeats: make(map[uuid.UUID]entities.DriverHeartbeat),
+	}
+}
+
+// Touch сохраняет сигнал водителя; более ранний сигнал не заменяет сохраненный
+func (r *HeartbeatRepository) Touch(ctx context.Context, heartbeat *entities.DriverHeartbeat) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if current, ok := r.heartbeats[heartbeat.DriverID]; ok && !current.LastSeenAt.Before(heartbeat.LastSeenAt) {
+		return nil
+	}
+	r.heartbeats[heartbeat.DriverID] = *heartbeat
+	return nil
+}
+
+// ListByDriverIDs возвращает последние сигналы водителей
+func (r *HeartbeatRepository) ListByDriverIDs(ctx context.Context, driverIDs []uuid.UUID) (map[uuid.UUID]*entities.DriverHeartbeat, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	heartbeats := make(map[uuid.UUID]*entities.DriverHeartbeat, len(driverIDs))
+	for _, id := range driverIDs {
+		if heartbeat, ok := r.heartbeats[id]; ok {
+			heartbeats[id] = &heartbeat
+		}
+	}
+	return heartbeats, nil
+}
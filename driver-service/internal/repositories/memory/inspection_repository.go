@@ -0,0 +1,196 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// InspectionRepository in-memory реализация repositories.InspectionRepository
+type InspectionRepository struct {
+	mu          sync.RWMutex
+	inspections map[uuid.UUID]*entities.VehicleInspection
+	photos      map[uuid.UUID][]*entities.InspectionPhoto
+}
+
+var _ repositories.InspectionRepository = (*InspectionRepository)(nil)
+
+// NewInspectionRepository создает новый in-memory репозиторий техосмотров
+func NewInspectionRepository() *InspectionRepository {
+	return &InspectionRepository{
+		inspections: make(map[uuid.UUID]*entities.VehicleInspection),
+		photos:      make(map[uuid.UUID][]*entities.InspectionPhoto),
+	}
+}
+
+// Create создает новый техосмотр
+func (r *InspectionRepository) Create(ctx context.Context, inspection *entities.VehicleInspection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.inspections[inspection.ID] = copyInspection(inspection)
+	return nil
+}
+
+// GetByID получает техосмотр по ID
+func (r *InspectionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.VehicleInspection, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	inspection, ok := r.inspections[id]
+	if !ok {
+		return nil, entities.ErrInspectionNotFound
+	}
+	return copyInspection(inspection), nil
+}
+
+// Update обновляет техосмотр
+func (r *InspectionRepository) Update(ctx context.Context, inspection *entities.VehicleInspection) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.inspections[inspection.ID]; !ok {
+		return entities.ErrInspectionNotFound
+	}
+
+	inspection.UpdatedAt = time.Now()
+	r.inspections[inspection.ID] = copyInspection(inspection)
+	return nil
+}
+
+// List получает список техосмотров с фильтрами, ближайшие по сроку первыми
+func (r *InspectionRepository) List(ctx context.Context, filters *entities.InspectionFilters) ([]*entities.VehicleInspection, error) {
+	inspections := r.filter(filters)
+	if filters == nil {
+		return inspections, nil
+	}
+	return paginate(inspections, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество техосмотров с фильтрами
+func (r *InspectionRepository) Count(ctx context.Context, filters *entities.InspectionFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// SavePhoto сохраняет фотографию; фотография того же ракурса заменяется
+func (r *InspectionRepository) SavePhoto(ctx context.Context, photo *entities.InspectionPhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	photos := r.photos[photo.InspectionID]
+	for i, existing := range photos {
+		if existing.Angle == photo.Angle {
+			// При замене фотографии сохраняется ID исходной записи
+			photo.ID = existing.ID
+			clone := *photo
+			photos[i] = &clone
+			return nil
+		}
+	}
+
+	clone := *photo
+	r.photos[photo.InspectionID] = append(photos, &clone)
+	return nil
+}
+
+// UpdatePhoto обновляет результат проверки фотографии
+func (r *InspectionRepository) UpdatePhoto(ctx context.Context, photo *entities.InspectionPhoto) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.photos[photo.InspectionID] {
+		if existing.ID == photo.ID {
+			clone := *photo
+			r.photos[photo.InspectionID][i] = &clone
+			return nil
+		}
+	}
+	return entities.ErrInvalidInspectionPhoto
+}
+
+// ListPhotos возвращает фотографии техосмотра в порядке загрузки
+func (r *InspectionRepository) ListPhotos(ctx context.Context, inspectionID uuid.UUID) ([]*entities.InspectionPhoto, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.InspectionPhoto, 0, len(r.photos[inspectionID]))
+	for _, photo := range r.photos[inspectionID] {
+		clone := *photo
+		result = append(result, &clone)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].UploadedAt.Before(result[j].UploadedAt)
+	})
+	return result, nil
+}
+
+// filter возвращает копии техосмотров по фильтрам
+func (r *InspectionRepository) filter(filters *entities.InspectionFilters) []*entities.VehicleInspection {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.VehicleInspection, 0)
+	for _, inspection := range r.inspections {
+		if matchInspection(inspection, filters) {
+			result = append(result, copyInspection(inspection))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].DueDate.Before(result[j].DueDate)
+	})
+	return result
+}
+
+// matchInspection проверяет техосмотр на соответствие фильтрам
+func matchInspection(inspection *entities.VehicleInspection, filters *entities.InspectionFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.VehicleID != nil && inspection.VehicleID != *filters.VehicleID {
+		return false
+	}
+	if filters.FleetID != nil && (inspection.FleetID == nil || *inspection.FleetID != *filters.FleetID) {
+		return false
+	}
+	if filters.DriverID != nil && (inspection.DriverID == nil || *inspection.DriverID != *filters.DriverID) {
+		return false
+	}
+
+	if len(filters.Status) > 0 {
+		found := false
+		for _, status := range filters.Status {
+			if inspection.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.DueBefore != nil && inspection.DueDate.After(*filters.DueBefore) {
+		return false
+	}
+	if filters.DueAfter != nil && inspection.DueDate.Before(*filters.DueAfter) {
+		return false
+	}
+
+	return true
+}
+
+// copyInspection возвращает независимую копию техосмотра
+func copyInspection(inspection *entities.VehicleInspection) *entities.VehicleInspection {
+	clone := *inspection
+	clone.Metadata = cloneMetadata(inspection.Metadata)
+	return &clone
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+)
+
+// JobRunRepository in-memory реализация repositories.JobRunRepository
+type JobRunRepository struct {
+	mu   sync.RWMutex
+	runs []*entities.JobRun
+	// claims последний отмеченный запуск по расписанию каждой задачи
+	claims map[string]jobClaim
+}
+
+// jobClaim отметка запуска задачи по расписанию
+type jobClaim struct {
+	scheduledAt time.Time
+	instance    string
+}
+
+var _ repositories.JobRunRepository = (*JobRunRepository)(nil)
+
+// NewJobRunRepository создает новый in-memory репозиторий истории запусков
+func NewJobRunRepository() *JobRunRepository {
+	return &JobRunRepository{claims: make(map[string]jobClaim)}
+}
+
+// Create сохраняет запуск задачи
+func (r *JobRunRepository) Create(ctx context.Context, run *entities.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *run
+	r.runs = append(r.runs, &clone)
+	return nil
+}
+
+// List получает запуски задач, новые первыми
+func (r *JobRunRepository) List(ctx context.Context, filters *entities.JobRunFilters) ([]*entities.JobRun, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	runs := make([]*entities.JobRun, 0)
+	for _, run := range r.runs {
+		if filters.JobName != "" && run.JobName != filters.JobName {
+			continue
+		}
+		if filters.Status != "" && run.Status != filters.Status {
+			continue
+		}
+		clone := *run
+		runs = append(runs, &clone)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartedAt.After(runs[j].StartedAt)
+	})
+	return paginate(runs, filters.Limit, 0), nil
+}
+
+// ClaimScheduledRun отмечает запуск задачи по расписанию
+func (r *JobRunRepository) ClaimScheduledRun(ctx context.Context, name string, scheduledAt time.Time, instance string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if claim, ok := r.claims[name]; ok {
+		if claim.scheduledAt.After(scheduledAt) ||
+			(claim.scheduledAt.Equal(scheduledAt) && claim.instance != instance) {
+			return false, nil
+		}
+	}
+	r.claims[name] = jobClaim{scheduledAt: scheduledAt, instance: instance}
+	return true, nil
+}
+
+// DeleteOlderThan удаляет запуски, начатые до before
+func (r *JobRunRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.runs[:0]
+	for _, run := range r.runs {
+		if run.StartedAt.Before(before) {
+			continue
+		}
+		kept = append(kept, run)
+	}
+	deleted := len(r.runs) - len(kept)
+	r.runs = kept
+	return deleted, nil
+}
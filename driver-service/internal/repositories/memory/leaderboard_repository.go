@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// LeaderboardRepository in-memory реализация repositories.LeaderboardRepository.
+// Агрегаты считаются на лету по in-memory репозиториям водителей, смен и оценок
+type LeaderboardRepository struct {
+	drivers *DriverRepository
+	shifts  *ShiftRepository
+	ratings *RatingRepository
+}
+
+var _ repositories.LeaderboardRepository = (*LeaderboardRepository)(nil)
+
+// NewLeaderboardRepository создает новый in-memory репозиторий рейтингов
+func NewLeaderboardRepository(drivers *DriverRepository, shifts *ShiftRepository, ratings *RatingRepository) *LeaderboardRepository {
+	return &LeaderboardRepository{
+		drivers: drivers,
+		shifts:  shifts,
+		ratings: ratings,
+	}
+}
+
+// GetWeeklyAggregates вычисляет показатели водителей за неделю
+func (r *LeaderboardRepository) GetWeeklyAggregates(ctx context.Context, weekStart time.Time) ([]*entities.LeaderboardAggregate, error) {
+	weekStart = entities.WeekStart(weekStart)
+	// To включительный, как и в фильтрах репозиториев
+	weekEnd := weekStart.AddDate(0, 0, 7).Add(-time.Nanosecond)
+
+	shifts, err := r.shifts.List(ctx, &entities.ShiftFilters{
+		Status: []entities.ShiftStatus{entities.ShiftStatusActive, entities.ShiftStatusCompleted},
+		From:   &weekStart,
+		To:     &weekEnd,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := r.ratings.List(ctx, &entities.RatingFilters{From: &weekStart, To: &weekEnd})
+	if err != nil {
+		return nil, err
+	}
+
+	aggregates := make(map[uuid.UUID]*entities.LeaderboardAggregate)
+	ratingSums := make(map[uuid.UUID]int)
+	aggregate := func(driverID uuid.UUID) *entities.LeaderboardAggregate {
+		if a, ok := aggregates[driverID]; ok {
+			return a
+		}
+		a := &entities.LeaderboardAggregate{DriverID: driverID, WeekStart: weekStart}
+		aggregates[driverID] = a
+		return a
+	}
+
+	for _, shift := range shifts {
+		a := aggregate(shift.DriverID)
+		a.Trips += shift.TotalTrips
+		a.Earnings += shift.TotalEarnings
+	}
+	for _, rating := range ratings {
+		a := aggregate(rating.DriverID)
+		a.RatingCount++
+		ratingSums[rating.DriverID] += rating.Rating
+	}
+
+	result := make([]*entities.LeaderboardAggregate, 0, len(aggregates))
+	for driverID, a := range aggregates {
+		driver, err := r.drivers.GetByID(ctx, driverID)
+		if err != nil {
+			// Удаленные водители в рейтинг не попадают
+			continue
+		}
+
+		a.FirstName = driver.FirstName
+		a.LastName = driver.LastName
+		a.City = driver.City()
+		a.FleetID = driver.FleetID
+		a.Visibility = driver.LeaderboardVisibility()
+		if a.RatingCount > 0 {
+			a.AverageRating = float64(ratingSums[driverID]) / float64(a.RatingCount)
+		}
+		result = append(result, a)
+	}
+
+	return result, nil
+}
+
+// Refresh ничего не делает: агрегаты всегда актуальны
+func (r *LeaderboardRepository) Refresh(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// LocationExportRepository in-memory реализация repositories.LocationExportRepository
+type LocationExportRepository struct {
+	mu      sync.RWMutex
+	exports map[uuid.UUID]*entities.LocationExport
+}
+
+var _ repositories.LocationExportRepository = (*LocationExportRepository)(nil)
+
+// NewLocationExportRepository создает новый in-memory репозиторий выгрузок истории местоположений
+func NewLocationExportRepository() *LocationExportRepository {
+	return &LocationExportRepository{
+		exports: make(map[uuid.UUID]*entities.LocationExport),
+	}
+}
+
+// Create сохраняет выгрузку
+func (r *LocationExportRepository) Create(ctx context.Context, export *entities.LocationExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *export
+	r.exports[export.ID] = &clone
+	return nil
+}
+
+// GetByID получает выгрузку по ID
+func (r *LocationExportRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.LocationExport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	export, ok := r.exports[id]
+	if !ok {
+		return nil, entities.ErrLocationExportNotFound
+	}
+	clone := *export
+	return &clone, nil
+}
+
+// Update сохраняет состояние и результат выгрузки
+func (r *LocationExportRepository) Update(ctx context.Context, export *entities.LocationExport) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.exports[export.ID]; !ok {
+		return entities.ErrLocationExportNotFound
+	}
+	clone := *export
+	clone.DownloadURL, clone.DownloadURLExpiresAt = nil, nil
+	r.exports[export.ID] = &clone
+	return nil
+}
+
+// Delete удаляет выгрузку
+func (r *LocationExportRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.exports, id)
+	return nil
+}
+
+// ListExpired получает выгрузки с истекшим сроком хранения, начиная с самых старых
+func (r *LocationExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*entities.LocationExport, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.LocationExport
+	for _, export := range r.exports {
+		if export.ExpiresAt != nil && !export.ExpiresAt.After(before) {
+			clone := *export
+			result = append(result, &clone)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ExpiresAt.Before(*result[j].ExpiresAt) })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// FailStale завершает ошибкой зависшие выгрузки
+func (r *LocationExportRepository) FailStale(ctx context.Context, createdBefore time.Time, reason string, now time.Time, retention time.Duration) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failed int64
+	for _, export := range r.exports {
+		if (export.Status == entities.LocationExportPending || export.Status == entities.LocationExportRunning) &&
+			export.CreatedAt.Before(createdBefore) {
+			export.Fail(reason, now, retention)
+			failed++
+		}
+	}
+	return failed, nil
+}
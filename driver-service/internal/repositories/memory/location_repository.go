@@ -0,0 +1,296 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// LocationRepository in-memory реализация repositories.LocationRepository
+type LocationRepository struct {
+	mu        sync.RWMutex
+	locations map[uuid.UUID]*entities.DriverLocation
+}
+
+var _ repositories.LocationRepository = (*LocationRepository)(nil)
+
+// NewLocationRepository создает новый in-memory репозиторий местоположений
+func NewLocationRepository() *LocationRepository {
+	return &LocationRepository{
+		locations: make(map[uuid.UUID]*entities.DriverLocation),
+	}
+}
+
+// Create сохраняет местоположение; точка водителя с уже сохраненным временем записи пропускается
+// и возвращается false
+func (r *LocationRepository) Create(ctx context.Context, location *entities.DriverLocation) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.exists(location) {
+		return false, nil
+	}
+	r.locations[location.ID] = copyLocation(location)
+	return true, nil
+}
+
+// GetByID получает местоположение по ID
+func (r *LocationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverLocation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	location, ok := r.locations[id]
+	if !ok {
+		return nil, entities.ErrLocationNotFound
+	}
+	return copyLocation(location), nil
+}
+
+// GetLatestByDriverID получает последнее местоположение водителя
+func (r *LocationRepository) GetLatestByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverLocation, error) {
+	locations := r.filter(&entities.LocationFilters{DriverID: &driverID})
+	if len(locations) == 0 {
+		return nil, entities.ErrLocationNotFound
+	}
+	return locations[0], nil
+}
+
+// GetByDriverIDInTimeRange получает местоположения водителя за период в хронологическом порядке
+func (r *LocationRepository) GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverLocation, error) {
+	locations := r.filter(&entities.LocationFilters{DriverID: &driverID, From: &from, To: &to})
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].RecordedAt.Before(locations[j].RecordedAt)
+	})
+	return locations, nil
+}
+
+// List получает список местоположений с фильтрами
+func (r *LocationRepository) List(ctx context.Context, filters *entities.LocationFilters) ([]*entities.DriverLocation, error) {
+	locations := r.filter(filters)
+	if filters == nil {
+		return locations, nil
+	}
+	return paginate(locations, filters.Limit, filters.Offset), nil
+}
+
+// CreateBatch сохраняет несколько местоположений, пропуская повторы
+func (r *LocationRepository) CreateBatch(ctx context.Context, locations []*entities.DriverLocation) ([]*entities.DriverLocation, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	saved := make([]*entities.DriverLocation, 0, len(locations))
+	for _, location := range locations {
+		if r.exists(location) {
+			continue
+		}
+		r.locations[location.ID] = copyLocation(location)
+		saved = append(saved, location)
+	}
+	return saved, nil
+}
+
+// exists проверяет, сохранена ли точка водителя с тем же временем записи
+// (ограничение уникальности driver_id, recorded_at)
+func (r *LocationRepository) exists(location *entities.DriverLocation) bool {
+	key := location.PointKey()
+	for _, saved := range r.locations {
+		if saved.PointKey() == key {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteOld удаляет местоположения старше указанного времени
+func (r *LocationRepository) DeleteOld(ctx context.Context, olderThan time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, location := range r.locations {
+		if location.RecordedAt.Before(olderThan) {
+			delete(r.locations, id)
+		}
+	}
+	return nil
+}
+
+// EnsurePartitions ничего не делает: в памяти местоположения не секционируются
+func (r *LocationRepository) EnsurePartitions(ctx context.Context, from, to time.Time) (int, error) {
+	return 0, nil
+}
+
+// GetOldestRecordedAt получает время записи самого старого местоположения
+func (r *LocationRepository) GetOldestRecordedAt(ctx context.Context) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var oldest time.Time
+	for _, location := range r.locations {
+		if oldest.IsZero() || location.RecordedAt.Before(oldest) {
+			oldest = location.RecordedAt
+		}
+	}
+	if oldest.IsZero() {
+		return time.Time{}, entities.ErrLocationNotFound
+	}
+	return oldest, nil
+}
+
+// GetActivity считает пробег и время на связи водителя по точкам в [from, to)
+func (r *LocationRepository) GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time, maxGap time.Duration) (*entities.LocationActivity, error) {
+	r.mu.RLock()
+	locations := make([]*entities.DriverLocation, 0)
+	for _, location := range r.locations {
+		if location.DriverID == driverID && !location.RecordedAt.Before(from) && location.RecordedAt.Before(to) {
+			locations = append(locations, location)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return locations[i].RecordedAt.Before(locations[j].RecordedAt)
+	})
+	return entities.NewLocationActivity(locations, maxGap), nil
+}
+
+// ListDriverIDsInTimeRange получает водителей, местоположения которых записаны в [from, to)
+func (r *LocationRepository) ListDriverIDsInTimeRange(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[uuid.UUID]bool)
+	driverIDs := make([]uuid.UUID, 0)
+	for _, location := range r.locations {
+		if location.RecordedAt.Before(from) || !location.RecordedAt.Before(to) || seen[location.DriverID] {
+			continue
+		}
+		seen[location.DriverID] = true
+		driverIDs = append(driverIDs, location.DriverID)
+	}
+	return driverIDs, nil
+}
+
+// DeleteByDriverID удаляет все местоположения водителя
+func (r *LocationRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, location := range r.locations {
+		if location.DriverID == driverID {
+			delete(r.locations, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// GetNearby возвращает последние местоположения водителей в радиусе, ближайшие первыми
+func (r *LocationRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error) {
+	r.mu.RLock()
+	latest := make(map[uuid.UUID]*entities.DriverLocation)
+	for _, location := range r.locations {
+		if current, ok := latest[location.DriverID]; !ok || location.RecordedAt.After(current.RecordedAt) {
+			latest[location.DriverID] = location
+		}
+	}
+	r.mu.RUnlock()
+
+	center := &entities.DriverLocation{Latitude: lat, Longitude: lon}
+	result := make([]*entities.DriverLocation, 0, len(latest))
+	for _, location := range latest {
+		if location.IsInRadius(center, radiusKm) {
+			result = append(result, copyLocation(location))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return center.DistanceTo(result[i]) < center.DistanceTo(result[j])
+	})
+
+	return paginate(result, limit, 0), nil
+}
+
+// filter возвращает копии местоположений по фильтрам, новые первыми
+func (r *LocationRepository) filter(filters *entities.LocationFilters) []*entities.DriverLocation {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverLocation, 0)
+	for _, location := range r.locations {
+		if matchLocation(location, filters) {
+			result = append(result, copyLocation(location))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].RecordedAt.After(result[j].RecordedAt)
+	})
+	return result
+}
+
+// matchLocation проверяет местоположение на соответствие фильтрам
+func matchLocation(location *entities.DriverLocation, filters *entities.LocationFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && location.DriverID != *filters.DriverID {
+		return false
+	}
+	if filters.From != nil && location.RecordedAt.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && location.RecordedAt.After(*filters.To) {
+		return false
+	}
+	if filters.MinSpeed != nil && location.GetSpeed() < *filters.MinSpeed {
+		return false
+	}
+	if filters.MaxSpeed != nil && location.GetSpeed() > *filters.MaxSpeed {
+		return false
+	}
+	if filters.MinAccuracy != nil && (location.Accuracy == nil || *location.Accuracy > *filters.MinAccuracy) {
+		return false
+	}
+	if filters.OnlyHighAccuracy && !location.IsHighAccuracy() {
+		return false
+	}
+	if filters.OnTrip != nil && location.IsOnTrip() != *filters.OnTrip {
+		return false
+	}
+	if filters.OrderID != nil {
+		orderID := location.OrderID()
+		if orderID == nil || *orderID != *filters.OrderID {
+			return false
+		}
+	}
+	if filters.Source != nil && location.Source() != *filters.Source {
+		return false
+	}
+	if filters.City != "" && location.ShardKey != entities.NormalizeCity(filters.City) {
+		return false
+	}
+	if filters.Bounds != nil {
+		ne, sw := filters.Bounds.NorthEast, filters.Bounds.SouthWest
+		if location.Latitude > ne.Latitude || location.Latitude < sw.Latitude ||
+			location.Longitude > ne.Longitude || location.Longitude < sw.Longitude {
+			return false
+		}
+	}
+
+	return true
+}
+
+// copyLocation возвращает независимую копию местоположения
+func copyLocation(location *entities.DriverLocation) *entities.DriverLocation {
+	clone := *location
+	clone.Metadata = cloneMetadata(location.Metadata)
+	return &clone
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// locationSummaryKey ключ сводной точки: водитель, уровень и начало интервала
+type locationSummaryKey struct {
+	driverID    uuid.UUID
+	tier        string
+	bucketStart int64
+}
+
+// LocationSummaryRepository in-memory реализация repositories.LocationSummaryRepository
+type LocationSummaryRepository struct {
+	mu        sync.RWMutex
+	summaries map[locationSummaryKey]*entities.LocationSummary
+}
+
+var _ repositories.LocationSummaryRepository = (*LocationSummaryRepository)(nil)
+
+// NewLocationSummaryRepository создает новый in-memory репозиторий сводных точек
+func NewLocationSummaryRepository() *LocationSummaryRepository {
+	return &LocationSummaryRepository{
+		summaries: make(map[locationSummaryKey]*entities.LocationSummary),
+	}
+}
+
+// Upsert сохраняет сводные точки, заменяя точки тех же интервалов
+func (r *LocationSummaryRepository) Upsert(ctx context.Context, summaries []*entities.LocationSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, summary := range summaries {
+		key := locationSummaryKey{
+			driverID:    summary.DriverID,
+			tier:        summary.Tier,
+			bucketStart: summary.BucketStart.UnixNano(),
+		}
+		r.summaries[key] = copyLocationSummary(summary)
+	}
+	return nil
+}
+
+// GetByDriverIDInTimeRange получает сводные точки водителя за период в хронологическом порядке
+func (r *LocationSummaryRepository) GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, tier string, from, to time.Time) ([]*entities.LocationSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.LocationSummary, 0)
+	for _, summary := range r.summaries {
+		if summary.DriverID != driverID || summary.Tier != tier ||
+			summary.BucketStart.Before(from) || summary.BucketStart.After(to) {
+			continue
+		}
+		result = append(result, copyLocationSummary(summary))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].BucketStart.Before(result[j].BucketStart)
+	})
+	return result, nil
+}
+
+// ListByDriverID получает сводные точки водителя всех уровней
+func (r *LocationSummaryRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.LocationSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.LocationSummary, 0)
+	for _, summary := range r.summaries {
+		if summary.DriverID == driverID {
+			result = append(result, copyLocationSummary(summary))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Tier != result[j].Tier {
+			return result[i].Tier < result[j].Tier
+		}
+		return result[i].BucketStart.Before(result[j].BucketStart)
+	})
+	return result, nil
+}
+
+// DeleteOld удаляет сводные точки уровня с интервалами, начавшимися до olderThan
+func (r *LocationSummaryRepository) DeleteOld(ctx context.Context, tier string, olderThan time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, summary := range r.summaries {
+		if summary.Tier == tier && summary.BucketStart.Before(olderThan) {
+			delete(r.summaries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteByDriverID удаляет все сводные точки водителя
+func (r *LocationSummaryRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for key, summary := range r.summaries {
+		if summary.DriverID == driverID {
+			delete(r.summaries, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// copyLocationSummary возвращает независимую копию сводной точки
+func copyLocationSummary(summary *entities.LocationSummary) *entities.LocationSummary {
+	clone := *summary
+	if summary.OrderID != nil {
+		orderID := *summary.OrderID
+		clone.OrderID = &orderID
+	}
+	return &clone
+}
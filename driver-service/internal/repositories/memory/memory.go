@@ -0,0 +1,48 @@
+// Package memory содержит in-memory реализации репозиториев для unit-тестов
+// и локальной разработки без PostgreSQL.
+package memory
+
+import (
+	"sort"
+
+	"driver-service/internal/domain/entities"
+)
+
+// paginate применяет offset и limit к отсортированному срезу
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset > 0 {
+		if offset >= len(items) {
+			return []T{}
+		}
+		items = items[offset:]
+	}
+
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+
+	return items
+}
+
+// sortItems стабильно сортирует срез функцией сравнения с учетом направления ("asc"/"desc")
+func sortItems[T any](items []T, compare func(a, b T) int, direction string) {
+	sort.SliceStable(items, func(i, j int) bool {
+		if direction == "desc" {
+			return compare(items[i], items[j]) > 0
+		}
+		return compare(items[i], items[j]) < 0
+	})
+}
+
+// cloneMetadata возвращает независимую копию метаданных
+func cloneMetadata(m entities.Metadata) entities.Metadata {
+	if m == nil {
+		return nil
+	}
+
+	clone := make(entities.Metadata, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,178 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// MessageRepository in-memory реализация repositories.MessageRepository
+type MessageRepository struct {
+	mu       sync.RWMutex
+	messages map[uuid.UUID]*entities.DriverMessage
+}
+
+var _ repositories.MessageRepository = (*MessageRepository)(nil)
+
+// NewMessageRepository создает новый in-memory репозиторий сообщений
+func NewMessageRepository() *MessageRepository {
+	return &MessageRepository{
+		messages: make(map[uuid.UUID]*entities.DriverMessage),
+	}
+}
+
+// Create сохраняет сообщение; повторное сохранение того же ID игнорируется
+func (r *MessageRepository) Create(ctx context.Context, message *entities.DriverMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.messages[message.ID]; !exists {
+		r.messages[message.ID] = copyMessage(message)
+	}
+	return nil
+}
+
+// GetByIDs получает сообщения водителя по ID; повторяющиеся ID возвращаются один раз
+func (r *MessageRepository) GetByIDs(ctx context.Context, driverID uuid.UUID, ids []uuid.UUID) ([]*entities.DriverMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]*entities.DriverMessage, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if message, ok := r.messages[id]; ok && message.DriverID == driverID {
+			result = append(result, copyMessage(message))
+		}
+	}
+	return result, nil
+}
+
+// List получает страницу переписки, новые сообщения первыми
+func (r *MessageRepository) List(ctx context.Context, filters *entities.MessageFilters) ([]*entities.DriverMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := r.thread(filters)
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID.String() > result[j].ID.String()
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// Count считает сообщения переписки
+func (r *MessageRepository) Count(ctx context.Context, filters *entities.MessageFilters) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.thread(filters)), nil
+}
+
+// UpdateReceipt сохраняет отметки о доставке и прочтении
+func (r *MessageRepository) UpdateReceipt(ctx context.Context, message *entities.DriverMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.messages[message.ID]
+	if !ok {
+		return entities.ErrMessageNotFound
+	}
+	stored.DeliveredAt = message.DeliveredAt
+	stored.ReadAt = message.ReadAt
+	return nil
+}
+
+// ListByDriverID получает всю переписку водителя в хронологическом порядке
+func (r *MessageRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverMessage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverMessage, 0)
+	for _, message := range r.messages {
+		if message.DriverID == driverID {
+			result = append(result, copyMessage(message))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return result, nil
+}
+
+// DeleteByDriverID удаляет всю переписку водителя
+func (r *MessageRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, message := range r.messages {
+		if message.DriverID == driverID {
+			delete(r.messages, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteOlderThan удаляет сообщения, отправленные до before
+func (r *MessageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, message := range r.messages {
+		if message.CreatedAt.Before(before) {
+			delete(r.messages, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// thread возвращает копии сообщений переписки; вызывается под блокировкой
+func (r *MessageRepository) thread(filters *entities.MessageFilters) []*entities.DriverMessage {
+	result := make([]*entities.DriverMessage, 0)
+	for _, message := range r.messages {
+		if message.DriverID != filters.DriverID {
+			continue
+		}
+		if filters.OrderID == nil && message.OrderID != nil {
+			continue
+		}
+		if filters.OrderID != nil && (message.OrderID == nil || *message.OrderID != *filters.OrderID) {
+			continue
+		}
+		result = append(result, copyMessage(message))
+	}
+	return result
+}
+
+// copyMessage возвращает независимую копию сообщения
+func copyMessage(message *entities.DriverMessage) *entities.DriverMessage {
+	clone := *message
+	if message.OrderID != nil {
+		orderID := *message.OrderID
+		clone.OrderID = &orderID
+	}
+	if message.DelayMinutes != nil {
+		delay := *message.DelayMinutes
+		clone.DelayMinutes = &delay
+	}
+	return &clone
+}
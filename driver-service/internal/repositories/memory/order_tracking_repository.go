@@ -0,0 +1,93 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// OrderTrackingRepository in-memory реализация repositories.OrderTrackingRepository
+type OrderTrackingRepository struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]*entities.OrderTrackingSession
+}
+
+var _ repositories.OrderTrackingRepository = (*OrderTrackingRepository)(nil)
+
+// NewOrderTrackingRepository создает новый in-memory репозиторий отслеживания заказов
+func NewOrderTrackingRepository() *OrderTrackingRepository {
+	return &OrderTrackingRepository{
+		sessions: make(map[uuid.UUID]*entities.OrderTrackingSession),
+	}
+}
+
+// Create сохраняет отслеживание; второе неостановленное отслеживание заказа не сохраняется
+func (r *OrderTrackingRepository) Create(ctx context.Context, session *entities.OrderTrackingSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.sessions {
+		if existing.OrderID == session.OrderID && existing.StoppedAt == nil {
+			return entities.ErrOrderTrackingExists
+		}
+	}
+
+	r.sessions[session.ID] = copyOrderTracking(session)
+	return nil
+}
+
+// GetByID получает отслеживание по ID
+func (r *OrderTrackingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.OrderTrackingSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, entities.ErrOrderTrackingNotFound
+	}
+	return copyOrderTracking(session), nil
+}
+
+// GetActiveByOrder получает неостановленное отслеживание заказа
+func (r *OrderTrackingRepository) GetActiveByOrder(ctx context.Context, orderID uuid.UUID) (*entities.OrderTrackingSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, session := range r.sessions {
+		if session.OrderID == orderID && session.StoppedAt == nil {
+			return copyOrderTracking(session), nil
+		}
+	}
+	return nil, entities.ErrOrderTrackingNotFound
+}
+
+// Stop сохраняет остановку отслеживания, если оно еще не остановлено
+func (r *OrderTrackingRepository) Stop(ctx context.Context, session *entities.OrderTrackingSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.sessions[session.ID]
+	if !ok {
+		return entities.ErrOrderTrackingNotFound
+	}
+	if stored.StoppedAt != nil {
+		return entities.ErrOrderTrackingInactive
+	}
+
+	r.sessions[session.ID] = copyOrderTracking(session)
+	return nil
+}
+
+// copyOrderTracking возвращает независимую копию отслеживания
+func copyOrderTracking(session *entities.OrderTrackingSession) *entities.OrderTrackingSession {
+	clone := *session
+	if session.StoppedAt != nil {
+		stoppedAt := *session.StoppedAt
+		clone.StoppedAt = &stoppedAt
+	}
+	return &clone
+}
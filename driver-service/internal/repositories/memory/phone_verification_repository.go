@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// PhoneVerificationRepository in-memory реализация repositories.PhoneVerificationRepository
+type PhoneVerificationRepository struct {
+	mu            sync.Mutex
+	verifications map[uuid.UUID]*entities.PhoneVerification
+}
+
+var _ repositories.PhoneVerificationRepository = (*PhoneVerificationRepository)(nil)
+
+// NewPhoneVerificationRepository создает новый in-memory репозиторий кодов подтверждения телефона
+func NewPhoneVerificationRepository() *PhoneVerificationRepository {
+	return &PhoneVerificationRepository{
+		verifications: make(map[uuid.UUID]*entities.PhoneVerification),
+	}
+}
+
+// Save сохраняет код водителя, заменяя прежний
+func (r *PhoneVerificationRepository) Save(ctx context.Context, verification *entities.PhoneVerification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clone := *verification
+	r.verifications[verification.DriverID] = &clone
+	return nil
+}
+
+// Get получает действующий код водителя
+func (r *PhoneVerificationRepository) Get(ctx context.Context, driverID uuid.UUID) (*entities.PhoneVerification, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verification, ok := r.verifications[driverID]
+	if !ok {
+		return nil, entities.ErrVerificationCodeNotFound
+	}
+	clone := *verification
+	return &clone, nil
+}
+
+// IncrementAttempts учитывает попытку ввода кода
+func (r *PhoneVerificationRepository) IncrementAttempts(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	verification, ok := r.verifications[driverID]
+	if !ok {
+		return 0, entities.ErrVerificationCodeNotFound
+	}
+	verification.Attempts++
+	return verification.Attempts, nil
+}
+
+// Delete удаляет код водителя
+func (r *PhoneVerificationRepository) Delete(ctx context.Context, driverID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.verifications, driverID)
+	return nil
+}
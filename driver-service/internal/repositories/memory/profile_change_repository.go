@@ -0,0 +1,168 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ProfileChangeRepository in-memory реализация repositories.ProfileChangeRepository. Поля
+// водителя при одобрении меняются в связанном DriverRepository
+type ProfileChangeRepository struct {
+	mu      sync.RWMutex
+	changes map[uuid.UUID]*entities.ProfileChange
+	drivers *DriverRepository
+}
+
+var _ repositories.ProfileChangeRepository = (*ProfileChangeRepository)(nil)
+
+// NewProfileChangeRepository создает новый in-memory репозиторий заявок на изменение профиля
+func NewProfileChangeRepository(drivers *DriverRepository) *ProfileChangeRepository {
+	return &ProfileChangeRepository{
+		changes: make(map[uuid.UUID]*entities.ProfileChange),
+		drivers: drivers,
+	}
+}
+
+// Create сохраняет заявку; вторая ожидающая заявка водителя возвращает ErrProfileChangePending
+func (r *ProfileChangeRepository) Create(ctx context.Context, change *entities.ProfileChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, current := range r.changes {
+		if current.DriverID == change.DriverID && current.IsPending() {
+			return entities.ErrProfileChangePending
+		}
+	}
+
+	r.changes[change.ID] = copyProfileChange(change)
+	return nil
+}
+
+// GetByID получает заявку
+func (r *ProfileChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProfileChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	change, ok := r.changes[id]
+	if !ok {
+		return nil, entities.ErrProfileChangeNotFound
+	}
+	return copyProfileChange(change), nil
+}
+
+// ListByDriver получает заявки водителя, новые первыми
+func (r *ProfileChangeRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error) {
+	changes := r.list(func(change *entities.ProfileChange) bool {
+		return change.DriverID == driverID
+	})
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.After(changes[j].CreatedAt)
+	})
+	return paginate(changes, limit, offset), nil
+}
+
+// ListPending получает заявки, ожидающие решения, старые первыми
+func (r *ProfileChangeRepository) ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error) {
+	changes := r.list(func(change *entities.ProfileChange) bool {
+		return change.IsPending()
+	})
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.Before(changes[j].CreatedAt)
+	})
+	return paginate(changes, limit, offset), nil
+}
+
+// Approve сохраняет одобрение заявки и юридически значимые поля водителя
+func (r *ProfileChangeRepository) Approve(ctx context.Context, change *entities.ProfileChange, driver *entities.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.changes[change.ID]
+	if !ok {
+		return entities.ErrProfileChangeNotFound
+	}
+	if !current.IsPending() {
+		return entities.ErrProfileChangeResolved
+	}
+	if err := r.drivers.updateLegalFields(driver); err != nil {
+		return err
+	}
+
+	r.changes[change.ID] = copyProfileChange(change)
+	return nil
+}
+
+// Reject сохраняет отклонение заявки
+func (r *ProfileChangeRepository) Reject(ctx context.Context, change *entities.ProfileChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.changes[change.ID]
+	if !ok {
+		return entities.ErrProfileChangeNotFound
+	}
+	if !current.IsPending() {
+		return entities.ErrProfileChangeResolved
+	}
+
+	r.changes[change.ID] = copyProfileChange(change)
+	return nil
+}
+
+// list возвращает копии заявок, удовлетворяющих условию
+func (r *ProfileChangeRepository) list(match func(*entities.ProfileChange) bool) []*entities.ProfileChange {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []*entities.ProfileChange{}
+	for _, change := range r.changes {
+		if match(change) {
+			result = append(result, copyProfileChange(change))
+		}
+	}
+	return result
+}
+
+// updateLegalFields сохраняет ФИО и данные паспорта и удостоверения водителя. Номер
+// удостоверения, занятый другим неудаленным водителем, возвращает ErrDriverExists
+func (r *DriverRepository) updateLegalFields(driver *entities.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.drivers[driver.ID]
+	if !ok || existing.DeletedAt != nil {
+		return entities.ErrDriverNotFound
+	}
+	for _, other := range r.drivers {
+		if other.ID != driver.ID && other.DeletedAt == nil && matchUnique(other, "", "", driver.LicenseNumber) {
+			return entities.ErrDriverExists
+		}
+	}
+
+	existing.FirstName = driver.FirstName
+	existing.LastName = driver.LastName
+	existing.MiddleName = nil
+	if driver.MiddleName != nil {
+		middleName := *driver.MiddleName
+		existing.MiddleName = &middleName
+	}
+	existing.PassportSeries = driver.PassportSeries
+	existing.PassportNumber = driver.PassportNumber
+	existing.LicenseNumber = driver.LicenseNumber
+	existing.UpdatedAt = time.Now()
+	return nil
+}
+
+// copyProfileChange возвращает независимую копию заявки
+func copyProfileChange(change *entities.ProfileChange) *entities.ProfileChange {
+	clone := *change
+	clone.Changes = cloneMetadata(change.Changes)
+	return &clone
+}
@@ -0,0 +1,230 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// RatingRepository in-memory реализация repositories.RatingRepository
+type RatingRepository struct {
+	mu      sync.RWMutex
+	ratings map[uuid.UUID]*entities.DriverRating
+}
+
+var _ repositories.RatingRepository = (*RatingRepository)(nil)
+
+// NewRatingRepository создает новый in-memory репозиторий оценок
+func NewRatingRepository() *RatingRepository {
+	return &RatingRepository{
+		ratings: make(map[uuid.UUID]*entities.DriverRating),
+	}
+}
+
+// Create создает новую оценку
+func (r *RatingRepository) Create(ctx context.Context, rating *entities.DriverRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Одна оценка клиента на заказ, как и уникальный индекс в PostgreSQL
+	for _, existing := range r.ratings {
+		if existing.ID == rating.ID || sameOrderRating(existing, rating) {
+			return fmt.Errorf("failed to create rating: %w", entities.ErrRatingExists)
+		}
+	}
+
+	r.ratings[rating.ID] = copyRating(rating)
+	return nil
+}
+
+// GetByID получает оценку по ID
+func (r *RatingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverRating, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rating, ok := r.ratings[id]
+	if !ok {
+		return nil, entities.ErrRatingNotFound
+	}
+	return copyRating(rating), nil
+}
+
+// GetByDriverID получает последние оценки водителя
+func (r *RatingRepository) GetByDriverID(ctx context.Context, driverID uuid.UUID, limit int) ([]*entities.DriverRating, error) {
+	return r.List(ctx, &entities.RatingFilters{DriverID: &driverID, Limit: limit})
+}
+
+// Update обновляет оценку
+func (r *RatingRepository) Update(ctx context.Context, rating *entities.DriverRating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ratings[rating.ID]; !ok {
+		return entities.ErrRatingNotFound
+	}
+
+	rating.UpdatedAt = time.Now()
+	r.ratings[rating.ID] = copyRating(rating)
+	return nil
+}
+
+// EraseComments удаляет комментарии к оценкам водителя
+func (r *RatingRepository) EraseComments(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	erased := 0
+	now := time.Now()
+	for _, rating := range r.ratings {
+		if rating.DriverID == driverID && rating.Comment != nil {
+			rating.Comment = nil
+			rating.UpdatedAt = now
+			erased++
+		}
+	}
+	return erased, nil
+}
+
+// Delete удаляет оценку
+func (r *RatingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.ratings[id]; !ok {
+		return entities.ErrRatingNotFound
+	}
+
+	delete(r.ratings, id)
+	return nil
+}
+
+// List получает список оценок с фильтрами
+func (r *RatingRepository) List(ctx context.Context, filters *entities.RatingFilters) ([]*entities.DriverRating, error) {
+	ratings := r.filter(filters)
+	if filters == nil {
+		return ratings, nil
+	}
+
+	sortRatings(ratings, filters.SortBy, filters.SortDirection)
+	return paginate(ratings, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество оценок с фильтрами
+func (r *RatingRepository) Count(ctx context.Context, filters *entities.RatingFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// GetStats вычисляет статистику оценок водителя
+func (r *RatingRepository) GetStats(ctx context.Context, driverID uuid.UUID) (*entities.RatingStats, error) {
+	stats := entities.NewRatingStats(driverID)
+	stats.Calculate(r.filter(&entities.RatingFilters{DriverID: &driverID}))
+	return stats, nil
+}
+
+// filter возвращает копии оценок по фильтрам
+func (r *RatingRepository) filter(filters *entities.RatingFilters) []*entities.DriverRating {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverRating, 0)
+	for _, rating := range r.ratings {
+		if matchRating(rating, filters) {
+			result = append(result, copyRating(rating))
+		}
+	}
+
+	sortRatings(result, "", "")
+	return result
+}
+
+// matchRating проверяет оценку на соответствие фильтрам
+func matchRating(rating *entities.DriverRating, filters *entities.RatingFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && rating.DriverID != *filters.DriverID {
+		return false
+	}
+	if filters.CustomerID != nil && (rating.CustomerID == nil || *rating.CustomerID != *filters.CustomerID) {
+		return false
+	}
+	if filters.OrderID != nil && (rating.OrderID == nil || *rating.OrderID != *filters.OrderID) {
+		return false
+	}
+
+	if len(filters.RatingType) > 0 {
+		found := false
+		for _, ratingType := range filters.RatingType {
+			if rating.RatingType == ratingType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.MinRating != nil && rating.Rating < *filters.MinRating {
+		return false
+	}
+	if filters.MaxRating != nil && rating.Rating > *filters.MaxRating {
+		return false
+	}
+	if filters.IsVerified != nil && rating.IsVerified != *filters.IsVerified {
+		return false
+	}
+	if filters.From != nil && rating.CreatedAt.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && rating.CreatedAt.After(*filters.To) {
+		return false
+	}
+
+	return true
+}
+
+// sameOrderRating проверяет, относятся ли оценки к одному заказу одного клиента
+func sameOrderRating(a, b *entities.DriverRating) bool {
+	if a.OrderID == nil || b.OrderID == nil || a.CustomerID == nil || b.CustomerID == nil {
+		return false
+	}
+	return *a.OrderID == *b.OrderID && *a.CustomerID == *b.CustomerID
+}
+
+// sortRatings сортирует оценки; без sortBy используется created_at DESC
+func sortRatings(ratings []*entities.DriverRating, sortBy, direction string) {
+	if sortBy == "" {
+		sortBy, direction = "created_at", "desc"
+	} else if direction != "desc" {
+		direction = "asc"
+	}
+
+	compare := func(a, b *entities.DriverRating) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	if sortBy == "rating" {
+		compare = func(a, b *entities.DriverRating) int { return cmp.Compare(a.Rating, b.Rating) }
+	}
+
+	sortItems(ratings, compare, direction)
+}
+
+// copyRating возвращает независимую копию оценки
+func copyRating(rating *entities.DriverRating) *entities.DriverRating {
+	clone := *rating
+	clone.Metadata = cloneMetadata(rating.Metadata)
+	if rating.CriteriaScores != nil {
+		clone.CriteriaScores = make(entities.CriteriaScores, len(rating.CriteriaScores))
+		for k, v := range rating.CriteriaScores {
+			clone.CriteriaScores[k] = v
+		}
+	}
+	return &clone
+}
@@ -0,0 +1,167 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ReferralRepository in-memory реализация repositories.ReferralRepository
+type ReferralRepository struct {
+	mu        sync.RWMutex
+	codes     map[uuid.UUID]*entities.ReferralCode
+	referrals map[uuid.UUID]*entities.Referral
+}
+
+var _ repositories.ReferralRepository = (*ReferralRepository)(nil)
+
+// NewReferralRepository создает новый in-memory репозиторий рефералов
+func NewReferralRepository() *ReferralRepository {
+	return &ReferralRepository{
+		codes:     make(map[uuid.UUID]*entities.ReferralCode),
+		referrals: make(map[uuid.UUID]*entities.Referral),
+	}
+}
+
+// GetCode получает реферальный код водителя
+func (r *ReferralRepository) GetCode(ctx context.Context, driverID uuid.UUID) (*entities.ReferralCode, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	code, ok := r.codes[driverID]
+	if !ok {
+		return nil, entities.ErrReferralCodeNotFound
+	}
+	clone := *code
+	return &clone, nil
+}
+
+// CreateCode сохраняет реферальный код водителя
+func (r *ReferralRepository) CreateCode(ctx context.Context, code *entities.ReferralCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.codes[code.DriverID]; ok {
+		return entities.ErrReferralCodeTaken
+	}
+	for _, existing := range r.codes {
+		if existing.Code == code.Code {
+			return entities.ErrReferralCodeTaken
+		}
+	}
+
+	clone := *code
+	r.codes[code.DriverID] = &clone
+	return nil
+}
+
+// Create сохраняет реферал
+func (r *ReferralRepository) Create(ctx context.Context, referral *entities.Referral) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.referrals {
+		if existing.ReferredDriverID == referral.ReferredDriverID {
+			return entities.ErrReferralExists
+		}
+	}
+
+	r.referrals[referral.ID] = copyReferral(referral)
+	return nil
+}
+
+// GetByReferredDriver получает реферал приглашенного водителя
+func (r *ReferralRepository) GetByReferredDriver(ctx context.Context, driverID uuid.UUID) (*entities.Referral, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, referral := range r.referrals {
+		if referral.ReferredDriverID == driverID {
+			return copyReferral(referral), nil
+		}
+	}
+	return nil, entities.ErrReferralNotFound
+}
+
+// ListByReferrer получает рефералы пригласившего водителя, последние первыми
+func (r *ReferralRepository) ListByReferrer(ctx context.Context, referrerID uuid.UUID) ([]*entities.Referral, error) {
+	referrals := r.filter(func(referral *entities.Referral) bool {
+		return referral.ReferrerID == referrerID
+	})
+	sort.Slice(referrals, func(i, j int) bool {
+		return referrals[i].RegisteredAt.After(referrals[j].RegisteredAt)
+	})
+	return referrals, nil
+}
+
+// ListOpen получает страницу незавершенных рефералов по возрастанию ID
+func (r *ReferralRepository) ListOpen(ctx context.Context, after uuid.UUID, limit int) ([]*entities.Referral, error) {
+	referrals := r.filter(func(referral *entities.Referral) bool {
+		return referral.IsOpen() && bytes.Compare(referral.ID[:], after[:]) > 0
+	})
+	sort.Slice(referrals, func(i, j int) bool {
+		return bytes.Compare(referrals[i].ID[:], referrals[j].ID[:]) < 0
+	})
+	return paginate(referrals, limit, 0), nil
+}
+
+// UpdateProgress сохраняет прогресс реферала, если его этап не изменился
+func (r *ReferralRepository) UpdateProgress(ctx context.Context, referral *entities.Referral, from entities.ReferralStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	current, ok := r.referrals[referral.ID]
+	if !ok || current.Status != from {
+		return false, nil
+	}
+
+	r.referrals[referral.ID] = copyReferral(referral)
+	return true, nil
+}
+
+// ListCompleted получает рефералы, завершенные в [from, to), в порядке завершения
+func (r *ReferralRepository) ListCompleted(ctx context.Context, from, to time.Time) ([]*entities.Referral, error) {
+	referrals := r.filter(func(referral *entities.Referral) bool {
+		return referral.Status == entities.ReferralStatusCompleted && referral.CompletedAt != nil &&
+			!referral.CompletedAt.Before(from) && referral.CompletedAt.Before(to)
+	})
+	sort.Slice(referrals, func(i, j int) bool {
+		return referrals[i].CompletedAt.Before(*referrals[j].CompletedAt)
+	})
+	return referrals, nil
+}
+
+// filter возвращает копии рефералов, удовлетворяющих условию
+func (r *ReferralRepository) filter(match func(referral *entities.Referral) bool) []*entities.Referral {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	referrals := make([]*entities.Referral, 0)
+	for _, referral := range r.referrals {
+		if match(referral) {
+			referrals = append(referrals, copyReferral(referral))
+		}
+	}
+	return referrals
+}
+
+// copyReferral возвращает независимую копию реферала
+func copyReferral(referral *entities.Referral) *entities.Referral {
+	clone := *referral
+	if referral.VerifiedAt != nil {
+		verifiedAt := *referral.VerifiedAt
+		clone.VerifiedAt = &verifiedAt
+	}
+	if referral.CompletedAt != nil {
+		completedAt := *referral.CompletedAt
+		clone.CompletedAt = &completedAt
+	}
+	return &clone
+}
@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ScheduleRepository in-memory реализация repositories.ScheduleRepository
+type ScheduleRepository struct {
+	mu        sync.RWMutex
+	schedules map[uuid.UUID]*entities.DriverSchedule
+}
+
+var _ repositories.ScheduleRepository = (*ScheduleRepository)(nil)
+
+// NewScheduleRepository создает новый in-memory репозиторий расписаний
+func NewScheduleRepository() *ScheduleRepository {
+	return &ScheduleRepository{
+		schedules: make(map[uuid.UUID]*entities.DriverSchedule),
+	}
+}
+
+// GetByDriverID получает расписание водителя
+func (r *ScheduleRepository) GetByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schedule, ok := r.schedules[driverID]
+	if !ok {
+		return nil, entities.ErrScheduleNotFound
+	}
+	return copySchedule(schedule), nil
+}
+
+// Upsert сохраняет расписание водителя; дата создания прежнего расписания сохраняется
+func (r *ScheduleRepository) Upsert(ctx context.Context, schedule *entities.DriverSchedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := copySchedule(schedule)
+	if existing, ok := r.schedules[schedule.DriverID]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	}
+	r.schedules[schedule.DriverID] = stored
+	return nil
+}
+
+// Delete удаляет расписание водителя
+func (r *ScheduleRepository) Delete(ctx context.Context, driverID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.schedules[driverID]; !ok {
+		return entities.ErrScheduleNotFound
+	}
+	delete(r.schedules, driverID)
+	return nil
+}
+
+// List получает страницу расписаний по возрастанию ID водителя
+func (r *ScheduleRepository) List(ctx context.Context, afterDriverID uuid.UUID, limit int) ([]*entities.DriverSchedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	after := afterDriverID.String()
+	result := make([]*entities.DriverSchedule, 0)
+	for driverID, schedule := range r.schedules {
+		if driverID.String() > after {
+			result = append(result, copySchedule(schedule))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DriverID.String() < result[j].DriverID.String()
+	})
+	return paginate(result, limit, 0), nil
+}
+
+// copySchedule возвращает независимую копию расписания
+func copySchedule(schedule *entities.DriverSchedule) *entities.DriverSchedule {
+	clone := *schedule
+	clone.Windows = make(entities.AvailabilityWindowList, len(schedule.Windows))
+	for i, window := range schedule.Windows {
+		clone.Windows[i] = window
+		clone.Windows[i].Days = append([]string(nil), window.Days...)
+	}
+	return &clone
+}
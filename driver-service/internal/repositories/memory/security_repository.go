@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// SecurityRepository in-memory реализация repositories.SecurityRepository
+type SecurityRepository struct {
+	mu       sync.RWMutex
+	events   map[uuid.UUID]*entities.SecurityEvent
+	sessions map[string]*entities.DriverSession
+}
+
+var _ repositories.SecurityRepository = (*SecurityRepository)(nil)
+
+// NewSecurityRepository создает новый in-memory репозиторий событий безопасности
+func NewSecurityRepository() *SecurityRepository {
+	return &SecurityRepository{
+		events:   make(map[uuid.UUID]*entities.SecurityEvent),
+		sessions: make(map[string]*entities.DriverSession),
+	}
+}
+
+// CreateEvent сохраняет событие безопасности
+func (r *SecurityRepository) CreateEvent(ctx context.Context, event *entities.SecurityEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.events[event.ID]; !exists {
+		r.events[event.ID] = copySecurityEvent(event)
+	}
+	return nil
+}
+
+// GetEvent получает событие безопасности по ID
+func (r *SecurityRepository) GetEvent(ctx context.Context, id uuid.UUID) (*entities.SecurityEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	event, ok := r.events[id]
+	if !ok {
+		return nil, entities.ErrSecurityEventNotFound
+	}
+	return copySecurityEvent(event), nil
+}
+
+// UpdateEvent сохраняет рассмотрение события
+func (r *SecurityRepository) UpdateEvent(ctx context.Context, event *entities.SecurityEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.events[event.ID]; !ok {
+		return entities.ErrSecurityEventNotFound
+	}
+	r.events[event.ID] = copySecurityEvent(event)
+	return nil
+}
+
+// ListEvents получает события безопасности по фильтрам, новые первыми
+func (r *SecurityRepository) ListEvents(ctx context.Context, filters *entities.SecurityEventFilters) ([]*entities.SecurityEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.SecurityEvent
+	for _, event := range r.events {
+		if filters.DriverID != nil && event.DriverID != *filters.DriverID {
+			continue
+		}
+		if filters.Type != "" && event.Type != filters.Type {
+			continue
+		}
+		if filters.Status != "" && event.Status != filters.Status {
+			continue
+		}
+		if filters.Since != nil && event.CreatedAt.Before(*filters.Since) {
+			continue
+		}
+		result = append(result, copySecurityEvent(event))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.After(result[j].CreatedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
+
+// GetSession получает сессию по ID
+func (r *SecurityRepository) GetSession(ctx context.Context, id string) (*entities.DriverSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, entities.ErrSessionNotFound
+	}
+	return copyDriverSession(session), nil
+}
+
+// SaveSession создает или обновляет сессию; отзыв сессии не снимается
+func (r *SecurityRepository) SaveSession(ctx context.Context, session *entities.DriverSession) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.sessions[session.ID]
+	if !ok {
+		r.sessions[session.ID] = copyDriverSession(session)
+		return nil
+	}
+
+	existing.Country = session.Country
+	existing.IPAddress = session.IPAddress
+	if session.LastSeenAt.After(existing.LastSeenAt) {
+		existing.LastSeenAt = session.LastSeenAt
+	}
+	return nil
+}
+
+// ListSessions получает сессии водителя, активные после since
+func (r *SecurityRepository) ListSessions(ctx context.Context, driverID uuid.UUID, since time.Time) ([]*entities.DriverSession, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.DriverSession
+	for _, session := range r.sessions {
+		if session.DriverID == driverID && !session.LastSeenAt.Before(since) {
+			result = append(result, copyDriverSession(session))
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastSeenAt.After(result[j].LastSeenAt)
+	})
+	return result, nil
+}
+
+// RevokeSessions отзывает действующие сессии водителя
+func (r *SecurityRepository) RevokeSessions(ctx context.Context, driverID uuid.UUID, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revoked := 0
+	for _, session := range r.sessions {
+		if session.DriverID == driverID && session.RevokedAt == nil {
+			revokedAt := at
+			session.RevokedAt = &revokedAt
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// RevokeDeviceSessions отзывает действующие сессии водителя с одного устройства
+func (r *SecurityRepository) RevokeDeviceSessions(ctx context.Context, driverID uuid.UUID, deviceID string, at time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	revoked := 0
+	for _, session := range r.sessions {
+		if session.DriverID == driverID && session.DeviceID == deviceID && session.RevokedAt == nil {
+			revokedAt := at
+			session.RevokedAt = &revokedAt
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// copySecurityEvent возвращает независимую копию события
+func copySecurityEvent(event *entities.SecurityEvent) *entities.SecurityEvent {
+	clone := *event
+	clone.Details = cloneMetadata(event.Details)
+	return &clone
+}
+
+// copyDriverSession возвращает независимую копию сессии
+func copyDriverSession(session *entities.DriverSession) *entities.DriverSession {
+	clone := *session
+	return &clone
+}
@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ShiftBreakRepository in-memory реализация repositories.ShiftBreakRepository
+type ShiftBreakRepository struct {
+	mu     sync.RWMutex
+	breaks map[uuid.UUID]*entities.ShiftBreak
+}
+
+var _ repositories.ShiftBreakRepository = (*ShiftBreakRepository)(nil)
+
+// NewShiftBreakRepository создает новый in-memory репозиторий перерывов в сменах
+func NewShiftBreakRepository() *ShiftBreakRepository {
+	return &ShiftBreakRepository{
+		breaks: make(map[uuid.UUID]*entities.ShiftBreak),
+	}
+}
+
+// Create сохраняет перерыв; второй идущий перерыв в смене не сохраняется
+func (r *ShiftBreakRepository) Create(ctx context.Context, shiftBreak *entities.ShiftBreak) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if shiftBreak.IsOpen() {
+		for _, existing := range r.breaks {
+			if existing.ShiftID == shiftBreak.ShiftID && existing.IsOpen() {
+				return entities.ErrShiftBreakActive
+			}
+		}
+	}
+
+	r.breaks[shiftBreak.ID] = copyShiftBreak(shiftBreak)
+	return nil
+}
+
+// Update сохраняет окончание перерыва
+func (r *ShiftBreakRepository) Update(ctx context.Context, shiftBreak *entities.ShiftBreak) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.breaks[shiftBreak.ID]; !ok {
+		return entities.ErrShiftBreakNotActive
+	}
+
+	r.breaks[shiftBreak.ID] = copyShiftBreak(shiftBreak)
+	return nil
+}
+
+// ListByShift возвращает перерывы смены в порядке начала
+func (r *ShiftBreakRepository) ListByShift(ctx context.Context, shiftID uuid.UUID) ([]*entities.ShiftBreak, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.ShiftBreak, 0)
+	for _, shiftBreak := range r.breaks {
+		if shiftBreak.ShiftID == shiftID {
+			result = append(result, copyShiftBreak(shiftBreak))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].StartedAt.Equal(result[j].StartedAt) {
+			return result[i].StartedAt.Before(result[j].StartedAt)
+		}
+		return result[i].ID.String() < result[j].ID.String()
+	})
+	return result, nil
+}
+
+// copyShiftBreak возвращает независимую копию перерыва
+func copyShiftBreak(shiftBreak *entities.ShiftBreak) *entities.ShiftBreak {
+	clone := *shiftBreak
+	if shiftBreak.EndedAt != nil {
+		endedAt := *shiftBreak.EndedAt
+		clone.EndedAt = &endedAt
+	}
+	return &clone
+}
@@ -0,0 +1,215 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ShiftRepository in-memory реализация repositories.ShiftRepository
+type ShiftRepository struct {
+	mu     sync.RWMutex
+	shifts map[uuid.UUID]*entities.DriverShift
+}
+
+var _ repositories.ShiftRepository = (*ShiftRepository)(nil)
+
+// NewShiftRepository создает новый in-memory репозиторий смен
+func NewShiftRepository() *ShiftRepository {
+	return &ShiftRepository{
+		shifts: make(map[uuid.UUID]*entities.DriverShift),
+	}
+}
+
+// Create создает новую смену
+func (r *ShiftRepository) Create(ctx context.Context, shift *entities.DriverShift) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Не более одной активной смены на водителя, как и частичный уникальный индекс
+	for _, existing := range r.shifts {
+		if existing.DriverID == shift.DriverID && existing.IsActive() && shift.IsActive() {
+			return fmt.Errorf("failed to create shift: %w", entities.ErrShiftExists)
+		}
+	}
+
+	r.shifts[shift.ID] = copyShift(shift)
+	return nil
+}
+
+// GetByID получает смену по ID
+func (r *ShiftRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverShift, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	shift, ok := r.shifts[id]
+	if !ok {
+		return nil, entities.ErrShiftNotFound
+	}
+	return copyShift(shift), nil
+}
+
+// GetActiveByDriverID получает активную смену водителя
+func (r *ShiftRepository) GetActiveByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, shift := range r.shifts {
+		if shift.DriverID == driverID && shift.IsActive() {
+			return copyShift(shift), nil
+		}
+	}
+	return nil, entities.ErrShiftNotFound
+}
+
+// Update обновляет смену
+func (r *ShiftRepository) Update(ctx context.Context, shift *entities.DriverShift) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.shifts[shift.ID]; !ok {
+		return entities.ErrShiftNotFound
+	}
+
+	shift.UpdatedAt = time.Now()
+	r.shifts[shift.ID] = copyShift(shift)
+	return nil
+}
+
+// List получает список смен с фильтрами
+func (r *ShiftRepository) List(ctx context.Context, filters *entities.ShiftFilters) ([]*entities.DriverShift, error) {
+	shifts := r.filter(filters)
+	if filters == nil {
+		return shifts, nil
+	}
+
+	sortShifts(shifts, filters.SortBy, filters.SortDirection)
+	return paginate(shifts, filters.Limit, filters.Offset), nil
+}
+
+// Count возвращает количество смен с фильтрами
+func (r *ShiftRepository) Count(ctx context.Context, filters *entities.ShiftFilters) (int, error) {
+	return len(r.filter(filters)), nil
+}
+
+// EraseLocations удаляет координаты начала и окончания смен водителя
+func (r *ShiftRepository) EraseLocations(ctx context.Context, driverID uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	erased := 0
+	now := time.Now()
+	for _, shift := range r.shifts {
+		if shift.DriverID != driverID || (shift.StartLatitude == nil && shift.EndLatitude == nil) {
+			continue
+		}
+		shift.StartLatitude, shift.StartLongitude = nil, nil
+		shift.EndLatitude, shift.EndLongitude = nil, nil
+		shift.UpdatedAt = now
+		erased++
+	}
+	return erased, nil
+}
+
+// filter возвращает копии смен по фильтрам
+func (r *ShiftRepository) filter(filters *entities.ShiftFilters) []*entities.DriverShift {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.DriverShift, 0)
+	for _, shift := range r.shifts {
+		if matchShift(shift, filters) {
+			result = append(result, copyShift(shift))
+		}
+	}
+
+	sortShifts(result, "", "")
+	return result
+}
+
+// matchShift проверяет смену на соответствие фильтрам
+func matchShift(shift *entities.DriverShift, filters *entities.ShiftFilters) bool {
+	if filters == nil {
+		return true
+	}
+
+	if filters.DriverID != nil && shift.DriverID != *filters.DriverID {
+		return false
+	}
+	if filters.VehicleID != nil && (shift.VehicleID == nil || *shift.VehicleID != *filters.VehicleID) {
+		return false
+	}
+
+	if len(filters.Status) > 0 {
+		found := false
+		for _, status := range filters.Status {
+			if shift.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filters.From != nil && shift.StartTime.Before(*filters.From) {
+		return false
+	}
+	if filters.To != nil && shift.StartTime.After(*filters.To) {
+		return false
+	}
+	if filters.MinEarnings != nil && shift.TotalEarnings < *filters.MinEarnings {
+		return false
+	}
+	if filters.MaxEarnings != nil && shift.TotalEarnings > *filters.MaxEarnings {
+		return false
+	}
+	if filters.MinTrips != nil && shift.TotalTrips < *filters.MinTrips {
+		return false
+	}
+	if filters.MaxTrips != nil && shift.TotalTrips > *filters.MaxTrips {
+		return false
+	}
+
+	return true
+}
+
+// sortShifts сортирует смены; без sortBy используется start_time DESC
+func sortShifts(shifts []*entities.DriverShift, sortBy, direction string) {
+	if sortBy == "" {
+		sortBy, direction = "start_time", "desc"
+	} else if direction != "desc" {
+		direction = "asc"
+	}
+
+	var compare func(a, b *entities.DriverShift) int
+	switch sortBy {
+	case "total_trips":
+		compare = func(a, b *entities.DriverShift) int { return cmp.Compare(a.TotalTrips, b.TotalTrips) }
+	case "total_distance":
+		compare = func(a, b *entities.DriverShift) int { return cmp.Compare(a.TotalDistance, b.TotalDistance) }
+	case "total_earnings":
+		compare = func(a, b *entities.DriverShift) int { return cmp.Compare(a.TotalEarnings, b.TotalEarnings) }
+	case "created_at":
+		compare = func(a, b *entities.DriverShift) int { return a.CreatedAt.Compare(b.CreatedAt) }
+	default:
+		compare = func(a, b *entities.DriverShift) int { return a.StartTime.Compare(b.StartTime) }
+	}
+
+	sortItems(shifts, compare, direction)
+}
+
+// copyShift возвращает независимую копию смены
+func copyShift(shift *entities.DriverShift) *entities.DriverShift {
+	clone := *shift
+	clone.Metadata = cloneMetadata(shift.Metadata)
+	return &clone
+}
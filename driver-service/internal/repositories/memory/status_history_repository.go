@@ -0,0 +1,48 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+)
+
+// StatusHistoryRepository in-memory реализация repositories.StatusHistoryRepository: читает
+// историю, которую DriverRepository записывает при смене статуса
+type StatusHistoryRepository struct {
+	drivers *DriverRepository
+}
+
+var _ repositories.StatusHistoryRepository = (*StatusHistoryRepository)(nil)
+
+// NewStatusHistoryRepository создает новый in-memory репозиторий истории статусов
+func NewStatusHistoryRepository(drivers *DriverRepository) *StatusHistoryRepository {
+	return &StatusHistoryRepository{drivers: drivers}
+}
+
+// List получает смены статуса водителя в порядке времени
+func (r *StatusHistoryRepository) List(ctx context.Context, filters *entities.StatusHistoryFilters) ([]*entities.StatusTransition, error) {
+	r.drivers.mu.RLock()
+	defer r.drivers.mu.RUnlock()
+
+	var result []*entities.StatusTransition
+	for _, transition := range r.drivers.history {
+		if transition.DriverID != filters.DriverID {
+			continue
+		}
+		if filters.From != nil && transition.ChangedAt.Before(*filters.From) {
+			continue
+		}
+		if filters.To != nil && !transition.ChangedAt.Before(*filters.To) {
+			continue
+		}
+		copied := *transition
+		result = append(result, &copied)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ChangedAt.Before(result[j].ChangedAt)
+	})
+	return paginate(result, filters.Limit, filters.Offset), nil
+}
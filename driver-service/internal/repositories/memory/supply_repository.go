@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// SupplyRepository in-memory реализация repositories.SupplyRepository.
+// Количества считаются на лету по in-memory репозиториям водителей и местоположений
+type SupplyRepository struct {
+	drivers   *DriverRepository
+	locations *LocationRepository
+}
+
+var _ repositories.SupplyRepository = (*SupplyRepository)(nil)
+
+// NewSupplyRepository создает новый in-memory репозиторий карты предложения
+func NewSupplyRepository(drivers *DriverRepository, locations *LocationRepository) *SupplyRepository {
+	return &SupplyRepository{
+		drivers:   drivers,
+		locations: locations,
+	}
+}
+
+// CountByCell считает водителей по ячейкам geohash и статусам по их последним точкам
+func (r *SupplyRepository) CountByCell(ctx context.Context, query *entities.SupplyHeatmapQuery) ([]*entities.SupplyCellCount, error) {
+	latest := make(map[uuid.UUID]*entities.DriverLocation)
+	for _, location := range r.locations.filter(&entities.LocationFilters{From: &query.Since}) {
+		if current, ok := latest[location.DriverID]; !ok || location.RecordedAt.After(current.RecordedAt) {
+			latest[location.DriverID] = location
+		}
+	}
+
+	type cellStatus struct {
+		geohash string
+		status  entities.Status
+	}
+	counts := make(map[cellStatus]*entities.SupplyCellCount)
+	for driverID, location := range latest {
+		if !query.Bounds.Contains(location.Latitude, location.Longitude) {
+			continue
+		}
+		driver, err := r.drivers.GetByID(ctx, driverID)
+		if err != nil {
+			// Удаленные водители на карту не попадают
+			continue
+		}
+
+		key := cellStatus{entities.EncodeGeohash(location.Latitude, location.Longitude, query.Precision), driver.Status}
+		if _, ok := counts[key]; !ok {
+			counts[key] = &entities.SupplyCellCount{Geohash: key.geohash, Status: key.status}
+		}
+		counts[key].Drivers++
+	}
+
+	result := make([]*entities.SupplyCellCount, 0, len(counts))
+	for _, count := range counts {
+		result = append(result, count)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Geohash != result[j].Geohash {
+			return result[i].Geohash < result[j].Geohash
+		}
+		return result[i].Status < result[j].Status
+	})
+	return result, nil
+}
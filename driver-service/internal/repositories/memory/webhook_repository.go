@@ -0,0 +1,183 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// WebhookRepository in-memory реализация repositories.WebhookRepository
+type WebhookRepository struct {
+	mu            sync.RWMutex
+	subscriptions map[uuid.UUID]*entities.WebhookSubscription
+	deliveries    map[uuid.UUID]*entities.WebhookDelivery
+}
+
+var _ repositories.WebhookRepository = (*WebhookRepository)(nil)
+
+// NewWebhookRepository создает новый in-memory репозиторий вебхуков
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		subscriptions: make(map[uuid.UUID]*entities.WebhookSubscription),
+		deliveries:    make(map[uuid.UUID]*entities.WebhookDelivery),
+	}
+}
+
+// CreateSubscription сохраняет подписку
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscriptions[subscription.ID] = copyWebhookSubscription(subscription)
+	return nil
+}
+
+// GetSubscription получает подписку по ID
+func (r *WebhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	subscription, ok := r.subscriptions[id]
+	if !ok {
+		return nil, entities.ErrWebhookNotFound
+	}
+	return copyWebhookSubscription(subscription), nil
+}
+
+// ListSubscriptions получает все подписки
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*entities.WebhookSubscription, 0, len(r.subscriptions))
+	for _, subscription := range r.subscriptions {
+		result = append(result, copyWebhookSubscription(subscription))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+// DeleteSubscription удаляет подписку вместе с журналом ее доставок
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.subscriptions[id]; !ok {
+		return entities.ErrWebhookNotFound
+	}
+	delete(r.subscriptions, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.SubscriptionID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// CreateDeliveries сохраняет доставки
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, delivery := range deliveries {
+		if _, ok := r.deliveries[delivery.ID]; !ok {
+			r.deliveries[delivery.ID] = copyWebhookDelivery(delivery)
+		}
+	}
+	return nil
+}
+
+// GetDelivery получает доставку по ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*entities.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, entities.ErrWebhookDeliveryNotFound
+	}
+	return copyWebhookDelivery(delivery), nil
+}
+
+// ListDeliveries получает журнал доставок подписки, начиная с новых
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, filters *entities.WebhookDeliveryFilters) ([]*entities.WebhookDelivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []*entities.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.SubscriptionID != filters.SubscriptionID {
+			continue
+		}
+		if filters.Status != "" && delivery.Status != filters.Status {
+			continue
+		}
+		result = append(result, copyWebhookDelivery(delivery))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.After(result[j].CreatedAt) })
+
+	if filters.Offset > 0 {
+		if filters.Offset >= len(result) {
+			return nil, nil
+		}
+		result = result[filters.Offset:]
+	}
+	if filters.Limit > 0 && len(result) > filters.Limit {
+		result = result[:filters.Limit]
+	}
+	return result, nil
+}
+
+// UpdateDelivery сохраняет итог попытки доставки
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return entities.ErrWebhookDeliveryNotFound
+	}
+	r.deliveries[delivery.ID] = copyWebhookDelivery(delivery)
+	return nil
+}
+
+// ClaimDueDeliveries захватывает ожидающие доставки с наступившим временем попытки
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var due []*entities.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == entities.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	result := make([]*entities.WebhookDelivery, 0, len(due))
+	for _, delivery := range due {
+		delivery.NextAttemptAt = now.Add(lease)
+		result = append(result, copyWebhookDelivery(delivery))
+	}
+	return result, nil
+}
+
+func copyWebhookSubscription(subscription *entities.WebhookSubscription) *entities.WebhookSubscription {
+	clone := *subscription
+	clone.EventTypes = append(entities.WebhookEventTypeList(nil), subscription.EventTypes...)
+	return &clone
+}
+
+func copyWebhookDelivery(delivery *entities.WebhookDelivery) *entities.WebhookDelivery {
+	clone := *delivery
+	clone.Payload = append(entities.WebhookPayload(nil), delivery.Payload...)
+	return &clone
+}
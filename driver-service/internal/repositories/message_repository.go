@@ -0,0 +1,201 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// MessageRepository интерфейс для переписки водителей с диспетчерами
+type MessageRepository interface {
+	Create(ctx context.Context, message *entities.DriverMessage) error
+	// GetByIDs получает сообщения водителя по ID; чужие и несуществующие ID пропускаются
+	GetByIDs(ctx context.Context, driverID uuid.UUID, ids []uuid.UUID) ([]*entities.DriverMessage, error)
+	// List возвращает сообщения переписки, новые первыми
+	List(ctx context.Context, filters *entities.MessageFilters) ([]*entities.DriverMessage, error)
+	Count(ctx context.Context, filters *entities.MessageFilters) (int, error)
+	// UpdateReceipt сохраняет отметки о доставке и прочтении
+	UpdateReceipt(ctx context.Context, message *entities.DriverMessage) error
+	// ListByDriverID возвращает всю переписку водителя в хронологическом порядке
+	ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverMessage, error)
+	DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error)
+	// DeleteOlderThan удаляет сообщения, отправленные до before
+	DeleteOlderThan(ctx context.Context, before time.Time) (int, error)
+}
+
+// messageRepository реализация MessageRepository
+type messageRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewMessageRepository создает новый репозиторий сообщений
+func NewMessageRepository(db *database.DB, logger *zap.Logger) MessageRepository {
+	return &messageRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет сообщение
+func (r *messageRepository) Create(ctx context.Context, message *entities.DriverMessage) error {
+	query := `
+		INSERT INTO driver_messages (
+			id, driver_id, order_id, sender, sender_id, kind, delay_minutes, text,
+			created_at, delivered_at, read_at
+		) VALUES (
+			:id, :driver_id, :order_id, :sender, :sender_id, :kind, :delay_minutes, :text,
+			:created_at, :delivered_at, :read_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, message); err != nil {
+		r.logger.Error("Failed to create driver message",
+			zap.Error(err),
+			zap.String("driver_id", message.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create driver message: %w", err)
+	}
+
+	return nil
+}
+
+// GetByIDs получает сообщения водителя по ID
+func (r *messageRepository) GetByIDs(ctx context.Context, driverID uuid.UUID, ids []uuid.UUID) ([]*entities.DriverMessage, error) {
+	var messages []*entities.DriverMessage
+	query := `SELECT * FROM driver_messages WHERE driver_id = $1 AND id = ANY($2)`
+	if err := r.db.SelectContext(ctx, &messages, query, driverID, pq.Array(ids)); err != nil {
+		r.logger.Error("Failed to get driver messages",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get driver messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// List получает страницу переписки
+func (r *messageRepository) List(ctx context.Context, filters *entities.MessageFilters) ([]*entities.DriverMessage, error) {
+	where, args := messageThreadCondition(filters)
+	query := fmt.Sprintf(`
+		SELECT * FROM driver_messages
+		WHERE %s
+		ORDER BY created_at DESC, id DESC
+		LIMIT $%d OFFSET $%d`, where, len(args)+1, len(args)+2)
+	args = append(args, filters.Limit, filters.Offset)
+
+	var messages []*entities.DriverMessage
+	if err := r.db.SelectContext(ctx, &messages, query, args...); err != nil {
+		r.logger.Error("Failed to list driver messages",
+			zap.Error(err),
+			zap.String("driver_id", filters.DriverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// Count считает сообщения переписки
+func (r *messageRepository) Count(ctx context.Context, filters *entities.MessageFilters) (int, error) {
+	where, args := messageThreadCondition(filters)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM driver_messages WHERE `+where, args...); err != nil {
+		r.logger.Error("Failed to count driver messages",
+			zap.Error(err),
+			zap.String("driver_id", filters.DriverID.String()),
+		)
+		return 0, fmt.Errorf("failed to count driver messages: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateReceipt сохраняет отметки о доставке и прочтении
+func (r *messageRepository) UpdateReceipt(ctx context.Context, message *entities.DriverMessage) error {
+	query := `UPDATE driver_messages SET delivered_at = $2, read_at = $3 WHERE id = $1`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, message.ID, message.DeliveredAt, message.ReadAt)
+	if err != nil {
+		r.logger.Error("Failed to update message receipt",
+			zap.Error(err),
+			zap.String("message_id", message.ID.String()),
+		)
+		return fmt.Errorf("failed to update message receipt: %w", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if updated == 0 {
+		return entities.ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// ListByDriverID получает всю переписку водителя
+func (r *messageRepository) ListByDriverID(ctx context.Context, driverID uuid.UUID) ([]*entities.DriverMessage, error) {
+	var messages []*entities.DriverMessage
+	query := `SELECT * FROM driver_messages WHERE driver_id = $1 ORDER BY created_at ASC, id ASC`
+	if err := r.db.SelectContext(ctx, &messages, query, driverID); err != nil {
+		r.logger.Error("Failed to list all driver messages",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list all driver messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// DeleteByDriverID удаляет всю переписку водителя
+func (r *messageRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM driver_messages WHERE driver_id = $1`, driverID)
+	if err != nil {
+		r.logger.Error("Failed to delete driver messages",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to delete driver messages: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// DeleteOlderThan удаляет сообщения старше срока хранения
+func (r *messageRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM driver_messages WHERE created_at < $1`, before)
+	if err != nil {
+		r.logger.Error("Failed to delete old driver messages", zap.Error(err))
+		return 0, fmt.Errorf("failed to delete old driver messages: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(deleted), nil
+}
+
+// messageThreadCondition условие выбора переписки: по заказу или общей
+func messageThreadCondition(filters *entities.MessageFilters) (string, []interface{}) {
+	if filters.OrderID != nil {
+		return "driver_id = $1 AND order_id = $2", []interface{}{filters.DriverID, *filters.OrderID}
+	}
+	return "driver_id = $1 AND order_id IS NULL", []interface{}{filters.DriverID}
+}
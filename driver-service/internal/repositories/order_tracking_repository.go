@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// OrderTrackingRepository интерфейс для отслеживания заказов
+type OrderTrackingRepository interface {
+	// Create сохраняет отслеживание. Если заказ уже отслеживается, возвращается ErrOrderTrackingExists
+	Create(ctx context.Context, session *entities.OrderTrackingSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.OrderTrackingSession, error)
+	// GetActiveByOrder возвращает неостановленное отслеживание заказа, в том числе с истекшей ссылкой
+	GetActiveByOrder(ctx context.Context, orderID uuid.UUID) (*entities.OrderTrackingSession, error)
+	// Stop сохраняет остановку отслеживания; уже остановленное не изменяется и дает
+	// ErrOrderTrackingInactive
+	Stop(ctx context.Context, session *entities.OrderTrackingSession) error
+}
+
+// orderTrackingRepository реализация OrderTrackingRepository
+type orderTrackingRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewOrderTrackingRepository создает новый репозиторий отслеживания заказов
+func NewOrderTrackingRepository(db *database.DB, logger *zap.Logger) OrderTrackingRepository {
+	return &orderTrackingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет отслеживание
+func (r *orderTrackingRepository) Create(ctx context.Context, session *entities.OrderTrackingSession) error {
+	query := `
+		INSERT INTO order_tracking_sessions (id, order_id, driver_id, started_at, expires_at, created_by)
+		VALUES (:id, :order_id, :driver_id, :started_at, :expires_at, :created_by)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, session); err != nil {
+		// Второе отслеживание заказа нарушает idx_order_tracking_sessions_active
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrOrderTrackingExists
+		}
+		r.logger.Error("Failed to create order tracking session",
+			zap.Error(err),
+			zap.String("order_id", session.OrderID.String()),
+		)
+		return fmt.Errorf("failed to create order tracking session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает отслеживание по ID
+func (r *orderTrackingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.OrderTrackingSession, error) {
+	var session entities.OrderTrackingSession
+	if err := r.db.GetContext(ctx, &session, `SELECT * FROM order_tracking_sessions WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrOrderTrackingNotFound
+		}
+		r.logger.Error("Failed to get order tracking session", zap.Error(err), zap.String("session_id", id.String()))
+		return nil, fmt.Errorf("failed to get order tracking session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// GetActiveByOrder получает неостановленное отслеживание заказа
+func (r *orderTrackingRepository) GetActiveByOrder(ctx context.Context, orderID uuid.UUID) (*entities.OrderTrackingSession, error) {
+	query := `SELECT * FROM order_tracking_sessions WHERE order_id = $1 AND stopped_at IS NULL`
+
+	var session entities.OrderTrackingSession
+	if err := r.db.GetContext(ctx, &session, query, orderID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrOrderTrackingNotFound
+		}
+		r.logger.Error("Failed to get active order tracking session", zap.Error(err), zap.String("order_id", orderID.String()))
+		return nil, fmt.Errorf("failed to get active order tracking session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// Stop сохраняет остановку отслеживания, если оно еще не остановлено
+func (r *orderTrackingRepository) Stop(ctx context.Context, session *entities.OrderTrackingSession) error {
+	query := `
+		UPDATE order_tracking_sessions SET stopped_at = :stopped_at
+		WHERE id = :id AND stopped_at IS NULL`
+
+	result, err := r.db.NamedExecContext(ctx, query, session)
+	if err != nil {
+		r.logger.Error("Failed to stop order tracking session",
+			zap.Error(err),
+			zap.String("session_id", session.ID.String()),
+		)
+		return fmt.Errorf("failed to stop order tracking session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrOrderTrackingInactive
+	}
+
+	return nil
+}
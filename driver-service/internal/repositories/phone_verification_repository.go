@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// PhoneVerificationRepository интерфейс для кодов подтверждения телефона
+type PhoneVerificationRepository interface {
+	// Save сохраняет код водителя, заменяя прежний вместе со счетчиком попыток
+	Save(ctx context.Context, verification *entities.PhoneVerification) error
+	// Get получает действующий код водителя; ErrVerificationCodeNotFound, если кода нет
+	Get(ctx context.Context, driverID uuid.UUID) (*entities.PhoneVerification, error)
+	// IncrementAttempts учитывает попытку ввода кода и возвращает число попыток с ней
+	IncrementAttempts(ctx context.Context, driverID uuid.UUID) (int, error)
+	// Delete удаляет код водителя; отсутствие кода не ошибка
+	Delete(ctx context.Context, driverID uuid.UUID) error
+}
+
+// phoneVerificationRepository реализация PhoneVerificationRepository
+type phoneVerificationRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewPhoneVerificationRepository создает новый репозиторий кодов подтверждения телефона
+func NewPhoneVerificationRepository(db *database.DB, logger *zap.Logger) PhoneVerificationRepository {
+	return &phoneVerificationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Save сохраняет код водителя
+func (r *phoneVerificationRepository) Save(ctx context.Context, verification *entities.PhoneVerification) error {
+	query := `
+		INSERT INTO phone_verifications (driver_id, phone, code_hash, attempts, expires_at, sent_at)
+		VALUES (:driver_id, :phone, :code_hash, :attempts, :expires_at, :sent_at)
+		ON CONFLICT (driver_id) DO UPDATE SET
+			phone = EXCLUDED.phone,
+			code_hash = EXCLUDED.code_hash,
+			attempts = EXCLUDED.attempts,
+			expires_at = EXCLUDED.expires_at,
+			sent_at = EXCLUDED.sent_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, verification); err != nil {
+		r.logger.Error("Failed to save phone verification",
+			zap.Error(err),
+			zap.String("driver_id", verification.DriverID.String()),
+		)
+		return fmt.Errorf("failed to save phone verification: %w", err)
+	}
+
+	return nil
+}
+
+// Get получает действующий код водителя
+func (r *phoneVerificationRepository) Get(ctx context.Context, driverID uuid.UUID) (*entities.PhoneVerification, error) {
+	var verification entities.PhoneVerification
+	query := `SELECT * FROM phone_verifications WHERE driver_id = $1`
+	if err := r.db.GetContext(ctx, &verification, query, driverID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrVerificationCodeNotFound
+		}
+		r.logger.Error("Failed to get phone verification",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get phone verification: %w", err)
+	}
+
+	return &verification, nil
+}
+
+// IncrementAttempts увеличивает счетчик попыток одним запросом, чтобы параллельные попытки
+// не обходили ограничение
+func (r *phoneVerificationRepository) IncrementAttempts(ctx context.Context, driverID uuid.UUID) (int, error) {
+	var attempts int
+	query := `
+		UPDATE phone_verifications SET attempts = attempts + 1
+		WHERE driver_id = $1
+		RETURNING attempts`
+	if err := r.db.GetContext(ctx, &attempts, query, driverID); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, entities.ErrVerificationCodeNotFound
+		}
+		r.logger.Error("Failed to increment phone verification attempts",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to increment phone verification attempts: %w", err)
+	}
+
+	return attempts, nil
+}
+
+// Delete удаляет код водителя
+func (r *phoneVerificationRepository) Delete(ctx context.Context, driverID uuid.UUID) error {
+	if _, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM phone_verifications WHERE driver_id = $1`, driverID); err != nil {
+		r.logger.Error("Failed to delete phone verification",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return fmt.Errorf("failed to delete phone verification: %w", err)
+	}
+
+	return nil
+}
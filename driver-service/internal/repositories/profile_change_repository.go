@@ -0,0 +1,224 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ProfileChangeRepository интерфейс для заявок на изменение юридически значимых полей профиля.
+// Одобрение заявки и изменение водителя сохраняются вместе
+type ProfileChangeRepository interface {
+	// Create сохраняет заявку. Ожидающая решения заявка у водителя может быть только одна:
+	// вторая возвращает ErrProfileChangePending
+	Create(ctx context.Context, change *entities.ProfileChange) error
+	// GetByID получает заявку
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.ProfileChange, error)
+	// ListByDriver возвращает заявки водителя, новые первыми
+	ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error)
+	// ListPending возвращает заявки, ожидающие решения, старые первыми
+	ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error)
+	// Approve сохраняет одобрение заявки и юридически значимые поля водителя одной транзакцией.
+	// Заявка, по которой уже принято решение, возвращает ErrProfileChangeResolved, номер
+	// удостоверения другого водителя — ErrDriverExists
+	Approve(ctx context.Context, change *entities.ProfileChange, driver *entities.Driver) error
+	// Reject сохраняет отклонение заявки; заявка, по которой уже принято решение, возвращает
+	// ErrProfileChangeResolved
+	Reject(ctx context.Context, change *entities.ProfileChange) error
+}
+
+// profileChangeRepository реализация ProfileChangeRepository
+type profileChangeRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewProfileChangeRepository создает новый репозиторий заявок на изменение профиля
+func NewProfileChangeRepository(db *database.DB, logger *zap.Logger) ProfileChangeRepository {
+	return &profileChangeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// resolveProfileChangeQuery сохраняет решение по заявке, еще ожидающей его
+const resolveProfileChangeQuery = `
+	UPDATE driver_profile_changes SET
+		status = :status, reviewed_by = :reviewed_by,
+		reviewed_at = :reviewed_at, review_comment = :review_comment
+	WHERE id = :id AND status = 'pending'`
+
+// Create сохраняет заявку
+func (r *profileChangeRepository) Create(ctx context.Context, change *entities.ProfileChange) error {
+	query := `
+		INSERT INTO driver_profile_changes (
+			id, driver_id, changes, comment, status, requested_by, created_at
+		) VALUES (
+			:id, :driver_id, :changes, :comment, :status, :requested_by, :created_at
+		)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, change); err != nil {
+		// Вторая ожидающая заявка нарушает idx_driver_profile_changes_pending
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrProfileChangePending
+		}
+		r.logger.Error("Failed to create profile change",
+			zap.Error(err),
+			zap.String("driver_id", change.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create profile change: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает заявку
+func (r *profileChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.ProfileChange, error) {
+	var change entities.ProfileChange
+	query := `SELECT * FROM driver_profile_changes WHERE id = $1`
+	if err := r.db.GetContext(ctx, &change, query, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrProfileChangeNotFound
+		}
+		r.logger.Error("Failed to get profile change", zap.Error(err))
+		return nil, fmt.Errorf("failed to get profile change: %w", err)
+	}
+
+	return &change, nil
+}
+
+// ListByDriver получает заявки водителя, новые первыми
+func (r *profileChangeRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, limit, offset int) ([]*entities.ProfileChange, error) {
+	query := `
+		SELECT * FROM driver_profile_changes
+		WHERE driver_id = $1
+		ORDER BY created_at DESC, id
+		LIMIT $2 OFFSET $3`
+
+	var changes []*entities.ProfileChange
+	if err := r.db.ReplicaSelectContext(ctx, &changes, query, driverID, limit, offset); err != nil {
+		r.logger.Error("Failed to list driver profile changes",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver profile changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// ListPending получает заявки, ожидающие решения, старые первыми
+func (r *profileChangeRepository) ListPending(ctx context.Context, limit, offset int) ([]*entities.ProfileChange, error) {
+	query := `
+		SELECT * FROM driver_profile_changes
+		WHERE status = 'pending'
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2`
+
+	var changes []*entities.ProfileChange
+	if err := r.db.ReplicaSelectContext(ctx, &changes, query, limit, offset); err != nil {
+		r.logger.Error("Failed to list pending profile changes", zap.Error(err))
+		return nil, fmt.Errorf("failed to list pending profile changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Approve сохраняет одобрение заявки и поля водителя одной транзакцией: заявку, одобренную
+// параллельно другим администратором, второй раз к профилю не применяет
+func (r *profileChangeRepository) Approve(ctx context.Context, change *entities.ProfileChange, driver *entities.Driver) error {
+	err := r.db.TransactionWithContext(ctx, func(tx *sqlx.Tx) error {
+		if err := resolveProfileChange(ctx, tx, change); err != nil {
+			return err
+		}
+
+		result, err := tx.NamedExecContext(ctx, `
+			UPDATE drivers SET
+				first_name = :first_name, last_name = :last_name, middle_name = :middle_name,
+				passport_series = :passport_series, passport_number = :passport_number,
+				license_number = :license_number, updated_at = :updated_at
+			WHERE id = :id AND deleted_at IS NULL`, driver)
+		if err != nil {
+			// Номер удостоверения занят другим водителем (idx_drivers_license_unique)
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				return entities.ErrDriverExists
+			}
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return entities.ErrDriverNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if err == entities.ErrProfileChangeResolved || err == entities.ErrDriverNotFound || err == entities.ErrDriverExists {
+			return err
+		}
+		r.logger.Error("Failed to approve profile change",
+			zap.Error(err),
+			zap.String("driver_id", change.DriverID.String()),
+			zap.String("change_id", change.ID.String()),
+		)
+		return fmt.Errorf("failed to approve profile change: %w", err)
+	}
+
+	return nil
+}
+
+// Reject сохраняет отклонение заявки
+func (r *profileChangeRepository) Reject(ctx context.Context, change *entities.ProfileChange) error {
+	result, err := r.db.NamedExecContext(ctx, resolveProfileChangeQuery, change)
+	if err == nil {
+		err = checkProfileChangeResolved(result)
+	}
+	if err != nil {
+		if err == entities.ErrProfileChangeResolved {
+			return err
+		}
+		r.logger.Error("Failed to reject profile change",
+			zap.Error(err),
+			zap.String("driver_id", change.DriverID.String()),
+			zap.String("change_id", change.ID.String()),
+		)
+		return fmt.Errorf("failed to reject profile change: %w", err)
+	}
+
+	return nil
+}
+
+// resolveProfileChange сохраняет решение по заявке в транзакции tx
+func resolveProfileChange(ctx context.Context, tx *sqlx.Tx, change *entities.ProfileChange) error {
+	result, err := tx.NamedExecContext(ctx, resolveProfileChangeQuery, change)
+	if err != nil {
+		return err
+	}
+	return checkProfileChangeResolved(result)
+}
+
+// checkProfileChangeResolved возвращает ErrProfileChangeResolved, если решение не сохранено:
+// по заявке его уже приняли
+func checkProfileChangeResolved(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return entities.ErrProfileChangeResolved
+	}
+	return nil
+}
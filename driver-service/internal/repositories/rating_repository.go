@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// RatingRepository интерфейс для работы с оценками водителей
+type RatingRepository interface {
+	Create(ctx context.Context, rating *entities.DriverRating) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverRating, error)
+	GetByDriverID(ctx context.Context, driverID uuid.UUID, limit int) ([]*entities.DriverRating, error)
+	Update(ctx context.Context, rating *entities.DriverRating) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, filters *entities.RatingFilters) ([]*entities.DriverRating, error)
+	Count(ctx context.Context, filters *entities.RatingFilters) (int, error)
+	GetStats(ctx context.Context, driverID uuid.UUID) (*entities.RatingStats, error)
+	// EraseComments удаляет комментарии к оценкам водителя, сохраняя оценки, и возвращает
+	// число измененных оценок
+	EraseComments(ctx context.Context, driverID uuid.UUID) (int, error)
+}
+
+// ratingRepository реализация RatingRepository
+type ratingRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewRatingRepository создает новый репозиторий оценок
+func NewRatingRepository(db *database.DB, logger *zap.Logger) RatingRepository {
+	return &ratingRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новую оценку
+func (r *ratingRepository) Create(ctx context.Context, rating *entities.DriverRating) error {
+	query := `
+		INSERT INTO driver_ratings (
+			id, driver_id, order_id, customer_id, rating, comment, rating_type,
+			criteria_scores, is_verified, is_anonymous, metadata, created_at, updated_at
+		) VALUES (
+			:id, :driver_id, :order_id, :customer_id, :rating, :comment, :rating_type,
+			:criteria_scores, :is_verified, :is_anonymous, :metadata, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, rating)
+	if err != nil {
+		// Повторная оценка того же заказа нарушает idx_driver_ratings_unique_customer_order
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+			return fmt.Errorf("failed to create rating: %w", entities.ErrRatingExists)
+		}
+		r.logger.Error("Failed to create rating",
+			zap.Error(err),
+			zap.String("rating_id", rating.ID.String()),
+			zap.String("driver_id", rating.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create rating: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает оценку по ID
+func (r *ratingRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverRating, error) {
+	var rating entities.DriverRating
+	query := `SELECT * FROM driver_ratings WHERE id = $1`
+
+	err := r.db.ReplicaGetContext(ctx, &rating, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrRatingNotFound
+		}
+		r.logger.Error("Failed to get rating by ID",
+			zap.Error(err),
+			zap.String("rating_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get rating by ID: %w", err)
+	}
+
+	return &rating, nil
+}
+
+// GetByDriverID получает последние оценки водителя
+func (r *ratingRepository) GetByDriverID(ctx context.Context, driverID uuid.UUID, limit int) ([]*entities.DriverRating, error) {
+	return r.List(ctx, &entities.RatingFilters{
+		DriverID: &driverID,
+		Limit:    limit,
+	})
+}
+
+// Update обновляет оценку
+func (r *ratingRepository) Update(ctx context.Context, rating *entities.DriverRating) error {
+	rating.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE driver_ratings SET
+			rating = :rating, comment = :comment, criteria_scores = :criteria_scores,
+			is_verified = :is_verified, is_anonymous = :is_anonymous,
+			metadata = :metadata, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecContext(ctx, query, rating)
+	if err != nil {
+		r.logger.Error("Failed to update rating",
+			zap.Error(err),
+			zap.String("rating_id", rating.ID.String()),
+		)
+		return fmt.Errorf("failed to update rating: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrRatingNotFound
+	}
+
+	return nil
+}
+
+// EraseComments удаляет комментарии к оценкам водителя
+func (r *ratingRepository) EraseComments(ctx context.Context, driverID uuid.UUID) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx,
+		`UPDATE driver_ratings SET comment = NULL, updated_at = NOW() WHERE driver_id = $1 AND comment IS NOT NULL`,
+		driverID,
+	)
+	if err != nil {
+		r.logger.Error("Failed to erase rating comments",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to erase rating comments: %w", err)
+	}
+
+	erased, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(erased), nil
+}
+
+// Delete удаляет оценку
+func (r *ratingRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM driver_ratings WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete rating",
+			zap.Error(err),
+			zap.String("rating_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete rating: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrRatingNotFound
+	}
+
+	return nil
+}
+
+// List получает список оценок с фильтрами
+func (r *ratingRepository) List(ctx context.Context, filters *entities.RatingFilters) ([]*entities.DriverRating, error) {
+	query, args := r.buildListQuery(filters, false)
+
+	var ratings []*entities.DriverRating
+	if err := r.db.ReplicaSelectContext(ctx, &ratings, query, args...); err != nil {
+		r.logger.Error("Failed to list ratings", zap.Error(err))
+		return nil, fmt.Errorf("failed to list ratings: %w", err)
+	}
+
+	return ratings, nil
+}
+
+// Count возвращает количество оценок с фильтрами
+func (r *ratingRepository) Count(ctx context.Context, filters *entities.RatingFilters) (int, error) {
+	query, args := r.buildListQuery(filters, true)
+
+	var count int
+	if err := r.db.ReplicaGetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count ratings", zap.Error(err))
+		return 0, fmt.Errorf("failed to count ratings: %w", err)
+	}
+
+	return count, nil
+}
+
+// ratingStatsRow строка таблицы driver_rating_stats
+type ratingStatsRow struct {
+	DriverID           uuid.UUID  `db:"driver_id"`
+	AverageRating      float64    `db:"average_rating"`
+	TotalRatings       int        `db:"total_ratings"`
+	RatingDistribution []byte     `db:"rating_distribution"`
+	CriteriaAverages   []byte     `db:"criteria_averages"`
+	LastRatingDate     *time.Time `db:"last_rating_date"`
+	LastUpdated        time.Time  `db:"last_updated"`
+}
+
+// GetStats получает агрегированную статистику оценок водителя
+func (r *ratingRepository) GetStats(ctx context.Context, driverID uuid.UUID) (*entities.RatingStats, error) {
+	var row ratingStatsRow
+	query := `SELECT * FROM driver_rating_stats WHERE driver_id = $1`
+
+	err := r.db.GetContext(ctx, &row, query, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return entities.NewRatingStats(driverID), nil
+		}
+		r.logger.Error("Failed to get rating stats",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get rating stats: %w", err)
+	}
+
+	stats := entities.NewRatingStats(driverID)
+	stats.AverageRating = row.AverageRating
+	stats.TotalRatings = row.TotalRatings
+	stats.LastRatingDate = row.LastRatingDate
+	stats.LastUpdated = row.LastUpdated
+
+	if len(row.RatingDistribution) > 0 {
+		if err := json.Unmarshal(row.RatingDistribution, &stats.RatingDistribution); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rating distribution: %w", err)
+		}
+	}
+	if len(row.CriteriaAverages) > 0 {
+		if err := json.Unmarshal(row.CriteriaAverages, &stats.CriteriaAverages); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal criteria averages: %w", err)
+		}
+	}
+
+	return stats, nil
+}
+
+// ratingSortColumns допустимые поля сортировки оценок
+var ratingSortColumns = map[string]bool{
+	"rating":     true,
+	"created_at": true,
+}
+
+// buildListQuery строит SQL запрос для получения списка оценок
+func (r *ratingRepository) buildListQuery(filters *entities.RatingFilters, isCount bool) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argCount := 0
+
+	selectClause := "SELECT * "
+	if isCount {
+		selectClause = "SELECT COUNT(*) "
+	}
+	query := selectClause + "FROM driver_ratings WHERE 1=1"
+
+	if filters != nil {
+		if filters.DriverID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("driver_id = $%d", argCount))
+			args = append(args, *filters.DriverID)
+		}
+
+		if filters.CustomerID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("customer_id = $%d", argCount))
+			args = append(args, *filters.CustomerID)
+		}
+
+		if filters.OrderID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("order_id = $%d", argCount))
+			args = append(args, *filters.OrderID)
+		}
+
+		if len(filters.RatingType) > 0 {
+			placeholders := make([]string, len(filters.RatingType))
+			for i, ratingType := range filters.RatingType {
+				argCount++
+				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				args = append(args, ratingType)
+			}
+			conditions = append(conditions, fmt.Sprintf("rating_type IN (%s)", strings.Join(placeholders, ",")))
+		}
+
+		if filters.MinRating != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("rating >= $%d", argCount))
+			args = append(args, *filters.MinRating)
+		}
+
+		if filters.MaxRating != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("rating <= $%d", argCount))
+			args = append(args, *filters.MaxRating)
+		}
+
+		if filters.IsVerified != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("is_verified = $%d", argCount))
+			args = append(args, *filters.IsVerified)
+		}
+
+		if filters.From != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argCount))
+			args = append(args, *filters.From)
+		}
+
+		if filters.To != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argCount))
+			args = append(args, *filters.To)
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	if !isCount && filters != nil {
+		orderBy := "ORDER BY created_at DESC"
+		if ratingSortColumns[filters.SortBy] {
+			direction := "ASC"
+			if filters.SortDirection == "desc" {
+				direction = "DESC"
+			}
+			orderBy = fmt.Sprintf("ORDER BY %s %s", filters.SortBy, direction)
+		}
+		query += " " + orderBy
+
+		if filters.Limit > 0 {
+			argCount++
+			query += fmt.Sprintf(" LIMIT $%d", argCount)
+			args = append(args, filters.Limit)
+		}
+
+		if filters.Offset > 0 {
+			argCount++
+			query += fmt.Sprintf(" OFFSET $%d", argCount)
+			args = append(args, filters.Offset)
+		}
+	}
+
+	return query, args
+}
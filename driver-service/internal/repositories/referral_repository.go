@@ -0,0 +1,219 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ReferralRepository интерфейс для реферальных кодов и приглашенных водителей
+type ReferralRepository interface {
+	// GetCode получает реферальный код водителя
+	GetCode(ctx context.Context, driverID uuid.UUID) (*entities.ReferralCode, error)
+	// CreateCode сохраняет реферальный код. Если код уже занят или у водителя уже есть код,
+	// возвращает ErrReferralCodeTaken
+	CreateCode(ctx context.Context, code *entities.ReferralCode) error
+	// Create сохраняет реферал; повторное приглашение водителя возвращает ErrReferralExists
+	Create(ctx context.Context, referral *entities.Referral) error
+	// GetByReferredDriver получает реферал приглашенного водителя
+	GetByReferredDriver(ctx context.Context, driverID uuid.UUID) (*entities.Referral, error)
+	// ListByReferrer возвращает рефералы пригласившего водителя, последние первыми
+	ListByReferrer(ctx context.Context, referrerID uuid.UUID) ([]*entities.Referral, error)
+	// ListOpen возвращает не больше limit незавершенных рефералов с ID больше after по возрастанию ID
+	ListOpen(ctx context.Context, after uuid.UUID, limit int) ([]*entities.Referral, error)
+	// UpdateProgress сохраняет прогресс реферала, если его этап все еще from; false, если
+	// реферал параллельно перешел на другой этап
+	UpdateProgress(ctx context.Context, referral *entities.Referral, from entities.ReferralStatus) (bool, error)
+	// ListCompleted возвращает рефералы, завершенные в [from, to), в порядке завершения
+	ListCompleted(ctx context.Context, from, to time.Time) ([]*entities.Referral, error)
+}
+
+// referralRepository реализация ReferralRepository
+type referralRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewReferralRepository создает новый репозиторий рефералов
+func NewReferralRepository(db *database.DB, logger *zap.Logger) ReferralRepository {
+	return &referralRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetCode получает реферальный код водителя
+func (r *referralRepository) GetCode(ctx context.Context, driverID uuid.UUID) (*entities.ReferralCode, error) {
+	var code entities.ReferralCode
+	query := `SELECT * FROM referral_codes WHERE driver_id = $1`
+	if err := r.db.GetContext(ctx, &code, query, driverID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrReferralCodeNotFound
+		}
+		r.logger.Error("Failed to get referral code",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get referral code: %w", err)
+	}
+
+	return &code, nil
+}
+
+// CreateCode сохраняет реферальный код водителя
+func (r *referralRepository) CreateCode(ctx context.Context, code *entities.ReferralCode) error {
+	query := `
+		INSERT INTO referral_codes (driver_id, code, created_at)
+		VALUES (:driver_id, :code, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, code); err != nil {
+		// Совпадение кода или второй код водителя нарушают уникальность
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrReferralCodeTaken
+		}
+		r.logger.Error("Failed to create referral code",
+			zap.Error(err),
+			zap.String("driver_id", code.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create referral code: %w", err)
+	}
+
+	return nil
+}
+
+// Create сохраняет реферал
+func (r *referralRepository) Create(ctx context.Context, referral *entities.Referral) error {
+	query := `
+		INSERT INTO referrals (
+			id, referrer_id, referred_driver_id, code, status, trips_completed,
+			required_trips, registered_at, verified_at, completed_at, updated_at
+		) VALUES (
+			:id, :referrer_id, :referred_driver_id, :code, :status, :trips_completed,
+			:required_trips, :registered_at, :verified_at, :completed_at, :updated_at
+		)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, referral); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrReferralExists
+		}
+		r.logger.Error("Failed to create referral",
+			zap.Error(err),
+			zap.String("referrer_id", referral.ReferrerID.String()),
+			zap.String("referred_driver_id", referral.ReferredDriverID.String()),
+		)
+		return fmt.Errorf("failed to create referral: %w", err)
+	}
+
+	return nil
+}
+
+// GetByReferredDriver получает реферал приглашенного водителя
+func (r *referralRepository) GetByReferredDriver(ctx context.Context, driverID uuid.UUID) (*entities.Referral, error) {
+	var referral entities.Referral
+	query := `SELECT * FROM referrals WHERE referred_driver_id = $1`
+	if err := r.db.GetContext(ctx, &referral, query, driverID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrReferralNotFound
+		}
+		r.logger.Error("Failed to get referral",
+			zap.Error(err),
+			zap.String("referred_driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get referral: %w", err)
+	}
+
+	return &referral, nil
+}
+
+// ListByReferrer получает рефералы пригласившего водителя
+func (r *referralRepository) ListByReferrer(ctx context.Context, referrerID uuid.UUID) ([]*entities.Referral, error) {
+	query := `
+		SELECT * FROM referrals
+		WHERE referrer_id = $1
+		ORDER BY registered_at DESC`
+
+	referrals := make([]*entities.Referral, 0)
+	if err := r.db.SelectContext(ctx, &referrals, query, referrerID); err != nil {
+		r.logger.Error("Failed to list referrals",
+			zap.Error(err),
+			zap.String("referrer_id", referrerID.String()),
+		)
+		return nil, fmt.Errorf("failed to list referrals: %w", err)
+	}
+
+	return referrals, nil
+}
+
+// ListOpen получает страницу незавершенных рефералов
+func (r *referralRepository) ListOpen(ctx context.Context, after uuid.UUID, limit int) ([]*entities.Referral, error) {
+	query := `
+		SELECT * FROM referrals
+		WHERE status <> $1 AND id > $2
+		ORDER BY id
+		LIMIT $3`
+
+	var referrals []*entities.Referral
+	if err := r.db.SelectContext(ctx, &referrals, query, entities.ReferralStatusCompleted, after, limit); err != nil {
+		r.logger.Error("Failed to list open referrals", zap.Error(err))
+		return nil, fmt.Errorf("failed to list open referrals: %w", err)
+	}
+
+	return referrals, nil
+}
+
+// UpdateProgress сохраняет этап и счетчик поездок реферала
+func (r *referralRepository) UpdateProgress(ctx context.Context, referral *entities.Referral, from entities.ReferralStatus) (bool, error) {
+	query := `
+		UPDATE referrals SET
+			status = $1, trips_completed = $2, verified_at = $3,
+			completed_at = $4, updated_at = $5
+		WHERE id = $6 AND status = $7`
+
+	// Запрос не повторяется: после обрыва соединения он мог быть применен, и повтор
+	// ошибочно сообщил бы о параллельном изменении
+	result, err := r.db.ExecContext(ctx, query,
+		referral.Status, referral.TripsCompleted, referral.VerifiedAt,
+		referral.CompletedAt, referral.UpdatedAt, referral.ID, from,
+	)
+	if err != nil {
+		r.logger.Error("Failed to update referral progress",
+			zap.Error(err),
+			zap.String("referral_id", referral.ID.String()),
+		)
+		return false, fmt.Errorf("failed to update referral progress: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// ListCompleted получает рефералы, завершенные за период
+func (r *referralRepository) ListCompleted(ctx context.Context, from, to time.Time) ([]*entities.Referral, error) {
+	query := `
+		SELECT * FROM referrals
+		WHERE status = $1 AND completed_at >= $2 AND completed_at < $3
+		ORDER BY completed_at, id`
+
+	referrals := make([]*entities.Referral, 0)
+	if err := r.db.SelectContext(ctx, &referrals, query, entities.ReferralStatusCompleted, from, to); err != nil {
+		r.logger.Error("Failed to list completed referrals", zap.Error(err))
+		return nil, fmt.Errorf("failed to list completed referrals: %w", err)
+	}
+
+	return referrals, nil
+}
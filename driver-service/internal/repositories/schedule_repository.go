@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ScheduleRepository интерфейс для расписаний доступности водителей
+type ScheduleRepository interface {
+	GetByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverSchedule, error)
+	// Upsert сохраняет расписание, заменяя прежнее расписание водителя
+	Upsert(ctx context.Context, schedule *entities.DriverSchedule) error
+	Delete(ctx context.Context, driverID uuid.UUID) error
+	// List возвращает расписания по возрастанию ID водителя начиная после afterDriverID
+	List(ctx context.Context, afterDriverID uuid.UUID, limit int) ([]*entities.DriverSchedule, error)
+}
+
+// scheduleRepository реализация ScheduleRepository
+type scheduleRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewScheduleRepository создает новый репозиторий расписаний
+func NewScheduleRepository(db *database.DB, logger *zap.Logger) ScheduleRepository {
+	return &scheduleRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// GetByDriverID получает расписание водителя
+func (r *scheduleRepository) GetByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverSchedule, error) {
+	var schedule entities.DriverSchedule
+	err := r.db.GetContext(ctx, &schedule, `SELECT * FROM driver_schedules WHERE driver_id = $1`, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrScheduleNotFound
+		}
+		r.logger.Error("Failed to get driver schedule",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get driver schedule: %w", err)
+	}
+	return &schedule, nil
+}
+
+// Upsert сохраняет расписание водителя
+func (r *scheduleRepository) Upsert(ctx context.Context, schedule *entities.DriverSchedule) error {
+	query := `
+		INSERT INTO driver_schedules (
+			driver_id, timezone, windows, created_at, updated_at
+		) VALUES (
+			:driver_id, :timezone, :windows, :created_at, :updated_at
+		)
+		ON CONFLICT (driver_id) DO UPDATE SET
+			timezone = EXCLUDED.timezone,
+			windows = EXCLUDED.windows,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, schedule); err != nil {
+		r.logger.Error("Failed to save driver schedule",
+			zap.Error(err),
+			zap.String("driver_id", schedule.DriverID.String()),
+		)
+		return fmt.Errorf("failed to save driver schedule: %w", err)
+	}
+
+	return nil
+}
+
+// Delete удаляет расписание водителя
+func (r *scheduleRepository) Delete(ctx context.Context, driverID uuid.UUID) error {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM driver_schedules WHERE driver_id = $1`, driverID)
+	if err != nil {
+		r.logger.Error("Failed to delete driver schedule",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return fmt.Errorf("failed to delete driver schedule: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if deleted == 0 {
+		return entities.ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+// List получает страницу расписаний по ключу driver_id
+func (r *scheduleRepository) List(ctx context.Context, afterDriverID uuid.UUID, limit int) ([]*entities.DriverSchedule, error) {
+	query := `
+		SELECT * FROM driver_schedules
+		WHERE driver_id > $1
+		ORDER BY driver_id ASC
+		LIMIT $2`
+
+	var schedules []*entities.DriverSchedule
+	if err := r.db.SelectContext(ctx, &schedules, query, afterDriverID, limit); err != nil {
+		r.logger.Error("Failed to list driver schedules", zap.Error(err))
+		return nil, fmt.Errorf("failed to list driver schedules: %w", err)
+	}
+
+	return schedules, nil
+}
@@ -0,0 +1,257 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SecurityRepository интерфейс для сессий водителей и событий безопасности
+type SecurityRepository interface {
+	CreateEvent(ctx context.Context, event *entities.SecurityEvent) error
+	GetEvent(ctx context.Context, id uuid.UUID) (*entities.SecurityEvent, error)
+	UpdateEvent(ctx context.Context, event *entities.SecurityEvent) error
+	// ListEvents возвращает события, новые первыми
+	ListEvents(ctx context.Context, filters *entities.SecurityEventFilters) ([]*entities.SecurityEvent, error)
+
+	GetSession(ctx context.Context, id string) (*entities.DriverSession, error)
+	// SaveSession создает сессию или обновляет время последнего запроса и сетевые данные
+	SaveSession(ctx context.Context, session *entities.DriverSession) error
+	// ListSessions возвращает сессии водителя, активные после since
+	ListSessions(ctx context.Context, driverID uuid.UUID, since time.Time) ([]*entities.DriverSession, error)
+	// RevokeSessions отзывает все действующие сессии водителя и возвращает их число
+	RevokeSessions(ctx context.Context, driverID uuid.UUID, at time.Time) (int, error)
+	// RevokeDeviceSessions отзывает действующие сессии водителя с устройства deviceID и возвращает их число
+	RevokeDeviceSessions(ctx context.Context, driverID uuid.UUID, deviceID string, at time.Time) (int, error)
+}
+
+// securityRepository реализация SecurityRepository
+type securityRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewSecurityRepository создает новый репозиторий событий безопасности
+func NewSecurityRepository(db *database.DB, logger *zap.Logger) SecurityRepository {
+	return &securityRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateEvent сохраняет событие безопасности
+func (r *securityRepository) CreateEvent(ctx context.Context, event *entities.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (
+			id, driver_id, event_type, status, details, sessions_revoked, created_at
+		) VALUES (
+			:id, :driver_id, :event_type, :status, :details, :sessions_revoked, :created_at
+		)
+		ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, event); err != nil {
+		r.logger.Error("Failed to create security event",
+			zap.Error(err),
+			zap.String("driver_id", event.DriverID.String()),
+			zap.String("type", string(event.Type)),
+		)
+		return fmt.Errorf("failed to create security event: %w", err)
+	}
+
+	return nil
+}
+
+// GetEvent получает событие безопасности по ID
+func (r *securityRepository) GetEvent(ctx context.Context, id uuid.UUID) (*entities.SecurityEvent, error) {
+	var event entities.SecurityEvent
+	if err := r.db.GetContext(ctx, &event, `SELECT * FROM security_events WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrSecurityEventNotFound
+		}
+		r.logger.Error("Failed to get security event", zap.Error(err), zap.String("event_id", id.String()))
+		return nil, fmt.Errorf("failed to get security event: %w", err)
+	}
+
+	return &event, nil
+}
+
+// UpdateEvent сохраняет рассмотрение события
+func (r *securityRepository) UpdateEvent(ctx context.Context, event *entities.SecurityEvent) error {
+	query := `
+		UPDATE security_events SET
+			status = :status, sessions_revoked = :sessions_revoked, reviewed_by = :reviewed_by,
+			review_note = :review_note, reviewed_at = :reviewed_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, event)
+	if err != nil {
+		r.logger.Error("Failed to update security event", zap.Error(err), zap.String("event_id", event.ID.String()))
+		return fmt.Errorf("failed to update security event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrSecurityEventNotFound
+	}
+
+	return nil
+}
+
+// ListEvents получает события безопасности по фильтрам
+func (r *securityRepository) ListEvents(ctx context.Context, filters *entities.SecurityEventFilters) ([]*entities.SecurityEvent, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filters.DriverID != nil {
+		args = append(args, *filters.DriverID)
+		conditions = append(conditions, fmt.Sprintf("driver_id = $%d", len(args)))
+	}
+	if filters.Type != "" {
+		args = append(args, filters.Type)
+		conditions = append(conditions, fmt.Sprintf("event_type = $%d", len(args)))
+	}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filters.Since != nil {
+		args = append(args, *filters.Since)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	query := `SELECT * FROM security_events`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id"
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	var events []*entities.SecurityEvent
+	if err := r.db.SelectContext(ctx, &events, query, args...); err != nil {
+		r.logger.Error("Failed to list security events", zap.Error(err))
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetSession получает сессию по ID
+func (r *securityRepository) GetSession(ctx context.Context, id string) (*entities.DriverSession, error) {
+	var session entities.DriverSession
+	if err := r.db.GetContext(ctx, &session, `SELECT * FROM driver_sessions WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrSessionNotFound
+		}
+		r.logger.Error("Failed to get driver session", zap.Error(err))
+		return nil, fmt.Errorf("failed to get driver session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// SaveSession создает или обновляет сессию; отзыв сессии не снимается
+func (r *securityRepository) SaveSession(ctx context.Context, session *entities.DriverSession) error {
+	query := `
+		INSERT INTO driver_sessions (
+			id, driver_id, device_id, country, ip_address, first_seen_at, last_seen_at, revoked_at
+		) VALUES (
+			:id, :driver_id, :device_id, :country, :ip_address, :first_seen_at, :last_seen_at, :revoked_at
+		)
+		ON CONFLICT (id) DO UPDATE SET
+			country = EXCLUDED.country,
+			ip_address = EXCLUDED.ip_address,
+			last_seen_at = GREATEST(driver_sessions.last_seen_at, EXCLUDED.last_seen_at)`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, session); err != nil {
+		r.logger.Error("Failed to save driver session",
+			zap.Error(err),
+			zap.String("driver_id", session.DriverID.String()),
+		)
+		return fmt.Errorf("failed to save driver session: %w", err)
+	}
+
+	return nil
+}
+
+// ListSessions получает сессии водителя, активные после since
+func (r *securityRepository) ListSessions(ctx context.Context, driverID uuid.UUID, since time.Time) ([]*entities.DriverSession, error) {
+	query := `
+		SELECT * FROM driver_sessions
+		WHERE driver_id = $1 AND last_seen_at >= $2
+		ORDER BY last_seen_at DESC`
+
+	var sessions []*entities.DriverSession
+	if err := r.db.SelectContext(ctx, &sessions, query, driverID, since); err != nil {
+		r.logger.Error("Failed to list driver sessions",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSessions отзывает действующие сессии водителя
+func (r *securityRepository) RevokeSessions(ctx context.Context, driverID uuid.UUID, at time.Time) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx,
+		`UPDATE driver_sessions SET revoked_at = $2 WHERE driver_id = $1 AND revoked_at IS NULL`,
+		driverID, at,
+	)
+	if err != nil {
+		r.logger.Error("Failed to revoke driver sessions",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to revoke driver sessions: %w", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(revoked), nil
+}
+
+// RevokeDeviceSessions отзывает действующие сессии водителя с одного устройства
+func (r *securityRepository) RevokeDeviceSessions(ctx context.Context, driverID uuid.UUID, deviceID string, at time.Time) (int, error) {
+	result, err := r.db.ExecIdempotentContext(ctx,
+		`UPDATE driver_sessions SET revoked_at = $3 WHERE driver_id = $1 AND device_id = $2 AND revoked_at IS NULL`,
+		driverID, deviceID, at,
+	)
+	if err != nil {
+		r.logger.Error("Failed to revoke device sessions",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+			zap.String("device_id", deviceID),
+		)
+		return 0, fmt.Errorf("failed to revoke device sessions: %w", err)
+	}
+
+	revoked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(revoked), nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"go.uber.org/zap"
+)
+
+// ShardRepository интерфейс для назначений городов шардам (таблица city_shards основной базы).
+// Шардирование поддерживается только при хранении в PostgreSQL, поэтому in-memory реализации нет
+type ShardRepository interface {
+	ListAssignments(ctx context.Context) ([]*entities.CityShard, error)
+	// SaveAssignment закрепляет город за шардом или снимает блокировку записи
+	SaveAssignment(ctx context.Context, assignment *entities.CityShard) error
+}
+
+// shardRepository реализация ShardRepository
+type shardRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewShardRepository создает новый репозиторий назначений шардов
+func NewShardRepository(db *database.DB, logger *zap.Logger) ShardRepository {
+	return &shardRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListAssignments получает назначения городов
+func (r *shardRepository) ListAssignments(ctx context.Context) ([]*entities.CityShard, error) {
+	var assignments []*entities.CityShard
+	if err := r.db.SelectContext(ctx, &assignments, `SELECT * FROM city_shards ORDER BY city`); err != nil {
+		r.logger.Error("Failed to list city shard assignments", zap.Error(err))
+		return nil, fmt.Errorf("failed to list city shard assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// SaveAssignment сохраняет назначение города
+func (r *shardRepository) SaveAssignment(ctx context.Context, assignment *entities.CityShard) error {
+	assignment.City = entities.NormalizeCity(assignment.City)
+	assignment.UpdatedAt = time.Now()
+
+	query := `
+		INSERT INTO city_shards (city, shard, locked, updated_at)
+		VALUES (:city, :shard, :locked, :updated_at)
+		ON CONFLICT (city) DO UPDATE SET
+			shard = EXCLUDED.shard,
+			locked = EXCLUDED.locked,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, assignment); err != nil {
+		r.logger.Error("Failed to save city shard assignment",
+			zap.Error(err),
+			zap.String("city", assignment.City),
+			zap.String("shard", assignment.Shard),
+		)
+		return fmt.Errorf("failed to save city shard assignment: %w", err)
+	}
+
+	return nil
+}
+
+// LoadShardAssignments перечитывает назначения городов в маршрутизатор шардов
+func LoadShardAssignments(ctx context.Context, repo ShardRepository, router *database.ShardRouter) error {
+	assignments, err := repo.ListAssignments(ctx)
+	if err != nil {
+		return err
+	}
+	router.SetAssignments(assignments)
+	return nil
+}
@@ -0,0 +1,326 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// shardedLocationRepository распределяет местоположения между шардами по ключу города водителя.
+// Запросы по водителю или городу выполняются на одном шарде; на всех шардах — только
+// глобальные запросы: выборка без водителя и города, поиск поблизости без города и очистка
+type shardedLocationRepository struct {
+	router  *database.ShardRouter
+	shards  map[string]LocationRepository
+	names   []string
+	drivers DriverRepository
+	logger  *zap.Logger
+}
+
+// NewShardedLocationRepository создает репозиторий местоположений поверх шардов.
+// Ключ шарда водителя берется из основной базы через drivers
+func NewShardedLocationRepository(router *database.ShardRouter, drivers DriverRepository, logger *zap.Logger) (LocationRepository, error) {
+	shards := make(map[string]LocationRepository)
+	for _, name := range router.Names() {
+		db, err := router.Shard(name)
+		if err != nil {
+			return nil, err
+		}
+		shards[name] = NewLocationRepository(db, logger.With(zap.String("shard", name)))
+	}
+
+	return &shardedLocationRepository{
+		router:  router,
+		shards:  shards,
+		names:   router.Names(),
+		drivers: drivers,
+		logger:  logger,
+	}, nil
+}
+
+func (r *shardedLocationRepository) Create(ctx context.Context, location *entities.DriverLocation) (bool, error) {
+	repo, err := r.writeShard(ctx, location)
+	if err != nil {
+		return false, err
+	}
+	return repo.Create(ctx, location)
+}
+
+// GetByID ищет местоположение на всех шардах: ID не содержит ключа шарда
+func (r *shardedLocationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverLocation, error) {
+	for _, name := range r.names {
+		location, err := r.shards[name].GetByID(ctx, id)
+		if err == nil {
+			return location, nil
+		}
+		if err != entities.ErrLocationNotFound {
+			return nil, err
+		}
+	}
+	return nil, entities.ErrLocationNotFound
+}
+
+func (r *shardedLocationRepository) GetLatestByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverLocation, error) {
+	repo, err := r.driverShard(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetLatestByDriverID(ctx, driverID)
+}
+
+func (r *shardedLocationRepository) GetByDriverIDInTimeRange(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]*entities.DriverLocation, error) {
+	repo, err := r.driverShard(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetByDriverIDInTimeRange(ctx, driverID, from, to)
+}
+
+// List выполняет выборку на шарде города или водителя; без них — на всех шардах
+// с объединением по времени записи
+func (r *shardedLocationRepository) List(ctx context.Context, filters *entities.LocationFilters) ([]*entities.DriverLocation, error) {
+	if filters != nil && filters.City != "" {
+		return r.shards[r.router.Resolve(filters.City).Shard].List(ctx, filters)
+	}
+	if filters != nil && filters.DriverID != nil {
+		repo, err := r.driverShard(ctx, *filters.DriverID)
+		if err != nil {
+			return nil, err
+		}
+		return repo.List(ctx, filters)
+	}
+
+	results, err := r.fanOut(ctx, func(repo LocationRepository) ([]*entities.DriverLocation, error) {
+		return repo.List(ctx, filters)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].RecordedAt.After(results[j].RecordedAt)
+	})
+	if filters != nil && filters.Limit > 0 && len(results) > filters.Limit {
+		results = results[:filters.Limit]
+	}
+	return results, nil
+}
+
+// CreateBatch сохраняет пакет по шардам; если город любого водителя переносится,
+// пакет не сохраняется целиком
+func (r *shardedLocationRepository) CreateBatch(ctx context.Context, locations []*entities.DriverLocation) ([]*entities.DriverLocation, error) {
+	batches := make(map[string][]*entities.DriverLocation)
+	for _, location := range locations {
+		if err := r.resolveShardKey(ctx, location); err != nil {
+			return nil, err
+		}
+		shard, err := r.router.ShardForWrite(location.ShardKey)
+		if err != nil {
+			return nil, err
+		}
+		batches[shard] = append(batches[shard], location)
+	}
+
+	var saved []*entities.DriverLocation
+	for _, name := range r.names {
+		if batch := batches[name]; len(batch) > 0 {
+			shardSaved, err := r.shards[name].CreateBatch(ctx, batch)
+			if err != nil {
+				return saved, fmt.Errorf("shard %s: %w", name, err)
+			}
+			saved = append(saved, shardSaved...)
+		}
+	}
+	return saved, nil
+}
+
+// DeleteOld удаляет устаревшие местоположения на всех шардах
+func (r *shardedLocationRepository) DeleteOld(ctx context.Context, olderThan time.Time) error {
+	var errs []error
+	for _, name := range r.names {
+		if err := r.shards[name].DeleteOld(ctx, olderThan); err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// EnsurePartitions создает месячные секции на всех шардах и возвращает их общее число
+func (r *shardedLocationRepository) EnsurePartitions(ctx context.Context, from, to time.Time) (int, error) {
+	created := 0
+	var errs []error
+	for _, name := range r.names {
+		n, err := r.shards[name].EnsurePartitions(ctx, from, to)
+		created += n
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", name, err))
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+// GetOldestRecordedAt возвращает самое раннее время записи среди всех шардов
+func (r *shardedLocationRepository) GetOldestRecordedAt(ctx context.Context) (time.Time, error) {
+	var oldest time.Time
+	for _, name := range r.names {
+		recordedAt, err := r.shards[name].GetOldestRecordedAt(ctx)
+		if err == entities.ErrLocationNotFound {
+			continue
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("shard %s: %w", name, err)
+		}
+		if oldest.IsZero() || recordedAt.Before(oldest) {
+			oldest = recordedAt
+		}
+	}
+	if oldest.IsZero() {
+		return time.Time{}, entities.ErrLocationNotFound
+	}
+	return oldest, nil
+}
+
+// GetActivity считает пробег и время на связи на шарде водителя
+func (r *shardedLocationRepository) GetActivity(ctx context.Context, driverID uuid.UUID, from, to time.Time, maxGap time.Duration) (*entities.LocationActivity, error) {
+	repo, err := r.driverShard(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetActivity(ctx, driverID, from, to, maxGap)
+}
+
+// ListDriverIDsInTimeRange объединяет водителей всех шардов
+func (r *shardedLocationRepository) ListDriverIDsInTimeRange(ctx context.Context, from, to time.Time) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool)
+	var driverIDs []uuid.UUID
+	for _, name := range r.names {
+		ids, err := r.shards[name].ListDriverIDsInTimeRange(ctx, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("shard %s: %w", name, err)
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				driverIDs = append(driverIDs, id)
+			}
+		}
+	}
+	return driverIDs, nil
+}
+
+// DeleteByDriverID удаляет местоположения водителя на всех шардах: после переноса
+// города часть истории могла остаться на прежнем шарде
+func (r *shardedLocationRepository) DeleteByDriverID(ctx context.Context, driverID uuid.UUID) (int, error) {
+	var (
+		deleted int
+		errs    []error
+	)
+	for _, name := range r.names {
+		count, err := r.shards[name].DeleteByDriverID(ctx, driverID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("shard %s: %w", name, err))
+			continue
+		}
+		deleted += count
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// GetNearby ищет на шарде города из контекста (entities.WithShardCity); без города —
+// на всех шардах, ближайшие первыми
+func (r *shardedLocationRepository) GetNearby(ctx context.Context, lat, lon, radiusKm float64, limit int) ([]*entities.DriverLocation, error) {
+	if city, ok := entities.ShardCityFromContext(ctx); ok {
+		return r.shards[r.router.Resolve(city).Shard].GetNearby(ctx, lat, lon, radiusKm, limit)
+	}
+
+	results, err := r.fanOut(ctx, func(repo LocationRepository) ([]*entities.DriverLocation, error) {
+		return repo.GetNearby(ctx, lat, lon, radiusKm, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	center := &entities.DriverLocation{Latitude: lat, Longitude: lon}
+	sort.SliceStable(results, func(i, j int) bool {
+		return center.DistanceTo(results[i]) < center.DistanceTo(results[j])
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// fanOut выполняет запрос на всех шардах параллельно и объединяет результаты
+func (r *shardedLocationRepository) fanOut(ctx context.Context, query func(LocationRepository) ([]*entities.DriverLocation, error)) ([]*entities.DriverLocation, error) {
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results []*entities.DriverLocation
+		errs    []error
+	)
+	for _, name := range r.names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			locations, err := query(r.shards[name])
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("shard %s: %w", name, err))
+				return
+			}
+			results = append(results, locations...)
+		}(name)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		r.logger.Error("Cross-shard location query failed", zap.Error(errors.Join(errs...)))
+		return nil, errors.Join(errs...)
+	}
+	return results, nil
+}
+
+// driverShard возвращает шард местоположений водителя
+func (r *shardedLocationRepository) driverShard(ctx context.Context, driverID uuid.UUID) (LocationRepository, error) {
+	driver, err := r.drivers.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return r.shards[r.router.Resolve(driver.ShardKey).Shard], nil
+}
+
+// writeShard возвращает шард для записи местоположения
+func (r *shardedLocationRepository) writeShard(ctx context.Context, location *entities.DriverLocation) (LocationRepository, error) {
+	if err := r.resolveShardKey(ctx, location); err != nil {
+		return nil, err
+	}
+	shard, err := r.router.ShardForWrite(location.ShardKey)
+	if err != nil {
+		return nil, err
+	}
+	return r.shards[shard], nil
+}
+
+// resolveShardKey заполняет ключ шарда местоположения по водителю, если сервис его не задал
+func (r *shardedLocationRepository) resolveShardKey(ctx context.Context, location *entities.DriverLocation) error {
+	if location.ShardKey != "" {
+		return nil
+	}
+	driver, err := r.drivers.GetByID(ctx, location.DriverID)
+	if err != nil {
+		return err
+	}
+	location.ShardKey = driver.ShardKey
+	return nil
+}
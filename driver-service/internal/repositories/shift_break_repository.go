@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// ShiftBreakRepository интерфейс для перерывов в сменах
+type ShiftBreakRepository interface {
+	// Create сохраняет перерыв. Второй идущий перерыв в смене не сохраняется:
+	// возвращается ErrShiftBreakActive
+	Create(ctx context.Context, shiftBreak *entities.ShiftBreak) error
+	// Update сохраняет окончание перерыва
+	Update(ctx context.Context, shiftBreak *entities.ShiftBreak) error
+	// ListByShift возвращает перерывы смены в порядке начала
+	ListByShift(ctx context.Context, shiftID uuid.UUID) ([]*entities.ShiftBreak, error)
+}
+
+// shiftBreakRepository реализация ShiftBreakRepository
+type shiftBreakRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewShiftBreakRepository создает новый репозиторий перерывов в сменах
+func NewShiftBreakRepository(db *database.DB, logger *zap.Logger) ShiftBreakRepository {
+	return &shiftBreakRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create сохраняет перерыв
+func (r *shiftBreakRepository) Create(ctx context.Context, shiftBreak *entities.ShiftBreak) error {
+	query := `
+		INSERT INTO shift_breaks (id, shift_id, driver_id, started_at, ended_at, notes, created_at)
+		VALUES (:id, :shift_id, :driver_id, :started_at, :ended_at, :notes, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, shiftBreak); err != nil {
+		// Второй идущий перерыв нарушает idx_shift_breaks_open
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return entities.ErrShiftBreakActive
+		}
+		r.logger.Error("Failed to create shift break",
+			zap.Error(err),
+			zap.String("shift_id", shiftBreak.ShiftID.String()),
+		)
+		return fmt.Errorf("failed to create shift break: %w", err)
+	}
+
+	return nil
+}
+
+// Update сохраняет окончание перерыва
+func (r *shiftBreakRepository) Update(ctx context.Context, shiftBreak *entities.ShiftBreak) error {
+	query := `UPDATE shift_breaks SET ended_at = :ended_at, notes = :notes WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, shiftBreak)
+	if err != nil {
+		r.logger.Error("Failed to update shift break",
+			zap.Error(err),
+			zap.String("break_id", shiftBreak.ID.String()),
+		)
+		return fmt.Errorf("failed to update shift break: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrShiftBreakNotActive
+	}
+
+	return nil
+}
+
+// ListByShift возвращает перерывы смены в порядке начала
+func (r *shiftBreakRepository) ListByShift(ctx context.Context, shiftID uuid.UUID) ([]*entities.ShiftBreak, error) {
+	query := `SELECT * FROM shift_breaks WHERE shift_id = $1 ORDER BY started_at, id`
+
+	breaks := []*entities.ShiftBreak{}
+	if err := r.db.SelectContext(ctx, &breaks, query, shiftID); err != nil {
+		r.logger.Error("Failed to list shift breaks",
+			zap.Error(err),
+			zap.String("shift_id", shiftID.String()),
+		)
+		return nil, fmt.Errorf("failed to list shift breaks: %w", err)
+	}
+
+	return breaks, nil
+}
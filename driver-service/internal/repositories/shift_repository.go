@@ -0,0 +1,314 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ShiftRepository интерфейс для работы со сменами водителей
+type ShiftRepository interface {
+	Create(ctx context.Context, shift *entities.DriverShift) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverShift, error)
+	GetActiveByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error)
+	Update(ctx context.Context, shift *entities.DriverShift) error
+	List(ctx context.Context, filters *entities.ShiftFilters) ([]*entities.DriverShift, error)
+	Count(ctx context.Context, filters *entities.ShiftFilters) (int, error)
+	// EraseLocations удаляет координаты начала и окончания смен водителя, сохраняя итоги смен,
+	// и возвращает число измененных смен
+	EraseLocations(ctx context.Context, driverID uuid.UUID) (int, error)
+}
+
+// shiftRepository реализация ShiftRepository
+type shiftRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewShiftRepository создает новый репозиторий смен
+func NewShiftRepository(db *database.DB, logger *zap.Logger) ShiftRepository {
+	return &shiftRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create создает новую смену
+func (r *shiftRepository) Create(ctx context.Context, shift *entities.DriverShift) error {
+	query := `
+		INSERT INTO driver_shifts (
+			id, driver_id, vehicle_id, start_time, end_time, status,
+			start_latitude, start_longitude, end_latitude, end_longitude,
+			total_trips, total_distance, total_earnings, fuel_consumed,
+			average_speed, idle_minutes, metadata, created_at, updated_at
+		) VALUES (
+			:id, :driver_id, :vehicle_id, :start_time, :end_time, :status,
+			:start_latitude, :start_longitude, :end_latitude, :end_longitude,
+			:total_trips, :total_distance, :total_earnings, :fuel_consumed,
+			:average_speed, :idle_minutes, :metadata, :created_at, :updated_at
+		)`
+
+	_, err := r.db.NamedExecContext(ctx, query, shift)
+	if err != nil {
+		r.logger.Error("Failed to create shift",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+			zap.String("driver_id", shift.DriverID.String()),
+		)
+		return fmt.Errorf("failed to create shift: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID получает смену по ID
+func (r *shiftRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.DriverShift, error) {
+	var shift entities.DriverShift
+	query := `SELECT * FROM driver_shifts WHERE id = $1`
+
+	err := r.db.ReplicaGetContext(ctx, &shift, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrShiftNotFound
+		}
+		r.logger.Error("Failed to get shift by ID",
+			zap.Error(err),
+			zap.String("shift_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get shift by ID: %w", err)
+	}
+
+	return &shift, nil
+}
+
+// GetActiveByDriverID получает активную смену водителя
+func (r *shiftRepository) GetActiveByDriverID(ctx context.Context, driverID uuid.UUID) (*entities.DriverShift, error) {
+	var shift entities.DriverShift
+	query := `
+		SELECT * FROM driver_shifts
+		WHERE driver_id = $1 AND status = 'active' AND end_time IS NULL
+		LIMIT 1`
+
+	err := r.db.GetContext(ctx, &shift, query, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrShiftNotFound
+		}
+		r.logger.Error("Failed to get active shift",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return nil, fmt.Errorf("failed to get active shift: %w", err)
+	}
+
+	return &shift, nil
+}
+
+// Update обновляет смену
+func (r *shiftRepository) Update(ctx context.Context, shift *entities.DriverShift) error {
+	shift.UpdatedAt = time.Now()
+
+	query := `
+		UPDATE driver_shifts SET
+			vehicle_id = :vehicle_id, end_time = :end_time, status = :status,
+			end_latitude = :end_latitude, end_longitude = :end_longitude,
+			total_trips = :total_trips, total_distance = :total_distance,
+			total_earnings = :total_earnings, fuel_consumed = :fuel_consumed,
+			average_speed = :average_speed, idle_minutes = :idle_minutes,
+			break_minutes = :break_minutes, rest_compliant = :rest_compliant,
+			metadata = :metadata, updated_at = :updated_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, shift)
+	if err != nil {
+		r.logger.Error("Failed to update shift",
+			zap.Error(err),
+			zap.String("shift_id", shift.ID.String()),
+		)
+		return fmt.Errorf("failed to update shift: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return entities.ErrShiftNotFound
+	}
+
+	return nil
+}
+
+// List получает список смен с фильтрами
+func (r *shiftRepository) List(ctx context.Context, filters *entities.ShiftFilters) ([]*entities.DriverShift, error) {
+	query, args := r.buildListQuery(filters, false)
+
+	var shifts []*entities.DriverShift
+	if err := r.db.ReplicaSelectContext(ctx, &shifts, query, args...); err != nil {
+		r.logger.Error("Failed to list shifts", zap.Error(err))
+		return nil, fmt.Errorf("failed to list shifts: %w", err)
+	}
+
+	return shifts, nil
+}
+
+// Count возвращает количество смен с фильтрами
+func (r *shiftRepository) Count(ctx context.Context, filters *entities.ShiftFilters) (int, error) {
+	query, args := r.buildListQuery(filters, true)
+
+	var count int
+	if err := r.db.ReplicaGetContext(ctx, &count, query, args...); err != nil {
+		r.logger.Error("Failed to count shifts", zap.Error(err))
+		return 0, fmt.Errorf("failed to count shifts: %w", err)
+	}
+
+	return count, nil
+}
+
+// EraseLocations удаляет координаты начала и окончания смен водителя
+func (r *shiftRepository) EraseLocations(ctx context.Context, driverID uuid.UUID) (int, error) {
+	query := `
+		UPDATE driver_shifts SET
+			start_latitude = NULL, start_longitude = NULL,
+			end_latitude = NULL, end_longitude = NULL, updated_at = NOW()
+		WHERE driver_id = $1
+			AND (start_latitude IS NOT NULL OR end_latitude IS NOT NULL)`
+
+	result, err := r.db.ExecIdempotentContext(ctx, query, driverID)
+	if err != nil {
+		r.logger.Error("Failed to erase shift locations",
+			zap.Error(err),
+			zap.String("driver_id", driverID.String()),
+		)
+		return 0, fmt.Errorf("failed to erase shift locations: %w", err)
+	}
+
+	erased, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(erased), nil
+}
+
+// shiftSortColumns допустимые поля сортировки смен
+var shiftSortColumns = map[string]bool{
+	"start_time":     true,
+	"end_time":       true,
+	"total_trips":    true,
+	"total_distance": true,
+	"total_earnings": true,
+	"created_at":     true,
+}
+
+// buildListQuery строит SQL запрос для получения списка смен
+func (r *shiftRepository) buildListQuery(filters *entities.ShiftFilters, isCount bool) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	argCount := 0
+
+	selectClause := "SELECT * "
+	if isCount {
+		selectClause = "SELECT COUNT(*) "
+	}
+	query := selectClause + "FROM driver_shifts WHERE 1=1"
+
+	if filters != nil {
+		if filters.DriverID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("driver_id = $%d", argCount))
+			args = append(args, *filters.DriverID)
+		}
+
+		if filters.VehicleID != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("vehicle_id = $%d", argCount))
+			args = append(args, *filters.VehicleID)
+		}
+
+		if len(filters.Status) > 0 {
+			placeholders := make([]string, len(filters.Status))
+			for i, status := range filters.Status {
+				argCount++
+				placeholders[i] = fmt.Sprintf("$%d", argCount)
+				args = append(args, status)
+			}
+			conditions = append(conditions, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ",")))
+		}
+
+		if filters.From != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("start_time >= $%d", argCount))
+			args = append(args, *filters.From)
+		}
+
+		if filters.To != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("start_time <= $%d", argCount))
+			args = append(args, *filters.To)
+		}
+
+		if filters.MinEarnings != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("total_earnings >= $%d", argCount))
+			args = append(args, *filters.MinEarnings)
+		}
+
+		if filters.MaxEarnings != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("total_earnings <= $%d", argCount))
+			args = append(args, *filters.MaxEarnings)
+		}
+
+		if filters.MinTrips != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("total_trips >= $%d", argCount))
+			args = append(args, *filters.MinTrips)
+		}
+
+		if filters.MaxTrips != nil {
+			argCount++
+			conditions = append(conditions, fmt.Sprintf("total_trips <= $%d", argCount))
+			args = append(args, *filters.MaxTrips)
+		}
+	}
+
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	if !isCount && filters != nil {
+		orderBy := "ORDER BY start_time DESC"
+		if shiftSortColumns[filters.SortBy] {
+			direction := "ASC"
+			if filters.SortDirection == "desc" {
+				direction = "DESC"
+			}
+			orderBy = fmt.Sprintf("ORDER BY %s %s", filters.SortBy, direction)
+		}
+		query += " " + orderBy
+
+		if filters.Limit > 0 {
+			argCount++
+			query += fmt.Sprintf(" LIMIT $%d", argCount)
+			args = append(args, filters.Limit)
+		}
+
+		if filters.Offset > 0 {
+			argCount++
+			query += fmt.Sprintf(" OFFSET $%d", argCount)
+			args = append(args, filters.Offset)
+		}
+	}
+
+	return query, args
+}
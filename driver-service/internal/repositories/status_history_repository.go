@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+// StatusHistoryRepository интерфейс для чтения истории статусов водителей. Записи истории
+// создают репозитории, меняющие статус, в одной транзакции со сменой
+type StatusHistoryRepository interface {
+	// List возвращает смены статуса водителя по фильтрам в порядке времени
+	List(ctx context.Context, filters *entities.StatusHistoryFilters) ([]*entities.StatusTransition, error)
+}
+
+// insertStatusTransitionQuery вставка записи истории статусов
+const insertStatusTransitionQuery = `
+		INSERT INTO driver_status_history (
+			id, driver_id, from_status, to_status, actor, request_id, snapshot, changed_at
+		) VALUES (
+			:id, :driver_id, :from_status, :to_status, :actor, :request_id, :snapshot, :changed_at
+		)`
+
+// recordStatusTransition сохраняет смену статуса водителя в транзакции tx
+func recordStatusTransition(ctx context.Context, tx *sqlx.Tx, driverID uuid.UUID, from *entities.Status, to entities.Status, at time.Time) error {
+	transition := entities.NewStatusTransition(ctx, driverID, from, to, at)
+	if _, err := tx.NamedExecContext(ctx, insertStatusTransitionQuery, transition); err != nil {
+		return fmt.Errorf("failed to record status transition: %w", err)
+	}
+	return nil
+}
+
+// lockDriverStatus блокирует строку водителя до конца транзакции tx и возвращает его статус
+func lockDriverStatus(ctx context.Context, tx *sqlx.Tx, driverID uuid.UUID) (entities.Status, error) {
+	var status entities.Status
+	err := tx.GetContext(ctx, &status,
+		`SELECT status FROM drivers WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", entities.ErrDriverNotFound
+		}
+		return "", err
+	}
+	return status, nil
+}
+
+// statusHistoryRepository реализация StatusHistoryRepository
+type statusHistoryRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewStatusHistoryRepository создает новый репозиторий истории статусов
+func NewStatusHistoryRepository(db *database.DB, logger *zap.Logger) StatusHistoryRepository {
+	return &statusHistoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// List получает смены статуса водителя в порядке времени
+func (r *statusHistoryRepository) List(ctx context.Context, filters *entities.StatusHistoryFilters) ([]*entities.StatusTransition, error) {
+	args := []interface{}{filters.DriverID}
+	query := `SELECT * FROM driver_status_history WHERE driver_id = $1`
+	if filters.From != nil {
+		args = append(args, *filters.From)
+		query += fmt.Sprintf(" AND changed_at >= $%d", len(args))
+	}
+	if filters.To != nil {
+		args = append(args, *filters.To)
+		query += fmt.Sprintf(" AND changed_at < $%d", len(args))
+	}
+	args = append(args, filters.Limit, filters.Offset)
+	query += fmt.Sprintf(" ORDER BY changed_at, id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	var history []*entities.StatusTransition
+	if err := r.db.SelectContext(ctx, &history, query, args...); err != nil {
+		r.logger.Error("Failed to list driver status history",
+			zap.Error(err),
+			zap.String("driver_id", filters.DriverID.String()),
+		)
+		return nil, fmt.Errorf("failed to list driver status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// supplyLatestQuery последняя точка каждого водителя не старше $1 внутри области $2-$5
+// с ячейкой geohash длиной $6. Последняя точка выбирается до отбора по области: водитель,
+// уехавший из области, не учитывается по старой точке. Покрывающий индекс
+// idx_driver_locations_supply позволяет обойтись без чтения таблицы
+const supplyLatestQuery = `
+	WITH latest AS (
+		SELECT DISTINCT ON (driver_id) driver_id, latitude, longitude, recorded_at
+		FROM driver_locations
+		WHERE recorded_at >= $1
+		ORDER BY driver_id, recorded_at DESC
+	)
+	SELECT driver_id, recorded_at,
+		ST_GeoHash(ST_SetSRID(ST_MakePoint(longitude::double precision, latitude::double precision), 4326), $6) AS geohash
+	FROM latest
+	WHERE latitude BETWEEN $2 AND $3 AND longitude BETWEEN $4 AND $5`
+
+// SupplyRepository интерфейс для карты предложения водителей
+type SupplyRepository interface {
+	// CountByCell считает водителей по ячейкам geohash и статусам по последним точкам
+	// не старше query.Since внутри query.Bounds; удаленные водители не учитываются
+	CountByCell(ctx context.Context, query *entities.SupplyHeatmapQuery) ([]*entities.SupplyCellCount, error)
+}
+
+// supplyArgs параметры supplyLatestQuery
+func supplyArgs(query *entities.SupplyHeatmapQuery) []interface{} {
+	return []interface{}{
+		query.Since,
+		query.Bounds.SouthWest.Latitude, query.Bounds.NorthEast.Latitude,
+		query.Bounds.SouthWest.Longitude, query.Bounds.NorthEast.Longitude,
+		query.Precision,
+	}
+}
+
+// supplyRepository реализация SupplyRepository для местоположений в основной базе
+type supplyRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewSupplyRepository создает новый репозиторий карты предложения
+func NewSupplyRepository(db *database.DB, logger *zap.Logger) SupplyRepository {
+	return &supplyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CountByCell агрегирует последние точки водителей вместе с их статусами одним запросом
+func (r *supplyRepository) CountByCell(ctx context.Context, query *entities.SupplyHeatmapQuery) ([]*entities.SupplyCellCount, error) {
+	countQuery := `
+		WITH cells AS (` + supplyLatestQuery + `
+		)
+		SELECT c.geohash, d.status, COUNT(*) AS drivers
+		FROM cells c
+		JOIN drivers d ON d.id = c.driver_id
+		WHERE d.deleted_at IS NULL
+		GROUP BY c.geohash, d.status
+		ORDER BY c.geohash, d.status`
+
+	var counts []*entities.SupplyCellCount
+	if err := r.db.ReplicaSelectContext(ctx, &counts, countQuery, supplyArgs(query)...); err != nil {
+		r.logger.Error("Failed to count driver supply", zap.Error(err))
+		return nil, fmt.Errorf("failed to count driver supply: %w", err)
+	}
+
+	return counts, nil
+}
+
+// supplyDriverCell ячейка последней точки водителя
+type supplyDriverCell struct {
+	DriverID   uuid.UUID `db:"driver_id"`
+	RecordedAt time.Time `db:"recorded_at"`
+	Geohash    string    `db:"geohash"`
+}
+
+// shardedSupplyRepository реализация SupplyRepository для шардированных местоположений.
+// Ячейки водителей считаются на каждом шарде, статусы — в основной базе, где хранятся водители
+type shardedSupplyRepository struct {
+	router  *database.ShardRouter
+	primary *database.DB
+	logger  *zap.Logger
+}
+
+// NewShardedSupplyRepository создает репозиторий карты предложения поверх шардов
+func NewShardedSupplyRepository(router *database.ShardRouter, primary *database.DB, logger *zap.Logger) SupplyRepository {
+	return &shardedSupplyRepository{
+		router:  router,
+		primary: primary,
+		logger:  logger,
+	}
+}
+
+// CountByCell собирает ячейки водителей со всех шардов и группирует их по статусам в основной базе
+func (r *shardedSupplyRepository) CountByCell(ctx context.Context, query *entities.SupplyHeatmapQuery) ([]*entities.SupplyCellCount, error) {
+	cells, err := r.driverCells(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) == 0 {
+		return []*entities.SupplyCellCount{}, nil
+	}
+
+	driverIDs := make([]uuid.UUID, len(cells))
+	geohashes := make([]string, len(cells))
+	for i, cell := range cells {
+		driverIDs[i] = cell.DriverID
+		geohashes[i] = cell.Geohash
+	}
+
+	countQuery := `
+		SELECT c.geohash, d.status, COUNT(*) AS drivers
+		FROM unnest($1::uuid[], $2::text[]) AS c(driver_id, geohash)
+		JOIN drivers d ON d.id = c.driver_id
+		WHERE d.deleted_at IS NULL
+		GROUP BY c.geohash, d.status
+		ORDER BY c.geohash, d.status`
+
+	var counts []*entities.SupplyCellCount
+	if err := r.primary.ReplicaSelectContext(ctx, &counts, countQuery, pq.Array(driverIDs), pq.Array(geohashes)); err != nil {
+		r.logger.Error("Failed to count driver supply", zap.Error(err), zap.Int("drivers", len(cells)))
+		return nil, fmt.Errorf("failed to count driver supply: %w", err)
+	}
+
+	return counts, nil
+}
+
+// driverCells выполняет supplyLatestQuery на всех шардах параллельно. До очистки после
+// переноса города точки водителя есть на двух шардах: учитывается ячейка самой новой точки
+func (r *shardedSupplyRepository) driverCells(ctx context.Context, query *entities.SupplyHeatmapQuery) ([]supplyDriverCell, error) {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		latest = make(map[uuid.UUID]supplyDriverCell)
+		errs   []error
+	)
+	for _, name := range r.router.Names() {
+		db, err := r.router.Shard(name)
+		if err != nil {
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func(name string, db *database.DB) {
+			defer wg.Done()
+			var shardCells []supplyDriverCell
+			err := db.ReplicaSelectContext(ctx, &shardCells, supplyLatestQuery, supplyArgs(query)...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("shard %s: %w", name, err))
+				return
+			}
+			for _, cell := range shardCells {
+				if current, ok := latest[cell.DriverID]; !ok || cell.RecordedAt.After(current.RecordedAt) {
+					latest[cell.DriverID] = cell
+				}
+			}
+		}(name, db)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		r.logger.Error("Cross-shard supply query failed", zap.Error(errors.Join(errs...)))
+		return nil, errors.Join(errs...)
+	}
+
+	cells := make([]supplyDriverCell, 0, len(latest))
+	for _, cell := range latest {
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"driver-service/internal/infrastructure/database"
+)
+
+// TxManager выполняет операции нескольких репозиториев атомарно. Репозитории берут
+// транзакцию из контекста, переданного в fn, поэтому все запросы с ним фиксируются
+// или откатываются вместе. Вложенный вызов присоединяется к внешней транзакции
+type TxManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// NewTxManager создает TxManager для репозиториев PostgreSQL
+func NewTxManager(db *database.DB) TxManager {
+	return db
+}
+
+// WithTransaction выполняет fn в транзакции tx. Без tx (nil, например при хранении в памяти)
+// fn выполняется без транзакции
+func WithTransaction(ctx context.Context, tx TxManager, fn func(ctx context.Context) error) error {
+	if tx == nil {
+		return fn(ctx)
+	}
+	return tx.WithTransaction(ctx, fn)
+}
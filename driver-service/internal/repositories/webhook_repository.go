@@ -0,0 +1,241 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/infrastructure/database"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// WebhookRepository интерфейс для подписок на события и журнала доставок
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*entities.WebhookSubscription, error)
+	// DeleteSubscription удаляет подписку вместе с журналом ее доставок
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+
+	CreateDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uuid.UUID) (*entities.WebhookDelivery, error)
+	// ListDeliveries возвращает журнал доставок подписки, начиная с новых
+	ListDeliveries(ctx context.Context, filters *entities.WebhookDeliveryFilters) ([]*entities.WebhookDelivery, error)
+	// UpdateDelivery сохраняет итог попытки доставки
+	UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// ClaimDueDeliveries захватывает до limit ожидающих доставок с наступившим временем попытки,
+	// откладывая их следующую попытку на lease, чтобы другие экземпляры их не подхватили
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error)
+}
+
+// webhookRepository реализация WebhookRepository
+type webhookRepository struct {
+	db     *database.DB
+	logger *zap.Logger
+}
+
+// NewWebhookRepository создает новый репозиторий вебхуков
+func NewWebhookRepository(db *database.DB, logger *zap.Logger) WebhookRepository {
+	return &webhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// CreateSubscription сохраняет подписку
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *entities.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (
+			id, name, url, event_types, secret, created_by, created_at
+		) VALUES (
+			:id, :name, :url, :event_types, :secret, :created_by, :created_at
+		)`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, subscription); err != nil {
+		r.logger.Error("Failed to create webhook subscription",
+			zap.Error(err),
+			zap.String("name", subscription.Name),
+		)
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription получает подписку по ID
+func (r *webhookRepository) GetSubscription(ctx context.Context, id uuid.UUID) (*entities.WebhookSubscription, error) {
+	var subscription entities.WebhookSubscription
+	if err := r.db.GetContext(ctx, &subscription, `SELECT * FROM webhook_subscriptions WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrWebhookNotFound
+		}
+		r.logger.Error("Failed to get webhook subscription",
+			zap.Error(err),
+			zap.String("subscription_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+	return &subscription, nil
+}
+
+// ListSubscriptions получает все подписки
+func (r *webhookRepository) ListSubscriptions(ctx context.Context) ([]*entities.WebhookSubscription, error) {
+	var subscriptions []*entities.WebhookSubscription
+	if err := r.db.SelectContext(ctx, &subscriptions, `SELECT * FROM webhook_subscriptions ORDER BY created_at`); err != nil {
+		r.logger.Error("Failed to list webhook subscriptions", zap.Error(err))
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription удаляет подписку
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecIdempotentContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		r.logger.Error("Failed to delete webhook subscription",
+			zap.Error(err),
+			zap.String("subscription_id", id.String()),
+		)
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// CreateDeliveries сохраняет доставки одного события всем подписчикам
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, subscription_id, event_id, event_type, driver_id, payload, status,
+			attempts, next_attempt_at, redelivery_of, created_at
+		) VALUES (
+			:id, :subscription_id, :event_id, :event_type, :driver_id, :payload, :status,
+			:attempts, :next_attempt_at, :redelivery_of, :created_at
+		) ON CONFLICT (id) DO NOTHING`
+
+	if _, err := r.db.NamedExecIdempotentContext(ctx, query, deliveries); err != nil {
+		r.logger.Error("Failed to create webhook deliveries",
+			zap.Error(err),
+			zap.String("event_type", deliveries[0].EventType),
+		)
+		return fmt.Errorf("failed to create webhook deliveries: %w", err)
+	}
+
+	return nil
+}
+
+// GetDelivery получает доставку по ID
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*entities.WebhookDelivery, error) {
+	var delivery entities.WebhookDelivery
+	if err := r.db.GetContext(ctx, &delivery, `SELECT * FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, entities.ErrWebhookDeliveryNotFound
+		}
+		r.logger.Error("Failed to get webhook delivery",
+			zap.Error(err),
+			zap.String("delivery_id", id.String()),
+		)
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries получает журнал доставок подписки
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filters *entities.WebhookDeliveryFilters) ([]*entities.WebhookDelivery, error) {
+	conditions := []string{"subscription_id = $1"}
+	args := []interface{}{filters.SubscriptionID}
+	if filters.Status != "" {
+		args = append(args, filters.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := "SELECT * FROM webhook_deliveries WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY created_at DESC"
+	if filters.Limit > 0 {
+		args = append(args, filters.Limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if filters.Offset > 0 {
+		args = append(args, filters.Offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	var deliveries []*entities.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, args...); err != nil {
+		r.logger.Error("Failed to list webhook deliveries",
+			zap.Error(err),
+			zap.String("subscription_id", filters.SubscriptionID.String()),
+		)
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery сохраняет итог попытки доставки
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries SET
+			status = :status, attempts = :attempts, next_attempt_at = :next_attempt_at,
+			last_attempt_at = :last_attempt_at, last_status_code = :last_status_code,
+			last_error = :last_error, delivered_at = :delivered_at
+		WHERE id = :id`
+
+	result, err := r.db.NamedExecIdempotentContext(ctx, query, delivery)
+	if err != nil {
+		r.logger.Error("Failed to update webhook delivery",
+			zap.Error(err),
+			zap.String("delivery_id", delivery.ID.String()),
+		)
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return entities.ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// ClaimDueDeliveries захватывает ожидающие доставки, начиная с самых давних.
+// FOR UPDATE SKIP LOCKED гарантирует, что параллельные экземпляры получат разные доставки
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	// Захват не повторяется: после обрыва соединения он мог быть применен
+	var deliveries []*entities.WebhookDelivery
+	if err := r.db.SelectOnceContext(ctx, &deliveries, query, now, now.Add(lease), limit); err != nil {
+		r.logger.Error("Failed to claim due webhook deliveries", zap.Error(err))
+		return nil, fmt.Errorf("failed to claim due webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,211 @@
+package driverservicetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Contract ожидания одного потребителя от API driver-service. Файлы контрактов лежат в
+// api/contracts и проверяются против сервиса тестом этого пакета (provider verification)
+type Contract struct {
+	Consumer     string        `json:"consumer"`
+	Provider     string        `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction запрос потребителя и ожидаемый ответ
+type Interaction struct {
+	Description   string        `json:"description"`
+	ProviderState ProviderState `json:"provider_state"`
+	Request       Request       `json:"request"`
+	Response      Response      `json:"response"`
+}
+
+// ProviderState состояние сервиса перед запросом, см. Server.SetState
+type ProviderState struct {
+	Name   string            `json:"name"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+// Request запрос потребителя
+type Request struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// Response ожидаемый ответ. Тело сверяется по типам: у ответа должны быть все поля
+// из Body с теми же JSON типами, лишние поля допускаются, каждый элемент массива
+// сверяется с первым элементом из Body. Значения сверяются точно только для путей
+// из Equal, например "$.status" или "$.drivers[].driver_id"
+type Response struct {
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Equal  []string        `json:"equal,omitempty"`
+}
+
+// LoadContracts читает все контракты *.json из каталога dir по алфавиту
+func LoadContracts(dir string) ([]*Contract, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	contracts := make([]*Contract, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var contract Contract
+		if err := json.Unmarshal(data, &contract); err != nil {
+			return nil, fmt.Errorf("contract %s: %w", path, err)
+		}
+		contracts = append(contracts, &contract)
+	}
+	return contracts, nil
+}
+
+// Verify выполняет запрос взаимодействия к сервису по адресу baseURL и сверяет ответ
+// с ожидаемым. Состояние сервиса должно быть подготовлено заранее
+func (i *Interaction) Verify(client *http.Client, baseURL string) error {
+	url := baseURL + i.Request.Path
+	if i.Request.Query != "" {
+		url += "?" + i.Request.Query
+	}
+
+	var body io.Reader
+	if len(i.Request.Body) > 0 {
+		body = bytes.NewReader(i.Request.Body)
+	}
+	req, err := http.NewRequest(i.Request.Method, url, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range i.Request.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != i.Response.Status {
+		return fmt.Errorf("status %d, expected %d: %s", resp.StatusCode, i.Response.Status, data)
+	}
+	if len(i.Response.Body) == 0 {
+		return nil
+	}
+
+	var expected, actual interface{}
+	if err := json.Unmarshal(i.Response.Body, &expected); err != nil {
+		return fmt.Errorf("invalid expected body: %w", err)
+	}
+	if err := json.Unmarshal(data, &actual); err != nil {
+		return fmt.Errorf("response is not JSON: %w", err)
+	}
+
+	equal := make(map[string]bool, len(i.Response.Equal))
+	for _, path := range i.Response.Equal {
+		equal[path] = true
+	}
+	return matchLike("$", expected, actual, equal)
+}
+
+// matchLike сверяет значение ответа с ожидаемым по типам, а по путям из equal — точно
+func matchLike(path string, expected, actual interface{}, equal map[string]bool) error {
+	if equal[path] {
+		if !reflect.DeepEqual(expected, actual) {
+			return fmt.Errorf("%s: got %v, expected %v", path, actual, expected)
+		}
+		return nil
+	}
+
+	switch exp := expected.(type) {
+	case nil:
+		// null в контракте означает любое значение
+		return nil
+	case map[string]interface{}:
+		act, ok := actual.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: got %s, expected object", path, jsonType(actual))
+		}
+		keys := make([]string, 0, len(exp))
+		for key := range exp {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := act[key]
+			if !ok {
+				return fmt.Errorf("%s.%s: missing", path, key)
+			}
+			if err := matchLike(path+"."+key, exp[key], value, equal); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []interface{}:
+		act, ok := actual.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: got %s, expected array", path, jsonType(actual))
+		}
+		if len(exp) == 0 {
+			return nil
+		}
+		if len(act) == 0 {
+			return fmt.Errorf("%s: got empty array, expected at least one element", path)
+		}
+		for _, value := range act {
+			if err := matchLike(path+"[]", exp[0], value, equal); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if jsonType(expected) != jsonType(actual) {
+			return fmt.Errorf("%s: got %s, expected %s", path, jsonType(actual), jsonType(expected))
+		}
+		return nil
+	}
+}
+
+// jsonType возвращает имя JSON типа разобранного значения
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return strings.ToLower(reflect.TypeOf(value).Kind().String())
+	}
+}
@@ -0,0 +1,94 @@
+package driverservicetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// contractsDir каталог контрактов потребителей относительно пакета
+const contractsDir = "../../api/contracts"
+
+// TestProviderContracts проверяет, что сервис выполняет контракты всех потребителей
+func TestProviderContracts(t *testing.T) {
+	contracts, err := LoadContracts(contractsDir)
+	require.NoError(t, err)
+	require.NotEmpty(t, contracts)
+
+	for _, contract := range contracts {
+		for _, interaction := range contract.Interactions {
+			interaction := interaction
+			t.Run(contract.Consumer+"/"+interaction.Description, func(t *testing.T) {
+				srv := NewServer()
+				defer srv.Close()
+
+				require.NoError(t, srv.SetState(interaction.ProviderState.Name, interaction.ProviderState.Params))
+				assert.NoError(t, interaction.Verify(srv.Client(), srv.URL))
+			})
+		}
+	}
+}
+
+func TestMatchLike(t *testing.T) {
+	var expected, actual interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"a","items":[{"n":1}],"any":null}`), &expected))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"b","items":[{"n":2,"extra":true},{"n":3}],"any":[1]}`), &actual))
+	assert.NoError(t, matchLike("$", expected, actual, nil))
+	assert.Error(t, matchLike("$", expected, actual, map[string]bool{"$.id": true}))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"a","items":[{"n":"2"}],"any":1}`), &actual))
+	assert.EqualError(t, matchLike("$", expected, actual, nil), "$.items[].n: got string, expected number")
+
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"a","items":[]}`), &actual))
+	assert.EqualError(t, matchLike("$", expected, actual, nil), "$.any: missing")
+}
+
+func TestServer_CannedData(t *testing.T) {
+	srv := NewServer(WithCannedData(), WithDriver(Driver{Phone: "+79000000099", Email: "extra@driver.test",
+		FirstName: "Анна", LastName: "Дополнительная", LicenseNumber: "TEST000099"}))
+	defer srv.Close()
+
+	resp, err := srv.Client().Get(srv.URL + "/api/v1/drivers/" + OnShiftDriverID.String())
+	require.NoError(t, err)
+	var driver struct {
+		ID     uuid.UUID `json:"id"`
+		Status string    `json:"status"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&driver))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, OnShiftDriverID, driver.ID)
+	assert.Equal(t, "on_shift", driver.Status)
+
+	resp, err = srv.Client().Get(srv.URL + "/api/v1/locations/nearby?latitude=55.7539&longitude=37.6208&radius_km=2")
+	require.NoError(t, err)
+	var nearby struct {
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&nearby))
+	resp.Body.Close()
+	assert.Equal(t, len(CannedLocations), nearby.Count)
+
+	resp, err = srv.Client().Get(srv.URL + "/api/v1/drivers?limit=10")
+	require.NoError(t, err)
+	var list struct {
+		Drivers []json.RawMessage `json:"drivers"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	assert.Len(t, list.Drivers, len(CannedDrivers)+1)
+
+	resp, err = srv.Client().Get(srv.URL + "/api/v1/drivers?tag=VIP-capable")
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	resp.Body.Close()
+	assert.Len(t, list.Drivers, 1)
+
+	_, err = srv.AddDriver(Driver{Status: "unknown"})
+	assert.Error(t, err)
+}
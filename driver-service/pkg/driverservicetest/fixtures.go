@@ -0,0 +1,110 @@
+package driverservicetest
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ID готовых водителей; постоянны, чтобы на них можно было ссылаться в ожиданиях тестов
+var (
+	AvailableDriverID = uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000001")
+	OnShiftDriverID   = uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000002")
+	BlockedDriverID   = uuid.MustParse("6f1c2d3e-0000-4000-8000-000000000003")
+)
+
+// CannedDrivers готовые водители для WithCannedData
+var CannedDrivers = []Driver{
+	{
+		ID:            AvailableDriverID,
+		Phone:         "+79000000001",
+		Email:         "available@driver.test",
+		FirstName:     "Иван",
+		LastName:      "Свободный",
+		LicenseNumber: "TEST000001",
+		Status:        "available",
+		Rating:        4.8,
+		TotalTrips:    120,
+		Tags:          []string{"vip-capable", "pet-friendly"},
+	},
+	{
+		ID:            OnShiftDriverID,
+		Phone:         "+79000000002",
+		Email:         "on-shift@driver.test",
+		FirstName:     "Петр",
+		LastName:      "Сменный",
+		LicenseNumber: "TEST000002",
+		Status:        "on_shift",
+		Rating:        4.5,
+		TotalTrips:    57,
+	},
+	{
+		ID:            BlockedDriverID,
+		Phone:         "+79000000003",
+		Email:         "blocked@driver.test",
+		FirstName:     "Олег",
+		LastName:      "Заблокированный",
+		LicenseNumber: "TEST000003",
+		Status:        "blocked",
+		Rating:        3.1,
+		TotalTrips:    9,
+	},
+}
+
+// CannedLocations текущие точки готовых водителей на линии (центр Москвы)
+var CannedLocations = []Location{
+	{DriverID: AvailableDriverID, Latitude: 55.751244, Longitude: 37.618423},
+	{DriverID: OnShiftDriverID, Latitude: 55.757814, Longitude: 37.630185},
+}
+
+// Состояния сервиса, на которые ссылаются контракты потребителей (provider_state)
+const (
+	// StateDriverExists водитель params["id"] существует; params["status"] задает его статус
+	StateDriverExists = "driver exists"
+	// StateDriverHasLocation водитель params["id"] на линии в точке params["latitude"],
+	// params["longitude"]
+	StateDriverHasLocation = "driver has current location"
+	// StateNoDrivers в сервисе нет водителей
+	StateNoDrivers = "no drivers"
+)
+
+// SetState приводит сервис к состоянию name из контракта потребителя
+func (s *Server) SetState(name string, params map[string]string) error {
+	switch name {
+	case StateNoDrivers:
+		return nil
+	case StateDriverExists, StateDriverHasLocation:
+		id, err := uuid.Parse(params["id"])
+		if err != nil {
+			return fmt.Errorf("state %q: invalid id: %w", name, err)
+		}
+		driver := Driver{
+			ID:            id,
+			Phone:         "+7900" + id.String()[:7],
+			Email:         id.String() + "@driver.test",
+			FirstName:     "Контракт",
+			LastName:      "Водитель",
+			LicenseNumber: "C" + id.String()[:8],
+			Status:        params["status"],
+			Rating:        4.9,
+		}
+		if _, err := s.AddDriver(driver); err != nil {
+			return fmt.Errorf("state %q: %w", name, err)
+		}
+		if name == StateDriverExists {
+			return nil
+		}
+
+		var location Location
+		location.DriverID = id
+		if _, err := fmt.Sscan(params["latitude"], &location.Latitude); err != nil {
+			return fmt.Errorf("state %q: invalid latitude: %w", name, err)
+		}
+		if _, err := fmt.Sscan(params["longitude"], &location.Longitude); err != nil {
+			return fmt.Errorf("state %q: invalid longitude: %w", name, err)
+		}
+		return s.AddLocation(location)
+	default:
+		return fmt.Errorf("unknown provider state %q", name)
+	}
+}
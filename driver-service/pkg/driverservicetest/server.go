@@ -0,0 +1,212 @@
+// Package driverservicetest запускает в процессе поддельный driver-service для интеграционных
+// тестов сервисов-потребителей. Сервер отвечает настоящими обработчиками API водителей и
+// местоположений поверх in-memory хранилища, поэтому форма ответов совпадает с рабочим
+// сервисом; аутентификация отключена.
+//
+//	srv := driverservicetest.NewServer(driverservicetest.WithCannedData())
+//	defer srv.Close()
+//	client := dispatch.NewDriverClient(srv.URL)
+package driverservicetest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"driver-service/internal/config"
+	"driver-service/internal/domain/entities"
+	"driver-service/internal/domain/services"
+	httpServer "driver-service/internal/interfaces/http"
+	"driver-service/internal/interfaces/http/handlers"
+	"driver-service/internal/repositories/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// requestTimeout ограничение времени обработки запроса поддельным сервисом
+const requestTimeout = 10 * time.Second
+
+// Driver водитель, которым заполняется поддельный сервис. Незаданные ID и Status
+// заполняются случайным ID и статусом available
+type Driver struct {
+	ID            uuid.UUID
+	Phone         string
+	Email         string
+	FirstName     string
+	LastName      string
+	LicenseNumber string
+	Status        string
+	Rating        float64
+	TotalTrips    int
+	// Tags метки водителя, например vip-capable; приводятся к нормальной форме
+	Tags []string
+}
+
+// Location точка местоположения водителя. Незаданное RecordedAt заполняется текущим
+// временем: текущим местоположением считается только точка не старше 10 минут
+type Location struct {
+	DriverID   uuid.UUID
+	Latitude   float64
+	Longitude  float64
+	RecordedAt time.Time
+}
+
+// Option настраивает поддельный сервис
+type Option func(*Server)
+
+// WithDriver добавляет водителя при запуске
+func WithDriver(driver Driver) Option {
+	return func(s *Server) {
+		s.seed = append(s.seed, func() error {
+			_, err := s.AddDriver(driver)
+			return err
+		})
+	}
+}
+
+// WithLocation добавляет точку местоположения при запуске
+func WithLocation(location Location) Option {
+	return func(s *Server) {
+		s.seed = append(s.seed, func() error {
+			return s.AddLocation(location)
+		})
+	}
+}
+
+// WithCannedData добавляет при запуске готовых водителей CannedDrivers и их точки
+// CannedLocations
+func WithCannedData() Option {
+	return func(s *Server) {
+		for _, driver := range CannedDrivers {
+			WithDriver(driver)(s)
+		}
+		for _, location := range CannedLocations {
+			WithLocation(location)(s)
+		}
+	}
+}
+
+// WithLogger задает журнал поддельного сервиса; по умолчанию журнал не ведется
+func WithLogger(logger *zap.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// Server поддельный driver-service, запущенный на локальном порту
+type Server struct {
+	// URL адрес сервера без префикса API, например http://127.0.0.1:54321
+	URL string
+
+	httpServer *httptest.Server
+	drivers    *memory.DriverRepository
+	locations  *memory.LocationRepository
+	logger     *zap.Logger
+	seed       []func() error
+}
+
+// NewServer запускает поддельный сервис. Ошибка заполнения данными из опций означает
+// неверные данные теста, поэтому вызывает панику, как httptest.NewServer при занятом порте
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		drivers:   memory.NewDriverRepository(),
+		locations: memory.NewLocationRepository(),
+		logger:    zap.NewNop(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	for _, seed := range s.seed {
+		if err := seed(); err != nil {
+			panic("driverservicetest: " + err.Error())
+		}
+	}
+	s.seed = nil
+
+	s.httpServer = httptest.NewServer(s.router())
+	s.URL = s.httpServer.URL
+	return s
+}
+
+// router собирает маршруты API с настоящими обработчиками
+func (s *Server) router() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	driverService := services.NewDriverService(s.drivers, memory.NewDocumentRepository(), nil, noopEventPublisher{}, s.logger)
+	locationService := services.NewLocationService(s.locations, s.drivers, nil, noopEventPublisher{}, nil, nil, nil, services.LocationPolicy{}, s.logger)
+
+	cfg := &config.Config{
+		Server: config.ServerConfig{
+			Environment: "test",
+			Timeout:     requestTimeout,
+		},
+	}
+	server := httpServer.NewServer(cfg, s.logger, nil, nil, nil, nil,
+		handlers.NewDriverHandler(driverService, s.logger),
+		handlers.NewLocationHandler(locationService, s.logger),
+	)
+	return server.GetRouter()
+}
+
+// Close останавливает сервер
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Client возвращает HTTP клиент для обращения к серверу
+func (s *Server) Client() *http.Client {
+	return s.httpServer.Client()
+}
+
+// AddDriver добавляет водителя и возвращает его ID
+func (s *Server) AddDriver(driver Driver) (uuid.UUID, error) {
+	d := entities.NewDriver(driver.Phone, driver.Email, driver.FirstName, driver.LastName, driver.LicenseNumber)
+	if driver.ID != uuid.Nil {
+		d.ID = driver.ID
+	}
+	d.Status = entities.StatusAvailable
+	if driver.Status != "" {
+		d.Status = entities.Status(driver.Status)
+		if !d.Status.IsValid() {
+			return uuid.Nil, entities.ErrInvalidStatus
+		}
+	}
+	tags, err := entities.NormalizeDriverTags(driver.Tags)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	d.Tags = tags
+	d.CurrentRating = driver.Rating
+	d.TotalTrips = driver.TotalTrips
+	d.BirthDate = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	d.LicenseExpiry = d.CreatedAt.AddDate(5, 0, 0)
+	d.PassportSeries = "0000"
+	d.PassportNumber = "000000"
+
+	if err := s.drivers.Create(context.Background(), d); err != nil {
+		return uuid.Nil, err
+	}
+	return d.ID, nil
+}
+
+// AddLocation добавляет точку местоположения водителя
+func (s *Server) AddLocation(location Location) error {
+	recordedAt := location.RecordedAt
+	if recordedAt.IsZero() {
+		recordedAt = time.Now()
+	}
+	_, err := s.locations.Create(context.Background(),
+		entities.NewDriverLocation(location.DriverID, location.Latitude, location.Longitude, recordedAt))
+	return err
+}
+
+// noopEventPublisher отбрасывает доменные события: потребителям поддельного сервиса
+// они не доставляются
+type noopEventPublisher struct{}
+
+func (noopEventPublisher) PublishDriverEvent(ctx context.Context, eventType string, driverID uuid.UUID, data interface{}) error {
+	return nil
+}
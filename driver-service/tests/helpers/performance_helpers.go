@@ -190,7 +190,7 @@ func (h *PerformanceTestHelper) BenchmarkBatchLocationUpdates(ctx context.Contex
 			location.CreatedAt = location.RecordedAt
 		}
 
-		err := h.locationService.BatchUpdateLocations(ctx, locations)
+		_, err := h.locationService.BatchUpdateLocations(ctx, locations)
 		if err != nil {
 			errors++
 			h.t.Logf("Batch update error: %v", err)
@@ -248,7 +248,7 @@ func (h *PerformanceTestHelper) BenchmarkNearbyDriversSearch(ctx context.Context
 		searchLat := 55.7558 + (float64(i%20)-10)*0.01
 		searchLon := 37.6173 + (float64(i%20)-10)*0.01
 
-		_, err := h.locationService.GetNearbyDrivers(ctx, searchLat, searchLon, 5.0, 20)
+		_, err := h.locationService.GetNearbyDrivers(ctx, searchLat, searchLon, 5.0, 20, nil)
 		if err != nil {
 			errors++
 			h.t.Logf("Nearby search error: %v", err)
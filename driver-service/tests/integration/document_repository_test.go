@@ -128,7 +128,7 @@ func (suite *DocumentRepositoryTestSuite) TestGetDocumentByType() {
 	require.NoError(suite.T(), err)
 
 	// Act
-	foundDoc, err := suite.documentRepo.GetByDriverIDAndType(suite.ctx, suite.testDriverID, entities.DocumentTypeDriverLicense)
+	foundDoc, err := suite.documentRepo.GetCurrent(suite.ctx, suite.testDriverID, entities.DocumentTypeDriverLicense)
 
 	// Assert
 	require.NoError(suite.T(), err)
@@ -56,8 +56,8 @@ func (suite *DriverAPITestSuite) SetupSuite() {
 	eventBus := &mockEventPublisher{logger: logger}
 
 	// Инициализируем сервисы
-	suite.driverService = services.NewDriverService(driverRepo, documentRepo, eventBus, logger)
-	locationService := services.NewLocationService(locationRepo, driverRepo, eventBus, logger)
+	suite.driverService = services.NewDriverService(driverRepo, documentRepo, nil, eventBus, logger)
+	locationService := services.NewLocationService(locationRepo, driverRepo, nil, eventBus, nil, nil, nil, services.LocationPolicy{}, logger)
 
 	// Создаем handlers
 	driverHandler := httpHandlers.NewDriverHandler(suite.driverService, logger)
@@ -73,7 +73,7 @@ func (suite *DriverAPITestSuite) SetupSuite() {
 	}
 
 	// Создаем HTTP сервер
-	suite.server = httpServer.NewServer(cfg, logger, driverHandler, locationHandler)
+	suite.server = httpServer.NewServer(cfg, logger, nil, nil, nil, nil, driverHandler, locationHandler)
 	suite.router = suite.server.GetRouter()
 }
 
@@ -239,7 +239,8 @@ func (suite *DriverAPITestSuite) TestListDriversAPI() {
 	require.NoError(suite.T(), err)
 
 	assert.Len(suite.T(), response.Drivers, 3)
-	assert.Equal(suite.T(), 5, response.Total)
+	require.NotNil(suite.T(), response.Total)
+	assert.Equal(suite.T(), 5, *response.Total)
 	assert.Equal(suite.T(), 3, response.Limit)
 	assert.Equal(suite.T(), 0, response.Offset)
 	assert.True(suite.T(), response.HasMore)
@@ -434,8 +435,10 @@ func (suite *DriverAPITestSuite) TestDriverAPIPagination() {
 
 	assert.Len(suite.T(), page1.Drivers, 3)
 	assert.Len(suite.T(), page2.Drivers, 3)
-	assert.Equal(suite.T(), 7, page1.Total)
-	assert.Equal(suite.T(), 7, page2.Total)
+	require.NotNil(suite.T(), page1.Total)
+	assert.Equal(suite.T(), 7, *page1.Total)
+	require.NotNil(suite.T(), page2.Total)
+	assert.Equal(suite.T(), 7, *page2.Total)
 	assert.True(suite.T(), page1.HasMore)
 	assert.True(suite.T(), page2.HasMore)
 }
@@ -57,8 +57,8 @@ func (suite *LocationAPITestSuite) SetupSuite() {
 	eventBus := &mockEventPublisher{logger: logger}
 
 	// Инициализируем сервисы
-	suite.driverService = services.NewDriverService(driverRepo, documentRepo, eventBus, logger)
-	suite.locationService = services.NewLocationService(locationRepo, driverRepo, eventBus, logger)
+	suite.driverService = services.NewDriverService(driverRepo, documentRepo, nil, eventBus, logger)
+	suite.locationService = services.NewLocationService(locationRepo, driverRepo, nil, eventBus, nil, nil, nil, services.LocationPolicy{}, logger)
 
 	// Создаем handlers
 	driverHandler := httpHandlers.NewDriverHandler(suite.driverService, logger)
@@ -74,7 +74,7 @@ func (suite *LocationAPITestSuite) SetupSuite() {
 	}
 
 	// Создаем HTTP сервер
-	suite.server = httpServer.NewServer(cfg, logger, driverHandler, locationHandler)
+	suite.server = httpServer.NewServer(cfg, logger, nil, nil, nil, nil, driverHandler, locationHandler)
 	suite.router = suite.server.GetRouter()
 }
 
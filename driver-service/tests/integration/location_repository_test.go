@@ -60,7 +60,7 @@ func (suite *LocationRepositoryTestSuite) TestCreateLocation() {
 	location := fixtures.CreateTestLocation(suite.testDriverID)
 
 	// Act
-	err := suite.locationRepo.Create(suite.ctx, location)
+	_, err := suite.locationRepo.Create(suite.ctx, location)
 
 	// Assert
 	require.NoError(suite.T(), err)
@@ -79,7 +79,7 @@ func (suite *LocationRepositoryTestSuite) TestGetLatestLocation() {
 	locations := fixtures.CreateTestLocationHistory(suite.testDriverID, 5, 1*time.Minute)
 
 	for _, location := range locations {
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -120,7 +120,7 @@ func (suite *LocationRepositoryTestSuite) TestGetLocationHistory() {
 	locations[3].RecordedAt = now.Add(30 * time.Minute)  // Вне диапазона
 
 	for _, location := range locations {
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -143,7 +143,7 @@ func (suite *LocationRepositoryTestSuite) TestBatchCreateLocations() {
 	locations := fixtures.CreateTestLocationHistory(suite.testDriverID, 10, 30*time.Second)
 
 	// Act
-	err := suite.locationRepo.CreateBatch(suite.ctx, locations)
+	_, err := suite.locationRepo.CreateBatch(suite.ctx, locations)
 
 	// Assert
 	require.NoError(suite.T(), err)
@@ -180,7 +180,7 @@ func (suite *LocationRepositoryTestSuite) TestGetNearbyLocations() {
 	}
 
 	for _, location := range locations {
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -192,6 +192,42 @@ func (suite *LocationRepositoryTestSuite) TestGetNearbyLocations() {
 	assert.Len(suite.T(), nearbyLocations, 2) // Первые два местоположения
 }
 
+// TestGetNearbyUsesLatestLocation тестирует, что поиск учитывает только последние местоположения водителей
+func (suite *LocationRepositoryTestSuite) TestGetNearbyUsesLatestLocation() {
+	// Arrange
+	drivers := fixtures.CreateMultipleTestDrivers(3)
+	for _, driver := range drivers {
+		err := suite.driverRepo.Create(suite.ctx, driver)
+		require.NoError(suite.T(), err)
+	}
+
+	centerLat := 55.7558
+	centerLon := 37.6173
+	now := time.Now()
+
+	// Водитель 0 уехал из радиуса, водители 1 и 2 находятся в нем
+	left := fixtures.CreateTestLocationWithCoords(drivers[0].ID, centerLat+0.001, centerLon)
+	left.RecordedAt = now.Add(-10 * time.Minute)
+	moved := fixtures.CreateTestLocationWithCoords(drivers[0].ID, centerLat+0.2, centerLon+0.2)
+	moved.RecordedAt = now
+	far := fixtures.CreateTestLocationWithCoords(drivers[1].ID, centerLat+0.01, centerLon)
+	near := fixtures.CreateTestLocationWithCoords(drivers[2].ID, centerLat+0.002, centerLon)
+
+	for _, location := range []*entities.DriverLocation{left, moved, far, near} {
+		_, err := suite.locationRepo.Create(suite.ctx, location)
+		require.NoError(suite.T(), err)
+	}
+
+	// Act
+	nearbyLocations, err := suite.locationRepo.GetNearby(suite.ctx, centerLat, centerLon, 3.0, 10)
+
+	// Assert - ближайшие первыми, без устаревшей точки водителя 0
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), nearbyLocations, 2)
+	assert.Equal(suite.T(), near.ID, nearbyLocations[0].ID)
+	assert.Equal(suite.T(), far.ID, nearbyLocations[1].ID)
+}
+
 // TestDeleteOldLocations тестирует удаление старых местоположений
 func (suite *LocationRepositoryTestSuite) TestDeleteOldLocations() {
 	// Arrange
@@ -205,14 +241,14 @@ func (suite *LocationRepositoryTestSuite) TestDeleteOldLocations() {
 	// Устанавливаем времена для старых местоположений
 	for i, location := range oldLocations {
 		location.RecordedAt = now.Add(-time.Duration(2+i) * time.Hour)
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
 	// Устанавливаем времена для новых местоположений
 	for i, location := range newLocations {
 		location.RecordedAt = now.Add(-time.Duration(i*10) * time.Minute)
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -241,7 +277,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationFilters() {
 	locations := fixtures.CreateTestLocationHistory(suite.testDriverID, 10, 5*time.Minute)
 
 	for _, location := range locations {
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -277,7 +313,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationTimeRangeFilter() {
 
 	for i, location := range locations {
 		location.RecordedAt = now.Add(baseTimes[i])
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 	}
 
@@ -314,7 +350,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationValidation() {
 
 	// Act & Assert
 	for i, location := range invalidLocations {
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		assert.Error(suite.T(), err, "Invalid location %d should cause error", i)
 	}
 }
@@ -327,7 +363,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationPerformance() {
 
 	// Act - измеряем время пакетной вставки
 	start := time.Now()
-	err := suite.locationRepo.CreateBatch(suite.ctx, locations)
+	_, err := suite.locationRepo.CreateBatch(suite.ctx, locations)
 	batchDuration := time.Since(start)
 
 	// Assert
@@ -366,7 +402,8 @@ func (suite *LocationRepositoryTestSuite) TestLocationConcurrency() {
 				locations[j] = location
 			}
 
-			done <- suite.locationRepo.CreateBatch(suite.ctx, locations)
+			_, err := suite.locationRepo.CreateBatch(suite.ctx, locations)
+			done <- err
 		}(i)
 	}
 
@@ -398,7 +435,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationMetadata() {
 	}
 
 	// Act
-	err := suite.locationRepo.Create(suite.ctx, location)
+	_, err := suite.locationRepo.Create(suite.ctx, location)
 	require.NoError(suite.T(), err)
 
 	// Assert
@@ -432,7 +469,7 @@ func (suite *LocationRepositoryTestSuite) TestLocationAccuracy() {
 		location.ID = uuid.New()
 		location.Accuracy = &tc.accuracy
 
-		err := suite.locationRepo.Create(suite.ctx, location)
+		_, err := suite.locationRepo.Create(suite.ctx, location)
 		require.NoError(suite.T(), err)
 
 		createdLocation, err := suite.locationRepo.GetByID(suite.ctx, location.ID)
@@ -450,10 +487,10 @@ func (suite *LocationRepositoryTestSuite) TestLocationDistanceCalculation() {
 	// Примерно в 1км от Красной площади
 	location2 := fixtures.CreateTestLocationWithCoords(suite.testDriverID, 55.7650, 37.6250)
 
-	err := suite.locationRepo.Create(suite.ctx, location1)
+	_, err := suite.locationRepo.Create(suite.ctx, location1)
 	require.NoError(suite.T(), err)
 
-	err = suite.locationRepo.Create(suite.ctx, location2)
+	_, err = suite.locationRepo.Create(suite.ctx, location2)
 	require.NoError(suite.T(), err)
 
 	// Act
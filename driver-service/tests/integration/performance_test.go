@@ -44,8 +44,8 @@ func (suite *PerformanceTestSuite) SetupSuite() {
 	eventBus := &mockEventPublisher{logger: logger}
 
 	// Инициализируем сервисы
-	suite.driverService = services.NewDriverService(driverRepo, documentRepo, eventBus, logger)
-	suite.locationService = services.NewLocationService(locationRepo, driverRepo, eventBus, logger)
+	suite.driverService = services.NewDriverService(driverRepo, documentRepo, nil, eventBus, logger)
+	suite.locationService = services.NewLocationService(locationRepo, driverRepo, nil, eventBus, nil, nil, nil, services.LocationPolicy{}, logger)
 
 	// Создаем helper для тестирования производительности
 	suite.perfHelper = helpers.NewPerformanceTestHelper(suite.T(), suite.driverService, suite.locationService)
@@ -294,7 +294,7 @@ func (suite *PerformanceTestSuite) TestResourceCleanup() {
 			location.CreatedAt = location.RecordedAt
 		}
 
-		err := suite.locationService.BatchUpdateLocations(suite.ctx, locations)
+		_, err := suite.locationService.BatchUpdateLocations(suite.ctx, locations)
 		require.NoError(suite.T(), err)
 	}
 
@@ -46,8 +46,8 @@ func (suite *ServiceIntegrationTestSuite) SetupSuite() {
 	eventBus := &mockEventPublisher{logger: logger}
 
 	// Инициализируем сервисы
-	suite.driverService = services.NewDriverService(suite.driverRepo, suite.documentRepo, eventBus, logger)
-	suite.locationService = services.NewLocationService(suite.locationRepo, suite.driverRepo, eventBus, logger)
+	suite.driverService = services.NewDriverService(suite.driverRepo, suite.documentRepo, nil, eventBus, logger)
+	suite.locationService = services.NewLocationService(suite.locationRepo, suite.driverRepo, nil, eventBus, nil, nil, nil, services.LocationPolicy{}, logger)
 }
 
 // TearDownSuite выполняется один раз после всех тестов
@@ -227,7 +227,7 @@ func (suite *ServiceIntegrationTestSuite) TestNearbyDriversIntegration() {
 	}
 
 	// Act
-	nearbyLocations, err := suite.locationService.GetNearbyDrivers(suite.ctx, 55.7558, 37.6173, 1.0, 10)
+	nearbyLocations, err := suite.locationService.GetNearbyDrivers(suite.ctx, 55.7558, 37.6173, 1.0, 10, nil)
 
 	// Assert
 	require.NoError(suite.T(), err)
@@ -258,7 +258,7 @@ func (suite *ServiceIntegrationTestSuite) TestBatchLocationUpdates() {
 
 	// Act
 	start := time.Now()
-	err = suite.locationService.BatchUpdateLocations(suite.ctx, locations)
+	_, err = suite.locationService.BatchUpdateLocations(suite.ctx, locations)
 	duration := time.Since(start)
 
 	// Assert